@@ -36,6 +36,7 @@ func newA2ACmd() *cobra.Command {
 	cmd.PersistentFlags().StringVarP(&flags.agentName, "agent", "a", "root", "Name of the agent to run")
 	cmd.PersistentFlags().IntVar(&flags.port, "port", 0, "Port to listen on (default: random available port)")
 	addRuntimeConfigFlags(cmd, &flags.runConfig)
+	addDisclosureFlags(cmd, &flags.runConfig)
 
 	return cmd
 }
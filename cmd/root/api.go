@@ -11,6 +11,7 @@ import (
 	"github.com/docker/cagent/pkg/cli"
 	"github.com/docker/cagent/pkg/config"
 	"github.com/docker/cagent/pkg/connectrpc"
+	"github.com/docker/cagent/pkg/review"
 	"github.com/docker/cagent/pkg/server"
 	"github.com/docker/cagent/pkg/session"
 	"github.com/docker/cagent/pkg/telemetry"
@@ -23,6 +24,9 @@ type apiFlags struct {
 	fakeResponses    string
 	recordPath       string
 	connectRPC       bool
+	reviewDB         string
+	reviewTTL        time.Duration
+	reviewOnExpiry   string
 	runConfig        config.RuntimeConfig
 }
 
@@ -44,8 +48,14 @@ func newAPICmd() *cobra.Command {
 	cmd.PersistentFlags().StringVar(&flags.fakeResponses, "fake", "", "Replay AI responses from cassette file (for testing)")
 	cmd.PersistentFlags().StringVar(&flags.recordPath, "record", "", "Record AI API interactions to cassette file")
 	cmd.PersistentFlags().BoolVar(&flags.connectRPC, "connect-rpc", false, "Use Connect-RPC protocol instead of HTTP/JSON API")
+	cmd.PersistentFlags().IntVar(&flags.runConfig.WarmSessions, "warm-sessions", 0, "Keep this many runtimes ready per agent, with MCP servers started, for new sessions (0 = disabled)")
+	cmd.PersistentFlags().StringVar(&flags.reviewDB, "review-db", defaultReviewDB(), "Path to the review queue database, for the unattended runs")
+	cmd.PersistentFlags().DurationVar(&flags.reviewTTL, "review-ttl", 24*time.Hour, "How long a tool call waits for review before expiring (0 for no expiry)")
+	cmd.PersistentFlags().StringVar(&flags.reviewOnExpiry, "review-on-expiry", string(review.ExpiryReject), "What to do when a review expires: reject the tool call or abort the run")
 	cmd.MarkFlagsMutuallyExclusive("fake", "record")
 	addRuntimeConfigFlags(cmd, &flags.runConfig)
+	addPolicyFlags(cmd, &flags.runConfig)
+	addDeduplicationFlags(cmd, &flags.runConfig)
 
 	return cmd
 }
@@ -71,6 +81,23 @@ func (f *apiFlags) runAPICommand(cmd *cobra.Command, args []string) error {
 		}
 	}()
 
+	auditCleanup, err := setupAuditForwarding(ctx, &f.runConfig)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := auditCleanup(); err != nil {
+			slog.Error("Failed to flush audit events", "error", err)
+		}
+	}()
+
+	queue, reviewStore, err := openReviewQueue(f.reviewDB, f.reviewTTL, f.reviewOnExpiry)
+	if err != nil {
+		return err
+	}
+	defer reviewStore.Close()
+	f.runConfig.ReviewQueue = queue
+
 	// Start recording proxy if --record is specified
 	if _, cleanup, err := setupRecordingProxy(f.recordPath, &f.runConfig); err != nil {
 		return err
@@ -0,0 +1,51 @@
+package root
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/docker/cagent/pkg/audit"
+	"github.com/docker/cagent/pkg/config"
+)
+
+// auditFlushTimeout is how long we wait for pending audit events to be sent on exit.
+const auditFlushTimeout = 10 * time.Second
+
+// setupAuditForwarding starts forwarding audit events to the --audit-forward targets.
+// It returns a cleanup function that flushes pending events (typically via defer).
+func setupAuditForwarding(ctx context.Context, runConfig *config.RuntimeConfig) (cleanup func() error, err error) {
+	if len(runConfig.AuditTargets) == 0 {
+		return func() error { return nil }, nil
+	}
+
+	var (
+		forwarders []*audit.Forwarder
+		recorders  []audit.Recorder
+	)
+	for _, target := range runConfig.AuditTargets {
+		sink, err := audit.NewSink(ctx, target, runConfig.EnvProvider())
+		if err != nil {
+			return nil, err
+		}
+
+		forwarder := audit.NewForwarder(sink)
+		forwarders = append(forwarders, forwarder)
+		recorders = append(recorders, forwarder)
+		slog.Debug("Forwarding audit events", "target", sink.Name())
+	}
+
+	runConfig.AuditRecorder = audit.Multi(recorders...)
+
+	return func() error {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), auditFlushTimeout)
+		defer cancel()
+
+		var errs []error
+		for _, forwarder := range forwarders {
+			errs = append(errs, forwarder.Close(ctx))
+		}
+		return errors.Join(errs...)
+	}, nil
+}
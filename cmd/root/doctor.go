@@ -0,0 +1,44 @@
+package root
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/cagent/pkg/cli"
+	"github.com/docker/cagent/pkg/telemetry"
+	"github.com/docker/cagent/pkg/userconfig"
+	"github.com/docker/cagent/pkg/version"
+)
+
+func newDoctorCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "doctor",
+		Short:   "Show diagnostic information",
+		Long:    "Show the version, the user configuration and the experimental features enabled, to diagnose issues",
+		GroupID: "advanced",
+		Args:    cobra.NoArgs,
+		RunE:    runDoctorCommand,
+	}
+}
+
+func runDoctorCommand(cmd *cobra.Command, args []string) error {
+	telemetry.TrackCommand("doctor", args)
+
+	out := cli.NewPrinter(cmd.OutOrStdout())
+	out.Printf("Version: %s (%s)\n", version.Version, version.Commit)
+
+	// A broken config is what's being diagnosed, report it instead of failing
+	var userFeatures map[string]bool
+	cfg, err := userconfig.Load()
+	if err != nil {
+		out.Printf("Config:  %s (%v)\n", userconfig.Path(), err)
+	} else {
+		out.Printf("Config:  %s\n", userconfig.Path())
+		userFeatures = cfg.Features
+	}
+
+	out.Println("\nExperimental features:")
+	printFeatures(out, os.Getenv, userFeatures)
+	return nil
+}
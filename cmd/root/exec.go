@@ -1,9 +1,12 @@
 package root
 
 import (
+	"time"
+
 	"github.com/spf13/cobra"
 
 	"github.com/docker/cagent/pkg/cli"
+	"github.com/docker/cagent/pkg/review"
 	"github.com/docker/cagent/pkg/telemetry"
 )
 
@@ -18,7 +21,8 @@ func newExecCmd() *cobra.Command {
   cagent exec ./team.yaml --agent root
   cagent exec ./echo.yaml "INSTRUCTIONS"
   echo "INSTRUCTIONS" | cagent exec ./echo.yaml -
-  cagent exec ./agent.yaml "question" --record  # Records to auto-generated file`,
+  cagent exec ./agent.yaml "question" --record  # Records to auto-generated file
+  cagent exec ./agent.yaml "nightly cleanup" --review-queue  # Queues tool calls for human review`,
 		GroupID:           "core",
 		ValidArgsFunction: completeRunExec,
 		Args:              cobra.RangeArgs(1, 2),
@@ -27,8 +31,14 @@ func newExecCmd() *cobra.Command {
 
 	addRunOrExecFlags(cmd, &flags)
 	addRuntimeConfigFlags(cmd, &flags.runConfig)
+	addPolicyFlags(cmd, &flags.runConfig)
+	addDeduplicationFlags(cmd, &flags.runConfig)
 	cmd.PersistentFlags().BoolVar(&flags.hideToolCalls, "hide-tool-calls", false, "Hide the tool calls in the output")
 	cmd.PersistentFlags().BoolVar(&flags.outputJSON, "json", false, "Output results in JSON format")
+	cmd.PersistentFlags().BoolVar(&flags.reviewQueue, "review-queue", false, "Queue tool calls requiring approval for human review and wait for a decision")
+	cmd.PersistentFlags().StringVar(&flags.reviewDB, "review-db", defaultReviewDB(), "Path to the review queue database")
+	cmd.PersistentFlags().DurationVar(&flags.reviewTTL, "review-ttl", 24*time.Hour, "How long a tool call waits for review before expiring (0 for no expiry)")
+	cmd.PersistentFlags().StringVar(&flags.reviewOnExpiry, "review-on-expiry", string(review.ExpiryReject), "What to do when a review expires: reject the tool call or abort the run")
 
 	return cmd
 }
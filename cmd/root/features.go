@@ -0,0 +1,154 @@
+package root
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/cagent/pkg/cli"
+	"github.com/docker/cagent/pkg/features"
+	"github.com/docker/cagent/pkg/telemetry"
+	"github.com/docker/cagent/pkg/userconfig"
+)
+
+func newFeaturesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "features",
+		Short: "Manage experimental features",
+		Long: `List, enable and disable the experimental features.
+
+Experimental features are disabled by default. They are enabled in the user
+configuration, or by their environment variable set to 1 or 0, which takes
+precedence over the configuration.`,
+		Example: `  # List the experimental features
+  cagent features list
+
+  # Enable an experimental feature
+  cagent features enable markdown-renderer
+
+  # Disable it again
+  cagent features disable markdown-renderer`,
+		GroupID: "advanced",
+		RunE:    runFeaturesListCommand,
+	}
+
+	cmd.AddCommand(newFeaturesListCmd())
+	cmd.AddCommand(newFeaturesSetCmd("enable", true))
+	cmd.AddCommand(newFeaturesSetCmd("disable", false))
+
+	return cmd
+}
+
+func newFeaturesListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the experimental features and their state",
+		Args:    cobra.NoArgs,
+		RunE:    runFeaturesListCommand,
+	}
+}
+
+func newFeaturesSetCmd(action string, enabled bool) *cobra.Command {
+	return &cobra.Command{
+		Use:               action + " <feature>",
+		Short:             strings.ToUpper(action[:1]) + action[1:] + " an experimental feature",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeFeatureNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			telemetry.TrackCommand("features", append([]string{action}, args...))
+			return runFeaturesSetCommand(cmd, args[0], enabled)
+		},
+	}
+}
+
+func runFeaturesListCommand(cmd *cobra.Command, args []string) error {
+	telemetry.TrackCommand("features", append([]string{"list"}, args...))
+
+	cfg, err := userconfig.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	printFeatures(cli.NewPrinter(cmd.OutOrStdout()), os.Getenv, cfg.Features)
+	return nil
+}
+
+func runFeaturesSetCommand(cmd *cobra.Command, name string, enabled bool) error {
+	out := cli.NewPrinter(cmd.OutOrStdout())
+
+	flag, ok := features.Lookup(name)
+	if !ok {
+		return fmt.Errorf("unknown feature %q, expected one of %s", name, strings.Join(featureNames(), ", "))
+	}
+
+	cfg, err := userconfig.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	cfg.SetFeature(flag.Name, enabled)
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	state := "disabled"
+	if enabled {
+		state = "enabled"
+	}
+	out.Printf("Feature '%s' %s\n", flag.Name, state)
+
+	// The environment wins over the config, warn when it's not what was asked
+	if current := features.Resolve(flag, os.Getenv, cfg.Features); current.Enabled != enabled {
+		out.Printf("Note: %s=%s overrides it\n", flag.Env, os.Getenv(flag.Env))
+	}
+	return nil
+}
+
+// printFeatures prints the experimental features, their state and where it
+// comes from.
+func printFeatures(out *cli.Printer, getenv func(string) string, config map[string]bool) {
+	all := features.All()
+
+	maxLen := 0
+	for _, flag := range all {
+		maxLen = max(maxLen, runewidth.StringWidth(flag.Name))
+	}
+
+	for _, flag := range all {
+		padding := strings.Repeat(" ", maxLen-runewidth.StringWidth(flag.Name))
+		state := features.Resolve(flag, getenv, config)
+
+		status := "disabled"
+		if state.Enabled {
+			status = "enabled"
+		}
+
+		var source string
+		switch state.Source {
+		case features.SourceEnv:
+			source = " (set by " + flag.Env + ")"
+		case features.SourceConfig:
+			source = " (set in the config)"
+		}
+
+		out.Printf("  %s%s  %-8s  %s%s\n", flag.Name, padding, status, flag.Description, source)
+	}
+}
+
+func featureNames() []string {
+	var names []string
+	for _, flag := range features.All() {
+		names = append(names, flag.Name)
+	}
+	return names
+}
+
+func completeFeatureNames(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return featureNames(), cobra.ShellCompDirectiveNoFileComp
+}
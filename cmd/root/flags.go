@@ -16,6 +16,9 @@ import (
 const (
 	flagModelsGateway = "models-gateway"
 	envModelsGateway  = "CAGENT_MODELS_GATEWAY"
+
+	flagModelAllowlist = "model-allowlist"
+	envModelAllowlist  = "CAGENT_MODEL_ALLOWLIST"
 )
 
 func addRuntimeConfigFlags(cmd *cobra.Command, runConfig *config.RuntimeConfig) {
@@ -25,6 +28,47 @@ func addRuntimeConfigFlags(cmd *cobra.Command, runConfig *config.RuntimeConfig)
 	cmd.PersistentFlags().StringVar(&runConfig.WorkingDir, "working-dir", "", "Set the working directory for the session (applies to tools and relative paths)")
 }
 
+// loadModelAllowlist loads the model allowlist from the --model-allowlist flag
+// or, if not set, the CAGENT_MODEL_ALLOWLIST environment variable.
+func loadModelAllowlist(filename string, runConfig *config.RuntimeConfig) error {
+	if filename != "" {
+		logFlagShadowing(os.Getenv(envModelAllowlist), envModelAllowlist, flagModelAllowlist)
+	} else {
+		filename = os.Getenv(envModelAllowlist)
+	}
+	if filename == "" {
+		return nil
+	}
+
+	allowlist, err := config.LoadModelAllowlist(filename)
+	if err != nil {
+		return err
+	}
+
+	runConfig.ModelAllowlist = allowlist
+	return nil
+}
+
+// addPolicyFlags adds flags for commands whose runtimes enforce policies
+// and record audit events.
+func addPolicyFlags(cmd *cobra.Command, runConfig *config.RuntimeConfig) {
+	cmd.PersistentFlags().StringSliceVar(&runConfig.PolicyFiles, "policy", nil, "Rego policy files deciding which tool calls are allowed, denied or need confirmation")
+	cmd.PersistentFlags().StringSliceVar(&runConfig.AuditTargets, "audit-forward", nil, "Forward audit events to syslog://, syslog+tcp://, splunk://, or https:// targets")
+}
+
+// addDeduplicationFlags adds flags for commands whose runtimes can share the
+// results of identical calls between agents.
+func addDeduplicationFlags(cmd *cobra.Command, runConfig *config.RuntimeConfig) {
+	cmd.PersistentFlags().DurationVar(&runConfig.DedupWindow, "dedup-window", 0, "Share the results of identical model and read-only tool calls between agents for this long (0 = disabled)")
+}
+
+// addDisclosureFlags adds flags for commands that send agent responses to
+// external channels.
+func addDisclosureFlags(cmd *cobra.Command, runConfig *config.RuntimeConfig) {
+	cmd.PersistentFlags().StringVar(&runConfig.Disclosure.Text, "disclosure-footer", "", "Append this text to agent responses ({agent} and {version} are expanded)")
+	cmd.PersistentFlags().BoolVar(&runConfig.Disclosure.Metadata, "disclosure-metadata", false, "Append a hidden comment identifying the agent and cagent version to agent responses")
+}
+
 func setupWorkingDirectory(workingDir string) error {
 	if workingDir == "" {
 		return nil
@@ -67,6 +111,9 @@ var loadUserConfig = userconfig.Load
 func addGatewayFlags(cmd *cobra.Command, runConfig *config.RuntimeConfig) {
 	cmd.PersistentFlags().StringVar(&runConfig.ModelsGateway, flagModelsGateway, "", "Set the models gateway address")
 
+	var modelAllowlist string
+	cmd.PersistentFlags().StringVar(&modelAllowlist, flagModelAllowlist, "", "Only allow the providers, regions and models listed in this file")
+
 	persistentPreRunE := cmd.PersistentPreRunE
 	cmd.PersistentPreRunE = func(_ *cobra.Command, args []string) error {
 		// Precedence: CLI flag > environment variable > user config
@@ -80,6 +127,10 @@ func addGatewayFlags(cmd *cobra.Command, runConfig *config.RuntimeConfig) {
 
 		runConfig.ModelsGateway = canonize(runConfig.ModelsGateway)
 
+		if err := loadModelAllowlist(modelAllowlist, runConfig); err != nil {
+			return err
+		}
+
 		if err := setupWorkingDirectory(runConfig.WorkingDir); err != nil {
 			return err
 		}
@@ -87,8 +138,12 @@ func addGatewayFlags(cmd *cobra.Command, runConfig *config.RuntimeConfig) {
 		if persistentPreRunE != nil {
 			return persistentPreRunE(cmd, args)
 		}
-		if cmd.Parent() != nil && cmd.Parent().PersistentPreRunE != nil {
-			return cmd.Parent().PersistentPreRunE(cmd, args)
+		// Like cobra, run the hook of the closest ancestor having one, which
+		// may not be the parent of subcommands such as "kb add"
+		for parent := cmd.Parent(); parent != nil; parent = parent.Parent() {
+			if parent.PersistentPreRunE != nil {
+				return parent.PersistentPreRunE(cmd, args)
+			}
 		}
 
 		return nil
@@ -0,0 +1,86 @@
+package root
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/docker/cagent/pkg/cli"
+	"github.com/docker/cagent/pkg/config"
+	"github.com/docker/cagent/pkg/review"
+	"github.com/docker/cagent/pkg/runtime"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/teamloader"
+)
+
+// headlessRun describes a run of an agent that nobody watches, e.g. a
+// scheduled run or a step of a pipeline.
+type headlessRun struct {
+	agentFile   string
+	agentName   string
+	prompt      string
+	workingDir  string
+	autoApprove bool
+	// review, if set, queues the tool calls for a human to decide. Otherwise,
+	// they are rejected without autoApprove.
+	review *review.Queue
+	// sessionStore, if set, keeps the conversation.
+	sessionStore session.Store
+}
+
+// runHeadless runs an agent like "cagent exec" would, without printing
+// anything. Each run has its own runtime and tool sets. The session is
+// returned even if the run failed, once it was created.
+func runHeadless(ctx context.Context, runConfig *config.RuntimeConfig, run headlessRun) (*session.Session, error) {
+	runConfig = runConfig.Clone()
+	runConfig.WorkingDir = run.workingDir
+
+	agentSource, err := config.Resolve(run.agentFile)
+	if err != nil {
+		return nil, err
+	}
+	loadResult, err := teamloader.LoadWithConfig(ctx, agentSource, runConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := loadResult.Team.StopToolSets(context.WithoutCancel(ctx)); err != nil {
+			slog.Error("Failed to stop tool sets", "error", err)
+		}
+	}()
+
+	agent, err := loadResult.Team.Agent(run.agentName)
+	if err != nil {
+		return nil, err
+	}
+
+	opts, err := governanceOpts(ctx, runConfig)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts,
+		runtime.WithCurrentAgent(run.agentName),
+		runtime.WithConfigHash(loadResult.ConfigHash),
+	)
+	if run.sessionStore != nil {
+		opts = append(opts, runtime.WithSessionStore(run.sessionStore))
+	}
+	rt, err := runtime.New(loadResult.Team, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("creating runtime: %w", err)
+	}
+
+	sess := session.New(
+		session.WithMaxIterations(agent.MaxIterations()),
+		session.WithToolsApproved(run.autoApprove),
+		session.WithWorkingDir(run.workingDir),
+	)
+
+	cfg := cli.Config{AppName: AppName, AutoApprove: run.autoApprove, OutputJSON: true, Review: run.review}
+	err = cli.Run(ctx, cli.NewPrinter(io.Discard), cfg, rt, sess, []string{"exec", run.prompt})
+	if cliErr, ok := err.(cli.RuntimeError); ok {
+		err = cliErr.Err
+	}
+	return sess, err
+}
@@ -0,0 +1,225 @@
+package root
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/cagent/pkg/cli"
+	"github.com/docker/cagent/pkg/config"
+	"github.com/docker/cagent/pkg/knowledge"
+	"github.com/docker/cagent/pkg/rag/types"
+	"github.com/docker/cagent/pkg/telemetry"
+)
+
+func newKnowledgeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "kb",
+		Short: "Manage local knowledge bases",
+		Long: `Manage the local knowledge bases agents search with the knowledge tool.
+
+The documents of a knowledge base are chunked and embedded when they're added,
+and stored in a local vector database.`,
+		Example: `  # Index the documentation in the default knowledge base
+  cagent kb add ./docs
+
+  # Index the specs in a knowledge base of their own
+  cagent kb add ./specs --name specs
+
+  # List the knowledge bases
+  cagent kb list`,
+		GroupID: "advanced",
+	}
+
+	cmd.AddCommand(newKnowledgeAddCmd())
+	cmd.AddCommand(newKnowledgeListCmd())
+	cmd.AddCommand(newKnowledgeRemoveCmd())
+
+	return cmd
+}
+
+type knowledgeAddFlags struct {
+	name       string
+	model      string
+	dimensions int
+	runConfig  config.RuntimeConfig
+}
+
+func newKnowledgeAddCmd() *cobra.Command {
+	var flags knowledgeAddFlags
+
+	cmd := &cobra.Command{
+		Use:   "add <path>...",
+		Short: "Add documents to a knowledge base and index them",
+		Long: `Add files and directories to a knowledge base, creating it if needed, and
+index them. The documents already indexed are only indexed again if they
+changed: run the command again to update the knowledge base.`,
+		Example: `  cagent kb add ./docs ./README.md
+  cagent kb add ./docs --name docs --model dmr/ai/embeddinggemma --dimensions 768`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: flags.runKnowledgeAddCommand,
+	}
+
+	cmd.Flags().StringVar(&flags.name, "name", knowledge.DefaultName, "Name of the knowledge base")
+	cmd.Flags().StringVar(&flags.model, "model", knowledge.DefaultModel, "Embedding model of a new knowledge base (format: provider/model)")
+	cmd.Flags().IntVar(&flags.dimensions, "dimensions", knowledge.DefaultDimensions, "Number of dimensions of the vectors of the embedding model")
+	cmd.Flags().StringSliceVar(&flags.runConfig.EnvFiles, "env-from-file", nil, "Set environment variables from file")
+	addGatewayFlags(cmd, &flags.runConfig)
+
+	return cmd
+}
+
+func newKnowledgeListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the knowledge bases",
+		Args:    cobra.NoArgs,
+		RunE:    runKnowledgeListCommand,
+	}
+}
+
+func newKnowledgeRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "remove <name>",
+		Aliases: []string{"rm"},
+		Short:   "Remove a knowledge base, leaving its documents untouched",
+		Args:    cobra.ExactArgs(1),
+		RunE:    runKnowledgeRemoveCommand,
+	}
+}
+
+func (f *knowledgeAddFlags) runKnowledgeAddCommand(cmd *cobra.Command, args []string) error {
+	telemetry.TrackCommand("kb", append([]string{"add"}, args...))
+
+	ctx := cmd.Context()
+	out := cli.NewPrinter(cmd.OutOrStdout())
+
+	kb, err := knowledge.LoadOrNew(knowledge.Dir(), f.name)
+	if err != nil {
+		return err
+	}
+
+	// The vectors of a knowledge base can only be compared with vectors of
+	// the same model
+	modelChanged := cmd.Flags().Changed("model") && f.model != kb.Model
+	dimensionsChanged := cmd.Flags().Changed("dimensions") && f.dimensions != kb.Dimensions
+	if len(kb.Docs) > 0 && (modelChanged || dimensionsChanged) {
+		return fmt.Errorf("knowledge base %s embeds with %s (%d dimensions): remove it to change its model", kb.Name, kb.Model, kb.Dimensions)
+	}
+	if len(kb.Docs) == 0 {
+		kb.Model, kb.Dimensions = f.model, f.dimensions
+	}
+
+	if err := kb.AddDocs(args...); err != nil {
+		return err
+	}
+	if err := kb.Save(); err != nil {
+		return fmt.Errorf("saving knowledge base %s: %w", kb.Name, err)
+	}
+
+	manager, err := kb.NewManager(ctx, f.runConfig.EnvProvider(), f.runConfig.ModelsGateway)
+	if err != nil {
+		return err
+	}
+	defer manager.Close()
+
+	// The progress of the indexing goes to stderr
+	var tokens int64
+	var cost float64
+	var errs []error
+	handle := func(event types.Event) {
+		switch event.Type {
+		case types.EventTypeIndexingProgress:
+			if event.Progress != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "\rIndexing %d/%d files", event.Progress.Current, event.Progress.Total)
+			}
+		case types.EventTypeIndexingComplete:
+			fmt.Fprintln(cmd.ErrOrStderr())
+		case types.EventTypeUsage:
+			tokens, cost = event.TotalTokens, event.Cost
+		case types.EventTypeError:
+			if event.Error != nil {
+				errs = append(errs, event.Error)
+			}
+		}
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case event := <-manager.Events():
+				handle(event)
+			case <-stop:
+				// The events sent before the indexing returned
+				for {
+					select {
+					case event := <-manager.Events():
+						handle(event)
+					default:
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	err = manager.Initialize(ctx)
+	close(stop)
+	<-done
+	if err == nil && len(errs) > 0 {
+		err = fmt.Errorf("%d documents couldn't be indexed: %w", len(errs), errors.Join(errs...))
+	}
+	if err != nil {
+		return fmt.Errorf("indexing knowledge base %s: %w", kb.Name, err)
+	}
+
+	out.Printf("Knowledge base '%s' is up to date\n", kb.Name)
+	out.Printf("  Documents: %s\n", strings.Join(kb.Docs, ", "))
+	out.Printf("  Model:     %s\n", kb.Model)
+	if tokens > 0 {
+		out.Printf("  Indexing:  %d tokens ($%.4f)\n", tokens, cost)
+	}
+	out.Printf("\nAgents can search it with a toolset of type knowledge, named %s\n", kb.Name)
+
+	return nil
+}
+
+func runKnowledgeListCommand(cmd *cobra.Command, args []string) error {
+	telemetry.TrackCommand("kb", append([]string{"list"}, args...))
+
+	out := cli.NewPrinter(cmd.OutOrStdout())
+
+	kbs, err := knowledge.List(knowledge.Dir())
+	if err != nil {
+		return err
+	}
+	if len(kbs) == 0 {
+		out.Println("No knowledge bases.")
+		out.Println("\nCreate one with: cagent kb add <path>")
+		return nil
+	}
+
+	for _, kb := range kbs {
+		out.Printf("  %s (%s) → %s\n", kb.Name, kb.Model, strings.Join(kb.Docs, ", "))
+	}
+	return nil
+}
+
+func runKnowledgeRemoveCommand(cmd *cobra.Command, args []string) error {
+	telemetry.TrackCommand("kb", append([]string{"remove"}, args...))
+
+	out := cli.NewPrinter(cmd.OutOrStdout())
+
+	if err := knowledge.Remove(knowledge.Dir(), args[0]); err != nil {
+		return err
+	}
+
+	out.Printf("Knowledge base '%s' removed\n", args[0])
+	return nil
+}
@@ -34,10 +34,28 @@ func newMCPCmd() *cobra.Command {
 		RunE:    flags.runMCPCommand,
 	}
 
+	cmd.AddCommand(&cobra.Command{
+		Use:   "serve <agent-file>|<registry-ref>",
+		Short: "Expose each agent of a team as a tool of an MCP server",
+		Long: `Start an MCP server with a tool per agent of the team, so that MCP clients
+such as Claude Desktop, IDEs or other cagent instances can call the agents.
+By default, uses stdio transport. Use --http to start a streaming HTTP server
+instead.
+
+The tool calls the agent with a message and returns its answer. Clients asking
+for progress are told about the tools the agent calls while it works.`,
+		Example: `  cagent mcp serve ./team.yaml
+  cagent mcp serve ./team.yaml --agent reviewer
+  cagent mcp serve ./team.yaml --http --port 8080`,
+		Args: cobra.ExactArgs(1),
+		RunE: flags.runMCPCommand,
+	})
+
 	cmd.PersistentFlags().StringVarP(&flags.agentName, "agent", "a", "", "Name of the agent to run (all agents if not specified)")
 	cmd.PersistentFlags().BoolVar(&flags.http, "http", false, "Use streaming HTTP transport instead of stdio")
 	cmd.PersistentFlags().IntVar(&flags.port, "port", 0, "Port to listen on when using HTTP transport (default: random available port)")
 	addRuntimeConfigFlags(cmd, &flags.runConfig)
+	addDisclosureFlags(cmd, &flags.runConfig)
 
 	return cmd
 }
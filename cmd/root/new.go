@@ -2,6 +2,7 @@ package root
 
 import (
 	"context"
+	"log/slog"
 	"strings"
 
 	tea "charm.land/bubbletea/v2"
@@ -14,6 +15,7 @@ import (
 	"github.com/docker/cagent/pkg/session"
 	"github.com/docker/cagent/pkg/telemetry"
 	"github.com/docker/cagent/pkg/tui"
+	"github.com/docker/cagent/pkg/tui/styles"
 )
 
 type newFlags struct {
@@ -69,12 +71,16 @@ func (f *newFlags) runNewCommand(cmd *cobra.Command, args []string) error {
 
 	sess := session.New(sessOpts...)
 
-	return runTUI(ctx, rt, sess, appOpts...)
+	return runTUI(ctx, rt, sess, appOpts)
 }
 
-func runTUI(ctx context.Context, rt runtime.Runtime, sess *session.Session, opts ...app.Opt) error {
-	a := app.New(ctx, rt, sess, opts...)
-	m := tui.New(ctx, a)
+func runTUI(ctx context.Context, rt runtime.Runtime, sess *session.Session, appOpts []app.Opt, tuiOpts ...tui.Opt) error {
+	if err := styles.LoadUserTheme(); err != nil {
+		slog.Warn("Failed to load theme, using the default one", "path", styles.UserThemePath(), "error", err)
+	}
+
+	a := app.New(ctx, rt, sess, appOpts...)
+	m := tui.New(ctx, a, tuiOpts...)
 
 	p := tea.NewProgram(m, tea.WithContext(ctx))
 	go a.Subscribe(ctx, p)
@@ -0,0 +1,139 @@
+package root
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/cagent/pkg/cli"
+	"github.com/docker/cagent/pkg/config"
+	"github.com/docker/cagent/pkg/pipeline"
+	"github.com/docker/cagent/pkg/telemetry"
+)
+
+type pipelineFlags struct {
+	autoApprove bool
+	runConfig   config.RuntimeConfig
+}
+
+func newPipelineCmd() *cobra.Command {
+	var flags pipelineFlags
+
+	cmd := &cobra.Command{
+		Use:     "pipeline",
+		Short:   "Chain agents in pipelines",
+		GroupID: "advanced",
+	}
+
+	runCmd := &cobra.Command{
+		Use:   "run <pipeline-file> [input]|-",
+		Short: "Run a pipeline",
+		Long: `Run the steps of a pipeline in order, the output of each step being
+available to the prompts of the next ones, and print the output of the last
+step.`,
+		Example: `  cagent pipeline run ./release.yaml "Prepare the 1.2.0 release"
+  git diff | cagent pipeline run ./review.yaml -`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: flags.runPipelineRunCommand,
+	}
+	runCmd.Flags().BoolVar(&flags.autoApprove, "yolo", false, "Automatically approve all tool calls, which are rejected otherwise")
+	addRuntimeConfigFlags(runCmd, &flags.runConfig)
+	addPolicyFlags(runCmd, &flags.runConfig)
+
+	cmd.AddCommand(runCmd)
+
+	return cmd
+}
+
+func (f *pipelineFlags) runPipelineRunCommand(cmd *cobra.Command, args []string) error {
+	telemetry.TrackCommand("pipeline", append([]string{"run"}, args...))
+
+	ctx := cmd.Context()
+	out := cli.NewPrinter(cmd.OutOrStdout())
+
+	p, err := pipeline.Load(args[0])
+	if err != nil {
+		return err
+	}
+
+	var input string
+	if message, err := readInitialMessage(args); err != nil {
+		return err
+	} else if message != nil {
+		input = *message
+	}
+
+	auditCleanup, err := setupAuditForwarding(ctx, &f.runConfig)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := auditCleanup(); err != nil {
+			slog.Error("Failed to flush audit events", "error", err)
+		}
+	}()
+
+	progress := func(p pipeline.Progress) {
+		switch {
+		case p.Done:
+			fmt.Fprintf(cmd.ErrOrStderr(), "✓ %s\n", p.Step)
+		case p.Runs == 1:
+			fmt.Fprintf(cmd.ErrOrStderr(), "→ %s\n", p.Step)
+		default:
+			fmt.Fprintf(cmd.ErrOrStderr(), "→ %s (%d runs)\n", p.Step, p.Runs)
+		}
+	}
+
+	result, err := p.Run(ctx, input, f.runPipelineStep, pipeline.WithProgress(progress))
+	if err != nil {
+		return err
+	}
+
+	if text, ok := result.Output.(string); ok {
+		out.Println(text)
+		return nil
+	}
+	buf, err := json.MarshalIndent(result.Output, "", "  ")
+	if err != nil {
+		return err
+	}
+	out.Println(string(buf))
+	return nil
+}
+
+// runPipelineStep runs the agent of a step like "cagent exec" would. Each run
+// has its own runtime, so that the runs of a step with for_each don't share
+// their tool sets.
+func (f *pipelineFlags) runPipelineStep(ctx context.Context, step *pipeline.Step, prompt string) (string, error) {
+	agentName := step.AgentName
+	if agentName == "" {
+		agentName = "root"
+	}
+
+	workingDir := f.runConfig.WorkingDir
+	if workingDir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return "", err
+		}
+		workingDir = wd
+	}
+
+	// The output of the agent is its last answer: nobody is there to approve
+	// the tool calls, which are rejected without --yolo
+	sess, err := runHeadless(ctx, &f.runConfig, headlessRun{
+		agentFile:   step.Agent,
+		agentName:   agentName,
+		prompt:      prompt,
+		workingDir:  workingDir,
+		autoApprove: f.autoApprove,
+	})
+	if err != nil {
+		return "", err
+	}
+	return sess.GetLastAssistantMessageContent(), nil
+}
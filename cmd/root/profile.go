@@ -0,0 +1,65 @@
+package root
+
+import (
+	"cmp"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+)
+
+// startProfiling starts a CPU profile or an execution trace, or prepares a
+// heap profile, written to path. The returned function stops the profile and
+// must be called before cagent exits.
+func startProfiling(kind, path string) (func() error, error) {
+	switch kind {
+	case "":
+		return func() error { return nil }, nil
+	case "cpu", "mem", "trace":
+	default:
+		return nil, fmt.Errorf("invalid profile %q: must be one of cpu, mem or trace", kind)
+	}
+
+	path = cmp.Or(path, defaultProfilePath(kind))
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("creating profile file: %w", err)
+	}
+	slog.Debug("Profiling", "profile", kind, "path", path)
+
+	switch kind {
+	case "cpu":
+		if err := pprof.StartCPUProfile(f); err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("starting CPU profile: %w", err)
+		}
+		return func() error {
+			pprof.StopCPUProfile()
+			return f.Close()
+		}, nil
+	case "trace":
+		if err := trace.Start(f); err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("starting trace: %w", err)
+		}
+		return func() error {
+			trace.Stop()
+			return f.Close()
+		}, nil
+	default:
+		return func() error {
+			defer f.Close()
+			runtime.GC() // Get up-to-date statistics
+			return pprof.WriteHeapProfile(f)
+		}, nil
+	}
+}
+
+func defaultProfilePath(kind string) string {
+	if kind == "trace" {
+		return "cagent.trace.out"
+	}
+	return "cagent." + kind + ".pprof"
+}
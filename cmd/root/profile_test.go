@@ -0,0 +1,30 @@
+package root
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartProfiling(t *testing.T) {
+	t.Parallel()
+
+	_, err := startProfiling("gpu", "")
+	require.Error(t, err)
+
+	stop, err := startProfiling("", "")
+	require.NoError(t, err)
+	require.NoError(t, stop())
+
+	path := filepath.Join(t.TempDir(), "mem.pprof")
+	stop, err = startProfiling("mem", path)
+	require.NoError(t, err)
+	require.NoError(t, stop())
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Positive(t, info.Size())
+}
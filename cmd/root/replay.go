@@ -0,0 +1,153 @@
+package root
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/cli"
+	"github.com/docker/cagent/pkg/config"
+	"github.com/docker/cagent/pkg/paths"
+	"github.com/docker/cagent/pkg/runtime"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/teamloader"
+	"github.com/docker/cagent/pkg/telemetry"
+)
+
+func defaultRecordingsDir() string {
+	return filepath.Join(paths.GetDataDir(), "recordings")
+}
+
+type replayFlags struct {
+	agentName     string
+	sessionDB     string
+	recordingPath string
+	output        string
+	runConfig     config.RuntimeConfig
+}
+
+func newReplayCmd() *cobra.Command {
+	var flags replayFlags
+
+	cmd := &cobra.Command{
+		Use:   "replay <agent-file>|<registry-ref> <session-id>",
+		Short: "Replay a recorded session",
+		Long: `Replay a session recorded with "cagent run --record-session" or
+"cagent exec --record-session". The messages of the user are sent again to the
+agents, and the models and the tools answer as they did when the session was
+recorded: nothing is sent to the providers and no tool runs.
+
+A warning is printed when a request to a model differs from the recorded one,
+for example after the configuration of the agent changed.`,
+		Example: `  cagent run ./agent.yaml --record-session
+  cagent replay ./agent.yaml <session-id>
+  cagent replay ./agent.yaml <session-id> --output jsonl`,
+		GroupID: "advanced",
+		Args:    cobra.ExactArgs(2),
+		RunE:    flags.runReplayCommand,
+	}
+
+	cmd.PersistentFlags().StringVarP(&flags.agentName, "agent", "a", "root", "Name of the agent to run")
+	cmd.PersistentFlags().StringVarP(&flags.sessionDB, "session-db", "s", filepath.Join(paths.GetHomeDir(), ".cagent", "session.db"), "Path to the session database")
+	cmd.PersistentFlags().StringVar(&flags.recordingPath, "recording", "", "Path to the recording of the session (default: in ~/.cagent/recordings)")
+	cmd.PersistentFlags().StringVar(&flags.output, "output", outputPlain, "Output mode: plain, json or jsonl")
+	addRuntimeConfigFlags(cmd, &flags.runConfig)
+
+	return cmd
+}
+
+func (f *replayFlags) runReplayCommand(cmd *cobra.Command, args []string) error {
+	telemetry.TrackCommand("replay", args)
+
+	ctx := cmd.Context()
+	out := cli.NewPrinter(cmd.OutOrStdout())
+	sessionID := args[1]
+
+	cfg := cli.Config{AppName: AppName, AutoApprove: true}
+	switch f.output {
+	case outputPlain:
+		cfg.ShowUsage = true
+	case outputJSON:
+		cfg.OutputJSON = true
+	case outputJSONL:
+		cfg.OutputJSONL = true
+	default:
+		return fmt.Errorf("invalid output mode %q: must be one of %s, %s or %s", f.output, outputPlain, outputJSON, outputJSONL)
+	}
+
+	store, err := session.NewSQLiteSessionStore(f.sessionDB)
+	if err != nil {
+		return fmt.Errorf("opening session store: %w", err)
+	}
+	recorded, err := store.GetSession(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("loading session %s: %w", sessionID, err)
+	}
+
+	recordingPath := f.recordingPath
+	if recordingPath == "" {
+		recordingPath = runtime.RecordingPath(defaultRecordingsDir(), sessionID)
+	}
+	recording, err := runtime.LoadRecording(recordingPath)
+	if err != nil {
+		return fmt.Errorf("loading the recording of session %s: %w", sessionID, err)
+	}
+
+	agentSource, err := config.Resolve(args[0])
+	if err != nil {
+		return err
+	}
+	loadResult, err := teamloader.LoadWithConfig(ctx, agentSource, &f.runConfig)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := loadResult.Team.StopToolSets(context.WithoutCancel(ctx)); err != nil {
+			slog.Error("Failed to stop tool sets", "error", err)
+		}
+	}()
+
+	// Compacting the session would call a model outside of the recording
+	rt, err := runtime.New(loadResult.Team,
+		runtime.WithCurrentAgent(f.agentName),
+		runtime.WithReplay(recording),
+		runtime.WithSessionCompaction(false),
+	)
+	if err != nil {
+		return fmt.Errorf("creating runtime: %w", err)
+	}
+
+	sess := session.New(
+		session.WithMaxIterations(recorded.MaxIterations),
+		session.WithToolsApproved(true),
+	)
+	for _, msg := range userMessages(recorded) {
+		if err := cli.Run(ctx, out, cfg, rt, sess, []string{"replay", msg}); err != nil {
+			return err
+		}
+	}
+
+	if recording.Diverged() {
+		out.Println()
+		out.Println("Warning: the replay diverged from the recording, the answers may not match the conversation.")
+	}
+	return nil
+}
+
+// userMessages returns the messages the user sent in a session.
+func userMessages(sess *session.Session) []string {
+	var messages []string
+	for _, item := range sess.Messages {
+		if !item.IsMessage() {
+			continue
+		}
+		if msg := item.Message; msg.Message.Role == chat.MessageRoleUser && !msg.Implicit {
+			messages = append(messages, msg.Message.Content)
+		}
+	}
+	return messages
+}
@@ -0,0 +1,151 @@
+package root
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/cagent/pkg/cli"
+	"github.com/docker/cagent/pkg/paths"
+	"github.com/docker/cagent/pkg/review"
+	"github.com/docker/cagent/pkg/telemetry"
+)
+
+func defaultReviewDB() string {
+	return filepath.Join(paths.GetHomeDir(), ".cagent", "review.db")
+}
+
+// openReviewQueue opens the review queue of unattended runs. The store must
+// be closed once the runs are done.
+func openReviewQueue(db string, ttl time.Duration, onExpiry string) (*review.Queue, *review.SQLiteStore, error) {
+	policy, err := review.ParseExpiryPolicy(onExpiry)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	store, err := review.NewSQLiteStore(db)
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening review queue: %w", err)
+	}
+
+	return review.NewQueue(store, review.WithTTL(ttl), review.WithExpiryPolicy(policy)), store, nil
+}
+
+type reviewFlags struct {
+	reviewDB string
+	all      bool
+}
+
+func newReviewCmd() *cobra.Command {
+	var flags reviewFlags
+
+	cmd := &cobra.Command{
+		Use:   "review",
+		Short: "Manage tool calls waiting for human review",
+		Long: `Manage the queue of tool calls waiting for a human decision.
+
+Unattended runs, started with "cagent exec --review-queue" or by
+"cagent schedule daemon", don't prompt for tool call approvals. Instead, tool calls are queued here and the run waits
+until they are approved, rejected or expired.`,
+		Example: `  # List pending tool calls
+  cagent review list
+
+  # Approve a tool call and resume the run
+  cagent review approve <id>
+
+  # Reject a tool call
+  cagent review reject <id>`,
+		GroupID: "advanced",
+	}
+
+	cmd.PersistentFlags().StringVar(&flags.reviewDB, "review-db", defaultReviewDB(), "Path to the review queue database")
+
+	listCmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List tool calls waiting for review",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runReviewListCommand(cmd, args, &flags)
+		},
+	}
+	listCmd.Flags().BoolVar(&flags.all, "all", false, "Also list decided and expired requests")
+
+	cmd.AddCommand(listCmd)
+	cmd.AddCommand(newReviewDecideCmd("approve", "Approve a tool call and resume its run", review.StatusApproved, &flags))
+	cmd.AddCommand(newReviewDecideCmd("reject", "Reject a tool call", review.StatusRejected, &flags))
+
+	return cmd
+}
+
+func newReviewDecideCmd(name, short string, status review.Status, flags *reviewFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   name + " <id>",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			telemetry.TrackCommand("review", append([]string{name}, args...))
+
+			out := cli.NewPrinter(cmd.OutOrStdout())
+
+			store, err := review.NewSQLiteStore(flags.reviewDB)
+			if err != nil {
+				return fmt.Errorf("opening review queue: %w", err)
+			}
+			defer store.Close()
+
+			if err := store.Decide(cmd.Context(), args[0], status); err != nil {
+				if errors.Is(err, review.ErrAlreadyDecided) {
+					req, getErr := store.GetRequest(cmd.Context(), args[0])
+					if getErr == nil {
+						return fmt.Errorf("review request %s is already %s", args[0], req.Status)
+					}
+				}
+				return err
+			}
+
+			out.Printf("Review request %s %s\n", args[0], status)
+			return nil
+		},
+	}
+}
+
+func runReviewListCommand(cmd *cobra.Command, args []string, flags *reviewFlags) error {
+	telemetry.TrackCommand("review", append([]string{"list"}, args...))
+
+	out := cli.NewPrinter(cmd.OutOrStdout())
+
+	store, err := review.NewSQLiteStore(flags.reviewDB)
+	if err != nil {
+		return fmt.Errorf("opening review queue: %w", err)
+	}
+	defer store.Close()
+
+	status := review.StatusPending
+	if flags.all {
+		status = ""
+	}
+
+	requests, err := store.ListRequests(cmd.Context(), status)
+	if err != nil {
+		return err
+	}
+
+	if len(requests) == 0 {
+		out.Println("No tool calls waiting for review.")
+		return nil
+	}
+
+	for _, req := range requests {
+		out.Printf("%s  %-8s  %s  agent=%s  session=%s\n", req.ID, req.Status, req.CreatedAt.Local().Format(time.DateTime), req.AgentName, req.SessionID)
+		out.Printf("    %s(%s)\n", req.ToolName, req.Arguments)
+		if req.Status == review.StatusPending && !req.ExpiresAt.IsZero() {
+			out.Printf("    expires %s\n", req.ExpiresAt.Local().Format(time.DateTime))
+		}
+	}
+
+	return nil
+}
@@ -26,6 +26,10 @@ type rootFlags struct {
 	debugMode   bool
 	logFilePath string
 	logFile     io.Closer
+
+	profile       string
+	profileOutput string
+	stopProfile   func() error
 }
 
 func NewRootCmd() *cobra.Command {
@@ -53,6 +57,12 @@ func NewRootCmd() *cobra.Command {
 
 			telemetry.SetGlobalTelemetryDebugMode(flags.debugMode)
 
+			stopProfile, err := startProfiling(flags.profile, flags.profileOutput)
+			if err != nil {
+				return err
+			}
+			flags.stopProfile = stopProfile
+
 			if flags.enableOtel {
 				if err := initOTelSDK(cmd.Context()); err != nil {
 					slog.Warn("Failed to initialize OpenTelemetry SDK", "error", err)
@@ -64,6 +74,11 @@ func NewRootCmd() *cobra.Command {
 			return nil
 		},
 		PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+			if flags.stopProfile != nil {
+				if err := flags.stopProfile(); err != nil {
+					slog.Warn("Failed to write profile", "error", err)
+				}
+			}
 			if flags.logFile != nil {
 				_ = flags.logFile.Close()
 			}
@@ -81,6 +96,8 @@ func NewRootCmd() *cobra.Command {
 	cmd.PersistentFlags().BoolVarP(&flags.debugMode, "debug", "d", false, "Enable debug logging")
 	cmd.PersistentFlags().BoolVarP(&flags.enableOtel, "otel", "o", false, "Enable OpenTelemetry tracing")
 	cmd.PersistentFlags().StringVar(&flags.logFilePath, "log-file", "", "Path to debug log file (default: ~/.cagent/cagent.debug.log; only used with --debug)")
+	cmd.PersistentFlags().StringVar(&flags.profile, "profile", "", "Profile cagent: cpu, mem or trace")
+	cmd.PersistentFlags().StringVar(&flags.profileOutput, "profile-output", "", "Path to the profile file (default: cagent.<profile>.pprof, or cagent.trace.out for traces)")
 
 	cmd.AddCommand(newVersionCmd())
 	cmd.AddCommand(newRunCmd())
@@ -98,7 +115,17 @@ func NewRootCmd() *cobra.Command {
 	cmd.AddCommand(newCatalogCmd())
 	cmd.AddCommand(newBuildCmd())
 	cmd.AddCommand(newAliasCmd())
+	cmd.AddCommand(newReviewCmd())
 	cmd.AddCommand(newConfigCmd())
+	cmd.AddCommand(newValidateCmd())
+	cmd.AddCommand(newFeaturesCmd())
+	cmd.AddCommand(newDoctorCmd())
+	cmd.AddCommand(newSessionCmd())
+	cmd.AddCommand(newReplayCmd())
+	cmd.AddCommand(newScheduleCmd())
+	cmd.AddCommand(newPipelineCmd())
+	cmd.AddCommand(newKnowledgeCmd())
+	cmd.AddCommand(newTuneCmd())
 
 	// Define groups
 	cmd.AddGroup(&cobra.Group{ID: "core", Title: "Core Commands:"})
@@ -171,12 +198,14 @@ We collect anonymous usage data to help improve cagent. To disable:
 }
 
 // setupLogging configures slog logging behavior.
-// When --debug is enabled, logs are written to a rotating file <dataDir>/cagent.debug.log,
+// Logs are always kept in memory, for the log pane of the TUI.
+// When --debug is enabled, logs are also written to a rotating file <dataDir>/cagent.debug.log,
 // or to the file specified by --log-file. Log files are rotated when they exceed 10MB,
 // keeping up to 3 backup files.
 func (f *rootFlags) setupLogging() error {
+	recent := logging.Recent().Handler(slog.LevelDebug)
 	if !f.debugMode {
-		slog.SetDefault(slog.New(slog.DiscardHandler))
+		slog.SetDefault(slog.New(recent))
 		return nil
 	}
 
@@ -188,7 +217,10 @@ func (f *rootFlags) setupLogging() error {
 	}
 	f.logFile = logFile
 
-	slog.SetDefault(slog.New(slog.NewTextHandler(logFile, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	slog.SetDefault(slog.New(logging.Tee(
+		slog.NewTextHandler(logFile, &slog.HandlerOptions{Level: slog.LevelDebug}),
+		recent,
+	)))
 
 	return nil
 }
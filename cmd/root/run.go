@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
@@ -16,10 +17,13 @@ import (
 	"github.com/docker/cagent/pkg/cli"
 	"github.com/docker/cagent/pkg/config"
 	"github.com/docker/cagent/pkg/paths"
+	"github.com/docker/cagent/pkg/policy"
 	"github.com/docker/cagent/pkg/runtime"
 	"github.com/docker/cagent/pkg/session"
 	"github.com/docker/cagent/pkg/teamloader"
 	"github.com/docker/cagent/pkg/telemetry"
+	"github.com/docker/cagent/pkg/tui"
+	"github.com/docker/cagent/pkg/userconfig"
 )
 
 type runExecFlags struct {
@@ -33,15 +37,56 @@ type runExecFlags struct {
 	runConfig      config.RuntimeConfig
 	sessionDB      string
 	sessionID      string
+	resume         bool
 	recordPath     string
 	fakeResponses  string
+	profileStartup bool
+	timeLimit      time.Duration
+	hideSubAgents  bool
+	recordSession  bool
+	confirmCost    float64
+
+	// startup measures the startup phases when --profile-startup is set
+	startup *startupProfile
 
 	// Exec only
-	hideToolCalls bool
-	outputJSON    bool
+	hideToolCalls  bool
+	outputJSON     bool
+	outputJSONL    bool
+	reviewQueue    bool
+	reviewDB       string
+	reviewTTL      time.Duration
+	reviewOnExpiry string
 
 	// Run only
 	hideToolResults bool
+	pickSession     bool
+	output          string
+	showUsage       bool
+}
+
+// Output modes of the run command
+const (
+	outputTUI   = "tui"
+	outputPlain = "plain"
+	outputJSON  = "json"
+	outputJSONL = "jsonl"
+)
+
+// resolveOutputMode returns the output mode to run with. With no explicit
+// mode, the TUI is only used when stdout is a terminal.
+func resolveOutputMode(output string, terminal bool) (string, error) {
+	switch output {
+	case "":
+		if terminal {
+			return outputTUI, nil
+		}
+		return outputPlain, nil
+	case outputTUI, outputPlain, outputJSON, outputJSONL:
+		return output, nil
+	default:
+		return "", fmt.Errorf("invalid output mode %q: must be one of %s, %s, %s or %s", output, outputPlain, outputJSON, outputJSONL, outputTUI)
+	}
 }
 
 func newRunCmd() *cobra.Command {
@@ -56,7 +101,10 @@ func newRunCmd() *cobra.Command {
   cagent run # built-in default agent
   cagent run ./echo.yaml "INSTRUCTIONS"
   echo "INSTRUCTIONS" | cagent run ./echo.yaml -
-  cagent run ./agent.yaml --record  # Records session to auto-generated file`,
+  cagent run ./agent.yaml --record  # Records session to auto-generated file
+  cagent run ./agent.yaml --resume  # Continues the most recent session of the agent in this directory
+  cagent run ./agent.yaml --pick-session  # Picks the session to continue from a list
+  cagent run ./agent.yaml "question" --output plain  # Prints the answer without the TUI`,
 		GroupID:           "core",
 		ValidArgsFunction: completeRunExec,
 		Args:              cobra.RangeArgs(0, 2),
@@ -65,6 +113,11 @@ func newRunCmd() *cobra.Command {
 
 	addRunOrExecFlags(cmd, &flags)
 	addRuntimeConfigFlags(cmd, &flags.runConfig)
+	addPolicyFlags(cmd, &flags.runConfig)
+	addDeduplicationFlags(cmd, &flags.runConfig)
+	cmd.PersistentFlags().BoolVar(&flags.pickSession, "pick-session", false, "Pick the session to continue from a list on startup")
+	cmd.PersistentFlags().StringVar(&flags.output, "output", "", "Output mode: plain, json, jsonl or tui (default: tui when stdout is a terminal, plain otherwise)")
+	cmd.MarkFlagsMutuallyExclusive("session", "resume", "pick-session")
 
 	return cmd
 }
@@ -76,14 +129,21 @@ func addRunOrExecFlags(cmd *cobra.Command, flags *runExecFlags) {
 	cmd.PersistentFlags().StringVar(&flags.attachmentPath, "attach", "", "Attach an image file to the message")
 	cmd.PersistentFlags().StringArrayVar(&flags.modelOverrides, "model", nil, "Override agent model: [agent=]provider/model (repeatable)")
 	cmd.PersistentFlags().BoolVar(&flags.dryRun, "dry-run", false, "Initialize the agent without executing anything")
+	cmd.PersistentFlags().Float64Var(&flags.confirmCost, "confirm-cost", 0, "Ask for confirmation when the estimated cost of the first request exceeds this amount in USD")
 	cmd.PersistentFlags().StringVar(&flags.remoteAddress, "remote", "", "Use remote runtime with specified address")
 	cmd.PersistentFlags().BoolVar(&flags.connectRPC, "connect-rpc", false, "Use Connect-RPC protocol for remote communication (requires --remote)")
 	cmd.PersistentFlags().StringVarP(&flags.sessionDB, "session-db", "s", filepath.Join(paths.GetHomeDir(), ".cagent", "session.db"), "Path to the session database")
 	cmd.PersistentFlags().StringVar(&flags.sessionID, "session", "", "Continue from a previous session by ID")
+	cmd.PersistentFlags().BoolVar(&flags.resume, "resume", false, "Continue from the most recent session of the agent in the working directory")
 	cmd.PersistentFlags().StringVar(&flags.fakeResponses, "fake", "", "Replay AI responses from cassette file (for testing)")
 	cmd.PersistentFlags().StringVar(&flags.recordPath, "record", "", "Record AI API interactions to cassette file (auto-generates filename if empty)")
 	cmd.PersistentFlags().Lookup("record").NoOptDefVal = "true"
+	cmd.PersistentFlags().BoolVar(&flags.profileStartup, "profile-startup", false, "Report how long each phase of the startup takes")
+	cmd.PersistentFlags().BoolVar(&flags.hideSubAgents, "hide-sub-agents", false, "Hide what the agents tasks are transferred to say and do, only their results are shown")
+	cmd.PersistentFlags().BoolVar(&flags.recordSession, "record-session", false, "Record the answers of the models and the tools, to replay the session with \"cagent replay\"")
+	cmd.PersistentFlags().DurationVar(&flags.timeLimit, "time-limit", 0, "Wall-clock time each run may take, the agent is asked to wrap up shortly before (e.g. 30m)")
 	cmd.MarkFlagsMutuallyExclusive("fake", "record")
+	cmd.MarkFlagsMutuallyExclusive("session", "resume")
 }
 
 func (f *runExecFlags) runRunCommand(cmd *cobra.Command, args []string) error {
@@ -92,12 +152,23 @@ func (f *runExecFlags) runRunCommand(cmd *cobra.Command, args []string) error {
 	ctx := cmd.Context()
 	out := cli.NewPrinter(cmd.OutOrStdout())
 
-	tui := isatty.IsTerminal(os.Stdout.Fd())
-	return f.runOrExec(ctx, out, args, tui)
+	mode, err := resolveOutputMode(f.output, isatty.IsTerminal(os.Stdout.Fd()))
+	if err != nil {
+		return err
+	}
+	if mode != outputTUI && f.pickSession {
+		return fmt.Errorf("--pick-session requires the %s output", outputTUI)
+	}
+	f.outputJSON = mode == outputJSON
+	f.outputJSONL = mode == outputJSONL
+	f.showUsage = mode == outputPlain
+
+	return f.runOrExec(ctx, out, args, mode == outputTUI)
 }
 
 func (f *runExecFlags) runOrExec(ctx context.Context, out *cli.Printer, args []string, tui bool) error {
 	slog.Debug("Starting agent", "agent", f.agentName)
+	f.startup = newStartupProfile(f.profileStartup)
 
 	var agentFileName string
 	if len(args) > 0 {
@@ -127,6 +198,16 @@ func (f *runExecFlags) runOrExec(ctx context.Context, out *cli.Printer, args []s
 		}
 	}()
 
+	auditCleanup, err := setupAuditForwarding(ctx, &f.runConfig)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := auditCleanup(); err != nil {
+			slog.Error("Failed to flush audit events", "error", err)
+		}
+	}()
+
 	// Record AI API interactions to a cassette file if --record flag is specified.
 	cassettePath, recordCleanup, err := setupRecordingProxy(f.recordPath, &f.runConfig)
 	if err != nil {
@@ -136,11 +217,13 @@ func (f *runExecFlags) runOrExec(ctx context.Context, out *cli.Printer, args []s
 		defer recordCleanup()
 		out.Println("Recording mode enabled, cassette: " + cassettePath)
 	}
+	f.startup.mark("set up proxies")
 
 	var (
-		rt      runtime.Runtime
-		sess    *session.Session
-		cleanup func()
+		rt        runtime.Runtime
+		sess      *session.Session
+		cleanup   func()
+		agentFile string // The file of the local agent, whose changes reload the tools
 	)
 	if f.remoteAddress != "" {
 		rt, sess, err = f.createRemoteRuntimeAndSession(ctx, agentFileName)
@@ -148,21 +231,25 @@ func (f *runExecFlags) runOrExec(ctx context.Context, out *cli.Printer, args []s
 			return err
 		}
 		cleanup = func() {} // Remote runtime doesn't need local cleanup
+		f.startup.mark("connect to remote runtime")
 	} else {
 		agentSource, err := config.Resolve(agentFileName)
 		if err != nil {
 			return err
 		}
+		f.startup.mark("resolve agent")
 
 		loadResult, err := f.loadAgentFrom(ctx, agentSource)
 		if err != nil {
 			return err
 		}
 
-		rt, sess, err = f.createLocalRuntimeAndSession(ctx, loadResult)
+		rt, sess, err = f.createLocalRuntimeAndSession(ctx, agentSource, loadResult)
 		if err != nil {
 			return err
 		}
+		agentFile, _ = config.LocalPath(agentSource)
+		f.startup.mark("create runtime and session")
 
 		// Setup cleanup for local runtime
 		cleanup = func() {
@@ -175,20 +262,68 @@ func (f *runExecFlags) runOrExec(ctx context.Context, out *cli.Printer, args []s
 	}
 	defer cleanup()
 
-	if f.dryRun {
-		out.Println("Dry run mode enabled. Agent initialized but will not execute.")
-		return nil
+	// Printed before the TUI starts, it is still visible once it exits
+	f.startup.report(os.Stderr)
+
+	if f.dryRun || f.confirmCost > 0 {
+		// The prompt read from stdin can't be read twice
+		if len(args) == 2 && args[1] == "-" {
+			prompt, err := readInitialMessage(args)
+			if err != nil {
+				return err
+			}
+			args = []string{args[0], *prompt}
+		}
+
+		proceed, err := f.checkEstimatedCost(ctx, out, rt, sess, args)
+		if err != nil || !proceed {
+			return err
+		}
 	}
 
 	if !tui {
 		return f.handleExecMode(ctx, out, rt, sess, args)
 	}
 
-	return f.handleRunMode(ctx, rt, sess, args)
+	return f.handleRunMode(ctx, rt, sess, agentFile, args)
+}
+
+// checkEstimatedCost estimates the cost of the first request of the run. In
+// dry run mode, it prints the estimate and stops there. Otherwise, it asks
+// for confirmation when the estimate exceeds the --confirm-cost threshold.
+func (f *runExecFlags) checkEstimatedCost(ctx context.Context, out *cli.Printer, rt runtime.Runtime, sess *session.Session, args []string) (bool, error) {
+	var prompt string
+	if len(args) == 2 {
+		prompt = args[1]
+	}
+
+	var estimate *runtime.CostEstimate
+	if estimator, ok := rt.(runtime.CostEstimator); ok {
+		var err error
+		if estimate, err = estimator.EstimateCost(ctx, sess, prompt); err != nil {
+			return false, fmt.Errorf("estimating cost: %w", err)
+		}
+	}
+
+	if f.dryRun {
+		if estimate != nil {
+			out.PrintCostEstimate(estimate)
+		}
+		out.Println("Dry run mode enabled. Agent initialized but will not execute.")
+		return false, nil
+	}
+
+	if estimate == nil || !estimate.Priced || estimate.MaxCost <= f.confirmCost {
+		return true, nil
+	}
+	return out.PromptCostContinue(ctx, estimate, f.confirmCost) == cli.ConfirmationApprove, nil
 }
 
 func (f *runExecFlags) loadAgentFrom(ctx context.Context, agentSource config.Source) (*teamloader.LoadResult, error) {
-	result, err := teamloader.LoadWithConfig(ctx, agentSource, &f.runConfig, teamloader.WithModelOverrides(f.modelOverrides))
+	result, err := teamloader.LoadWithConfig(ctx, agentSource, &f.runConfig,
+		teamloader.WithModelOverrides(f.modelOverrides),
+		teamloader.WithPhaseHook(f.startup.hook()),
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -211,6 +346,7 @@ func (f *runExecFlags) createConnectRPCRuntimeAndSession(ctx context.Context, or
 
 	sessTemplate := session.New(
 		session.WithToolsApproved(f.autoApprove),
+		session.WithTimeLimit(f.timeLimit),
 	)
 
 	sess, err := connectClient.CreateSession(ctx, sessTemplate)
@@ -238,6 +374,7 @@ func (f *runExecFlags) createHTTPRuntimeAndSession(ctx context.Context, original
 
 	sessTemplate := session.New(
 		session.WithToolsApproved(f.autoApprove),
+		session.WithTimeLimit(f.timeLimit),
 	)
 
 	sess, err := remoteClient.CreateSession(ctx, sessTemplate)
@@ -257,7 +394,27 @@ func (f *runExecFlags) createHTTPRuntimeAndSession(ctx context.Context, original
 	return remoteRt, sess, nil
 }
 
-func (f *runExecFlags) createLocalRuntimeAndSession(ctx context.Context, loadResult *teamloader.LoadResult) (runtime.Runtime, *session.Session, error) {
+// governanceOpts returns the runtime options deciding which tool calls are
+// allowed and recording them: the approvals remembered by the user, the
+// --policy files and the --audit-forward targets.
+func governanceOpts(ctx context.Context, runConfig *config.RuntimeConfig) ([]runtime.Opt, error) {
+	opts := []runtime.Opt{
+		runtime.WithToolApprovals(userconfig.NewToolApprovals()),
+	}
+	if len(runConfig.PolicyFiles) > 0 {
+		engine, err := policy.NewRegoEngine(ctx, runConfig.PolicyFiles)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, runtime.WithPolicyEngine(engine))
+	}
+	if runConfig.AuditRecorder != nil {
+		opts = append(opts, runtime.WithAuditRecorder(runConfig.AuditRecorder))
+	}
+	return opts, nil
+}
+
+func (f *runExecFlags) createLocalRuntimeAndSession(ctx context.Context, agentSource config.Source, loadResult *teamloader.LoadResult) (runtime.Runtime, *session.Session, error) {
 	t := loadResult.Team
 
 	agent, err := t.Agent(f.agentName)
@@ -277,18 +434,53 @@ func (f *runExecFlags) createLocalRuntimeAndSession(ctx context.Context, loadRes
 		ModelsGateway:      f.runConfig.ModelsGateway,
 		EnvProvider:        f.runConfig.EnvProvider(),
 		AgentDefaultModels: loadResult.AgentDefaultModels,
+		ModelAllowlist:     f.runConfig.ModelAllowlist,
 	}
 
-	localRt, err := runtime.New(t,
+	opts := []runtime.Opt{
 		runtime.WithSessionStore(sessStore),
 		runtime.WithCurrentAgent(f.agentName),
 		runtime.WithTracer(otel.Tracer(AppName)),
 		runtime.WithModelSwitcherConfig(modelSwitcherCfg),
-	)
+		runtime.WithConfigHash(loadResult.ConfigHash),
+		runtime.WithToolsReloader(func(ctx context.Context) error {
+			return teamloader.ReloadToolSets(ctx, agentSource, &f.runConfig, t)
+		}),
+	}
+	governance, err := governanceOpts(ctx, &f.runConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	opts = append(opts, governance...)
+	if f.runConfig.DedupWindow > 0 {
+		opts = append(opts, runtime.WithDeduplication(f.runConfig.DedupWindow))
+	}
+	if f.recordSession {
+		opts = append(opts, runtime.WithRecorder(runtime.NewRecorder(defaultRecordingsDir())))
+	}
+
+	localRt, err := runtime.New(t, opts...)
 	if err != nil {
 		return nil, nil, fmt.Errorf("creating runtime: %w", err)
 	}
 
+	workingDir, err := os.Getwd()
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting the working directory: %w", err)
+	}
+
+	if f.resume {
+		summaries, err := sessStore.GetSessionSummaries(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("listing sessions: %w", err)
+		}
+		id, ok := latestSession(summaries, workingDir, agent.Name())
+		if !ok {
+			return nil, nil, fmt.Errorf("no session of agent %s to resume in %s", agent.Name(), workingDir)
+		}
+		f.sessionID = id
+	}
+
 	var sess *session.Session
 	if f.sessionID != "" {
 		// Load existing session
@@ -298,6 +490,7 @@ func (f *runExecFlags) createLocalRuntimeAndSession(ctx context.Context, loadRes
 		}
 		sess.ToolsApproved = f.autoApprove
 		sess.HideToolResults = f.hideToolResults
+		sess.TimeLimit = f.timeLimit
 
 		// Apply any stored model overrides from the session
 		if len(sess.AgentModelOverrides) > 0 {
@@ -314,6 +507,8 @@ func (f *runExecFlags) createLocalRuntimeAndSession(ctx context.Context, loadRes
 			session.WithMaxIterations(agent.MaxIterations()),
 			session.WithToolsApproved(f.autoApprove),
 			session.WithHideToolResults(f.hideToolResults),
+			session.WithTimeLimit(f.timeLimit),
+			session.WithWorkingDir(workingDir),
 		)
 		// Session is stored lazily on first UpdateSession call (when content is added)
 		// This avoids creating empty sessions in the database
@@ -323,6 +518,17 @@ func (f *runExecFlags) createLocalRuntimeAndSession(ctx context.Context, loadRes
 	return localRt, sess, nil
 }
 
+// latestSession returns the most recent of the sessions of an agent run
+// from a working directory. The summaries are sorted from the most recent.
+func latestSession(summaries []session.Summary, workingDir, agentName string) (string, bool) {
+	for _, summary := range summaries {
+		if summary.WorkingDir == workingDir && summary.AgentName == agentName {
+			return summary.ID, true
+		}
+	}
+	return "", false
+}
+
 func (f *runExecFlags) handleExecMode(ctx context.Context, out *cli.Printer, rt runtime.Runtime, sess *session.Session, args []string) error {
 	execArgs := []string{"exec"}
 	if len(args) == 2 {
@@ -331,13 +537,28 @@ func (f *runExecFlags) handleExecMode(ctx context.Context, out *cli.Printer, rt
 		execArgs = append(execArgs, "Please proceed.")
 	}
 
-	err := cli.Run(ctx, out, cli.Config{
+	cfg := cli.Config{
 		AppName:        AppName,
 		AttachmentPath: f.attachmentPath,
 		HideToolCalls:  f.hideToolCalls,
+		HideSubAgents:  f.hideSubAgents,
 		OutputJSON:     f.outputJSON,
+		OutputJSONL:    f.outputJSONL,
+		ShowUsage:      f.showUsage,
 		AutoApprove:    f.autoApprove,
-	}, rt, sess, execArgs)
+	}
+
+	if f.reviewQueue {
+		queue, reviewStore, err := openReviewQueue(f.reviewDB, f.reviewTTL, f.reviewOnExpiry)
+		if err != nil {
+			return err
+		}
+		defer reviewStore.Close()
+
+		cfg.Review = queue
+	}
+
+	err := cli.Run(ctx, out, cfg, rt, sess, execArgs)
 	if cliErr, ok := err.(cli.RuntimeError); ok {
 		return RuntimeError{Err: cliErr.Err}
 	}
@@ -361,7 +582,7 @@ func readInitialMessage(args []string) (*string, error) {
 	return &args[1], nil
 }
 
-func (f *runExecFlags) handleRunMode(ctx context.Context, rt runtime.Runtime, sess *session.Session, args []string) error {
+func (f *runExecFlags) handleRunMode(ctx context.Context, rt runtime.Runtime, sess *session.Session, agentFile string, args []string) error {
 	firstMessage, err := readInitialMessage(args)
 	if err != nil {
 		return err
@@ -374,6 +595,17 @@ func (f *runExecFlags) handleRunMode(ctx context.Context, rt runtime.Runtime, se
 	if f.attachmentPath != "" {
 		opts = append(opts, app.WithFirstMessageAttachment(f.attachmentPath))
 	}
+	if agentFile != "" {
+		opts = append(opts, app.WithAgentFileWatch(agentFile))
+	}
+
+	var tuiOpts []tui.Opt
+	if f.pickSession {
+		tuiOpts = append(tuiOpts, tui.WithSessionPicker())
+	}
+	if f.hideSubAgents {
+		tuiOpts = append(tuiOpts, tui.WithSubAgentsHidden())
+	}
 
-	return runTUI(ctx, rt, sess, opts...)
+	return runTUI(ctx, rt, sess, opts, tuiOpts...)
 }
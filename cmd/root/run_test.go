@@ -0,0 +1,53 @@
+package root
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/session"
+)
+
+func TestResolveOutputMode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		output   string
+		terminal bool
+		expected string
+	}{
+		{output: "", terminal: true, expected: outputTUI},
+		{output: "", terminal: false, expected: outputPlain},
+		{output: "plain", terminal: true, expected: outputPlain},
+		{output: "json", terminal: true, expected: outputJSON},
+		{output: "jsonl", terminal: false, expected: outputJSONL},
+		{output: "tui", terminal: false, expected: outputTUI},
+	}
+	for _, tt := range tests {
+		mode, err := resolveOutputMode(tt.output, tt.terminal)
+		require.NoError(t, err)
+		assert.Equal(t, tt.expected, mode)
+	}
+
+	_, err := resolveOutputMode("html", true)
+	require.ErrorContains(t, err, `invalid output mode "html"`)
+}
+
+func TestLatestSession(t *testing.T) {
+	t.Parallel()
+
+	summaries := []session.Summary{
+		{ID: "other-dir", AgentName: "root", WorkingDir: "/other"},
+		{ID: "other-agent", AgentName: "reviewer", WorkingDir: "/project"},
+		{ID: "latest", AgentName: "root", WorkingDir: "/project"},
+		{ID: "older", AgentName: "root", WorkingDir: "/project"},
+	}
+
+	id, ok := latestSession(summaries, "/project", "root")
+	require.True(t, ok)
+	assert.Equal(t, "latest", id)
+
+	_, ok = latestSession(summaries, "/elsewhere", "root")
+	assert.False(t, ok)
+}
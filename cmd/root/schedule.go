@@ -0,0 +1,311 @@
+package root
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/cagent/pkg/cli"
+	"github.com/docker/cagent/pkg/config"
+	"github.com/docker/cagent/pkg/paths"
+	"github.com/docker/cagent/pkg/review"
+	"github.com/docker/cagent/pkg/schedule"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/telemetry"
+)
+
+func defaultScheduleDB() string {
+	return filepath.Join(paths.GetHomeDir(), ".cagent", "schedules.db")
+}
+
+type scheduleFlags struct {
+	scheduleDB string
+
+	// add
+	agentName   string
+	prompt      string
+	notify      []string
+	autoApprove bool
+
+	// daemon
+	sessionDB      string
+	reviewDB       string
+	reviewTTL      time.Duration
+	reviewOnExpiry string
+	runConfig      config.RuntimeConfig
+}
+
+func newScheduleCmd() *cobra.Command {
+	var flags scheduleFlags
+
+	cmd := &cobra.Command{
+		Use:   "schedule",
+		Short: "Run agents on a schedule",
+		Long: `Run agents on cron expressions, such as a triage agent every morning.
+
+Schedules are run by "cagent schedule daemon", which keeps each run as a
+session and notifies the channels of the schedule of its result.`,
+		Example: `  # Triage the issues every day at 9am
+  cagent schedule add "0 9 * * *" ./triage.yaml --prompt "Triage the new issues" --notify desktop
+
+  # List the schedules
+  cagent schedule list
+
+  # Run the schedules
+  cagent schedule daemon`,
+		GroupID: "advanced",
+	}
+
+	cmd.PersistentFlags().StringVar(&flags.scheduleDB, "schedule-db", defaultScheduleDB(), "Path to the schedules database")
+
+	addCmd := &cobra.Command{
+		Use:   "add <cron> <agent-file>|<registry-ref>",
+		Short: "Schedule an agent",
+		Args:  cobra.ExactArgs(2),
+		RunE:  flags.runScheduleAddCommand,
+	}
+	addCmd.Flags().StringVarP(&flags.agentName, "agent", "a", "root", "Name of the agent to run")
+	addCmd.Flags().StringVar(&flags.prompt, "prompt", "", "Message sent to the agent on each run")
+	addCmd.Flags().StringArrayVar(&flags.notify, "notify", nil, "Notify the result of each run: desktop, or the URL of a webhook (repeatable)")
+	addCmd.Flags().BoolVar(&flags.autoApprove, "yolo", false, "Automatically approve all tool calls, which are queued for review otherwise")
+	_ = addCmd.MarkFlagRequired("prompt")
+
+	listCmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the schedules",
+		Args:    cobra.NoArgs,
+		RunE:    flags.runScheduleListCommand,
+	}
+
+	removeCmd := &cobra.Command{
+		Use:     "remove <id>",
+		Aliases: []string{"rm"},
+		Short:   "Remove a schedule",
+		Args:    cobra.ExactArgs(1),
+		RunE:    flags.runScheduleRemoveCommand,
+	}
+
+	daemonCmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "Run the schedules when they are due",
+		Long: `Run the schedules when they are due, until interrupted. Schedules added
+while the daemon runs are picked up within a minute. A schedule that was due
+while the daemon wasn't running is run once when it starts.
+
+Tool calls requiring approval are queued for review, see "cagent review".`,
+		Args: cobra.NoArgs,
+		RunE: flags.runScheduleDaemonCommand,
+	}
+	daemonCmd.Flags().StringVarP(&flags.sessionDB, "session-db", "s", filepath.Join(paths.GetHomeDir(), ".cagent", "session.db"), "Path to the session database")
+	daemonCmd.Flags().StringVar(&flags.reviewDB, "review-db", defaultReviewDB(), "Path to the review queue database")
+	daemonCmd.Flags().DurationVar(&flags.reviewTTL, "review-ttl", 24*time.Hour, "How long a tool call waits for review before expiring (0 for no expiry)")
+	daemonCmd.Flags().StringVar(&flags.reviewOnExpiry, "review-on-expiry", string(review.ExpiryReject), "What to do when a review expires: reject the tool call or abort the run")
+	addRuntimeConfigFlags(daemonCmd, &flags.runConfig)
+	addPolicyFlags(daemonCmd, &flags.runConfig)
+
+	cmd.AddCommand(addCmd, listCmd, removeCmd, daemonCmd)
+
+	return cmd
+}
+
+func (f *scheduleFlags) runScheduleAddCommand(cmd *cobra.Command, args []string) error {
+	telemetry.TrackCommand("schedule", append([]string{"add"}, args...))
+
+	ctx := cmd.Context()
+	out := cli.NewPrinter(cmd.OutOrStdout())
+
+	workingDir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	// Local agent files are run from the daemon, in another directory
+	agentFile := args[1]
+	if _, err := os.Stat(agentFile); err == nil {
+		if agentFile, err = filepath.Abs(agentFile); err != nil {
+			return err
+		}
+	}
+
+	sched := &schedule.Schedule{
+		ID:          uuid.New().String(),
+		Cron:        args[0],
+		AgentFile:   agentFile,
+		Agent:       f.agentName,
+		Prompt:      f.prompt,
+		WorkingDir:  workingDir,
+		AutoApprove: f.autoApprove,
+		Notify:      f.notify,
+		CreatedAt:   time.Now(),
+	}
+	if err := sched.Validate(); err != nil {
+		return err
+	}
+
+	store, err := schedule.NewSQLiteStore(f.scheduleDB)
+	if err != nil {
+		return fmt.Errorf("opening schedules: %w", err)
+	}
+	defer store.Close()
+
+	if err := store.AddSchedule(ctx, sched); err != nil {
+		return err
+	}
+
+	next, _ := sched.NextRun()
+	out.Printf("Schedule %s added, next run %s\n", sched.ID, formatNextRun(next))
+	return nil
+}
+
+func (f *scheduleFlags) runScheduleListCommand(cmd *cobra.Command, args []string) error {
+	telemetry.TrackCommand("schedule", append([]string{"list"}, args...))
+
+	out := cli.NewPrinter(cmd.OutOrStdout())
+
+	store, err := schedule.NewSQLiteStore(f.scheduleDB)
+	if err != nil {
+		return fmt.Errorf("opening schedules: %w", err)
+	}
+	defer store.Close()
+
+	schedules, err := store.ListSchedules(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	if len(schedules) == 0 {
+		out.Println("No schedules.")
+		return nil
+	}
+
+	for _, s := range schedules {
+		next, _ := s.NextRun()
+		out.Printf("%s  %-15s  %s  agent=%s\n", s.ID, s.Cron, s.AgentFile, s.Agent)
+		out.Printf("    %s\n", s.Prompt)
+		out.Printf("    next run %s\n", formatNextRun(next))
+		switch {
+		case s.LastRunAt.IsZero():
+		case s.LastError != "":
+			out.Printf("    last run %s failed: %s\n", s.LastRunAt.Local().Format(time.DateTime), s.LastError)
+		default:
+			out.Printf("    last run %s, session=%s\n", s.LastRunAt.Local().Format(time.DateTime), s.LastSessionID)
+		}
+	}
+
+	return nil
+}
+
+func (f *scheduleFlags) runScheduleRemoveCommand(cmd *cobra.Command, args []string) error {
+	telemetry.TrackCommand("schedule", append([]string{"remove"}, args...))
+
+	out := cli.NewPrinter(cmd.OutOrStdout())
+
+	store, err := schedule.NewSQLiteStore(f.scheduleDB)
+	if err != nil {
+		return fmt.Errorf("opening schedules: %w", err)
+	}
+	defer store.Close()
+
+	if err := store.RemoveSchedule(cmd.Context(), args[0]); err != nil {
+		if errors.Is(err, schedule.ErrNotFound) {
+			return fmt.Errorf("schedule %s not found", args[0])
+		}
+		return err
+	}
+
+	out.Printf("Schedule %s removed\n", args[0])
+	return nil
+}
+
+func (f *scheduleFlags) runScheduleDaemonCommand(cmd *cobra.Command, args []string) error {
+	telemetry.TrackCommand("schedule", append([]string{"daemon"}, args...))
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	store, err := schedule.NewSQLiteStore(f.scheduleDB)
+	if err != nil {
+		return fmt.Errorf("opening schedules: %w", err)
+	}
+	defer store.Close()
+
+	sessStore, err := session.NewSQLiteSessionStore(f.sessionDB)
+	if err != nil {
+		return fmt.Errorf("creating session store: %w", err)
+	}
+
+	auditCleanup, err := setupAuditForwarding(ctx, &f.runConfig)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := auditCleanup(); err != nil {
+			slog.Error("Failed to flush audit events", "error", err)
+		}
+	}()
+
+	queue, reviewStore, err := openReviewQueue(f.reviewDB, f.reviewTTL, f.reviewOnExpiry)
+	if err != nil {
+		return err
+	}
+	defer reviewStore.Close()
+
+	slog.Info("Running the schedules", "schedule_db", f.scheduleDB, "review_db", f.reviewDB)
+	return schedule.NewDaemon(store, f.scheduleRunner(sessStore, queue), schedule.NewNotifier()).Run(ctx)
+}
+
+// scheduleRunner runs the agent of a schedule like "cagent exec --review-queue"
+// would, keeping the conversation in the session store.
+func (f *scheduleFlags) scheduleRunner(sessStore session.Store, queue *review.Queue) schedule.Runner {
+	return func(ctx context.Context, s *schedule.Schedule) *schedule.Result {
+		result := &schedule.Result{
+			AgentFile: s.AgentFile,
+			Agent:     s.Agent,
+			Status:    schedule.StatusSucceeded,
+			StartedAt: time.Now(),
+		}
+
+		sess, err := f.runScheduledAgent(ctx, sessStore, queue, s)
+		if sess != nil {
+			result.SessionID = sess.ID
+			result.Answer = sess.GetLastAssistantMessageContent()
+		}
+		if err != nil {
+			result.Status = schedule.StatusFailed
+			result.Error = err.Error()
+		}
+		result.FinishedAt = time.Now()
+		return result
+	}
+}
+
+func (f *scheduleFlags) runScheduledAgent(ctx context.Context, sessStore session.Store, queue *review.Queue, s *schedule.Schedule) (*session.Session, error) {
+	// Nobody is there to approve the tool calls: without --yolo, they wait
+	// in the review queue for a human to decide
+	return runHeadless(ctx, &f.runConfig, headlessRun{
+		agentFile:    s.AgentFile,
+		agentName:    s.Agent,
+		prompt:       s.Prompt,
+		workingDir:   s.WorkingDir,
+		autoApprove:  s.AutoApprove,
+		review:       queue,
+		sessionStore: sessStore,
+	})
+}
+
+func formatNextRun(next time.Time) string {
+	if next.IsZero() {
+		return "never"
+	}
+	return next.Local().Format(time.DateTime)
+}
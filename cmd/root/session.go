@@ -0,0 +1,86 @@
+package root
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/cagent/pkg/app/export"
+	"github.com/docker/cagent/pkg/cli"
+	"github.com/docker/cagent/pkg/paths"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/telemetry"
+)
+
+type sessionFlags struct {
+	sessionDB string
+	output    string
+	format    string
+}
+
+func newSessionCmd() *cobra.Command {
+	var flags sessionFlags
+
+	cmd := &cobra.Command{
+		Use:   "session",
+		Short: "Manage stored sessions",
+		Example: `  # Export a session as HTML
+  cagent session export <session-id>
+
+  # Export a session as Markdown
+  cagent session export <session-id> --output transcript.md`,
+		GroupID: "advanced",
+	}
+
+	cmd.PersistentFlags().StringVarP(&flags.sessionDB, "session-db", "s", filepath.Join(paths.GetHomeDir(), ".cagent", "session.db"), "Path to the session database")
+
+	exportCmd := &cobra.Command{
+		Use:   "export <session-id>",
+		Short: "Export a session as HTML or Markdown",
+		Long: `Export the conversation of a session to a standalone HTML file, or a
+Markdown file. Tool calls are collapsed, and the usage of the session is
+summarized at the top.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSessionExportCommand(cmd, args, &flags)
+		},
+	}
+	exportCmd.Flags().StringVar(&flags.output, "output", "", "File to write (default: named after the session title)")
+	exportCmd.Flags().StringVar(&flags.format, "format", "", "Export format: html or md (default: from the extension of --output, html otherwise)")
+	cmd.AddCommand(exportCmd)
+
+	return cmd
+}
+
+func runSessionExportCommand(cmd *cobra.Command, args []string, flags *sessionFlags) error {
+	telemetry.TrackCommand("session", append([]string{"export"}, args...))
+
+	out := cli.NewPrinter(cmd.OutOrStdout())
+
+	var format export.Format
+	if flags.format != "" {
+		var err error
+		if format, err = export.ParseFormat(flags.format); err != nil {
+			return err
+		}
+	}
+
+	store, err := session.NewSQLiteSessionStore(flags.sessionDB)
+	if err != nil {
+		return fmt.Errorf("opening session store: %w", err)
+	}
+
+	sess, err := store.GetSession(cmd.Context(), args[0])
+	if err != nil {
+		return fmt.Errorf("loading session %s: %w", args[0], err)
+	}
+
+	path, err := export.SessionToFile(sess, "", flags.output, format)
+	if err != nil {
+		return fmt.Errorf("exporting session: %w", err)
+	}
+
+	out.Printf("Session exported to %s\n", path)
+	return nil
+}
@@ -0,0 +1,68 @@
+package root
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// processStart approximates when cagent started: package variables are
+// initialized before main runs.
+var processStart = time.Now()
+
+type startupPhase struct {
+	name     string
+	duration time.Duration
+}
+
+// startupProfile measures how long each phase of the startup takes, for
+// --profile-startup. A nil profile measures nothing.
+type startupProfile struct {
+	last   time.Time
+	phases []startupPhase
+}
+
+func newStartupProfile(enabled bool) *startupProfile {
+	if !enabled {
+		return nil
+	}
+
+	p := &startupProfile{last: processStart}
+	p.mark("initialize")
+	return p
+}
+
+// mark ends the current phase, started when the previous one ended.
+func (p *startupProfile) mark(phase string) {
+	if p == nil {
+		return
+	}
+
+	now := time.Now()
+	p.phases = append(p.phases, startupPhase{name: phase, duration: now.Sub(p.last)})
+	p.last = now
+}
+
+// hook returns the function that marks the end of the phases of a step, such
+// as loading the team, or nil when nothing is measured.
+func (p *startupProfile) hook() func(phase string) {
+	if p == nil {
+		return nil
+	}
+	return p.mark
+}
+
+func (p *startupProfile) report(w io.Writer) {
+	if p == nil {
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PHASE\tDURATION")
+	for _, phase := range p.phases {
+		fmt.Fprintf(tw, "%s\t%s\n", phase.name, phase.duration.Round(time.Microsecond))
+	}
+	fmt.Fprintf(tw, "total\t%s\n", p.last.Sub(processStart).Round(time.Microsecond))
+	_ = tw.Flush()
+}
@@ -0,0 +1,37 @@
+package root
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStartupProfile(t *testing.T) {
+	t.Parallel()
+
+	p := newStartupProfile(true)
+	p.hook()("load config")
+	p.mark("create runtime")
+
+	var buf bytes.Buffer
+	p.report(&buf)
+
+	out := buf.String()
+	assert.Contains(t, out, "initialize")
+	assert.Contains(t, out, "load config")
+	assert.Contains(t, out, "create runtime")
+	assert.Contains(t, out, "total")
+}
+
+func TestStartupProfile_Disabled(t *testing.T) {
+	t.Parallel()
+
+	p := newStartupProfile(false)
+	assert.Nil(t, p.hook())
+	p.mark("load config")
+
+	var buf bytes.Buffer
+	p.report(&buf)
+	assert.Empty(t, buf.String())
+}
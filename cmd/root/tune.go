@@ -0,0 +1,124 @@
+package root
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/cagent/pkg/cli"
+	"github.com/docker/cagent/pkg/config"
+	"github.com/docker/cagent/pkg/paths"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/teamloader"
+	"github.com/docker/cagent/pkg/telemetry"
+	"github.com/docker/cagent/pkg/tuning"
+)
+
+type tuneFlags struct {
+	agentName string
+	sessionDB string
+	runConfig config.RuntimeConfig
+}
+
+func newTuneCmd() *cobra.Command {
+	var flags tuneFlags
+
+	cmd := &cobra.Command{
+		Use:   "tune <agent-file>",
+		Short: "Propose instruction edits from the feedback on an agent's answers",
+		Long: `Analyze the feedback users gave on the answers of an agent (rated up or
+down in the TUI, with + and -) across all stored sessions, and propose edits
+to the instruction of the agent. The edits are printed as a unified diff of
+the agent file, to review and apply with "git apply" or "patch".`,
+		Example: `  # Propose edits to the instruction of the root agent
+  cagent tune agent.yaml
+
+  # Tune another agent of the team, and apply the edits
+  cagent tune agent.yaml --agent writer > tune.diff && git apply tune.diff`,
+		Args:    cobra.ExactArgs(1),
+		GroupID: "advanced",
+		RunE:    flags.runTuneCommand,
+	}
+
+	cmd.Flags().StringVarP(&flags.agentName, "agent", "a", "root", "Name of the agent to tune")
+	cmd.Flags().StringVarP(&flags.sessionDB, "session-db", "s", filepath.Join(paths.GetHomeDir(), ".cagent", "session.db"), "Path to the session database")
+	addRuntimeConfigFlags(cmd, &flags.runConfig)
+
+	return cmd
+}
+
+func (f *tuneFlags) runTuneCommand(cmd *cobra.Command, args []string) error {
+	telemetry.TrackCommand("tune", args)
+
+	ctx := cmd.Context()
+	agentFilename := args[0]
+	out := cli.NewPrinter(cmd.OutOrStdout())
+
+	store, err := session.NewSQLiteSessionStore(f.sessionDB)
+	if err != nil {
+		return fmt.Errorf("opening session store: %w", err)
+	}
+
+	sessions, err := store.GetSessions(ctx)
+	if err != nil {
+		return fmt.Errorf("loading sessions: %w", err)
+	}
+
+	feedback := tuning.Collect(sessions, f.agentName)
+	if len(feedback) == 0 {
+		out.Printf("No feedback on the answers of agent %s yet. Rate answers in the TUI with + and -.\n", f.agentName)
+		return nil
+	}
+
+	agentSource, err := config.Resolve(agentFilename)
+	if err != nil {
+		return err
+	}
+
+	original, err := agentSource.Read(ctx)
+	if err != nil {
+		return err
+	}
+
+	instruction, err := tuning.Instruction(original, f.agentName)
+	if err != nil {
+		return err
+	}
+
+	team, err := teamloader.Load(ctx, agentSource, &f.runConfig)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := team.StopToolSets(ctx); err != nil {
+			slog.Error("Failed to stop tool sets", "error", err)
+		}
+	}()
+
+	agent, err := team.Agent(f.agentName)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Proposing instruction edits", "agent", f.agentName, "feedback", len(feedback))
+	proposed, err := tuning.Propose(ctx, agent.Model(), instruction, feedback)
+	if err != nil {
+		return err
+	}
+
+	edited, err := tuning.ReplaceInstruction(original, f.agentName, proposed)
+	if err != nil {
+		return err
+	}
+
+	diff := tuning.Diff(filepath.Base(agentFilename), original, edited)
+	if diff == "" {
+		out.Printf("No edits proposed for agent %s.\n", f.agentName)
+		return nil
+	}
+
+	out.Print(diff)
+	return nil
+}
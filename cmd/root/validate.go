@@ -0,0 +1,75 @@
+package root
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/cagent/pkg/cli"
+	"github.com/docker/cagent/pkg/config"
+	"github.com/docker/cagent/pkg/telemetry"
+)
+
+type validateFlags struct {
+	runConfig config.RuntimeConfig
+}
+
+func newValidateCmd() *cobra.Command {
+	var flags validateFlags
+
+	cmd := &cobra.Command{
+		Use:   "validate <agent-file>|<registry-ref>...",
+		Short: "Validate agent configuration files",
+		Long:  "Validate agent configuration files, and check that their models comply with the model allowlist",
+		Example: `  cagent validate ./agent.yaml
+  cagent validate --model-allowlist ./allowlist.yaml ./agents/*.yaml`,
+		GroupID: "advanced",
+		Args:    cobra.MinimumNArgs(1),
+		RunE:    flags.runValidateCommand,
+	}
+
+	addGatewayFlags(cmd, &flags.runConfig)
+
+	return cmd
+}
+
+func (f *validateFlags) runValidateCommand(cmd *cobra.Command, args []string) error {
+	telemetry.TrackCommand("validate", args)
+
+	ctx := cmd.Context()
+	out := cli.NewPrinter(cmd.OutOrStdout())
+
+	var invalid int
+	for _, agentFilename := range args {
+		if err := f.validate(ctx, agentFilename); err != nil {
+			invalid++
+			out.Printf("✗ %s\n%v\n", agentFilename, err)
+			continue
+		}
+		out.Printf("✓ %s\n", agentFilename)
+	}
+
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d agent configuration(s) are not valid", invalid, len(args))
+	}
+	return nil
+}
+
+func (f *validateFlags) validate(ctx context.Context, agentFilename string) error {
+	agentSource, err := config.Resolve(agentFilename)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(ctx, agentSource)
+	if err != nil {
+		return err
+	}
+
+	if err := f.runConfig.ModelAllowlist.CheckConfig(cfg); err != nil {
+		return fmt.Errorf("not allowed by the model allowlist:\n%w", err)
+	}
+
+	return nil
+}
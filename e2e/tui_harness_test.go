@@ -0,0 +1,279 @@
+package e2e_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/exp/teatest/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/app"
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/model/provider/base"
+	"github.com/docker/cagent/pkg/modelsdev"
+	"github.com/docker/cagent/pkg/runtime"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/team"
+	"github.com/docker/cagent/pkg/tools"
+	"github.com/docker/cagent/pkg/tui"
+)
+
+// reply is what the scripted model answers to one request.
+type reply []chat.MessageStreamResponse
+
+// textReply answers with text, and the usage of the request.
+func textReply(text string, inputTokens, outputTokens int64) reply {
+	return reply{
+		{Choices: []chat.MessageStreamChoice{{Delta: chat.MessageDelta{Content: text}}}},
+		{
+			Choices: []chat.MessageStreamChoice{{FinishReason: chat.FinishReasonStop}},
+			Usage:   &chat.Usage{InputTokens: inputTokens, OutputTokens: outputTokens},
+		},
+	}
+}
+
+// toolCallReply answers with a call of a tool.
+func toolCallReply(id, name, arguments string) reply {
+	return reply{
+		{Choices: []chat.MessageStreamChoice{{Delta: chat.MessageDelta{ToolCalls: []tools.ToolCall{{
+			ID:       id,
+			Type:     "function",
+			Function: tools.FunctionCall{Name: name, Arguments: arguments},
+		}}}}}},
+		{Choices: []chat.MessageStreamChoice{{FinishReason: chat.FinishReasonToolCalls}}},
+	}
+}
+
+// scriptedProvider is a model answering the requests with the scripted
+// replies, in order.
+type scriptedProvider struct {
+	mu      sync.Mutex
+	replies []reply
+}
+
+func (p *scriptedProvider) ID() string { return "mock/scripted" }
+
+func (p *scriptedProvider) BaseConfig() base.Config { return base.Config{} }
+
+func (p *scriptedProvider) CreateChatCompletionStream(context.Context, []chat.Message, []tools.Tool) (chat.MessageStream, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.replies) == 0 {
+		return nil, errors.New("no more scripted replies")
+	}
+	next := p.replies[0]
+	p.replies = p.replies[1:]
+	return &scriptedStream{responses: next}, nil
+}
+
+type scriptedStream struct {
+	responses []chat.MessageStreamResponse
+}
+
+func (s *scriptedStream) Recv() (chat.MessageStreamResponse, error) {
+	if len(s.responses) == 0 {
+		return chat.MessageStreamResponse{}, io.EOF
+	}
+	next := s.responses[0]
+	s.responses = s.responses[1:]
+	return next, nil
+}
+
+func (s *scriptedStream) Close() {}
+
+type toolSet struct {
+	tools.BaseToolSet
+	tools []tools.Tool
+}
+
+func (s *toolSet) Tools(context.Context) ([]tools.Tool, error) { return s.tools, nil }
+
+// frameRecorder keeps the last frame rendered by a model, without its escape
+// sequences. The terminal output can't be searched for text: only the cells
+// that changed since the previous frame are written.
+type frameRecorder struct {
+	tea.Model
+
+	mu    sync.Mutex
+	frame string
+}
+
+func (r *frameRecorder) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	r.Model, cmd = r.Model.Update(msg)
+	return r, cmd
+}
+
+func (r *frameRecorder) View() tea.View {
+	view := r.Model.View()
+
+	r.mu.Lock()
+	r.frame = ansi.Strip(view.Content)
+	r.mu.Unlock()
+
+	return view
+}
+
+func (r *frameRecorder) lastFrame() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.frame
+}
+
+// harness runs the whole TUI, with its app and runtime, in a virtual
+// terminal. The test types keys and waits for text to be on screen.
+type harness struct {
+	t      *testing.T
+	tm     *teatest.TestModel
+	frames *frameRecorder
+	store  session.Store
+}
+
+type harnessOptions struct {
+	tools   []tools.Tool
+	session *session.Session
+}
+
+type harnessOpt func(*harnessOptions)
+
+// withTools gives tools to the agent.
+func withTools(agentTools ...tools.Tool) harnessOpt {
+	return func(o *harnessOptions) { o.tools = agentTools }
+}
+
+// withSession starts the TUI on a session other than a new one.
+func withSession(sess *session.Session) harnessOpt {
+	return func(o *harnessOptions) { o.session = sess }
+}
+
+// newHarness starts the TUI on an agent answering with the scripted replies.
+// The user config is read from an empty home directory, so the tests can't
+// run in parallel.
+func newHarness(t *testing.T, replies []reply, opts ...harnessOpt) *harness {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+
+	var options harnessOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	root := agent.New("root", "You are a test agent",
+		agent.WithModel(&scriptedProvider{replies: replies}),
+		agent.WithToolSets(&toolSet{tools: options.tools}),
+	)
+	store := session.NewInMemorySessionStore()
+	rt, err := runtime.New(team.New(team.WithAgents(root)),
+		runtime.WithSessionCompaction(false),
+		runtime.WithSessionStore(store),
+		runtime.WithModelStore(noModels{}),
+	)
+	require.NoError(t, err)
+
+	sess := options.session
+	if sess == nil {
+		// A title, so that none is generated with the scripted replies
+		sess = session.New(session.WithTitle("Harness"))
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	a := app.New(ctx, rt, sess)
+	frames := &frameRecorder{Model: tui.New(ctx, a)}
+	tm := teatest.NewTestModel(t, frames, teatest.WithInitialTermSize(140, 45))
+	go a.Subscribe(ctx, tm.GetProgram())
+
+	t.Cleanup(func() {
+		_ = tm.Quit()
+		cancel()
+	})
+
+	return &harness{t: t, tm: tm, frames: frames, store: store}
+}
+
+// typeText types text in the focused component.
+func (h *harness) typeText(text string) {
+	h.tm.Type(text)
+}
+
+// press presses a key, like tea.KeyEnter or 'y'.
+func (h *harness) press(code rune, mod ...tea.KeyMod) {
+	msg := tea.KeyPressMsg{Code: code}
+	for _, m := range mod {
+		msg.Mod |= m
+	}
+	if code >= ' ' && code < tea.KeyExtended && len(mod) == 0 {
+		msg.Text = string(code)
+	}
+	h.tm.Send(msg)
+}
+
+// send types a message and sends it to the agent.
+func (h *harness) send(text string) {
+	h.typeText(text)
+	h.press(tea.KeyEnter)
+}
+
+// waitFor waits until all the texts are on screen.
+func (h *harness) waitFor(texts ...string) {
+	h.t.Helper()
+	h.waitForScreen(func(screen string) bool {
+		return !slices.ContainsFunc(texts, func(text string) bool { return !strings.Contains(screen, text) })
+	}, "%q on screen", texts)
+}
+
+// waitForGone waits until none of the texts are on screen.
+func (h *harness) waitForGone(texts ...string) {
+	h.t.Helper()
+	h.waitForScreen(func(screen string) bool {
+		return !slices.ContainsFunc(texts, func(text string) bool { return strings.Contains(screen, text) })
+	}, "%q gone from the screen", texts)
+}
+
+func (h *harness) waitForScreen(condition func(screen string) bool, format string, args ...any) {
+	h.t.Helper()
+
+	deadline := time.Now().Add(waitTimeout)
+	for {
+		screen := h.frames.lastFrame()
+		if condition(screen) {
+			return
+		}
+		if time.Now().After(deadline) {
+			h.t.Fatalf("timed out waiting for "+format+", screen:\n%s", append(args, screen)...)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// waitForSession waits until the session, in the store, satisfies the condition.
+func (h *harness) waitForSession(id string, condition func(*session.Session) bool) *session.Session {
+	h.t.Helper()
+
+	deadline := time.Now().Add(waitTimeout)
+	for time.Now().Before(deadline) {
+		if sess, err := h.store.GetSession(h.t.Context(), id); err == nil && condition(sess) {
+			return sess
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	h.t.Fatalf("session %s never reached the expected state", id)
+	return nil
+}
+
+// waitTimeout is how long the harness waits for the TUI to get in the
+// expected state.
+const waitTimeout = 10 * time.Second
+
+type noModels struct{}
+
+func (noModels) GetModel(context.Context, string) (*modelsdev.Model, error) { return nil, nil }
@@ -0,0 +1,96 @@
+package e2e_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/tools"
+)
+
+func TestTUI_ChatShowsUsageInSidebar(t *testing.T) {
+	h := newHarness(t, []reply{
+		textReply("Hello from the scripted model", 1200, 34),
+		textReply("Still here", 800, 20),
+	})
+
+	h.send("Hi")
+	h.waitFor("Hello from the scripted model", "1.2K (0%) $0.00")
+
+	h.send("Are you there?")
+	h.waitFor("Still here", "820 (0%) $0.00")
+}
+
+// runCommand is a tool that needs the user's approval.
+func runCommand(calls chan<- string) tools.Tool {
+	return tools.Tool{
+		Name:       "run_command",
+		Parameters: map[string]any{"type": "object"},
+		Handler: func(_ context.Context, toolCall tools.ToolCall) (*tools.ToolCallResult, error) {
+			calls <- toolCall.Function.Arguments
+			return tools.ResultSuccess("ok"), nil
+		},
+	}
+}
+
+func TestTUI_ApproveToolCall(t *testing.T) {
+	calls := make(chan string, 1)
+	h := newHarness(t, []reply{
+		toolCallReply("call_1", "run_command", `{"cmd":"make test"}`),
+		textReply("The tests pass", 100, 10),
+	}, withTools(runCommand(calls)))
+
+	h.send("Run the tests")
+	h.waitFor("Tool Confirmation", "Runs: make test", "always allow this tool for root")
+
+	h.press('y')
+	h.waitForGone("Tool Confirmation")
+	h.waitFor("The tests pass")
+
+	select {
+	case args := <-calls:
+		assert.JSONEq(t, `{"cmd":"make test"}`, args)
+	case <-time.After(waitTimeout):
+		t.Fatal("the tool was never called")
+	}
+}
+
+func TestTUI_RejectToolCall(t *testing.T) {
+	calls := make(chan string, 1)
+	h := newHarness(t, []reply{
+		toolCallReply("call_1", "run_command", `{"cmd":"rm -rf /"}`),
+		textReply("I won't run it", 100, 10),
+	}, withTools(runCommand(calls)))
+
+	h.send("Clean up")
+	h.waitFor("Tool Confirmation", "Runs: rm -rf /")
+
+	h.press('n')
+	h.waitFor("I won't run it")
+	assert.Empty(t, calls)
+}
+
+func TestTUI_SwitchSession(t *testing.T) {
+	h := newHarness(t, nil)
+
+	earlier := session.New(session.WithTitle("Earlier chat"))
+	earlier.AddMessage(session.UserMessage("What did we do yesterday?"))
+	earlier.AddMessage(session.NewAgentMessage(agent.New("root", ""), &chat.Message{
+		Role:    chat.MessageRoleAssistant,
+		Content: "We fixed the flaky tests",
+	}))
+	require.NoError(t, h.store.AddSession(t.Context(), earlier))
+
+	h.press('f', tea.ModCtrl)
+	h.waitFor("Earlier chat")
+
+	h.press(tea.KeyEnter)
+	h.waitFor("We fixed the flaky tests")
+}
@@ -12,13 +12,15 @@ import (
 	"google.golang.org/genai"
 
 	cagent "github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/disclosure"
 	"github.com/docker/cagent/pkg/runtime"
 	"github.com/docker/cagent/pkg/session"
 	"github.com/docker/cagent/pkg/team"
 )
 
-// newCAgentAdapter creates a new ADK agent adapter from a cagent team and agent name
-func newCAgentAdapter(t *team.Team, agentName string) (agent.Agent, error) {
+// newCAgentAdapter creates a new ADK agent adapter from a cagent team and agent name.
+// The disclosure footer, if any, is appended to the final response.
+func newCAgentAdapter(t *team.Team, agentName string, footer *disclosure.Footer) (agent.Agent, error) {
 	a, err := t.Agent(agentName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get agent %s: %w", agentName, err)
@@ -30,13 +32,13 @@ func newCAgentAdapter(t *team.Team, agentName string) (agent.Agent, error) {
 		Name:        agentName,
 		Description: desc,
 		Run: func(ctx agent.InvocationContext) iter.Seq2[*adksession.Event, error] {
-			return runCAgent(ctx, t, agentName, a)
+			return runCAgent(ctx, t, agentName, a, footer)
 		},
 	})
 }
 
 // runCAgent executes a cagent agent and returns ADK session events
-func runCAgent(ctx agent.InvocationContext, t *team.Team, agentName string, a *cagent.Agent) iter.Seq2[*adksession.Event, error] {
+func runCAgent(ctx agent.InvocationContext, t *team.Team, agentName string, a *cagent.Agent, footer *disclosure.Footer) iter.Seq2[*adksession.Event, error] {
 	return func(yield func(*adksession.Event, error) bool) {
 		// Extract user message from the ADK context
 		userContent := ctx.UserContent()
@@ -101,7 +103,7 @@ func runCAgent(ctx agent.InvocationContext, t *team.Team, agentName string, a *c
 					finalEvent := &adksession.Event{
 						Author: agentName,
 						LLMResponse: model.LLMResponse{
-							Content:      genai.NewContentFromParts([]*genai.Part{{Text: contentBuilder}}, genai.RoleModel),
+							Content:      genai.NewContentFromParts([]*genai.Part{{Text: footer.Apply(contentBuilder, agentName)}}, genai.RoleModel),
 							Partial:      false,
 							TurnComplete: true,
 							FinishReason: genai.FinishReasonStop,
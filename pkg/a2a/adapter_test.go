@@ -23,7 +23,7 @@ func TestNewCAgentAdapter(t *testing.T) {
 		require.NoError(t, team.StopToolSets(t.Context()))
 	}()
 
-	adapter, err := newCAgentAdapter(team, "root")
+	adapter, err := newCAgentAdapter(team, "root", nil)
 
 	require.NoError(t, err)
 	assert.Equal(t, "root", adapter.Name())
@@ -42,7 +42,7 @@ func TestNewCAgentAdapter_NonExistent(t *testing.T) {
 		require.NoError(t, team.StopToolSets(t.Context()))
 	}()
 
-	_, err = newCAgentAdapter(team, "nonexistent")
+	_, err = newCAgentAdapter(team, "nonexistent", nil)
 
 	assert.Contains(t, err.Error(), "failed to get agent")
 }
@@ -41,7 +41,7 @@ func Run(ctx context.Context, agentFilename, agentName string, runConfig *config
 		}
 	}()
 
-	adkAgent, err := newCAgentAdapter(t, agentName)
+	adkAgent, err := newCAgentAdapter(t, agentName, &runConfig.Disclosure)
 	if err != nil {
 		return fmt.Errorf("failed to create ADK agent adapter: %w", err)
 	}
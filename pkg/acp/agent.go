@@ -18,6 +18,7 @@ import (
 	"github.com/docker/cagent/pkg/session"
 	"github.com/docker/cagent/pkg/team"
 	"github.com/docker/cagent/pkg/teamloader"
+	"github.com/docker/cagent/pkg/todo"
 	"github.com/docker/cagent/pkg/tools"
 	"github.com/docker/cagent/pkg/tools/builtin"
 	"github.com/docker/cagent/pkg/version"
@@ -706,9 +707,9 @@ func isTodoTool(toolName string) bool {
 // buildPlanUpdateFromTodos converts todo metadata to an ACP plan update
 func buildPlanUpdateFromTodos(meta any) *acp.SessionUpdate {
 	// Meta should be a slice of todos
-	todos, ok := meta.([]builtin.Todo)
+	todos, ok := meta.([]todo.Todo)
 	if !ok {
-		slog.Debug("Todo meta is not []builtin.Todo", "type", fmt.Sprintf("%T", meta))
+		slog.Debug("Todo meta is not []todo.Todo", "type", fmt.Sprintf("%T", meta))
 		return nil
 	}
 
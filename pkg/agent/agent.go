@@ -1,40 +1,61 @@
 package agent
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"log/slog"
 	"math/rand"
+	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/docker/cagent/pkg/config/latest"
 	"github.com/docker/cagent/pkg/config/types"
+	"github.com/docker/cagent/pkg/guardrails"
 	"github.com/docker/cagent/pkg/model/provider"
 	"github.com/docker/cagent/pkg/tools"
 )
 
 // Agent represents an AI agent
 type Agent struct {
-	name               string
-	description        string
-	welcomeMessage     string
-	instruction        string
-	toolsets           []*StartableToolSet
-	models             []provider.Provider
-	modelOverrides     atomic.Pointer[[]provider.Provider] // Optional model override(s) set at runtime (supports alloy)
-	subAgents          []*Agent
-	handoffs           []*Agent
-	parents            []*Agent
-	addDate            bool
-	addEnvironmentInfo bool
-	maxIterations      int
-	numHistoryItems    int
-	addPromptFiles     []string
-	tools              []tools.Tool
-	commands           types.Commands
-	pendingWarnings    []string
-	skillsEnabled      bool
-	hooks              *latest.HooksConfig
+	name                string
+	description         string
+	welcomeMessage      string
+	instruction         string
+	toolsetsMu          sync.RWMutex // Protects toolsets, replaced when the tools are reloaded
+	toolsets            []*StartableToolSet
+	models              []provider.Provider
+	modelOverrides      atomic.Pointer[[]provider.Provider] // Optional model override(s) set at runtime (supports alloy)
+	subAgents           []*Agent
+	handoffs            []*Agent
+	parents             []*Agent
+	addDate             bool
+	addEnvironmentInfo  bool
+	maxIterations       int
+	numHistoryItems     int
+	addPromptFiles      []string
+	tools               []tools.Tool
+	commands            types.Commands
+	pendingWarnings     []string
+	skillsEnabled       bool
+	hooks               *latest.HooksConfig
+	confirmUntrusted    bool
+	summaryModel        provider.Provider
+	summaryThreshold    int
+	governor            *latest.GovernorConfig
+	priority            int
+	maxParallelTools    int
+	maxRepeatedCalls    int
+	maxTurnTokens       int
+	compactionModel     provider.Provider
+	compactionThreshold float64
+	reportConfidence    bool
+	verifyLowConfidence bool
+	escalationModel     provider.Provider
+	retry               *RetryPolicy
+	streamTimeout       time.Duration
+	guardrails          []*guardrails.Guardrail
 }
 
 // New creates a new agent
@@ -174,12 +195,55 @@ func (a *Agent) Hooks() *latest.HooksConfig {
 	return a.hooks
 }
 
+// ConfirmUntrusted returns whether tool calls made after untrusted content
+// entered the conversation always require user confirmation.
+func (a *Agent) ConfirmUntrusted() bool {
+	return a.confirmUntrusted
+}
+
+// ToolResultSummarization returns the model used to summarize large tool results
+// and the estimated number of tokens above which they are summarized.
+// A zero threshold means tool results are never summarized.
+func (a *Agent) ToolResultSummarization() (provider.Provider, int) {
+	return a.summaryModel, a.summaryThreshold
+}
+
+// defaultCompactionThreshold is the fraction of the context limit above which
+// sessions are compacted when the agent doesn't set one.
+const defaultCompactionThreshold = 0.9
+
+// Compaction returns the model that summarizes the sessions nearing the context
+// limit, nil for the agent's model, and the fraction of the context limit above
+// which they are compacted.
+func (a *Agent) Compaction() (provider.Provider, float64) {
+	return a.compactionModel, cmp.Or(a.compactionThreshold, defaultCompactionThreshold)
+}
+
+// ReportsConfidence returns whether the agent reports its confidence in its
+// final answers.
+func (a *Agent) ReportsConfidence() bool {
+	return a.reportConfidence
+}
+
+// LowConfidenceVerification returns whether the answers the agent has a low
+// confidence in are verified, and the model that verifies them, nil for the
+// agent's model.
+func (a *Agent) LowConfidenceVerification() (bool, provider.Provider) {
+	return a.verifyLowConfidence, a.escalationModel
+}
+
+// Guardrails returns the guardrails checking the answers of the agent and the
+// arguments of its tool calls, in order.
+func (a *Agent) Guardrails() []*guardrails.Guardrail {
+	return a.guardrails
+}
+
 // Tools returns the tools available to this agent
 func (a *Agent) Tools(ctx context.Context) ([]tools.Tool, error) {
 	a.ensureToolSetsAreStarted(ctx)
 
 	var agentTools []tools.Tool
-	for _, toolSet := range a.toolsets {
+	for _, toolSet := range a.startableToolSets() {
 		if !toolSet.IsStarted() {
 			// Toolset failed to start; skip it
 			continue
@@ -201,15 +265,38 @@ func (a *Agent) Tools(ctx context.Context) ([]tools.Tool, error) {
 func (a *Agent) ToolSets() []tools.ToolSet {
 	var toolSets []tools.ToolSet
 
-	for _, ts := range a.toolsets {
+	for _, ts := range a.startableToolSets() {
 		toolSets = append(toolSets, ts)
 	}
 
 	return toolSets
 }
 
+func (a *Agent) startableToolSets() []*StartableToolSet {
+	a.toolsetsMu.RLock()
+	defer a.toolsetsMu.RUnlock()
+	return a.toolsets
+}
+
+// ReplaceToolSets gives new toolsets to the agent, for example once its
+// configuration changed, and stops the previous ones. The next turns use the
+// new toolsets, which are started when their tools are first listed.
+func (a *Agent) ReplaceToolSets(ctx context.Context, toolSet ...tools.ToolSet) error {
+	var replacements []*StartableToolSet
+	for _, ts := range toolSet {
+		replacements = append(replacements, &StartableToolSet{ToolSet: ts})
+	}
+
+	a.toolsetsMu.Lock()
+	previous := a.toolsets
+	a.toolsets = replacements
+	a.toolsetsMu.Unlock()
+
+	return stopToolSets(ctx, previous)
+}
+
 func (a *Agent) ensureToolSetsAreStarted(ctx context.Context) {
-	for _, toolSet := range a.toolsets {
+	for _, toolSet := range a.startableToolSets() {
 		if err := toolSet.Start(ctx); err != nil {
 			slog.Warn("Toolset start failed; skipping", "agent", a.Name(), "toolset", fmt.Sprintf("%T", toolSet.ToolSet), "error", err)
 			a.addToolWarning(fmt.Sprintf("%T start failed: %v", toolSet.ToolSet, err))
@@ -237,7 +324,11 @@ func (a *Agent) DrainWarnings() []string {
 }
 
 func (a *Agent) StopToolSets(ctx context.Context) error {
-	for _, toolSet := range a.toolsets {
+	return stopToolSets(ctx, a.startableToolSets())
+}
+
+func stopToolSets(ctx context.Context, toolSets []*StartableToolSet) error {
+	for _, toolSet := range toolSets {
 		// Only stop toolsets that were successfully started
 		if !toolSet.IsStarted() {
 			continue
@@ -250,3 +341,56 @@ func (a *Agent) StopToolSets(ctx context.Context) error {
 
 	return nil
 }
+
+// Governor returns when the sessions run by the agent are checkpointed and compacted.
+// It returns nil when the agent has no governor.
+func (a *Agent) Governor() *latest.GovernorConfig {
+	return a.governor
+}
+
+// Priority returns the share of the requests to a rate-limited provider the
+// agent gets when other agents are waiting too. It is at least 1.
+func (a *Agent) Priority() int {
+	return max(1, a.priority)
+}
+
+// MaxParallelToolCalls returns the number of tool calls of a turn run at the
+// same time. It is at least 1.
+func (a *Agent) MaxParallelToolCalls() int {
+	return max(1, a.maxParallelTools)
+}
+
+// defaultMaxRepeatedToolCalls is the number of identical tool calls in a row
+// after which an agent is stopped as looping, when it doesn't set one.
+const defaultMaxRepeatedToolCalls = 5
+
+// MaxRepeatedToolCalls returns the number of times in a row the agent may
+// call the same tool with the same arguments before it's stopped as looping.
+// Zero means the agent is never stopped.
+func (a *Agent) MaxRepeatedToolCalls() int {
+	if a.maxRepeatedCalls < 0 {
+		return 0
+	}
+	return cmp.Or(a.maxRepeatedCalls, defaultMaxRepeatedToolCalls)
+}
+
+// defaultStreamTimeout is how long the stream of a model may go without
+// sending anything, when the agent doesn't set it. Reasoning models may think
+// for a while before they send their first token.
+const defaultStreamTimeout = 5 * time.Minute
+
+// StreamTimeout returns how long the stream of the agent's model may go
+// without sending anything before it's considered stalled. Zero means the
+// streams are never considered stalled.
+func (a *Agent) StreamTimeout() time.Duration {
+	if a.streamTimeout < 0 {
+		return 0
+	}
+	return cmp.Or(a.streamTimeout, defaultStreamTimeout)
+}
+
+// MaxTurnTokens returns the number of tokens the agent may add to the
+// context in a turn before it's asked to conclude. Zero means no limit.
+func (a *Agent) MaxTurnTokens() int {
+	return max(0, a.maxTurnTokens)
+}
@@ -87,6 +87,35 @@ func TestAgentTools(t *testing.T) {
 	}
 }
 
+type stoppableToolSet struct {
+	stubToolSet
+	stopped bool
+}
+
+func (s *stoppableToolSet) Stop(context.Context) error {
+	s.stopped = true
+	return nil
+}
+
+func TestReplaceToolSets(t *testing.T) {
+	previous := &stoppableToolSet{stubToolSet: stubToolSet{tools: []tools.Tool{{Name: "old"}}}}
+	a := New("root", "test", WithToolSets(previous))
+
+	got, err := a.Tools(t.Context())
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+
+	replacement := newStubToolSet(nil, []tools.Tool{{Name: "new"}, {Name: "newer"}}, nil)
+	require.NoError(t, a.ReplaceToolSets(t.Context(), replacement))
+	assert.True(t, previous.stopped)
+
+	got, err = a.Tools(t.Context())
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "new", got[0].Name)
+	assert.Len(t, a.ToolSets(), 1)
+}
+
 // mockProvider implements provider.Provider for testing
 type mockProvider struct {
 	id string
@@ -3,9 +3,11 @@ package agent
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/docker/cagent/pkg/config/latest"
 	"github.com/docker/cagent/pkg/config/types"
+	"github.com/docker/cagent/pkg/guardrails"
 	"github.com/docker/cagent/pkg/model/provider"
 	"github.com/docker/cagent/pkg/tools"
 )
@@ -132,6 +134,21 @@ func WithHooks(hooks *latest.HooksConfig) Opt {
 	}
 }
 
+func WithConfirmUntrusted(confirm bool) Opt {
+	return func(a *Agent) {
+		a.confirmUntrusted = confirm
+	}
+}
+
+// WithToolResultSummarization summarizes tool results estimated to be larger
+// than threshold tokens with the given model.
+func WithToolResultSummarization(model provider.Provider, threshold int) Opt {
+	return func(a *Agent) {
+		a.summaryModel = model
+		a.summaryThreshold = threshold
+	}
+}
+
 type StartableToolSet struct {
 	tools.ToolSet
 
@@ -164,3 +181,90 @@ func (s *StartableToolSet) Start(ctx context.Context) error {
 	}
 	return err
 }
+
+// WithGovernor checkpoints and compacts the agent's sessions at turn or token milestones.
+func WithGovernor(governor *latest.GovernorConfig) Opt {
+	return func(a *Agent) {
+		a.governor = governor
+	}
+}
+
+// WithCompaction compacts the agent's sessions with the given model, nil for
+// the agent's model, once their context exceeds the given fraction of the
+// context limit, zero for the default.
+func WithCompaction(model provider.Provider, threshold float64) Opt {
+	return func(a *Agent) {
+		a.compactionModel = model
+		a.compactionThreshold = threshold
+	}
+}
+
+// WithConfidenceReports has the agent report its confidence in its final
+// answers. With verify, the answers it has a low confidence in are verified by
+// escalateTo, nil for the agent's model.
+func WithConfidenceReports(verify bool, escalateTo provider.Provider) Opt {
+	return func(a *Agent) {
+		a.reportConfidence = true
+		a.verifyLowConfidence = verify || escalateTo != nil
+		a.escalationModel = escalateTo
+	}
+}
+
+// WithPriority sets the share of the requests to a rate-limited provider the
+// agent gets when other agents are waiting too.
+func WithPriority(priority int) Opt {
+	return func(a *Agent) {
+		a.priority = priority
+	}
+}
+
+// WithMaxParallelToolCalls sets the number of tool calls of a turn run at the
+// same time.
+func WithMaxParallelToolCalls(n int) Opt {
+	return func(a *Agent) {
+		a.maxParallelTools = n
+	}
+}
+
+// WithMaxRepeatedToolCalls sets the number of times in a row the agent may
+// call the same tool with the same arguments before it's stopped as looping.
+// A negative number disables the loop detection.
+func WithMaxRepeatedToolCalls(n int) Opt {
+	return func(a *Agent) {
+		a.maxRepeatedCalls = n
+	}
+}
+
+// WithMaxTurnTokens sets the number of tokens the agent may add to the
+// context in a turn, with its answers and the results of its tool calls,
+// before it's asked to conclude.
+func WithMaxTurnTokens(n int) Opt {
+	return func(a *Agent) {
+		a.maxTurnTokens = n
+	}
+}
+
+// WithStreamTimeout sets how long the stream of the agent's model may go
+// without sending anything before it's considered stalled. A negative
+// duration disables the stall detection.
+func WithStreamTimeout(timeout time.Duration) Opt {
+	return func(a *Agent) {
+		a.streamTimeout = timeout
+	}
+}
+
+// WithRetryPolicy retries the requests to the agent's model that fail with a
+// rate limit, a server error or a dropped stream.
+func WithRetryPolicy(policy RetryPolicy) Opt {
+	return func(a *Agent) {
+		a.retry = &policy
+	}
+}
+
+// WithGuardrails checks the answers of the agent and the arguments of its
+// tool calls with guardrails, in order.
+func WithGuardrails(g ...*guardrails.Guardrail) Opt {
+	return func(a *Agent) {
+		a.guardrails = append(a.guardrails, g...)
+	}
+}
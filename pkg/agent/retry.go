@@ -0,0 +1,54 @@
+package agent
+
+import (
+	"cmp"
+	"time"
+
+	"github.com/docker/cagent/pkg/model/provider"
+)
+
+// Defaults of the retry policy of an agent that configures one.
+const (
+	defaultMaxRetries   = 3
+	defaultInitialDelay = time.Second
+	defaultMaxDelay     = 30 * time.Second
+)
+
+// RetryPolicy is how the requests to the agent's model that fail with a rate
+// limit, a server error or a dropped stream are retried.
+type RetryPolicy struct {
+	// MaxRetries is the number of times a failed request is retried, zero for
+	// the default, negative for none.
+	MaxRetries int
+	// InitialDelay is the delay before the first retry, doubled with each
+	// retry up to MaxDelay. Zero for the defaults.
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	// Fallback is the model the agent fails over to, for the rest of the turn,
+	// once the retries are exhausted. Nil for none.
+	Fallback provider.Provider
+}
+
+// Delay returns the delay before the given retry, counted from 1.
+func (p RetryPolicy) Delay(retry int) time.Duration {
+	delay := p.InitialDelay
+	for i := 1; i < retry && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, p.MaxDelay)
+}
+
+// RetryPolicy returns how the failed requests to the agent's model are
+// retried, with the defaults applied. Without a policy, requests aren't
+// retried.
+func (a *Agent) RetryPolicy() RetryPolicy {
+	if a.retry == nil {
+		return RetryPolicy{}
+	}
+
+	policy := *a.retry
+	policy.MaxRetries = max(0, cmp.Or(policy.MaxRetries, defaultMaxRetries))
+	policy.InitialDelay = cmp.Or(policy.InitialDelay, defaultInitialDelay)
+	policy.MaxDelay = max(policy.InitialDelay, cmp.Or(policy.MaxDelay, defaultMaxDelay))
+	return policy
+}
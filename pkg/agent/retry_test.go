@@ -0,0 +1,31 @@
+package agent
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, RetryPolicy{}, New("root", "").RetryPolicy())
+
+	policy := New("root", "", WithRetryPolicy(RetryPolicy{})).RetryPolicy()
+	assert.Equal(t, 3, policy.MaxRetries)
+	assert.Equal(t, time.Second, policy.Delay(1))
+	assert.Equal(t, 4*time.Second, policy.Delay(3))
+	assert.Equal(t, 30*time.Second, policy.Delay(10))
+
+	policy = New("root", "", WithRetryPolicy(RetryPolicy{MaxRetries: -1})).RetryPolicy()
+	assert.Zero(t, policy.MaxRetries)
+}
+
+func TestStreamTimeout(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 5*time.Minute, New("root", "").StreamTimeout())
+	assert.Equal(t, time.Minute, New("root", "", WithStreamTimeout(time.Minute)).StreamTimeout())
+	assert.Zero(t, New("root", "", WithStreamTimeout(-time.Second)).StreamTimeout())
+}
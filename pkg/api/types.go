@@ -152,6 +152,19 @@ type DesktopTokenResponse struct {
 	Token string `json:"token"`
 }
 
+// ReviewRequest represents a tool call waiting for human review
+type ReviewRequest struct {
+	ID        string    `json:"id"`
+	SessionID string    `json:"session_id"`
+	AgentName string    `json:"agent_name"`
+	ToolName  string    `json:"tool_name"`
+	Arguments string    `json:"arguments"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
+	DecidedAt time.Time `json:"decided_at,omitzero"`
+}
+
 // ResumeElicitationRequest represents a request to resume with an elicitation response
 type ResumeElicitationRequest struct {
 	Action  string         `json:"action"`  // "accept", "decline", or "cancel"
@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"log/slog"
 	"os/exec"
+	"path/filepath"
 	"slices"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	tea "charm.land/bubbletea/v2"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -29,6 +32,11 @@ type App struct {
 	events             chan tea.Msg
 	throttleDuration   time.Duration
 	cancel             context.CancelFunc
+	usage              *runtime.UsageTracker
+	agentFile          string // Reloads the tools when this file changes
+
+	agentsMu sync.RWMutex
+	agents   []string
 }
 
 // Opt is an option for creating a new App.
@@ -54,6 +62,7 @@ func New(ctx context.Context, rt runtime.Runtime, sess *session.Session, opts ..
 		session:          sess,
 		events:           make(chan tea.Msg, 128),
 		throttleDuration: 50 * time.Millisecond, // Throttle rapid events
+		usage:            runtime.NewUsageTracker(),
 	}
 
 	for _, opt := range opts {
@@ -70,6 +79,7 @@ func New(ctx context.Context, rt runtime.Runtime, sess *session.Session, opts ..
 			rt.EmitStartupInfo(ctx, startupEvents)
 		}()
 		for event := range startupEvents {
+			app.recordTeamInfo(event)
 			app.events <- event
 		}
 	}()
@@ -83,6 +93,15 @@ func New(ctx context.Context, rt runtime.Runtime, sess *session.Session, opts ..
 		})
 	}
 
+	if _, ok := rt.(runtime.ToolsReloader); ok && app.agentFile != "" {
+		go app.watchAgentFile(ctx)
+	}
+
+	// Prepare the first turn while the user types their first message
+	if warmer, ok := rt.(runtime.Warmer); ok {
+		go warmer.WarmUp(ctx, sess)
+	}
+
 	return app
 }
 
@@ -100,6 +119,30 @@ func (a *App) Runtime() runtime.Runtime {
 	return a.runtime
 }
 
+// AvailableAgents returns the names of the agents of the team, as last
+// reported by the runtime.
+func (a *App) AvailableAgents() []string {
+	a.agentsMu.RLock()
+	defer a.agentsMu.RUnlock()
+	return slices.Clone(a.agents)
+}
+
+func (a *App) recordTeamInfo(event runtime.Event) {
+	info, ok := event.(*runtime.TeamInfoEvent)
+	if !ok {
+		return
+	}
+
+	agents := make([]string, 0, len(info.AvailableAgents))
+	for _, agent := range info.AvailableAgents {
+		agents = append(agents, agent.Name)
+	}
+
+	a.agentsMu.Lock()
+	a.agents = agents
+	a.agentsMu.Unlock()
+}
+
 // CurrentAgentCommands returns the commands for the active agent
 func (a *App) CurrentAgentCommands(ctx context.Context) types.Commands {
 	return a.runtime.CurrentAgentInfo(ctx).Commands
@@ -184,37 +227,83 @@ func (a *App) Run(ctx context.Context, cancel context.CancelFunc, message string
 			for key, dataURL := range attachments {
 				multiContent = append(multiContent, chat.MessagePart{
 					Type: chat.MessagePartTypeText,
-					Text: fmt.Sprintf("Contents of %s: %s", key, dataURL),
+					Text: a.attachmentText(key, dataURL),
 				})
 			}
 			a.session.AddMessage(session.UserMessage(message, multiContent...))
 		} else {
 			a.session.AddMessage(session.UserMessage(message))
 		}
-		for event := range a.runtime.RunStream(ctx, a.session) {
-			if ctx.Err() != nil {
-				return
-			}
-			a.events <- event
-		}
+		a.forwardEvents(ctx, a.runtime.RunStream(ctx, a.session))
 	}()
 }
 
+// attachmentPreviewLength is how much of a large attachment is kept in the
+// user message.
+const attachmentPreviewLength = 8000
+
+// attachmentText is how an attachment is added to the user message. Large
+// attachments are written to a file of the session and only their beginning
+// is kept in the message, with the path of the file.
+func (a *App) attachmentText(name, content string) string {
+	if len(content) <= session.SpillThreshold {
+		return fmt.Sprintf("Contents of %s: %s", name, content)
+	}
+
+	path, err := session.Spill(a.session.ID, "attachment-"+filepath.Base(name), content)
+	if err != nil {
+		slog.Warn("Failed to spill large attachment", "attachment", name, "error", err)
+		return fmt.Sprintf("Contents of %s: %s", name, content)
+	}
+
+	n := attachmentPreviewLength
+	for n > 0 && !utf8.RuneStart(content[n]) {
+		n--
+	}
+	preview := content[:n]
+	return fmt.Sprintf("Beginning of %s: %s\n\n[%s has %d characters, only the first %d are shown. The whole file was saved to %s.]",
+		name, preview, name, len(content), len(preview), path)
+}
+
 // RunWithMessage runs the agent loop with a pre-constructed message.
 // This is used for special cases like image attachments.
 func (a *App) RunWithMessage(ctx context.Context, cancel context.CancelFunc, msg *session.Message) {
 	a.cancel = cancel
 	go func() {
 		a.session.AddMessage(msg)
-		for event := range a.runtime.RunStream(ctx, a.session) {
-			if ctx.Err() != nil {
-				return
-			}
-			a.events <- event
-		}
+		a.forwardEvents(ctx, a.runtime.RunStream(ctx, a.session))
 	}()
 }
 
+// forwardEvents forwards the events of an agent loop to the UI. When the
+// governor continues the conversation in a new session, the app follows it.
+func (a *App) forwardEvents(ctx context.Context, events <-chan runtime.Event) {
+	for event := range events {
+		if ctx.Err() != nil {
+			return
+		}
+		if rotated, ok := event.(*runtime.SessionRotatedEvent); ok {
+			a.followRotatedSession(ctx, rotated.SessionID)
+		}
+		a.recordTeamInfo(event)
+		a.events <- event
+	}
+}
+
+func (a *App) followRotatedSession(ctx context.Context, sessionID string) {
+	store := a.runtime.SessionStore()
+	if store == nil {
+		return
+	}
+
+	sess, err := store.GetSession(ctx, sessionID)
+	if err != nil {
+		slog.Warn("Failed to load rotated session", "session_id", sessionID, "error", err)
+		return
+	}
+	a.session = sess
+}
+
 func (a *App) RunBangCommand(ctx context.Context, command string) {
 	out, _ := exec.CommandContext(ctx, "/bin/sh", "-c", command).CombinedOutput()
 	a.events <- runtime.ShellOutput("$ " + command + "\n" + string(out))
@@ -250,12 +339,19 @@ func (a *App) NewSession() {
 		a.cancel()
 		a.cancel = nil
 	}
-	a.session = session.New()
+	a.session = session.New(session.WithTimeLimit(a.session.TimeLimit))
+	a.usage.Reset()
 	// Clear first message so it won't be re-sent on re-init
 	a.firstMessage = nil
 	a.firstMessageAttach = ""
 }
 
+// Usage returns the tracker aggregating the token usage of the current session
+// and its sub-sessions.
+func (a *App) Usage() *runtime.UsageTracker {
+	return a.usage
+}
+
 func (a *App) Session() *session.Session {
 	return a.session
 }
@@ -401,6 +497,9 @@ func (a *App) AvailableModels(ctx context.Context) []runtime.ModelChoice {
 		})
 	}
 
+	// Context window and pricing, for the configured and custom models alike
+	modelSwitcher.DescribeModels(ctx, models)
+
 	return models
 }
 
@@ -436,6 +535,39 @@ func (a *App) CompactSession(additionalPrompt string) {
 	}
 }
 
+// WritePostMortem writes, in the background, the post-mortem of the last run
+// of the session, that failed for the given reason. It returns false when the
+// runtime can't write post-mortems.
+func (a *App) WritePostMortem(failure string) bool {
+	writer, ok := a.runtime.(runtime.PostMortemWriter)
+	if !ok || a.session == nil {
+		return false
+	}
+
+	sess := a.session
+	go func() {
+		events := make(chan runtime.Event, 100)
+		go func() {
+			defer close(events)
+			writer.WritePostMortem(context.Background(), sess, failure, events)
+		}()
+		for event := range events {
+			a.events <- event
+		}
+	}()
+	return true
+}
+
+// DescribeTeam describes what the team of agents can do. It returns false
+// when the runtime can't describe its team.
+func (a *App) DescribeTeam(ctx context.Context) (runtime.TeamDescription, bool) {
+	describer, ok := a.runtime.(runtime.TeamDescriber)
+	if !ok {
+		return runtime.TeamDescription{}, false
+	}
+	return describer.Describe(ctx), true
+}
+
 func (a *App) PlainTextTranscript() string {
 	return transcript(a.session)
 }
@@ -456,6 +588,7 @@ func (a *App) ReplaceSession(ctx context.Context, sess *session.Session) {
 		a.cancel = nil
 	}
 	a.session = sess
+	a.usage.Reset()
 	// Clear first message so it won't be re-sent on re-init
 	a.firstMessage = nil
 	a.firstMessageAttach = ""
@@ -519,13 +652,16 @@ func (a *App) throttleEvents(ctx context.Context, in <-chan tea.Msg) <-chan tea.
 			}
 		}
 
+		// Reuse the buffer for the next period, without holding on to the
+		// events that were sent
+		clear(buffer)
 		buffer = buffer[:0]
 		timerCh = nil
 	}
-	defer flush()
 
 	go func() {
 		defer close(out)
+		defer flush()
 
 		for {
 			select {
@@ -575,7 +711,7 @@ func (a *App) mergeEvents(events []tea.Msg) []tea.Msg {
 		return events
 	}
 
-	var result []tea.Msg
+	result := make([]tea.Msg, 0, len(events))
 
 	// Group events by type and merge
 	for i := 0; i < len(events); i++ {
@@ -584,39 +720,41 @@ func (a *App) mergeEvents(events []tea.Msg) []tea.Msg {
 		switch ev := current.(type) {
 		case *runtime.AgentChoiceEvent:
 			// Merge consecutive AgentChoiceEvents with same agent
-			merged := ev
-			for i+1 < len(events) {
-				if next, ok := events[i+1].(*runtime.AgentChoiceEvent); ok && next.AgentName == ev.AgentName {
-					// Concatenate content
-					merged = &runtime.AgentChoiceEvent{
-						Type:         ev.Type,
-						Content:      merged.Content + next.Content,
-						AgentContext: ev.AgentContext,
-					}
-					i++
-				} else {
-					break
+			content, last := mergeContent(events, i, func(msg tea.Msg) (string, bool) {
+				next, ok := msg.(*runtime.AgentChoiceEvent)
+				if !ok || next.AgentName != ev.AgentName {
+					return "", false
+				}
+				return next.Content, true
+			})
+			if last > i {
+				current = &runtime.AgentChoiceEvent{
+					Type:         ev.Type,
+					Content:      content,
+					AgentContext: ev.AgentContext,
 				}
+				i = last
 			}
-			result = append(result, merged)
+			result = append(result, current)
 
 		case *runtime.AgentChoiceReasoningEvent:
 			// Merge consecutive AgentChoiceReasoningEvents with same agent
-			merged := ev
-			for i+1 < len(events) {
-				if next, ok := events[i+1].(*runtime.AgentChoiceReasoningEvent); ok && next.AgentName == ev.AgentName {
-					// Concatenate content
-					merged = &runtime.AgentChoiceReasoningEvent{
-						Type:         ev.Type,
-						Content:      merged.Content + next.Content,
-						AgentContext: ev.AgentContext,
-					}
-					i++
-				} else {
-					break
+			content, last := mergeContent(events, i, func(msg tea.Msg) (string, bool) {
+				next, ok := msg.(*runtime.AgentChoiceReasoningEvent)
+				if !ok || next.AgentName != ev.AgentName {
+					return "", false
+				}
+				return next.Content, true
+			})
+			if last > i {
+				current = &runtime.AgentChoiceReasoningEvent{
+					Type:         ev.Type,
+					Content:      content,
+					AgentContext: ev.AgentContext,
 				}
+				i = last
 			}
-			result = append(result, merged)
+			result = append(result, current)
 
 		case *runtime.PartialToolCallEvent:
 			// For PartialToolCallEvent, keep only the latest one per tool call ID
@@ -641,9 +779,37 @@ func (a *App) mergeEvents(events []tea.Msg) []tea.Msg {
 	return result
 }
 
-// ExportHTML exports the current session as a standalone HTML file.
+// mergeContent concatenates the content of the run of events, starting at
+// events[start], for which content returns true. The content is copied once,
+// however long the run: fast streams buffer hundreds of tokens per
+// throttling period. It also returns the index of the last event of the run.
+func mergeContent(events []tea.Msg, start int, content func(tea.Msg) (string, bool)) (string, int) {
+	end, size := start, 0
+	for end < len(events) {
+		c, ok := content(events[end])
+		if !ok {
+			break
+		}
+		size += len(c)
+		end++
+	}
+	if end-start <= 1 {
+		return "", start
+	}
+
+	var b strings.Builder
+	b.Grow(size)
+	for _, ev := range events[start:end] {
+		c, _ := content(ev)
+		b.WriteString(c)
+	}
+	return b.String(), end - 1
+}
+
+// ExportSession exports the current session as a standalone HTML file, or
+// as Markdown if filename ends with .md.
 // If filename is empty, a default name based on the session title and timestamp is used.
-func (a *App) ExportHTML(ctx context.Context, filename string) (string, error) {
+func (a *App) ExportSession(ctx context.Context, filename string) (string, error) {
 	agentInfo := a.runtime.CurrentAgentInfo(ctx)
-	return export.SessionToFile(a.session, agentInfo.Description, filename)
+	return export.SessionToFile(a.session, agentInfo.Description, filename, "")
 }
@@ -0,0 +1,56 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/runtime"
+	"github.com/docker/cagent/pkg/tools"
+)
+
+func TestMergeEvents(t *testing.T) {
+	t.Parallel()
+
+	call := func(args string) *runtime.PartialToolCallEvent {
+		return runtime.PartialToolCall(tools.ToolCall{ID: "call_1", Function: tools.FunctionCall{Arguments: args}}, tools.Tool{}, "root").(*runtime.PartialToolCallEvent)
+	}
+
+	a := &App{}
+	merged := a.mergeEvents([]tea.Msg{
+		runtime.AgentChoice("root", "Hel"),
+		runtime.AgentChoice("root", "lo"),
+		runtime.AgentChoice("helper", "!"),
+		runtime.AgentChoiceReasoning("root", "think"),
+		runtime.AgentChoiceReasoning("root", "ing"),
+		call(`{"a"`),
+		call(`{"a":1}`),
+		runtime.StreamStopped("session", "root"),
+	})
+
+	require.Len(t, merged, 5)
+	assert.Equal(t, "Hello", merged[0].(*runtime.AgentChoiceEvent).Content)
+	assert.Equal(t, "root", merged[0].(*runtime.AgentChoiceEvent).AgentName)
+	assert.Equal(t, "!", merged[1].(*runtime.AgentChoiceEvent).Content)
+	assert.Equal(t, "thinking", merged[2].(*runtime.AgentChoiceReasoningEvent).Content)
+	assert.JSONEq(t, `{"a":1}`, merged[3].(*runtime.PartialToolCallEvent).ToolCall.Function.Arguments)
+	assert.IsType(t, &runtime.StreamStoppedEvent{}, merged[4])
+}
+
+// BenchmarkMergeEvents merges the tokens of a fast stream buffered during
+// one throttling period.
+func BenchmarkMergeEvents(b *testing.B) {
+	events := make([]tea.Msg, 500)
+	for i := range events {
+		events[i] = runtime.AgentChoice("root", strings.Repeat("x", 4))
+	}
+
+	a := &App{}
+	b.ReportAllocs()
+	for b.Loop() {
+		a.mergeEvents(events)
+	}
+}
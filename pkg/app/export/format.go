@@ -0,0 +1,62 @@
+package export
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Format is the format of an exported session.
+type Format string
+
+const (
+	FormatHTML     Format = "html"
+	FormatMarkdown Format = "markdown"
+)
+
+// Validate returns an error if the format is unknown.
+func (f Format) Validate() error {
+	switch f {
+	case FormatHTML, FormatMarkdown:
+		return nil
+	default:
+		return fmt.Errorf("unknown export format %q, expected %s or %s", f, FormatHTML, FormatMarkdown)
+	}
+}
+
+// Extension returns the file extension of the format.
+func (f Format) Extension() string {
+	if f == FormatMarkdown {
+		return ".md"
+	}
+	return ".html"
+}
+
+// ParseFormat parses the name of a format. "md" is short for markdown.
+func ParseFormat(name string) (Format, error) {
+	format := Format(strings.ToLower(name))
+	if format == "md" {
+		format = FormatMarkdown
+	}
+	return format, format.Validate()
+}
+
+// FormatFromFilename returns the format matching the extension of a
+// filename, HTML by default.
+func FormatFromFilename(filename string) Format {
+	if format, ok := formatOfExtension(filename); ok {
+		return format
+	}
+	return FormatHTML
+}
+
+func formatOfExtension(filename string) (Format, bool) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".html", ".htm":
+		return FormatHTML, true
+	case ".md", ".markdown":
+		return FormatMarkdown, true
+	default:
+		return "", false
+	}
+}
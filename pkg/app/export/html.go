@@ -1,4 +1,4 @@
-// Package export provides HTML export functionality for cagent sessions.
+// Package export provides HTML and Markdown export functionality for cagent sessions.
 package export
 
 import (
@@ -48,7 +48,7 @@ var markdown = goldmark.New(
 	),
 )
 
-// SessionData contains the session information needed for HTML export.
+// SessionData contains the session information needed for export.
 type SessionData struct {
 	Title            string
 	AgentDescription string
@@ -57,6 +57,9 @@ type SessionData struct {
 	OutputTokens     int64
 	Cost             float64
 	Messages         []Message
+	// Provenance is the history of the binaries and configurations that ran
+	// the session, oldest first.
+	Provenance []session.Provenance
 }
 
 // Message represents a single message in the session.
@@ -68,6 +71,8 @@ type Message struct {
 	ToolCalls        []ToolCall
 	AgentName        string
 	Implicit         bool
+	// CreatedAt is zero for the messages of old sessions, without a time
+	CreatedAt time.Time
 }
 
 // ToolCall represents a tool invocation.
@@ -77,16 +82,17 @@ type ToolCall struct {
 	Arguments string
 }
 
-// SessionToFile exports a session to an HTML file.
+// SessionToFile exports a session to an HTML or Markdown file.
 // If filename is empty, a default name based on the title and timestamp is used.
+// If format is empty, it's guessed from the extension of the filename.
 // Returns the absolute path of the created file.
-func SessionToFile(sess *session.Session, agentDescription, filename string) (string, error) {
+func SessionToFile(sess *session.Session, agentDescription, filename string, format Format) (string, error) {
 	if sess == nil {
 		return "", fmt.Errorf("no session to export")
 	}
 	data := sessionToData(sess)
 	data.AgentDescription = agentDescription
-	return ToFile(data, filename)
+	return ToFile(data, filename, format)
 }
 
 func sessionToData(sess *session.Session) SessionData {
@@ -101,7 +107,9 @@ func sessionToData(sess *session.Session) SessionData {
 				Arguments: tc.Function.Arguments,
 			}
 		}
+		createdAt, _ := time.Parse(time.RFC3339, msg.Message.CreatedAt)
 		exportMessages[i] = Message{
+			CreatedAt:        createdAt,
 			Role:             msg.Message.Role,
 			Content:          msg.Message.Content,
 			ReasoningContent: msg.Message.ReasoningContent,
@@ -118,17 +126,26 @@ func sessionToData(sess *session.Session) SessionData {
 		OutputTokens: sess.OutputTokens,
 		Cost:         sess.Cost,
 		Messages:     exportMessages,
+		Provenance:   sess.Provenance,
 	}
 }
 
-// ToFile exports session data to an HTML file.
+// ToFile exports session data to an HTML or Markdown file.
 // If filename is empty, a default name based on the title and timestamp is used.
+// If format is empty, it's guessed from the extension of the filename.
 // Returns the absolute path of the created file.
-func ToFile(data SessionData, filename string) (string, error) {
+func ToFile(data SessionData, filename string, format Format) (string, error) {
 	if len(data.Messages) == 0 {
 		return "", fmt.Errorf("session is empty")
 	}
 
+	if format == "" {
+		format = FormatFromFilename(filename)
+	}
+	if err := format.Validate(); err != nil {
+		return "", err
+	}
+
 	// Generate filename if not provided
 	if filename == "" {
 		title := data.Title
@@ -136,20 +153,27 @@ func ToFile(data SessionData, filename string) (string, error) {
 			title = "cagent-session"
 		}
 		title = sanitizeFilename(title)
-		filename = fmt.Sprintf("%s-%s.html", title, time.Now().Format("2006-01-02-150405"))
+		filename = fmt.Sprintf("%s-%s", title, time.Now().Format("2006-01-02-150405"))
 	}
 
-	// Ensure .html extension
-	if !strings.HasSuffix(strings.ToLower(filename), ".html") {
-		filename += ".html"
+	// Ensure the extension matches the format
+	if f, ok := formatOfExtension(filename); !ok || f != format {
+		filename += format.Extension()
 	}
 
-	htmlContent, err := Generate(data)
-	if err != nil {
-		return "", fmt.Errorf("failed to generate HTML: %w", err)
+	var content string
+	switch format {
+	case FormatMarkdown:
+		content = GenerateMarkdown(data)
+	default:
+		htmlContent, err := Generate(data)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate HTML: %w", err)
+		}
+		content = htmlContent
 	}
 
-	if err := os.WriteFile(filename, []byte(htmlContent), 0o644); err != nil {
+	if err := os.WriteFile(filename, []byte(content), 0o644); err != nil {
 		return "", fmt.Errorf("failed to write file: %w", err)
 	}
 
@@ -193,7 +217,11 @@ type templateData struct {
 	ToolsUsedCount   int
 	TotalTokens      int64
 	FormattedTokens  string
+	FormattedUsage   string
 	FormattedCost    template.HTML
+	ProvenanceLines  []string
+	// Provenance is embedded as JSON, for tools reading the export
+	Provenance []session.Provenance
 }
 
 // messageViewData holds data for rendering a single message.
@@ -202,6 +230,7 @@ type messageViewData struct {
 	LabelName        string
 	LabelClasses     string
 	ShowLabel        bool
+	Timestamp        string
 	ContentHTML      template.HTML
 	ReasoningHTML    template.HTML
 	HasReasoning     bool
@@ -235,6 +264,7 @@ var messageTemplate = template.Must(template.New("message").Parse(`
     <div class="hidden sm:block sm:w-14 shrink-0"></div>
     {{end}}
     <div class="flex-1 flex flex-col gap-3 overflow-hidden text-sm">
+        {{if .Timestamp}}<div class="text-xs text-muted-foreground">{{.Timestamp}}</div>{{end}}
         <div class="whitespace-pre-wrap">{{.ContentHTML}}</div>
     </div>
 </div>
@@ -246,6 +276,7 @@ var messageTemplate = template.Must(template.New("message").Parse(`
     <div class="hidden sm:block sm:w-14 shrink-0"></div>
     {{end}}
     <div class="flex-1 flex flex-col gap-3 overflow-hidden text-sm">
+        {{if .Timestamp}}<div class="text-xs text-muted-foreground">{{.Timestamp}}</div>{{end}}
         {{if .HasReasoning}}
         <div class="border-l-2 border-tui-purple bg-tui-purple/5">
             <div class="flex items-center gap-2 px-3 py-2 cursor-pointer text-xs font-bold text-tui-purple select-none hover:bg-tui-purple/10" onclick="toggle(this)">
@@ -293,13 +324,7 @@ var toolCallTemplate = template.Must(template.New("toolcall").Parse(`
 
 // Generate creates an HTML string from the session data.
 func Generate(data SessionData) (string, error) {
-	// Build a map of tool call ID -> tool result content
-	toolResults := make(map[string]string)
-	for _, msg := range data.Messages {
-		if msg.Role == chat.MessageRoleTool && msg.ToolCallID != "" {
-			toolResults[msg.ToolCallID] = msg.Content
-		}
-	}
+	toolResults := toolResultsByID(data.Messages)
 
 	// Count unique tools used
 	toolsUsed := make(map[string]bool)
@@ -364,7 +389,10 @@ func Generate(data SessionData) (string, error) {
 		ToolsUsedCount:   len(toolsUsed),
 		TotalTokens:      totalTokens,
 		FormattedTokens:  formatTokens(totalTokens),
+		FormattedUsage:   fmt.Sprintf("%s input, %s output", formatTokens(data.InputTokens), formatTokens(data.OutputTokens)),
 		FormattedCost:    template.HTML(formatCost(data.Cost)), //nolint:gosec // formatCost returns safe HTML
+		ProvenanceLines:  provenanceLines(data.Provenance),
+		Provenance:       data.Provenance,
 	}
 
 	var buf bytes.Buffer
@@ -375,6 +403,17 @@ func Generate(data SessionData) (string, error) {
 	return buf.String(), nil
 }
 
+// toolResultsByID maps the IDs of the tool calls to their results.
+func toolResultsByID(messages []Message) map[string]string {
+	toolResults := make(map[string]string)
+	for _, msg := range messages {
+		if msg.Role == chat.MessageRoleTool && msg.ToolCallID != "" {
+			toolResults[msg.ToolCallID] = msg.Content
+		}
+	}
+	return toolResults
+}
+
 func getSender(msg Message) string {
 	if msg.Role == chat.MessageRoleUser {
 		return "you"
@@ -395,6 +434,31 @@ func formatTokens(tokens int64) string {
 	return fmt.Sprintf("%d", tokens)
 }
 
+// provenanceLines describes each provenance of a session on a line.
+func provenanceLines(provenance []session.Provenance) []string {
+	var lines []string
+	for _, p := range provenance {
+		line := fmt.Sprintf("%s: cagent %s (%s)", p.Since.Local().Format("Jan 2, 2006 15:04"), p.Version, p.Commit)
+		if len(p.Features) > 0 {
+			line += ", features: " + strings.Join(p.Features, ", ")
+		}
+		if p.ConfigHash != "" {
+			line += ", config " + shortHash(p.ConfigHash)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// shortHash shortens a "sha256:<hex>" hash for display.
+func shortHash(hash string) string {
+	const length = len("sha256:") + 12
+	if len(hash) > length {
+		return hash[:length]
+	}
+	return hash
+}
+
 func formatCost(cost float64) string {
 	if cost <= 0 {
 		return ""
@@ -423,6 +487,7 @@ func renderUserMessage(msg Message, showLabel bool) (string, error) {
 		LabelName:    "you",
 		LabelClasses: "bg-tui-yellow/20 text-tui-yellow",
 		ShowLabel:    showLabel,
+		Timestamp:    labelTimestamp(msg, showLabel),
 		ContentHTML:  template.HTML(content), //nolint:gosec // Content is escaped above
 	}
 
@@ -444,6 +509,7 @@ func renderAssistantMessage(msg Message, toolResults map[string]string, showLabe
 		LabelName:        agentName,
 		LabelClasses:     "bg-tui-cyan/20 text-tui-cyan",
 		ShowLabel:        showLabel,
+		Timestamp:        labelTimestamp(msg, showLabel),
 		ChevronRightIcon: template.HTML(svgChevronRight), //nolint:gosec // Constant SVG
 		ChevronDownIcon:  template.HTML(svgChevronDown),  //nolint:gosec // Constant SVG
 	}
@@ -493,6 +559,15 @@ func renderAssistantMessage(msg Message, toolResults map[string]string, showLabe
 	return buf.String(), nil
 }
 
+// labelTimestamp returns the time of the first message of the messages sent
+// in a row by the same sender.
+func labelTimestamp(msg Message, showLabel bool) string {
+	if !showLabel || msg.CreatedAt.IsZero() {
+		return ""
+	}
+	return msg.CreatedAt.Local().Format(time.TimeOnly)
+}
+
 func renderToolCall(name, args, result string) (string, error) {
 	data := toolCallViewData{
 		Name:              name,
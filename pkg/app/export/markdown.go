@@ -0,0 +1,130 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/session"
+)
+
+// GenerateMarkdown creates a Markdown document from the session data. The
+// reasoning and the tool calls are folded in <details> blocks, which most
+// Markdown viewers render collapsed.
+func GenerateMarkdown(data SessionData) string {
+	toolResults := toolResultsByID(data.Messages)
+
+	title := data.Title
+	if title == "" {
+		title = "cagent Session"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	if !data.CreatedAt.IsZero() {
+		fmt.Fprintf(&b, "_%s_\n\n", data.CreatedAt.Format("January 2, 2006 at 3:04 PM"))
+	}
+	if data.AgentDescription != "" {
+		fmt.Fprintf(&b, "> %s\n\n", strings.ReplaceAll(data.AgentDescription, "\n", "\n> "))
+	}
+	fmt.Fprintf(&b, "**Usage:** %s\n\n", formatUsage(data))
+	writeMarkdownProvenance(&b, data.Provenance)
+
+	var prevSender string
+	for _, msg := range data.Messages {
+		// Tool messages are rendered with their tool calls
+		if msg.Implicit || (msg.Role != chat.MessageRoleUser && msg.Role != chat.MessageRoleAssistant) {
+			continue
+		}
+
+		if sender := getSender(msg); sender != prevSender {
+			b.WriteString("---\n\n")
+			fmt.Fprintf(&b, "### %s", sender)
+			if !msg.CreatedAt.IsZero() {
+				fmt.Fprintf(&b, " · %s", msg.CreatedAt.Local().Format(time.TimeOnly))
+			}
+			b.WriteString("\n\n")
+			prevSender = sender
+		}
+
+		if msg.ReasoningContent != "" {
+			b.WriteString("<details>\n<summary>Thinking</summary>\n\n")
+			b.WriteString(quote(msg.ReasoningContent))
+			b.WriteString("\n\n</details>\n\n")
+		}
+		if msg.Content != "" {
+			b.WriteString(strings.TrimSpace(msg.Content))
+			b.WriteString("\n\n")
+		}
+		for _, tc := range msg.ToolCalls {
+			writeMarkdownToolCall(&b, tc, toolResults[tc.ID])
+		}
+	}
+
+	return b.String()
+}
+
+// writeMarkdownProvenance lists the binaries and configurations that ran the
+// session, followed by their JSON for the tools reading the export.
+func writeMarkdownProvenance(b *strings.Builder, provenance []session.Provenance) {
+	if len(provenance) == 0 {
+		return
+	}
+
+	b.WriteString("<details>\n<summary>Provenance</summary>\n\n")
+	for _, line := range provenanceLines(provenance) {
+		fmt.Fprintf(b, "- %s\n", line)
+	}
+	b.WriteString("\n")
+	if data, err := json.MarshalIndent(provenance, "", "  "); err == nil {
+		b.WriteString(codeBlock(string(data), "json"))
+	}
+	b.WriteString("</details>\n\n")
+}
+
+func writeMarkdownToolCall(b *strings.Builder, tc ToolCall, result string) {
+	fmt.Fprintf(b, "<details>\n<summary>🔧 %s</summary>\n\n", tc.Name)
+
+	args := formatJSONForDisplay(tc.Arguments)
+	if args != "" && args != "{}" && args != "null" {
+		b.WriteString("**Arguments**\n\n")
+		b.WriteString(codeBlock(args, "json"))
+	}
+	if result != "" {
+		b.WriteString("**Result**\n\n")
+		b.WriteString(codeBlock(formatJSONForDisplay(result), ""))
+	}
+
+	b.WriteString("</details>\n\n")
+}
+
+// codeBlock fences text with more backticks than it contains in a row, so
+// that it can't close the block itself.
+func codeBlock(text, language string) string {
+	longest, run := 0, 0
+	for _, r := range text {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	fence := strings.Repeat("`", max(3, longest+1))
+	return fence + language + "\n" + strings.TrimRight(text, "\n") + "\n" + fence + "\n\n"
+}
+
+func quote(text string) string {
+	return "> " + strings.ReplaceAll(strings.TrimSpace(text), "\n", "\n> ")
+}
+
+func formatUsage(data SessionData) string {
+	usage := fmt.Sprintf("%s tokens (%s input, %s output)",
+		formatTokens(data.InputTokens+data.OutputTokens), formatTokens(data.InputTokens), formatTokens(data.OutputTokens))
+	if data.Cost > 0 {
+		usage += fmt.Sprintf(", $%.2f", data.Cost)
+	}
+	return usage
+}
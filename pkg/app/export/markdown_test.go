@@ -0,0 +1,129 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/session"
+)
+
+func testSessionData() SessionData {
+	at := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+	return SessionData{
+		Title:        "Listing files",
+		CreatedAt:    at,
+		InputTokens:  1200,
+		OutputTokens: 80,
+		Cost:         0.02,
+		Messages: []Message{
+			{Role: chat.MessageRoleUser, Content: "List the files", CreatedAt: at},
+			{Role: chat.MessageRoleUser, Content: "hidden", Implicit: true},
+			{
+				Role:             chat.MessageRoleAssistant,
+				AgentName:        "root",
+				ReasoningContent: "Let me look",
+				ToolCalls:        []ToolCall{{ID: "call_1", Name: "shell", Arguments: `{"cmd":"ls"}`}},
+				CreatedAt:        at.Add(time.Second),
+			},
+			{Role: chat.MessageRoleTool, ToolCallID: "call_1", Content: "a.go\n```b.md"},
+			{Role: chat.MessageRoleAssistant, AgentName: "root", Content: "There are **two** files."},
+		},
+	}
+}
+
+func TestGenerateMarkdown(t *testing.T) {
+	t.Parallel()
+
+	data := testSessionData()
+	md := GenerateMarkdown(data)
+
+	assert.Contains(t, md, "# Listing files\n")
+	assert.Contains(t, md, "**Usage:** 1.3K tokens (1.2K input, 80 output), $0.02")
+	assert.Contains(t, md, "### you · "+data.Messages[0].CreatedAt.Local().Format(time.TimeOnly)+"\n\nList the files")
+	assert.NotContains(t, md, "hidden")
+	assert.Contains(t, md, "<details>\n<summary>Thinking</summary>\n\n> Let me look")
+	assert.Contains(t, md, "<summary>🔧 shell</summary>")
+	assert.Contains(t, md, "```json\n{\n  \"cmd\": \"ls\"\n}\n```")
+	// The result contains a fence, it's fenced with more backticks
+	assert.Contains(t, md, "````\na.go\n```b.md\n````")
+	// Messages of the same agent in a row have one heading
+	assert.Equal(t, 1, strings.Count(md, "### root"))
+	assert.Contains(t, md, "There are **two** files.")
+}
+
+func TestExportProvenance(t *testing.T) {
+	t.Parallel()
+
+	data := testSessionData()
+	data.Provenance = []session.Provenance{{
+		Version:    "v1.2.3",
+		Commit:     "abc123",
+		Features:   []string{"markdown-renderer"},
+		Providers:  map[string]string{"anthropic": "v1.19.0"},
+		ConfigHash: "sha256:0123456789abcdef0123",
+		Since:      time.Date(2025, 1, 2, 15, 0, 0, 0, time.UTC),
+	}}
+
+	md := GenerateMarkdown(data)
+	assert.Contains(t, md, "<summary>Provenance</summary>")
+	assert.Contains(t, md, "cagent v1.2.3 (abc123), features: markdown-renderer, config sha256:0123456789ab\n")
+	assert.Contains(t, md, `"config_hash": "sha256:0123456789abcdef0123"`)
+
+	// Sessions run before the provenance was recorded have none
+	assert.NotContains(t, GenerateMarkdown(testSessionData()), "Provenance")
+
+	html, err := Generate(data)
+	require.NoError(t, err)
+	assert.Contains(t, html, "cagent v1.2.3 (abc123)")
+	assert.Contains(t, html, `<script type="application/json" id="cagent-provenance">[{"version":"v1.2.3"`)
+}
+
+func TestToFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	data := testSessionData()
+
+	path, err := ToFile(data, filepath.Join(dir, "transcript.md"), "")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "transcript.md"), path)
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "# Listing files")
+
+	path, err = ToFile(data, filepath.Join(dir, "transcript"), FormatMarkdown)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "transcript.md"), path)
+
+	path, err = ToFile(data, filepath.Join(dir, "transcript.txt"), "")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "transcript.txt.html"), path)
+	content, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "1.2K input, 80 output")
+
+	_, err = ToFile(data, filepath.Join(dir, "transcript"), "pdf")
+	require.ErrorContains(t, err, `unknown export format "pdf"`)
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	format, err := ParseFormat("md")
+	require.NoError(t, err)
+	assert.Equal(t, FormatMarkdown, format)
+
+	format, err = ParseFormat("HTML")
+	require.NoError(t, err)
+	assert.Equal(t, FormatHTML, format)
+
+	_, err = ParseFormat("pdf")
+	require.Error(t, err)
+}
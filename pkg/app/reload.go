@@ -0,0 +1,91 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/docker/cagent/pkg/runtime"
+)
+
+// agentFileReloadDelay is how long the agent file stays unchanged before the
+// tools are reloaded: editors write files in several steps.
+const agentFileReloadDelay = 500 * time.Millisecond
+
+// WithAgentFileWatch reloads the tools of the agents when the file of their
+// configuration changes.
+func WithAgentFileWatch(path string) Opt {
+	return func(a *App) {
+		if abs, err := filepath.Abs(path); err == nil {
+			a.agentFile = abs
+		}
+	}
+}
+
+// ReloadTools gives the agents their toolsets as currently configured, for
+// example once an MCP server was added. The sidebar is updated through the
+// events of the runtime.
+func (a *App) ReloadTools(ctx context.Context) error {
+	reloader, ok := a.runtime.(runtime.ToolsReloader)
+	if !ok {
+		return errors.New("the tools can't be reloaded with a remote runtime")
+	}
+
+	events := make(chan runtime.Event, 10)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(events)
+		errs <- reloader.ReloadTools(ctx, events)
+	}()
+	for event := range events {
+		a.events <- event
+	}
+	return <-errs
+}
+
+// watchAgentFile reloads the tools each time the agent file is written.
+func (a *App) watchAgentFile(ctx context.Context) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		slog.Warn("Failed to watch the agent file", "path", a.agentFile, "error", err)
+		return
+	}
+	defer watcher.Close()
+
+	// The directory is watched since editors often replace the file
+	if err := watcher.Add(filepath.Dir(a.agentFile)); err != nil {
+		slog.Warn("Failed to watch the agent file", "path", a.agentFile, "error", err)
+		return
+	}
+
+	var reload <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) == a.agentFile && event.Has(fsnotify.Write|fsnotify.Create) {
+				reload = time.After(agentFileReloadDelay)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			slog.Warn("Error watching the agent file", "path", a.agentFile, "error", err)
+		case <-reload:
+			reload = nil
+			slog.Debug("Agent file changed, reloading the tools", "path", a.agentFile)
+			if err := a.ReloadTools(ctx); err != nil {
+				a.events <- runtime.Warning(fmt.Sprintf("The agent file changed but the tools couldn't be reloaded: %v", err), "")
+			}
+		}
+	}
+}
@@ -0,0 +1,71 @@
+// Package audit records security-relevant runtime events (tool call
+// approvals and denials, policy decisions, untrusted content...) and ships
+// them to external systems such as syslog, Splunk or any HTTPS endpoint.
+//
+// Events are recorded without blocking the runtime and are forwarded in
+// batches, with retries, by a Forwarder.
+package audit
+
+import (
+	"time"
+)
+
+// EventType identifies what happened.
+type EventType string
+
+const (
+	// ToolCallApproved is recorded when a tool call is allowed to run,
+	// either automatically or by the user.
+	ToolCallApproved EventType = "tool_call.approved"
+	// ToolCallDenied is recorded when permissions or a policy block a tool call.
+	ToolCallDenied EventType = "tool_call.denied"
+	// ToolCallRejected is recorded when the user rejects a tool call.
+	ToolCallRejected EventType = "tool_call.rejected"
+	// ToolCallExecuted is recorded once a tool call has run.
+	ToolCallExecuted EventType = "tool_call.executed"
+	// PolicyDecision is recorded every time the policy engine is consulted.
+	PolicyDecision EventType = "guardrail.policy_decision"
+	// UntrustedContent is recorded when a tool returns untrusted content.
+	UntrustedContent EventType = "guardrail.untrusted_content"
+	// GuardrailBlocked is recorded when a guardrail blocks an answer or a tool call.
+	GuardrailBlocked EventType = "guardrail.blocked"
+	// GuardrailRedacted is recorded when a guardrail redacts an answer or the
+	// arguments of a tool call.
+	GuardrailRedacted EventType = "guardrail.redacted"
+)
+
+// Event is a single audit log entry.
+type Event struct {
+	Time       time.Time `json:"time"`
+	Type       EventType `json:"type"`
+	SessionID  string    `json:"session_id,omitempty"`
+	Agent      string    `json:"agent,omitempty"`
+	Tool       string    `json:"tool,omitempty"`
+	ToolCallID string    `json:"tool_call_id,omitempty"`
+	// Arguments are the raw JSON arguments of the tool call.
+	Arguments string `json:"arguments,omitempty"`
+	// Decision is the outcome of a decision (allow, deny, ask...).
+	Decision string `json:"decision,omitempty"`
+	// Source tells who took the decision (user, policy, team permissions...).
+	Source string `json:"source,omitempty"`
+	Reason string `json:"reason,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Recorder records audit events. Implementations must not block.
+type Recorder interface {
+	Record(event Event)
+}
+
+// Multi returns a Recorder sending every event to all the given recorders.
+func Multi(recorders ...Recorder) Recorder {
+	return multiRecorder(recorders)
+}
+
+type multiRecorder []Recorder
+
+func (m multiRecorder) Record(event Event) {
+	for _, r := range m {
+		r.Record(event)
+	}
+}
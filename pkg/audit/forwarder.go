@@ -0,0 +1,184 @@
+package audit
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Sink ships a batch of events to an external system.
+type Sink interface {
+	Send(ctx context.Context, events []Event) error
+	// Name is used in logs, it must not contain secrets.
+	Name() string
+}
+
+const (
+	defaultBatchSize     = 100
+	defaultFlushInterval = 2 * time.Second
+	defaultMaxRetries    = 5
+	defaultRetryBackoff  = 500 * time.Millisecond
+	defaultBufferSize    = 10_000
+	sendTimeout          = 10 * time.Second
+)
+
+// Forwarder batches events and sends them to a Sink in the background,
+// retrying failed batches with exponential backoff.
+type Forwarder struct {
+	sink          Sink
+	batchSize     int
+	flushInterval time.Duration
+	maxRetries    int
+	retryBackoff  time.Duration
+
+	events chan Event
+	done   chan struct{}
+
+	// mu guards closed, so that no event is sent once events is closed.
+	mu     sync.Mutex
+	closed bool
+}
+
+type ForwarderOpt func(*Forwarder)
+
+// WithBatchSize sets the maximum number of events sent at once.
+func WithBatchSize(size int) ForwarderOpt {
+	return func(f *Forwarder) {
+		f.batchSize = size
+	}
+}
+
+// WithFlushInterval sets how long events can wait before being sent.
+func WithFlushInterval(interval time.Duration) ForwarderOpt {
+	return func(f *Forwarder) {
+		f.flushInterval = interval
+	}
+}
+
+// WithRetries sets how many times a failed batch is retried, and the
+// initial backoff between attempts.
+func WithRetries(maxRetries int, backoff time.Duration) ForwarderOpt {
+	return func(f *Forwarder) {
+		f.maxRetries = maxRetries
+		f.retryBackoff = backoff
+	}
+}
+
+// NewForwarder starts forwarding events to the given sink. Close must be
+// called to flush the pending events.
+func NewForwarder(sink Sink, opts ...ForwarderOpt) *Forwarder {
+	f := &Forwarder{
+		sink:          sink,
+		batchSize:     defaultBatchSize,
+		flushInterval: defaultFlushInterval,
+		maxRetries:    defaultMaxRetries,
+		retryBackoff:  defaultRetryBackoff,
+		events:        make(chan Event, defaultBufferSize),
+		done:          make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	go f.loop()
+
+	return f
+}
+
+// Record queues an event. If the buffer is full, the event is dropped
+// rather than slowing down the runtime. Events recorded after Close are
+// dropped too.
+func (f *Forwarder) Record(event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		slog.Debug("Audit forwarder is closed, dropping event", "sink", f.sink.Name(), "type", event.Type)
+		return
+	}
+
+	select {
+	case f.events <- event:
+	default:
+		slog.Warn("Audit buffer is full, dropping event", "sink", f.sink.Name(), "type", event.Type)
+	}
+}
+
+// Close flushes the pending events and stops the forwarder. It gives up
+// when the context is done.
+func (f *Forwarder) Close(ctx context.Context) error {
+	f.mu.Lock()
+	if !f.closed {
+		f.closed = true
+		close(f.events)
+	}
+	f.mu.Unlock()
+
+	select {
+	case <-f.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if closer, ok := f.sink.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+func (f *Forwarder) loop() {
+	defer close(f.done)
+
+	ticker := time.NewTicker(f.flushInterval)
+	defer ticker.Stop()
+
+	var batch []Event
+	for {
+		select {
+		case event, ok := <-f.events:
+			if !ok {
+				f.send(batch)
+				return
+			}
+			batch = append(batch, event)
+			if len(batch) >= f.batchSize {
+				f.send(batch)
+				batch = nil
+			}
+		case <-ticker.C:
+			f.send(batch)
+			batch = nil
+		}
+	}
+}
+
+func (f *Forwarder) send(batch []Event) {
+	if len(batch) == 0 {
+		return
+	}
+
+	backoff := f.retryBackoff
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		err := f.sink.Send(ctx, batch)
+		cancel()
+		if err == nil {
+			return
+		}
+
+		if attempt >= f.maxRetries {
+			slog.Error("Failed to forward audit events", "sink", f.sink.Name(), "events", len(batch), "error", err)
+			return
+		}
+
+		slog.Debug("Retrying audit events", "sink", f.sink.Name(), "attempt", attempt+1, "error", err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
@@ -0,0 +1,101 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSink struct {
+	mu       sync.Mutex
+	batches  [][]Event
+	failures int
+}
+
+func (s *fakeSink) Name() string { return "fake" }
+
+func (s *fakeSink) Send(_ context.Context, events []Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("unavailable")
+	}
+	s.batches = append(s.batches, events)
+	return nil
+}
+
+func (s *fakeSink) sent() [][]Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.batches
+}
+
+func TestForwarder_Batches(t *testing.T) {
+	sink := &fakeSink{}
+	f := NewForwarder(sink, WithBatchSize(2), WithFlushInterval(time.Hour))
+
+	for _, tool := range []string{"a", "b", "c"} {
+		f.Record(Event{Type: ToolCallExecuted, Tool: tool})
+	}
+	require.NoError(t, f.Close(t.Context()))
+
+	batches := sink.sent()
+	require.Len(t, batches, 2)
+	assert.Len(t, batches[0], 2)
+	assert.Equal(t, "c", batches[1][0].Tool)
+	assert.False(t, batches[0][0].Time.IsZero())
+}
+
+func TestForwarder_FlushesOnInterval(t *testing.T) {
+	sink := &fakeSink{}
+	f := NewForwarder(sink, WithFlushInterval(10*time.Millisecond))
+	defer f.Close(t.Context())
+
+	f.Record(Event{Type: ToolCallApproved})
+
+	assert.Eventually(t, func() bool { return len(sink.sent()) == 1 }, time.Second, 5*time.Millisecond)
+}
+
+func TestForwarder_Retries(t *testing.T) {
+	sink := &fakeSink{failures: 2}
+	f := NewForwarder(sink, WithRetries(2, time.Millisecond))
+
+	f.Record(Event{Type: ToolCallDenied})
+	require.NoError(t, f.Close(t.Context()))
+
+	assert.Len(t, sink.sent(), 1)
+}
+
+func TestForwarder_GivesUpAfterMaxRetries(t *testing.T) {
+	sink := &fakeSink{failures: 3}
+	f := NewForwarder(sink, WithRetries(1, time.Millisecond))
+
+	f.Record(Event{Type: ToolCallDenied})
+	require.NoError(t, f.Close(t.Context()))
+
+	assert.Empty(t, sink.sent())
+}
+
+func TestForwarder_DropsEventsAfterClose(t *testing.T) {
+	sink := &fakeSink{}
+	f := NewForwarder(sink)
+
+	f.Record(Event{Type: ToolCallExecuted, Tool: "a"})
+	require.NoError(t, f.Close(t.Context()))
+
+	assert.NotPanics(t, func() {
+		f.Record(Event{Type: ToolCallExecuted, Tool: "b"})
+	})
+	require.NoError(t, f.Close(t.Context()))
+
+	batches := sink.sent()
+	require.Len(t, batches, 1)
+	assert.Equal(t, "a", batches[0][0].Tool)
+}
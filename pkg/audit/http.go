@@ -0,0 +1,114 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/docker/cagent/pkg/httpclient"
+)
+
+// HTTPSink posts batches of events, as a JSON array, to any HTTP(S) endpoint.
+type HTTPSink struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// NewHTTPSink creates a sink posting to url. When token is not empty, it's
+// sent as a bearer token.
+func NewHTTPSink(url, token string) *HTTPSink {
+	return &HTTPSink{
+		url:    url,
+		token:  token,
+		client: httpclient.NewHTTPClient(),
+	}
+}
+
+func (s *HTTPSink) Name() string {
+	return s.url
+}
+
+func (s *HTTPSink) Send(ctx context.Context, events []Event) error {
+	body, err := json.Marshal(events)
+	if err != nil {
+		return fmt.Errorf("marshalling audit events: %w", err)
+	}
+
+	authorization := ""
+	if s.token != "" {
+		authorization = "Bearer " + s.token
+	}
+
+	return post(ctx, s.client, s.url, authorization, body)
+}
+
+// SplunkSink sends events to a Splunk HTTP Event Collector.
+type SplunkSink struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// NewSplunkSink creates a sink for the HEC at baseURL, eg. https://splunk:8088.
+func NewSplunkSink(baseURL, token string) *SplunkSink {
+	return &SplunkSink{
+		url:    baseURL + "/services/collector/event",
+		token:  token,
+		client: httpclient.NewHTTPClient(),
+	}
+}
+
+func (s *SplunkSink) Name() string {
+	return s.url
+}
+
+type splunkEvent struct {
+	Time       float64 `json:"time"`
+	SourceType string  `json:"sourcetype"`
+	Event      Event   `json:"event"`
+}
+
+func (s *SplunkSink) Send(ctx context.Context, events []Event) error {
+	// HEC accepts a batch as concatenated JSON objects
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, event := range events {
+		if err := encoder.Encode(splunkEvent{
+			Time:       float64(event.Time.UnixMilli()) / 1000,
+			SourceType: "cagent:audit",
+			Event:      event,
+		}); err != nil {
+			return fmt.Errorf("marshalling audit event: %w", err)
+		}
+	}
+
+	return post(ctx, s.client, s.url, "Splunk "+s.token, body.Bytes())
+}
+
+func post(ctx context.Context, client *http.Client, url, authorization string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send audit events: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("audit endpoint returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package audit
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testEvent = Event{
+	Time:      time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	Type:      ToolCallDenied,
+	SessionID: "session-1",
+	Tool:      "shell",
+	Source:    "policy",
+}
+
+func TestSyslogSink_UDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	sink := NewSyslogSink("udp", conn.LocalAddr().String())
+	defer sink.Close()
+	require.NoError(t, sink.Send(t.Context(), []Event{testEvent}))
+
+	buf := make([]byte, 4096)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+
+	msg := string(buf[:n])
+	assert.True(t, strings.HasPrefix(msg, "<133>1 2025-01-02T03:04:05Z "), msg)
+	assert.Contains(t, msg, " cagent ")
+	assert.Contains(t, msg, " tool_call.denied - {")
+
+	var event Event
+	require.NoError(t, json.Unmarshal([]byte(msg[strings.Index(msg, "{"):]), &event))
+	assert.Equal(t, testEvent, event)
+}
+
+func TestSplunkSink(t *testing.T) {
+	var (
+		authorization string
+		body          string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/services/collector/event", r.URL.Path)
+		authorization = r.Header.Get("Authorization")
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+	}))
+	defer server.Close()
+
+	sink := NewSplunkSink(server.URL, "secret")
+	require.NoError(t, sink.Send(t.Context(), []Event{testEvent, testEvent}))
+
+	assert.Equal(t, "Splunk secret", authorization)
+	lines := strings.Split(strings.TrimSpace(body), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"time":1735787045,"sourcetype":"cagent:audit","event":{`)
+}
+
+func TestHTTPSink(t *testing.T) {
+	var (
+		authorization string
+		events        []Event
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&events))
+	}))
+	defer server.Close()
+
+	require.NoError(t, NewHTTPSink(server.URL, "token").Send(t.Context(), []Event{testEvent}))
+
+	assert.Equal(t, "Bearer token", authorization)
+	assert.Equal(t, []Event{testEvent}, events)
+}
+
+func TestHTTPSink_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	err := NewHTTPSink(server.URL, "").Send(t.Context(), []Event{testEvent})
+	require.ErrorContains(t, err, "status 503")
+}
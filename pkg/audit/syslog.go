@@ -0,0 +1,118 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Facility local0, severity notice.
+const syslogPriority = 16*8 + 5
+
+// SyslogSink sends events as RFC 5424 messages, with a JSON payload, over
+// UDP or TCP.
+type SyslogSink struct {
+	network  string
+	address  string
+	hostname string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewSyslogSink creates a sink for the given network ("udp" or "tcp") and
+// address. The connection is opened lazily.
+func NewSyslogSink(network, address string) *SyslogSink {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+
+	return &SyslogSink{
+		network:  network,
+		address:  address,
+		hostname: hostname,
+	}
+}
+
+func (s *SyslogSink) Name() string {
+	return "syslog+" + s.network + "://" + s.address
+}
+
+func (s *SyslogSink) Send(ctx context.Context, events []Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, s.network, s.address)
+		if err != nil {
+			return fmt.Errorf("connecting to syslog: %w", err)
+		}
+		s.conn = conn
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = s.conn.SetWriteDeadline(deadline)
+	}
+
+	for _, event := range events {
+		line, err := s.format(event)
+		if err != nil {
+			return err
+		}
+		if _, err := s.conn.Write(line); err != nil {
+			// Reconnect on the next attempt
+			_ = s.conn.Close()
+			s.conn = nil
+			return fmt.Errorf("writing to syslog: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// format renders an event as an RFC 5424 message. TCP messages use the
+// octet-counting framing of RFC 6587.
+func (s *SyslogSink) format(event Event) ([]byte, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling audit event: %w", err)
+	}
+
+	msgID := strings.ReplaceAll(string(event.Type), " ", "_")
+	if msgID == "" {
+		msgID = "-"
+	}
+
+	msg := fmt.Sprintf("<%d>1 %s %s cagent %d %s - %s",
+		syslogPriority,
+		event.Time.UTC().Format(time.RFC3339Nano),
+		s.hostname,
+		os.Getpid(),
+		msgID,
+		payload,
+	)
+
+	if s.network == "tcp" {
+		return fmt.Appendf(nil, "%d %s", len(msg), msg), nil
+	}
+	return []byte(msg), nil
+}
+
+func (s *SyslogSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
@@ -0,0 +1,64 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/docker/cagent/pkg/environment"
+)
+
+const (
+	// envSplunkToken holds the token of Splunk HTTP Event Collectors.
+	envSplunkToken = "SPLUNK_HEC_TOKEN"
+	// envAuditToken holds the bearer token sent to HTTP(S) endpoints.
+	envAuditToken = "CAGENT_AUDIT_TOKEN"
+)
+
+// NewSink creates a sink from a target URL:
+//
+//   - syslog://host:514 (UDP) or syslog+tcp://host:514
+//   - splunk://host:8088 (HTTPS) or splunk+http://host:8088, with the token
+//     read from SPLUNK_HEC_TOKEN
+//   - https://host/path or http://host/path, with an optional bearer token
+//     read from CAGENT_AUDIT_TOKEN
+func NewSink(ctx context.Context, target string, env environment.Provider) (Sink, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid audit target %q: %w", target, err)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid audit target %q: missing host", target)
+	}
+
+	switch u.Scheme {
+	case "syslog", "syslog+udp":
+		return NewSyslogSink("udp", withDefaultPort(u.Host, "514")), nil
+	case "syslog+tcp":
+		return NewSyslogSink("tcp", withDefaultPort(u.Host, "514")), nil
+	case "splunk", "splunk+https", "splunk+http":
+		token, _ := env.Get(ctx, envSplunkToken)
+		if token == "" {
+			return nil, errors.New("environment variable " + envSplunkToken + " is required to forward audit events to Splunk")
+		}
+		scheme := "https"
+		if u.Scheme == "splunk+http" {
+			scheme = "http"
+		}
+		return NewSplunkSink(scheme+"://"+withDefaultPort(u.Host, "8088"), token), nil
+	case "https", "http":
+		token, _ := env.Get(ctx, envAuditToken)
+		return NewHTTPSink(target, token), nil
+	default:
+		return nil, fmt.Errorf("unsupported audit target scheme %q", u.Scheme)
+	}
+}
+
+func withDefaultPort(host, port string) string {
+	if strings.LastIndex(host, ":") > strings.LastIndex(host, "]") {
+		return host
+	}
+	return host + ":" + port
+}
@@ -0,0 +1,45 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/environment"
+)
+
+func TestNewSink(t *testing.T) {
+	env := environment.NewEnvListProvider([]string{"SPLUNK_HEC_TOKEN=hec", "CAGENT_AUDIT_TOKEN=bearer"})
+
+	tests := []struct {
+		target string
+		name   string
+	}{
+		{"syslog://logs.example.com", "syslog+udp://logs.example.com:514"},
+		{"syslog+tcp://logs.example.com:6514", "syslog+tcp://logs.example.com:6514"},
+		{"splunk://splunk.example.com", "https://splunk.example.com:8088/services/collector/event"},
+		{"splunk+http://localhost:8000", "http://localhost:8000/services/collector/event"},
+		{"https://siem.example.com/ingest", "https://siem.example.com/ingest"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			sink, err := NewSink(t.Context(), tt.target, env)
+			require.NoError(t, err)
+			assert.Equal(t, tt.name, sink.Name())
+		})
+	}
+}
+
+func TestNewSink_Errors(t *testing.T) {
+	env := environment.NewEnvListProvider(nil)
+
+	_, err := NewSink(t.Context(), "ftp://example.com", env)
+	require.ErrorContains(t, err, "unsupported audit target scheme")
+
+	_, err = NewSink(t.Context(), "splunk://example.com", env)
+	require.ErrorContains(t, err, "SPLUNK_HEC_TOKEN")
+
+	_, err = NewSink(t.Context(), "https://", env)
+	require.ErrorContains(t, err, "missing host")
+}
@@ -14,6 +14,7 @@ import (
 	"golang.org/x/term"
 
 	"github.com/docker/cagent/pkg/input"
+	"github.com/docker/cagent/pkg/runtime"
 	"github.com/docker/cagent/pkg/tools"
 )
 
@@ -31,6 +32,10 @@ var bold = color.New(color.Bold).SprintfFunc()
 
 type Printer struct {
 	out io.Writer
+
+	// indent starts the lines printed for the sub-agents
+	indent  string
+	midLine bool
 }
 
 func NewPrinter(out io.Writer) *Printer {
@@ -40,15 +45,37 @@ func NewPrinter(out io.Writer) *Printer {
 }
 
 func (p *Printer) Println(a ...any) {
-	fmt.Fprintln(p.out, a...)
+	p.write(fmt.Sprintln(a...))
 }
 
 func (p *Printer) Print(a ...any) {
-	fmt.Fprint(p.out, a...)
+	p.write(fmt.Sprint(a...))
 }
 
 func (p *Printer) Printf(format string, a ...any) {
-	fmt.Fprintf(p.out, format, a...)
+	p.write(fmt.Sprintf(format, a...))
+}
+
+// SetDepth indents the next lines printed by the depth of the agent in task
+// transfers, so that what sub-agents do is nested in the output of the agent
+// that transferred them the task.
+func (p *Printer) SetDepth(depth int) {
+	p.indent = strings.Repeat("│ ", depth)
+}
+
+func (p *Printer) write(s string) {
+	for s != "" {
+		if !p.midLine && s[0] != '\n' {
+			fmt.Fprint(p.out, p.indent)
+		}
+		line, rest, found := strings.Cut(s, "\n")
+		fmt.Fprint(p.out, line)
+		if found {
+			fmt.Fprintln(p.out)
+		}
+		p.midLine = !found
+		s = rest
+	}
 }
 
 // PrintWelcomeMessage prints the welcome message
@@ -138,6 +165,11 @@ func (p *Printer) PrintToolCallResponse(toolCall tools.ToolCall, response string
 	p.Printf("\n%s response%s\n", bold(toolCall.Function.Name), formatToolCallResponse(response))
 }
 
+// PrintUsage prints the tokens used and the cost of a run
+func (p *Printer) PrintUsage(totals runtime.UsageTotals) {
+	p.Printf("\n\n--- Usage: %d input tokens, %d output tokens, $%.4f ---\n", totals.InputTokens, totals.OutputTokens, totals.Cost)
+}
+
 // PromptMaxIterationsContinue prompts the user to continue after max iterations
 func (p *Printer) PromptMaxIterationsContinue(ctx context.Context, maxIterations int) ConfirmationResult {
 	p.Printf("\n⚠️  Maximum iterations (%d) reached. The agent may be stuck in a loop.\n", maxIterations)
@@ -160,6 +192,37 @@ func (p *Printer) PromptMaxIterationsContinue(ctx context.Context, maxIterations
 	}
 }
 
+// PrintCostEstimate prints the estimated tokens and cost of a request
+func (p *Printer) PrintCostEstimate(estimate *runtime.CostEstimate) {
+	p.Printf("Estimated input: ~%d tokens for %s (%s)\n", estimate.InputTokens, estimate.Agent, estimate.Model)
+	if !estimate.Priced {
+		p.Println("Estimated cost: unknown, no pricing for this model")
+		return
+	}
+	p.Printf("Estimated cost: $%.4f - $%.4f (up to %d output tokens)\n", estimate.MinCost, estimate.MaxCost, estimate.MaxOutputTokens)
+}
+
+// PromptCostContinue prompts the user to run a request whose estimated cost
+// exceeds a threshold
+func (p *Printer) PromptCostContinue(ctx context.Context, estimate *runtime.CostEstimate, threshold float64) ConfirmationResult {
+	p.Printf("\n⚠️  The request may cost up to $%.4f, more than $%.4f.\n", estimate.MaxCost, threshold)
+	p.PrintCostEstimate(estimate)
+	p.Printf("\n%s (y/n): ", "Do you want to send it?")
+
+	response, err := input.ReadLine(ctx, os.Stdin)
+	if err != nil {
+		p.Println("\nFailed to read input, exiting...")
+		return ConfirmationAbort
+	}
+
+	response = strings.TrimSpace(strings.ToLower(response))
+	if response == "y" || response == "yes" {
+		return ConfirmationApprove
+	}
+	p.Println("Exiting...")
+	return ConfirmationReject
+}
+
 // PromptOAuthAuthorization prompts the user for OAuth authorization
 func (p *Printer) PromptOAuthAuthorization(ctx context.Context, serverURL string) ConfirmationResult {
 	p.Println("\n🔐 OAuth Authorization Required")
@@ -186,6 +249,114 @@ func (p *Printer) PromptOAuthAuthorization(ctx context.Context, serverURL string
 	}
 }
 
+// PromptSampling asks the user to approve a completion requested by an MCP server
+func (p *Printer) PromptSampling(ctx context.Context, message string, rd io.Reader) ConfirmationResult {
+	p.Printf("\n%s\n", bold("🤖 Sampling request"))
+	p.Println(message)
+	p.Printf("\n%s (y/n): ", "Do you want to run it?")
+
+	response, err := input.ReadLine(ctx, rd)
+	if err != nil {
+		return ConfirmationReject
+	}
+
+	response = strings.TrimSpace(strings.ToLower(response))
+	if response == "y" || response == "yes" {
+		return ConfirmationApprove
+	}
+	return ConfirmationReject
+}
+
+// PromptElicitation asks the user for the input requested by an MCP server,
+// as a JSON object. An empty answer declines the request.
+func (p *Printer) PromptElicitation(ctx context.Context, server, message string, schema any, rd io.Reader) (tools.ElicitationAction, map[string]any) {
+	fields, err := tools.ElicitationFields(schema)
+	if err != nil {
+		p.PrintError(err)
+		return tools.ElicitationActionDecline, nil
+	}
+
+	p.Printf("\n%s\n", bold("📝 Input requested by %s", server))
+	p.Println(message)
+	for _, field := range fields {
+		p.Println(formatElicitationField(field))
+	}
+
+	for {
+		if len(fields) == 0 {
+			p.Printf("\n%s (y/n): ", "Do you want to continue?")
+		} else {
+			p.Printf("\n%s ", "Enter a JSON object (empty to decline):")
+		}
+
+		response, err := input.ReadLine(ctx, rd)
+		if err != nil {
+			return tools.ElicitationActionCancel, nil
+		}
+		response = strings.TrimSpace(response)
+
+		if len(fields) == 0 {
+			if r := strings.ToLower(response); r == "y" || r == "yes" {
+				return tools.ElicitationActionAccept, nil
+			}
+			return tools.ElicitationActionDecline, nil
+		}
+		if response == "" {
+			return tools.ElicitationActionDecline, nil
+		}
+
+		content, err := parseElicitationContent(fields, response)
+		if err != nil {
+			p.PrintError(err)
+			continue
+		}
+		return tools.ElicitationActionAccept, content
+	}
+}
+
+func formatElicitationField(field tools.ElicitationField) string {
+	line := fmt.Sprintf("  - %s (%s", field.Name, field.Type)
+	if field.Required {
+		line += ", required"
+	}
+	line += ")"
+	if field.Description != "" {
+		line += ": " + field.Description
+	}
+	if len(field.Enum) > 0 {
+		line += fmt.Sprintf(" [%s]", strings.Join(field.Enum, ", "))
+	}
+	if field.Default != nil {
+		line += fmt.Sprintf(" (default: %v)", field.Default)
+	}
+	return line
+}
+
+// parseElicitationContent parses the JSON object typed by the user and
+// checks that it has the required fields of the form.
+func parseElicitationContent(fields []tools.ElicitationField, text string) (map[string]any, error) {
+	var content map[string]any
+	if err := json.Unmarshal([]byte(text), &content); err != nil {
+		return nil, fmt.Errorf("invalid JSON object: %w", err)
+	}
+
+	for _, field := range fields {
+		value, ok := content[field.Name]
+		if !ok {
+			if field.Required {
+				return nil, fmt.Errorf("%s is required", field.Name)
+			}
+			continue
+		}
+		if len(field.Enum) > 0 {
+			if _, err := field.Parse(fmt.Sprint(value)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return content, nil
+}
+
 func formatToolCallArguments(arguments string) string {
 	if arguments == "" {
 		return "()"
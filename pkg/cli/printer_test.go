@@ -1,9 +1,16 @@
 package cli
 
 import (
+	"bytes"
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+
+	"github.com/docker/cagent/pkg/runtime"
+	"github.com/docker/cagent/pkg/tools"
 )
 
 func TestFormatToolCallResponse_Empty(t *testing.T) {
@@ -81,3 +88,67 @@ func TestFormatToolCallArguments_PlainText(t *testing.T) {
 
 	assert.Equal(t, `(Plain Text)`, formatted)
 }
+
+func TestPrintUsage(t *testing.T) {
+	var buf bytes.Buffer
+	NewPrinter(&buf).PrintUsage(runtime.UsageTotals{InputTokens: 1200, OutputTokens: 34, Cost: 0.0125})
+
+	assert.Equal(t, "\n\n--- Usage: 1200 input tokens, 34 output tokens, $0.0125 ---\n", buf.String())
+}
+
+func TestPrinter_IndentsSubAgents(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewPrinter(&buf)
+
+	p.Print("Let me ask.\n")
+	p.SetDepth(1)
+	p.Print("Searching")
+	p.Print(" now\n\nFound")
+	p.SetDepth(0)
+	p.Println()
+	p.Print("Done")
+
+	assert.Equal(t, "Let me ask.\n│ Searching now\n\n│ Found\nDone", buf.String())
+}
+
+func TestPromptElicitation(t *testing.T) {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"env":      map[string]any{"type": "string", "enum": []any{"dev", "prod"}},
+			"replicas": map[string]any{"type": "integer", "description": "Number of replicas"},
+		},
+		"required": []string{"env"},
+	}
+
+	var buf bytes.Buffer
+	action, content := NewPrinter(&buf).PromptElicitation(t.Context(), "deploy", "Where should it run?", schema,
+		// Each prompt reads the input anew: it's read as a terminal would send it
+		iotest.OneByteReader(strings.NewReader("{\"replicas\": 2}\n{\"env\": \"prod\", \"replicas\": 2}\n")))
+
+	assert.Equal(t, tools.ElicitationActionAccept, action)
+	assert.DeepEqual(t, map[string]any{"env": "prod", "replicas": float64(2)}, content)
+	assert.Assert(t, is.Contains(buf.String(), "Input requested by deploy"))
+	assert.Assert(t, is.Contains(buf.String(), "  - env (string, required) [dev, prod]"))
+	assert.Assert(t, is.Contains(buf.String(), "  - replicas (integer): Number of replicas"))
+	assert.Assert(t, is.Contains(buf.String(), "env is required"))
+}
+
+func TestPromptElicitation_EmptyDeclines(t *testing.T) {
+	schema := map[string]any{"type": "object", "properties": map[string]any{"name": map[string]any{"type": "string"}}}
+
+	action, content := NewPrinter(&bytes.Buffer{}).PromptElicitation(t.Context(), "deploy", "Name?", schema, strings.NewReader("\n"))
+
+	assert.Equal(t, tools.ElicitationActionDecline, action)
+	assert.Assert(t, content == nil)
+}
+
+func TestParseElicitationContent(t *testing.T) {
+	fields := []tools.ElicitationField{{Name: "env", Type: "string", Enum: []string{"dev", "prod"}}}
+
+	_, err := parseElicitationContent(fields, `{"env": "staging"}`)
+	assert.Error(t, err, "env must be one of dev, prod")
+
+	_, err = parseElicitationContent(fields, `not json`)
+	assert.ErrorContains(t, err, "invalid JSON object")
+}
@@ -11,12 +11,15 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/docker/cagent/pkg/chat"
 	"github.com/docker/cagent/pkg/input"
+	"github.com/docker/cagent/pkg/review"
 	"github.com/docker/cagent/pkg/runtime"
 	"github.com/docker/cagent/pkg/session"
 	"github.com/docker/cagent/pkg/telemetry"
+	"github.com/docker/cagent/pkg/tools"
 )
 
 // RuntimeError wraps runtime errors to distinguish them from usage errors
@@ -38,7 +41,18 @@ type Config struct {
 	AttachmentPath string
 	AutoApprove    bool
 	HideToolCalls  bool
-	OutputJSON     bool
+	// HideSubAgents hides what the agents tasks are transferred to say and
+	// the tools they call. They still ask for confirmations.
+	HideSubAgents bool
+	OutputJSON    bool
+	// OutputJSONL prints every event as a versioned runtime.EventRecord, one
+	// per line.
+	OutputJSONL bool
+	// ShowUsage prints the tokens and cost of each run once it's done.
+	ShowUsage bool
+	// Review, when set, sends tool calls requiring approval to a persistent
+	// review queue instead of prompting on the terminal.
+	Review *review.Queue
 }
 
 // Run executes an agent in non-TUI mode, handling user input and runtime events
@@ -66,31 +80,57 @@ func Run(ctx context.Context, out *Printer, cfg Config, rt runtime.Runtime, sess
 
 		sess.AddMessage(PrepareUserMessage(ctx, rt, userInput, cfg.AttachmentPath))
 
-		if cfg.OutputJSON {
+		if cfg.OutputJSON || cfg.OutputJSONL {
 			for event := range rt.RunStream(ctx, sess) {
+				if cfg.HideSubAgents && runtime.EventDepth(event) > 0 && isSubAgentOutput(event) {
+					continue
+				}
 				switch e := event.(type) {
 				case *runtime.ToolCallConfirmationEvent:
-					if !cfg.AutoApprove {
+					switch {
+					case cfg.Review != nil:
+						resumeWithReview(ctx, rt, cfg, sess, e, nil, cancel)
+					case !cfg.AutoApprove:
 						rt.Resume(ctx, runtime.ResumeTypeReject)
 					}
 				case *runtime.ErrorEvent:
-					return fmt.Errorf("%s", e.Error)
+					if !cfg.OutputJSONL {
+						return fmt.Errorf("%s", e.Error)
+					}
+					// Errors are events like the others, the run fails once they're written
+					lastErr = fmt.Errorf("%s", e.Error)
 				}
 
-				buf, err := json.Marshal(event)
+				buf, err := encodeEvent(cfg, event)
 				if err != nil {
 					return err
 				}
 				out.Println(string(buf))
+
+				// The request is written first: the answer is the next line of the input
+				if _, ok := event.(*runtime.ElicitationRequestEvent); ok {
+					action, content := readElicitationResponse(ctx, rd)
+					_ = rt.ResumeElicitation(ctx, action, content)
+				}
 			}
 
+			if lastErr != nil {
+				return RuntimeError{Err: lastErr}
+			}
 			return nil
 		}
 
 		firstLoop := true
 		lastAgent := rt.CurrentAgentName()
 		var lastConfirmedToolCallID string
+		usage := runtime.NewUsageTracker()
 		for event := range rt.RunStream(ctx, sess) {
+			depth := runtime.EventDepth(event)
+			if cfg.HideSubAgents && depth > 0 && isSubAgentOutput(event) {
+				continue
+			}
+			out.SetDepth(depth)
+
 			agentName := event.GetAgentName()
 			if agentName != "" && (firstLoop || lastAgent != agentName) {
 				if !firstLoop {
@@ -105,7 +145,17 @@ func Run(ctx context.Context, out *Printer, cfg Config, rt runtime.Runtime, sess
 				out.Print(e.Content)
 			case *runtime.AgentChoiceReasoningEvent:
 				out.Print(e.Content)
+			case *runtime.TokenUsageEvent:
+				usage.Record(e)
 			case *runtime.ToolCallConfirmationEvent:
+				if cfg.Review != nil {
+					out.PrintToolCall(e.ToolCall)
+					lastConfirmedToolCallID = e.ToolCall.ID
+					if !resumeWithReview(ctx, rt, cfg, sess, e, out, cancel) {
+						lastConfirmedToolCallID = ""
+					}
+					continue
+				}
 				result := out.PrintToolCallWithConfirmation(ctx, e.ToolCall, rd)
 				// If interrupted, skip resuming; the runtime will notice context cancellation and stop
 				if ctx.Err() != nil {
@@ -164,20 +214,47 @@ func Run(ctx context.Context, out *Printer, cfg Config, rt runtime.Runtime, sess
 					return nil
 				}
 			case *runtime.ElicitationRequestEvent:
-				serverURL := e.Meta["cagent/server_url"].(string)
-				result := out.PromptOAuthAuthorization(ctx, serverURL)
-				switch {
-				case ctx.Err() != nil:
-					return ctx.Err()
-				case result == ConfirmationApprove:
-					_ = rt.ResumeElicitation(ctx, "accept", nil)
-				case result == ConfirmationReject:
-					_ = rt.ResumeElicitation(ctx, "decline", nil)
-					return fmt.Errorf("OAuth authorization rejected by user")
+				switch e.Meta["cagent/type"] {
+				case "sampling":
+					action := tools.ElicitationActionDecline
+					if out.PromptSampling(ctx, e.Message, rd) == ConfirmationApprove {
+						action = tools.ElicitationActionAccept
+					}
+					if ctx.Err() != nil {
+						return ctx.Err()
+					}
+					_ = rt.ResumeElicitation(ctx, action, nil)
+				case "elicitation":
+					server, _ := e.Meta["cagent/server"].(string)
+					message := e.Message
+					if url, ok := e.Meta["cagent/url"].(string); ok {
+						message += "\n" + url
+					}
+					action, content := out.PromptElicitation(ctx, server, message, e.Schema, rd)
+					if ctx.Err() != nil {
+						return ctx.Err()
+					}
+					_ = rt.ResumeElicitation(ctx, action, content)
+				default:
+					serverURL, _ := e.Meta["cagent/server_url"].(string)
+					result := out.PromptOAuthAuthorization(ctx, serverURL)
+					switch {
+					case ctx.Err() != nil:
+						return ctx.Err()
+					case result == ConfirmationApprove:
+						_ = rt.ResumeElicitation(ctx, "accept", nil)
+					case result == ConfirmationReject:
+						_ = rt.ResumeElicitation(ctx, "decline", nil)
+						return fmt.Errorf("OAuth authorization rejected by user")
+					}
 				}
 			}
 		}
 
+		if cfg.ShowUsage {
+			out.PrintUsage(usage.Totals())
+		}
+
 		// Wrap runtime errors to prevent duplicate error messages and usage display
 		if lastErr != nil {
 			return RuntimeError{Err: lastErr}
@@ -229,6 +306,100 @@ func Run(ctx context.Context, out *Printer, cfg Config, rt runtime.Runtime, sess
 	return nil
 }
 
+// isSubAgentOutput reports whether an event is only printed to follow what
+// an agent does, and can be hidden for sub-agents.
+func isSubAgentOutput(event runtime.Event) bool {
+	switch event.(type) {
+	case *runtime.AgentChoiceEvent, *runtime.AgentChoiceReasoningEvent, *runtime.ToolCallEvent, *runtime.ToolCallResponseEvent:
+		return true
+	default:
+		return false
+	}
+}
+
+// encodeEvent encodes an event for the JSON outputs.
+func encodeEvent(cfg Config, event runtime.Event) ([]byte, error) {
+	if !cfg.OutputJSONL {
+		return json.Marshal(event)
+	}
+
+	record, err := runtime.NewEventRecord(event, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(record)
+}
+
+// resumeWithReview submits a tool call to the review queue, suspends until a
+// human decides and resumes the runtime accordingly. It reports whether the
+// tool call was approved.
+func resumeWithReview(ctx context.Context, rt runtime.Runtime, cfg Config, sess *session.Session, e *runtime.ToolCallConfirmationEvent, out *Printer, cancel context.CancelFunc) bool {
+	q := cfg.Review
+	req, err := q.Submit(ctx, sess.ID, e.AgentName, e.ToolCall)
+	if err != nil {
+		slog.Error("Failed to submit tool call for review", "tool", e.ToolCall.Function.Name, "error", err)
+		rt.Resume(ctx, runtime.ResumeTypeReject)
+		return false
+	}
+
+	slog.Info("Tool call waiting for review", "review_id", req.ID, "tool", req.ToolName)
+	if out != nil {
+		out.Printf("\n%s\n", bold("⏸️ Tool call queued for review: "+req.ID))
+		out.Printf("Approve or reject it with: %s review approve|reject %s\n", cfg.AppName, req.ID)
+	}
+
+	status, err := q.Wait(ctx, req.ID)
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		slog.Error("Failed to wait for review", "review_id", req.ID, "error", err)
+		rt.Resume(ctx, runtime.ResumeTypeReject)
+		return false
+	}
+
+	if status == review.StatusExpired && q.ExpiryPolicy() == review.ExpiryAbort {
+		slog.Warn("Review request expired, aborting run", "review_id", req.ID)
+		cancel()
+		return false
+	}
+
+	slog.Info("Tool call reviewed", "review_id", req.ID, "status", status)
+	if status == review.StatusApproved {
+		rt.Resume(ctx, runtime.ResumeTypeApprove)
+		return true
+	}
+
+	rt.Resume(ctx, runtime.ResumeTypeReject)
+	return false
+}
+
+// readElicitationResponse reads the answer to an elicitation request in the
+// JSON outputs: a line such as {"action":"accept","content":{"name":"value"}}.
+// The request is declined when the input has no answer.
+func readElicitationResponse(ctx context.Context, rd io.Reader) (tools.ElicitationAction, map[string]any) {
+	line, err := input.ReadLine(ctx, rd)
+	if err != nil {
+		return tools.ElicitationActionDecline, nil
+	}
+
+	var response struct {
+		Action  tools.ElicitationAction `json:"action"`
+		Content map[string]any          `json:"content"`
+	}
+	if err := json.Unmarshal([]byte(line), &response); err != nil {
+		slog.Warn("Declining elicitation request, invalid response", "error", err)
+		return tools.ElicitationActionDecline, nil
+	}
+	switch response.Action {
+	case tools.ElicitationActionAccept, tools.ElicitationActionDecline, tools.ElicitationActionCancel:
+		return response.Action, response.Content
+	default:
+		slog.Warn("Declining elicitation request, invalid action", "action", response.Action)
+		return tools.ElicitationActionDecline, nil
+	}
+}
+
 // PrepareUserMessage resolves commands, parses /attach directives, and creates
 // a user message with optional image attachment. This is the common flow for
 // both TUI and CLI modes.
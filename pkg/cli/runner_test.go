@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/cagent/pkg/runtime"
+	"github.com/docker/cagent/pkg/tools"
+)
+
+func TestEncodeEvent(t *testing.T) {
+	event := runtime.AgentChoice("root", "Hello")
+
+	buf, err := encodeEvent(Config{OutputJSON: true}, event)
+	assert.NilError(t, err)
+	assert.Equal(t, `{"type":"agent_choice","content":"Hello","agent_name":"root"}`, string(buf))
+
+	buf, err = encodeEvent(Config{OutputJSONL: true}, event)
+	assert.NilError(t, err)
+	var record runtime.EventRecord
+	assert.NilError(t, json.Unmarshal(buf, &record))
+	assert.Equal(t, runtime.EventRecordVersion, record.Version)
+	assert.Equal(t, "agent_choice", record.Type)
+	assert.Equal(t, "root", record.Agent)
+	assert.Equal(t, `{"type":"agent_choice","content":"Hello","agent_name":"root"}`, string(record.Event))
+}
+
+func TestReadElicitationResponse(t *testing.T) {
+	action, content := readElicitationResponse(t.Context(), strings.NewReader(`{"action":"accept","content":{"env":"prod"}}`+"\n"))
+	assert.Equal(t, tools.ElicitationActionAccept, action)
+	assert.DeepEqual(t, map[string]any{"env": "prod"}, content)
+
+	action, content = readElicitationResponse(t.Context(), strings.NewReader(`{"action":"maybe"}`+"\n"))
+	assert.Equal(t, tools.ElicitationActionDecline, action)
+	assert.Assert(t, content == nil)
+
+	// No answer: the input is closed
+	action, _ = readElicitationResponse(t.Context(), strings.NewReader(""))
+	assert.Equal(t, tools.ElicitationActionDecline, action)
+}
@@ -54,6 +54,13 @@ func (s *Slice[V]) All() []V {
 	return append([]V(nil), s.values...)
 }
 
+func (s *Slice[V]) Replace(values []V) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values = append([]V(nil), values...)
+}
+
 func (s *Slice[V]) Range(f func(index int, value V) bool) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -71,6 +71,20 @@ func TestSlice_All(t *testing.T) {
 	assert.Equal(t, 1, val)
 }
 
+func TestSlice_Replace(t *testing.T) {
+	s := NewSlice[int]()
+	s.Append(1)
+
+	values := []int{2, 3}
+	s.Replace(values)
+	values[0] = 100
+
+	assert.Equal(t, []int{2, 3}, s.All())
+
+	s.Replace(nil)
+	assert.Equal(t, 0, s.Length())
+}
+
 func TestSlice_Range(t *testing.T) {
 	s := NewSlice[int]()
 	s.Append(10)
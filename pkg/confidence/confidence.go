@@ -0,0 +1,51 @@
+// Package confidence lets agents report how confident they are in their final
+// answers, so that users see it and the runtime can have the answers it has
+// little confidence in verified.
+//
+// The report ends the answer as a tagged block:
+//
+//	<confidence level="low">The API docs don't say whether the limit is per key.</confidence>
+package confidence
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Confidence levels
+const (
+	High   = "high"
+	Medium = "medium"
+	Low    = "low"
+)
+
+// Instruction is the system prompt fragment asking the agent to report its
+// confidence in its final answers.
+const Instruction = `End every final answer, the one where you don't call any tool, with your confidence in it, on a line of its own:
+<confidence level="high|medium|low">One sentence on why: what you checked, and what you assumed or couldn't verify.</confidence>
+Report a low confidence when the answer relies on guesses or assumptions you couldn't check.`
+
+// VerifyInstruction is sent to the agent when it reported a low confidence
+// and its answer should be verified.
+const VerifyInstruction = `You reported a low confidence in your last answer. Verify it: check the assumptions you couldn't verify, using your tools if they help, then give your final answer again, with your confidence in it.`
+
+// Report is the confidence an agent reported in an answer.
+type Report struct {
+	Level     string `json:"level"`
+	Rationale string `json:"rationale,omitempty"`
+}
+
+var reportPattern = regexp.MustCompile(`(?s)\s*<confidence\s+level="(high|medium|low)"\s*>(.*?)</confidence>\s*$`)
+
+// Parse splits an answer into its content and the confidence report ending
+// it. The report is nil when the answer doesn't end with one.
+func Parse(answer string) (string, *Report) {
+	m := reportPattern.FindStringSubmatchIndex(answer)
+	if m == nil {
+		return answer, nil
+	}
+	return answer[:m[0]], &Report{
+		Level:     answer[m[2]:m[3]],
+		Rationale: strings.TrimSpace(answer[m[4]:m[5]]),
+	}
+}
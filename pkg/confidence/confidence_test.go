@@ -0,0 +1,32 @@
+package confidence
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	content, report := Parse("The limit is 100 requests a minute.\n\n<confidence level=\"low\">\nThe docs don't say whether it's per key.\n</confidence>\n")
+
+	assert.Equal(t, "The limit is 100 requests a minute.", content)
+	assert.Equal(t, &Report{Level: Low, Rationale: "The docs don't say whether it's per key."}, report)
+}
+
+func TestParse_NoReport(t *testing.T) {
+	t.Parallel()
+
+	for _, answer := range []string{
+		"The limit is 100 requests a minute.",
+		// Only a report ending the answer counts
+		"<confidence level=\"high\">Checked.</confidence> Then more text.",
+		"<confidence level=\"certain\">Checked.</confidence>",
+	} {
+		content, report := Parse(answer)
+
+		assert.Equal(t, answer, content)
+		assert.Nil(t, report)
+	}
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/docker/cagent/pkg/config/latest"
+)
+
+// ModelAllowlist pins the providers, regions and models agents are allowed to use.
+// It's meant to be distributed by organizations to enforce data-residency rules.
+// Empty lists allow everything.
+type ModelAllowlist struct {
+	// Providers are the allowed provider names (openai, anthropic, amazon-bedrock...)
+	Providers []string `yaml:"providers,omitempty"`
+	// Models are the allowed provider/model references. Globs are supported, eg. anthropic/claude-*
+	Models []string `yaml:"models,omitempty"`
+	// Regions are the allowed regions, for providers that support them (amazon-bedrock, google on Vertex AI)
+	Regions []string `yaml:"regions,omitempty"`
+}
+
+// LoadModelAllowlist reads a model allowlist from a YAML file.
+func LoadModelAllowlist(filename string) (*ModelAllowlist, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("reading model allowlist: %w", err)
+	}
+
+	var allowlist ModelAllowlist
+	if err := yaml.UnmarshalWithOptions(data, &allowlist, yaml.Strict()); err != nil {
+		return nil, fmt.Errorf("parsing model allowlist %s: %w", filename, err)
+	}
+
+	for _, pattern := range allowlist.Models {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid model pattern %q in %s: %w", pattern, filename, err)
+		}
+	}
+
+	return &allowlist, nil
+}
+
+// CheckModel returns an error if the model, or one of the models it routes to, is not allowed.
+// A nil allowlist allows every model.
+func (a *ModelAllowlist) CheckModel(model *latest.ModelConfig, models map[string]latest.ModelConfig) error {
+	if a == nil {
+		return nil
+	}
+
+	if err := a.check(model); err != nil {
+		return err
+	}
+
+	for _, rule := range model.Routing {
+		target, ok := models[rule.Model]
+		if !ok {
+			providerName, modelName, found := strings.Cut(rule.Model, "/")
+			if !found {
+				continue
+			}
+			target = latest.ModelConfig{Provider: providerName, Model: modelName}
+		}
+		if err := a.check(&target); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CheckConfig returns all the models of an agent configuration that are not allowed.
+func (a *ModelAllowlist) CheckConfig(cfg *latest.Config) error {
+	if a == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(cfg.Models))
+	for name := range cfg.Models {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		model := cfg.Models[name]
+		// Alloy models only reference other models
+		if model.Provider == "" {
+			continue
+		}
+		if err := a.CheckModel(&model, cfg.Models); err != nil {
+			errs = append(errs, fmt.Errorf("model '%s': %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (a *ModelAllowlist) check(model *latest.ModelConfig) error {
+	ref := model.Provider + "/" + model.Model
+
+	if len(a.Providers) > 0 && !slices.Contains(a.Providers, model.Provider) {
+		return fmt.Errorf("provider '%s' is not allowed (allowed: %s)", model.Provider, strings.Join(a.Providers, ", "))
+	}
+
+	if len(a.Models) > 0 && !slices.ContainsFunc(a.Models, func(pattern string) bool {
+		matched, _ := path.Match(pattern, ref)
+		return matched
+	}) {
+		return fmt.Errorf("model '%s' is not allowed", ref)
+	}
+
+	if len(a.Regions) > 0 {
+		region := modelRegion(model)
+		if region == "" && model.Provider == "amazon-bedrock" {
+			return fmt.Errorf("model '%s' must pin its region in provider_opts (allowed: %s)", ref, strings.Join(a.Regions, ", "))
+		}
+		if region != "" && !slices.Contains(a.Regions, region) {
+			return fmt.Errorf("region '%s' of model '%s' is not allowed (allowed: %s)", region, ref, strings.Join(a.Regions, ", "))
+		}
+	}
+
+	return nil
+}
+
+// modelRegion returns the region a model is pinned to, if any.
+func modelRegion(model *latest.ModelConfig) string {
+	for _, key := range []string{"region", "location"} {
+		if region, ok := model.ProviderOpts[key].(string); ok && region != "" {
+			return region
+		}
+	}
+	return ""
+}
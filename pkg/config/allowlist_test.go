@@ -0,0 +1,90 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/config/latest"
+)
+
+func TestLoadModelAllowlist(t *testing.T) {
+	t.Parallel()
+
+	filename := filepath.Join(t.TempDir(), "allowlist.yaml")
+	require.NoError(t, os.WriteFile(filename, []byte("providers: [anthropic, amazon-bedrock]\nmodels: [\"*/claude-*\"]\nregions: [eu-west-1]\n"), 0o644))
+
+	allowlist, err := LoadModelAllowlist(filename)
+	require.NoError(t, err)
+	assert.Equal(t, &ModelAllowlist{
+		Providers: []string{"anthropic", "amazon-bedrock"},
+		Models:    []string{"*/claude-*"},
+		Regions:   []string{"eu-west-1"},
+	}, allowlist)
+
+	require.NoError(t, os.WriteFile(filename, []byte("model: [openai/*]\n"), 0o644))
+	_, err = LoadModelAllowlist(filename)
+	require.Error(t, err, "unknown fields are rejected")
+}
+
+func TestModelAllowlist_CheckModel(t *testing.T) {
+	t.Parallel()
+
+	allowlist := &ModelAllowlist{
+		Providers: []string{"anthropic", "amazon-bedrock"},
+		Models:    []string{"anthropic/claude-*", "amazon-bedrock/*"},
+		Regions:   []string{"eu-west-1"},
+	}
+
+	tests := []struct {
+		name  string
+		model latest.ModelConfig
+		err   string
+	}{
+		{name: "allowed", model: latest.ModelConfig{Provider: "anthropic", Model: "claude-sonnet-4-5"}},
+		{name: "provider", model: latest.ModelConfig{Provider: "openai", Model: "gpt-4o"}, err: "provider 'openai' is not allowed"},
+		{name: "model", model: latest.ModelConfig{Provider: "anthropic", Model: "other"}, err: "model 'anthropic/other' is not allowed"},
+		{name: "region", model: latest.ModelConfig{Provider: "amazon-bedrock", Model: "claude", ProviderOpts: map[string]any{"region": "us-east-1"}}, err: "region 'us-east-1'"},
+		{name: "unpinned region", model: latest.ModelConfig{Provider: "amazon-bedrock", Model: "claude"}, err: "must pin its region"},
+		{name: "pinned region", model: latest.ModelConfig{Provider: "amazon-bedrock", Model: "claude", ProviderOpts: map[string]any{"region": "eu-west-1"}}},
+		{
+			name: "routing",
+			model: latest.ModelConfig{Provider: "anthropic", Model: "claude-haiku-4-5", Routing: []latest.RoutingRule{
+				{Model: "openai/gpt-4o"},
+			}},
+			err: "provider 'openai' is not allowed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := allowlist.CheckModel(&tt.model, nil)
+			if tt.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+
+	var none *ModelAllowlist
+	require.NoError(t, none.CheckModel(&latest.ModelConfig{Provider: "openai", Model: "gpt-4o"}, nil))
+}
+
+func TestModelAllowlist_CheckConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := &latest.Config{Models: map[string]latest.ModelConfig{
+		"fast":  {Provider: "openai", Model: "gpt-4o-mini"},
+		"smart": {Provider: "anthropic", Model: "claude-sonnet-4-5"},
+		"both":  {Model: "fast,smart"},
+	}}
+
+	err := (&ModelAllowlist{Providers: []string{"anthropic"}}).CheckConfig(cfg)
+	require.ErrorContains(t, err, "model 'fast': provider 'openai' is not allowed")
+	assert.NotContains(t, err.Error(), "smart")
+}
@@ -6,7 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net/url"
-	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/goccy/go-yaml"
@@ -129,6 +129,54 @@ func validateConfig(cfg *latest.Config) error {
 		if err := validateSkillsConfiguration(agent.Name, &agent); err != nil {
 			return err
 		}
+
+		if agent.Priority < 0 {
+			return fmt.Errorf("agent '%s': priority must not be negative", agent.Name)
+		}
+
+		if agent.MaxParallelToolCalls < 0 {
+			return fmt.Errorf("agent '%s': max_parallel_tool_calls must not be negative", agent.Name)
+		}
+
+		if agent.MaxRepeatedToolCalls < -1 {
+			return fmt.Errorf("agent '%s': max_repeated_tool_calls must be -1 (disabled) or more", agent.Name)
+		}
+
+		if agent.MaxTurnTokens < 0 {
+			return fmt.Errorf("agent '%s': max_turn_tokens must not be negative", agent.Name)
+		}
+
+		if agent.StreamTimeout < -1 {
+			return fmt.Errorf("agent '%s': stream_timeout must be -1 (disabled) or more", agent.Name)
+		}
+
+		if retry := agent.Retry; retry != nil {
+			if retry.MaxRetries < -1 {
+				return fmt.Errorf("agent '%s': retry max_retries must be -1 (disabled) or more", agent.Name)
+			}
+			if retry.InitialDelay < 0 || retry.MaxDelay < 0 {
+				return fmt.Errorf("agent '%s': retry delays must not be negative", agent.Name)
+			}
+		}
+
+		if err := validateGuardrails(agent.Name, agent.Guardrails); err != nil {
+			return err
+		}
+
+		// The knowledge tools of an agent would all be named search_knowledge
+		knowledgeToolsets := 0
+		for _, toolset := range agent.Toolsets {
+			if toolset.Type == "knowledge" {
+				knowledgeToolsets++
+			}
+		}
+		if knowledgeToolsets > 1 {
+			return fmt.Errorf("agent '%s' can only search one knowledge base: add all the documents to it", agent.Name)
+		}
+
+		if agent.Compaction != nil && (agent.Compaction.Threshold < 0 || agent.Compaction.Threshold > 1) {
+			return fmt.Errorf("agent '%s': compaction threshold must be between 0 and 1", agent.Name)
+		}
 	}
 
 	return nil
@@ -157,6 +205,21 @@ func validateProviders(cfg *latest.Config) error {
 			return fmt.Errorf("provider '%s': %w", name, err)
 		}
 
+		if provCfg.MaxConcurrentRequests < 0 {
+			return fmt.Errorf("provider '%s': max_concurrent_requests must not be negative", name)
+		}
+		if provCfg.RequestsPerMinute < 0 {
+			return fmt.Errorf("provider '%s': requests_per_minute must not be negative", name)
+		}
+		if provCfg.TokensPerMinute < 0 {
+			return fmt.Errorf("provider '%s': tokens_per_minute must not be negative", name)
+		}
+
+		// An entry may only limit the requests to a provider, built-in or not
+		if limits := provCfg.Limits(); provCfg == limits && limits != (latest.ProviderConfig{}) {
+			continue
+		}
+
 		// Validate api_type
 		if !providerAPITypes[provCfg.APIType] {
 			return fmt.Errorf("provider '%s': invalid api_type '%s' (must be one of: openai_chatcompletions, openai_responses)", name, provCfg.APIType)
@@ -207,14 +270,7 @@ func validateSkillsConfiguration(agentName string, agent *latest.AgentConfig) er
 			hasFilesystemToolset = true
 
 			// Check if read_file tool is enabled
-			// If no specific tools are listed, all tools are enabled
-			if len(toolset.Tools) == 0 {
-				hasReadFileTool = true
-				break
-			}
-
-			// Check if read_file is in the tools list
-			if slices.Contains(toolset.Tools, "read_file") {
+			if toolset.Tools.Enables("read_file") {
 				hasReadFileTool = true
 				break
 			}
@@ -231,3 +287,43 @@ func validateSkillsConfiguration(agentName string, agent *latest.AgentConfig) er
 
 	return nil
 }
+
+// validateGuardrails ensures that the guardrails of an agent have what their
+// type needs and known actions and targets.
+func validateGuardrails(agentName string, guardrails []latest.GuardrailConfig) error {
+	for i, g := range guardrails {
+		name := cmp.Or(g.Name, g.Type, strconv.Itoa(i+1))
+
+		switch g.Type {
+		case "regex":
+			if len(g.Patterns) == 0 {
+				return fmt.Errorf("agent '%s': guardrail '%s' has no patterns", agentName, name)
+			}
+		case "keywords":
+			if len(g.Keywords) == 0 {
+				return fmt.Errorf("agent '%s': guardrail '%s' has no keywords", agentName, name)
+			}
+		case "pii":
+		case "moderation":
+			if g.Action == "redact" {
+				return fmt.Errorf("agent '%s': guardrail '%s' can't redact: moderation models don't tell where the violations are", agentName, name)
+			}
+		default:
+			return fmt.Errorf("agent '%s': guardrail '%s' has an unknown type '%s', expected regex, keywords, pii or moderation", agentName, name, g.Type)
+		}
+
+		switch g.Action {
+		case "", "block", "warn", "redact":
+		default:
+			return fmt.Errorf("agent '%s': guardrail '%s' has an unknown action '%s', expected block, warn or redact", agentName, name, g.Action)
+		}
+
+		for _, target := range g.ApplyTo {
+			if target != "output" && target != "tool_arguments" {
+				return fmt.Errorf("agent '%s': guardrail '%s' applies to an unknown target '%s', expected output or tool_arguments", agentName, name, target)
+			}
+		}
+	}
+
+	return nil
+}
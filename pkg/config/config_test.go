@@ -3,6 +3,7 @@ package config
 import (
 	"context"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -257,6 +258,10 @@ func TestCheckRequiredEnvVars(t *testing.T) {
 			yaml:            "openai_and_unused_mistral_model.yaml",
 			expectedMissing: []string{"OPENAI_API_KEY"},
 		},
+		{
+			yaml:            "web_search.yaml",
+			expectedMissing: []string{"BRAVE_API_KEY"},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.yaml, func(t *testing.T) {
@@ -495,6 +500,44 @@ func TestProviders_Validation(t *testing.T) {
 			},
 			wantErr: "invalid api_type 'invalid_api_type'",
 		},
+		{
+			name: "limit of a built-in provider",
+			providers: map[string]latest.ProviderConfig{
+				"anthropic": {
+					MaxConcurrentRequests: 2,
+				},
+			},
+			wantErr: "",
+		},
+		{
+			name: "negative limit",
+			providers: map[string]latest.ProviderConfig{
+				"my_provider": {
+					BaseURL:               "https://api.example.com/v1",
+					MaxConcurrentRequests: -1,
+				},
+			},
+			wantErr: "max_concurrent_requests must not be negative",
+		},
+		{
+			name: "rate limit of a built-in provider",
+			providers: map[string]latest.ProviderConfig{
+				"openai": {
+					RequestsPerMinute: 60,
+					TokensPerMinute:   100000,
+				},
+			},
+			wantErr: "",
+		},
+		{
+			name: "negative rate limit",
+			providers: map[string]latest.ProviderConfig{
+				"openai": {
+					TokensPerMinute: -1,
+				},
+			},
+			wantErr: "tokens_per_minute must not be negative",
+		},
 		{
 			name: "provider name with slash",
 			providers: map[string]latest.ProviderConfig{
@@ -529,3 +572,86 @@ func TestProviders_Validation(t *testing.T) {
 		})
 	}
 }
+
+func TestGuardrails_Validation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		guardrail latest.GuardrailConfig
+		wantErr   string
+	}{
+		{
+			name:      "valid regex",
+			guardrail: latest.GuardrailConfig{Type: "regex", Patterns: []string{`sk-\w+`}, Action: "redact", ApplyTo: []string{"tool_arguments"}},
+		},
+		{
+			name:      "valid moderation",
+			guardrail: latest.GuardrailConfig{Type: "moderation", Model: "openai/gpt-4o-mini", Action: "warn"},
+		},
+		{
+			name:      "unknown type",
+			guardrail: latest.GuardrailConfig{Type: "profanity"},
+			wantErr:   "unknown type 'profanity'",
+		},
+		{
+			name:      "keywords without keywords",
+			guardrail: latest.GuardrailConfig{Name: "internal", Type: "keywords"},
+			wantErr:   "guardrail 'internal' has no keywords",
+		},
+		{
+			name:      "redacting moderation",
+			guardrail: latest.GuardrailConfig{Type: "moderation", Action: "redact"},
+			wantErr:   "guardrail 'moderation' can't redact",
+		},
+		{
+			name:      "unknown action",
+			guardrail: latest.GuardrailConfig{Type: "pii", Action: "drop"},
+			wantErr:   "unknown action 'drop'",
+		},
+		{
+			name:      "unknown target",
+			guardrail: latest.GuardrailConfig{Type: "pii", ApplyTo: []string{"input"}},
+			wantErr:   "unknown target 'input'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &latest.Config{
+				Agents: []latest.AgentConfig{
+					{Name: "root", Model: "openai/gpt-4o", Guardrails: []latest.GuardrailConfig{tt.guardrail}},
+				},
+			}
+
+			err := validateConfig(cfg)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func FuzzLoad(f *testing.F) {
+	files, err := filepath.Glob("testdata/*.yaml")
+	require.NoError(f, err)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		require.NoError(f, err)
+		f.Add(data)
+	}
+	f.Add([]byte("version: \"0\"\nagents:\n  root:\n    model: openai/gpt-4o\n"))
+	f.Add([]byte("version: \"1\"\nagents:\n  root:\n    model: a\nmodels:\n  a:\n    provider: alloy\n"))
+	f.Add([]byte("agents: [root]\n"))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Invalid configs must be rejected with an error, not a panic
+		_, _ = Load(t.Context(), NewBytesSource("fuzz.yaml", data))
+	})
+}
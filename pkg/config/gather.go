@@ -13,6 +13,7 @@ import (
 	"github.com/docker/cagent/pkg/environment"
 	"github.com/docker/cagent/pkg/gateway"
 	"github.com/docker/cagent/pkg/model/provider"
+	"github.com/docker/cagent/pkg/websearch"
 )
 
 // gatherMissingEnvVars finds out which environment variables are required by the models and tools.
@@ -90,12 +91,11 @@ func gatherEnvVarsForModel(cfg *latest.Config, modelName string, requiredEnv map
 func addEnvVarsForModelConfig(model *latest.ModelConfig, customProviders map[string]latest.ProviderConfig, requiredEnv map[string]bool) {
 	if model.TokenKey != "" {
 		requiredEnv[model.TokenKey] = true
-	} else if customProviders != nil {
-		// Check custom providers from config
-		if provCfg, exists := customProviders[model.Provider]; exists {
-			if provCfg.TokenKey != "" {
-				requiredEnv[provCfg.TokenKey] = true
-			}
+	} else if provCfg, exists := customProviders[model.Provider]; exists && provCfg.BaseURL != "" {
+		// Check custom providers from config. Entries without a base URL only
+		// limit the requests to a built-in provider.
+		if provCfg.TokenKey != "" {
+			requiredEnv[provCfg.TokenKey] = true
 		}
 	} else if alias, exists := provider.Aliases[model.Provider]; exists {
 		// Check built-in aliases
@@ -128,6 +128,13 @@ func GatherEnvVarsForTools(ctx context.Context, cfg *latest.Config) ([]string, e
 
 		for j := range agent.Toolsets {
 			toolSet := agent.Toolsets[j]
+			if toolSet.Type == "web_search" {
+				if env := websearch.APIKeyEnv(toolSet.Engine); env != "" {
+					requiredEnv[env] = true
+				}
+				continue
+			}
+
 			ref := toolSet.Ref
 			if toolSet.Type != "mcp" || ref == "" {
 				continue
@@ -0,0 +1,21 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/docker/cagent/pkg/config/latest"
+)
+
+// Hash returns a hash of a loaded configuration, to tell whether two runs used
+// the same one. It's computed on the configuration once migrated to the latest
+// version, so that formatting and comments don't change it.
+func Hash(cfg *latest.Config) (string, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
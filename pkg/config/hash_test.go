@@ -0,0 +1,41 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHash(t *testing.T) {
+	t.Parallel()
+
+	hash := func(yaml string) string {
+		cfg, err := Load(t.Context(), NewBytesSource("agent.yaml", []byte(yaml)))
+		require.NoError(t, err)
+		h, err := Hash(cfg)
+		require.NoError(t, err)
+		return h
+	}
+
+	original := hash(`agents:
+  root:
+    model: openai/gpt-4o
+    instruction: Be helpful
+`)
+	assert.Regexp(t, `^sha256:[0-9a-f]{64}$`, original)
+
+	// Formatting and comments don't change the configuration
+	assert.Equal(t, original, hash(`# My agent
+agents:
+  root:
+    instruction:   Be helpful
+    model: openai/gpt-4o
+`))
+
+	assert.NotEqual(t, original, hash(`agents:
+  root:
+    model: openai/gpt-4o
+    instruction: Be concise
+`))
+}
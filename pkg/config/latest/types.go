@@ -3,6 +3,7 @@ package latest
 import (
 	"encoding/json"
 	"fmt"
+	"path"
 
 	"github.com/goccy/go-yaml"
 
@@ -106,6 +107,24 @@ type ProviderConfig struct {
 	BaseURL string `json:"base_url"`
 	// TokenKey is the environment variable name containing the API token
 	TokenKey string `json:"token_key,omitempty"`
+	// MaxConcurrentRequests caps the requests sent to the provider at the same
+	// time, by all the agents. Zero means no limit. An entry naming a built-in
+	// provider, like anthropic, may only set this limit.
+	MaxConcurrentRequests int `json:"max_concurrent_requests,omitempty"`
+	// RequestsPerMinute and TokensPerMinute cap the requests sent to the
+	// provider, and the tokens they use, over any minute, by all the agents.
+	// Zero means no limit.
+	RequestsPerMinute int `json:"requests_per_minute,omitempty"`
+	TokensPerMinute   int `json:"tokens_per_minute,omitempty"`
+}
+
+// Limits returns the limits of the provider, without its other settings.
+func (p *ProviderConfig) Limits() ProviderConfig {
+	return ProviderConfig{
+		MaxConcurrentRequests: p.MaxConcurrentRequests,
+		RequestsPerMinute:     p.RequestsPerMinute,
+		TokensPerMinute:       p.TokensPerMinute,
+	}
 }
 
 // AgentConfig represents a single agent configuration
@@ -129,6 +148,143 @@ type AgentConfig struct {
 	StructuredOutput   *StructuredOutput `json:"structured_output,omitempty"`
 	Skills             *bool             `json:"skills,omitempty"`
 	Hooks              *HooksConfig      `json:"hooks,omitempty"`
+	// ConfirmUntrusted requires user confirmation for every tool call made after
+	// untrusted content entered the conversation, even if it would otherwise be auto-approved.
+	ConfirmUntrusted bool `json:"confirm_untrusted,omitempty"`
+	// SummarizeToolResults summarizes large tool results before they are added to the context.
+	SummarizeToolResults *SummarizeToolResultsConfig `json:"summarize_tool_results,omitempty"`
+	// Governor keeps long-running sessions usable by compacting them at turn or token milestones.
+	Governor *GovernorConfig `json:"governor,omitempty"`
+	// Compaction configures the automatic compaction of sessions nearing the
+	// context limit of the agent's model.
+	Compaction *CompactionConfig `json:"compaction,omitempty"`
+	// Confidence has the agent report its confidence in its final answers.
+	Confidence *ConfidenceConfig `json:"confidence,omitempty"`
+	// Priority is the share of the requests to a provider the agent gets when
+	// they are limited and other agents are waiting too. Defaults to 1.
+	Priority int `json:"priority,omitempty"`
+	// MaxParallelToolCalls is the number of tool calls of a turn run at the
+	// same time. Defaults to 1: the calls are run one after the other.
+	MaxParallelToolCalls int `json:"max_parallel_tool_calls,omitempty"`
+	// MaxRepeatedToolCalls is the number of times in a row the agent may call
+	// the same tool with the same arguments before it's stopped as looping.
+	// Defaults to 5, -1 disables the loop detection.
+	MaxRepeatedToolCalls int `json:"max_repeated_tool_calls,omitempty"`
+	// MaxTurnTokens is the number of tokens the agent may add to the context
+	// in a turn, with its answers and the results of its tool calls, before
+	// it's asked to conclude or to ask the user for guidance. No limit by
+	// default.
+	MaxTurnTokens int `json:"max_turn_tokens,omitempty"`
+	// StreamTimeout is the number of seconds the stream of a model may go
+	// without sending anything before it's considered stalled: the request is
+	// then retried, or the turn fails. Defaults to 300, -1 disables it.
+	StreamTimeout int `json:"stream_timeout,omitempty"`
+	// Retry retries the requests to the agent's model that fail with a rate
+	// limit, a server error or a dropped stream, and fails over to a fallback
+	// model.
+	Retry *RetryConfig `json:"retry,omitempty"`
+	// Guardrails check the answers of the agent and the arguments of its tool
+	// calls before they're used, and block, warn about or redact what they
+	// find.
+	Guardrails []GuardrailConfig `json:"guardrails,omitempty"`
+}
+
+// GovernorConfig configures when a session is checkpointed and compacted.
+// A milestone is reached when either limit is exceeded at the end of a turn.
+type GovernorConfig struct {
+	// MaxTurns is the number of user turns since the last compaction after which
+	// the session is compacted. Zero disables the turn milestone.
+	MaxTurns int `json:"max_turns,omitempty"`
+	// MaxTokens is the size of the context, in tokens, above which the session
+	// is compacted. Zero disables the token milestone.
+	MaxTokens int64 `json:"max_tokens,omitempty"`
+	// Rotate continues the conversation in a fresh session, linked to the
+	// checkpointed one and seeded with its summary.
+	Rotate bool `json:"rotate,omitempty"`
+}
+
+// CompactionConfig configures when and with which model a session is compacted
+// as its context nears the limit of the model.
+type CompactionConfig struct {
+	// Threshold is the fraction of the context limit above which the session is
+	// compacted, between 0 and 1. Defaults to 0.9.
+	Threshold float64 `json:"threshold,omitempty"`
+	// Model is the model that writes the summary, either a reference to the models
+	// section or an inline "provider/model". Defaults to the agent's model.
+	Model string `json:"model,omitempty"`
+}
+
+// ConfidenceConfig configures the confidence reports of an agent and what
+// happens when it reports a low confidence in an answer.
+type ConfidenceConfig struct {
+	// VerifyLow asks the agent to verify an answer it has a low confidence in,
+	// once per run.
+	VerifyLow bool `json:"verify_low,omitempty"`
+	// EscalateTo is the model that verifies the answers the agent has a low
+	// confidence in, either a reference to the models section or an inline
+	// "provider/model". Implies VerifyLow.
+	EscalateTo string `json:"escalate_to,omitempty"`
+}
+
+// RetryConfig configures how the requests to an agent's model are retried
+// when the provider is rate limited or unavailable, or the stream drops.
+type RetryConfig struct {
+	// MaxRetries is the number of times a failed request is retried. Defaults
+	// to 3, -1 disables the retries.
+	MaxRetries int `json:"max_retries,omitempty"`
+	// InitialDelay is the delay, in seconds, before the first retry. It doubles
+	// with each retry. Defaults to 1.
+	InitialDelay int `json:"initial_delay,omitempty"`
+	// MaxDelay is the longest delay, in seconds, between two retries. Defaults
+	// to 30.
+	MaxDelay int `json:"max_delay,omitempty"`
+	// Fallback is the model the agent fails over to, for the rest of the turn,
+	// once the retries are exhausted: a reference to the models section or an
+	// inline "provider/model".
+	Fallback string `json:"fallback,omitempty"`
+}
+
+// GuardrailConfig configures a guardrail of an agent.
+type GuardrailConfig struct {
+	// Name identifies the guardrail in warnings and errors. Defaults to its
+	// type.
+	Name string `json:"name,omitempty"`
+	// Type is how the guardrail finds what it forbids: regex, keywords, pii or
+	// moderation.
+	Type string `json:"type"`
+	// Patterns are the regular expressions of a regex guardrail.
+	Patterns []string `json:"patterns,omitempty"`
+	// Keywords are the words of a keywords guardrail, matched as whole words
+	// ignoring the case.
+	Keywords []string `json:"keywords,omitempty"`
+	// PII are the kinds of personally identifiable information of a pii
+	// guardrail: email, credit_card, ssn, phone and ip_address. Defaults to
+	// all of them.
+	PII []string `json:"pii,omitempty"`
+	// Model is the model of a moderation guardrail, either a reference to the
+	// models section or an inline "provider/model". Defaults to the agent's
+	// model.
+	Model string `json:"model,omitempty"`
+	// Policy tells a moderation model what to flag. Defaults to harmful
+	// content and leaked secrets.
+	Policy string `json:"policy,omitempty"`
+	// Action is what the guardrail does with what it finds: block, warn or
+	// redact. Defaults to block. Moderation guardrails can't redact.
+	Action string `json:"action,omitempty"`
+	// ApplyTo is what the guardrail checks: output, tool_arguments or both.
+	// Defaults to both.
+	ApplyTo []string `json:"apply_to,omitempty"`
+}
+
+// SummarizeToolResultsConfig configures the summarization of large tool results.
+// The raw output stays available to the agent through the read_more tool.
+type SummarizeToolResultsConfig struct {
+	// Threshold is the estimated number of tokens above which a tool result is summarized.
+	// Defaults to 4000.
+	Threshold int `json:"threshold,omitempty"`
+	// Model is the model used to summarize, either a reference to the models section or an
+	// inline "provider/model". Defaults to the agent's model.
+	Model string `json:"model,omitempty"`
 }
 
 // ModelConfig represents the configuration for a model
@@ -225,35 +381,67 @@ type PostEditConfig struct {
 
 // Toolset represents a tool configuration
 type Toolset struct {
-	Type        string   `json:"type,omitempty"`
-	Tools       []string `json:"tools,omitempty"`
-	Instruction string   `json:"instruction,omitempty"`
-	Toon        string   `json:"toon,omitempty"`
+	Type        string      `json:"type,omitempty"`
+	Tools       ToolsConfig `json:"tools,omitzero"`
+	Instruction string      `json:"instruction,omitempty"`
+	Toon        string      `json:"toon,omitempty"`
+
+	// Untrusted marks the toolset's outputs as coming from sources that are not
+	// controlled by the user. Defaults to true for the `fetch` and `web_search`
+	// tools.
+	Untrusted *bool `json:"untrusted,omitempty"`
 
 	Defer DeferConfig `json:"defer,omitempty" yaml:"defer,omitempty"`
 
+	// Limits bounds how long the tools of the toolset may run and how large
+	// their results may be
+	Limits ToolLimits `json:"limits,omitzero"`
+
 	// For the `mcp` tool
 	Command string   `json:"command,omitempty"`
 	Args    []string `json:"args,omitempty"`
 	Ref     string   `json:"ref,omitempty"`
 	Remote  Remote   `json:"remote,omitempty"`
 	Config  any      `json:"config,omitempty"`
+	// For the `mcp` tool - the resources of the server, such as
+	// `repo://readme`, given to the agent as context
+	Resources []string `json:"resources,omitempty"`
 
-	// For the `a2a` tool
+	// For the `a2a` tool, or the knowledge base of the `knowledge` tool
 	Name string `json:"name,omitempty"`
-	URL  string `json:"url,omitempty"`
+	// For the `a2a` tool, or the SearxNG instance of the `web_search` tool
+	URL string `json:"url,omitempty"`
+
+	// For the `web_search` tool - the search engine: `brave`, `searxng` or
+	// `tavily`
+	Engine string `json:"engine,omitempty"`
 
 	// For `shell`, `script`, `mcp` or `lsp` tools
 	Env map[string]string `json:"env,omitempty"`
 
-	// For the `shell` tool - sandbox mode
+	// For the `shell` tool, or the command of the `mcp` tool - sandbox mode
 	Sandbox *SandboxConfig `json:"sandbox,omitempty"`
+	// For the `shell` tool - the only commands it can run
+	AllowedCommands []string `json:"allowed_commands,omitempty"`
+	// For the `shell` tool - the variables of the environment of cagent
+	// passed to the commands, all of them when empty. Patterns such as
+	// `GO*` are supported.
+	AllowedEnv []string `json:"allowed_env,omitempty"`
+	// For the `shell` tool - only start commands in the working directory,
+	// or below
+	ConfineWorkingDir bool `json:"confine_working_dir,omitempty"`
 
 	// For the `todo` tool
 	Shared bool `json:"shared,omitempty"`
 
 	// For the `memory` tool
 	Path string `json:"path,omitempty"`
+	// Scope keeps the memories of the agent, or of the project, apart from
+	// the others: `agent` or `project`. Memories are shared by default.
+	Scope string `json:"scope,omitempty"`
+	// Recall is the number of memories relevant to the message of the user
+	// given to the agent at the start of each turn
+	Recall int `json:"recall,omitempty"`
 
 	// For the `script` tool
 	Shell map[string]ScriptShellToolConfig `json:"shell,omitempty"`
@@ -268,6 +456,17 @@ type Toolset struct {
 
 	// For the `fetch` tool
 	Timeout int `json:"timeout,omitempty"`
+	// For the `fetch` tool - the number of bytes of a page read, 1MB by
+	// default
+	MaxSize int `json:"max_size,omitempty"`
+}
+
+// IsUntrusted returns whether the toolset's outputs should be treated as untrusted content.
+func (t *Toolset) IsUntrusted() bool {
+	if t.Untrusted != nil {
+		return *t.Untrusted
+	}
+	return t.Type == "fetch" || t.Type == "web_search"
 }
 
 func (t *Toolset) UnmarshalYAML(unmarshal func(any) error) error {
@@ -298,7 +497,48 @@ type SandboxConfig struct {
 	// Each path can optionally have a ":ro" suffix for read-only access.
 	// Default is read-write (:rw) if no suffix is specified.
 	// Example: [".", "/tmp", "/config:ro"]
+	// MCP servers can be run without any path.
 	Paths []string `json:"paths"`
+
+	// Network gives the container access to the network. Defaults to true.
+	Network *bool `json:"network,omitempty"`
+}
+
+// HasNetwork returns whether the sandbox container can access the network.
+func (s *SandboxConfig) HasNetwork() bool {
+	return s.Network == nil || *s.Network
+}
+
+// ToolLimits bounds the calls of the tools of a toolset, so that a runaway
+// call, such as `find /`, can't freeze the run.
+type ToolLimits struct {
+	// Timeout is the number of seconds a call may run before it's stopped
+	Timeout int `json:"timeout,omitempty"`
+	// MaxResultSize is the number of bytes of a result given to the model,
+	// the rest is cut
+	MaxResultSize int `json:"max_result_size,omitempty"`
+	// Tools overrides the limits of some tools of the toolset, by name
+	Tools map[string]ToolLimits `json:"tools,omitempty"`
+}
+
+// For returns the limits of a tool of the toolset: the limits of the
+// toolset, overridden by the ones of the tool.
+func (l *ToolLimits) For(name string) ToolLimits {
+	limits := ToolLimits{Timeout: l.Timeout, MaxResultSize: l.MaxResultSize}
+	if override, ok := l.Tools[name]; ok {
+		if override.Timeout != 0 {
+			limits.Timeout = override.Timeout
+		}
+		if override.MaxResultSize != 0 {
+			limits.MaxResultSize = override.MaxResultSize
+		}
+	}
+	return limits
+}
+
+// IsEmpty returns whether no tool of the toolset is limited.
+func (l *ToolLimits) IsEmpty() bool {
+	return l.Timeout == 0 && l.MaxResultSize == 0 && len(l.Tools) == 0
 }
 
 // DeferConfig represents the deferred loading configuration for a toolset.
@@ -344,6 +584,103 @@ func (d DeferConfig) MarshalYAML() ([]byte, error) {
 	return yaml.Marshal(d.Tools)
 }
 
+// ToolsConfig selects the tools of a toolset given to the agent, and renames
+// them. It's either the list of the tools to include or an object:
+//
+//	tools:
+//	  include: [git_*]
+//	  exclude: [git_push]
+//	  aliases:
+//	    search: github_search
+//
+// Tools are selected by their original names, with patterns such as `git_*`.
+// Aliases avoid collisions between the tools of two servers: the agent only
+// knows the tools by their new names.
+type ToolsConfig struct {
+	// Include lists the tools to keep, all of them when empty
+	Include []string `json:"include,omitempty"`
+	// Exclude lists the tools to remove
+	Exclude []string `json:"exclude,omitempty"`
+	// Aliases maps the names of tools to their new names
+	Aliases map[string]string `json:"aliases,omitempty"`
+}
+
+// isList reports whether the config is a plain list of tools to include.
+func (t ToolsConfig) isList() bool {
+	return len(t.Exclude) == 0 && len(t.Aliases) == 0
+}
+
+// Enables reports whether the tool of the given original name is given to
+// the agent.
+func (t ToolsConfig) Enables(name string) bool {
+	if len(t.Include) > 0 && !MatchesToolPattern(t.Include, name) {
+		return false
+	}
+	return !MatchesToolPattern(t.Exclude, name)
+}
+
+// MatchesToolPattern reports whether the name of a tool matches one of the
+// patterns, such as `git_*`.
+func MatchesToolPattern(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *ToolsConfig) UnmarshalYAML(unmarshal func(any) error) error {
+	var tools []string
+	if err := unmarshal(&tools); err == nil {
+		*t = ToolsConfig{Include: tools}
+		return nil
+	}
+
+	type alias ToolsConfig
+	var tmp alias
+	if err := unmarshal(&tmp); err != nil {
+		return err
+	}
+	*t = ToolsConfig(tmp)
+	return nil
+}
+
+// MarshalYAML keeps the list format when only tools to include are set
+func (t ToolsConfig) MarshalYAML() ([]byte, error) {
+	if t.isList() {
+		return yaml.Marshal(t.Include)
+	}
+	type alias ToolsConfig
+	return yaml.Marshal(alias(t))
+}
+
+// MarshalJSON keeps the list format when only tools to include are set
+func (t ToolsConfig) MarshalJSON() ([]byte, error) {
+	if t.isList() {
+		return json.Marshal(t.Include)
+	}
+	type alias ToolsConfig
+	return json.Marshal(alias(t))
+}
+
+// UnmarshalJSON accepts the list and the object formats
+func (t *ToolsConfig) UnmarshalJSON(data []byte) error {
+	var tools []string
+	if err := json.Unmarshal(data, &tools); err == nil {
+		*t = ToolsConfig{Include: tools}
+		return nil
+	}
+
+	type alias ToolsConfig
+	var tmp alias
+	if err := json.Unmarshal(data, &tmp); err != nil {
+		return err
+	}
+	*t = ToolsConfig(tmp)
+	return nil
+}
+
 // ThinkingBudget represents reasoning budget configuration.
 // It accepts either a string effort level or an integer token budget:
 // - String: "minimal", "low", "medium", "high" (for OpenAI)
@@ -688,6 +1025,11 @@ type RAGDatabaseConfig struct {
 	value any // nil (unset) or string path
 }
 
+// RAGDatabasePath returns the configuration of a SQLite database file.
+func RAGDatabasePath(path string) RAGDatabaseConfig {
+	return RAGDatabaseConfig{value: path}
+}
+
 // UnmarshalYAML implements custom unmarshaling for DatabaseConfig
 func (d *RAGDatabaseConfig) UnmarshalYAML(unmarshal func(any) error) error {
 	var str string
@@ -897,6 +1239,12 @@ type HooksConfig struct {
 	// PostToolUse hooks run after tool execution
 	PostToolUse []HookMatcherConfig `json:"post_tool_use,omitempty" yaml:"post_tool_use,omitempty"`
 
+	// PreModelCall hooks run before the agent's model is called
+	PreModelCall []HookDefinition `json:"pre_model_call,omitempty" yaml:"pre_model_call,omitempty"`
+
+	// PostModelCall hooks run after the model answered, before the answer is used
+	PostModelCall []HookDefinition `json:"post_model_call,omitempty" yaml:"post_model_call,omitempty"`
+
 	// SessionStart hooks run when a session begins
 	SessionStart []HookDefinition `json:"session_start,omitempty" yaml:"session_start,omitempty"`
 
@@ -911,6 +1259,8 @@ func (h *HooksConfig) IsEmpty() bool {
 	}
 	return len(h.PreToolUse) == 0 &&
 		len(h.PostToolUse) == 0 &&
+		len(h.PreModelCall) == 0 &&
+		len(h.PostModelCall) == 0 &&
 		len(h.SessionStart) == 0 &&
 		len(h.SessionEnd) == 0
 }
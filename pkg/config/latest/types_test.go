@@ -1,6 +1,7 @@
 package latest
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/goccy/go-yaml"
@@ -217,3 +218,59 @@ func toFloat64(v any) float64 {
 		return 0
 	}
 }
+
+func TestToolsConfig_MarshalUnmarshal_List(t *testing.T) {
+	t.Parallel()
+
+	input := []byte("tools:\n- read_file\n- write_file\n")
+	var config struct {
+		Tools ToolsConfig `yaml:"tools"`
+	}
+
+	err := yaml.Unmarshal(input, &config)
+	require.NoError(t, err)
+	require.Equal(t, ToolsConfig{Include: []string{"read_file", "write_file"}}, config.Tools)
+
+	output, err := yaml.Marshal(config)
+	require.NoError(t, err)
+	require.Equal(t, string(input), string(output))
+}
+
+func TestToolsConfig_MarshalUnmarshal_Object(t *testing.T) {
+	t.Parallel()
+
+	var toolset Toolset
+	err := yaml.Unmarshal([]byte(`
+type: mcp
+command: github-mcp
+tools:
+  include: [git_*, search]
+  exclude: [git_push]
+  aliases:
+    search: github_search
+`), &toolset)
+	require.NoError(t, err)
+	require.Equal(t, ToolsConfig{
+		Include: []string{"git_*", "search"},
+		Exclude: []string{"git_push"},
+		Aliases: map[string]string{"search": "github_search"},
+	}, toolset.Tools)
+
+	// Through JSON, as when upgrading the config
+	buf, err := json.Marshal(toolset)
+	require.NoError(t, err)
+	var clone Toolset
+	require.NoError(t, json.Unmarshal(buf, &clone))
+	require.Equal(t, toolset.Tools, clone.Tools)
+}
+
+func TestToolsConfig_Enables(t *testing.T) {
+	t.Parallel()
+
+	tools := ToolsConfig{Include: []string{"git_*"}, Exclude: []string{"git_push"}}
+	require.True(t, tools.Enables("git_status"))
+	require.False(t, tools.Enables("git_push"))
+	require.False(t, tools.Enables("search"))
+
+	require.True(t, ToolsConfig{}.Enables("search"))
+}
@@ -1,6 +1,8 @@
 package latest
 
 import (
+	"fmt"
+
 	"github.com/goccy/go-yaml"
 
 	"github.com/docker/cagent/pkg/config/types"
@@ -31,7 +33,10 @@ func UpgradeIfNeeded(c any, raw []byte) (any, error) {
 	}
 
 	for _, agent := range original.Agents {
-		name := agent.Key.(string)
+		name, ok := agent.Key.(string)
+		if !ok {
+			return nil, fmt.Errorf("agent name must be a string")
+		}
 
 		var agentConfig AgentConfig
 		types.CloneThroughJSON(previousAgents[name], &agentConfig)
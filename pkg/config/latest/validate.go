@@ -2,6 +2,9 @@ package latest
 
 import (
 	"errors"
+	"fmt"
+	"path"
+	"slices"
 	"strings"
 )
 
@@ -41,6 +44,12 @@ func (t *Toolset) validate() error {
 	if t.Path != "" && t.Type != "memory" {
 		return errors.New("path can only be used with type 'memory'")
 	}
+	if t.Scope != "" && t.Type != "memory" {
+		return errors.New("scope can only be used with type 'memory'")
+	}
+	if t.Recall != 0 && t.Type != "memory" {
+		return errors.New("recall can only be used with type 'memory'")
+	}
 	if len(t.PostEdit) > 0 && t.Type != "filesystem" {
 		return errors.New("post_edit can only be used with type 'filesystem'")
 	}
@@ -50,8 +59,17 @@ func (t *Toolset) validate() error {
 	if len(t.Env) > 0 && (t.Type != "shell" && t.Type != "script" && t.Type != "mcp" && t.Type != "lsp") {
 		return errors.New("env can only be used with type 'shell', 'script', 'mcp' or 'lsp'")
 	}
-	if t.Sandbox != nil && t.Type != "shell" {
-		return errors.New("sandbox can only be used with type 'shell'")
+	if t.Sandbox != nil && t.Type != "shell" && t.Type != "mcp" {
+		return errors.New("sandbox can only be used with type 'shell' or 'mcp'")
+	}
+	if len(t.AllowedCommands) > 0 && t.Type != "shell" {
+		return errors.New("allowed_commands can only be used with type 'shell'")
+	}
+	if len(t.AllowedEnv) > 0 && t.Type != "shell" {
+		return errors.New("allowed_env can only be used with type 'shell'")
+	}
+	if t.ConfineWorkingDir && t.Type != "shell" {
+		return errors.New("confine_working_dir can only be used with type 'shell'")
 	}
 	if t.Shared && t.Type != "todo" {
 		return errors.New("shared can only be used with type 'todo'")
@@ -74,11 +92,27 @@ func (t *Toolset) validate() error {
 	if t.Config != nil && t.Type != "mcp" {
 		return errors.New("config can only be used with type 'mcp'")
 	}
-	if t.URL != "" && t.Type != "a2a" {
-		return errors.New("url can only be used with type 'a2a'")
+	if len(t.Resources) > 0 && t.Type != "mcp" {
+		return errors.New("resources can only be used with type 'mcp'")
+	}
+	if t.URL != "" && t.Type != "a2a" && t.Type != "web_search" {
+		return errors.New("url can only be used with type 'a2a' or 'web_search'")
 	}
-	if t.Name != "" && (t.Type != "mcp" && t.Type != "a2a") {
-		return errors.New("name can only be used with type 'mcp' or 'a2a'")
+	if t.Engine != "" && t.Type != "web_search" {
+		return errors.New("engine can only be used with type 'web_search'")
+	}
+	if t.MaxSize != 0 && t.Type != "fetch" {
+		return errors.New("max_size can only be used with type 'fetch'")
+	}
+	if t.Name != "" && (t.Type != "mcp" && t.Type != "a2a" && t.Type != "knowledge") {
+		return errors.New("name can only be used with type 'mcp', 'a2a' or 'knowledge'")
+	}
+
+	if err := t.Tools.validate(); err != nil {
+		return err
+	}
+	if err := t.Limits.validate(); err != nil {
+		return err
 	}
 
 	switch t.Type {
@@ -86,9 +120,33 @@ func (t *Toolset) validate() error {
 		if t.Sandbox != nil && len(t.Sandbox.Paths) == 0 {
 			return errors.New("sandbox requires at least one path to be set")
 		}
+		for _, pattern := range t.AllowedEnv {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid allowed_env pattern %q: %w", pattern, err)
+			}
+		}
+	case "web_search":
+		switch t.Engine {
+		case "":
+			return errors.New("web_search toolset requires an engine: brave, searxng or tavily")
+		case "brave", "tavily":
+		case "searxng":
+			if t.URL == "" {
+				return errors.New("the searxng engine requires the url of an instance")
+			}
+		default:
+			return fmt.Errorf("unknown search engine %q, expected brave, searxng or tavily", t.Engine)
+		}
+	case "fetch":
+		if t.MaxSize < 0 {
+			return errors.New("max_size must be positive")
+		}
 	case "memory":
-		if t.Path == "" {
-			return errors.New("memory toolset requires a path to be set")
+		if t.Scope != "" && t.Scope != "agent" && t.Scope != "project" {
+			return fmt.Errorf("unknown memory scope %q, expected agent or project", t.Scope)
+		}
+		if t.Recall < 0 {
+			return errors.New("recall must be positive")
 		}
 	case "mcp":
 		count := 0
@@ -108,9 +166,28 @@ func (t *Toolset) validate() error {
 			return errors.New("either command, remote or ref must be set, but only one of those")
 		}
 
+		if t.Sandbox != nil {
+			if t.Command == "" {
+				return errors.New("sandbox can only be used with the command of an mcp toolset")
+			}
+			if t.Sandbox.Image == "" {
+				return errors.New("the sandbox of an mcp toolset requires an image with the server's command")
+			}
+		}
+
 		if t.Ref != "" && !strings.Contains(t.Ref, "docker:") {
 			return errors.New("only docker refs are supported for MCP tools, e.g., 'docker:context7'")
 		}
+		switch t.Remote.TransportType {
+		case "", "streamable", "streamable-http", "sse":
+		default:
+			return fmt.Errorf("unsupported transport_type %q, expected 'streamable' or 'sse'", t.Remote.TransportType)
+		}
+		for _, uri := range t.Resources {
+			if scheme, _, ok := strings.Cut(uri, ":"); !ok || scheme == "" {
+				return fmt.Errorf("invalid resource %q, expected a URI such as 'repo://readme'", uri)
+			}
+		}
 	case "a2a":
 		if t.URL == "" {
 			return errors.New("a2a toolset requires a url to be set")
@@ -123,3 +200,44 @@ func (t *Toolset) validate() error {
 
 	return nil
 }
+
+func (t *ToolsConfig) validate() error {
+	for _, pattern := range slices.Concat(t.Include, t.Exclude) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid tools pattern %q: %w", pattern, err)
+		}
+	}
+
+	names := make(map[string]string, len(t.Aliases))
+	for name, alias := range t.Aliases {
+		if alias == "" {
+			return fmt.Errorf("the alias of tool %q can't be empty", name)
+		}
+		if other, ok := names[alias]; ok {
+			return fmt.Errorf("tools %q and %q have the same alias %q", min(name, other), max(name, other), alias)
+		}
+		names[alias] = name
+	}
+
+	return nil
+}
+
+func (l *ToolLimits) validate() error {
+	if l.Timeout < 0 {
+		return errors.New("the timeout of the limits must be positive")
+	}
+	if l.MaxResultSize < 0 {
+		return errors.New("the max_result_size of the limits must be positive")
+	}
+
+	for name, limits := range l.Tools {
+		if len(limits.Tools) > 0 {
+			return fmt.Errorf("the limits of tool %q can't have tools", name)
+		}
+		if err := limits.validate(); err != nil {
+			return fmt.Errorf("tool %q: %w", name, err)
+		}
+	}
+
+	return nil
+}
@@ -159,6 +159,54 @@ agents:
 `,
 			wantErr: "sandbox can only be used with type 'shell'",
 		},
+		{
+			name: "mcp command in a sandbox",
+			config: `
+version: "3"
+agents:
+  root:
+    model: "openai/gpt-4"
+    toolsets:
+      - type: mcp
+        command: npx
+        args: ["-y", "@modelcontextprotocol/server-everything"]
+        sandbox:
+          image: node:20-alpine
+`,
+			wantErr: "",
+		},
+		{
+			name: "mcp sandbox without image",
+			config: `
+version: "3"
+agents:
+  root:
+    model: "openai/gpt-4"
+    toolsets:
+      - type: mcp
+        command: npx
+        sandbox:
+          paths:
+            - .
+`,
+			wantErr: "the sandbox of an mcp toolset requires an image",
+		},
+		{
+			name: "remote mcp in a sandbox",
+			config: `
+version: "3"
+agents:
+  root:
+    model: "openai/gpt-4"
+    toolsets:
+      - type: mcp
+        remote:
+          url: https://mcp.example.com
+        sandbox:
+          image: alpine:latest
+`,
+			wantErr: "sandbox can only be used with the command of an mcp toolset",
+		},
 		{
 			name: "shell without sandbox is valid",
 			config: `
@@ -189,3 +237,112 @@ agents:
 		})
 	}
 }
+
+func TestToolset_Validate_Tools(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		tools   string
+		wantErr string
+	}{
+		{
+			name:  "include list",
+			tools: "[read_file]",
+		},
+		{
+			name:  "include, exclude and aliases",
+			tools: "{include: [git_*], exclude: [git_push], aliases: {git_status: status}}",
+		},
+		{
+			name:    "invalid pattern",
+			tools:   "{exclude: ['git_[']}",
+			wantErr: `invalid tools pattern "git_["`,
+		},
+		{
+			name:    "empty alias",
+			tools:   "{aliases: {search: ''}}",
+			wantErr: `the alias of tool "search" can't be empty`,
+		},
+		{
+			name:    "same alias",
+			tools:   "{aliases: {search: find, lookup: find}}",
+			wantErr: `tools "lookup" and "search" have the same alias "find"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var toolset Toolset
+			err := yaml.Unmarshal([]byte("type: filesystem\ntools: "+tt.tools+"\n"), &toolset)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestToolset_Validate_Limits(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		limits  string
+		wantErr string
+	}{
+		{
+			name:   "timeout and max result size",
+			limits: "{timeout: 30, max_result_size: 10000, tools: {search_files: {timeout: 5}}}",
+		},
+		{
+			name:    "negative timeout",
+			limits:  "{timeout: -1}",
+			wantErr: "the timeout of the limits must be positive",
+		},
+		{
+			name:    "negative max result size of a tool",
+			limits:  "{tools: {read_file: {max_result_size: -1}}}",
+			wantErr: `tool "read_file": the max_result_size of the limits must be positive`,
+		},
+		{
+			name:    "nested tools",
+			limits:  "{tools: {read_file: {tools: {write_file: {timeout: 1}}}}}",
+			wantErr: `the limits of tool "read_file" can't have tools`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var toolset Toolset
+			err := yaml.Unmarshal([]byte("type: filesystem\nlimits: "+tt.limits+"\n"), &toolset)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestToolLimits_For(t *testing.T) {
+	t.Parallel()
+
+	limits := ToolLimits{
+		Timeout:       30,
+		MaxResultSize: 10000,
+		Tools:         map[string]ToolLimits{"search_files": {Timeout: 5}},
+	}
+
+	require.Equal(t, ToolLimits{Timeout: 5, MaxResultSize: 10000}, limits.For("search_files"))
+	require.Equal(t, ToolLimits{Timeout: 30, MaxResultSize: 10000}, limits.For("read_file"))
+}
@@ -97,6 +97,24 @@ func ensureModelsExist(cfg *latest.Config) error {
 				return err
 			}
 		}
+
+		if agent.SummarizeToolResults != nil {
+			if err := ensureSingleModelExists(cfg, agent.SummarizeToolResults.Model, fmt.Sprintf("tool result summarization of agent '%s'", agent.Name)); err != nil {
+				return err
+			}
+		}
+
+		if agent.Retry != nil {
+			if err := ensureSingleModelExists(cfg, agent.Retry.Fallback, fmt.Sprintf("retry fallback of agent '%s'", agent.Name)); err != nil {
+				return err
+			}
+		}
+
+		for _, guardrail := range agent.Guardrails {
+			if err := ensureSingleModelExists(cfg, guardrail.Model, fmt.Sprintf("guardrail of agent '%s'", agent.Name)); err != nil {
+				return err
+			}
+		}
 	}
 
 	// Ensure models referenced by routing rules exist
@@ -3,8 +3,12 @@ package config
 import (
 	"log/slog"
 	"sync"
+	"time"
 
+	"github.com/docker/cagent/pkg/audit"
+	"github.com/docker/cagent/pkg/disclosure"
 	"github.com/docker/cagent/pkg/environment"
+	"github.com/docker/cagent/pkg/review"
 )
 
 type RuntimeConfig struct {
@@ -20,6 +24,23 @@ type Config struct {
 	ModelsGateway  string
 	GlobalCodeMode bool
 	WorkingDir     string
+	PolicyFiles    []string
+	// ModelAllowlist restricts the models agents can use, nil allows every model.
+	ModelAllowlist *ModelAllowlist
+	AuditTargets   []string
+	// AuditRecorder receives the audit events of the runtimes, it's set up from AuditTargets.
+	AuditRecorder audit.Recorder
+	// Disclosure is appended to the responses sent through integrations (MCP, A2A).
+	Disclosure disclosure.Footer
+	// DedupWindow is how long the result of a call is shared with the
+	// identical calls of other agents, zero disables the deduplication.
+	DedupWindow time.Duration
+	// WarmSessions is how many runtimes the API server keeps ready for each
+	// agent, with their toolsets started, for new sessions to claim.
+	WarmSessions int
+	// ReviewQueue receives the tool calls requiring approval of the unattended
+	// runs started through the API server, such as webhook-triggered runs.
+	ReviewQueue *review.Queue
 }
 
 func (runConfig *RuntimeConfig) Clone() *RuntimeConfig {
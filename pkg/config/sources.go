@@ -37,6 +37,15 @@ func NewFileSource(path string) Source {
 	}
 }
 
+// LocalPath returns the path of the file a source reads the configuration
+// from, when it's a local file.
+func LocalPath(source Source) (string, bool) {
+	if s, ok := source.(fileSource); ok {
+		return s.path, true
+	}
+	return "", false
+}
+
 func (a fileSource) Name() string {
 	return a.path
 }
@@ -39,6 +39,30 @@ func TestValidationErrors(t *testing.T) {
 			name: "memory toolset missing path",
 			path: "missing_memory_path_v2.yaml",
 		},
+		{
+			name: "unknown memory scope",
+			path: "invalid_memory_scope.yaml",
+		},
+		{
+			name: "two knowledge toolsets",
+			path: "two_knowledge_toolsets.yaml",
+		},
+		{
+			name: "allowed_commands in non shell toolset",
+			path: "invalid_allowed_commands.yaml",
+		},
+		{
+			name: "web_search toolset missing engine",
+			path: "web_search_missing_engine.yaml",
+		},
+		{
+			name: "unsupported remote mcp transport",
+			path: "invalid_transport_type.yaml",
+		},
+		{
+			name: "mcp resource without scheme",
+			path: "invalid_mcp_resource.yaml",
+		},
 		{
 			name: "path in non memory toolset",
 			path: "invalid_path_v2.yaml",
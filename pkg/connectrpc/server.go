@@ -270,7 +270,7 @@ func (s *Server) RunAgent(ctx context.Context, req *connect.Request[cagentv1.Run
 		}
 	}
 
-	streamChan, err := s.sm.RunSession(ctx, sessionID, agentFilename, currentAgent, messages)
+	streamChan, err := s.sm.RunSession(ctx, sessionID, agentFilename, currentAgent, messages, false)
 	if err != nil {
 		return connect.NewError(connect.CodeInternal, fmt.Errorf("failed to run session: %w", err))
 	}
@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/docker/cagent/pkg/features"
 )
 
 type DockerHubInfo struct {
@@ -22,7 +24,7 @@ func GetToken(ctx context.Context) string {
 		return token
 	}
 
-	if os.Getenv("EXPERIMENTAL_DOCKER_TOKEN_REFRESH") != "1" {
+	if !features.DockerTokenRefresh.Enabled() {
 		return token
 	}
 
@@ -0,0 +1,49 @@
+// Package disclosure appends an AI-disclosure footer to the responses that
+// leave cagent through an integration (MCP, A2A) and end up in external
+// channels such as Slack, emails or pull request comments.
+package disclosure
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/cagent/pkg/version"
+)
+
+// Footer describes what is appended to agent responses.
+// The zero value, and a nil Footer, leave responses untouched.
+type Footer struct {
+	// Text is appended after the response. The {agent} and {version}
+	// placeholders are expanded.
+	Text string
+	// Metadata appends a hidden HTML comment identifying the agent and
+	// the cagent version that produced the response.
+	Metadata bool
+}
+
+// Enabled returns true if the footer changes responses.
+func (f *Footer) Enabled() bool {
+	return f != nil && (f.Text != "" || f.Metadata)
+}
+
+// Apply appends the footer to the response of the given agent.
+// Empty responses are returned as is.
+func (f *Footer) Apply(response, agentName string) string {
+	if !f.Enabled() || strings.TrimSpace(response) == "" {
+		return response
+	}
+
+	var sb strings.Builder
+	sb.WriteString(strings.TrimRight(response, "\n"))
+
+	if f.Text != "" {
+		text := strings.NewReplacer("{agent}", agentName, "{version}", version.Version).Replace(f.Text)
+		sb.WriteString("\n\n---\n")
+		sb.WriteString(text)
+	}
+	if f.Metadata {
+		fmt.Fprintf(&sb, "\n\n<!-- generated-by: cagent/%s agent=%s -->", version.Version, agentName)
+	}
+
+	return sb.String()
+}
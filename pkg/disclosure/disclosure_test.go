@@ -0,0 +1,38 @@
+package disclosure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/docker/cagent/pkg/version"
+)
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	footer := &Footer{Text: "Written by the {agent} AI agent."}
+	assert.Equal(t, "Hello\n\n---\nWritten by the root AI agent.", footer.Apply("Hello\n", "root"))
+}
+
+func TestApplyMetadata(t *testing.T) {
+	t.Parallel()
+
+	footer := &Footer{Metadata: true}
+	assert.Equal(t, "Hello\n\n<!-- generated-by: cagent/"+version.Version+" agent=root -->", footer.Apply("Hello", "root"))
+}
+
+func TestApplyDisabled(t *testing.T) {
+	t.Parallel()
+
+	var footer *Footer
+	assert.Equal(t, "Hello", footer.Apply("Hello", "root"))
+	assert.Equal(t, "Hello", (&Footer{}).Apply("Hello", "root"))
+}
+
+func TestApplyEmptyResponse(t *testing.T) {
+	t.Parallel()
+
+	footer := &Footer{Text: "AI generated"}
+	assert.Empty(t, footer.Apply("", "root"))
+}
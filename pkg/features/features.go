@@ -0,0 +1,116 @@
+// Package features gates the experimental capabilities of cagent, so that
+// they can ship disabled and be tried by enabling them.
+//
+// A feature is enabled by its environment variable, set to 1 or 0, or else by
+// the features of the user config (cagent features enable <name>).
+package features
+
+import (
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/docker/cagent/pkg/userconfig"
+)
+
+// Flag is an experimental feature, disabled by default.
+type Flag struct {
+	Name        string
+	Description string
+	// Env is the environment variable enabling or disabling the feature.
+	Env string
+}
+
+var registry []*Flag
+
+func register(name, description string) *Flag {
+	return registerWithEnv(name, "CAGENT_EXPERIMENTAL_"+strings.ToUpper(strings.ReplaceAll(name, "-", "_")), description)
+}
+
+func registerWithEnv(name, env, description string) *Flag {
+	flag := &Flag{Name: name, Description: description, Env: env}
+	registry = append(registry, flag)
+	return flag
+}
+
+var (
+	// MarkdownRenderer renders markdown in the TUI with the fast renderers
+	// instead of glamour.
+	MarkdownRenderer = register("markdown-renderer", "Render markdown in the TUI with the faster, incremental renderer")
+	// DebugLayout shows the layout of the messages in the TUI.
+	DebugLayout = register("debug-layout", "Show the layout of the messages in the TUI")
+	// DockerTokenRefresh refreshes the expired Docker Desktop tokens.
+	DockerTokenRefresh = registerWithEnv("docker-token-refresh", "EXPERIMENTAL_DOCKER_TOKEN_REFRESH", "Refresh the expired Docker Desktop token with docker login")
+)
+
+// All returns the features, sorted by name.
+func All() []*Flag {
+	return slices.SortedFunc(slices.Values(registry), func(a, b *Flag) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+}
+
+// Lookup returns the feature with the given name.
+func Lookup(name string) (*Flag, bool) {
+	for _, flag := range registry {
+		if flag.Name == name {
+			return flag, true
+		}
+	}
+	return nil, false
+}
+
+// Source is where the state of a feature comes from.
+type Source string
+
+const (
+	SourceDefault Source = "default"
+	SourceEnv     Source = "env"
+	SourceConfig  Source = "config"
+)
+
+// State is whether a feature is enabled, and why.
+type State struct {
+	Enabled bool
+	Source  Source
+}
+
+// Resolve returns the state of a feature, given the environment and the
+// features of the user config.
+func Resolve(flag *Flag, getenv func(string) string, config map[string]bool) State {
+	if value := getenv(flag.Env); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err == nil {
+			return State{Enabled: enabled, Source: SourceEnv}
+		}
+		slog.Warn("Ignoring invalid feature flag value", "env", flag.Env, "value", value)
+	}
+	if enabled, ok := config[flag.Name]; ok {
+		return State{Enabled: enabled, Source: SourceConfig}
+	}
+	return State{Source: SourceDefault}
+}
+
+// Current returns the state of a feature for this process.
+func (f *Flag) Current() State {
+	return Resolve(f, os.Getenv, userFeatures())
+}
+
+// Enabled reports whether the feature is enabled for this process.
+func (f *Flag) Enabled() bool {
+	return f.Current().Enabled
+}
+
+// userFeatures is read once: features are checked on hot paths, like the
+// rendering of messages.
+var userFeatures = sync.OnceValue(func() map[string]bool {
+	config, err := userconfig.Load()
+	if err != nil {
+		slog.Warn("Failed to load the features of the user config", "error", err)
+		return nil
+	}
+	return config.Features
+})
@@ -0,0 +1,45 @@
+package features
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{}
+	getenv := func(key string) string { return env[key] }
+
+	assert.Equal(t, State{Source: SourceDefault}, Resolve(MarkdownRenderer, getenv, nil))
+	assert.Equal(t, State{Enabled: true, Source: SourceConfig}, Resolve(MarkdownRenderer, getenv, map[string]bool{"markdown-renderer": true}))
+
+	// The environment wins over the config
+	env["CAGENT_EXPERIMENTAL_MARKDOWN_RENDERER"] = "0"
+	assert.Equal(t, State{Source: SourceEnv}, Resolve(MarkdownRenderer, getenv, map[string]bool{"markdown-renderer": true}))
+	env["CAGENT_EXPERIMENTAL_MARKDOWN_RENDERER"] = "1"
+	assert.Equal(t, State{Enabled: true, Source: SourceEnv}, Resolve(MarkdownRenderer, getenv, nil))
+
+	// Invalid values are ignored
+	env["CAGENT_EXPERIMENTAL_MARKDOWN_RENDERER"] = "maybe"
+	assert.Equal(t, State{Source: SourceDefault}, Resolve(MarkdownRenderer, getenv, nil))
+}
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+
+	flag, ok := Lookup("docker-token-refresh")
+	require.True(t, ok)
+	assert.Equal(t, "EXPERIMENTAL_DOCKER_TOKEN_REFRESH", flag.Env)
+
+	_, ok = Lookup("unknown")
+	assert.False(t, ok)
+
+	var names []string
+	for _, flag := range All() {
+		names = append(names, flag.Name)
+	}
+	assert.IsIncreasing(t, names)
+}
@@ -0,0 +1,99 @@
+package fsx
+
+import (
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMaxIndexedFiles caps the number of entries walked by a FileIndex.
+	DefaultMaxIndexedFiles = 20_000
+	// DefaultIndexRefreshInterval is how long a FileIndex reuses its listing.
+	DefaultIndexRefreshInterval = 10 * time.Second
+)
+
+// FileIndex lists the files of a directory tree, for example to complete
+// file paths as the user types. Files ignored by VCS rules (.git, .gitignore)
+// are skipped. The listing is cached and walked again once it gets older
+// than the refresh interval, so that repeated lookups stay fast.
+type FileIndex struct {
+	root            string
+	maxFiles        int
+	refreshInterval time.Duration
+
+	mu        sync.Mutex
+	files     []string
+	indexedAt time.Time
+}
+
+// FileIndexOpt is an option for creating a FileIndex.
+type FileIndexOpt func(*FileIndex)
+
+// WithMaxIndexedFiles caps the number of entries walked by the index.
+// Zero or less means no limit.
+func WithMaxIndexedFiles(n int) FileIndexOpt {
+	return func(idx *FileIndex) {
+		idx.maxFiles = n
+	}
+}
+
+// WithIndexRefreshInterval sets how long the index reuses its listing.
+func WithIndexRefreshInterval(d time.Duration) FileIndexOpt {
+	return func(idx *FileIndex) {
+		idx.refreshInterval = d
+	}
+}
+
+// NewFileIndex creates an index of the files under root.
+func NewFileIndex(root string, opts ...FileIndexOpt) *FileIndex {
+	idx := &FileIndex{
+		root:            root,
+		maxFiles:        DefaultMaxIndexedFiles,
+		refreshInterval: DefaultIndexRefreshInterval,
+	}
+	for _, opt := range opts {
+		opt(idx)
+	}
+	return idx
+}
+
+// Files returns the paths of the indexed files, joined to the root: an index
+// of "." returns paths relative to the working directory. The directory tree
+// is walked on the first call and whenever the listing is stale.
+func (idx *FileIndex) Files() []string {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if idx.files == nil || time.Since(idx.indexedAt) > idx.refreshInterval {
+		idx.files = idx.walk()
+		idx.indexedAt = time.Now()
+	}
+
+	return slices.Clone(idx.files)
+}
+
+// Invalidate drops the cached listing so that the next call to Files walks
+// the directory tree again.
+func (idx *FileIndex) Invalidate() {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.files = nil
+}
+
+func (idx *FileIndex) walk() []string {
+	var shouldIgnore func(string) bool
+	if vcsMatcher, err := NewVCSMatcher(idx.root); err == nil && vcsMatcher != nil {
+		shouldIgnore = vcsMatcher.ShouldIgnore
+	}
+
+	tree, err := DirectoryTree(idx.root, func(string) error { return nil }, shouldIgnore, idx.maxFiles)
+	if err != nil {
+		return []string{}
+	}
+
+	files := []string{}
+	CollectFilesFromTree(tree, filepath.Dir(idx.root), &files)
+	return files
+}
@@ -0,0 +1,62 @@
+package fsx
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileIndex(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "src"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "README.md"), []byte("readme"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "src", "main.go"), []byte("package main"), 0o644))
+
+	idx := NewFileIndex(root, WithIndexRefreshInterval(time.Hour))
+	assert.ElementsMatch(t, []string{
+		filepath.Join(root, "README.md"),
+		filepath.Join(root, "src", "main.go"),
+	}, idx.Files())
+
+	// The listing is cached until it's invalidated.
+	require.NoError(t, os.WriteFile(filepath.Join(root, "new.txt"), []byte("new"), 0o644))
+	assert.Len(t, idx.Files(), 2)
+
+	idx.Invalidate()
+	assert.Len(t, idx.Files(), 3)
+}
+
+func TestFileIndex_RespectsGitignore(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".git"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "build"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".gitignore"), []byte("build/\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".git", "HEAD"), []byte("ref: refs/heads/main"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "build", "out.bin"), []byte("bin"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), []byte("package main"), 0o644))
+
+	files := NewFileIndex(root).Files()
+	assert.Contains(t, files, filepath.Join(root, "main.go"))
+	assert.NotContains(t, files, filepath.Join(root, "build", "out.bin"))
+	assert.NotContains(t, files, filepath.Join(root, ".git", "HEAD"))
+}
+
+func TestFileIndex_MaxFiles(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	for _, name := range []string{"a.txt", "b.txt", "c.txt", "d.txt"} {
+		require.NoError(t, os.WriteFile(filepath.Join(root, name), []byte(name), 0o644))
+	}
+
+	// The root directory counts as one entry.
+	assert.Len(t, NewFileIndex(root, WithMaxIndexedFiles(3)).Files(), 2)
+}
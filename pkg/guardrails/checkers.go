@@ -0,0 +1,167 @@
+package guardrails
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// patternChecker finds the matches of regular expressions.
+type patternChecker struct {
+	patterns []*regexp.Regexp
+	// reasons tell what each pattern found
+	reasons []string
+	// valid, if set, filters out the false positives of a pattern
+	valid []func(match string) bool
+}
+
+func (c *patternChecker) Check(_ context.Context, text string) (*Finding, error) {
+	var finding *Finding
+	for i, re := range c.patterns {
+		for _, loc := range re.FindAllStringIndex(text, -1) {
+			if c.valid != nil && c.valid[i] != nil && !c.valid[i](text[loc[0]:loc[1]]) {
+				continue
+			}
+			if finding == nil {
+				finding = &Finding{Reason: c.reasons[i]}
+			}
+			finding.Spans = append(finding.Spans, [2]int{loc[0], loc[1]})
+		}
+	}
+	return finding, nil
+}
+
+// Regex returns a checker finding the matches of regular expressions, with
+// the RE2 syntax.
+func Regex(patterns []string) (Checker, error) {
+	if len(patterns) == 0 {
+		return nil, errors.New("no patterns")
+	}
+
+	c := &patternChecker{}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		c.patterns = append(c.patterns, re)
+		c.reasons = append(c.reasons, fmt.Sprintf("text matching %q", pattern))
+	}
+	return c, nil
+}
+
+// Keywords returns a checker finding keywords, as whole words, ignoring the
+// case.
+func Keywords(keywords []string) (Checker, error) {
+	if len(keywords) == 0 {
+		return nil, errors.New("no keywords")
+	}
+
+	c := &patternChecker{}
+	for _, keyword := range keywords {
+		keyword = strings.TrimSpace(keyword)
+		if keyword == "" {
+			return nil, errors.New("empty keyword")
+		}
+
+		pattern := regexp.QuoteMeta(keyword)
+		// \b only separates word characters from the others
+		if isWordChar(rune(keyword[0])) {
+			pattern = `\b` + pattern
+		}
+		if isWordChar(rune(keyword[len(keyword)-1])) {
+			pattern += `\b`
+		}
+		c.patterns = append(c.patterns, regexp.MustCompile("(?i)"+pattern))
+		c.reasons = append(c.reasons, fmt.Sprintf("the keyword %q", keyword))
+	}
+	return c, nil
+}
+
+func isWordChar(r rune) bool {
+	return r == '_' || r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+// Kinds of personally identifiable information
+const (
+	PIIEmail      = "email"
+	PIIPhone      = "phone"
+	PIICreditCard = "credit_card"
+	PIISSN        = "ssn"
+	PIIIPAddress  = "ip_address"
+)
+
+// PIIKinds are the kinds of PII the PII checker finds. Card numbers are
+// looked for before phone numbers, which they can look like.
+var PIIKinds = []string{PIIEmail, PIICreditCard, PIISSN, PIIPhone, PIIIPAddress}
+
+var piiDetectors = map[string]struct {
+	pattern *regexp.Regexp
+	reason  string
+	valid   func(string) bool
+}{
+	PIIEmail: {
+		pattern: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+		reason:  "an email address",
+	},
+	PIIPhone: {
+		pattern: regexp.MustCompile(`(?:\+\d{1,3}[\s.-]?)?(?:\(\d{2,4}\)[\s.-]?|\b\d{2,4}[\s.-])\d{3,4}[\s.-]\d{3,4}\b`),
+		reason:  "a phone number",
+	},
+	PIICreditCard: {
+		pattern: regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+		reason:  "a credit card number",
+		valid:   luhn,
+	},
+	PIISSN: {
+		pattern: regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`),
+		reason:  "a social security number",
+	},
+	PIIIPAddress: {
+		pattern: regexp.MustCompile(`\b(?:(?:25[0-5]|2[0-4]\d|1?\d?\d)\.){3}(?:25[0-5]|2[0-4]\d|1?\d?\d)\b`),
+		reason:  "an IP address",
+	},
+}
+
+// PII returns a checker finding personally identifiable information of the
+// given kinds, all of them when none is given.
+func PII(kinds []string) (Checker, error) {
+	if len(kinds) == 0 {
+		kinds = PIIKinds
+	}
+
+	c := &patternChecker{}
+	for _, kind := range kinds {
+		detector, ok := piiDetectors[kind]
+		if !ok {
+			return nil, fmt.Errorf("unknown kind of PII %q, expected one of %s", kind, strings.Join(PIIKinds, ", "))
+		}
+		c.patterns = append(c.patterns, detector.pattern)
+		c.reasons = append(c.reasons, detector.reason)
+		c.valid = append(c.valid, detector.valid)
+	}
+	return c, nil
+}
+
+// luhn returns whether a card number has a valid Luhn checksum, to tell card
+// numbers from other long numbers.
+func luhn(number string) bool {
+	digits := slices.DeleteFunc([]rune(number), func(r rune) bool { return r == ' ' || r == '-' })
+
+	sum := 0
+	for i, r := range slices.Backward(digits) {
+		d := int(r - '0')
+		if (len(digits)-1-i)%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
@@ -0,0 +1,235 @@
+// Package guardrails checks the answers of agents and the arguments of their
+// tool calls before they're used: a guardrail finds what it forbids with a
+// regular expression, keywords, PII detection or a moderation model, and then
+// blocks the text, warns about it or redacts it.
+package guardrails
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Action is what a guardrail does with the text it finds.
+type Action string
+
+const (
+	// ActionBlock rejects the answer, or the tool call.
+	ActionBlock Action = "block"
+	// ActionWarn lets the text through, and tells the user.
+	ActionWarn Action = "warn"
+	// ActionRedact replaces the text found with Redacted.
+	ActionRedact Action = "redact"
+)
+
+// Target is what a guardrail checks.
+type Target string
+
+const (
+	// TargetOutput is the answers of the agent.
+	TargetOutput Target = "output"
+	// TargetToolArguments is the arguments of the tool calls of the agent,
+	// checked before the tools run.
+	TargetToolArguments Target = "tool_arguments"
+)
+
+// Redacted replaces the text redacted by a guardrail.
+const Redacted = "[REDACTED]"
+
+// Checker finds what a guardrail forbids in a text.
+type Checker interface {
+	// Check returns what was found in text, or nil if nothing was.
+	Check(ctx context.Context, text string) (*Finding, error)
+}
+
+// Finding is what a checker found in a text.
+type Finding struct {
+	// Reason tells what was found, e.g. "an email address" or "the keyword
+	// \"internal\""
+	Reason string
+	// Spans are the start and end byte offsets of the text found, to redact
+	// it. Checkers that can't locate it, such as moderation models, leave
+	// them empty.
+	Spans [][2]int
+}
+
+// Guardrail checks the answers of an agent, or the arguments of its tool
+// calls, with a checker.
+type Guardrail struct {
+	Name    string
+	Action  Action
+	Checker Checker
+	// Targets is what the guardrail checks, everything when empty
+	Targets []Target
+}
+
+// AppliesTo returns whether the guardrail checks target.
+func (g *Guardrail) AppliesTo(target Target) bool {
+	return len(g.Targets) == 0 || slices.Contains(g.Targets, target)
+}
+
+// Violation is a guardrail that found something.
+type Violation struct {
+	Guardrail string `json:"guardrail"`
+	Action    Action `json:"action"`
+	Reason    string `json:"reason"`
+}
+
+// Result is the outcome of checking a text against guardrails.
+type Result struct {
+	// Text is the text checked, with the redactions
+	Text       string
+	Violations []Violation
+}
+
+// Blocked returns the violation blocking the text, if any.
+func (r *Result) Blocked() *Violation {
+	for i := range r.Violations {
+		if r.Violations[i].Action == ActionBlock {
+			return &r.Violations[i]
+		}
+	}
+	return nil
+}
+
+// Redacted returns whether a guardrail redacted the text.
+func (r *Result) Redacted() bool {
+	return slices.ContainsFunc(r.Violations, func(v Violation) bool {
+		return v.Action == ActionRedact
+	})
+}
+
+// Check checks an answer of an agent against the guardrails applying to
+// target, in order: each guardrail checks the text redacted by the previous
+// ones. It stops at the first guardrail blocking it.
+func Check(ctx context.Context, guardrails []*Guardrail, target Target, text string) Result {
+	fields := []string{text}
+	violations := check(ctx, guardrails, target, fields)
+	return Result{Text: fields[0], Violations: violations}
+}
+
+// CheckArguments checks the JSON arguments of a tool call against the
+// guardrails applying to tool arguments. Only the string values are checked
+// and redacted, so that the arguments stay valid JSON.
+func CheckArguments(ctx context.Context, guardrails []*Guardrail, arguments string) Result {
+	var args any
+	if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+		return Check(ctx, guardrails, TargetToolArguments, arguments)
+	}
+
+	var fields []string
+	walkStrings(args, func(s string) string {
+		fields = append(fields, s)
+		return s
+	})
+	violations := check(ctx, guardrails, TargetToolArguments, fields)
+
+	result := Result{Text: arguments, Violations: violations}
+	if result.Redacted() {
+		i := 0
+		args = walkStrings(args, func(string) string {
+			i++
+			return fields[i-1]
+		})
+		if redacted, err := json.Marshal(args); err == nil {
+			result.Text = string(redacted)
+		}
+	}
+	return result
+}
+
+// check runs the guardrails applying to target on fields, redacting them in
+// place. The guardrails that don't redact check all the fields at once.
+func check(ctx context.Context, guardrails []*Guardrail, target Target, fields []string) []Violation {
+	var violations []Violation
+	for _, g := range guardrails {
+		if !g.AppliesTo(target) {
+			continue
+		}
+
+		var finding *Finding
+		var err error
+		if g.Action == ActionRedact {
+			for i, field := range fields {
+				var f *Finding
+				if f, err = g.Checker.Check(ctx, field); err != nil {
+					break
+				}
+				if f != nil {
+					fields[i] = redact(field, f.Spans)
+					// The reason of the violation is the first thing found
+					finding = cmp.Or(finding, f)
+				}
+			}
+		} else {
+			finding, err = g.Checker.Check(ctx, strings.Join(fields, "\n"))
+		}
+
+		switch {
+		case err != nil:
+			// A guardrail that can't check the text doesn't let it through
+			// silently
+			action := g.Action
+			if action == ActionRedact {
+				action = ActionBlock
+			}
+			violations = append(violations, Violation{Guardrail: g.Name, Action: action, Reason: fmt.Sprintf("content it couldn't check (%v)", err)})
+		case finding != nil:
+			violations = append(violations, Violation{Guardrail: g.Name, Action: g.Action, Reason: finding.Reason})
+		default:
+			continue
+		}
+
+		if violations[len(violations)-1].Action == ActionBlock {
+			break
+		}
+	}
+	return violations
+}
+
+// redact replaces the spans of text with Redacted. The spans may overlap.
+func redact(text string, spans [][2]int) string {
+	if len(spans) == 0 {
+		return text
+	}
+
+	spans = slices.Clone(spans)
+	slices.SortFunc(spans, func(a, b [2]int) int { return a[0] - b[0] })
+
+	var redacted strings.Builder
+	end := 0
+	for _, span := range spans {
+		if span[1] <= end {
+			continue
+		}
+		if span[0] >= end {
+			redacted.WriteString(text[end:span[0]])
+			redacted.WriteString(Redacted)
+		}
+		end = span[1]
+	}
+	redacted.WriteString(text[end:])
+	return redacted.String()
+}
+
+// walkStrings replaces the strings of a JSON value with fn, in a stable
+// order.
+func walkStrings(v any, fn func(string) string) any {
+	switch v := v.(type) {
+	case string:
+		return fn(v)
+	case []any:
+		for i := range v {
+			v[i] = walkStrings(v[i], fn)
+		}
+	case map[string]any:
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			v[k] = walkStrings(v[k], fn)
+		}
+	}
+	return v
+}
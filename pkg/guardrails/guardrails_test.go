@@ -0,0 +1,131 @@
+package guardrails
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// checker fails the test if a checker can't be created.
+func checker(t *testing.T) func(Checker, error) Checker {
+	t.Helper()
+	return func(c Checker, err error) Checker {
+		require.NoError(t, err)
+		return c
+	}
+}
+
+func TestCheck_Redact(t *testing.T) {
+	t.Parallel()
+
+	pii := &Guardrail{Name: "pii", Action: ActionRedact, Checker: checker(t)(PII(nil))}
+
+	result := Check(t.Context(), []*Guardrail{pii}, TargetOutput, "Mail jane.doe@example.com or call +1 415-555-0132, card 4111 1111 1111 1111.")
+	assert.Equal(t, "Mail [REDACTED] or call [REDACTED], card [REDACTED].", result.Text)
+	assert.Equal(t, []Violation{{Guardrail: "pii", Action: ActionRedact, Reason: "an email address"}}, result.Violations)
+	assert.Nil(t, result.Blocked())
+}
+
+func TestCheck_BlockStopsAtFirstGuardrail(t *testing.T) {
+	t.Parallel()
+
+	warn := &Guardrail{Name: "internal", Action: ActionWarn, Checker: checker(t)(Keywords([]string{"Project X"}))}
+	block := &Guardrail{Name: "secrets", Action: ActionBlock, Checker: checker(t)(Regex([]string{`sk-[A-Za-z0-9]{8,}`}))}
+	redact := &Guardrail{Name: "pii", Action: ActionRedact, Checker: checker(t)(PII(nil))}
+
+	result := Check(t.Context(), []*Guardrail{warn, block, redact}, TargetOutput, "project x uses sk-abcdef123456 for me@example.com")
+	assert.Equal(t, []Violation{
+		{Guardrail: "internal", Action: ActionWarn, Reason: `the keyword "Project X"`},
+		{Guardrail: "secrets", Action: ActionBlock, Reason: `text matching "sk-[A-Za-z0-9]{8,}"`},
+	}, result.Violations)
+	require.NotNil(t, result.Blocked())
+	assert.Equal(t, "secrets", result.Blocked().Guardrail)
+}
+
+func TestCheck_Targets(t *testing.T) {
+	t.Parallel()
+
+	g := &Guardrail{Name: "words", Action: ActionBlock, Checker: checker(t)(Keywords([]string{"drop"})), Targets: []Target{TargetToolArguments}}
+
+	assert.Empty(t, Check(t.Context(), []*Guardrail{g}, TargetOutput, "drop the table").Violations)
+	assert.NotEmpty(t, Check(t.Context(), []*Guardrail{g}, TargetToolArguments, "drop the table").Violations)
+	// Keywords are whole words
+	assert.Empty(t, Check(t.Context(), []*Guardrail{g}, TargetToolArguments, "a dropdown").Violations)
+}
+
+type failingChecker struct{}
+
+func (failingChecker) Check(context.Context, string) (*Finding, error) {
+	return nil, errors.New("model unavailable")
+}
+
+func TestCheck_FailedCheckDoesntLetTextThrough(t *testing.T) {
+	t.Parallel()
+
+	g := &Guardrail{Name: "moderation", Action: ActionBlock, Checker: failingChecker{}}
+
+	result := Check(t.Context(), []*Guardrail{g}, TargetOutput, "hello")
+	require.NotNil(t, result.Blocked())
+	assert.Equal(t, "content it couldn't check (model unavailable)", result.Blocked().Reason)
+}
+
+func TestCheckArguments(t *testing.T) {
+	t.Parallel()
+
+	pii := &Guardrail{Name: "pii", Action: ActionRedact, Checker: checker(t)(PII([]string{PIIEmail}))}
+
+	result := CheckArguments(t.Context(), []*Guardrail{pii}, `{"to": ["a@example.com", "b@example.com"], "body": "Hi \"a@example.com\"", "count": 2}`)
+	assert.True(t, result.Redacted())
+	assert.JSONEq(t, `{"to": ["[REDACTED]", "[REDACTED]"], "body": "Hi \"[REDACTED]\"", "count": 2}`, result.Text)
+
+	// Arguments without anything to redact are left as they are
+	result = CheckArguments(t.Context(), []*Guardrail{pii}, `{"path": "README.md"}`)
+	assert.Empty(t, result.Violations)
+	assert.Equal(t, `{"path": "README.md"}`, result.Text)
+}
+
+func TestPII(t *testing.T) {
+	t.Parallel()
+
+	c := checker(t)(PII(nil))
+	tests := map[string]string{
+		"ssn 123-45-6789":          "a social security number",
+		"host 192.168.1.10":        "an IP address",
+		"card 5500-0000-0000-0004": "a credit card number",
+		"call (415) 555-0132":      "a phone number",
+	}
+	for text, reason := range tests {
+		finding, err := c.Check(t.Context(), text)
+		require.NoError(t, err)
+		require.NotNil(t, finding, text)
+		assert.Equal(t, reason, finding.Reason, text)
+	}
+
+	// Long numbers failing the Luhn checksum aren't card numbers
+	finding, err := c.Check(t.Context(), "order 1234567890123456 on 2024-01-15")
+	require.NoError(t, err)
+	assert.Nil(t, finding)
+
+	_, err = PII([]string{"passport"})
+	assert.Error(t, err)
+}
+
+func TestParseModeration(t *testing.T) {
+	t.Parallel()
+
+	finding, err := parseModeration("```json\n{\"flagged\": true, \"reason\": \"an API key\"}\n```")
+	require.NoError(t, err)
+	require.NotNil(t, finding)
+	assert.Equal(t, "content violating the policy: an API key", finding.Reason)
+	assert.Empty(t, finding.Spans)
+
+	finding, err = parseModeration(`{"flagged": false}`)
+	require.NoError(t, err)
+	assert.Nil(t, finding)
+
+	_, err = parseModeration("I can't help with that")
+	assert.Error(t, err)
+}
@@ -0,0 +1,104 @@
+package guardrails
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/model/provider"
+)
+
+// DefaultModerationPolicy is what a moderation model flags without a policy.
+const DefaultModerationPolicy = "Harmful content: hate, harassment, violence, sexual content, self-harm, instructions for illegal or dangerous activities, and leaked secrets or credentials."
+
+const moderationSystemPrompt = `You are a content moderator. You check a text against a policy and tell whether it violates it.
+
+Answer with JSON only, without any other text: {"flagged": true or false, "reason": "what violates the policy, in a few words"}.`
+
+const moderationUserPrompt = `<policy>
+%s
+</policy>
+
+<text>
+%s
+</text>`
+
+// maxModerationInput is the number of bytes of a text a moderation model
+// reads.
+const maxModerationInput = 32000
+
+type moderationChecker struct {
+	model  provider.Provider
+	policy string
+}
+
+// Moderation returns a checker asking a model whether a text violates a
+// policy. It can't tell where the violation is, so it can't redact.
+func Moderation(model provider.Provider, policy string) Checker {
+	if strings.TrimSpace(policy) == "" {
+		policy = DefaultModerationPolicy
+	}
+	return &moderationChecker{model: model, policy: policy}
+}
+
+func (c *moderationChecker) Check(ctx context.Context, text string) (*Finding, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+	if len(text) > maxModerationInput {
+		text = strings.ToValidUTF8(text[:maxModerationInput], "")
+	}
+
+	messages := []chat.Message{
+		{Role: chat.MessageRoleSystem, Content: moderationSystemPrompt},
+		{Role: chat.MessageRoleUser, Content: fmt.Sprintf(moderationUserPrompt, c.policy, text)},
+	}
+
+	stream, err := c.model.CreateChatCompletionStream(ctx, messages, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating chat completion: %w", err)
+	}
+	defer stream.Close()
+
+	var answer strings.Builder
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("receiving the moderation: %w", err)
+		}
+		for _, choice := range resp.Choices {
+			answer.WriteString(choice.Delta.Content)
+		}
+	}
+
+	return parseModeration(answer.String())
+}
+
+// parseModeration parses the answer of a moderation model, which may wrap
+// its JSON in a code block.
+func parseModeration(answer string) (*Finding, error) {
+	text := strings.TrimSpace(answer)
+	if start, end := strings.Index(text, "{"), strings.LastIndex(text, "}"); start >= 0 && end > start {
+		text = text[start : end+1]
+	}
+
+	var moderation struct {
+		Flagged bool   `json:"flagged"`
+		Reason  string `json:"reason"`
+	}
+	if err := json.Unmarshal([]byte(text), &moderation); err != nil {
+		return nil, fmt.Errorf("unexpected moderation %q", answer)
+	}
+	if !moderation.Flagged {
+		return nil, nil
+	}
+	return &Finding{Reason: "content violating the policy: " + cmp.Or(strings.TrimSpace(moderation.Reason), "flagged by the moderation model")}, nil
+}
@@ -7,15 +7,20 @@ import (
 	"strings"
 )
 
+// DefaultMaxSize is the number of prompts kept in the history by default.
+const DefaultMaxSize = 1000
+
 type History struct {
 	Messages []string `json:"messages"`
 
 	path    string
 	current int
+	maxSize int
 }
 
 type options struct {
 	homeDir string
+	maxSize int
 }
 
 type Opt func(*options)
@@ -26,6 +31,14 @@ func WithBaseDir(dir string) Opt {
 	}
 }
 
+// WithMaxSize caps the number of prompts kept in the history.
+// Zero or a negative size uses DefaultMaxSize.
+func WithMaxSize(size int) Opt {
+	return func(o *options) {
+		o.maxSize = size
+	}
+}
+
 func New(opts ...Opt) (*History, error) {
 	o := &options{}
 	for _, opt := range opts {
@@ -43,6 +56,10 @@ func New(opts ...Opt) (*History, error) {
 	h := &History{
 		path:    filepath.Join(homeDir, ".cagent", "history.json"),
 		current: -1,
+		maxSize: DefaultMaxSize,
+	}
+	if o.maxSize > 0 {
+		h.maxSize = o.maxSize
 	}
 
 	if err := h.load(); err != nil && !os.IsNotExist(err) {
@@ -53,6 +70,12 @@ func New(opts ...Opt) (*History, error) {
 }
 
 func (h *History) Add(message string) error {
+	// The history is shared by every running cagent: pick up the prompts
+	// that were submitted elsewhere before saving.
+	if err := h.load(); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
 	// Add the message last but avoid duplicate messages
 	var messages []string
 	for _, msg := range h.Messages {
@@ -61,6 +84,9 @@ func (h *History) Add(message string) error {
 		}
 	}
 	messages = append(messages, message)
+	if len(messages) > h.maxSize {
+		messages = messages[len(messages)-h.maxSize:]
+	}
 
 	h.Messages = messages
 	h.current = len(h.Messages)
@@ -121,6 +147,39 @@ func (h *History) LatestMatch(prefix string) string {
 	return ""
 }
 
+// Search returns the entries matching the query, best matches first, like a
+// shell's reverse search. Entries containing the query come first, most
+// recent first, followed by entries that contain the query's characters in
+// order. Matching is case-insensitive.
+func (h *History) Search(query string) []string {
+	query = strings.ToLower(query)
+
+	var exact, fuzzy []string
+	for i := len(h.Messages) - 1; i >= 0; i-- {
+		message := strings.ToLower(h.Messages[i])
+		switch {
+		case strings.Contains(message, query):
+			exact = append(exact, h.Messages[i])
+		case fuzzyMatch(message, query):
+			fuzzy = append(fuzzy, h.Messages[i])
+		}
+	}
+
+	return append(exact, fuzzy...)
+}
+
+// fuzzyMatch reports whether the characters of query appear in s, in order.
+func fuzzyMatch(s, query string) bool {
+	for _, r := range query {
+		i := strings.IndexRune(s, r)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(string(r)):]
+	}
+	return true
+}
+
 func (h *History) save() error {
 	data, err := json.Marshal(h)
 	if err != nil {
@@ -120,3 +120,50 @@ func TestHistory_MoveDuplicateLast(t *testing.T) {
 	assert.Equal(t, "second", h.Previous())
 	assert.Equal(t, "second", h.Previous())
 }
+
+func TestHistory_MaxSize(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	h, err := New(WithMaxSize(2))
+	require.NoError(t, err)
+
+	for _, msg := range []string{"first", "second", "third"} {
+		require.NoError(t, h.Add(msg))
+	}
+
+	assert.Equal(t, []string{"second", "third"}, h.Messages)
+}
+
+func TestHistory_SharedBetweenInstances(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	h1, err := New()
+	require.NoError(t, err)
+	h2, err := New()
+	require.NoError(t, err)
+
+	require.NoError(t, h1.Add("from first"))
+	require.NoError(t, h2.Add("from second"))
+	require.NoError(t, h1.Add("again from first"))
+
+	assert.Equal(t, []string{"from first", "from second", "again from first"}, h1.Messages)
+}
+
+func TestHistory_Search(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	h, err := New()
+	require.NoError(t, err)
+
+	for _, msg := range []string{"run the tests", "Fix the failing test", "explain the readme", "write docs"} {
+		require.NoError(t, h.Add(msg))
+	}
+
+	assert.Equal(t, []string{"Fix the failing test", "run the tests"}, h.Search("test"))
+	assert.Equal(t, []string{"Fix the failing test", "run the tests"}, h.Search("TEST"))
+	// Substring matches come before fuzzy ones, even older ones
+	assert.Equal(t, []string{"run the tests", "Fix the failing test"}, h.Search("the t"))
+	assert.Equal(t, []string{"write docs", "run the tests"}, h.Search("rt"))
+	assert.Empty(t, h.Search("zzz"))
+	assert.Len(t, h.Search(""), 4)
+}
@@ -44,6 +44,24 @@ func FromConfig(cfg *latest.HooksConfig) *Config {
 		result.PostToolUse = append(result.PostToolUse, mc)
 	}
 
+	// Convert PreModelCall
+	for _, h := range cfg.PreModelCall {
+		result.PreModelCall = append(result.PreModelCall, Hook{
+			Type:    HookType(h.Type),
+			Command: h.Command,
+			Timeout: h.Timeout,
+		})
+	}
+
+	// Convert PostModelCall
+	for _, h := range cfg.PostModelCall {
+		result.PostModelCall = append(result.PostModelCall, Hook{
+			Type:    HookType(h.Type),
+			Command: h.Command,
+			Timeout: h.Timeout,
+		})
+	}
+
 	// Convert SessionStart
 	for _, h := range cfg.SessionStart {
 		result.SessionStart = append(result.SessionStart, Hook{
@@ -167,6 +167,28 @@ func (e *Executor) ExecutePostToolUse(ctx context.Context, input *Input) (*Resul
 	return e.executeHooks(ctx, hooksToRun, input, EventPostToolUse)
 }
 
+// ExecutePreModelCall runs pre-model-call hooks
+func (e *Executor) ExecutePreModelCall(ctx context.Context, input *Input) (*Result, error) {
+	if e.config == nil || len(e.config.PreModelCall) == 0 {
+		return &Result{Allowed: true}, nil
+	}
+
+	input.HookEventName = EventPreModelCall
+
+	return e.executeHooks(ctx, e.config.PreModelCall, input, EventPreModelCall)
+}
+
+// ExecutePostModelCall runs post-model-call hooks
+func (e *Executor) ExecutePostModelCall(ctx context.Context, input *Input) (*Result, error) {
+	if e.config == nil || len(e.config.PostModelCall) == 0 {
+		return &Result{Allowed: true}, nil
+	}
+
+	input.HookEventName = EventPostModelCall
+
+	return e.executeHooks(ctx, e.config.PostModelCall, input, EventPostModelCall)
+}
+
 // ExecuteSessionStart runs session start hooks
 func (e *Executor) ExecuteSessionStart(ctx context.Context, input *Input) (*Result, error) {
 	if e.config == nil || len(e.config.SessionStart) == 0 {
@@ -371,6 +393,11 @@ func (e *Executor) aggregateResults(results []hookResult, eventType EventType) (
 					}
 				}
 
+				// PostModelCall content replacement, the last hook wins
+				if eventType == EventPostModelCall && hso.UpdatedContent != nil {
+					finalResult.UpdatedContent = hso.UpdatedContent
+				}
+
 				// Additional context
 				if hso.AdditionalContext != "" {
 					additionalContexts = append(additionalContexts, hso.AdditionalContext)
@@ -378,7 +405,7 @@ func (e *Executor) aggregateResults(results []hookResult, eventType EventType) (
 			}
 		} else if r.stdout != "" {
 			// Plain text stdout is added as context for some events
-			if eventType == EventSessionStart || eventType == EventPostToolUse {
+			if eventType == EventSessionStart || eventType == EventPostToolUse || eventType == EventPreModelCall {
 				additionalContexts = append(additionalContexts, strings.TrimSpace(r.stdout))
 			}
 		}
@@ -408,6 +435,16 @@ func (e *Executor) HasPostToolUseHooks() bool {
 	return e.config != nil && len(e.postToolUseMatchers) > 0
 }
 
+// HasPreModelCallHooks returns true if there are any pre-model-call hooks configured
+func (e *Executor) HasPreModelCallHooks() bool {
+	return e.config != nil && len(e.config.PreModelCall) > 0
+}
+
+// HasPostModelCallHooks returns true if there are any post-model-call hooks configured
+func (e *Executor) HasPostModelCallHooks() bool {
+	return e.config != nil && len(e.config.PostModelCall) > 0
+}
+
 // HasSessionStartHooks returns true if there are any session start hooks configured
 func (e *Executor) HasSessionStartHooks() bool {
 	return e.config != nil && len(e.config.SessionStart) > 0
@@ -8,6 +8,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/chat"
 )
 
 func TestHookGetTimeout(t *testing.T) {
@@ -480,6 +482,58 @@ func TestExecuteSessionEnd(t *testing.T) {
 	assert.True(t, result.Allowed)
 }
 
+func TestExecutePreModelCall(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{
+		PreModelCall: []Hook{
+			// The hook reads the messages sent to the model on stdin
+			{Type: HookTypeCommand, Command: `grep -q '"content":"deploy"' && echo 'Deployments need a ticket number.'`, Timeout: 5},
+		},
+	}
+
+	exec := NewExecutor(config, t.TempDir(), nil)
+	input := &Input{
+		SessionID: "test-session",
+		Model:     "openai/gpt-4o",
+		Messages:  []chat.Message{{Role: chat.MessageRoleUser, Content: "deploy"}},
+	}
+
+	result, err := exec.ExecutePreModelCall(t.Context(), input)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, "Deployments need a ticket number.", result.AdditionalContext)
+	assert.Equal(t, EventPreModelCall, input.HookEventName)
+}
+
+func TestExecutePostModelCall(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{
+		PostModelCall: []Hook{
+			{Type: HookTypeCommand, Command: `echo '{"hook_specific_output":{"updated_content":"[redacted]"}}'`, Timeout: 5},
+		},
+	}
+
+	exec := NewExecutor(config, t.TempDir(), nil)
+	input := &Input{
+		SessionID: "test-session",
+		Response:  &ModelResponse{Content: "the password is hunter2"},
+	}
+
+	result, err := exec.ExecutePostModelCall(t.Context(), input)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	require.NotNil(t, result.UpdatedContent)
+	assert.Equal(t, "[redacted]", *result.UpdatedContent)
+
+	config.PostModelCall = []Hook{{Type: HookTypeCommand, Command: "echo 'no secrets' >&2; exit 2", Timeout: 5}}
+	result, err = NewExecutor(config, t.TempDir(), nil).ExecutePostModelCall(t.Context(), input)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, "no secrets", result.Message)
+}
+
 func TestExecuteHooksWithContextCancellation(t *testing.T) {
 	t.Parallel()
 
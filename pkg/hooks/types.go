@@ -1,4 +1,4 @@
-// Package hooks provides lifecycle hooks for agent tool execution.
+// Package hooks provides lifecycle hooks for agent tool execution and model calls.
 // Hooks allow users to run shell commands or prompts at various points
 // during the agent's execution lifecycle, providing deterministic control
 // over agent behavior.
@@ -7,6 +7,9 @@ package hooks
 import (
 	"encoding/json"
 	"time"
+
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/tools"
 )
 
 // EventType represents the type of hook event
@@ -21,6 +24,15 @@ const (
 	// Can provide validation, feedback, or additional processing.
 	EventPostToolUse EventType = "post_tool_use"
 
+	// PreModelCall is triggered before the agent's model is called.
+	// Can block the call, or add context to the messages sent to the model.
+	EventPreModelCall EventType = "pre_model_call"
+
+	// PostModelCall is triggered after the model answered, before its
+	// answer is added to the session and its tool calls run.
+	// Can block the answer, or replace its content.
+	EventPostModelCall EventType = "post_model_call"
+
 	// SessionStart is triggered when a session begins or resumes.
 	// Can load context, setup environment, install dependencies.
 	EventSessionStart EventType = "session_start"
@@ -76,6 +88,12 @@ type Config struct {
 	// PostToolUse hooks run after tool execution
 	PostToolUse []MatcherConfig `json:"post_tool_use,omitempty" yaml:"post_tool_use,omitempty"`
 
+	// PreModelCall hooks run before the model is called
+	PreModelCall []Hook `json:"pre_model_call,omitempty" yaml:"pre_model_call,omitempty"`
+
+	// PostModelCall hooks run after the model answered
+	PostModelCall []Hook `json:"post_model_call,omitempty" yaml:"post_model_call,omitempty"`
+
 	// SessionStart hooks run when a session begins
 	SessionStart []Hook `json:"session_start,omitempty" yaml:"session_start,omitempty"`
 
@@ -87,6 +105,8 @@ type Config struct {
 func (c *Config) IsEmpty() bool {
 	return len(c.PreToolUse) == 0 &&
 		len(c.PostToolUse) == 0 &&
+		len(c.PreModelCall) == 0 &&
+		len(c.PostModelCall) == 0 &&
 		len(c.SessionStart) == 0 &&
 		len(c.SessionEnd) == 0
 }
@@ -106,6 +126,14 @@ type Input struct {
 	// PostToolUse specific
 	ToolResponse any `json:"tool_response,omitempty"`
 
+	// Model-related fields (for PreModelCall and PostModelCall)
+	AgentName string         `json:"agent_name,omitempty"`
+	Model     string         `json:"model,omitempty"`
+	Messages  []chat.Message `json:"messages,omitempty"`
+
+	// PostModelCall specific
+	Response *ModelResponse `json:"response,omitempty"`
+
 	// SessionStart specific
 	Source string `json:"source,omitempty"` // "startup", "resume", "clear", "compact"
 
@@ -113,6 +141,12 @@ type Input struct {
 	Reason string `json:"reason,omitempty"` // "clear", "logout", "prompt_input_exit", "other"
 }
 
+// ModelResponse is the answer of a model, passed to the post-model-call hooks
+type ModelResponse struct {
+	Content   string           `json:"content"`
+	ToolCalls []tools.ToolCall `json:"tool_calls,omitempty"`
+}
+
 // ToJSON serializes the input to JSON
 func (i *Input) ToJSON() ([]byte, error) {
 	return json.Marshal(i)
@@ -179,8 +213,11 @@ type HookSpecificOutput struct {
 	PermissionDecisionReason string         `json:"permission_decision_reason,omitempty"`
 	UpdatedInput             map[string]any `json:"updated_input,omitempty"`
 
-	// PostToolUse/SessionStart fields
+	// PostToolUse/SessionStart/PreModelCall fields
 	AdditionalContext string `json:"additional_context,omitempty"`
+
+	// PostModelCall fields
+	UpdatedContent *string `json:"updated_content,omitempty"`
 }
 
 // Result represents the result of executing hooks
@@ -194,9 +231,12 @@ type Result struct {
 	// ModifiedInput contains any modifications to tool input (PreToolUse only)
 	ModifiedInput map[string]any
 
-	// AdditionalContext is context to add (PostToolUse/SessionStart)
+	// AdditionalContext is context to add (PostToolUse/SessionStart/PreModelCall)
 	AdditionalContext string
 
+	// UpdatedContent replaces the content of the model's answer (PostModelCall only)
+	UpdatedContent *string
+
 	// SystemMessage is a warning to show the user
 	SystemMessage string
 
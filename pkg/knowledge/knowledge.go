@@ -0,0 +1,206 @@
+// Package knowledge manages local knowledge bases: documents added with
+// "cagent kb add" are chunked, embedded and stored in a local vector database
+// that agents search with the search_knowledge tool.
+//
+// A knowledge base is a manifest, <name>.yaml, listing its documents and the
+// embedding model, next to its database, <name>.db, in the knowledge
+// directory.
+package knowledge
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/docker/cagent/pkg/config/latest"
+	"github.com/docker/cagent/pkg/environment"
+	"github.com/docker/cagent/pkg/paths"
+	"github.com/docker/cagent/pkg/rag"
+)
+
+const (
+	// DefaultName is the knowledge base documents are added to, and agents
+	// search, when none is named.
+	DefaultName = "default"
+	// DefaultModel is the embedding model of new knowledge bases.
+	DefaultModel = "openai/text-embedding-3-small"
+	// DefaultDimensions is the number of dimensions of the vectors of
+	// DefaultModel.
+	DefaultDimensions = 1536
+	// ToolName is the name of the tool searching a knowledge base.
+	ToolName = "search_knowledge"
+)
+
+var validName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// KnowledgeBase is a set of documents indexed with an embedding model.
+type KnowledgeBase struct {
+	Name string `yaml:"-"`
+	// Docs are the absolute paths of the files and directories indexed
+	Docs []string `yaml:"docs"`
+	// Model is the embedding model, as provider/model
+	Model string `yaml:"model"`
+	// Dimensions is the number of dimensions of the vectors of the model
+	Dimensions int `yaml:"dimensions"`
+
+	dir string
+}
+
+// Dir returns the directory of the knowledge bases of the user.
+func Dir() string {
+	return filepath.Join(paths.GetDataDir(), "knowledge")
+}
+
+// Load reads a knowledge base of a directory. It returns an error wrapping
+// os.ErrNotExist if there's none with that name.
+func Load(dir, name string) (*KnowledgeBase, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, name+".yaml"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("knowledge base %s not found, add documents to it with \"cagent kb add\": %w", name, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	kb := &KnowledgeBase{Name: name, dir: dir}
+	if err := yaml.Unmarshal(data, kb); err != nil {
+		return nil, fmt.Errorf("reading knowledge base %s: %w", name, err)
+	}
+	return kb, nil
+}
+
+// LoadOrNew reads a knowledge base of a directory, or returns a new one with
+// the default embedding model if there's none with that name.
+func LoadOrNew(dir, name string) (*KnowledgeBase, error) {
+	kb, err := Load(dir, name)
+	if errors.Is(err, os.ErrNotExist) {
+		return &KnowledgeBase{Name: name, Model: DefaultModel, Dimensions: DefaultDimensions, dir: dir}, nil
+	}
+	return kb, err
+}
+
+// List returns the knowledge bases of a directory, sorted by name.
+func List(dir string) ([]*KnowledgeBase, error) {
+	manifests, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+
+	var kbs []*KnowledgeBase
+	for _, manifest := range manifests {
+		kb, err := Load(dir, strings.TrimSuffix(filepath.Base(manifest), ".yaml"))
+		if err != nil {
+			return nil, err
+		}
+		kbs = append(kbs, kb)
+	}
+	return kbs, nil
+}
+
+// Remove deletes a knowledge base and its database. The indexed documents
+// are left untouched.
+func Remove(dir, name string) error {
+	kb, err := Load(dir, name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(kb.DatabasePath()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return os.Remove(filepath.Join(dir, name+".yaml"))
+}
+
+// ValidateName returns an error if name can't name a knowledge base.
+func ValidateName(name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("invalid knowledge base name %q: use letters, digits, '.', '_' and '-'", name)
+	}
+	return nil
+}
+
+// AddDocs adds files and directories to the knowledge base, relative to the
+// working directory. The ones it already has are skipped.
+func (kb *KnowledgeBase) AddDocs(docs ...string) error {
+	for _, doc := range docs {
+		abs, err := filepath.Abs(doc)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(abs); err != nil {
+			return err
+		}
+		if !slices.Contains(kb.Docs, abs) {
+			kb.Docs = append(kb.Docs, abs)
+		}
+	}
+	return nil
+}
+
+// Save writes the manifest of the knowledge base.
+func (kb *KnowledgeBase) Save() error {
+	if err := os.MkdirAll(kb.dir, 0o700); err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(kb)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(kb.dir, kb.Name+".yaml"), data, 0o600)
+}
+
+// DatabasePath returns the path of the vector database of the knowledge base.
+func (kb *KnowledgeBase) DatabasePath() string {
+	return filepath.Join(kb.dir, kb.Name+".db")
+}
+
+// RAGConfig returns the configuration of the RAG source searching the
+// knowledge base: its chunks are embedded with its model and found by
+// similarity.
+func (kb *KnowledgeBase) RAGConfig() latest.RAGConfig {
+	return latest.RAGConfig{
+		Tool: latest.RAGToolConfig{
+			Name: ToolName,
+			Description: fmt.Sprintf("Search the %s knowledge base, made of local documents, for the passages relevant to a query. "+
+				"Provide a natural language query describing what you need. "+
+				"Returns the most relevant document chunks with file paths.", kb.Name),
+			Instruction: fmt.Sprintf("Use the %s tool to look for the answers to the questions about the documents of the %s knowledge base before answering them. "+
+				"Quote the paths of the documents you used.", ToolName, kb.Name),
+		},
+		Docs: kb.Docs,
+		Strategies: []latest.RAGStrategyConfig{{
+			Type:     "chunked-embeddings",
+			Database: latest.RAGDatabasePath(kb.DatabasePath()),
+			Chunking: latest.RAGChunkingConfig{Size: 1000, Overlap: 100, RespectWordBoundaries: true},
+			Params: map[string]any{
+				"embedding_model":   cmp.Or(kb.Model, DefaultModel),
+				"vector_dimensions": cmp.Or(kb.Dimensions, DefaultDimensions),
+			},
+		}},
+	}
+}
+
+// NewManager returns the RAG manager indexing and searching the knowledge
+// base. The embedding model reads its credentials from env.
+func (kb *KnowledgeBase) NewManager(ctx context.Context, env environment.Provider, modelsGateway string) (*rag.Manager, error) {
+	managers, err := rag.NewManagers(ctx, &latest.Config{RAG: map[string]latest.RAGConfig{kb.Name: kb.RAGConfig()}}, rag.ManagersBuildConfig{
+		ParentDir:     kb.dir,
+		ModelsGateway: modelsGateway,
+		Env:           env,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return managers[kb.Name], nil
+}
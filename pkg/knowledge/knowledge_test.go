@@ -0,0 +1,81 @@
+package knowledge
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKnowledgeBase_SaveAndLoad(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	docs := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(docs, "guide.md"), []byte("# Guide"), 0o644))
+
+	kb, err := LoadOrNew(dir, "docs")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultModel, kb.Model)
+	assert.Equal(t, DefaultDimensions, kb.Dimensions)
+
+	require.NoError(t, kb.AddDocs(docs, filepath.Join(docs, "guide.md"), docs))
+	require.NoError(t, kb.Save())
+
+	loaded, err := Load(dir, "docs")
+	require.NoError(t, err)
+	assert.Equal(t, "docs", loaded.Name)
+	assert.Equal(t, []string{docs, filepath.Join(docs, "guide.md")}, loaded.Docs)
+	assert.Equal(t, filepath.Join(dir, "docs.db"), loaded.DatabasePath())
+
+	kbs, err := List(dir)
+	require.NoError(t, err)
+	require.Len(t, kbs, 1)
+	assert.Equal(t, "docs", kbs[0].Name)
+
+	require.NoError(t, Remove(dir, "docs"))
+	_, err = Load(dir, "docs")
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestKnowledgeBase_AddMissingDocs(t *testing.T) {
+	t.Parallel()
+
+	kb, err := LoadOrNew(t.TempDir(), DefaultName)
+	require.NoError(t, err)
+
+	require.Error(t, kb.AddDocs(filepath.Join(t.TempDir(), "missing")))
+}
+
+func TestValidateName(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, ValidateName("default"))
+	require.NoError(t, ValidateName("team-docs_v1.2"))
+	require.Error(t, ValidateName(""))
+	require.Error(t, ValidateName("../docs"))
+	require.Error(t, ValidateName(".hidden"))
+}
+
+func TestKnowledgeBase_RAGConfig(t *testing.T) {
+	t.Parallel()
+
+	kb, err := LoadOrNew(t.TempDir(), "docs")
+	require.NoError(t, err)
+	kb.Docs = []string{"/docs"}
+
+	cfg := kb.RAGConfig()
+	assert.Equal(t, ToolName, cfg.Tool.Name)
+	assert.Equal(t, []string{"/docs"}, cfg.Docs)
+	require.Len(t, cfg.Strategies, 1)
+
+	strategy := cfg.Strategies[0]
+	assert.Equal(t, "chunked-embeddings", strategy.Type)
+	assert.Equal(t, DefaultModel, strategy.Params["embedding_model"])
+	assert.Equal(t, DefaultDimensions, strategy.Params["vector_dimensions"])
+	database, err := strategy.Database.AsString()
+	require.NoError(t, err)
+	assert.Equal(t, kb.DatabasePath(), database)
+}
@@ -0,0 +1,201 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRingSize is the number of log records kept in memory.
+const DefaultRingSize = 1000
+
+// Entry is a log record kept in memory.
+type Entry struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	// Attrs are the attributes of the record, formatted as key=value pairs
+	Attrs string
+}
+
+// Ring keeps the latest log records in memory, so they can be shown without
+// writing them to a file.
+type Ring struct {
+	mu      sync.Mutex
+	entries []Entry
+	next    int
+	full    bool
+	written uint64
+}
+
+// NewRing creates a ring keeping the latest size records.
+func NewRing(size int) *Ring {
+	return &Ring{entries: make([]Entry, max(1, size))}
+}
+
+var recent = NewRing(DefaultRingSize)
+
+// Recent returns the ring the logs of this process are kept in.
+func Recent() *Ring {
+	return recent
+}
+
+func (r *Ring) add(entry Entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries[r.next] = entry
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+	r.written++
+}
+
+// Entries returns the records kept at minLevel or above, oldest first.
+func (r *Ring) Entries(minLevel slog.Level) []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var ordered []Entry
+	if r.full {
+		ordered = append(ordered, r.entries[r.next:]...)
+	}
+	ordered = append(ordered, r.entries[:r.next]...)
+
+	var entries []Entry
+	for _, entry := range ordered {
+		if entry.Level >= minLevel {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// Written returns the number of records written so far, to tell whether new
+// ones arrived.
+func (r *Ring) Written() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.written
+}
+
+// Handler returns a handler writing the records at level or above to the
+// ring.
+func (r *Ring) Handler(level slog.Leveler) slog.Handler {
+	return &ringHandler{ring: r, level: level}
+}
+
+type ringHandler struct {
+	ring  *Ring
+	level slog.Leveler
+	// attrs are the attributes added with WithAttrs, already formatted
+	attrs  []string
+	groups []string
+}
+
+func (h *ringHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *ringHandler) Handle(_ context.Context, record slog.Record) error {
+	attrs := append([]string{}, h.attrs...)
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs = appendAttr(attrs, h.groups, attr)
+		return true
+	})
+
+	h.ring.add(Entry{
+		Time:    record.Time,
+		Level:   record.Level,
+		Message: record.Message,
+		Attrs:   strings.Join(attrs, " "),
+	})
+	return nil
+}
+
+func (h *ringHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append([]string{}, h.attrs...)
+	for _, attr := range attrs {
+		clone.attrs = appendAttr(clone.attrs, h.groups, attr)
+	}
+	return &clone
+}
+
+func (h *ringHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.groups = append(append([]string{}, h.groups...), name)
+	return &clone
+}
+
+// appendAttr formats an attribute as key=value, its key prefixed by the
+// groups it is in.
+func appendAttr(attrs, groups []string, attr slog.Attr) []string {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return attrs
+	}
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			groups = append(append([]string{}, groups...), attr.Key)
+		}
+		for _, member := range attr.Value.Group() {
+			attrs = appendAttr(attrs, groups, member)
+		}
+		return attrs
+	}
+
+	key := strings.Join(append(append([]string{}, groups...), attr.Key), ".")
+	return append(attrs, key+"="+attr.Value.String())
+}
+
+// Tee returns a handler writing the records to all the handlers.
+func Tee(handlers ...slog.Handler) slog.Handler {
+	return teeHandler(handlers)
+}
+
+type teeHandler []slog.Handler
+
+func (t teeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range t {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t teeHandler) Handle(ctx context.Context, record slog.Record) error {
+	var firstErr error
+	for _, h := range t {
+		if !h.Enabled(ctx, record.Level) {
+			continue
+		}
+		if err := h.Handle(ctx, record.Clone()); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (t teeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make(teeHandler, len(t))
+	for i, h := range t {
+		handlers[i] = h.WithAttrs(attrs)
+	}
+	return handlers
+}
+
+func (t teeHandler) WithGroup(name string) slog.Handler {
+	handlers := make(teeHandler, len(t))
+	for i, h := range t {
+		handlers[i] = h.WithGroup(name)
+	}
+	return handlers
+}
@@ -0,0 +1,48 @@
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRing(t *testing.T) {
+	ring := NewRing(3)
+	logger := slog.New(ring.Handler(slog.LevelDebug))
+
+	logger.Debug("connecting", "server", "github")
+	logger.Info("connected")
+	logger.With("provider", "openai").WithGroup("http").Warn("retrying", "status", 429)
+	logger.Error("failed", "error", "timeout")
+
+	// The oldest record was dropped
+	entries := ring.Entries(slog.LevelDebug)
+	require.Len(t, entries, 3)
+	assert.Equal(t, "connected", entries[0].Message)
+	assert.Equal(t, "provider=openai http.status=429", entries[1].Attrs)
+	assert.Equal(t, "failed", entries[2].Message)
+	assert.Equal(t, uint64(4), ring.Written())
+
+	warnings := ring.Entries(slog.LevelWarn)
+	require.Len(t, warnings, 2)
+	assert.Equal(t, slog.LevelWarn, warnings[0].Level)
+}
+
+func TestTee(t *testing.T) {
+	var buf bytes.Buffer
+	ring := NewRing(10)
+	logger := slog.New(Tee(
+		slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}),
+		ring.Handler(slog.LevelDebug),
+	))
+
+	logger.Debug("handshake", "server", "fetch")
+	logger.Info("ready")
+
+	assert.NotContains(t, buf.String(), "handshake")
+	assert.Contains(t, buf.String(), "ready")
+	assert.Len(t, ring.Entries(slog.LevelDebug), 2)
+}
@@ -0,0 +1,49 @@
+package mcp
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// progressReporter tells the client what the agent is doing, when the client
+// asked to be notified of the progress of the tool call.
+type progressReporter struct {
+	session *mcp.ServerSession
+	token   any
+	count   float64
+}
+
+// newProgressReporter returns nil when the client didn't ask for progress.
+func newProgressReporter(req *mcp.CallToolRequest) *progressReporter {
+	if req == nil || req.Session == nil || req.Params == nil {
+		return nil
+	}
+
+	token := req.Params.GetProgressToken()
+	if token == nil {
+		return nil
+	}
+
+	return &progressReporter{
+		session: req.Session,
+		token:   token,
+	}
+}
+
+func (p *progressReporter) report(ctx context.Context, message string) {
+	if p == nil {
+		return
+	}
+
+	// The total is unknown: progress only increases
+	p.count++
+	if err := p.session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+		ProgressToken: p.token,
+		Progress:      p.count,
+		Message:       message,
+	}); err != nil {
+		slog.Debug("Failed to notify the progress of the tool call", "error", err)
+	}
+}
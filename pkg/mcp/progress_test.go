@@ -0,0 +1,83 @@
+package mcp
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// callWithProgress calls a tool reporting two steps, and returns the
+// progress notifications received by the client.
+func callWithProgress(t *testing.T, progressToken any) []*mcp.ProgressNotificationParams {
+	t.Helper()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "root"}, func(ctx context.Context, req *mcp.CallToolRequest, _ ToolInput) (*mcp.CallToolResult, ToolOutput, error) {
+		progress := newProgressReporter(req)
+		progress.report(ctx, "root is calling shell")
+		progress.report(ctx, "root is calling read_file")
+		return nil, ToolOutput{Response: "done"}, nil
+	})
+
+	var (
+		mu       sync.Mutex
+		received []*mcp.ProgressNotificationParams
+	)
+	client := mcp.NewClient(&mcp.Implementation{Name: "client"}, &mcp.ClientOptions{
+		ProgressNotificationHandler: func(_ context.Context, req *mcp.ProgressNotificationClientRequest) {
+			mu.Lock()
+			defer mu.Unlock()
+			received = append(received, req.Params)
+		},
+	})
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(t.Context(), serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = serverSession.Close() })
+	clientSession, err := client.Connect(t.Context(), clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = clientSession.Close() })
+
+	params := &mcp.CallToolParams{Name: "root", Arguments: map[string]any{"message": "hello"}}
+	if progressToken != nil {
+		params.Meta = mcp.Meta{"progressToken": progressToken}
+	}
+	_, err = clientSession.CallTool(t.Context(), params)
+	require.NoError(t, err)
+
+	// Notifications are delivered asynchronously
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return progressToken == nil || len(received) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	return received
+}
+
+func TestProgressReporter(t *testing.T) {
+	t.Parallel()
+
+	received := callWithProgress(t, "call-1")
+
+	require.Len(t, received, 2)
+	assert.Equal(t, "root is calling shell", received[0].Message)
+	assert.InDelta(t, 1, received[0].Progress, 0)
+	assert.Equal(t, "root is calling read_file", received[1].Message)
+	assert.InDelta(t, 2, received[1].Progress, 0)
+}
+
+func TestProgressReporter_NotRequested(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, callWithProgress(t, nil))
+	assert.Nil(t, newProgressReporter(nil))
+}
@@ -3,6 +3,7 @@ package mcp
 import (
 	"cmp"
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/docker/cagent/pkg/agent"
 	"github.com/docker/cagent/pkg/config"
+	"github.com/docker/cagent/pkg/disclosure"
 	"github.com/docker/cagent/pkg/runtime"
 	"github.com/docker/cagent/pkg/session"
 	"github.com/docker/cagent/pkg/team"
@@ -141,7 +143,7 @@ func createMCPServer(ctx context.Context, agentFilename, agentName string, runCo
 			OutputSchema: tools.MustSchemaFor[ToolOutput](),
 		}
 
-		mcp.AddTool(server, toolDef, CreateToolHandler(t, agentName))
+		mcp.AddTool(server, toolDef, withDisclosure(CreateToolHandler(t, agentName), &runConfig.Disclosure, agentName))
 	}
 
 	return server, cleanup, nil
@@ -170,10 +172,19 @@ func CreateToolHandler(t *team.Team, agentName string) func(context.Context, *mc
 			return nil, ToolOutput{}, fmt.Errorf("failed to create runtime: %w", err)
 		}
 
-		_, err = rt.Run(ctx, sess)
-		if err != nil {
-			slog.Error("Agent execution failed", "agent", agentName, "error", err)
-			return nil, ToolOutput{}, fmt.Errorf("agent execution failed: %w", err)
+		progress := newProgressReporter(req)
+		var runErr error
+		for event := range rt.RunStream(ctx, sess) {
+			switch event := event.(type) {
+			case *runtime.ErrorEvent:
+				runErr = cmp.Or(runErr, errors.New(event.Error))
+			case *runtime.ToolCallEvent:
+				progress.report(ctx, fmt.Sprintf("%s is calling %s", event.AgentName, event.ToolCall.Function.Name))
+			}
+		}
+		if runErr != nil {
+			slog.Error("Agent execution failed", "agent", agentName, "error", runErr)
+			return nil, ToolOutput{}, fmt.Errorf("agent execution failed: %w", runErr)
 		}
 
 		result := cmp.Or(sess.GetLastAssistantMessageContent(), "No response from agent")
@@ -184,6 +195,23 @@ func CreateToolHandler(t *team.Team, agentName string) func(context.Context, *mc
 	}
 }
 
+// withDisclosure appends the disclosure footer to the responses of a tool handler.
+func withDisclosure(handler func(context.Context, *mcp.CallToolRequest, ToolInput) (*mcp.CallToolResult, ToolOutput, error), footer *disclosure.Footer, agentName string) func(context.Context, *mcp.CallToolRequest, ToolInput) (*mcp.CallToolResult, ToolOutput, error) {
+	if !footer.Enabled() {
+		return handler
+	}
+
+	return func(ctx context.Context, req *mcp.CallToolRequest, input ToolInput) (*mcp.CallToolResult, ToolOutput, error) {
+		result, output, err := handler(ctx, req, input)
+		if err != nil {
+			return result, output, err
+		}
+
+		output.Response = footer.Apply(output.Response, agentName)
+		return result, output, nil
+	}
+}
+
 func isReadOnlyAgent(ctx context.Context, ag *agent.Agent) (bool, error) {
 	allTools, err := ag.Tools(ctx)
 	if err != nil {
@@ -11,6 +11,9 @@ type UserMemory struct {
 	ID        string `description:"The ID of the memory"`
 	CreatedAt string `description:"The creation timestamp of the memory"`
 	Memory    string `description:"The content of the memory"`
+	// Scope keeps the memories of an agent, or of a project, apart from the
+	// others. Memories without a scope are shared.
+	Scope string `description:"The scope of the memory"`
 }
 
 type Database interface {
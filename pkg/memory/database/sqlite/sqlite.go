@@ -20,11 +20,13 @@ func NewMemoryDatabase(path string) (database.Database, error) {
 	// Ensure we close the connection if table creation fails
 	// Note: We don't defer close here because we return the db on success
 
-	_, err = db.ExecContext(context.Background(), "CREATE TABLE IF NOT EXISTS memories (id TEXT PRIMARY KEY, created_at TEXT, memory TEXT)")
+	_, err = db.ExecContext(context.Background(), "CREATE TABLE IF NOT EXISTS memories (id TEXT PRIMARY KEY, created_at TEXT, memory TEXT, scope TEXT NOT NULL DEFAULT '')")
 	if err != nil {
 		db.Close()
 		return nil, err
 	}
+	// Databases created before memories had a scope don't have the column
+	_, _ = db.ExecContext(context.Background(), "ALTER TABLE memories ADD COLUMN scope TEXT NOT NULL DEFAULT ''")
 
 	return &MemoryDatabase{db: db}, nil
 }
@@ -33,13 +35,13 @@ func (m *MemoryDatabase) AddMemory(ctx context.Context, memory database.UserMemo
 	if memory.ID == "" {
 		return database.ErrEmptyID
 	}
-	_, err := m.db.ExecContext(ctx, "INSERT INTO memories (id, created_at, memory) VALUES (?, ?, ?)",
-		memory.ID, memory.CreatedAt, memory.Memory)
+	_, err := m.db.ExecContext(ctx, "INSERT INTO memories (id, created_at, memory, scope) VALUES (?, ?, ?, ?)",
+		memory.ID, memory.CreatedAt, memory.Memory, memory.Scope)
 	return err
 }
 
 func (m *MemoryDatabase) GetMemories(ctx context.Context) ([]database.UserMemory, error) {
-	rows, err := m.db.QueryContext(ctx, "SELECT id, created_at, memory FROM memories")
+	rows, err := m.db.QueryContext(ctx, "SELECT id, created_at, memory, scope FROM memories")
 	if err != nil {
 		return nil, err
 	}
@@ -48,7 +50,7 @@ func (m *MemoryDatabase) GetMemories(ctx context.Context) ([]database.UserMemory
 	var memories []database.UserMemory
 	for rows.Next() {
 		var memory database.UserMemory
-		err := rows.Scan(&memory.ID, &memory.CreatedAt, &memory.Memory)
+		err := rows.Scan(&memory.ID, &memory.CreatedAt, &memory.Memory, &memory.Scope)
 		if err != nil {
 			return nil, err
 		}
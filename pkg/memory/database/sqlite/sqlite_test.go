@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/docker/cagent/pkg/memory/database"
+	"github.com/docker/cagent/pkg/sqliteutil"
 )
 
 func setupTestDB(t *testing.T) database.Database {
@@ -192,3 +193,38 @@ func TestDatabaseWithMultipleInstances(t *testing.T) {
 	assert.Equal(t, "shared-id", memories[0].ID)
 	assert.Equal(t, "Shared memory", memories[0].Memory)
 }
+
+func TestScope(t *testing.T) {
+	db := setupTestDB(t)
+
+	err := db.AddMemory(t.Context(), database.UserMemory{ID: "1", Memory: "Uses tabs", Scope: "project:/src/app"})
+	require.NoError(t, err)
+
+	memories, err := db.GetMemories(t.Context())
+	require.NoError(t, err)
+	require.Len(t, memories, 1)
+	assert.Equal(t, "project:/src/app", memories[0].Scope)
+}
+
+func TestMemoriesWithoutScope(t *testing.T) {
+	tmpFile := t.TempDir() + "/old.db"
+
+	// A database created before memories had a scope
+	old, err := sqliteutil.OpenDB(tmpFile)
+	require.NoError(t, err)
+	_, err = old.ExecContext(t.Context(), "CREATE TABLE memories (id TEXT PRIMARY KEY, created_at TEXT, memory TEXT)")
+	require.NoError(t, err)
+	_, err = old.ExecContext(t.Context(), "INSERT INTO memories (id, created_at, memory) VALUES ('1', '', 'Likes Go')")
+	require.NoError(t, err)
+	require.NoError(t, old.Close())
+
+	db, err := NewMemoryDatabase(tmpFile)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.(*MemoryDatabase).db.Close() })
+
+	memories, err := db.GetMemories(t.Context())
+	require.NoError(t, err)
+	require.Len(t, memories, 1)
+	assert.Equal(t, "Likes Go", memories[0].Memory)
+	assert.Empty(t, memories[0].Scope)
+}
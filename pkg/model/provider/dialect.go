@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"github.com/docker/cagent/pkg/tools"
+)
+
+// isToolNameChar reports whether r is allowed in a tool name by OpenAI, Anthropic and Bedrock.
+func isToolNameChar(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' || r == '-'
+}
+
+// isGeminiToolNameChar reports whether r is allowed in a Gemini function name.
+func isGeminiToolNameChar(r rune) bool {
+	return isToolNameChar(r) || r == '.' || r == ':'
+}
+
+// schemaDialects lists, per provider type, what is accepted in tool definitions.
+var schemaDialects = map[string]tools.SchemaDialect{
+	"openai": {
+		Name:                  "openai",
+		MaxNameLength:         64,
+		NameChars:             isToolNameChar,
+		NoTopLevelCombinators: true,
+	},
+	"anthropic": {
+		Name:                  "anthropic",
+		MaxNameLength:         64,
+		NameChars:             isToolNameChar,
+		NoTopLevelCombinators: true,
+	},
+	"amazon-bedrock": {
+		Name:                  "amazon-bedrock",
+		MaxNameLength:         64,
+		NameChars:             isToolNameChar,
+		NoTopLevelCombinators: true,
+	},
+	"google": {
+		Name:                    "google",
+		MaxNameLength:           64,
+		NameChars:               isGeminiToolNameChar,
+		NameMustStartWithLetter: true,
+		// Keywords without an equivalent in Gemini's schema. Harmless ones,
+		// like $schema or additionalProperties, are silently dropped by the client.
+		UnsupportedKeywords: []string{
+			"$ref", "$defs", "definitions", "patternProperties", "const", "not", "if", "then", "else",
+		},
+		StringFormats:         []string{"enum", "date-time"},
+		NoOneOf:               true,
+		NoAllOf:               true,
+		NoTopLevelCombinators: true,
+	},
+	"dmr": {
+		Name:                  "dmr",
+		MaxNameLength:         64,
+		NameChars:             isToolNameChar,
+		NoTopLevelCombinators: true,
+	},
+}
+
+// SchemaDialect returns the tool definition dialect accepted by a provider.
+// Unknown providers get an empty dialect, which leaves tools untouched.
+func SchemaDialect(p Provider) tools.SchemaDialect {
+	cfg := p.BaseConfig().ModelConfig
+	switch providerType := resolveProviderTypeFromConfig(&cfg); providerType {
+	case "openai_chatcompletions", "openai_responses":
+		return schemaDialects["openai"]
+	default:
+		return schemaDialects[providerType]
+	}
+}
@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/docker/cagent/pkg/config/latest"
+	"github.com/docker/cagent/pkg/model/provider/base"
+	"github.com/docker/cagent/pkg/tools"
+)
+
+type dialectProvider struct {
+	Provider
+	cfg latest.ModelConfig
+}
+
+func (p *dialectProvider) BaseConfig() base.Config {
+	return base.Config{ModelConfig: p.cfg}
+}
+
+func TestSchemaDialect(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		cfg      latest.ModelConfig
+		expected string
+	}{
+		{cfg: latest.ModelConfig{Provider: "openai"}, expected: "openai"},
+		{cfg: latest.ModelConfig{Provider: "mistral"}, expected: "openai"},
+		{cfg: latest.ModelConfig{Provider: "custom", ProviderOpts: map[string]any{"api_type": "openai_responses"}}, expected: "openai"},
+		{cfg: latest.ModelConfig{Provider: "anthropic"}, expected: "anthropic"},
+		{cfg: latest.ModelConfig{Provider: "google"}, expected: "google"},
+		{cfg: latest.ModelConfig{Provider: "amazon-bedrock"}, expected: "amazon-bedrock"},
+		{cfg: latest.ModelConfig{Provider: "unknown"}, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cfg.Provider, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, SchemaDialect(&dialectProvider{cfg: tt.cfg}).Name)
+		})
+	}
+}
+
+func TestGeminiDialectToolNames(t *testing.T) {
+	t.Parallel()
+
+	dialect := SchemaDialect(&dialectProvider{cfg: latest.ModelConfig{Provider: "google"}})
+	normalized, _ := tools.NormalizeTools([]tools.Tool{{Name: "github.list_issues"}, {Name: "2fa-code"}}, dialect)
+
+	assert.Equal(t, "github.list_issues", normalized[0].Name)
+	assert.Equal(t, "_2fa-code", normalized[1].Name)
+
+	dialect = SchemaDialect(&dialectProvider{cfg: latest.ModelConfig{Provider: "anthropic"}})
+	normalized, _ = tools.NormalizeTools([]tools.Tool{{Name: "github.list_issues"}}, dialect)
+	assert.Equal(t, "github_list_issues", normalized[0].Name)
+}
@@ -169,12 +169,7 @@ func (g *StreamAdapter) Recv() (chat.MessageStreamResponse, error) {
 
 		// Handle token usage if present
 		if res.resp.UsageMetadata != nil && g.trackUsage {
-			resp.Usage = &chat.Usage{
-				InputTokens:       int64(res.resp.UsageMetadata.PromptTokenCount),
-				OutputTokens:      int64(res.resp.UsageMetadata.CandidatesTokenCount),
-				CachedInputTokens: int64(res.resp.UsageMetadata.CachedContentTokenCount),
-				ReasoningTokens:   int64(res.resp.UsageMetadata.ThoughtsTokenCount),
-			}
+			resp.Usage = convertUsage(res.resp.UsageMetadata)
 		}
 
 		// Handle text and thoughts separately so TUI can render them distinctly
@@ -313,3 +308,16 @@ func extractTextViaRegex(errMsg string) string {
 	textContent = strings.ReplaceAll(textContent, `\t`, "\t")
 	return textContent
 }
+
+// convertUsage maps the usage of Gemini to the one of the other providers:
+// the prompt count of Gemini includes the cached tokens, that are billed
+// apart, and its candidates count leaves out the thoughts, that are billed
+// as output.
+func convertUsage(usage *genai.GenerateContentResponseUsageMetadata) *chat.Usage {
+	return &chat.Usage{
+		InputTokens:       int64(usage.PromptTokenCount - usage.CachedContentTokenCount + usage.ToolUsePromptTokenCount),
+		OutputTokens:      int64(usage.CandidatesTokenCount + usage.ThoughtsTokenCount),
+		CachedInputTokens: int64(usage.CachedContentTokenCount),
+		ReasoningTokens:   int64(usage.ThoughtsTokenCount),
+	}
+}
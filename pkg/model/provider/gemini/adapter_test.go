@@ -54,3 +54,32 @@ func TestStreamAdapter_FunctionCalls(t *testing.T) {
 		require.Empty(t, finalResp.Choices[0].Delta.ToolCalls)
 	})
 }
+
+func TestStreamAdapter_Usage(t *testing.T) {
+	iter := func(fn func(*genai.GenerateContentResponse, error) bool) {
+		fn(&genai.GenerateContentResponse{
+			Candidates: []*genai.Candidate{
+				{Content: &genai.Content{Parts: []*genai.Part{{Text: "Hello"}}}},
+			},
+			UsageMetadata: &genai.GenerateContentResponseUsageMetadata{
+				PromptTokenCount:        1000,
+				CachedContentTokenCount: 800,
+				ToolUsePromptTokenCount: 50,
+				CandidatesTokenCount:    100,
+				ThoughtsTokenCount:      30,
+			},
+		}, nil)
+	}
+
+	adapter := NewStreamAdapter(iter, "gemini-2.0-flash", true)
+
+	resp, err := adapter.Recv()
+	require.NoError(t, err)
+	// The cached tokens are billed apart, the thoughts as output
+	require.Equal(t, &chat.Usage{
+		InputTokens:       250,
+		OutputTokens:      130,
+		CachedInputTokens: 800,
+		ReasoningTokens:   30,
+	}, resp.Usage)
+}
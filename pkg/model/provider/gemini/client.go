@@ -168,8 +168,8 @@ func convertMessagesToGemini(messages []chat.Message) []*genai.Content {
 	for i := range messages {
 		msg := &messages[i]
 
-		// Skip empty messages
-		if msg.Content == "" && len(msg.MultiContent) == 0 && len(msg.ToolCalls) == 0 && msg.ToolCallID == "" {
+		// Skip empty messages, and system messages which are sent as the system instruction
+		if msg.Role == chat.MessageRoleSystem || (msg.Content == "" && len(msg.MultiContent) == 0 && len(msg.ToolCalls) == 0 && msg.ToolCallID == "") {
 			continue
 		}
 
@@ -224,6 +224,35 @@ func convertMessagesToGemini(messages []chat.Message) []*genai.Content {
 	return contents
 }
 
+// extractSystemInstruction converts any system-role messages into the Gemini
+// system instruction, or returns nil when there are none.
+func extractSystemInstruction(messages []chat.Message) *genai.Content {
+	var parts []*genai.Part
+	for i := range messages {
+		msg := &messages[i]
+		if msg.Role != chat.MessageRoleSystem {
+			continue
+		}
+
+		if len(msg.MultiContent) > 0 {
+			for _, part := range msg.MultiContent {
+				if part.Type == chat.MessagePartTypeText {
+					if txt := strings.TrimSpace(part.Text); txt != "" {
+						parts = append(parts, genai.NewPartFromText(txt))
+					}
+				}
+			}
+		} else if txt := strings.TrimSpace(msg.Content); txt != "" {
+			parts = append(parts, genai.NewPartFromText(txt))
+		}
+	}
+
+	if len(parts) == 0 {
+		return nil
+	}
+	return genai.NewContentFromParts(parts, genai.RoleUser)
+}
+
 // messageRoleToGemini converts chat.MessageRole to genai.Role
 func messageRoleToGemini(role chat.MessageRole) genai.Role {
 	if role == chat.MessageRoleAssistant {
@@ -457,6 +486,7 @@ func (c *Client) CreateChatCompletionStream(
 		}
 	}
 
+	config.SystemInstruction = extractSystemInstruction(messages)
 	contents := convertMessagesToGemini(messages)
 
 	// Debug: Log the messages we're sending
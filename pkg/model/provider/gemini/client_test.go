@@ -0,0 +1,38 @@
+package gemini
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genai"
+
+	"github.com/docker/cagent/pkg/chat"
+)
+
+func TestSystemMessagesAreSentAsSystemInstruction(t *testing.T) {
+	messages := []chat.Message{
+		{Role: chat.MessageRoleSystem, Content: "You are a helpful agent."},
+		{Role: chat.MessageRoleSystem, MultiContent: []chat.MessagePart{
+			{Type: chat.MessagePartTypeText, Text: "Be brief."},
+		}},
+		{Role: chat.MessageRoleUser, Content: "Hello"},
+		{Role: chat.MessageRoleAssistant, Content: "Hi!"},
+	}
+
+	instruction := extractSystemInstruction(messages)
+	require.NotNil(t, instruction)
+	require.Len(t, instruction.Parts, 2)
+	assert.Equal(t, "You are a helpful agent.", instruction.Parts[0].Text)
+	assert.Equal(t, "Be brief.", instruction.Parts[1].Text)
+
+	contents := convertMessagesToGemini(messages)
+	require.Len(t, contents, 2)
+	assert.Equal(t, genai.RoleUser, contents[0].Role)
+	assert.Equal(t, "Hello", contents[0].Parts[0].Text)
+	assert.Equal(t, genai.RoleModel, contents[1].Role)
+}
+
+func TestNoSystemInstruction(t *testing.T) {
+	assert.Nil(t, extractSystemInstruction([]chat.Message{{Role: chat.MessageRoleUser, Content: "Hello"}}))
+}
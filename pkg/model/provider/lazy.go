@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/config/latest"
+	"github.com/docker/cagent/pkg/environment"
+	"github.com/docker/cagent/pkg/model/provider/base"
+	"github.com/docker/cagent/pkg/model/provider/options"
+	"github.com/docker/cagent/pkg/tools"
+)
+
+// lazyProvider defers the creation of a provider until its first request.
+// Creating some clients is slow (Docker Model Runner queries and configures
+// the model, Bedrock loads the AWS configuration...) and an agent's models
+// might not all be used, e.g. fallback models or the models of sub-agents.
+type lazyProvider struct {
+	cfg    latest.ModelConfig
+	models map[string]latest.ModelConfig
+	env    environment.Provider
+	opts   []options.Opt
+
+	// config is what BaseConfig returns until the provider is created
+	config base.Config
+
+	// createMu makes concurrent first requests create the provider once
+	createMu sync.Mutex
+	mu       sync.RWMutex
+	provider Provider
+}
+
+// NewLazy returns a provider that is created, as NewWithModels would, on
+// its first request. Only the provider type is checked right away: other
+// creation errors are returned by the first request, and creating the
+// provider is retried on the next one.
+func NewLazy(cfg *latest.ModelConfig, models map[string]latest.ModelConfig, env environment.Provider, opts ...options.Opt) (Provider, error) {
+	var globalOptions options.ModelOptions
+	for _, opt := range opts {
+		opt(&globalOptions)
+	}
+
+	enhancedCfg := applyProviderDefaults(cfg, globalOptions.Providers())
+	if providerType := resolveProviderTypeFromConfig(enhancedCfg); len(cfg.Routing) == 0 && !knownProviderTypes[providerType] {
+		return nil, fmt.Errorf("unknown provider type: %s", providerType)
+	}
+
+	return &lazyProvider{
+		cfg:    *cfg,
+		models: models,
+		env:    env,
+		opts:   opts,
+		config: base.Config{
+			ModelConfig:  *enhancedCfg,
+			ModelOptions: globalOptions,
+			Env:          env,
+		},
+	}, nil
+}
+
+func (p *lazyProvider) ID() string {
+	return p.config.ID()
+}
+
+func (p *lazyProvider) BaseConfig() base.Config {
+	if provider := p.created(); provider != nil {
+		return provider.BaseConfig()
+	}
+	return p.config
+}
+
+func (p *lazyProvider) CreateChatCompletionStream(ctx context.Context, messages []chat.Message, tools []tools.Tool) (chat.MessageStream, error) {
+	provider, err := p.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return provider.CreateChatCompletionStream(ctx, messages, tools)
+}
+
+func (p *lazyProvider) created() Provider {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.provider
+}
+
+func (p *lazyProvider) get(ctx context.Context) (Provider, error) {
+	p.createMu.Lock()
+	defer p.createMu.Unlock()
+
+	if provider := p.created(); provider != nil {
+		return provider, nil
+	}
+
+	slog.Debug("Creating deferred model provider", "id", p.config.ID())
+	provider, err := NewWithModels(ctx, &p.cfg, p.models, p.env, p.opts...)
+	if err != nil {
+		return nil, fmt.Errorf("creating model provider %s: %w", p.config.ID(), err)
+	}
+
+	p.mu.Lock()
+	p.provider = provider
+	p.mu.Unlock()
+	return provider, nil
+}
+
+// Warm creates the client of a provider returned by NewLazy ahead of its
+// first request, e.g. while the user types. Other providers are created
+// already.
+func Warm(ctx context.Context, p Provider) error {
+	lazy, ok := p.(*lazyProvider)
+	if !ok {
+		return nil
+	}
+	_, err := lazy.get(ctx)
+	return err
+}
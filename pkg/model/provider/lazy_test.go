@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/config/latest"
+	"github.com/docker/cagent/pkg/model/provider/options"
+)
+
+func TestNewLazy(t *testing.T) {
+	t.Parallel()
+
+	modelCfg := &latest.ModelConfig{
+		Provider: "mistral",
+		Model:    "mistral-large",
+		TokenKey: "MISSING_API_KEY",
+	}
+
+	// The missing API key isn't noticed until the provider is used
+	p, err := NewLazy(modelCfg, nil, newMockEnvProvider(map[string]string{}), options.WithMaxTokens(1000))
+	require.NoError(t, err)
+
+	assert.Equal(t, "mistral/mistral-large", p.ID())
+	config := p.BaseConfig()
+	assert.Equal(t, "https://api.mistral.ai/v1", config.ModelConfig.BaseURL, "provider defaults are applied")
+	assert.Equal(t, int64(1000), config.ModelOptions.MaxTokens())
+	assert.Equal(t, "openai", SchemaDialect(p).Name)
+
+	_, err = p.CreateChatCompletionStream(t.Context(), nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "MISSING_API_KEY")
+}
+
+func TestNewLazy_UnknownProvider(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewLazy(&latest.ModelConfig{Provider: "unknown", Model: "model"}, nil, newMockEnvProvider(nil))
+	require.ErrorContains(t, err, "unknown provider type: unknown")
+}
+
+func TestWarm(t *testing.T) {
+	t.Parallel()
+
+	p, err := NewLazy(&latest.ModelConfig{Provider: "mistral", Model: "mistral-large", TokenKey: "MISSING_API_KEY"}, nil, newMockEnvProvider(map[string]string{}))
+	require.NoError(t, err)
+
+	err = Warm(t.Context(), p)
+	require.ErrorContains(t, err, "MISSING_API_KEY", "warming up creates the client")
+}
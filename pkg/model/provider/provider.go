@@ -158,6 +158,17 @@ func createRuleBasedRouter(ctx context.Context, cfg *latest.ModelConfig, models
 	return rulebased.NewClient(ctx, cfg, models, env, factory, opts...)
 }
 
+// knownProviderTypes are the provider types createDirectProvider can create.
+var knownProviderTypes = map[string]bool{
+	"openai":                 true,
+	"openai_chatcompletions": true,
+	"openai_responses":       true,
+	"anthropic":              true,
+	"google":                 true,
+	"dmr":                    true,
+	"amazon-bedrock":         true,
+}
+
 // createDirectProvider creates a provider without routing (direct model access).
 func createDirectProvider(ctx context.Context, cfg *latest.ModelConfig, env environment.Provider, opts ...options.Opt) (Provider, error) {
 	var globalOptions options.ModelOptions
@@ -232,7 +243,8 @@ func applyProviderDefaults(cfg *latest.ModelConfig, customProviders map[string]l
 	enhancedCfg := *cfg
 
 	if customProviders != nil {
-		if providerCfg, exists := customProviders[cfg.Provider]; exists {
+		// Entries without a base URL only limit the requests to the provider
+		if providerCfg, exists := customProviders[cfg.Provider]; exists && providerCfg.BaseURL != "" {
 			slog.Debug("Applying custom provider defaults",
 				"provider", cfg.Provider,
 				"model", cfg.Model,
@@ -0,0 +1,41 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/openai/openai-go/v3"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genai"
+)
+
+func TestIsRetryable(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		err       error
+		retryable bool
+	}{
+		{"rate limited", &openai.Error{StatusCode: 429}, true},
+		{"overloaded", &anthropic.Error{StatusCode: 529}, true},
+		{"unavailable", genai.APIError{Code: 503}, true},
+		{"bad request", &openai.Error{StatusCode: 400}, false},
+		{"unauthorized", &anthropic.Error{StatusCode: 401}, false},
+		{"dropped stream", fmt.Errorf("error receiving from stream: %w", io.ErrUnexpectedEOF), true},
+		{"canceled", fmt.Errorf("creating chat completion: %w", context.Canceled), false},
+		{"other", errors.New("invalid tool definition"), false},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.retryable, IsRetryable(tt.err))
+		})
+	}
+}
@@ -342,6 +342,10 @@ var bedrockRegionPrefixes = map[string]bool{
 	"us":     true, // US region inference profile
 	"eu":     true, // EU region inference profile
 	"apac":   true, // Asia Pacific region inference profile
+	"jp":     true, // Japan region inference profile
+	"au":     true, // Australia region inference profile
+	"ca":     true, // Canada region inference profile
+	"us-gov": true, // AWS GovCloud (US) inference profile
 	"global": true, // Global inference profile (routes to any available region)
 }
 
@@ -89,3 +89,29 @@ func TestDatePattern(t *testing.T) {
 		})
 	}
 }
+
+func TestGetModel_BedrockInferenceProfile(t *testing.T) {
+	t.Parallel()
+
+	cost := &Cost{Input: 1, Output: 5, CacheRead: 0.1, CacheWrite: 1.25}
+	store, err := NewStore(WithCacheDir(t.TempDir()))
+	require.NoError(t, err)
+	store.SetDatabaseForTesting(&Database{
+		Providers: map[string]Provider{
+			"amazon-bedrock": {
+				Models: map[string]Model{
+					"anthropic.claude-haiku-4-5-20251001-v1:0": {Name: "Claude Haiku 4.5", Cost: cost},
+				},
+			},
+		},
+	})
+
+	for _, prefix := range []string{"", "us.", "eu.", "apac.", "jp.", "au.", "ca.", "us-gov.", "global."} {
+		model, err := store.GetModel(t.Context(), "amazon-bedrock/"+prefix+"anthropic.claude-haiku-4-5-20251001-v1:0")
+		require.NoError(t, err, prefix)
+		assert.Equal(t, cost, model.Cost, prefix)
+	}
+
+	_, err = store.GetModel(t.Context(), "amazon-bedrock/mars.anthropic.claude-haiku-4-5-20251001-v1:0")
+	require.Error(t, err)
+}
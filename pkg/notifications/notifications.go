@@ -0,0 +1,190 @@
+// Package notifications tells the user about events that need their
+// attention while they look at another window: with the terminal bell and,
+// optionally, a desktop notification.
+//
+// Each type of event is configured in the notifications section of the user
+// config:
+//
+//	notifications:
+//	  run_completed:
+//	    desktop: true
+//	    min_duration: 30s
+//	  approval_required:
+//	    bell: false
+package notifications
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Event is a type of event the user can be notified of.
+type Event string
+
+const (
+	// RunCompleted is sent when the agent is done and waits for the user.
+	RunCompleted Event = "run_completed"
+	// ApprovalRequired is sent when a tool call waits for the user's approval.
+	ApprovalRequired Event = "approval_required"
+)
+
+// defaultMinDuration is how long a run must take before its completion is
+// notified, unless configured otherwise.
+const defaultMinDuration = 10 * time.Second
+
+// Settings configures how the user is notified of a type of event.
+type Settings struct {
+	// Bell rings the terminal bell (default: true)
+	Bell *bool `yaml:"bell,omitempty"`
+	// Desktop shows a desktop notification (default: false)
+	Desktop bool `yaml:"desktop,omitempty"`
+	// MinDuration is, for run_completed, how long a run must take to be
+	// notified (default: 10s)
+	MinDuration time.Duration `yaml:"min_duration,omitempty"`
+}
+
+// Notifier notifies the user of events, as configured.
+type Notifier struct {
+	settings map[Event]Settings
+
+	// desktop shows a desktop notification and returns the escape sequence
+	// to write to the terminal for it, if any
+	desktop func(title, body string) string
+}
+
+// New creates a notifier with the settings of the user config, by event
+// type. Unknown event types are reported in the error and ignored.
+func New(settings map[string]Settings) (*Notifier, error) {
+	n := &Notifier{
+		settings: map[Event]Settings{},
+		desktop:  showDesktopNotification,
+	}
+
+	var unknown []string
+	for name, s := range settings {
+		switch event := Event(name); event {
+		case RunCompleted, ApprovalRequired:
+			n.settings[event] = s
+		default:
+			unknown = append(unknown, fmt.Sprintf("%q", name))
+		}
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return n, fmt.Errorf("unknown notification events: %s", strings.Join(unknown, ", "))
+	}
+	return n, nil
+}
+
+// Notify notifies the user of an event, with title and body for desktop
+// notifications. It returns the escape sequences to write to the terminal,
+// empty when nothing needs to be written.
+func (n *Notifier) Notify(event Event, title, body string) string {
+	s := n.settings[event]
+
+	var out strings.Builder
+	if s.Desktop {
+		out.WriteString(n.desktop(title, body))
+	}
+	if s.Bell == nil || *s.Bell {
+		out.WriteString("\a")
+	}
+	return out.String()
+}
+
+// MinDuration returns how long a run must take for its completion to be
+// notified.
+func (n *Notifier) MinDuration() time.Duration {
+	if d := n.settings[RunCompleted].MinDuration; d > 0 {
+		return d
+	}
+	return defaultMinDuration
+}
+
+// ShowDesktop shows a desktop notification with the native command of the
+// platform, for processes that don't write to a terminal.
+func ShowDesktop(title, body string) error {
+	cmd := nativeCommand(title, body)
+	if cmd == nil {
+		return fmt.Errorf("no command to show desktop notifications on %s", runtime.GOOS)
+	}
+	return cmd.Run()
+}
+
+// showDesktopNotification shows a notification with the native command of
+// the platform or, when there's none or cagent runs over SSH, returns an OSC
+// 777 escape sequence that the terminal turns into a notification.
+func showDesktopNotification(title, body string) string {
+	if !isSSHSession() && !supportsOSC777() {
+		if cmd := nativeCommand(title, body); cmd != nil {
+			err := cmd.Start()
+			if err == nil {
+				go func() { _ = cmd.Wait() }()
+				return ""
+			}
+			slog.Debug("Failed to show desktop notification", "command", cmd.Path, "error", err)
+		}
+	}
+	return osc777(title, body)
+}
+
+// nativeCommand returns the command showing a desktop notification on this
+// platform, or nil if it's not installed.
+func nativeCommand(title, body string) *exec.Cmd {
+	switch runtime.GOOS {
+	case "darwin":
+		if path, err := exec.LookPath("terminal-notifier"); err == nil {
+			return exec.Command(path, "-title", title, "-message", body)
+		}
+	case "linux", "freebsd", "openbsd":
+		if path, err := exec.LookPath("notify-send"); err == nil {
+			return exec.Command(path, title, body)
+		}
+	}
+	return nil
+}
+
+// osc777 returns the escape sequence of a desktop notification. Terminals
+// that don't support it ignore it.
+func osc777(title, body string) string {
+	return "\x1b]777;notify;" + sanitize(title) + ";" + sanitize(body) + "\a"
+}
+
+// sanitize removes what would end the escape sequence early, or split its
+// fields.
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == ';':
+			return ','
+		case r < 0x20, r == 0x7f:
+			return ' '
+		default:
+			return r
+		}
+	}, s)
+}
+
+// supportsOSC777 reports whether the terminal is known to show OSC 777
+// notifications itself.
+func supportsOSC777() bool {
+	switch os.Getenv("TERM_PROGRAM") {
+	case "WezTerm", "ghostty":
+		return true
+	}
+	term := os.Getenv("TERM")
+	return strings.HasPrefix(term, "foot") || strings.HasPrefix(term, "rxvt")
+}
+
+// isSSHSession returns true if cagent runs in an SSH session, where native
+// commands would notify the remote machine.
+func isSSHSession() bool {
+	return os.Getenv("SSH_TTY") != "" || os.Getenv("SSH_CONNECTION") != ""
+}
@@ -0,0 +1,56 @@
+package notifications
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifyRingsTheBellByDefault(t *testing.T) {
+	t.Parallel()
+
+	n, err := New(nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "\a", n.Notify(RunCompleted, "cagent", "root is done"))
+	assert.Equal(t, "\a", n.Notify(ApprovalRequired, "cagent", "root needs approval"))
+	assert.Equal(t, defaultMinDuration, n.MinDuration())
+}
+
+func TestNotifyPerEventSettings(t *testing.T) {
+	t.Parallel()
+
+	bell := false
+	n, err := New(map[string]Settings{
+		"run_completed":     {Desktop: true, MinDuration: time.Minute},
+		"approval_required": {Bell: &bell},
+	})
+	require.NoError(t, err)
+
+	var shown []string
+	n.desktop = func(title, body string) string {
+		shown = append(shown, title+": "+body)
+		return osc777(title, body)
+	}
+
+	assert.Equal(t, "\x1b]777;notify;cagent;root is done\a\a", n.Notify(RunCompleted, "cagent", "root is done"))
+	assert.Empty(t, n.Notify(ApprovalRequired, "cagent", "root needs approval"))
+	assert.Equal(t, []string{"cagent: root is done"}, shown)
+	assert.Equal(t, time.Minute, n.MinDuration())
+}
+
+func TestNewReportsUnknownEvents(t *testing.T) {
+	t.Parallel()
+
+	n, err := New(map[string]Settings{"run_failed": {}, "run_completed": {Desktop: true}})
+	require.ErrorContains(t, err, `unknown notification events: "run_failed"`)
+	assert.True(t, n.settings[RunCompleted].Desktop, "known events are kept")
+}
+
+func TestOSC777EscapesFields(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "\x1b]777;notify;a,b;c d\a", osc777("a;b", "c\ad"))
+}
@@ -0,0 +1,150 @@
+// Package pipeline chains agent runs: the output of a step becomes the input
+// of the next ones.
+//
+// A pipeline is a list of steps, each running an agent with a prompt
+// rendered from the input of the pipeline and the outputs of the previous
+// steps:
+//
+//	steps:
+//	  - name: plan
+//	    agent: ./planner.yaml
+//	    prompt: "Split this task into independent subtasks: {{ .Input }}"
+//	    output_schema:
+//	      type: array
+//	      items: {type: string}
+//	  - name: work
+//	    agent: ./worker.yaml
+//	    for_each: plan
+//	    prompt: "Do this subtask: {{ .Item }}"
+//	  - name: summary
+//	    agent: ./writer.yaml
+//	    prompt: "Summarize the results: {{ json .Steps.work }}"
+//
+// A step with an output schema answers with JSON validated against it. A
+// step with for_each fans out: it runs once per item of the output of an
+// earlier step, concurrently, and its output is the list of the outputs of
+// its runs, which the next steps fan in.
+package pipeline
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/goccy/go-yaml"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// Pipeline is a list of steps run in order.
+type Pipeline struct {
+	Steps []Step `yaml:"steps"`
+}
+
+// Step runs an agent with a prompt.
+type Step struct {
+	Name string `yaml:"name"`
+	// Agent is the agent file, relative to the pipeline file, or the registry
+	// reference of the agent
+	Agent string `yaml:"agent"`
+	// AgentName is the agent of a multi-agent file to run (default: root)
+	AgentName string `yaml:"agent_name,omitempty"`
+	// Prompt is a Go template of the message sent to the agent, with the
+	// input of the pipeline as .Input, the outputs of the previous steps as
+	// .Steps.<name> and, for steps with for_each, the item as .Item
+	Prompt string `yaml:"prompt"`
+	// OutputSchema is the JSON schema the output of the step must match. The
+	// agent is then asked to answer with JSON.
+	OutputSchema map[string]any `yaml:"output_schema,omitempty"`
+	// ForEach is the name of an earlier step whose output is a list: this
+	// step runs once per item
+	ForEach string `yaml:"for_each,omitempty"`
+	// MaxParallel is how many runs of a step with for_each run at once
+	// (default: all)
+	MaxParallel int `yaml:"max_parallel,omitempty"`
+
+	prompt *template.Template
+	schema *gojsonschema.Schema
+}
+
+// Load reads and validates a pipeline file. The local agent files of the
+// steps are resolved relative to it.
+func Load(path string) (*Pipeline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var p Pipeline
+	if err := yaml.UnmarshalWithOptions(data, &p, yaml.Strict()); err != nil {
+		return nil, fmt.Errorf("parsing pipeline %s: %w", path, err)
+	}
+
+	dir := filepath.Dir(path)
+	for i := range p.Steps {
+		step := &p.Steps[i]
+		if step.Agent != "" && !filepath.IsAbs(step.Agent) {
+			if local := filepath.Join(dir, step.Agent); isFile(local) {
+				step.Agent = local
+			}
+		}
+	}
+
+	if err := p.validate(); err != nil {
+		return nil, fmt.Errorf("invalid pipeline %s: %w", path, err)
+	}
+	return &p, nil
+}
+
+func (p *Pipeline) validate() error {
+	if len(p.Steps) == 0 {
+		return errors.New("no steps")
+	}
+
+	seen := map[string]bool{}
+	for i := range p.Steps {
+		step := &p.Steps[i]
+		switch {
+		case step.Name == "":
+			return fmt.Errorf("step %d has no name", i+1)
+		case seen[step.Name]:
+			return fmt.Errorf("step %s is defined twice", step.Name)
+		case step.Agent == "":
+			return fmt.Errorf("step %s has no agent", step.Name)
+		case strings.TrimSpace(step.Prompt) == "":
+			return fmt.Errorf("step %s has no prompt", step.Name)
+		case step.ForEach != "" && !seen[step.ForEach]:
+			return fmt.Errorf("step %s runs for each output of %s, which isn't an earlier step", step.Name, step.ForEach)
+		case step.MaxParallel < 0:
+			return fmt.Errorf("step %s: max_parallel must be positive", step.Name)
+		}
+		seen[step.Name] = true
+
+		var err error
+		if step.prompt, err = template.New(step.Name).Funcs(templateFuncs).Option("missingkey=error").Parse(step.Prompt); err != nil {
+			return fmt.Errorf("step %s: invalid prompt: %w", step.Name, err)
+		}
+		if step.OutputSchema != nil {
+			if step.schema, err = gojsonschema.NewSchema(gojsonschema.NewGoLoader(step.OutputSchema)); err != nil {
+				return fmt.Errorf("step %s: invalid output schema: %w", step.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// templateFuncs are the functions of the templates of the prompts.
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		buf, err := json.MarshalIndent(v, "", "  ")
+		return string(buf), err
+	},
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
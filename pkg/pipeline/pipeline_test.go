@@ -0,0 +1,157 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePipeline(t *testing.T, content string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "agent.yaml"), []byte("agents: {}"), 0o644))
+	path := filepath.Join(dir, "pipeline.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+const fanOutPipeline = `
+steps:
+  - name: plan
+    agent: ./agent.yaml
+    prompt: "Split {{ .Input }}"
+    output_schema:
+      type: array
+      items: {type: string}
+  - name: work
+    agent: ./agent.yaml
+    for_each: plan
+    max_parallel: 2
+    prompt: "Do {{ .Item }}"
+  - name: summary
+    agent: registry.example.com/writer
+    prompt: "Summarize {{ json .Steps.work }}"
+`
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	path := writePipeline(t, fanOutPipeline)
+	p, err := Load(path)
+	require.NoError(t, err)
+
+	require.Len(t, p.Steps, 3)
+	assert.Equal(t, filepath.Join(filepath.Dir(path), "agent.yaml"), p.Steps[0].Agent, "local agent files are relative to the pipeline")
+	assert.Equal(t, "registry.example.com/writer", p.Steps[2].Agent)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"no steps":          `steps: []`,
+		"no name":           `steps: [{agent: a.yaml, prompt: hi}]`,
+		"duplicate":         `steps: [{name: a, agent: a.yaml, prompt: hi}, {name: a, agent: a.yaml, prompt: hi}]`,
+		"no agent":          `steps: [{name: a, prompt: hi}]`,
+		"no prompt":         `steps: [{name: a, agent: a.yaml}]`,
+		"later for_each":    `steps: [{name: a, agent: a.yaml, prompt: hi, for_each: b}, {name: b, agent: a.yaml, prompt: hi}]`,
+		"invalid template":  `steps: [{name: a, agent: a.yaml, prompt: "{{ .Input "}]`,
+		"invalid schema":    `steps: [{name: a, agent: a.yaml, prompt: hi, output_schema: {type: 12}}]`,
+		"unknown attribute": `steps: [{name: a, agent: a.yaml, prompt: hi, model: gpt}]`,
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Load(writePipeline(t, content))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRun_FanOutFanIn(t *testing.T) {
+	t.Parallel()
+
+	p, err := Load(writePipeline(t, fanOutPipeline))
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var prompts []string
+	runner := func(_ context.Context, step *Step, prompt string) (string, error) {
+		mu.Lock()
+		prompts = append(prompts, prompt)
+		mu.Unlock()
+
+		switch step.Name {
+		case "plan":
+			return "```json\n[\"tests\", \"docs\"]\n```", nil
+		case "work":
+			return "did " + strings.TrimPrefix(prompt, "Do "), nil
+		default:
+			return "summary", nil
+		}
+	}
+
+	var progress []Progress
+	result, err := p.Run(t.Context(), "the release", runner, WithProgress(func(p Progress) {
+		progress = append(progress, p)
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, []any{"tests", "docs"}, result.Steps["plan"])
+	assert.Equal(t, []any{"did tests", "did docs"}, result.Steps["work"], "the outputs keep the order of the items")
+	assert.Equal(t, "summary", result.Output)
+
+	assert.Contains(t, prompts[0], "Split the release")
+	assert.Contains(t, prompts[0], "Answer with JSON only")
+	assert.Equal(t, "Summarize [\n  \"did tests\",\n  \"did docs\"\n]", prompts[len(prompts)-1])
+
+	assert.Equal(t, []Progress{
+		{Step: "plan", Runs: 1}, {Step: "plan", Runs: 1, Done: true},
+		{Step: "work", Runs: 2}, {Step: "work", Runs: 2, Done: true},
+		{Step: "summary", Runs: 1}, {Step: "summary", Runs: 1, Done: true},
+	}, progress)
+}
+
+func TestRun_OutputDoesNotMatchSchema(t *testing.T) {
+	t.Parallel()
+
+	p, err := Load(writePipeline(t, fanOutPipeline))
+	require.NoError(t, err)
+
+	runner := func(context.Context, *Step, string) (string, error) {
+		return `{"tasks": ["tests"]}`, nil
+	}
+	_, err = p.Run(t.Context(), "the release", runner)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "step plan: the output doesn't match its schema")
+}
+
+func TestRun_StopsAtFailedStep(t *testing.T) {
+	t.Parallel()
+
+	p, err := Load(writePipeline(t, fanOutPipeline))
+	require.NoError(t, err)
+
+	runner := func(_ context.Context, step *Step, prompt string) (string, error) {
+		if step.Name == "plan" {
+			return `["tests", "docs"]`, nil
+		}
+		if strings.Contains(prompt, "docs") {
+			return "", fmt.Errorf("model unavailable")
+		}
+		return "done", nil
+	}
+	result, err := p.Run(t.Context(), "the release", runner)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "step work: item 2: model unavailable")
+	assert.NotContains(t, result.Steps, "summary")
+}
@@ -0,0 +1,181 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+	"golang.org/x/sync/errgroup"
+)
+
+// Runner runs the agent of a step with a prompt and returns its answer.
+type Runner func(ctx context.Context, step *Step, prompt string) (string, error)
+
+// Opt configures a run of a pipeline.
+type Opt func(*runOptions)
+
+type runOptions struct {
+	progress func(Progress)
+}
+
+// Progress tells that the runs of a step started, or are done.
+type Progress struct {
+	Step string
+	// Runs is the number of runs of the step: the number of items of a step
+	// with for_each, one otherwise
+	Runs int
+	Done bool
+}
+
+// WithProgress calls fn when the runs of each step start and are done.
+func WithProgress(fn func(Progress)) Opt {
+	return func(o *runOptions) {
+		o.progress = fn
+	}
+}
+
+// Result is the outcome of a run of a pipeline.
+type Result struct {
+	// Steps are the outputs of the steps, by name: the answer of the agent,
+	// the JSON value of steps with an output schema, and lists of these for
+	// steps with for_each
+	Steps map[string]any
+	// Output is the output of the last step
+	Output any
+}
+
+// templateData is what the templates of the prompts are rendered with.
+type templateData struct {
+	Input string
+	Steps map[string]any
+	Item  any
+}
+
+// Run runs the steps of the pipeline in order, with input as the input of
+// the first one. It stops at the first step that fails.
+func (p *Pipeline) Run(ctx context.Context, input string, runner Runner, opts ...Opt) (*Result, error) {
+	var o runOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	result := &Result{Steps: map[string]any{}}
+	for i := range p.Steps {
+		step := &p.Steps[i]
+		data := templateData{Input: input, Steps: result.Steps}
+
+		var items []any
+		runs := 1
+		if step.ForEach != "" {
+			var ok bool
+			if items, ok = result.Steps[step.ForEach].([]any); !ok {
+				return result, fmt.Errorf("step %s: the output of %s isn't a list: give it an output schema of type array", step.Name, step.ForEach)
+			}
+			runs = len(items)
+		}
+		if o.progress != nil {
+			o.progress(Progress{Step: step.Name, Runs: runs})
+		}
+
+		var output any
+		var err error
+		if step.ForEach == "" {
+			output, err = step.run(ctx, data, runner)
+		} else {
+			output, err = step.runForEach(ctx, data, items, runner)
+		}
+		if err != nil {
+			return result, fmt.Errorf("step %s: %w", step.Name, err)
+		}
+		result.Steps[step.Name] = output
+		result.Output = output
+
+		if o.progress != nil {
+			o.progress(Progress{Step: step.Name, Runs: runs, Done: true})
+		}
+	}
+	return result, nil
+}
+
+// runForEach runs a step once per item, concurrently, and returns the list
+// of their outputs, in the order of the items.
+func (s *Step) runForEach(ctx context.Context, data templateData, items []any, runner Runner) (any, error) {
+	outputs := make([]any, len(items))
+
+	g, ctx := errgroup.WithContext(ctx)
+	if s.MaxParallel > 0 {
+		g.SetLimit(s.MaxParallel)
+	}
+	for i, item := range items {
+		g.Go(func() error {
+			itemData := data
+			itemData.Item = item
+
+			output, err := s.run(ctx, itemData, runner)
+			if err != nil {
+				return fmt.Errorf("item %d: %w", i+1, err)
+			}
+			outputs[i] = output
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return outputs, nil
+}
+
+func (s *Step) run(ctx context.Context, data templateData, runner Runner) (any, error) {
+	var prompt strings.Builder
+	if err := s.prompt.Execute(&prompt, data); err != nil {
+		return nil, fmt.Errorf("rendering prompt: %w", err)
+	}
+	if s.schema != nil {
+		schema, err := json.Marshal(s.OutputSchema)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&prompt, "\n\nAnswer with JSON only, without any other text, matching this JSON schema:\n%s", schema)
+	}
+
+	answer, err := runner(ctx, s, prompt.String())
+	if err != nil {
+		return nil, err
+	}
+	if s.schema == nil {
+		return answer, nil
+	}
+	return parseOutput(answer, s.schema)
+}
+
+// parseOutput parses the JSON answer of an agent and validates it against
+// the output schema of its step. Models often wrap JSON in a code block.
+func parseOutput(answer string, schema *gojsonschema.Schema) (any, error) {
+	text := strings.TrimSpace(answer)
+	if strings.HasPrefix(text, "```") {
+		text = strings.TrimPrefix(text, "```json")
+		text = strings.TrimPrefix(text, "```")
+		text = strings.TrimSuffix(strings.TrimSpace(text), "```")
+	}
+
+	var output any
+	if err := json.Unmarshal([]byte(text), &output); err != nil {
+		return nil, fmt.Errorf("the output isn't JSON: %w", err)
+	}
+
+	res, err := schema.Validate(gojsonschema.NewGoLoader(output))
+	if err != nil {
+		return nil, fmt.Errorf("validating the output: %w", err)
+	}
+	if !res.Valid() {
+		var errs []error
+		for _, e := range res.Errors() {
+			errs = append(errs, errors.New(e.String()))
+		}
+		return nil, fmt.Errorf("the output doesn't match its schema: %w", errors.Join(errs...))
+	}
+	return output, nil
+}
@@ -0,0 +1,112 @@
+// Package policy lets tool approval, budget and egress decisions be delegated
+// to an external policy engine, so that security teams can centrally manage
+// what agents may do.
+//
+// Before a tool call is executed, the runtime builds an Input document
+// describing the agent, the tool, its arguments and the session, and asks the
+// Engine for a Decision.
+package policy
+
+import (
+	"context"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Action is the outcome of a policy evaluation.
+type Action string
+
+const (
+	// Allow runs the tool call without asking the user.
+	Allow Action = "allow"
+	// Deny blocks the tool call.
+	Deny Action = "deny"
+	// Ask requires the user to confirm the tool call, even with --yolo.
+	Ask Action = "ask"
+	// NoOpinion lets the regular permission rules decide.
+	NoOpinion Action = ""
+)
+
+// Decision is returned by an Engine.
+type Decision struct {
+	Action Action `json:"action"`
+	// Reason is shown to the model (and user) when a tool call is denied.
+	Reason string `json:"reason,omitempty"`
+}
+
+// Engine evaluates policies.
+type Engine interface {
+	Evaluate(ctx context.Context, input Input) (Decision, error)
+}
+
+// Input is the document policies are evaluated against.
+type Input struct {
+	Agent     AgentInput     `json:"agent"`
+	Tool      ToolInput      `json:"tool"`
+	Arguments map[string]any `json:"arguments"`
+	Session   SessionInput   `json:"session"`
+	Egress    EgressInput    `json:"egress"`
+}
+
+type AgentInput struct {
+	Name string `json:"name"`
+}
+
+type ToolInput struct {
+	Name     string `json:"name"`
+	Category string `json:"category,omitempty"`
+	ReadOnly bool   `json:"read_only"`
+}
+
+type SessionInput struct {
+	ID               string  `json:"id"`
+	Cost             float64 `json:"cost"`
+	InputTokens      int64   `json:"input_tokens"`
+	OutputTokens     int64   `json:"output_tokens"`
+	ToolsApproved    bool    `json:"tools_approved"`
+	UntrustedContent bool    `json:"untrusted_content"`
+	WorkingDir       string  `json:"working_dir,omitempty"`
+}
+
+// EgressInput lists the network destinations found in the tool arguments.
+type EgressInput struct {
+	URLs  []string `json:"urls"`
+	Hosts []string `json:"hosts"`
+}
+
+// ExtractEgress walks tool arguments and collects every http(s) URL and its host.
+func ExtractEgress(args map[string]any) EgressInput {
+	egress := EgressInput{
+		URLs:  []string{},
+		Hosts: []string{},
+	}
+
+	var walk func(v any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case string:
+			u, err := url.Parse(strings.TrimSpace(v))
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return
+			}
+			egress.URLs = append(egress.URLs, u.String())
+			if host := u.Hostname(); !slices.Contains(egress.Hosts, host) {
+				egress.Hosts = append(egress.Hosts, host)
+			}
+		case []any:
+			for _, item := range v {
+				walk(item)
+			}
+		case map[string]any:
+			for _, item := range v {
+				walk(item)
+			}
+		}
+	}
+	walk(args)
+
+	slices.Sort(egress.URLs)
+	slices.Sort(egress.Hosts)
+	return egress
+}
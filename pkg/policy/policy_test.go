@@ -0,0 +1,113 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractEgress(t *testing.T) {
+	egress := ExtractEgress(map[string]any{
+		"urls": []any{"https://example.com/a", "https://example.com/b"},
+		"options": map[string]any{
+			"proxy": "http://proxy.internal:3128",
+		},
+		"command": "curl https://not-a-url-on-its-own",
+		"path":    "/etc/passwd",
+	})
+
+	assert.Equal(t, []string{"http://proxy.internal:3128", "https://example.com/a", "https://example.com/b"}, egress.URLs)
+	assert.Equal(t, []string{"example.com", "proxy.internal"}, egress.Hosts)
+}
+
+func TestExtractEgress_NoArguments(t *testing.T) {
+	egress := ExtractEgress(nil)
+
+	assert.Empty(t, egress.URLs)
+	assert.NotNil(t, egress.URLs, "urls must serialize as an empty array for policies")
+}
+
+// writePolicy writes a Rego policy to a temporary file.
+func writePolicy(t *testing.T, rego string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "policy.rego")
+	require.NoError(t, os.WriteFile(path, []byte(rego), 0o644))
+	return path
+}
+
+func TestRegoEngine_Evaluate(t *testing.T) {
+	path := writePolicy(t, `package cagent
+
+decision := {"action": "deny", "reason": "no shell on untrusted content"} if {
+	input.tool.name == "shell"
+	input.session.untrusted_content
+}
+
+decision := "allow" if input.tool.read_only
+`)
+
+	engine, err := NewRegoEngine(t.Context(), []string{path})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		input    Input
+		expected Decision
+	}{
+		{
+			name:     "undefined",
+			input:    Input{Tool: ToolInput{Name: "write_file"}},
+			expected: Decision{Action: NoOpinion},
+		},
+		{
+			name:     "string",
+			input:    Input{Tool: ToolInput{Name: "read_file", ReadOnly: true}},
+			expected: Decision{Action: Allow},
+		},
+		{
+			name:     "object",
+			input:    Input{Tool: ToolInput{Name: "shell"}, Session: SessionInput{UntrustedContent: true}},
+			expected: Decision{Action: Deny, Reason: "no shell on untrusted content"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision, err := engine.Evaluate(t.Context(), tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, decision)
+		})
+	}
+}
+
+func TestRegoEngine_UnknownAction(t *testing.T) {
+	path := writePolicy(t, `package cagent
+
+decision := "maybe"
+`)
+
+	engine, err := NewRegoEngine(t.Context(), []string{path})
+	require.NoError(t, err)
+
+	_, err = engine.Evaluate(t.Context(), Input{})
+	require.Error(t, err)
+}
+
+func TestNewRegoEngine_InvalidPolicy(t *testing.T) {
+	path := writePolicy(t, `package cagent
+
+decision := 
+`)
+
+	_, err := NewRegoEngine(t.Context(), []string{path})
+	require.Error(t, err)
+}
+
+func TestNewRegoEngine_MissingFile(t *testing.T) {
+	_, err := NewRegoEngine(t.Context(), []string{"does-not-exist.rego"})
+	require.Error(t, err)
+}
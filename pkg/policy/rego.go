@@ -0,0 +1,114 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/open-policy-agent/opa/v1/rego"
+)
+
+// DefaultQuery is the Rego rule evaluated to get a decision. It can either
+// produce a string ("allow", "deny", "ask") or an object with an "action"
+// and a "reason".
+const DefaultQuery = "data.cagent.decision"
+
+// RegoEngine evaluates Rego policies in-process with the Open Policy Agent
+// library. The policies are compiled once, when the engine is created.
+type RegoEngine struct {
+	query rego.PreparedEvalQuery
+}
+
+type regoConfig struct {
+	query string
+}
+
+type RegoOpt func(*regoConfig)
+
+// WithQuery overrides the Rego query used to get a decision.
+func WithQuery(query string) RegoOpt {
+	return func(c *regoConfig) {
+		c.query = query
+	}
+}
+
+// NewRegoEngine creates an engine evaluating the given Rego files (or
+// directories). Data files (JSON or YAML) are loaded too.
+func NewRegoEngine(ctx context.Context, files []string, opts ...RegoOpt) (*RegoEngine, error) {
+	if len(files) == 0 {
+		return nil, errors.New("at least one policy file is required")
+	}
+	for _, file := range files {
+		if _, err := os.Stat(file); err != nil {
+			return nil, fmt.Errorf("policy file: %w", err)
+		}
+	}
+
+	cfg := regoConfig{
+		query: DefaultQuery,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	query, err := rego.New(
+		rego.Query(cfg.query),
+		rego.Load(files, nil),
+	).PrepareForEval(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("compiling policies: %w", err)
+	}
+
+	return &RegoEngine{query: query}, nil
+}
+
+func (e *RegoEngine) Evaluate(ctx context.Context, input Input) (Decision, error) {
+	// Go through JSON so that the policies see the same field names as the docs
+	inputJSON, err := json.Marshal(input)
+	if err != nil {
+		return Decision{}, err
+	}
+	var inputDoc any
+	if err := json.Unmarshal(inputJSON, &inputDoc); err != nil {
+		return Decision{}, err
+	}
+
+	results, err := e.query.Eval(ctx, rego.EvalInput(inputDoc))
+	if err != nil {
+		return Decision{}, fmt.Errorf("evaluating policy: %w", err)
+	}
+
+	// An undefined decision means the policy has no opinion.
+	if len(results) == 0 || len(results[0].Expressions) == 0 {
+		return Decision{Action: NoOpinion}, nil
+	}
+
+	return parseDecision(results[0].Expressions[0].Value)
+}
+
+func parseDecision(value any) (Decision, error) {
+	var decision Decision
+	switch v := value.(type) {
+	case string:
+		decision.Action = Action(v)
+	case map[string]any:
+		buf, err := json.Marshal(v)
+		if err != nil {
+			return Decision{}, err
+		}
+		if err := json.Unmarshal(buf, &decision); err != nil {
+			return Decision{}, fmt.Errorf("policy decision must be a string or an object with an action: %s", buf)
+		}
+	default:
+		return Decision{}, fmt.Errorf("policy decision must be a string or an object with an action: %v", value)
+	}
+
+	switch decision.Action {
+	case Allow, Deny, Ask, NoOpinion:
+		return decision, nil
+	default:
+		return Decision{}, fmt.Errorf("unknown policy action %q", decision.Action)
+	}
+}
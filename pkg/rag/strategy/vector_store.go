@@ -317,6 +317,7 @@ func (s *VectorStore) Initialize(ctx context.Context, docPaths []string, chunkin
 			// Index the file
 			if err := s.indexFile(gctx, status.path); err != nil {
 				slog.Error("Failed to index file", "path", status.path, "error", err)
+				s.emitEvent(types.Event{Type: types.EventTypeError, Error: fmt.Errorf("indexing %s: %w", status.path, err)})
 				// Don't return error - continue indexing other files
 				return nil
 			}
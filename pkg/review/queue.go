@@ -0,0 +1,138 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/docker/cagent/pkg/tools"
+)
+
+// ExpiryPolicy decides what happens to a run when its review request expires.
+type ExpiryPolicy string
+
+const (
+	// ExpiryReject rejects the tool call and lets the run continue.
+	ExpiryReject ExpiryPolicy = "reject"
+	// ExpiryAbort stops the run.
+	ExpiryAbort ExpiryPolicy = "abort"
+)
+
+// ParseExpiryPolicy validates an expiry policy name.
+func ParseExpiryPolicy(s string) (ExpiryPolicy, error) {
+	switch p := ExpiryPolicy(s); p {
+	case ExpiryReject, ExpiryAbort:
+		return p, nil
+	default:
+		return "", fmt.Errorf("invalid expiry policy %q: must be %q or %q", s, ExpiryReject, ExpiryAbort)
+	}
+}
+
+const defaultPollInterval = 2 * time.Second
+
+// Queue submits tool calls for review and waits for decisions.
+type Queue struct {
+	store        Store
+	ttl          time.Duration
+	onExpiry     ExpiryPolicy
+	pollInterval time.Duration
+}
+
+type Opt func(*Queue)
+
+// WithTTL sets how long a request stays pending before it expires.
+// A zero TTL means requests never expire.
+func WithTTL(ttl time.Duration) Opt {
+	return func(q *Queue) {
+		q.ttl = ttl
+	}
+}
+
+// WithExpiryPolicy sets what happens to a run when its request expires.
+func WithExpiryPolicy(policy ExpiryPolicy) Opt {
+	return func(q *Queue) {
+		q.onExpiry = policy
+	}
+}
+
+// WithPollInterval sets how often the store is checked for a decision.
+func WithPollInterval(interval time.Duration) Opt {
+	return func(q *Queue) {
+		q.pollInterval = interval
+	}
+}
+
+func NewQueue(store Store, opts ...Opt) *Queue {
+	q := &Queue{
+		store:        store,
+		onExpiry:     ExpiryReject,
+		pollInterval: defaultPollInterval,
+	}
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
+}
+
+// ExpiryPolicy returns what should happen to a run when its request expires.
+func (q *Queue) ExpiryPolicy() ExpiryPolicy {
+	return q.onExpiry
+}
+
+// List returns the requests with the given status, or all requests if status
+// is empty.
+func (q *Queue) List(ctx context.Context, status Status) ([]*Request, error) {
+	return q.store.ListRequests(ctx, status)
+}
+
+// Decide approves or rejects a pending request.
+func (q *Queue) Decide(ctx context.Context, id string, status Status) error {
+	return q.store.Decide(ctx, id, status)
+}
+
+// Submit adds a pending review request for a tool call.
+func (q *Queue) Submit(ctx context.Context, sessionID, agentName string, toolCall tools.ToolCall) (*Request, error) {
+	now := time.Now()
+	req := &Request{
+		ID:        uuid.New().String(),
+		SessionID: sessionID,
+		AgentName: agentName,
+		ToolName:  toolCall.Function.Name,
+		Arguments: toolCall.Function.Arguments,
+		Status:    StatusPending,
+		CreatedAt: now,
+	}
+	if q.ttl > 0 {
+		req.ExpiresAt = now.Add(q.ttl)
+	}
+
+	if err := q.store.AddRequest(ctx, req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// Wait blocks until the request is approved, rejected or expired, or until
+// the context is canceled.
+func (q *Queue) Wait(ctx context.Context, id string) (Status, error) {
+	ticker := time.NewTicker(q.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		req, err := q.store.GetRequest(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		if req.Status != StatusPending {
+			return req.Status, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
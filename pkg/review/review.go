@@ -0,0 +1,56 @@
+// Package review implements a persistent queue of tool calls waiting for a
+// human decision.
+//
+// Unattended runs (scheduled jobs, webhooks, CI...) can't prompt anyone when a
+// tool call requires approval. Instead of rejecting the call, the run submits
+// it to the queue and suspends until someone approves or rejects it, or until
+// the request expires.
+package review
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrEmptyID         = errors.New("review request ID cannot be empty")
+	ErrNotFound        = errors.New("review request not found")
+	ErrAlreadyDecided  = errors.New("review request was already decided")
+	ErrInvalidDecision = errors.New("review decision must be approved or rejected")
+)
+
+// Status is the state of a review request.
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusApproved Status = "approved"
+	StatusRejected Status = "rejected"
+	StatusExpired  Status = "expired"
+)
+
+// Request is a tool call waiting for a human decision.
+type Request struct {
+	ID        string
+	SessionID string
+	AgentName string
+	ToolName  string
+	Arguments string
+	Status    Status
+	CreatedAt time.Time
+	// ExpiresAt is the time after which a pending request expires.
+	// A zero value means the request never expires.
+	ExpiresAt time.Time
+	DecidedAt time.Time
+}
+
+// Store persists review requests.
+type Store interface {
+	AddRequest(ctx context.Context, req *Request) error
+	GetRequest(ctx context.Context, id string) (*Request, error)
+	// ListRequests returns requests with the given status, or all requests if status is empty.
+	ListRequests(ctx context.Context, status Status) ([]*Request, error)
+	// Decide approves or rejects a pending request.
+	Decide(ctx context.Context, id string, status Status) error
+}
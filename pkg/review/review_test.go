@@ -0,0 +1,96 @@
+package review
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/tools"
+)
+
+func newTestStore(t *testing.T) *SQLiteStore {
+	t.Helper()
+
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "review.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+
+	return store
+}
+
+func shellCall() tools.ToolCall {
+	return tools.ToolCall{
+		ID:       "call_1",
+		Function: tools.FunctionCall{Name: "shell", Arguments: `{"cmd":"rm -rf build"}`},
+	}
+}
+
+func TestQueue_SubmitAndDecide(t *testing.T) {
+	store := newTestStore(t)
+	queue := NewQueue(store, WithPollInterval(10*time.Millisecond))
+
+	req, err := queue.Submit(t.Context(), "session-1", "root", shellCall())
+	require.NoError(t, err)
+
+	pending, err := store.ListRequests(t.Context(), StatusPending)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, "shell", pending[0].ToolName)
+	assert.JSONEq(t, `{"cmd":"rm -rf build"}`, pending[0].Arguments)
+	assert.True(t, pending[0].ExpiresAt.IsZero())
+
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		_ = store.Decide(t.Context(), req.ID, StatusApproved)
+	}()
+
+	status, err := queue.Wait(t.Context(), req.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusApproved, status)
+
+	// A decided request can't be decided again
+	err = store.Decide(t.Context(), req.ID, StatusRejected)
+	require.ErrorIs(t, err, ErrAlreadyDecided)
+}
+
+func TestQueue_Expiry(t *testing.T) {
+	store := newTestStore(t)
+	queue := NewQueue(store, WithTTL(20*time.Millisecond), WithExpiryPolicy(ExpiryAbort), WithPollInterval(10*time.Millisecond))
+
+	req, err := queue.Submit(t.Context(), "session-1", "root", shellCall())
+	require.NoError(t, err)
+	assert.False(t, req.ExpiresAt.IsZero())
+
+	status, err := queue.Wait(t.Context(), req.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusExpired, status)
+	assert.Equal(t, ExpiryAbort, queue.ExpiryPolicy())
+
+	err = store.Decide(t.Context(), req.ID, StatusApproved)
+	require.ErrorIs(t, err, ErrAlreadyDecided)
+}
+
+func TestStore_Errors(t *testing.T) {
+	store := newTestStore(t)
+
+	_, err := store.GetRequest(t.Context(), "missing")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	err = store.Decide(t.Context(), "missing", StatusApproved)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	err = store.Decide(t.Context(), "missing", StatusExpired)
+	require.ErrorIs(t, err, ErrInvalidDecision)
+}
+
+func TestParseExpiryPolicy(t *testing.T) {
+	policy, err := ParseExpiryPolicy("abort")
+	require.NoError(t, err)
+	assert.Equal(t, ExpiryAbort, policy)
+
+	_, err = ParseExpiryPolicy("approve")
+	require.Error(t, err)
+}
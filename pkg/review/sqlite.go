@@ -0,0 +1,191 @@
+package review
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/docker/cagent/pkg/sqliteutil"
+)
+
+// SQLiteStore is a Store backed by a SQLite database, so that requests
+// submitted by one process can be decided from another one.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore opens (or creates) a review queue database.
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	db, err := sqliteutil.OpenDB(path)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.ExecContext(context.Background(), `CREATE TABLE IF NOT EXISTS review_requests (
+		id TEXT PRIMARY KEY,
+		session_id TEXT,
+		agent_name TEXT,
+		tool_name TEXT,
+		arguments TEXT,
+		status TEXT,
+		created_at TEXT,
+		expires_at TEXT,
+		decided_at TEXT
+	)`)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &SQLiteStore{db: db}, nil
+}
+
+// Close closes the underlying database.
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *SQLiteStore) AddRequest(ctx context.Context, req *Request) error {
+	if req.ID == "" {
+		return ErrEmptyID
+	}
+
+	_, err := s.db.ExecContext(ctx,
+		"INSERT INTO review_requests (id, session_id, agent_name, tool_name, arguments, status, created_at, expires_at, decided_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		req.ID, req.SessionID, req.AgentName, req.ToolName, req.Arguments, string(req.Status),
+		formatTime(req.CreatedAt), formatTime(req.ExpiresAt), formatTime(req.DecidedAt))
+	return err
+}
+
+func (s *SQLiteStore) GetRequest(ctx context.Context, id string) (*Request, error) {
+	if id == "" {
+		return nil, ErrEmptyID
+	}
+
+	if err := s.expire(ctx); err != nil {
+		return nil, err
+	}
+
+	row := s.db.QueryRowContext(ctx,
+		"SELECT id, session_id, agent_name, tool_name, arguments, status, created_at, expires_at, decided_at FROM review_requests WHERE id = ?", id)
+
+	req, err := scanRequest(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func (s *SQLiteStore) ListRequests(ctx context.Context, status Status) ([]*Request, error) {
+	if err := s.expire(ctx); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT id, session_id, agent_name, tool_name, arguments, status, created_at, expires_at, decided_at FROM review_requests WHERE ? = '' OR status = ? ORDER BY created_at",
+		string(status), string(status))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requests []*Request
+	for rows.Next() {
+		req, err := scanRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, req)
+	}
+
+	return requests, rows.Err()
+}
+
+func (s *SQLiteStore) Decide(ctx context.Context, id string, status Status) error {
+	if id == "" {
+		return ErrEmptyID
+	}
+	if status != StatusApproved && status != StatusRejected {
+		return ErrInvalidDecision
+	}
+
+	if err := s.expire(ctx); err != nil {
+		return err
+	}
+
+	result, err := s.db.ExecContext(ctx,
+		"UPDATE review_requests SET status = ?, decided_at = ? WHERE id = ? AND status = ?",
+		string(status), formatTime(time.Now()), id, string(StatusPending))
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected > 0 {
+		return nil
+	}
+
+	// Nothing was updated, find out why
+	if _, err := s.GetRequest(ctx, id); err != nil {
+		return err
+	}
+	return ErrAlreadyDecided
+}
+
+// expire marks pending requests whose deadline has passed as expired.
+func (s *SQLiteStore) expire(ctx context.Context) error {
+	now := formatTime(time.Now())
+	_, err := s.db.ExecContext(ctx,
+		"UPDATE review_requests SET status = ?, decided_at = ? WHERE status = ? AND expires_at != '' AND expires_at <= ?",
+		string(StatusExpired), now, string(StatusPending), now)
+	return err
+}
+
+func scanRequest(scanner interface {
+	Scan(dest ...any) error
+},
+) (*Request, error) {
+	var req Request
+	var status, createdAt, expiresAt, decidedAt string
+
+	if err := scanner.Scan(&req.ID, &req.SessionID, &req.AgentName, &req.ToolName, &req.Arguments, &status, &createdAt, &expiresAt, &decidedAt); err != nil {
+		return nil, err
+	}
+
+	var err error
+	req.Status = Status(status)
+	if req.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, err
+	}
+	if req.ExpiresAt, err = parseTime(expiresAt); err != nil {
+		return nil, err
+	}
+	if req.DecidedAt, err = parseTime(decidedAt); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+// formatTime uses a fixed-width UTC format so that timestamps can be compared as strings.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format("2006-01-02T15:04:05.000000Z")
+}
+
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse("2006-01-02T15:04:05.000000Z", s)
+}
@@ -24,6 +24,7 @@ type Client struct {
 	baseURL    *url.URL
 	httpClient *http.Client
 	registry   map[string]func() Event
+	eventLevel EventLevel
 }
 
 // ClientOption is a function for configuring the Client
@@ -46,6 +47,14 @@ func WithTimeout(timeout time.Duration) ClientOption {
 	}
 }
 
+// WithEventLevel sets the events the runs of agents send, every event by
+// default
+func WithEventLevel(level EventLevel) ClientOption {
+	return func(c *Client) {
+		c.eventLevel = level
+	}
+}
+
 // NewClient creates a new HTTP client for the cagent server
 func NewClient(baseURL string, opts ...ClientOption) (*Client, error) {
 	parsedURL, err := url.Parse(baseURL)
@@ -58,6 +67,7 @@ func NewClient(baseURL string, opts ...ClientOption) (*Client, error) {
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		eventLevel: EventLevelDebug,
 		registry: map[string]func() Event{
 			"user_message":           func() Event { return &UserMessageEvent{} },
 			"tool_call":              func() Event { return &ToolCallEvent{} },
@@ -69,16 +79,29 @@ func NewClient(baseURL string, opts ...ClientOption) (*Client, error) {
 			"shell":                  func() Event { return &ShellOutputEvent{} },
 			"session_title":          func() Event { return &SessionTitleEvent{} },
 			"session_summary":        func() Event { return &SessionSummaryEvent{} },
+			"post_mortem":            func() Event { return &PostMortemEvent{} },
 			"session_compaction":     func() Event { return &SessionCompactionEvent{} },
+			"session_rotated":        func() Event { return &SessionRotatedEvent{} },
+			"tool_result_summarized": func() Event { return &ToolResultSummarizedEvent{} },
 			"partial_tool_call":      func() Event { return &PartialToolCallEvent{} },
 			"max_iterations_reached": func() Event { return &MaxIterationsReachedEvent{} },
 			"error":                  func() Event { return &ErrorEvent{} },
+			"model_retry":            func() Event { return &ModelRetryEvent{} },
+			"model_failover":         func() Event { return &ModelFailoverEvent{} },
+			"request_throttled":      func() Event { return &RequestThrottledEvent{} },
+			"stall_detected":         func() Event { return &StallDetectedEvent{} },
+			"guardrail_triggered":    func() Event { return &GuardrailTriggeredEvent{} },
+			"pending_messages":       func() Event { return &PendingMessagesEvent{} },
+			"call_deduplicated":      func() Event { return &CallDeduplicatedEvent{} },
 			"elicitation_request":    func() Event { return &ElicitationRequestEvent{} },
 			"authorization_event":    func() Event { return &AuthorizationEvent{} },
 			"agent_choice":           func() Event { return &AgentChoiceEvent{} },
 			"agent_choice_reasoning": func() Event { return &AgentChoiceReasoningEvent{} },
 			"mcp_init_started":       func() Event { return &MCPInitStartedEvent{} },
 			"mcp_init_finished":      func() Event { return &MCPInitFinishedEvent{} },
+			"mcp_server_status":      func() Event { return &MCPServerStatusEvent{} },
+			"sampling_usage":         func() Event { return &SamplingUsageEvent{} },
+			"tools_reloaded":         func() Event { return &ToolsReloadedEvent{} },
 		},
 	}
 
@@ -292,6 +315,9 @@ func (c *Client) runAgentWithAgentName(ctx context.Context, sessionID, agent, ag
 
 	u := *c.baseURL
 	u.Path = path.Join(u.Path, endpoint)
+	if c.eventLevel != EventLevelDebug {
+		u.RawQuery = url.Values{"events": {c.eventLevel.String()}}.Encode()
+	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(jsonBody))
 	if err != nil {
@@ -341,24 +367,8 @@ func (c *Client) runAgentWithAgentName(ctx context.Context, sessionID, agent, ag
 
 			slog.Debug("event", "event", string(after))
 
-			// First unmarshal to get the type
-			var baseEvent struct {
-				Type string `json:"type"`
-			}
-			if err := json.Unmarshal(after, &baseEvent); err != nil {
-				slog.Debug("event", "error", err)
-				continue
-			}
-
-			// Then unmarshal the full event
-			createEvent, found := c.registry[baseEvent.Type]
-			if !found {
-				slog.Debug("event", "invalid_type", baseEvent.Type)
-				continue
-			}
-
-			e := createEvent()
-			if err := json.Unmarshal(after, &e); err != nil {
+			e, err := c.decodeEvent(after)
+			if err != nil {
 				slog.Debug("event", "error", err)
 				continue
 			}
@@ -374,6 +384,29 @@ func (c *Client) runAgentWithAgentName(ctx context.Context, sessionID, agent, ag
 	return eventChan, nil
 }
 
+// decodeEvent decodes an event sent by the server.
+func (c *Client) decodeEvent(data []byte) (Event, error) {
+	// First unmarshal to get the type
+	var baseEvent struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &baseEvent); err != nil {
+		return nil, err
+	}
+
+	// Then unmarshal the full event
+	createEvent, found := c.registry[baseEvent.Type]
+	if !found {
+		return nil, fmt.Errorf("invalid event type %q", baseEvent.Type)
+	}
+
+	e := createEvent()
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
 func (c *Client) ResumeElicitation(ctx context.Context, sessionID string, action tools.ElicitationAction, content map[string]any) error {
 	req := api.ResumeElicitationRequest{Action: string(action), Content: content}
 	return c.doRequest(ctx, http.MethodPost, "/api/sessions/"+sessionID+"/elicitation", req, nil)
@@ -0,0 +1,71 @@
+package runtime
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/tools"
+)
+
+func clientEventSeeds(t testing.TB) [][]byte {
+	t.Helper()
+
+	toolCall := tools.ToolCall{ID: "call_1", Function: tools.FunctionCall{Name: "shell", Arguments: `{"cmd":"ls"}`}}
+	var seeds [][]byte
+	for _, event := range []Event{
+		UserMessage("Hello"),
+		AgentChoice("root", "Hi"),
+		ToolCall(toolCall, tools.Tool{Name: "shell"}, "root"),
+		ToolCallResponse(toolCall, tools.Tool{Name: "shell"}, tools.ResultSuccess("file.txt"), "file.txt", "root"),
+		TokenUsage("session", "root", 10, 5, 15, 1000, 0.01),
+		ElicitationRequest("Authorize?", map[string]any{"type": "object"}, map[string]any{"cagent/server_url": "https://example.com"}, "root"),
+		Error("boom"),
+	} {
+		buf, err := json.Marshal(event)
+		require.NoError(t, err)
+		seeds = append(seeds, buf)
+	}
+	return seeds
+}
+
+func TestClient_DecodeEvent(t *testing.T) {
+	t.Parallel()
+
+	client, err := NewClient("http://localhost")
+	require.NoError(t, err)
+
+	event, err := client.decodeEvent(clientEventSeeds(t)[4])
+	require.NoError(t, err)
+	usage, ok := event.(*TokenUsageEvent)
+	require.True(t, ok)
+	assert.Equal(t, int64(10), usage.Usage.InputTokens)
+
+	_, err = client.decodeEvent([]byte(`{"type":"unknown"}`))
+	require.ErrorContains(t, err, `invalid event type "unknown"`)
+}
+
+func FuzzClient_DecodeEvent(f *testing.F) {
+	for _, seed := range clientEventSeeds(f) {
+		f.Add(seed)
+	}
+	f.Add([]byte(`{"type":"token_usage","usage":null}`))
+	f.Add([]byte(`{"type":"tool_call","tool_call":[]}`))
+
+	client, err := NewClient("http://localhost")
+	require.NoError(f, err)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		event, err := client.decodeEvent(data)
+		if err != nil {
+			return
+		}
+
+		// Decoded events are handed to the TUI as is, they must encode again
+		_, err = json.Marshal(event)
+		require.NoError(t, err)
+		_ = event.GetAgentName()
+	})
+}
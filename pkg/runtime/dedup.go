@@ -0,0 +1,361 @@
+package runtime
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/modelsdev"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/tools"
+)
+
+// DeduplicationStats is what the calls shared between agents saved.
+type DeduplicationStats struct {
+	ModelCalls int     `json:"model_calls"`
+	ToolCalls  int     `json:"tool_calls"`
+	Tokens     int64   `json:"tokens"`
+	Cost       float64 `json:"cost"`
+}
+
+// Calls is the number of calls that were not sent.
+func (s DeduplicationStats) Calls() int {
+	return s.ModelCalls + s.ToolCalls
+}
+
+// unsharedToolCategories are the read-only tools whose result depends on the
+// agent calling them, or changes on its own.
+var unsharedToolCategories = map[string]bool{
+	"todo":     true,
+	"deferred": true,
+	"shell":    true,
+}
+
+// deduplicator shares the result of a model or tool call with the identical
+// calls the agents of a runtime make while it runs and for a short window
+// after: sub-agents fanned out on the same research often ask the same
+// question or fetch the same page.
+type deduplicator struct {
+	window time.Duration
+
+	mu      sync.Mutex
+	calls   map[string]*sharedCall
+	stats   DeduplicationStats
+	nowFunc func() time.Time
+}
+
+// sharedCall is a call in flight, or done and shared until it expires.
+type sharedCall struct {
+	done    chan struct{}
+	ok      bool
+	expires time.Time
+	// The responses of a model call, and its usage
+	responses []chat.MessageStreamResponse
+	usage     *chat.Usage
+	// The result of a tool call
+	result *tools.ToolCallResult
+}
+
+func newDeduplicator(window time.Duration) *deduplicator {
+	return &deduplicator{
+		window:  window,
+		calls:   map[string]*sharedCall{},
+		nowFunc: time.Now,
+	}
+}
+
+// join returns the call with the given key. The first caller leads it: it
+// makes the call and must settle it. The others wait for its result.
+func (d *deduplicator) join(key string) (call *sharedCall, leader bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if call, ok := d.calls[key]; ok {
+		select {
+		case <-call.done:
+			if d.nowFunc().Before(call.expires) {
+				return call, false
+			}
+		default:
+			return call, false
+		}
+	}
+
+	d.prune()
+	call = &sharedCall{done: make(chan struct{})}
+	d.calls[key] = call
+	return call, true
+}
+
+// prune drops the calls no longer shared.
+func (d *deduplicator) prune() {
+	now := d.nowFunc()
+	for key, call := range d.calls {
+		select {
+		case <-call.done:
+			if !now.Before(call.expires) {
+				delete(d.calls, key)
+			}
+		default:
+		}
+	}
+}
+
+// settle records the outcome of a call led by a caller and wakes up the
+// callers waiting for it. A failed call isn't shared.
+func (d *deduplicator) settle(key string, call *sharedCall, ok bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	call.ok = ok
+	call.expires = d.nowFunc().Add(d.window)
+	if !ok && d.calls[key] == call {
+		delete(d.calls, key)
+	}
+	close(call.done)
+}
+
+// wait waits for the call led by another caller and reports whether it
+// succeeded.
+func (d *deduplicator) wait(ctx context.Context, call *sharedCall) (bool, error) {
+	select {
+	case <-call.done:
+		return call.ok, nil
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
+// forgetToolCalls drops the shared tool results, once a tool may have
+// changed what they read. The callers already waiting for a call in flight
+// still get its result.
+func (d *deduplicator) forgetToolCalls() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for key := range d.calls {
+		if strings.HasPrefix(key, toolCallKeyPrefix) {
+			delete(d.calls, key)
+		}
+	}
+}
+
+// savedModelCall records a model call answered with the response of another.
+func (d *deduplicator) savedModelCall(usage *chat.Usage, m *modelsdev.Model) DeduplicationStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.stats.ModelCalls++
+	if usage != nil {
+		d.stats.Tokens += usage.InputTokens + usage.OutputTokens
+		if m != nil && m.Cost != nil {
+			d.stats.Cost += (float64(usage.InputTokens)*m.Cost.Input +
+				float64(usage.OutputTokens)*m.Cost.Output +
+				float64(usage.CachedInputTokens)*m.Cost.CacheRead +
+				float64(usage.CacheWriteTokens)*m.Cost.CacheWrite) / 1e6
+		}
+	}
+	return d.stats
+}
+
+// savedToolCall records a tool call answered with the result of another.
+func (d *deduplicator) savedToolCall() DeduplicationStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.stats.ToolCalls++
+	return d.stats
+}
+
+// modelCallKey identifies a model call by the model, the conversation and
+// the tools. The timestamps, usage and costs recorded on the messages don't
+// change the answer, they are left out.
+func modelCallKey(modelID string, messages []chat.Message, agentTools []tools.Tool) string {
+	type message struct {
+		Role         chat.MessageRole   `json:"role"`
+		Content      string             `json:"content"`
+		MultiContent []chat.MessagePart `json:"multi_content,omitempty"`
+		ToolCalls    []tools.ToolCall   `json:"tool_calls,omitempty"`
+		ToolCallID   string             `json:"tool_call_id,omitempty"`
+	}
+	type tool struct {
+		Name        string `json:"name"`
+		Description string `json:"description,omitempty"`
+		Parameters  any    `json:"parameters"`
+	}
+
+	request := struct {
+		Model    string    `json:"model"`
+		Messages []message `json:"messages"`
+		Tools    []tool    `json:"tools"`
+	}{Model: modelID}
+	for i := range messages {
+		msg := &messages[i]
+		request.Messages = append(request.Messages, message{msg.Role, msg.Content, msg.MultiContent, msg.ToolCalls, msg.ToolCallID})
+	}
+	for _, t := range agentTools {
+		request.Tools = append(request.Tools, tool{t.Name, t.Description, t.Parameters})
+	}
+
+	buf, err := json.Marshal(request)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(buf)
+	return "model:" + hex.EncodeToString(sum[:])
+}
+
+const toolCallKeyPrefix = "tool:"
+
+// toolCallKey identifies a tool call by the tool, its arguments and the
+// working directory they are relative to. Tools of the same name offered by
+// other toolsets or servers have another definition, so the definition of
+// the tool is part of the key.
+func toolCallKey(tool tools.Tool, toolCall tools.ToolCall, workingDir string) string {
+	identity := struct {
+		Category    string `json:"category,omitempty"`
+		Description string `json:"description,omitempty"`
+		Parameters  any    `json:"parameters"`
+		WorkingDir  string `json:"working_dir"`
+	}{tool.Category, tool.Description, tool.Parameters, workingDir}
+
+	buf, err := json.Marshal(identity)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(append(buf, callSignature(toolCall)...))
+	return toolCallKeyPrefix + hex.EncodeToString(sum[:])
+}
+
+// sharesToolCalls reports whether the calls to a tool can be answered with
+// the result of an identical call: only the tools that read without side
+// effects can.
+func sharesToolCalls(tool tools.Tool) bool {
+	return tool.Annotations.ReadOnlyHint && !unsharedToolCategories[tool.Category]
+}
+
+// callTool calls the handler of a tool, or answers with the recorded result
+// when the runtime replays a session.
+func (r *LocalRuntime) callTool(ctx context.Context, tool tools.Tool, toolCall tools.ToolCall, events chan Event, sess *session.Session, a *agent.Agent) (*tools.ToolCallResult, error) {
+	if r.replay != nil {
+		return r.replay.toolResult(tool, toolCall)
+	}
+	if r.recorder != nil {
+		res, err := r.shareToolCall(ctx, tool, toolCall, events, sess, a)
+		r.recorder.recordToolCall(a.Name(), toolCall, res, err)
+		return res, err
+	}
+	return r.shareToolCall(ctx, tool, toolCall, events, sess, a)
+}
+
+// shareToolCall calls the handler of a tool, unless an identical call of an
+// agent of the runtime answers it.
+func (r *LocalRuntime) shareToolCall(ctx context.Context, tool tools.Tool, toolCall tools.ToolCall, events chan Event, sess *session.Session, a *agent.Agent) (*tools.ToolCallResult, error) {
+	if r.dedup == nil {
+		return tool.Handler(ctx, toolCall)
+	}
+
+	if !sharesToolCalls(tool) {
+		r.dedup.forgetToolCalls()
+		defer r.dedup.forgetToolCalls()
+		return tool.Handler(ctx, toolCall)
+	}
+
+	key := toolCallKey(tool, toolCall, cmp.Or(sess.WorkingDir, r.workingDir))
+	if key == "" {
+		return tool.Handler(ctx, toolCall)
+	}
+	call, leader := r.dedup.join(key)
+	if !leader {
+		ok, err := r.dedup.wait(ctx, call)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			saved := r.dedup.savedToolCall()
+			slog.Debug("Sharing the result of an identical tool call", "agent", a.Name(), "tool", toolCall.Function.Name)
+			events <- CallDeduplicated("tool", toolCall.Function.Name, saved, a.Name())
+			return call.result, nil
+		}
+
+		// The identical call failed, maybe for a reason of its own
+		return tool.Handler(ctx, toolCall)
+	}
+
+	res, err := tool.Handler(ctx, toolCall)
+	call.result = res
+	r.dedup.settle(key, call, err == nil && res != nil && !res.IsError)
+	return res, err
+}
+
+// recordingStream records the responses of the stream of a model call, to
+// replay them to the identical calls.
+type recordingStream struct {
+	chat.MessageStream
+	dedup *deduplicator
+	key   string
+	call  *sharedCall
+	once  sync.Once
+}
+
+func (s *recordingStream) Recv() (chat.MessageStreamResponse, error) {
+	response, err := s.MessageStream.Recv()
+	switch {
+	case errors.Is(err, io.EOF):
+		s.settle(true)
+	case err != nil:
+		s.settle(false)
+	default:
+		if response.Usage != nil {
+			s.call.usage = response.Usage
+		}
+		s.call.responses = append(s.call.responses, response)
+		// The stream isn't read past the end of the answer
+		for _, choice := range response.Choices {
+			if choice.FinishReason == chat.FinishReasonStop || choice.FinishReason == chat.FinishReasonLength {
+				s.settle(true)
+			}
+		}
+	}
+	return response, err
+}
+
+func (s *recordingStream) Close() {
+	// A stream closed before its end isn't shared
+	s.settle(false)
+	s.MessageStream.Close()
+}
+
+func (s *recordingStream) settle(ok bool) {
+	s.once.Do(func() {
+		s.dedup.settle(s.key, s.call, ok)
+	})
+}
+
+// replayStream replays the responses recorded from another stream, without
+// their usage: the call wasn't billed twice.
+type replayStream struct {
+	responses []chat.MessageStreamResponse
+}
+
+func (s *replayStream) Recv() (chat.MessageStreamResponse, error) {
+	if len(s.responses) == 0 {
+		return chat.MessageStreamResponse{}, io.EOF
+	}
+	response := s.responses[0]
+	s.responses = s.responses[1:]
+	response.Usage = nil
+	return response, nil
+}
+
+func (s *replayStream) Close() {}
@@ -0,0 +1,186 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/team"
+	"github.com/docker/cagent/pkg/tools"
+)
+
+func TestRunStream_SharesIdenticalModelCalls(t *testing.T) {
+	t.Parallel()
+
+	prov := &queueProvider{id: "test/mock-model", streams: []chat.MessageStream{
+		newStreamBuilder().AddContent("Hello").AddStopWithUsage(30, 20).Build(),
+		newStreamBuilder().AddContent("Not shared").AddStopWithUsage(30, 20).Build(),
+	}}
+	root := agent.New("root", "You are a test agent", agent.WithModel(prov))
+	rt, err := New(team.New(team.WithAgents(root)), WithSessionCompaction(false), WithModelStore(mockModelStore{}), WithDeduplication(time.Minute))
+	require.NoError(t, err)
+
+	run := func() ([]Event, *session.Session) {
+		sess := session.New(session.WithUserMessage("Hi"), session.WithTitle("Unit Test"), session.WithToolsApproved(true))
+		var events []Event
+		for ev := range rt.RunStream(t.Context(), sess) {
+			events = append(events, ev)
+		}
+		return events, sess
+	}
+
+	_, first := run()
+	events, second := run()
+
+	assert.Equal(t, "Hello", first.GetLastAssistantMessageContent())
+	assert.Equal(t, "Hello", second.GetLastAssistantMessageContent())
+	assert.Len(t, prov.streams, 1, "the second run must not call the model")
+	assert.Equal(t, int64(50), first.InputTokens+first.OutputTokens)
+	assert.Zero(t, second.InputTokens+second.OutputTokens)
+
+	var deduplicated *CallDeduplicatedEvent
+	for _, ev := range events {
+		if e, ok := ev.(*CallDeduplicatedEvent); ok {
+			deduplicated = e
+		}
+	}
+	require.NotNil(t, deduplicated)
+	assert.Equal(t, "model", deduplicated.Kind)
+	assert.Equal(t, DeduplicationStats{ModelCalls: 1, Tokens: 50}, deduplicated.Saved)
+}
+
+func TestCallTool_SharesReadOnlyToolCalls(t *testing.T) {
+	t.Parallel()
+
+	var reads, writes int
+	read := tools.Tool{
+		Name:        "read_file",
+		Annotations: tools.ToolAnnotations{ReadOnlyHint: true},
+		Handler: func(context.Context, tools.ToolCall) (*tools.ToolCallResult, error) {
+			reads++
+			return tools.ResultSuccess("content"), nil
+		},
+	}
+	write := tools.Tool{
+		Name: "write_file",
+		Handler: func(context.Context, tools.ToolCall) (*tools.ToolCallResult, error) {
+			writes++
+			return tools.ResultSuccess("written"), nil
+		},
+	}
+	call := func(name, arguments string) tools.ToolCall {
+		return tools.ToolCall{Function: tools.FunctionCall{Name: name, Arguments: arguments}}
+	}
+
+	r := &LocalRuntime{dedup: newDeduplicator(time.Minute)}
+	a := agent.New("researcher", "")
+	sess := session.New(session.WithWorkingDir("/project"))
+	events := make(chan Event, 10)
+
+	for _, arguments := range []string{`{"path":"a.txt"}`, `{ "path": "a.txt" }`} {
+		res, err := r.callTool(t.Context(), read, call("read_file", arguments), events, sess, a)
+		require.NoError(t, err)
+		assert.Equal(t, "content", res.Output)
+	}
+	assert.Equal(t, 1, reads)
+	require.Len(t, events, 1)
+	assert.Equal(t, DeduplicationStats{ToolCalls: 1}, (<-events).(*CallDeduplicatedEvent).Saved)
+
+	// Other arguments are another call
+	_, err := r.callTool(t.Context(), read, call("read_file", `{"path":"b.txt"}`), events, sess, a)
+	require.NoError(t, err)
+	assert.Equal(t, 2, reads)
+
+	// A write may change what was read: the next read isn't shared
+	_, err = r.callTool(t.Context(), write, call("write_file", `{"path":"a.txt"}`), events, sess, a)
+	require.NoError(t, err)
+	_, err = r.callTool(t.Context(), write, call("write_file", `{"path":"a.txt"}`), events, sess, a)
+	require.NoError(t, err)
+	assert.Equal(t, 2, writes)
+
+	_, err = r.callTool(t.Context(), read, call("read_file", `{"path":"a.txt"}`), events, sess, a)
+	require.NoError(t, err)
+	assert.Equal(t, 3, reads)
+
+	// The same path in another working directory is another file
+	other := session.New(session.WithWorkingDir("/other"))
+	_, err = r.callTool(t.Context(), read, call("read_file", `{"path":"a.txt"}`), events, other, a)
+	require.NoError(t, err)
+	assert.Equal(t, 4, reads)
+}
+
+func TestToolCallKey(t *testing.T) {
+	t.Parallel()
+
+	call := tools.ToolCall{Function: tools.FunctionCall{Name: "search", Arguments: `{"query":"cagent"}`}}
+	docs := tools.Tool{Name: "search", Description: "Search the docs"}
+	issues := tools.Tool{Name: "search", Description: "Search the issues"}
+
+	assert.Equal(t, toolCallKey(docs, call, "/project"), toolCallKey(docs, call, "/project"))
+	assert.NotEqual(t, toolCallKey(docs, call, "/project"), toolCallKey(issues, call, "/project"))
+	assert.NotEqual(t, toolCallKey(docs, call, "/project"), toolCallKey(docs, call, "/other"))
+
+	// A tool that can't be identified isn't shared
+	assert.Empty(t, toolCallKey(tools.Tool{Parameters: func() {}}, call, "/project"))
+}
+
+func TestDeduplicator_Expires(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	d := newDeduplicator(time.Second)
+	d.nowFunc = func() time.Time { return now }
+
+	call, leader := d.join("key")
+	require.True(t, leader)
+
+	waiting, leader := d.join("key")
+	assert.False(t, leader)
+	assert.Same(t, call, waiting)
+
+	d.settle("key", call, true)
+	ok, err := d.wait(t.Context(), waiting)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	now = now.Add(2 * time.Second)
+	_, leader = d.join("key")
+	assert.True(t, leader)
+}
+
+func TestDeduplicator_FailedCallsAreNotShared(t *testing.T) {
+	t.Parallel()
+
+	d := newDeduplicator(time.Minute)
+	call, _ := d.join("key")
+	waiting, _ := d.join("key")
+
+	d.settle("key", call, false)
+	ok, err := d.wait(t.Context(), waiting)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, leader := d.join("key")
+	assert.True(t, leader)
+}
+
+func TestModelCallKey_IgnoresTimestamps(t *testing.T) {
+	t.Parallel()
+
+	first := []chat.Message{{Role: chat.MessageRoleUser, Content: "Hi", CreatedAt: "2025-01-01T00:00:00Z"}}
+	second := []chat.Message{{Role: chat.MessageRoleUser, Content: "Hi", CreatedAt: "2025-01-01T00:00:05Z"}}
+	other := []chat.Message{{Role: chat.MessageRoleUser, Content: "Hello"}}
+
+	assert.Equal(t, modelCallKey("openai/gpt-4o", first, nil), modelCallKey("openai/gpt-4o", second, nil))
+	assert.NotEqual(t, modelCallKey("openai/gpt-4o", first, nil), modelCallKey("openai/gpt-4o", other, nil))
+	assert.NotEqual(t, modelCallKey("openai/gpt-4o", first, nil), modelCallKey("openai/gpt-4o-mini", first, nil))
+
+	// A call that can't be identified isn't shared
+	assert.Empty(t, modelCallKey("openai/gpt-4o", first, []tools.Tool{{Name: "broken", Parameters: func() {}}}))
+}
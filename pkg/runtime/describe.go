@@ -0,0 +1,249 @@
+package runtime
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/team"
+)
+
+// TeamDescription describes what a team of agents can do, for the users who
+// discover it: the agents, their roles, their tools, models and budgets.
+type TeamDescription struct {
+	DefaultAgent string             `json:"default_agent"`
+	Agents       []AgentDescription `json:"agents"`
+	// ProviderLimits is the maximum number of concurrent requests, by provider
+	ProviderLimits map[string]int `json:"provider_limits,omitempty"`
+	// ProviderRateLimits are the requests and tokens per minute, by provider
+	ProviderRateLimits map[string]team.RateLimit `json:"provider_rate_limits,omitempty"`
+}
+
+// AgentDescription describes an agent of a team.
+type AgentDescription struct {
+	Name        string               `json:"name"`
+	Description string               `json:"description,omitempty"`
+	Model       string               `json:"model,omitempty"`
+	Fallback    string               `json:"fallback,omitempty"`
+	SubAgents   []string             `json:"sub_agents,omitempty"`
+	Handoffs    []string             `json:"handoffs,omitempty"`
+	Tools       []ToolDescription    `json:"tools,omitempty"`
+	Commands    []CommandDescription `json:"commands,omitempty"`
+	Budgets     AgentBudgets         `json:"budgets"`
+}
+
+// ToolDescription describes a tool an agent can call.
+type ToolDescription struct {
+	Name        string `json:"name"`
+	Category    string `json:"category,omitempty"`
+	Description string `json:"description,omitempty"`
+	ReadOnly    bool   `json:"read_only,omitempty"`
+}
+
+// CommandDescription describes a named command of an agent.
+type CommandDescription struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// AgentBudgets are the limits an agent runs within. Zero means no limit.
+type AgentBudgets struct {
+	MaxIterations        int `json:"max_iterations,omitempty"`
+	MaxRepeatedToolCalls int `json:"max_repeated_tool_calls,omitempty"`
+	MaxTurnTokens        int `json:"max_turn_tokens,omitempty"`
+	MaxParallelToolCalls int `json:"max_parallel_tool_calls,omitempty"`
+	MaxRetries           int `json:"max_retries,omitempty"`
+}
+
+// TeamDescriber is an optional interface for runtimes that can describe what
+// their team can do.
+type TeamDescriber interface {
+	Describe(ctx context.Context) TeamDescription
+}
+
+// DescribeTeam describes the team as it's loaded: the current models of the
+// agents and the tools their toolsets actually provide, which starts them.
+func DescribeTeam(ctx context.Context, t *team.Team) TeamDescription {
+	var desc TeamDescription
+	if defaultAgent, err := t.DefaultAgent(); err == nil {
+		desc.DefaultAgent = defaultAgent.Name()
+	}
+	if limits := t.ProviderLimits(); len(limits) > 0 {
+		desc.ProviderLimits = maps.Clone(limits)
+	}
+	if limits := t.ProviderRateLimits(); len(limits) > 0 {
+		desc.ProviderRateLimits = maps.Clone(limits)
+	}
+
+	for _, name := range t.AgentNames() {
+		a, err := t.Agent(name)
+		if err != nil {
+			continue
+		}
+		desc.Agents = append(desc.Agents, describeAgent(ctx, a))
+	}
+
+	return desc
+}
+
+func describeAgent(ctx context.Context, a *agent.Agent) AgentDescription {
+	retry := a.RetryPolicy()
+	desc := AgentDescription{
+		Name:        a.Name(),
+		Description: a.Description(),
+		Model:       getAgentModelID(a),
+		Budgets: AgentBudgets{
+			MaxIterations:        a.MaxIterations(),
+			MaxRepeatedToolCalls: a.MaxRepeatedToolCalls(),
+			MaxTurnTokens:        a.MaxTurnTokens(),
+			MaxParallelToolCalls: a.MaxParallelToolCalls(),
+			MaxRetries:           retry.MaxRetries,
+		},
+	}
+	if retry.Fallback != nil {
+		desc.Fallback = retry.Fallback.ID()
+	}
+	for _, sub := range a.SubAgents() {
+		desc.SubAgents = append(desc.SubAgents, sub.Name())
+	}
+	for _, handoff := range a.Handoffs() {
+		desc.Handoffs = append(desc.Handoffs, handoff.Name())
+	}
+
+	agentTools, err := a.Tools(ctx)
+	if err != nil {
+		slog.Warn("Failed to list the tools of an agent", "agent", a.Name(), "error", err)
+	}
+	for _, tool := range agentTools {
+		desc.Tools = append(desc.Tools, ToolDescription{
+			Name:        tool.Name,
+			Category:    tool.Category,
+			Description: firstLine(tool.Description),
+			ReadOnly:    tool.Annotations.ReadOnlyHint,
+		})
+	}
+	slices.SortStableFunc(desc.Tools, func(a, b ToolDescription) int {
+		return cmp.Compare(a.Category, b.Category)
+	})
+
+	commands := a.Commands()
+	for _, name := range slices.Sorted(maps.Keys(commands)) {
+		desc.Commands = append(desc.Commands, CommandDescription{Name: name, Description: commands[name].DisplayText()})
+	}
+
+	return desc
+}
+
+// Describe describes the team of the runtime.
+func (r *LocalRuntime) Describe(ctx context.Context) TeamDescription {
+	return DescribeTeam(ctx, r.team)
+}
+
+// Markdown renders the description for people to read.
+func (d *TeamDescription) Markdown() string {
+	var b strings.Builder
+
+	for i := range d.Agents {
+		a := &d.Agents[i]
+
+		title := a.Name
+		if a.Name == d.DefaultAgent {
+			title += " (default)"
+		}
+		fmt.Fprintf(&b, "## %s\n\n", title)
+		if a.Description != "" {
+			fmt.Fprintf(&b, "%s\n\n", a.Description)
+		}
+
+		if a.Model != "" {
+			fmt.Fprintf(&b, "- **Model:** %s", a.Model)
+			if a.Fallback != "" {
+				fmt.Fprintf(&b, ", falls back to %s", a.Fallback)
+			}
+			b.WriteString("\n")
+		}
+		if len(a.SubAgents) > 0 {
+			fmt.Fprintf(&b, "- **Delegates to:** %s\n", strings.Join(a.SubAgents, ", "))
+		}
+		if len(a.Handoffs) > 0 {
+			fmt.Fprintf(&b, "- **Hands off to:** %s\n", strings.Join(a.Handoffs, ", "))
+		}
+		if budgets := a.Budgets.summary(); budgets != "" {
+			fmt.Fprintf(&b, "- **Budgets:** %s\n", budgets)
+		}
+
+		if len(a.Tools) > 0 {
+			fmt.Fprintf(&b, "\n### Tools (%d)\n\n", len(a.Tools))
+			for _, tool := range a.Tools {
+				fmt.Fprintf(&b, "- `%s`", tool.Name)
+				if tool.ReadOnly {
+					b.WriteString(" (read-only)")
+				}
+				if tool.Description != "" {
+					fmt.Fprintf(&b, ": %s", tool.Description)
+				}
+				b.WriteString("\n")
+			}
+		}
+
+		if len(a.Commands) > 0 {
+			b.WriteString("\n### Commands\n\n")
+			for _, command := range a.Commands {
+				fmt.Fprintf(&b, "- `/%s`: %s\n", command.Name, command.Description)
+			}
+		}
+
+		b.WriteString("\n")
+	}
+
+	if len(d.ProviderLimits) > 0 || len(d.ProviderRateLimits) > 0 {
+		b.WriteString("## Limits\n\n")
+		providers := slices.Concat(slices.Collect(maps.Keys(d.ProviderLimits)), slices.Collect(maps.Keys(d.ProviderRateLimits)))
+		slices.Sort(providers)
+		for _, provider := range slices.Compact(providers) {
+			var parts []string
+			if limit := d.ProviderLimits[provider]; limit > 0 {
+				parts = append(parts, fmt.Sprintf("%d concurrent requests", limit))
+			}
+			if rate := d.ProviderRateLimits[provider]; rate.RequestsPerMinute > 0 {
+				parts = append(parts, fmt.Sprintf("%d requests per minute", rate.RequestsPerMinute))
+			}
+			if rate := d.ProviderRateLimits[provider]; rate.TokensPerMinute > 0 {
+				parts = append(parts, fmt.Sprintf("%d tokens per minute", rate.TokensPerMinute))
+			}
+			fmt.Fprintf(&b, "- %s: %s\n", provider, strings.Join(parts, ", "))
+		}
+	}
+
+	return strings.TrimSpace(b.String())
+}
+
+func (b AgentBudgets) summary() string {
+	var parts []string
+	if b.MaxIterations > 0 {
+		parts = append(parts, fmt.Sprintf("%d iterations per run", b.MaxIterations))
+	}
+	if b.MaxRepeatedToolCalls > 0 {
+		parts = append(parts, fmt.Sprintf("%d identical tool calls in a row", b.MaxRepeatedToolCalls))
+	}
+	if b.MaxTurnTokens > 0 {
+		parts = append(parts, fmt.Sprintf("%d tokens per turn", b.MaxTurnTokens))
+	}
+	if b.MaxParallelToolCalls > 1 {
+		parts = append(parts, fmt.Sprintf("%d tool calls at once", b.MaxParallelToolCalls))
+	}
+	if b.MaxRetries > 0 {
+		parts = append(parts, fmt.Sprintf("%d retries", b.MaxRetries))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
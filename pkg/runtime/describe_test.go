@@ -0,0 +1,61 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/config/types"
+	"github.com/docker/cagent/pkg/team"
+	"github.com/docker/cagent/pkg/tools"
+)
+
+func TestDescribeTeam(t *testing.T) {
+	t.Parallel()
+
+	prov := &queueProvider{id: "test/mock-model"}
+	fallback := &queueProvider{id: "test/fallback"}
+	researcher := agent.New("researcher", "You research",
+		agent.WithDescription("Finds sources"),
+		agent.WithModel(prov),
+		agent.WithToolSets(newStubToolSet(nil, []tools.Tool{{
+			Name:        "fetch",
+			Category:    "fetch",
+			Description: "Fetch a URL\nand return its content",
+			Annotations: tools.ToolAnnotations{ReadOnlyHint: true},
+		}}, nil)),
+	)
+	root := agent.New("root", "You lead",
+		agent.WithDescription("Answers questions"),
+		agent.WithModel(prov),
+		agent.WithSubAgents(researcher),
+		agent.WithMaxIterations(20),
+		agent.WithRetryPolicy(agent.RetryPolicy{MaxRetries: 2, Fallback: fallback}),
+		agent.WithCommands(types.Commands{"summarize": {Description: "Summarize the sources"}}),
+	)
+
+	desc := DescribeTeam(t.Context(), team.New(team.WithAgents(root, researcher), team.WithProviderLimits(map[string]int{"test": 2}), team.WithProviderRateLimits(map[string]team.RateLimit{"test": {RequestsPerMinute: 60}})))
+
+	assert.Equal(t, "root", desc.DefaultAgent)
+	assert.Equal(t, map[string]int{"test": 2}, desc.ProviderLimits)
+	require.Len(t, desc.Agents, 2)
+
+	assert.Equal(t, "Answers questions", desc.Agents[0].Description)
+	assert.Equal(t, "test/mock-model", desc.Agents[0].Model)
+	assert.Equal(t, "test/fallback", desc.Agents[0].Fallback)
+	assert.Equal(t, []string{"researcher"}, desc.Agents[0].SubAgents)
+	assert.Equal(t, 20, desc.Agents[0].Budgets.MaxIterations)
+	assert.Equal(t, 2, desc.Agents[0].Budgets.MaxRetries)
+	assert.Equal(t, []CommandDescription{{Name: "summarize", Description: "Summarize the sources"}}, desc.Agents[0].Commands)
+
+	assert.Equal(t, []ToolDescription{{Name: "fetch", Category: "fetch", Description: "Fetch a URL", ReadOnly: true}}, desc.Agents[1].Tools)
+
+	markdown := desc.Markdown()
+	assert.Contains(t, markdown, "## root (default)\n\nAnswers questions\n\n- **Model:** test/mock-model, falls back to test/fallback\n- **Delegates to:** researcher\n")
+	assert.Contains(t, markdown, "- **Budgets:** 20 iterations per run, 5 identical tool calls in a row, 2 retries\n")
+	assert.Contains(t, markdown, "- `/summarize`: Summarize the sources")
+	assert.Contains(t, markdown, "### Tools (1)\n\n- `fetch` (read-only): Fetch a URL\n")
+	assert.Contains(t, markdown, "- test: 2 concurrent requests, 60 requests per minute")
+}
@@ -0,0 +1,96 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/session"
+)
+
+// CostEstimator is implemented by runtimes that can estimate the cost of a
+// request before sending it.
+type CostEstimator interface {
+	EstimateCost(ctx context.Context, sess *session.Session, prompt string) (*CostEstimate, error)
+}
+
+// CostEstimate is what the next request of a session is expected to cost,
+// before it's sent.
+type CostEstimate struct {
+	Agent string
+	Model string
+
+	// InputTokens is the estimated size of the request: the system prompt,
+	// the tools, the conversation and the prompt
+	InputTokens int64
+	// MaxOutputTokens is the most the model may answer
+	MaxOutputTokens int64
+
+	// MinCost is the cost of the input alone, MaxCost adds the longest answer
+	// the model may give. Both are in USD and only set when Priced.
+	MinCost float64
+	MaxCost float64
+	Priced  bool
+}
+
+// EstimateCost estimates the tokens and the cost of the request the current
+// agent would send to its model for a prompt, without sending it. Tokens are
+// estimated from the length of the text, and the range only covers the
+// first request of the turn: tool calls add requests the estimate can't
+// predict.
+func (r *LocalRuntime) EstimateCost(ctx context.Context, sess *session.Session, prompt string) (*CostEstimate, error) {
+	a := r.CurrentAgent()
+	model := a.Model()
+
+	agentTools, err := a.Tools(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing tools: %w", err)
+	}
+
+	inputTokens := systemPromptTokens(nil, agentTools)
+	for _, msg := range sess.GetMessages(a) {
+		inputTokens += messageTokens(msg)
+	}
+	inputTokens += estimateTokens(prompt)
+
+	modelOptions := model.BaseConfig().ModelOptions
+	estimate := &CostEstimate{
+		Agent:           a.Name(),
+		Model:           model.ID(),
+		InputTokens:     int64(inputTokens),
+		MaxOutputTokens: modelOptions.MaxTokens(),
+	}
+
+	m, err := r.modelsStore.GetModel(ctx, model.ID())
+	if err != nil {
+		slog.Debug("Failed to get model definition", "model", model.ID(), "error", err)
+		return estimate, nil
+	}
+	if m == nil {
+		return estimate, nil
+	}
+	if estimate.MaxOutputTokens <= 0 {
+		estimate.MaxOutputTokens = m.Limit.Output
+	}
+	if m.Cost != nil {
+		estimate.MinCost = float64(estimate.InputTokens) * m.Cost.Input / 1e6
+		estimate.MaxCost = estimate.MinCost + float64(estimate.MaxOutputTokens)*m.Cost.Output/1e6
+		estimate.Priced = true
+	}
+
+	return estimate, nil
+}
+
+// messageTokens estimates the number of tokens a message of the conversation
+// takes in a request.
+func messageTokens(msg chat.Message) int {
+	tokens := estimateTokens(msg.Content) + estimateTokens(msg.ReasoningContent)
+	for _, part := range msg.MultiContent {
+		tokens += estimateTokens(part.Text)
+	}
+	for _, call := range msg.ToolCalls {
+		tokens += estimateTokens(call.Function.Name + call.Function.Arguments)
+	}
+	return tokens
+}
@@ -0,0 +1,62 @@
+package runtime
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/modelsdev"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/team"
+)
+
+type mockModelStoreWithCost struct{}
+
+func (mockModelStoreWithCost) GetModel(context.Context, string) (*modelsdev.Model, error) {
+	return &modelsdev.Model{
+		Limit: modelsdev.Limit{Context: 100_000, Output: 1_000},
+		Cost:  &modelsdev.Cost{Input: 3, Output: 15},
+	}, nil
+}
+
+func TestEstimateCost(t *testing.T) {
+	t.Parallel()
+
+	root := agent.New("root", "You are a test agent", agent.WithModel(&mockProvider{id: "test/mock-model", stream: &mockStream{}}))
+	tm := team.New(team.WithAgents(root))
+
+	rt, err := New(tm, WithSessionCompaction(false), WithModelStore(mockModelStoreWithCost{}))
+	require.NoError(t, err)
+
+	sess := session.New()
+	short, err := rt.EstimateCost(t.Context(), sess, "Hello")
+	require.NoError(t, err)
+	long, err := rt.EstimateCost(t.Context(), sess, strings.Repeat("word ", 1000))
+	require.NoError(t, err)
+
+	assert.Equal(t, "root", short.Agent)
+	assert.Equal(t, "test/mock-model", short.Model)
+	assert.Equal(t, short.InputTokens+1249, long.InputTokens)
+
+	require.True(t, long.Priced)
+	assert.Equal(t, int64(1_000), long.MaxOutputTokens, "the output limit of the model applies without max_tokens")
+	assert.InDelta(t, float64(long.InputTokens)*3/1e6, long.MinCost, 1e-12)
+	assert.InDelta(t, long.MinCost+1_000*15/1e6, long.MaxCost, 1e-12)
+}
+
+func TestEstimateCost_Unpriced(t *testing.T) {
+	t.Parallel()
+
+	root := agent.New("root", "You are a test agent", agent.WithModel(&mockProvider{id: "test/mock-model", stream: &mockStream{}}))
+	rt, err := New(team.New(team.WithAgents(root)), WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+
+	estimate, err := rt.EstimateCost(t.Context(), session.New(), "Hello")
+	require.NoError(t, err)
+	assert.Positive(t, estimate.InputTokens)
+	assert.False(t, estimate.Priced)
+}
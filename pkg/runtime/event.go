@@ -2,7 +2,9 @@ package runtime
 
 import (
 	"cmp"
+	"time"
 
+	"github.com/docker/cagent/pkg/guardrails"
 	"github.com/docker/cagent/pkg/tools"
 )
 
@@ -13,11 +15,29 @@ type Event interface {
 // AgentContext carries optional agent attribution for an event.
 type AgentContext struct {
 	AgentName string `json:"agent_name,omitempty"`
+	// Depth is how deep the agent is in task transfers: 0 for the agent the
+	// user talks to, 1 for the agents it transfers tasks to, and so on.
+	Depth int `json:"depth,omitempty"`
 }
 
 // GetAgentName returns the agent name for events embedding AgentContext.
 func (a AgentContext) GetAgentName() string { return a.AgentName }
 
+func (a AgentContext) depth() int { return a.Depth }
+
+// nest marks the event as sent by an agent one task transfer deeper.
+func (a *AgentContext) nest() { a.Depth++ }
+
+// EventDepth returns how deep in task transfers the agent that sent an event
+// is. The events of sub-agents are nested in the stream of the agent that
+// transferred them the task, clients indent or hide them.
+func EventDepth(event Event) int {
+	if e, ok := event.(interface{ depth() int }); ok {
+		return e.depth()
+	}
+	return 0
+}
+
 // UserMessageEvent is sent when a user message is received
 type UserMessageEvent struct {
 	Type    string `json:"type"`
@@ -186,6 +206,157 @@ func Warning(message, agentName string) Event {
 	}
 }
 
+// ModelRetryEvent is sent when a request to a model failed and is retried
+// after a delay. Discarded tells that what the model streamed before it failed
+// was dropped: the response is streamed again.
+type ModelRetryEvent struct {
+	Type       string `json:"type"`
+	Model      string `json:"model"`
+	Retry      int    `json:"retry"`
+	MaxRetries int    `json:"max_retries"`
+	DelayMs    int64  `json:"delay_ms"`
+	Error      string `json:"error"`
+	Discarded  bool   `json:"discarded,omitempty"`
+	AgentContext
+}
+
+func ModelRetry(model string, retry, maxRetries int, delay time.Duration, err string, discarded bool, agentName string) Event {
+	return &ModelRetryEvent{
+		Type:         "model_retry",
+		Model:        model,
+		Retry:        retry,
+		MaxRetries:   maxRetries,
+		DelayMs:      delay.Milliseconds(),
+		Error:        err,
+		Discarded:    discarded,
+		AgentContext: AgentContext{AgentName: agentName},
+	}
+}
+
+// ModelFailoverEvent is sent when the requests to a model kept failing and the
+// agent fails over to its fallback model for the rest of the turn.
+type ModelFailoverEvent struct {
+	Type      string `json:"type"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Error     string `json:"error"`
+	Discarded bool   `json:"discarded,omitempty"`
+	AgentContext
+}
+
+func ModelFailover(from, to, err string, discarded bool, agentName string) Event {
+	return &ModelFailoverEvent{
+		Type:         "model_failover",
+		From:         from,
+		To:           to,
+		Error:        err,
+		Discarded:    discarded,
+		AgentContext: AgentContext{AgentName: agentName},
+	}
+}
+
+// StallDetectedEvent is sent when the stream of a model sent nothing for
+// longer than the stream timeout of the agent. The request is then retried,
+// or the turn fails.
+type StallDetectedEvent struct {
+	Type      string `json:"type"`
+	Model     string `json:"model"`
+	TimeoutMs int64  `json:"timeout_ms"`
+	AgentContext
+}
+
+func StallDetected(model string, timeout time.Duration, agentName string) Event {
+	return &StallDetectedEvent{
+		Type:         "stall_detected",
+		Model:        model,
+		TimeoutMs:    timeout.Milliseconds(),
+		AgentContext: AgentContext{AgentName: agentName},
+	}
+}
+
+// GuardrailTriggeredEvent is sent when a guardrail of an agent found what it
+// forbids in an answer of the agent, or in the arguments of a tool call.
+type GuardrailTriggeredEvent struct {
+	Type      string            `json:"type"`
+	Guardrail string            `json:"guardrail"`
+	Action    guardrails.Action `json:"action"`
+	Reason    string            `json:"reason"`
+	Target    guardrails.Target `json:"target"`
+	// ToolName is the tool called, for the guardrails of tool arguments
+	ToolName string `json:"tool_name,omitempty"`
+	AgentContext
+}
+
+func GuardrailTriggered(violation guardrails.Violation, target guardrails.Target, toolName, agentName string) Event {
+	return &GuardrailTriggeredEvent{
+		Type:         "guardrail_triggered",
+		Guardrail:    violation.Guardrail,
+		Action:       violation.Action,
+		Reason:       violation.Reason,
+		Target:       target,
+		ToolName:     toolName,
+		AgentContext: AgentContext{AgentName: agentName},
+	}
+}
+
+// RequestThrottledEvent is sent when a request of an agent waits for the rate
+// limit of its provider, and again once it's sent.
+type RequestThrottledEvent struct {
+	Type      string `json:"type"`
+	Model     string `json:"model"`
+	Throttled bool   `json:"throttled"`
+	WaitMs    int64  `json:"wait_ms,omitempty"`
+	AgentContext
+}
+
+func RequestThrottled(model string, throttled bool, wait time.Duration, agentName string) Event {
+	return &RequestThrottledEvent{
+		Type:         "request_throttled",
+		Model:        model,
+		Throttled:    throttled,
+		WaitMs:       wait.Milliseconds(),
+		AgentContext: AgentContext{AgentName: agentName},
+	}
+}
+
+// PendingMessagesEvent is sent when the messages queued for a session while
+// one of its turns is in progress change: one was queued, or its turn started.
+type PendingMessagesEvent struct {
+	Type      string   `json:"type"`
+	SessionID string   `json:"session_id"`
+	Messages  []string `json:"messages"`
+	AgentContext
+}
+
+func PendingMessages(sessionID string, messages []string, agentName string) Event {
+	return &PendingMessagesEvent{
+		Type:         "pending_messages",
+		SessionID:    sessionID,
+		Messages:     messages,
+		AgentContext: AgentContext{AgentName: agentName},
+	}
+}
+
+// CallDeduplicatedEvent is sent when a model or tool call of an agent is
+// answered with the result of an identical call, with what was saved so far.
+type CallDeduplicatedEvent struct {
+	Type  string             `json:"type"`
+	Kind  string             `json:"kind"` // "model" or "tool"
+	Name  string             `json:"name"` // The model or the tool called
+	Saved DeduplicationStats `json:"saved"`
+	AgentContext
+}
+
+func CallDeduplicated(kind, name string, saved DeduplicationStats, agentName string) Event {
+	return &CallDeduplicatedEvent{
+		Type:         "call_deduplicated",
+		Kind:         kind,
+		Name:         name,
+		Saved:        saved,
+		AgentContext: AgentContext{AgentName: agentName},
+	}
+}
+
 type TokenUsageEvent struct {
 	Type      string `json:"type"`
 	SessionID string `json:"session_id"`
@@ -199,6 +370,8 @@ type Usage struct {
 	ContextLength int64   `json:"context_length"`
 	ContextLimit  int64   `json:"context_limit"`
 	Cost          float64 `json:"cost"`
+	// Tools is how many tokens the results of each tool take in the context
+	Tools []ToolUsage `json:"tools,omitempty"`
 }
 
 func TokenUsage(sessionID, agentName string, inputTokens, outputTokens, contextLength, contextLimit int64, cost float64) Event {
@@ -247,6 +420,61 @@ func SessionSummary(sessionID, summary, agentName string) Event {
 	}
 }
 
+// PostMortemEvent is sent when the post-mortem of a failed run was written.
+type PostMortemEvent struct {
+	Type       string `json:"type"`
+	SessionID  string `json:"session_id"`
+	PostMortem string `json:"post_mortem"`
+	AgentContext
+}
+
+func PostMortem(sessionID, postMortem, agentName string) Event {
+	return &PostMortemEvent{
+		Type:         "post_mortem",
+		SessionID:    sessionID,
+		PostMortem:   postMortem,
+		AgentContext: AgentContext{AgentName: agentName},
+	}
+}
+
+// ToolResultSummarizedEvent is sent when a large tool result was replaced by
+// a summary in the agent's context.
+type ToolResultSummarizedEvent struct {
+	Type           string         `json:"type"`
+	ToolCall       tools.ToolCall `json:"tool_call"`
+	Summary        string         `json:"summary"`
+	OriginalTokens int            `json:"original_tokens"`
+	AgentContext
+}
+
+func ToolResultSummarized(toolCall tools.ToolCall, summary string, originalTokens int, agentName string) Event {
+	return &ToolResultSummarizedEvent{
+		Type:           "tool_result_summarized",
+		ToolCall:       toolCall,
+		Summary:        summary,
+		OriginalTokens: originalTokens,
+		AgentContext:   AgentContext{AgentName: agentName},
+	}
+}
+
+// SessionRotatedEvent is sent when the governor continued a long-running
+// conversation in a fresh session seeded with the summary of the previous one.
+type SessionRotatedEvent struct {
+	Type              string `json:"type"`
+	PreviousSessionID string `json:"previous_session_id"`
+	SessionID         string `json:"session_id"`
+	AgentContext
+}
+
+func SessionRotated(previousSessionID, sessionID, agentName string) Event {
+	return &SessionRotatedEvent{
+		Type:              "session_rotated",
+		PreviousSessionID: previousSessionID,
+		SessionID:         sessionID,
+		AgentContext:      AgentContext{AgentName: agentName},
+	}
+}
+
 type SessionCompactionEvent struct {
 	Type      string `json:"type"`
 	SessionID string `json:"session_id"`
@@ -400,15 +628,18 @@ type AgentSwitchingEvent struct {
 	Switching bool   `json:"switching"`
 	FromAgent string `json:"from_agent,omitempty"`
 	ToAgent   string `json:"to_agent,omitempty"`
+	// Priority is the priority of the task transferred, empty when normal
+	Priority string `json:"priority,omitempty"`
 	AgentContext
 }
 
-func AgentSwitching(switching bool, fromAgent, toAgent string) Event {
+func AgentSwitching(switching bool, fromAgent, toAgent, priority string) Event {
 	return &AgentSwitchingEvent{
 		Type:         "agent_switching",
 		Switching:    switching,
 		FromAgent:    fromAgent,
 		ToAgent:      toAgent,
+		Priority:     priority,
 		AgentContext: AgentContext{AgentName: cmp.Or(toAgent, fromAgent)},
 	}
 }
@@ -431,6 +662,74 @@ func ToolsetInfo(availableTools int, loading bool, agentName string) Event {
 	}
 }
 
+// MCPServerStatusEvent is sent when the server of an MCP toolset crashed or
+// hung and is restarted, once it's reconnected, or when it couldn't be. For
+// servers run in a container, it's also sent when the container starts and
+// stops.
+type MCPServerStatusEvent struct {
+	Type           string            `json:"type"`
+	Server         string            `json:"server"`
+	Status         tools.ServerState `json:"status"`
+	Attempt        int               `json:"attempt,omitempty"`
+	Error          string            `json:"error,omitempty"`
+	AvailableTools int               `json:"available_tools,omitempty"`
+	Container      string            `json:"container,omitempty"`
+	AgentContext
+}
+
+func MCPServerStatus(status tools.ServerStatus, agentName string) Event {
+	return &MCPServerStatusEvent{
+		Type:           "mcp_server_status",
+		Server:         status.Server,
+		Status:         status.State,
+		Attempt:        status.Attempt,
+		Error:          status.Error,
+		AvailableTools: status.Tools,
+		Container:      status.Container,
+		AgentContext:   AgentContext{AgentName: agentName},
+	}
+}
+
+// ToolsReloadedEvent is sent once the toolsets of the agents were created
+// again from their configuration, with the tools of the current agent.
+type ToolsReloadedEvent struct {
+	Type           string `json:"type"`
+	AvailableTools int    `json:"available_tools"`
+	AgentContext
+}
+
+func ToolsReloaded(availableTools int, agentName string) Event {
+	return &ToolsReloadedEvent{
+		Type:           "tools_reloaded",
+		AvailableTools: availableTools,
+		AgentContext:   AgentContext{AgentName: agentName},
+	}
+}
+
+// SamplingUsageEvent is sent when an MCP server requested a completion from
+// the model of the agent, with the usage of the completion.
+type SamplingUsageEvent struct {
+	Type         string  `json:"type"`
+	Server       string  `json:"server"`
+	Model        string  `json:"model"`
+	InputTokens  int64   `json:"input_tokens"`
+	OutputTokens int64   `json:"output_tokens"`
+	Cost         float64 `json:"cost"`
+	AgentContext
+}
+
+func SamplingUsage(server, model string, inputTokens, outputTokens int64, cost float64, agentName string) Event {
+	return &SamplingUsageEvent{
+		Type:         "sampling_usage",
+		Server:       server,
+		Model:        model,
+		InputTokens:  inputTokens,
+		OutputTokens: outputTokens,
+		Cost:         cost,
+		AgentContext: AgentContext{AgentName: agentName},
+	}
+}
+
 // RAGIndexingStartedEvent is for RAG lifecycle events
 type RAGIndexingStartedEvent struct {
 	Type         string `json:"type"`
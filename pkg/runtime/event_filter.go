@@ -0,0 +1,115 @@
+package runtime
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EventLevel is how much of the events of a run a client subscribes to. Each
+// level includes the events of the levels below it.
+type EventLevel int
+
+const (
+	// EventLevelMessages sends the messages, whole instead of token by token,
+	// and the events the client must answer for the run to go on.
+	EventLevelMessages EventLevel = iota
+	// EventLevelToolCalls adds the tool calls and their results.
+	EventLevelToolCalls
+	// EventLevelUsage adds the token usage.
+	EventLevelUsage
+	// EventLevelDebug sends every event, with the per-token deltas.
+	EventLevelDebug
+)
+
+var eventLevelNames = []string{"messages", "tool_calls", "usage", "debug"}
+
+func (l EventLevel) String() string {
+	if l < 0 || int(l) >= len(eventLevelNames) {
+		return fmt.Sprintf("EventLevel(%d)", int(l))
+	}
+	return eventLevelNames[l]
+}
+
+// ParseEventLevel parses the name of an event level. An empty name is
+// EventLevelDebug: every event is sent.
+func ParseEventLevel(name string) (EventLevel, error) {
+	if name == "" {
+		return EventLevelDebug, nil
+	}
+	for i, n := range eventLevelNames {
+		if n == name {
+			return EventLevel(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown event level %q, expected one of %s", name, strings.Join(eventLevelNames, ", "))
+}
+
+// eventLevel returns the lowest level an event is sent at.
+func eventLevel(event Event) EventLevel {
+	switch event.(type) {
+	case *UserMessageEvent, *AgentChoiceEvent, *AgentChoiceReasoningEvent,
+		*StreamStartedEvent, *StreamStoppedEvent, *ErrorEvent, *WarningEvent, *ModelRetryEvent, *ModelFailoverEvent, *RequestThrottledEvent, *StallDetectedEvent, *PendingMessagesEvent, *GuardrailTriggeredEvent,
+		*ToolCallConfirmationEvent, *ElicitationRequestEvent, *AuthorizationEvent, *MaxIterationsReachedEvent,
+		*SessionTitleEvent, *SessionSummaryEvent, *SessionRotatedEvent, *PostMortemEvent:
+		return EventLevelMessages
+	case *ToolCallEvent, *ToolCallResponseEvent, *ToolResultSummarizedEvent, *HookBlockedEvent, *ShellOutputEvent:
+		return EventLevelToolCalls
+	case *TokenUsageEvent, *CallDeduplicatedEvent:
+		return EventLevelUsage
+	default:
+		return EventLevelDebug
+	}
+}
+
+// FilterEvents forwards the events of a run sent at the given level. Below
+// EventLevelDebug, the consecutive content and reasoning deltas of an agent
+// are merged into one event, sent when the next event arrives.
+func FilterEvents(in <-chan Event, level EventLevel) <-chan Event {
+	if level >= EventLevelDebug {
+		return in
+	}
+
+	out := make(chan Event, 128)
+	go func() {
+		defer close(out)
+
+		var pending Event
+		var content strings.Builder
+		flush := func() {
+			switch e := pending.(type) {
+			case *AgentChoiceEvent:
+				out <- &AgentChoiceEvent{Type: e.Type, Content: content.String(), AgentContext: e.AgentContext}
+			case *AgentChoiceReasoningEvent:
+				out <- &AgentChoiceReasoningEvent{Type: e.Type, Content: content.String(), AgentContext: e.AgentContext}
+			}
+			pending = nil
+			content.Reset()
+		}
+		defer flush()
+
+		for event := range in {
+			switch e := event.(type) {
+			case *AgentChoiceEvent:
+				if p, ok := pending.(*AgentChoiceEvent); !ok || p.AgentName != e.AgentName {
+					flush()
+					pending = e
+				}
+				content.WriteString(e.Content)
+			case *AgentChoiceReasoningEvent:
+				if p, ok := pending.(*AgentChoiceReasoningEvent); !ok || p.AgentName != e.AgentName {
+					flush()
+					pending = e
+				}
+				content.WriteString(e.Content)
+			default:
+				// Even the events that aren't forwarded, like tool calls,
+				// end the messages
+				flush()
+				if eventLevel(event) <= level {
+					out <- event
+				}
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,105 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/tools"
+)
+
+func TestParseEventLevel(t *testing.T) {
+	t.Parallel()
+
+	for name, want := range map[string]EventLevel{
+		"":           EventLevelDebug,
+		"messages":   EventLevelMessages,
+		"tool_calls": EventLevelToolCalls,
+		"usage":      EventLevelUsage,
+		"debug":      EventLevelDebug,
+	} {
+		level, err := ParseEventLevel(name)
+		require.NoError(t, err)
+		assert.Equal(t, want, level)
+	}
+
+	_, err := ParseEventLevel("everything")
+	require.ErrorContains(t, err, `unknown event level "everything"`)
+}
+
+func filterEvents(level EventLevel, events ...Event) []Event {
+	in := make(chan Event, len(events))
+	for _, e := range events {
+		in <- e
+	}
+	close(in)
+
+	var out []Event
+	for e := range FilterEvents(in, level) {
+		out = append(out, e)
+	}
+	return out
+}
+
+func runEvents() []Event {
+	toolCall := tools.ToolCall{ID: "call_1", Function: tools.FunctionCall{Name: "shell"}}
+	return []Event{
+		StreamStarted("session", "root"),
+		AgentChoiceReasoning("root", "Let me "),
+		AgentChoiceReasoning("root", "check"),
+		AgentChoice("root", "Check"),
+		AgentChoice("root", "ing."),
+		PartialToolCall(toolCall, tools.Tool{}, "root"),
+		ToolCall(toolCall, tools.Tool{}, "root"),
+		ToolCallResponse(toolCall, tools.Tool{}, tools.ResultSuccess("ok"), "ok", "root"),
+		TokenUsage("session", "root", 10, 5, 15, 1000, 0.01),
+		AgentChoice("root", "Done"),
+		StreamStopped("session", "root"),
+	}
+}
+
+func TestFilterEvents_Messages(t *testing.T) {
+	t.Parallel()
+
+	out := filterEvents(EventLevelMessages, runEvents()...)
+
+	require.Len(t, out, 5)
+	assert.IsType(t, &StreamStartedEvent{}, out[0])
+	assert.Equal(t, "Let me check", out[1].(*AgentChoiceReasoningEvent).Content)
+	assert.Equal(t, "Checking.", out[2].(*AgentChoiceEvent).Content)
+	assert.Equal(t, "root", out[2].(*AgentChoiceEvent).AgentName)
+	assert.Equal(t, "Done", out[3].(*AgentChoiceEvent).Content)
+	assert.IsType(t, &StreamStoppedEvent{}, out[4])
+}
+
+func TestFilterEvents_ToolCallsAndUsage(t *testing.T) {
+	t.Parallel()
+
+	out := filterEvents(EventLevelToolCalls, runEvents()...)
+	require.Len(t, out, 7)
+	assert.IsType(t, &ToolCallEvent{}, out[3])
+	assert.IsType(t, &ToolCallResponseEvent{}, out[4])
+
+	out = filterEvents(EventLevelUsage, runEvents()...)
+	require.Len(t, out, 8)
+	assert.IsType(t, &TokenUsageEvent{}, out[5])
+}
+
+func TestFilterEvents_Debug(t *testing.T) {
+	t.Parallel()
+
+	events := runEvents()
+	assert.Equal(t, events, filterEvents(EventLevelDebug, events...))
+}
+
+func TestFilterEvents_FlushesAtTheEnd(t *testing.T) {
+	t.Parallel()
+
+	out := filterEvents(EventLevelMessages, AgentChoice("root", "a"), AgentChoice("helper", "b"), AgentChoice("helper", "c"))
+
+	require.Len(t, out, 2)
+	assert.Equal(t, "a", out[0].(*AgentChoiceEvent).Content)
+	assert.Equal(t, "bc", out[1].(*AgentChoiceEvent).Content)
+	assert.Equal(t, "helper", out[1].(*AgentChoiceEvent).AgentName)
+}
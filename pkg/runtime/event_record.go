@@ -0,0 +1,50 @@
+package runtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// EventRecordVersion is the version of the serialization of events by
+// EventRecord. Fields may be added to events without changing it, it changes
+// when fields are renamed, removed or change meaning.
+const EventRecordVersion = 1
+
+// EventRecord is the serialization of an event for the programs consuming
+// the events of runs, one record per line.
+type EventRecord struct {
+	Version int       `json:"version"`
+	Type    string    `json:"type"`
+	Time    time.Time `json:"time"`
+	// Agent is the agent the event is about, if any.
+	Agent string `json:"agent,omitempty"`
+	// Event is the event, with the same type field.
+	Event json.RawMessage `json:"event"`
+}
+
+// NewEventRecord serializes an event that happened at the given time.
+func NewEventRecord(event Event, at time.Time) (EventRecord, error) {
+	buf, err := json.Marshal(event)
+	if err != nil {
+		return EventRecord{}, fmt.Errorf("encoding event: %w", err)
+	}
+
+	var base struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(buf, &base); err != nil {
+		return EventRecord{}, fmt.Errorf("reading event type: %w", err)
+	}
+	if base.Type == "" {
+		return EventRecord{}, fmt.Errorf("event %T has no type", event)
+	}
+
+	return EventRecord{
+		Version: EventRecordVersion,
+		Type:    base.Type,
+		Time:    at.UTC(),
+		Agent:   event.GetAgentName(),
+		Event:   buf,
+	}, nil
+}
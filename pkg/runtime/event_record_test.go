@@ -0,0 +1,51 @@
+package runtime
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/tools"
+)
+
+// The records are read by other programs: changing any of these is a
+// breaking change that needs a new EventRecordVersion.
+func TestEventRecord_Serialization(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	toolCall := tools.ToolCall{ID: "call_1", Type: "function", Function: tools.FunctionCall{Name: "shell", Arguments: `{"cmd":"ls"}`}}
+
+	tests := []struct {
+		event    Event
+		expected string
+	}{
+		{
+			event:    AgentChoice("root", "Hello"),
+			expected: `{"version":1,"type":"agent_choice","time":"2025-01-02T03:04:05Z","agent":"root","event":{"type":"agent_choice","content":"Hello","agent_name":"root"}}`,
+		},
+		{
+			event:    TokenUsage("session", "root", 10, 5, 15, 1000, 0.01),
+			expected: `{"version":1,"type":"token_usage","time":"2025-01-02T03:04:05Z","agent":"root","event":{"type":"token_usage","session_id":"session","usage":{"input_tokens":10,"output_tokens":5,"context_length":15,"context_limit":1000,"cost":0.01},"agent_name":"root"}}`,
+		},
+		{
+			event:    ToolCall(toolCall, tools.Tool{Name: "shell"}, "root"),
+			expected: `{"version":1,"type":"tool_call","time":"2025-01-02T03:04:05Z","agent":"root","event":{"type":"tool_call","tool_call":{"id":"call_1","type":"function","function":{"name":"shell","arguments":"{\"cmd\":\"ls\"}"}},"tool_definition":{"name":"shell","category":"","parameters":null,"outputSchema":null,"annotations":{}},"agent_name":"root"}}`,
+		},
+		{
+			event:    Error("boom"),
+			expected: `{"version":1,"type":"error","time":"2025-01-02T03:04:05Z","event":{"type":"error","error":"boom"}}`,
+		},
+	}
+	for _, tt := range tests {
+		record, err := NewEventRecord(tt.event, at)
+		require.NoError(t, err)
+
+		buf, err := json.Marshal(record)
+		require.NoError(t, err)
+		assert.JSONEq(t, tt.expected, string(buf))
+	}
+}
@@ -0,0 +1,86 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/audit"
+	"github.com/docker/cagent/pkg/guardrails"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/tools"
+)
+
+// checksOutput tells whether guardrails check the answers of the agent.
+func checksOutput(a *agent.Agent) bool {
+	return slices.ContainsFunc(a.Guardrails(), func(g *guardrails.Guardrail) bool {
+		return g.AppliesTo(guardrails.TargetOutput)
+	})
+}
+
+// checkOutputGuardrails checks the answer of the model against the
+// guardrails of the agent before it's used and shown. The redactions replace
+// its content; it returns false when a guardrail blocked the answer.
+func (r *LocalRuntime) checkOutputGuardrails(ctx context.Context, sess *session.Session, a *agent.Agent, res *streamResult, events chan Event) bool {
+	if !checksOutput(a) || strings.TrimSpace(res.Content) == "" {
+		return true
+	}
+
+	result := guardrails.Check(ctx, a.Guardrails(), guardrails.TargetOutput, res.Content)
+	for _, v := range result.Violations {
+		events <- GuardrailTriggered(v, guardrails.TargetOutput, "", a.Name())
+	}
+	r.recordGuardrailViolations(sess, a, tools.ToolCall{}, result.Violations)
+	if v := result.Blocked(); v != nil {
+		slog.Debug("Guardrail blocked the answer of the model", "agent", a.Name(), "guardrail", v.Guardrail, "reason", v.Reason)
+		events <- Error(fmt.Sprintf("Model answer blocked by guardrail %s: %s", v.Guardrail, v.Reason))
+		return false
+	}
+
+	res.Content = result.Text
+	return true
+}
+
+// checkToolGuardrails checks the arguments of a tool call against the
+// guardrails of the agent before the tool runs. It returns the tool call with
+// the redacted arguments, and the violation blocking it, if any.
+func (r *LocalRuntime) checkToolGuardrails(ctx context.Context, sess *session.Session, a *agent.Agent, toolCall tools.ToolCall, events chan Event) (tools.ToolCall, *guardrails.Violation) {
+	if len(a.Guardrails()) == 0 {
+		return toolCall, nil
+	}
+
+	result := guardrails.CheckArguments(ctx, a.Guardrails(), toolCall.Function.Arguments)
+	for _, v := range result.Violations {
+		events <- GuardrailTriggered(v, guardrails.TargetToolArguments, toolCall.Function.Name, a.Name())
+	}
+	// The audit log gets the redacted arguments, not what was redacted
+	redacted := toolCall
+	redacted.Function.Arguments = result.Text
+	r.recordGuardrailViolations(sess, a, redacted, result.Violations)
+
+	if v := result.Blocked(); v != nil {
+		slog.Debug("Guardrail blocked a tool call", "agent", a.Name(), "tool", toolCall.Function.Name, "guardrail", v.Guardrail, "reason", v.Reason)
+		return toolCall, v
+	}
+	return redacted, nil
+}
+
+// recordGuardrailViolations records the blocks and redactions of the
+// guardrails in the audit log. Warnings are only shown.
+func (r *LocalRuntime) recordGuardrailViolations(sess *session.Session, a *agent.Agent, toolCall tools.ToolCall, violations []guardrails.Violation) {
+	for _, v := range violations {
+		var eventType audit.EventType
+		switch v.Action {
+		case guardrails.ActionBlock:
+			eventType = audit.GuardrailBlocked
+		case guardrails.ActionRedact:
+			eventType = audit.GuardrailRedacted
+		default:
+			continue
+		}
+		r.recordAudit(sess, a, toolCall, audit.Event{Type: eventType, Source: v.Guardrail, Reason: v.Reason})
+	}
+}
@@ -0,0 +1,117 @@
+package runtime
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/audit"
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/guardrails"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/team"
+	"github.com/docker/cagent/pkg/tools"
+)
+
+func TestRunStream_GuardrailsRedactAndBlock(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+	agentTools := []tools.Tool{{
+		Name:       "send_email",
+		Parameters: map[string]any{},
+		Handler: func(_ context.Context, call tools.ToolCall) (*tools.ToolCallResult, error) {
+			calls = append(calls, call.Function.Arguments)
+			return tools.ResultSuccess("sent"), nil
+		},
+	}}
+
+	pii, err := guardrails.PII([]string{guardrails.PIIEmail})
+	require.NoError(t, err)
+	keywords, err := guardrails.Keywords([]string{"rm -rf"})
+	require.NoError(t, err)
+
+	prov := &queueProvider{id: "test/mock-model", streams: []chat.MessageStream{
+		newStreamBuilder().
+			AddToolCallName("call_1", "send_email").
+			AddToolCallArguments("call_1", `{"to":"jane@example.com","body":"hi"}`).
+			AddToolCallName("call_2", "send_email").
+			AddToolCallArguments("call_2", `{"to":"ops","body":"run rm -rf /"}`).
+			Build(),
+		newStreamBuilder().AddContent("Sent to jane@example.com").AddStopWithUsage(10, 5).Build(),
+	}}
+	root := agent.New("root", "You are a test agent",
+		agent.WithModel(prov),
+		agent.WithToolSets(newStubToolSet(nil, agentTools, nil)),
+		agent.WithGuardrails(
+			&guardrails.Guardrail{Name: "pii", Action: guardrails.ActionRedact, Checker: pii},
+			&guardrails.Guardrail{Name: "commands", Action: guardrails.ActionBlock, Checker: keywords, Targets: []guardrails.Target{guardrails.TargetToolArguments}},
+		),
+	)
+	log := &auditLog{}
+	rt, err := New(team.New(team.WithAgents(root)), WithSessionCompaction(false), WithModelStore(mockModelStore{}), WithAuditRecorder(log))
+	require.NoError(t, err)
+
+	sess := session.New(session.WithUserMessage("Email Jane"), session.WithTitle("Unit Test"))
+	var (
+		triggered []*GuardrailTriggeredEvent
+		confirmed []string
+		shown     strings.Builder
+	)
+	for ev := range rt.RunStream(t.Context(), sess) {
+		switch e := ev.(type) {
+		case *GuardrailTriggeredEvent:
+			triggered = append(triggered, e)
+		case *ToolCallConfirmationEvent:
+			confirmed = append(confirmed, e.ToolCall.Function.Arguments)
+			rt.Resume(t.Context(), ResumeTypeApprove)
+		case *AgentChoiceEvent:
+			shown.WriteString(e.Content)
+		}
+	}
+
+	// The user approves the redacted arguments, and isn't asked about the
+	// blocked call
+	require.Len(t, confirmed, 1)
+	assert.JSONEq(t, `{"to":"[REDACTED]","body":"hi"}`, confirmed[0])
+
+	// The tool runs with the redacted arguments, the blocked call doesn't run
+	require.Len(t, calls, 1)
+	assert.JSONEq(t, `{"to":"[REDACTED]","body":"hi"}`, calls[0])
+
+	messages := sess.GetAllMessages()
+	assert.Equal(t, "Tool call blocked by guardrail commands: the keyword \"rm -rf\"", messages[3].Message.Content)
+	assert.Equal(t, "Sent to [REDACTED]", messages[len(messages)-1].Message.Content)
+
+	// The answer is only shown once redacted
+	assert.Equal(t, "Sent to [REDACTED]", shown.String())
+
+	require.Len(t, triggered, 3)
+	assert.Equal(t, guardrails.ActionRedact, triggered[0].Action)
+	assert.Equal(t, "send_email", triggered[0].ToolName)
+	assert.Equal(t, guardrails.ActionBlock, triggered[1].Action)
+	assert.Equal(t, guardrails.TargetOutput, triggered[2].Target)
+
+	// The blocks and redactions are audited, with the redacted arguments
+	var audited []audit.Event
+	for _, event := range log.events {
+		if event.Type == audit.GuardrailBlocked || event.Type == audit.GuardrailRedacted {
+			audited = append(audited, event)
+		}
+	}
+	require.Len(t, audited, 3)
+	assert.Equal(t, audit.GuardrailRedacted, audited[0].Type)
+	assert.Equal(t, "pii", audited[0].Source)
+	assert.Equal(t, "call_1", audited[0].ToolCallID)
+	assert.JSONEq(t, `{"to":"[REDACTED]","body":"hi"}`, audited[0].Arguments)
+	assert.Equal(t, audit.GuardrailBlocked, audited[1].Type)
+	assert.Equal(t, "commands", audited[1].Source)
+	assert.Equal(t, "call_2", audited[1].ToolCallID)
+	assert.Equal(t, audit.GuardrailRedacted, audited[2].Type)
+	assert.Empty(t, audited[2].Tool)
+	assert.Equal(t, sess.ID, audited[2].SessionID)
+}
@@ -0,0 +1,80 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/tools"
+)
+
+// loopDetector spots an agent calling the same tool with the same arguments
+// over and over, making no progress.
+type loopDetector struct {
+	limit int
+	last  string
+	count int
+}
+
+// newLoopDetector returns a detector of limit identical calls in a row. A
+// limit of zero detects nothing.
+func newLoopDetector(limit int) *loopDetector {
+	return &loopDetector{limit: limit}
+}
+
+// record records the tool calls the agent asked for, in order, and returns
+// the first call that was repeated limit times in a row.
+func (d *loopDetector) record(calls []tools.ToolCall) (tools.ToolCall, bool) {
+	for _, call := range calls {
+		signature := callSignature(call)
+		if signature == d.last {
+			d.count++
+		} else {
+			d.last = signature
+			d.count = 1
+		}
+
+		if d.limit > 0 && d.count >= d.limit {
+			return call, true
+		}
+	}
+	return tools.ToolCall{}, false
+}
+
+// callSignature identifies a call by its tool and its arguments, ignoring
+// how the arguments are formatted.
+func callSignature(call tools.ToolCall) string {
+	arguments := call.Function.Arguments
+
+	var decoded any
+	if err := json.Unmarshal([]byte(arguments), &decoded); err == nil {
+		if normalized, err := json.Marshal(decoded); err == nil {
+			arguments = string(normalized)
+		}
+	}
+
+	return call.Function.Name + "\x00" + arguments
+}
+
+// stopLooping stops an agent that repeated a tool call too many times. The
+// calls it asked for aren't run but still get a response, so that the
+// conversation can go on after the user steps in.
+func (r *LocalRuntime) stopLooping(ctx context.Context, sess *session.Session, a *agent.Agent, calls []tools.ToolCall, repeated tools.ToolCall, agentTools []tools.Tool, events chan Event) {
+	slog.Warn("Agent stopped for looping", "agent", a.Name(), "tool", repeated.Function.Name, "repeats", a.MaxRepeatedToolCalls(), "session_id", sess.ID)
+
+	message := fmt.Sprintf("Agent %s was stopped: it called %s with the same arguments %d times in a row without making progress.",
+		a.Name(), repeated.Function.Name, a.MaxRepeatedToolCalls())
+
+	agentToolMap := make(map[string]tools.Tool, len(agentTools))
+	for _, t := range agentTools {
+		agentToolMap[t.Name] = t
+	}
+	for _, call := range calls {
+		r.addToolErrorResponse(ctx, sess, call, agentToolMap[call.Function.Name], events, a, "The tool call was not run. "+message)
+	}
+
+	events <- Error(message)
+}
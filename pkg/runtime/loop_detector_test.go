@@ -0,0 +1,101 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/team"
+	"github.com/docker/cagent/pkg/tools"
+)
+
+func toolCall(name, arguments string) tools.ToolCall {
+	return tools.ToolCall{Function: tools.FunctionCall{Name: name, Arguments: arguments}}
+}
+
+func TestLoopDetector(t *testing.T) {
+	t.Parallel()
+
+	d := newLoopDetector(3)
+
+	_, looping := d.record([]tools.ToolCall{toolCall("read", `{"path":"a"}`), toolCall("read", `{"path": "a"}`)})
+	require.False(t, looping)
+
+	// Another call breaks the series
+	_, looping = d.record([]tools.ToolCall{toolCall("read", `{"path":"b"}`)})
+	require.False(t, looping)
+	_, looping = d.record([]tools.ToolCall{toolCall("read", `{"path":"b"}`)})
+	require.False(t, looping)
+
+	repeated, looping := d.record([]tools.ToolCall{toolCall("read", `{ "path" : "b" }`)})
+	require.True(t, looping)
+	require.Equal(t, "read", repeated.Function.Name)
+}
+
+func TestLoopDetector_Disabled(t *testing.T) {
+	t.Parallel()
+
+	d := newLoopDetector(0)
+	for range 10 {
+		_, looping := d.record([]tools.ToolCall{toolCall("read", `{}`)})
+		require.False(t, looping)
+	}
+}
+
+func TestRunStream_StopsLoopingAgent(t *testing.T) {
+	var executed int
+	agentTools := []tools.Tool{{
+		Name:       "status",
+		Parameters: map[string]any{},
+		Handler: func(context.Context, tools.ToolCall) (*tools.ToolCallResult, error) {
+			executed++
+			return tools.ResultSuccess("pending"), nil
+		},
+	}}
+
+	var streams []chat.MessageStream
+	for i := range 5 {
+		id := string(rune('a' + i))
+		streams = append(streams, newStreamBuilder().
+			AddToolCallName(id, "status").
+			AddToolCallArguments(id, `{"job":"build"}`).
+			Build())
+	}
+
+	prov := &queueProvider{id: "test/mock-model", streams: streams}
+	root := agent.New("root", "You are a test agent",
+		agent.WithModel(prov),
+		agent.WithToolSets(newStubToolSet(nil, agentTools, nil)),
+		agent.WithMaxRepeatedToolCalls(3),
+	)
+	tm := team.New(team.WithAgents(root))
+
+	rt, err := New(tm, WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+
+	sess := session.New(session.WithUserMessage("Wait for the build"), session.WithTitle("Unit Test"), session.WithToolsApproved(true))
+	var events []Event
+	for ev := range rt.RunStream(t.Context(), sess) {
+		events = append(events, ev)
+	}
+
+	var errorEvent *ErrorEvent
+	for _, ev := range events {
+		if e, ok := ev.(*ErrorEvent); ok {
+			errorEvent = e
+		}
+	}
+	require.NotNil(t, errorEvent)
+	require.Contains(t, errorEvent.Error, "called status with the same arguments 3 times in a row")
+
+	// The third call isn't run but still gets a response
+	require.Equal(t, 2, executed)
+	messages := sess.GetAllMessages()
+	last := messages[len(messages)-1]
+	require.Equal(t, chat.MessageRoleTool, last.Message.Role)
+	require.Contains(t, last.Message.Content, "The tool call was not run.")
+}
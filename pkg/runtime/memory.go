@@ -0,0 +1,79 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/tools"
+	"github.com/docker/cagent/pkg/tools/builtin"
+)
+
+// unwrapMemoryTool extracts a memory tool from the toolsets wrapping it.
+func unwrapMemoryTool(toolset tools.ToolSet) *builtin.MemoryTool {
+	for toolset != nil {
+		switch ts := toolset.(type) {
+		case *builtin.MemoryTool:
+			return ts
+		case *agent.StartableToolSet:
+			toolset = ts.ToolSet
+		case interface{ Unwrap() tools.ToolSet }:
+			toolset = ts.Unwrap()
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// recallMemories returns the memories of the agent relevant to the last
+// message of the user, as instructions for the model, or an empty string if
+// none is. Only the memory toolsets with recall enabled recall memories.
+func (r *LocalRuntime) recallMemories(ctx context.Context, a *agent.Agent, sess *session.Session) string {
+	message := sess.GetLastUserMessageContent()
+
+	var lines []string
+	for _, toolset := range a.ToolSets() {
+		memoryTool := unwrapMemoryTool(toolset)
+		if memoryTool == nil {
+			continue
+		}
+
+		memories, err := memoryTool.Recall(ctx, message)
+		if err != nil {
+			slog.Warn("Failed to recall memories", "agent", a.Name(), "error", err)
+			continue
+		}
+		for _, memory := range memories {
+			lines = append(lines, fmt.Sprintf("- %s (ID: %s)", memory.Memory, memory.ID))
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+
+	slog.Debug("Recalled memories", "agent", a.Name(), "count", len(lines))
+	return "## Memories\n\nThese memories, saved in earlier conversations, may be relevant to the message of the user:\n" + strings.Join(lines, "\n")
+}
+
+// withMemories adds the recalled memories to the system messages sent to the
+// model. They aren't added to the session.
+func withMemories(messages []chat.Message, memories string) []chat.Message {
+	if memories == "" {
+		return messages
+	}
+
+	i := 0
+	for i < len(messages) && messages[i].Role == chat.MessageRoleSystem {
+		i++
+	}
+	return slices.Insert(slices.Clip(messages), i, chat.Message{
+		Role:    chat.MessageRoleSystem,
+		Content: memories,
+	})
+}
@@ -0,0 +1,80 @@
+package runtime
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/memory/database"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/team"
+	"github.com/docker/cagent/pkg/tools"
+	"github.com/docker/cagent/pkg/tools/builtin"
+)
+
+type staticMemories []database.UserMemory
+
+func (m staticMemories) AddMemory(context.Context, database.UserMemory) error { return nil }
+
+func (m staticMemories) GetMemories(context.Context) ([]database.UserMemory, error) { return m, nil }
+
+func (m staticMemories) DeleteMemory(context.Context, database.UserMemory) error { return nil }
+
+// messagesProvider records the messages of each call to the model.
+type messagesProvider struct {
+	queueProvider
+	calls [][]chat.Message
+}
+
+func (p *messagesProvider) CreateChatCompletionStream(ctx context.Context, messages []chat.Message, agentTools []tools.Tool) (chat.MessageStream, error) {
+	p.calls = append(p.calls, messages)
+	return p.queueProvider.CreateChatCompletionStream(ctx, messages, agentTools)
+}
+
+func TestRunStream_RecallsMemories(t *testing.T) {
+	t.Parallel()
+
+	memories := staticMemories{
+		{ID: "1", Memory: "The user deploys with Helm charts"},
+		{ID: "2", Memory: "The user likes cats"},
+	}
+	prov := &messagesProvider{queueProvider: queueProvider{id: "test/mock-model", streams: []chat.MessageStream{
+		newStreamBuilder().AddContent("Run helm upgrade").AddStopWithUsage(10, 5).Build(),
+	}}}
+	root := agent.New("root", "You are a test agent",
+		agent.WithModel(prov),
+		agent.WithToolSets(builtin.NewMemoryTool(memories, builtin.WithRecall(3))),
+	)
+	rt, err := New(team.New(team.WithAgents(root)), WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+
+	sess := session.New(session.WithUserMessage("How do I deploy the charts?"), session.WithTitle("Unit Test"), session.WithToolsApproved(true))
+	for range rt.RunStream(t.Context(), sess) {
+	}
+
+	require.Len(t, prov.calls, 1)
+	messages := prov.calls[0]
+
+	var recalled []string
+	for i, message := range messages {
+		if message.Role == chat.MessageRoleSystem && i > 0 && messages[i-1].Role != chat.MessageRoleSystem {
+			t.Fatalf("system message %d comes after the conversation", i)
+		}
+		if message.Role == chat.MessageRoleSystem && strings.HasPrefix(message.Content, "## Memories") {
+			recalled = append(recalled, message.Content)
+		}
+	}
+	require.Len(t, recalled, 1)
+	assert.Contains(t, recalled[0], "The user deploys with Helm charts (ID: 1)")
+	assert.NotContains(t, recalled[0], "cats")
+
+	// The memories aren't added to the session
+	for _, message := range sess.GetAllMessages() {
+		assert.NotContains(t, message.Message.Content, "Helm charts")
+	}
+}
@@ -0,0 +1,94 @@
+package runtime
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/hooks"
+	"github.com/docker/cagent/pkg/session"
+)
+
+// holdsOutput tells whether the answers of the agent are checked by
+// guardrails or changed by post-model-call hooks. Those answers aren't
+// streamed, but shown once final.
+func holdsOutput(a *agent.Agent) bool {
+	return checksOutput(a) || (a.Hooks() != nil && len(a.Hooks().PostModelCall) > 0)
+}
+
+// runPreModelHooks executes the pre-model-call hooks of the agent, if
+// configured. It returns the messages to send to the model, with the context
+// the hooks added, and false when a hook blocked the call.
+func (r *LocalRuntime) runPreModelHooks(ctx context.Context, hooksExec *hooks.Executor, sess *session.Session, a *agent.Agent, modelID string, messages []chat.Message, events chan Event) ([]chat.Message, bool) {
+	if hooksExec == nil || !hooksExec.HasPreModelCallHooks() {
+		return messages, true
+	}
+
+	result, err := hooksExec.ExecutePreModelCall(ctx, &hooks.Input{
+		SessionID: sess.ID,
+		Cwd:       r.workingDir,
+		AgentName: a.Name(),
+		Model:     modelID,
+		Messages:  messages,
+	})
+	if err != nil {
+		slog.Warn("Pre-model hook execution failed", "agent", a.Name(), "error", err)
+		return messages, true
+	}
+	if result.SystemMessage != "" {
+		events <- Warning(result.SystemMessage, a.Name())
+	}
+	if !result.Allowed {
+		slog.Debug("Pre-model hook blocked the model call", "agent", a.Name(), "message", result.Message)
+		events <- Error("Model call blocked by hook: " + result.Message)
+		return nil, false
+	}
+
+	// The context is only sent with this call, it isn't added to the session
+	if result.AdditionalContext != "" {
+		messages = append(slices.Clip(messages), chat.Message{
+			Role:    chat.MessageRoleSystem,
+			Content: result.AdditionalContext,
+		})
+	}
+	return messages, true
+}
+
+// runPostModelHooks executes the post-model-call hooks of the agent, if
+// configured, before its answer is used. The hooks may replace the content of
+// the answer; it returns false when a hook blocked the answer.
+func (r *LocalRuntime) runPostModelHooks(ctx context.Context, hooksExec *hooks.Executor, sess *session.Session, a *agent.Agent, modelID string, res *streamResult, events chan Event) bool {
+	if hooksExec == nil || !hooksExec.HasPostModelCallHooks() {
+		return true
+	}
+
+	result, err := hooksExec.ExecutePostModelCall(ctx, &hooks.Input{
+		SessionID: sess.ID,
+		Cwd:       r.workingDir,
+		AgentName: a.Name(),
+		Model:     modelID,
+		Response: &hooks.ModelResponse{
+			Content:   res.Content,
+			ToolCalls: res.Calls,
+		},
+	})
+	if err != nil {
+		slog.Warn("Post-model hook execution failed", "agent", a.Name(), "error", err)
+		return true
+	}
+	if result.SystemMessage != "" {
+		events <- Warning(result.SystemMessage, a.Name())
+	}
+	if !result.Allowed {
+		slog.Debug("Post-model hook blocked the answer of the model", "agent", a.Name(), "message", result.Message)
+		events <- Error("Model answer blocked by hook: " + result.Message)
+		return false
+	}
+
+	if result.UpdatedContent != nil {
+		res.Content = *result.UpdatedContent
+	}
+	return true
+}
@@ -0,0 +1,102 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/config/latest"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/team"
+	"github.com/docker/cagent/pkg/tools"
+)
+
+func TestRunStream_HooksMutateModelAndToolCalls(t *testing.T) {
+	t.Parallel()
+
+	var args string
+	agentTools := []tools.Tool{{
+		Name:       "shell",
+		Parameters: map[string]any{},
+		Handler: func(_ context.Context, call tools.ToolCall) (*tools.ToolCallResult, error) {
+			args = call.Function.Arguments
+			return tools.ResultSuccess("ok"), nil
+		},
+	}}
+
+	prov := &queueProvider{id: "test/mock-model", streams: []chat.MessageStream{
+		newStreamBuilder().
+			AddToolCallName("call_1", "shell").
+			AddToolCallArguments("call_1", `{"cmd":"ls"}`).
+			Build(),
+		newStreamBuilder().AddContent("The password is hunter2").AddStopWithUsage(10, 5).Build(),
+	}}
+	root := agent.New("root", "You are a test agent",
+		agent.WithModel(prov),
+		agent.WithToolSets(newStubToolSet(nil, agentTools, nil)),
+		agent.WithHooks(&latest.HooksConfig{
+			PreToolUse: []latest.HookMatcherConfig{{
+				Matcher: "shell",
+				Hooks: []latest.HookDefinition{{
+					Type:    "command",
+					Command: `echo '{"hook_specific_output":{"updated_input":{"cmd":"ls -h"}}}'`,
+				}},
+			}},
+			PostModelCall: []latest.HookDefinition{{
+				Type:    "command",
+				Command: `grep -q hunter2 && echo '{"hook_specific_output":{"updated_content":"The password is [redacted]"}}' || true`,
+			}},
+		}),
+	)
+	rt, err := New(team.New(team.WithAgents(root)), WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+
+	sess := session.New(session.WithUserMessage("List the files"), session.WithTitle("Unit Test"), session.WithToolsApproved(true))
+	var shown []string
+	for ev := range rt.RunStream(t.Context(), sess) {
+		if choice, ok := ev.(*AgentChoiceEvent); ok {
+			shown = append(shown, choice.Content)
+		}
+	}
+
+	assert.JSONEq(t, `{"cmd":"ls -h"}`, args)
+	// The answer isn't streamed before the hook replaced it
+	assert.Equal(t, []string{"The password is [redacted]"}, shown)
+
+	messages := sess.GetAllMessages()
+	assert.Equal(t, "The password is [redacted]", messages[len(messages)-1].Message.Content)
+}
+
+func TestRunStream_PreModelHookBlocksCall(t *testing.T) {
+	t.Parallel()
+
+	prov := &queueProvider{id: "test/mock-model", streams: []chat.MessageStream{
+		newStreamBuilder().AddContent("Hello").AddStopWithUsage(10, 5).Build(),
+	}}
+	root := agent.New("root", "You are a test agent",
+		agent.WithModel(prov),
+		agent.WithHooks(&latest.HooksConfig{
+			PreModelCall: []latest.HookDefinition{{
+				Type:    "command",
+				Command: "echo 'Model calls are disabled' >&2; exit 2",
+			}},
+		}),
+	)
+	rt, err := New(team.New(team.WithAgents(root)), WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+
+	sess := session.New(session.WithUserMessage("Hi"), session.WithTitle("Unit Test"))
+	var errs []string
+	for ev := range rt.RunStream(t.Context(), sess) {
+		if e, ok := ev.(*ErrorEvent); ok {
+			errs = append(errs, e.Error)
+		}
+	}
+
+	assert.Equal(t, []string{"Model call blocked by hook: Model calls are disabled"}, errs)
+	assert.Len(t, sess.GetAllMessages(), 1)
+}
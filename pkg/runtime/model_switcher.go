@@ -6,10 +6,12 @@ import (
 	"log/slog"
 	"strings"
 
+	"github.com/docker/cagent/pkg/config"
 	"github.com/docker/cagent/pkg/config/latest"
 	"github.com/docker/cagent/pkg/environment"
 	"github.com/docker/cagent/pkg/model/provider"
 	"github.com/docker/cagent/pkg/model/provider/options"
+	"github.com/docker/cagent/pkg/modelsdev"
 )
 
 // ModelChoice represents a model available for selection in the TUI picker.
@@ -28,6 +30,10 @@ type ModelChoice struct {
 	IsCurrent bool
 	// IsCustom indicates this is a custom model from the session history (not from config)
 	IsCustom bool
+	// ContextLimit is the size of the context window in tokens, 0 when unknown
+	ContextLimit int
+	// Cost is the price of the model per million tokens, nil when unknown
+	Cost *modelsdev.Cost
 }
 
 // ModelSwitcher is an optional interface for runtimes that support changing the model
@@ -44,6 +50,10 @@ type ModelSwitcher interface {
 	// This includes all models defined in the config, with the current agent's
 	// default model marked as IsDefault.
 	AvailableModels(ctx context.Context) []ModelChoice
+
+	// DescribeModels fills the context window and the pricing of the models,
+	// when they are known.
+	DescribeModels(ctx context.Context, models []ModelChoice)
 }
 
 // ModelSwitcherConfig holds the configuration needed for model switching.
@@ -59,6 +69,8 @@ type ModelSwitcherConfig struct {
 	EnvProvider environment.Provider
 	// AgentDefaultModels maps agent names to their configured default model references
 	AgentDefaultModels map[string]string
+	// ModelAllowlist restricts the models that can be switched to, nil allows every model
+	ModelAllowlist *config.ModelAllowlist
 }
 
 // SetAgentModel implements ModelSwitcher for LocalRuntime.
@@ -276,8 +288,32 @@ func (r *LocalRuntime) AvailableModels(_ context.Context) []ModelChoice {
 	return choices
 }
 
+// DescribeModels implements ModelSwitcher for LocalRuntime.
+func (r *LocalRuntime) DescribeModels(ctx context.Context, models []ModelChoice) {
+	if r.modelsStore == nil {
+		return
+	}
+
+	for i := range models {
+		// Alloy models have no provider, and several models
+		if models[i].Provider == "" || models[i].Model == "" {
+			continue
+		}
+		m, err := r.modelsStore.GetModel(ctx, models[i].Provider+"/"+models[i].Model)
+		if err != nil || m == nil {
+			continue
+		}
+		models[i].ContextLimit = m.Limit.Context
+		models[i].Cost = m.Cost
+	}
+}
+
 // createProviderFromConfig creates a provider from a ModelConfig using the runtime's configuration.
 func (r *LocalRuntime) createProviderFromConfig(ctx context.Context, cfg *latest.ModelConfig) (provider.Provider, error) {
+	if err := r.modelSwitcherCfg.ModelAllowlist.CheckModel(cfg, r.modelSwitcherCfg.Models); err != nil {
+		return nil, err
+	}
+
 	opts := []options.Opt{
 		options.WithGateway(r.modelSwitcherCfg.ModelsGateway),
 		options.WithProviders(r.modelSwitcherCfg.Providers),
@@ -1,9 +1,12 @@
 package runtime
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/docker/cagent/pkg/modelsdev"
 )
 
 func TestIsInlineAlloySpec(t *testing.T) {
@@ -69,3 +72,31 @@ func TestIsInlineAlloySpec(t *testing.T) {
 		})
 	}
 }
+
+type describingModelStore map[string]*modelsdev.Model
+
+func (s describingModelStore) GetModel(_ context.Context, id string) (*modelsdev.Model, error) {
+	return s[id], nil
+}
+
+func TestDescribeModels(t *testing.T) {
+	t.Parallel()
+
+	cost := &modelsdev.Cost{Input: 3, Output: 15}
+	r := &LocalRuntime{modelsStore: describingModelStore{
+		"anthropic/claude-sonnet-4-0": {Cost: cost, Limit: modelsdev.Limit{Context: 200_000}},
+	}}
+
+	models := []ModelChoice{
+		{Name: "sonnet", Provider: "anthropic", Model: "claude-sonnet-4-0"},
+		{Name: "unknown", Provider: "openai", Model: "gpt-unknown"},
+		{Name: "alloy", Model: "openai/gpt-4o,anthropic/claude-sonnet-4-0"},
+	}
+	r.DescribeModels(t.Context(), models)
+
+	assert.Equal(t, 200_000, models[0].ContextLimit)
+	assert.Equal(t, cost, models[0].Cost)
+	assert.Zero(t, models[1].ContextLimit)
+	assert.Nil(t, models[1].Cost)
+	assert.Nil(t, models[2].Cost)
+}
@@ -0,0 +1,143 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/model/provider"
+	"github.com/docker/cagent/pkg/model/provider/options"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/team"
+)
+
+const (
+	postMortemSystemPrompt = "You are a helpful AI assistant that analyzes the failed runs of other AI agents. You read what the agent did and explain, briefly and factually, why it didn't succeed and how the next attempt could."
+	postMortemUserPrompt   = `The run of an AI agent below didn't succeed: %s.
+
+Write a post-mortem of the run with these sections:
+- Attempted: what the agent tried to do, step by step.
+- Failed tools: the tool calls that failed or didn't give the expected result, with their error.
+- Suspected cause: the most likely reason the run didn't succeed.
+- Next steps: what the next attempt should do differently.
+
+Keep identifiers verbatim: file paths, commands, error messages. Return ONLY the post-mortem, nothing else.
+
+Run log:
+%s`
+
+	// maxPostMortemEntryLength caps the size of each message of the run log.
+	maxPostMortemEntryLength = 2000
+)
+
+// PostMortemWriter is an optional interface for runtimes that can write the
+// post-mortem of a run that failed or was aborted.
+type PostMortemWriter interface {
+	// WritePostMortem analyzes the last run of the session, that ended with
+	// failure, and saves the post-mortem with the session so that the next
+	// attempt can learn from it.
+	WritePostMortem(ctx context.Context, sess *session.Session, failure string, events chan Event)
+}
+
+// WritePostMortem implements PostMortemWriter for LocalRuntime. The
+// post-mortem is written by the agent's compaction model, a cheaper model
+// when one is configured, or else by the agent's model.
+func (r *LocalRuntime) WritePostMortem(ctx context.Context, sess *session.Session, failure string, events chan Event) {
+	a, err := r.team.Agent(r.currentAgent)
+	if err != nil {
+		events <- Error(err.Error())
+		return
+	}
+
+	runLog := buildRunLog(sess.GetAllMessages())
+	if runLog == "" {
+		events <- Warning("There is no run to write a post-mortem for.", a.Name())
+		return
+	}
+
+	model, _ := a.Compaction()
+	if model == nil {
+		model = a.Model()
+	}
+
+	slog.Debug("Writing post-mortem", "session_id", sess.ID, "failure", failure)
+
+	postMortem := generatePostMortem(ctx, model, fmt.Sprintf(postMortemUserPrompt, failure, truncateUTF8(runLog, maxSummaryInputLength)))
+	if postMortem == "" {
+		events <- Warning("The post-mortem couldn't be written.", a.Name())
+		return
+	}
+
+	sess.PostMortem = postMortem
+	if err := r.sessionStore.UpdateSession(context.WithoutCancel(ctx), sess); err != nil {
+		slog.Warn("Failed to save post-mortem", "session_id", sess.ID, "error", err)
+	}
+
+	events <- PostMortem(sess.ID, postMortem, a.Name())
+}
+
+// buildRunLog describes the messages of the last run: from the last message
+// the user sent to the end of the session.
+func buildRunLog(messages []session.Message) string {
+	start := -1
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Message.Role == chat.MessageRoleUser && !messages[i].Implicit {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return ""
+	}
+
+	toolNames := make(map[string]string)
+
+	var builder strings.Builder
+	for i := start; i < len(messages); i++ {
+		msg := &messages[i]
+		switch {
+		case msg.TurnCancelled:
+			builder.WriteString("\n[The user cancelled the run]")
+		case msg.Message.Role == chat.MessageRoleUser && !msg.Implicit:
+			fmt.Fprintf(&builder, "\nUser: %s", truncateUTF8(msg.Message.Content, maxPostMortemEntryLength))
+		case msg.Message.Role == chat.MessageRoleAssistant:
+			if content := strings.TrimSpace(msg.Message.Content); content != "" {
+				fmt.Fprintf(&builder, "\nAssistant (%s): %s", msg.AgentName, truncateUTF8(content, maxPostMortemEntryLength))
+			}
+			for _, toolCall := range msg.Message.ToolCalls {
+				toolNames[toolCall.ID] = toolCall.Function.Name
+				fmt.Fprintf(&builder, "\nTool call (%s): %s %s", msg.AgentName, toolCall.Function.Name, truncateUTF8(toolCall.Function.Arguments, maxPostMortemEntryLength))
+			}
+		case msg.Message.Role == chat.MessageRoleTool:
+			fmt.Fprintf(&builder, "\nTool result (%s): %s", toolNames[msg.Message.ToolCallID], truncateUTF8(msg.Message.Content, maxPostMortemEntryLength))
+		}
+	}
+	return builder.String()
+}
+
+func generatePostMortem(ctx context.Context, model provider.Provider, userPrompt string) string {
+	postMortemModel := provider.CloneWithOptions(ctx, model, options.WithStructuredOutput(nil))
+	newTeam := team.New(
+		team.WithAgents(agent.New("root", postMortemSystemPrompt, agent.WithModel(postMortemModel))),
+	)
+
+	postMortemSession := session.New(session.WithSystemMessage(postMortemSystemPrompt))
+	postMortemSession.AddMessage(session.UserMessage(userPrompt))
+	postMortemSession.Title = "Writing post-mortem..."
+
+	postMortemRuntime, err := New(newTeam, WithSessionCompaction(false))
+	if err != nil {
+		slog.Error("Failed to create post-mortem runtime", "error", err)
+		return ""
+	}
+
+	if _, err := postMortemRuntime.Run(ctx, postMortemSession); err != nil {
+		slog.Error("Failed to write post-mortem", "error", err)
+		return ""
+	}
+
+	return postMortemSession.GetLastAssistantMessageContent()
+}
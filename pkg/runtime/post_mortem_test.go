@@ -0,0 +1,86 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/team"
+	"github.com/docker/cagent/pkg/tools"
+)
+
+func failedRunSession() *session.Session {
+	sess := session.New(session.WithUserMessage("Old request"), session.WithTitle("Unit Test"))
+	sess.AddMessage(session.UserMessage("Build the project"))
+	sess.AddMessage(&session.Message{
+		AgentName: "root",
+		Message: chat.Message{
+			Role:    chat.MessageRoleAssistant,
+			Content: "Let me build it.",
+			ToolCalls: []tools.ToolCall{{
+				ID:       "call_1",
+				Function: tools.FunctionCall{Name: "shell", Arguments: `{"cmd":"go build ./..."}`},
+			}},
+		},
+	})
+	sess.AddMessage(&session.Message{
+		AgentName: "root",
+		Message: chat.Message{
+			Role:       chat.MessageRoleTool,
+			Content:    "go: cannot find main module",
+			ToolCallID: "call_1",
+		},
+	})
+	sess.AddMessage(session.TurnCancelledMessage())
+	return sess
+}
+
+func TestBuildRunLog(t *testing.T) {
+	t.Parallel()
+
+	runLog := buildRunLog(failedRunSession().GetAllMessages())
+
+	require.NotContains(t, runLog, "Old request")
+	require.Contains(t, runLog, "User: Build the project")
+	require.Contains(t, runLog, "Assistant (root): Let me build it.")
+	require.Contains(t, runLog, `Tool call (root): shell {"cmd":"go build ./..."}`)
+	require.Contains(t, runLog, "Tool result (shell): go: cannot find main module")
+	require.Contains(t, runLog, "[The user cancelled the run]")
+}
+
+func TestBuildRunLog_NoRun(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, buildRunLog(session.New().GetAllMessages()))
+}
+
+func TestWritePostMortem(t *testing.T) {
+	postMortemStream := newStreamBuilder().
+		AddContent("Suspected cause: the module has no go.mod.").
+		AddStopWithUsage(1, 1).
+		Build()
+
+	prov := &queueProvider{id: "test/mock-model"}
+	cheap := &queueProvider{id: "test/cheap-model", streams: []chat.MessageStream{postMortemStream}}
+	root := agent.New("root", "You are a test agent", agent.WithModel(prov), agent.WithCompaction(cheap, 0))
+	tm := team.New(team.WithAgents(root))
+
+	rt, err := New(tm, WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+
+	sess := failedRunSession()
+	events := make(chan Event, 10)
+	rt.WritePostMortem(t.Context(), sess, "it was cancelled by the user", events)
+	close(events)
+
+	var seen []Event
+	for ev := range events {
+		seen = append(seen, ev)
+	}
+
+	require.True(t, hasEventType(t, seen, &PostMortemEvent{}))
+	require.Equal(t, "Suspected cause: the module has no go.mod.", sess.PostMortem)
+}
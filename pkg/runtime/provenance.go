@@ -0,0 +1,62 @@
+package runtime
+
+import (
+	"time"
+
+	"github.com/docker/cagent/pkg/features"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/version"
+)
+
+// providerSDKs are the modules implementing the model providers. Their
+// version changes how the requests are built and the responses parsed.
+var providerSDKs = map[string]string{
+	"anthropic":      "github.com/anthropics/anthropic-sdk-go",
+	"openai":         "github.com/openai/openai-go/v3",
+	"google":         "google.golang.org/genai",
+	"amazon-bedrock": "github.com/aws/aws-sdk-go-v2/service/bedrockruntime",
+}
+
+// WithConfigHash sets the hash of the agent configuration, recorded in the
+// provenance of the sessions.
+func WithConfigHash(hash string) Opt {
+	return func(r *LocalRuntime) {
+		r.configHash = hash
+	}
+}
+
+// currentProvenance returns the provenance of the sessions run by this
+// process, with the given configuration.
+func currentProvenance(configHash string, now time.Time) session.Provenance {
+	enabled := []string{}
+	for _, flag := range features.All() {
+		if flag.Enabled() {
+			enabled = append(enabled, flag.Name)
+		}
+	}
+
+	providers := make(map[string]string, len(providerSDKs))
+	for provider, module := range providerSDKs {
+		if v := version.Module(module); v != "" {
+			providers[provider] = v
+		}
+	}
+
+	return session.Provenance{
+		Version:    version.Version,
+		Commit:     version.Commit,
+		Features:   enabled,
+		Providers:  providers,
+		ConfigHash: configHash,
+		Since:      now,
+	}
+}
+
+// recordProvenance records the provenance of a session about to run.
+// Sub-sessions are part of their parent, which records it.
+func (r *LocalRuntime) recordProvenance(sess *session.Session) {
+	if sess.IsSubSession() {
+		return
+	}
+	sess.RecordProvenance(currentProvenance(r.configHash, time.Now()))
+}
@@ -0,0 +1,44 @@
+package runtime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/version"
+)
+
+func TestCurrentProvenance(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	p := currentProvenance("sha256:1234", now)
+
+	assert.Equal(t, version.Version, p.Version)
+	assert.Equal(t, version.Commit, p.Commit)
+	assert.NotNil(t, p.Features)
+	assert.Equal(t, "sha256:1234", p.ConfigHash)
+	assert.Equal(t, now, p.Since)
+	// The provider SDKs are dependencies of the test binary too
+	assert.NotEmpty(t, p.Providers["anthropic"])
+	assert.NotEmpty(t, p.Providers["openai"])
+}
+
+func TestRecordProvenance(t *testing.T) {
+	t.Parallel()
+
+	r := &LocalRuntime{configHash: "sha256:1234"}
+
+	sess := session.New()
+	r.recordProvenance(sess)
+	r.recordProvenance(sess)
+	require.Len(t, sess.Provenance, 1)
+	assert.Equal(t, "sha256:1234", sess.Provenance[0].ConfigHash)
+
+	sub := session.New(session.WithParentID(sess.ID))
+	r.recordProvenance(sub)
+	assert.Empty(t, sub.Provenance)
+}
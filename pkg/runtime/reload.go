@@ -0,0 +1,50 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ToolsReloader is implemented by runtimes whose tools can be reloaded while
+// they run, once the configuration of the agents changed. Remote runtimes
+// typically can't.
+type ToolsReloader interface {
+	ReloadTools(ctx context.Context, events chan Event) error
+}
+
+// WithToolsReloader sets how the toolsets of the agents are created again,
+// from their configuration, when the tools are reloaded.
+func WithToolsReloader(reload func(ctx context.Context) error) Opt {
+	return func(r *LocalRuntime) {
+		r.toolsReloader = reload
+	}
+}
+
+// ReloadTools gives the agents their toolsets as currently configured. The
+// tools of the current agent are listed again, which starts its new toolsets,
+// and the next turns advertise them to the model.
+func (r *LocalRuntime) ReloadTools(ctx context.Context, events chan Event) error {
+	if r.toolsReloader == nil {
+		return errors.New("the tools of this runtime can't be reloaded")
+	}
+
+	// The agent file watcher and the user can reload the tools at the same time
+	r.toolsReloadMu.Lock()
+	defer r.toolsReloadMu.Unlock()
+
+	if err := r.toolsReloader(ctx); err != nil {
+		return fmt.Errorf("reloading the tools: %w", err)
+	}
+
+	a := r.CurrentAgent()
+	agentTools, err := a.Tools(ctx)
+	if err != nil {
+		return fmt.Errorf("listing the tools: %w", err)
+	}
+	r.emitAgentWarnings(a, events)
+	events <- ToolsetInfo(len(agentTools), false, r.currentAgent)
+	events <- ToolsReloaded(len(agentTools), r.currentAgent)
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/team"
+	"github.com/docker/cagent/pkg/tools"
+)
+
+func TestReloadTools(t *testing.T) {
+	t.Parallel()
+
+	root := agent.New("root", "You are a test agent",
+		agent.WithModel(&mockProvider{id: "test/mock-model"}),
+		agent.WithToolSets(newStubToolSet(nil, []tools.Tool{{Name: "shell"}}, nil)),
+	)
+	reload := func(ctx context.Context) error {
+		return root.ReplaceToolSets(ctx,
+			newStubToolSet(nil, []tools.Tool{{Name: "shell"}, {Name: "fetch"}}, nil),
+		)
+	}
+	rt, err := New(team.New(team.WithAgents(root)), WithSessionCompaction(false), WithModelStore(mockModelStore{}), WithToolsReloader(reload))
+	require.NoError(t, err)
+
+	events := make(chan Event, 10)
+	require.NoError(t, rt.ReloadTools(t.Context(), events))
+	close(events)
+
+	var got []Event
+	for event := range events {
+		got = append(got, event)
+	}
+	require.Len(t, got, 2)
+	assert.Equal(t, ToolsetInfo(2, false, "root"), got[0])
+	assert.Equal(t, ToolsReloaded(2, "root"), got[1])
+}
+
+func TestReloadTools_WithoutReloader(t *testing.T) {
+	t.Parallel()
+
+	root := agent.New("root", "You are a test agent", agent.WithModel(&mockProvider{id: "test/mock-model"}))
+	rt, err := New(team.New(team.WithAgents(root)), WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+
+	require.Error(t, rt.ReloadTools(t.Context(), make(chan Event, 10)))
+}
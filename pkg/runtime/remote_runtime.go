@@ -173,7 +173,11 @@ func (r *RemoteRuntime) RunStream(ctx context.Context, sess *session.Session) <-
 
 		for streamEvent := range streamChan {
 			if elicitationRequest, ok := streamEvent.(*ElicitationRequestEvent); ok {
-				r.pendingOAuthElicitation = elicitationRequest
+				// Only the OAuth flow runs on the client, the other requests are answered as is
+				r.pendingOAuthElicitation = nil
+				if elicitationRequest.Meta["cagent/type"] == "oauth_flow" {
+					r.pendingOAuthElicitation = elicitationRequest
+				}
 			}
 			events <- streamEvent
 		}
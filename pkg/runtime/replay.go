@@ -0,0 +1,367 @@
+package runtime
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/model/provider"
+	"github.com/docker/cagent/pkg/todo"
+	"github.com/docker/cagent/pkg/tools"
+)
+
+// WithRecorder records what the models and the tools answer during the runs
+// of the runtime, to replay the sessions with WithReplay.
+func WithRecorder(recorder *Recorder) Opt {
+	return func(r *LocalRuntime) {
+		r.recorder = recorder
+	}
+}
+
+// WithReplay answers the model and tool calls of the runtime with a
+// recording instead of calling the providers and the tools, to reproduce a
+// session offline.
+func WithReplay(recording *Recording) Opt {
+	return func(r *LocalRuntime) {
+		r.replay = recording
+	}
+}
+
+// Kinds of the entries of a recording
+const (
+	recordedTools     = "tools"
+	recordedModelCall = "model_call"
+	recordedToolCall  = "tool_call"
+	recordedSummary   = "tool_summary"
+)
+
+// RecordingEntry is what a provider or a tool answered during a run, one per
+// line of the recording of a session.
+type RecordingEntry struct {
+	Kind  string `json:"kind"`
+	Agent string `json:"agent"`
+
+	// Tools are the tools the agent had for its turn
+	Tools []tools.Tool `json:"tools,omitempty"`
+
+	// Request identifies the request sent to the model, Responses are the
+	// chunks of the stream it answered
+	Request   string                       `json:"request,omitempty"`
+	Responses []chat.MessageStreamResponse `json:"responses,omitempty"`
+
+	// ToolCallID is the tool call Result or Error answers
+	ToolCallID string                `json:"tool_call_id,omitempty"`
+	Result     *tools.ToolCallResult `json:"result,omitempty"`
+	Error      string                `json:"error,omitempty"`
+
+	// Summary is the summary of the result of the tool call, when it was too
+	// large for the context of the agent
+	Summary string `json:"summary,omitempty"`
+}
+
+// RecordingPath returns the path of the recording of a session in dir.
+func RecordingPath(dir, sessionID string) string {
+	return filepath.Join(dir, filepath.Base(sessionID)+".jsonl")
+}
+
+// Recorder records what the providers and the tools answer during the runs
+// of a runtime, to replay the sessions without calling them. Each session is
+// recorded to its own file of a directory, the runs of its sub-agents
+// included.
+type Recorder struct {
+	dir string
+
+	mu        sync.Mutex
+	sessionID string
+	file      *os.File
+}
+
+// NewRecorder returns a recorder writing the recordings of the sessions to dir.
+func NewRecorder(dir string) *Recorder {
+	return &Recorder{dir: dir}
+}
+
+// start records the next entries to the recording of a session.
+func (rec *Recorder) start(sessionID string) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	if rec.sessionID == sessionID {
+		return
+	}
+	rec.closeLocked()
+	rec.sessionID = sessionID
+
+	if err := os.MkdirAll(rec.dir, 0o700); err != nil {
+		slog.Warn("Failed to create the recordings directory", "dir", rec.dir, "error", err)
+		return
+	}
+	file, err := os.OpenFile(RecordingPath(rec.dir, sessionID), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		slog.Warn("Failed to open the recording of the session", "session_id", sessionID, "error", err)
+		return
+	}
+	rec.file = file
+}
+
+func (rec *Recorder) record(entry RecordingEntry) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	if rec.file == nil {
+		return
+	}
+	buf, err := json.Marshal(entry)
+	if err != nil {
+		slog.Warn("Failed to encode a recording entry", "kind", entry.Kind, "error", err)
+		return
+	}
+	if _, err := rec.file.Write(append(buf, '\n')); err != nil {
+		slog.Warn("Failed to write the recording of the session", "session_id", rec.sessionID, "error", err)
+	}
+}
+
+// Close closes the recording of the current session.
+func (rec *Recorder) Close() error {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return rec.closeLocked()
+}
+
+func (rec *Recorder) closeLocked() error {
+	if rec.file == nil {
+		return nil
+	}
+	err := rec.file.Close()
+	rec.file = nil
+	return err
+}
+
+// recordTools records the tools an agent has for its turn.
+func (rec *Recorder) recordTools(agentName string, agentTools []tools.Tool) {
+	rec.record(RecordingEntry{Kind: recordedTools, Agent: agentName, Tools: agentTools})
+}
+
+// recordToolCall records the result of a tool call.
+func (rec *Recorder) recordToolCall(agentName string, toolCall tools.ToolCall, res *tools.ToolCallResult, err error) {
+	entry := RecordingEntry{Kind: recordedToolCall, Agent: agentName, ToolCallID: toolCall.ID, Result: res}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	rec.record(entry)
+}
+
+// recordSummary records the summary of the result of a tool call.
+func (rec *Recorder) recordSummary(agentName string, toolCall tools.ToolCall, summary string) {
+	rec.record(RecordingEntry{Kind: recordedSummary, Agent: agentName, ToolCallID: toolCall.ID, Summary: summary})
+}
+
+// recordStream records the stream a model answered once it's read to the
+// end of the answer. Streams that fail aren't recorded: the replay reproduces
+// the request that succeeded after the retries.
+func (rec *Recorder) recordStream(agentName, request string, stream chat.MessageStream) chat.MessageStream {
+	return &tapeStream{MessageStream: stream, recorder: rec, entry: RecordingEntry{Kind: recordedModelCall, Agent: agentName, Request: request}}
+}
+
+type tapeStream struct {
+	chat.MessageStream
+	recorder *Recorder
+	entry    RecordingEntry
+	once     sync.Once
+}
+
+func (s *tapeStream) Recv() (chat.MessageStreamResponse, error) {
+	response, err := s.MessageStream.Recv()
+	switch {
+	case errors.Is(err, io.EOF):
+		s.settle(true)
+	case err != nil:
+		s.settle(false)
+	default:
+		s.entry.Responses = append(s.entry.Responses, response)
+		// The stream isn't read past the end of the answer
+		for _, choice := range response.Choices {
+			if choice.FinishReason == chat.FinishReasonStop || choice.FinishReason == chat.FinishReasonLength {
+				s.settle(true)
+			}
+		}
+	}
+	return response, err
+}
+
+func (s *tapeStream) Close() {
+	s.settle(false)
+	s.MessageStream.Close()
+}
+
+func (s *tapeStream) settle(ok bool) {
+	s.once.Do(func() {
+		if ok {
+			s.recorder.record(s.entry)
+		}
+	})
+}
+
+// Recording is the recording of a session, replayed by a runtime instead of
+// calling the providers and the tools.
+type Recording struct {
+	mu         sync.Mutex
+	tools      map[string][]RecordingEntry
+	modelCalls map[string][]RecordingEntry
+	toolCalls  map[string]RecordingEntry
+	summaries  map[string]string
+	diverged   bool
+}
+
+// LoadRecording reads the recording of a session.
+func LoadRecording(path string) (*Recording, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening recording: %w", err)
+	}
+	defer f.Close()
+
+	rec := &Recording{
+		tools:      make(map[string][]RecordingEntry),
+		modelCalls: make(map[string][]RecordingEntry),
+		toolCalls:  make(map[string]RecordingEntry),
+		summaries:  make(map[string]string),
+	}
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var entry RecordingEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("reading line %d of the recording: %w", line, err)
+		}
+		switch entry.Kind {
+		case recordedTools:
+			rec.tools[entry.Agent] = append(rec.tools[entry.Agent], entry)
+		case recordedModelCall:
+			rec.modelCalls[entry.Agent] = append(rec.modelCalls[entry.Agent], entry)
+		case recordedToolCall:
+			rec.toolCalls[entry.ToolCallID] = entry
+		case recordedSummary:
+			rec.summaries[entry.ToolCallID] = entry.Summary
+		default:
+			return nil, fmt.Errorf("unknown entry %q on line %d of the recording", entry.Kind, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading recording: %w", err)
+	}
+
+	return rec, nil
+}
+
+// Diverged reports whether the replay sent a request to a model that differs
+// from the recorded one: the answers replayed may not match the conversation.
+func (rec *Recording) Diverged() bool {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return rec.diverged
+}
+
+// agentTools returns the tools an agent had for its next turn. The last
+// recorded tools are kept once they're all replayed.
+func (rec *Recording) agentTools(agentName string) ([]tools.Tool, error) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	recorded := rec.tools[agentName]
+	if len(recorded) == 0 {
+		return nil, fmt.Errorf("no tools recorded for agent %s", agentName)
+	}
+	if len(recorded) > 1 {
+		rec.tools[agentName] = recorded[1:]
+	}
+	return recorded[0].Tools, nil
+}
+
+// stream returns the next stream an agent's model answered.
+func (rec *Recording) stream(agentName, request string) (chat.MessageStream, error) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	recorded := rec.modelCalls[agentName]
+	if len(recorded) == 0 {
+		return nil, fmt.Errorf("no recorded answer left for agent %s", agentName)
+	}
+	rec.modelCalls[agentName] = recorded[1:]
+
+	entry := recorded[0]
+	if entry.Request != request && !rec.diverged {
+		slog.Warn("The replay diverged from the recording", "agent", agentName)
+		rec.diverged = true
+	}
+	return &recordedStream{responses: entry.Responses}, nil
+}
+
+// toolResult returns the recorded result of a tool call.
+func (rec *Recording) toolResult(tool tools.Tool, toolCall tools.ToolCall) (*tools.ToolCallResult, error) {
+	rec.mu.Lock()
+	entry, ok := rec.toolCalls[toolCall.ID]
+	rec.mu.Unlock()
+
+	switch {
+	case !ok:
+		return tools.ResultError(fmt.Sprintf("No result was recorded for the call to %s.", toolCall.Function.Name)), nil
+	case entry.Error != "":
+		return nil, errors.New(entry.Error)
+	case entry.Result == nil:
+		return nil, fmt.Errorf("no result recorded for tool call %s", toolCall.ID)
+	}
+
+	res := *entry.Result
+	// The todo list is kept in the session as typed todos
+	if tool.Category == "todo" && res.Meta != nil {
+		var todos []todo.Todo
+		if buf, err := json.Marshal(res.Meta); err == nil && json.Unmarshal(buf, &todos) == nil {
+			res.Meta = todos
+		}
+	}
+	return &res, nil
+}
+
+// summary returns the recorded summary of the result of a tool call, if any.
+func (rec *Recording) summary(toolCallID string) string {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return rec.summaries[toolCallID]
+}
+
+// recordedStream replays the chunks of a recorded stream, with their usage.
+type recordedStream struct {
+	responses []chat.MessageStreamResponse
+}
+
+func (s *recordedStream) Recv() (chat.MessageStreamResponse, error) {
+	if len(s.responses) == 0 {
+		return chat.MessageStreamResponse{}, io.EOF
+	}
+	response := s.responses[0]
+	s.responses = s.responses[1:]
+	return response, nil
+}
+
+func (s *recordedStream) Close() {}
+
+// replayKey identifies a request to a model the same way when it's recorded
+// and when it's replayed, with the tools as they are once recorded.
+func replayKey(model provider.Provider, messages []chat.Message, agentTools []tools.Tool) string {
+	if buf, err := json.Marshal(agentTools); err == nil {
+		var recorded []tools.Tool
+		if json.Unmarshal(buf, &recorded) == nil {
+			agentTools = recorded
+		}
+	}
+	return modelCallKey(model.ID(), messages, agentTools)
+}
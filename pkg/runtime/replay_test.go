@@ -0,0 +1,77 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/team"
+	"github.com/docker/cagent/pkg/tools"
+)
+
+func TestReplay(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	calls := 0
+	newAgent := func(prov *queueProvider) *agent.Agent {
+		agentTools := []tools.Tool{{
+			Name:       "roll_dice",
+			Parameters: map[string]any{},
+			Handler: func(context.Context, tools.ToolCall) (*tools.ToolCallResult, error) {
+				calls++
+				return tools.ResultSuccess("4"), nil
+			},
+		}}
+		return agent.New("root", "You are a test agent", agent.WithModel(prov), agent.WithToolSets(newStubToolSet(nil, agentTools, nil)))
+	}
+	run := func(rt *LocalRuntime, message string) *session.Session {
+		sess := session.New(session.WithUserMessage(message), session.WithTitle("Unit Test"), session.WithToolsApproved(true))
+		for range rt.RunStream(t.Context(), sess) {
+		}
+		return sess
+	}
+
+	prov := &queueProvider{id: "test/mock-model", streams: []chat.MessageStream{
+		newStreamBuilder().AddToolCallName("call_1", "roll_dice").AddToolCallArguments("call_1", `{}`).Build(),
+		newStreamBuilder().AddContent("You rolled a 4").AddStopWithUsage(20, 5).Build(),
+	}}
+	recorder := NewRecorder(dir)
+	rt, err := New(team.New(team.WithAgents(newAgent(prov))), WithSessionCompaction(false), WithModelStore(mockModelStore{}), WithRecorder(recorder))
+	require.NoError(t, err)
+	recorded := run(rt, "Roll a dice")
+	require.NoError(t, recorder.Close())
+	require.Equal(t, 1, calls)
+
+	// The replay neither calls the model nor the tool
+	recording, err := LoadRecording(RecordingPath(dir, recorded.ID))
+	require.NoError(t, err)
+	rt, err = New(team.New(team.WithAgents(newAgent(&queueProvider{id: "test/mock-model"}))), WithSessionCompaction(false), WithModelStore(mockModelStore{}), WithReplay(recording))
+	require.NoError(t, err)
+	replayed := run(rt, "Roll a dice")
+
+	assert.Equal(t, 1, calls)
+	assert.False(t, recording.Diverged())
+	assert.Equal(t, "You rolled a 4", replayed.GetLastAssistantMessageContent())
+	assert.Equal(t, recorded.InputTokens, replayed.InputTokens)
+	assert.Equal(t, recorded.OutputTokens, replayed.OutputTokens)
+	require.Len(t, replayed.GetAllMessages(), len(recorded.GetAllMessages()))
+	assert.Equal(t, "4", replayed.GetAllMessages()[2].Message.Content)
+	assert.Equal(t, int64(20), replayed.InputTokens)
+
+	// A conversation that changed is reported
+	recording, err = LoadRecording(RecordingPath(dir, recorded.ID))
+	require.NoError(t, err)
+	rt, err = New(team.New(team.WithAgents(newAgent(&queueProvider{id: "test/mock-model"}))), WithSessionCompaction(false), WithModelStore(mockModelStore{}), WithReplay(recording))
+	require.NoError(t, err)
+	run(rt, "Roll two dice")
+
+	assert.True(t, recording.Diverged())
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,158 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/model/provider"
+	"github.com/docker/cagent/pkg/modelsdev"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/tools"
+)
+
+// streamWithRetries sends a request to the model and handles its stream. The
+// requests that fail with a rate limit, a server error or a dropped stream are
+// retried as the retry policy of the agent says, then sent to its fallback
+// model. The model that answered is returned, with its definition.
+func (r *LocalRuntime) streamWithRetries(ctx, streamCtx context.Context, sess *session.Session, a *agent.Agent, model provider.Provider, m *modelsdev.Model, messages []chat.Message, agentTools []tools.Tool, events chan Event) (streamResult, provider.Provider, *modelsdev.Model, error) {
+	policy := a.RetryPolicy()
+
+	res, err := r.streamResponse(ctx, streamCtx, sess, a, model, m, messages, agentTools, events)
+	for retry := 1; err != nil && ctx.Err() == nil && retryable(err); retry++ {
+		discarded := res.Content != "" || res.ReasoningContent != "" || len(res.Calls) > 0
+
+		if retry > policy.MaxRetries {
+			if policy.Fallback == nil || model == policy.Fallback {
+				break
+			}
+
+			slog.Warn("Failing over to the fallback model", "agent", a.Name(), "from", model.ID(), "to", policy.Fallback.ID(), "error", err)
+			events <- ModelFailover(model.ID(), policy.Fallback.ID(), err.Error(), discarded, a.Name())
+
+			model = policy.Fallback
+			def, defErr := r.modelsStore.GetModel(ctx, model.ID())
+			if defErr != nil {
+				slog.Debug("Failed to get model definition", "error", defErr)
+			}
+			m = def
+			agentTools = r.normalizeTools(a, model, agentTools, events)
+
+			// The fallback model gets its own retries
+			retry = 0
+			res, err = r.streamResponse(ctx, streamCtx, sess, a, model, m, messages, agentTools, events)
+			continue
+		}
+
+		delay := policy.Delay(retry)
+		slog.Warn("Retrying a failed model request", "agent", a.Name(), "model", model.ID(), "retry", retry, "delay", delay, "error", err)
+		events <- ModelRetry(model.ID(), retry, policy.MaxRetries, delay, err.Error(), discarded, a.Name())
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return streamResult{}, model, m, ctx.Err()
+		}
+
+		res, err = r.streamResponse(ctx, streamCtx, sess, a, model, m, messages, agentTools, events)
+	}
+
+	return res, model, m, err
+}
+
+// streamResponse sends one request to the model and handles its stream. When
+// an agent of the runtime sends an identical request, its response is shared.
+func (r *LocalRuntime) streamResponse(ctx, streamCtx context.Context, sess *session.Session, a *agent.Agent, model provider.Provider, m *modelsdev.Model, messages []chat.Message, agentTools []tools.Tool, events chan Event) (streamResult, error) {
+	var (
+		key  string
+		call *sharedCall
+	)
+	if r.dedup != nil {
+		key = modelCallKey(model.ID(), messages, agentTools)
+	}
+	// A call that can't be identified isn't shared
+	if key != "" {
+		var leader bool
+		call, leader = r.dedup.join(key)
+		if !leader {
+			ok, err := r.dedup.wait(streamCtx, call)
+			if err != nil {
+				return streamResult{}, err
+			}
+			if ok {
+				saved := r.dedup.savedModelCall(call.usage, m)
+				slog.Debug("Sharing the response of an identical model call", "agent", a.Name(), "model", model.ID())
+				events <- CallDeduplicated("model", model.ID(), saved, a.Name())
+				return r.handleStream(ctx, &replayStream{responses: call.responses}, a, agentTools, sess, m, events)
+			}
+
+			// The identical call failed: send ours, without sharing it
+			call = nil
+		}
+	}
+
+	// A stream that stalls cancels its request
+	requestCtx, cancel := context.WithCancelCause(streamCtx)
+	defer cancel(nil)
+
+	stream, err := r.createStream(requestCtx, a, sess, model, messages, agentTools, events)
+	if err != nil {
+		if call != nil {
+			r.dedup.settle(key, call, false)
+		}
+		return streamResult{}, err
+	}
+	if timeout := a.StreamTimeout(); timeout > 0 {
+		stream = watchStall(requestCtx, cancel, stream, timeout)
+	}
+	if call != nil {
+		stream = &recordingStream{MessageStream: stream, dedup: r.dedup, key: key, call: call}
+	}
+
+	slog.Debug("Processing stream", "agent", a.Name())
+	res, err := r.handleStream(ctx, stream, a, agentTools, sess, m, events)
+	if stalled := (*StreamStalledError)(nil); errors.As(err, &stalled) {
+		slog.Warn("The model stream stalled", "agent", a.Name(), "model", model.ID(), "timeout", stalled.Timeout)
+		events <- StallDetected(model.ID(), stalled.Timeout, a.Name())
+	}
+	if res.Usage != nil {
+		r.requests.recordTokens(model.ID(), res.Usage.InputTokens+res.Usage.OutputTokens)
+	}
+	return res, err
+}
+
+// createStream sends a request to the model, once there's a slot for it.
+func (r *LocalRuntime) createStream(ctx context.Context, a *agent.Agent, sess *session.Session, model provider.Provider, messages []chat.Message, agentTools []tools.Tool, events chan Event) (chat.MessageStream, error) {
+	if r.replay != nil {
+		return r.replay.stream(a.Name(), replayKey(model, messages, agentTools))
+	}
+
+	// Wait for a slot when the provider limits its concurrent requests, or
+	// for its rate limit
+	throttled := false
+	release, err := r.requests.acquire(ctx, model.ID(), a.Name(), requestPriority(a, sess), func(wait time.Duration) {
+		throttled = true
+		events <- RequestThrottled(model.ID(), true, wait, a.Name())
+	})
+	if throttled {
+		events <- RequestThrottled(model.ID(), false, 0, a.Name())
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	slog.Debug("Creating chat completion stream", "agent", a.Name())
+	stream, err := model.CreateChatCompletionStream(ctx, messages, agentTools)
+	if err != nil {
+		return nil, fmt.Errorf("creating chat completion: %w", err)
+	}
+	if r.recorder != nil {
+		stream = r.recorder.recordStream(a.Name(), replayKey(model, messages, agentTools), stream)
+	}
+	return stream, nil
+}
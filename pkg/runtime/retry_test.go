@@ -0,0 +1,199 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/model/provider/base"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/team"
+	"github.com/docker/cagent/pkg/tools"
+)
+
+// droppedStream streams its responses, then drops.
+type droppedStream struct{ mockStream }
+
+func (s *droppedStream) Recv() (chat.MessageStreamResponse, error) {
+	resp, err := s.mockStream.Recv()
+	if err == io.EOF {
+		return resp, fmt.Errorf("reading the response: %w", io.ErrUnexpectedEOF)
+	}
+	return resp, err
+}
+
+// unavailableProvider fails every request.
+type unavailableProvider struct {
+	id       string
+	requests int
+}
+
+func (p *unavailableProvider) ID() string { return p.id }
+
+func (p *unavailableProvider) CreateChatCompletionStream(context.Context, []chat.Message, []tools.Tool) (chat.MessageStream, error) {
+	p.requests++
+	return nil, fmt.Errorf("connecting: %w", io.ErrUnexpectedEOF)
+}
+
+func (p *unavailableProvider) BaseConfig() base.Config { return base.Config{} }
+
+func runWithRetries(t *testing.T, root *agent.Agent) ([]Event, *session.Session) {
+	t.Helper()
+
+	rt, err := New(team.New(team.WithAgents(root)), WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+
+	sess := session.New(session.WithUserMessage("Hi"), session.WithTitle("Unit Test"), session.WithToolsApproved(true))
+	var events []Event
+	for ev := range rt.RunStream(t.Context(), sess) {
+		events = append(events, ev)
+	}
+	return events, sess
+}
+
+func TestRunStream_RetriesDroppedStream(t *testing.T) {
+	t.Parallel()
+
+	dropped := &droppedStream{mockStream: *newStreamBuilder().AddContent("Hel").Build()}
+	prov := &queueProvider{id: "test/mock-model", streams: []chat.MessageStream{
+		dropped,
+		newStreamBuilder().AddContent("Hello").AddStopWithUsage(3, 2).Build(),
+	}}
+	root := agent.New("root", "You are a test agent",
+		agent.WithModel(prov),
+		agent.WithRetryPolicy(agent.RetryPolicy{MaxRetries: 2, InitialDelay: time.Millisecond}),
+	)
+
+	events, sess := runWithRetries(t, root)
+
+	var retry *ModelRetryEvent
+	for _, ev := range events {
+		if e, ok := ev.(*ModelRetryEvent); ok {
+			retry = e
+		}
+		_, isError := ev.(*ErrorEvent)
+		require.False(t, isError)
+	}
+	require.NotNil(t, retry)
+	assert.Equal(t, 1, retry.Retry)
+	assert.True(t, retry.Discarded)
+
+	messages := sess.GetAllMessages()
+	assert.Equal(t, "Hello", messages[len(messages)-1].Message.Content)
+}
+
+func TestRunStream_FailsOverToFallback(t *testing.T) {
+	t.Parallel()
+
+	primary := &unavailableProvider{id: "test/primary"}
+	fallback := &queueProvider{id: "test/fallback", streams: []chat.MessageStream{
+		newStreamBuilder().AddContent("From the fallback").AddStopWithUsage(3, 2).Build(),
+	}}
+	root := agent.New("root", "You are a test agent",
+		agent.WithModel(primary),
+		agent.WithRetryPolicy(agent.RetryPolicy{MaxRetries: 1, InitialDelay: time.Millisecond, Fallback: fallback}),
+	)
+
+	events, sess := runWithRetries(t, root)
+
+	var failover *ModelFailoverEvent
+	for _, ev := range events {
+		if e, ok := ev.(*ModelFailoverEvent); ok {
+			failover = e
+		}
+	}
+	require.NotNil(t, failover)
+	assert.Equal(t, "test/primary", failover.From)
+	assert.Equal(t, "test/fallback", failover.To)
+	assert.Equal(t, 2, primary.requests)
+
+	last := sess.GetAllMessages()[len(sess.GetAllMessages())-1]
+	assert.Equal(t, "From the fallback", last.Message.Content)
+	assert.Equal(t, "test/fallback", last.Message.Model)
+}
+
+func TestRunStream_NoRetriesWithoutPolicy(t *testing.T) {
+	t.Parallel()
+
+	primary := &unavailableProvider{id: "test/primary"}
+	events, _ := runWithRetries(t, agent.New("root", "You are a test agent", agent.WithModel(primary)))
+
+	assert.Equal(t, 1, primary.requests)
+	assert.True(t, hasEventType(t, events, &ErrorEvent{}))
+}
+
+// stallingProvider answers with streams that send a chunk, then stall until
+// their request is cancelled, before answering with its streams.
+type stallingProvider struct {
+	queueProvider
+	stalls int
+}
+
+func (p *stallingProvider) CreateChatCompletionStream(ctx context.Context, messages []chat.Message, agentTools []tools.Tool) (chat.MessageStream, error) {
+	if p.stalls > 0 {
+		p.stalls--
+		return &stalledStream{ctx: ctx, mockStream: *newStreamBuilder().AddContent("Hel").Build()}, nil
+	}
+	return p.queueProvider.CreateChatCompletionStream(ctx, messages, agentTools)
+}
+
+type stalledStream struct {
+	mockStream
+	ctx context.Context
+}
+
+func (s *stalledStream) Recv() (chat.MessageStreamResponse, error) {
+	resp, err := s.mockStream.Recv()
+	if err == io.EOF {
+		<-s.ctx.Done()
+		return resp, s.ctx.Err()
+	}
+	return resp, err
+}
+
+func TestRunStream_RetriesStalledStream(t *testing.T) {
+	t.Parallel()
+
+	prov := &stallingProvider{stalls: 1, queueProvider: queueProvider{id: "test/mock-model", streams: []chat.MessageStream{
+		newStreamBuilder().AddContent("Hello").AddStopWithUsage(3, 2).Build(),
+	}}}
+	root := agent.New("root", "You are a test agent",
+		agent.WithModel(prov),
+		agent.WithStreamTimeout(20*time.Millisecond),
+		agent.WithRetryPolicy(agent.RetryPolicy{MaxRetries: 1, InitialDelay: time.Millisecond}),
+	)
+
+	events, sess := runWithRetries(t, root)
+
+	assert.True(t, hasEventType(t, events, &StallDetectedEvent{}))
+	assert.True(t, hasEventType(t, events, &ModelRetryEvent{}))
+	assert.False(t, hasEventType(t, events, &ErrorEvent{}))
+
+	messages := sess.GetAllMessages()
+	assert.Equal(t, "Hello", messages[len(messages)-1].Message.Content)
+}
+
+func TestRunStream_StalledStreamFailsTurn(t *testing.T) {
+	t.Parallel()
+
+	prov := &stallingProvider{stalls: 1, queueProvider: queueProvider{id: "test/mock-model"}}
+	root := agent.New("root", "You are a test agent", agent.WithModel(prov), agent.WithStreamTimeout(20*time.Millisecond))
+
+	events, _ := runWithRetries(t, root)
+
+	var errs []string
+	for _, ev := range events {
+		if e, ok := ev.(*ErrorEvent); ok {
+			errs = append(errs, e.Error)
+		}
+	}
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0], "the model stream stalled: nothing was received for 20ms")
+}
@@ -1,6 +1,7 @@
 package runtime
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
@@ -20,17 +21,22 @@ import (
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/audit"
 	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/confidence"
 	"github.com/docker/cagent/pkg/config/latest"
 	"github.com/docker/cagent/pkg/config/types"
 	"github.com/docker/cagent/pkg/hooks"
+	"github.com/docker/cagent/pkg/model/provider"
 	"github.com/docker/cagent/pkg/modelsdev"
 	"github.com/docker/cagent/pkg/permissions"
+	"github.com/docker/cagent/pkg/policy"
 	"github.com/docker/cagent/pkg/rag"
 	ragtypes "github.com/docker/cagent/pkg/rag/types"
 	"github.com/docker/cagent/pkg/session"
 	"github.com/docker/cagent/pkg/team"
 	"github.com/docker/cagent/pkg/telemetry"
+	"github.com/docker/cagent/pkg/todo"
 	"github.com/docker/cagent/pkg/tools"
 	"github.com/docker/cagent/pkg/tools/builtin"
 	mcptools "github.com/docker/cagent/pkg/tools/mcp"
@@ -53,6 +59,19 @@ func UnwrapMCPToolset(toolset tools.ToolSet) *mcptools.Toolset {
 	return nil
 }
 
+// unwrapTodoTool extracts a todo tool from a potentially wrapped StartableToolSet.
+func unwrapTodoTool(toolset tools.ToolSet) *builtin.TodoTool {
+	if startableTS, ok := toolset.(*agent.StartableToolSet); ok {
+		toolset = startableTS.ToolSet
+	}
+
+	if todoTool, ok := toolset.(*builtin.TodoTool); ok {
+		return todoTool
+	}
+
+	return nil
+}
+
 type ResumeType string
 
 // ElicitationResult represents the result of an elicitation request
@@ -75,8 +94,21 @@ const (
 	ResumeTypeApprove        ResumeType = "approve"
 	ResumeTypeApproveSession ResumeType = "approve-session"
 	ResumeTypeReject         ResumeType = "reject"
+	// ResumeTypeApproveTool approves the tool call and the next calls of the
+	// same tool for the rest of the session
+	ResumeTypeApproveTool ResumeType = "approve-tool"
+	// ResumeTypeAlwaysApproveTool approves the tool call and remembers, in
+	// the runtime's ToolApprovals, that the agent may always call the tool
+	ResumeTypeAlwaysApproveTool ResumeType = "always-approve-tool"
 )
 
+// ToolApprovals remembers, across sessions, the tools the user always allows
+// an agent to call without asking.
+type ToolApprovals interface {
+	IsAlwaysAllowed(agentName, toolName string) bool
+	AlwaysAllow(agentName, toolName string) error
+}
+
 // ToolHandlerFunc is a function type for handling tool calls
 type ToolHandlerFunc func(ctx context.Context, sess *session.Session, toolCall tools.ToolCall, events chan Event) (*tools.ToolCallResult, error)
 
@@ -146,6 +178,7 @@ type LocalRuntime struct {
 	managedOAuth                bool
 	startupInfoEmitted          bool                   // Track if startup info has been emitted to avoid unnecessary duplication
 	elicitationRequestCh        chan ElicitationResult // Channel for receiving elicitation responses
+	elicitationPending          atomic.Int32           // Number of elicitation requests waiting for their response
 	elicitationEventsChannel    chan Event             // Current events channel for sending elicitation requests
 	elicitationEventsChannelMux sync.RWMutex           // Protects elicitationEventsChannel
 	ragInitialized              atomic.Bool
@@ -155,6 +188,25 @@ type LocalRuntime struct {
 	workingDir                  string   // Working directory for hooks execution
 	env                         []string // Environment variables for hooks execution
 	modelSwitcherCfg            *ModelSwitcherConfig
+	todosSessionID              string // ID of the session whose todos were last restored
+	policyEngine                policy.Engine
+	auditRecorder               audit.Recorder
+	toolApprovals               ToolApprovals
+	autosaveInterval            time.Duration
+	autosaver                   *sessionAutosaver
+	schemaRepairs               map[string]bool // Tool definition rewrites already reported to the user
+	schemaRepairsMu             sync.Mutex
+	rawToolOutputs              sync.Map // Tool call ID -> raw output of summarized tool results, read with read_more
+	argumentSchemas             sync.Map // JSON input schema of a tool -> its compiled *gojsonschema.Schema
+	configHash                  string   // Hash of the agent configuration, recorded in the sessions' provenance
+	requests                    *requestScheduler
+	runClocks                   sync.Map // Session ID -> *runClock of the run of a session with a time limit
+	turnQueues                  sync.Map // Session ID -> *turnQueue of the messages sent while the session runs
+	dedup                       *deduplicator
+	recorder                    *Recorder  // Records the answers of the models and the tools, to replay the sessions
+	replay                      *Recording // Recording replayed instead of calling the models and the tools
+	toolsReloader               func(ctx context.Context) error
+	toolsReloadMu               sync.Mutex
 }
 
 type streamResult struct {
@@ -176,6 +228,29 @@ func WithCurrentAgent(agentName string) Opt {
 	}
 }
 
+// WithPolicyEngine delegates tool call decisions to a policy engine.
+// Policy decisions take precedence over permissions and --yolo.
+func WithPolicyEngine(engine policy.Engine) Opt {
+	return func(r *LocalRuntime) {
+		r.policyEngine = engine
+	}
+}
+
+// WithAuditRecorder records tool call decisions and guardrail events.
+func WithAuditRecorder(recorder audit.Recorder) Opt {
+	return func(r *LocalRuntime) {
+		r.auditRecorder = recorder
+	}
+}
+
+// WithToolApprovals skips the confirmation of the tools the user always
+// allows and records the new ones.
+func WithToolApprovals(approvals ToolApprovals) Opt {
+	return func(r *LocalRuntime) {
+		r.toolApprovals = approvals
+	}
+}
+
 func WithManagedOAuth(managed bool) Opt {
 	return func(r *LocalRuntime) {
 		r.managedOAuth = managed
@@ -207,6 +282,27 @@ func WithSessionStore(store session.Store) Opt {
 	}
 }
 
+// WithAutosaveInterval sets how often, at most, a running session is written
+// to the session store. Zero writes it on every change.
+func WithAutosaveInterval(interval time.Duration) Opt {
+	return func(r *LocalRuntime) {
+		r.autosaveInterval = interval
+	}
+}
+
+// WithDeduplication shares the result of a model or tool call with the
+// identical calls the agents make while it runs and for the given window
+// after. Zero disables the deduplication.
+func WithDeduplication(window time.Duration) Opt {
+	return func(r *LocalRuntime) {
+		if window > 0 {
+			r.dedup = newDeduplicator(window)
+		} else {
+			r.dedup = nil
+		}
+	}
+}
+
 // WithWorkingDir sets the working directory for hooks execution
 func WithWorkingDir(dir string) Opt {
 	return func(r *LocalRuntime) {
@@ -238,12 +334,16 @@ func New(agents *team.Team, opts ...Opt) (*LocalRuntime, error) {
 		team:                 agents,
 		currentAgent:         defaultAgent.Name(),
 		resumeChan:           make(chan ResumeType),
-		elicitationRequestCh: make(chan ElicitationResult),
+		elicitationRequestCh: make(chan ElicitationResult, 1),
 		modelsStore:          modelsStore,
 		sessionCompaction:    true,
 		managedOAuth:         true,
 		sessionStore:         session.NewInMemorySessionStore(),
+		autosaveInterval:     defaultAutosaveInterval,
+		requests:             requests,
 	}
+	r.requests.setLimits(agents.ProviderLimits())
+	r.requests.setRateLimits(agents.ProviderRateLimits())
 
 	for _, opt := range opts {
 		opt(r)
@@ -261,6 +361,7 @@ func New(agents *team.Team, opts ...Opt) (*LocalRuntime, error) {
 		return nil, fmt.Errorf("agent %s has no valid model", defaultAgent.Name())
 	}
 
+	r.autosaver = newSessionAutosaver(r.sessionStore, r.autosaveInterval)
 	r.titleGen = newTitleGenerator(model)
 	r.sessionCompactor = newSessionCompactor(model, r.sessionStore)
 
@@ -601,13 +702,16 @@ func (r *LocalRuntime) registerDefaultTools() {
 
 	tt := builtin.NewTransferTaskTool()
 	ht := builtin.NewHandoffTool()
+	rm := builtin.NewReadMoreTool()
 	ttTools, _ := tt.Tools(context.TODO())
 	htTools, _ := ht.Tools(context.TODO())
-	allTools := append(ttTools, htTools...)
+	rmTools, _ := rm.Tools(context.TODO())
+	allTools := slices.Concat(ttTools, htTools, rmTools)
 
 	handlers := map[string]ToolHandlerFunc{
 		builtin.ToolNameTransferTask: r.handleTaskTransfer,
 		builtin.ToolNameHandoff:      r.handleHandoff,
+		builtin.ToolNameReadMore:     r.handleReadMore,
 	}
 
 	for _, t := range allTools {
@@ -622,8 +726,17 @@ func (r *LocalRuntime) registerDefaultTools() {
 }
 
 func (r *LocalRuntime) finalizeEventChannel(ctx context.Context, sess *session.Session, events chan Event) {
-	defer close(events)
+	defer func() {
+		// The events sent in the background stop before the channel is closed
+		r.elicitationEventsChannelMux.Lock()
+		if r.elicitationEventsChannel == events {
+			r.elicitationEventsChannel = nil
+		}
+		r.elicitationEventsChannelMux.Unlock()
+		close(events)
+	}()
 
+	r.flushSession(ctx, sess)
 	events <- StreamStopped(sess.ID, r.currentAgent)
 
 	telemetry.RecordSessionEnd(ctx)
@@ -636,7 +749,13 @@ func (r *LocalRuntime) RunStream(ctx context.Context, sess *session.Session) <-c
 	slog.Debug("Starting runtime stream", "agent", r.currentAgent, "session_id", sess.ID)
 	events := make(chan Event, 128)
 
+	// Opened before the run starts, so that the messages sent right after
+	// are queued rather than starting another run of the session
+	queue := r.openTurnQueue(sess)
+
 	go func() {
+		defer r.closeTurnQueue(sess.ID, queue)
+
 		telemetry.RecordSessionStart(ctx, r.currentAgent, sess.ID)
 
 		ctx, sessionSpan := r.startSpan(ctx, "runtime.session", trace.WithAttributes(
@@ -663,6 +782,17 @@ func (r *LocalRuntime) RunStream(ctx context.Context, sess *session.Session) <-c
 
 		r.emitAgentWarnings(a, events)
 		r.configureToolsetHandlers(a, events)
+		r.restoreTodos(a, sess)
+		r.configureTodoVerifier(a, sess)
+		r.recordProvenance(sess)
+		if r.recorder != nil && !sess.IsSubSession() {
+			r.recorder.start(sess.ID)
+		}
+		if !sess.IsSubSession() {
+			// The session can be rewound to the state it was in before
+			// the message of the user
+			sess.RecordCheckpoint()
+		}
 
 		agentTools, err := r.getTools(ctx, a, sessionSpan, events)
 		if err != nil {
@@ -680,6 +810,7 @@ func (r *LocalRuntime) RunStream(ctx context.Context, sess *session.Session) <-c
 		events <- StreamStarted(sess.ID, a.Name())
 
 		defer r.finalizeEventChannel(ctx, sess, events)
+		defer r.watchTurnQueue(sess, queue, a.Name(), events)()
 
 		r.registerDefaultTools()
 
@@ -691,6 +822,31 @@ func (r *LocalRuntime) RunStream(ctx context.Context, sess *session.Session) <-c
 		// Use a runtime copy of maxIterations so we don't modify the session's persistent config
 		runtimeMaxIterations := sess.MaxIterations
 
+		// The iteration cap and the loop detection are per agent: an agent the
+		// conversation is handed off to starts afresh, with its own
+		iterationAgent := a.Name()
+		loops := newLoopDetector(a.MaxRepeatedToolCalls())
+		budget := newTurnBudget(a.MaxTurnTokens())
+
+		clock := newRunClock(sess, time.Now())
+		if clock != nil {
+			r.runClocks.Store(sess.ID, clock)
+			defer r.runClocks.Delete(sess.ID)
+		}
+
+		// An answer the agent has a low confidence in is verified once per run,
+		// possibly by a stronger model that answers for the rest of the run
+		lowConfidenceVerified := false
+		var escalationModel provider.Provider
+
+		// Once the requests to the model of the agent kept failing, its fallback
+		// model answers for the rest of the run
+		var failoverModel provider.Provider
+
+		// The memories relevant to the message of the user are recalled once per
+		// run, and again for an agent the conversation is handed off to
+		memories := r.recallMemories(ctx, a, sess)
+
 		for {
 			// Set elicitation handler on all MCP toolsets before getting tools
 			a := r.CurrentAgent()
@@ -704,9 +860,52 @@ func (r *LocalRuntime) RunStream(ctx context.Context, sess *session.Session) <-c
 				return
 			}
 
+			// Check time limit: the agent is asked to wrap up shortly before, and
+			// stopped at its next turn after the deadline
+			if clock.expired(time.Now()) {
+				slog.Debug("Time limit reached", "agent", a.Name(), "session_id", sess.ID, "limit", sess.TimeLimit)
+				events <- Warning(fmt.Sprintf("The run was stopped at its time limit of %s.", sess.TimeLimit), a.Name())
+				// Synthesize a final assistant message so callers (e.g., parent agents)
+				// receive a non-empty response.
+				assistantMessage := chat.Message{
+					Role:      chat.MessageRoleAssistant,
+					Content:   fmt.Sprintf("I have reached the time limit of this run (%s). Stopping.", sess.TimeLimit),
+					CreatedAt: time.Now().Format(time.RFC3339),
+				}
+				sess.AddMessage(session.NewAgentMessage(a, &assistantMessage))
+				r.saveSession(ctx, sess)
+				return
+			}
+			if clock.wrapUpDue(time.Now()) {
+				slog.Debug("Asking the agent to wrap up before the time limit", "agent", a.Name(), "session_id", sess.ID, "limit", sess.TimeLimit)
+				sess.AddMessage(session.ImplicitUserMessage(wrapUpInstruction))
+				r.saveSession(ctx, sess)
+				events <- Warning("The run is about to reach its time limit: the agent was asked to wrap up.", a.Name())
+			}
+
+			if a.Name() != iterationAgent {
+				iterationAgent = a.Name()
+				iteration = 0
+				runtimeMaxIterations = cmp.Or(a.MaxIterations(), sess.MaxIterations)
+				loops = newLoopDetector(a.MaxRepeatedToolCalls())
+				budget = newTurnBudget(a.MaxTurnTokens())
+				failoverModel = nil
+				memories = r.recallMemories(ctx, a, sess)
+			}
+
+			// The agent is asked to conclude once the turn used its token
+			// budget, and stopped if it keeps calling tools
+			if budget.concludeDue() {
+				slog.Debug("Asking the agent to conclude at the token budget of its turn", "agent", a.Name(), "session_id", sess.ID, "limit", a.MaxTurnTokens())
+				sess.AddMessage(session.ImplicitUserMessage(concludeInstruction))
+				r.saveSession(ctx, sess)
+				events <- Warning(fmt.Sprintf("The turn used its budget of %d tokens: the agent was asked to conclude.", a.MaxTurnTokens()), a.Name())
+			}
+
 			// Check iteration limit
 			if runtimeMaxIterations > 0 && iteration >= runtimeMaxIterations {
 				slog.Debug("Maximum iterations reached", "agent", a.Name(), "iterations", iteration, "max", runtimeMaxIterations)
+				r.flushSession(ctx, sess)
 				events <- MaxIterationsReached(runtimeMaxIterations)
 
 				// Wait for user decision
@@ -730,6 +929,7 @@ func (r *LocalRuntime) RunStream(ctx context.Context, sess *session.Session) <-c
 					}
 				case <-ctx.Done():
 					slog.Debug("Context cancelled while waiting for max iterations decision", "agent", a.Name())
+					r.cancelTurn(ctx, sess, a, "")
 					return
 				}
 			}
@@ -737,6 +937,7 @@ func (r *LocalRuntime) RunStream(ctx context.Context, sess *session.Session) <-c
 			// Exit immediately if the stream context has been cancelled (e.g., Ctrl+C)
 			if err := ctx.Err(); err != nil {
 				slog.Debug("Runtime stream context cancelled, stopping loop", "agent", a.Name(), "session_id", sess.ID)
+				r.cancelTurn(ctx, sess, a, "")
 				return
 			}
 			slog.Debug("Starting conversation loop iteration", "agent", a.Name())
@@ -747,8 +948,16 @@ func (r *LocalRuntime) RunStream(ctx context.Context, sess *session.Session) <-c
 			))
 
 			model := a.Model()
+			switch {
+			case escalationModel != nil:
+				model = escalationModel
+			case failoverModel != nil:
+				model = failoverModel
+			}
 			modelID := model.ID()
 			slog.Debug("Using agent", "agent", a.Name(), "model", modelID)
+
+			agentTools = r.normalizeTools(a, model, agentTools, events)
 			slog.Debug("Getting model definition", "model_id", modelID)
 			m, err := r.modelsStore.GetModel(ctx, modelID)
 			if err != nil {
@@ -761,35 +970,40 @@ func (r *LocalRuntime) RunStream(ctx context.Context, sess *session.Session) <-c
 			}
 
 			if m != nil && r.sessionCompaction {
-				if sess.InputTokens+sess.OutputTokens > int64(float64(contextLimit)*0.9) {
+				_, threshold := a.Compaction()
+				if sess.InputTokens+sess.OutputTokens > int64(float64(contextLimit)*threshold) {
 					r.Summarize(ctx, sess, "", events)
-					events <- TokenUsage(sess.ID, r.currentAgent, sess.InputTokens, sess.OutputTokens, sess.InputTokens+sess.OutputTokens, contextLimit, sess.Cost)
+					events <- r.tokenUsage(sess, contextLimit, sess.GetMessages(a))
 				}
 			}
 
-			messages := sess.GetMessages(a)
+			messages := withMemories(sess.GetMessages(a), memories)
 			slog.Debug("Retrieved messages for processing", "agent", a.Name(), "message_count", len(messages))
 
-			slog.Debug("Creating chat completion stream", "agent", a.Name())
-			stream, err := model.CreateChatCompletionStream(streamCtx, messages, agentTools)
-			if err != nil {
-				streamSpan.RecordError(err)
-				streamSpan.SetStatus(codes.Error, "creating chat completion")
-				slog.Error("Failed to create chat completion stream", "agent", a.Name(), "error", err)
-				// Track error in telemetry
-				telemetry.RecordError(ctx, err.Error())
-				events <- Error(fmt.Sprintf("creating chat completion: %v", err))
+			hooksExec := r.getHooksExecutor(a)
+			messages, allowed := r.runPreModelHooks(ctx, hooksExec, sess, a, modelID, messages, events)
+			if !allowed {
 				streamSpan.End()
 				return
 			}
 
-			slog.Debug("Processing stream", "agent", a.Name())
-			res, err := r.handleStream(ctx, stream, a, agentTools, sess, m, events)
+			res, answered, answeredDef, err := r.streamWithRetries(ctx, streamCtx, sess, a, model, m, messages, agentTools, events)
+			if answered != model {
+				// The agent failed over to its fallback model for the rest of the turn
+				failoverModel = answered
+				modelID, m = answered.ID(), answeredDef
+				contextLimit = 0
+				if m != nil {
+					contextLimit = int64(m.Limit.Context)
+				}
+			}
 			if err != nil {
-				// Treat context cancellation as a graceful stop
-				if errors.Is(err, context.Canceled) {
+				// Treat context cancellation as a graceful stop, keeping what the
+				// model streamed so far
+				if errors.Is(err, context.Canceled) || ctx.Err() != nil {
 					slog.Debug("Model stream canceled by context", "agent", a.Name(), "session_id", sess.ID)
 					streamSpan.End()
+					r.cancelTurn(ctx, sess, a, res.Content)
 					return
 				}
 				streamSpan.RecordError(err)
@@ -809,6 +1023,21 @@ func (r *LocalRuntime) RunStream(ctx context.Context, sess *session.Session) <-c
 			streamSpan.End()
 			slog.Debug("Stream processed", "agent", a.Name(), "tool_calls", len(res.Calls), "content_length", len(res.Content), "stopped", res.Stopped)
 
+			if !r.runPostModelHooks(ctx, hooksExec, sess, a, modelID, &res, events) {
+				return
+			}
+			if !r.checkOutputGuardrails(ctx, sess, a, &res, events) {
+				return
+			}
+			if holdsOutput(a) && res.Content != "" {
+				events <- AgentChoice(a.Name(), res.Content)
+			}
+
+			var report *confidence.Report
+			if a.ReportsConfidence() && len(res.Calls) == 0 {
+				_, report = confidence.Parse(res.Content)
+			}
+
 			// Add assistant message to conversation history, but skip empty assistant messages
 			// Providers reject assistant messages that have neither content nor tool calls.
 			if strings.TrimSpace(res.Content) != "" || len(res.Calls) > 0 {
@@ -855,19 +1084,72 @@ func (r *LocalRuntime) RunStream(ctx context.Context, sess *session.Session) <-c
 					Cost:              messageCost,
 				}
 
-				sess.AddMessage(session.NewAgentMessage(a, &assistantMessage))
+				agentMessage := session.NewAgentMessage(a, &assistantMessage)
+				agentMessage.Confidence = report
+				sess.AddMessage(agentMessage)
 				r.saveSession(ctx, sess)
 				slog.Debug("Added assistant message to session", "agent", a.Name(), "total_messages", len(sess.GetAllMessages()))
 			} else {
 				slog.Debug("Skipping empty assistant message (no content and no tool calls)", "agent", a.Name())
 			}
 
-			events <- TokenUsage(sess.ID, r.currentAgent, sess.InputTokens, sess.OutputTokens, sess.InputTokens+sess.OutputTokens, contextLimit, sess.Cost)
+			events <- r.tokenUsage(sess, contextLimit, messages)
+
+			if repeated, looping := loops.record(res.Calls); looping {
+				r.stopLooping(ctx, sess, a, res.Calls, repeated, agentTools, events)
+				return
+			}
+
+			if budget.overrun(res.Calls) {
+				r.stopOverBudget(ctx, sess, a, res.Calls, agentTools, events)
+				return
+			}
 
+			itemCount := len(sess.Messages)
 			r.processToolCalls(ctx, sess, res.Calls, agentTools, events)
+			budget.record(res, sess.Messages[itemCount:])
+
+			if ctx.Err() != nil {
+				slog.Debug("Tool calls canceled by context", "agent", a.Name(), "session_id", sess.ID)
+				r.cancelTurn(ctx, sess, a, "")
+				return
+			}
+
+			if res.Stopped && report != nil && report.Level == confidence.Low && !lowConfidenceVerified {
+				if verify, escalateTo := a.LowConfidenceVerification(); verify {
+					lowConfidenceVerified = true
+					escalationModel = escalateTo
+					verifier := modelID
+					if escalateTo != nil {
+						verifier = escalateTo.ID()
+					}
+					slog.Debug("Verifying an answer with a low confidence", "agent", a.Name(), "model", verifier, "session_id", sess.ID)
+					sess.AddMessage(session.ImplicitUserMessage(confidence.VerifyInstruction))
+					r.saveSession(ctx, sess)
+					events <- Warning(fmt.Sprintf("The agent has a low confidence in its answer: verifying it with %s.", verifier), a.Name())
+					continue
+				}
+			}
 
 			if res.Stopped {
 				slog.Debug("Conversation stopped", "agent", a.Name())
+				r.governSession(ctx, a, sess, events)
+
+				// The messages sent during the turn are answered in order,
+				// each in a turn of its own
+				if msg := queue.next(); msg != nil {
+					slog.Debug("Answering a queued message", "agent", a.Name(), "session_id", sess.ID)
+					sess.RecordCheckpoint()
+					sess.AddMessage(msg)
+					r.saveSession(ctx, sess)
+					events <- UserMessage(msg.Message.Content)
+
+					iteration = 0
+					loops = newLoopDetector(a.MaxRepeatedToolCalls())
+					budget = newTurnBudget(a.MaxTurnTokens())
+					lowConfidenceVerified = false
+					continue
+				}
 				break
 			}
 		}
@@ -888,6 +1170,12 @@ func (r *LocalRuntime) getTools(ctx context.Context, a *agent.Agent, sessionSpan
 		}
 	}()
 
+	// A replay answers with the tools the agent had when it was recorded,
+	// without starting its toolsets
+	if r.replay != nil {
+		return r.replay.agentTools(a.Name())
+	}
+
 	agentTools, err := a.Tools(ctx)
 	if err != nil {
 		slog.Error("Failed to get agent tools", "agent", a.Name(), "error", err)
@@ -898,10 +1186,13 @@ func (r *LocalRuntime) getTools(ctx context.Context, a *agent.Agent, sessionSpan
 	}
 
 	slog.Debug("Retrieved agent tools", "agent", a.Name(), "tool_count", len(agentTools))
+	if r.recorder != nil {
+		r.recorder.recordTools(a.Name(), agentTools)
+	}
 	return agentTools, nil
 }
 
-// configureToolsetHandlers sets up elicitation and OAuth handlers for all toolsets of an agent.
+// configureToolsetHandlers sets up elicitation, OAuth, status and sampling handlers for all toolsets of an agent.
 func (r *LocalRuntime) configureToolsetHandlers(a *agent.Agent, events chan Event) {
 	for _, toolset := range a.ToolSets() {
 		toolset.SetElicitationHandler(r.elicitationHandler)
@@ -909,6 +1200,94 @@ func (r *LocalRuntime) configureToolsetHandlers(a *agent.Agent, events chan Even
 			events <- Authorization(tools.ElicitationActionAccept, r.currentAgent)
 		})
 		toolset.SetManagedOAuth(r.managedOAuth)
+		toolset.SetStatusHandler(func(status tools.ServerStatus) {
+			r.emitServerStatus(status, a.Name())
+		})
+		toolset.SetSamplingHandler(r.samplingHandler(a))
+	}
+}
+
+// emitServerStatus sends the status of a supervised MCP server to the client
+// of the current run. Servers are supervised in the background: their status
+// changes between runs aren't sent, the next run lists their tools again.
+func (r *LocalRuntime) emitServerStatus(status tools.ServerStatus, agentName string) {
+	r.emitBackgroundEvent(MCPServerStatus(status, agentName))
+}
+
+// emitBackgroundEvent sends an event that doesn't come from the agent loop,
+// but from a toolset, to the client of the current run. It's dropped when no
+// run is in progress or the client doesn't keep up.
+func (r *LocalRuntime) emitBackgroundEvent(event Event) {
+	r.elicitationEventsChannelMux.RLock()
+	defer r.elicitationEventsChannelMux.RUnlock()
+
+	if r.elicitationEventsChannel == nil {
+		return
+	}
+	select {
+	case r.elicitationEventsChannel <- event:
+	default:
+		slog.Debug("Dropped background event", "event", fmt.Sprintf("%T", event))
+	}
+}
+
+// policyInput builds the document a tool call is evaluated against by the policy engine.
+func policyInput(a *agent.Agent, sess *session.Session, tool tools.Tool, toolArgs map[string]any) policy.Input {
+	if toolArgs == nil {
+		toolArgs = map[string]any{}
+	}
+
+	return policy.Input{
+		Agent: policy.AgentInput{
+			Name: a.Name(),
+		},
+		Tool: policy.ToolInput{
+			Name:     tool.Name,
+			Category: tool.Category,
+			ReadOnly: tool.Annotations.ReadOnlyHint,
+		},
+		Arguments: toolArgs,
+		Session: policy.SessionInput{
+			ID:               sess.ID,
+			Cost:             sess.Cost,
+			InputTokens:      sess.InputTokens,
+			OutputTokens:     sess.OutputTokens,
+			ToolsApproved:    sess.ToolsApproved,
+			UntrustedContent: sess.UntrustedContent,
+			WorkingDir:       sess.WorkingDir,
+		},
+		Egress: policy.ExtractEgress(toolArgs),
+	}
+}
+
+// recordAudit records an audit event about a tool call, if an audit recorder is configured.
+func (r *LocalRuntime) recordAudit(sess *session.Session, a *agent.Agent, toolCall tools.ToolCall, event audit.Event) {
+	if r.auditRecorder == nil {
+		return
+	}
+
+	event.Time = time.Now().UTC()
+	event.SessionID = sess.ID
+	event.Agent = a.Name()
+	event.Tool = toolCall.Function.Name
+	event.ToolCallID = toolCall.ID
+	event.Arguments = toolCall.Function.Arguments
+	r.auditRecorder.Record(event)
+}
+
+// restoreTodos seeds the agent's todo tools with the todo list persisted in the
+// session. This only happens once per session so that todos created since the
+// session was loaded are not overwritten.
+func (r *LocalRuntime) restoreTodos(a *agent.Agent, sess *session.Session) {
+	if sess.IsSubSession() || r.todosSessionID == sess.ID {
+		return
+	}
+	r.todosSessionID = sess.ID
+
+	for _, toolset := range a.ToolSets() {
+		if todoTool := unwrapTodoTool(toolset); todoTool != nil {
+			todoTool.SetTodos(sess.Todos)
+		}
 	}
 }
 
@@ -925,6 +1304,41 @@ func (r *LocalRuntime) emitAgentWarnings(a *agent.Agent, events chan Event) {
 	}
 }
 
+// normalizeTools rewrites the tool definitions that the model's provider would
+// reject, instead of failing the request. Each rewrite is reported once.
+func (r *LocalRuntime) normalizeTools(a *agent.Agent, model provider.Provider, agentTools []tools.Tool, events chan Event) []tools.Tool {
+	dialect := provider.SchemaDialect(model)
+	normalized, changes := tools.NormalizeTools(agentTools, dialect)
+
+	r.schemaRepairsMu.Lock()
+	var unreported []string
+	for _, change := range changes {
+		key := dialect.Name + "|" + change
+		if r.schemaRepairs[key] {
+			continue
+		}
+		if r.schemaRepairs == nil {
+			r.schemaRepairs = make(map[string]bool)
+		}
+		r.schemaRepairs[key] = true
+		unreported = append(unreported, change)
+	}
+	r.schemaRepairsMu.Unlock()
+
+	if len(unreported) > 0 {
+		slog.Warn("Rewrote tool definitions for provider", "agent", a.Name(), "provider", dialect.Name, "changes", unreported)
+
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "Some tool definitions of agent '%s' were adapted to what %s accepts.\n\nChanges:\n\n", a.Name(), dialect.Name)
+		for _, change := range unreported {
+			fmt.Fprintf(&builder, "- %s\n", change)
+		}
+		events <- Warning(strings.TrimSuffix(builder.String(), "\n"), r.currentAgent)
+	}
+
+	return normalized
+}
+
 func formatToolWarning(a *agent.Agent, warnings []string) string {
 	var builder strings.Builder
 	fmt.Fprintf(&builder, "Some toolsets failed to initialize for agent '%s'.\n\nDetails:\n\n", a.Name())
@@ -954,6 +1368,13 @@ func (r *LocalRuntime) ResumeElicitation(ctx context.Context, action tools.Elici
 		Content: content,
 	}
 
+	if r.elicitationPending.Load() == 0 {
+		slog.Debug("Elicitation channel not ready")
+		return fmt.Errorf("no elicitation request in progress")
+	}
+
+	// The channel is buffered: the client may answer before the handler waits
+	// for the response
 	select {
 	case <-ctx.Done():
 		slog.Debug("Context cancelled while sending elicitation response")
@@ -962,8 +1383,7 @@ func (r *LocalRuntime) ResumeElicitation(ctx context.Context, action tools.Elici
 		slog.Debug("Elicitation response sent successfully", "action", action)
 		return nil
 	default:
-		slog.Debug("Elicitation channel not ready")
-		return fmt.Errorf("no elicitation request in progress")
+		return fmt.Errorf("the elicitation request was already answered")
 	}
 }
 
@@ -988,6 +1408,10 @@ func (r *LocalRuntime) handleStream(ctx context.Context, stream chat.MessageStre
 	var thinkingSignature string
 	var thoughtSignature []byte
 	var toolCalls []tools.ToolCall
+	// Arguments are streamed in many small deltas: accumulate them without
+	// copying what was received so far for each delta
+	var toolCallArguments []*strings.Builder
+	var toolsByName map[string]tools.Tool
 	var actualModel string
 	var actualModelEventEmitted bool
 	var messageUsage *chat.Usage
@@ -1001,7 +1425,7 @@ func (r *LocalRuntime) handleStream(ctx context.Context, stream chat.MessageStre
 			break
 		}
 		if err != nil {
-			return streamResult{Stopped: true}, fmt.Errorf("error receiving from stream: %w", err)
+			return streamResult{Content: fullContent.String(), ReasoningContent: fullReasoningContent.String(), Calls: toolCalls, Stopped: true}, fmt.Errorf("error receiving from stream: %w", err)
 		}
 
 		if response.Usage != nil {
@@ -1079,6 +1503,7 @@ func (r *LocalRuntime) handleStream(ctx context.Context, stream chat.MessageStre
 						ID:   deltaToolCall.ID,
 						Type: deltaToolCall.Type,
 					})
+					toolCallArguments = append(toolCallArguments, &strings.Builder{})
 				}
 
 				// Check if we should emit a partial event for this tool call
@@ -1098,26 +1523,23 @@ func (r *LocalRuntime) handleStream(ctx context.Context, stream chat.MessageStre
 					toolCalls[idx].Function.Name = deltaToolCall.Function.Name
 				}
 				if deltaToolCall.Function.Arguments != "" {
-					if toolCalls[idx].Function.Arguments == "" {
-						toolCalls[idx].Function.Arguments = deltaToolCall.Function.Arguments
-					} else {
-						toolCalls[idx].Function.Arguments += deltaToolCall.Function.Arguments
-					}
+					toolCallArguments[idx].WriteString(deltaToolCall.Function.Arguments)
+					toolCalls[idx].Function.Arguments = toolCallArguments[idx].String()
 					// Emit if we get more arguments
 					shouldEmitPartial = true
 				}
 
 				// Emit PartialToolCallEvent when we first get the function name
 				if shouldEmitPartial {
-					// TODO: clean this up, it's gross
-					tool := tools.Tool{}
-					for _, t := range agentTools {
-						if t.Name == toolCalls[idx].Function.Name {
-							tool = t
-							break
+					if toolsByName == nil {
+						toolsByName = make(map[string]tools.Tool, len(agentTools))
+						for _, t := range agentTools {
+							if _, exists := toolsByName[t.Name]; !exists {
+								toolsByName[t.Name] = t
+							}
 						}
 					}
-					events <- PartialToolCall(toolCalls[idx], tool, a.Name())
+					events <- PartialToolCall(toolCalls[idx], toolsByName[toolCalls[idx].Function.Name], a.Name())
 					emittedPartialEvents[deltaToolCall.ID] = true
 				}
 			}
@@ -1135,7 +1557,10 @@ func (r *LocalRuntime) handleStream(ctx context.Context, stream chat.MessageStre
 		}
 
 		if choice.Delta.Content != "" {
-			events <- AgentChoice(a.Name(), choice.Delta.Content)
+			// The answers checked by guardrails or hooks are only shown once final
+			if !holdsOutput(a) {
+				events <- AgentChoice(a.Name(), choice.Delta.Content)
+			}
 			fullContent.WriteString(choice.Delta.Content)
 		}
 	}
@@ -1166,7 +1591,23 @@ func (r *LocalRuntime) processToolCalls(ctx context.Context, sess *session.Sessi
 		agentToolMap[t.Name] = t
 	}
 
+	// The handlers may run concurrently, their results are recorded in the
+	// order of the calls
+	batch := newToolCallBatch(a.MaxParallelToolCalls())
+	defer batch.wait()
+
 	for i, toolCall := range calls {
+		// Every call gets a response, even the ones the user cancelled
+		// before they started
+		if ctx.Err() != nil {
+			for _, canceledCall := range calls[i:] {
+				batch.add(func() {
+					r.addToolErrorResponse(ctx, sess, canceledCall, agentToolMap[canceledCall.Function.Name], events, a, "The tool call was canceled by the user.")
+				})
+			}
+			return
+		}
+
 		callCtx, callSpan := r.startSpan(ctx, "runtime.tool.call", trace.WithAttributes(
 			attribute.String("tool.name", toolCall.Function.Name),
 			attribute.String("tool.type", string(toolCall.Type)),
@@ -1185,16 +1626,18 @@ func (r *LocalRuntime) processToolCalls(ctx context.Context, sess *session.Sessi
 			// Validate that the tool is actually available to this agent
 			if _, available := agentToolMap[toolCall.Function.Name]; !available {
 				slog.Warn("Tool call rejected: tool not available to agent", "agent", a.Name(), "tool", toolCall.Function.Name, "session_id", sess.ID)
-				r.addToolErrorResponse(ctx, sess, toolCall, def.tool, events, a, fmt.Sprintf("Tool '%s' is not available to this agent (%s).", toolCall.Function.Name, a.Name()))
+				batch.add(func() {
+					r.addToolErrorResponse(ctx, sess, toolCall, def.tool, events, a, fmt.Sprintf("Tool '%s' is not available to this agent (%s).", toolCall.Function.Name, a.Name()))
+				})
 				callSpan.SetStatus(codes.Error, "tool not available to agent")
 				callSpan.End()
 				continue
 			}
 			tool = def.tool
-			runTool = func() { r.runAgentTool(callCtx, def.handler, sess, toolCall, def.tool, events, a) }
+			runTool = func() { r.runAgentTool(callCtx, batch, def.handler, sess, toolCall, def.tool, events, a) }
 		} else if t, exists := agentToolMap[toolCall.Function.Name]; exists {
 			tool = t
-			runTool = func() { r.runTool(callCtx, t, toolCall, events, sess, a) }
+			runTool = func() { r.runTool(callCtx, batch, t, toolCall, events, sess, a) }
 		} else {
 			// Tool not found - skip
 			callSpan.SetStatus(codes.Ok, "tool not found")
@@ -1202,16 +1645,45 @@ func (r *LocalRuntime) processToolCalls(ctx context.Context, sess *session.Sessi
 			continue
 		}
 
+		// Arguments not matching the schema of the tool go back to the model
+		// rather than to the tool
+		if invalid := r.validateToolArguments(tool, toolCall); invalid != "" {
+			slog.Debug("Tool call rejected: invalid arguments", "agent", a.Name(), "tool", toolCall.Function.Name, "session_id", sess.ID)
+			batch.add(func() {
+				r.addToolErrorResponse(ctx, sess, toolCall, tool, events, a, invalid)
+			})
+			callSpan.SetStatus(codes.Error, "invalid tool arguments")
+			callSpan.End()
+			continue
+		}
+
+		// The guardrails check the arguments before the user is asked to
+		// approve the call, so that what's approved is what runs. runTool
+		// sees the redacted arguments.
+		checked, blocked := r.checkToolGuardrails(callCtx, sess, a, toolCall, events)
+		toolCall = checked
+		if blocked != nil {
+			batch.add(func() {
+				r.addToolErrorResponse(ctx, sess, toolCall, tool, events, a, fmt.Sprintf("Tool call blocked by guardrail %s: %s", blocked.Guardrail, blocked.Reason))
+			})
+			callSpan.SetStatus(codes.Error, "tool call blocked by guardrail")
+			callSpan.End()
+			continue
+		}
+
 		// Execute tool with approval check
-		canceled := r.executeWithApproval(callCtx, sess, toolCall, tool, events, a, runTool, calls[i+1:])
+		canceled := r.executeWithApproval(callCtx, batch, sess, toolCall, tool, events, a, runTool, calls[i+1:])
 		if canceled {
 			callSpan.SetStatus(codes.Ok, "tool call canceled by user")
 			callSpan.End()
 			return
 		}
 
-		callSpan.SetStatus(codes.Ok, "tool call processed")
-		callSpan.End()
+		// The span ends once the result of the call is recorded
+		batch.add(func() {
+			callSpan.SetStatus(codes.Ok, "tool call processed")
+			callSpan.End()
+		})
 	}
 }
 
@@ -1220,6 +1692,7 @@ func (r *LocalRuntime) processToolCalls(ctx context.Context, sess *session.Sessi
 //
 // The approval flow considers (in order):
 //
+//  0. Policy engine (if configured) - allow/deny decisions are final, ask forces a confirmation
 //  1. Session-level permissions (if configured) - checked first
 //     a. Per-tool settings (Tools map) - most specific, checked first
 //     b. Pattern-based Allow/Deny - broader rules, checked as fallback
@@ -1228,6 +1701,10 @@ func (r *LocalRuntime) processToolCalls(ctx context.Context, sess *session.Sessi
 //  4. tool.Annotations.ReadOnlyHint - auto-approve read-only tools
 //  5. Default: ask for user confirmation
 //
+// When the agent sets confirm_untrusted and untrusted content (e.g. a fetched web
+// page) was added to the conversation, steps that would auto-approve a tool ask
+// for user confirmation instead. Deny rules are still enforced.
+//
 // Example session permissions configurations:
 //
 //	// Per-tool settings - granular control per tool
@@ -1255,6 +1732,7 @@ func (r *LocalRuntime) processToolCalls(ctx context.Context, sess *session.Sessi
 //	}
 func (r *LocalRuntime) executeWithApproval(
 	ctx context.Context,
+	batch *toolCallBatch,
 	sess *session.Session,
 	toolCall tools.ToolCall,
 	tool tools.Tool,
@@ -1278,6 +1756,65 @@ func (r *LocalRuntime) executeWithApproval(
 	// (skipping pattern-based rules and other auto-approve checks)
 	requiresConfirmation := false
 
+	// When the agent is configured to confirm tool calls after untrusted content
+	// entered the conversation, nothing is auto-approved. Deny rules still apply.
+	untrustedGuard := a.ConfirmUntrusted() && sess.UntrustedContent
+	// policyAsk is set when the policy engine requires a user confirmation.
+	policyAsk := false
+	autoApprove := func(reason string) bool {
+		if untrustedGuard {
+			slog.Debug("Auto-approval skipped: conversation contains untrusted content", "tool", toolName, "reason", reason, "session_id", sess.ID)
+			return false
+		}
+		if policyAsk {
+			slog.Debug("Auto-approval skipped: policy requires confirmation", "tool", toolName, "reason", reason, "session_id", sess.ID)
+			return false
+		}
+		slog.Debug("Tool auto-approved", "tool", toolName, "reason", reason, "session_id", sess.ID)
+		r.recordAudit(sess, a, toolCall, audit.Event{Type: audit.ToolCallApproved, Source: reason})
+		runTool()
+		return true
+	}
+
+	// 0. Delegate the decision to the policy engine (if configured)
+	if r.policyEngine != nil {
+		decision, err := r.policyEngine.Evaluate(ctx, policyInput(a, sess, tool, toolArgs))
+		if err != nil {
+			// Fail closed: a broken policy must not let tool calls through
+			slog.Error("Policy evaluation failed", "tool", toolName, "error", err)
+			r.recordAudit(sess, a, toolCall, audit.Event{Type: audit.ToolCallDenied, Source: "policy", Error: err.Error()})
+			batch.add(func() {
+				r.addToolErrorResponse(ctx, sess, toolCall, tool, events, a, fmt.Sprintf("Tool '%s' is blocked: policy evaluation failed.", toolName))
+			})
+			return false
+		}
+
+		r.recordAudit(sess, a, toolCall, audit.Event{Type: audit.PolicyDecision, Decision: string(decision.Action), Reason: decision.Reason})
+
+		switch decision.Action {
+		case policy.Deny:
+			slog.Debug("Tool denied by policy", "tool", toolName, "reason", decision.Reason, "session_id", sess.ID)
+			msg := fmt.Sprintf("Tool '%s' is denied by policy.", toolName)
+			if decision.Reason != "" {
+				msg = fmt.Sprintf("Tool '%s' is denied by policy: %s", toolName, decision.Reason)
+			}
+			r.recordAudit(sess, a, toolCall, audit.Event{Type: audit.ToolCallDenied, Source: "policy", Reason: decision.Reason})
+			batch.add(func() {
+				r.addToolErrorResponse(ctx, sess, toolCall, tool, events, a, msg)
+			})
+			return false
+		case policy.Allow:
+			slog.Debug("Tool allowed by policy", "tool", toolName, "session_id", sess.ID)
+			r.recordAudit(sess, a, toolCall, audit.Event{Type: audit.ToolCallApproved, Source: "policy", Reason: decision.Reason})
+			runTool()
+			return false
+		case policy.Ask:
+			policyAsk = true
+		case policy.NoOpinion:
+			// Fall through to permissions
+		}
+	}
+
 	// 1. Check session-level permissions first (if configured)
 	if sess.Permissions != nil {
 		// 1a. Check Tools map first (new per-tool settings)
@@ -1285,16 +1822,20 @@ func (r *LocalRuntime) executeWithApproval(
 			// Check if tool is disabled
 			if !sess.Permissions.IsToolEnabled(toolName) {
 				slog.Debug("Tool disabled by session permissions", "tool", toolName, "session_id", sess.ID)
-				r.addToolErrorResponse(ctx, sess, toolCall, tool, events, a, fmt.Sprintf("Tool '%s' is disabled by session permissions.", toolName))
+				r.recordAudit(sess, a, toolCall, audit.Event{Type: audit.ToolCallDenied, Source: "session per-tool permissions"})
+				batch.add(func() {
+					r.addToolErrorResponse(ctx, sess, toolCall, tool, events, a, fmt.Sprintf("Tool '%s' is disabled by session permissions.", toolName))
+				})
 				return false
 			}
 			// Check permission mode
 			mode := sess.Permissions.GetToolMode(toolName)
 			switch mode {
 			case session.PermissionModeAlwaysAllow:
-				slog.Debug("Tool auto-approved by session per-tool permissions", "tool", toolName, "mode", mode, "session_id", sess.ID)
-				runTool()
-				return false
+				if autoApprove("session per-tool permissions") {
+					return false
+				}
+				requiresConfirmation = true
 			case session.PermissionModeAsk:
 			default:
 				slog.Debug("Tool requires confirmation by session per-tool permissions ", "tool", toolName, "mode", mode, "session_id", sess.ID)
@@ -1312,12 +1853,15 @@ func (r *LocalRuntime) executeWithApproval(
 			switch decision {
 			case permissions.Deny:
 				slog.Debug("Tool denied by session permissions", "tool", toolName, "session_id", sess.ID)
-				r.addToolErrorResponse(ctx, sess, toolCall, tool, events, a, fmt.Sprintf("Tool '%s' is denied by session permissions.", toolName))
+				r.recordAudit(sess, a, toolCall, audit.Event{Type: audit.ToolCallDenied, Source: "session permissions"})
+				batch.add(func() {
+					r.addToolErrorResponse(ctx, sess, toolCall, tool, events, a, fmt.Sprintf("Tool '%s' is denied by session permissions.", toolName))
+				})
 				return false
 			case permissions.Allow:
-				slog.Debug("Tool auto-approved by session permissions", "tool", toolName, "session_id", sess.ID)
-				runTool()
-				return false
+				if autoApprove("session permissions") {
+					return false
+				}
 			case permissions.Ask:
 				// Fall through to team permissions
 			}
@@ -1331,26 +1875,34 @@ func (r *LocalRuntime) executeWithApproval(
 			switch decision {
 			case permissions.Deny:
 				slog.Debug("Tool denied by team permissions config", "tool", toolName, "session_id", sess.ID)
-				r.addToolErrorResponse(ctx, sess, toolCall, tool, events, a, fmt.Sprintf("Tool '%s' is denied by permissions configuration.", toolName))
+				r.recordAudit(sess, a, toolCall, audit.Event{Type: audit.ToolCallDenied, Source: "team permissions config"})
+				batch.add(func() {
+					r.addToolErrorResponse(ctx, sess, toolCall, tool, events, a, fmt.Sprintf("Tool '%s' is denied by permissions configuration.", toolName))
+				})
 				return false
 			case permissions.Allow:
-				slog.Debug("Tool auto-approved by team permissions config", "tool", toolName, "session_id", sess.ID)
-				runTool()
-				return false
+				if autoApprove("team permissions config") {
+					return false
+				}
 			case permissions.Ask:
 				// Fall through to normal approval flow
 			}
 		}
 
 		// Check --yolo flag or read-only hint (skip if per-tool ask mode is set)
-		if sess.ToolsApproved || tool.Annotations.ReadOnlyHint {
-			runTool()
+		if (sess.ToolsApproved || tool.Annotations.ReadOnlyHint) && autoApprove("yolo or read-only") {
+			return false
+		}
+
+		if r.toolApprovals != nil && r.toolApprovals.IsAlwaysAllowed(a.Name(), toolName) && autoApprove("always allowed by the user") {
 			return false
 		}
 	}
 
-	// Ask user for confirmation
+	// Ask user for confirmation, once the results of the calls running are shown
+	batch.wait()
 	slog.Debug("Tools not approved, waiting for resume", "tool", toolCall.Function.Name, "session_id", sess.ID)
+	r.flushSession(ctx, sess)
 	events <- ToolCallConfirmation(toolCall, tool, a.Name())
 
 	select {
@@ -1358,18 +1910,37 @@ func (r *LocalRuntime) executeWithApproval(
 		switch cType {
 		case ResumeTypeApprove:
 			slog.Debug("Resume signal received, approving tool", "tool", toolCall.Function.Name, "session_id", sess.ID)
+			r.recordAudit(sess, a, toolCall, audit.Event{Type: audit.ToolCallApproved, Source: "user"})
 			runTool()
 		case ResumeTypeApproveSession:
 			slog.Debug("Resume signal received, approving session", "tool", toolCall.Function.Name, "session_id", sess.ID)
+			r.recordAudit(sess, a, toolCall, audit.Event{Type: audit.ToolCallApproved, Source: "user", Reason: "approved for the session"})
 			sess.ToolsApproved = true
 			runTool()
+		case ResumeTypeApproveTool:
+			slog.Debug("Resume signal received, approving tool for the session", "tool", toolCall.Function.Name, "session_id", sess.ID)
+			r.recordAudit(sess, a, toolCall, audit.Event{Type: audit.ToolCallApproved, Source: "user", Reason: "tool approved for the session"})
+			sess.AllowTool(toolName)
+			runTool()
+		case ResumeTypeAlwaysApproveTool:
+			slog.Debug("Resume signal received, always approving tool", "tool", toolCall.Function.Name, "session_id", sess.ID)
+			r.recordAudit(sess, a, toolCall, audit.Event{Type: audit.ToolCallApproved, Source: "user", Reason: "tool always approved"})
+			sess.AllowTool(toolName)
+			if r.toolApprovals != nil {
+				if err := r.toolApprovals.AlwaysAllow(a.Name(), toolName); err != nil {
+					slog.Warn("Failed to remember tool approval", "agent", a.Name(), "tool", toolName, "error", err)
+				}
+			}
+			runTool()
 		case ResumeTypeReject:
 			slog.Debug("Resume signal received, rejecting tool", "tool", toolCall.Function.Name, "session_id", sess.ID)
+			r.recordAudit(sess, a, toolCall, audit.Event{Type: audit.ToolCallRejected, Source: "user"})
 			r.addToolErrorResponse(ctx, sess, toolCall, tool, events, a, "The user rejected the tool call.")
 		}
 		return false
 	case <-ctx.Done():
 		slog.Debug("Context cancelled while waiting for resume", "tool", toolCall.Function.Name, "session_id", sess.ID)
+		r.recordAudit(sess, a, toolCall, audit.Event{Type: audit.ToolCallRejected, Source: "user", Reason: "canceled"})
 		r.addToolErrorResponse(ctx, sess, toolCall, tool, events, a, "The tool call was canceled by the user.")
 		for _, remainingCall := range remainingCalls {
 			r.addToolErrorResponse(ctx, sess, remainingCall, tool, events, a, "The tool call was canceled by the user.")
@@ -1380,8 +1951,11 @@ func (r *LocalRuntime) executeWithApproval(
 
 // executeToolWithHandler is a common helper that handles tool execution, error handling,
 // event emission, and session updates. It reduces duplication between runTool and runAgentTool.
+// The handler runs in the batch, possibly concurrently with other calls; the result is
+// recorded in the session, then recorded is called, in the order of the calls.
 func (r *LocalRuntime) executeToolWithHandler(
 	ctx context.Context,
+	batch *toolCallBatch,
 	toolCall tools.ToolCall,
 	tool tools.Tool,
 	events chan Event,
@@ -1389,6 +1963,7 @@ func (r *LocalRuntime) executeToolWithHandler(
 	a *agent.Agent,
 	spanName string,
 	execute func(ctx context.Context) (*tools.ToolCallResult, time.Duration, error),
+	recorded func(res *tools.ToolCallResult),
 ) {
 	ctx, span := r.startSpan(ctx, spanName, trace.WithAttributes(
 		attribute.String("tool.name", toolCall.Function.Name),
@@ -1396,38 +1971,110 @@ func (r *LocalRuntime) executeToolWithHandler(
 		attribute.String("session.id", sess.ID),
 		attribute.String("tool.call_id", toolCall.ID),
 	))
-	defer span.End()
 
-	events <- ToolCall(toolCall, tool, a.Name())
+	var (
+		res *tools.ToolCallResult
+		err error
+	)
+	batch.run(ctx, func(ctx context.Context) {
+		events <- ToolCall(toolCall, tool, a.Name())
 
-	res, duration, err := execute(ctx)
+		var duration time.Duration
+		res, duration, err = execute(ctx)
 
-	telemetry.RecordToolCall(ctx, toolCall.Function.Name, sess.ID, a.Name(), duration, err)
+		telemetry.RecordToolCall(ctx, toolCall.Function.Name, sess.ID, a.Name(), duration, err)
 
-	if err != nil {
-		if errors.Is(err, context.Canceled) || errors.Is(ctx.Err(), context.Canceled) {
-			slog.Debug("Tool handler canceled by context", "tool", toolCall.Function.Name, "agent", a.Name(), "session_id", sess.ID)
-			res = tools.ResultError("The tool call was canceled by the user.")
-			span.SetStatus(codes.Ok, "tool handler canceled by user")
+		if err != nil {
+			if errors.Is(err, context.Canceled) || errors.Is(ctx.Err(), context.Canceled) {
+				slog.Debug("Tool handler canceled by context", "tool", toolCall.Function.Name, "agent", a.Name(), "session_id", sess.ID)
+				res = tools.ResultError("The tool call was canceled by the user.")
+				span.SetStatus(codes.Ok, "tool handler canceled by user")
+			} else {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, "tool handler error")
+				slog.Error("Error calling tool", "tool", toolCall.Function.Name, "error", err)
+				res = tools.ResultError(fmt.Sprintf("Error calling tool: %v", err))
+			}
 		} else {
-			span.RecordError(err)
-			span.SetStatus(codes.Error, "tool handler error")
-			slog.Error("Error calling tool", "tool", toolCall.Function.Name, "error", err)
-			res = tools.ResultError(fmt.Sprintf("Error calling tool: %v", err))
+			span.SetStatus(codes.Ok, "tool handler completed")
+			slog.Debug("Tool call completed", "tool", toolCall.Function.Name, "output_length", len(res.Output))
+		}
+	}, func() {
+		defer span.End()
+		r.recordToolResult(ctx, toolCall, tool, events, sess, a, res, err)
+		if recorded != nil {
+			recorded(res)
+		}
+	})
+}
+
+// recordToolResult emits the result of a tool call and adds it to the session.
+func (r *LocalRuntime) recordToolResult(
+	ctx context.Context,
+	toolCall tools.ToolCall,
+	tool tools.Tool,
+	events chan Event,
+	sess *session.Session,
+	a *agent.Agent,
+	res *tools.ToolCallResult,
+	err error,
+) {
+
+	// Outputs too large to be kept in memory are written to a file of the
+	// session: the UI and the conversation only keep their beginning
+	output := res.Output
+	spilled := false
+	if !res.IsError && len(output) > session.SpillThreshold {
+		if preview, spillErr := spillToolOutput(sess, a, toolCall, output); spillErr != nil {
+			slog.Warn("Failed to spill large tool output", "tool", toolCall.Function.Name, "error", spillErr)
+		} else {
+			spilledRes := *res
+			spilledRes.Output = preview
+			res = &spilledRes
+			spilled = true
 		}
-	} else {
-		span.SetStatus(codes.Ok, "tool handler completed")
-		slog.Debug("Tool call completed", "tool", toolCall.Function.Name, "output_length", len(res.Output))
 	}
 
 	events <- ToolCallResponse(toolCall, tool, res, res.Output, a.Name())
 
+	executed := audit.Event{Type: audit.ToolCallExecuted}
+	if err != nil {
+		executed.Error = err.Error()
+	} else if res.IsError {
+		executed.Error = res.Output
+	}
+	r.recordAudit(sess, a, toolCall, executed)
+
+	if res.Untrusted {
+		if !sess.UntrustedContent {
+			r.recordAudit(sess, a, toolCall, audit.Event{Type: audit.UntrustedContent})
+		}
+		sess.UntrustedContent = true
+	}
+
+	// Keep track of the latest todo list so that it survives restarts
+	if todos, ok := res.Meta.([]todo.Todo); ok && tool.Category == "todo" {
+		sess.Todos = todos
+	}
+
 	// Ensure tool response content is not empty for API compatibility
 	content := res.Output
 	if strings.TrimSpace(content) == "" {
 		content = "(no output)"
 	}
 
+	// Large results are summarized before entering the context; the TUI keeps showing the raw output
+	if !res.IsError {
+		if summary, ok := r.summarizeToolResult(ctx, a, toolCall, output); ok {
+			if spilled {
+				// read_more reads the spilled file rather than a copy in memory
+				r.rawToolOutputs.Delete(toolCall.ID)
+			}
+			content = summary
+			events <- ToolResultSummarized(toolCall, summary, estimateTokens(output), a.Name())
+		}
+	}
+
 	toolResponseMsg := chat.Message{
 		Role:       chat.MessageRoleTool,
 		Content:    content,
@@ -1439,7 +2086,7 @@ func (r *LocalRuntime) executeToolWithHandler(
 }
 
 // runTool executes agent tools from toolsets (MCP, filesystem, etc.).
-func (r *LocalRuntime) runTool(ctx context.Context, tool tools.Tool, toolCall tools.ToolCall, events chan Event, sess *session.Session, a *agent.Agent) {
+func (r *LocalRuntime) runTool(ctx context.Context, batch *toolCallBatch, tool tools.Tool, toolCall tools.ToolCall, events chan Event, sess *session.Session, a *agent.Agent) {
 	// Get hooks executor for this agent
 	hooksExec := r.getHooksExecutor(a)
 
@@ -1462,20 +2109,59 @@ func (r *LocalRuntime) runTool(ctx context.Context, tool tools.Tool, toolCall to
 			// Hook blocked the tool call
 			slog.Debug("Pre-tool hook blocked tool call", "tool", toolCall.Function.Name, "message", result.Message)
 			events <- HookBlocked(toolCall, tool, result.Message, a.Name())
-			r.addToolErrorResponse(ctx, sess, toolCall, tool, events, a, "Tool call blocked by hook: "+result.Message)
+			batch.add(func() {
+				r.addToolErrorResponse(ctx, sess, toolCall, tool, events, a, "Tool call blocked by hook: "+result.Message)
+			})
 			return
-		case result.SystemMessage != "":
-			events <- Warning(result.SystemMessage, a.Name())
+		default:
+			if result.SystemMessage != "" {
+				events <- Warning(result.SystemMessage, a.Name())
+			}
+			// The tool runs with the arguments updated by the hooks, which
+			// the guardrails check again
+			if len(result.ModifiedInput) > 0 {
+				toolCall = withModifiedInput(toolCall, toolInput, result.ModifiedInput)
+
+				checked, blocked := r.checkToolGuardrails(ctx, sess, a, toolCall, events)
+				toolCall = checked
+				if blocked != nil {
+					batch.add(func() {
+						r.addToolErrorResponse(ctx, sess, toolCall, tool, events, a, fmt.Sprintf("Tool call blocked by guardrail %s: %s", blocked.Guardrail, blocked.Reason))
+					})
+					return
+				}
+			}
 		}
 	}
 
-	r.executeToolWithHandler(ctx, toolCall, tool, events, sess, a, "runtime.tool.handler",
+	r.executeToolWithHandler(ctx, batch, toolCall, tool, events, sess, a, "runtime.tool.handler",
 		func(ctx context.Context) (*tools.ToolCallResult, time.Duration, error) {
-			res, err := tool.Handler(ctx, toolCall)
+			res, err := r.callTool(ctx, tool, toolCall, events, sess, a)
 			return res, 0, err
-		})
+		},
+		func(res *tools.ToolCallResult) { r.runPostToolHooks(ctx, hooksExec, toolCall, res, events, sess, a) })
+}
 
-	// Execute post-tool hooks if configured
+// withModifiedInput returns the tool call with its arguments updated by the
+// pre-tool hooks.
+func withModifiedInput(toolCall tools.ToolCall, toolInput, modified map[string]any) tools.ToolCall {
+	args := maps.Clone(toolInput)
+	if args == nil {
+		args = make(map[string]any, len(modified))
+	}
+	maps.Copy(args, modified)
+
+	arguments, err := json.Marshal(args)
+	if err != nil {
+		slog.Warn("Failed to apply the arguments updated by a hook", "tool", toolCall.Function.Name, "error", err)
+		return toolCall
+	}
+	toolCall.Function.Arguments = string(arguments)
+	return toolCall
+}
+
+// runPostToolHooks executes the post-tool hooks of the agent, if configured.
+func (r *LocalRuntime) runPostToolHooks(ctx context.Context, hooksExec *hooks.Executor, toolCall tools.ToolCall, res *tools.ToolCallResult, events chan Event, sess *session.Session, a *agent.Agent) {
 	if hooksExec != nil && hooksExec.HasPostToolUseHooks() {
 		toolInput := parseToolInput(toolCall.Function.Arguments)
 		input := &hooks.Input{
@@ -1484,7 +2170,7 @@ func (r *LocalRuntime) runTool(ctx context.Context, tool tools.Tool, toolCall to
 			ToolName:     toolCall.Function.Name,
 			ToolUseID:    toolCall.ID,
 			ToolInput:    toolInput,
-			ToolResponse: nil, // TODO: pass actual tool response if needed
+			ToolResponse: res.Output,
 		}
 
 		result, err := hooksExec.ExecutePostToolUse(ctx, input)
@@ -1505,13 +2191,17 @@ func parseToolInput(arguments string) map[string]any {
 	return result
 }
 
-func (r *LocalRuntime) runAgentTool(ctx context.Context, handler ToolHandlerFunc, sess *session.Session, toolCall tools.ToolCall, tool tools.Tool, events chan Event, a *agent.Agent) {
-	r.executeToolWithHandler(ctx, toolCall, tool, events, sess, a, "runtime.tool.handler.runtime",
+// runAgentTool executes the tools of the runtime (transfer_task, handoff...).
+// They change the session and the current agent, so they run alone, once the
+// results of the tool calls before them are recorded.
+func (r *LocalRuntime) runAgentTool(ctx context.Context, batch *toolCallBatch, handler ToolHandlerFunc, sess *session.Session, toolCall tools.ToolCall, tool tools.Tool, events chan Event, a *agent.Agent) {
+	batch.wait()
+	r.executeToolWithHandler(ctx, nil, toolCall, tool, events, sess, a, "runtime.tool.handler.runtime",
 		func(ctx context.Context) (*tools.ToolCallResult, time.Duration, error) {
 			start := time.Now()
 			res, err := handler(ctx, sess, toolCall, events)
 			return res, time.Since(start), err
-		})
+		}, nil)
 }
 
 // addToolErrorResponse adds a tool error response to the session and emits the event.
@@ -1532,11 +2222,36 @@ func (r *LocalRuntime) addToolErrorResponse(ctx context.Context, sess *session.S
 // saveSession persists the session to the store, but only for root sessions.
 // Sub-sessions (those with a ParentID) are not persisted as standalone entries;
 // they are embedded within the parent session's Messages array.
+// Writes are coalesced: see sessionAutosaver.
 func (r *LocalRuntime) saveSession(ctx context.Context, sess *session.Session) {
 	if sess.IsSubSession() {
 		return
 	}
-	_ = r.sessionStore.UpdateSession(ctx, sess)
+	r.autosaver.save(ctx, sess)
+}
+
+// cancelTurn records that the user cancelled the current turn: what the model
+// streamed so far, if anything, then a marker telling the next turn that this
+// one was cut short.
+func (r *LocalRuntime) cancelTurn(ctx context.Context, sess *session.Session, a *agent.Agent, partialContent string) {
+	if strings.TrimSpace(partialContent) != "" {
+		sess.AddMessage(session.NewAgentMessage(a, &chat.Message{
+			Role:      chat.MessageRoleAssistant,
+			Content:   partialContent,
+			CreatedAt: time.Now().Format(time.RFC3339),
+		}))
+	}
+	sess.AddMessage(session.TurnCancelledMessage())
+	r.saveSession(ctx, sess)
+}
+
+// flushSession writes the changes of a root session not persisted yet, before
+// the run stops or waits for the user.
+func (r *LocalRuntime) flushSession(ctx context.Context, sess *session.Session) {
+	if sess.IsSubSession() {
+		return
+	}
+	r.autosaver.flush(ctx, sess)
 }
 
 // startSpan wraps tracer.Start, returning a no-op span if the tracer is nil.
@@ -1548,16 +2263,17 @@ func (r *LocalRuntime) startSpan(ctx context.Context, name string, opts ...trace
 }
 
 func (r *LocalRuntime) handleTaskTransfer(ctx context.Context, sess *session.Session, toolCall tools.ToolCall, evts chan Event) (*tools.ToolCallResult, error) {
-	var params struct {
-		Agent          string `json:"agent"`
-		Task           string `json:"task"`
-		ExpectedOutput string `json:"expected_output"`
-	}
-
+	var params builtin.TransferTaskArgs
 	if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
 		return nil, fmt.Errorf("invalid arguments: %w", err)
 	}
 
+	// Sub-tasks inherit the priority of the task they are part of
+	priority := cmp.Or(params.Priority, sess.Priority)
+	if _, ok := taskPriorityWeights[cmp.Or(priority, builtin.TaskPriorityNormal)]; !ok {
+		return nil, fmt.Errorf("invalid priority %q: must be one of critical, normal or background", params.Priority)
+	}
+
 	a := r.CurrentAgent()
 
 	// Span for task transfer (optional)
@@ -1565,22 +2281,23 @@ func (r *LocalRuntime) handleTaskTransfer(ctx context.Context, sess *session.Ses
 		attribute.String("from.agent", a.Name()),
 		attribute.String("to.agent", params.Agent),
 		attribute.String("session.id", sess.ID),
+		attribute.String("priority", priority),
 	))
 	defer span.End()
 
-	slog.Debug("Transferring task to agent", "from_agent", a.Name(), "to_agent", params.Agent, "task", params.Task)
+	slog.Debug("Transferring task to agent", "from_agent", a.Name(), "to_agent", params.Agent, "task", params.Task, "priority", priority)
 
 	ca := r.currentAgent
 
 	// Emit agent switching start event
-	evts <- AgentSwitching(true, ca, params.Agent)
+	evts <- AgentSwitching(true, ca, params.Agent, priority)
 
 	r.currentAgent = params.Agent
 	defer func() {
 		r.currentAgent = ca
 
 		// Emit agent switching end event
-		evts <- AgentSwitching(false, params.Agent, ca)
+		evts <- AgentSwitching(false, params.Agent, ca, priority)
 
 		// Restore original agent info in sidebar
 		if originalAgent, err := r.team.Agent(ca); err == nil {
@@ -1614,9 +2331,19 @@ func (r *LocalRuntime) handleTaskTransfer(ctx context.Context, sess *session.Ses
 		session.WithToolsApproved(sess.ToolsApproved),
 		session.WithSendUserMessage(false),
 		session.WithParentID(sess.ID),
+		session.WithPriority(priority),
 	)
+	s.UntrustedContent = sess.UntrustedContent
+	if clock, ok := r.runClocks.Load(sess.ID); ok {
+		// The task must fit in what remains of the run
+		s.TimeLimit = clock.(*runClock).remaining(time.Now())
+	}
 
+	start := time.Now()
 	for event := range r.RunStream(ctx, s) {
+		if e, ok := event.(interface{ nest() }); ok {
+			e.nest()
+		}
 		evts <- event
 		if errEvent, ok := event.(*ErrorEvent); ok {
 			span.RecordError(fmt.Errorf("%s", errEvent.Error))
@@ -1626,16 +2353,24 @@ func (r *LocalRuntime) handleTaskTransfer(ctx context.Context, sess *session.Ses
 	}
 
 	sess.ToolsApproved = s.ToolsApproved
+	sess.UntrustedContent = sess.UntrustedContent || s.UntrustedContent
+	if s.Todos != nil {
+		sess.Todos = s.Todos
+	}
 
+	s.Handoff = session.NewHandoff(params.Agent, s, time.Since(start))
 	sess.AddSubSession(s)
 
-	slog.Debug("Task transfer completed", "agent", params.Agent, "task", params.Task)
+	slog.Debug("Task transfer completed", "agent", params.Agent, "task", params.Task, "outcome", s.Handoff.Outcome)
 
 	span.SetStatus(codes.Ok, "task transfer completed")
-	return tools.ResultSuccess(s.GetLastAssistantMessageContent()), nil
+	return &tools.ToolCallResult{
+		Output: s.GetLastAssistantMessageContent() + "\n\n" + s.Handoff.String(),
+		Meta:   s.Handoff,
+	}, nil
 }
 
-func (r *LocalRuntime) handleHandoff(_ context.Context, _ *session.Session, toolCall tools.ToolCall, _ chan Event) (*tools.ToolCallResult, error) {
+func (r *LocalRuntime) handleHandoff(_ context.Context, sess *session.Session, toolCall tools.ToolCall, _ chan Event) (*tools.ToolCallResult, error) {
 	var params builtin.HandoffArgs
 	if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
 		return nil, fmt.Errorf("invalid arguments: %w", err)
@@ -1669,6 +2404,7 @@ func (r *LocalRuntime) handleHandoff(_ context.Context, _ *session.Session, tool
 	}
 
 	r.currentAgent = next.Name()
+	sess.AddNote(ca, next.Name(), params.Notes)
 	handoffMessage := "The agent " + ca + " handed off the conversation to you. " +
 		"Your available handoff agents and tools are specified in the system messages that follow. " +
 		"Only use those capabilities - do not attempt to use tools or hand off to agents that you see " +
@@ -1684,7 +2420,11 @@ func (r *LocalRuntime) handleHandoff(_ context.Context, _ *session.Session, tool
 // The additionalPrompt parameter allows users to provide additional instructions
 // for the summarization (e.g., "focus on code changes" or "include action items").
 func (r *LocalRuntime) Summarize(ctx context.Context, sess *session.Session, additionalPrompt string, events chan Event) {
-	r.sessionCompactor.Compact(ctx, sess, additionalPrompt, events, r.currentAgent)
+	var model provider.Provider
+	if a, err := r.team.Agent(r.currentAgent); err == nil {
+		model, _ = a.Compaction()
+	}
+	r.sessionCompactor.Compact(ctx, model, sess, additionalPrompt, events, r.currentAgent)
 }
 
 // setElicitationEventsChannel sets the current events channel for elicitation requests
@@ -1718,6 +2458,14 @@ func (r *LocalRuntime) elicitationHandler(ctx context.Context, req *mcp.ElicitPa
 	slog.Debug("Sending elicitation request event to client", "message", req.Message, "requested_schema", req.RequestedSchema)
 	slog.Debug("Elicitation request meta", "meta", req.Meta)
 
+	// Drop the response to a previous request that was cancelled
+	select {
+	case <-r.elicitationRequestCh:
+	default:
+	}
+	r.elicitationPending.Add(1)
+	defer r.elicitationPending.Add(-1)
+
 	// Send elicitation request event to the runtime's client
 	eventsChannel <- ElicitationRequest(req.Message, req.RequestedSchema, req.Meta, r.currentAgent)
 
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -13,11 +14,14 @@ import (
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/audit"
 	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/confidence"
 	"github.com/docker/cagent/pkg/config/latest"
 	"github.com/docker/cagent/pkg/model/provider/base"
 	"github.com/docker/cagent/pkg/modelsdev"
 	"github.com/docker/cagent/pkg/permissions"
+	"github.com/docker/cagent/pkg/policy"
 	"github.com/docker/cagent/pkg/rag"
 	"github.com/docker/cagent/pkg/rag/database"
 	"github.com/docker/cagent/pkg/rag/strategy"
@@ -25,6 +29,7 @@ import (
 	"github.com/docker/cagent/pkg/session"
 	"github.com/docker/cagent/pkg/team"
 	"github.com/docker/cagent/pkg/tools"
+	"github.com/docker/cagent/pkg/tools/builtin"
 )
 
 type stubToolSet struct {
@@ -400,6 +405,54 @@ func TestContextCancellation(t *testing.T) {
 	require.IsType(t, &StreamStoppedEvent{}, events[len(events)-1])
 }
 
+// cancelledStream streams its responses, then cancels the run as if the user
+// pressed Esc.
+type cancelledStream struct {
+	*mockStream
+	cancel context.CancelFunc
+}
+
+func (s *cancelledStream) Recv() (chat.MessageStreamResponse, error) {
+	resp, err := s.mockStream.Recv()
+	if errors.Is(err, io.EOF) {
+		s.cancel()
+		return chat.MessageStreamResponse{}, context.Canceled
+	}
+	return resp, err
+}
+
+func TestContextCancellation_MidStream(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	stream := &cancelledStream{mockStream: newStreamBuilder().AddContent("Half an ans").Build(), cancel: cancel}
+	prov := &mockProvider{id: "test/mock-model", stream: stream}
+	root := agent.New("root", "You are a test agent", agent.WithModel(prov))
+	tm := team.New(team.WithAgents(root))
+
+	rt, err := New(tm, WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+
+	sess := session.New(session.WithUserMessage("Hi"))
+	sess.Title = "Unit Test"
+
+	var events []Event
+	for ev := range rt.RunStream(ctx, sess) {
+		events = append(events, ev)
+	}
+
+	require.False(t, hasEventType(t, events, &ErrorEvent{}))
+	require.IsType(t, &StreamStoppedEvent{}, events[len(events)-1])
+
+	// What was streamed is kept, followed by the marker of the cancelled turn
+	messages := sess.GetAllMessages()
+	require.Len(t, messages, 3)
+	require.Equal(t, chat.MessageRoleAssistant, messages[1].Message.Role)
+	require.Equal(t, "Half an ans", messages[1].Message.Content)
+	require.True(t, messages[2].TurnCancelled)
+	require.True(t, messages[2].Implicit)
+}
+
 // stubRAGStrategy is a minimal implementation of strategy.Strategy for testing RAG initialization.
 type stubRAGStrategy struct{}
 
@@ -626,6 +679,76 @@ func TestCompaction(t *testing.T) {
 	require.NotEqual(t, -1, compactionStartIdx, "expected a SessionCompaction start event")
 }
 
+func TestCompaction_Threshold(t *testing.T) {
+	mainStream := newStreamBuilder().
+		AddContent("Hello there").
+		AddStopWithUsage(60, 0). // Context limit will be 100
+		Build()
+	summaryStream := newStreamBuilder().
+		AddContent("summary").
+		AddStopWithUsage(1, 1).
+		Build()
+
+	prov := &queueProvider{id: "test/mock-model", streams: []chat.MessageStream{mainStream}}
+	summaryProv := &queueProvider{id: "test/cheap-model", streams: []chat.MessageStream{summaryStream}}
+
+	// Compacted with a cheaper model above half of the context limit
+	root := agent.New("root", "You are a test agent", agent.WithModel(prov), agent.WithCompaction(summaryProv, 0.5))
+	tm := team.New(team.WithAgents(root))
+
+	rt, err := New(tm, WithSessionCompaction(true), WithModelStore(mockModelStoreWithLimit{limit: 100}))
+	require.NoError(t, err)
+
+	sess := session.New(session.WithUserMessage("Start"), session.WithTitle("Unit Test"))
+	for range rt.RunStream(t.Context(), sess) {
+	}
+
+	sess.AddMessage(session.UserMessage("Again"))
+	var seen []Event
+	for ev := range rt.RunStream(t.Context(), sess) {
+		seen = append(seen, ev)
+	}
+
+	require.True(t, hasEventType(t, seen, &SessionCompactionEvent{}))
+	require.True(t, hasEventType(t, seen, &SessionSummaryEvent{}))
+	require.Equal(t, "summary", sess.Messages[len(sess.Messages)-1].Summary)
+}
+
+func TestRunStream_EscalatesLowConfidence(t *testing.T) {
+	lowStream := newStreamBuilder().
+		AddContent("Probably 100.\n<confidence level=\"low\">I guessed.</confidence>").
+		AddStopWithUsage(3, 2).
+		Build()
+	highStream := newStreamBuilder().
+		AddContent("It's 100.\n<confidence level=\"high\">It's in the docs.</confidence>").
+		AddStopWithUsage(3, 2).
+		Build()
+
+	prov := &queueProvider{id: "test/mock-model", streams: []chat.MessageStream{lowStream}}
+	strong := &queueProvider{id: "test/strong-model", streams: []chat.MessageStream{highStream}}
+	root := agent.New("root", "You are a test agent", agent.WithModel(prov), agent.WithConfidenceReports(true, strong))
+	tm := team.New(team.WithAgents(root))
+
+	rt, err := New(tm, WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+
+	sess := session.New(session.WithUserMessage("What's the limit?"), session.WithTitle("Unit Test"))
+	var events []Event
+	for ev := range rt.RunStream(t.Context(), sess) {
+		events = append(events, ev)
+	}
+
+	require.True(t, hasEventType(t, events, &WarningEvent{}))
+
+	messages := sess.GetAllMessages()
+	require.Len(t, messages, 4)
+	require.Equal(t, &confidence.Report{Level: confidence.Low, Rationale: "I guessed."}, messages[1].Confidence)
+	require.True(t, messages[2].Implicit)
+	require.Equal(t, confidence.VerifyInstruction, messages[2].Message.Content)
+	require.Equal(t, "test/strong-model", messages[3].Message.Model)
+	require.Equal(t, confidence.High, messages[3].Confidence.Level)
+}
+
 func TestSessionWithoutUserMessage(t *testing.T) {
 	stream := newStreamBuilder().AddContent("OK").AddStopWithUsage(1, 1).Build()
 
@@ -1259,6 +1382,119 @@ func TestSessionPermissions_PerToolAskMode(t *testing.T) {
 	require.True(t, gotConfirmation, "expected tool to require confirmation with ask mode")
 }
 
+type fakeToolApprovals map[string]bool
+
+func (f fakeToolApprovals) IsAlwaysAllowed(agentName, toolName string) bool {
+	return f[agentName+"/"+toolName]
+}
+
+func (f fakeToolApprovals) AlwaysAllow(agentName, toolName string) error {
+	f[agentName+"/"+toolName] = true
+	return nil
+}
+
+func TestToolApprovals_AlwaysApproveTool(t *testing.T) {
+	var executions int
+	agentTools := []tools.Tool{{
+		Name:       "shell",
+		Parameters: map[string]any{},
+		Handler: func(_ context.Context, _ tools.ToolCall) (*tools.ToolCallResult, error) {
+			executions++
+			return tools.ResultSuccess("executed"), nil
+		},
+	}}
+
+	prov := &mockProvider{id: "test/mock-model", stream: &mockStream{}}
+	root := agent.New("root", "You are a test agent",
+		agent.WithModel(prov),
+		agent.WithToolSets(newStubToolSet(nil, agentTools, nil)),
+	)
+	tm := team.New(team.WithAgents(root))
+
+	approvals := fakeToolApprovals{}
+	rt, err := New(tm, WithSessionCompaction(false), WithModelStore(mockModelStore{}), WithToolApprovals(approvals))
+	require.NoError(t, err)
+
+	calls := []tools.ToolCall{{
+		ID:       "call_1",
+		Type:     "function",
+		Function: tools.FunctionCall{Name: "shell", Arguments: "{}"},
+	}}
+
+	// The first call asks the user, who always approves the tool
+	sess := session.New(session.WithUserMessage("Test"))
+	events := make(chan Event, 10)
+	go func() {
+		rt.processToolCalls(t.Context(), sess, calls, agentTools, events)
+		close(events)
+	}()
+	var gotConfirmation bool
+	for ev := range events {
+		if _, ok := ev.(*ToolCallConfirmationEvent); ok {
+			gotConfirmation = true
+			rt.resumeChan <- ResumeTypeAlwaysApproveTool
+		}
+	}
+
+	require.True(t, gotConfirmation)
+	require.Equal(t, 1, executions)
+	require.True(t, approvals["root/shell"])
+	require.Equal(t, session.PermissionModeAlwaysAllow, sess.Permissions.GetToolMode("shell"))
+	require.False(t, sess.ToolsApproved)
+
+	// Other sessions don't ask anymore
+	events = make(chan Event, 10)
+	rt.processToolCalls(t.Context(), session.New(session.WithUserMessage("Test")), calls, agentTools, events)
+	close(events)
+	for ev := range events {
+		_, ok := ev.(*ToolCallConfirmationEvent)
+		require.False(t, ok, "expected the tool to be approved without asking")
+	}
+	require.Equal(t, 2, executions)
+}
+
+func TestToolApprovals_ApproveToolForSession(t *testing.T) {
+	agentTools := []tools.Tool{{
+		Name:       "shell",
+		Parameters: map[string]any{},
+		Handler: func(_ context.Context, _ tools.ToolCall) (*tools.ToolCallResult, error) {
+			return tools.ResultSuccess("executed"), nil
+		},
+	}}
+
+	prov := &mockProvider{id: "test/mock-model", stream: &mockStream{}}
+	root := agent.New("root", "You are a test agent",
+		agent.WithModel(prov),
+		agent.WithToolSets(newStubToolSet(nil, agentTools, nil)),
+	)
+	tm := team.New(team.WithAgents(root))
+
+	approvals := fakeToolApprovals{}
+	rt, err := New(tm, WithSessionCompaction(false), WithModelStore(mockModelStore{}), WithToolApprovals(approvals))
+	require.NoError(t, err)
+
+	sess := session.New(session.WithUserMessage("Test"))
+	calls := []tools.ToolCall{{
+		ID:       "call_1",
+		Type:     "function",
+		Function: tools.FunctionCall{Name: "shell", Arguments: "{}"},
+	}}
+
+	events := make(chan Event, 10)
+	go func() {
+		rt.processToolCalls(t.Context(), sess, calls, agentTools, events)
+		close(events)
+	}()
+	for ev := range events {
+		if _, ok := ev.(*ToolCallConfirmationEvent); ok {
+			rt.resumeChan <- ResumeTypeApproveTool
+		}
+	}
+
+	require.Equal(t, session.PermissionModeAlwaysAllow, sess.Permissions.GetToolMode("shell"))
+	require.Empty(t, approvals)
+}
+
 func TestSessionPermissions_PerToolDisabled(t *testing.T) {
 	// Test that per-tool settings with Enabled: false rejects tools
 	var executed bool
@@ -1409,3 +1645,431 @@ func TestSessionPermissions_FallbackToPatternRules(t *testing.T) {
 
 	require.True(t, executed, "expected tool to fall through to pattern-based Allow rules")
 }
+
+func TestConfirmUntrusted_DisablesAutoApproval(t *testing.T) {
+	agentTools := []tools.Tool{{
+		Name:       "shell",
+		Parameters: map[string]any{},
+		Handler: func(_ context.Context, _ tools.ToolCall) (*tools.ToolCallResult, error) {
+			return tools.ResultSuccess("executed"), nil
+		},
+	}}
+
+	prov := &mockProvider{id: "test/mock-model", stream: &mockStream{}}
+	root := agent.New("root", "You are a test agent",
+		agent.WithModel(prov),
+		agent.WithToolSets(newStubToolSet(nil, agentTools, nil)),
+		agent.WithConfirmUntrusted(true),
+	)
+	tm := team.New(team.WithAgents(root))
+
+	rt, err := New(tm, WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+
+	sess := session.New(
+		session.WithUserMessage("Test"),
+		session.WithToolsApproved(true),
+	)
+	sess.UntrustedContent = true
+
+	calls := []tools.ToolCall{{
+		ID:       "call_1",
+		Type:     "function",
+		Function: tools.FunctionCall{Name: "shell", Arguments: "{}"},
+	}}
+
+	events := make(chan Event, 10)
+	go func() {
+		rt.processToolCalls(t.Context(), sess, calls, agentTools, events)
+		close(events)
+	}()
+
+	var gotConfirmation bool
+	for ev := range events {
+		if _, ok := ev.(*ToolCallConfirmationEvent); ok {
+			gotConfirmation = true
+			rt.resumeChan <- ResumeTypeReject
+			break
+		}
+	}
+
+	require.True(t, gotConfirmation, "expected yolo to be ignored once untrusted content is in the conversation")
+}
+
+func TestTodos_PersistedAndRestored(t *testing.T) {
+	todoTool := builtin.NewTodoTool()
+	agentTools, err := todoTool.Tools(t.Context())
+	require.NoError(t, err)
+
+	prov := &mockProvider{id: "test/mock-model", stream: &mockStream{}}
+	root := agent.New("root", "You are a test agent",
+		agent.WithModel(prov),
+		agent.WithToolSets(todoTool),
+	)
+	tm := team.New(team.WithAgents(root))
+
+	rt, err := New(tm, WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+
+	sess := session.New(
+		session.WithUserMessage("Test"),
+		session.WithToolsApproved(true),
+	)
+
+	calls := []tools.ToolCall{{
+		ID:       "call_1",
+		Type:     "function",
+		Function: tools.FunctionCall{Name: builtin.ToolNameCreateTodo, Arguments: `{"description":"Write tests"}`},
+	}}
+
+	events := make(chan Event, 10)
+	rt.processToolCalls(t.Context(), sess, calls, agentTools, events)
+
+	require.Len(t, sess.Todos, 1)
+	require.Equal(t, "Write tests", sess.Todos[0].Description)
+
+	// A fresh todo tool gets the session's todos back when the session is resumed
+	todoTool.SetTodos(nil)
+	rt.restoreTodos(root, sess)
+	require.Equal(t, sess.Todos, todoTool.Todos())
+
+	// Todos are only restored once per session
+	todoTool.SetTodos(nil)
+	rt.restoreTodos(root, sess)
+	require.Empty(t, todoTool.Todos())
+}
+
+func TestRunStream_RecordsCheckpoints(t *testing.T) {
+	t.Parallel()
+
+	prov := &queueProvider{id: "test/mock-model", streams: []chat.MessageStream{
+		newStreamBuilder().AddContent("Hello").AddStopWithUsage(30, 20).Build(),
+		newStreamBuilder().AddContent("Bye").AddStopWithUsage(60, 10).Build(),
+	}}
+	root := agent.New("root", "You are a test agent", agent.WithModel(prov))
+	rt, err := New(team.New(team.WithAgents(root)), WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+
+	sess := session.New(session.WithUserMessage("Hi"), session.WithTitle("Unit Test"), session.WithToolsApproved(true))
+	for range rt.RunStream(t.Context(), sess) {
+	}
+	sess.AddMessage(session.UserMessage("Goodbye"))
+	for range rt.RunStream(t.Context(), sess) {
+	}
+
+	require.Len(t, sess.Checkpoints, 2)
+	require.Equal(t, "Goodbye", sess.Checkpoints[1].Prompt)
+	require.Equal(t, int64(30), sess.Checkpoints[1].InputTokens)
+
+	fork, err := sess.Fork(1)
+	require.NoError(t, err)
+	require.Equal(t, "Hello", fork.GetLastAssistantMessageContent())
+	require.Equal(t, int64(50), fork.InputTokens+fork.OutputTokens)
+}
+
+func TestRunStream_NestsSubAgentEvents(t *testing.T) {
+	t.Parallel()
+
+	prov := &queueProvider{id: "test/mock-model", streams: []chat.MessageStream{
+		newStreamBuilder().
+			AddToolCallName("call_1", builtin.ToolNameTransferTask).
+			AddToolCallArguments("call_1", `{"agent":"researcher","task":"Find sources","expected_output":"Links"}`).
+			Build(),
+		newStreamBuilder().AddContent("Found them").AddStopWithUsage(10, 5).Build(),
+		newStreamBuilder().AddContent("Done").AddStopWithUsage(20, 5).Build(),
+	}}
+	researcher := agent.New("researcher", "You research", agent.WithModel(prov))
+	root := agent.New("root", "You lead", agent.WithModel(prov), agent.WithSubAgents(researcher), agent.WithToolSets(builtin.NewTransferTaskTool()))
+	rt, err := New(team.New(team.WithAgents(root, researcher)), WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+
+	sess := session.New(session.WithUserMessage("Hi"), session.WithTitle("Unit Test"), session.WithToolsApproved(true))
+	depths := map[string]int{}
+	for ev := range rt.RunStream(t.Context(), sess) {
+		if e, ok := ev.(*AgentChoiceEvent); ok {
+			depths[e.Content] = EventDepth(e)
+		}
+	}
+
+	require.Equal(t, map[string]int{"Found them": 1, "Done": 0}, depths)
+}
+
+func TestRunStream_HandoffWritesWorkingMemory(t *testing.T) {
+	t.Parallel()
+
+	prov := &queueProvider{id: "test/mock-model", streams: []chat.MessageStream{
+		newStreamBuilder().
+			AddToolCallName("call_1", builtin.ToolNameHandoff).
+			AddToolCallArguments("call_1", `{"agent":"billing","notes":"Customer 42 was charged twice in May"}`).
+			Build(),
+		newStreamBuilder().AddContent("Refunded").AddStopWithUsage(10, 5).Build(),
+	}}
+	billing := agent.New("billing", "You handle invoices", agent.WithModel(prov))
+	root := agent.New("root", "You route", agent.WithModel(prov), agent.WithHandoffs(billing), agent.WithToolSets(builtin.NewHandoffTool()))
+	rt, err := New(team.New(team.WithAgents(root, billing)), WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+
+	sess := session.New(session.WithUserMessage("I was charged twice"), session.WithTitle("Unit Test"), session.WithToolsApproved(true))
+	for range rt.RunStream(t.Context(), sess) {
+	}
+
+	require.Equal(t, "billing", rt.CurrentAgentName())
+	require.Len(t, sess.WorkingMemory, 1)
+	require.Equal(t, "root", sess.WorkingMemory[0].From)
+	require.Equal(t, "billing", sess.WorkingMemory[0].To)
+	require.Equal(t, "Customer 42 was charged twice in May", sess.WorkingMemory[0].Content)
+}
+
+type staticPolicyEngine struct {
+	decision policy.Decision
+	inputs   []policy.Input
+}
+
+func (e *staticPolicyEngine) Evaluate(_ context.Context, input policy.Input) (policy.Decision, error) {
+	e.inputs = append(e.inputs, input)
+	return e.decision, nil
+}
+
+func TestPolicyEngine_Decisions(t *testing.T) {
+	var executed bool
+	agentTools := []tools.Tool{{
+		Name:       "fetch",
+		Parameters: map[string]any{},
+		Handler: func(_ context.Context, _ tools.ToolCall) (*tools.ToolCallResult, error) {
+			executed = true
+			return tools.ResultSuccess("fetched"), nil
+		},
+	}}
+	calls := []tools.ToolCall{{
+		ID:       "call_1",
+		Type:     "function",
+		Function: tools.FunctionCall{Name: "fetch", Arguments: `{"urls":["https://evil.example.com/x"]}`},
+	}}
+
+	newRuntime := func(t *testing.T, engine policy.Engine) *LocalRuntime {
+		t.Helper()
+
+		prov := &mockProvider{id: "test/mock-model", stream: &mockStream{}}
+		root := agent.New("root", "You are a test agent",
+			agent.WithModel(prov),
+			agent.WithToolSets(newStubToolSet(nil, agentTools, nil)),
+		)
+		rt, err := New(team.New(team.WithAgents(root)), WithSessionCompaction(false), WithModelStore(mockModelStore{}), WithPolicyEngine(engine))
+		require.NoError(t, err)
+		return rt
+	}
+
+	t.Run("deny", func(t *testing.T) {
+		executed = false
+		engine := &staticPolicyEngine{decision: policy.Decision{Action: policy.Deny, Reason: "host not allowed"}}
+		rt := newRuntime(t, engine)
+		sess := session.New(session.WithUserMessage("Test"), session.WithToolsApproved(true))
+
+		rt.processToolCalls(t.Context(), sess, calls, agentTools, make(chan Event, 10))
+
+		require.False(t, executed)
+		require.Len(t, engine.inputs, 1)
+		require.Equal(t, "root", engine.inputs[0].Agent.Name)
+		require.Equal(t, []string{"evil.example.com"}, engine.inputs[0].Egress.Hosts)
+		require.True(t, engine.inputs[0].Session.ToolsApproved)
+
+		messages := sess.GetAllMessages()
+		require.Contains(t, messages[len(messages)-1].Message.Content, "denied by policy: host not allowed")
+	})
+
+	t.Run("ask overrides yolo", func(t *testing.T) {
+		executed = false
+		rt := newRuntime(t, &staticPolicyEngine{decision: policy.Decision{Action: policy.Ask}})
+		sess := session.New(session.WithUserMessage("Test"), session.WithToolsApproved(true))
+
+		events := make(chan Event, 10)
+		go func() {
+			rt.processToolCalls(t.Context(), sess, calls, agentTools, events)
+			close(events)
+		}()
+
+		var gotConfirmation bool
+		for ev := range events {
+			if _, ok := ev.(*ToolCallConfirmationEvent); ok {
+				gotConfirmation = true
+				rt.resumeChan <- ResumeTypeReject
+			}
+		}
+
+		require.True(t, gotConfirmation)
+		require.False(t, executed)
+	})
+
+	t.Run("allow", func(t *testing.T) {
+		executed = false
+		rt := newRuntime(t, &staticPolicyEngine{decision: policy.Decision{Action: policy.Allow}})
+		sess := session.New(session.WithUserMessage("Test"))
+
+		rt.processToolCalls(t.Context(), sess, calls, agentTools, make(chan Event, 10))
+
+		require.True(t, executed)
+	})
+}
+
+type auditLog struct {
+	events []audit.Event
+}
+
+func (l *auditLog) Record(event audit.Event) {
+	l.events = append(l.events, event)
+}
+
+func (l *auditLog) types() []audit.EventType {
+	var types []audit.EventType
+	for _, event := range l.events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+func TestAuditRecorder(t *testing.T) {
+	agentTools := []tools.Tool{{
+		Name:       "fetch",
+		Parameters: map[string]any{},
+		Handler: func(_ context.Context, _ tools.ToolCall) (*tools.ToolCallResult, error) {
+			return &tools.ToolCallResult{Output: "fetched", Untrusted: true}, nil
+		},
+	}}
+	calls := []tools.ToolCall{{
+		ID:       "call_1",
+		Type:     "function",
+		Function: tools.FunctionCall{Name: "fetch", Arguments: `{"urls":["https://example.com"]}`},
+	}}
+
+	newRuntime := func(t *testing.T, log *auditLog, opts ...Opt) *LocalRuntime {
+		t.Helper()
+
+		prov := &mockProvider{id: "test/mock-model", stream: &mockStream{}}
+		root := agent.New("root", "You are a test agent",
+			agent.WithModel(prov),
+			agent.WithToolSets(newStubToolSet(nil, agentTools, nil)),
+		)
+		opts = append(opts, WithSessionCompaction(false), WithModelStore(mockModelStore{}), WithAuditRecorder(log))
+		rt, err := New(team.New(team.WithAgents(root)), opts...)
+		require.NoError(t, err)
+		return rt
+	}
+
+	t.Run("auto-approved", func(t *testing.T) {
+		log := &auditLog{}
+		rt := newRuntime(t, log)
+		sess := session.New(session.WithUserMessage("Test"), session.WithToolsApproved(true))
+
+		rt.processToolCalls(t.Context(), sess, calls, agentTools, make(chan Event, 10))
+
+		require.Equal(t, []audit.EventType{audit.ToolCallApproved, audit.ToolCallExecuted, audit.UntrustedContent}, log.types())
+		approved := log.events[0]
+		require.Equal(t, "yolo or read-only", approved.Source)
+		require.Equal(t, sess.ID, approved.SessionID)
+		require.Equal(t, "root", approved.Agent)
+		require.Equal(t, "fetch", approved.Tool)
+		require.Equal(t, "call_1", approved.ToolCallID)
+		require.JSONEq(t, `{"urls":["https://example.com"]}`, approved.Arguments)
+	})
+
+	t.Run("denied by policy", func(t *testing.T) {
+		log := &auditLog{}
+		engine := &staticPolicyEngine{decision: policy.Decision{Action: policy.Deny, Reason: "no egress"}}
+		rt := newRuntime(t, log, WithPolicyEngine(engine))
+		sess := session.New(session.WithUserMessage("Test"))
+
+		rt.processToolCalls(t.Context(), sess, calls, agentTools, make(chan Event, 10))
+
+		require.Equal(t, []audit.EventType{audit.PolicyDecision, audit.ToolCallDenied}, log.types())
+		require.Equal(t, "deny", log.events[0].Decision)
+		require.Equal(t, "policy", log.events[1].Source)
+		require.Equal(t, "no egress", log.events[1].Reason)
+	})
+
+	t.Run("rejected by user", func(t *testing.T) {
+		log := &auditLog{}
+		rt := newRuntime(t, log)
+		sess := session.New(session.WithUserMessage("Test"))
+
+		events := make(chan Event, 10)
+		go func() {
+			rt.processToolCalls(t.Context(), sess, calls, agentTools, events)
+			close(events)
+		}()
+		for ev := range events {
+			if _, ok := ev.(*ToolCallConfirmationEvent); ok {
+				rt.resumeChan <- ResumeTypeReject
+			}
+		}
+
+		require.Equal(t, []audit.EventType{audit.ToolCallRejected}, log.types())
+		require.Equal(t, "user", log.events[0].Source)
+	})
+}
+
+func TestProcessToolCalls_Parallel(t *testing.T) {
+	t.Parallel()
+
+	// Both handlers must be running for either of them to return, and the
+	// second one returns first
+	var running atomic.Int32
+	waitForBoth := func() error {
+		running.Add(1)
+		for deadline := time.Now().Add(time.Second); running.Load() < 2; time.Sleep(time.Millisecond) {
+			if time.Now().After(deadline) {
+				return errors.New("the calls ran one after the other")
+			}
+		}
+		return nil
+	}
+	secondDone := make(chan struct{})
+	agentTools := []tools.Tool{{
+		Name:       "first",
+		Parameters: map[string]any{},
+		Handler: func(ctx context.Context, tc tools.ToolCall) (*tools.ToolCallResult, error) {
+			if err := waitForBoth(); err != nil {
+				return nil, err
+			}
+			<-secondDone
+			return tools.ResultSuccess("first result"), nil
+		},
+	}, {
+		Name:       "second",
+		Parameters: map[string]any{},
+		Handler: func(ctx context.Context, tc tools.ToolCall) (*tools.ToolCallResult, error) {
+			if err := waitForBoth(); err != nil {
+				return nil, err
+			}
+			close(secondDone)
+			return tools.ResultSuccess("second result"), nil
+		},
+	}}
+
+	prov := &mockProvider{id: "test/mock-model", stream: &mockStream{}}
+	root := agent.New("root", "You are a test agent",
+		agent.WithModel(prov),
+		agent.WithToolSets(newStubToolSet(nil, agentTools, nil)),
+		agent.WithMaxParallelToolCalls(2),
+	)
+	rt, err := New(team.New(team.WithAgents(root)), WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+
+	sess := session.New(session.WithUserMessage("Test"), session.WithToolsApproved(true))
+	calls := []tools.ToolCall{
+		{ID: "call_1", Type: "function", Function: tools.FunctionCall{Name: "first", Arguments: "{}"}},
+		{ID: "call_2", Type: "function", Function: tools.FunctionCall{Name: "second", Arguments: "{}"}},
+	}
+
+	events := make(chan Event, 10)
+	rt.processToolCalls(t.Context(), sess, calls, agentTools, events)
+	close(events)
+
+	// The results are in the order of the calls
+	var results []string
+	for _, msg := range sess.GetAllMessages() {
+		if msg.Message.Role == chat.MessageRoleTool {
+			results = append(results, msg.Message.ToolCallID+": "+msg.Message.Content)
+		}
+	}
+	require.Equal(t, []string{"call_1: first result", "call_2: second result"}, results)
+}
@@ -0,0 +1,221 @@
+package runtime
+
+import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/model/provider"
+	"github.com/docker/cagent/pkg/model/provider/options"
+	"github.com/docker/cagent/pkg/tools"
+	"github.com/docker/cagent/pkg/tools/builtin"
+)
+
+// maxSamplingPreviewLength caps the size of the prompt shown to the user when
+// a server requests a completion.
+const maxSamplingPreviewLength = 500
+
+// samplingHandler runs the completions requested by the MCP servers of an
+// agent with the model of the agent, once the user approved them.
+func (r *LocalRuntime) samplingHandler(a *agent.Agent) tools.SamplingHandler {
+	return func(ctx context.Context, server string, req *mcp.CreateMessageParams) (*mcp.CreateMessageResult, error) {
+		return r.sample(ctx, a, server, req)
+	}
+}
+
+func (r *LocalRuntime) sample(ctx context.Context, a *agent.Agent, server string, req *mcp.CreateMessageParams) (*mcp.CreateMessageResult, error) {
+	model := a.Model()
+	if model == nil {
+		return nil, fmt.Errorf("agent %s has no model", a.Name())
+	}
+	modelID := model.ID()
+
+	messages, err := samplingMessages(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// The server pays with the user's credits: every request is approved
+	approval, err := r.elicitationHandler(ctx, &mcp.ElicitParams{
+		Message: samplingApprovalMessage(server, modelID, req),
+		Meta: map[string]any{
+			"cagent/type":   "sampling",
+			"cagent/server": server,
+			"cagent/model":  modelID,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("asking the user to approve the sampling request: %w", err)
+	}
+	if approval.Action != tools.ElicitationActionAccept {
+		return nil, errors.New("the user declined the sampling request")
+	}
+
+	if req.MaxTokens > 0 {
+		model = provider.CloneWithOptions(ctx, model, options.WithMaxTokens(req.MaxTokens))
+	}
+
+	stream, err := r.createSamplingStream(ctx, a, model, messages)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	var content strings.Builder
+	var usage *chat.Usage
+	var actualModel string
+	stopReason := "endTurn"
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("receiving the completion: %w", err)
+		}
+		if resp.Usage != nil {
+			usage = resp.Usage
+		}
+		if resp.Model != "" {
+			actualModel = resp.Model
+		}
+		for _, choice := range resp.Choices {
+			content.WriteString(choice.Delta.Content)
+			if choice.FinishReason == chat.FinishReasonLength {
+				stopReason = "maxTokens"
+			}
+		}
+	}
+
+	slog.Debug("Ran sampling request", "server", server, "model", modelID, "usage", usage)
+	if usage != nil {
+		r.requests.recordTokens(modelID, usage.InputTokens+usage.OutputTokens)
+	}
+	r.emitSamplingUsage(ctx, a, server, modelID, usage)
+
+	return &mcp.CreateMessageResult{
+		Content:    &mcp.TextContent{Text: content.String()},
+		Model:      cmp.Or(actualModel, modelID),
+		Role:       "assistant",
+		StopReason: stopReason,
+	}, nil
+}
+
+// createSamplingStream sends a completion requested by a server to the model,
+// once there's a slot for it: these requests count towards the limits of the
+// provider, like the ones of the agents.
+func (r *LocalRuntime) createSamplingStream(ctx context.Context, a *agent.Agent, model provider.Provider, messages []chat.Message) (chat.MessageStream, error) {
+	throttled := false
+	release, err := r.requests.acquire(ctx, model.ID(), a.Name(), a.Priority()*taskPriorityWeights[builtin.TaskPriorityNormal], func(wait time.Duration) {
+		throttled = true
+		r.emitBackgroundEvent(RequestThrottled(model.ID(), true, wait, a.Name()))
+	})
+	if throttled {
+		r.emitBackgroundEvent(RequestThrottled(model.ID(), false, 0, a.Name()))
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	stream, err := model.CreateChatCompletionStream(ctx, messages, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating chat completion: %w", err)
+	}
+	return stream, nil
+}
+
+// samplingMessages converts the messages of a sampling request to the
+// messages sent to the model.
+func samplingMessages(req *mcp.CreateMessageParams) ([]chat.Message, error) {
+	if len(req.Messages) == 0 {
+		return nil, errors.New("the sampling request has no messages")
+	}
+
+	var messages []chat.Message
+	if req.SystemPrompt != "" {
+		messages = append(messages, chat.Message{Role: chat.MessageRoleSystem, Content: req.SystemPrompt})
+	}
+	for _, msg := range req.Messages {
+		role := chat.MessageRoleUser
+		if msg.Role == "assistant" {
+			role = chat.MessageRoleAssistant
+		}
+
+		switch content := msg.Content.(type) {
+		case *mcp.TextContent:
+			messages = append(messages, chat.Message{Role: role, Content: content.Text})
+		case *mcp.ImageContent:
+			messages = append(messages, chat.Message{
+				Role: role,
+				MultiContent: []chat.MessagePart{{
+					Type: chat.MessagePartTypeImageURL,
+					ImageURL: &chat.MessageImageURL{
+						URL:    "data:" + content.MIMEType + ";base64," + base64.StdEncoding.EncodeToString(content.Data),
+						Detail: chat.ImageURLDetailAuto,
+					},
+				}},
+			})
+		default:
+			return nil, fmt.Errorf("unsupported content %T in the sampling request", msg.Content)
+		}
+	}
+	return messages, nil
+}
+
+// samplingApprovalMessage is the message asking the user to approve a
+// sampling request. It shows the last message of the request, which is
+// usually the prompt.
+func samplingApprovalMessage(server, modelID string, req *mcp.CreateMessageParams) string {
+	var prompt string
+	if len(req.Messages) > 0 {
+		if text, ok := req.Messages[len(req.Messages)-1].Content.(*mcp.TextContent); ok {
+			prompt = text.Text
+		}
+	}
+	if len(prompt) > maxSamplingPreviewLength {
+		prompt = truncateUTF8(prompt, maxSamplingPreviewLength) + "…"
+	}
+
+	msg := fmt.Sprintf("The MCP server %q requests a completion from %s", server, modelID)
+	if req.MaxTokens > 0 {
+		msg += fmt.Sprintf(", of up to %d tokens", req.MaxTokens)
+	}
+	if prompt == "" {
+		return msg + "."
+	}
+	return msg + ":\n\n" + prompt
+}
+
+// emitSamplingUsage sends the usage of a completion requested by a server to
+// the client of the current run, so that it's attributed to the server.
+func (r *LocalRuntime) emitSamplingUsage(ctx context.Context, a *agent.Agent, server, modelID string, usage *chat.Usage) {
+	if usage == nil {
+		return
+	}
+
+	var cost float64
+	m, err := r.modelsStore.GetModel(ctx, modelID)
+	if err != nil {
+		slog.Debug("Failed to get model definition", "error", err)
+	}
+	if m != nil && m.Cost != nil {
+		cost = (float64(usage.InputTokens)*m.Cost.Input +
+			float64(usage.OutputTokens)*m.Cost.Output +
+			float64(usage.CachedInputTokens)*m.Cost.CacheRead +
+			float64(usage.CacheWriteTokens)*m.Cost.CacheWrite) / 1e6
+	}
+
+	inputTokens := usage.InputTokens + usage.CachedInputTokens + usage.CacheWriteTokens
+	r.emitBackgroundEvent(SamplingUsage(server, modelID, inputTokens, usage.OutputTokens, cost, a.Name()))
+}
@@ -0,0 +1,137 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/team"
+	"github.com/docker/cagent/pkg/tools"
+)
+
+// samplingRuntime returns a runtime whose agent answers with stream, and the
+// events channel of its current run.
+func samplingRuntime(t *testing.T, stream *mockStream) (*LocalRuntime, *agent.Agent, chan Event) {
+	t.Helper()
+
+	prov := &queueProvider{id: "test/mock-model", streams: []chat.MessageStream{stream}}
+	root := agent.New("root", "You are a test agent", agent.WithModel(prov))
+	rt, err := New(team.New(team.WithAgents(root)), WithSessionCompaction(false), WithModelStore(mockModelStoreWithCost{}))
+	require.NoError(t, err)
+
+	events := make(chan Event, 10)
+	rt.setElicitationEventsChannel(events)
+	return rt, root, events
+}
+
+// answerSampling answers the approval request of a sampling request.
+func answerSampling(t *testing.T, rt *LocalRuntime, events chan Event, action tools.ElicitationAction) {
+	t.Helper()
+
+	go func() {
+		event := <-events
+		request, ok := event.(*ElicitationRequestEvent)
+		if !assert.True(t, ok, "expected an elicitation request, got %T", event) {
+			return
+		}
+		assert.Equal(t, "sampling", request.Meta["cagent/type"])
+		assert.Equal(t, "docs", request.Meta["cagent/server"])
+		assert.Contains(t, request.Message, "Summarize the changelog")
+		assert.NoError(t, rt.ResumeElicitation(context.Background(), action, nil))
+	}()
+}
+
+func samplingRequest() *mcp.CreateMessageParams {
+	return &mcp.CreateMessageParams{
+		SystemPrompt: "You summarize documents.",
+		MaxTokens:    100,
+		Messages: []*mcp.SamplingMessage{
+			{Role: "user", Content: &mcp.TextContent{Text: "Summarize the changelog"}},
+		},
+	}
+}
+
+func TestSampling_Approved(t *testing.T) {
+	t.Parallel()
+
+	stream := newStreamBuilder().
+		AddContent("Bug fixes.").
+		AddStopWithUsage(1000, 100).
+		Build()
+	rt, root, events := samplingRuntime(t, stream)
+	answerSampling(t, rt, events, tools.ElicitationActionAccept)
+
+	result, err := rt.samplingHandler(root)(t.Context(), "docs", samplingRequest())
+	require.NoError(t, err)
+	assert.Equal(t, &mcp.TextContent{Text: "Bug fixes."}, result.Content)
+	assert.Equal(t, "test/mock-model", result.Model)
+	assert.Equal(t, mcp.Role("assistant"), result.Role)
+
+	usage, ok := (<-events).(*SamplingUsageEvent)
+	require.True(t, ok)
+	assert.Equal(t, "docs", usage.Server)
+	assert.Equal(t, "root", usage.AgentName)
+	assert.Equal(t, int64(1000), usage.InputTokens)
+	assert.Equal(t, int64(100), usage.OutputTokens)
+	assert.InDelta(t, 0.0045, usage.Cost, 1e-9)
+}
+
+func TestSampling_RecordsTokens(t *testing.T) {
+	t.Parallel()
+
+	stream := newStreamBuilder().
+		AddContent("Bug fixes.").
+		AddStopWithUsage(1000, 100).
+		Build()
+	rt, root, events := samplingRuntime(t, stream)
+	rt.requests = newRequestScheduler()
+	rt.requests.setRateLimits(map[string]team.RateLimit{"test": {TokensPerMinute: 10000}})
+	answerSampling(t, rt, events, tools.ElicitationActionAccept)
+
+	_, err := rt.samplingHandler(root)(t.Context(), "docs", samplingRequest())
+	require.NoError(t, err)
+
+	// The completions requested by servers count towards the rate limits of
+	// the provider
+	rt.requests.mu.Lock()
+	defer rt.requests.mu.Unlock()
+	require.Len(t, rt.requests.queues["test"].tokens, 1)
+	assert.Equal(t, int64(1100), rt.requests.queues["test"].tokens[0].tokens)
+}
+
+func TestSampling_Declined(t *testing.T) {
+	t.Parallel()
+
+	rt, root, events := samplingRuntime(t, newStreamBuilder().AddContent("unused").Build())
+	answerSampling(t, rt, events, tools.ElicitationActionDecline)
+
+	_, err := rt.samplingHandler(root)(t.Context(), "docs", samplingRequest())
+	require.ErrorContains(t, err, "declined")
+}
+
+func TestSamplingMessages(t *testing.T) {
+	t.Parallel()
+
+	messages, err := samplingMessages(&mcp.CreateMessageParams{
+		SystemPrompt: "Be brief.",
+		Messages: []*mcp.SamplingMessage{
+			{Role: "user", Content: &mcp.TextContent{Text: "What's in this image?"}},
+			{Role: "user", Content: &mcp.ImageContent{MIMEType: "image/png", Data: []byte("png")}},
+			{Role: "assistant", Content: &mcp.TextContent{Text: "A cat."}},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, messages, 4)
+	assert.Equal(t, chat.Message{Role: chat.MessageRoleSystem, Content: "Be brief."}, messages[0])
+	assert.Equal(t, chat.MessageRoleUser, messages[1].Role)
+	assert.Equal(t, "data:image/png;base64,cG5n", messages[2].MultiContent[0].ImageURL.URL)
+	assert.Equal(t, chat.Message{Role: chat.MessageRoleAssistant, Content: "A cat."}, messages[3])
+
+	_, err = samplingMessages(&mcp.CreateMessageParams{})
+	require.Error(t, err)
+}
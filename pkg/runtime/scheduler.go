@@ -0,0 +1,309 @@
+package runtime
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/team"
+	"github.com/docker/cagent/pkg/tools/builtin"
+)
+
+// rateWindow is the window the requests and tokens per minute of the
+// providers are counted over.
+const rateWindow = time.Minute
+
+// requests schedules the requests of all the runtimes of the process, so
+// that the sessions sharing a provider key share its limit too.
+var requests = newRequestScheduler()
+
+// taskPriorityWeights multiply the priority of the agents by the priority of
+// the task they run.
+var taskPriorityWeights = map[string]int{
+	builtin.TaskPriorityBackground: 1,
+	builtin.TaskPriorityNormal:     2,
+	builtin.TaskPriorityCritical:   4,
+}
+
+// requestPriority returns the share of the requests to a rate-limited
+// provider an agent gets while running a session.
+func requestPriority(a *agent.Agent, sess *session.Session) int {
+	return a.Priority() * taskPriorityWeights[cmp.Or(sess.Priority, builtin.TaskPriorityNormal)]
+}
+
+// requestScheduler caps the number of concurrent requests to each provider,
+// and the requests and tokens per minute. When a limit is reached, the
+// waiting requests are granted in turn to each agent, in proportion to its
+// priority, so a chatty sub-agent can't starve the others.
+type requestScheduler struct {
+	mu         sync.Mutex
+	limits     map[string]int
+	rateLimits map[string]team.RateLimit
+	queues     map[string]*providerQueue
+	now        func() time.Time
+}
+
+// providerQueue are the requests to a provider, running and waiting.
+type providerQueue struct {
+	running int
+	waiting []*waitingRequest
+	// pass is, by agent, the virtual time its next request is due at. Each
+	// granted request moves it forward by the inverse of the agent's priority.
+	pass map[string]float64
+	// now is the pass of the last granted request
+	now float64
+
+	// sent are the times of the requests granted in the last minute, tokens
+	// the tokens they used
+	sent   []time.Time
+	tokens []tokenUse
+	// wakeup re-dispatches the requests once the rate limit allows them
+	wakeup *time.Timer
+}
+
+type tokenUse struct {
+	at     time.Time
+	tokens int64
+}
+
+type waitingRequest struct {
+	agent    string
+	priority int
+	granted  chan struct{}
+	// throttled receives how long the request waits at least, once it waits
+	// for the rate limit
+	throttled chan time.Duration
+	notified  bool
+}
+
+func newRequestScheduler() *requestScheduler {
+	return &requestScheduler{
+		limits:     map[string]int{},
+		rateLimits: map[string]team.RateLimit{},
+		queues:     map[string]*providerQueue{},
+		now:        time.Now,
+	}
+}
+
+// setLimits sets the maximum number of concurrent requests of providers.
+func (s *requestScheduler) setLimits(limits map[string]int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for provider, limit := range limits {
+		s.limits[provider] = limit
+		if queue, ok := s.queues[provider]; ok {
+			s.dispatch(provider, queue)
+		}
+	}
+}
+
+// setRateLimits sets the requests and tokens per minute of providers.
+func (s *requestScheduler) setRateLimits(limits map[string]team.RateLimit) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for provider, limit := range limits {
+		s.rateLimits[provider] = limit
+		if queue, ok := s.queues[provider]; ok {
+			s.dispatch(provider, queue)
+		}
+	}
+}
+
+// limited reports whether the requests to a provider are limited. Must be
+// called with the lock held.
+func (s *requestScheduler) limited(provider string) bool {
+	return s.limits[provider] > 0 || s.rateLimits[provider] != (team.RateLimit{})
+}
+
+// acquire waits until a request of the agent to the provider of the model
+// may be sent. The returned function must be called once the response is
+// read. Requests to providers without a limit don't wait. throttled, when
+// set, is called if the request waits for the rate limit of the provider.
+func (s *requestScheduler) acquire(ctx context.Context, modelID, agentName string, priority int, throttled func(wait time.Duration)) (release func(), err error) {
+	provider, _, _ := strings.Cut(modelID, "/")
+
+	s.mu.Lock()
+	if !s.limited(provider) {
+		s.mu.Unlock()
+		return func() {}, nil
+	}
+
+	queue := s.queue(provider)
+	request := &waitingRequest{agent: agentName, priority: max(1, priority), granted: make(chan struct{}), throttled: make(chan time.Duration, 1)}
+	queue.waiting = append(queue.waiting, request)
+	s.dispatch(provider, queue)
+	s.mu.Unlock()
+
+	release = sync.OnceFunc(func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		queue.running--
+		s.dispatch(provider, queue)
+	})
+
+	select {
+	case <-request.granted:
+		return release, nil
+	default:
+	}
+
+	slog.Debug("Waiting for a request slot", "provider", provider, "agent", agentName)
+	for {
+		select {
+		case <-request.granted:
+			return release, nil
+		case wait := <-request.throttled:
+			if throttled != nil {
+				throttled(wait)
+			}
+		case <-ctx.Done():
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			select {
+			case <-request.granted:
+				// Granted while cancelled: give the slot to the next request
+				queue.running--
+				s.dispatch(provider, queue)
+			default:
+				queue.remove(request)
+			}
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// recordTokens counts the tokens a request to the provider of the model used
+// against its tokens per minute.
+func (s *requestScheduler) recordTokens(modelID string, tokens int64) {
+	provider, _, _ := strings.Cut(modelID, "/")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.rateLimits[provider].TokensPerMinute <= 0 || tokens <= 0 {
+		return
+	}
+	queue := s.queue(provider)
+	queue.tokens = append(queue.tokens, tokenUse{at: s.now(), tokens: tokens})
+}
+
+// queue returns the queue of the requests to a provider. Must be called with
+// the lock held.
+func (s *requestScheduler) queue(provider string) *providerQueue {
+	queue, ok := s.queues[provider]
+	if !ok {
+		queue = &providerQueue{pass: map[string]float64{}}
+		s.queues[provider] = queue
+	}
+	return queue
+}
+
+// wake dispatches the requests that waited for the rate limit of a provider.
+func (s *requestScheduler) wake(provider string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if queue, ok := s.queues[provider]; ok {
+		queue.wakeup = nil
+		s.dispatch(provider, queue)
+	}
+}
+
+// dispatch grants waiting requests while the provider is under its limits,
+// the request of the agent most behind on its share first. Must be called
+// with the lock held.
+func (s *requestScheduler) dispatch(provider string, queue *providerQueue) {
+	limit := s.limits[provider]
+	for (limit <= 0 || queue.running < limit) && len(queue.waiting) > 0 {
+		now := s.now()
+		if wait := queue.rateWait(s.rateLimits[provider], now); wait > 0 {
+			s.throttle(provider, queue, wait)
+			return
+		}
+
+		next := 0
+		for i, request := range queue.waiting {
+			if queue.passOf(request.agent) < queue.passOf(queue.waiting[next].agent) {
+				next = i
+			}
+		}
+
+		request := queue.waiting[next]
+		queue.waiting = append(queue.waiting[:next], queue.waiting[next+1:]...)
+		queue.running++
+		queue.sent = append(queue.sent, now)
+		queue.now = queue.passOf(request.agent)
+		queue.pass[request.agent] = queue.now + 1/float64(request.priority)
+		close(request.granted)
+	}
+}
+
+// throttle holds the waiting requests until the rate limit of the provider
+// allows the next one. Must be called with the lock held.
+func (s *requestScheduler) throttle(provider string, queue *providerQueue, wait time.Duration) {
+	if queue.wakeup == nil {
+		slog.Debug("Requests throttled by the rate limit of the provider", "provider", provider, "wait", wait)
+		queue.wakeup = time.AfterFunc(wait, func() { s.wake(provider) })
+	}
+	for _, request := range queue.waiting {
+		if !request.notified {
+			request.notified = true
+			request.throttled <- wait
+		}
+	}
+}
+
+// rateWait returns how long the next request must wait for the rate limit,
+// forgetting the requests and tokens older than a minute.
+func (q *providerQueue) rateWait(limit team.RateLimit, now time.Time) time.Duration {
+	since := now.Add(-rateWindow)
+	for len(q.sent) > 0 && !q.sent[0].After(since) {
+		q.sent = q.sent[1:]
+	}
+	for len(q.tokens) > 0 && !q.tokens[0].at.After(since) {
+		q.tokens = q.tokens[1:]
+	}
+
+	var wait time.Duration
+	if limit.RequestsPerMinute > 0 && len(q.sent) >= limit.RequestsPerMinute {
+		// Until enough of the requests of the last minute are old enough
+		wait = q.sent[len(q.sent)-limit.RequestsPerMinute].Sub(since)
+	}
+	if limit.TokensPerMinute > 0 {
+		var used int64
+		for _, use := range q.tokens {
+			used += use.tokens
+		}
+		// Until the tokens of the last minute are under the limit
+		for _, use := range q.tokens {
+			if used < int64(limit.TokensPerMinute) {
+				break
+			}
+			used -= use.tokens
+			wait = max(wait, use.at.Sub(since))
+		}
+	}
+	return wait
+}
+
+// passOf returns the pass of an agent. An agent that was idle doesn't get to
+// catch up on the requests it didn't send.
+func (q *providerQueue) passOf(agent string) float64 {
+	return max(q.pass[agent], q.now)
+}
+
+func (q *providerQueue) remove(request *waitingRequest) {
+	for i, waiting := range q.waiting {
+		if waiting == request {
+			q.waiting = append(q.waiting[:i], q.waiting[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/team"
+)
+
+func TestRequestScheduler_Unlimited(t *testing.T) {
+	t.Parallel()
+
+	s := newRequestScheduler()
+	s.setLimits(map[string]int{"anthropic": 1})
+
+	for range 3 {
+		release, err := s.acquire(t.Context(), "openai/gpt-4o", "root", 1, nil)
+		require.NoError(t, err)
+		defer release()
+	}
+}
+
+func TestRequestScheduler_Limit(t *testing.T) {
+	t.Parallel()
+
+	s := newRequestScheduler()
+	s.setLimits(map[string]int{"openai": 1})
+
+	release, err := s.acquire(t.Context(), "openai/gpt-4o", "root", 1, nil)
+	require.NoError(t, err)
+
+	granted := make(chan struct{})
+	go func() {
+		release, err := s.acquire(t.Context(), "openai/gpt-4o-mini", "helper", 1, nil)
+		assert.NoError(t, err)
+		close(granted)
+		release()
+	}()
+
+	select {
+	case <-granted:
+		t.Fatal("the limit is exceeded")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	release()
+	// Releasing twice doesn't free another slot
+	release()
+	<-granted
+}
+
+func TestRequestScheduler_Cancel(t *testing.T) {
+	t.Parallel()
+
+	s := newRequestScheduler()
+	s.setLimits(map[string]int{"openai": 1})
+
+	release, err := s.acquire(t.Context(), "openai/gpt-4o", "root", 1, nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+	_, err = s.acquire(ctx, "openai/gpt-4o", "helper", 1, nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// The cancelled request doesn't hold a slot
+	release()
+	release, err = s.acquire(t.Context(), "openai/gpt-4o", "root", 1, nil)
+	require.NoError(t, err)
+	release()
+}
+
+func TestRequestScheduler_RateLimit(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := newRequestScheduler()
+	s.now = func() time.Time { return now }
+	s.setRateLimits(map[string]team.RateLimit{"openai": {RequestsPerMinute: 2, TokensPerMinute: 1000}})
+
+	for range 2 {
+		release, err := s.acquire(t.Context(), "openai/gpt-4o", "root", 1, nil)
+		require.NoError(t, err)
+		release()
+	}
+
+	// The third request of the minute waits until the first one is a minute old
+	now = now.Add(20 * time.Second)
+	throttled := make(chan time.Duration, 1)
+	granted := make(chan struct{})
+	go func() {
+		release, err := s.acquire(t.Context(), "openai/gpt-4o", "helper", 1, func(wait time.Duration) { throttled <- wait })
+		assert.NoError(t, err)
+		release()
+		close(granted)
+	}()
+	assert.Equal(t, 40*time.Second, <-throttled)
+
+	s.mu.Lock()
+	now = now.Add(40 * time.Second)
+	s.mu.Unlock()
+	s.wake("openai")
+	<-granted
+
+	// Once the tokens of the last minute reach the limit, the requests wait
+	// until they are a minute old
+	s.recordTokens("openai/gpt-4o", 600)
+	now = now.Add(30 * time.Second)
+	s.recordTokens("openai/gpt-4o", 600)
+	s.mu.Lock()
+	wait := s.queues["openai"].rateWait(s.rateLimits["openai"], now)
+	s.mu.Unlock()
+	assert.Equal(t, 30*time.Second, wait)
+
+	// Other providers aren't limited
+	release, err := s.acquire(t.Context(), "anthropic/claude-sonnet-4-0", "root", 1, func(time.Duration) { t.Error("throttled") })
+	require.NoError(t, err)
+	release()
+}
+
+// TestRequestScheduler_Fairness queues requests of a chatty sub-agent before
+// the ones of the orchestrator, and checks the order they are granted in.
+func TestRequestScheduler_Fairness(t *testing.T) {
+	t.Parallel()
+
+	s := newRequestScheduler()
+	s.setLimits(map[string]int{"openai": 1})
+
+	release, err := s.acquire(t.Context(), "openai/gpt-4o", "blocker", 1, nil)
+	require.NoError(t, err)
+
+	order := make(chan string, 9)
+	queued := 0
+	queue := func(agent string, priority int) {
+		go func() {
+			release, err := s.acquire(t.Context(), "openai/gpt-4o", agent, priority, nil)
+			if !assert.NoError(t, err) {
+				return
+			}
+			order <- agent
+			release()
+		}()
+		queued++
+		waitForWaiting(t, s, "openai", queued)
+	}
+
+	for range 6 {
+		queue("chatty", 1)
+	}
+	for range 3 {
+		queue("root", 2)
+	}
+
+	release()
+	var granted []string
+	for range 9 {
+		granted = append(granted, <-order)
+	}
+
+	// root has twice the priority of chatty: two requests for one, although
+	// it queued last
+	assert.Equal(t, []string{"chatty", "root", "root", "chatty", "root", "chatty", "chatty", "chatty", "chatty"}, granted)
+}
+
+// waitForWaiting waits until n requests to a provider are waiting, so the
+// requests are queued in order.
+func waitForWaiting(t *testing.T, s *requestScheduler, provider string, n int) {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		queue, ok := s.queues[provider]
+		return ok && len(queue.waiting) == n
+	}, time.Second, time.Millisecond)
+}
+
+func TestRequestPriority(t *testing.T) {
+	t.Parallel()
+
+	a := agent.New("researcher", "", agent.WithPriority(3))
+
+	assert.Equal(t, 6, requestPriority(a, session.New()))
+	assert.Equal(t, 3, requestPriority(a, session.New(session.WithPriority("background"))))
+	assert.Equal(t, 12, requestPriority(a, session.New(session.WithPriority("critical"))))
+}
@@ -0,0 +1,126 @@
+package runtime
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/docker/cagent/pkg/session"
+)
+
+// defaultAutosaveInterval is how often, at most, a running session is
+// written to the session store.
+const defaultAutosaveInterval = 2 * time.Second
+
+// sessionAutosaver coalesces the writes of running sessions: a session is
+// written at most once per interval. The changes in between are written once
+// the interval elapsed, with the next write, or when the run stops or waits
+// for the user, whichever comes first.
+//
+// Writes happen on the goroutine of the run, the only one changing the
+// session, so there's no copy of the session to make. Only the changes
+// written once the interval elapsed are written from a copy, taken when they
+// were saved.
+type sessionAutosaver struct {
+	store    session.Store
+	interval time.Duration
+	now      func() time.Time
+
+	// writeMu orders the writes, so that a copy never overwrites a newer
+	// write of the same session
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	written map[string]time.Time        // Time of the last write, by session ID
+	pending map[string]*session.Session // Copies of the sessions changed since their last write
+	timers  map[string]*time.Timer      // Timers writing the pending changes
+}
+
+func newSessionAutosaver(store session.Store, interval time.Duration) *sessionAutosaver {
+	return &sessionAutosaver{
+		store:    store,
+		interval: interval,
+		now:      time.Now,
+		written:  make(map[string]time.Time),
+		pending:  make(map[string]*session.Session),
+		timers:   make(map[string]*time.Timer),
+	}
+}
+
+// save writes the session, unless it was written less than an interval ago.
+// In that case, the changes are written once the interval elapsed.
+func (s *sessionAutosaver) save(ctx context.Context, sess *session.Session) {
+	s.mu.Lock()
+	if wait := s.interval - s.now().Sub(s.written[sess.ID]); wait > 0 {
+		s.pending[sess.ID] = snapshotSession(sess)
+		if s.timers[sess.ID] == nil {
+			id := sess.ID
+			s.timers[id] = time.AfterFunc(wait, func() { s.writePending(ctx, id) })
+		}
+		s.mu.Unlock()
+		return
+	}
+	s.mu.Unlock()
+
+	s.write(ctx, sess)
+}
+
+// flush writes the changes of the session that weren't written yet.
+func (s *sessionAutosaver) flush(ctx context.Context, sess *session.Session) {
+	s.mu.Lock()
+	_, pending := s.pending[sess.ID]
+	s.mu.Unlock()
+
+	if pending {
+		s.write(ctx, sess)
+	}
+}
+
+func (s *sessionAutosaver) write(ctx context.Context, sess *session.Session) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	s.mu.Lock()
+	s.written[sess.ID] = s.now()
+	delete(s.pending, sess.ID)
+	s.mu.Unlock()
+
+	s.update(ctx, sess)
+}
+
+// writePending writes the copy of a session changed since its last write,
+// unless the run wrote the session in the meantime.
+func (s *sessionAutosaver) writePending(ctx context.Context, id string) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	s.mu.Lock()
+	delete(s.timers, id)
+	sess := s.pending[id]
+	if sess != nil {
+		s.written[id] = s.now()
+		delete(s.pending, id)
+	}
+	s.mu.Unlock()
+
+	if sess != nil {
+		s.update(ctx, sess)
+	}
+}
+
+func (s *sessionAutosaver) update(ctx context.Context, sess *session.Session) {
+	// The session must be written even when the run was canceled
+	if err := s.store.UpdateSession(context.WithoutCancel(ctx), sess); err != nil {
+		slog.Warn("Failed to save session", "session_id", sess.ID, "error", err)
+	}
+}
+
+// snapshotSession copies a session for a write on another goroutine. The
+// messages of a session are only appended, so the copy shares them.
+func snapshotSession(sess *session.Session) *session.Session {
+	snapshot := *sess
+	snapshot.Messages = slices.Clone(sess.Messages)
+	return &snapshot
+}
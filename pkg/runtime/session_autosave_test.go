@@ -0,0 +1,74 @@
+package runtime
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/session"
+)
+
+type countingSessionStore struct {
+	session.Store
+	updates atomic.Int32
+}
+
+func (s *countingSessionStore) UpdateSession(ctx context.Context, sess *session.Session) error {
+	s.updates.Add(1)
+	return s.Store.UpdateSession(ctx, sess)
+}
+
+func TestSessionAutosaver_CoalescesWrites(t *testing.T) {
+	t.Parallel()
+
+	store := &countingSessionStore{Store: session.NewInMemorySessionStore()}
+	saver := newSessionAutosaver(store, time.Second)
+	now := time.Now()
+	saver.now = func() time.Time { return now }
+
+	sess := session.New(session.WithUserMessage("Hello"))
+
+	// The first save is written, the next ones wait for the interval
+	saver.save(t.Context(), sess)
+	saver.save(t.Context(), sess)
+	saver.save(t.Context(), sess)
+	assert.Equal(t, int32(1), store.updates.Load())
+
+	now = now.Add(time.Second)
+	saver.save(t.Context(), sess)
+	assert.Equal(t, int32(2), store.updates.Load())
+
+	// Flushing only writes the pending changes
+	saver.flush(t.Context(), sess)
+	assert.Equal(t, int32(2), store.updates.Load())
+
+	saver.save(t.Context(), sess)
+	saver.flush(t.Context(), sess)
+	assert.Equal(t, int32(3), store.updates.Load())
+}
+
+func TestSessionAutosaver_WritesPendingChanges(t *testing.T) {
+	t.Parallel()
+
+	store := &countingSessionStore{Store: session.NewInMemorySessionStore()}
+	saver := newSessionAutosaver(store, 50*time.Millisecond)
+
+	sess := session.New(session.WithUserMessage("Hello"))
+	require.NoError(t, store.AddSession(t.Context(), sess))
+
+	saver.save(t.Context(), sess)
+	sess.AddMessage(session.UserMessage("Are you there?"))
+	saver.save(t.Context(), sess)
+	assert.Equal(t, int32(1), store.updates.Load())
+
+	// The pending changes are written once the interval elapsed, even if
+	// the session isn't saved again
+	assert.Eventually(t, func() bool { return store.updates.Load() == 2 }, time.Second, 10*time.Millisecond)
+	saved, err := store.GetSession(t.Context(), sess.ID)
+	require.NoError(t, err)
+	assert.Len(t, saved.GetAllMessages(), 2)
+}
@@ -40,7 +40,8 @@ func newSessionCompactor(model provider.Provider, sessionStore session.Store) *s
 	}
 }
 
-func (c *sessionCompactor) Compact(ctx context.Context, sess *session.Session, additionalPrompt string, events chan Event, agentName string) {
+// Compact summarizes the session with model, or the compactor's model when nil.
+func (c *sessionCompactor) Compact(ctx context.Context, model provider.Provider, sess *session.Session, additionalPrompt string, events chan Event, agentName string) {
 	slog.Debug("Generating summary for session", "session_id", sess.ID)
 
 	events <- SessionCompaction(sess.ID, "started", agentName)
@@ -57,7 +58,10 @@ func (c *sessionCompactor) Compact(ctx context.Context, sess *session.Session, a
 	conversationHistory := c.buildConversationHistory(messages)
 	userPrompt := c.buildUserPrompt(conversationHistory, additionalPrompt)
 
-	summary := c.generateSummary(ctx, userPrompt)
+	if model == nil {
+		model = c.model
+	}
+	summary := c.generateSummary(ctx, model, userPrompt)
 	if summary == "" {
 		return
 	}
@@ -94,8 +98,8 @@ func (c *sessionCompactor) buildUserPrompt(conversationHistory, additionalPrompt
 	return prompt
 }
 
-func (c *sessionCompactor) generateSummary(ctx context.Context, userPrompt string) string {
-	summaryModel := provider.CloneWithOptions(ctx, c.model, options.WithStructuredOutput(nil))
+func (c *sessionCompactor) generateSummary(ctx context.Context, model provider.Provider, userPrompt string) string {
+	summaryModel := provider.CloneWithOptions(ctx, model, options.WithStructuredOutput(nil))
 	newTeam := team.New(
 		team.WithAgents(agent.New("root", compactionSystemPrompt, agent.WithModel(summaryModel))),
 	)
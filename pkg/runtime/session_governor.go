@@ -0,0 +1,100 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/config/latest"
+	"github.com/docker/cagent/pkg/session"
+)
+
+// governSession checkpoints and compacts the session once it reached one of
+// the milestones of the agent's governor. With rotation, the conversation
+// continues in a fresh session linked to the checkpointed one.
+func (r *LocalRuntime) governSession(ctx context.Context, a *agent.Agent, sess *session.Session, events chan Event) {
+	governor := a.Governor()
+	if governor == nil || sess.IsSubSession() {
+		return
+	}
+
+	turns := turnsSinceSummary(sess)
+	tokens := sess.InputTokens + sess.OutputTokens
+	if !milestoneReached(governor, turns, tokens) {
+		return
+	}
+
+	slog.Debug("Session reached a governor milestone", "session_id", sess.ID, "turns", turns, "tokens", tokens)
+
+	// Checkpoint the full history before it gets compacted.
+	r.autosaver.write(ctx, sess)
+
+	itemCount := len(sess.Messages)
+	r.Summarize(ctx, sess, "", events)
+	if len(sess.Messages) == itemCount {
+		// The summary couldn't be generated.
+		return
+	}
+
+	if !governor.Rotate {
+		return
+	}
+
+	next := rotateSession(sess, sess.Messages[len(sess.Messages)-1].Summary)
+	if err := r.sessionStore.AddSession(ctx, next); err != nil {
+		slog.Error("Failed to store rotated session", "session_id", next.ID, "error", err)
+		events <- Warning(fmt.Sprintf("Failed to continue in a new session: %v", err), a.Name())
+		return
+	}
+
+	slog.Debug("Rotated session", "previous_session_id", sess.ID, "session_id", next.ID)
+	events <- SessionRotated(sess.ID, next.ID, a.Name())
+}
+
+// milestoneReached reports whether a session with the given number of turns
+// since its last compaction and context size should be compacted.
+func milestoneReached(governor *latest.GovernorConfig, turns int, tokens int64) bool {
+	return (governor.MaxTurns > 0 && turns >= governor.MaxTurns) ||
+		(governor.MaxTokens > 0 && tokens >= governor.MaxTokens)
+}
+
+// turnsSinceSummary counts the user messages added since the last summary.
+func turnsSinceSummary(sess *session.Session) int {
+	turns := 0
+	for i := len(sess.Messages) - 1; i >= 0; i-- {
+		item := sess.Messages[i]
+		if item.Summary != "" {
+			break
+		}
+		if item.IsMessage() && item.Message.Message.Role == chat.MessageRoleUser && !item.Message.Implicit {
+			turns++
+		}
+	}
+	return turns
+}
+
+// rotateSession creates the session that continues sess, seeded with its
+// summary and carrying over its settings.
+func rotateSession(sess *session.Session, summary string) *session.Session {
+	next := session.New(
+		session.WithPreviousSessionID(sess.ID),
+		session.WithTitle(sess.Title),
+		session.WithMaxIterations(sess.MaxIterations),
+		session.WithWorkingDir(sess.WorkingDir),
+		session.WithToolsApproved(sess.ToolsApproved),
+		session.WithHideToolResults(sess.HideToolResults),
+		session.WithSendUserMessage(sess.SendUserMessage),
+		session.WithPermissions(sess.Permissions),
+	)
+	next.Messages = []session.Item{{Summary: summary}}
+	next.AgentModelOverrides = maps.Clone(sess.AgentModelOverrides)
+	next.CustomModelsUsed = slices.Clone(sess.CustomModelsUsed)
+	next.Todos = slices.Clone(sess.Todos)
+	// The summary may carry untrusted content over.
+	next.UntrustedContent = sess.UntrustedContent
+	return next
+}
@@ -0,0 +1,64 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/config/latest"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/todo"
+)
+
+func TestMilestoneReached(t *testing.T) {
+	t.Parallel()
+
+	governor := &latest.GovernorConfig{MaxTurns: 10, MaxTokens: 1000}
+
+	assert.False(t, milestoneReached(governor, 9, 999))
+	assert.True(t, milestoneReached(governor, 10, 0))
+	assert.True(t, milestoneReached(governor, 0, 1000))
+	assert.False(t, milestoneReached(&latest.GovernorConfig{}, 1000, 1_000_000), "no milestone configured")
+}
+
+func TestTurnsSinceSummary(t *testing.T) {
+	t.Parallel()
+
+	sess := session.New()
+	sess.AddMessage(session.UserMessage("first"))
+	sess.AddMessage(session.NewAgentMessage(agent.New("root", ""), &chat.Message{Role: chat.MessageRoleAssistant, Content: "ok"}))
+	sess.Messages = append(sess.Messages, session.Item{Summary: "summary"})
+	sess.AddMessage(session.UserMessage("second"))
+	sess.AddMessage(session.ImplicitUserMessage("implicit"))
+	sess.AddMessage(session.UserMessage("third"))
+
+	assert.Equal(t, 2, turnsSinceSummary(sess))
+}
+
+func TestRotateSession(t *testing.T) {
+	t.Parallel()
+
+	sess := session.New(
+		session.WithTitle("Week-long refactoring"),
+		session.WithWorkingDir("/work"),
+		session.WithToolsApproved(true),
+	)
+	sess.AddMessage(session.UserMessage("hello"))
+	sess.AgentModelOverrides = map[string]string{"root": "openai/gpt-4o"}
+	sess.Todos = []todo.Todo{{ID: "todo_1", Description: "Refactor", Status: "pending"}}
+	sess.InputTokens = 5000
+
+	next := rotateSession(sess, "what happened so far")
+
+	assert.NotEqual(t, sess.ID, next.ID)
+	assert.Equal(t, sess.ID, next.PreviousSessionID)
+	assert.Equal(t, []session.Item{{Summary: "what happened so far"}}, next.Messages)
+	assert.Equal(t, "Week-long refactoring", next.Title)
+	assert.Equal(t, "/work", next.WorkingDir)
+	assert.True(t, next.ToolsApproved)
+	assert.Equal(t, sess.AgentModelOverrides, next.AgentModelOverrides)
+	assert.Equal(t, sess.Todos, next.Todos)
+	assert.Zero(t, next.InputTokens)
+}
@@ -0,0 +1,68 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/model/provider"
+)
+
+// errStreamStalled cancels the request of a stream that stalled.
+var errStreamStalled = errors.New("stream stalled")
+
+// StreamStalledError is returned when the stream of a model sent nothing for
+// longer than the stream timeout of the agent.
+type StreamStalledError struct {
+	Timeout time.Duration
+}
+
+func (e *StreamStalledError) Error() string {
+	return fmt.Sprintf("the model stream stalled: nothing was received for %s", e.Timeout)
+}
+
+// retryable reports whether a failed request to a model is retried: the
+// stalled streams are, like the errors the providers may recover from.
+func retryable(err error) bool {
+	var stalled *StreamStalledError
+	return errors.As(err, &stalled) || provider.IsRetryable(err)
+}
+
+// stallStream cancels the request of a stream that doesn't send anything for
+// longer than its timeout, so that a stalled provider doesn't hang the run.
+type stallStream struct {
+	chat.MessageStream
+	ctx     context.Context
+	timeout time.Duration
+	timer   *time.Timer
+}
+
+// watchStall watches a stream sent with a request that cancel cancels.
+func watchStall(ctx context.Context, cancel context.CancelCauseFunc, stream chat.MessageStream, timeout time.Duration) chat.MessageStream {
+	return &stallStream{
+		MessageStream: stream,
+		ctx:           ctx,
+		timeout:       timeout,
+		timer:         time.AfterFunc(timeout, func() { cancel(errStreamStalled) }),
+	}
+}
+
+func (s *stallStream) Recv() (chat.MessageStreamResponse, error) {
+	response, err := s.MessageStream.Recv()
+	if err != nil {
+		s.timer.Stop()
+		if errors.Is(context.Cause(s.ctx), errStreamStalled) {
+			return response, &StreamStalledError{Timeout: s.timeout}
+		}
+		return response, err
+	}
+	s.timer.Reset(s.timeout)
+	return response, nil
+}
+
+func (s *stallStream) Close() {
+	s.timer.Stop()
+	s.MessageStream.Close()
+}
@@ -0,0 +1,61 @@
+package runtime
+
+import (
+	"time"
+
+	"github.com/docker/cagent/pkg/session"
+)
+
+// wrapUpInstruction is sent to the agent when its run is about to reach its
+// time limit, so it leaves the task in a state someone can pick it up from.
+const wrapUpInstruction = `The time allotted to this run is almost over. Don't start anything new.
+Update the todo list, if you have one, with what is done and what remains, then reply with a short status report: what you did, what is left and how to continue.`
+
+// maxWrapUpMargin caps how long before the time limit the agent is asked to
+// wrap up.
+const maxWrapUpMargin = 2 * time.Minute
+
+// runClock tracks the time limit of a run of a session.
+type runClock struct {
+	deadline time.Time
+	wrapUpAt time.Time
+	// wrappedUp is set once the agent was asked to wrap up
+	wrappedUp bool
+}
+
+// newRunClock starts the clock of a run of the session. It returns nil when
+// the session has no time limit.
+func newRunClock(sess *session.Session, now time.Time) *runClock {
+	if sess.TimeLimit <= 0 {
+		return nil
+	}
+	return &runClock{
+		deadline: now.Add(sess.TimeLimit),
+		wrapUpAt: now.Add(sess.TimeLimit - min(sess.TimeLimit/5, maxWrapUpMargin)),
+	}
+}
+
+// wrapUpDue reports whether the agent should now be asked to wrap up. It
+// reports true only once.
+func (c *runClock) wrapUpDue(now time.Time) bool {
+	if c == nil || c.wrappedUp || now.Before(c.wrapUpAt) {
+		return false
+	}
+	c.wrappedUp = true
+	return true
+}
+
+// expired reports whether the run is over. The agent always gets a turn to
+// wrap up, even when it was asked to after the deadline.
+func (c *runClock) expired(now time.Time) bool {
+	return c != nil && c.wrappedUp && !now.Before(c.deadline)
+}
+
+// remaining returns the time left before the deadline, zero when the run has
+// no time limit. The sub-sessions of a run get what remains of it.
+func (c *runClock) remaining(now time.Time) time.Duration {
+	if c == nil {
+		return 0
+	}
+	return max(time.Millisecond, c.deadline.Sub(now))
+}
@@ -0,0 +1,70 @@
+package runtime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/session"
+)
+
+func TestRunClock(t *testing.T) {
+	t.Parallel()
+
+	start := time.Now()
+	clock := newRunClock(session.New(session.WithTimeLimit(10*time.Minute)), start)
+
+	assert.False(t, clock.wrapUpDue(start.Add(7*time.Minute)))
+	assert.Equal(t, 3*time.Minute, clock.remaining(start.Add(7*time.Minute)))
+
+	// Asked to wrap up two minutes before the deadline, once
+	assert.True(t, clock.wrapUpDue(start.Add(8*time.Minute)))
+	assert.False(t, clock.wrapUpDue(start.Add(9*time.Minute)))
+	assert.False(t, clock.expired(start.Add(9*time.Minute)))
+	assert.True(t, clock.expired(start.Add(10*time.Minute)))
+}
+
+func TestRunClock_WrapUpAfterDeadline(t *testing.T) {
+	t.Parallel()
+
+	start := time.Now()
+	clock := newRunClock(session.New(session.WithTimeLimit(time.Minute)), start)
+
+	// A turn that outlasted the whole run still leaves one to wrap up
+	assert.False(t, clock.expired(start.Add(2*time.Minute)))
+	assert.True(t, clock.wrapUpDue(start.Add(2*time.Minute)))
+	assert.True(t, clock.expired(start.Add(2*time.Minute)))
+}
+
+func TestRunClock_NoLimit(t *testing.T) {
+	t.Parallel()
+
+	clock := newRunClock(session.New(), time.Now())
+
+	assert.Nil(t, clock)
+	assert.False(t, clock.wrapUpDue(time.Now().Add(time.Hour)))
+	assert.False(t, clock.expired(time.Now().Add(time.Hour)))
+	assert.Zero(t, clock.remaining(time.Now()))
+}
+
+func TestRunStream_WrapUp(t *testing.T) {
+	stream := newStreamBuilder().
+		AddContent("Done: the parser. Left: the tests.").
+		AddStopWithUsage(3, 2).
+		Build()
+
+	// The run is over before it starts: the agent only gets to wrap up
+	sess := session.New(session.WithUserMessage("Hi"), session.WithTimeLimit(time.Nanosecond))
+	events := runSession(t, sess, stream)
+
+	assert.True(t, hasEventType(t, events, &WarningEvent{}))
+
+	messages := sess.GetAllMessages()
+	require.Len(t, messages, 3)
+	assert.True(t, messages[1].Message.Role == chat.MessageRoleUser && messages[1].Implicit)
+	assert.Equal(t, wrapUpInstruction, messages[1].Message.Content)
+	assert.Equal(t, "Done: the parser. Left: the tests.", messages[2].Message.Content)
+}
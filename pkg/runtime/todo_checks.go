@@ -0,0 +1,147 @@
+package runtime
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/audit"
+	"github.com/docker/cagent/pkg/config/latest"
+	"github.com/docker/cagent/pkg/permissions"
+	"github.com/docker/cagent/pkg/policy"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/todo"
+	"github.com/docker/cagent/pkg/tools"
+	"github.com/docker/cagent/pkg/tools/builtin"
+)
+
+// configureTodoVerifier lets the agent's todo tools run the checks of the
+// todos the agent completes.
+func (r *LocalRuntime) configureTodoVerifier(a *agent.Agent, sess *session.Session) {
+	for _, toolset := range a.ToolSets() {
+		if todoTool := unwrapTodoTool(toolset); todoTool != nil {
+			todoTool.SetVerifier(func(ctx context.Context, check todo.DoneCheck) todo.CheckResult {
+				return r.runDoneCheck(ctx, a, sess, check)
+			})
+		}
+	}
+}
+
+// runDoneCheck runs a check of a todo. Commands are written by the agent, so
+// they only run when the agent could run them with its shell tool without
+// asking the user. Otherwise the check is skipped.
+func (r *LocalRuntime) runDoneCheck(ctx context.Context, a *agent.Agent, sess *session.Session, check todo.DoneCheck) todo.CheckResult {
+	if check.FileExists != "" {
+		path := check.FileExists
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(cmp.Or(sess.WorkingDir, r.workingDir), path)
+		}
+		if _, err := os.Stat(path); err != nil {
+			return todo.CheckResult{Status: todo.CheckFailed, Output: err.Error()}
+		}
+		return todo.CheckResult{Status: todo.CheckPassed}
+	}
+
+	shell := agentShellTool(a)
+	if shell == nil {
+		return todo.CheckResult{Status: todo.CheckSkipped, Output: "The agent has no shell tool to run the command."}
+	}
+
+	args := map[string]any{"cmd": check.Command}
+	if !r.autoApproved(ctx, a, sess, builtin.ToolNameShell, args) {
+		return todo.CheckResult{Status: todo.CheckSkipped, Output: "Running the command needs the approval of the user."}
+	}
+	arguments, _ := json.Marshal(args)
+	toolCall := tools.ToolCall{
+		Type:     "function",
+		Function: tools.FunctionCall{Name: builtin.ToolNameShell, Arguments: string(arguments)},
+	}
+	r.recordAudit(sess, a, toolCall, audit.Event{Type: audit.ToolCallApproved, Source: "todo check"})
+
+	output, err := shell.RunCheck(ctx, check.Command)
+	if err != nil {
+		return todo.CheckResult{Status: todo.CheckFailed, Output: fmt.Sprintf("%v\n%s", err, output)}
+	}
+	return todo.CheckResult{Status: todo.CheckPassed, Output: output}
+}
+
+// autoApproved reports whether a call of a tool would run without asking the
+// user: allowed by the policy or the permissions, or with all the tools
+// approved. Unlike executeWithApproval, it never asks.
+func (r *LocalRuntime) autoApproved(ctx context.Context, a *agent.Agent, sess *session.Session, toolName string, toolArgs map[string]any) bool {
+	if a.ConfirmUntrusted() && sess.UntrustedContent {
+		return false
+	}
+
+	if r.policyEngine != nil {
+		tool := tools.Tool{Name: toolName, Category: toolName}
+		decision, err := r.policyEngine.Evaluate(ctx, policyInput(a, sess, tool, toolArgs))
+		if err != nil {
+			return false
+		}
+		switch decision.Action {
+		case policy.Allow:
+			return true
+		case policy.Deny, policy.Ask:
+			return false
+		}
+	}
+
+	if sess.Permissions != nil {
+		if perm := sess.Permissions.GetToolPermission(toolName); perm != nil {
+			return sess.Permissions.IsToolEnabled(toolName) && sess.Permissions.GetToolMode(toolName) == session.PermissionModeAlwaysAllow
+		}
+		checker := permissions.NewChecker(&latest.PermissionsConfig{
+			Allow: sess.Permissions.Allow,
+			Deny:  sess.Permissions.Deny,
+		})
+		switch checker.CheckWithArgs(toolName, toolArgs) {
+		case permissions.Allow:
+			return true
+		case permissions.Deny:
+			return false
+		}
+	}
+
+	if checker := r.team.Permissions(); checker != nil {
+		switch checker.CheckWithArgs(toolName, toolArgs) {
+		case permissions.Allow:
+			return true
+		case permissions.Deny:
+			return false
+		}
+	}
+
+	return sess.ToolsApproved || (r.toolApprovals != nil && r.toolApprovals.IsAlwaysAllowed(a.Name(), toolName))
+}
+
+// agentShellTool returns the shell tool of an agent, if it has one.
+func agentShellTool(a *agent.Agent) *builtin.ShellTool {
+	for _, toolset := range a.ToolSets() {
+		if shell := unwrapShellTool(toolset); shell != nil {
+			return shell
+		}
+	}
+	return nil
+}
+
+// unwrapShellTool extracts a shell tool from the toolsets wrapping it.
+func unwrapShellTool(toolset tools.ToolSet) *builtin.ShellTool {
+	for toolset != nil {
+		switch ts := toolset.(type) {
+		case *builtin.ShellTool:
+			return ts
+		case *agent.StartableToolSet:
+			toolset = ts.ToolSet
+		case interface{ Unwrap() tools.ToolSet }:
+			toolset = ts.Unwrap()
+		default:
+			return nil
+		}
+	}
+	return nil
+}
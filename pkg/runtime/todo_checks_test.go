@@ -0,0 +1,82 @@
+package runtime
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/config"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/team"
+	"github.com/docker/cagent/pkg/todo"
+	"github.com/docker/cagent/pkg/tools"
+	"github.com/docker/cagent/pkg/tools/builtin"
+)
+
+func TestRunDoneCheck(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "report.md"), []byte("done"), 0o644))
+
+	shell := builtin.NewShellTool(nil, &config.RuntimeConfig{Config: config.Config{WorkingDir: dir}}, nil)
+	prov := &mockProvider{id: "test/mock-model", stream: &mockStream{}}
+	root := agent.New("root", "You are a test agent", agent.WithModel(prov), agent.WithToolSets(shell))
+	rt, err := New(team.New(team.WithAgents(root)), WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+
+	sess := session.New(session.WithWorkingDir(dir))
+
+	result := rt.runDoneCheck(t.Context(), root, sess, todo.DoneCheck{FileExists: "report.md"})
+	assert.Equal(t, todo.CheckPassed, result.Status)
+	result = rt.runDoneCheck(t.Context(), root, sess, todo.DoneCheck{FileExists: "missing.md"})
+	assert.Equal(t, todo.CheckFailed, result.Status)
+
+	// Commands written by the agent don't run without the approval of the user
+	result = rt.runDoneCheck(t.Context(), root, sess, todo.DoneCheck{Command: "true"})
+	assert.Equal(t, todo.CheckSkipped, result.Status)
+
+	// They run when the permissions allow them
+	sess.Permissions = &session.PermissionsConfig{Allow: []string{"shell:cmd=test *"}}
+	result = rt.runDoneCheck(t.Context(), root, sess, todo.DoneCheck{Command: "test -f report.md"})
+	assert.Equal(t, todo.CheckPassed, result.Status)
+	result = rt.runDoneCheck(t.Context(), root, sess, todo.DoneCheck{Command: "test -f missing.md"})
+	assert.Equal(t, todo.CheckFailed, result.Status)
+	result = rt.runDoneCheck(t.Context(), root, sess, todo.DoneCheck{Command: "rm report.md"})
+	assert.Equal(t, todo.CheckSkipped, result.Status)
+	assert.FileExists(t, filepath.Join(dir, "report.md"))
+
+	// Nothing is auto-approved once untrusted content entered the conversation
+	sess.ToolsApproved = true
+	sess.UntrustedContent = true
+	guarded := agent.New("guarded", "You are a test agent", agent.WithToolSets(shell), agent.WithConfirmUntrusted(true))
+	result = rt.runDoneCheck(t.Context(), guarded, sess, todo.DoneCheck{Command: "test -f report.md"})
+	assert.Equal(t, todo.CheckSkipped, result.Status)
+
+	// Agents without a shell tool can't run commands
+	result = rt.runDoneCheck(t.Context(), agent.New("other", "You are a test agent"), sess, todo.DoneCheck{Command: "true"})
+	assert.Equal(t, todo.CheckSkipped, result.Status)
+}
+
+// wrappedToolSet wraps a toolset like the toolsets of the team loader do.
+type wrappedToolSet struct {
+	tools.ToolSet
+}
+
+func (w wrappedToolSet) Unwrap() tools.ToolSet {
+	return w.ToolSet
+}
+
+func TestAgentShellTool_Unwraps(t *testing.T) {
+	t.Parallel()
+
+	shell := builtin.NewShellTool(nil, &config.RuntimeConfig{}, nil)
+	root := agent.New("root", "You are a test agent", agent.WithToolSets(wrappedToolSet{wrappedToolSet{shell}}))
+
+	assert.Same(t, shell, agentShellTool(root))
+	assert.Nil(t, agentShellTool(agent.New("other", "You are a test agent")))
+}
@@ -0,0 +1,76 @@
+package runtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+
+	"github.com/docker/cagent/pkg/tools"
+)
+
+// validateToolArguments checks the arguments the model generated for a tool
+// call against the input schema of the tool, before the tool is approved or
+// runs. It returns the message telling the model what's wrong with them, or
+// an empty string if they're valid. Tools without a schema, or with a schema
+// that can't be compiled, accept any arguments.
+func (r *LocalRuntime) validateToolArguments(tool tools.Tool, toolCall tools.ToolCall) string {
+	if tool.Parameters == nil {
+		return ""
+	}
+	schema := r.argumentsSchema(tool)
+	if schema == nil {
+		return ""
+	}
+
+	arguments := toolCall.Function.Arguments
+	if strings.TrimSpace(arguments) == "" {
+		arguments = "{}"
+	}
+	var args any
+	if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+		return fmt.Sprintf("Invalid arguments for tool '%s': they aren't valid JSON: %s. Fix the arguments and call the tool again.", toolCall.Function.Name, err)
+	}
+
+	result, err := schema.Validate(gojsonschema.NewGoLoader(args))
+	if err != nil {
+		slog.Debug("Failed to validate tool arguments", "tool", toolCall.Function.Name, "error", err)
+		return ""
+	}
+	if result.Valid() {
+		return ""
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "Invalid arguments for tool '%s':\n", toolCall.Function.Name)
+	for _, e := range result.Errors() {
+		fmt.Fprintf(&msg, "- %s\n", e.String())
+	}
+	msg.WriteString("Fix the arguments and call the tool again.")
+	return msg.String()
+}
+
+// argumentsSchema returns the compiled input schema of a tool. Schemas are
+// compiled once and cached by their content, since the tools of MCP servers
+// can change between turns.
+func (r *LocalRuntime) argumentsSchema(tool tools.Tool) *gojsonschema.Schema {
+	buf, err := json.Marshal(tool.Parameters)
+	if err != nil {
+		return nil
+	}
+	key := string(buf)
+	if cached, ok := r.argumentSchemas.Load(key); ok {
+		schema, _ := cached.(*gojsonschema.Schema)
+		return schema
+	}
+
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(buf))
+	if err != nil {
+		slog.Debug("Tool arguments won't be validated, its schema can't be compiled", "tool", tool.Name, "error", err)
+		schema = nil
+	}
+	r.argumentSchemas.Store(key, schema)
+	return schema
+}
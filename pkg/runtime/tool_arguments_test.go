@@ -0,0 +1,102 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/team"
+	"github.com/docker/cagent/pkg/tools"
+)
+
+type searchArgs struct {
+	Query string `json:"query"`
+	Count int    `json:"count,omitempty"`
+}
+
+func TestRunStream_InvalidToolArguments(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+	agentTools := []tools.Tool{{
+		Name:       "search",
+		Parameters: tools.MustSchemaFor[searchArgs](),
+		Handler: func(_ context.Context, call tools.ToolCall) (*tools.ToolCallResult, error) {
+			calls = append(calls, call.Function.Arguments)
+			return tools.ResultSuccess("found"), nil
+		},
+	}}
+
+	prov := &queueProvider{id: "test/mock-model", streams: []chat.MessageStream{
+		newStreamBuilder().
+			AddToolCallName("call_1", "search").
+			AddToolCallArguments("call_1", `{"query":"cagent","count":"ten"}`).
+			Build(),
+		newStreamBuilder().
+			AddToolCallName("call_2", "search").
+			AddToolCallArguments("call_2", `{"query":"cagent","count":10}`).
+			Build(),
+		newStreamBuilder().AddContent("Done").AddStopWithUsage(10, 5).Build(),
+	}}
+	root := agent.New("root", "You are a test agent",
+		agent.WithModel(prov),
+		agent.WithToolSets(newStubToolSet(nil, agentTools, nil)),
+	)
+	rt, err := New(team.New(team.WithAgents(root)), WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+
+	sess := session.New(session.WithUserMessage("Search"), session.WithTitle("Unit Test"), session.WithToolsApproved(true))
+	for range rt.RunStream(t.Context(), sess) {
+	}
+
+	// The tool only runs once the model fixed its arguments
+	require.Len(t, calls, 1)
+	assert.JSONEq(t, `{"query":"cagent","count":10}`, calls[0])
+
+	messages := sess.GetAllMessages()
+	assert.Equal(t, "call_1", messages[2].Message.ToolCallID)
+	assert.Contains(t, messages[2].Message.Content, "Invalid arguments for tool 'search':")
+	assert.Contains(t, messages[2].Message.Content, "count: Invalid type. Expected: integer, given: string")
+}
+
+func TestValidateToolArguments(t *testing.T) {
+	t.Parallel()
+
+	rt := &LocalRuntime{}
+	search := tools.Tool{Name: "search", Parameters: tools.MustSchemaFor[searchArgs]()}
+
+	tests := []struct {
+		name      string
+		tool      tools.Tool
+		arguments string
+		want      []string
+	}{
+		{name: "valid", tool: search, arguments: `{"query":"cagent"}`},
+		{name: "missing required", tool: search, arguments: `{"count":1}`, want: []string{"(root): query is required"}},
+		{name: "empty arguments", tool: search, arguments: "", want: []string{"(root): query is required"}},
+		{name: "unknown argument", tool: search, arguments: `{"query":"cagent","limit":1}`, want: []string{"Additional property limit is not allowed"}},
+		{name: "invalid JSON", tool: search, arguments: `{"query":`, want: []string{"they aren't valid JSON"}},
+		{name: "no schema", tool: tools.Tool{Name: "any"}, arguments: `not JSON`},
+		{name: "schema without properties", tool: tools.Tool{Name: "any", Parameters: map[string]any{}}, arguments: `{"a":1}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := rt.validateToolArguments(tt.tool, tools.ToolCall{Function: tools.FunctionCall{Name: tt.tool.Name, Arguments: tt.arguments}})
+			if tt.want == nil {
+				assert.Empty(t, got)
+				return
+			}
+			for _, want := range tt.want {
+				assert.Contains(t, got, want)
+			}
+			assert.Contains(t, got, "Fix the arguments and call the tool again.")
+		})
+	}
+}
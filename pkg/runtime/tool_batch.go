@@ -0,0 +1,105 @@
+package runtime
+
+import (
+	"context"
+)
+
+// toolCallBatch runs the tool calls a model asked for in one turn. Up to limit
+// handlers run at the same time, each with its own context, but the results
+// are recorded one at a time, by the goroutine of the batch, in the order of
+// the calls: the session isn't safe for concurrent use and the transcript
+// must not depend on which call finished first.
+type toolCallBatch struct {
+	// slots holds a token per running handler. It is nil when the calls run
+	// one after the other.
+	slots   chan struct{}
+	pending []*pendingToolCall
+}
+
+// pendingToolCall is a tool call whose result wasn't recorded yet.
+type pendingToolCall struct {
+	done   chan struct{}
+	record func()
+}
+
+func newToolCallBatch(limit int) *toolCallBatch {
+	b := &toolCallBatch{}
+	if limit > 1 {
+		b.slots = make(chan struct{}, limit)
+	}
+	return b
+}
+
+// run calls handler, concurrently with the other calls of the batch if the
+// limit allows, then record once the results of the earlier calls were
+// recorded. When ctx is cancelled while the call waits for a slot, handler
+// is called with the cancelled context, to return early. A nil batch runs
+// the call right away.
+func (b *toolCallBatch) run(ctx context.Context, handler func(ctx context.Context), record func()) {
+	if b == nil || b.slots == nil {
+		handler(ctx)
+		record()
+		return
+	}
+
+	call := &pendingToolCall{done: make(chan struct{}), record: record}
+	b.pending = append(b.pending, call)
+
+	acquired := false
+	select {
+	case b.slots <- struct{}{}:
+		acquired = true
+	case <-ctx.Done():
+	}
+
+	callCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		defer close(call.done)
+		defer cancel()
+		if acquired {
+			defer func() { <-b.slots }()
+		}
+		handler(callCtx)
+	}()
+
+	b.recordFinished()
+}
+
+// add records a result that doesn't need a handler, an error for instance,
+// once the results of the earlier calls were recorded.
+func (b *toolCallBatch) add(record func()) {
+	if b.slots == nil {
+		record()
+		return
+	}
+
+	done := make(chan struct{})
+	close(done)
+	b.pending = append(b.pending, &pendingToolCall{done: done, record: record})
+	b.recordFinished()
+}
+
+// recordFinished records the results of the calls that finished, up to the
+// first one still running.
+func (b *toolCallBatch) recordFinished() {
+	for len(b.pending) > 0 {
+		select {
+		case <-b.pending[0].done:
+		default:
+			return
+		}
+		call := b.pending[0]
+		b.pending = b.pending[1:]
+		call.record()
+	}
+}
+
+// wait waits for the running handlers and records all the results.
+func (b *toolCallBatch) wait() {
+	for len(b.pending) > 0 {
+		call := b.pending[0]
+		b.pending = b.pending[1:]
+		<-call.done
+		call.record()
+	}
+}
@@ -0,0 +1,55 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"unicode/utf8"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/tools"
+	"github.com/docker/cagent/pkg/tools/builtin"
+)
+
+// spillToolOutput writes a tool output too large to be kept in the messages
+// of the session to a file. It returns what the conversation keeps instead:
+// the beginning of the output and where to find the rest.
+func spillToolOutput(sess *session.Session, a *agent.Agent, toolCall tools.ToolCall, output string) (string, error) {
+	path, err := session.Spill(sess.ID, toolCall.ID, output)
+	if err != nil {
+		return "", err
+	}
+
+	preview := truncateUTF8(output, defaultReadMoreLength)
+	reference := fmt.Sprintf("[The output has %d characters, only the first %d are shown. The whole output was saved to %s.", len(output), len(preview), path)
+	if model, _ := a.ToolResultSummarization(); model != nil {
+		reference += fmt.Sprintf(" Call %s with id %q and offset %d to read the rest.", builtin.ToolNameReadMore, toolCall.ID, len(preview))
+	}
+	return preview + "\n\n" + reference + "]", nil
+}
+
+// readSpilledToolOutput is read_more for the outputs written to a file of the
+// session by spillToolOutput.
+func readSpilledToolOutput(sess *session.Session, id string, offset, length int) *tools.ToolCallResult {
+	if sess == nil {
+		return unknownReadMoreID(id)
+	}
+
+	// Read a few more bytes to cut the chunk on a character boundary
+	chunk, size, err := session.ReadSpilled(sess.ID, id, offset, length+utf8.UTFMax)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return unknownReadMoreID(id)
+	case err != nil:
+		return tools.ResultError(fmt.Sprintf("Reading the output of %q: %v", id, err))
+	case offset >= size:
+		return tools.ResultError(fmt.Sprintf("Offset %d is past the end of the output (%d characters).", offset, size))
+	}
+
+	chunk = truncateUTF8(chunk, length)
+	if next := offset + len(chunk); next < size {
+		chunk += fmt.Sprintf("\n\n[Showing characters %d to %d of %d. Call %s with offset %d to continue.]", offset, next, size, builtin.ToolNameReadMore, next)
+	}
+	return tools.ResultSuccess(chunk)
+}
@@ -0,0 +1,152 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"unicode/utf8"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/model/provider"
+	"github.com/docker/cagent/pkg/model/provider/options"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/team"
+	"github.com/docker/cagent/pkg/tools"
+	"github.com/docker/cagent/pkg/tools/builtin"
+)
+
+const (
+	toolResultSummarySystemPrompt = "You are a helpful AI assistant that condenses the output of tools used by another AI agent. You keep every detail the agent might need to act on and drop the rest."
+	toolResultSummaryUserPrompt   = `The tool %q returned the output below. Summarize it for the agent that called the tool:
+- Keep identifiers verbatim: file paths, URLs, IDs, names, versions, numbers, error messages and code symbols.
+- Keep the structure of the output when it helps (lists, tables, sections).
+- Mention what was left out, so that the agent knows when to read the raw output.
+
+Return ONLY the summary, nothing else.
+
+Tool output:
+%s`
+
+	// maxSummaryInputLength caps the size of the output sent to the summarization model.
+	maxSummaryInputLength = 200_000
+	// defaultReadMoreLength is the number of characters returned by read_more by default.
+	defaultReadMoreLength = 8000
+)
+
+// estimateTokens roughly estimates the number of tokens in text.
+func estimateTokens(text string) int {
+	return len(text) / 4
+}
+
+// summarizeToolResult summarizes a tool output that is too large for the
+// agent's context. The raw output is kept so that the agent can read it with
+// the read_more tool. It returns false when the output is left as is.
+func (r *LocalRuntime) summarizeToolResult(ctx context.Context, a *agent.Agent, toolCall tools.ToolCall, output string) (string, bool) {
+	model, threshold := a.ToolResultSummarization()
+	if model == nil || threshold <= 0 || toolCall.Function.Name == builtin.ToolNameReadMore {
+		return "", false
+	}
+
+	tokens := estimateTokens(output)
+	if tokens <= threshold {
+		return "", false
+	}
+
+	slog.Debug("Summarizing tool result", "tool", toolCall.Function.Name, "tokens", tokens, "threshold", threshold)
+
+	var summary string
+	if r.replay != nil {
+		summary = r.replay.summary(toolCall.ID)
+	} else {
+		input := output
+		if len(input) > maxSummaryInputLength {
+			input = truncateUTF8(input, maxSummaryInputLength) + "\n[output truncated]"
+		}
+		summary = generateToolResultSummary(ctx, model, fmt.Sprintf(toolResultSummaryUserPrompt, toolCall.Function.Name, input))
+		if r.recorder != nil && summary != "" {
+			r.recorder.recordSummary(a.Name(), toolCall, summary)
+		}
+	}
+	if summary == "" {
+		return "", false
+	}
+
+	r.rawToolOutputs.Store(toolCall.ID, output)
+
+	return fmt.Sprintf("%s\n\n[This is a summary of a %d-token output. Call %s with id %q to read the raw output.]",
+		summary, tokens, builtin.ToolNameReadMore, toolCall.ID), true
+}
+
+func generateToolResultSummary(ctx context.Context, model provider.Provider, userPrompt string) string {
+	summaryModel := provider.CloneWithOptions(ctx, model, options.WithStructuredOutput(nil))
+	newTeam := team.New(
+		team.WithAgents(agent.New("root", toolResultSummarySystemPrompt, agent.WithModel(summaryModel))),
+	)
+
+	summarySession := session.New(session.WithSystemMessage(toolResultSummarySystemPrompt))
+	summarySession.AddMessage(session.UserMessage(userPrompt))
+	summarySession.Title = "Summarizing tool result..."
+
+	summaryRuntime, err := New(newTeam, WithSessionCompaction(false))
+	if err != nil {
+		slog.Error("Failed to create tool result summarizer runtime", "error", err)
+		return ""
+	}
+
+	if _, err := summaryRuntime.Run(ctx, summarySession); err != nil {
+		slog.Error("Failed to summarize tool result", "error", err)
+		return ""
+	}
+
+	return summarySession.GetLastAssistantMessageContent()
+}
+
+// handleReadMore returns a chunk of the raw output of a summarized tool result,
+// or of a tool output that was too large and spilled to a file.
+func (r *LocalRuntime) handleReadMore(_ context.Context, sess *session.Session, toolCall tools.ToolCall, _ chan Event) (*tools.ToolCallResult, error) {
+	var params builtin.ReadMoreArgs
+	if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %w", err)
+	}
+
+	offset := max(params.Offset, 0)
+	length := params.Length
+	if length <= 0 {
+		length = defaultReadMoreLength
+	}
+
+	value, ok := r.rawToolOutputs.Load(params.ID)
+	if !ok {
+		return readSpilledToolOutput(sess, params.ID, offset, length), nil
+	}
+	output := value.(string)
+
+	if offset >= len(output) {
+		return tools.ResultError(fmt.Sprintf("Offset %d is past the end of the output (%d characters).", offset, len(output))), nil
+	}
+
+	chunk := truncateUTF8(output[offset:], length)
+	next := offset + len(chunk)
+	if next < len(output) {
+		chunk += fmt.Sprintf("\n\n[Showing characters %d to %d of %d. Call %s with offset %d to continue.]", offset, next, len(output), builtin.ToolNameReadMore, next)
+	}
+
+	return tools.ResultSuccess(chunk), nil
+}
+
+// unknownReadMoreID is the result of read_more for an ID with no raw output.
+func unknownReadMoreID(id string) *tools.ToolCallResult {
+	return tools.ResultError(fmt.Sprintf("No summarized tool result with id %q. Raw outputs are only kept while cagent is running.", id))
+}
+
+// truncateUTF8 truncates s to at most n bytes without splitting a UTF-8 character.
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
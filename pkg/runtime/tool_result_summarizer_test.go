@@ -0,0 +1,95 @@
+package runtime
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/tools"
+	"github.com/docker/cagent/pkg/tools/builtin"
+)
+
+func readMoreCall(args string) tools.ToolCall {
+	return tools.ToolCall{Function: tools.FunctionCall{Name: builtin.ToolNameReadMore, Arguments: args}}
+}
+
+func TestHandleReadMore(t *testing.T) {
+	t.Parallel()
+
+	r := &LocalRuntime{}
+	r.rawToolOutputs.Store("call_1", strings.Repeat("a", 10)+strings.Repeat("b", 10))
+
+	res, err := r.handleReadMore(t.Context(), nil, readMoreCall(`{"id":"call_1","length":10}`), nil)
+	require.NoError(t, err)
+	assert.False(t, res.IsError)
+	assert.True(t, strings.HasPrefix(res.Output, strings.Repeat("a", 10)+"\n"))
+	assert.Contains(t, res.Output, "offset 10 to continue")
+
+	res, err = r.handleReadMore(t.Context(), nil, readMoreCall(`{"id":"call_1","offset":10}`), nil)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat("b", 10), res.Output)
+
+	res, err = r.handleReadMore(t.Context(), nil, readMoreCall(`{"id":"call_1","offset":20}`), nil)
+	require.NoError(t, err)
+	assert.True(t, res.IsError)
+
+	res, err = r.handleReadMore(t.Context(), nil, readMoreCall(`{"id":"unknown"}`), nil)
+	require.NoError(t, err)
+	assert.True(t, res.IsError)
+}
+
+func TestSummarizeToolResultSkipsSmallOutputs(t *testing.T) {
+	t.Parallel()
+
+	r := &LocalRuntime{}
+	a := agent.New("root", "", agent.WithModel(&mockProvider{id: "test/mock-model"}), agent.WithToolResultSummarization(&mockProvider{id: "test/mock-model"}, 100))
+	call := tools.ToolCall{ID: "call_1", Function: tools.FunctionCall{Name: "shell"}}
+
+	_, ok := r.summarizeToolResult(t.Context(), a, call, strings.Repeat("x", 400))
+	assert.False(t, ok)
+
+	_, ok = r.summarizeToolResult(t.Context(), agent.New("root", ""), call, strings.Repeat("x", 100_000))
+	assert.False(t, ok, "summarization is disabled")
+
+	_, stored := r.rawToolOutputs.Load("call_1")
+	assert.False(t, stored)
+}
+
+func TestTruncateUTF8(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "abc", truncateUTF8("abc", 10))
+	assert.Equal(t, "h", truncateUTF8("hé", 2))
+	assert.Equal(t, "hé", truncateUTF8("hé!", 3))
+}
+
+func TestReadMoreReadsSpilledOutputs(t *testing.T) {
+	t.Parallel()
+
+	sess := session.New()
+	t.Cleanup(func() { _ = session.RemoveSpilled(sess) })
+
+	output := strings.Repeat("a", session.SpillThreshold) + strings.Repeat("é", 10)
+	call := tools.ToolCall{ID: "call_1", Function: tools.FunctionCall{Name: "shell"}}
+	preview, err := spillToolOutput(sess, agent.New("root", ""), call, output)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(preview, strings.Repeat("a", defaultReadMoreLength)+"\n"))
+	assert.Contains(t, preview, session.SpillDir(sess.ID))
+	assert.NotContains(t, preview, builtin.ToolNameReadMore, "the agent has no read_more tool")
+
+	r := &LocalRuntime{}
+	res, err := r.handleReadMore(t.Context(), sess, readMoreCall(fmt.Sprintf(`{"id":"call_1","offset":%d,"length":5}`, session.SpillThreshold-2)), nil)
+	require.NoError(t, err)
+	assert.False(t, res.IsError)
+	assert.True(t, strings.HasPrefix(res.Output, "aaé\n"), "chunks are cut on character boundaries")
+	assert.Contains(t, res.Output, fmt.Sprintf("offset %d to continue", session.SpillThreshold+2))
+
+	res, err = r.handleReadMore(t.Context(), sess, readMoreCall(`{"id":"call_2"}`), nil)
+	require.NoError(t, err)
+	assert.True(t, res.IsError)
+}
@@ -0,0 +1,66 @@
+package runtime
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/session"
+)
+
+// ToolUsage is how many tokens the results of a tool take in the context,
+// to find the tools, and the MCP servers, that bloat it.
+type ToolUsage struct {
+	Name   string `json:"name"`
+	Calls  int    `json:"calls"`
+	Tokens int64  `json:"tokens"`
+}
+
+// toolUsage attributes the tool results of a conversation to their tools,
+// the tools whose results take the most tokens first.
+func toolUsage(messages []chat.Message) []ToolUsage {
+	names := map[string]string{}
+	var results []ToolUsage
+	for _, msg := range messages {
+		for _, call := range msg.ToolCalls {
+			names[call.ID] = call.Function.Name
+		}
+		if name, ok := names[msg.ToolCallID]; ok && msg.Role == chat.MessageRoleTool {
+			results = append(results, ToolUsage{Name: name, Calls: 1, Tokens: int64(messageTokens(msg))})
+		}
+	}
+	return sumToolUsage(results)
+}
+
+// sumToolUsage adds up the usage of the same tools, for example across
+// sessions, the tools taking the most tokens first.
+func sumToolUsage(tools []ToolUsage) []ToolUsage {
+	if len(tools) == 0 {
+		return nil
+	}
+
+	var usage []ToolUsage
+	index := map[string]int{}
+	for _, tool := range tools {
+		i, ok := index[tool.Name]
+		if !ok {
+			i = len(usage)
+			index[tool.Name] = i
+			usage = append(usage, ToolUsage{Name: tool.Name})
+		}
+		usage[i].Calls += tool.Calls
+		usage[i].Tokens += tool.Tokens
+	}
+
+	slices.SortFunc(usage, func(a, b ToolUsage) int {
+		return cmp.Or(cmp.Compare(b.Tokens, a.Tokens), cmp.Compare(a.Name, b.Name))
+	})
+	return usage
+}
+
+// tokenUsage is the usage event of a session whose context is messages.
+func (r *LocalRuntime) tokenUsage(sess *session.Session, contextLimit int64, messages []chat.Message) Event {
+	event := TokenUsage(sess.ID, r.currentAgent, sess.InputTokens, sess.OutputTokens, sess.InputTokens+sess.OutputTokens, contextLimit, sess.Cost).(*TokenUsageEvent)
+	event.Usage.Tools = toolUsage(messages)
+	return event
+}
@@ -0,0 +1,53 @@
+package runtime
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/tools"
+)
+
+func toolCallMessages(id, name, result string) []chat.Message {
+	return []chat.Message{
+		{Role: chat.MessageRoleAssistant, ToolCalls: []tools.ToolCall{{ID: id, Function: tools.FunctionCall{Name: name}}}},
+		{Role: chat.MessageRoleTool, ToolCallID: id, Content: result},
+	}
+}
+
+func TestToolUsage(t *testing.T) {
+	t.Parallel()
+
+	var messages []chat.Message
+	messages = append(messages, chat.Message{Role: chat.MessageRoleUser, Content: "Read the docs"})
+	messages = append(messages, toolCallMessages("1", "read_file", strings.Repeat("a", 400))...)
+	messages = append(messages, toolCallMessages("2", "github_get_file", strings.Repeat("b", 4000))...)
+	messages = append(messages, toolCallMessages("3", "read_file", strings.Repeat("c", 400))...)
+
+	assert.Equal(t, []ToolUsage{
+		{Name: "github_get_file", Calls: 1, Tokens: 1000},
+		{Name: "read_file", Calls: 2, Tokens: 200},
+	}, toolUsage(messages))
+	assert.Nil(t, toolUsage(messages[:1]))
+}
+
+func TestUsageTrackerSumsToolsOfSessions(t *testing.T) {
+	t.Parallel()
+
+	usage := NewUsageTracker()
+
+	root := TokenUsage("root", "root", 100, 10, 110, 1000, 0).(*TokenUsageEvent)
+	root.Usage.Tools = []ToolUsage{{Name: "shell", Calls: 2, Tokens: 300}, {Name: "fetch", Calls: 1, Tokens: 100}}
+	usage.Record(root)
+
+	sub := TokenUsage("sub", "helper", 50, 5, 55, 1000, 0).(*TokenUsageEvent)
+	sub.Usage.Tools = []ToolUsage{{Name: "fetch", Calls: 3, Tokens: 500}}
+	usage.Record(sub)
+
+	assert.Equal(t, []ToolUsage{
+		{Name: "fetch", Calls: 4, Tokens: 600},
+		{Name: "shell", Calls: 2, Tokens: 300},
+	}, usage.Tools())
+}
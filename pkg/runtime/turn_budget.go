@@ -0,0 +1,96 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/tools"
+)
+
+// concludeInstruction is sent to the agent once its turn used its token
+// budget, so it answers with what it has instead of filling the context.
+const concludeInstruction = `This turn has used up its token budget. Don't call any more tools.
+Reply now with what you found so far and what is left to do. If more work is needed, ask the user whether and how to continue.`
+
+// turnBudget tracks the tokens an agent adds to the context in a turn: its
+// answers and the results of the tools it called.
+type turnBudget struct {
+	limit int64
+	used  int64
+	// concluding is set once the agent was asked to conclude
+	concluding bool
+}
+
+// newTurnBudget starts the token budget of a turn. It returns nil when the
+// agent has no limit.
+func newTurnBudget(limit int) *turnBudget {
+	if limit <= 0 {
+		return nil
+	}
+	return &turnBudget{limit: int64(limit)}
+}
+
+// record records an answer of the agent and the results of the tool calls
+// it asked for.
+func (b *turnBudget) record(res streamResult, toolResults []session.Item) {
+	if b == nil {
+		return
+	}
+
+	if res.Usage != nil && res.Usage.OutputTokens > 0 {
+		b.used += res.Usage.OutputTokens
+	} else {
+		b.used += int64(estimateTokens(res.Content))
+	}
+	for _, item := range toolResults {
+		if item.IsMessage() && item.Message.Message.Role == chat.MessageRoleTool {
+			b.used += int64(estimateTokens(item.Message.Message.Content))
+		}
+	}
+}
+
+// concludeDue reports whether the agent should now be asked to conclude. It
+// reports true only once.
+func (b *turnBudget) concludeDue() bool {
+	if b == nil || b.concluding || b.used < b.limit {
+		return false
+	}
+	b.concluding = true
+	return true
+}
+
+// overrun reports whether the agent still calls tools after it was asked to
+// conclude.
+func (b *turnBudget) overrun(calls []tools.ToolCall) bool {
+	return b != nil && b.concluding && len(calls) > 0
+}
+
+// stopOverBudget ends a turn whose agent kept calling tools past its token
+// budget. The calls aren't run.
+func (r *LocalRuntime) stopOverBudget(ctx context.Context, sess *session.Session, a *agent.Agent, calls []tools.ToolCall, agentTools []tools.Tool, events chan Event) {
+	slog.Warn("Agent stopped at the token budget of its turn", "agent", a.Name(), "limit", a.MaxTurnTokens(), "session_id", sess.ID)
+
+	agentToolMap := make(map[string]tools.Tool, len(agentTools))
+	for _, t := range agentTools {
+		agentToolMap[t.Name] = t
+	}
+	for _, call := range calls {
+		r.addToolErrorResponse(ctx, sess, call, agentToolMap[call.Function.Name], events, a, "The tool call was not run: the turn used up its token budget.")
+	}
+
+	events <- Warning(fmt.Sprintf("The turn was stopped at its budget of %d tokens.", a.MaxTurnTokens()), a.Name())
+	// Synthesize a final assistant message so callers (e.g., parent agents)
+	// receive a non-empty response.
+	assistantMessage := chat.Message{
+		Role:      chat.MessageRoleAssistant,
+		Content:   fmt.Sprintf("I have used the token budget of this turn (%d tokens) and stopped before doing more. Tell me whether and how to continue.", a.MaxTurnTokens()),
+		CreatedAt: time.Now().Format(time.RFC3339),
+	}
+	sess.AddMessage(session.NewAgentMessage(a, &assistantMessage))
+	r.saveSession(ctx, sess)
+}
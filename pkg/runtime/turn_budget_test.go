@@ -0,0 +1,86 @@
+package runtime
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/team"
+	"github.com/docker/cagent/pkg/tools"
+)
+
+func TestTurnBudget(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, newTurnBudget(0))
+
+	budget := newTurnBudget(100)
+	budget.record(streamResult{Usage: &chat.Usage{OutputTokens: 40}}, []session.Item{
+		session.NewMessageItem(&session.Message{Message: chat.Message{Role: chat.MessageRoleTool, Content: strings.Repeat("x", 200)}}),
+	})
+	assert.False(t, budget.concludeDue())
+	assert.False(t, budget.overrun([]tools.ToolCall{{ID: "a"}}))
+
+	// Asked to conclude once, and overrun when it keeps calling tools
+	budget.record(streamResult{Content: strings.Repeat("x", 40)}, nil)
+	assert.True(t, budget.concludeDue())
+	assert.False(t, budget.concludeDue())
+	assert.False(t, budget.overrun(nil))
+	assert.True(t, budget.overrun([]tools.ToolCall{{ID: "b"}}))
+}
+
+func TestRunStream_StopsAtTurnBudget(t *testing.T) {
+	t.Parallel()
+
+	var executed int
+	agentTools := []tools.Tool{{
+		Name:       "fetch",
+		Parameters: map[string]any{},
+		Handler: func(context.Context, tools.ToolCall) (*tools.ToolCallResult, error) {
+			executed++
+			return tools.ResultSuccess(strings.Repeat("x", 1000)), nil
+		},
+	}}
+
+	var streams []chat.MessageStream
+	for _, id := range []string{"a", "b"} {
+		streams = append(streams, newStreamBuilder().
+			AddToolCallName(id, "fetch").
+			AddToolCallArguments(id, `{"url":"https://example.com/`+id+`"}`).
+			Build())
+	}
+
+	prov := &queueProvider{id: "test/mock-model", streams: streams}
+	root := agent.New("root", "You are a test agent",
+		agent.WithModel(prov),
+		agent.WithToolSets(newStubToolSet(nil, agentTools, nil)),
+		agent.WithMaxTurnTokens(100),
+	)
+	rt, err := New(team.New(team.WithAgents(root)), WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+
+	sess := session.New(session.WithUserMessage("Read the page"), session.WithTitle("Unit Test"), session.WithToolsApproved(true))
+	var warnings []string
+	for ev := range rt.RunStream(t.Context(), sess) {
+		if e, ok := ev.(*WarningEvent); ok {
+			warnings = append(warnings, e.Message)
+		}
+	}
+
+	// The second call, after the agent was asked to conclude, isn't run
+	require.Equal(t, 1, executed)
+	require.Equal(t, []string{
+		"The turn used its budget of 100 tokens: the agent was asked to conclude.",
+		"The turn was stopped at its budget of 100 tokens.",
+	}, warnings)
+
+	messages := sess.GetAllMessages()
+	require.Contains(t, messages[len(messages)-2].Message.Content, "the turn used up its token budget")
+	require.Contains(t, messages[len(messages)-1].Message.Content, "I have used the token budget of this turn")
+}
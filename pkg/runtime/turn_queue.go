@@ -0,0 +1,180 @@
+package runtime
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/docker/cagent/pkg/session"
+)
+
+// MessageQueuer is implemented by runtimes that accept the messages of the
+// user while a turn of the session is in progress, answered once the turn
+// is over.
+type MessageQueuer interface {
+	QueueMessages(sessionID string, messages ...*session.Message) (pending []string, ok bool)
+}
+
+// QueueMessages queues messages of the user for a session whose run is in
+// progress. They are answered in order, each in a turn of its own, once the
+// current turn is over. It returns the previews of the pending messages, and
+// false when the session isn't running: the messages must then be sent with
+// a new run.
+func (r *LocalRuntime) QueueMessages(sessionID string, messages ...*session.Message) ([]string, bool) {
+	value, ok := r.turnQueues.Load(sessionID)
+	if !ok {
+		return nil, false
+	}
+	return value.(*turnQueue).push(messages...)
+}
+
+// openTurnQueue starts queuing the messages sent to a session while it runs.
+// It returns nil when another run of the session already queues them.
+func (r *LocalRuntime) openTurnQueue(sess *session.Session) *turnQueue {
+	if sess.IsSubSession() {
+		return nil
+	}
+	queue := newTurnQueue()
+	if _, loaded := r.turnQueues.LoadOrStore(sess.ID, queue); loaded {
+		return nil
+	}
+	return queue
+}
+
+// closeTurnQueue stops queuing the messages sent to a session and returns
+// those that were never answered.
+func (r *LocalRuntime) closeTurnQueue(sessionID string, queue *turnQueue) []*session.Message {
+	if queue == nil {
+		return nil
+	}
+	r.turnQueues.CompareAndDelete(sessionID, queue)
+	return queue.close()
+}
+
+// watchTurnQueue sends the pending messages of a session each time they
+// change during its run. The returned function stops watching and closes the
+// queue, warning about the messages that were never answered.
+func (r *LocalRuntime) watchTurnQueue(sess *session.Session, queue *turnQueue, agentName string, events chan Event) func() {
+	if queue == nil {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	// unsent is set when the messages changed since they were last sent
+	var unsent bool
+	wg.Go(func() {
+		for {
+			select {
+			case <-queue.queued:
+				unsent = true
+				select {
+				case events <- PendingMessages(sess.ID, queue.previews(), agentName):
+					unsent = false
+				case <-done:
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	})
+
+	return func() {
+		close(done)
+		wg.Wait()
+
+		select {
+		case <-queue.queued:
+			unsent = true
+		default:
+		}
+		if unsent {
+			events <- PendingMessages(sess.ID, queue.previews(), agentName)
+		}
+		if dropped := r.closeTurnQueue(sess.ID, queue); len(dropped) > 0 {
+			events <- Warning(fmt.Sprintf("%d queued messages were not answered: the run stopped before their turn.", len(dropped)), agentName)
+			events <- PendingMessages(sess.ID, nil, agentName)
+		}
+	}
+}
+
+// turnQueue holds the messages of the user sent to a session while one of
+// its turns is in progress.
+type turnQueue struct {
+	mu       sync.Mutex
+	messages []*session.Message
+	closed   bool
+
+	// queued is notified when the messages change
+	queued chan struct{}
+}
+
+func newTurnQueue() *turnQueue {
+	return &turnQueue{queued: make(chan struct{}, 1)}
+}
+
+func (q *turnQueue) push(messages ...*session.Message) ([]string, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return nil, false
+	}
+	q.messages = append(q.messages, messages...)
+	q.notify()
+	return q.previewsLocked(), true
+}
+
+// next pops the next message to answer. Once there is none, the queue is
+// closed: messages sent after are sent with a new run, rather than left
+// unanswered by a run that is stopping.
+func (q *turnQueue) next() *session.Message {
+	if q == nil {
+		return nil
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.messages) == 0 {
+		q.closed = true
+		return nil
+	}
+	msg := q.messages[0]
+	q.messages = q.messages[1:]
+	q.notify()
+	return msg
+}
+
+func (q *turnQueue) notify() {
+	select {
+	case q.queued <- struct{}{}:
+	default:
+	}
+}
+
+func (q *turnQueue) close() []*session.Message {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.closed = true
+	dropped := q.messages
+	q.messages = nil
+	return dropped
+}
+
+func (q *turnQueue) previews() []string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.previewsLocked()
+}
+
+func (q *turnQueue) previewsLocked() []string {
+	previews := make([]string, len(q.messages))
+	for i, msg := range q.messages {
+		content, _, _ := strings.Cut(strings.TrimSpace(msg.Message.Content), "\n")
+		previews[i] = content
+	}
+	return previews
+}
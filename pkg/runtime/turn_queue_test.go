@@ -0,0 +1,104 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/team"
+	"github.com/docker/cagent/pkg/tools"
+)
+
+func TestRunStream_AnswersQueuedMessagesInOrder(t *testing.T) {
+	t.Parallel()
+
+	prov := &queueProvider{id: "test/mock-model", streams: []chat.MessageStream{
+		newStreamBuilder().AddToolCallName("call_1", "ask_more").AddToolCallArguments("call_1", "{}").Build(),
+		newStreamBuilder().AddContent("First answer").AddStopWithUsage(3, 2).Build(),
+		newStreamBuilder().AddContent("Second answer").AddStopWithUsage(3, 2).Build(),
+		newStreamBuilder().AddContent("Third answer").AddStopWithUsage(3, 2).Build(),
+	}}
+
+	var rt *LocalRuntime
+	sess := session.New(session.WithUserMessage("Hi"), session.WithTitle("Unit Test"), session.WithToolsApproved(true))
+
+	// The messages are sent while the turn is in progress
+	askMore := tools.Tool{
+		Name:       "ask_more",
+		Parameters: map[string]any{"type": "object"},
+		Handler: func(context.Context, tools.ToolCall) (*tools.ToolCallResult, error) {
+			pending, ok := rt.QueueMessages(sess.ID, session.UserMessage("Second question"))
+			assert.True(t, ok)
+			assert.Equal(t, []string{"Second question"}, pending)
+
+			_, ok = rt.QueueMessages(sess.ID, session.UserMessage("Third question"))
+			assert.True(t, ok)
+			return tools.ResultSuccess("ok"), nil
+		},
+	}
+	root := agent.New("root", "You are a test agent",
+		agent.WithModel(prov),
+		agent.WithToolSets(newStubToolSet(nil, []tools.Tool{askMore}, nil)),
+	)
+
+	var err error
+	rt, err = New(team.New(team.WithAgents(root)), WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+
+	var events []Event
+	for ev := range rt.RunStream(t.Context(), sess) {
+		events = append(events, ev)
+	}
+
+	var conversation []string
+	for _, msg := range sess.GetAllMessages() {
+		if msg.Message.Role != chat.MessageRoleTool && msg.Message.Content != "" {
+			conversation = append(conversation, msg.Message.Content)
+		}
+	}
+	assert.Equal(t, []string{"Hi", "First answer", "Second question", "Second answer", "Third question", "Third answer"}, conversation)
+
+	var pending [][]string
+	for _, ev := range events {
+		if e, ok := ev.(*PendingMessagesEvent); ok {
+			pending = append(pending, e.Messages)
+		}
+	}
+	require.NotEmpty(t, pending)
+	assert.Empty(t, pending[len(pending)-1], "no message is left pending")
+
+	_, ok := rt.QueueMessages(sess.ID, session.UserMessage("Too late"))
+	assert.False(t, ok, "messages sent once the run is over start a new run")
+}
+
+func TestQueueMessages_NotRunning(t *testing.T) {
+	t.Parallel()
+
+	root := agent.New("root", "You are a test agent", agent.WithModel(&mockProvider{id: "test/mock-model", stream: &mockStream{}}))
+	rt, err := New(team.New(team.WithAgents(root)), WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+
+	pending, ok := rt.QueueMessages("unknown", session.UserMessage("Hello"))
+	assert.False(t, ok)
+	assert.Empty(t, pending)
+}
+
+func TestTurnQueue_ClosesOnceEmpty(t *testing.T) {
+	t.Parallel()
+
+	queue := newTurnQueue()
+	_, ok := queue.push(session.UserMessage("First line\nSecond line"))
+	require.True(t, ok)
+	assert.Equal(t, []string{"First line"}, queue.previews())
+
+	assert.NotNil(t, queue.next())
+	assert.Nil(t, queue.next())
+
+	_, ok = queue.push(session.UserMessage("After"))
+	assert.False(t, ok)
+}
@@ -0,0 +1,217 @@
+package runtime
+
+import (
+	"maps"
+	"sync"
+
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/session"
+)
+
+// UsageTotals is an aggregated view of the token usage of several sessions.
+type UsageTotals struct {
+	InputTokens  int64   `json:"input_tokens"`
+	OutputTokens int64   `json:"output_tokens"`
+	Cost         float64 `json:"cost"`
+	// Sessions is the number of sessions that reported usage.
+	Sessions int `json:"sessions"`
+	// ContextLength and ContextLimit are only set when a single session
+	// reported usage, the context window being per session.
+	ContextLength int64 `json:"context_length,omitempty"`
+	ContextLimit  int64 `json:"context_limit,omitempty"`
+}
+
+// Tokens returns the total number of input and output tokens.
+func (t UsageTotals) Tokens() int64 {
+	return t.InputTokens + t.OutputTokens
+}
+
+// ContextPercent returns how full the context window is, or false if
+// it's not known.
+func (t UsageTotals) ContextPercent() (float64, bool) {
+	if t.ContextLimit <= 0 {
+		return 0, false
+	}
+	return float64(t.ContextLength) / float64(t.ContextLimit) * 100, true
+}
+
+// UsageTracker aggregates the token usage events of sessions and their
+// sub-sessions. It's safe for concurrent use so that the TUI, the API server
+// and cost reports can share the same view of the usage.
+type UsageTracker struct {
+	mu          sync.RWMutex
+	sessions    map[string]Usage  // sessionID -> latest usage snapshot
+	agents      map[string]string // sessionID -> agent name
+	subscribers map[chan UsageTotals]struct{}
+}
+
+func NewUsageTracker() *UsageTracker {
+	return &UsageTracker{
+		sessions:    make(map[string]Usage),
+		agents:      make(map[string]string),
+		subscribers: make(map[chan UsageTotals]struct{}),
+	}
+}
+
+// Record stores the usage reported by an event. Usage events carry
+// cumulative totals so they replace the previous snapshot of their session.
+func (t *UsageTracker) Record(event *TokenUsageEvent) {
+	if event == nil || event.Usage == nil || event.SessionID == "" {
+		return
+	}
+
+	t.mu.Lock()
+	t.sessions[event.SessionID] = *event.Usage
+	if event.AgentName != "" {
+		t.agents[event.SessionID] = event.AgentName
+	}
+	t.mu.Unlock()
+
+	t.notify()
+}
+
+// Restore seeds the tracker with the persisted usage of a session.
+func (t *UsageTracker) Restore(sess *session.Session) {
+	if sess == nil || (sess.InputTokens == 0 && sess.OutputTokens == 0 && sess.Cost == 0) {
+		return
+	}
+
+	var messages []chat.Message
+	for _, msg := range sess.GetAllMessages() {
+		messages = append(messages, msg.Message)
+	}
+
+	t.mu.Lock()
+	t.sessions[sess.ID] = Usage{
+		InputTokens:  sess.InputTokens,
+		OutputTokens: sess.OutputTokens,
+		Cost:         sess.Cost,
+		Tools:        toolUsage(messages),
+	}
+	t.mu.Unlock()
+
+	t.notify()
+}
+
+// Reset forgets about every session.
+func (t *UsageTracker) Reset() {
+	t.mu.Lock()
+	clear(t.sessions)
+	clear(t.agents)
+	t.mu.Unlock()
+
+	t.notify()
+}
+
+// Session returns the latest usage reported for a session.
+func (t *UsageTracker) Session(sessionID string) (Usage, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	usage, ok := t.sessions[sessionID]
+	return usage, ok
+}
+
+// Sessions returns the latest usage of every session.
+func (t *UsageTracker) Sessions() map[string]Usage {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return maps.Clone(t.sessions)
+}
+
+// Agents returns the totals of the sessions of each agent.
+func (t *UsageTracker) Agents() map[string]UsageTotals {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	byAgent := make(map[string]map[string]Usage)
+	for sessionID, usage := range t.sessions {
+		agentName := t.agents[sessionID]
+		if byAgent[agentName] == nil {
+			byAgent[agentName] = make(map[string]Usage)
+		}
+		byAgent[agentName][sessionID] = usage
+	}
+
+	totals := make(map[string]UsageTotals, len(byAgent))
+	for agentName, sessions := range byAgent {
+		totals[agentName] = aggregateUsage(sessions)
+	}
+	return totals
+}
+
+// Totals returns the usage aggregated over every session.
+func (t *UsageTracker) Totals() UsageTotals {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return aggregateUsage(t.sessions)
+}
+
+// Tools returns how many tokens the results of each tool take in the
+// context of every session, the tools taking the most tokens first.
+func (t *UsageTracker) Tools() []ToolUsage {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var tools []ToolUsage
+	for _, usage := range t.sessions {
+		tools = append(tools, usage.Tools...)
+	}
+	return sumToolUsage(tools)
+}
+
+// Subscribe returns a channel receiving the new totals each time the usage
+// changes, and a function to unsubscribe. Slow subscribers only miss
+// intermediate totals, they always end up with the latest ones.
+func (t *UsageTracker) Subscribe() (<-chan UsageTotals, func()) {
+	ch := make(chan UsageTotals, 1)
+
+	t.mu.Lock()
+	t.subscribers[ch] = struct{}{}
+	t.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			t.mu.Lock()
+			delete(t.subscribers, ch)
+			t.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+func (t *UsageTracker) notify() {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	totals := aggregateUsage(t.sessions)
+	for ch := range t.subscribers {
+		// Replace any pending totals with the latest ones.
+		select {
+		case <-ch:
+		default:
+		}
+		select {
+		case ch <- totals:
+		default:
+		}
+	}
+}
+
+func aggregateUsage(sessions map[string]Usage) UsageTotals {
+	var totals UsageTotals
+	for _, usage := range sessions {
+		totals.InputTokens += usage.InputTokens
+		totals.OutputTokens += usage.OutputTokens
+		totals.Cost += usage.Cost
+		totals.Sessions++
+		if len(sessions) == 1 {
+			totals.ContextLength = usage.ContextLength
+			totals.ContextLimit = usage.ContextLimit
+		}
+	}
+	return totals
+}
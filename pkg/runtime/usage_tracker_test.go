@@ -0,0 +1,81 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/session"
+)
+
+func TestUsageTrackerAggregatesSessions(t *testing.T) {
+	t.Parallel()
+
+	usage := NewUsageTracker()
+	usage.Record(TokenUsage("root", "root", 100, 10, 110, 1000, 0.5).(*TokenUsageEvent))
+	usage.Record(TokenUsage("sub", "helper", 50, 5, 55, 1000, 0.25).(*TokenUsageEvent))
+	// Events carry cumulative totals and replace the previous snapshot.
+	usage.Record(TokenUsage("root", "root", 200, 20, 220, 1000, 1).(*TokenUsageEvent))
+
+	totals := usage.Totals()
+	assert.Equal(t, int64(250), totals.InputTokens)
+	assert.Equal(t, int64(25), totals.OutputTokens)
+	assert.Equal(t, int64(275), totals.Tokens())
+	assert.InDelta(t, 1.25, totals.Cost, 1e-9)
+	assert.Equal(t, 2, totals.Sessions)
+
+	// The context window is per session.
+	_, ok := totals.ContextPercent()
+	assert.False(t, ok)
+
+	agents := usage.Agents()
+	assert.Equal(t, int64(220), agents["root"].Tokens())
+	assert.Equal(t, int64(55), agents["helper"].Tokens())
+}
+
+func TestUsageTrackerContextPercent(t *testing.T) {
+	t.Parallel()
+
+	usage := NewUsageTracker()
+	usage.Record(TokenUsage("root", "root", 100, 150, 250, 1000, 0).(*TokenUsageEvent))
+
+	percent, ok := usage.Totals().ContextPercent()
+	require.True(t, ok)
+	assert.InDelta(t, 25, percent, 1e-9)
+}
+
+func TestUsageTrackerRestoreAndReset(t *testing.T) {
+	t.Parallel()
+
+	usage := NewUsageTracker()
+	usage.Restore(&session.Session{ID: "root", InputTokens: 10, OutputTokens: 5, Cost: 0.1})
+
+	got, ok := usage.Session("root")
+	require.True(t, ok)
+	assert.Equal(t, int64(10), got.InputTokens)
+
+	usage.Reset()
+	assert.Equal(t, UsageTotals{}, usage.Totals())
+}
+
+func TestUsageTrackerSubscribe(t *testing.T) {
+	t.Parallel()
+
+	usage := NewUsageTracker()
+	updates, unsubscribe := usage.Subscribe()
+
+	usage.Record(TokenUsage("root", "root", 1, 1, 2, 0, 0).(*TokenUsageEvent))
+	usage.Record(TokenUsage("root", "root", 3, 3, 6, 0, 0).(*TokenUsageEvent))
+
+	// Only the latest totals are kept for slow subscribers.
+	assert.Equal(t, int64(6), (<-updates).Tokens())
+
+	unsubscribe()
+	_, open := <-updates
+	assert.False(t, open)
+
+	// Recording after unsubscribing must not panic.
+	usage.Record(TokenUsage("root", "root", 4, 4, 8, 0, 0).(*TokenUsageEvent))
+	unsubscribe()
+}
@@ -0,0 +1,78 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/model/provider"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/tools"
+)
+
+// Warmer is implemented by runtimes that can prepare the first turn of a
+// session in the background, while the user types their first message.
+// Remote runtimes typically do not.
+type Warmer interface {
+	WarmUp(ctx context.Context, sess *session.Session)
+}
+
+// WarmUp prepares, concurrently, what the first turn of a session waits for:
+// the clients of the current agent's models and their definitions, and the
+// agent's tools, which starts its MCP servers. It then counts the tokens of
+// the agent's system prompt and tools. Failures are only logged, the turn
+// retries what failed.
+func (r *LocalRuntime) WarmUp(ctx context.Context, sess *session.Session) {
+	start := time.Now()
+	a := r.CurrentAgent()
+
+	var wg sync.WaitGroup
+	for _, model := range a.ConfiguredModels() {
+		wg.Go(func() {
+			if err := provider.Warm(ctx, model); err != nil {
+				slog.Debug("Failed to warm up model", "model", model.ID(), "error", err)
+			}
+			if _, err := r.modelsStore.GetModel(ctx, model.ID()); err != nil {
+				slog.Debug("Failed to get model definition", "model", model.ID(), "error", err)
+			}
+		})
+	}
+
+	var agentTools []tools.Tool
+	wg.Go(func() {
+		var err error
+		if agentTools, err = a.Tools(ctx); err != nil {
+			slog.Debug("Failed to list tools", "agent", a.Name(), "error", err)
+		}
+	})
+
+	wg.Wait()
+	if ctx.Err() != nil {
+		return
+	}
+
+	slog.Debug("Warmed up session",
+		"agent", a.Name(),
+		"session_id", sess.ID,
+		"system_prompt_tokens", systemPromptTokens(sess.SystemMessages(a), agentTools),
+		"duration", time.Since(start))
+}
+
+// systemPromptTokens estimates the number of tokens the system messages and
+// the definitions of the tools take in every request.
+func systemPromptTokens(messages []chat.Message, agentTools []tools.Tool) int {
+	var tokens int
+	for _, msg := range messages {
+		tokens += estimateTokens(msg.Content)
+	}
+	for _, tool := range agentTools {
+		tokens += estimateTokens(tool.Name + tool.Description)
+		if params, err := json.Marshal(tool.Parameters); err == nil {
+			tokens += estimateTokens(string(params))
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,42 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/team"
+	"github.com/docker/cagent/pkg/tools"
+)
+
+func TestWarmUpStartsToolSets(t *testing.T) {
+	t.Parallel()
+
+	toolSet := newStubToolSet(nil, []tools.Tool{{Name: "shell"}}, nil)
+	root := agent.New("root", "You are a test agent",
+		agent.WithModel(&mockProvider{id: "test/mock-model"}),
+		agent.WithToolSets(toolSet),
+	)
+	rt, err := New(team.New(team.WithAgents(root)), WithSessionCompaction(false), WithModelStore(mockModelStore{}))
+	require.NoError(t, err)
+
+	rt.WarmUp(t.Context(), session.New())
+
+	startable, ok := root.ToolSets()[0].(*agent.StartableToolSet)
+	require.True(t, ok)
+	assert.True(t, startable.IsStarted())
+}
+
+func TestSystemPromptTokens(t *testing.T) {
+	t.Parallel()
+
+	messages := []chat.Message{{Role: chat.MessageRoleSystem, Content: "12345678"}}
+	assert.Equal(t, 2, systemPromptTokens(messages, nil))
+
+	agentTools := []tools.Tool{{Name: "shell", Description: "Run a command", Parameters: map[string]any{"type": "object"}}}
+	assert.Greater(t, systemPromptTokens(messages, agentTools), 2, "tool definitions are sent with every request")
+}
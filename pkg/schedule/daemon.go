@@ -0,0 +1,127 @@
+package schedule
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Runner runs the agent of a schedule and returns the outcome of the run.
+type Runner func(ctx context.Context, s *Schedule) *Result
+
+// Daemon runs the schedules of a store when they are due.
+type Daemon struct {
+	store    Store
+	run      Runner
+	notifier *Notifier
+	now      func() time.Time
+
+	mu sync.Mutex
+	// running are the schedules with a run in progress, which aren't run
+	// again before it's over
+	running map[string]bool
+	wg      sync.WaitGroup
+}
+
+// NewDaemon creates a daemon running the schedules of a store with a runner,
+// and notifying their channels with a notifier.
+func NewDaemon(store Store, run Runner, notifier *Notifier) *Daemon {
+	return &Daemon{
+		store:    store,
+		run:      run,
+		notifier: notifier,
+		now:      time.Now,
+		running:  map[string]bool{},
+	}
+}
+
+// Run checks the schedules every minute and starts those that are due,
+// until ctx is done. The schedules added meanwhile are picked up. It returns
+// once the runs in progress are over.
+func (d *Daemon) Run(ctx context.Context) error {
+	defer d.wg.Wait()
+
+	for {
+		if err := d.runDue(ctx); err != nil {
+			slog.Error("Failed to list schedules", "error", err)
+		}
+
+		now := d.now()
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(now.Truncate(time.Minute).Add(time.Minute).Sub(now)):
+		}
+	}
+}
+
+// runDue starts the runs of the schedules that are due.
+func (d *Daemon) runDue(ctx context.Context) error {
+	schedules, err := d.store.ListSchedules(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := d.now()
+	for _, s := range schedules {
+		next, err := s.NextRun()
+		if err != nil {
+			slog.Warn("Invalid schedule", "id", s.ID, "error", err)
+			continue
+		}
+		if next.IsZero() || next.After(now) || !d.start(s.ID) {
+			continue
+		}
+
+		// Recorded before the run, so that a daemon restarted meanwhile
+		// doesn't run it again
+		if err := d.store.RecordRun(ctx, s.ID, now, "", ""); err != nil {
+			slog.Error("Failed to record the run of a schedule", "id", s.ID, "error", err)
+			d.finish(s.ID)
+			continue
+		}
+
+		d.wg.Go(func() {
+			defer d.finish(s.ID)
+			d.runSchedule(ctx, s, now)
+		})
+	}
+	return nil
+}
+
+func (d *Daemon) runSchedule(ctx context.Context, s *Schedule, at time.Time) {
+	slog.Info("Running schedule", "id", s.ID, "agent_file", s.AgentFile, "agent", s.Agent)
+
+	result := d.run(ctx, s)
+	result.ScheduleID = s.ID
+	if result.Status == StatusFailed {
+		slog.Error("Scheduled run failed", "id", s.ID, "session_id", result.SessionID, "error", result.Error)
+	} else {
+		slog.Info("Scheduled run succeeded", "id", s.ID, "session_id", result.SessionID)
+	}
+
+	if err := d.store.RecordRun(context.WithoutCancel(ctx), s.ID, at, result.SessionID, result.Error); err != nil {
+		slog.Error("Failed to record the run of a schedule", "id", s.ID, "error", err)
+	}
+	if err := d.notifier.Notify(context.WithoutCancel(ctx), s.Notify, result); err != nil {
+		slog.Warn("Failed to notify the result of a scheduled run", "id", s.ID, "error", err)
+	}
+}
+
+func (d *Daemon) start(id string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.running[id] {
+		return false
+	}
+	d.running[id] = true
+	return true
+}
+
+func (d *Daemon) finish(id string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.running, id)
+}
@@ -0,0 +1,127 @@
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStore(t *testing.T) *SQLiteStore {
+	t.Helper()
+
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "schedules.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+
+	return store
+}
+
+func TestSQLiteStore(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+	created := time.Date(2025, time.January, 15, 10, 30, 0, 0, time.UTC)
+	require.NoError(t, store.AddSchedule(t.Context(), &Schedule{
+		ID: "triage", Cron: "0 9 * * *", AgentFile: "/agents/triage.yaml", Agent: "root", Prompt: "Triage",
+		Notify: []string{Desktop, "https://example.com/hook"}, CreatedAt: created,
+	}))
+
+	sched, err := store.GetSchedule(t.Context(), "triage")
+	require.NoError(t, err)
+	assert.Equal(t, "/agents/triage.yaml", sched.AgentFile)
+	assert.Equal(t, []string{Desktop, "https://example.com/hook"}, sched.Notify)
+	assert.True(t, sched.CreatedAt.Equal(created))
+	assert.True(t, sched.LastRunAt.IsZero())
+
+	ran := created.Add(time.Hour)
+	require.NoError(t, store.RecordRun(t.Context(), "triage", ran, "session-1", ""))
+	schedules, err := store.ListSchedules(t.Context())
+	require.NoError(t, err)
+	require.Len(t, schedules, 1)
+	assert.True(t, schedules[0].LastRunAt.Equal(ran))
+	assert.Equal(t, "session-1", schedules[0].LastSessionID)
+
+	require.NoError(t, store.RemoveSchedule(t.Context(), "triage"))
+	assert.ErrorIs(t, store.RemoveSchedule(t.Context(), "triage"), ErrNotFound)
+	_, err = store.GetSchedule(t.Context(), "triage")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestSchedule_Validate(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, (&Schedule{Cron: "@daily", Notify: []string{Desktop, "http://localhost:8080/hook"}}).Validate())
+	assert.Error(t, (&Schedule{Cron: "every day"}).Validate())
+	assert.Error(t, (&Schedule{Cron: "@daily", Notify: []string{"slack"}}).Validate())
+}
+
+func TestDaemon_RunsDueSchedules(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var posted []Result
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var result Result
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&result))
+		mu.Lock()
+		posted = append(posted, result)
+		mu.Unlock()
+	}))
+	t.Cleanup(hook.Close)
+
+	store := newTestStore(t)
+	created := time.Date(2025, time.January, 15, 8, 0, 0, 0, time.Local)
+	require.NoError(t, store.AddSchedule(t.Context(), &Schedule{ID: "due", Cron: "0 9 * * *", Agent: "root", Prompt: "Triage", Notify: []string{hook.URL}, CreatedAt: created}))
+	require.NoError(t, store.AddSchedule(t.Context(), &Schedule{ID: "later", Cron: "0 18 * * *", Agent: "root", Prompt: "Report", CreatedAt: created}))
+
+	var runs []string
+	runner := func(_ context.Context, s *Schedule) *Result {
+		mu.Lock()
+		runs = append(runs, s.ID)
+		mu.Unlock()
+		return &Result{SessionID: "session-" + s.ID, Status: StatusSucceeded, Answer: "Done"}
+	}
+
+	daemon := NewDaemon(store, runner, NewNotifier())
+	now := time.Date(2025, time.January, 15, 9, 0, 30, 0, time.Local)
+	daemon.now = func() time.Time { return now }
+
+	require.NoError(t, daemon.runDue(t.Context()))
+	daemon.wg.Wait()
+	assert.Equal(t, []string{"due"}, runs)
+	require.Len(t, posted, 1)
+	assert.Equal(t, "due", posted[0].ScheduleID)
+	assert.Equal(t, "session-due", posted[0].SessionID)
+
+	sched, err := store.GetSchedule(t.Context(), "due")
+	require.NoError(t, err)
+	assert.True(t, sched.LastRunAt.Equal(now))
+	assert.Equal(t, "session-due", sched.LastSessionID)
+
+	// Not due again before the next day
+	require.NoError(t, daemon.runDue(t.Context()))
+	daemon.wg.Wait()
+	assert.Equal(t, []string{"due"}, runs)
+}
+
+func TestNotifier_Desktop(t *testing.T) {
+	t.Parallel()
+
+	var title, body string
+	n := &Notifier{desktop: func(t, b string) error {
+		title, body = t, b
+		return nil
+	}}
+
+	require.NoError(t, n.Notify(t.Context(), []string{Desktop}, &Result{AgentFile: "triage.yaml", Status: StatusFailed, Error: "boom"}))
+	assert.Equal(t, "Scheduled run of triage.yaml failed", title)
+	assert.Equal(t, "boom", body)
+}
@@ -0,0 +1,112 @@
+package schedule
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/docker/cagent/pkg/httpclient"
+	"github.com/docker/cagent/pkg/notifications"
+)
+
+// Statuses of a run
+const (
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// Result is the outcome of a run of a schedule, sent to the webhooks of the
+// schedule.
+type Result struct {
+	ScheduleID string    `json:"schedule_id"`
+	AgentFile  string    `json:"agent_file"`
+	Agent      string    `json:"agent"`
+	SessionID  string    `json:"session_id,omitempty"`
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+	Answer     string    `json:"answer,omitempty"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// Notifier notifies the channels of a schedule of the result of its runs.
+type Notifier struct {
+	client  *http.Client
+	desktop func(title, body string) error
+}
+
+// NewNotifier creates a notifier posting to webhooks and showing desktop
+// notifications.
+func NewNotifier() *Notifier {
+	return &Notifier{
+		client:  httpclient.NewHTTPClient(),
+		desktop: notifications.ShowDesktop,
+	}
+}
+
+// Notify notifies each channel of the result of a run. All the channels are
+// notified even if some fail.
+func (n *Notifier) Notify(ctx context.Context, channels []string, result *Result) error {
+	var errs []error
+	for _, channel := range channels {
+		var err error
+		if channel == Desktop {
+			err = n.desktop(notificationTitle(result), notificationBody(result))
+		} else {
+			err = n.post(ctx, channel, result)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("notifying %s: %w", channel, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (n *Notifier) post(ctx context.Context, url string, result *Result) error {
+	body, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func notificationTitle(result *Result) string {
+	if result.Status == StatusFailed {
+		return fmt.Sprintf("Scheduled run of %s failed", result.AgentFile)
+	}
+	return fmt.Sprintf("Scheduled run of %s is done", result.AgentFile)
+}
+
+// notificationBody is the start of the answer of the agent, or the error.
+func notificationBody(result *Result) string {
+	body := result.Answer
+	if result.Status == StatusFailed {
+		body = result.Error
+	}
+	if runes := []rune(body); len(runes) > 200 {
+		body = string(runes[:200]) + "…"
+	}
+	return body
+}
@@ -0,0 +1,102 @@
+// Package schedule runs agents on cron expressions, such as a triage agent
+// every morning or a report agent every week.
+//
+// Schedules are kept in a SQLite database, so that "cagent schedule add"
+// can add them while the daemon runs. The daemon runs each schedule when it
+// is due, keeps the conversation as a session, and notifies the channels of
+// the schedule of the result.
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+var (
+	ErrEmptyID  = errors.New("schedule ID cannot be empty")
+	ErrNotFound = errors.New("schedule not found")
+)
+
+// Desktop is the notification channel showing a desktop notification.
+const Desktop = "desktop"
+
+// Schedule is an agent run on a cron expression.
+type Schedule struct {
+	ID   string
+	Cron string
+
+	// AgentFile is the agent file or the registry reference of the agent,
+	// run from WorkingDir
+	AgentFile  string
+	Agent      string
+	Prompt     string
+	WorkingDir string
+	// AutoApprove approves all the tool calls. Otherwise, the tool calls
+	// requiring approval are rejected: nobody is there to approve them.
+	AutoApprove bool
+	// Notify are the channels notified of the result of each run: Desktop,
+	// or the URLs of webhooks
+	Notify []string
+
+	CreatedAt time.Time
+
+	// What happened on the last run
+	LastRunAt     time.Time
+	LastSessionID string
+	LastError     string
+}
+
+// Validate checks that the cron expression and the notification channels of
+// a schedule are valid.
+func (s *Schedule) Validate() error {
+	if _, err := ParseCron(s.Cron); err != nil {
+		return err
+	}
+	for _, channel := range s.Notify {
+		if err := validateChannel(channel); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NextRun returns when the schedule is next due after its last run, or
+// after it was created. A daemon that wasn't running when it was due runs it
+// once when it starts.
+func (s *Schedule) NextRun() (time.Time, error) {
+	cron, err := ParseCron(s.Cron)
+	if err != nil {
+		return time.Time{}, err
+	}
+	from := s.CreatedAt
+	if s.LastRunAt.After(from) {
+		from = s.LastRunAt
+	}
+	// The expression is in local time
+	return cron.Next(from.Local()), nil
+}
+
+func validateChannel(channel string) error {
+	if channel == Desktop {
+		return nil
+	}
+	u, err := url.Parse(channel)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid notification channel %q: must be %q or the URL of a webhook", channel, Desktop)
+	}
+	return nil
+}
+
+// Store persists schedules.
+type Store interface {
+	AddSchedule(ctx context.Context, s *Schedule) error
+	GetSchedule(ctx context.Context, id string) (*Schedule, error)
+	ListSchedules(ctx context.Context) ([]*Schedule, error)
+	RemoveSchedule(ctx context.Context, id string) error
+	// RecordRun records the last run of a schedule: when it was due, the
+	// session it ran in and why it failed, if it did.
+	RecordRun(ctx context.Context, id string, at time.Time, sessionID, runErr string) error
+}
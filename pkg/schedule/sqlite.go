@@ -0,0 +1,180 @@
+package schedule
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/docker/cagent/pkg/sqliteutil"
+)
+
+// SQLiteStore is a Store backed by a SQLite database, so that schedules
+// added by one process are run by the daemon.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore opens (or creates) a schedules database.
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	db, err := sqliteutil.OpenDB(path)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.ExecContext(context.Background(), `CREATE TABLE IF NOT EXISTS schedules (
+		id TEXT PRIMARY KEY,
+		cron TEXT,
+		agent_file TEXT,
+		agent TEXT,
+		prompt TEXT,
+		working_dir TEXT,
+		auto_approve BOOLEAN,
+		notify TEXT,
+		created_at TEXT,
+		last_run_at TEXT,
+		last_session_id TEXT,
+		last_error TEXT
+	)`)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &SQLiteStore{db: db}, nil
+}
+
+// Close closes the underlying database.
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+const scheduleColumns = "id, cron, agent_file, agent, prompt, working_dir, auto_approve, notify, created_at, last_run_at, last_session_id, last_error"
+
+func (s *SQLiteStore) AddSchedule(ctx context.Context, sched *Schedule) error {
+	if sched.ID == "" {
+		return ErrEmptyID
+	}
+
+	notify, err := json.Marshal(sched.Notify)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		"INSERT INTO schedules ("+scheduleColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		sched.ID, sched.Cron, sched.AgentFile, sched.Agent, sched.Prompt, sched.WorkingDir, sched.AutoApprove, string(notify),
+		formatTime(sched.CreatedAt), formatTime(sched.LastRunAt), sched.LastSessionID, sched.LastError)
+	return err
+}
+
+func (s *SQLiteStore) GetSchedule(ctx context.Context, id string) (*Schedule, error) {
+	if id == "" {
+		return nil, ErrEmptyID
+	}
+
+	row := s.db.QueryRowContext(ctx, "SELECT "+scheduleColumns+" FROM schedules WHERE id = ?", id)
+
+	sched, err := scanSchedule(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return sched, nil
+}
+
+func (s *SQLiteStore) ListSchedules(ctx context.Context) ([]*Schedule, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+scheduleColumns+" FROM schedules ORDER BY created_at")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schedules []*Schedule
+	for rows.Next() {
+		sched, err := scanSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, sched)
+	}
+
+	return schedules, rows.Err()
+}
+
+func (s *SQLiteStore) RemoveSchedule(ctx context.Context, id string) error {
+	if id == "" {
+		return ErrEmptyID
+	}
+
+	result, err := s.db.ExecContext(ctx, "DELETE FROM schedules WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *SQLiteStore) RecordRun(ctx context.Context, id string, at time.Time, sessionID, runErr string) error {
+	if id == "" {
+		return ErrEmptyID
+	}
+
+	_, err := s.db.ExecContext(ctx,
+		"UPDATE schedules SET last_run_at = ?, last_session_id = ?, last_error = ? WHERE id = ?",
+		formatTime(at), sessionID, runErr, id)
+	return err
+}
+
+func scanSchedule(scanner interface {
+	Scan(dest ...any) error
+},
+) (*Schedule, error) {
+	var sched Schedule
+	var notify, createdAt, lastRunAt string
+
+	if err := scanner.Scan(&sched.ID, &sched.Cron, &sched.AgentFile, &sched.Agent, &sched.Prompt, &sched.WorkingDir, &sched.AutoApprove,
+		&notify, &createdAt, &lastRunAt, &sched.LastSessionID, &sched.LastError); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(notify), &sched.Notify); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if sched.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, err
+	}
+	if sched.LastRunAt, err = parseTime(lastRunAt); err != nil {
+		return nil, err
+	}
+
+	return &sched, nil
+}
+
+// formatTime uses a fixed-width UTC format so that timestamps can be compared as strings.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format("2006-01-02T15:04:05.000000Z")
+}
+
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse("2006-01-02T15:04:05.000000Z", s)
+}
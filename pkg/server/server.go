@@ -4,11 +4,13 @@ import (
 	"cmp"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net"
 	"net/http"
 	"slices"
+	"strconv"
 	"time"
 
 	"github.com/labstack/echo/v4"
@@ -16,9 +18,18 @@ import (
 
 	"github.com/docker/cagent/pkg/api"
 	"github.com/docker/cagent/pkg/config"
+	"github.com/docker/cagent/pkg/review"
+	"github.com/docker/cagent/pkg/runtime"
 	"github.com/docker/cagent/pkg/session"
 )
 
+const (
+	// syncPollInterval is how often a streamed session transcript is checked for changes.
+	syncPollInterval = time.Second
+	// defaultSyncLimit is the default maximum number of transcript items sent at once.
+	defaultSyncLimit = 100
+)
+
 type Server struct {
 	e  *echo.Echo
 	sm *SessionManager
@@ -40,11 +51,19 @@ func New(ctx context.Context, sessionStore session.Store, runConfig *config.Runt
 	group.GET("/agents", s.getAgents)
 	// Get an agent by id
 	group.GET("/agents/:id", s.getAgentConfig)
+	// Describe what the team of an agent can do
+	group.GET("/agents/:id/capabilities", s.getAgentCapabilities)
 
 	// List all sessions
 	group.GET("/sessions", s.getSessions)
 	// Get a session by id
 	group.GET("/sessions/:id", s.getSession)
+	// Get the token usage of a session
+	group.GET("/sessions/:id/usage", s.getSessionUsage)
+	// Get the changes to a session transcript since a resume token
+	group.GET("/sessions/:id/sync", s.syncSession)
+	// Stream the changes to a session transcript since a resume token
+	group.GET("/sessions/:id/sync/stream", s.streamSessionSync)
 	// Resume a session by id
 	group.POST("/sessions/:id/resume", s.resumeSession)
 	// Toggle YOLO mode for a session
@@ -60,6 +79,12 @@ func New(ctx context.Context, sessionStore session.Store, runConfig *config.Runt
 	group.POST("/sessions/:id/agent/:agent/:agent_name", s.runAgent)
 	group.POST("/sessions/:id/elicitation", s.elicitation)
 
+	// List the tool calls waiting for review
+	group.GET("/reviews", s.getReviews)
+	// Approve or reject a tool call waiting for review
+	group.POST("/reviews/:id/approve", s.decideReview(review.StatusApproved))
+	group.POST("/reviews/:id/reject", s.decideReview(review.StatusRejected))
+
 	// Health check endpoint
 	group.GET("/ping", func(c echo.Context) error {
 		return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
@@ -141,6 +166,18 @@ func (s *Server) getAgentConfig(c echo.Context) error {
 	return echo.NewHTTPError(http.StatusNotFound)
 }
 
+func (s *Server) getAgentCapabilities(c echo.Context) error {
+	desc, err := s.sm.DescribeTeam(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("failed to describe agent: %v", err))
+	}
+
+	if c.QueryParam("format") == "markdown" {
+		return c.Blob(http.StatusOK, "text/markdown; charset=utf-8", []byte(desc.Markdown()))
+	}
+	return c.JSON(http.StatusOK, desc)
+}
+
 func (s *Server) getSessions(c echo.Context) error {
 	sessions, err := s.sm.GetSessions(c.Request().Context())
 	if err != nil {
@@ -195,6 +232,93 @@ func (s *Server) getSession(c echo.Context) error {
 	})
 }
 
+func (s *Server) getSessionUsage(c echo.Context) error {
+	usage, err := s.sm.SessionUsage(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("session not found: %v", err))
+	}
+
+	return c.JSON(http.StatusOK, usage)
+}
+
+func (s *Server) syncSession(c echo.Context) error {
+	limit, err := syncLimit(c)
+	if err != nil {
+		return err
+	}
+
+	sess, err := s.sm.GetSession(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("session not found: %v", err))
+	}
+
+	return c.JSON(http.StatusOK, sess.TranscriptDelta(c.QueryParam("token"), limit))
+}
+
+// streamSessionSync streams the changes to a session transcript as server-sent
+// events, starting from the client's resume token. A client that reconnects with
+// the token of the last event it received only gets what it missed.
+func (s *Server) streamSessionSync(c echo.Context) error {
+	limit, err := syncLimit(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	sessionID := c.Param("id")
+	if _, err := s.sm.GetSession(ctx, sessionID); err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("session not found: %v", err))
+	}
+
+	c.Response().Header().Set("Content-Type", "text/event-stream")
+	c.Response().Header().Set("Cache-Control", "no-cache")
+	c.Response().Header().Set("Connection", "keep-alive")
+	c.Response().WriteHeader(http.StatusOK)
+
+	token := c.QueryParam("token")
+	ticker := time.NewTicker(syncPollInterval)
+	defer ticker.Stop()
+	for {
+		sess, err := s.sm.GetSession(ctx, sessionID)
+		if err != nil {
+			return nil
+		}
+
+		delta := sess.TranscriptDelta(token, limit)
+		if delta.Reset || len(delta.Ops) > 0 {
+			data, err := json.Marshal(delta)
+			if err != nil {
+				return nil
+			}
+			fmt.Fprintf(c.Response(), "id: %s\ndata: %s\n\n", delta.Token, data)
+			c.Response().Flush()
+			token = delta.Token
+		}
+		if delta.More {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// syncLimit reads the maximum number of transcript items sent at once.
+func syncLimit(c echo.Context) (int, error) {
+	limit := c.QueryParam("limit")
+	if limit == "" {
+		return defaultSyncLimit, nil
+	}
+	n, err := strconv.Atoi(limit)
+	if err != nil || n <= 0 {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid limit: %q", limit))
+	}
+	return n, nil
+}
+
 func (s *Server) resumeSession(c echo.Context) error {
 	var req api.ResumeSessionRequest
 	if err := c.Bind(&req); err != nil {
@@ -246,12 +370,29 @@ func (s *Server) runAgent(c echo.Context) error {
 
 	slog.Debug("Running agent", "agent_filename", agentFilename, "session_id", sessionID, "current_agent", currentAgent)
 
+	// Low-bandwidth clients and bots subscribe to fewer events
+	level, err := runtime.ParseEventLevel(c.QueryParam("events"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	// Unattended runs, e.g. triggered by webhooks, queue the tool calls for review
+	unattended := false
+	if param := c.QueryParam("review"); param != "" {
+		if unattended, err = strconv.ParseBool(param); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid review parameter: %v", err))
+		}
+		if unattended && s.sm.runConfig.ReviewQueue == nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "the review queue is not enabled")
+		}
+	}
+
 	var messages []api.Message
 	if err := json.NewDecoder(c.Request().Body).Decode(&messages); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
 	}
 
-	streamChan, err := s.sm.RunSession(c.Request().Context(), sessionID, agentFilename, currentAgent, messages)
+	streamChan, err := s.sm.RunSession(c.Request().Context(), sessionID, agentFilename, currentAgent, messages, unattended)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("failed to run session: %v", err))
 	}
@@ -260,7 +401,7 @@ func (s *Server) runAgent(c echo.Context) error {
 	c.Response().Header().Set("Cache-Control", "no-cache")
 	c.Response().Header().Set("Connection", "keep-alive")
 	c.Response().WriteHeader(http.StatusOK)
-	for event := range streamChan {
+	for event := range runtime.FilterEvents(streamChan, level) {
 		data, err := json.Marshal(event)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("failed to marshal event: %v", err))
@@ -285,3 +426,67 @@ func (s *Server) elicitation(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, nil)
 }
+
+// reviewQueue returns the review queue, or an error if it's not enabled.
+func (s *Server) reviewQueue() (*review.Queue, error) {
+	if s.sm.runConfig.ReviewQueue == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "the review queue is not enabled")
+	}
+	return s.sm.runConfig.ReviewQueue, nil
+}
+
+func (s *Server) getReviews(c echo.Context) error {
+	q, err := s.reviewQueue()
+	if err != nil {
+		return err
+	}
+
+	status := review.StatusPending
+	if all, _ := strconv.ParseBool(c.QueryParam("all")); all {
+		status = ""
+	}
+
+	requests, err := q.List(c.Request().Context(), status)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("failed to list review requests: %v", err))
+	}
+
+	responses := make([]api.ReviewRequest, 0, len(requests))
+	for _, req := range requests {
+		responses = append(responses, api.ReviewRequest{
+			ID:        req.ID,
+			SessionID: req.SessionID,
+			AgentName: req.AgentName,
+			ToolName:  req.ToolName,
+			Arguments: req.Arguments,
+			Status:    string(req.Status),
+			CreatedAt: req.CreatedAt,
+			ExpiresAt: req.ExpiresAt,
+			DecidedAt: req.DecidedAt,
+		})
+	}
+
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (s *Server) decideReview(status review.Status) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		q, err := s.reviewQueue()
+		if err != nil {
+			return err
+		}
+
+		if err := q.Decide(c.Request().Context(), c.Param("id"), status); err != nil {
+			switch {
+			case errors.Is(err, review.ErrNotFound):
+				return echo.NewHTTPError(http.StatusNotFound, err.Error())
+			case errors.Is(err, review.ErrAlreadyDecided):
+				return echo.NewHTTPError(http.StatusConflict, err.Error())
+			default:
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("failed to decide review request: %v", err))
+			}
+		}
+
+		return c.JSON(http.StatusOK, map[string]string{"status": string(status)})
+	}
+}
@@ -17,7 +17,10 @@ import (
 
 	"github.com/docker/cagent/pkg/api"
 	"github.com/docker/cagent/pkg/config"
+	"github.com/docker/cagent/pkg/review"
+	"github.com/docker/cagent/pkg/runtime"
 	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/tools"
 )
 
 func TestServer_ListAgents(t *testing.T) {
@@ -55,6 +58,32 @@ func TestServer_EmptyList(t *testing.T) {
 	assert.Equal(t, "[]\n", string(buf)) // We don't want null, but an empty array
 }
 
+func TestServer_AgentCapabilities(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "dummy")
+	t.Setenv("ANTHROPIC_API_KEY", "dummy")
+
+	ctx := t.Context()
+	lnPath := startServer(t, ctx, prepareAgentsDir(t, "multi_agents.yaml"))
+
+	var agents []api.Agent
+	unmarshal(t, httpGET(t, ctx, lnPath, "/api/agents"), &agents)
+	require.Len(t, agents, 1)
+
+	var desc runtime.TeamDescription
+	unmarshal(t, httpGET(t, ctx, lnPath, "/api/agents/"+agents[0].Name+"/capabilities"), &desc)
+
+	assert.Equal(t, "root", desc.DefaultAgent)
+	require.Len(t, desc.Agents, 3)
+	assert.Equal(t, "root", desc.Agents[0].Name)
+	assert.Equal(t, "Multi Agent", desc.Agents[0].Description)
+	assert.Equal(t, "openai/gpt-4o", desc.Agents[0].Model)
+	assert.Equal(t, []string{"contradict", "pirate"}, desc.Agents[0].SubAgents)
+
+	markdown := string(httpGET(t, ctx, lnPath, "/api/agents/"+agents[0].Name+"/capabilities?format=markdown"))
+	assert.Contains(t, markdown, "## root (default)")
+	assert.Contains(t, markdown, "- **Delegates to:** contradict, pirate")
+}
+
 func TestServer_ListSessions(t *testing.T) {
 	t.Parallel()
 
@@ -69,6 +98,44 @@ func TestServer_ListSessions(t *testing.T) {
 	assert.Empty(t, sessions)
 }
 
+func TestServer_Reviews(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	store, err := review.NewSQLiteStore(filepath.Join(t.TempDir(), "review.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+	queue := review.NewQueue(store)
+
+	var runConfig config.RuntimeConfig
+	runConfig.ReviewQueue = queue
+	lnPath := startServerWithConfig(t, ctx, prepareAgentsDir(t), &runConfig)
+
+	shell, err := queue.Submit(ctx, "session-1", "root", tools.ToolCall{Function: tools.FunctionCall{Name: "shell", Arguments: `{"cmd":"ls"}`}})
+	require.NoError(t, err)
+	_, err = queue.Submit(ctx, "session-1", "root", tools.ToolCall{Function: tools.FunctionCall{Name: "write_file"}})
+	require.NoError(t, err)
+
+	var pending []api.ReviewRequest
+	unmarshal(t, httpGET(t, ctx, lnPath, "/api/reviews"), &pending)
+	require.Len(t, pending, 2)
+
+	httpDo(t, ctx, http.MethodPost, lnPath, "/api/reviews/"+shell.ID+"/approve", nil)
+
+	pending = nil
+	unmarshal(t, httpGET(t, ctx, lnPath, "/api/reviews"), &pending)
+	require.Len(t, pending, 1)
+	assert.Equal(t, "write_file", pending[0].ToolName)
+
+	var all []api.ReviewRequest
+	unmarshal(t, httpGET(t, ctx, lnPath, "/api/reviews?all=true"), &all)
+	require.Len(t, all, 2)
+
+	status, err := queue.Wait(ctx, shell.ID)
+	require.NoError(t, err)
+	assert.Equal(t, review.StatusApproved, status)
+}
+
 func prepareAgentsDir(t *testing.T, testFiles ...string) string {
 	t.Helper()
 
@@ -89,13 +156,17 @@ func prepareAgentsDir(t *testing.T, testFiles ...string) string {
 
 func startServer(t *testing.T, ctx context.Context, agentsDir string) string {
 	t.Helper()
+	return startServerWithConfig(t, ctx, agentsDir, &config.RuntimeConfig{})
+}
+
+func startServerWithConfig(t *testing.T, ctx context.Context, agentsDir string, runConfig *config.RuntimeConfig) string {
+	t.Helper()
 
 	var store mockStore
-	runConfig := config.RuntimeConfig{}
 
 	sources, err := config.ResolveSources(agentsDir)
 	require.NoError(t, err)
-	srv, err := New(ctx, store, &runConfig, 0, sources)
+	srv, err := New(ctx, store, runConfig, 0, sources)
 	require.NoError(t, err)
 
 	socketPath := "unix://" + filepath.Join(t.TempDir(), "sock")
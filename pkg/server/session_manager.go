@@ -14,6 +14,8 @@ import (
 	"github.com/docker/cagent/pkg/api"
 	"github.com/docker/cagent/pkg/concurrent"
 	"github.com/docker/cagent/pkg/config"
+	"github.com/docker/cagent/pkg/policy"
+	"github.com/docker/cagent/pkg/review"
 	"github.com/docker/cagent/pkg/runtime"
 	"github.com/docker/cagent/pkg/session"
 	"github.com/docker/cagent/pkg/team"
@@ -24,6 +26,7 @@ import (
 type activeRuntimes struct {
 	runtime runtime.Runtime
 	cancel  context.CancelFunc
+	usage   *runtime.UsageTracker
 }
 
 // SessionManager manages sessions for HTTP and Connect-RPC servers.
@@ -38,6 +41,9 @@ type SessionManager struct {
 
 	refreshInterval time.Duration
 
+	// warm keeps runtimes ready for the new sessions
+	warm *warmPool
+
 	mux sync.Mutex
 }
 
@@ -56,6 +62,13 @@ func NewSessionManager(ctx context.Context, sources config.Sources, sessionStore
 		runConfig:       runConfig,
 	}
 
+	sm.warm = newWarmPool(ctx, runConfig.WarmSessions, sm.startWarmRuntime)
+	if runConfig.WarmSessions > 0 {
+		for name := range loaders {
+			sm.warm.fill(warmKey{agentFilename: name, currentAgent: "root"})
+		}
+	}
+
 	return sm
 }
 
@@ -74,6 +87,7 @@ func (sm *SessionManager) CreateSession(ctx context.Context, sessionTemplate *se
 	opts = append(opts,
 		session.WithMaxIterations(sessionTemplate.MaxIterations),
 		session.WithToolsApproved(sessionTemplate.ToolsApproved),
+		session.WithTimeLimit(sessionTemplate.TimeLimit),
 	)
 
 	if wd := strings.TrimSpace(sessionTemplate.WorkingDir); wd != "" {
@@ -130,9 +144,24 @@ func (sm *SessionManager) DeleteSession(ctx context.Context, sessionID string) e
 }
 
 // RunSession runs a session with the given messages.
-func (sm *SessionManager) RunSession(ctx context.Context, sessionID, agentFilename, currentAgent string, messages []api.Message) (<-chan runtime.Event, error) {
+//
+// Unattended runs, such as the ones triggered by webhooks, send the tool calls
+// requiring approval to the review queue instead of waiting for the client.
+// They keep running once the client is gone.
+func (sm *SessionManager) RunSession(ctx context.Context, sessionID, agentFilename, currentAgent string, messages []api.Message, unattended bool) (<-chan runtime.Event, error) {
+	if unattended && sm.runConfig.ReviewQueue == nil {
+		return nil, errors.New("unattended runs require the review queue")
+	}
+
 	sm.mux.Lock()
 	defer sm.mux.Unlock()
+
+	// The messages sent while the session runs are answered once its turn
+	// is over, by the same run
+	if streamChan, queued := sm.queueMessages(sessionID, messages); queued {
+		return streamChan, nil
+	}
+
 	sess, err := sm.sessionStore.GetSession(ctx, sessionID)
 	if err != nil {
 		return nil, err
@@ -149,7 +178,11 @@ func (sm *SessionManager) RunSession(ctx context.Context, sessionID, agentFilena
 	}
 
 	runtimeSession, exists := sm.runtimeSessions.Load(sessionID)
-	streamCtx, cancel := context.WithCancel(ctx)
+	runCtx := ctx
+	if unattended {
+		runCtx = context.WithoutCancel(ctx)
+	}
+	streamCtx, cancel := context.WithCancel(runCtx)
 	if !exists {
 		rt, err := sm.runtimeForSession(ctx, sess, agentFilename, currentAgent, rc)
 		if err != nil {
@@ -159,7 +192,9 @@ func (sm *SessionManager) RunSession(ctx context.Context, sessionID, agentFilena
 		runtimeSession = &activeRuntimes{
 			runtime: rt,
 			cancel:  cancel,
+			usage:   runtime.NewUsageTracker(),
 		}
+		runtimeSession.usage.Restore(sess)
 		sm.runtimeSessions.Store(sessionID, runtimeSession)
 	}
 
@@ -173,10 +208,22 @@ func (sm *SessionManager) RunSession(ctx context.Context, sessionID, agentFilena
 			if streamCtx.Err() != nil {
 				return
 			}
-			streamChan <- event
+			if usage, ok := event.(*runtime.TokenUsageEvent); ok {
+				runtimeSession.usage.Record(usage)
+			}
+
+			// The events of unattended runs are dropped once the client is gone
+			select {
+			case streamChan <- event:
+			case <-ctx.Done():
+			}
+
+			if confirmation, ok := event.(*runtime.ToolCallConfirmationEvent); ok && unattended {
+				sm.reviewToolCall(streamCtx, runtimeSession.runtime, sess, confirmation, cancel)
+			}
 		}
 
-		if err := sm.sessionStore.UpdateSession(ctx, sess); err != nil {
+		if err := sm.sessionStore.UpdateSession(context.WithoutCancel(ctx), sess); err != nil {
 			return
 		}
 	}()
@@ -184,6 +231,80 @@ func (sm *SessionManager) RunSession(ctx context.Context, sessionID, agentFilena
 	return streamChan, nil
 }
 
+// reviewToolCall submits a tool call of an unattended run to the review queue,
+// and resumes the run once a human decided. The run is stopped if the request
+// expires and the queue aborts the runs on expiry.
+func (sm *SessionManager) reviewToolCall(ctx context.Context, rt runtime.Runtime, sess *session.Session, e *runtime.ToolCallConfirmationEvent, cancel context.CancelFunc) {
+	q := sm.runConfig.ReviewQueue
+	req, err := q.Submit(ctx, sess.ID, e.AgentName, e.ToolCall)
+	if err != nil {
+		slog.Error("Failed to submit tool call for review", "tool", e.ToolCall.Function.Name, "error", err)
+		rt.Resume(ctx, runtime.ResumeTypeReject)
+		return
+	}
+
+	slog.Info("Tool call waiting for review", "review_id", req.ID, "tool", req.ToolName, "session_id", sess.ID)
+	status, err := q.Wait(ctx, req.ID)
+	switch {
+	case ctx.Err() != nil:
+	case err != nil:
+		slog.Error("Failed to wait for review", "review_id", req.ID, "error", err)
+		rt.Resume(ctx, runtime.ResumeTypeReject)
+	case status == review.StatusApproved:
+		rt.Resume(ctx, runtime.ResumeTypeApprove)
+	case status == review.StatusExpired && q.ExpiryPolicy() == review.ExpiryAbort:
+		slog.Warn("Review request expired, aborting run", "review_id", req.ID, "session_id", sess.ID)
+		cancel()
+	default:
+		rt.Resume(ctx, runtime.ResumeTypeReject)
+	}
+}
+
+// queueMessages queues messages for a session that is running. The returned
+// stream only tells which messages are pending: the run of the session
+// answers them.
+func (sm *SessionManager) queueMessages(sessionID string, messages []api.Message) (<-chan runtime.Event, bool) {
+	runtimeSession, exists := sm.runtimeSessions.Load(sessionID)
+	if !exists {
+		return nil, false
+	}
+	queuer, ok := runtimeSession.runtime.(runtime.MessageQueuer)
+	if !ok {
+		return nil, false
+	}
+
+	queued := make([]*session.Message, len(messages))
+	for i, msg := range messages {
+		queued[i] = session.UserMessage(msg.Content, msg.MultiContent...)
+	}
+	pending, ok := queuer.QueueMessages(sessionID, queued...)
+	if !ok {
+		return nil, false
+	}
+
+	streamChan := make(chan runtime.Event, 1)
+	streamChan <- runtime.PendingMessages(sessionID, pending, "")
+	close(streamChan)
+	return streamChan, true
+}
+
+// SessionUsage returns the token usage of a session and its sub-sessions.
+// The usage of sessions that are not running is read from the session store.
+func (sm *SessionManager) SessionUsage(ctx context.Context, sessionID string) (runtime.UsageTotals, error) {
+	if runtimeSession, exists := sm.runtimeSessions.Load(sessionID); exists {
+		return runtimeSession.usage.Totals(), nil
+	}
+
+	sess, err := sm.sessionStore.GetSession(ctx, sessionID)
+	if err != nil {
+		return runtime.UsageTotals{}, err
+	}
+
+	usage := runtime.NewUsageTracker()
+	usage.Restore(sess)
+	return usage.Totals(), nil
+}
+
 // ResumeSession resumes a paused session.
 func (sm *SessionManager) ResumeSession(ctx context.Context, sessionID, confirmation string) error {
 	sm.mux.Lock()
@@ -243,34 +364,110 @@ func (sm *SessionManager) runtimeForSession(ctx context.Context, sess *session.S
 		return rt.runtime, nil
 	}
 
-	t, err := sm.loadTeam(ctx, agentFilename, rc)
+	// Warm runtimes run in the working directory of the server
+	if rc.WorkingDir == "" {
+		if w, ok := sm.warm.claim(warmKey{agentFilename: agentFilename, currentAgent: currentAgent}); ok {
+			sess.MaxIterations = w.maxIterations
+			sm.runtimeSessions.Store(sess.ID, &activeRuntimes{
+				runtime: w.runtime,
+				usage:   runtime.NewUsageTracker(),
+			})
+			slog.Debug("Warm runtime claimed for session", "session_id", sess.ID)
+			return w.runtime, nil
+		}
+	}
+
+	w, err := sm.newRuntime(ctx, agentFilename, currentAgent, rc)
 	if err != nil {
 		return nil, err
 	}
+	sess.MaxIterations = w.maxIterations
+
+	sm.runtimeSessions.Store(sess.ID, &activeRuntimes{
+		runtime: w.runtime,
+		usage:   runtime.NewUsageTracker(),
+	})
+
+	slog.Debug("Runtime created for session", "session_id", sess.ID)
+
+	return w.runtime, nil
+}
+
+// startWarmRuntime creates a runtime for the pool of warm runtimes, and starts
+// the toolsets of its agents for the first session not to wait for them.
+func (sm *SessionManager) startWarmRuntime(ctx context.Context, key warmKey) (warmRuntime, error) {
+	rc := sm.runConfig.Clone()
+	rc.WorkingDir = ""
+
+	w, err := sm.newRuntime(ctx, key.agentFilename, key.currentAgent, rc)
+	if err != nil {
+		return warmRuntime{}, err
+	}
+
+	for _, name := range w.team.AgentNames() {
+		a, err := w.team.Agent(name)
+		if err != nil {
+			continue
+		}
+		if _, err := a.Tools(ctx); err != nil {
+			slog.Warn("Failed to list the tools of a warm runtime", "agent", name, "error", err)
+		}
+	}
+
+	return w, nil
+}
+
+func (sm *SessionManager) newRuntime(ctx context.Context, agentFilename, currentAgent string, rc *config.RuntimeConfig) (warmRuntime, error) {
+	t, err := sm.loadTeam(ctx, agentFilename, rc)
+	if err != nil {
+		return warmRuntime{}, err
+	}
 
 	agent, err := t.Agent(currentAgent)
 	if err != nil {
-		return nil, err
+		return warmRuntime{}, err
 	}
-	sess.MaxIterations = agent.MaxIterations()
 
 	opts := []runtime.Opt{
 		runtime.WithCurrentAgent(currentAgent),
 		runtime.WithManagedOAuth(false),
 		runtime.WithSessionStore(sm.sessionStore),
 	}
+	if len(rc.PolicyFiles) > 0 {
+		engine, err := policy.NewRegoEngine(ctx, rc.PolicyFiles)
+		if err != nil {
+			return warmRuntime{}, err
+		}
+		opts = append(opts, runtime.WithPolicyEngine(engine))
+	}
+	if rc.AuditRecorder != nil {
+		opts = append(opts, runtime.WithAuditRecorder(rc.AuditRecorder))
+	}
+	if rc.DedupWindow > 0 {
+		opts = append(opts, runtime.WithDeduplication(rc.DedupWindow))
+	}
 	run, err := runtime.New(t, opts...)
 	if err != nil {
-		return nil, err
+		return warmRuntime{}, err
 	}
 
-	sm.runtimeSessions.Store(sess.ID, &activeRuntimes{
-		runtime: run,
-	})
+	return warmRuntime{runtime: run, team: t, maxIterations: agent.MaxIterations()}, nil
+}
 
-	slog.Debug("Runtime created for session", "session_id", sess.ID)
+// DescribeTeam describes what the team of an agent file can do. The team is
+// loaded and its toolsets started, to list the tools they actually provide.
+func (sm *SessionManager) DescribeTeam(ctx context.Context, agentFilename string) (runtime.TeamDescription, error) {
+	t, err := sm.loadTeam(ctx, agentFilename, sm.runConfig)
+	if err != nil {
+		return runtime.TeamDescription{}, err
+	}
+	defer func() {
+		if err := t.StopToolSets(ctx); err != nil {
+			slog.Error("Failed to stop tool sets", "error", err)
+		}
+	}()
 
-	return run, nil
+	return runtime.DescribeTeam(ctx, t), nil
 }
 
 func (sm *SessionManager) loadTeam(ctx context.Context, agentFilename string, runConfig *config.RuntimeConfig) (*team.Team, error) {
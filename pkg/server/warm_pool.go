@@ -0,0 +1,138 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/docker/cagent/pkg/runtime"
+	"github.com/docker/cagent/pkg/team"
+)
+
+// warmKey identifies the runtimes that can be handed to a session: the same
+// agent file, starting with the same agent.
+type warmKey struct {
+	agentFilename string
+	currentAgent  string
+}
+
+// warmRuntime is a runtime ready to run a session: its team is loaded, with
+// the model providers created, and the toolsets of every agent are started.
+type warmRuntime struct {
+	runtime       runtime.Runtime
+	team          *team.Team
+	maxIterations int
+}
+
+// warmPool keeps a few runtimes ready for each agent, so new sessions don't
+// wait for MCP servers to start. A claimed runtime is replaced in the
+// background.
+type warmPool struct {
+	ctx   context.Context
+	size  int
+	start func(ctx context.Context, key warmKey) (warmRuntime, error)
+
+	mu      sync.Mutex
+	ready   map[warmKey][]warmRuntime
+	warming map[warmKey]int
+}
+
+func newWarmPool(ctx context.Context, size int, start func(ctx context.Context, key warmKey) (warmRuntime, error)) *warmPool {
+	p := &warmPool{
+		ctx:     ctx,
+		size:    size,
+		start:   start,
+		ready:   make(map[warmKey][]warmRuntime),
+		warming: make(map[warmKey]int),
+	}
+
+	if size > 0 {
+		go func() {
+			<-ctx.Done()
+			p.close()
+		}()
+	}
+
+	return p
+}
+
+// fill starts warming runtimes until the pool of an agent is full.
+func (p *warmPool) fill(key warmKey) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.fillLocked(key)
+}
+
+func (p *warmPool) fillLocked(key warmKey) {
+	if p.ctx.Err() != nil {
+		return
+	}
+	for range p.size - len(p.ready[key]) - p.warming[key] {
+		p.warming[key]++
+		go p.warm(key)
+	}
+}
+
+func (p *warmPool) warm(key warmKey) {
+	w, err := p.start(p.ctx, key)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.warming[key]--
+
+	if err != nil {
+		// Not retried, the next claim tries again
+		slog.Warn("Failed to warm a runtime", "agent", key.agentFilename, "current_agent", key.currentAgent, "error", err)
+		return
+	}
+	if p.ctx.Err() != nil {
+		stopWarmRuntime(w)
+		return
+	}
+
+	p.ready[key] = append(p.ready[key], w)
+	slog.Debug("Runtime warmed", "agent", key.agentFilename, "current_agent", key.currentAgent, "ready", len(p.ready[key]))
+}
+
+// claim takes a ready runtime of an agent, if there's one, and warms another
+// one to replace it.
+func (p *warmPool) claim(key warmKey) (warmRuntime, bool) {
+	if p.size == 0 {
+		return warmRuntime{}, false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	defer p.fillLocked(key)
+
+	ready := p.ready[key]
+	if len(ready) == 0 {
+		return warmRuntime{}, false
+	}
+	w := ready[0]
+	p.ready[key] = ready[1:]
+	return w, true
+}
+
+// close stops the toolsets of the runtimes that were never claimed.
+func (p *warmPool) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for key, ready := range p.ready {
+		for _, w := range ready {
+			stopWarmRuntime(w)
+		}
+		delete(p.ready, key)
+	}
+}
+
+func stopWarmRuntime(w warmRuntime) {
+	if w.team == nil {
+		return
+	}
+	// The context of the pool is done by now
+	if err := w.team.StopToolSets(context.Background()); err != nil {
+		slog.Error("Failed to stop tool sets", "error", err)
+	}
+}
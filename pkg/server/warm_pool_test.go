@@ -0,0 +1,80 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWarmPool(t *testing.T) {
+	t.Parallel()
+
+	var started atomic.Int32
+	pool := newWarmPool(t.Context(), 2, func(context.Context, warmKey) (warmRuntime, error) {
+		started.Add(1)
+		return warmRuntime{maxIterations: 10}, nil
+	})
+	key := warmKey{agentFilename: "pirate.yaml", currentAgent: "root"}
+
+	// Nothing is ready before the pool is filled, claiming fills it
+	_, ok := pool.claim(key)
+	assert.False(t, ok)
+	assert.Eventually(t, func() bool { return readyRuntimes(pool, key) == 2 }, time.Second, time.Millisecond)
+
+	w, ok := pool.claim(key)
+	assert.True(t, ok)
+	assert.Equal(t, 10, w.maxIterations)
+
+	// The claimed runtime is replaced
+	assert.Eventually(t, func() bool { return readyRuntimes(pool, key) == 2 }, time.Second, time.Millisecond)
+	assert.Equal(t, int32(3), started.Load())
+
+	// Other agents have their own runtimes
+	_, ok = pool.claim(warmKey{agentFilename: "pirate.yaml", currentAgent: "parrot"})
+	assert.False(t, ok)
+}
+
+func TestWarmPool_Disabled(t *testing.T) {
+	t.Parallel()
+
+	pool := newWarmPool(t.Context(), 0, func(context.Context, warmKey) (warmRuntime, error) {
+		t.Fatal("no runtime should be warmed")
+		return warmRuntime{}, nil
+	})
+	key := warmKey{agentFilename: "pirate.yaml", currentAgent: "root"}
+
+	pool.fill(key)
+	_, ok := pool.claim(key)
+	assert.False(t, ok)
+}
+
+func TestWarmPool_FailuresAreNotRetried(t *testing.T) {
+	t.Parallel()
+
+	var started atomic.Int32
+	pool := newWarmPool(t.Context(), 1, func(context.Context, warmKey) (warmRuntime, error) {
+		started.Add(1)
+		return warmRuntime{}, errors.New("boom")
+	})
+	key := warmKey{agentFilename: "pirate.yaml", currentAgent: "root"}
+
+	pool.fill(key)
+	assert.Eventually(t, func() bool { return warmingRuntimes(pool, key) == 0 && started.Load() == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, 0, readyRuntimes(pool, key))
+}
+
+func readyRuntimes(p *warmPool, key warmKey) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.ready[key])
+}
+
+func warmingRuntimes(p *warmPool, key warmKey) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.warming[key]
+}
@@ -0,0 +1,97 @@
+package session
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/todo"
+)
+
+// Checkpoint is the state of a session when the user sent a message: the
+// conversation before the message, the todos and the usage. The session can
+// be rewound to it.
+type Checkpoint struct {
+	// Items is the number of items of the session before the message
+	Items        int         `json:"items"`
+	Prompt       string      `json:"prompt"`
+	Todos        []todo.Todo `json:"todos,omitempty"`
+	InputTokens  int64       `json:"input_tokens"`
+	OutputTokens int64       `json:"output_tokens"`
+	Cost         float64     `json:"cost"`
+	CreatedAt    time.Time   `json:"created_at"`
+}
+
+// RecordCheckpoint records a checkpoint before the last message of the
+// session, when it's a message the user sent that has no checkpoint yet. It
+// returns true if the checkpoint was recorded.
+func (s *Session) RecordCheckpoint() bool {
+	n := len(s.Messages)
+	if n == 0 || !s.Messages[n-1].IsMessage() {
+		return false
+	}
+	last := s.Messages[n-1].Message
+	if last.Message.Role != chat.MessageRoleUser || last.Implicit {
+		return false
+	}
+	if c := len(s.Checkpoints); c > 0 && s.Checkpoints[c-1].Items >= n-1 {
+		return false
+	}
+
+	s.Checkpoints = append(s.Checkpoints, Checkpoint{
+		Items:        n - 1,
+		Prompt:       last.Message.Content,
+		Todos:        slices.Clone(s.Todos),
+		InputTokens:  s.InputTokens,
+		OutputTokens: s.OutputTokens,
+		Cost:         s.Cost,
+		CreatedAt:    time.Now(),
+	})
+	return true
+}
+
+// Fork returns a new session with the state of this one at a checkpoint, to
+// take the conversation another way from there. This session isn't changed.
+func (s *Session) Fork(checkpoint int) (*Session, error) {
+	if checkpoint < 0 || checkpoint >= len(s.Checkpoints) {
+		return nil, fmt.Errorf("checkpoint %d not found in session %s", checkpoint, s.ID)
+	}
+	cp := s.Checkpoints[checkpoint]
+	if cp.Items > len(s.Messages) {
+		return nil, fmt.Errorf("checkpoint %d doesn't match the messages of session %s", checkpoint, s.ID)
+	}
+
+	items := make([]Item, cp.Items)
+	for i, item := range s.Messages[:cp.Items] {
+		if item.IsMessage() {
+			msg := *item.Message
+			item.Message = &msg
+		}
+		items[i] = item
+	}
+
+	return &Session{
+		ID:                  uuid.New().String(),
+		Title:               s.Title,
+		Messages:            items,
+		CreatedAt:           time.Now(),
+		ToolsApproved:       s.ToolsApproved,
+		HideToolResults:     s.HideToolResults,
+		WorkingDir:          s.WorkingDir,
+		MaxIterations:       s.MaxIterations,
+		InputTokens:         cp.InputTokens,
+		OutputTokens:        cp.OutputTokens,
+		Cost:                cp.Cost,
+		Permissions:         s.Permissions,
+		AgentModelOverrides: maps.Clone(s.AgentModelOverrides),
+		CustomModelsUsed:    slices.Clone(s.CustomModelsUsed),
+		Todos:               slices.Clone(cp.Todos),
+		Provenance:          slices.Clone(s.Provenance),
+		Checkpoints:         slices.Clone(s.Checkpoints[:checkpoint]),
+		ForkedFrom:          s.ID,
+	}, nil
+}
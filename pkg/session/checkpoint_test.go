@@ -0,0 +1,76 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/todo"
+)
+
+func TestRecordCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	s := New(WithUserMessage("First"))
+	require.True(t, s.RecordCheckpoint())
+	assert.False(t, s.RecordCheckpoint(), "a message has a single checkpoint")
+
+	s.AddMessage(&Message{AgentName: "root", Message: chat.Message{Role: chat.MessageRoleAssistant, Content: "One"}})
+	assert.False(t, s.RecordCheckpoint(), "only the messages of the user have checkpoints")
+
+	s.AddMessage(ImplicitUserMessage("Keep going"))
+	assert.False(t, s.RecordCheckpoint(), "implicit messages have no checkpoints")
+
+	s.Todos = []todo.Todo{{ID: "todo_1", Description: "Write tests", Status: "pending"}}
+	s.InputTokens, s.OutputTokens, s.Cost = 100, 20, 0.5
+	s.AddMessage(UserMessage("Second"))
+	require.True(t, s.RecordCheckpoint())
+
+	require.Len(t, s.Checkpoints, 2)
+	assert.Equal(t, 0, s.Checkpoints[0].Items)
+	assert.Equal(t, "First", s.Checkpoints[0].Prompt)
+	assert.Equal(t, 3, s.Checkpoints[1].Items)
+	assert.Equal(t, "Second", s.Checkpoints[1].Prompt)
+	assert.Equal(t, s.Todos, s.Checkpoints[1].Todos)
+	assert.Equal(t, int64(100), s.Checkpoints[1].InputTokens)
+}
+
+func TestFork(t *testing.T) {
+	t.Parallel()
+
+	s := New(WithUserMessage("First"), WithTitle("Research"), WithToolsApproved(true))
+	s.RecordCheckpoint()
+	s.AddMessage(&Message{AgentName: "root", Message: chat.Message{Role: chat.MessageRoleAssistant, Content: "One"}})
+	s.InputTokens, s.Cost = 100, 0.5
+	s.AddMessage(UserMessage("Second"))
+	s.RecordCheckpoint()
+	s.AddMessage(&Message{AgentName: "root", Message: chat.Message{Role: chat.MessageRoleAssistant, Content: "Two"}})
+	s.InputTokens, s.Cost = 300, 1.5
+	s.Todos = []todo.Todo{{ID: "todo_1", Description: "Write tests", Status: "pending"}}
+
+	fork, err := s.Fork(1)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, s.ID, fork.ID)
+	assert.Equal(t, s.ID, fork.ForkedFrom)
+	assert.Equal(t, "Research", fork.Title)
+	assert.True(t, fork.ToolsApproved)
+	require.Len(t, fork.Messages, 2)
+	assert.Equal(t, "One", fork.Messages[1].Message.Message.Content)
+	assert.Equal(t, int64(100), fork.InputTokens)
+	assert.InDelta(t, 0.5, fork.Cost, 1e-9)
+	assert.Empty(t, fork.Todos)
+	require.Len(t, fork.Checkpoints, 1)
+	assert.Equal(t, "First", fork.Checkpoints[0].Prompt)
+
+	// The original session isn't changed, even when the fork is
+	fork.Messages[1].Message.Message.Content = "Changed"
+	assert.Len(t, s.Messages, 4)
+	assert.Equal(t, "One", s.Messages[1].Message.Message.Content)
+	assert.Len(t, s.Checkpoints, 2)
+
+	_, err = s.Fork(2)
+	require.Error(t, err)
+}
@@ -0,0 +1,65 @@
+package session
+
+import (
+	"time"
+
+	"github.com/docker/cagent/pkg/chat"
+)
+
+// Ratings the user gives to an answer.
+const (
+	FeedbackPositive = "positive"
+	FeedbackNegative = "negative"
+)
+
+// Feedback is what the user thought of an answer of an agent. It keeps the
+// answer and the request it replied to, so that the instructions of the agent
+// can be tuned from the feedback of many sessions.
+type Feedback struct {
+	AgentName string    `json:"agent_name"`
+	Rating    string    `json:"rating"`
+	Comment   string    `json:"comment,omitempty"`
+	Request   string    `json:"request,omitempty"`
+	Answer    string    `json:"answer"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AddFeedback records the feedback of the user on an answer of an agent. It
+// replaces the feedback given before on the same answer.
+func (s *Session) AddFeedback(agentName, answer, rating, comment string) Feedback {
+	feedback := Feedback{
+		AgentName: agentName,
+		Rating:    rating,
+		Comment:   comment,
+		Request:   s.requestOf(agentName, answer),
+		Answer:    answer,
+		CreatedAt: time.Now(),
+	}
+
+	for i := range s.Feedback {
+		if s.Feedback[i].AgentName == agentName && s.Feedback[i].Answer == answer {
+			s.Feedback[i] = feedback
+			return feedback
+		}
+	}
+	s.Feedback = append(s.Feedback, feedback)
+	return feedback
+}
+
+// requestOf returns the last message the user sent before an answer of an
+// agent, or "" when the answer isn't found.
+func (s *Session) requestOf(agentName, answer string) string {
+	messages := s.GetAllMessages()
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].AgentName != agentName || messages[i].Message.Role != chat.MessageRoleAssistant || messages[i].Message.Content != answer {
+			continue
+		}
+		for j := i - 1; j >= 0; j-- {
+			if messages[j].Message.Role == chat.MessageRoleUser && !messages[j].Implicit {
+				return messages[j].Message.Content
+			}
+		}
+		return ""
+	}
+	return ""
+}
@@ -0,0 +1,39 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/chat"
+)
+
+func TestAddFeedback(t *testing.T) {
+	t.Parallel()
+
+	s := New(WithUserMessage("What's the capital of France?"))
+	s.AddMessage(&Message{AgentName: "root", Message: chat.Message{Role: chat.MessageRoleAssistant, Content: "Paris."}})
+	s.AddMessage(ImplicitUserMessage("Verify your answer."))
+	s.AddMessage(&Message{AgentName: "root", Message: chat.Message{Role: chat.MessageRoleAssistant, Content: "It's Paris."}})
+
+	feedback := s.AddFeedback("root", "It's Paris.", FeedbackNegative, "Too slow")
+	assert.Equal(t, "What's the capital of France?", feedback.Request)
+	assert.Equal(t, "Too slow", feedback.Comment)
+
+	// Rating the same answer again replaces the feedback
+	s.AddFeedback("root", "It's Paris.", FeedbackPositive, "")
+	require.Len(t, s.Feedback, 1)
+	assert.Equal(t, FeedbackPositive, s.Feedback[0].Rating)
+
+	s.AddFeedback("root", "Paris.", FeedbackPositive, "")
+	assert.Len(t, s.Feedback, 2)
+}
+
+func TestAddFeedback_UnknownAnswer(t *testing.T) {
+	t.Parallel()
+
+	s := New(WithUserMessage("Hello"))
+	feedback := s.AddFeedback("root", "Never said", FeedbackPositive, "")
+	assert.Empty(t, feedback.Request)
+}
@@ -0,0 +1,139 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/confidence"
+	"github.com/docker/cagent/pkg/tools/builtin"
+)
+
+// Outcomes of a handoff
+const (
+	// HandoffCompleted is the outcome of a task whose todos are all completed
+	HandoffCompleted = "completed"
+	// HandoffIncomplete is the outcome of a task whose todo list still has
+	// open items
+	HandoffIncomplete = "incomplete"
+)
+
+// Handoff is what a sub-agent hands back to the agent that transferred it a
+// task, once it is done with it.
+type Handoff struct {
+	Agent   string `json:"agent"`
+	Outcome string `json:"outcome"`
+	// Artifacts are the files the sub-agent wrote or edited
+	Artifacts []string `json:"artifacts,omitempty"`
+	// OpenIssues are the items of the sub-agent's todo list not completed
+	OpenIssues []string `json:"open_issues,omitempty"`
+	// Confidence is the confidence the sub-agent reported in its answer, if any
+	Confidence   *confidence.Report `json:"confidence,omitempty"`
+	InputTokens  int64              `json:"input_tokens"`
+	OutputTokens int64              `json:"output_tokens"`
+	Cost         float64            `json:"cost"`
+	Duration     time.Duration      `json:"duration"`
+}
+
+// NewHandoff builds the handoff of the sub-session an agent ran a task in.
+func NewHandoff(agentName string, sub *Session, duration time.Duration) *Handoff {
+	h := &Handoff{
+		Agent:        agentName,
+		Outcome:      HandoffCompleted,
+		Artifacts:    artifacts(sub),
+		InputTokens:  sub.InputTokens,
+		OutputTokens: sub.OutputTokens,
+		Cost:         sub.Cost,
+		Duration:     duration,
+	}
+
+	for _, todo := range sub.Todos {
+		if todo.Status != "completed" {
+			h.OpenIssues = append(h.OpenIssues, todo.Description)
+		}
+	}
+	if len(h.OpenIssues) > 0 {
+		h.Outcome = HandoffIncomplete
+	}
+
+	messages := sub.GetAllMessages()
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Message.Role == chat.MessageRoleAssistant {
+			h.Confidence = messages[i].Confidence
+			break
+		}
+	}
+
+	return h
+}
+
+// artifacts returns the files written or edited in a session, in the order
+// they were first touched.
+func artifacts(sess *Session) []string {
+	var paths []string
+	for _, msg := range sess.GetAllMessages() {
+		if msg.Message.Role != chat.MessageRoleAssistant {
+			continue
+		}
+		for _, call := range msg.Message.ToolCalls {
+			var args struct {
+				Path  string `json:"path"`
+				Files []struct {
+					Path string `json:"path"`
+				} `json:"files"`
+				Patch string `json:"patch"`
+			}
+			if err := json.Unmarshal([]byte(call.Function.Arguments), &args); err != nil {
+				continue
+			}
+
+			var touched []string
+			switch call.Function.Name {
+			case builtin.ToolNameWriteFile, builtin.ToolNameEditFile:
+				touched = []string{args.Path}
+			case builtin.ToolNameEditFiles:
+				for _, file := range args.Files {
+					touched = append(touched, file.Path)
+				}
+			case builtin.ToolNameApplyPatch:
+				touched = builtin.PatchedFiles(args.Patch)
+			}
+			for _, path := range touched {
+				if path != "" && !slices.Contains(paths, path) {
+					paths = append(paths, path)
+				}
+			}
+		}
+	}
+	return paths
+}
+
+// String formats the handoff for the model of the agent that transferred the
+// task.
+func (h *Handoff) String() string {
+	var b strings.Builder
+	b.WriteString("<handoff>\n")
+	fmt.Fprintf(&b, "agent: %s\noutcome: %s\n", h.Agent, h.Outcome)
+	if len(h.Artifacts) > 0 {
+		fmt.Fprintf(&b, "artifacts: %s\n", strings.Join(h.Artifacts, ", "))
+	}
+	if len(h.OpenIssues) > 0 {
+		b.WriteString("open issues:\n")
+		for _, issue := range h.OpenIssues {
+			fmt.Fprintf(&b, "- %s\n", issue)
+		}
+	}
+	if h.Confidence != nil {
+		fmt.Fprintf(&b, "confidence: %s", h.Confidence.Level)
+		if h.Confidence.Rationale != "" {
+			fmt.Fprintf(&b, " (%s)", h.Confidence.Rationale)
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "cost: $%.4f (%d input tokens, %d output tokens) in %s\n", h.Cost, h.InputTokens, h.OutputTokens, h.Duration.Round(time.Second))
+	b.WriteString("</handoff>")
+	return b.String()
+}
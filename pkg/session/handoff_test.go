@@ -0,0 +1,82 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/todo"
+	"github.com/docker/cagent/pkg/tools"
+	"github.com/docker/cagent/pkg/tools/builtin"
+)
+
+func TestNewHandoff(t *testing.T) {
+	t.Parallel()
+
+	sub := New()
+	sub.AddMessage(&Message{Message: chat.Message{
+		Role: chat.MessageRoleAssistant,
+		ToolCalls: []tools.ToolCall{
+			{Function: tools.FunctionCall{Name: builtin.ToolNameWriteFile, Arguments: `{"path":"parser.go","content":"package parser"}`}},
+			{Function: tools.FunctionCall{Name: builtin.ToolNameReadFile, Arguments: `{"path":"README.md"}`}},
+		},
+	}})
+	sub.AddMessage(&Message{Message: chat.Message{
+		Role: chat.MessageRoleAssistant,
+		ToolCalls: []tools.ToolCall{
+			{Function: tools.FunctionCall{Name: builtin.ToolNameEditFile, Arguments: `{"path":"parser.go","edits":[]}`}},
+			{Function: tools.FunctionCall{Name: builtin.ToolNameEditFile, Arguments: `{"path":"lexer.go","edits":[]}`}},
+		},
+	}})
+	sub.Todos = []todo.Todo{
+		{ID: "1", Description: "Write the parser", Status: "completed"},
+		{ID: "2", Description: "Test the parser", Status: "pending"},
+	}
+	sub.InputTokens = 1200
+	sub.OutputTokens = 300
+	sub.Cost = 0.0125
+
+	h := NewHandoff("developer", sub, 90*time.Second)
+
+	assert.Equal(t, "developer", h.Agent)
+	assert.Equal(t, HandoffIncomplete, h.Outcome)
+	assert.Equal(t, []string{"parser.go", "lexer.go"}, h.Artifacts)
+	assert.Equal(t, []string{"Test the parser"}, h.OpenIssues)
+	assert.Equal(t, `<handoff>
+agent: developer
+outcome: incomplete
+artifacts: parser.go, lexer.go
+open issues:
+- Test the parser
+cost: $0.0125 (1200 input tokens, 300 output tokens) in 1m30s
+</handoff>`, h.String())
+}
+
+func TestNewHandoff_Completed(t *testing.T) {
+	t.Parallel()
+
+	h := NewHandoff("researcher", New(), time.Second)
+
+	assert.Equal(t, HandoffCompleted, h.Outcome)
+	assert.Empty(t, h.Artifacts)
+	assert.Empty(t, h.OpenIssues)
+}
+
+func TestNewHandoff_BatchEdits(t *testing.T) {
+	t.Parallel()
+
+	sub := New()
+	sub.AddMessage(&Message{Message: chat.Message{
+		Role: chat.MessageRoleAssistant,
+		ToolCalls: []tools.ToolCall{
+			{Function: tools.FunctionCall{Name: builtin.ToolNameEditFiles, Arguments: `{"files":[{"path":"a.go","edits":[]},{"path":"b.go","edits":[]}]}`}},
+			{Function: tools.FunctionCall{Name: builtin.ToolNameApplyPatch, Arguments: `{"patch":"--- a/b.go\n+++ b/b.go\n@@ -1 +1 @@\n-x\n+y\n--- /dev/null\n+++ b/c.go\n@@ -0,0 +1 @@\n+z\n"}`}},
+		},
+	}})
+
+	h := NewHandoff("developer", sub, time.Second)
+
+	assert.Equal(t, []string{"a.go", "b.go", "c.go"}, h.Artifacts)
+}
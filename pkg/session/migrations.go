@@ -235,5 +235,82 @@ func getAllMigrations() []Migration {
 			UpSQL:       `ALTER TABLE sessions ADD COLUMN custom_models_used TEXT DEFAULT '[]'`,
 			DownSQL:     `ALTER TABLE sessions DROP COLUMN custom_models_used`,
 		},
+		{
+			ID:          13,
+			Name:        "013_add_todos_column",
+			Description: "Add todos column to sessions table to persist the todo list across restarts",
+			UpSQL:       `ALTER TABLE sessions ADD COLUMN todos TEXT DEFAULT '[]'`,
+			DownSQL:     `ALTER TABLE sessions DROP COLUMN todos`,
+		},
+		{
+			ID:          14,
+			Name:        "014_add_agent_name_column",
+			Description: "Add agent_name column to sessions table to compare the usage of sessions of the same agent",
+			UpSQL:       `ALTER TABLE sessions ADD COLUMN agent_name TEXT DEFAULT ''`,
+			DownSQL:     `ALTER TABLE sessions DROP COLUMN agent_name`,
+		},
+		{
+			ID:          15,
+			Name:        "015_add_previous_session_id_column",
+			Description: "Add previous_session_id column to sessions table to link sessions rotated by the governor",
+			UpSQL:       `ALTER TABLE sessions ADD COLUMN previous_session_id TEXT DEFAULT ''`,
+			DownSQL:     `ALTER TABLE sessions DROP COLUMN previous_session_id`,
+		},
+		{
+			ID:          16,
+			Name:        "016_add_updated_at_column",
+			Description: "Add updated_at column to sessions table to list sessions by last activity",
+			UpSQL:       `ALTER TABLE sessions ADD COLUMN updated_at TEXT DEFAULT ''`,
+			DownSQL:     `ALTER TABLE sessions DROP COLUMN updated_at`,
+		},
+		{
+			ID:          17,
+			Name:        "017_add_provenance_column",
+			Description: "Add provenance column to sessions table to record the binaries and configurations that ran a session",
+			UpSQL:       `ALTER TABLE sessions ADD COLUMN provenance TEXT DEFAULT '[]'`,
+			DownSQL:     `ALTER TABLE sessions DROP COLUMN provenance`,
+		},
+		{
+			ID:          18,
+			Name:        "018_add_post_mortem_column",
+			Description: "Add post_mortem column to sessions table to keep the post-mortem of an unsuccessful run for the next attempt",
+			UpSQL:       `ALTER TABLE sessions ADD COLUMN post_mortem TEXT DEFAULT ''`,
+			DownSQL:     `ALTER TABLE sessions DROP COLUMN post_mortem`,
+		},
+		{
+			ID:          19,
+			Name:        "019_add_feedback_column",
+			Description: "Add feedback column to sessions table to keep what the user thought of the answers of the agents",
+			UpSQL:       `ALTER TABLE sessions ADD COLUMN feedback TEXT DEFAULT '[]'`,
+			DownSQL:     `ALTER TABLE sessions DROP COLUMN feedback`,
+		},
+		{
+			ID:          20,
+			Name:        "020_add_checkpoints_column",
+			Description: "Add checkpoints column to sessions table to rewind a session to the state it was in when the user sent a message",
+			UpSQL:       `ALTER TABLE sessions ADD COLUMN checkpoints TEXT DEFAULT '[]'`,
+			DownSQL:     `ALTER TABLE sessions DROP COLUMN checkpoints`,
+		},
+		{
+			ID:          21,
+			Name:        "021_add_forked_from_column",
+			Description: "Add forked_from column to sessions table to link a session rewound to a checkpoint to the session it was forked from",
+			UpSQL:       `ALTER TABLE sessions ADD COLUMN forked_from TEXT DEFAULT ''`,
+			DownSQL:     `ALTER TABLE sessions DROP COLUMN forked_from`,
+		},
+		{
+			ID:          22,
+			Name:        "022_add_working_memory_column",
+			Description: "Add working_memory column to sessions table to keep the notes the agents write when handing off the conversation",
+			UpSQL:       `ALTER TABLE sessions ADD COLUMN working_memory TEXT DEFAULT '[]'`,
+			DownSQL:     `ALTER TABLE sessions DROP COLUMN working_memory`,
+		},
+		{
+			ID:          23,
+			Name:        "023_add_untrusted_content_column",
+			Description: "Add untrusted_content column to sessions table to keep guarding the tool calls of resumed sessions that read untrusted content",
+			UpSQL:       `ALTER TABLE sessions ADD COLUMN untrusted_content BOOLEAN DEFAULT 0`,
+			DownSQL:     `ALTER TABLE sessions DROP COLUMN untrusted_content`,
+		},
 	}
 }
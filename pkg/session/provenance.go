@@ -0,0 +1,48 @@
+package session
+
+import (
+	"maps"
+	"slices"
+	"time"
+)
+
+// Provenance describes the binary and the configuration that ran a session:
+// a transcript is interpreted against the behavior they had.
+//
+// Its JSON form is stable, it's stored in the session database and written
+// in the exports.
+type Provenance struct {
+	// Version and Commit are the version of cagent.
+	Version string `json:"version"`
+	Commit  string `json:"commit"`
+	// Features are the experimental features enabled, sorted by name.
+	Features []string `json:"features"`
+	// Providers maps the model providers to the version of their SDK.
+	Providers map[string]string `json:"providers"`
+	// ConfigHash is the hash of the agent configuration, empty if unknown.
+	ConfigHash string `json:"config_hash,omitempty"`
+	// Since is when the session started running with this provenance.
+	Since time.Time `json:"since"`
+}
+
+// Same reports whether two provenances describe the same behavior, regardless
+// of when they were recorded.
+func (p Provenance) Same(other Provenance) bool {
+	return p.Version == other.Version &&
+		p.Commit == other.Commit &&
+		slices.Equal(p.Features, other.Features) &&
+		maps.Equal(p.Providers, other.Providers) &&
+		p.ConfigHash == other.ConfigHash
+}
+
+// RecordProvenance records the provenance of the next runs of the session.
+// It's only appended when it differs from the latest one, so that a session
+// resumed with another binary or configuration keeps the history of both.
+// It returns true if the provenance was appended.
+func (s *Session) RecordProvenance(p Provenance) bool {
+	if n := len(s.Provenance); n > 0 && s.Provenance[n-1].Same(p) {
+		return false
+	}
+	s.Provenance = append(s.Provenance, p)
+	return true
+}
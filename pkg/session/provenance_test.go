@@ -0,0 +1,65 @@
+package session
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordProvenance(t *testing.T) {
+	t.Parallel()
+
+	first := Provenance{
+		Version:   "v1.0.0",
+		Features:  []string{"debug-layout"},
+		Providers: map[string]string{"openai": "v3.16.0"},
+		Since:     time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	var sess Session
+	assert.True(t, sess.RecordProvenance(first))
+
+	// The same binary and configuration, running later
+	again := first
+	again.Since = first.Since.Add(time.Hour)
+	assert.False(t, sess.RecordProvenance(again))
+
+	// Another version of cagent
+	upgraded := again
+	upgraded.Version = "v1.1.0"
+	assert.True(t, sess.RecordProvenance(upgraded))
+
+	// Another configuration
+	reconfigured := upgraded
+	reconfigured.ConfigHash = "sha256:5678"
+	assert.True(t, sess.RecordProvenance(reconfigured))
+
+	assert.Equal(t, []Provenance{first, upgraded, reconfigured}, sess.Provenance)
+}
+
+func TestProvenance_JSON(t *testing.T) {
+	t.Parallel()
+
+	data, err := json.Marshal(Provenance{
+		Version:    "v1.0.0",
+		Commit:     "abc123",
+		Features:   []string{"markdown-renderer"},
+		Providers:  map[string]string{"anthropic": "v1.19.0", "openai": "v3.16.0"},
+		ConfigHash: "sha256:1234",
+		Since:      time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	// The format is read back by other versions of cagent, and by other tools
+	assert.JSONEq(t, `{
+		"version": "v1.0.0",
+		"commit": "abc123",
+		"features": ["markdown-renderer"],
+		"providers": {"anthropic": "v1.19.0", "openai": "v3.16.0"},
+		"config_hash": "sha256:1234",
+		"since": "2026-01-02T03:04:05Z"
+	}`, string(data))
+}
@@ -10,7 +10,9 @@ import (
 
 	"github.com/docker/cagent/pkg/agent"
 	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/confidence"
 	"github.com/docker/cagent/pkg/skills"
+	"github.com/docker/cagent/pkg/todo"
 )
 
 const (
@@ -40,6 +42,13 @@ func (si *Item) IsMessage() bool {
 	return si.Message != nil
 }
 
+// WithPreviousSessionID links this session to the session it continues.
+func WithPreviousSessionID(previousSessionID string) Opt {
+	return func(s *Session) {
+		s.PreviousSessionID = previousSessionID
+	}
+}
+
 // IsSubSession returns true if this item contains a sub-session
 func (si *Item) IsSubSession() bool {
 	return si.SubSession != nil
@@ -95,10 +104,59 @@ type Session struct {
 	// These are shown in the model picker for easy re-selection.
 	CustomModelsUsed []string `json:"custom_models_used,omitempty"`
 
+	// Todos is the latest todo list produced by the agents' todo tools.
+	// When a session is resumed, the list is restored into the todo tools.
+	Todos []todo.Todo `json:"todos,omitempty"`
+
+	// Provenance is the history of the binaries and configurations that ran
+	// the session, oldest first.
+	Provenance []Provenance `json:"provenance,omitempty"`
+
+	// PostMortem is the analysis of the last unsuccessful run of the session:
+	// what was attempted, what failed and what to try next. It's given to
+	// the agent for the next attempt.
+	PostMortem string `json:"post_mortem,omitempty"`
+
+	// Feedback is what the user thought of the answers of the agents.
+	Feedback []Feedback `json:"feedback,omitempty"`
+
+	// Checkpoints are the states of the session when the user sent a
+	// message, oldest first. The session can be rewound to them.
+	Checkpoints []Checkpoint `json:"checkpoints,omitempty"`
+
+	// ForkedFrom is the session this one was forked from, when it was
+	// rewound to a checkpoint.
+	ForkedFrom string `json:"forked_from,omitempty"`
+
+	// WorkingMemory holds the notes the agents wrote when handing off the
+	// conversation to each other. It's given to every agent, and outlives
+	// the compaction of the conversation.
+	WorkingMemory []Note `json:"working_memory,omitempty"`
+
+	// UntrustedContent is set once a tool result coming from an untrusted source
+	// (web page, email, issue body...) has been added to the conversation.
+	UntrustedContent bool `json:"untrusted_content,omitempty"`
+
 	// ParentID indicates this is a sub-session created by task transfer.
 	// Sub-sessions are not persisted as standalone entries; they are embedded
 	// within the parent session's Messages array.
 	ParentID string `json:"-"`
+
+	// PreviousSessionID links a session to the one it continues, when the
+	// governor rotated a long-running conversation to a fresh session.
+	PreviousSessionID string `json:"previous_session_id,omitempty"`
+
+	// Priority is the priority of the task a sub-session runs: critical,
+	// normal or background. Empty is normal.
+	Priority string `json:"priority,omitempty"`
+
+	// TimeLimit is the wall-clock time each run of the session may take. The
+	// agent is asked to wrap up shortly before. Zero means no limit.
+	TimeLimit time.Duration `json:"time_limit,omitempty"`
+
+	// Handoff is what the agent of a sub-session handed back to the agent
+	// that transferred it the task, once it was done.
+	Handoff *Handoff `json:"handoff,omitempty"`
 }
 
 // Permission mode constants
@@ -171,6 +229,20 @@ func (p *PermissionsConfig) GetToolMode(toolName string) string {
 	return perm.Mode
 }
 
+// AllowTool lets the agents call a tool without asking the user, for the rest
+// of the session.
+func (s *Session) AllowTool(toolName string) {
+	if s.Permissions == nil {
+		s.Permissions = &PermissionsConfig{}
+	}
+	if s.Permissions.Tools == nil {
+		s.Permissions.Tools = map[string]ToolPermission{}
+	}
+	perm := s.Permissions.Tools[toolName]
+	perm.Mode = PermissionModeAlwaysAllow
+	s.Permissions.Tools[toolName] = perm
+}
+
 // Message is a message from an agent
 type Message struct {
 	AgentName string       `json:"agentName"` // TODO: rename to agent_name
@@ -179,6 +251,12 @@ type Message struct {
 	// like when an agent transfers a task to another agent - new session is created with a default user message, but this shouldn't be shown to the user.
 	// Such messages should be marked as true
 	Implicit bool `json:"implicit,omitempty"`
+	// TurnCancelled marks the message recorded when the user cancelled a turn
+	// before it completed.
+	TurnCancelled bool `json:"turn_cancelled,omitempty"`
+	// Confidence is the confidence the agent reported in this answer, when it
+	// reports it.
+	Confidence *confidence.Report `json:"confidence,omitempty"`
 }
 
 func ImplicitUserMessage(content string) *Message {
@@ -187,6 +265,14 @@ func ImplicitUserMessage(content string) *Message {
 	return msg
 }
 
+// TurnCancelledMessage returns the message recorded when the user cancels a
+// turn, telling the model on the next turn that the previous one was cut short.
+func TurnCancelledMessage() *Message {
+	msg := ImplicitUserMessage("The user cancelled the previous turn before it completed. Don't resume it unless asked to.")
+	msg.TurnCancelled = true
+	return msg
+}
+
 func UserMessage(content string, multiContent ...chat.MessagePart) *Message {
 	return &Message{
 		Message: chat.Message{
@@ -259,6 +345,18 @@ func (s *Session) Duration() time.Duration {
 	return last.Sub(first)
 }
 
+// LastActivity returns the time of the last message of the session, or its
+// creation time when it has none.
+func (s *Session) LastActivity() time.Time {
+	messages := s.GetAllMessages()
+	for i := len(messages) - 1; i >= 0; i-- {
+		if t, err := time.Parse(time.RFC3339, messages[i].Message.CreatedAt); err == nil {
+			return t
+		}
+	}
+	return s.CreatedAt
+}
+
 // AllowedDirectories returns the directories that should be considered safe for tools
 func (s *Session) AllowedDirectories() []string {
 	if s.WorkingDir == "" {
@@ -267,6 +365,17 @@ func (s *Session) AllowedDirectories() []string {
 	return []string{s.WorkingDir}
 }
 
+// AgentName returns the name of the first agent that answered in the session,
+// ignoring sub-sessions. It's empty if no agent answered yet.
+func (s *Session) AgentName() string {
+	for _, item := range s.Messages {
+		if item.IsMessage() && item.Message.AgentName != "" {
+			return item.Message.AgentName
+		}
+	}
+	return ""
+}
+
 // GetAllMessages extracts all messages from the session, including from sub-sessions
 func (s *Session) GetAllMessages() []Message {
 	var messages []Message
@@ -370,6 +479,20 @@ func WithParentID(parentID string) Opt {
 	}
 }
 
+// WithPriority sets the priority of the task run by a sub-session.
+func WithPriority(priority string) Opt {
+	return func(s *Session) {
+		s.Priority = priority
+	}
+}
+
+// WithTimeLimit sets the wall-clock time each run of the session may take.
+func WithTimeLimit(limit time.Duration) Opt {
+	return func(s *Session) {
+		s.TimeLimit = limit
+	}
+}
+
 // IsSubSession returns true if this session is a sub-session (has a parent).
 func (s *Session) IsSubSession() bool {
 	return s.ParentID != ""
@@ -466,6 +589,13 @@ func buildInvariantSystemMessages(a *agent.Agent) []chat.Message {
 		})
 	}
 
+	if a.ReportsConfidence() {
+		messages = append(messages, chat.Message{
+			Role:    chat.MessageRoleSystem,
+			Content: confidence.Instruction,
+		})
+	}
+
 	for _, toolSet := range a.ToolSets() {
 		if toolSet.Instructions() != "" {
 			messages = append(messages, chat.Message{
@@ -545,8 +675,8 @@ func buildContextSpecificSystemMessages(a *agent.Agent, s *Session) []chat.Messa
 	return messages
 }
 
-// buildSessionSummaryMessages builds system messages containing the session summary
-// if one exists. Session summaries are context-specific per session and thus should not have a checkpoint (they will be cached alongside the first user message anyway)
+// buildSessionSummaryMessages builds system messages containing the session summary,
+// the post-mortem of the last unsuccessful run and the working memory, if they exist. Session summaries are context-specific per session and thus should not have a checkpoint (they will be cached alongside the first user message anyway)
 //
 // lastSummaryIndex is the index of the last summary item in s.Messages, or -1 if none exists.
 func buildSessionSummaryMessages(s *Session) ([]chat.Message, int) {
@@ -569,9 +699,31 @@ func buildSessionSummaryMessages(s *Session) ([]chat.Message, int) {
 		})
 	}
 
+	if s.PostMortem != "" {
+		messages = append(messages, chat.Message{
+			Role:      chat.MessageRoleSystem,
+			Content:   "Post-mortem of the previous unsuccessful attempt, learn from it before trying again:\n\n" + s.PostMortem,
+			CreatedAt: time.Now().Format(time.RFC3339),
+		})
+	}
+
+	if workingMemory := s.workingMemoryPrompt(); workingMemory != "" {
+		messages = append(messages, chat.Message{
+			Role:      chat.MessageRoleSystem,
+			Content:   workingMemory,
+			CreatedAt: time.Now().Format(time.RFC3339),
+		})
+	}
+
 	return messages, lastSummaryIndex
 }
 
+// SystemMessages returns the system messages an agent gets in this session,
+// before the session summary and the conversation.
+func (s *Session) SystemMessages(a *agent.Agent) []chat.Message {
+	return append(buildInvariantSystemMessages(a), buildContextSpecificSystemMessages(a, s)...)
+}
+
 func (s *Session) GetMessages(a *agent.Agent) []chat.Message {
 	slog.Debug("Getting messages for agent", "agent", a.Name(), "session_id", s.ID)
 
@@ -1,6 +1,7 @@
 package session
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -160,6 +161,23 @@ func TestGetMessagesWithSummary(t *testing.T) {
 	assert.Equal(t, 2, userAssistantMessages, "should only include messages after summary")
 }
 
+func TestGetMessagesWithPostMortem(t *testing.T) {
+	testAgent := agent.New("root", "instructions")
+
+	s := New(WithUserMessage("Build the project"))
+	s.PostMortem = "The build failed because go.mod was missing."
+
+	messages := s.GetMessages(testAgent)
+
+	var found bool
+	for _, msg := range messages {
+		if msg.Role == chat.MessageRoleSystem && strings.Contains(msg.Content, s.PostMortem) {
+			found = true
+		}
+	}
+	assert.True(t, found, "should include the post-mortem as system message")
+}
+
 func TestGetMessages_Instructions(t *testing.T) {
 	testAgent := agent.New("root", "instructions")
 
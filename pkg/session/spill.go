@@ -0,0 +1,94 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SpillThreshold is the size, in bytes, above which tool outputs and
+// attachments are written to a file of the session instead of being kept
+// whole in its messages.
+const SpillThreshold = 256 * 1024
+
+// spillRoot is where the spilled content of all the sessions is written.
+var spillRoot = filepath.Join(os.TempDir(), "cagent-spill")
+
+// SpillDir returns the directory holding the spilled content of a session.
+func SpillDir(sessionID string) string {
+	return filepath.Join(spillRoot, spillName(sessionID))
+}
+
+// Spill writes content to a file of the session, named after id, and returns
+// its path.
+func Spill(sessionID, id, content string) (string, error) {
+	dir := SpillDir(sessionID)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("creating spill directory: %w", err)
+	}
+
+	path := filepath.Join(dir, spillName(id))
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		return "", fmt.Errorf("spilling %s: %w", id, err)
+	}
+	return path, nil
+}
+
+// ReadSpilled reads at most length bytes, starting at offset, of the content
+// spilled by a session under id. It also returns the size of the whole
+// content. It returns an error wrapping os.ErrNotExist when nothing was
+// spilled under id.
+func ReadSpilled(sessionID, id string, offset, length int) (chunk string, size int, err error) {
+	f, err := os.Open(filepath.Join(SpillDir(sessionID), spillName(id)))
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", 0, err
+	}
+	size = int(info.Size())
+	if offset >= size {
+		return "", size, nil
+	}
+
+	buf := make([]byte, min(length, size-offset))
+	n, err := f.ReadAt(buf, int64(offset))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", 0, err
+	}
+	return string(buf[:n]), size, nil
+}
+
+// RemoveSpilled removes the spilled content of a session and of its
+// sub-sessions.
+func RemoveSpilled(s *Session) error {
+	errs := []error{os.RemoveAll(SpillDir(s.ID))}
+	for _, item := range s.Messages {
+		if item.IsSubSession() {
+			errs = append(errs, RemoveSpilled(item.SubSession))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// spillName turns an ID into a file name that can't escape its directory.
+func spillName(id string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, strings.Trim(id, "."))
+	if name == "" {
+		return "_"
+	}
+	return name
+}
@@ -0,0 +1,67 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpill(t *testing.T) {
+	t.Parallel()
+
+	s := New()
+	t.Cleanup(func() { _ = RemoveSpilled(s) })
+
+	path, err := Spill(s.ID, "../call_1", "0123456789")
+	require.NoError(t, err)
+	assert.Equal(t, SpillDir(s.ID), filepath.Dir(path), "IDs can't escape the directory")
+
+	chunk, size, err := ReadSpilled(s.ID, "../call_1", 2, 3)
+	require.NoError(t, err)
+	assert.Equal(t, "234", chunk)
+	assert.Equal(t, 10, size)
+
+	chunk, _, err = ReadSpilled(s.ID, "../call_1", 8, 100)
+	require.NoError(t, err)
+	assert.Equal(t, "89", chunk)
+
+	chunk, _, err = ReadSpilled(s.ID, "../call_1", 10, 100)
+	require.NoError(t, err)
+	assert.Empty(t, chunk)
+
+	_, _, err = ReadSpilled(s.ID, "unknown", 0, 100)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestRemoveSpilledIncludesSubSessions(t *testing.T) {
+	t.Parallel()
+
+	parent := New()
+	sub := New(WithParentID(parent.ID))
+	parent.AddSubSession(sub)
+
+	_, err := Spill(parent.ID, "call_1", "parent")
+	require.NoError(t, err)
+	_, err = Spill(sub.ID, "call_2", "sub")
+	require.NoError(t, err)
+
+	require.NoError(t, RemoveSpilled(parent))
+	assert.NoDirExists(t, SpillDir(parent.ID))
+	assert.NoDirExists(t, SpillDir(sub.ID))
+}
+
+func TestDeleteSessionRemovesSpilledContent(t *testing.T) {
+	t.Parallel()
+
+	store := NewInMemorySessionStore()
+	s := New()
+	require.NoError(t, store.AddSession(t.Context(), s))
+	_, err := Spill(s.ID, "call_1", "output")
+	require.NoError(t, err)
+
+	require.NoError(t, store.DeleteSession(t.Context(), s.ID))
+	assert.NoDirExists(t, SpillDir(s.ID))
+}
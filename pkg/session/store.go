@@ -1,15 +1,20 @@
 package session
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"log/slog"
+	"slices"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/docker/cagent/pkg/concurrent"
 	"github.com/docker/cagent/pkg/sqliteutil"
+	"github.com/docker/cagent/pkg/todo"
 )
 
 var (
@@ -20,10 +25,16 @@ var (
 // Summary contains lightweight session metadata for listing purposes.
 // This is used instead of loading full Session objects with all messages.
 type Summary struct {
-	ID        string
-	Title     string
-	CreatedAt time.Time
-	Starred   bool
+	ID           string
+	Title        string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time // time of the last message
+	Starred      bool
+	AgentName    string
+	WorkingDir   string
+	InputTokens  int64
+	OutputTokens int64
+	Cost         float64
 }
 
 // convertMessagesToItems converts a slice of Messages to SessionItems for backward compatibility
@@ -44,6 +55,7 @@ type Store interface {
 	DeleteSession(ctx context.Context, id string) error
 	UpdateSession(ctx context.Context, session *Session) error
 	SetSessionStarred(ctx context.Context, id string, starred bool) error
+	SetSessionTitle(ctx context.Context, id, title string) error
 }
 
 type InMemorySessionStore struct {
@@ -88,13 +100,22 @@ func (s *InMemorySessionStore) GetSessionSummaries(_ context.Context) ([]Summary
 	summaries := make([]Summary, 0, s.sessions.Length())
 	s.sessions.Range(func(_ string, value *Session) bool {
 		summaries = append(summaries, Summary{
-			ID:        value.ID,
-			Title:     value.Title,
-			CreatedAt: value.CreatedAt,
-			Starred:   value.Starred,
+			ID:           value.ID,
+			Title:        value.Title,
+			CreatedAt:    value.CreatedAt,
+			UpdatedAt:    value.LastActivity(),
+			Starred:      value.Starred,
+			AgentName:    value.AgentName(),
+			WorkingDir:   value.WorkingDir,
+			InputTokens:  value.InputTokens,
+			OutputTokens: value.OutputTokens,
+			Cost:         value.Cost,
 		})
 		return true
 	})
+	slices.SortFunc(summaries, func(a, b Summary) int {
+		return b.UpdatedAt.Compare(a.UpdatedAt)
+	})
 	return summaries, nil
 }
 
@@ -102,11 +123,27 @@ func (s *InMemorySessionStore) DeleteSession(_ context.Context, id string) error
 	if id == "" {
 		return ErrEmptyID
 	}
-	_, exists := s.sessions.Load(id)
+	value, exists := s.sessions.Load(id)
 	if !exists {
 		return ErrNotFound
 	}
 	s.sessions.Delete(id)
+	if err := RemoveSpilled(value); err != nil {
+		slog.Warn("Failed to remove spilled content of session", "session_id", id, "error", err)
+	}
+	return nil
+}
+
+// SetSessionTitle renames a session.
+func (s *InMemorySessionStore) SetSessionTitle(_ context.Context, id, title string) error {
+	if id == "" {
+		return ErrEmptyID
+	}
+	value, exists := s.sessions.Load(id)
+	if !exists {
+		return ErrNotFound
+	}
+	value.Title = title
 	return nil
 }
 
@@ -137,6 +174,11 @@ func (s *InMemorySessionStore) SetSessionStarred(_ context.Context, id string, s
 // SQLiteSessionStore implements Store using SQLite
 type SQLiteSessionStore struct {
 	db *sql.DB
+
+	// mu serializes the writes of sessions, guarding written
+	mu sync.Mutex
+	// written holds, by session ID, the items this store last wrote
+	written map[string][]itemRef
 }
 
 // NewSQLiteSessionStore creates a new SQLite session store
@@ -168,7 +210,7 @@ func NewSQLiteSessionStore(path string) (Store, error) {
 		return nil, err
 	}
 
-	return &SQLiteSessionStore{db: db}, nil
+	return &SQLiteSessionStore{db: db, written: make(map[string][]itemRef)}, nil
 }
 
 // AddSession adds a new session to the store
@@ -182,39 +224,152 @@ func (s *SQLiteSessionStore) AddSession(ctx context.Context, session *Session) e
 		return err
 	}
 
-	permissionsJSON := ""
+	columns, err := encodeSessionColumns(session)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err = s.db.ExecContext(ctx,
+		"INSERT INTO sessions (id, messages, tools_approved, input_tokens, output_tokens, title, send_user_message, max_iterations, working_dir, created_at, permissions, agent_model_overrides, custom_models_used, todos, agent_name, previous_session_id, updated_at, provenance, post_mortem, feedback, checkpoints, forked_from, working_memory, untrusted_content) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		session.ID, string(itemsJSON), session.ToolsApproved, session.InputTokens, session.OutputTokens, session.Title, session.SendUserMessage, session.MaxIterations, session.WorkingDir, session.CreatedAt.Format(time.RFC3339), columns.permissions, columns.agentModelOverrides, columns.customModelsUsed, columns.todos, session.AgentName(), session.PreviousSessionID, session.LastActivity().Format(time.RFC3339), columns.provenance, session.PostMortem, columns.feedback, columns.checkpoints, session.ForkedFrom, columns.workingMemory, session.UntrustedContent)
+	if err != nil {
+		return err
+	}
+
+	s.written[session.ID] = itemRefs(session.Messages)
+	return nil
+}
+
+// sessionColumns holds the columns of a session encoded as JSON, other than
+// its messages.
+type sessionColumns struct {
+	permissions         string
+	agentModelOverrides string
+	customModelsUsed    string
+	todos               string
+	provenance          string
+	feedback            string
+	checkpoints         string
+	workingMemory       string
+}
+
+// encodeSessionColumns encodes the columns of a session holding JSON, other
+// than its messages.
+func encodeSessionColumns(session *Session) (sessionColumns, error) {
+	var c sessionColumns
 	if session.Permissions != nil {
 		permBytes, err := json.Marshal(session.Permissions)
 		if err != nil {
-			return err
+			return sessionColumns{}, err
 		}
-		permissionsJSON = string(permBytes)
+		c.permissions = string(permBytes)
 	}
 
 	// Marshal agent model overrides (default to empty object if nil)
-	agentModelOverridesJSON := "{}"
+	c.agentModelOverrides = "{}"
 	if len(session.AgentModelOverrides) > 0 {
 		overridesBytes, err := json.Marshal(session.AgentModelOverrides)
 		if err != nil {
-			return err
+			return sessionColumns{}, err
 		}
-		agentModelOverridesJSON = string(overridesBytes)
+		c.agentModelOverrides = string(overridesBytes)
 	}
 
 	// Marshal custom models used (default to empty array if nil)
-	customModelsUsedJSON := "[]"
+	c.customModelsUsed = "[]"
 	if len(session.CustomModelsUsed) > 0 {
 		customBytes, err := json.Marshal(session.CustomModelsUsed)
 		if err != nil {
-			return err
+			return sessionColumns{}, err
 		}
-		customModelsUsedJSON = string(customBytes)
+		c.customModelsUsed = string(customBytes)
 	}
 
-	_, err = s.db.ExecContext(ctx,
-		"INSERT INTO sessions (id, messages, tools_approved, input_tokens, output_tokens, title, send_user_message, max_iterations, working_dir, created_at, permissions, agent_model_overrides, custom_models_used) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
-		session.ID, string(itemsJSON), session.ToolsApproved, session.InputTokens, session.OutputTokens, session.Title, session.SendUserMessage, session.MaxIterations, session.WorkingDir, session.CreatedAt.Format(time.RFC3339), permissionsJSON, agentModelOverridesJSON, customModelsUsedJSON)
-	return err
+	// Marshal todos (default to empty array if nil)
+	c.todos = "[]"
+	if len(session.Todos) > 0 {
+		todosBytes, err := json.Marshal(session.Todos)
+		if err != nil {
+			return sessionColumns{}, err
+		}
+		c.todos = string(todosBytes)
+	}
+
+	// Marshal provenance (default to empty array if nil)
+	c.provenance = "[]"
+	if len(session.Provenance) > 0 {
+		provenanceBytes, err := json.Marshal(session.Provenance)
+		if err != nil {
+			return sessionColumns{}, err
+		}
+		c.provenance = string(provenanceBytes)
+	}
+
+	// Marshal feedback (default to empty array if nil)
+	c.feedback = "[]"
+	if len(session.Feedback) > 0 {
+		feedbackBytes, err := json.Marshal(session.Feedback)
+		if err != nil {
+			return sessionColumns{}, err
+		}
+		c.feedback = string(feedbackBytes)
+	}
+
+	// Marshal checkpoints (default to empty array if nil)
+	c.checkpoints = "[]"
+	if len(session.Checkpoints) > 0 {
+		checkpointsBytes, err := json.Marshal(session.Checkpoints)
+		if err != nil {
+			return sessionColumns{}, err
+		}
+		c.checkpoints = string(checkpointsBytes)
+	}
+
+	// Marshal working memory (default to empty array if nil)
+	c.workingMemory = "[]"
+	if len(session.WorkingMemory) > 0 {
+		workingMemoryBytes, err := json.Marshal(session.WorkingMemory)
+		if err != nil {
+			return sessionColumns{}, err
+		}
+		c.workingMemory = string(workingMemoryBytes)
+	}
+
+	return c, nil
+}
+
+// itemRef identifies an item of a session. Items are only ever appended to a
+// session, never modified, so an item written to the database keeps its
+// identity until the session is reloaded.
+type itemRef struct {
+	message    *Message
+	subSession *Session
+	summary    string
+}
+
+func itemRefs(items []Item) []itemRef {
+	refs := make([]itemRef, len(items))
+	for i := range items {
+		refs[i] = itemRef{message: items[i].Message, subSession: items[i].SubSession, summary: items[i].Summary}
+	}
+	return refs
+}
+
+// appendedItems returns the items appended to a session since the written
+// ones, or false if the written items were changed or there are none.
+func appendedItems(written []itemRef, items []Item) ([]Item, bool) {
+	if len(written) == 0 || len(written) > len(items) {
+		return nil, false
+	}
+	for i, ref := range written {
+		if ref != (itemRef{message: items[i].Message, subSession: items[i].SubSession, summary: items[i].Summary}) {
+			return nil, false
+		}
+	}
+	return items[len(written):], true
 }
 
 // scanSession scans a single row into a Session struct
@@ -222,12 +377,13 @@ func scanSession(scanner interface {
 	Scan(dest ...any) error
 },
 ) (*Session, error) {
-	var messagesJSON, toolsApprovedStr, inputTokensStr, outputTokensStr, titleStr, costStr, sendUserMessageStr, maxIterationsStr, createdAtStr, starredStr, agentModelOverridesJSON, customModelsUsedJSON string
+	var messagesJSON, toolsApprovedStr, inputTokensStr, outputTokensStr, titleStr, costStr, sendUserMessageStr, maxIterationsStr, createdAtStr, starredStr, agentModelOverridesJSON, customModelsUsedJSON, todosJSON, provenanceJSON, postMortem, feedbackJSON, checkpointsJSON, workingMemoryJSON string
 	var sessionID string
-	var workingDir sql.NullString
+	var workingDir, previousSessionID, forkedFrom sql.NullString
 	var permissionsJSON sql.NullString
+	var untrustedContent sql.NullBool
 
-	err := scanner.Scan(&sessionID, &messagesJSON, &toolsApprovedStr, &inputTokensStr, &outputTokensStr, &titleStr, &costStr, &sendUserMessageStr, &maxIterationsStr, &workingDir, &createdAtStr, &starredStr, &permissionsJSON, &agentModelOverridesJSON, &customModelsUsedJSON)
+	err := scanner.Scan(&sessionID, &messagesJSON, &toolsApprovedStr, &inputTokensStr, &outputTokensStr, &titleStr, &costStr, &sendUserMessageStr, &maxIterationsStr, &workingDir, &createdAtStr, &starredStr, &permissionsJSON, &agentModelOverridesJSON, &customModelsUsedJSON, &todosJSON, &previousSessionID, &provenanceJSON, &postMortem, &feedbackJSON, &checkpointsJSON, &forkedFrom, &workingMemoryJSON, &untrustedContent)
 	if err != nil {
 		return nil, err
 	}
@@ -319,6 +475,46 @@ func scanSession(scanner interface {
 		}
 	}
 
+	// Parse todos (may be empty or "[]")
+	var todos []todo.Todo
+	if todosJSON != "" && todosJSON != "[]" {
+		if err := json.Unmarshal([]byte(todosJSON), &todos); err != nil {
+			return nil, err
+		}
+	}
+
+	// Parse provenance (may be empty or "[]")
+	var provenance []Provenance
+	if provenanceJSON != "" && provenanceJSON != "[]" {
+		if err := json.Unmarshal([]byte(provenanceJSON), &provenance); err != nil {
+			return nil, err
+		}
+	}
+
+	// Parse feedback (may be empty or "[]")
+	var feedback []Feedback
+	if feedbackJSON != "" && feedbackJSON != "[]" {
+		if err := json.Unmarshal([]byte(feedbackJSON), &feedback); err != nil {
+			return nil, err
+		}
+	}
+
+	// Parse checkpoints (may be empty or "[]")
+	var checkpoints []Checkpoint
+	if checkpointsJSON != "" && checkpointsJSON != "[]" {
+		if err := json.Unmarshal([]byte(checkpointsJSON), &checkpoints); err != nil {
+			return nil, err
+		}
+	}
+
+	// Parse working memory (may be empty or "[]")
+	var workingMemory []Note
+	if workingMemoryJSON != "" && workingMemoryJSON != "[]" {
+		if err := json.Unmarshal([]byte(workingMemoryJSON), &workingMemory); err != nil {
+			return nil, err
+		}
+	}
+
 	return &Session{
 		ID:                  sessionID,
 		Title:               titleStr,
@@ -335,6 +531,15 @@ func scanSession(scanner interface {
 		Permissions:         permissions,
 		AgentModelOverrides: agentModelOverrides,
 		CustomModelsUsed:    customModelsUsed,
+		Todos:               todos,
+		PreviousSessionID:   previousSessionID.String,
+		Provenance:          provenance,
+		PostMortem:          postMortem,
+		Feedback:            feedback,
+		Checkpoints:         checkpoints,
+		ForkedFrom:          forkedFrom.String,
+		WorkingMemory:       workingMemory,
+		UntrustedContent:    untrustedContent.Bool,
 	}, nil
 }
 
@@ -345,7 +550,7 @@ func (s *SQLiteSessionStore) GetSession(ctx context.Context, id string) (*Sessio
 	}
 
 	row := s.db.QueryRowContext(ctx,
-		"SELECT id, messages, tools_approved, input_tokens, output_tokens, title, cost, send_user_message, max_iterations, working_dir, created_at, starred, permissions, agent_model_overrides, custom_models_used FROM sessions WHERE id = ?", id)
+		"SELECT id, messages, tools_approved, input_tokens, output_tokens, title, cost, send_user_message, max_iterations, working_dir, created_at, starred, permissions, agent_model_overrides, custom_models_used, todos, previous_session_id, provenance, post_mortem, feedback, checkpoints, forked_from, working_memory, untrusted_content FROM sessions WHERE id = ?", id)
 
 	session, err := scanSession(row)
 	if err != nil {
@@ -361,7 +566,7 @@ func (s *SQLiteSessionStore) GetSession(ctx context.Context, id string) (*Sessio
 // GetSessions retrieves all sessions
 func (s *SQLiteSessionStore) GetSessions(ctx context.Context) ([]*Session, error) {
 	rows, err := s.db.QueryContext(ctx,
-		"SELECT id, messages, tools_approved, input_tokens, output_tokens, title, cost, send_user_message, max_iterations, working_dir, created_at, starred, permissions, agent_model_overrides, custom_models_used FROM sessions ORDER BY created_at DESC")
+		"SELECT id, messages, tools_approved, input_tokens, output_tokens, title, cost, send_user_message, max_iterations, working_dir, created_at, starred, permissions, agent_model_overrides, custom_models_used, todos, previous_session_id, provenance, post_mortem, feedback, checkpoints, forked_from, working_memory, untrusted_content FROM sessions ORDER BY created_at DESC")
 	if err != nil {
 		return nil, err
 	}
@@ -383,7 +588,8 @@ func (s *SQLiteSessionStore) GetSessions(ctx context.Context) ([]*Session, error
 // This is much faster than GetSessions as it doesn't load message content.
 func (s *SQLiteSessionStore) GetSessionSummaries(ctx context.Context) ([]Summary, error) {
 	rows, err := s.db.QueryContext(ctx,
-		"SELECT id, title, created_at, starred FROM sessions ORDER BY created_at DESC")
+		`SELECT id, title, created_at, COALESCE(NULLIF(updated_at, ''), created_at) AS updated_at, starred, agent_name, working_dir, input_tokens, output_tokens, cost
+		 FROM sessions ORDER BY updated_at DESC, created_at DESC`)
 	if err != nil {
 		return nil, err
 	}
@@ -391,23 +597,36 @@ func (s *SQLiteSessionStore) GetSessionSummaries(ctx context.Context) ([]Summary
 
 	var summaries []Summary
 	for rows.Next() {
-		var id, title, createdAtStr, starredStr string
-		if err := rows.Scan(&id, &title, &createdAtStr, &starredStr); err != nil {
+		var id, title, createdAtStr, updatedAtStr, starredStr string
+		var agentName, workingDir sql.NullString
+		var inputTokens, outputTokens sql.NullInt64
+		var cost sql.NullFloat64
+		if err := rows.Scan(&id, &title, &createdAtStr, &updatedAtStr, &starredStr, &agentName, &workingDir, &inputTokens, &outputTokens, &cost); err != nil {
 			return nil, err
 		}
 		createdAt, err := time.Parse(time.RFC3339, createdAtStr)
 		if err != nil {
 			return nil, err
 		}
+		updatedAt, err := time.Parse(time.RFC3339, updatedAtStr)
+		if err != nil {
+			return nil, err
+		}
 		starred, err := strconv.ParseBool(starredStr)
 		if err != nil {
 			return nil, err
 		}
 		summaries = append(summaries, Summary{
-			ID:        id,
-			Title:     title,
-			CreatedAt: createdAt,
-			Starred:   starred,
+			ID:           id,
+			Title:        title,
+			CreatedAt:    createdAt,
+			UpdatedAt:    updatedAt,
+			Starred:      starred,
+			AgentName:    agentName.String,
+			WorkingDir:   workingDir.String,
+			InputTokens:  inputTokens.Int64,
+			OutputTokens: outputTokens.Int64,
+			Cost:         cost.Float64,
 		})
 	}
 
@@ -420,6 +639,16 @@ func (s *SQLiteSessionStore) DeleteSession(ctx context.Context, id string) error
 		return ErrEmptyID
 	}
 
+	// Load the session first to know the sub-sessions whose content was spilled
+	session, err := s.GetSession(ctx, id)
+	if err != nil {
+		session = &Session{ID: id}
+	}
+
+	s.mu.Lock()
+	delete(s.written, id)
+	s.mu.Unlock()
+
 	result, err := s.db.ExecContext(ctx, "DELETE FROM sessions WHERE id = ?", id)
 	if err != nil {
 		return err
@@ -434,6 +663,10 @@ func (s *SQLiteSessionStore) DeleteSession(ctx context.Context, id string) error
 		return ErrNotFound
 	}
 
+	if err := RemoveSpilled(session); err != nil {
+		slog.Warn("Failed to remove spilled content of session", "session_id", id, "error", err)
+	}
+
 	return nil
 }
 
@@ -444,44 +677,77 @@ func (s *SQLiteSessionStore) UpdateSession(ctx context.Context, session *Session
 		return ErrEmptyID
 	}
 
-	itemsJSON, err := json.Marshal(session.Messages)
+	columns, err := encodeSessionColumns(session)
 	if err != nil {
 		return err
 	}
 
-	permissionsJSON := ""
-	if session.Permissions != nil {
-		permBytes, err := json.Marshal(session.Permissions)
-		if err != nil {
-			return err
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Most updates only add messages: append them to the written ones instead
+	// of rewriting the whole session
+	if appended, ok := appendedItems(s.written[session.ID], session.Messages); ok {
+		var tail bytes.Buffer
+		for _, item := range appended {
+			itemJSON, err := json.Marshal(item)
+			if err != nil {
+				return err
+			}
+			tail.WriteByte(',')
+			tail.Write(itemJSON)
 		}
-		permissionsJSON = string(permBytes)
-	}
 
-	// Marshal agent model overrides (default to empty object if nil)
-	agentModelOverridesJSON := "{}"
-	if len(session.AgentModelOverrides) > 0 {
-		overridesBytes, err := json.Marshal(session.AgentModelOverrides)
+		result, err := s.db.ExecContext(ctx,
+			`UPDATE sessions SET
+			   messages = substr(messages, 1, length(messages) - 1) || ? || ']',
+			   title = ?,
+			   tools_approved = ?,
+			   input_tokens = ?,
+			   output_tokens = ?,
+			   cost = ?,
+			   send_user_message = ?,
+			   max_iterations = ?,
+			   working_dir = ?,
+			   starred = ?,
+			   permissions = ?,
+			   agent_model_overrides = ?,
+			   custom_models_used = ?,
+			   todos = ?,
+			   agent_name = ?,
+			   previous_session_id = ?,
+			   updated_at = ?,
+			   provenance = ?,
+			   post_mortem = ?,
+			   feedback = ?,
+			   checkpoints = ?,
+			   forked_from = ?,
+			   working_memory = ?,
+			   untrusted_content = ?
+			 WHERE id = ?`,
+			tail.String(), session.Title, session.ToolsApproved, session.InputTokens, session.OutputTokens,
+			session.Cost, session.SendUserMessage, session.MaxIterations, session.WorkingDir, session.Starred,
+			columns.permissions, columns.agentModelOverrides, columns.customModelsUsed, columns.todos, session.AgentName(), session.PreviousSessionID,
+			session.LastActivity().Format(time.RFC3339), columns.provenance, session.PostMortem, columns.feedback, columns.checkpoints, session.ForkedFrom, columns.workingMemory, session.UntrustedContent, session.ID)
 		if err != nil {
 			return err
 		}
-		agentModelOverridesJSON = string(overridesBytes)
+		if rowsAffected, err := result.RowsAffected(); err == nil && rowsAffected > 0 {
+			s.written[session.ID] = itemRefs(session.Messages)
+			return nil
+		}
+		// The session was deleted in the meantime: write it whole
 	}
 
-	// Marshal custom models used (default to empty array if nil)
-	customModelsUsedJSON := "[]"
-	if len(session.CustomModelsUsed) > 0 {
-		customBytes, err := json.Marshal(session.CustomModelsUsed)
-		if err != nil {
-			return err
-		}
-		customModelsUsedJSON = string(customBytes)
+	itemsJSON, err := json.Marshal(session.Messages)
+	if err != nil {
+		return err
 	}
 
 	// Use INSERT OR REPLACE for upsert behavior - creates if not exists, updates if exists
 	_, err = s.db.ExecContext(ctx,
-		`INSERT INTO sessions (id, messages, tools_approved, input_tokens, output_tokens, title, cost, send_user_message, max_iterations, working_dir, created_at, starred, permissions, agent_model_overrides, custom_models_used)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`INSERT INTO sessions (id, messages, tools_approved, input_tokens, output_tokens, title, cost, send_user_message, max_iterations, working_dir, created_at, starred, permissions, agent_model_overrides, custom_models_used, todos, agent_name, previous_session_id, updated_at, provenance, post_mortem, feedback, checkpoints, forked_from, working_memory, untrusted_content)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		 ON CONFLICT(id) DO UPDATE SET
 		   messages = excluded.messages,
 		   title = excluded.title,
@@ -495,11 +761,28 @@ func (s *SQLiteSessionStore) UpdateSession(ctx context.Context, session *Session
 		   starred = excluded.starred,
 		   permissions = excluded.permissions,
 		   agent_model_overrides = excluded.agent_model_overrides,
-		   custom_models_used = excluded.custom_models_used`,
+		   custom_models_used = excluded.custom_models_used,
+		   todos = excluded.todos,
+		   agent_name = excluded.agent_name,
+		   previous_session_id = excluded.previous_session_id,
+		   updated_at = excluded.updated_at,
+		   provenance = excluded.provenance,
+		   post_mortem = excluded.post_mortem,
+		   feedback = excluded.feedback,
+		   checkpoints = excluded.checkpoints,
+		   forked_from = excluded.forked_from,
+		   working_memory = excluded.working_memory,
+		   untrusted_content = excluded.untrusted_content`,
 		session.ID, string(itemsJSON), session.ToolsApproved, session.InputTokens, session.OutputTokens,
 		session.Title, session.Cost, session.SendUserMessage, session.MaxIterations, session.WorkingDir,
-		session.CreatedAt.Format(time.RFC3339), session.Starred, permissionsJSON, agentModelOverridesJSON, customModelsUsedJSON)
-	return err
+		session.CreatedAt.Format(time.RFC3339), session.Starred, columns.permissions, columns.agentModelOverrides, columns.customModelsUsed, columns.todos, session.AgentName(), session.PreviousSessionID,
+		session.LastActivity().Format(time.RFC3339), columns.provenance, session.PostMortem, columns.feedback, columns.checkpoints, session.ForkedFrom, columns.workingMemory, session.UntrustedContent)
+	if err != nil {
+		return err
+	}
+
+	s.written[session.ID] = itemRefs(session.Messages)
+	return nil
 }
 
 // SetSessionStarred sets the starred status of a session.
@@ -525,6 +808,29 @@ func (s *SQLiteSessionStore) SetSessionStarred(ctx context.Context, id string, s
 	return nil
 }
 
+// SetSessionTitle renames a session.
+func (s *SQLiteSessionStore) SetSessionTitle(ctx context.Context, id, title string) error {
+	if id == "" {
+		return ErrEmptyID
+	}
+
+	result, err := s.db.ExecContext(ctx, "UPDATE sessions SET title = ? WHERE id = ?", title, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
 // Close closes the database connection
 func (s *SQLiteSessionStore) Close() error {
 	return s.db.Close()
@@ -11,6 +11,7 @@ import (
 
 	"github.com/docker/cagent/pkg/agent"
 	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/todo"
 )
 
 func TestStoreAgentName(t *testing.T) {
@@ -183,7 +184,10 @@ func TestGetSessionSummaries(t *testing.T) {
 				Content: "A very long message that should not be loaded when getting summaries",
 			})),
 		},
-		CreatedAt: session1Time,
+		CreatedAt:    session1Time,
+		WorkingDir:   "/project",
+		InputTokens:  100,
+		OutputTokens: 20,
 	}
 
 	session2 := &Session{
@@ -209,7 +213,7 @@ func TestGetSessionSummaries(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, summaries, 2)
 
-	// Summaries should be ordered by created_at DESC (most recent first)
+	// Summaries should be ordered by last activity (most recent first)
 	assert.Equal(t, "session-2", summaries[0].ID)
 	assert.Equal(t, "Second Session", summaries[0].Title)
 	assert.Equal(t, session2Time, summaries[0].CreatedAt)
@@ -217,6 +221,10 @@ func TestGetSessionSummaries(t *testing.T) {
 	assert.Equal(t, "session-1", summaries[1].ID)
 	assert.Equal(t, "First Session", summaries[1].Title)
 	assert.Equal(t, session1Time, summaries[1].CreatedAt)
+	assert.Equal(t, "test-agent", summaries[1].AgentName)
+	assert.Equal(t, "/project", summaries[1].WorkingDir)
+	assert.Equal(t, int64(100), summaries[1].InputTokens)
+	assert.Equal(t, int64(20), summaries[1].OutputTokens)
 }
 
 func TestStoreAgentNameJSON(t *testing.T) {
@@ -524,3 +532,292 @@ func TestAgentModelOverrides_EmptyMap(t *testing.T) {
 	// Verify no model overrides (should be nil or empty)
 	assert.Empty(t, retrieved.AgentModelOverrides)
 }
+
+func TestTodos_SQLite(t *testing.T) {
+	tempDB := filepath.Join(t.TempDir(), "test_todos.db")
+
+	store, err := NewSQLiteSessionStore(tempDB)
+	require.NoError(t, err)
+	defer store.(*SQLiteSessionStore).Close()
+
+	session := &Session{
+		ID:        "todos-session",
+		Title:     "Test Session",
+		CreatedAt: time.Now(),
+	}
+
+	err = store.AddSession(t.Context(), session)
+	require.NoError(t, err)
+
+	retrieved, err := store.GetSession(t.Context(), "todos-session")
+	require.NoError(t, err)
+	assert.Empty(t, retrieved.Todos)
+
+	// Update the session with todos
+	session.Todos = []todo.Todo{
+		{ID: "todo_1", Description: "Write the plan", Status: "completed"},
+		{ID: "todo_2", Description: "Execute the plan", Status: "in-progress"},
+	}
+	err = store.UpdateSession(t.Context(), session)
+	require.NoError(t, err)
+
+	retrieved, err = store.GetSession(t.Context(), "todos-session")
+	require.NoError(t, err)
+	assert.Equal(t, session.Todos, retrieved.Todos)
+}
+
+func TestPreviousSessionID_SQLite(t *testing.T) {
+	tempDB := filepath.Join(t.TempDir(), "test_previous_session.db")
+
+	store, err := NewSQLiteSessionStore(tempDB)
+	require.NoError(t, err)
+	defer store.(*SQLiteSessionStore).Close()
+
+	err = store.AddSession(t.Context(), &Session{ID: "first", CreatedAt: time.Now()})
+	require.NoError(t, err)
+
+	err = store.AddSession(t.Context(), &Session{ID: "second", PreviousSessionID: "first", CreatedAt: time.Now()})
+	require.NoError(t, err)
+
+	retrieved, err := store.GetSession(t.Context(), "second")
+	require.NoError(t, err)
+	assert.Equal(t, "first", retrieved.PreviousSessionID)
+
+	retrieved, err = store.GetSession(t.Context(), "first")
+	require.NoError(t, err)
+	assert.Empty(t, retrieved.PreviousSessionID)
+}
+
+func TestProvenance_SQLite(t *testing.T) {
+	tempDB := filepath.Join(t.TempDir(), "test_provenance.db")
+
+	store, err := NewSQLiteSessionStore(tempDB)
+	require.NoError(t, err)
+	defer store.(*SQLiteSessionStore).Close()
+
+	session := &Session{ID: "provenance-session", CreatedAt: time.Now()}
+	session.RecordProvenance(Provenance{
+		Version:    "v1.0.0",
+		Commit:     "abc123",
+		Features:   []string{"markdown-renderer"},
+		Providers:  map[string]string{"anthropic": "v1.19.0"},
+		ConfigHash: "sha256:1234",
+		Since:      time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	})
+	session.AddMessage(UserMessage("hello"))
+	require.NoError(t, store.UpdateSession(t.Context(), session))
+
+	retrieved, err := store.GetSession(t.Context(), "provenance-session")
+	require.NoError(t, err)
+	assert.Equal(t, session.Provenance, retrieved.Provenance)
+
+	// Only appending messages still writes the provenance
+	session.RecordProvenance(Provenance{Version: "v1.1.0", Since: time.Date(2026, 2, 2, 3, 4, 5, 0, time.UTC)})
+	session.AddMessage(UserMessage("again"))
+	require.NoError(t, store.UpdateSession(t.Context(), session))
+
+	retrieved, err = store.GetSession(t.Context(), "provenance-session")
+	require.NoError(t, err)
+	require.Len(t, retrieved.Provenance, 2)
+	assert.Equal(t, session.Provenance, retrieved.Provenance)
+}
+
+func TestPostMortem_SQLite(t *testing.T) {
+	tempDB := filepath.Join(t.TempDir(), "test_post_mortem.db")
+
+	store, err := NewSQLiteSessionStore(tempDB)
+	require.NoError(t, err)
+	defer store.(*SQLiteSessionStore).Close()
+
+	session := &Session{ID: "post-mortem-session", CreatedAt: time.Now()}
+	session.AddMessage(UserMessage("hello"))
+	require.NoError(t, store.AddSession(t.Context(), session))
+
+	session.PostMortem = "The build failed because go.mod was missing."
+	session.AddMessage(UserMessage("again"))
+	require.NoError(t, store.UpdateSession(t.Context(), session))
+
+	retrieved, err := store.GetSession(t.Context(), "post-mortem-session")
+	require.NoError(t, err)
+	assert.Equal(t, session.PostMortem, retrieved.PostMortem)
+}
+
+func TestFeedback_SQLite(t *testing.T) {
+	tempDB := filepath.Join(t.TempDir(), "test_feedback.db")
+
+	store, err := NewSQLiteSessionStore(tempDB)
+	require.NoError(t, err)
+	defer store.(*SQLiteSessionStore).Close()
+
+	session := &Session{ID: "feedback-session", CreatedAt: time.Now()}
+	session.AddMessage(UserMessage("hello"))
+	session.AddMessage(&Message{AgentName: "root", Message: chat.Message{Role: chat.MessageRoleAssistant, Content: "hi"}})
+	require.NoError(t, store.AddSession(t.Context(), session))
+
+	// Feedback on a message already written is still saved
+	session.AddFeedback("root", "hi", FeedbackNegative, "Too terse")
+	require.NoError(t, store.UpdateSession(t.Context(), session))
+
+	retrieved, err := store.GetSession(t.Context(), "feedback-session")
+	require.NoError(t, err)
+	require.Len(t, retrieved.Feedback, 1)
+	assert.Equal(t, "hello", retrieved.Feedback[0].Request)
+	assert.Equal(t, "Too terse", retrieved.Feedback[0].Comment)
+}
+
+func TestCheckpoints_SQLite(t *testing.T) {
+	tempDB := filepath.Join(t.TempDir(), "test_checkpoints.db")
+
+	store, err := NewSQLiteSessionStore(tempDB)
+	require.NoError(t, err)
+	defer store.(*SQLiteSessionStore).Close()
+
+	session := &Session{ID: "checkpoints-session", CreatedAt: time.Now()}
+	session.AddMessage(UserMessage("hello"))
+	session.RecordCheckpoint()
+	session.AddNote("root", "billing", "Invoice of May")
+	require.NoError(t, store.AddSession(t.Context(), session))
+
+	fork, err := session.Fork(0)
+	require.NoError(t, err)
+	require.NoError(t, store.AddSession(t.Context(), fork))
+
+	retrieved, err := store.GetSession(t.Context(), "checkpoints-session")
+	require.NoError(t, err)
+	require.Len(t, retrieved.Checkpoints, 1)
+	assert.Equal(t, "hello", retrieved.Checkpoints[0].Prompt)
+	require.Len(t, retrieved.WorkingMemory, 1)
+	assert.Equal(t, "Invoice of May", retrieved.WorkingMemory[0].Content)
+
+	retrieved, err = store.GetSession(t.Context(), fork.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "checkpoints-session", retrieved.ForkedFrom)
+	assert.Empty(t, retrieved.Messages)
+}
+
+func TestUntrustedContent_SQLite(t *testing.T) {
+	tempDB := filepath.Join(t.TempDir(), "test_untrusted.db")
+
+	store, err := NewSQLiteSessionStore(tempDB)
+	require.NoError(t, err)
+	defer store.(*SQLiteSessionStore).Close()
+
+	session := &Session{ID: "untrusted-session", CreatedAt: time.Now()}
+	session.AddMessage(UserMessage("summarize this page"))
+	require.NoError(t, store.AddSession(t.Context(), session))
+
+	retrieved, err := store.GetSession(t.Context(), "untrusted-session")
+	require.NoError(t, err)
+	assert.False(t, retrieved.UntrustedContent)
+
+	// The marking is kept when a tool result from an untrusted source is added
+	session.AddMessage(&Message{AgentName: "root", Message: chat.Message{Role: chat.MessageRoleAssistant, Content: "fetched"}})
+	session.UntrustedContent = true
+	require.NoError(t, store.UpdateSession(t.Context(), session))
+
+	retrieved, err = store.GetSession(t.Context(), "untrusted-session")
+	require.NoError(t, err)
+	assert.True(t, retrieved.UntrustedContent)
+}
+
+func TestGetSessionSummaries_LastActivity(t *testing.T) {
+	tempDB := filepath.Join(t.TempDir(), "test_last_activity.db")
+
+	store, err := NewSQLiteSessionStore(tempDB)
+	require.NoError(t, err)
+	defer store.(*SQLiteSessionStore).Close()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	older := &Session{ID: "older", CreatedAt: now.Add(-2 * time.Hour)}
+	newer := &Session{ID: "newer", CreatedAt: now.Add(-time.Hour)}
+	require.NoError(t, store.AddSession(t.Context(), older))
+	require.NoError(t, store.AddSession(t.Context(), newer))
+
+	// A message in the older session makes it the most recently active
+	message := UserMessage("hello")
+	message.Message.CreatedAt = now.Format(time.RFC3339)
+	older.AddMessage(message)
+	require.NoError(t, store.UpdateSession(t.Context(), older))
+
+	summaries, err := store.GetSessionSummaries(t.Context())
+	require.NoError(t, err)
+	require.Len(t, summaries, 2)
+	assert.Equal(t, "older", summaries[0].ID)
+	assert.Equal(t, now, summaries[0].UpdatedAt)
+	assert.Equal(t, "newer", summaries[1].ID)
+	assert.Equal(t, newer.CreatedAt, summaries[1].UpdatedAt)
+}
+
+func TestSetSessionTitle(t *testing.T) {
+	tempDB := filepath.Join(t.TempDir(), "test_set_title.db")
+
+	sqliteStore, err := NewSQLiteSessionStore(tempDB)
+	require.NoError(t, err)
+	defer sqliteStore.(*SQLiteSessionStore).Close()
+
+	for name, store := range map[string]Store{
+		"sqlite":    sqliteStore,
+		"in-memory": NewInMemorySessionStore(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, store.AddSession(t.Context(), &Session{ID: "session", Title: "Old", CreatedAt: time.Now()}))
+
+			require.NoError(t, store.SetSessionTitle(t.Context(), "session", "New"))
+
+			retrieved, err := store.GetSession(t.Context(), "session")
+			require.NoError(t, err)
+			assert.Equal(t, "New", retrieved.Title)
+
+			require.ErrorIs(t, store.SetSessionTitle(t.Context(), "missing", "New"), ErrNotFound)
+			require.ErrorIs(t, store.SetSessionTitle(t.Context(), "", "New"), ErrEmptyID)
+		})
+	}
+}
+
+func TestUpdateSession_AppendsItems(t *testing.T) {
+	tempDB := filepath.Join(t.TempDir(), "test_append.db")
+
+	store, err := NewSQLiteSessionStore(tempDB)
+	require.NoError(t, err)
+	defer store.(*SQLiteSessionStore).Close()
+
+	testAgent := agent.New("test-agent", "test prompt")
+	session := &Session{ID: "session", CreatedAt: time.Now()}
+	session.AddMessage(UserMessage("Hello"))
+	require.NoError(t, store.UpdateSession(t.Context(), session))
+
+	// Only the new items are written
+	session.AddMessage(NewAgentMessage(testAgent, &chat.Message{Role: chat.MessageRoleAssistant, Content: "Hi there!"}))
+	session.Messages = append(session.Messages, Item{Summary: "They said hello"})
+	session.Title = "Greetings"
+	require.NoError(t, store.UpdateSession(t.Context(), session))
+	require.NoError(t, store.UpdateSession(t.Context(), session))
+
+	retrieved, err := store.GetSession(t.Context(), "session")
+	require.NoError(t, err)
+	require.Len(t, retrieved.Messages, 3)
+	assert.Equal(t, "Hello", retrieved.Messages[0].Message.Message.Content)
+	assert.Equal(t, "Hi there!", retrieved.Messages[1].Message.Message.Content)
+	assert.Equal(t, "They said hello", retrieved.Messages[2].Summary)
+	assert.Equal(t, "Greetings", retrieved.Title)
+
+	// Changed items rewrite the session
+	session.Messages = session.Messages[:1]
+	session.AddMessage(UserMessage("Bye"))
+	require.NoError(t, store.UpdateSession(t.Context(), session))
+
+	retrieved, err = store.GetSession(t.Context(), "session")
+	require.NoError(t, err)
+	require.Len(t, retrieved.Messages, 2)
+	assert.Equal(t, "Bye", retrieved.Messages[1].Message.Message.Content)
+
+	// A session deleted behind the store's back is written again whole
+	_, err = store.(*SQLiteSessionStore).db.ExecContext(t.Context(), "DELETE FROM sessions WHERE id = ?", "session")
+	require.NoError(t, err)
+	session.AddMessage(UserMessage("Still there?"))
+	require.NoError(t, store.UpdateSession(t.Context(), session))
+
+	retrieved, err = store.GetSession(t.Context(), "session")
+	require.NoError(t, err)
+	assert.Len(t, retrieved.Messages, 3)
+}
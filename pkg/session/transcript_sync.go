@@ -0,0 +1,136 @@
+package session
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// Transcript operations sent to remote clients.
+const (
+	// TranscriptOpAppend adds an item at the end of the transcript.
+	TranscriptOpAppend = "append"
+	// TranscriptOpReplace replaces the item at the given index.
+	TranscriptOpReplace = "replace"
+)
+
+// TranscriptOp is a patch operation on the transcript of a session.
+type TranscriptOp struct {
+	Op    string `json:"op"`
+	Index int    `json:"index"`
+	Item  Item   `json:"item"`
+}
+
+// TranscriptDelta brings a client's copy of a session transcript up to date.
+type TranscriptDelta struct {
+	// Token identifies the transcript the client has once the delta is applied.
+	// It is sent back by the client to get the next delta.
+	Token string `json:"token"`
+	// Reset tells the client to discard its transcript before applying the
+	// operations, because its token is unknown or the transcript was rewritten.
+	Reset bool           `json:"reset,omitempty"`
+	Ops   []TranscriptOp `json:"ops"`
+	// More is set when the delta was cut to the requested limit: the client
+	// should ask for the next delta right away.
+	More bool `json:"more,omitempty"`
+}
+
+// transcriptToken is what a resume token encodes: the number of items the
+// client has, a hash of all of them but the last one, and a hash of the last
+// one, which is the only item that changes while it's being produced.
+type transcriptToken struct {
+	Count  int    `json:"n"`
+	Prefix string `json:"p"`
+	Last   string `json:"l"`
+}
+
+// TranscriptDelta returns the operations that bring a transcript identified
+// by token up to date with the session. An empty token gets the whole
+// transcript. A positive limit caps the number of items in the delta.
+func (s *Session) TranscriptDelta(token string, limit int) TranscriptDelta {
+	hashes := make([]string, len(s.Messages))
+	for i := range s.Messages {
+		hashes[i] = hashItem(&s.Messages[i])
+	}
+
+	delta := TranscriptDelta{Ops: []TranscriptOp{}}
+	start := 0
+	if client, ok := decodeTranscriptToken(token); ok && client.Count <= len(hashes) && (client.Count == 0 || client.Prefix == chainHashes(hashes[:client.Count-1])) {
+		start = client.Count
+		if client.Count > 0 && client.Last != hashes[client.Count-1] {
+			delta.Ops = append(delta.Ops, TranscriptOp{Op: TranscriptOpReplace, Index: client.Count - 1, Item: s.Messages[client.Count-1]})
+		}
+	} else {
+		delta.Reset = true
+	}
+
+	end := len(s.Messages)
+	if limit > 0 && end-start > limit {
+		end = start + limit
+		delta.More = true
+	}
+	for i := start; i < end; i++ {
+		delta.Ops = append(delta.Ops, TranscriptOp{Op: TranscriptOpAppend, Index: i, Item: s.Messages[i]})
+	}
+
+	delta.Token = encodeTranscriptToken(hashes[:end])
+	return delta
+}
+
+// ApplyTranscriptDelta applies a delta to a client's copy of a transcript.
+func ApplyTranscriptDelta(items []Item, delta TranscriptDelta) []Item {
+	if delta.Reset {
+		items = nil
+	}
+	for _, op := range delta.Ops {
+		switch op.Op {
+		case TranscriptOpAppend:
+			items = append(items, op.Item)
+		case TranscriptOpReplace:
+			if op.Index >= 0 && op.Index < len(items) {
+				items[op.Index] = op.Item
+			}
+		}
+	}
+	return items
+}
+
+func hashItem(item *Item) string {
+	data, _ := json.Marshal(item)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func chainHashes(hashes []string) string {
+	h := sha256.New()
+	for _, hash := range hashes {
+		h.Write([]byte(hash))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func encodeTranscriptToken(hashes []string) string {
+	token := transcriptToken{Count: len(hashes)}
+	if len(hashes) > 0 {
+		token.Prefix = chainHashes(hashes[:len(hashes)-1])
+		token.Last = hashes[len(hashes)-1]
+	}
+	data, _ := json.Marshal(token)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeTranscriptToken(token string) (transcriptToken, bool) {
+	var decoded transcriptToken
+	if token == "" {
+		return decoded, false
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return decoded, false
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.Count < 0 {
+		return decoded, false
+	}
+	return decoded, true
+}
@@ -0,0 +1,92 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranscriptDelta(t *testing.T) {
+	t.Parallel()
+
+	sess := New()
+	sess.AddMessage(UserMessage("first"))
+	sess.AddMessage(UserMessage("second"))
+
+	// A new client gets the whole transcript
+	delta := sess.TranscriptDelta("", 0)
+	assert.True(t, delta.Reset)
+	assert.Len(t, delta.Ops, 2)
+	client := ApplyTranscriptDelta(nil, delta)
+	assert.Equal(t, sess.Messages, client)
+
+	// Nothing changed
+	unchanged := sess.TranscriptDelta(delta.Token, 0)
+	assert.False(t, unchanged.Reset)
+	assert.Empty(t, unchanged.Ops)
+	assert.Equal(t, delta.Token, unchanged.Token)
+
+	// Only new items are sent
+	sess.AddMessage(UserMessage("third"))
+	appended := sess.TranscriptDelta(delta.Token, 0)
+	assert.False(t, appended.Reset)
+	require.Len(t, appended.Ops, 1)
+	assert.Equal(t, TranscriptOp{Op: TranscriptOpAppend, Index: 2, Item: sess.Messages[2]}, appended.Ops[0])
+	client = ApplyTranscriptDelta(client, appended)
+	assert.Equal(t, sess.Messages, client)
+
+	// The last item changed in place
+	sess.Messages[2].Message.Message.Content = "third, updated"
+	replaced := sess.TranscriptDelta(appended.Token, 0)
+	require.Len(t, replaced.Ops, 1)
+	assert.Equal(t, TranscriptOpReplace, replaced.Ops[0].Op)
+	client = ApplyTranscriptDelta(client, replaced)
+	assert.Equal(t, sess.Messages, client)
+
+	// An older item was rewritten: the client starts over
+	sess.Messages[0].Message.Message.Content = "rewritten"
+	reset := sess.TranscriptDelta(replaced.Token, 0)
+	assert.True(t, reset.Reset)
+	assert.Len(t, reset.Ops, 3)
+	assert.Equal(t, sess.Messages, ApplyTranscriptDelta(client, reset))
+}
+
+func TestTranscriptDeltaLimit(t *testing.T) {
+	t.Parallel()
+
+	sess := New()
+	for range 5 {
+		sess.AddMessage(UserMessage("message"))
+	}
+
+	var client []Item
+	token := ""
+	pages := 0
+	for {
+		delta := sess.TranscriptDelta(token, 2)
+		assert.LessOrEqual(t, len(delta.Ops), 2)
+		client = ApplyTranscriptDelta(client, delta)
+		token = delta.Token
+		pages++
+		if !delta.More {
+			break
+		}
+	}
+
+	assert.Equal(t, 3, pages)
+	assert.Equal(t, sess.Messages, client)
+}
+
+func TestTranscriptDeltaInvalidToken(t *testing.T) {
+	t.Parallel()
+
+	sess := New()
+	sess.AddMessage(UserMessage("first"))
+
+	for _, token := range []string{"not a token", "eyJuIjo1fQ"} {
+		delta := sess.TranscriptDelta(token, 0)
+		assert.True(t, delta.Reset)
+		assert.Len(t, delta.Ops, 1)
+	}
+}
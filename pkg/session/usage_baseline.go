@@ -0,0 +1,63 @@
+package session
+
+// minBaselineSessions is the number of past sessions needed for a usage
+// baseline to be meaningful.
+const minBaselineSessions = 3
+
+// UsageBaseline is the typical usage of the past sessions of an agent.
+type UsageBaseline struct {
+	AgentName     string
+	Sessions      int
+	AverageTokens float64
+	AverageCost   float64
+}
+
+// NewUsageBaseline computes the usage baseline of an agent from session summaries.
+// The current session and sessions without usage are ignored.
+func NewUsageBaseline(summaries []Summary, agentName, currentSessionID string) UsageBaseline {
+	baseline := UsageBaseline{AgentName: agentName}
+
+	var totalTokens int64
+	var totalCost float64
+	for _, summary := range summaries {
+		if summary.ID == currentSessionID || summary.AgentName != agentName {
+			continue
+		}
+		tokens := summary.InputTokens + summary.OutputTokens
+		if tokens == 0 && summary.Cost == 0 {
+			continue
+		}
+
+		totalTokens += tokens
+		totalCost += summary.Cost
+		baseline.Sessions++
+	}
+
+	if baseline.Sessions > 0 {
+		baseline.AverageTokens = float64(totalTokens) / float64(baseline.Sessions)
+		baseline.AverageCost = totalCost / float64(baseline.Sessions)
+	}
+
+	return baseline
+}
+
+// Valid returns true if enough past sessions were found to compare against.
+func (b UsageBaseline) Valid() bool {
+	return b.Sessions >= minBaselineSessions
+}
+
+// CostRatio returns how the cost of a session compares to the average cost.
+func (b UsageBaseline) CostRatio(cost float64) (float64, bool) {
+	if !b.Valid() || b.AverageCost <= 0 {
+		return 0, false
+	}
+	return cost / b.AverageCost, true
+}
+
+// TokensRatio returns how the token count of a session compares to the average.
+func (b UsageBaseline) TokensRatio(tokens int64) (float64, bool) {
+	if !b.Valid() || b.AverageTokens <= 0 {
+		return 0, false
+	}
+	return float64(tokens) / b.AverageTokens, true
+}
@@ -0,0 +1,46 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUsageBaseline(t *testing.T) {
+	t.Parallel()
+
+	summaries := []Summary{
+		{ID: "current", AgentName: "root", InputTokens: 10_000, Cost: 10},
+		{ID: "1", AgentName: "root", InputTokens: 1000, OutputTokens: 1000, Cost: 1},
+		{ID: "2", AgentName: "root", InputTokens: 3000, OutputTokens: 1000, Cost: 2},
+		{ID: "3", AgentName: "root", InputTokens: 5000, OutputTokens: 1000, Cost: 3},
+		{ID: "empty", AgentName: "root"},
+		{ID: "other", AgentName: "other", InputTokens: 1_000_000, Cost: 100},
+	}
+
+	baseline := NewUsageBaseline(summaries, "root", "current")
+	assert.Equal(t, 3, baseline.Sessions)
+	assert.InDelta(t, 4000, baseline.AverageTokens, 1e-9)
+	assert.InDelta(t, 2, baseline.AverageCost, 1e-9)
+	assert.True(t, baseline.Valid())
+
+	ratio, ok := baseline.CostRatio(4.6)
+	assert.True(t, ok)
+	assert.InDelta(t, 2.3, ratio, 1e-9)
+
+	ratio, ok = baseline.TokensRatio(2000)
+	assert.True(t, ok)
+	assert.InDelta(t, 0.5, ratio, 1e-9)
+}
+
+func TestUsageBaselineNeedsEnoughSessions(t *testing.T) {
+	t.Parallel()
+
+	baseline := NewUsageBaseline([]Summary{
+		{ID: "1", AgentName: "root", InputTokens: 1000, Cost: 1},
+	}, "root", "")
+
+	assert.False(t, baseline.Valid())
+	_, ok := baseline.CostRatio(2)
+	assert.False(t, ok)
+}
@@ -0,0 +1,53 @@
+package session
+
+import (
+	"strings"
+	"time"
+)
+
+// maxNotes is the number of notes kept in the working memory of a session,
+// the oldest ones are dropped first.
+const maxNotes = 20
+
+// Note is what an agent wrote down for the agent it handed the conversation
+// off to: what was done, the decisions made and what is left to do.
+type Note struct {
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AddNote adds a note to the working memory shared by the agents of the
+// session.
+func (s *Session) AddNote(from, to, content string) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return
+	}
+
+	s.WorkingMemory = append(s.WorkingMemory, Note{
+		From:      from,
+		To:        to,
+		Content:   content,
+		CreatedAt: time.Now(),
+	})
+	if n := len(s.WorkingMemory); n > maxNotes {
+		s.WorkingMemory = s.WorkingMemory[n-maxNotes:]
+	}
+}
+
+// workingMemoryPrompt returns the working memory of the session as given to
+// the agents, or "" when it's empty.
+func (s *Session) workingMemoryPrompt() string {
+	if len(s.WorkingMemory) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("Working memory shared by the agents of the team, the notes they wrote when handing off the conversation, oldest first:\n")
+	for _, note := range s.WorkingMemory {
+		b.WriteString("\n- From " + note.From + " to " + note.To + ": " + note.Content)
+	}
+	return b.String()
+}
@@ -0,0 +1,42 @@
+package session
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/agent"
+	"github.com/docker/cagent/pkg/chat"
+)
+
+func TestAddNote(t *testing.T) {
+	t.Parallel()
+
+	s := New(WithUserMessage("I was charged twice"))
+	s.AddNote("root", "billing", "  ")
+	assert.Empty(t, s.WorkingMemory)
+
+	for i := range maxNotes + 2 {
+		s.AddNote("root", "billing", fmt.Sprintf("Note %d", i))
+	}
+	require.Len(t, s.WorkingMemory, maxNotes)
+	assert.Equal(t, "Note 2", s.WorkingMemory[0].Content)
+}
+
+func TestGetMessages_WorkingMemory(t *testing.T) {
+	t.Parallel()
+
+	s := New(WithUserMessage("I was charged twice"))
+	s.AddNote("root", "billing", "Customer 42, invoice of May")
+
+	var workingMemory []string
+	for _, msg := range s.GetMessages(agent.New("billing", "You handle invoices")) {
+		if msg.Role == chat.MessageRoleSystem && msg.Content != "You handle invoices" {
+			workingMemory = append(workingMemory, msg.Content)
+		}
+	}
+	require.NotEmpty(t, workingMemory)
+	assert.Contains(t, workingMemory[len(workingMemory)-1], "- From root to billing: Customer 42, invoice of May")
+}
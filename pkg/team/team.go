@@ -16,6 +16,17 @@ type Team struct {
 	agents      []*agent.Agent
 	ragManagers map[string]*rag.Manager
 	permissions *permissions.Checker
+	// providerLimits caps the concurrent requests to each provider
+	providerLimits map[string]int
+	// providerRateLimits caps the requests and tokens per minute of each provider
+	providerRateLimits map[string]RateLimit
+}
+
+// RateLimit caps the requests sent to a provider, and the tokens they use,
+// over any minute. Zero means no limit.
+type RateLimit struct {
+	RequestsPerMinute int `json:"requests_per_minute,omitempty"`
+	TokensPerMinute   int `json:"tokens_per_minute,omitempty"`
 }
 
 type Opt func(*Team)
@@ -38,6 +49,22 @@ func WithPermissions(checker *permissions.Checker) Opt {
 	}
 }
 
+// WithProviderLimits caps the number of concurrent requests to providers, by
+// provider name.
+func WithProviderLimits(limits map[string]int) Opt {
+	return func(t *Team) {
+		t.providerLimits = limits
+	}
+}
+
+// WithProviderRateLimits caps the requests and tokens per minute of
+// providers, by provider name.
+func WithProviderRateLimits(limits map[string]RateLimit) Opt {
+	return func(t *Team) {
+		t.providerRateLimits = limits
+	}
+}
+
 func New(opts ...Opt) *Team {
 	t := &Team{
 		ragManagers: make(map[string]*rag.Manager),
@@ -171,3 +198,15 @@ func (t *Team) StartRAGFileWatchers(ctx context.Context) {
 func (t *Team) Permissions() *permissions.Checker {
 	return t.permissions
 }
+
+// ProviderLimits returns the maximum number of concurrent requests to
+// providers, by provider name. Providers not listed aren't limited.
+func (t *Team) ProviderLimits() map[string]int {
+	return t.providerLimits
+}
+
+// ProviderRateLimits returns the requests and tokens per minute providers
+// are limited to, by provider name. Providers not listed aren't limited.
+func (t *Team) ProviderRateLimits() map[string]RateLimit {
+	return t.providerRateLimits
+}
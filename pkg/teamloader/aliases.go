@@ -0,0 +1,58 @@
+package teamloader
+
+import (
+	"context"
+
+	"github.com/docker/cagent/pkg/tools"
+)
+
+// WithToolAliases creates a toolset whose tools are renamed, so that two
+// toolsets can have tools of the same name. The aliases map the original
+// names to the new ones.
+func WithToolAliases(inner tools.ToolSet, aliases map[string]string) tools.ToolSet {
+	if len(aliases) == 0 {
+		return inner
+	}
+
+	return &aliasTools{
+		ToolSet: inner,
+		aliases: aliases,
+	}
+}
+
+type aliasTools struct {
+	tools.ToolSet
+	aliases map[string]string
+}
+
+// Unwrap returns the toolset whose tools are renamed.
+func (f *aliasTools) Unwrap() tools.ToolSet {
+	return f.ToolSet
+}
+
+func (f *aliasTools) Tools(ctx context.Context) ([]tools.Tool, error) {
+	allTools, err := f.ToolSet.Tools(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, tool := range allTools {
+		alias, ok := f.aliases[tool.Name]
+		if !ok {
+			continue
+		}
+
+		// The toolset is called with the original name
+		name, handler := tool.Name, tool.Handler
+		tool.Name = alias
+		if handler != nil {
+			tool.Handler = func(ctx context.Context, toolCall tools.ToolCall) (*tools.ToolCallResult, error) {
+				toolCall.Function.Name = name
+				return handler(ctx, toolCall)
+			}
+		}
+		allTools[i] = tool
+	}
+
+	return allTools, nil
+}
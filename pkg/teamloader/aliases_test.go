@@ -0,0 +1,45 @@
+package teamloader
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/tools"
+)
+
+func TestWithToolAliases_NoAliases(t *testing.T) {
+	inner := &mockToolSet{}
+
+	wrapped := WithToolAliases(inner, nil)
+
+	assert.Same(t, inner, wrapped)
+}
+
+func TestWithToolAliases(t *testing.T) {
+	var called string
+	inner := &mockToolSet{
+		toolsFunc: func(context.Context) ([]tools.Tool, error) {
+			handler := func(_ context.Context, toolCall tools.ToolCall) (*tools.ToolCallResult, error) {
+				called = toolCall.Function.Name
+				return tools.ResultSuccess("ok"), nil
+			}
+			return []tools.Tool{{Name: "search", Handler: handler}, {Name: "fetch", Handler: handler}}, nil
+		},
+	}
+
+	wrapped := WithToolAliases(inner, map[string]string{"search": "github_search"})
+
+	result, err := wrapped.Tools(t.Context())
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, "github_search", result[0].Name)
+	assert.Equal(t, "fetch", result[1].Name)
+
+	// The toolset is called with the original name
+	_, err = result[0].Handler(t.Context(), tools.ToolCall{Function: tools.FunctionCall{Name: "github_search"}})
+	require.NoError(t, err)
+	assert.Equal(t, "search", called)
+}
@@ -3,13 +3,14 @@ package teamloader
 import (
 	"context"
 	"log/slog"
-	"slices"
 
+	"github.com/docker/cagent/pkg/config/latest"
 	"github.com/docker/cagent/pkg/tools"
 )
 
 // WithToolsFilter creates a toolset that only includes the specified tools.
-// If no tool names are provided, all tools are included.
+// Names can be patterns such as `git_*`. If no tool names are provided, all
+// tools are included.
 func WithToolsFilter(inner tools.ToolSet, toolNames ...string) tools.ToolSet {
 	if len(toolNames) == 0 {
 		return inner
@@ -23,7 +24,8 @@ func WithToolsFilter(inner tools.ToolSet, toolNames ...string) tools.ToolSet {
 }
 
 // WithToolsExcludeFilter creates a toolset that excludes the specified tools.
-// If no tool names are provided, all tools are included.
+// Names can be patterns such as `git_*`. If no tool names are provided, all
+// tools are included.
 func WithToolsExcludeFilter(inner tools.ToolSet, toolNames ...string) tools.ToolSet {
 	if len(toolNames) == 0 {
 		return inner
@@ -42,6 +44,11 @@ type filterTools struct {
 	exclude   bool
 }
 
+// Unwrap returns the filtered toolset.
+func (f *filterTools) Unwrap() tools.ToolSet {
+	return f.ToolSet
+}
+
 func (f *filterTools) Tools(ctx context.Context) ([]tools.Tool, error) {
 	allTools, err := f.ToolSet.Tools(ctx)
 	if err != nil {
@@ -50,7 +57,7 @@ func (f *filterTools) Tools(ctx context.Context) ([]tools.Tool, error) {
 
 	var filtered []tools.Tool
 	for _, tool := range allTools {
-		contains := slices.Contains(f.toolNames, tool.Name)
+		contains := latest.MatchesToolPattern(f.toolNames, tool.Name)
 
 		// Exclude mode: keep only tools NOT in the list
 		// Include mode: keep only tools in the list
@@ -119,3 +119,18 @@ func TestWithToolsFilter_CaseSensitive(t *testing.T) {
 	require.Len(t, result, 1)
 	assert.Equal(t, "tool1", result[0].Name)
 }
+
+func TestWithToolsFilter_Patterns(t *testing.T) {
+	inner := &mockToolSet{
+		toolsFunc: func(context.Context) ([]tools.Tool, error) {
+			return []tools.Tool{{Name: "git_status"}, {Name: "git_push"}, {Name: "search"}}, nil
+		},
+	}
+
+	wrapped := WithToolsExcludeFilter(WithToolsFilter(inner, "git_*"), "git_push")
+
+	result, err := wrapped.Tools(t.Context())
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, "git_status", result[0].Name)
+}
@@ -0,0 +1,55 @@
+package teamloader
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+
+	"github.com/docker/cagent/pkg/config"
+	"github.com/docker/cagent/pkg/config/latest"
+	"github.com/docker/cagent/pkg/guardrails"
+	"github.com/docker/cagent/pkg/model/provider"
+)
+
+// getGuardrailsForAgent creates the guardrails of an agent. Moderation
+// guardrails without a model use the agent's model.
+func getGuardrailsForAgent(ctx context.Context, cfg *latest.Config, a *latest.AgentConfig, agentModel provider.Provider, autoModelFn func() latest.ModelConfig, runConfig *config.RuntimeConfig) ([]*guardrails.Guardrail, error) {
+	var agentGuardrails []*guardrails.Guardrail
+	for _, g := range a.Guardrails {
+		guardrail := &guardrails.Guardrail{
+			Name:   cmp.Or(g.Name, g.Type),
+			Action: guardrails.Action(cmp.Or(g.Action, string(guardrails.ActionBlock))),
+		}
+		for _, target := range g.ApplyTo {
+			guardrail.Targets = append(guardrail.Targets, guardrails.Target(target))
+		}
+
+		var err error
+		switch g.Type {
+		case "regex":
+			guardrail.Checker, err = guardrails.Regex(g.Patterns)
+		case "keywords":
+			guardrail.Checker, err = guardrails.Keywords(g.Keywords)
+		case "pii":
+			guardrail.Checker, err = guardrails.PII(g.PII)
+		case "moderation":
+			model := agentModel
+			if g.Model != "" {
+				models, modelErr := getModelsForAgent(ctx, cfg, &latest.AgentConfig{Name: a.Name, Model: g.Model}, autoModelFn, runConfig)
+				if modelErr != nil {
+					return nil, fmt.Errorf("failed to get the model of guardrail %s: %w", guardrail.Name, modelErr)
+				}
+				model = models[0]
+			}
+			guardrail.Checker = guardrails.Moderation(model, g.Policy)
+		default:
+			err = fmt.Errorf("unknown type %q", g.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid guardrail %s: %w", guardrail.Name, err)
+		}
+
+		agentGuardrails = append(agentGuardrails, guardrail)
+	}
+	return agentGuardrails, nil
+}
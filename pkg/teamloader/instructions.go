@@ -25,3 +25,8 @@ type replaceInstruction struct {
 func (a replaceInstruction) Instructions() string {
 	return strings.Replace(a.instruction, "{ORIGINAL_INSTRUCTIONS}", a.ToolSet.Instructions(), 1)
 }
+
+// Unwrap returns the toolset whose instructions are replaced.
+func (a replaceInstruction) Unwrap() tools.ToolSet {
+	return a.ToolSet
+}
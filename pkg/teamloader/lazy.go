@@ -0,0 +1,153 @@
+package teamloader
+
+import (
+	"context"
+	"sync"
+
+	"github.com/docker/cagent/pkg/config/latest"
+	"github.com/docker/cagent/pkg/tools"
+)
+
+// isSlowToCreate returns true for the toolsets whose creation does more than
+// reading their configuration: MCP servers from the catalog are looked up
+// over the network and memory toolsets open their database. They are
+// created when they are started, in the background, instead of delaying the
+// start of cagent.
+func isSlowToCreate(toolset latest.Toolset) bool {
+	switch toolset.Type {
+	case "mcp":
+		return toolset.Ref != ""
+	case "memory":
+		return true
+	default:
+		return false
+	}
+}
+
+// lazyToolSet creates its toolset when it is started. A failed creation is
+// returned by Start, like a toolset failing to start, and retried on the next
+// call.
+type lazyToolSet struct {
+	create func(ctx context.Context) (tools.ToolSet, error)
+
+	mu                 sync.Mutex
+	toolSet            tools.ToolSet
+	elicitationHandler tools.ElicitationHandler
+	oauthHandler       func()
+	managedOAuth       bool
+	statusHandler      tools.StatusHandler
+	samplingHandler    tools.SamplingHandler
+}
+
+func newLazyToolSet(create func(ctx context.Context) (tools.ToolSet, error)) *lazyToolSet {
+	return &lazyToolSet{create: create}
+}
+
+func (l *lazyToolSet) Start(ctx context.Context) error {
+	l.mu.Lock()
+	if l.toolSet == nil {
+		toolSet, err := l.create(ctx)
+		if err != nil {
+			l.mu.Unlock()
+			return err
+		}
+		if l.elicitationHandler != nil {
+			toolSet.SetElicitationHandler(l.elicitationHandler)
+		}
+		if l.oauthHandler != nil {
+			toolSet.SetOAuthSuccessHandler(l.oauthHandler)
+		}
+		toolSet.SetManagedOAuth(l.managedOAuth)
+		if l.statusHandler != nil {
+			toolSet.SetStatusHandler(l.statusHandler)
+		}
+		if l.samplingHandler != nil {
+			toolSet.SetSamplingHandler(l.samplingHandler)
+		}
+		l.toolSet = toolSet
+	}
+	toolSet := l.toolSet
+	l.mu.Unlock()
+
+	return toolSet.Start(ctx)
+}
+
+func (l *lazyToolSet) Stop(ctx context.Context) error {
+	toolSet := l.created()
+	if toolSet == nil {
+		return nil
+	}
+	return toolSet.Stop(ctx)
+}
+
+func (l *lazyToolSet) Tools(ctx context.Context) ([]tools.Tool, error) {
+	toolSet := l.created()
+	if toolSet == nil {
+		return nil, nil
+	}
+	return toolSet.Tools(ctx)
+}
+
+func (l *lazyToolSet) Instructions() string {
+	toolSet := l.created()
+	if toolSet == nil {
+		return ""
+	}
+	return toolSet.Instructions()
+}
+
+func (l *lazyToolSet) SetElicitationHandler(handler tools.ElicitationHandler) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.elicitationHandler = handler
+	if l.toolSet != nil {
+		l.toolSet.SetElicitationHandler(handler)
+	}
+}
+
+func (l *lazyToolSet) SetOAuthSuccessHandler(handler func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.oauthHandler = handler
+	if l.toolSet != nil {
+		l.toolSet.SetOAuthSuccessHandler(handler)
+	}
+}
+
+func (l *lazyToolSet) SetManagedOAuth(managed bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.managedOAuth = managed
+	if l.toolSet != nil {
+		l.toolSet.SetManagedOAuth(managed)
+	}
+}
+
+func (l *lazyToolSet) SetStatusHandler(handler tools.StatusHandler) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.statusHandler = handler
+	if l.toolSet != nil {
+		l.toolSet.SetStatusHandler(handler)
+	}
+}
+
+func (l *lazyToolSet) SetSamplingHandler(handler tools.SamplingHandler) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.samplingHandler = handler
+	if l.toolSet != nil {
+		l.toolSet.SetSamplingHandler(handler)
+	}
+}
+
+func (l *lazyToolSet) created() tools.ToolSet {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.toolSet
+}
+
+// Unwrap returns the toolset once it's created, nil before.
+func (l *lazyToolSet) Unwrap() tools.ToolSet {
+	return l.created()
+}
@@ -0,0 +1,62 @@
+package teamloader
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/config"
+	"github.com/docker/cagent/pkg/config/latest"
+	"github.com/docker/cagent/pkg/tools"
+	"github.com/docker/cagent/pkg/tools/builtin"
+)
+
+func TestGetToolsForAgent_CreatesSlowToolsetsOnStart(t *testing.T) {
+	t.Parallel()
+
+	var created int
+	registry := NewToolsetRegistry()
+	registry.Register("memory", func(context.Context, latest.Toolset, string, *config.RuntimeConfig) (tools.ToolSet, error) {
+		created++
+		return builtin.NewThinkTool(), nil
+	})
+
+	a := &latest.AgentConfig{Toolsets: []latest.Toolset{{Type: "memory"}}}
+	runConfig := config.RuntimeConfig{EnvProviderForTests: &noEnvProvider{}}
+
+	got, warnings := getToolsForAgent(t.Context(), a, ".", &runConfig, registry)
+	require.Empty(t, warnings)
+	require.Len(t, got, 1)
+	assert.Zero(t, created, "the toolset isn't created while loading the agent")
+
+	require.NoError(t, got[0].Start(t.Context()))
+	require.NoError(t, got[0].Start(t.Context()))
+	assert.Equal(t, 1, created)
+
+	allTools, err := got[0].Tools(t.Context())
+	require.NoError(t, err)
+	assert.NotEmpty(t, allTools)
+}
+
+func TestLazyToolSet_RetriesFailedCreation(t *testing.T) {
+	t.Parallel()
+
+	fail := true
+	lazy := newLazyToolSet(func(context.Context) (tools.ToolSet, error) {
+		if fail {
+			return nil, errors.New("catalog unreachable")
+		}
+		return builtin.NewThinkTool(), nil
+	})
+
+	require.ErrorContains(t, lazy.Start(t.Context()), "catalog unreachable")
+	assert.Empty(t, lazy.Instructions())
+	require.NoError(t, lazy.Stop(t.Context()), "stopping a toolset that was never created is a no-op")
+
+	fail = false
+	require.NoError(t, lazy.Start(t.Context()))
+	assert.NotEmpty(t, lazy.Instructions())
+}
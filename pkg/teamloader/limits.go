@@ -0,0 +1,135 @@
+package teamloader
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/docker/cagent/pkg/config/latest"
+	"github.com/docker/cagent/pkg/tools"
+)
+
+// WithToolLimits creates a toolset whose calls are stopped once they run for
+// longer than their timeout, and whose results are cut once larger than
+// their max size.
+func WithToolLimits(inner tools.ToolSet, limits latest.ToolLimits) tools.ToolSet {
+	if limits.IsEmpty() {
+		return inner
+	}
+
+	return &limitedTools{
+		ToolSet: inner,
+		limits:  limits,
+	}
+}
+
+type limitedTools struct {
+	tools.ToolSet
+	limits latest.ToolLimits
+}
+
+// Unwrap returns the toolset whose calls are limited.
+func (f *limitedTools) Unwrap() tools.ToolSet {
+	return f.ToolSet
+}
+
+func (f *limitedTools) Tools(ctx context.Context) ([]tools.Tool, error) {
+	allTools, err := f.ToolSet.Tools(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, tool := range allTools {
+		limits := f.limits.For(tool.Name)
+		if tool.Handler == nil || (limits.Timeout == 0 && limits.MaxResultSize == 0) {
+			continue
+		}
+
+		handler := tool.Handler
+		if limits.Timeout > 0 {
+			handler = withTimeout(handler, time.Duration(limits.Timeout)*time.Second)
+		}
+		if limits.MaxResultSize > 0 {
+			handler = withMaxResultSize(handler, limits.MaxResultSize)
+		}
+		allTools[i].Handler = handler
+	}
+
+	return allTools, nil
+}
+
+// toolLimitError is what the model is told when a call breached a limit.
+type toolLimitError struct {
+	Error          string `json:"error"`
+	Tool           string `json:"tool"`
+	TimeoutSeconds int    `json:"timeout_seconds"`
+	Message        string `json:"message"`
+}
+
+// withTimeout stops the calls running for longer than timeout. The handler is
+// canceled and the model is told, even when the handler doesn't stop.
+func withTimeout(handler tools.ToolHandler, timeout time.Duration) tools.ToolHandler {
+	return func(ctx context.Context, toolCall tools.ToolCall) (*tools.ToolCallResult, error) {
+		callCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		type outcome struct {
+			res *tools.ToolCallResult
+			err error
+		}
+		done := make(chan outcome, 1)
+		go func() {
+			res, err := handler(callCtx, toolCall)
+			done <- outcome{res, err}
+		}()
+
+		select {
+		case o := <-done:
+			if o.err == nil || !errors.Is(callCtx.Err(), context.DeadlineExceeded) || ctx.Err() != nil {
+				return o.res, o.err
+			}
+		case <-callCtx.Done():
+			// The run was canceled, not the call
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+		}
+
+		return timeoutResult(toolCall.Function.Name, timeout), nil
+	}
+}
+
+func timeoutResult(name string, timeout time.Duration) *tools.ToolCallResult {
+	buf, _ := json.Marshal(toolLimitError{
+		Error:          "timeout",
+		Tool:           name,
+		TimeoutSeconds: int(timeout.Seconds()),
+		Message:        fmt.Sprintf("The call ran for more than %s and was stopped. Try a call that does less, for example one that searches fewer files.", timeout),
+	})
+	return tools.ResultError(string(buf))
+}
+
+// withMaxResultSize cuts the outputs larger than maxSize bytes, and says so at
+// their end.
+func withMaxResultSize(handler tools.ToolHandler, maxSize int) tools.ToolHandler {
+	return func(ctx context.Context, toolCall tools.ToolCall) (*tools.ToolCallResult, error) {
+		res, err := handler(ctx, toolCall)
+		if err != nil || res == nil || len(res.Output) <= maxSize {
+			return res, err
+		}
+
+		// Don't split a character
+		cut := maxSize
+		for cut > 0 && !utf8.RuneStart(res.Output[cut]) {
+			cut--
+		}
+		output := res.Output[:cut]
+
+		truncated := *res
+		truncated.Output = fmt.Sprintf("%s\n\n[Output truncated: the first %d of %d bytes are shown, the limit of %s is %d bytes.]", output, len(output), len(res.Output), toolCall.Function.Name, maxSize)
+		return &truncated, nil
+	}
+}
@@ -0,0 +1,100 @@
+package teamloader
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/config/latest"
+	"github.com/docker/cagent/pkg/tools"
+)
+
+// limitsToolSet has a tool that runs until it's canceled, and one that
+// returns output.
+func limitsToolSet(output string) *mockToolSet {
+	return &mockToolSet{
+		toolsFunc: func(context.Context) ([]tools.Tool, error) {
+			return []tools.Tool{
+				{Name: "find", Handler: func(ctx context.Context, _ tools.ToolCall) (*tools.ToolCallResult, error) {
+					<-ctx.Done()
+					return nil, ctx.Err()
+				}},
+				{Name: "read", Handler: func(context.Context, tools.ToolCall) (*tools.ToolCallResult, error) {
+					return tools.ResultSuccess(output), nil
+				}},
+			}, nil
+		},
+	}
+}
+
+func callTool(t *testing.T, ctx context.Context, toolSet tools.ToolSet, name string) (*tools.ToolCallResult, error) {
+	t.Helper()
+
+	allTools, err := toolSet.Tools(t.Context())
+	require.NoError(t, err)
+	for _, tool := range allTools {
+		if tool.Name == name {
+			return tool.Handler(ctx, tools.ToolCall{Function: tools.FunctionCall{Name: name}})
+		}
+	}
+	require.FailNow(t, "unknown tool", name)
+	return nil, nil
+}
+
+func TestWithToolLimits_NoLimits(t *testing.T) {
+	inner := &mockToolSet{}
+
+	wrapped := WithToolLimits(inner, latest.ToolLimits{})
+
+	assert.Same(t, inner, wrapped)
+}
+
+func TestWithToolLimits_Timeout(t *testing.T) {
+	wrapped := WithToolLimits(limitsToolSet("ok"), latest.ToolLimits{Timeout: 1})
+
+	start := time.Now()
+	result, err := callTool(t, t.Context(), wrapped, "find")
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.True(t, result.IsError)
+
+	var limitErr toolLimitError
+	require.NoError(t, json.Unmarshal([]byte(result.Output), &limitErr))
+	assert.Equal(t, "timeout", limitErr.Error)
+	assert.Equal(t, "find", limitErr.Tool)
+	assert.Equal(t, 1, limitErr.TimeoutSeconds)
+
+	// Calls ending in time aren't changed
+	result, err = callTool(t, t.Context(), wrapped, "read")
+	require.NoError(t, err)
+	assert.Equal(t, tools.ResultSuccess("ok"), result)
+}
+
+func TestWithToolLimits_Canceled(t *testing.T) {
+	wrapped := WithToolLimits(limitsToolSet("ok"), latest.ToolLimits{Timeout: 60})
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+
+	// The run was canceled, not the call: the error is kept
+	_, err := callTool(t, ctx, wrapped, "find")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestWithToolLimits_MaxResultSize(t *testing.T) {
+	output := strings.Repeat("é", 10)
+	wrapped := WithToolLimits(limitsToolSet(output), latest.ToolLimits{
+		MaxResultSize: 100,
+		Tools:         map[string]latest.ToolLimits{"read": {MaxResultSize: 5}},
+	})
+
+	result, err := callTool(t, t.Context(), wrapped, "read")
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, "éé\n\n[Output truncated: the first 4 of 20 bytes are shown, the limit of read is 5 bytes.]", result.Output)
+}
@@ -1,6 +1,7 @@
 package teamloader
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"os"
@@ -12,12 +13,16 @@ import (
 	"github.com/docker/cagent/pkg/environment"
 	"github.com/docker/cagent/pkg/gateway"
 	"github.com/docker/cagent/pkg/js"
+	"github.com/docker/cagent/pkg/knowledge"
 	"github.com/docker/cagent/pkg/memory/database/sqlite"
 	"github.com/docker/cagent/pkg/path"
+	"github.com/docker/cagent/pkg/paths"
+	"github.com/docker/cagent/pkg/rag"
 	"github.com/docker/cagent/pkg/tools"
 	"github.com/docker/cagent/pkg/tools/a2a"
 	"github.com/docker/cagent/pkg/tools/builtin"
 	"github.com/docker/cagent/pkg/tools/mcp"
+	"github.com/docker/cagent/pkg/websearch"
 )
 
 // ToolsetCreator is a function that creates a toolset based on the provided configuration
@@ -65,10 +70,12 @@ func NewDefaultToolsetRegistry() *ToolsetRegistry {
 	r.Register("script", createScriptTool)
 	r.Register("filesystem", createFilesystemTool)
 	r.Register("fetch", createFetchTool)
+	r.Register("web_search", createWebSearchTool)
 	r.Register("mcp", createMCPTool)
 	r.Register("api", createAPITool)
 	r.Register("a2a", createA2ATool)
 	r.Register("lsp", createLSPTool)
+	r.Register("knowledge", createKnowledgeTool)
 	return r
 }
 
@@ -89,9 +96,15 @@ func createMemoryTool(_ context.Context, toolset latest.Toolset, parentDir strin
 		memoryPath = parentDir
 	}
 
-	validatedMemoryPath, err := path.ValidatePathInDirectory(toolset.Path, memoryPath)
-	if err != nil {
-		return nil, fmt.Errorf("invalid memory database path: %w", err)
+	var validatedMemoryPath string
+	if toolset.Path == "" {
+		// Memories are kept in the data directory by default
+		validatedMemoryPath = filepath.Join(paths.GetDataDir(), "memory.db")
+	} else {
+		var err error
+		if validatedMemoryPath, err = path.ValidatePathInDirectory(toolset.Path, memoryPath); err != nil {
+			return nil, fmt.Errorf("invalid memory database path: %w", err)
+		}
 	}
 	if err := os.MkdirAll(filepath.Dir(validatedMemoryPath), 0o700); err != nil {
 		return nil, fmt.Errorf("failed to create memory database directory: %w", err)
@@ -102,7 +115,27 @@ func createMemoryTool(_ context.Context, toolset latest.Toolset, parentDir strin
 		return nil, fmt.Errorf("failed to create memory database: %w", err)
 	}
 
-	return builtin.NewMemoryTool(db), nil
+	scope := toolset.Scope
+	if scope == "project" {
+		project, err := filepath.Abs(cmp.Or(runConfig.WorkingDir, parentDir))
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve the project of the memories: %w", err)
+		}
+		scope = "project:" + project
+	}
+
+	return builtin.NewMemoryTool(db, builtin.WithMemoryScope(scope), builtin.WithRecall(toolset.Recall)), nil
+}
+
+func createKnowledgeTool(_ context.Context, toolset latest.Toolset, _ string, runConfig *config.RuntimeConfig) (tools.ToolSet, error) {
+	kb, err := knowledge.Load(knowledge.Dir(), cmp.Or(toolset.Name, knowledge.DefaultName))
+	if err != nil {
+		return nil, err
+	}
+
+	return builtin.NewKnowledgeTool(func(ctx context.Context) (*rag.Manager, error) {
+		return kb.NewManager(ctx, runConfig.EnvProvider(), runConfig.ModelsGateway)
+	}), nil
 }
 
 func createThinkTool(_ context.Context, _ latest.Toolset, _ string, _ *config.RuntimeConfig) (tools.ToolSet, error) {
@@ -114,12 +147,24 @@ func createShellTool(ctx context.Context, toolset latest.Toolset, _ string, runC
 	if err != nil {
 		return nil, fmt.Errorf("failed to expand the tool's environment variables: %w", err)
 	}
-	env = append(env, os.Environ()...)
+	if len(toolset.AllowedEnv) > 0 {
+		env = append(env, builtin.FilterEnv(os.Environ(), toolset.AllowedEnv)...)
+	} else {
+		env = append(env, os.Environ()...)
+	}
 
 	// Expand sandbox paths with JS interpolation (e.g., ${env.HOME}:ro)
 	sandboxConfig := expandSandboxPaths(ctx, toolset.Sandbox, runConfig.EnvProvider())
 
-	return builtin.NewShellTool(env, runConfig, sandboxConfig), nil
+	var opts []builtin.ShellToolOption
+	if len(toolset.AllowedCommands) > 0 {
+		opts = append(opts, builtin.WithAllowedCommands(toolset.AllowedCommands))
+	}
+	if toolset.ConfineWorkingDir {
+		opts = append(opts, builtin.WithConfinedWorkingDir())
+	}
+
+	return builtin.NewShellTool(env, runConfig, sandboxConfig, opts...), nil
 }
 
 // expandSandboxPaths expands environment variable references in sandbox paths.
@@ -137,8 +182,9 @@ func expandSandboxPaths(ctx context.Context, sandbox *latest.SandboxConfig, envP
 	}
 
 	return &latest.SandboxConfig{
-		Image: sandbox.Image,
-		Paths: expandedPaths,
+		Image:   sandbox.Image,
+		Paths:   expandedPaths,
+		Network: sandbox.Network,
 	}
 }
 
@@ -207,9 +253,25 @@ func createFetchTool(_ context.Context, toolset latest.Toolset, _ string, _ *con
 		timeout := time.Duration(toolset.Timeout) * time.Second
 		opts = append(opts, builtin.WithTimeout(timeout))
 	}
+	if toolset.MaxSize > 0 {
+		opts = append(opts, builtin.WithMaxSize(int64(toolset.MaxSize)))
+	}
 	return builtin.NewFetchTool(opts...), nil
 }
 
+func createWebSearchTool(ctx context.Context, toolset latest.Toolset, _ string, runConfig *config.RuntimeConfig) (tools.ToolSet, error) {
+	var apiKey string
+	if env := websearch.APIKeyEnv(toolset.Engine); env != "" {
+		apiKey, _ = runConfig.EnvProvider().Get(ctx, env)
+	}
+
+	engine, err := websearch.New(toolset.Engine, toolset.URL, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	return builtin.NewWebSearchTool(engine), nil
+}
+
 func createMCPTool(ctx context.Context, toolset latest.Toolset, _ string, runConfig *config.RuntimeConfig) (tools.ToolSet, error) {
 	envProvider := runConfig.EnvProvider()
 
@@ -224,7 +286,7 @@ func createMCPTool(ctx context.Context, toolset latest.Toolset, _ string, runCon
 
 		// TODO(dga): until the MCP Gateway supports oauth with cagent, we fetch the remote url and directly connect to it.
 		if serverSpec.Type == "remote" {
-			return mcp.NewRemoteToolset(toolset.Name, serverSpec.Remote.URL, serverSpec.Remote.TransportType, nil), nil
+			return mcp.NewRemoteToolset(toolset.Name, serverSpec.Remote.URL, serverSpec.Remote.TransportType, nil, mcp.WithResources(toolset.Resources)), nil
 		}
 
 		env, err := environment.ExpandAll(ctx, environment.ToValues(toolset.Env), envProvider)
@@ -237,7 +299,7 @@ func createMCPTool(ctx context.Context, toolset latest.Toolset, _ string, runCon
 			envProvider,
 		)
 
-		return mcp.NewGatewayToolset(ctx, toolset.Name, mcpServerName, toolset.Config, envProvider, runConfig.WorkingDir)
+		return mcp.NewGatewayToolset(ctx, toolset.Name, mcpServerName, toolset.Config, envProvider, runConfig.WorkingDir, mcp.WithResources(toolset.Resources))
 
 	// STDIO MCP Server from shell command
 	case toolset.Command != "":
@@ -245,9 +307,17 @@ func createMCPTool(ctx context.Context, toolset latest.Toolset, _ string, runCon
 		if err != nil {
 			return nil, fmt.Errorf("failed to expand the tool's environment variables: %w", err)
 		}
-		env = append(env, os.Environ()...)
 
-		return mcp.NewToolsetCommand(toolset.Name, toolset.Command, toolset.Args, env, runConfig.WorkingDir), nil
+		// The server runs in an ephemeral container, with only the variables
+		// of the toolset: the environment of cagent is only docker's
+		if toolset.Sandbox != nil {
+			sandbox := expandSandboxPaths(ctx, toolset.Sandbox, envProvider)
+			command, args := builtin.SandboxCommand(sandbox, runConfig.WorkingDir, env, toolset.Command, toolset.Args)
+			return mcp.NewToolsetCommand(toolset.Name, command, args, os.Environ(), runConfig.WorkingDir, mcp.WithResources(toolset.Resources), mcp.WithContainer(sandbox.Image)), nil
+		}
+
+		env = append(env, os.Environ()...)
+		return mcp.NewToolsetCommand(toolset.Name, toolset.Command, toolset.Args, env, runConfig.WorkingDir, mcp.WithResources(toolset.Resources)), nil
 
 	// Remote MCP Server
 	case toolset.Remote.URL != "":
@@ -256,7 +326,7 @@ func createMCPTool(ctx context.Context, toolset latest.Toolset, _ string, runCon
 		headers := expander.ExpandMap(ctx, toolset.Remote.Headers)
 		url := expander.Expand(ctx, toolset.Remote.URL)
 
-		return mcp.NewRemoteToolset(toolset.Name, url, toolset.Remote.TransportType, headers), nil
+		return mcp.NewRemoteToolset(toolset.Name, url, toolset.Remote.TransportType, headers, mcp.WithResources(toolset.Resources)), nil
 
 	default:
 		return nil, fmt.Errorf("mcp toolset requires either ref, command, or remote configuration")
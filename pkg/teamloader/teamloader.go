@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/docker/cagent/pkg/agent"
 	"github.com/docker/cagent/pkg/config"
@@ -25,9 +26,14 @@ import (
 
 var defaultMaxTokens int64 = 32000
 
+// defaultSummaryThreshold is the estimated number of tokens above which tool
+// results are summarized when an agent enables summarize_tool_results.
+const defaultSummaryThreshold = 4000
+
 type loadOptions struct {
 	modelOverrides  []string
 	toolsetRegistry *ToolsetRegistry
+	onPhase         func(phase string)
 }
 
 type Opt func(*loadOptions) error
@@ -47,6 +53,17 @@ func WithToolsetRegistry(registry *ToolsetRegistry) Opt {
 	}
 }
 
+// WithPhaseHook calls hook at the end of each phase of the loading, e.g. to
+// measure how long they take.
+func WithPhaseHook(hook func(phase string)) Opt {
+	return func(opts *loadOptions) error {
+		if hook != nil {
+			opts.onPhase = hook
+		}
+		return nil
+	}
+}
+
 // LoadResult contains the result of loading an agent team, including
 // the team and configuration needed for runtime model switching.
 type LoadResult struct {
@@ -55,6 +72,8 @@ type LoadResult struct {
 	Providers map[string]latest.ProviderConfig
 	// AgentDefaultModels maps agent names to their configured default model references
 	AgentDefaultModels map[string]string
+	// ConfigHash is the hash of the configuration, once the model overrides are applied
+	ConfigHash string
 }
 
 // Load loads an agent team from the given source
@@ -71,6 +90,7 @@ func Load(ctx context.Context, agentSource config.Source, runConfig *config.Runt
 func LoadWithConfig(ctx context.Context, agentSource config.Source, runConfig *config.RuntimeConfig, opts ...Opt) (*LoadResult, error) {
 	var loadOpts loadOptions
 	loadOpts.toolsetRegistry = NewDefaultToolsetRegistry()
+	loadOpts.onPhase = func(string) {}
 
 	for _, o := range opts {
 		if err := o(&loadOpts); err != nil {
@@ -83,16 +103,23 @@ func LoadWithConfig(ctx context.Context, agentSource config.Source, runConfig *c
 	if err != nil {
 		return nil, err
 	}
+	loadOpts.onPhase("load config")
 
 	// Resolve model aliases (e.g., "claude-sonnet-4-5" -> "claude-sonnet-4-5-20250929")
 	// This ensures the sidebar and other UI elements show the actual model being used.
 	config.ResolveModelAliases(ctx, cfg)
+	loadOpts.onPhase("resolve model aliases")
 
 	// Apply model overrides from CLI flags before checking required env vars
 	if err := config.ApplyModelOverrides(cfg, loadOpts.modelOverrides); err != nil {
 		return nil, err
 	}
 
+	configHash, err := config.Hash(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("hashing config: %w", err)
+	}
+
 	// Early check for required env vars before loading models and tools.
 	env := runConfig.EnvProvider()
 	if err := config.CheckRequiredEnvVars(ctx, cfg, runConfig.ModelsGateway, env); err != nil {
@@ -110,6 +137,7 @@ func LoadWithConfig(ctx context.Context, agentSource config.Source, runConfig *c
 	if err != nil {
 		return nil, fmt.Errorf("failed to create RAG managers: %w", err)
 	}
+	loadOpts.onPhase("create RAG managers")
 
 	// Load agents
 	var agents []*agent.Agent
@@ -139,6 +167,13 @@ func LoadWithConfig(ctx context.Context, agentSource config.Source, runConfig *c
 			agent.WithCommands(expander.ExpandCommands(ctx, agentConfig.Commands)),
 			agent.WithSkillsEnabled(skillsEnabled),
 			agent.WithHooks(agentConfig.Hooks),
+			agent.WithConfirmUntrusted(agentConfig.ConfirmUntrusted),
+			agent.WithGovernor(agentConfig.Governor),
+			agent.WithPriority(agentConfig.Priority),
+			agent.WithMaxParallelToolCalls(agentConfig.MaxParallelToolCalls),
+			agent.WithMaxRepeatedToolCalls(agentConfig.MaxRepeatedToolCalls),
+			agent.WithMaxTurnTokens(agentConfig.MaxTurnTokens),
+			agent.WithStreamTimeout(time.Duration(agentConfig.StreamTimeout) * time.Second),
 		}
 
 		models, err := getModelsForAgent(ctx, cfg, &agentConfig, autoModel, runConfig)
@@ -149,6 +184,66 @@ func LoadWithConfig(ctx context.Context, agentSource config.Source, runConfig *c
 			opts = append(opts, agent.WithModel(model))
 		}
 
+		if summarize := agentConfig.SummarizeToolResults; summarize != nil {
+			summaryModel := models[0]
+			if summarize.Model != "" {
+				summaryModels, err := getModelsForAgent(ctx, cfg, &latest.AgentConfig{Name: agentConfig.Name, Model: summarize.Model}, autoModel, runConfig)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get tool result summarization model: %w", err)
+				}
+				summaryModel = summaryModels[0]
+			}
+			opts = append(opts, agent.WithToolResultSummarization(summaryModel, cmp.Or(summarize.Threshold, defaultSummaryThreshold)))
+		}
+
+		if compaction := agentConfig.Compaction; compaction != nil {
+			var compactionModel provider.Provider
+			if compaction.Model != "" {
+				compactionModels, err := getModelsForAgent(ctx, cfg, &latest.AgentConfig{Name: agentConfig.Name, Model: compaction.Model}, autoModel, runConfig)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get compaction model: %w", err)
+				}
+				compactionModel = compactionModels[0]
+			}
+			opts = append(opts, agent.WithCompaction(compactionModel, compaction.Threshold))
+		}
+
+		if reports := agentConfig.Confidence; reports != nil {
+			var escalationModel provider.Provider
+			if reports.EscalateTo != "" {
+				escalationModels, err := getModelsForAgent(ctx, cfg, &latest.AgentConfig{Name: agentConfig.Name, Model: reports.EscalateTo}, autoModel, runConfig)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get confidence escalation model: %w", err)
+				}
+				escalationModel = escalationModels[0]
+			}
+			opts = append(opts, agent.WithConfidenceReports(reports.VerifyLow, escalationModel))
+		}
+
+		if retry := agentConfig.Retry; retry != nil {
+			policy := agent.RetryPolicy{
+				MaxRetries:   retry.MaxRetries,
+				InitialDelay: time.Duration(retry.InitialDelay) * time.Second,
+				MaxDelay:     time.Duration(retry.MaxDelay) * time.Second,
+			}
+			if retry.Fallback != "" {
+				fallbackModels, err := getModelsForAgent(ctx, cfg, &latest.AgentConfig{Name: agentConfig.Name, Model: retry.Fallback}, autoModel, runConfig)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get retry fallback model: %w", err)
+				}
+				policy.Fallback = fallbackModels[0]
+			}
+			opts = append(opts, agent.WithRetryPolicy(policy))
+		}
+
+		if len(agentConfig.Guardrails) > 0 {
+			agentGuardrails, err := getGuardrailsForAgent(ctx, cfg, &agentConfig, models[0], autoModel, runConfig)
+			if err != nil {
+				return nil, fmt.Errorf("agent '%s': %w", agentConfig.Name, err)
+			}
+			opts = append(opts, agent.WithGuardrails(agentGuardrails...))
+		}
+
 		agentTools, warnings := getToolsForAgent(ctx, &agentConfig, parentDir, runConfig, loadOpts.toolsetRegistry)
 		if len(warnings) > 0 {
 			opts = append(opts, agent.WithLoadTimeWarnings(warnings))
@@ -194,6 +289,8 @@ func LoadWithConfig(ctx context.Context, agentSource config.Source, runConfig *c
 		}
 	}
 
+	loadOpts.onPhase("create agents")
+
 	// Create permissions checker from config
 	permChecker := permissions.NewChecker(cfg.Permissions)
 
@@ -210,13 +307,79 @@ func LoadWithConfig(ctx context.Context, agentSource config.Source, runConfig *c
 			team.WithAgents(agents...),
 			team.WithRAGManagers(ragManagers),
 			team.WithPermissions(permChecker),
+			team.WithProviderLimits(providerLimits(cfg.Providers)),
+			team.WithProviderRateLimits(providerRateLimits(cfg.Providers)),
 		),
 		Models:             cfg.Models,
 		Providers:          cfg.Providers,
 		AgentDefaultModels: agentDefaultModels,
+		ConfigHash:         configHash,
 	}, nil
 }
 
+// ReloadToolSets reads the configuration of a running team again and gives
+// its agents their toolsets, as added or changed since the team was loaded.
+// The previous toolsets are stopped. Agents added to the configuration need a
+// restart, and the RAG tools are the ones of the running team: its RAG
+// sources aren't indexed again.
+func ReloadToolSets(ctx context.Context, agentSource config.Source, runConfig *config.RuntimeConfig, t *team.Team, opts ...Opt) error {
+	var loadOpts loadOptions
+	loadOpts.toolsetRegistry = NewDefaultToolsetRegistry()
+
+	for _, o := range opts {
+		if err := o(&loadOpts); err != nil {
+			return err
+		}
+	}
+
+	cfg, err := config.Load(ctx, agentSource)
+	if err != nil {
+		return err
+	}
+
+	parentDir := cmp.Or(agentSource.ParentDir(), runConfig.WorkingDir)
+	for _, agentConfig := range cfg.Agents {
+		a, err := t.Agent(agentConfig.Name)
+		if err != nil {
+			slog.Warn("Not reloading the toolsets of a new agent, cagent must be restarted", "agent", agentConfig.Name)
+			continue
+		}
+
+		agentTools, warnings := getToolsForAgent(ctx, &agentConfig, parentDir, runConfig, loadOpts.toolsetRegistry)
+		agentTools = append(agentTools, createRAGToolsForAgent(&agentConfig, t.RAGManagers())...)
+
+		if err := a.ReplaceToolSets(ctx, agentTools...); err != nil {
+			slog.Warn("Failed to stop the previous toolsets", "agent", a.Name(), "error", err)
+		}
+		agent.WithLoadTimeWarnings(warnings)(a)
+	}
+
+	return nil
+}
+
+// providerLimits returns the providers whose concurrent requests are limited,
+// and their limit.
+func providerLimits(providers map[string]latest.ProviderConfig) map[string]int {
+	limits := map[string]int{}
+	for name, provider := range providers {
+		if provider.MaxConcurrentRequests > 0 {
+			limits[name] = provider.MaxConcurrentRequests
+		}
+	}
+	return limits
+}
+
+func providerRateLimits(providers map[string]latest.ProviderConfig) map[string]team.RateLimit {
+	limits := map[string]team.RateLimit{}
+	for name, provider := range providers {
+		limit := team.RateLimit{RequestsPerMinute: provider.RequestsPerMinute, TokensPerMinute: provider.TokensPerMinute}
+		if limit != (team.RateLimit{}) {
+			limits[name] = limit
+		}
+	}
+	return limits
+}
+
 func getModelsForAgent(ctx context.Context, cfg *latest.Config, a *latest.AgentConfig, autoModelFn func() latest.ModelConfig, runConfig *config.RuntimeConfig) ([]provider.Provider, error) {
 	var models []provider.Provider
 
@@ -230,6 +393,10 @@ func getModelsForAgent(ctx context.Context, cfg *latest.Config, a *latest.AgentC
 			}
 		}
 
+		if err := runConfig.ModelAllowlist.CheckModel(&modelCfg, cfg.Models); err != nil {
+			return nil, fmt.Errorf("model '%s' of agent '%s' is not allowed: %w", name, a.Name, err)
+		}
+
 		opts := []options.Opt{
 			options.WithGateway(runConfig.ModelsGateway),
 			options.WithStructuredOutput(a.StructuredOutput),
@@ -255,8 +422,9 @@ func getModelsForAgent(ctx context.Context, cfg *latest.Config, a *latest.AgentC
 			opts = append(opts, options.WithMaxTokens(*maxTokens))
 		}
 
-		// Pass the full models map for routing rules to resolve model references
-		model, err := provider.NewWithModels(ctx,
+		// Pass the full models map for routing rules to resolve model references.
+		// The client is created on first use, to keep startup fast.
+		model, err := provider.NewLazy(
 			&modelCfg,
 			cfg.Models,
 			runConfig.EnvProvider(),
@@ -282,18 +450,34 @@ func getToolsForAgent(ctx context.Context, a *latest.AgentConfig, parentDir stri
 
 	for i := range a.Toolsets {
 		toolset := a.Toolsets[i]
+		if toolset.Type == "memory" && toolset.Scope == "agent" {
+			// The memories of an agent are kept apart from those of the others
+			toolset.Scope = "agent:" + a.Name
+		}
 
-		tool, err := registry.CreateTool(ctx, toolset, parentDir, runConfig)
-		if err != nil {
-			// Collect error but continue loading other toolsets
-			slog.Warn("Toolset configuration failed; skipping", "type", toolset.Type, "ref", toolset.Ref, "command", toolset.Command, "error", err)
-			warnings = append(warnings, fmt.Sprintf("toolset %s failed: %v", toolset.Type, err))
-			continue
+		var tool tools.ToolSet
+		if isSlowToCreate(toolset) {
+			tool = newLazyToolSet(func(ctx context.Context) (tools.ToolSet, error) {
+				return registry.CreateTool(ctx, toolset, parentDir, runConfig)
+			})
+		} else {
+			var err error
+			tool, err = registry.CreateTool(ctx, toolset, parentDir, runConfig)
+			if err != nil {
+				// Collect error but continue loading other toolsets
+				slog.Warn("Toolset configuration failed; skipping", "type", toolset.Type, "ref", toolset.Ref, "command", toolset.Command, "error", err)
+				warnings = append(warnings, fmt.Sprintf("toolset %s failed: %v", toolset.Type, err))
+				continue
+			}
 		}
 
-		wrapped := WithToolsFilter(tool, toolset.Tools...)
+		wrapped := WithToolsFilter(tool, toolset.Tools.Include...)
+		wrapped = WithToolsExcludeFilter(wrapped, toolset.Tools.Exclude...)
+		wrapped = WithToolLimits(wrapped, toolset.Limits)
+		wrapped = WithToolAliases(wrapped, toolset.Tools.Aliases)
 		wrapped = WithInstructions(wrapped, toolset.Instruction)
 		wrapped = WithToon(wrapped, toolset.Toon)
+		wrapped = WithUntrusted(wrapped, toolset.IsUntrusted())
 
 		// Handle deferred tools
 		if !toolset.Defer.IsEmpty() {
@@ -320,6 +504,9 @@ func getToolsForAgent(ctx context.Context, a *latest.AgentConfig, parentDir stri
 	if len(a.Handoffs) > 0 {
 		toolSets = append(toolSets, builtin.NewHandoffTool())
 	}
+	if a.SummarizeToolResults != nil {
+		toolSets = append(toolSets, builtin.NewReadMoreTool())
+	}
 
 	// Wrap all tools in a single Code Mode toolset.
 	// This allows the agent to call multiple tools in a single response.
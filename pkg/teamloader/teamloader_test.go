@@ -12,6 +12,7 @@ import (
 
 	"github.com/docker/cagent/pkg/config"
 	"github.com/docker/cagent/pkg/config/latest"
+	"github.com/docker/cagent/pkg/untrusted"
 )
 
 // skipExamples contains example files that require cloud-specific configurations
@@ -194,7 +195,25 @@ func TestToolsetInstructions(t *testing.T) {
 	toolsets := agent.ToolSets()
 	require.Len(t, toolsets, 1)
 
+	// fetch outputs are untrusted by default, which appends the untrusted content guidance.
 	instructions := toolsets[0].Instructions()
-	expected := "Dummy fetch tool instruction"
+	expected := "Dummy fetch tool instruction\n\n" + untrusted.Instructions
 	require.Equal(t, expected, instructions)
 }
+
+func TestModelAllowlist(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "dummy")
+
+	agentSource, err := config.Resolve("testdata/basic.yaml")
+	require.NoError(t, err)
+
+	runConfig := &config.RuntimeConfig{Config: config.Config{
+		ModelAllowlist: &config.ModelAllowlist{Providers: []string{"anthropic"}},
+	}}
+	_, err = Load(t.Context(), agentSource, runConfig)
+	require.ErrorContains(t, err, "model 'openai/gpt-4o' of agent 'root' is not allowed: provider 'openai' is not allowed")
+
+	runConfig.ModelAllowlist = &config.ModelAllowlist{Models: []string{"openai/gpt-4*"}}
+	_, err = Load(t.Context(), agentSource, runConfig)
+	require.NoError(t, err)
+}
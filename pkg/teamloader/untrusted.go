@@ -0,0 +1,72 @@
+package teamloader
+
+import (
+	"context"
+
+	"github.com/docker/cagent/pkg/tools"
+	"github.com/docker/cagent/pkg/untrusted"
+)
+
+// WithUntrusted wraps a toolset whose outputs come from untrusted sources.
+// Tool results are neutralized, wrapped in provenance-tagged blocks and
+// flagged as untrusted so that the runtime can apply stricter approval rules.
+func WithUntrusted(inner tools.ToolSet, enabled bool) tools.ToolSet {
+	if !enabled {
+		return inner
+	}
+
+	return &untrustedTools{
+		ToolSet: inner,
+	}
+}
+
+type untrustedTools struct {
+	tools.ToolSet
+}
+
+// Unwrap returns the untrusted toolset.
+func (u *untrustedTools) Unwrap() tools.ToolSet {
+	return u.ToolSet
+}
+
+func (u *untrustedTools) Instructions() string {
+	instructions := u.ToolSet.Instructions()
+	if instructions == "" {
+		return untrusted.Instructions
+	}
+	return instructions + "\n\n" + untrusted.Instructions
+}
+
+func (u *untrustedTools) Tools(ctx context.Context) ([]tools.Tool, error) {
+	allTools, err := u.ToolSet.Tools(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, tool := range allTools {
+		handler := tool.Handler
+		if handler == nil {
+			continue
+		}
+
+		name := tool.Name
+		tool.Handler = func(ctx context.Context, toolCall tools.ToolCall) (*tools.ToolCallResult, error) {
+			res, err := handler(ctx, toolCall)
+			if err != nil || res == nil {
+				return res, err
+			}
+
+			// The content is never trusted to tell whether it was already
+			// wrapped: it could forge an envelope. Only the flag set below
+			// by a nested untrusted toolset is.
+			if !res.Untrusted {
+				res.Output = untrusted.Wrap(name, res.Output)
+			}
+			res.Untrusted = true
+			return res, nil
+		}
+		allTools[i] = tool
+	}
+
+	return allTools, nil
+}
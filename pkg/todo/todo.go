@@ -0,0 +1,93 @@
+// Package todo defines the todo items of an agent and the results of their
+// checks. They are kept apart from the todo tools so that sessions can store
+// them without depending on the builtin tools.
+package todo
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	CheckPassed  = "passed"
+	CheckFailed  = "failed"
+	CheckSkipped = "skipped"
+)
+
+type Todo struct {
+	ID           string        `json:"id" jsonschema:"ID of the todo item"`
+	Description  string        `json:"description" jsonschema:"Description of the todo item"`
+	Status       string        `json:"status" jsonschema:"New status (pending, in-progress,completed)"`
+	DoneWhen     []DoneCheck   `json:"done_when,omitempty" jsonschema:"Checks that must pass for the todo to be completed"`
+	Verification *Verification `json:"verification,omitempty" jsonschema:"Results of the checks, the last time the todo was completed"`
+}
+
+// DoneCheck is a machine-checkable criterion of a todo. Exactly one of its
+// fields is set. Tests passing is checked with the command running them.
+type DoneCheck struct {
+	Command    string `json:"command,omitempty" jsonschema:"Shell command that must exit with status 0, e.g. go test ./..."`
+	FileExists string `json:"file_exists,omitempty" jsonschema:"Path of a file that must exist, relative to the working directory"`
+}
+
+func (c DoneCheck) String() string {
+	if c.Command != "" {
+		return fmt.Sprintf("`%s` exits with 0", c.Command)
+	}
+	return fmt.Sprintf("%s exists", c.FileExists)
+}
+
+// Validate checks that exactly one of the fields of the check is set.
+func (c DoneCheck) Validate() error {
+	if (c.Command == "") == (c.FileExists == "") {
+		return errors.New("each done_when check must set exactly one of command or file_exists")
+	}
+	return nil
+}
+
+// CheckResult is the result of a check of a todo.
+type CheckResult struct {
+	Check  string `json:"check"`
+	Status string `json:"status"`
+	// Output is what the command printed, or why the check failed or was skipped
+	Output string `json:"output,omitempty"`
+}
+
+// Verification holds the results of the checks of a todo.
+type Verification struct {
+	Results   []CheckResult `json:"results"`
+	CheckedAt time.Time     `json:"checked_at"`
+}
+
+// Failed reports whether one of the checks failed.
+func (v *Verification) Failed() bool {
+	return v.Count(CheckFailed) > 0
+}
+
+// Passed reports whether all the checks passed.
+func (v *Verification) Passed() bool {
+	return v.Count(CheckPassed) == len(v.Results)
+}
+
+// Count returns the number of checks with the given status.
+func (v *Verification) Count(status string) int {
+	n := 0
+	for _, r := range v.Results {
+		if r.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// Summary describes the verification in a few words, e.g. "2/3 checks passed".
+func (v *Verification) Summary() string {
+	switch {
+	case v.Failed():
+		return fmt.Sprintf("%d/%d checks failed", v.Count(CheckFailed), len(v.Results))
+	case v.Passed():
+		return fmt.Sprintf("%d/%d checks passed", len(v.Results), len(v.Results))
+	default:
+		return fmt.Sprintf("%d/%d checks not run", v.Count(CheckSkipped), len(v.Results))
+	}
+}
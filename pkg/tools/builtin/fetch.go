@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	"net/url"
 	"strings"
@@ -29,8 +30,14 @@ type FetchTool struct {
 
 var _ tools.ToolSet = (*FetchTool)(nil)
 
+// defaultFetchMaxSize is the number of bytes of a response read by default.
+const defaultFetchMaxSize = 1 << 20
+
 type fetchHandler struct {
 	timeout time.Duration
+	// maxSize is the number of bytes of a response read, the rest is
+	// dropped
+	maxSize int64
 }
 
 type FetchToolArgs struct {
@@ -68,8 +75,12 @@ func (h *fetchHandler) CallTool(ctx context.Context, params FetchToolArgs) (*too
 		if result.Error != "" {
 			return tools.ResultError(fmt.Sprintf("Error fetching %s: %s", result.URL, result.Error)), nil
 		}
-		return tools.ResultSuccess(fmt.Sprintf("Successfully fetched %s (Status: %d, Length: %d bytes):\n\n%s",
-			result.URL, result.StatusCode, result.ContentLength, result.Body)), nil
+		output := fmt.Sprintf("Successfully fetched %s (Status: %d, Length: %d bytes):\n\n%s",
+			result.URL, result.StatusCode, result.ContentLength, result.Body)
+		if result.Truncated {
+			output += fmt.Sprintf("\n\n[Content truncated: the page is larger than %d bytes]", h.maxSize)
+		}
+		return tools.ResultSuccess(output), nil
 	}
 
 	// Multiple URLs - return structured results
@@ -87,7 +98,9 @@ type FetchResult struct {
 	Status        string `json:"status"`
 	ContentType   string `json:"contentType,omitempty"`
 	ContentLength int    `json:"contentLength"`
+	Title         string `json:"title,omitempty"`
 	Body          string `json:"body,omitempty"`
+	Truncated     bool   `json:"truncated,omitempty"`
 	Error         string `json:"error,omitempty"`
 }
 
@@ -157,33 +170,40 @@ func (h *fetchHandler) fetchURL(ctx context.Context, client *http.Client, urlStr
 	result.Status = resp.Status
 	result.ContentType = resp.Header.Get("Content-Type")
 
-	// Read response body
-	maxSize := int64(1 << 20) // 1MB
-	body, err := io.ReadAll(io.LimitReader(resp.Body, maxSize))
+	// Read one more byte than the limit to tell if the body is truncated
+	body, err := io.ReadAll(io.LimitReader(resp.Body, h.maxSize+1))
 	if err != nil {
 		result.Error = fmt.Sprintf("failed to read response body: %v", err)
 		return result
 	}
+	if int64(len(body)) > h.maxSize {
+		body = body[:h.maxSize]
+		result.Truncated = true
+	}
 
 	contentType := resp.Header.Get("Content-Type")
-
-	switch format {
-	case "markdown":
-		if strings.Contains(contentType, "text/html") {
-			result.Body = htmlToMarkdown(string(body))
-		} else {
-			result.Body = string(body)
-		}
-	case "html":
-		result.Body = string(body)
-	case "text":
-		if strings.Contains(contentType, "text/html") {
-			result.Body = htmlToText(string(body))
-		} else {
-			result.Body = string(body)
-		}
+	if contentType == "" {
+		contentType = http.DetectContentType(body)
+	}
+	if !isTextContent(contentType) {
+		result.Error = fmt.Sprintf("unsupported content type %s: only text content can be fetched", contentType)
+		return result
+	}
+	isHTML := strings.Contains(contentType, "text/html") || strings.Contains(contentType, "application/xhtml")
+
+	// The markdown and text of HTML pages are the main content of the page,
+	// without the navigation, scripts and the like
+	switch {
+	case format == "markdown" && isHTML:
+		title, content := extractReadable(string(body))
+		result.Title = title
+		result.Body = htmlToMarkdown(content)
+	case format == "text" && isHTML:
+		title, content := extractReadable(string(body))
+		result.Title = title
+		result.Body = htmlToText(content)
 	default:
-		result.Body = string(body)
+		result.Body = strings.ToValidUTF8(string(body), "")
 	}
 
 	result.ContentLength = len(result.Body)
@@ -249,6 +269,27 @@ func (h *fetchHandler) checkRobotsAllowed(ctx context.Context, client *http.Clie
 	return robots.TestAgent(targetURL.Path, userAgent)
 }
 
+// isTextContent returns whether a content type is text a model can read.
+func isTextContent(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(contentType))
+	}
+
+	switch {
+	case strings.HasPrefix(mediaType, "text/"):
+		return true
+	case strings.HasSuffix(mediaType, "+json"), strings.HasSuffix(mediaType, "+xml"):
+		return true
+	}
+	switch mediaType {
+	case "application/json", "application/xml", "application/javascript", "application/x-javascript",
+		"application/ecmascript", "application/x-yaml", "application/yaml", "application/toml", "application/x-sh":
+		return true
+	}
+	return false
+}
+
 func htmlToMarkdown(html string) string {
 	markdown, err := htmltomarkdown.ConvertString(html)
 	if err != nil {
@@ -265,6 +306,7 @@ func NewFetchTool(options ...FetchToolOption) *FetchTool {
 	tool := &FetchTool{
 		handler: &fetchHandler{
 			timeout: 30 * time.Second,
+			maxSize: defaultFetchMaxSize,
 		},
 	}
 
@@ -283,6 +325,13 @@ func WithTimeout(timeout time.Duration) FetchToolOption {
 	}
 }
 
+// WithMaxSize sets the number of bytes of a response read, 1MB by default.
+func WithMaxSize(maxSize int64) FetchToolOption {
+	return func(t *FetchTool) {
+		t.handler.maxSize = maxSize
+	}
+}
+
 func (t *FetchTool) Instructions() string {
 	return `## "fetch" tool instructions
 
@@ -293,6 +342,8 @@ FEATURES
 - Support for multiple URLs in a single call
 - Returns response body and metadata (status code, content type, length)
 - Specify the output format (text, markdown, html)
+- The text and markdown of HTML pages are their main content, without navigation, scripts and the like
+- Only text content can be fetched (HTML, JSON, XML, plain text...), large pages are truncated
 - Respects robots.txt restrictions
 
 USAGE TIPS
@@ -316,3 +316,65 @@ func TestFetchTool_ParametersAreObjects(t *testing.T) {
 		assert.Equal(t, "object", m["type"])
 	}
 }
+
+func TestFetch_Readable(t *testing.T) {
+	url := runHTTPServer(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, `<html><head><title>The  Article</title><script>track()</script></head>
+<body>
+<header><a href="/">Home</a></header>
+<nav><a href="/blog">Blog</a></nav>
+<article><header><h1>Readable pages</h1></header><p>The content of the article.</p><aside>Related posts</aside></article>
+<footer>Copyright</footer>
+</body></html>`)
+	})
+
+	tool := NewFetchTool()
+
+	result, err := tool.handler.CallTool(t.Context(), FetchToolArgs{
+		URLs:   []string{url, url},
+		Format: "markdown",
+	})
+	require.NoError(t, err)
+
+	var results []FetchResult
+	require.NoError(t, json.Unmarshal([]byte(result.Output), &results))
+	require.Len(t, results, 2)
+	assert.Equal(t, "The Article", results[0].Title)
+	assert.Equal(t, "# Readable pages\n\nThe content of the article.", results[0].Body)
+}
+
+func TestFetch_UnsupportedContentType(t *testing.T) {
+	url := runHTTPServer(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write([]byte{0x89, 'P', 'N', 'G'})
+	})
+
+	tool := NewFetchTool()
+
+	result, err := tool.handler.CallTool(t.Context(), FetchToolArgs{
+		URLs:   []string{url},
+		Format: "text",
+	})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Output, "unsupported content type image/png")
+}
+
+func TestFetch_MaxSize(t *testing.T) {
+	url := runHTTPServer(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"items":[1,2,3,4,5,6,7,8,9]}`)
+	})
+
+	tool := NewFetchTool(WithMaxSize(10))
+
+	result, err := tool.handler.CallTool(t.Context(), FetchToolArgs{
+		URLs:   []string{url},
+		Format: "text",
+	})
+	require.NoError(t, err)
+	assert.Contains(t, result.Output, "Length: 10 bytes")
+	assert.Contains(t, result.Output, `{"items":[`)
+	assert.Contains(t, result.Output, "[Content truncated: the page is larger than 10 bytes]")
+}
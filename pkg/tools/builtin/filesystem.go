@@ -3,6 +3,7 @@ package builtin
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/fs"
 	"log/slog"
@@ -20,6 +21,8 @@ const (
 	ToolNameReadFile           = "read_file"
 	ToolNameReadMultipleFiles  = "read_multiple_files"
 	ToolNameEditFile           = "edit_file"
+	ToolNameEditFiles          = "edit_files"
+	ToolNameApplyPatch         = "apply_patch"
 	ToolNameWriteFile          = "write_file"
 	ToolNameDirectoryTree      = "directory_tree"
 	ToolNameListDirectory      = "list_directory"
@@ -160,6 +163,28 @@ type EditFileArgs struct {
 	Edits []Edit `json:"edits" jsonschema:"Array of edit operations"`
 }
 
+type EditFilesArgs struct {
+	Files []EditFileArgs `json:"files" jsonschema:"The files to edit, with their edit operations"`
+}
+
+type ApplyPatchArgs struct {
+	Patch string `json:"patch" jsonschema:"The unified diff to apply, changing, creating (from /dev/null) or deleting (to /dev/null) one or more files"`
+}
+
+// PatchMeta is the summary of the changes of apply_patch or edit_files.
+type PatchMeta struct {
+	Files []PatchedFile `json:"files"`
+}
+
+type PatchedFile struct {
+	Path      string `json:"path"`
+	Operation string `json:"operation"`
+	// Hunks is the number of hunks of a patch, or of edits, applied
+	Hunks   int `json:"hunks"`
+	Added   int `json:"added"`
+	Removed int `json:"removed"`
+}
+
 func (t *FilesystemTool) Tools(context.Context) ([]tools.Tool, error) {
 	return []tools.Tool{
 		{
@@ -208,6 +233,28 @@ func (t *FilesystemTool) Tools(context.Context) ([]tools.Tool, error) {
 				Title: "Edit",
 			},
 		},
+		{
+			Name:         ToolNameEditFiles,
+			Category:     "filesystem",
+			Description:  "Make exact text replacements in several files at once, all or nothing: if an edit fails, no file is changed. Prefer it to several edit_file calls for changes spanning files, like renames and refactors.",
+			Parameters:   tools.MustSchemaFor[EditFilesArgs](),
+			OutputSchema: tools.MustSchemaFor[string](),
+			Handler:      tools.NewHandler(t.handleEditFiles),
+			Annotations: tools.ToolAnnotations{
+				Title: "Edit Files",
+			},
+		},
+		{
+			Name:         ToolNameApplyPatch,
+			Category:     "filesystem",
+			Description:  "Apply a unified diff (as written by diff -u or git diff) changing, creating or deleting one or more files, all or nothing: if a hunk doesn't apply, no file is changed. Hunks are located by their lines, the line numbers of their headers are only hints.",
+			Parameters:   tools.MustSchemaFor[ApplyPatchArgs](),
+			OutputSchema: tools.MustSchemaFor[string](),
+			Handler:      tools.NewHandler(t.handleApplyPatch),
+			Annotations: tools.ToolAnnotations{
+				Title: "Apply Patch",
+			},
+		},
 		{
 			Name:         ToolNameListDirectory,
 			Category:     "filesystem",
@@ -404,16 +451,9 @@ func (t *FilesystemTool) handleEditFile(ctx context.Context, args EditFileArgs)
 		return tools.ResultError(fmt.Sprintf("Error reading file: %s", err)), nil
 	}
 
-	originalContent := string(content)
-	modifiedContent := originalContent
-
-	var changes []string
-	for i, edit := range args.Edits {
-		if !strings.Contains(modifiedContent, edit.OldText) {
-			return tools.ResultError(fmt.Sprintf("Edit %d failed: old text not found", i+1)), nil
-		}
-		modifiedContent = strings.Replace(modifiedContent, edit.OldText, edit.NewText, 1)
-		changes = append(changes, fmt.Sprintf("Edit %d: Replaced %d characters", i+1, len(edit.OldText)))
+	modifiedContent, changes, err := applyEdits(string(content), args.Edits)
+	if err != nil {
+		return tools.ResultError(err.Error()), nil
 	}
 
 	if err := os.WriteFile(resolvedPath, []byte(modifiedContent), 0o644); err != nil {
@@ -431,6 +471,173 @@ func (t *FilesystemTool) handleEditFile(ctx context.Context, args EditFileArgs)
 	return tools.ResultSuccess(fmt.Sprintf("File edited successfully. Changes:\n%s", strings.Join(changes, "\n"))), nil
 }
 
+// applyEdits replaces the old text of each edit with its new text, in order.
+func applyEdits(content string, edits []Edit) (string, []string, error) {
+	var changes []string
+	for i, edit := range edits {
+		if !strings.Contains(content, edit.OldText) {
+			return "", nil, fmt.Errorf("edit %d failed: old text not found", i+1)
+		}
+		content = strings.Replace(content, edit.OldText, edit.NewText, 1)
+		changes = append(changes, fmt.Sprintf("Edit %d: Replaced %d characters", i+1, len(edit.OldText)))
+	}
+	return content, changes, nil
+}
+
+func (t *FilesystemTool) handleEditFiles(ctx context.Context, args EditFilesArgs) (*tools.ToolCallResult, error) {
+	if len(args.Files) == 0 {
+		return tools.ResultError("No file to edit"), nil
+	}
+
+	// The edits of a file listed twice apply to the edited content
+	contents := map[string]string{}
+	var changes []fileChange
+	var meta PatchMeta
+	for _, file := range args.Files {
+		resolvedPath := t.resolvePath(file.Path)
+		content, edited := contents[resolvedPath]
+		if !edited {
+			data, err := os.ReadFile(resolvedPath)
+			if err != nil {
+				return tools.ResultError(fmt.Sprintf("Error reading file %s: %s. No file was changed.", file.Path, err)), nil
+			}
+			content = string(data)
+		}
+
+		modifiedContent, _, err := applyEdits(content, file.Edits)
+		if err != nil {
+			return tools.ResultError(fmt.Sprintf("%s: %s. No file was changed.", file.Path, err)), nil
+		}
+		contents[resolvedPath] = modifiedContent
+		changes = append(changes, fileChange{path: resolvedPath, content: modifiedContent})
+
+		summary := PatchedFile{Path: file.Path, Operation: PatchModify, Hunks: len(file.Edits)}
+		for _, edit := range file.Edits {
+			summary.Removed += strings.Count(edit.OldText, "\n") + 1
+			summary.Added += strings.Count(edit.NewText, "\n") + 1
+		}
+		meta.Files = append(meta.Files, summary)
+	}
+
+	return t.commit(ctx, changes, meta)
+}
+
+func (t *FilesystemTool) handleApplyPatch(ctx context.Context, args ApplyPatchArgs) (*tools.ToolCallResult, error) {
+	patches, err := parsePatch(args.Patch)
+	if err != nil {
+		return tools.ResultError(fmt.Sprintf("Invalid patch: %s", err)), nil
+	}
+
+	// The files are patched in memory first, so that nothing is written if a
+	// hunk doesn't apply. A file patched twice is patched from its new content.
+	contents := map[string]*string{}
+	read := func(path string) (*string, error) {
+		if content, ok := contents[path]; ok {
+			return content, nil
+		}
+		data, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		content := string(data)
+		return &content, nil
+	}
+
+	var changes []fileChange
+	var meta PatchMeta
+	for _, p := range patches {
+		op := p.operation()
+		fail := func(format string, a ...any) (*tools.ToolCallResult, error) {
+			return tools.ResultError(fmt.Sprintf("%s: %s. No file was changed.", p.path(), fmt.Sprintf(format, a...))), nil
+		}
+
+		var content string
+		if op != PatchCreate {
+			oldPath := t.resolvePath(p.oldPath)
+			current, err := read(oldPath)
+			if err != nil {
+				return fail("reading the file: %s", err)
+			}
+			if current == nil {
+				return fail("the file doesn't exist")
+			}
+			content = *current
+		}
+		if op == PatchRename {
+			target, err := read(t.resolvePath(p.newPath))
+			if err != nil {
+				return fail("reading the file: %s", err)
+			}
+			if target != nil {
+				return fail("the file already exists, it can't be renamed over")
+			}
+		} else if op == PatchCreate {
+			current, err := read(t.resolvePath(p.newPath))
+			if err != nil {
+				return fail("reading the file: %s", err)
+			}
+			if current != nil {
+				return fail("the file already exists, patch it instead of creating it")
+			}
+		}
+
+		patched, added, removed, err := p.apply(content)
+		if err != nil {
+			return fail("%s", err)
+		}
+		meta.Files = append(meta.Files, PatchedFile{Path: p.path(), Operation: op, Hunks: len(p.hunks), Added: added, Removed: removed})
+
+		if op == PatchDelete || op == PatchRename {
+			oldPath := t.resolvePath(p.oldPath)
+			contents[oldPath] = nil
+			changes = append(changes, fileChange{path: oldPath, remove: true})
+		}
+		if op != PatchDelete {
+			newPath := t.resolvePath(p.newPath)
+			contents[newPath] = &patched
+			changes = append(changes, fileChange{path: newPath, content: patched})
+		}
+	}
+
+	return t.commit(ctx, changes, meta)
+}
+
+// commit writes the changes of edit_files or apply_patch, all or nothing,
+// runs the post-edit commands of the files written and returns the summary.
+func (t *FilesystemTool) commit(ctx context.Context, changes []fileChange, meta PatchMeta) (*tools.ToolCallResult, error) {
+	if err := commitChanges(changes); err != nil {
+		return tools.ResultError(fmt.Sprintf("Error writing files: %s", err)), nil
+	}
+
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "%d files changed:", len(meta.Files))
+	for _, f := range meta.Files {
+		fmt.Fprintf(&summary, "\n- %s %s: %d hunks, +%d -%d", f.Operation, f.Path, f.Hunks, f.Added, f.Removed)
+	}
+
+	var postEditErrs []string
+	for _, change := range changes {
+		if change.remove {
+			continue
+		}
+		if err := t.executePostEditCommands(ctx, change.path); err != nil {
+			postEditErrs = append(postEditErrs, err.Error())
+		}
+	}
+	if len(postEditErrs) > 0 {
+		return &tools.ToolCallResult{
+			Output:  fmt.Sprintf("%s\n\nFiles changed successfully but post-edit commands failed:\n%s", summary.String(), strings.Join(postEditErrs, "\n")),
+			IsError: true,
+			Meta:    meta,
+		}, nil
+	}
+
+	return &tools.ToolCallResult{Output: summary.String(), Meta: meta}, nil
+}
+
 func (t *FilesystemTool) handleListDirectory(_ context.Context, args ListDirectoryArgs) (*tools.ToolCallResult, error) {
 	resolvedPath := t.resolvePath(args.Path)
 
@@ -601,3 +601,118 @@ func TestFilesystemTool_EmptyWorkingDir(t *testing.T) {
 	resolvedPath = tool.resolvePath("/etc/hosts")
 	assert.Equal(t, "/etc/hosts", resolvedPath)
 }
+
+func TestFilesystemTool_EditFiles(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	tool := NewFilesystemTool(tmpDir)
+
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "a.go"), []byte("func Old() {}\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "b.go"), []byte("Old()\nOld()\n"), 0o644))
+
+	result, err := tool.handleEditFiles(t.Context(), EditFilesArgs{Files: []EditFileArgs{
+		{Path: "a.go", Edits: []Edit{{OldText: "func Old()", NewText: "func New()"}}},
+		{Path: "b.go", Edits: []Edit{{OldText: "Old()", NewText: "New()"}, {OldText: "Old()", NewText: "New()"}}},
+	}})
+	require.NoError(t, err)
+	assert.False(t, result.IsError, result.Output)
+	assert.Equal(t, "2 files changed:\n- modify a.go: 1 hunks, +1 -1\n- modify b.go: 2 hunks, +2 -2", result.Output)
+	assert.Len(t, result.Meta.(PatchMeta).Files, 2)
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "b.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "New()\nNew()\n", string(content))
+
+	// All or nothing
+	result, err = tool.handleEditFiles(t.Context(), EditFilesArgs{Files: []EditFileArgs{
+		{Path: "a.go", Edits: []Edit{{OldText: "func New()", NewText: "func Newer()"}}},
+		{Path: "b.go", Edits: []Edit{{OldText: "Missing()", NewText: "Newer()"}}},
+	}})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Output, "b.go: edit 1 failed: old text not found. No file was changed.")
+
+	content, err = os.ReadFile(filepath.Join(tmpDir, "a.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "func New() {}\n", string(content))
+}
+
+func TestFilesystemTool_ApplyPatch(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	tool := NewFilesystemTool(tmpDir)
+
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte("package main\n\nfunc main() {\n\tprintln(\"hello\")\n}\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "old.txt"), []byte("bye\n"), 0o644))
+
+	result, err := tool.handleApplyPatch(t.Context(), ApplyPatchArgs{Patch: `--- a/main.go
++++ b/main.go
+@@ -3,3 +3,4 @@
+ func main() {
+-	println("hello")
++	println("hello, world")
++	println("bye")
+ }
+--- /dev/null
++++ b/pkg/util.go
+@@ -0,0 +1 @@
++package pkg
+--- a/old.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-bye
+`})
+	require.NoError(t, err)
+	assert.False(t, result.IsError, result.Output)
+	assert.Equal(t, PatchMeta{Files: []PatchedFile{
+		{Path: "main.go", Operation: PatchModify, Hunks: 1, Added: 2, Removed: 1},
+		{Path: "pkg/util.go", Operation: PatchCreate, Hunks: 1, Added: 1},
+		{Path: "old.txt", Operation: PatchDelete, Hunks: 1, Removed: 1},
+	}}, result.Meta)
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "main.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "package main\n\nfunc main() {\n\tprintln(\"hello, world\")\n\tprintln(\"bye\")\n}\n", string(content))
+	content, err = os.ReadFile(filepath.Join(tmpDir, "pkg", "util.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "package pkg\n", string(content))
+	assert.NoFileExists(t, filepath.Join(tmpDir, "old.txt"))
+
+	// All or nothing: the second file doesn't apply
+	result, err = tool.handleApplyPatch(t.Context(), ApplyPatchArgs{Patch: `--- a/main.go
++++ b/main.go
+@@ -1 +1 @@
+-package main
++package app
+--- a/pkg/util.go
++++ b/pkg/util.go
+@@ -1 +1 @@
+-package other
++package util
+`})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Output, "pkg/util.go: hunk 1 doesn't apply")
+	assert.Contains(t, result.Output, "No file was changed.")
+
+	content, err = os.ReadFile(filepath.Join(tmpDir, "main.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "package main\n")
+
+	result, err = tool.handleApplyPatch(t.Context(), ApplyPatchArgs{Patch: "--- /dev/null\n+++ b/main.go\n@@ -0,0 +1 @@\n+package main\n"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Output, "the file already exists")
+
+	// A rename doesn't overwrite an existing file
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "other.go"), []byte("package other\n"), 0o644))
+	result, err = tool.handleApplyPatch(t.Context(), ApplyPatchArgs{Patch: "--- a/main.go\n+++ b/other.go\n"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Output, "other.go: the file already exists")
+
+	content, err = os.ReadFile(filepath.Join(tmpDir, "other.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "package other\n", string(content))
+	assert.FileExists(t, filepath.Join(tmpDir, "main.go"))
+}
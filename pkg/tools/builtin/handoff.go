@@ -17,6 +17,7 @@ var _ tools.ToolSet = (*HandoffTool)(nil)
 
 type HandoffArgs struct {
 	Agent string `json:"agent" jsonschema:"The name of the agent to hand off the conversation to."`
+	Notes string `json:"notes,omitempty" jsonschema:"What the next agent needs to know that the conversation doesn't say: what was done, the decisions made and what is left to do. Kept in the working memory of the team."`
 }
 
 func NewHandoffTool() *HandoffTool {
@@ -0,0 +1,82 @@
+package builtin
+
+import (
+	"context"
+	"sync"
+
+	"github.com/docker/cagent/pkg/rag"
+	"github.com/docker/cagent/pkg/tools"
+)
+
+// KnowledgeTool searches a local knowledge base, indexed beforehand with
+// "cagent kb add". The knowledge base is opened when the tool starts and
+// closed when it stops.
+type KnowledgeTool struct {
+	tools.BaseToolSet
+	open func(ctx context.Context) (*rag.Manager, error)
+
+	mu      sync.Mutex
+	manager *rag.Manager
+}
+
+var _ tools.ToolSet = (*KnowledgeTool)(nil)
+
+// NewKnowledgeTool creates a tool searching the knowledge base opened by
+// open.
+func NewKnowledgeTool(open func(ctx context.Context) (*rag.Manager, error)) *KnowledgeTool {
+	return &KnowledgeTool{open: open}
+}
+
+func (t *KnowledgeTool) Start(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.manager != nil {
+		return nil
+	}
+	manager, err := t.open(ctx)
+	if err != nil {
+		return err
+	}
+	t.manager = manager
+	return nil
+}
+
+func (t *KnowledgeTool) Stop(context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.manager == nil {
+		return nil
+	}
+	err := t.manager.Close()
+	t.manager = nil
+	return err
+}
+
+func (t *KnowledgeTool) Instructions() string {
+	ragTool := t.ragTool()
+	if ragTool == nil {
+		return ""
+	}
+	return ragTool.Instructions()
+}
+
+func (t *KnowledgeTool) Tools(ctx context.Context) ([]tools.Tool, error) {
+	ragTool := t.ragTool()
+	if ragTool == nil {
+		return nil, nil
+	}
+	return ragTool.Tools(ctx)
+}
+
+// ragTool returns the tool querying the knowledge base, nil until it's open.
+func (t *KnowledgeTool) ragTool() *RAGTool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.manager == nil {
+		return nil
+	}
+	return NewRAGTool(t.manager, t.manager.ToolName())
+}
@@ -1,10 +1,14 @@
 package builtin
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/docker/cagent/pkg/memory/database"
 	"github.com/docker/cagent/pkg/tools"
@@ -25,29 +29,66 @@ type DB interface {
 type MemoryTool struct {
 	tools.BaseToolSet
 	db DB
+	// scope is the scope of the memories the tool adds and sees, all the
+	// memories when empty
+	scope string
+	// recall is the number of relevant memories recalled at the start of
+	// each turn
+	recall int
 }
 
 // Make sure Memory Tool implements the ToolSet Interface
 var _ tools.ToolSet = (*MemoryTool)(nil)
 
-func NewMemoryTool(manager DB) *MemoryTool {
-	return &MemoryTool{
+type MemoryToolOption func(*MemoryTool)
+
+// WithMemoryScope keeps the memories of the tool apart from the others, e.g.
+// "agent:root" or "project:/src/app".
+func WithMemoryScope(scope string) MemoryToolOption {
+	return func(t *MemoryTool) {
+		t.scope = scope
+	}
+}
+
+// WithRecall recalls up to n memories relevant to the message of the user at
+// the start of each turn.
+func WithRecall(n int) MemoryToolOption {
+	return func(t *MemoryTool) {
+		t.recall = n
+	}
+}
+
+func NewMemoryTool(manager DB, opts ...MemoryToolOption) *MemoryTool {
+	t := &MemoryTool{
 		db: manager,
 	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
 }
 
 type AddMemoryArgs struct {
 	Memory string `json:"memory" jsonschema:"The memory content to store"`
 }
 
+type GetMemoriesArgs struct {
+	Query string `json:"query,omitempty" jsonschema:"Only retrieve the memories relevant to this query, most relevant first"`
+}
+
 type DeleteMemoryArgs struct {
 	ID string `json:"id" jsonschema:"The ID of the memory to delete"`
 }
 
 func (t *MemoryTool) Instructions() string {
+	recall := `Before taking any action or responding to the user use the "get_memories" tool to remember things about the user.`
+	if t.recall > 0 {
+		recall = `The memories relevant to the message of the user are given to you at the start of each turn. Use the "get_memories" tool, with a query, to look for others.`
+	}
+
 	return `## Using the memory tool
 
-Before taking any action or responding to the user use the "get_memories" tool to remember things about the user.
+` + recall + `
 Do not talk about using the tool, just use it.
 
 ## Rules
@@ -70,7 +111,8 @@ func (t *MemoryTool) Tools(context.Context) ([]tools.Tool, error) {
 		{
 			Name:         ToolNameGetMemories,
 			Category:     "memory",
-			Description:  "Retrieve all stored memories",
+			Description:  "Retrieve the stored memories, or the ones relevant to a query",
+			Parameters:   tools.MustSchemaFor[GetMemoriesArgs](),
 			OutputSchema: tools.MustSchemaFor[[]database.UserMemory](),
 			Handler:      tools.NewHandler(t.handleGetMemories),
 			Annotations: tools.ToolAnnotations{
@@ -97,6 +139,7 @@ func (t *MemoryTool) handleAddMemory(ctx context.Context, args AddMemoryArgs) (*
 		ID:        fmt.Sprintf("%d", time.Now().UnixNano()),
 		CreatedAt: time.Now().Format(time.RFC3339),
 		Memory:    args.Memory,
+		Scope:     t.scope,
 	}
 
 	if err := t.db.AddMemory(ctx, memory); err != nil {
@@ -106,11 +149,14 @@ func (t *MemoryTool) handleAddMemory(ctx context.Context, args AddMemoryArgs) (*
 	return tools.ResultSuccess(fmt.Sprintf("Memory added successfully with ID: %s", memory.ID)), nil
 }
 
-func (t *MemoryTool) handleGetMemories(ctx context.Context, _ map[string]any) (*tools.ToolCallResult, error) {
-	memories, err := t.db.GetMemories(ctx)
+func (t *MemoryTool) handleGetMemories(ctx context.Context, args GetMemoriesArgs) (*tools.ToolCallResult, error) {
+	memories, err := t.memories(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get memories: %w", err)
 	}
+	if args.Query != "" {
+		memories = relevantMemories(memories, args.Query, 0)
+	}
 
 	result, err := json.Marshal(memories)
 	if err != nil {
@@ -125,9 +171,110 @@ func (t *MemoryTool) handleDeleteMemory(ctx context.Context, args DeleteMemoryAr
 		ID: args.ID,
 	}
 
+	// A scoped tool only deletes its own memories
+	if t.scope != "" {
+		memories, err := t.memories(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete memory: %w", err)
+		}
+		if !slices.ContainsFunc(memories, func(m database.UserMemory) bool { return m.ID == args.ID }) {
+			return tools.ResultError(fmt.Sprintf("Memory with ID %s not found", args.ID)), nil
+		}
+	}
+
 	if err := t.db.DeleteMemory(ctx, memory); err != nil {
 		return nil, fmt.Errorf("failed to delete memory: %w", err)
 	}
 
 	return tools.ResultSuccess(fmt.Sprintf("Memory with ID %s deleted successfully", args.ID)), nil
 }
+
+// memories returns the memories in the scope of the tool.
+func (t *MemoryTool) memories(ctx context.Context) ([]database.UserMemory, error) {
+	memories, err := t.db.GetMemories(ctx)
+	if err != nil || t.scope == "" {
+		return memories, err
+	}
+	return slices.DeleteFunc(memories, func(m database.UserMemory) bool {
+		return m.Scope != t.scope
+	}), nil
+}
+
+// Recall returns the memories most relevant to a message, for the runtime to
+// give them to the agent at the start of a turn. It returns none unless the
+// tool was created with WithRecall.
+func (t *MemoryTool) Recall(ctx context.Context, message string) ([]database.UserMemory, error) {
+	if t.recall <= 0 || strings.TrimSpace(message) == "" {
+		return nil, nil
+	}
+
+	memories, err := t.memories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return relevantMemories(memories, message, t.recall), nil
+}
+
+// relevantMemories returns the memories sharing words with a query, the ones
+// sharing the most first, then the most recent. It returns up to limit
+// memories, all of them when limit is zero.
+func relevantMemories(memories []database.UserMemory, query string, limit int) []database.UserMemory {
+	queryTerms := terms(query)
+
+	type scored struct {
+		memory database.UserMemory
+		score  int
+	}
+	var relevant []scored
+	for _, memory := range memories {
+		score := 0
+		for term := range terms(memory.Memory) {
+			if queryTerms[term] {
+				score++
+			}
+		}
+		if score > 0 {
+			relevant = append(relevant, scored{memory: memory, score: score})
+		}
+	}
+
+	slices.SortStableFunc(relevant, func(a, b scored) int {
+		return cmp.Or(cmp.Compare(b.score, a.score), strings.Compare(b.memory.CreatedAt, a.memory.CreatedAt))
+	})
+	if limit > 0 && len(relevant) > limit {
+		relevant = relevant[:limit]
+	}
+
+	result := make([]database.UserMemory, len(relevant))
+	for i, r := range relevant {
+		result[i] = r.memory
+	}
+	return result
+}
+
+// terms returns the distinct words of a text that tell what it's about: in
+// lower case, without the short and the most common ones.
+func terms(text string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	result := map[string]bool{}
+	for _, word := range words {
+		if len([]rune(word)) > 2 && !stopWords[word] {
+			result[word] = true
+		}
+	}
+	return result
+}
+
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "but": true, "not": true, "you": true,
+	"all": true, "any": true, "can": true, "was": true, "has": true, "have": true, "had": true,
+	"this": true, "that": true, "with": true, "from": true, "what": true, "when": true,
+	"where": true, "which": true, "who": true, "how": true, "why": true, "does": true,
+	"did": true, "will": true, "would": true, "should": true, "could": true, "about": true,
+	"into": true, "your": true, "its": true, "our": true, "their": true, "there": true,
+	"them": true, "they": true, "then": true, "than": true, "some": true, "use": true,
+	"please": true,
+}
@@ -89,7 +89,7 @@ func TestMemoryTool_HandleGetMemories(t *testing.T) {
 	}
 	manager.On("GetMemories", mock.Anything).Return(memories, nil)
 
-	result, err := tool.handleGetMemories(t.Context(), nil)
+	result, err := tool.handleGetMemories(t.Context(), GetMemoriesArgs{})
 	require.NoError(t, err)
 
 	var returnedMemories []database.UserMemory
@@ -144,3 +144,74 @@ func TestMemoryTool_ParametersAreObjects(t *testing.T) {
 		assert.Equal(t, "object", m["type"])
 	}
 }
+
+func TestMemoryTool_Scope(t *testing.T) {
+	manager := new(MockDB)
+	tool := NewMemoryTool(manager, WithMemoryScope("agent:root"))
+
+	manager.On("AddMemory", mock.Anything, mock.MatchedBy(func(memory database.UserMemory) bool {
+		return memory.Scope == "agent:root"
+	})).Return(nil)
+	_, err := tool.handleAddMemory(t.Context(), AddMemoryArgs{Memory: "Likes Go"})
+	require.NoError(t, err)
+
+	manager.On("GetMemories", mock.Anything).Return([]database.UserMemory{
+		{ID: "1", Memory: "Likes Go", Scope: "agent:root"},
+		{ID: "2", Memory: "Likes Rust", Scope: "agent:reviewer"},
+		{ID: "3", Memory: "Likes Python"},
+	}, nil)
+	result, err := tool.handleGetMemories(t.Context(), GetMemoriesArgs{})
+	require.NoError(t, err)
+
+	var returnedMemories []database.UserMemory
+	require.NoError(t, json.Unmarshal([]byte(result.Output), &returnedMemories))
+	require.Len(t, returnedMemories, 1)
+	assert.Equal(t, "1", returnedMemories[0].ID)
+
+	// The memories of other scopes can't be deleted
+	result, err = tool.handleDeleteMemory(t.Context(), DeleteMemoryArgs{ID: "2"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	manager.AssertNotCalled(t, "DeleteMemory", mock.Anything, mock.Anything)
+}
+
+func TestMemoryTool_Recall(t *testing.T) {
+	manager := new(MockDB)
+	manager.On("GetMemories", mock.Anything).Return([]database.UserMemory{
+		{ID: "1", CreatedAt: "2025-01-01T00:00:00Z", Memory: "The user prefers tabs for indentation"},
+		{ID: "2", CreatedAt: "2025-01-02T00:00:00Z", Memory: "The user's favorite language is Go"},
+		{ID: "3", CreatedAt: "2025-01-03T00:00:00Z", Memory: "Deploys go through the staging cluster first"},
+		{ID: "4", CreatedAt: "2025-01-04T00:00:00Z", Memory: "Indentation in Go files is done by gofmt"},
+	}, nil)
+
+	recalled, err := NewMemoryTool(manager, WithRecall(2)).Recall(t.Context(), "Which indentation should I use in Go?")
+	require.NoError(t, err)
+
+	var ids []string
+	for _, memory := range recalled {
+		ids = append(ids, memory.ID)
+	}
+	assert.Equal(t, []string{"4", "1"}, ids)
+
+	// Recalling is off by default
+	recalled, err = NewMemoryTool(manager).Recall(t.Context(), "Which indentation should I use in Go?")
+	require.NoError(t, err)
+	assert.Empty(t, recalled)
+}
+
+func TestMemoryTool_GetMemoriesWithQuery(t *testing.T) {
+	manager := new(MockDB)
+	tool := NewMemoryTool(manager)
+
+	manager.On("GetMemories", mock.Anything).Return([]database.UserMemory{
+		{ID: "1", Memory: "Likes cats"},
+		{ID: "2", Memory: "Deploys on Fridays"},
+	}, nil)
+	result, err := tool.handleGetMemories(t.Context(), GetMemoriesArgs{Query: "when are deploys?"})
+	require.NoError(t, err)
+
+	var returnedMemories []database.UserMemory
+	require.NoError(t, json.Unmarshal([]byte(result.Output), &returnedMemories))
+	require.Len(t, returnedMemories, 1)
+	assert.Equal(t, "2", returnedMemories[0].ID)
+}
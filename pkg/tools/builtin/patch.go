@@ -0,0 +1,320 @@
+package builtin
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Operations on the files of a patch
+const (
+	PatchCreate = "create"
+	PatchModify = "modify"
+	PatchDelete = "delete"
+	PatchRename = "rename"
+)
+
+// filePatch is the change of a file in a unified diff.
+type filePatch struct {
+	oldPath string
+	newPath string
+	hunks   []hunk
+}
+
+// hunk is a change of a file: the lines starting with ' ' or '-' are replaced
+// with the lines starting with ' ' or '+'.
+type hunk struct {
+	// oldStart is the line, from 1, where the hunk starts in the original
+	// file. It's only a hint, models often get it wrong.
+	oldStart int
+	lines    []string
+	// noNewline tells which lines are followed by "\ No newline at end of
+	// file"
+	noNewline map[int]bool
+}
+
+func (p *filePatch) operation() string {
+	switch {
+	case p.oldPath == "":
+		return PatchCreate
+	case p.newPath == "":
+		return PatchDelete
+	case p.oldPath != p.newPath:
+		return PatchRename
+	default:
+		return PatchModify
+	}
+}
+
+// path is the path of the file once patched, or the path of the deleted file.
+func (p *filePatch) path() string {
+	if p.newPath == "" {
+		return p.oldPath
+	}
+	return p.newPath
+}
+
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,\d+)? \+\d+(?:,\d+)? @@`)
+
+// parsePatch parses a unified diff, as written by diff -u or git diff, of
+// one or more files. The line counts of the hunks are ignored: a hunk ends
+// at the next hunk or file.
+func parsePatch(patch string) ([]*filePatch, error) {
+	lines := strings.Split(strings.ReplaceAll(patch, "\r\n", "\n"), "\n")
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	var patches []*filePatch
+	var current *filePatch
+	var h *hunk
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		switch {
+		case strings.HasPrefix(line, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ "):
+			current = &filePatch{
+				oldPath: patchPath(strings.TrimPrefix(line, "--- "), "a/"),
+				newPath: patchPath(strings.TrimPrefix(lines[i+1], "+++ "), "b/"),
+			}
+			if current.oldPath == "" && current.newPath == "" {
+				return nil, fmt.Errorf("line %d: a patch needs the path of the file", i+1)
+			}
+			patches = append(patches, current)
+			h = nil
+			i++
+		case strings.HasPrefix(line, "@@"):
+			if current == nil {
+				return nil, fmt.Errorf("line %d: hunk without the ---/+++ lines of its file", i+1)
+			}
+			m := hunkHeader.FindStringSubmatch(line)
+			if m == nil {
+				return nil, fmt.Errorf("line %d: invalid hunk header %q", i+1, line)
+			}
+			start, _ := strconv.Atoi(m[1])
+			current.hunks = append(current.hunks, hunk{oldStart: start, noNewline: map[int]bool{}})
+			h = &current.hunks[len(current.hunks)-1]
+		case h == nil:
+			// Headers of git, or text around the patch
+		case strings.HasPrefix(line, `\`):
+			if len(h.lines) > 0 {
+				h.noNewline[len(h.lines)-1] = true
+			}
+		case line == "":
+			// Models often drop the space of empty context lines
+			h.lines = append(h.lines, " ")
+		case line[0] == ' ' || line[0] == '-' || line[0] == '+':
+			h.lines = append(h.lines, line)
+		case strings.HasPrefix(line, "diff "):
+			h = nil
+		default:
+			return nil, fmt.Errorf("line %d: unexpected line in a hunk %q, lines of hunks start with ' ', '-' or '+'", i+1, line)
+		}
+	}
+
+	if len(patches) == 0 {
+		return nil, errors.New("no file in the patch, expected a unified diff with ---/+++ lines")
+	}
+	for _, p := range patches {
+		if len(p.hunks) == 0 && p.operation() != PatchDelete && p.operation() != PatchRename {
+			return nil, fmt.Errorf("no hunk for %s", p.path())
+		}
+	}
+	return patches, nil
+}
+
+// patchPath returns the path of a ---/+++ line, without the prefix of git
+// and the timestamp of diff, or an empty path for /dev/null.
+func patchPath(s, prefix string) string {
+	if before, _, ok := strings.Cut(s, "\t"); ok {
+		s = before
+	}
+	s = strings.TrimSpace(s)
+	if s == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(s, prefix)
+}
+
+// PatchedFiles returns the paths of the files a unified diff changes.
+func PatchedFiles(patch string) []string {
+	patches, err := parsePatch(patch)
+	if err != nil {
+		return nil
+	}
+	var paths []string
+	for _, p := range patches {
+		paths = append(paths, p.path())
+	}
+	return paths
+}
+
+// apply applies the hunks to content, in order, and returns the patched
+// content with the numbers of lines added and removed.
+func (p *filePatch) apply(content string) (patched string, added, removed int, err error) {
+	eol := content == "" || strings.HasSuffix(content, "\n")
+	var lines []string
+	if content != "" {
+		lines = strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	}
+
+	// The hunks apply in order: each one is looked for after the previous one
+	from, offset := 0, 0
+	for n, h := range p.hunks {
+		var old []string
+		for _, line := range h.lines {
+			if line[0] != '+' {
+				old = append(old, line[1:])
+			}
+		}
+
+		// Hunks adding lines only start after their line
+		hint := h.oldStart - 1
+		if len(old) == 0 {
+			hint = h.oldStart
+		}
+		at := findLines(lines, old, from, hint+offset)
+		if at < 0 {
+			return "", 0, 0, fmt.Errorf("hunk %d doesn't apply: the lines it changes weren't found", n+1)
+		}
+
+		// The context lines are kept as they are in the file, in case they
+		// only matched without their trailing whitespace
+		var replacement []string
+		newEnd := false
+		i := at
+		for j, line := range h.lines {
+			switch line[0] {
+			case ' ':
+				replacement = append(replacement, lines[i])
+				i++
+			case '-':
+				removed++
+				i++
+			case '+':
+				replacement = append(replacement, line[1:])
+				added++
+			}
+			if line[0] != '-' {
+				newEnd = h.noNewline[j]
+			}
+		}
+
+		atEnd := at+len(old) == len(lines)
+		lines = append(lines[:at], append(replacement, lines[at+len(old):]...)...)
+		from = at + len(replacement)
+		offset += len(replacement) - len(old)
+
+		// The end of the file has a newline unless the hunk tells otherwise
+		if atEnd {
+			eol = !newEnd
+		}
+	}
+
+	if len(lines) == 0 {
+		return "", added, removed, nil
+	}
+	patched = strings.Join(lines, "\n")
+	if eol {
+		patched += "\n"
+	}
+	return patched, added, removed, nil
+}
+
+// findLines returns where the lines of old are in lines, at or after from,
+// the closest to hint. Lines differing by trailing whitespace only match if
+// no exact match is found. It returns -1 if they're not found.
+func findLines(lines, old []string, from, hint int) int {
+	if len(old) == 0 {
+		return min(max(hint, from), len(lines))
+	}
+
+	for _, equal := range []func(a, b string) bool{
+		func(a, b string) bool { return a == b },
+		func(a, b string) bool { return strings.TrimRight(a, " \t") == strings.TrimRight(b, " \t") },
+	} {
+		matches := func(at int) bool {
+			for i := range old {
+				if !equal(lines[at+i], old[i]) {
+					return false
+				}
+			}
+			return true
+		}
+
+		last := len(lines) - len(old)
+		hint := min(max(hint, from), last)
+		for d := 0; hint-d >= from || hint+d <= last; d++ {
+			if at := hint - d; at >= from && at <= last && matches(at) {
+				return at
+			}
+			if at := hint + d; d > 0 && at >= from && at <= last && matches(at) {
+				return at
+			}
+		}
+	}
+	return -1
+}
+
+// fileChange is the new content of a file, or its removal.
+type fileChange struct {
+	path    string
+	content string
+	remove  bool
+}
+
+// commitChanges writes the changes of files, all of them or none: if one
+// can't be written, the files already changed are restored.
+func commitChanges(changes []fileChange) error {
+	type backup struct {
+		path    string
+		content []byte
+		mode    fs.FileMode
+		existed bool
+	}
+	var backups []backup
+
+	rollback := func() error {
+		var errs []error
+		for _, b := range backups {
+			if b.existed {
+				errs = append(errs, os.WriteFile(b.path, b.content, b.mode))
+			} else if err := os.Remove(b.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}
+
+	for _, change := range changes {
+		b := backup{path: change.path, mode: 0o644}
+		if info, err := os.Stat(change.path); err == nil {
+			content, err := os.ReadFile(change.path)
+			if err != nil {
+				return errors.Join(fmt.Errorf("reading %s: %w", change.path, err), rollback())
+			}
+			b.content, b.mode, b.existed = content, info.Mode().Perm(), true
+		}
+
+		var err error
+		if change.remove {
+			err = os.Remove(change.path)
+		} else {
+			if err = os.MkdirAll(filepath.Dir(change.path), 0o755); err == nil {
+				err = os.WriteFile(change.path, []byte(change.content), b.mode)
+			}
+		}
+		if err != nil {
+			if rbErr := rollback(); rbErr != nil {
+				return fmt.Errorf("%w, and the files already changed couldn't be restored: %w", err, rbErr)
+			}
+			return fmt.Errorf("%w: no file was changed", err)
+		}
+		backups = append([]backup{b}, backups...)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package builtin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePatch(t *testing.T) {
+	t.Parallel()
+
+	patches, err := parsePatch(`diff --git a/main.go b/main.go
+index 83db48f..bf269f4 100644
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,3 @@
+ package main
+-var x = 1
++var x = 2
+
+@@ -10,2 +10,3 @@ func main() {
+ 	run()
++	stop()
+ }
+--- /dev/null
++++ b/new.txt
+@@ -0,0 +1 @@
++hello
+\ No newline at end of file
+--- old.txt	2025-01-01 10:00:00
++++ /dev/null
+@@ -1 +0,0 @@
+-bye
+`)
+	require.NoError(t, err)
+	require.Len(t, patches, 3)
+
+	assert.Equal(t, PatchModify, patches[0].operation())
+	assert.Equal(t, "main.go", patches[0].path())
+	require.Len(t, patches[0].hunks, 2)
+	assert.Equal(t, 1, patches[0].hunks[0].oldStart)
+	assert.Equal(t, []string{" package main", "-var x = 1", "+var x = 2", " "}, patches[0].hunks[0].lines)
+
+	assert.Equal(t, PatchCreate, patches[1].operation())
+	assert.Equal(t, "new.txt", patches[1].path())
+	assert.True(t, patches[1].hunks[0].noNewline[0])
+
+	assert.Equal(t, PatchDelete, patches[2].operation())
+	assert.Equal(t, "old.txt", patches[2].path())
+
+	assert.Equal(t, []string{"main.go", "new.txt", "old.txt"}, PatchedFiles(`--- a/main.go
++++ b/main.go
+@@ -1 +1 @@
+-a
++b
+--- /dev/null
++++ b/new.txt
+@@ -0,0 +1 @@
++hello
+--- a/old.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-bye`))
+}
+
+func TestParsePatch_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for patch, expected := range map[string]string{
+		"just some text":                              "no file in the patch",
+		"@@ -1 +1 @@\n-a\n+b":                         "hunk without the ---/+++ lines",
+		"--- a/x\n+++ b/x\n@@ invalid @@":             "invalid hunk header",
+		"--- a/x\n+++ b/x\n@@ -1 +1 @@\n-a\nnot diff": "unexpected line in a hunk",
+		"--- a/x\n+++ b/x":                            "no hunk for x",
+	} {
+		_, err := parsePatch(patch)
+		assert.ErrorContains(t, err, expected, patch)
+	}
+}
+
+func TestFilePatch_Apply(t *testing.T) {
+	t.Parallel()
+
+	apply := func(content, patch string) (string, error) {
+		patches, err := parsePatch(patch)
+		require.NoError(t, err)
+		patched, _, _, err := patches[0].apply(content)
+		return patched, err
+	}
+
+	// Wrong line numbers: hunks are located by their lines
+	patched, err := apply("a\nb\nc\nd\ne\n", "--- a/f\n+++ b/f\n@@ -1,2 +1,2 @@\n d\n-e\n+E\n")
+	require.NoError(t, err)
+	assert.Equal(t, "a\nb\nc\nd\nE\n", patched)
+
+	// The closest match to the line of the hunk is patched
+	patched, err = apply("x\ny\nx\ny\nx\n", "--- a/f\n+++ b/f\n@@ -3,2 +3,2 @@\n x\n-y\n+Y\n")
+	require.NoError(t, err)
+	assert.Equal(t, "x\ny\nx\nY\nx\n", patched)
+
+	// Trailing whitespace is ignored when there's no exact match
+	patched, err = apply("a  \nb\n", "--- a/f\n+++ b/f\n@@ -1,2 +1,2 @@\n a\n-b\n+B\n")
+	require.NoError(t, err)
+	assert.Equal(t, "a  \nB\n", patched)
+
+	// Newline at the end of the file
+	patched, err = apply("a\nb", "--- a/f\n+++ b/f\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+B\n")
+	require.NoError(t, err)
+	assert.Equal(t, "a\nB\n", patched)
+
+	patched, err = apply("a\nb\n", "--- a/f\n+++ b/f\n@@ -1,2 +1,2 @@\n a\n-b\n+B\n\\ No newline at end of file\n")
+	require.NoError(t, err)
+	assert.Equal(t, "a\nB", patched)
+
+	_, err = apply("a\nb\n", "--- a/f\n+++ b/f\n@@ -1,2 +1,2 @@\n a\n-c\n+C\n")
+	require.ErrorContains(t, err, "hunk 1 doesn't apply")
+}
+
+func TestCommitChanges_Rollback(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing.txt")
+	require.NoError(t, os.WriteFile(existing, []byte("original"), 0o644))
+	created := filepath.Join(dir, "created.txt")
+	// A file can't be written below a file
+	blocked := filepath.Join(existing, "blocked.txt")
+
+	err := commitChanges([]fileChange{
+		{path: existing, content: "changed"},
+		{path: created, content: "new"},
+		{path: blocked, content: "never written"},
+	})
+	require.ErrorContains(t, err, "no file was changed")
+
+	content, err := os.ReadFile(existing)
+	require.NoError(t, err)
+	assert.Equal(t, "original", string(content))
+	assert.NoFileExists(t, created)
+}
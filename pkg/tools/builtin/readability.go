@@ -0,0 +1,126 @@
+package builtin
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// clutter are the elements that aren't the content of a page.
+var clutter = map[atom.Atom]bool{
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Noscript: true,
+	atom.Template: true,
+	atom.Svg:      true,
+	atom.Canvas:   true,
+	atom.Iframe:   true,
+	atom.Form:     true,
+	atom.Button:   true,
+	atom.Nav:      true,
+	atom.Footer:   true,
+	atom.Aside:    true,
+}
+
+// clutterRoles are the ARIA roles of the elements that aren't the content of
+// a page.
+var clutterRoles = map[string]bool{
+	"navigation":    true,
+	"banner":        true,
+	"contentinfo":   true,
+	"complementary": true,
+	"search":        true,
+	"dialog":        true,
+}
+
+// extractReadable returns the title of an HTML page and the HTML of its main
+// content: its article, or main element, without the navigation, the headers
+// of the page, footers, scripts and the like. It returns the page as is if it
+// can't be parsed.
+func extractReadable(page string) (title, content string) {
+	doc, err := html.Parse(strings.NewReader(page))
+	if err != nil {
+		return "", page
+	}
+
+	if n := findElement(doc, func(n *html.Node) bool { return n.DataAtom == atom.Title }); n != nil {
+		title = strings.Join(strings.Fields(textContent(n)), " ")
+	}
+
+	removeClutter(doc, false)
+
+	root := findElement(doc, func(n *html.Node) bool { return n.DataAtom == atom.Article })
+	if root == nil {
+		root = findElement(doc, isMain)
+	}
+	if root == nil {
+		root = findElement(doc, func(n *html.Node) bool { return n.DataAtom == atom.Body })
+	}
+	if root == nil {
+		return title, page
+	}
+
+	var b strings.Builder
+	for c := root.FirstChild; c != nil; c = c.NextSibling {
+		if err := html.Render(&b, c); err != nil {
+			return title, page
+		}
+	}
+	return title, b.String()
+}
+
+func isMain(n *html.Node) bool {
+	return n.DataAtom == atom.Main || attr(n, "role") == "main"
+}
+
+// removeClutter removes the elements that aren't content below n. The
+// headers of articles, with their titles, are content.
+func removeClutter(n *html.Node, inContent bool) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		switch {
+		case c.Type == html.CommentNode:
+			n.RemoveChild(c)
+		case c.Type != html.ElementNode:
+		case clutter[c.DataAtom] || clutterRoles[attr(c, "role")] || attr(c, "aria-hidden") == "true",
+			c.DataAtom == atom.Header && !inContent:
+			n.RemoveChild(c)
+		default:
+			removeClutter(c, inContent || c.DataAtom == atom.Article || isMain(c))
+		}
+		c = next
+	}
+}
+
+func findElement(n *html.Node, match func(*html.Node) bool) *html.Node {
+	if n.Type == html.ElementNode && match(n) {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findElement(c, match); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(textContent(c))
+	}
+	return b.String()
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,45 @@
+package builtin
+
+import (
+	"context"
+
+	"github.com/docker/cagent/pkg/tools"
+)
+
+const ToolNameReadMore = "read_more"
+
+// ReadMoreTool gives access to the raw output of tool results that were
+// summarized, or truncated, before being added to the context. Its handler is provided by
+// the runtime, which keeps the raw outputs.
+type ReadMoreTool struct {
+	tools.BaseToolSet
+}
+
+// Make sure Read More Tool implements the ToolSet Interface
+var _ tools.ToolSet = (*ReadMoreTool)(nil)
+
+type ReadMoreArgs struct {
+	ID     string `json:"id" jsonschema:"The ID of the summarized or truncated tool result."`
+	Offset int    `json:"offset,omitempty" jsonschema:"The character offset to start reading from (optional, defaults to 0)."`
+	Length int    `json:"length,omitempty" jsonschema:"The number of characters to read (optional, defaults to 8000)."`
+}
+
+func NewReadMoreTool() *ReadMoreTool {
+	return &ReadMoreTool{}
+}
+
+func (t *ReadMoreTool) Tools(context.Context) ([]tools.Tool, error) {
+	return []tools.Tool{
+		{
+			Name:     ToolNameReadMore,
+			Category: "read_more",
+			Description: `Read the raw output of a tool result that was summarized because it was too large.
+Use it when the summary misses details you need. Read the output in chunks using offset and length.`,
+			Parameters: tools.MustSchemaFor[ReadMoreArgs](),
+			Annotations: tools.ToolAnnotations{
+				ReadOnlyHint: true,
+				Title:        "Read More",
+			},
+		},
+	}, nil
+}
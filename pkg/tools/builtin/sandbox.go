@@ -35,6 +35,9 @@ type sandboxRunner struct {
 	env         []string
 	containerID string
 	mu          sync.Mutex
+
+	statusMu      sync.Mutex
+	statusHandler tools.StatusHandler
 }
 
 func newSandboxRunner(config *latest.SandboxConfig, workingDir string, env []string) *sandboxRunner {
@@ -103,11 +106,7 @@ func (s *sandboxRunner) runCommand(timeoutCtx, ctx context.Context, command, cwd
 		return tools.ResultError(fmt.Sprintf("Failed to start sandbox container: %s", err))
 	}
 
-	args := []string{"exec", "-w", cwd}
-	args = append(args, s.buildEnvVars()...)
-	args = append(args, containerID, "/bin/sh", "-c", command)
-
-	cmd := exec.CommandContext(timeoutCtx, "docker", args...)
+	cmd := exec.CommandContext(timeoutCtx, "docker", s.execArgs(containerID, cwd, command)...)
 	var outBuf bytes.Buffer
 	cmd.Stdout = &outBuf
 	cmd.Stderr = &outBuf
@@ -118,6 +117,14 @@ func (s *sandboxRunner) runCommand(timeoutCtx, ctx context.Context, command, cwd
 	return tools.ResultSuccess(limitOutput(output))
 }
 
+// execArgs returns the arguments of the docker command running a command in
+// the sandbox container.
+func (s *sandboxRunner) execArgs(containerID, cwd, command string) []string {
+	args := []string{"exec", "-w", cwd}
+	args = append(args, s.buildEnvVars()...)
+	return append(args, containerID, "/bin/sh", "-c", command)
+}
+
 // stop stops and removes the sandbox container.
 func (s *sandboxRunner) stop() {
 	s.mu.Lock()
@@ -131,6 +138,34 @@ func (s *sandboxRunner) stop() {
 	_ = stopCmd.Run()
 
 	s.containerID = ""
+	s.notify(tools.ServerStopped, nil)
+}
+
+func (s *sandboxRunner) setStatusHandler(handler tools.StatusHandler) {
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+	s.statusHandler = handler
+}
+
+// notify tells in which state the container is, as for the containers of
+// MCP servers.
+func (s *sandboxRunner) notify(state tools.ServerState, err error) {
+	s.statusMu.Lock()
+	handler := s.statusHandler
+	s.statusMu.Unlock()
+
+	if handler == nil {
+		return
+	}
+	status := tools.ServerStatus{Server: "shell", State: state, Container: s.image()}
+	if err != nil {
+		status.Error = err.Error()
+	}
+	handler(status)
+}
+
+func (s *sandboxRunner) image() string {
+	return cmp.Or(s.config.Image, "alpine:latest")
 }
 
 // ensureContainer ensures the sandbox container is running, starting it if necessary.
@@ -153,19 +188,11 @@ func (s *sandboxRunner) isContainerRunning(ctx context.Context) bool {
 }
 
 func (s *sandboxRunner) startContainer(ctx context.Context) (string, error) {
-	containerName := s.generateContainerName()
-	image := cmp.Or(s.config.Image, "alpine:latest")
+	s.notify(tools.ServerStarting, nil)
 
-	args := []string{
-		"run", "-d",
-		"--name", containerName,
-		"--rm", "--init", "--network", "host",
-		"--label", sandboxLabelKey + "=true",
-		"--label", fmt.Sprintf("%s=%d", sandboxLabelPID, os.Getpid()),
-	}
-	args = append(args, s.buildVolumeMounts()...)
-	args = append(args, s.buildEnvVars()...)
-	args = append(args, "-w", s.workingDir, image, "tail", "-f", "/dev/null")
+	args := []string{"run", "-d", "--name", s.generateContainerName()}
+	args = append(args, s.runArgs()...)
+	args = append(args, "tail", "-f", "/dev/null")
 
 	cmd := exec.CommandContext(ctx, "docker", args...)
 	var stderr bytes.Buffer
@@ -173,13 +200,55 @@ func (s *sandboxRunner) startContainer(ctx context.Context) (string, error) {
 
 	output, err := cmd.Output()
 	if err != nil {
-		return "", fmt.Errorf("failed to start sandbox container: %w\nstderr: %s", err, stderr.String())
+		err = fmt.Errorf("failed to start sandbox container: %w\nstderr: %s", err, stderr.String())
+		s.notify(tools.ServerStopped, err)
+		return "", err
 	}
 
 	s.containerID = strings.TrimSpace(string(output))
+	s.notify(tools.ServerRunning, nil)
 	return s.containerID, nil
 }
 
+// runArgs returns the arguments of docker run shared by the sandbox
+// containers, up to their image.
+func (s *sandboxRunner) runArgs() []string {
+	network := "host"
+	if !s.config.HasNetwork() {
+		network = "none"
+	}
+
+	args := []string{
+		"--rm", "--init", "--network", network,
+		"--label", sandboxLabelKey + "=true",
+		"--label", fmt.Sprintf("%s=%d", sandboxLabelPID, os.Getpid()),
+	}
+	args = append(args, s.buildVolumeMounts()...)
+	args = append(args, s.buildEnvVars()...)
+	if s.workingDir != "" {
+		args = append(args, "-w", s.workingDir)
+	}
+	return append(args, s.image())
+}
+
+// SandboxCommand returns the docker command running a command in an
+// ephemeral sandbox container, removed once the command exits. The command
+// talks through its standard input and output, like stdio MCP servers do.
+// Only env is passed to the container, not the environment of cagent.
+func SandboxCommand(config *latest.SandboxConfig, workingDir string, env []string, command string, args []string) (string, []string) {
+	cleanupOrphanedSandboxContainers()
+
+	s := &sandboxRunner{
+		config:     config,
+		workingDir: workingDir,
+		env:        env,
+	}
+
+	dockerArgs := append([]string{"run", "-i"}, s.runArgs()...)
+	dockerArgs = append(dockerArgs, command)
+	return "docker", append(dockerArgs, args...)
+}
+
 func (s *sandboxRunner) generateContainerName() string {
 	randomBytes := make([]byte, 4)
 	_, _ = rand.Read(randomBytes)
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"sync"
@@ -27,6 +28,10 @@ const (
 	ToolNameStopBackgroundJob  = "stop_background_job"
 )
 
+// checkTimeout bounds the commands of todo checks, longer than the default
+// timeout of the shell tool since they typically run test suites.
+const checkTimeout = 5 * time.Minute
+
 // ShellTool provides shell command execution capabilities.
 type ShellTool struct {
 	tools.BaseToolSet
@@ -44,6 +49,11 @@ type shellHandler struct {
 	jobs            *concurrent.Map[string, *backgroundJob]
 	jobCounter      atomic.Int64
 	sandbox         *sandboxRunner
+	// allowedCommands, if set, are the only commands that can run
+	allowedCommands []string
+	// confined restricts the directory the commands start in to the
+	// working directory, or below
+	confined bool
 }
 
 // Job status constants
@@ -141,6 +151,9 @@ func (h *shellHandler) RunShell(ctx context.Context, params RunShellArgs) (*tool
 	defer cancel()
 
 	cwd := h.resolveWorkDir(params.Cwd)
+	if err := h.checkCommand(params.Cmd, cwd); err != nil {
+		return tools.ResultError(err.Error()), nil
+	}
 
 	// Delegate to sandbox runner if configured
 	if h.sandbox != nil {
@@ -185,13 +198,45 @@ func (h *shellHandler) runNativeCommand(timeoutCtx, ctx context.Context, command
 	return tools.ResultSuccess(limitOutput(output))
 }
 
+// runCheck runs a command in the working directory, in the sandbox if there's
+// one, and returns its output. The error is set when it doesn't exit with 0.
+func (h *shellHandler) runCheck(ctx context.Context, command string) (string, error) {
+	if err := h.checkCommand(command, h.workingDir); err != nil {
+		return "", err
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if h.sandbox != nil {
+		containerID, err := h.sandbox.ensureContainer(ctx)
+		if err != nil {
+			return "", fmt.Errorf("starting sandbox container: %w", err)
+		}
+		cmd = exec.CommandContext(timeoutCtx, "docker", h.sandbox.execArgs(containerID, h.workingDir, command)...)
+	} else {
+		cmd = exec.CommandContext(timeoutCtx, h.shell, append(h.shellArgsPrefix, command)...)
+		cmd.Env = h.env
+		cmd.Dir = h.workingDir
+	}
+
+	out, err := cmd.CombinedOutput()
+	return limitOutput(strings.TrimSpace(string(out))), err
+}
+
 func (h *shellHandler) RunShellBackground(_ context.Context, params RunShellBackgroundArgs) (*tools.ToolCallResult, error) {
+	cwd := h.resolveWorkDir(params.Cwd)
+	if err := h.checkCommand(params.Cmd, cwd); err != nil {
+		return tools.ResultError(err.Error()), nil
+	}
+
 	counter := h.jobCounter.Add(1)
 	jobID := fmt.Sprintf("job_%d_%d", time.Now().Unix(), counter)
 
 	cmd := exec.Command(h.shell, append(h.shellArgsPrefix, params.Cmd)...)
 	cmd.Env = h.env
-	cmd.Dir = h.resolveWorkDir(params.Cwd)
+	cmd.Dir = cwd
 	cmd.SysProcAttr = platformSpecificSysProcAttr()
 
 	outputBuf := &bytes.Buffer{}
@@ -334,7 +379,7 @@ func (h *shellHandler) StopBackgroundJob(_ context.Context, params StopBackgroun
 }
 
 // NewShellTool creates a new shell tool with optional sandbox configuration.
-func NewShellTool(env []string, runConfig *config.RuntimeConfig, sandboxConfig *latest.SandboxConfig) *ShellTool {
+func NewShellTool(env []string, runConfig *config.RuntimeConfig, sandboxConfig *latest.SandboxConfig, opts ...ShellToolOption) *ShellTool {
 	shell, argsPrefix := detectShell(sandboxConfig != nil)
 
 	handler := &shellHandler{
@@ -345,6 +390,9 @@ func NewShellTool(env []string, runConfig *config.RuntimeConfig, sandboxConfig *
 		jobs:            concurrent.NewMap[string, *backgroundJob](),
 		workingDir:      runConfig.WorkingDir,
 	}
+	for _, opt := range opts {
+		opt(handler)
+	}
 
 	if sandboxConfig != nil {
 		handler.sandbox = newSandboxRunner(sandboxConfig, runConfig.WorkingDir, env)
@@ -376,11 +424,15 @@ func detectWindowsShell() (shell string, argsPrefix []string) {
 	return cmp.Or(os.Getenv("ComSpec"), "cmd.exe"), []string{"/C"}
 }
 
-// resolveWorkDir returns the effective working directory.
+// resolveWorkDir returns the effective working directory. Relative
+// directories are relative to the working directory of the agent.
 func (h *shellHandler) resolveWorkDir(cwd string) string {
 	if cwd == "" || cwd == "." {
 		return h.workingDir
 	}
+	if !filepath.IsAbs(cwd) && h.workingDir != "" {
+		return filepath.Join(h.workingDir, cwd)
+	}
 	return cwd
 }
 
@@ -402,6 +454,12 @@ func formatCommandOutput(timeoutCtx, ctx context.Context, err error, rawOutput s
 	return cmp.Or(strings.TrimSpace(output), "<no output>")
 }
 
+// RunCheck runs the command of a todo check the way the shell tool runs
+// commands. The error is set when the command doesn't exit with 0.
+func (t *ShellTool) RunCheck(ctx context.Context, command string) (string, error) {
+	return t.handler.runCheck(ctx, command)
+}
+
 func (t *ShellTool) Instructions() string {
 	if t.handler.sandbox != nil {
 		return t.buildSandboxInstructions()
@@ -419,6 +477,12 @@ func (t *ShellTool) Tools(context.Context) ([]tools.Tool, error) {
 	if t.handler.sandbox != nil {
 		shellDesc = `Executes the given shell command inside a sandboxed Linux container (Alpine Linux with /bin/sh). Only mounted paths are accessible. Installed tools persist across calls.`
 	}
+	if t.handler.allowedCommands != nil {
+		shellDesc += " Only these commands are allowed: " + strings.Join(t.handler.allowedCommands, ", ") + "."
+	}
+	if t.handler.confined {
+		shellDesc += " The cwd of the commands must be the working directory, or below."
+	}
 
 	return []tools.Tool{
 		{
@@ -484,3 +548,11 @@ func (t *ShellTool) Stop(context.Context) error {
 
 	return nil
 }
+
+// SetStatusHandler is notified when the sandbox container, if any, starts and
+// stops.
+func (t *ShellTool) SetStatusHandler(handler tools.StatusHandler) {
+	if t.handler.sandbox != nil {
+		t.handler.sandbox.setStatusHandler(handler)
+	}
+}
@@ -0,0 +1,199 @@
+package builtin
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ShellToolOption restricts what the shell tool can run.
+type ShellToolOption func(*shellHandler)
+
+// WithAllowedCommands only lets the shell tool run the given commands. Every
+// command of a pipeline or a list of commands must be allowed, and commands
+// can't be substituted, since the commands they'd run can't be known.
+// A command is matched by its name, or by its path for the allowed commands
+// with a path.
+func WithAllowedCommands(commands []string) ShellToolOption {
+	return func(h *shellHandler) {
+		h.allowedCommands = commands
+	}
+}
+
+// WithConfinedWorkingDir only lets commands start in the working directory of
+// the agent, or below. The paths the commands are given, or the directories
+// they change to, aren't checked: only a sandbox keeps them away from the
+// rest of the filesystem.
+func WithConfinedWorkingDir() ShellToolOption {
+	return func(h *shellHandler) {
+		h.confined = true
+	}
+}
+
+// FilterEnv returns the variables of env whose names match one of the
+// patterns, such as "PATH" or "GO*".
+func FilterEnv(env, patterns []string) []string {
+	var filtered []string
+	for _, v := range env {
+		name, _, _ := strings.Cut(v, "=")
+		if slices.ContainsFunc(patterns, func(pattern string) bool {
+			matched, _ := path.Match(pattern, name)
+			return matched
+		}) {
+			filtered = append(filtered, v)
+		}
+	}
+	return filtered
+}
+
+// checkCommand returns why command can't run in cwd, if it can't.
+func (h *shellHandler) checkCommand(command, cwd string) error {
+	if h.confined {
+		if err := h.checkWorkDir(cwd); err != nil {
+			return err
+		}
+	}
+	if h.allowedCommands == nil {
+		return nil
+	}
+
+	names, err := commandNames(command)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if !slices.Contains(h.allowedCommands, name) {
+			return fmt.Errorf("command %q is not allowed, the allowed commands are: %s", name, strings.Join(h.allowedCommands, ", "))
+		}
+	}
+	return nil
+}
+
+// checkWorkDir returns an error if dir isn't the working directory of the
+// agent, or below, once symbolic links are resolved.
+func (h *shellHandler) checkWorkDir(dir string) error {
+	root, err := realPath(h.workingDir)
+	if err != nil {
+		return fmt.Errorf("resolving the working directory: %w", err)
+	}
+	resolved, err := realPath(dir)
+	if err != nil {
+		return fmt.Errorf("resolving %s: %w", dir, err)
+	}
+
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("commands can only run in %s", h.workingDir)
+	}
+	return nil
+}
+
+func realPath(p string) (string, error) {
+	if p == "" {
+		var err error
+		if p, err = os.Getwd(); err != nil {
+			return "", err
+		}
+	}
+	p, err := filepath.Abs(p)
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(p)
+}
+
+// commandNames returns the names of the commands a shell command runs: the
+// first word of each command of its pipelines and lists, after the variable
+// assignments. It fails on what would run commands it can't tell, such as
+// command substitutions.
+func commandNames(command string) ([]string, error) {
+	var names []string
+	var word strings.Builder
+	inWord := false      // in the current word, even if empty like ""
+	commandStart := true // the next word is the first of a command
+	var quote rune
+
+	endWord := func() {
+		if !inWord {
+			return
+		}
+		w := word.String()
+		word.Reset()
+		inWord = false
+		if !commandStart {
+			return
+		}
+		// Variable assignments before the command
+		if name, _, ok := strings.Cut(w, "="); ok && isValidEnvVarName(name) {
+			return
+		}
+		names = append(names, w)
+		commandStart = false
+	}
+
+	runes := []rune(command)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\\':
+			inWord = true
+			if i+1 < len(runes) {
+				i++
+				if quote == 0 || strings.ContainsRune(`$"\`+"`", runes[i]) {
+					word.WriteRune(runes[i])
+				} else {
+					word.WriteRune('\\')
+					word.WriteRune(runes[i])
+				}
+			}
+		case r == '`', r == '$' && i+1 < len(runes) && runes[i+1] == '(':
+			return nil, errors.New("command substitutions are not allowed")
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			inWord = true
+			quote = r
+		case (r == '<' || r == '>') && i+1 < len(runes) && runes[i+1] == '(':
+			return nil, errors.New("process substitutions are not allowed")
+		case r == '&' && (i > 0 && (runes[i-1] == '>' || runes[i-1] == '<') || i+1 < len(runes) && runes[i+1] == '>'):
+			// Redirections such as 2>&1 or &>file
+			inWord = true
+			word.WriteRune(r)
+		case r == '#' && !inWord:
+			// Comments run to the end of the line
+			for i+1 < len(runes) && runes[i+1] != '\n' {
+				i++
+			}
+		case r == ';' || r == '&' || r == '|' || r == '\n' || r == '(' || r == ')':
+			endWord()
+			commandStart = true
+		case r == ' ' || r == '\t':
+			endWord()
+		case (r == '{' || r == '}' || r == '!') && !inWord && commandStart:
+			// Groups and negations of commands
+		default:
+			inWord = true
+			word.WriteRune(r)
+		}
+	}
+	if quote != 0 {
+		return nil, errors.New("unterminated quote")
+	}
+	endWord()
+
+	return names, nil
+}
@@ -2,6 +2,7 @@ package builtin
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -151,6 +152,42 @@ func TestParseSandboxPath(t *testing.T) {
 	}
 }
 
+func TestSandboxCommand(t *testing.T) {
+	t.Parallel()
+
+	noNetwork := false
+	command, args := SandboxCommand(&latest.SandboxConfig{
+		Image:   "node:20-alpine",
+		Paths:   []string{"/data:ro"},
+		Network: &noNetwork,
+	}, "/workspace", []string{"API_KEY=secret"}, "npx", []string{"-y", "server"})
+
+	assert.Equal(t, "docker", command)
+	assert.Equal(t, []string{"run", "-i", "--rm", "--init", "--network", "none"}, args[:6])
+	assert.Equal(t, []string{
+		"-v", "/data:/data:ro",
+		"-e", "API_KEY=secret",
+		"-w", "/workspace",
+		"node:20-alpine", "npx", "-y", "server",
+	}, args[len(args)-10:])
+}
+
+func TestShellTool_SandboxStatus(t *testing.T) {
+	t.Parallel()
+
+	tool := NewShellTool(nil, &config.RuntimeConfig{Config: config.Config{WorkingDir: t.TempDir()}}, &latest.SandboxConfig{Paths: []string{"."}})
+	var statuses []tools.ServerStatus
+	tool.SetStatusHandler(func(status tools.ServerStatus) { statuses = append(statuses, status) })
+
+	// The container isn't running: stopping it does nothing
+	tool.handler.sandbox.stop()
+	assert.Empty(t, statuses)
+
+	tool.handler.sandbox.containerID = "cagent-sandbox-test"
+	tool.handler.sandbox.stop()
+	assert.Equal(t, []tools.ServerStatus{{Server: "shell", State: tools.ServerStopped, Container: "alpine:latest"}}, statuses)
+}
+
 func TestShellTool_SandboxInstructions(t *testing.T) {
 	t.Parallel()
 
@@ -233,3 +270,110 @@ func TestIsProcessRunning(t *testing.T) {
 	// Non-existent PID should not be running (using a very high PID unlikely to exist)
 	assert.False(t, isProcessRunning(999999999), "Very high PID should not be running")
 }
+
+func TestShellTool_RunCheck(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	tool := NewShellTool(nil, &config.RuntimeConfig{Config: config.Config{WorkingDir: dir}}, nil)
+
+	output, err := tool.RunCheck(t.Context(), "pwd")
+	require.NoError(t, err)
+	assert.Contains(t, output, dir)
+
+	output, err = tool.RunCheck(t.Context(), "echo broken; exit 3")
+	require.Error(t, err)
+	assert.Equal(t, "broken", output)
+}
+
+func TestCommandNames(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		command string
+		names   []string
+		err     string
+	}{
+		{command: "ls -la", names: []string{"ls"}},
+		{command: "go test ./... && git status", names: []string{"go", "git"}},
+		{command: "cat file | grep foo; echo done", names: []string{"cat", "grep", "echo"}},
+		{command: "GOOS=linux go build", names: []string{"go"}},
+		{command: `echo "a; rm -rf /" 'b | c'`, names: []string{"echo"}},
+		{command: "go test 2>&1 | tail -n 5", names: []string{"go", "tail"}},
+		{command: "(cd sub && make) || { echo failed; }", names: []string{"cd", "make", "echo"}},
+		{command: "ls # rm everything\nwc -l", names: []string{"ls", "wc"}},
+		{command: `"/bin/rm" -rf x`, names: []string{"/bin/rm"}},
+		{command: "echo $(rm -rf x)", err: "command substitutions are not allowed"},
+		{command: "echo `rm -rf x`", err: "command substitutions are not allowed"},
+		{command: `echo "$(rm -rf x)"`, err: "command substitutions are not allowed"},
+		{command: "diff <(ls a) <(ls b)", err: "process substitutions are not allowed"},
+		{command: `echo "unterminated`, err: "unterminated quote"},
+	}
+
+	for _, tt := range tests {
+		names, err := commandNames(tt.command)
+		if tt.err != "" {
+			require.ErrorContains(t, err, tt.err, tt.command)
+			continue
+		}
+		require.NoError(t, err, tt.command)
+		assert.Equal(t, tt.names, names, tt.command)
+	}
+}
+
+func TestShellTool_AllowedCommands(t *testing.T) {
+	t.Parallel()
+
+	tool := NewShellTool(nil, &config.RuntimeConfig{Config: config.Config{WorkingDir: t.TempDir()}}, nil, WithAllowedCommands([]string{"echo", "wc"}))
+
+	result, err := tool.handler.RunShell(t.Context(), RunShellArgs{Cmd: "echo hello | wc -c"})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, "6", result.Output)
+
+	result, err = tool.handler.RunShell(t.Context(), RunShellArgs{Cmd: "echo hello; rm -rf ."})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Output, `command "rm" is not allowed`)
+
+	result, err = tool.handler.RunShellBackground(t.Context(), RunShellBackgroundArgs{Cmd: "sleep 10"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+
+	_, err = tool.RunCheck(t.Context(), "make test")
+	require.ErrorContains(t, err, `command "make" is not allowed`)
+
+	tls, err := tool.Tools(t.Context())
+	require.NoError(t, err)
+	assert.Contains(t, tls[0].Description, "Only these commands are allowed: echo, wc.")
+}
+
+func TestShellTool_ConfinedWorkingDir(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "sub"), 0o755))
+	require.NoError(t, os.Symlink(t.TempDir(), filepath.Join(dir, "escape")))
+	tool := NewShellTool(nil, &config.RuntimeConfig{Config: config.Config{WorkingDir: dir}}, nil, WithConfinedWorkingDir())
+
+	result, err := tool.handler.RunShell(t.Context(), RunShellArgs{Cmd: "pwd", Cwd: "sub"})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Contains(t, result.Output, "sub")
+
+	for _, cwd := range []string{"..", os.TempDir(), "escape"} {
+		result, err := tool.handler.RunShell(t.Context(), RunShellArgs{Cmd: "pwd", Cwd: cwd})
+		require.NoError(t, err)
+		assert.True(t, result.IsError, cwd)
+		assert.Contains(t, result.Output, "commands can only run in "+dir, cwd)
+	}
+}
+
+func TestFilterEnv(t *testing.T) {
+	t.Parallel()
+
+	env := []string{"PATH=/bin", "HOME=/home/user", "GOPATH=/go", "GOFLAGS=-v", "AWS_SECRET_ACCESS_KEY=secret"}
+
+	assert.Equal(t, []string{"PATH=/bin", "GOPATH=/go", "GOFLAGS=-v"}, FilterEnv(env, []string{"PATH", "GO*"}))
+	assert.Empty(t, FilterEnv(env, []string{"USER"}))
+}
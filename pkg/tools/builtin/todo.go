@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/docker/cagent/pkg/concurrent"
+	"github.com/docker/cagent/pkg/todo"
 	"github.com/docker/cagent/pkg/tools"
 )
 
@@ -25,14 +27,9 @@ type TodoTool struct {
 // Make sure Todo Tool implements the ToolSet Interface
 var _ tools.ToolSet = (*TodoTool)(nil)
 
-type Todo struct {
-	ID          string `json:"id" jsonschema:"ID of the todo item"`
-	Description string `json:"description" jsonschema:"Description of the todo item"`
-	Status      string `json:"status" jsonschema:"New status (pending, in-progress,completed)"`
-}
-
 type CreateTodoArgs struct {
-	Description string `json:"description" jsonschema:"Description of the todo item"`
+	Description string           `json:"description" jsonschema:"Description of the todo item"`
+	DoneWhen    []todo.DoneCheck `json:"done_when,omitempty" jsonschema:"Checks that must pass for the todo to be completed, e.g. the tests passing"`
 }
 
 type CreateTodosArgs struct {
@@ -49,7 +46,8 @@ type UpdateTodosArgs struct {
 }
 
 type todoHandler struct {
-	todos *concurrent.Slice[Todo]
+	todos    *concurrent.Slice[todo.Todo]
+	verifier atomic.Pointer[TodoVerifier]
 }
 
 var NewSharedTodoTool = sync.OnceValue(NewTodoTool)
@@ -57,11 +55,27 @@ var NewSharedTodoTool = sync.OnceValue(NewTodoTool)
 func NewTodoTool() *TodoTool {
 	return &TodoTool{
 		handler: &todoHandler{
-			todos: concurrent.NewSlice[Todo](),
+			todos: concurrent.NewSlice[todo.Todo](),
 		},
 	}
 }
 
+// Todos returns a snapshot of the current todo list.
+func (t *TodoTool) Todos() []todo.Todo {
+	return t.handler.todos.All()
+}
+
+// SetVerifier sets how the checks of the todos are run. Without a verifier,
+// todos are completed without running their checks.
+func (t *TodoTool) SetVerifier(verifier TodoVerifier) {
+	t.handler.verifier.Store(&verifier)
+}
+
+// SetTodos replaces the current todo list, e.g. when a session is resumed.
+func (t *TodoTool) SetTodos(todos []todo.Todo) {
+	t.handler.todos.Replace(todos)
+}
+
 func (t *TodoTool) Instructions() string {
 	return `## Using the Todo Tools
 
@@ -84,13 +98,20 @@ This toolset is REQUIRED for maintaining task state and ensuring all steps are c
 }
 
 func (h *todoHandler) createTodo(_ context.Context, params CreateTodoArgs) (*tools.ToolCallResult, error) {
+	for _, check := range params.DoneWhen {
+		if err := check.Validate(); err != nil {
+			return tools.ResultError(err.Error()), nil
+		}
+	}
+
 	id := fmt.Sprintf("todo_%d", h.todos.Length()+1)
-	todo := Todo{
+	item := todo.Todo{
 		ID:          id,
 		Description: params.Description,
 		Status:      "pending",
+		DoneWhen:    params.DoneWhen,
 	}
-	h.todos.Append(todo)
+	h.todos.Append(item)
 
 	return &tools.ToolCallResult{
 		Output: fmt.Sprintf("Created todo [%s]: %s", id, params.Description),
@@ -102,7 +123,7 @@ func (h *todoHandler) createTodos(_ context.Context, params CreateTodosArgs) (*t
 	start := h.todos.Length()
 	for i, desc := range params.Descriptions {
 		id := fmt.Sprintf("todo_%d", start+i+1)
-		h.todos.Append(Todo{
+		h.todos.Append(todo.Todo{
 			ID:          id,
 			Description: desc,
 			Status:      "pending",
@@ -124,22 +145,43 @@ func (h *todoHandler) createTodos(_ context.Context, params CreateTodosArgs) (*t
 	}, nil
 }
 
-func (h *todoHandler) updateTodos(_ context.Context, params UpdateTodosArgs) (*tools.ToolCallResult, error) {
+func (h *todoHandler) updateTodos(ctx context.Context, params UpdateTodosArgs) (*tools.ToolCallResult, error) {
 	var notFound []string
 	var updated []string
+	var reports []string
 
 	for _, update := range params.Updates {
-		_, idx := h.todos.Find(func(t Todo) bool { return t.ID == update.ID })
+		item, idx := h.todos.Find(func(t todo.Todo) bool { return t.ID == update.ID })
 		if idx == -1 {
 			notFound = append(notFound, update.ID)
 			continue
 		}
 
-		h.todos.Update(idx, func(t Todo) Todo {
-			t.Status = update.Status
+		// Completing a todo runs its checks: it goes back in progress if one fails
+		status := update.Status
+		var verification *todo.Verification
+		if status == "completed" && len(item.DoneWhen) > 0 {
+			if verifier := h.verifier.Load(); verifier != nil {
+				verification = verify(ctx, *verifier, item.DoneWhen)
+				if verification.Failed() {
+					status = "in-progress"
+				}
+				reports = append(reports, verificationReport(verification, item.ID))
+			}
+		}
+
+		h.todos.Update(idx, func(t todo.Todo) todo.Todo {
+			t.Status = status
+			if verification != nil {
+				t.Verification = verification
+			}
 			return t
 		})
-		updated = append(updated, fmt.Sprintf("%s -> %s", update.ID, update.Status))
+		if status != update.Status {
+			updated = append(updated, fmt.Sprintf("%s -> %s (checks failed)", update.ID, status))
+		} else {
+			updated = append(updated, fmt.Sprintf("%s -> %s", update.ID, status))
+		}
 	}
 
 	var output strings.Builder
@@ -152,6 +194,9 @@ func (h *todoHandler) updateTodos(_ context.Context, params UpdateTodosArgs) (*t
 		}
 		fmt.Fprintf(&output, "Not found: %s", strings.Join(notFound, ", "))
 	}
+	for _, report := range reports {
+		output.WriteString("\n\n" + report)
+	}
 
 	if len(notFound) > 0 && len(updated) == 0 {
 		return tools.ResultError(output.String()), nil
@@ -167,8 +212,11 @@ func (h *todoHandler) listTodos(_ context.Context, _ tools.ToolCall) (*tools.Too
 	var output strings.Builder
 	output.WriteString("Current todos:\n")
 
-	h.todos.Range(func(_ int, todo Todo) bool {
-		fmt.Fprintf(&output, "- [%s] %s (Status: %s)\n", todo.ID, todo.Description, todo.Status)
+	h.todos.Range(func(_ int, item todo.Todo) bool {
+		fmt.Fprintf(&output, "- [%s] %s (Status: %s)\n", item.ID, item.Description, item.Status)
+		for _, check := range item.DoneWhen {
+			fmt.Fprintf(&output, "  - done when: %s\n", check)
+		}
 		return true
 	})
 
@@ -183,7 +231,7 @@ func (t *TodoTool) Tools(context.Context) ([]tools.Tool, error) {
 		{
 			Name:         ToolNameCreateTodo,
 			Category:     "todo",
-			Description:  "Create a new todo item with a description",
+			Description:  "Create a new todo item with a description, and optionally the checks that must pass for it to be completed",
 			Parameters:   tools.MustSchemaFor[CreateTodoArgs](),
 			OutputSchema: tools.MustSchemaFor[string](),
 			Handler:      tools.NewHandler(t.handler.createTodo),
@@ -0,0 +1,43 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/cagent/pkg/todo"
+)
+
+// verificationReport tells the agent how the checks of a todo went.
+func verificationReport(v *todo.Verification, id string) string {
+	var b strings.Builder
+	switch {
+	case v.Failed():
+		fmt.Fprintf(&b, "Checks of %s failed, it's back in progress:", id)
+	case v.Passed():
+		fmt.Fprintf(&b, "Checks of %s passed:", id)
+	default:
+		fmt.Fprintf(&b, "Some checks of %s were not run, verify them yourself:", id)
+	}
+	for _, r := range v.Results {
+		fmt.Fprintf(&b, "\n- %s: %s", r.Check, r.Status)
+		if r.Status != todo.CheckPassed && r.Output != "" {
+			fmt.Fprintf(&b, "\n%s", r.Output)
+		}
+	}
+	return b.String()
+}
+
+// TodoVerifier runs a check of a todo.
+type TodoVerifier func(ctx context.Context, check todo.DoneCheck) todo.CheckResult
+
+func verify(ctx context.Context, verifier TodoVerifier, checks []todo.DoneCheck) *todo.Verification {
+	v := &todo.Verification{CheckedAt: time.Now()}
+	for _, check := range checks {
+		result := verifier(ctx, check)
+		result.Check = check.String()
+		v.Results = append(v.Results, result)
+	}
+	return v
+}
@@ -1,11 +1,13 @@
 package builtin
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/docker/cagent/pkg/todo"
 	"github.com/docker/cagent/pkg/tools"
 )
 
@@ -39,7 +41,7 @@ func TestTodoTool_CreateTodo(t *testing.T) {
 	assert.Equal(t, "pending", todos[0].Status)
 
 	// Verify Meta contains the created todo
-	metaTodos, ok := result.Meta.([]Todo)
+	metaTodos, ok := result.Meta.([]todo.Todo)
 	require.True(t, ok, "Meta should be []Todo")
 	require.Len(t, metaTodos, 1)
 	assert.Equal(t, "todo_1", metaTodos[0].ID)
@@ -67,7 +69,7 @@ func TestTodoTool_CreateTodos(t *testing.T) {
 	assert.Equal(t, 3, tool.handler.todos.Length())
 
 	// Verify Meta contains all todos (order not guaranteed from map)
-	metaTodos, ok := result.Meta.([]Todo)
+	metaTodos, ok := result.Meta.([]todo.Todo)
 	require.True(t, ok, "Meta should be []Todo")
 	require.Len(t, metaTodos, 3)
 
@@ -88,7 +90,7 @@ func TestTodoTool_CreateTodos(t *testing.T) {
 	assert.Equal(t, 4, tool.handler.todos.Length())
 
 	// Verify Meta for second call contains all 4 todos
-	metaTodos, ok = result.Meta.([]Todo)
+	metaTodos, ok = result.Meta.([]todo.Todo)
 	require.True(t, ok, "Meta should be []Todo")
 	require.Len(t, metaTodos, 4)
 }
@@ -120,7 +122,7 @@ func TestTodoTool_ListTodos(t *testing.T) {
 	}
 
 	// Verify Meta contains all todos
-	metaTodos, ok := result.Meta.([]Todo)
+	metaTodos, ok := result.Meta.([]todo.Todo)
 	require.True(t, ok, "Meta should be []Todo")
 	require.Len(t, metaTodos, 3)
 }
@@ -159,7 +161,7 @@ func TestTodoTool_UpdateTodos(t *testing.T) {
 	assert.Equal(t, "in-progress", todos[2].Status)
 
 	// Verify Meta contains all todos with updated status
-	metaTodos, ok := result.Meta.([]Todo)
+	metaTodos, ok := result.Meta.([]todo.Todo)
 	require.True(t, ok, "Meta should be []Todo")
 	require.Len(t, metaTodos, 3)
 }
@@ -206,6 +208,27 @@ func TestTodoTool_UpdateTodos_AllNotFound(t *testing.T) {
 	assert.Contains(t, result.Output, "Not found: nonexistent1, nonexistent2")
 }
 
+func TestTodoTool_SetTodos(t *testing.T) {
+	tool := NewTodoTool()
+
+	tool.SetTodos([]todo.Todo{
+		{ID: "todo_1", Description: "Restored todo", Status: "completed"},
+		{ID: "todo_2", Description: "Another one", Status: "pending"},
+	})
+	require.Len(t, tool.Todos(), 2)
+
+	// New todos continue the numbering of the restored ones
+	result, err := tool.handler.createTodo(t.Context(), CreateTodoArgs{
+		Description: "Next todo",
+	})
+	require.NoError(t, err)
+	assert.Contains(t, result.Output, "Created todo [todo_3]")
+
+	result, err = tool.handler.listTodos(t.Context(), tools.ToolCall{})
+	require.NoError(t, err)
+	assert.Contains(t, result.Output, "- [todo_1] Restored todo (Status: completed)")
+}
+
 func TestTodoTool_OutputSchema(t *testing.T) {
 	tool := NewTodoTool()
 
@@ -232,3 +255,80 @@ func TestTodoTool_ParametersAreObjects(t *testing.T) {
 		assert.Equal(t, "object", m["type"])
 	}
 }
+
+func TestTodoTool_CreateTodoRejectsInvalidChecks(t *testing.T) {
+	tool := NewTodoTool()
+
+	result, err := tool.handler.createTodo(t.Context(), CreateTodoArgs{
+		Description: "Fix the build",
+		DoneWhen:    []todo.DoneCheck{{Command: "go build ./...", FileExists: "bin/app"}},
+	})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, 0, tool.handler.todos.Length())
+}
+
+func TestTodoTool_CompletingRunsChecks(t *testing.T) {
+	tool := NewTodoTool()
+	failing := "go test ./..."
+	tool.SetVerifier(func(_ context.Context, check todo.DoneCheck) todo.CheckResult {
+		if check.Command == failing {
+			return todo.CheckResult{Status: todo.CheckFailed, Output: "FAIL: TestParse"}
+		}
+		return todo.CheckResult{Status: todo.CheckPassed}
+	})
+
+	_, err := tool.handler.createTodo(t.Context(), CreateTodoArgs{
+		Description: "Fix the parser",
+		DoneWhen:    []todo.DoneCheck{{FileExists: "parser.go"}, {Command: "go test ./..."}},
+	})
+	require.NoError(t, err)
+
+	result, err := tool.handler.updateTodos(t.Context(), UpdateTodosArgs{
+		Updates: []TodoUpdate{{ID: "todo_1", Status: "completed"}},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, result.Output, "todo_1 -> in-progress (checks failed)")
+	assert.Contains(t, result.Output, "FAIL: TestParse")
+
+	todos := result.Meta.([]todo.Todo)
+	assert.Equal(t, "in-progress", todos[0].Status)
+	require.NotNil(t, todos[0].Verification)
+	assert.True(t, todos[0].Verification.Failed())
+	assert.Equal(t, "1/2 checks failed", todos[0].Verification.Summary())
+
+	failing = "none"
+	result, err = tool.handler.updateTodos(t.Context(), UpdateTodosArgs{
+		Updates: []TodoUpdate{{ID: "todo_1", Status: "completed"}},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, result.Output, "todo_1 -> completed")
+
+	todos = result.Meta.([]todo.Todo)
+	assert.Equal(t, "completed", todos[0].Status)
+	assert.True(t, todos[0].Verification.Passed())
+}
+
+func TestTodoTool_CompletingWithSkippedChecks(t *testing.T) {
+	tool := NewTodoTool()
+	tool.SetVerifier(func(context.Context, todo.DoneCheck) todo.CheckResult {
+		return todo.CheckResult{Status: todo.CheckSkipped, Output: "Running the command needs the approval of the user."}
+	})
+
+	_, err := tool.handler.createTodo(t.Context(), CreateTodoArgs{
+		Description: "Fix the parser",
+		DoneWhen:    []todo.DoneCheck{{Command: "go test ./..."}},
+	})
+	require.NoError(t, err)
+
+	result, err := tool.handler.updateTodos(t.Context(), UpdateTodosArgs{
+		Updates: []TodoUpdate{{ID: "todo_1", Status: "completed"}},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, result.Output, "verify them yourself")
+
+	todos := result.Meta.([]todo.Todo)
+	assert.Equal(t, "completed", todos[0].Status)
+	assert.False(t, todos[0].Verification.Passed())
+	assert.False(t, todos[0].Verification.Failed())
+}
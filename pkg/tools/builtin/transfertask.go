@@ -8,6 +8,15 @@ import (
 
 const ToolNameTransferTask = "transfer_task"
 
+// Priorities of the transferred tasks. When a provider limits the concurrent
+// requests, the requests of critical tasks are sent first, and the ones of
+// background tasks last.
+const (
+	TaskPriorityCritical   = "critical"
+	TaskPriorityNormal     = "normal"
+	TaskPriorityBackground = "background"
+)
+
 type TransferTaskTool struct {
 	tools.BaseToolSet
 }
@@ -19,6 +28,7 @@ type TransferTaskArgs struct {
 	Agent          string `json:"agent" jsonschema:"The name of the agent to transfer the task to."`
 	Task           string `json:"task" jsonschema:"A clear and concise description of the task the member should achieve."`
 	ExpectedOutput string `json:"expected_output" jsonschema:"The expected output from the member (optional)."`
+	Priority       string `json:"priority,omitempty" jsonschema:"The priority of the task: critical for work blocking the answer, background for research that can wait, normal otherwise (optional, defaults to the priority of the current task)."`
 }
 
 func NewTransferTaskTool() *TransferTaskTool {
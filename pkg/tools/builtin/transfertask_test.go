@@ -46,6 +46,10 @@ func TestTaskTool_Tools(t *testing.T) {
 			"description": "The expected output from the member (optional).",
 			"type": "string"
 		},
+		"priority": {
+			"description": "The priority of the task: critical for work blocking the answer, background for research that can wait, normal otherwise (optional, defaults to the priority of the current task).",
+			"type": "string"
+		},
 		"task": {
 			"description": "A clear and concise description of the task the member should achieve.",
 			"type": "string"
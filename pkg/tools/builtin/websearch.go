@@ -0,0 +1,97 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/cagent/pkg/tools"
+	"github.com/docker/cagent/pkg/websearch"
+)
+
+const (
+	ToolNameWebSearch = "web_search"
+
+	defaultSearchResults = 5
+	maxSearchResults     = 20
+)
+
+// WebSearchTool searches the web with a search engine.
+type WebSearchTool struct {
+	tools.BaseToolSet
+	engine websearch.Engine
+}
+
+var _ tools.ToolSet = (*WebSearchTool)(nil)
+
+type WebSearchArgs struct {
+	Query string `json:"query" jsonschema:"The search query"`
+	Count int    `json:"count,omitempty" jsonschema:"The number of results to return (default: 5, max: 20)"`
+}
+
+// WebSearchMeta is the results of a search.
+type WebSearchMeta struct {
+	Results []websearch.Result `json:"results"`
+}
+
+func NewWebSearchTool(engine websearch.Engine) *WebSearchTool {
+	return &WebSearchTool{engine: engine}
+}
+
+func (t *WebSearchTool) Instructions() string {
+	return `## "web_search" tool instructions
+
+Use web_search to find pages on the web, then fetch the most relevant results to read them.
+The results only have a short snippet of each page: don't rely on snippets for details.`
+}
+
+func (t *WebSearchTool) handleWebSearch(ctx context.Context, args WebSearchArgs) (*tools.ToolCallResult, error) {
+	query := strings.TrimSpace(args.Query)
+	if query == "" {
+		return tools.ResultError("The query is empty"), nil
+	}
+	count := args.Count
+	if count <= 0 {
+		count = defaultSearchResults
+	}
+	count = min(count, maxSearchResults)
+
+	results, err := t.engine.Search(ctx, query, count)
+	if err != nil {
+		return tools.ResultError(fmt.Sprintf("Error searching the web: %s", err)), nil
+	}
+	if len(results) == 0 {
+		return tools.ResultSuccess(fmt.Sprintf("No results for %q", query)), nil
+	}
+
+	var output strings.Builder
+	fmt.Fprintf(&output, "Results for %q:\n", query)
+	for i, r := range results {
+		fmt.Fprintf(&output, "\n%d. [%s](%s)\n", i+1, r.Title, r.URL)
+		if snippet := strings.TrimSpace(r.Snippet); snippet != "" {
+			fmt.Fprintf(&output, "   %s\n", strings.Join(strings.Fields(snippet), " "))
+		}
+	}
+
+	return &tools.ToolCallResult{
+		Output: output.String(),
+		Meta:   WebSearchMeta{Results: results},
+	}, nil
+}
+
+func (t *WebSearchTool) Tools(context.Context) ([]tools.Tool, error) {
+	return []tools.Tool{
+		{
+			Name:         ToolNameWebSearch,
+			Category:     "web_search",
+			Description:  "Search the web. Returns the title, URL and a snippet of the pages found.",
+			Parameters:   tools.MustSchemaFor[WebSearchArgs](),
+			OutputSchema: tools.MustSchemaFor[string](),
+			Handler:      tools.NewHandler(t.handleWebSearch),
+			Annotations: tools.ToolAnnotations{
+				ReadOnlyHint: true,
+				Title:        "Web Search",
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,68 @@
+package builtin
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/websearch"
+)
+
+type fakeSearchEngine struct {
+	results []websearch.Result
+	err     error
+	count   int
+}
+
+func (e *fakeSearchEngine) Search(_ context.Context, _ string, count int) ([]websearch.Result, error) {
+	e.count = count
+	return e.results, e.err
+}
+
+func TestWebSearchTool(t *testing.T) {
+	t.Parallel()
+
+	engine := &fakeSearchEngine{results: []websearch.Result{
+		{Title: "Docker", URL: "https://docker.com", Snippet: "Build,\n  share, run"},
+		{Title: "Docs", URL: "https://docs.docker.com"},
+	}}
+	tool := NewWebSearchTool(engine)
+
+	result, err := tool.handleWebSearch(t.Context(), WebSearchArgs{Query: "docker"})
+	require.NoError(t, err)
+	assert.Equal(t, 5, engine.count)
+	assert.Equal(t, `Results for "docker":
+
+1. [Docker](https://docker.com)
+   Build, share, run
+
+2. [Docs](https://docs.docker.com)
+`, result.Output)
+	assert.Equal(t, WebSearchMeta{Results: engine.results}, result.Meta)
+
+	_, err = tool.handleWebSearch(t.Context(), WebSearchArgs{Query: "docker", Count: 100})
+	require.NoError(t, err)
+	assert.Equal(t, 20, engine.count)
+}
+
+func TestWebSearchTool_Errors(t *testing.T) {
+	t.Parallel()
+
+	tool := NewWebSearchTool(&fakeSearchEngine{})
+	result, err := tool.handleWebSearch(t.Context(), WebSearchArgs{Query: "  "})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+
+	result, err = tool.handleWebSearch(t.Context(), WebSearchArgs{Query: "nothing"})
+	require.NoError(t, err)
+	assert.Equal(t, `No results for "nothing"`, result.Output)
+
+	tool = NewWebSearchTool(&fakeSearchEngine{err: errors.New("quota exceeded")})
+	result, err = tool.handleWebSearch(t.Context(), WebSearchArgs{Query: "docker"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Output, "quota exceeded")
+}
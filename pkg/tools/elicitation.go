@@ -0,0 +1,113 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+)
+
+// ElicitationField is a field of the form an MCP server asks the user to
+// fill. Elicitation schemas are flat objects with primitive properties only.
+type ElicitationField struct {
+	Name        string
+	Title       string
+	Description string
+	// Type is string, number, integer or boolean
+	Type     string
+	Required bool
+	// Enum lists the values of the field, when they're restricted
+	Enum    []string
+	Default any
+}
+
+// Label is the title of the field, or its name.
+func (f ElicitationField) Label() string {
+	if f.Title != "" {
+		return f.Title
+	}
+	return f.Name
+}
+
+// Parse converts the text typed by the user to a value of the field's type.
+func (f ElicitationField) Parse(text string) (any, error) {
+	text = strings.TrimSpace(text)
+	if len(f.Enum) > 0 && !slices.Contains(f.Enum, text) {
+		return nil, fmt.Errorf("%s must be one of %s", f.Label(), strings.Join(f.Enum, ", "))
+	}
+
+	switch f.Type {
+	case "integer":
+		v, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be an integer", f.Label())
+		}
+		return v, nil
+	case "number":
+		v, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a number", f.Label())
+		}
+		return v, nil
+	case "boolean":
+		switch strings.ToLower(text) {
+		case "true", "yes", "y":
+			return true, nil
+		case "false", "no", "n":
+			return false, nil
+		}
+		return nil, fmt.Errorf("%s must be yes or no", f.Label())
+	default:
+		return text, nil
+	}
+}
+
+// ElicitationFields returns the fields of the form described by the schema
+// of an elicitation request, in the order of the schema.
+func ElicitationFields(schema any) ([]ElicitationField, error) {
+	if schema == nil {
+		return nil, nil
+	}
+
+	buf, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	var form struct {
+		Properties *orderedmap.OrderedMap[string, struct {
+			Type        string `json:"type"`
+			Title       string `json:"title"`
+			Description string `json:"description"`
+			Enum        []any  `json:"enum"`
+			Default     any    `json:"default"`
+		}] `json:"properties"`
+		Required []string `json:"required"`
+	}
+	if err := json.Unmarshal(buf, &form); err != nil {
+		return nil, fmt.Errorf("invalid elicitation schema: %w", err)
+	}
+	if form.Properties == nil {
+		return nil, nil
+	}
+
+	var fields []ElicitationField
+	for pair := form.Properties.Oldest(); pair != nil; pair = pair.Next() {
+		property := pair.Value
+		field := ElicitationField{
+			Name:        pair.Key,
+			Title:       property.Title,
+			Description: property.Description,
+			Type:        property.Type,
+			Required:    slices.Contains(form.Required, pair.Key),
+			Default:     property.Default,
+		}
+		for _, value := range property.Enum {
+			field.Enum = append(field.Enum, fmt.Sprint(value))
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
@@ -0,0 +1,69 @@
+package tools
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElicitationFields(t *testing.T) {
+	t.Parallel()
+
+	fields, err := ElicitationFields(map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"name":  map[string]any{"type": "string", "title": "Name"},
+			"count": map[string]any{"type": "integer", "default": 3},
+			"env":   map[string]any{"type": "string", "enum": []any{"dev", "prod"}},
+		},
+		"required": []string{"name"},
+	})
+	require.NoError(t, err)
+	require.Len(t, fields, 3)
+
+	// Maps are marshalled with sorted keys
+	assert.Equal(t, ElicitationField{Name: "count", Type: "integer", Default: float64(3)}, fields[0])
+	assert.Equal(t, ElicitationField{Name: "env", Type: "string", Enum: []string{"dev", "prod"}}, fields[1])
+	assert.Equal(t, ElicitationField{Name: "name", Title: "Name", Type: "string", Required: true}, fields[2])
+}
+
+func TestElicitationFields_KeepsSchemaOrder(t *testing.T) {
+	t.Parallel()
+
+	fields, err := ElicitationFields(json.RawMessage(`{"type":"object","properties":{"zone":{"type":"string"},"area":{"type":"string"}}}`))
+	require.NoError(t, err)
+	require.Len(t, fields, 2)
+	assert.Equal(t, "zone", fields[0].Name)
+	assert.Equal(t, "area", fields[1].Name)
+
+	fields, err = ElicitationFields(nil)
+	require.NoError(t, err)
+	assert.Empty(t, fields)
+}
+
+func TestElicitationField_Parse(t *testing.T) {
+	t.Parallel()
+
+	count := ElicitationField{Name: "count", Type: "integer"}
+	v, err := count.Parse(" 42 ")
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), v)
+	_, err = count.Parse("many")
+	require.EqualError(t, err, "count must be an integer")
+
+	confirm := ElicitationField{Name: "confirm", Title: "Confirm", Type: "boolean"}
+	v, err = confirm.Parse("Yes")
+	require.NoError(t, err)
+	assert.Equal(t, true, v)
+	_, err = confirm.Parse("maybe")
+	require.EqualError(t, err, "Confirm must be yes or no")
+
+	env := ElicitationField{Name: "env", Type: "string", Enum: []string{"dev", "prod"}}
+	v, err = env.Parse("prod")
+	require.NoError(t, err)
+	assert.Equal(t, "prod", v)
+	_, err = env.Parse("staging")
+	require.EqualError(t, err, "env must be one of dev, prod")
+}
@@ -0,0 +1,35 @@
+package mcp
+
+import (
+	"cmp"
+	"context"
+
+	"github.com/docker/cagent/pkg/tools"
+)
+
+// WithContainer tells that the server runs in an ephemeral container of the
+// image, so that the client is told when the container starts and stops.
+// The server is known by its image rather than by the docker command.
+func WithContainer(image string) ToolsetOption {
+	return func(ts *Toolset) {
+		ts.container = image
+		ts.logID = image
+	}
+}
+
+// startServer starts the server, and tells whether its container, if any,
+// is running.
+func (ts *Toolset) startServer(ctx context.Context) error {
+	if ts.container == "" {
+		return ts.doStart(ctx)
+	}
+
+	server := cmp.Or(ts.name, ts.logID)
+	ts.notify(tools.ServerStatus{Server: server, State: tools.ServerStarting})
+	if err := ts.doStart(ctx); err != nil {
+		ts.notify(tools.ServerStatus{Server: server, State: tools.ServerStopped, Error: err.Error()})
+		return err
+	}
+	ts.notify(tools.ServerStatus{Server: server, State: tools.ServerRunning})
+	return nil
+}
@@ -0,0 +1,39 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/tools"
+)
+
+func TestContainer_Lifecycle(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeServerClient{}
+	ts := &Toolset{
+		name:      "docs",
+		mcpClient: client,
+		checkNow:  make(chan struct{}, 1),
+	}
+	WithContainer("node:20-alpine")(ts)
+	statuses := make(chan tools.ServerStatus, 16)
+	ts.SetStatusHandler(func(status tools.ServerStatus) { statuses <- status })
+
+	require.NoError(t, ts.Start(t.Context()))
+	assert.Equal(t, tools.ServerStatus{Server: "docs", State: tools.ServerStarting, Container: "node:20-alpine"}, nextStatus(t, statuses))
+	assert.Equal(t, tools.ServerStatus{Server: "docs", State: tools.ServerRunning, Container: "node:20-alpine"}, nextStatus(t, statuses))
+
+	require.NoError(t, ts.Stop(t.Context()))
+	assert.Equal(t, tools.ServerStatus{Server: "docs", State: tools.ServerStopped, Container: "node:20-alpine"}, nextStatus(t, statuses))
+}
+
+func TestContainer_NotNotifiedWithoutContainer(t *testing.T) {
+	t.Parallel()
+
+	_, statuses := startSupervised(t, &fakeServerClient{})
+
+	assert.Empty(t, statuses)
+}
@@ -0,0 +1,49 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/tools"
+)
+
+type elicitationClient struct {
+	fakeServerClient
+	handler tools.ElicitationHandler
+}
+
+func (c *elicitationClient) SetElicitationHandler(handler tools.ElicitationHandler) {
+	c.handler = handler
+}
+
+func TestToolset_ElicitationHandler(t *testing.T) {
+	t.Parallel()
+
+	client := &elicitationClient{}
+	ts := &Toolset{name: "deploy", mcpClient: client, logID: "deploy-server"}
+
+	var received *mcp.ElicitParams
+	ts.SetElicitationHandler(func(_ context.Context, req *mcp.ElicitParams) (tools.ElicitationResult, error) {
+		received = req
+		return tools.ElicitationResult{Action: tools.ElicitationActionAccept}, nil
+	})
+
+	request := &mcp.ElicitParams{Message: "Which environment?", Meta: mcp.Meta{"progress": 1}}
+	_, err := client.handler(t.Context(), request)
+	require.NoError(t, err)
+	assert.Equal(t, "Which environment?", received.Message)
+	assert.Equal(t, "elicitation", received.Meta["cagent/type"])
+	assert.Equal(t, "deploy", received.Meta["cagent/server"])
+	assert.Equal(t, 1, received.Meta["progress"])
+	assert.NotContains(t, request.Meta, "cagent/type", "the request of the server is left as is")
+
+	// The requests of the OAuth flow are already described
+	oauth := &mcp.ElicitParams{Meta: mcp.Meta{"cagent/type": "oauth_flow"}}
+	_, err = client.handler(t.Context(), oauth)
+	require.NoError(t, err)
+	assert.Same(t, oauth, received)
+}
@@ -0,0 +1,196 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/docker/cagent/pkg/concurrent"
+)
+
+// keychainService is the service the OAuth tokens of the remote MCP servers
+// are stored under in the keychain of the OS.
+const keychainService = "cagent-mcp-oauth"
+
+// keychain stores secrets in the keychain of the OS, by account.
+type keychain interface {
+	get(ctx context.Context, account string) (string, error)
+	set(ctx context.Context, account, secret string) error
+	remove(ctx context.Context, account string) error
+}
+
+// KeychainTokenStore implements OAuthTokenStore in the keychain of the OS, so
+// that the authorizations survive restarts. Tokens are cached in memory, and
+// still work for the current run if the keychain can't store them.
+type KeychainTokenStore struct {
+	keychain keychain
+	// tokens caches the tokens read from the keychain, nil for the resources
+	// without a token
+	tokens *concurrent.Map[string, *OAuthToken]
+}
+
+// NewKeychainTokenStore creates a token store in the keychain of the OS: the
+// macOS keychain, with the security command, or the Secret Service on Linux,
+// with the secret-tool command.
+func NewKeychainTokenStore() (OAuthTokenStore, error) {
+	var kc keychain
+	switch runtime.GOOS {
+	case "darwin":
+		if _, err := exec.LookPath("security"); err != nil {
+			return nil, fmt.Errorf("macOS keychain not available: %w", err)
+		}
+		kc = macOSKeychain{}
+	case "linux":
+		if _, err := exec.LookPath("secret-tool"); err != nil {
+			return nil, fmt.Errorf("secret service not available: %w", err)
+		}
+		kc = secretServiceKeychain{}
+	default:
+		return nil, fmt.Errorf("no keychain support on %s", runtime.GOOS)
+	}
+
+	return newKeychainTokenStore(kc), nil
+}
+
+func newKeychainTokenStore(kc keychain) *KeychainTokenStore {
+	return &KeychainTokenStore{
+		keychain: kc,
+		tokens:   concurrent.NewMap[string, *OAuthToken](),
+	}
+}
+
+// defaultTokenStore returns the token store of the OS keychain, or an
+// in-memory store if there is no keychain.
+func defaultTokenStore() OAuthTokenStore {
+	store, err := NewKeychainTokenStore()
+	if err != nil {
+		slog.Debug("OAuth tokens of remote MCP servers won't be persisted", "error", err)
+		return NewInMemoryTokenStore()
+	}
+	return store
+}
+
+func (s *KeychainTokenStore) GetToken(resourceURL string) (*OAuthToken, error) {
+	token, ok := s.tokens.Load(resourceURL)
+	if !ok {
+		token = s.readToken(resourceURL)
+		s.tokens.Store(resourceURL, token)
+	}
+	if token == nil {
+		return nil, fmt.Errorf("no token found for resource: %s", resourceURL)
+	}
+	return token, nil
+}
+
+func (s *KeychainTokenStore) readToken(resourceURL string) *OAuthToken {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	secret, err := s.keychain.get(ctx, resourceURL)
+	if err != nil || secret == "" {
+		return nil
+	}
+	data, err := base64.StdEncoding.DecodeString(secret)
+	if err != nil {
+		slog.Debug("Ignoring invalid OAuth token in the keychain", "url", resourceURL, "error", err)
+		return nil
+	}
+	var token OAuthToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		slog.Debug("Ignoring invalid OAuth token in the keychain", "url", resourceURL, "error", err)
+		return nil
+	}
+	return &token
+}
+
+func (s *KeychainTokenStore) StoreToken(resourceURL string, token *OAuthToken) error {
+	s.tokens.Store(resourceURL, token)
+
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Encoded, the token can be passed to the keychain commands as is
+	if err := s.keychain.set(ctx, resourceURL, base64.StdEncoding.EncodeToString(data)); err != nil {
+		slog.Warn("Failed to store the OAuth token in the keychain, it won't survive a restart", "url", resourceURL, "error", err)
+	}
+	return nil
+}
+
+func (s *KeychainTokenStore) RemoveToken(resourceURL string) error {
+	s.tokens.Store(resourceURL, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := s.keychain.remove(ctx, resourceURL); err != nil {
+		return fmt.Errorf("removing the token from the keychain: %w", err)
+	}
+	return nil
+}
+
+// macOSKeychain stores secrets in the macOS keychain.
+type macOSKeychain struct{}
+
+func (macOSKeychain) get(ctx context.Context, account string) (string, error) {
+	return runKeychainCommand(ctx, "", "security", "find-generic-password", "-s", keychainService, "-a", account, "-w")
+}
+
+func (macOSKeychain) set(ctx context.Context, account, secret string) error {
+	// The commands are read from stdin so the secret doesn't show in the
+	// arguments of the process
+	if strings.ContainsAny(account, "\"\n") {
+		return errors.New("invalid account name")
+	}
+	command := fmt.Sprintf("add-generic-password -U -s %s -a \"%s\" -w %s\n", keychainService, account, secret)
+	_, err := runKeychainCommand(ctx, command, "security", "-i")
+	return err
+}
+
+func (macOSKeychain) remove(ctx context.Context, account string) error {
+	_, err := runKeychainCommand(ctx, "", "security", "delete-generic-password", "-s", keychainService, "-a", account)
+	return err
+}
+
+// secretServiceKeychain stores secrets with the Secret Service of Linux
+// desktops (GNOME Keyring, KWallet...).
+type secretServiceKeychain struct{}
+
+func (secretServiceKeychain) get(ctx context.Context, account string) (string, error) {
+	return runKeychainCommand(ctx, "", "secret-tool", "lookup", "service", keychainService, "account", account)
+}
+
+func (secretServiceKeychain) set(ctx context.Context, account, secret string) error {
+	_, err := runKeychainCommand(ctx, secret, "secret-tool", "store", "--label", "cagent MCP OAuth token for "+account, "service", keychainService, "account", account)
+	return err
+}
+
+func (secretServiceKeychain) remove(ctx context.Context, account string) error {
+	_, err := runKeychainCommand(ctx, "", "secret-tool", "clear", "service", keychainService, "account", account)
+	return err
+}
+
+func runKeychainCommand(ctx context.Context, stdin, name string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s: %w: %s", name, err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
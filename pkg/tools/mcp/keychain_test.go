@@ -0,0 +1,104 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeKeychain struct {
+	mu      sync.Mutex
+	secrets map[string]string
+	gets    int
+	failSet bool
+}
+
+func (k *fakeKeychain) get(_ context.Context, account string) (string, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.gets++
+	secret, ok := k.secrets[account]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return secret, nil
+}
+
+func (k *fakeKeychain) set(_ context.Context, account, secret string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.failSet {
+		return errors.New("keychain locked")
+	}
+	k.secrets[account] = secret
+	return nil
+}
+
+func (k *fakeKeychain) remove(_ context.Context, account string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	delete(k.secrets, account)
+	return nil
+}
+
+func TestKeychainTokenStore(t *testing.T) {
+	t.Parallel()
+
+	kc := &fakeKeychain{secrets: map[string]string{}}
+	token := &OAuthToken{
+		AccessToken:   "access",
+		RefreshToken:  "refresh",
+		ExpiresAt:     time.Now().Add(time.Hour).Truncate(time.Second),
+		ClientID:      "client",
+		TokenEndpoint: "https://auth.example.com/token",
+	}
+
+	store := newKeychainTokenStore(kc)
+	require.NoError(t, store.StoreToken("https://mcp.example.com", token))
+
+	// A new store, as after a restart, reads the token from the keychain
+	restarted := newKeychainTokenStore(kc)
+	got, err := restarted.GetToken("https://mcp.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "access", got.AccessToken)
+	assert.Equal(t, "refresh", got.RefreshToken)
+	assert.Equal(t, "client", got.ClientID)
+	assert.True(t, token.ExpiresAt.Equal(got.ExpiresAt))
+
+	require.NoError(t, restarted.RemoveToken("https://mcp.example.com"))
+	_, err = restarted.GetToken("https://mcp.example.com")
+	require.Error(t, err)
+	assert.Empty(t, kc.secrets)
+}
+
+func TestKeychainTokenStore_CachesMissingTokens(t *testing.T) {
+	t.Parallel()
+
+	kc := &fakeKeychain{secrets: map[string]string{"https://invalid.example.com": "not base64!"}}
+	store := newKeychainTokenStore(kc)
+
+	for range 3 {
+		_, err := store.GetToken("https://mcp.example.com")
+		require.Error(t, err)
+		_, err = store.GetToken("https://invalid.example.com")
+		require.Error(t, err)
+	}
+	assert.Equal(t, 2, kc.gets)
+}
+
+func TestKeychainTokenStore_KeychainFailure(t *testing.T) {
+	t.Parallel()
+
+	store := newKeychainTokenStore(&fakeKeychain{secrets: map[string]string{}, failSet: true})
+
+	// The token is still used for the current run
+	require.NoError(t, store.StoreToken("https://mcp.example.com", &OAuthToken{AccessToken: "access"}))
+	got, err := store.GetToken("https://mcp.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "access", got.AccessToken)
+}
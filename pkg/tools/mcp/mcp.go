@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"iter"
 	"log/slog"
+	"maps"
 	"strings"
 	"sync"
 	"time"
@@ -23,9 +24,11 @@ type mcpClient interface {
 	CallTool(ctx context.Context, request *mcp.CallToolParams) (*mcp.CallToolResult, error)
 	ListPrompts(ctx context.Context, request *mcp.ListPromptsParams) iter.Seq2[*mcp.Prompt, error]
 	GetPrompt(ctx context.Context, request *mcp.GetPromptParams) (*mcp.GetPromptResult, error)
+	ReadResource(ctx context.Context, request *mcp.ReadResourceParams) (*mcp.ReadResourceResult, error)
 	SetElicitationHandler(handler tools.ElicitationHandler)
 	SetOAuthSuccessHandler(handler func())
 	SetManagedOAuth(managed bool)
+	SetSamplingHandler(handler samplingHandler)
 	Close(ctx context.Context) error
 }
 
@@ -36,39 +39,64 @@ type baseMCPClient struct{}
 func (baseMCPClient) SetElicitationHandler(tools.ElicitationHandler) {}
 func (baseMCPClient) SetOAuthSuccessHandler(func())                  {}
 func (baseMCPClient) SetManagedOAuth(bool)                           {}
+func (baseMCPClient) SetSamplingHandler(samplingHandler)             {}
 
 // Toolset represents a set of MCP tools
 type Toolset struct {
-	name         string
-	mcpClient    mcpClient
-	logID        string
-	instructions string
-	mu           sync.Mutex
-	started      bool
+	name          string
+	mcpClient     mcpClient
+	logID         string
+	instructions  string
+	mu            sync.Mutex
+	started       bool
+	statusMu      sync.Mutex
+	statusHandler tools.StatusHandler
+	// container is the image of the container the server runs in, if any
+	container string
+	// resources are read when the server starts, and given to the agent
+	// with the instructions of the server
+	resources []string
+
+	// The servers running as child processes are supervised once started,
+	// until stop is closed
+	stop                chan struct{}
+	checkNow            chan struct{}
+	healthCheckInterval time.Duration
+	restartBackoff      time.Duration
 }
 
 var _ tools.ToolSet = (*Toolset)(nil)
 
 // NewToolsetCommand creates a new MCP toolset from a command.
-func NewToolsetCommand(name, command string, args, env []string, cwd string) *Toolset {
+func NewToolsetCommand(name, command string, args, env []string, cwd string, opts ...ToolsetOption) *Toolset {
 	slog.Debug("Creating Stdio MCP toolset", "command", command, "args", args)
 
-	return &Toolset{
+	ts := &Toolset{
 		name:      name,
 		mcpClient: newStdioCmdClient(command, args, env, cwd),
 		logID:     command,
+		checkNow:  make(chan struct{}, 1),
 	}
+	for _, opt := range opts {
+		opt(ts)
+	}
+	return ts
 }
 
 // NewRemoteToolset creates a new MCP toolset from a remote MCP Server.
-func NewRemoteToolset(name, url, transport string, headers map[string]string) *Toolset {
+func NewRemoteToolset(name, url, transport string, headers map[string]string, opts ...ToolsetOption) *Toolset {
 	slog.Debug("Creating Remote MCP toolset", "url", url, "transport", transport, "headers", headers)
 
-	return &Toolset{
+	ts := &Toolset{
 		name:      name,
-		mcpClient: newRemoteClient(url, transport, headers, NewInMemoryTokenStore()),
+		mcpClient: newRemoteClient(url, transport, headers, defaultTokenStore()),
 		logID:     url,
+		checkNow:  make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(ts)
 	}
+	return ts
 }
 
 func (ts *Toolset) Start(ctx context.Context) error {
@@ -79,11 +107,17 @@ func (ts *Toolset) Start(ctx context.Context) error {
 		return nil
 	}
 
-	err := ts.doStart(ctx)
-	if err == nil {
-		ts.started = true
+	err := ts.startServer(ctx)
+	if err != nil {
+		return err
 	}
-	return err
+	ts.started = true
+
+	if client, ok := ts.mcpClient.(supervisedClient); ok {
+		ts.stop = make(chan struct{})
+		go ts.supervise(context.WithoutCancel(ctx), client, ts.stop)
+	}
+	return nil
 }
 
 func (ts *Toolset) doStart(ctx context.Context) error {
@@ -136,18 +170,22 @@ func (ts *Toolset) doStart(ctx context.Context) error {
 
 	slog.Debug("Started MCP toolset successfully", "server", ts.logID)
 	ts.instructions = result.Instructions
+	if resources := ts.readResources(ctx); resources != "" {
+		ts.instructions = strings.TrimSpace(ts.instructions + "\n\n" + resources)
+	}
 	return nil
 }
 
 func (ts *Toolset) Instructions() string {
 	ts.mu.Lock()
 	started := ts.started
+	instructions := ts.instructions
 	ts.mu.Unlock()
 	if !started {
 		// TODO: this should never happen...
 		return ""
 	}
-	return ts.instructions
+	return instructions
 }
 
 func (ts *Toolset) Tools(ctx context.Context) ([]tools.Tool, error) {
@@ -213,6 +251,7 @@ func (ts *Toolset) callTool(ctx context.Context, toolCall tools.ToolCall) (*tool
 			return nil, err
 		}
 		slog.Error("Failed to call MCP tool", "tool", toolCall.Function.Name, "error", err)
+		ts.checkHealth()
 		return nil, fmt.Errorf("failed to call tool: %w", err)
 	}
 
@@ -225,6 +264,13 @@ func (ts *Toolset) callTool(ctx context.Context, toolCall tools.ToolCall) (*tool
 func (ts *Toolset) Stop(ctx context.Context) error {
 	slog.Debug("Stopping MCP toolset", "server", ts.logID)
 
+	ts.mu.Lock()
+	if ts.stop != nil {
+		close(ts.stop)
+		ts.stop = nil
+	}
+	ts.mu.Unlock()
+
 	if err := ts.mcpClient.Close(context.WithoutCancel(ctx)); err != nil {
 		if ctx.Err() != nil {
 			return nil
@@ -234,6 +280,9 @@ func (ts *Toolset) Stop(ctx context.Context) error {
 	}
 
 	slog.Debug("Stopped MCP toolset successfully", "server", ts.logID)
+	if ts.container != "" {
+		ts.notify(tools.ServerStatus{Server: cmp.Or(ts.name, ts.logID), State: tools.ServerStopped})
+	}
 	return nil
 }
 
@@ -253,23 +302,54 @@ func isInitNotificationSendError(err error) bool {
 
 func processMCPContent(toolResult *mcp.CallToolResult) *tools.ToolCallResult {
 	finalContent := ""
+	var images []tools.Image
 	for _, resultContent := range toolResult.Content {
-		if textContent, ok := resultContent.(*mcp.TextContent); ok {
-			finalContent += textContent.Text
+		switch content := resultContent.(type) {
+		case *mcp.TextContent:
+			finalContent += content.Text
+		case *mcp.ImageContent:
+			images = append(images, tools.Image{MimeType: content.MIMEType, Data: content.Data})
 		}
 	}
 
 	// Handle an empty response. This can happen if the MCP tool does not return any content.
 	finalContent = cmp.Or(finalContent, "no output")
 
+	result := tools.ResultSuccess(finalContent)
 	if toolResult.IsError {
-		return tools.ResultError(finalContent)
+		result = tools.ResultError(finalContent)
 	}
-	return tools.ResultSuccess(finalContent)
+	result.Images = images
+	return result
 }
 
+// SetElicitationHandler sets the handler of the user input requested by the
+// server. The requests of the server, unlike the ones of the OAuth flow, are
+// forms: their meta tells the runtime's client which server asks.
 func (ts *Toolset) SetElicitationHandler(handler tools.ElicitationHandler) {
-	ts.mcpClient.SetElicitationHandler(handler)
+	if handler == nil {
+		ts.mcpClient.SetElicitationHandler(nil)
+		return
+	}
+
+	server := cmp.Or(ts.name, ts.logID)
+	ts.mcpClient.SetElicitationHandler(func(ctx context.Context, req *mcp.ElicitParams) (tools.ElicitationResult, error) {
+		if _, ok := req.Meta["cagent/type"]; ok {
+			return handler(ctx, req)
+		}
+
+		params := *req
+		params.Meta = maps.Clone(req.Meta)
+		if params.Meta == nil {
+			params.Meta = mcp.Meta{}
+		}
+		params.Meta["cagent/type"] = "elicitation"
+		params.Meta["cagent/server"] = server
+		if req.URL != "" {
+			params.Meta["cagent/url"] = req.URL
+		}
+		return handler(ctx, &params)
+	})
 }
 
 func (ts *Toolset) SetOAuthSuccessHandler(handler func()) {
@@ -0,0 +1,59 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessMCPContentKeepsImages(t *testing.T) {
+	t.Parallel()
+
+	result := processMCPContent(&mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: "screenshot taken"},
+			&mcp.ImageContent{MIMEType: "image/png", Data: []byte("png")},
+		},
+	})
+
+	assert.Equal(t, "screenshot taken", result.Output)
+	assert.False(t, result.IsError)
+	require.Len(t, result.Images, 1)
+	assert.Equal(t, "image/png", result.Images[0].MimeType)
+	assert.Equal(t, []byte("png"), result.Images[0].Data)
+}
+
+func TestProcessMCPContentImageOnly(t *testing.T) {
+	t.Parallel()
+
+	result := processMCPContent(&mcp.CallToolResult{
+		IsError: true,
+		Content: []mcp.Content{&mcp.ImageContent{MIMEType: "image/jpeg", Data: []byte("jpg")}},
+	})
+
+	assert.Equal(t, "no output", result.Output)
+	assert.True(t, result.IsError)
+	assert.Len(t, result.Images, 1)
+}
+
+func FuzzProcessMCPContent(f *testing.F) {
+	f.Add([]byte(`{"content":[{"type":"text","text":"hello"}]}`))
+	f.Add([]byte(`{"content":[{"type":"image","mimeType":"image/png","data":"cG5n"}],"isError":true}`))
+	f.Add([]byte(`{"content":[{"type":"resource","resource":{"uri":"file:///a","text":"a"}}]}`))
+	f.Add([]byte(`{"content":[],"structuredContent":{"a":1}}`))
+	f.Add([]byte(`{"content":null}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Results sent by MCP servers are decoded by the SDK, then turned into tool results
+		var result mcp.CallToolResult
+		if err := json.Unmarshal(data, &result); err != nil {
+			return
+		}
+
+		toolResult := processMCPContent(&result)
+		require.NotEmpty(t, toolResult.Output)
+	})
+}
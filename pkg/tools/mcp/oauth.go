@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
@@ -165,6 +166,8 @@ type oauthTransport struct {
 	tokenStore OAuthTokenStore
 	baseURL    string
 	managed    bool
+	// mu makes concurrent requests wait for the token being refreshed
+	mu sync.Mutex
 }
 
 func (t *oauthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
@@ -180,7 +183,7 @@ func (t *oauthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 
 	reqClone := req.Clone(req.Context())
 
-	if token, err := t.tokenStore.GetToken(t.baseURL); err == nil && !token.IsExpired() {
+	if token := t.validToken(req.Context()); token != nil {
 		reqClone.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
 	}
 
@@ -210,6 +213,38 @@ func (t *oauthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	return resp, nil
 }
 
+// validToken returns the token of the server, refreshed if it expired, or nil
+// if there's no valid token and the server will ask for a new authorization.
+func (t *oauthTransport) validToken(ctx context.Context) *OAuthToken {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	token, err := t.tokenStore.GetToken(t.baseURL)
+	if err != nil {
+		return nil
+	}
+	if !token.IsExpired() {
+		return token
+	}
+	if !token.CanRefresh() {
+		return nil
+	}
+
+	slog.Debug("Refreshing expired OAuth token", "url", t.baseURL)
+	refreshed, err := RefreshAccessToken(ctx, token)
+	if err != nil {
+		slog.Debug("Failed to refresh OAuth token", "url", t.baseURL, "error", err)
+		if err := t.tokenStore.RemoveToken(t.baseURL); err != nil {
+			slog.Debug("Failed to remove expired OAuth token", "url", t.baseURL, "error", err)
+		}
+		return nil
+	}
+	if err := t.tokenStore.StoreToken(t.baseURL, refreshed); err != nil {
+		slog.Warn("Failed to store refreshed OAuth token", "url", t.baseURL, "error", err)
+	}
+	return refreshed
+}
+
 // handleOAuthFlow performs the OAuth flow when a 401 response is received
 func (t *oauthTransport) handleOAuthFlow(ctx context.Context, authServer, wwwAuth string) error {
 	if t.managed {
@@ -347,6 +382,9 @@ func (t *oauthTransport) handleManagedOAuthFlow(ctx context.Context, authServer,
 	if err != nil {
 		return fmt.Errorf("failed to exchange code for token: %w", err)
 	}
+	token.ClientID = clientID
+	token.ClientSecret = clientSecret
+	token.TokenEndpoint = authServerMetadata.TokenEndpoint
 
 	if err := t.tokenStore.StoreToken(t.baseURL, token); err != nil {
 		return fmt.Errorf("failed to store token: %w", err)
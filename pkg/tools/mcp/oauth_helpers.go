@@ -1,6 +1,7 @@
 package mcp
 
 import (
+	"cmp"
 	"context"
 	"crypto/rand"
 	"encoding/base64"
@@ -81,6 +82,55 @@ func ExchangeCodeForToken(ctx context.Context, tokenEndpoint, code, codeVerifier
 	return &token, nil
 }
 
+// RefreshAccessToken requests a new access token with the refresh token of an
+// expired one. The new token keeps the refresh token of the expired one if
+// the authorization server doesn't rotate it.
+func RefreshAccessToken(ctx context.Context, token *OAuthToken) (*OAuthToken, error) {
+	data := url.Values{}
+	data.Set("grant_type", "refresh_token")
+	data.Set("refresh_token", token.RefreshToken)
+	data.Set("client_id", token.ClientID)
+	if token.ClientSecret != "" {
+		data.Set("client_secret", token.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, token.TokenEndpoint, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create refresh request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("token refresh failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var refreshed OAuthToken
+	if err := json.NewDecoder(resp.Body).Decode(&refreshed); err != nil {
+		return nil, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if refreshed.AccessToken == "" {
+		return nil, fmt.Errorf("token response missing access_token")
+	}
+
+	if refreshed.ExpiresIn > 0 {
+		refreshed.ExpiresAt = time.Now().Add(time.Duration(refreshed.ExpiresIn) * time.Second)
+	}
+	refreshed.RefreshToken = cmp.Or(refreshed.RefreshToken, token.RefreshToken)
+	refreshed.ClientID = token.ClientID
+	refreshed.ClientSecret = token.ClientSecret
+	refreshed.TokenEndpoint = token.TokenEndpoint
+
+	return &refreshed, nil
+}
+
 // RequestAuthorizationCode requests the user to open the authorization URL and waits for the callback
 func RequestAuthorizationCode(ctx context.Context, authURL string, callbackServer *CallbackServer, expectedState string) (string, string, error) {
 	if err := browser.Open(ctx, authURL); err != nil {
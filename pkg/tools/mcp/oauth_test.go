@@ -0,0 +1,96 @@
+package mcp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOAuthTransport_RefreshesExpiredToken(t *testing.T) {
+	t.Parallel()
+
+	authServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "refresh_token", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "old-refresh", r.PostForm.Get("refresh_token"))
+		assert.Equal(t, "client", r.PostForm.Get("client_id"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"new-access","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer authServer.Close()
+
+	var authorization string
+	mcpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mcpServer.Close()
+
+	store := NewInMemoryTokenStore()
+	require.NoError(t, store.StoreToken(mcpServer.URL, &OAuthToken{
+		AccessToken:   "old-access",
+		RefreshToken:  "old-refresh",
+		ExpiresAt:     time.Now().Add(-time.Minute),
+		ClientID:      "client",
+		TokenEndpoint: authServer.URL,
+	}))
+
+	client := newRemoteClient(mcpServer.URL, "streamable", nil, store)
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, mcpServer.URL, http.NoBody)
+	require.NoError(t, err)
+	resp, err := client.createHTTPClient().Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, "Bearer new-access", authorization)
+
+	// The refreshed token keeps the refresh token, which wasn't rotated
+	token, err := store.GetToken(mcpServer.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "new-access", token.AccessToken)
+	assert.Equal(t, "old-refresh", token.RefreshToken)
+	assert.Equal(t, authServer.URL, token.TokenEndpoint)
+	assert.False(t, token.IsExpired())
+}
+
+func TestOAuthTransport_RefreshFailureRemovesToken(t *testing.T) {
+	t.Parallel()
+
+	authServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+	}))
+	defer authServer.Close()
+
+	store := NewInMemoryTokenStore()
+	require.NoError(t, store.StoreToken("https://mcp.example.com", &OAuthToken{
+		AccessToken:   "old-access",
+		RefreshToken:  "revoked",
+		ExpiresAt:     time.Now().Add(-time.Minute),
+		ClientID:      "client",
+		TokenEndpoint: authServer.URL,
+	}))
+
+	transport := &oauthTransport{tokenStore: store, baseURL: "https://mcp.example.com"}
+	assert.Nil(t, transport.validToken(t.Context()))
+
+	_, err := store.GetToken("https://mcp.example.com")
+	require.Error(t, err)
+}
+
+func TestOAuthTransport_ExpiredTokenWithoutRefreshToken(t *testing.T) {
+	t.Parallel()
+
+	store := NewInMemoryTokenStore()
+	require.NoError(t, store.StoreToken("https://mcp.example.com", &OAuthToken{
+		AccessToken: "old-access",
+		ExpiresAt:   time.Now().Add(-time.Minute),
+	}))
+
+	transport := &oauthTransport{tokenStore: store, baseURL: "https://mcp.example.com"}
+	assert.Nil(t, transport.validToken(t.Context()))
+}
@@ -20,6 +20,7 @@ type remoteMCPClient struct {
 	headers             map[string]string
 	tokenStore          OAuthTokenStore
 	elicitationHandler  tools.ElicitationHandler
+	samplingHandler     samplingHandler
 	oauthSuccessHandler func()
 	managed             bool
 	mu                  sync.RWMutex
@@ -88,7 +89,8 @@ func (c *remoteMCPClient) Initialize(ctx context.Context, _ *mcp.InitializeReque
 			Endpoint:   c.url,
 			HTTPClient: httpClient,
 		}
-	case "streamable", "streamable-http":
+	// Streamable HTTP is the transport of the current MCP specification
+	case "", "streamable", "streamable-http":
 		transport = &mcp.StreamableClientTransport{
 			Endpoint:             c.url,
 			HTTPClient:           httpClient,
@@ -98,14 +100,15 @@ func (c *remoteMCPClient) Initialize(ctx context.Context, _ *mcp.InitializeReque
 		return nil, fmt.Errorf("unsupported transport type: %s", c.transportType)
 	}
 
-	// Create an MCP client with elicitation support
+	// Create an MCP client with elicitation and sampling support
 	impl := &mcp.Implementation{
 		Name:    "cagent",
 		Version: "1.0.0",
 	}
 
 	opts := &mcp.ClientOptions{
-		ElicitationHandler: c.handleElicitationRequest,
+		ElicitationHandler:   c.handleElicitationRequest,
+		CreateMessageHandler: c.createMessage,
 	}
 
 	client := mcp.NewClient(impl, opts)
@@ -241,6 +244,21 @@ func (c *remoteMCPClient) SetElicitationHandler(handler tools.ElicitationHandler
 	c.mu.Unlock()
 }
 
+// SetSamplingHandler sets the handler of the completions requested by the server.
+func (c *remoteMCPClient) SetSamplingHandler(handler samplingHandler) {
+	c.mu.Lock()
+	c.samplingHandler = handler
+	c.mu.Unlock()
+}
+
+func (c *remoteMCPClient) createMessage(ctx context.Context, req *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	c.mu.RLock()
+	handler := c.samplingHandler
+	c.mu.RUnlock()
+
+	return createMessage(ctx, handler, req)
+}
+
 func (c *remoteMCPClient) SetOAuthSuccessHandler(handler func()) {
 	c.mu.Lock()
 	c.oauthSuccessHandler = handler
@@ -254,3 +272,16 @@ func (c *remoteMCPClient) SetManagedOAuth(managed bool) {
 	c.managed = managed
 	c.mu.Unlock()
 }
+
+// ReadResource reads a resource of the MCP server
+func (c *remoteMCPClient) ReadResource(ctx context.Context, request *mcp.ReadResourceParams) (*mcp.ReadResourceResult, error) {
+	c.mu.RLock()
+	session := c.session
+	c.mu.RUnlock()
+
+	if session == nil {
+		return nil, fmt.Errorf("session not initialized")
+	}
+
+	return session.ReadResource(ctx, request)
+}
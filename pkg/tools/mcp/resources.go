@@ -0,0 +1,73 @@
+package mcp
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxResourceLength caps the size of a resource given to the agent as
+// context.
+const maxResourceLength = 50_000
+
+// ToolsetOption configures an MCP toolset.
+type ToolsetOption func(*Toolset)
+
+// WithResources gives the agent the resources of the server, such as
+// "repo://readme", as context. They're read when the server starts.
+func WithResources(uris []string) ToolsetOption {
+	return func(ts *Toolset) {
+		ts.resources = uris
+	}
+}
+
+// readResources reads the resources given to the agent as context and
+// formats them to be added to the instructions of the server. The resources
+// that can't be read are left out.
+func (ts *Toolset) readResources(ctx context.Context) string {
+	if len(ts.resources) == 0 {
+		return ""
+	}
+
+	var resources strings.Builder
+	for _, uri := range ts.resources {
+		result, err := ts.mcpClient.ReadResource(ctx, &mcp.ReadResourceParams{URI: uri})
+		if err != nil {
+			slog.Warn("Failed to read MCP resource", "server", ts.logID, "uri", uri, "error", err)
+			continue
+		}
+
+		for _, content := range result.Contents {
+			if content.Text == "" {
+				slog.Debug("Skipping MCP resource without text", "server", ts.logID, "uri", content.URI, "mime_type", content.MIMEType)
+				continue
+			}
+
+			text := content.Text
+			if len(text) > maxResourceLength {
+				text = truncateResource(text) + "\n[resource truncated]"
+			}
+			fmt.Fprintf(&resources, "<resource uri=%q>\n%s\n</resource>\n", cmp.Or(content.URI, uri), text)
+		}
+	}
+	if resources.Len() == 0 {
+		return ""
+	}
+
+	return "The following resources of the MCP server are given as context:\n" + strings.TrimSuffix(resources.String(), "\n")
+}
+
+// truncateResource truncates a resource to maxResourceLength bytes without
+// splitting a UTF-8 character.
+func truncateResource(s string) string {
+	n := maxResourceLength
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,58 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type resourcesClient struct {
+	fakeServerClient
+	resources map[string]string
+}
+
+func (c *resourcesClient) Initialize(context.Context, *mcp.InitializeRequest) (*mcp.InitializeResult, error) {
+	return &mcp.InitializeResult{Instructions: "Use the repo tools."}, nil
+}
+
+func (c *resourcesClient) ReadResource(_ context.Context, request *mcp.ReadResourceParams) (*mcp.ReadResourceResult, error) {
+	text, ok := c.resources[request.URI]
+	if !ok {
+		return nil, errors.New("resource not found")
+	}
+	return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{URI: request.URI, MIMEType: "text/markdown", Text: text}}}, nil
+}
+
+func TestToolset_ResourcesAsContext(t *testing.T) {
+	t.Parallel()
+
+	client := &resourcesClient{resources: map[string]string{
+		"repo://readme":  "# cagent",
+		"repo://license": strings.Repeat("x", maxResourceLength+10),
+	}}
+	ts := &Toolset{mcpClient: client, logID: "repo"}
+	WithResources([]string{"repo://readme", "repo://missing", "repo://license"})(ts)
+
+	require.NoError(t, ts.Start(t.Context()))
+	t.Cleanup(func() { _ = ts.Stop(context.Background()) })
+
+	instructions := ts.Instructions()
+	assert.True(t, strings.HasPrefix(instructions, "Use the repo tools.\n\n"))
+	assert.Contains(t, instructions, "<resource uri=\"repo://readme\">\n# cagent\n</resource>")
+	assert.NotContains(t, instructions, "repo://missing")
+	assert.Contains(t, instructions, "[resource truncated]\n</resource>")
+}
+
+func TestToolset_WithoutResources(t *testing.T) {
+	t.Parallel()
+
+	ts := &Toolset{mcpClient: &resourcesClient{}, logID: "repo"}
+	require.NoError(t, ts.Start(t.Context()))
+	t.Cleanup(func() { _ = ts.Stop(context.Background()) })
+	assert.Equal(t, "Use the repo tools.", ts.Instructions())
+}
@@ -0,0 +1,38 @@
+package mcp
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/cagent/pkg/tools"
+)
+
+// samplingHandler runs the completions requested by the server of a client.
+type samplingHandler func(ctx context.Context, req *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error)
+
+// SetSamplingHandler lets the server of the toolset request completions from
+// the models of the agent.
+func (ts *Toolset) SetSamplingHandler(handler tools.SamplingHandler) {
+	if handler == nil {
+		ts.mcpClient.SetSamplingHandler(nil)
+		return
+	}
+
+	server := cmp.Or(ts.name, ts.logID)
+	ts.mcpClient.SetSamplingHandler(func(ctx context.Context, req *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+		slog.Debug("Sampling request received from MCP server", "server", ts.logID, "messages", len(req.Params.Messages), "max_tokens", req.Params.MaxTokens)
+		return handler(ctx, server, req.Params)
+	})
+}
+
+// createMessage answers a sampling request of a server with handler.
+func createMessage(ctx context.Context, handler samplingHandler, req *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	if handler == nil {
+		return nil, errors.New("sampling is not available")
+	}
+	return handler(ctx, req)
+}
@@ -0,0 +1,41 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type samplingClient struct {
+	fakeServerClient
+	handler samplingHandler
+}
+
+func (c *samplingClient) SetSamplingHandler(handler samplingHandler) {
+	c.handler = handler
+}
+
+func TestToolset_SamplingHandler(t *testing.T) {
+	t.Parallel()
+
+	client := &samplingClient{}
+	ts := &Toolset{name: "docs", mcpClient: client, logID: "docs-server"}
+
+	var server string
+	ts.SetSamplingHandler(func(_ context.Context, s string, _ *mcp.CreateMessageParams) (*mcp.CreateMessageResult, error) {
+		server = s
+		return &mcp.CreateMessageResult{Content: &mcp.TextContent{Text: "done"}, Model: "model", Role: "assistant"}, nil
+	})
+
+	result, err := createMessage(t.Context(), client.handler, &mcp.CreateMessageRequest{Params: &mcp.CreateMessageParams{MaxTokens: 10}})
+	require.NoError(t, err)
+	assert.Equal(t, "docs", server)
+	assert.Equal(t, &mcp.TextContent{Text: "done"}, result.Content)
+
+	ts.SetSamplingHandler(nil)
+	_, err = createMessage(t.Context(), client.handler, &mcp.CreateMessageRequest{Params: &mcp.CreateMessageParams{}})
+	require.ErrorContains(t, err, "sampling is not available")
+}
@@ -5,12 +5,15 @@ import (
 	"errors"
 	"fmt"
 	"iter"
+	"log/slog"
 	"os/exec"
 	"runtime"
+	"sync"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
 	"github.com/docker/cagent/pkg/desktop"
+	"github.com/docker/cagent/pkg/tools"
 )
 
 type stdioMCPClient struct {
@@ -18,8 +21,14 @@ type stdioMCPClient struct {
 	command string
 	args    []string
 	env     []string
-	session *mcp.ClientSession
 	cwd     string
+
+	mu      sync.RWMutex
+	session *mcp.ClientSession
+	// done is closed once the server of the session exited
+	done               chan struct{}
+	samplingHandler    samplingHandler
+	elicitationHandler tools.ElicitationHandler
 }
 
 func newStdioCmdClient(command string, args, env []string, cwd string) *stdioMCPClient {
@@ -41,7 +50,10 @@ func (c *stdioMCPClient) Initialize(ctx context.Context, _ *mcp.InitializeReques
 	client := mcp.NewClient(&mcp.Implementation{
 		Name:    "cagent",
 		Version: "1.0.0",
-	}, nil)
+	}, &mcp.ClientOptions{
+		ElicitationHandler:   c.handleElicitationRequest,
+		CreateMessageHandler: c.createMessage,
+	})
 
 	cmd := exec.CommandContext(ctx, c.command, c.args...)
 	cmd.Env = c.env
@@ -53,52 +65,143 @@ func (c *stdioMCPClient) Initialize(ctx context.Context, _ *mcp.InitializeReques
 		return nil, err
 	}
 
+	done := make(chan struct{})
+	go func() {
+		_ = session.Wait()
+		close(done)
+	}()
+
+	c.mu.Lock()
 	c.session = session
-	return c.session.InitializeResult(), nil
+	c.done = done
+	c.mu.Unlock()
+	return session.InitializeResult(), nil
+}
+
+// SetElicitationHandler sets the handler of the user input requested by the server.
+func (c *stdioMCPClient) SetElicitationHandler(handler tools.ElicitationHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.elicitationHandler = handler
+}
+
+// handleElicitationRequest forwards incoming elicitation requests from the MCP server
+func (c *stdioMCPClient) handleElicitationRequest(ctx context.Context, req *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+	slog.Debug("Received elicitation request from MCP server", "message", req.Params.Message)
+
+	c.mu.RLock()
+	handler := c.elicitationHandler
+	c.mu.RUnlock()
+	if handler == nil {
+		return nil, fmt.Errorf("no elicitation handler configured")
+	}
+
+	result, err := handler(ctx, req.Params)
+	if err != nil {
+		return nil, fmt.Errorf("elicitation failed: %w", err)
+	}
+
+	return &mcp.ElicitResult{
+		Action:  string(result.Action),
+		Content: result.Content,
+	}, nil
+}
+
+// SetSamplingHandler sets the handler of the completions requested by the server.
+func (c *stdioMCPClient) SetSamplingHandler(handler samplingHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.samplingHandler = handler
+}
+
+func (c *stdioMCPClient) createMessage(ctx context.Context, req *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	c.mu.RLock()
+	handler := c.samplingHandler
+	c.mu.RUnlock()
+
+	return createMessage(ctx, handler, req)
+}
+
+func (c *stdioMCPClient) getSession() *mcp.ClientSession {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.session
 }
 
 func (c *stdioMCPClient) Close(context.Context) error {
-	if c.session == nil {
+	session := c.getSession()
+	if session == nil {
 		return nil
 	}
 
-	return c.session.Close()
+	return session.Close()
+}
+
+// Ping checks that the server answers.
+func (c *stdioMCPClient) Ping(ctx context.Context) error {
+	session := c.getSession()
+	if session == nil {
+		return fmt.Errorf("session not initialized")
+	}
+
+	return session.Ping(ctx, nil)
+}
+
+// Done is closed once the server of the current session exited.
+func (c *stdioMCPClient) Done() <-chan struct{} {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.done
 }
 
 func (c *stdioMCPClient) ListTools(ctx context.Context, request *mcp.ListToolsParams) iter.Seq2[*mcp.Tool, error] {
-	if c.session == nil {
+	session := c.getSession()
+	if session == nil {
 		return func(yield func(*mcp.Tool, error) bool) {
 			yield(nil, fmt.Errorf("session not initialized"))
 		}
 	}
 
-	return c.session.Tools(ctx, request)
+	return session.Tools(ctx, request)
 }
 
 func (c *stdioMCPClient) CallTool(ctx context.Context, request *mcp.CallToolParams) (*mcp.CallToolResult, error) {
-	if c.session == nil {
+	session := c.getSession()
+	if session == nil {
 		return nil, fmt.Errorf("session not initialized")
 	}
 
-	return c.session.CallTool(ctx, request)
+	return session.CallTool(ctx, request)
 }
 
 // ListPrompts retrieves available prompts from the MCP server via stdio transport
 func (c *stdioMCPClient) ListPrompts(ctx context.Context, request *mcp.ListPromptsParams) iter.Seq2[*mcp.Prompt, error] {
-	if c.session == nil {
+	session := c.getSession()
+	if session == nil {
 		return func(yield func(*mcp.Prompt, error) bool) {
 			yield(nil, fmt.Errorf("session not initialized"))
 		}
 	}
 
-	return c.session.Prompts(ctx, request)
+	return session.Prompts(ctx, request)
 }
 
 // GetPrompt retrieves a specific prompt with arguments from the MCP server via stdio transport
 func (c *stdioMCPClient) GetPrompt(ctx context.Context, request *mcp.GetPromptParams) (*mcp.GetPromptResult, error) {
-	if c.session == nil {
+	session := c.getSession()
+	if session == nil {
+		return nil, fmt.Errorf("session not initialized")
+	}
+
+	return session.GetPrompt(ctx, request)
+}
+
+// ReadResource reads a resource of the MCP server
+func (c *stdioMCPClient) ReadResource(ctx context.Context, request *mcp.ReadResourceParams) (*mcp.ReadResourceResult, error) {
+	session := c.getSession()
+	if session == nil {
 		return nil, fmt.Errorf("session not initialized")
 	}
 
-	return c.session.GetPrompt(ctx, request)
+	return session.ReadResource(ctx, request)
 }
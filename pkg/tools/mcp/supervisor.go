@@ -0,0 +1,152 @@
+package mcp
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/docker/cagent/pkg/tools"
+)
+
+const (
+	defaultHealthCheckInterval = 30 * time.Second
+	pingTimeout                = 10 * time.Second
+	defaultRestartBackoff      = time.Second
+	maxRestartBackoff          = 30 * time.Second
+	maxRestartAttempts         = 5
+)
+
+// supervisedClient is an MCP client whose server runs as a child process,
+// that cagent restarts when it crashes or hangs.
+type supervisedClient interface {
+	Ping(ctx context.Context) error
+	// Done is closed once the server of the current session exited
+	Done() <-chan struct{}
+}
+
+// supervise restarts the server of the toolset when it exits or stops
+// answering pings, until the toolset is stopped.
+func (ts *Toolset) supervise(ctx context.Context, client supervisedClient, stop <-chan struct{}) {
+	ticker := time.NewTicker(cmp.Or(ts.healthCheckInterval, defaultHealthCheckInterval))
+	defer ticker.Stop()
+
+	for {
+		var reason error
+		select {
+		case <-stop:
+			return
+		case <-client.Done():
+			reason = errors.New("the server exited")
+		case <-ticker.C:
+			reason = ts.ping(ctx, client)
+		case <-ts.checkNow:
+			reason = ts.ping(ctx, client)
+		}
+		if reason == nil {
+			continue
+		}
+
+		// The server also exits when the toolset is stopped
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
+		if !ts.restart(ctx, reason, stop) {
+			return
+		}
+	}
+}
+
+func (ts *Toolset) ping(ctx context.Context, client supervisedClient) error {
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+
+	if err := client.Ping(ctx); err != nil {
+		return fmt.Errorf("the server didn't answer a ping: %w", err)
+	}
+	return nil
+}
+
+// restart restarts the server, with an exponential backoff between the
+// attempts. It returns false if the server couldn't be restarted, or the
+// toolset was stopped meanwhile.
+func (ts *Toolset) restart(ctx context.Context, reason error, stop <-chan struct{}) bool {
+	server := cmp.Or(ts.name, ts.logID)
+	backoff := cmp.Or(ts.restartBackoff, defaultRestartBackoff)
+
+	for attempt := 1; attempt <= maxRestartAttempts; attempt++ {
+		slog.Warn("Restarting MCP server", "server", ts.logID, "attempt", attempt, "reason", reason)
+		ts.notify(tools.ServerStatus{Server: server, State: tools.ServerRestarting, Attempt: attempt, Error: reason.Error()})
+
+		if err := ts.mcpClient.Close(ctx); err != nil {
+			slog.Debug("Failed to close the MCP server before restarting it", "server", ts.logID, "error", err)
+		}
+
+		select {
+		case <-stop:
+			return false
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxRestartBackoff)
+
+		ts.mu.Lock()
+		select {
+		case <-stop:
+			ts.mu.Unlock()
+			return false
+		default:
+		}
+		err := ts.startServer(ctx)
+		ts.mu.Unlock()
+		if err != nil {
+			reason = err
+			continue
+		}
+
+		// The tools are listed again: the restarted server may have others
+		toolsList, err := ts.Tools(ctx)
+		if err != nil {
+			reason = err
+			continue
+		}
+
+		slog.Info("Restarted MCP server", "server", ts.logID, "attempt", attempt, "tools", len(toolsList))
+		ts.notify(tools.ServerStatus{Server: server, State: tools.ServerReconnected, Attempt: attempt, Tools: len(toolsList)})
+		return true
+	}
+
+	slog.Error("Failed to restart MCP server", "server", ts.logID, "error", reason)
+	ts.notify(tools.ServerStatus{Server: server, State: tools.ServerFailed, Attempt: maxRestartAttempts, Error: reason.Error()})
+	return false
+}
+
+// checkHealth asks the supervisor to check the server now, rather than at
+// its next health check, when a tool call failed.
+func (ts *Toolset) checkHealth() {
+	select {
+	case ts.checkNow <- struct{}{}:
+	default:
+	}
+}
+
+func (ts *Toolset) notify(status tools.ServerStatus) {
+	ts.statusMu.Lock()
+	handler := ts.statusHandler
+	ts.statusMu.Unlock()
+
+	status.Container = ts.container
+	if handler != nil {
+		handler(status)
+	}
+}
+
+func (ts *Toolset) SetStatusHandler(handler tools.StatusHandler) {
+	ts.statusMu.Lock()
+	defer ts.statusMu.Unlock()
+	ts.statusHandler = handler
+}
@@ -0,0 +1,203 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/tools"
+)
+
+// fakeServerClient is an MCP client whose server can crash, hang or fail to
+// restart.
+type fakeServerClient struct {
+	baseMCPClient
+
+	mu         sync.Mutex
+	starts     int
+	done       chan struct{}
+	hung       bool
+	failStarts int
+}
+
+func (c *fakeServerClient) Initialize(context.Context, *mcp.InitializeRequest) (*mcp.InitializeResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.starts++
+	if c.starts > 1 && c.failStarts > 0 {
+		c.failStarts--
+		return nil, errors.New("command not found")
+	}
+	c.done = make(chan struct{})
+	c.hung = false
+	return &mcp.InitializeResult{}, nil
+}
+
+func (c *fakeServerClient) Ping(context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.hung {
+		return context.DeadlineExceeded
+	}
+	return nil
+}
+
+func (c *fakeServerClient) Done() <-chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.done
+}
+
+func (c *fakeServerClient) crash() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	close(c.done)
+}
+
+func (c *fakeServerClient) hang() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hung = true
+}
+
+func (c *fakeServerClient) startCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.starts
+}
+
+func (c *fakeServerClient) ListTools(context.Context, *mcp.ListToolsParams) iter.Seq2[*mcp.Tool, error] {
+	return func(yield func(*mcp.Tool, error) bool) {
+		_ = yield(&mcp.Tool{Name: "search"}, nil) && yield(&mcp.Tool{Name: "fetch"}, nil)
+	}
+}
+
+func (c *fakeServerClient) CallTool(context.Context, *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *fakeServerClient) ListPrompts(context.Context, *mcp.ListPromptsParams) iter.Seq2[*mcp.Prompt, error] {
+	return func(func(*mcp.Prompt, error) bool) {}
+}
+
+func (c *fakeServerClient) GetPrompt(context.Context, *mcp.GetPromptParams) (*mcp.GetPromptResult, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *fakeServerClient) ReadResource(context.Context, *mcp.ReadResourceParams) (*mcp.ReadResourceResult, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *fakeServerClient) Close(context.Context) error { return nil }
+
+func startSupervised(t *testing.T, client *fakeServerClient) (*Toolset, chan tools.ServerStatus) {
+	t.Helper()
+
+	ts := &Toolset{
+		name:                "docs",
+		mcpClient:           client,
+		logID:               "docs-server",
+		checkNow:            make(chan struct{}, 1),
+		healthCheckInterval: 10 * time.Millisecond,
+		restartBackoff:      time.Millisecond,
+	}
+	statuses := make(chan tools.ServerStatus, 16)
+	ts.SetStatusHandler(func(status tools.ServerStatus) { statuses <- status })
+
+	require.NoError(t, ts.Start(t.Context()))
+	t.Cleanup(func() { _ = ts.Stop(context.Background()) })
+	return ts, statuses
+}
+
+func nextStatus(t *testing.T, statuses chan tools.ServerStatus) tools.ServerStatus {
+	t.Helper()
+
+	select {
+	case status := <-statuses:
+		return status
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no status of the server")
+		return tools.ServerStatus{}
+	}
+}
+
+func TestSupervise_RestartsCrashedServer(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeServerClient{}
+	ts, statuses := startSupervised(t, client)
+
+	client.crash()
+
+	restarting := nextStatus(t, statuses)
+	assert.Equal(t, tools.ServerRestarting, restarting.State)
+	assert.Equal(t, "docs", restarting.Server)
+	assert.Equal(t, 1, restarting.Attempt)
+	assert.Equal(t, "the server exited", restarting.Error)
+
+	reconnected := nextStatus(t, statuses)
+	assert.Equal(t, tools.ServerReconnected, reconnected.State)
+	assert.Equal(t, 2, reconnected.Tools)
+	assert.Equal(t, 2, client.startCount())
+
+	toolsList, err := ts.Tools(t.Context())
+	require.NoError(t, err)
+	assert.Len(t, toolsList, 2)
+}
+
+func TestSupervise_RestartsHungServer(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeServerClient{}
+	_, statuses := startSupervised(t, client)
+
+	client.hang()
+
+	restarting := nextStatus(t, statuses)
+	assert.Equal(t, tools.ServerRestarting, restarting.State)
+	assert.Contains(t, restarting.Error, "the server didn't answer a ping")
+	assert.Equal(t, tools.ServerReconnected, nextStatus(t, statuses).State)
+}
+
+func TestSupervise_GivesUpAfterFailedRestarts(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeServerClient{failStarts: maxRestartAttempts}
+	_, statuses := startSupervised(t, client)
+
+	client.crash()
+
+	for attempt := 1; attempt <= maxRestartAttempts; attempt++ {
+		status := nextStatus(t, statuses)
+		assert.Equal(t, tools.ServerRestarting, status.State)
+		assert.Equal(t, attempt, status.Attempt)
+	}
+	failed := nextStatus(t, statuses)
+	assert.Equal(t, tools.ServerFailed, failed.State)
+	assert.Contains(t, failed.Error, "command not found")
+}
+
+func TestSupervise_StoppedServerIsNotRestarted(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeServerClient{}
+	ts, statuses := startSupervised(t, client)
+
+	require.NoError(t, ts.Stop(t.Context()))
+	client.crash()
+
+	select {
+	case status := <-statuses:
+		assert.Failf(t, "stopped server was restarted", "%+v", status)
+	case <-time.After(100 * time.Millisecond):
+	}
+	assert.Equal(t, 1, client.startCount())
+}
@@ -24,6 +24,11 @@ type OAuthToken struct {
 	RefreshToken string    `json:"refresh_token,omitempty"`
 	Scope        string    `json:"scope,omitempty"`
 	ExpiresAt    time.Time `json:"expires_at,omitempty"`
+	// The client the token was issued to and the endpoint issuing the
+	// tokens, to refresh it once it expires
+	ClientID      string `json:"client_id,omitempty"`
+	ClientSecret  string `json:"client_secret,omitempty"`
+	TokenEndpoint string `json:"token_endpoint,omitempty"`
 }
 
 // IsExpired checks if the token is expired
@@ -35,6 +40,11 @@ func (t *OAuthToken) IsExpired() bool {
 	return time.Now().Add(30 * time.Second).After(t.ExpiresAt)
 }
 
+// CanRefresh checks if a new token can be requested with the refresh token
+func (t *OAuthToken) CanRefresh() bool {
+	return t.RefreshToken != "" && t.TokenEndpoint != "" && t.ClientID != ""
+}
+
 // InMemoryTokenStore implements OAuthTokenStore in memory
 type InMemoryTokenStore struct {
 	tokens *concurrent.Map[string, *OAuthToken]
@@ -0,0 +1,238 @@
+package tools
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// SchemaDialect describes the subset of tool definitions a model provider accepts.
+type SchemaDialect struct {
+	// Name identifies the dialect in warnings.
+	Name string
+	// MaxNameLength is the maximum length of a tool name. Zero means unlimited.
+	MaxNameLength int
+	// NameChars reports whether a character is allowed in a tool name.
+	// Disallowed characters are replaced with '_'. Nil allows everything.
+	NameChars func(r rune) bool
+	// NameMustStartWithLetter requires tool names to start with a letter or '_'.
+	NameMustStartWithLetter bool
+	// UnsupportedKeywords are removed from the schema, at any depth.
+	UnsupportedKeywords []string
+	// StringFormats lists the accepted values of "format" on string
+	// properties. Other formats are removed. Nil accepts every format.
+	StringFormats []string
+	// NoTopLevelCombinators flattens oneOf/anyOf/allOf at the root of the
+	// parameters schema into a single object schema.
+	NoTopLevelCombinators bool
+	// NoOneOf rewrites oneOf as anyOf.
+	NoOneOf bool
+	// NoAllOf merges allOf sub-schemas into their parent.
+	NoAllOf bool
+}
+
+// NormalizeTools rewrites the tools' names and parameter schemas into a form
+// accepted by the dialect. It returns the rewritten tools along with a
+// description of every change that was made. Tools that need no change are
+// returned as is.
+func NormalizeTools(list []Tool, d SchemaDialect) ([]Tool, []string) {
+	var changes []string
+
+	normalized := make([]Tool, len(list))
+	// Renamed tools must not take the name of another tool.
+	used := make(map[string]bool, len(list))
+	for _, tool := range list {
+		used[tool.Name] = true
+	}
+	for i, tool := range list {
+		normalized[i] = tool
+
+		name := d.normalizeName(tool.Name, used)
+		used[name] = true
+		if name != tool.Name {
+			normalized[i].Name = name
+			changes = append(changes, fmt.Sprintf("tool %q: renamed to %q", tool.Name, name))
+		}
+
+		schema, err := SchemaToMap(tool.Parameters)
+		if err != nil {
+			continue
+		}
+		var schemaChanges []string
+		d.normalizeSchema(schema, "", &schemaChanges)
+		if len(schemaChanges) == 0 {
+			continue
+		}
+
+		normalized[i].Parameters = schema
+		for _, change := range schemaChanges {
+			changes = append(changes, fmt.Sprintf("tool %q: %s", tool.Name, change))
+		}
+	}
+
+	return normalized, changes
+}
+
+func (d SchemaDialect) normalizeName(name string, used map[string]bool) string {
+	var sb strings.Builder
+	for i, r := range name {
+		switch {
+		case d.NameChars != nil && !d.NameChars(r):
+			sb.WriteRune('_')
+		case i == 0 && d.NameMustStartWithLetter && !isLetter(r) && r != '_':
+			sb.WriteRune('_')
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	normalized := sb.String()
+	if normalized == "" {
+		normalized = "_"
+	}
+
+	if d.MaxNameLength > 0 && len(normalized) > d.MaxNameLength {
+		normalized = shortenName(name, normalized, d.MaxNameLength)
+	}
+
+	if used[normalized] && normalized != name {
+		maxLength := d.MaxNameLength
+		if maxLength == 0 {
+			maxLength = len(normalized) + 9
+		}
+		normalized = shortenName(name, normalized, maxLength)
+	}
+
+	return normalized
+}
+
+// shortenName truncates name to maxLength, keeping it unique with a hash of the original name.
+func shortenName(original, name string, maxLength int) string {
+	sum := sha256.Sum256([]byte(original))
+	suffix := "_" + hex.EncodeToString(sum[:])[:8]
+	return name[:min(len(name), max(0, maxLength-len(suffix)))] + suffix
+}
+
+func isLetter(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
+func (d SchemaDialect) normalizeSchema(schema map[string]any, path string, changes *[]string) {
+	for _, keyword := range d.UnsupportedKeywords {
+		if _, ok := schema[keyword]; ok {
+			delete(schema, keyword)
+			*changes = append(*changes, fmt.Sprintf("removed unsupported keyword %q%s", keyword, at(path)))
+		}
+	}
+
+	if format, ok := schema["format"].(string); ok && schema["type"] == "string" && d.StringFormats != nil && !slices.Contains(d.StringFormats, format) {
+		delete(schema, "format")
+		*changes = append(*changes, fmt.Sprintf("removed unsupported format %q%s", format, at(path)))
+	}
+
+	if d.NoAllOf {
+		if allOf, ok := schema["allOf"].([]any); ok {
+			delete(schema, "allOf")
+			for _, sub := range allOf {
+				if subSchema, ok := sub.(map[string]any); ok {
+					mergeSchema(schema, subSchema, true)
+				}
+			}
+			*changes = append(*changes, "merged allOf"+at(path))
+		}
+	}
+
+	if d.NoOneOf {
+		if oneOf, ok := schema["oneOf"]; ok {
+			delete(schema, "oneOf")
+			schema["anyOf"] = oneOf
+			*changes = append(*changes, "rewrote oneOf as anyOf"+at(path))
+		}
+	}
+
+	if path == "" && d.NoTopLevelCombinators {
+		for _, keyword := range []string{"allOf", "oneOf", "anyOf"} {
+			variants, ok := schema[keyword].([]any)
+			if !ok {
+				continue
+			}
+			delete(schema, keyword)
+			for _, sub := range variants {
+				if subSchema, ok := sub.(map[string]any); ok {
+					// Only allOf variants all apply, so only their required properties stay required.
+					mergeSchema(schema, subSchema, keyword == "allOf")
+				}
+			}
+			schema["type"] = "object"
+			*changes = append(*changes, fmt.Sprintf("flattened top-level %s", keyword))
+		}
+	}
+
+	if props, ok := schema["properties"].(map[string]any); ok {
+		for _, name := range slices.Sorted(maps.Keys(props)) {
+			if prop, ok := props[name].(map[string]any); ok {
+				d.normalizeSchema(prop, path+"."+name, changes)
+			}
+		}
+	}
+	if items, ok := schema["items"].(map[string]any); ok {
+		d.normalizeSchema(items, path+"[]", changes)
+	}
+	for _, keyword := range []string{"anyOf", "oneOf", "allOf"} {
+		if variants, ok := schema[keyword].([]any); ok {
+			for i, sub := range variants {
+				if subSchema, ok := sub.(map[string]any); ok {
+					d.normalizeSchema(subSchema, fmt.Sprintf("%s.%s[%d]", path, keyword, i), changes)
+				}
+			}
+		}
+	}
+}
+
+// mergeSchema merges the properties of src into dst.
+func mergeSchema(dst, src map[string]any, keepRequired bool) {
+	if srcProps, ok := src["properties"].(map[string]any); ok {
+		dstProps, ok := dst["properties"].(map[string]any)
+		if !ok {
+			dstProps = map[string]any{}
+			dst["properties"] = dstProps
+		}
+		for name, prop := range srcProps {
+			if _, exists := dstProps[name]; !exists {
+				dstProps[name] = prop
+			}
+		}
+	}
+
+	if keepRequired {
+		if srcRequired, ok := src["required"].([]any); ok {
+			dstRequired, _ := dst["required"].([]any)
+			for _, name := range srcRequired {
+				if !slices.Contains(dstRequired, name) {
+					dstRequired = append(dstRequired, name)
+				}
+			}
+			dst["required"] = dstRequired
+		}
+	}
+
+	for key, value := range src {
+		switch key {
+		case "properties", "required":
+			continue
+		}
+		if _, exists := dst[key]; !exists {
+			dst[key] = value
+		}
+	}
+}
+
+func at(path string) string {
+	if path == "" {
+		return ""
+	}
+	return " at " + strings.TrimPrefix(path, ".")
+}
@@ -0,0 +1,124 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func strictDialect() SchemaDialect {
+	return SchemaDialect{
+		Name:          "strict",
+		MaxNameLength: 20,
+		NameChars: func(r rune) bool {
+			return (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_'
+		},
+		NameMustStartWithLetter: true,
+		UnsupportedKeywords:     []string{"$ref", "const"},
+		StringFormats:           []string{"date-time"},
+		NoTopLevelCombinators:   true,
+		NoOneOf:                 true,
+		NoAllOf:                 true,
+	}
+}
+
+func TestNormalizeToolsLeavesValidToolsUntouched(t *testing.T) {
+	t.Parallel()
+
+	params := map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"path": map[string]any{"type": "string"}},
+	}
+	list := []Tool{{Name: "read_file", Parameters: params}}
+
+	normalized, changes := NormalizeTools(list, strictDialect())
+
+	assert.Empty(t, changes)
+	assert.Equal(t, list, normalized)
+}
+
+func TestNormalizeToolsRenames(t *testing.T) {
+	t.Parallel()
+
+	list := []Tool{
+		{Name: "github.list-issues"},
+		{Name: "github_list_issues"},
+		{Name: "1password"},
+		{Name: "a_really_long_tool_name_from_an_mcp_server"},
+	}
+
+	normalized, changes := NormalizeTools(list, strictDialect())
+
+	// The renamed tool doesn't take the name of the second one
+	assert.True(t, strings.HasPrefix(normalized[0].Name, "github_list_"))
+	assert.LessOrEqual(t, len(normalized[0].Name), 20)
+	assert.Equal(t, "github_list_issues", normalized[1].Name)
+	assert.Equal(t, "_1password", normalized[2].Name)
+	assert.Len(t, normalized[3].Name, 20)
+	assert.True(t, strings.HasPrefix(normalized[3].Name, "a_really_lo_"))
+
+	assert.Contains(t, changes, `tool "1password": renamed to "_1password"`)
+	assert.Len(t, changes, 3)
+}
+
+func TestNormalizeToolsRewritesSchema(t *testing.T) {
+	t.Parallel()
+
+	list := []Tool{{
+		Name: "search",
+		Parameters: map[string]any{
+			"oneOf": []any{
+				map[string]any{
+					"type":       "object",
+					"properties": map[string]any{"query": map[string]any{"type": "string", "format": "uri"}},
+					"required":   []any{"query"},
+				},
+				map[string]any{
+					"type":       "object",
+					"properties": map[string]any{"id": map[string]any{"const": "x", "type": "string"}},
+				},
+			},
+			"properties": map[string]any{
+				"since": map[string]any{"type": "string", "format": "date-time"},
+				"filter": map[string]any{
+					"allOf": []any{
+						map[string]any{"properties": map[string]any{"label": map[string]any{"type": "string"}}, "required": []any{"label"}},
+					},
+				},
+				"kind": map[string]any{"oneOf": []any{map[string]any{"type": "string"}, map[string]any{"$ref": "#/x"}}},
+			},
+		},
+	}}
+
+	normalized, changes := NormalizeTools(list, strictDialect())
+
+	assert.Equal(t, []string{
+		`tool "search": rewrote oneOf as anyOf`,
+		`tool "search": flattened top-level anyOf`,
+		`tool "search": merged allOf at filter`,
+		`tool "search": removed unsupported keyword "const" at id`,
+		`tool "search": rewrote oneOf as anyOf at kind`,
+		`tool "search": removed unsupported keyword "$ref" at kind.anyOf[1]`,
+		`tool "search": removed unsupported format "uri" at query`,
+	}, changes)
+
+	schema := normalized[0].Parameters.(map[string]any)
+	assert.Equal(t, "object", schema["type"])
+	assert.NotContains(t, schema, "oneOf")
+	assert.NotContains(t, schema, "anyOf")
+	assert.NotContains(t, schema, "required", "variants of oneOf are not all required")
+
+	props := schema["properties"].(map[string]any)
+	assert.Contains(t, props, "query")
+	assert.Contains(t, props, "id")
+	assert.Equal(t, "date-time", props["since"].(map[string]any)["format"])
+
+	filter := props["filter"].(map[string]any)
+	assert.Equal(t, []any{"label"}, filter["required"])
+	require.Contains(t, filter["properties"], "label")
+
+	// The original tool is not modified
+	assert.Contains(t, list[0].Parameters, "oneOf")
+}
@@ -24,6 +24,17 @@ type ToolCallResult struct {
 	Output  string `json:"output"`
 	IsError bool   `json:"isError,omitempty"`
 	Meta    any    `json:"meta,omitempty"`
+	// Untrusted is set when the output comes from a source that is not
+	// controlled by the user (web pages, emails, issue bodies...).
+	Untrusted bool `json:"untrusted,omitempty"`
+	// Images holds images returned by the tool alongside its text output.
+	Images []Image `json:"images,omitempty"`
+}
+
+// Image is an image returned by a tool.
+type Image struct {
+	MimeType string `json:"mimeType"`
+	Data     []byte `json:"data"`
 }
 
 func ResultError(output string) *ToolCallResult {
@@ -95,6 +106,12 @@ func (BaseToolSet) SetOAuthSuccessHandler(func()) {}
 // SetManagedOAuth is a no-op for tools that don't use OAuth.
 func (BaseToolSet) SetManagedOAuth(bool) {}
 
+// SetStatusHandler is a no-op for tools without a server to supervise.
+func (BaseToolSet) SetStatusHandler(StatusHandler) {}
+
+// SetSamplingHandler is a no-op for tools that don't request completions.
+func (BaseToolSet) SetSamplingHandler(SamplingHandler) {}
+
 type ToolSet interface {
 	Tools(ctx context.Context) ([]Tool, error)
 	Instructions() string
@@ -103,8 +120,48 @@ type ToolSet interface {
 	SetElicitationHandler(handler ElicitationHandler)
 	SetOAuthSuccessHandler(handler func())
 	SetManagedOAuth(managed bool)
+	SetStatusHandler(handler StatusHandler)
+	SetSamplingHandler(handler SamplingHandler)
+}
+
+// ServerState is the state of the server of a toolset, once it stopped
+// answering, or of the container it runs in.
+type ServerState string
+
+const (
+	ServerRestarting  ServerState = "restarting"
+	ServerReconnected ServerState = "reconnected"
+	ServerFailed      ServerState = "failed"
+
+	// The states of the containers of the servers run in a sandbox
+	ServerStarting ServerState = "starting"
+	ServerRunning  ServerState = "running"
+	ServerStopped  ServerState = "stopped"
+)
+
+// ServerStatus is a change of the state of the server of a toolset.
+type ServerStatus struct {
+	// Server is the name of the toolset, or the command of its server
+	Server string
+	State  ServerState
+	// Attempt is the restart attempt, from 1
+	Attempt int
+	// Error is why the server is restarted, or why it couldn't be
+	Error string
+	// Tools is the number of tools of the reconnected server
+	Tools int
+	// Container is the image of the container the server runs in, if any
+	Container string
 }
 
+// StatusHandler is notified when the server of a toolset crashes, hangs, or
+// is restarted, and when the container it runs in starts or stops.
+type StatusHandler func(status ServerStatus)
+
+// SamplingHandler runs a completion requested by the server of a toolset
+// with the models of the agent, on behalf of the server.
+type SamplingHandler func(ctx context.Context, server string, req *mcp.CreateMessageParams) (*mcp.CreateMessageResult, error)
+
 // NewHandler creates a type-safe tool handler from a function that accepts typed parameters.
 // It handles JSON unmarshaling of the tool call arguments into the specified type T.
 func NewHandler[T any](fn func(context.Context, T) (*ToolCallResult, error)) ToolHandler {
@@ -0,0 +1,40 @@
+// Package clipboard copies text to the system clipboard from the TUI.
+//
+// Text is always sent with an OSC52 escape sequence, which terminals forward to
+// the clipboard of the machine they run on. This is what makes copying work
+// over SSH. Outside of SSH sessions, the native clipboard is written too, for
+// terminals that don't support OSC52.
+package clipboard
+
+import (
+	"log/slog"
+	"os"
+
+	tea "charm.land/bubbletea/v2"
+	systemclipboard "github.com/atotto/clipboard"
+
+	"github.com/docker/cagent/pkg/tui/components/notification"
+)
+
+// Copy copies text to the clipboard and shows successMessage in a notification.
+func Copy(text, successMessage string) tea.Cmd {
+	return tea.Sequence(
+		tea.SetClipboard(text),
+		func() tea.Msg {
+			if isSSHSession() {
+				return nil
+			}
+			if err := systemclipboard.WriteAll(text); err != nil {
+				slog.Debug("Failed to write to the native clipboard", "error", err)
+			}
+			return nil
+		},
+		notification.SuccessCmd(successMessage),
+	)
+}
+
+// isSSHSession returns true if cagent runs in an SSH session, where the native
+// clipboard belongs to the remote machine.
+func isSSHSession() bool {
+	return os.Getenv("SSH_TTY") != "" || os.Getenv("SSH_CONNECTION") != ""
+}
@@ -3,15 +3,21 @@ package commands
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
+	"sync"
 
 	tea "charm.land/bubbletea/v2"
 
 	"github.com/docker/cagent/pkg/app"
 	"github.com/docker/cagent/pkg/feedback"
+	mcptools "github.com/docker/cagent/pkg/tools/mcp"
+	"github.com/docker/cagent/pkg/tui/components/notification"
 	"github.com/docker/cagent/pkg/tui/components/toolcommon"
 	"github.com/docker/cagent/pkg/tui/core"
 	"github.com/docker/cagent/pkg/tui/messages"
+	"github.com/docker/cagent/pkg/tui/styles"
 )
 
 // ExecuteFunc is a function that executes a command with an optional argument.
@@ -23,6 +29,10 @@ type Category struct {
 	Commands []Item
 }
 
+// ArgumentsFunc returns the values the completion popup offers for the
+// argument of a command.
+type ArgumentsFunc func(application *app.App) []string
+
 // Item represents a single command in the palette
 type Item struct {
 	ID           string
@@ -31,6 +41,27 @@ type Item struct {
 	Category     string
 	SlashCommand string
 	Execute      ExecuteFunc
+	Arguments    ArgumentsFunc
+}
+
+var (
+	registeredMu sync.Mutex
+	registered   []Item
+)
+
+// Register adds commands to the palette and to the slash commands of the
+// editor. Commands are listed in their category, which is created when it
+// doesn't exist yet. A registered command can't override a built-in one.
+func Register(items ...Item) {
+	registeredMu.Lock()
+	defer registeredMu.Unlock()
+	registered = append(registered, items...)
+}
+
+func registeredCommands() []Item {
+	registeredMu.Lock()
+	defer registeredMu.Unlock()
+	return slices.Clone(registered)
 }
 
 func builtInSessionCommands() []Item {
@@ -55,6 +86,26 @@ func builtInSessionCommands() []Item {
 				return core.CmdHandler(messages.NewSessionMsg{})
 			},
 		},
+		{
+			ID:           "session.clear",
+			Label:        "Clear",
+			SlashCommand: "/clear",
+			Description:  "Clear the conversation and start a new one",
+			Category:     "Session",
+			Execute: func(string) tea.Cmd {
+				return core.CmdHandler(messages.NewSessionMsg{})
+			},
+		},
+		{
+			ID:           "session.resume",
+			Label:        "Resume",
+			SlashCommand: "/resume",
+			Description:  "Resume the most recent session (usage: /resume [session id])",
+			Category:     "Session",
+			Execute: func(arg string) tea.Cmd {
+				return core.CmdHandler(messages.ResumeSessionMsg{SessionID: strings.TrimSpace(arg)})
+			},
+		},
 		{
 			ID:           "session.history",
 			Label:        "Sessions",
@@ -65,6 +116,16 @@ func builtInSessionCommands() []Item {
 				return core.CmdHandler(messages.OpenSessionBrowserMsg{})
 			},
 		},
+		{
+			ID:           "session.rewind",
+			Label:        "Rewind",
+			SlashCommand: "/rewind",
+			Description:  "Rewind the conversation to one of your messages, in a new session",
+			Category:     "Session",
+			Execute: func(string) tea.Cmd {
+				return core.CmdHandler(messages.ShowRewindMsg{})
+			},
+		},
 		{
 			ID:           "session.star",
 			Label:        "Star",
@@ -85,6 +146,19 @@ func builtInSessionCommands() []Item {
 				return core.CmdHandler(messages.OpenModelPickerMsg{})
 			},
 		},
+		{
+			ID:           "session.agent",
+			Label:        "Agent",
+			SlashCommand: "/agent",
+			Description:  "Switch to another agent (usage: /agent [name])",
+			Category:     "Session",
+			Execute: func(arg string) tea.Cmd {
+				return core.CmdHandler(messages.SwitchAgentMsg{AgentName: strings.TrimSpace(arg)})
+			},
+			Arguments: func(application *app.App) []string {
+				return application.AvailableAgents()
+			},
+		},
 		{
 			ID:           "session.compact",
 			Label:        "Compact",
@@ -95,6 +169,16 @@ func builtInSessionCommands() []Item {
 				return core.CmdHandler(messages.CompactSessionMsg{AdditionalPrompt: arg})
 			},
 		},
+		{
+			ID:           "session.postmortem",
+			Label:        "Post-mortem",
+			SlashCommand: "/postmortem",
+			Description:  "Analyze why the last run didn't succeed, for the next attempt",
+			Category:     "Session",
+			Execute: func(string) tea.Cmd {
+				return core.CmdHandler(messages.PostMortemMsg{})
+			},
+		},
 		{
 			ID:           "session.clipboard",
 			Label:        "Copy",
@@ -115,6 +199,16 @@ func builtInSessionCommands() []Item {
 				return core.CmdHandler(messages.CopyLastResponseToClipboardMsg{})
 			},
 		},
+		{
+			ID:           "session.copy_last_code_block",
+			Label:        "Copy Last Code Block",
+			SlashCommand: "/copy-code",
+			Description:  "Copy the last code block of the conversation to the clipboard",
+			Category:     "Session",
+			Execute: func(string) tea.Cmd {
+				return core.CmdHandler(messages.CopyLastCodeBlockToClipboardMsg{})
+			},
+		},
 		{
 			ID:           "session.eval",
 			Label:        "Eval",
@@ -129,7 +223,7 @@ func builtInSessionCommands() []Item {
 			ID:           "session.export",
 			Label:        "Export",
 			SlashCommand: "/export",
-			Description:  "Export the session as HTML (usage: /export [filename])",
+			Description:  "Export the session as HTML, or Markdown with a .md filename (usage: /export [filename])",
 			Category:     "Session",
 			Execute: func(arg string) tea.Cmd {
 				return core.CmdHandler(messages.ExportSessionMsg{Filename: arg})
@@ -165,6 +259,77 @@ func builtInSessionCommands() []Item {
 				return core.CmdHandler(messages.ShowCostDialogMsg{})
 			},
 		},
+		{
+			ID:           "session.help",
+			Label:        "Help",
+			SlashCommand: "/help",
+			Description:  "Show what the agents can do: their roles, tools, models and budgets",
+			Category:     "Session",
+			Execute: func(string) tea.Cmd {
+				return core.CmdHandler(messages.ShowHelpMsg{})
+			},
+		},
+		{
+			ID:           "session.tools",
+			Label:        "Reload Tools",
+			SlashCommand: "/tools",
+			Description:  "Reload the tools of the agents once their configuration changed (usage: /tools reload)",
+			Category:     "Session",
+			Execute: func(arg string) tea.Cmd {
+				switch strings.TrimSpace(arg) {
+				case "", "reload":
+					return core.CmdHandler(messages.ReloadToolsMsg{})
+				default:
+					return notification.ErrorCmd(fmt.Sprintf("Unknown tools command '%s', usage: /tools reload", strings.TrimSpace(arg)))
+				}
+			},
+			Arguments: func(*app.App) []string {
+				return []string{"reload"}
+			},
+		},
+		{
+			ID:           "session.raw",
+			Label:        "Raw Markdown",
+			SlashCommand: "/raw",
+			Description:  "Toggle between rendered and raw markdown for assistant messages",
+			Category:     "Session",
+			Execute: func(string) tea.Cmd {
+				return core.CmdHandler(messages.ToggleRawMarkdownMsg{})
+			},
+		},
+		{
+			ID:           "session.expand",
+			Label:        "Expand Tool Calls",
+			SlashCommand: "/expand",
+			Description:  "Expand or collapse the arguments and results of every tool call",
+			Category:     "Session",
+			Execute: func(string) tea.Cmd {
+				return core.CmdHandler(messages.ToggleExpandToolCallsMsg{})
+			},
+		},
+		{
+			ID:           "session.subagents",
+			Label:        "Hide Sub-Agents",
+			SlashCommand: "/subagents",
+			Description:  "Hide or show what the sub-agents say and do in the conversation",
+			Category:     "Session",
+			Execute: func(string) tea.Cmd {
+				return core.CmdHandler(messages.ToggleHideSubAgentsMsg{})
+			},
+		},
+		{
+			ID:           "session.theme",
+			Label:        "Theme",
+			SlashCommand: "/theme",
+			Description:  "Switch the color theme (usage: /theme [" + strings.Join(styles.ThemeNames(), "|") + "])",
+			Category:     "Session",
+			Execute: func(arg string) tea.Cmd {
+				return core.CmdHandler(messages.ChangeThemeMsg{Name: strings.TrimSpace(arg)})
+			},
+			Arguments: func(*app.App) []string {
+				return styles.ThemeNames()
+			},
+		},
 		{
 			ID:           "session.attach",
 			Label:        "Attach",
@@ -221,6 +386,15 @@ func BuildCommandCategories(ctx context.Context, application *app.App) []Categor
 		},
 	}
 
+	for _, item := range registeredCommands() {
+		i := slices.IndexFunc(categories, func(c Category) bool { return c.Name == item.Category })
+		if i < 0 {
+			categories = append(categories, Category{Name: item.Category})
+			i = len(categories) - 1
+		}
+		categories[i].Commands = append(categories[i].Commands, item)
+	}
+
 	agentCommands := application.CurrentAgentCommands(ctx)
 	if len(agentCommands) > 0 {
 		var commands []Item
@@ -246,81 +420,148 @@ func BuildCommandCategories(ctx context.Context, application *app.App) []Categor
 	mcpPrompts := application.CurrentMCPPrompts(ctx)
 	if len(mcpPrompts) > 0 {
 		mcpCommands := make([]Item, 0, len(mcpPrompts))
-		for promptName, promptInfo := range mcpPrompts {
-			// Build description with argument info
-			description := promptInfo.Description
-			if len(promptInfo.Arguments) > 0 {
-				// Count required arguments
-				requiredCount := 0
-				for _, arg := range promptInfo.Arguments {
-					if arg.Required {
-						requiredCount++
-					}
-				}
+		for _, promptName := range slices.Sorted(maps.Keys(mcpPrompts)) {
+			mcpCommands = append(mcpCommands, mcpPromptCommand(promptName, mcpPrompts[promptName]))
+		}
 
-				if requiredCount > 0 {
-					if description != "" {
-						description += " "
-					}
-					if requiredCount == 1 {
-						description += "(1 required arg)"
-					} else {
-						description += fmt.Sprintf("(%d required args)", requiredCount)
-					}
+		categories = append(categories, Category{
+			Name:     "MCP Prompts",
+			Commands: mcpCommands,
+		})
+	}
+
+	return categories
+}
+
+// mcpPromptCommand is the command running an MCP prompt, e.g. "/review" or
+// "/review pr=123". Prompts with required arguments that aren't given open a
+// dialog asking for them.
+func mcpPromptCommand(promptName string, promptInfo mcptools.PromptInfo) Item {
+	// Build description with argument info
+	description := promptInfo.Description
+	requiredCount := 0
+	for _, arg := range promptInfo.Arguments {
+		if arg.Required {
+			requiredCount++
+		}
+	}
+	if requiredCount > 0 {
+		if description != "" {
+			description += " "
+		}
+		if requiredCount == 1 {
+			description += "(1 required arg)"
+		} else {
+			description += fmt.Sprintf("(%d required args)", requiredCount)
+		}
+	}
+
+	return Item{
+		ID:           "mcp.prompt." + promptName,
+		Label:        promptName,
+		Description:  toolcommon.TruncateText(description, 55),
+		Category:     "MCP Prompts",
+		SlashCommand: "/" + promptName,
+		Execute: func(arg string) tea.Cmd {
+			arguments := parsePromptArguments(arg, promptInfo.Arguments)
+			for _, promptArg := range promptInfo.Arguments {
+				if promptArg.Required && arguments[promptArg.Name] == "" {
+					return core.CmdHandler(messages.ShowMCPPromptInputMsg{
+						PromptName: promptName,
+						PromptInfo: promptInfo,
+					})
 				}
 			}
 
-			// Truncate long descriptions to fit on one line
-			description = toolcommon.TruncateText(description, 55)
+			return core.CmdHandler(messages.MCPPromptMsg{
+				PromptName: promptName,
+				Arguments:  arguments,
+			})
+		},
+	}
+}
 
-			// Create closure variables to capture current iteration values
-			currentPromptName := promptName
-			currentPromptInfo := promptInfo
+// parsePromptArguments parses the arguments of an MCP prompt typed after its
+// slash command, as name=value pairs. The whole text is the value of the
+// argument of prompts that have a single one.
+func parsePromptArguments(text string, promptArgs []mcptools.PromptArgument) map[string]string {
+	arguments := make(map[string]string)
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return arguments
+	}
 
-			mcpCommands = append(mcpCommands, Item{
-				ID:          "mcp.prompt." + promptName,
-				Label:       promptName,
-				Description: description,
-				Category:    "MCP Prompts",
-				Execute: func(string) tea.Cmd {
-					// If prompt has no required arguments, execute immediately
-					hasRequiredArgs := false
-					for _, arg := range currentPromptInfo.Arguments {
-						if arg.Required {
-							hasRequiredArgs = true
-							break
-						}
-					}
-
-					if !hasRequiredArgs {
-						// Execute prompt with empty arguments
-						return core.CmdHandler(messages.MCPPromptMsg{
-							PromptName: currentPromptName,
-							Arguments:  make(map[string]string),
-						})
-					} else {
-						// Show parameter input dialog for prompts with required arguments
-						return core.CmdHandler(messages.ShowMCPPromptInputMsg{
-							PromptName: currentPromptName,
-							PromptInfo: currentPromptInfo,
-						})
-					}
-				},
-			})
+	if len(promptArgs) == 1 {
+		name, value, ok := strings.Cut(text, "=")
+		if !ok || name != promptArgs[0].Name {
+			value = text
 		}
+		arguments[promptArgs[0].Name] = value
+		return arguments
+	}
 
-		categories = append(categories, Category{
-			Name:     "MCP Prompts",
-			Commands: mcpCommands,
-		})
+	for field := range strings.FieldsSeq(text) {
+		if name, value, ok := strings.Cut(field, "="); ok {
+			arguments[name] = value
+		}
 	}
+	return arguments
+}
 
-	return categories
+// ParseMCPPrompt checks if the input runs an MCP prompt of the current agent,
+// e.g. "/review pr=123", and returns the tea.Cmd running it. Agent commands
+// with the same name take precedence. Returns nil otherwise.
+func ParseMCPPrompt(ctx context.Context, application *app.App, input string) tea.Cmd {
+	if input == "" || input[0] != '/' {
+		return nil
+	}
+
+	name, arg, _ := strings.Cut(input[1:], " ")
+	if _, found := application.CurrentAgentCommands(ctx)[name]; found {
+		return nil
+	}
+	promptInfo, found := application.CurrentMCPPrompts(ctx)[name]
+	if !found {
+		return nil
+	}
+
+	return mcpPromptCommand(name, promptInfo).Execute(arg)
+}
+
+// ExpandArguments adds an entry for each argument value of the commands that
+// have some, so that the palette can run "/agent reviewer" or "/theme light"
+// in one step.
+func ExpandArguments(categories []Category, application *app.App) []Category {
+	expanded := make([]Category, 0, len(categories))
+	for _, cat := range categories {
+		var items []Item
+		for _, item := range cat.Commands {
+			items = append(items, item)
+			if item.Arguments == nil {
+				continue
+			}
+			for _, arg := range item.Arguments(application) {
+				items = append(items, Item{
+					ID:           item.ID + "." + arg,
+					Label:        item.Label + ": " + arg,
+					Description:  item.SlashCommand + " " + arg,
+					Category:     item.Category,
+					SlashCommand: item.SlashCommand,
+					Execute: func(string) tea.Cmd {
+						return item.Execute(arg)
+					},
+				})
+			}
+		}
+		expanded = append(expanded, Category{Name: cat.Name, Commands: items})
+	}
+	return expanded
 }
 
 // ParseSlashCommand checks if the input matches a known slash command and returns
 // the tea.Cmd to execute it. Returns nil if not a slash command or not recognized.
-// This function only handles built-in session commands, not agent commands or MCP prompts.
+// This function only handles built-in session commands and registered commands,
+// not agent commands or MCP prompts.
 func ParseSlashCommand(input string) tea.Cmd {
 	if input == "" || input[0] != '/' {
 		return nil
@@ -329,8 +570,8 @@ func ParseSlashCommand(input string) tea.Cmd {
 	// Split into command and argument
 	cmd, arg, _ := strings.Cut(input, " ")
 
-	// Search through built-in commands
-	for _, item := range builtInSessionCommands() {
+	// Search through built-in commands, then registered ones
+	for _, item := range append(builtInSessionCommands(), registeredCommands()...) {
 		if item.SlashCommand == cmd {
 			return item.Execute(arg)
 		}
@@ -0,0 +1,118 @@
+package commands
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/app"
+	mcptools "github.com/docker/cagent/pkg/tools/mcp"
+	"github.com/docker/cagent/pkg/tui/core"
+	"github.com/docker/cagent/pkg/tui/messages"
+)
+
+func TestParseSlashCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input string
+		want  tea.Msg
+	}{
+		{input: "/agent reviewer", want: messages.SwitchAgentMsg{AgentName: "reviewer"}},
+		{input: "/agent", want: messages.SwitchAgentMsg{}},
+		{input: "/resume", want: messages.ResumeSessionMsg{}},
+		{input: "/resume 1234", want: messages.ResumeSessionMsg{SessionID: "1234"}},
+		{input: "/clear", want: messages.NewSessionMsg{}},
+		{input: "/compact keep the todos", want: messages.CompactSessionMsg{AdditionalPrompt: "keep the todos"}},
+		{input: "/export out.html", want: messages.ExportSessionMsg{Filename: "out.html"}},
+	}
+	for _, tt := range tests {
+		cmd := ParseSlashCommand(tt.input)
+		require.NotNil(t, cmd, tt.input)
+		assert.Equal(t, tt.want, cmd(), tt.input)
+	}
+
+	assert.Nil(t, ParseSlashCommand("hello"))
+	assert.Nil(t, ParseSlashCommand("/unknown"))
+}
+
+func TestRegister(t *testing.T) {
+	t.Parallel()
+
+	type pingMsg struct{ arg string }
+	Register(Item{
+		ID:           "test.ping",
+		Label:        "Ping",
+		SlashCommand: "/test-ping",
+		Category:     "Test",
+		Execute: func(arg string) tea.Cmd {
+			return core.CmdHandler(pingMsg{arg: arg})
+		},
+	})
+
+	cmd := ParseSlashCommand("/test-ping pong")
+	require.NotNil(t, cmd)
+	assert.Equal(t, pingMsg{arg: "pong"}, cmd())
+}
+
+func TestExpandArguments(t *testing.T) {
+	t.Parallel()
+
+	categories := []Category{{
+		Name: "Session",
+		Commands: []Item{{
+			ID:           "session.theme",
+			Label:        "Theme",
+			SlashCommand: "/theme",
+			Category:     "Session",
+			Execute: func(arg string) tea.Cmd {
+				return core.CmdHandler(messages.ChangeThemeMsg{Name: arg})
+			},
+			Arguments: func(*app.App) []string { return []string{"dark", "light"} },
+		}},
+	}}
+
+	expanded := ExpandArguments(categories, nil)
+	require.Len(t, expanded, 1)
+	require.Len(t, expanded[0].Commands, 3)
+
+	light := expanded[0].Commands[2]
+	assert.Equal(t, "Theme: light", light.Label)
+	assert.Equal(t, "/theme light", light.Description)
+	assert.Equal(t, messages.ChangeThemeMsg{Name: "light"}, light.Execute("")())
+}
+
+func TestMCPPromptCommand(t *testing.T) {
+	t.Parallel()
+
+	review := mcptools.PromptInfo{
+		Name: "review",
+		Arguments: []mcptools.PromptArgument{
+			{Name: "pr", Required: true},
+			{Name: "focus"},
+		},
+	}
+	item := mcpPromptCommand("review", review)
+	assert.Equal(t, "/review", item.SlashCommand)
+	assert.Equal(t, "(1 required arg)", item.Description)
+
+	assert.Equal(t, messages.MCPPromptMsg{
+		PromptName: "review",
+		Arguments:  map[string]string{"pr": "123", "focus": "security"},
+	}, item.Execute("pr=123 focus=security")())
+
+	// A missing required argument is asked for
+	assert.Equal(t, messages.ShowMCPPromptInputMsg{PromptName: "review", PromptInfo: review}, item.Execute("focus=security")())
+}
+
+func TestParsePromptArguments(t *testing.T) {
+	t.Parallel()
+
+	single := []mcptools.PromptArgument{{Name: "topic"}}
+	assert.Equal(t, map[string]string{"topic": "error handling"}, parsePromptArguments("error handling", single))
+	assert.Equal(t, map[string]string{"topic": "go"}, parsePromptArguments("topic=go", single))
+	assert.Equal(t, map[string]string{"topic": "a=b"}, parsePromptArguments("a=b", single))
+	assert.Empty(t, parsePromptArguments("  ", single))
+}
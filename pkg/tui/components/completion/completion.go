@@ -34,6 +34,7 @@ type Item struct {
 	Value       string
 	Execute     func() tea.Cmd
 	Pinned      bool // Pinned items always appear at the top, in original order
+	Argument    bool // Argument items only appear once the user starts typing a query
 }
 
 type OpenMsg struct {
@@ -298,7 +299,7 @@ func (c *manager) notifySelectionChanged() tea.Cmd {
 
 func (c *manager) filterItems(query string) {
 	if query == "" {
-		c.filteredItems = c.items
+		c.filteredItems = slices.DeleteFunc(slices.Clone(c.items), func(item Item) bool { return item.Argument })
 		// Reset selection when clearing the query
 		if c.selected >= len(c.filteredItems) {
 			c.selected = max(0, len(c.filteredItems)-1)
@@ -88,3 +88,27 @@ func TestCompletionManagerStaysOpenWithNoResults(t *testing.T) {
 		assert.Contains(t, view, "No command found", "should show no results message")
 	})
 }
+
+func TestCompletionManagerArgumentItems(t *testing.T) {
+	t.Parallel()
+
+	m := New().(*manager)
+	m.Update(OpenMsg{
+		Items: []Item{
+			{Label: "agent", Value: "/agent"},
+			{Label: "agent reviewer", Value: "/agent reviewer", Argument: true},
+			{Label: "exit", Value: "/exit"},
+		},
+		MatchMode: MatchPrefix,
+	})
+
+	assert.Len(t, m.filteredItems, 2, "argument items are hidden without a query")
+
+	m.Update(QueryMsg{Query: "ag"})
+	assert.Len(t, m.filteredItems, 2)
+	assert.Equal(t, "agent", m.filteredItems[0].Label)
+	assert.Equal(t, "agent reviewer", m.filteredItems[1].Label)
+
+	m.Update(QueryMsg{Query: ""})
+	assert.Len(t, m.filteredItems, 2)
+}
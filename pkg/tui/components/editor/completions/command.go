@@ -2,6 +2,7 @@ package completions
 
 import (
 	"context"
+	"strings"
 
 	"github.com/docker/cagent/pkg/app"
 	"github.com/docker/cagent/pkg/tui/commands"
@@ -40,6 +41,17 @@ func (c *commandCompletion) Items() []completion.Item {
 				Description: command.Description,
 				Value:       command.SlashCommand,
 			})
+			if command.Arguments == nil || command.SlashCommand == "" {
+				continue
+			}
+			for _, arg := range command.Arguments(c.app) {
+				items = append(items, completion.Item{
+					Label:       strings.TrimPrefix(command.SlashCommand, "/") + " " + arg,
+					Description: command.Label,
+					Value:       command.SlashCommand + " " + arg,
+					Argument:    true,
+				})
+			}
 		}
 	}
 
@@ -5,10 +5,21 @@ import (
 	"github.com/docker/cagent/pkg/tui/components/completion"
 )
 
-type fileCompletion struct{}
+type fileCompletion struct {
+	index *fsx.FileIndex
+}
+
+// workingDirIndex is shared by the editors of every session, so that the
+// working directory is only walked once in a while.
+var workingDirIndex = fsx.NewFileIndex(".")
 
 func NewFileCompletion() Completion {
-	return &fileCompletion{}
+	// Warm the index so that the first @ doesn't wait for the walk
+	go workingDirIndex.Files()
+
+	return &fileCompletion{
+		index: workingDirIndex,
+	}
 }
 
 func (c *fileCompletion) AutoSubmit() bool {
@@ -24,21 +35,7 @@ func (c *fileCompletion) Trigger() string {
 }
 
 func (c *fileCompletion) Items() []completion.Item {
-	// Try to create VCS matcher for current directory
-	vcsMatcher, err := fsx.NewVCSMatcher(".")
-
-	// Prepare shouldIgnore function
-	var shouldIgnore func(string) bool
-	if err == nil && vcsMatcher != nil {
-		shouldIgnore = vcsMatcher.ShouldIgnore
-	}
-	// If vcsMatcher is nil (not in git repo), shouldIgnore stays nil = show all files
-
-	// Get files with optional VCS filtering
-	files, err := fsx.ListDirectory(".", shouldIgnore)
-	if err != nil {
-		return nil
-	}
+	files := c.index.Files()
 
 	items := make([]completion.Item, len(files))
 	for i, f := range files {
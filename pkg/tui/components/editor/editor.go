@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -25,6 +26,8 @@ import (
 	"github.com/docker/cagent/pkg/tui/components/editor/completions"
 	"github.com/docker/cagent/pkg/tui/core"
 	"github.com/docker/cagent/pkg/tui/core/layout"
+	"github.com/docker/cagent/pkg/tui/keys"
+	"github.com/docker/cagent/pkg/tui/messages"
 	"github.com/docker/cagent/pkg/tui/styles"
 )
 
@@ -86,6 +89,14 @@ type Editor interface {
 	IsRecording() bool
 	// SendContent triggers sending the current editor content
 	SendContent() tea.Cmd
+	// IsSearchingHistory returns true while a reverse search through the prompt history is active
+	IsSearchingHistory() bool
+	// SetVimMode enables or disables the vim mode, with its normal, insert and command modes
+	SetVimMode(enabled bool)
+	// Mode returns the mode of the editor, always insert outside of the vim mode
+	Mode() Mode
+	// SetMode switches the editor to a mode of the vim mode
+	SetMode(mode Mode)
 }
 
 // editor implements [Editor]
@@ -121,6 +132,12 @@ type editor struct {
 	recording bool
 	// recordingDotPhase tracks the animation phase for the recording dots cursor
 	recordingDotPhase int
+	// search is the reverse search through the prompt history, nil when inactive
+	search *historySearch
+	// historySearchKey starts a reverse search, and shows older matches during one
+	historySearchKey key.Binding
+	// vim is the state of the vim mode, nil when disabled
+	vim *vimState
 }
 
 // New creates a new editor component
@@ -141,6 +158,7 @@ func New(a *app.App, hist *history.History) Editor {
 		completions:                   completions.Completions(a),
 		keyboardEnhancementsSupported: false,
 		banner:                        newAttachmentBanner(),
+		historySearchKey:              keys.Get(keys.HistorySearch),
 	}
 
 	e.configureNewlineKeybinding()
@@ -515,16 +533,19 @@ func (e *editor) resetAndSend(content string) tea.Cmd {
 // configureNewlineKeybinding sets up the appropriate newline keybinding
 // based on terminal keyboard enhancement support.
 func (e *editor) configureNewlineKeybinding() {
-	// Configure textarea's InsertNewline binding based on terminal capabilities
-	if e.keyboardEnhancementsSupported {
-		// Modern terminals:
-		e.textarea.KeyMap.InsertNewline.SetKeys("shift+enter", "ctrl+j")
-		e.textarea.KeyMap.InsertNewline.SetEnabled(true)
-	} else {
-		// Legacy terminals:
-		e.textarea.KeyMap.InsertNewline.SetKeys("ctrl+j")
-		e.textarea.KeyMap.InsertNewline.SetEnabled(true)
+	e.textarea.KeyMap.InsertNewline.SetKeys(NewlineKeys(e.keyboardEnhancementsSupported)...)
+	e.textarea.KeyMap.InsertNewline.SetEnabled(true)
+}
+
+// NewlineKeys returns the keys that insert a newline in the prompt. Legacy
+// terminals can't tell Shift+Enter from Enter, so it is left out unless the
+// terminal supports keyboard enhancements.
+func NewlineKeys(keyboardEnhancementsSupported bool) []string {
+	newlineKeys := keys.Get(keys.Newline).Keys()
+	if keyboardEnhancementsSupported {
+		return newlineKeys
 	}
+	return slices.DeleteFunc(newlineKeys, func(k string) bool { return k == "shift+enter" })
 }
 
 // Update handles messages and updates the component state
@@ -546,6 +567,9 @@ func (e *editor) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
 		e.textarea.Placeholder = "🎤 Listening" + dots
 		cmd := e.tickRecordingDots()
 		return e, cmd
+	case messages.ThemeChangedMsg:
+		e.textarea.SetStyles(styles.InputStyle)
+		return e, nil
 	case tea.PasteMsg:
 		if e.handlePaste(msg.Content) {
 			return e, nil
@@ -631,6 +655,19 @@ func (e *editor) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
 		}
 		return e, nil
 	case tea.KeyPressMsg:
+		if e.vim != nil && e.search == nil {
+			if cmd, handled := e.handleVimKey(msg); handled {
+				return e, cmd
+			}
+		}
+		if e.search != nil && e.handleHistorySearchKey(msg) {
+			return e, nil
+		}
+		if key.Matches(msg, e.historySearchKey) {
+			e.startHistorySearch()
+			return e, nil
+		}
+
 		if key.Matches(msg, e.textarea.KeyMap.Paste) {
 			return e.handleClipboardPaste()
 		}
@@ -645,6 +682,7 @@ func (e *editor) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
 		// Handle send/newline keys:
 		// - Enter: submit current input (if textarea inserted a newline, submit previous buffer).
 		// - Shift+Enter: insert newline when keyboard enhancements are supported.
+		// - Alt+Enter: insert newline, on every terminal.
 		// - Ctrl+J: fallback to insert '\n' when keyboard enhancements are not supported.
 		if msg.String() == "enter" || key.Matches(msg, e.textarea.KeyMap.InsertNewline) {
 			if !e.textarea.Focused() {
@@ -928,12 +966,22 @@ func (e *editor) getPasteCompletionItems() []completion.Item {
 
 // View renders the component
 func (e *editor) View() string {
+	defer core.TrackRender("editor")()
+
 	view := e.textarea.View()
 
 	if e.hasSuggestion && e.suggestion != "" {
 		view = e.applySuggestionOverlay(view)
 	}
 
+	if e.search != nil {
+		view = lipgloss.JoinVertical(lipgloss.Left, e.historySearchView(), view)
+	}
+
+	if e.Mode() == ModeCommand {
+		view = lipgloss.JoinVertical(lipgloss.Left, e.commandLineView(), view)
+	}
+
 	bannerView := e.banner.View()
 	if bannerView != "" {
 		// Banner is shown - no extra top padding needed
@@ -959,6 +1007,12 @@ func (e *editor) updateTextareaHeight() {
 	if e.banner != nil {
 		available -= e.banner.Height()
 	}
+	if e.search != nil {
+		available--
+	}
+	if e.Mode() == ModeCommand {
+		available--
+	}
 
 	available = max(available, 1)
 
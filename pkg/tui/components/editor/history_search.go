@@ -0,0 +1,112 @@
+package editor
+
+import (
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/docker/cagent/pkg/tui/styles"
+)
+
+// historySearch is the state of a reverse search through the prompt history.
+type historySearch struct {
+	// draft is the editor content when the search started, restored on cancel.
+	draft   string
+	query   string
+	results []string
+	// index is the position of the displayed result in results.
+	index int
+}
+
+// IsSearchingHistory returns true while a reverse search through the prompt history is active.
+func (e *editor) IsSearchingHistory() bool {
+	return e.search != nil
+}
+
+// startHistorySearch starts a reverse search through the prompt history.
+func (e *editor) startHistorySearch() {
+	if e.hist == nil {
+		return
+	}
+
+	e.search = &historySearch{draft: e.textarea.Value()}
+	e.clearSuggestion()
+	e.updateSearchResults()
+	e.updateTextareaHeight()
+}
+
+// handleHistorySearchKey handles a key press during a reverse search. Keys
+// that aren't part of the search accept the displayed prompt and return false
+// so that they are handled as usual.
+func (e *editor) handleHistorySearchKey(msg tea.KeyPressMsg) bool {
+	if key.Matches(msg, e.historySearchKey) {
+		// Show the next, older, match
+		if e.search.index < len(e.search.results)-1 {
+			e.search.index++
+			e.showSearchResult()
+		}
+		return true
+	}
+
+	switch msg.String() {
+	case "esc", "ctrl+g":
+		e.textarea.SetValue(e.search.draft)
+		e.textarea.MoveToEnd()
+		e.stopHistorySearch()
+		return true
+	case "enter":
+		e.stopHistorySearch()
+		return true
+	case "backspace":
+		if e.search.query != "" {
+			runes := []rune(e.search.query)
+			e.search.query = string(runes[:len(runes)-1])
+			e.updateSearchResults()
+		}
+		return true
+	}
+
+	// Typed characters refine the query
+	if msg.Text != "" && msg.Mod&^tea.ModShift == 0 {
+		e.search.query += msg.Text
+		e.updateSearchResults()
+		return true
+	}
+
+	e.stopHistorySearch()
+	return false
+}
+
+func (e *editor) updateSearchResults() {
+	e.search.results = e.hist.Search(e.search.query)
+	e.search.index = 0
+	e.showSearchResult()
+}
+
+func (e *editor) showSearchResult() {
+	value := e.search.draft
+	if e.search.query != "" && len(e.search.results) > 0 {
+		value = e.search.results[e.search.index]
+	}
+	e.textarea.SetValue(value)
+	e.textarea.MoveToEnd()
+}
+
+// stopHistorySearch ends the search, keeping the displayed prompt in the editor.
+func (e *editor) stopHistorySearch() {
+	e.search = nil
+	e.userTyped = e.textarea.Value() != ""
+	e.updateTextareaHeight()
+}
+
+// historySearchView renders the search bar shown above the input.
+func (e *editor) historySearchView() string {
+	status := ""
+	switch {
+	case e.search.query == "":
+	case len(e.search.results) == 0:
+		status = styles.WarningStyle.Render("  no match")
+	default:
+		status = styles.MutedStyle.Render("  " + e.historySearchKey.Help().Key + " older · enter accept · esc cancel")
+	}
+	return styles.MutedStyle.Render("history search: ") + e.search.query + status
+}
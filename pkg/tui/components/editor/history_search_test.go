@@ -0,0 +1,77 @@
+package editor
+
+import (
+	"testing"
+
+	"charm.land/bubbles/v2/textarea"
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/history"
+	"github.com/docker/cagent/pkg/tui/keys"
+)
+
+func newHistorySearchEditor(t *testing.T, prompts ...string) *editor {
+	t.Helper()
+
+	hist, err := history.New(history.WithBaseDir(t.TempDir()))
+	require.NoError(t, err)
+	for _, prompt := range prompts {
+		require.NoError(t, hist.Add(prompt))
+	}
+
+	ta := textarea.New()
+	ta.SetWidth(40)
+	ta.SetHeight(5)
+	ta.Focus()
+
+	return &editor{textarea: ta, hist: hist, banner: newAttachmentBanner(), historySearchKey: keys.Get(keys.HistorySearch)}
+}
+
+func typeKeys(e *editor, text string) {
+	for _, r := range text {
+		e.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+}
+
+func TestHistorySearch(t *testing.T) {
+	t.Parallel()
+
+	e := newHistorySearchEditor(t, "run the tests", "explain the readme", "fix the failing test")
+	e.textarea.SetValue("draft")
+
+	e.Update(tea.KeyPressMsg{Code: 'r', Mod: tea.ModCtrl})
+	require.True(t, e.IsSearchingHistory())
+	assert.Equal(t, "draft", e.Value(), "the draft stays until a query is typed")
+
+	typeKeys(e, "test")
+	assert.Equal(t, "fix the failing test", e.Value())
+	assert.Contains(t, ansi.Strip(e.View()), "history search: test")
+
+	// Ctrl+R shows older matches
+	e.Update(tea.KeyPressMsg{Code: 'r', Mod: tea.ModCtrl})
+	assert.Equal(t, "run the tests", e.Value())
+
+	// Enter accepts the match without sending it
+	_, cmd := e.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	assert.Nil(t, cmd)
+	assert.False(t, e.IsSearchingHistory())
+	assert.Equal(t, "run the tests", e.Value())
+}
+
+func TestHistorySearchCancel(t *testing.T) {
+	t.Parallel()
+
+	e := newHistorySearchEditor(t, "run the tests")
+	e.textarea.SetValue("draft")
+
+	e.Update(tea.KeyPressMsg{Code: 'r', Mod: tea.ModCtrl})
+	typeKeys(e, "run")
+	assert.Equal(t, "run the tests", e.Value())
+
+	e.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	assert.False(t, e.IsSearchingHistory())
+	assert.Equal(t, "draft", e.Value())
+}
@@ -0,0 +1,30 @@
+package editor
+
+import (
+	"testing"
+
+	"charm.land/bubbles/v2/textarea"
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAltEnterInsertsNewline(t *testing.T) {
+	t.Parallel()
+
+	for _, enhanced := range []bool{false, true} {
+		ta := textarea.New()
+		ta.SetWidth(40)
+		ta.SetHeight(5)
+		ta.Focus()
+
+		e := &editor{textarea: ta, userTyped: true, keyboardEnhancementsSupported: enhanced}
+		e.configureNewlineKeybinding()
+		e.textarea.SetValue("first line")
+		e.textarea.MoveToEnd()
+
+		_, cmd := e.Update(tea.KeyPressMsg{Code: tea.KeyEnter, Mod: tea.ModAlt})
+
+		assert.Nil(t, cmd, "alt+enter must not send the message")
+		assert.Equal(t, "first line\n", e.textarea.Value())
+	}
+}
@@ -0,0 +1,248 @@
+package editor
+
+import (
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/docker/cagent/pkg/tui/core"
+	"github.com/docker/cagent/pkg/tui/styles"
+)
+
+// Mode is the mode of the editor. Outside of the vim mode, the editor is
+// always in insert mode.
+type Mode int
+
+const (
+	// ModeInsert types text
+	ModeInsert Mode = iota
+	// ModeNormal moves the cursor and edits the prompt with vim commands
+	ModeNormal
+	// ModeCommand types a command after ':', run as a slash command
+	ModeCommand
+)
+
+// exAliases maps the usual vim commands to the slash commands doing the same.
+var exAliases = map[string]string{
+	"q":    "exit",
+	"q!":   "exit",
+	"qa":   "exit",
+	"quit": "exit",
+	"w":    "export",
+}
+
+// vimState is the state of the vim mode of the editor.
+type vimState struct {
+	mode Mode
+	// pending is the first key of a two-key command, like "dd" or "gg"
+	pending string
+	// command is the command typed after ':'
+	command string
+}
+
+// SetVimMode enables or disables the vim mode. The editor starts in insert
+// mode.
+func (e *editor) SetVimMode(enabled bool) {
+	if !enabled {
+		e.vim = nil
+	} else if e.vim == nil {
+		e.vim = &vimState{}
+	}
+	e.updateTextareaHeight()
+}
+
+// Mode returns the mode of the editor.
+func (e *editor) Mode() Mode {
+	if e.vim == nil {
+		return ModeInsert
+	}
+	return e.vim.mode
+}
+
+// SetMode switches the editor to a mode of the vim mode.
+func (e *editor) SetMode(mode Mode) {
+	if e.vim == nil {
+		return
+	}
+	e.vim.mode = mode
+	e.vim.pending = ""
+	e.vim.command = ""
+	if mode != ModeInsert {
+		e.clearSuggestion()
+	}
+	e.updateTextareaHeight()
+}
+
+// handleVimKey handles a key press in vim mode. Keys it returns false for are
+// handled as in insert mode.
+func (e *editor) handleVimKey(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	switch e.vim.mode {
+	case ModeCommand:
+		return e.handleCommandLineKey(msg), true
+	case ModeNormal:
+		return e.handleNormalKey(msg)
+	}
+
+	if msg.String() == "esc" {
+		e.SetMode(ModeNormal)
+		// Like in vim, the cursor goes back onto the last character typed
+		e.press(tea.KeyPressMsg{Code: tea.KeyLeft})
+		return nil, true
+	}
+	return nil, false
+}
+
+func (e *editor) handleNormalKey(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	// Keys that don't type text, like enter or the arrows, work as usual
+	if msg.Text == "" {
+		if msg.String() == "backspace" {
+			e.press(tea.KeyPressMsg{Code: tea.KeyLeft})
+			return nil, true
+		}
+		return nil, false
+	}
+
+	k := e.vim.pending + msg.Text
+	e.vim.pending = ""
+
+	switch k {
+	case "i":
+		e.SetMode(ModeInsert)
+	case "a":
+		e.press(tea.KeyPressMsg{Code: tea.KeyRight})
+		e.SetMode(ModeInsert)
+	case "I":
+		e.textarea.CursorStart()
+		e.SetMode(ModeInsert)
+	case "A":
+		e.textarea.CursorEnd()
+		e.SetMode(ModeInsert)
+	case "o":
+		e.textarea.CursorEnd()
+		e.textarea.InsertString("\n")
+		e.SetMode(ModeInsert)
+	case "O":
+		e.textarea.CursorStart()
+		e.textarea.InsertString("\n")
+		e.textarea.CursorUp()
+		e.SetMode(ModeInsert)
+	case "h":
+		e.press(tea.KeyPressMsg{Code: tea.KeyLeft})
+	case "l":
+		e.press(tea.KeyPressMsg{Code: tea.KeyRight})
+	case "j":
+		// Like the arrows: through the prompt history until the prompt is edited
+		return e.updateKey(tea.KeyPressMsg{Code: tea.KeyDown}), true
+	case "k":
+		return e.updateKey(tea.KeyPressMsg{Code: tea.KeyUp}), true
+	case "w", "e":
+		e.press(tea.KeyPressMsg{Code: tea.KeyRight, Mod: tea.ModAlt})
+	case "b":
+		e.press(tea.KeyPressMsg{Code: tea.KeyLeft, Mod: tea.ModAlt})
+	case "0", "^":
+		e.textarea.CursorStart()
+	case "$":
+		e.textarea.CursorEnd()
+	case "gg":
+		e.textarea.MoveToBegin()
+	case "G":
+		e.textarea.MoveToEnd()
+	case "x":
+		e.press(tea.KeyPressMsg{Code: tea.KeyDelete})
+	case "X":
+		_, cmd := e.handleGraphemeBackspace()
+		return cmd, true
+	case "D":
+		e.press(tea.KeyPressMsg{Code: 'k', Mod: tea.ModCtrl})
+	case "C":
+		e.press(tea.KeyPressMsg{Code: 'k', Mod: tea.ModCtrl})
+		e.SetMode(ModeInsert)
+	case "dd":
+		e.deleteLine()
+	case "cc", "S":
+		e.textarea.CursorStart()
+		e.press(tea.KeyPressMsg{Code: 'k', Mod: tea.ModCtrl})
+		e.SetMode(ModeInsert)
+	case "p":
+		_, cmd := e.handleClipboardPaste()
+		return cmd, true
+	case ":":
+		e.SetMode(ModeCommand)
+	case "d", "c", "g":
+		e.vim.pending = k
+	}
+
+	// Other keys don't type anything in normal mode
+	e.userTyped = e.textarea.Value() != ""
+	return nil, true
+}
+
+// deleteLine deletes the line of the cursor, like "dd" in vim.
+func (e *editor) deleteLine() {
+	e.textarea.CursorStart()
+	e.press(tea.KeyPressMsg{Code: 'k', Mod: tea.ModCtrl})
+	if e.textarea.LineCount() <= 1 {
+		return
+	}
+	// Remove the line break too, joining the next line or the previous one
+	if e.textarea.Line() < e.textarea.LineCount()-1 {
+		e.press(tea.KeyPressMsg{Code: tea.KeyDelete})
+	} else {
+		e.press(tea.KeyPressMsg{Code: tea.KeyBackspace})
+		e.textarea.CursorStart()
+	}
+}
+
+func (e *editor) handleCommandLineKey(msg tea.KeyPressMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		e.SetMode(ModeNormal)
+	case "enter":
+		command := strings.TrimSpace(e.vim.command)
+		e.SetMode(ModeNormal)
+		if command != "" {
+			// The prompt being written is kept, the command is sent on its own
+			return core.CmdHandler(SendMsg{Content: slashCommand(command)})
+		}
+	case "backspace":
+		if e.vim.command == "" {
+			e.SetMode(ModeNormal)
+			return nil
+		}
+		runes := []rune(e.vim.command)
+		e.vim.command = string(runes[:len(runes)-1])
+	default:
+		if msg.Text != "" {
+			e.vim.command += msg.Text
+		}
+	}
+	return nil
+}
+
+// slashCommand returns the slash command run by a command typed after ':'.
+func slashCommand(command string) string {
+	name, arg, hasArg := strings.Cut(command, " ")
+	if alias, ok := exAliases[name]; ok {
+		name = alias
+	}
+	if hasArg {
+		return "/" + name + " " + arg
+	}
+	return "/" + name
+}
+
+// press sends a key to the textarea, bypassing the handling of the editor.
+func (e *editor) press(msg tea.KeyPressMsg) {
+	e.textarea, _ = e.textarea.Update(msg)
+}
+
+// updateKey handles a key as if it was pressed in insert mode.
+func (e *editor) updateKey(msg tea.KeyPressMsg) tea.Cmd {
+	_, cmd := e.Update(msg)
+	return cmd
+}
+
+// commandLineView renders the command line shown above the input.
+func (e *editor) commandLineView() string {
+	return styles.HighlightWhiteStyle.Render(":") + e.vim.command + styles.MutedStyle.Render("█")
+}
@@ -0,0 +1,97 @@
+package editor
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newVimEditor(t *testing.T) *editor {
+	t.Helper()
+
+	e := newHistorySearchEditor(t)
+	e.SetVimMode(true)
+	return e
+}
+
+func TestVimModes(t *testing.T) {
+	t.Parallel()
+
+	e := newVimEditor(t)
+	assert.Equal(t, ModeInsert, e.Mode())
+
+	typeKeys(e, "hello world")
+	e.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	assert.Equal(t, ModeNormal, e.Mode())
+
+	// Keys don't type in normal mode
+	typeKeys(e, "z")
+	assert.Equal(t, "hello world", e.Value())
+
+	// Back to the start of the line, then into insert mode
+	typeKeys(e, "0i")
+	assert.Equal(t, ModeInsert, e.Mode())
+	typeKeys(e, "> ")
+	assert.Equal(t, "> hello world", e.Value())
+
+	// "A" appends at the end of the line
+	e.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	typeKeys(e, "0A!")
+	assert.Equal(t, "> hello world!", e.Value())
+}
+
+func TestVimEdits(t *testing.T) {
+	t.Parallel()
+
+	e := newVimEditor(t)
+	e.textarea.SetValue("first\nsecond\nthird")
+	e.SetMode(ModeNormal)
+
+	typeKeys(e, "ggdd")
+	assert.Equal(t, "second\nthird", e.Value())
+
+	typeKeys(e, "x")
+	assert.Equal(t, "econd\nthird", e.Value())
+
+	typeKeys(e, "Gdd")
+	assert.Equal(t, "econd", e.Value())
+
+	typeKeys(e, "ccnew")
+	assert.Equal(t, ModeInsert, e.Mode())
+	assert.Equal(t, "new", e.Value())
+}
+
+func TestVimCommandLine(t *testing.T) {
+	t.Parallel()
+
+	e := newVimEditor(t)
+	typeKeys(e, "draft")
+	e.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+
+	typeKeys(e, ":q")
+	assert.Equal(t, ModeCommand, e.Mode())
+	assert.Contains(t, ansi.Strip(e.View()), ":q")
+
+	_, cmd := e.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	require.NotNil(t, cmd)
+	assert.Equal(t, SendMsg{Content: "/exit"}, cmd())
+	assert.Equal(t, ModeNormal, e.Mode())
+	assert.Equal(t, "draft", e.Value(), "the prompt being written is kept")
+
+	// Esc leaves the command line without running anything
+	typeKeys(e, ":compact")
+	_, cmd = e.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	assert.Nil(t, cmd)
+	assert.Equal(t, ModeNormal, e.Mode())
+}
+
+func TestSlashCommand(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "/exit", slashCommand("q!"))
+	assert.Equal(t, "/export out.html", slashCommand("w out.html"))
+	assert.Equal(t, "/compact", slashCommand("compact"))
+}
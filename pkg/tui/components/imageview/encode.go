@@ -0,0 +1,191 @@
+package imageview
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	_ "image/gif"  // register GIF decoder
+	_ "image/jpeg" // register JPEG decoder
+	"image/png"
+	"strings"
+
+	"github.com/docker/cagent/pkg/tools"
+)
+
+const (
+	kittyChunkSize = 4096
+	// cellPixelWidth is a rough estimate of a terminal cell's width, used to
+	// size sixel images which, unlike the other protocols, are sized in pixels.
+	cellPixelWidth = 10
+)
+
+// Encode returns the escape sequence that draws img with the given protocol,
+// scaled to at most cols terminal cells wide.
+func Encode(p Protocol, img tools.Image, cols int) (string, error) {
+	switch p {
+	case ProtocolKitty:
+		return encodeKitty(img, cols)
+	case ProtocolITerm2:
+		return encodeITerm2(img, cols), nil
+	case ProtocolSixel:
+		return encodeSixel(img, cols)
+	default:
+		return "", errors.New("terminal doesn't support images")
+	}
+}
+
+// Size returns the dimensions of img in pixels.
+func Size(img tools.Image) (width, height int, err error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(img.Data))
+	if err != nil {
+		return 0, 0, err
+	}
+	return cfg.Width, cfg.Height, nil
+}
+
+func encodeKitty(img tools.Image, cols int) (string, error) {
+	data := img.Data
+	if img.MimeType != "image/png" {
+		// Kitty only decodes PNG by itself; other formats are sent as PNG.
+		decoded, _, err := image.Decode(bytes.NewReader(img.Data))
+		if err != nil {
+			return "", fmt.Errorf("decoding image: %w", err)
+		}
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, decoded); err != nil {
+			return "", fmt.Errorf("encoding image: %w", err)
+		}
+		data = buf.Bytes()
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(data)
+
+	var sb strings.Builder
+	for i := 0; i < len(encoded); i += kittyChunkSize {
+		end := min(i+kittyChunkSize, len(encoded))
+		more := 0
+		if end < len(encoded) {
+			more = 1
+		}
+		if i == 0 {
+			fmt.Fprintf(&sb, "\x1b_Ga=T,f=100,q=2,c=%d,m=%d;", cols, more)
+		} else {
+			fmt.Fprintf(&sb, "\x1b_Gm=%d;", more)
+		}
+		sb.WriteString(encoded[i:end])
+		sb.WriteString("\x1b\\")
+	}
+	return sb.String(), nil
+}
+
+func encodeITerm2(img tools.Image, cols int) string {
+	return fmt.Sprintf("\x1b]1337;File=inline=1;size=%d;width=%d;preserveAspectRatio=1:%s\a",
+		len(img.Data), cols, base64.StdEncoding.EncodeToString(img.Data))
+}
+
+func encodeSixel(img tools.Image, cols int) (string, error) {
+	decoded, _, err := image.Decode(bytes.NewReader(img.Data))
+	if err != nil {
+		return "", fmt.Errorf("decoding image: %w", err)
+	}
+
+	src := scaleToWidth(decoded, cols*cellPixelWidth)
+	bounds := src.Bounds()
+	paletted := image.NewPaletted(bounds, palette.WebSafe)
+	draw.FloydSteinberg.Draw(paletted, bounds, src, bounds.Min)
+
+	return sixel(paletted), nil
+}
+
+// scaleToWidth downscales img with nearest-neighbour sampling so that it is
+// at most maxWidth pixels wide. Smaller images are returned unchanged.
+func scaleToWidth(img image.Image, maxWidth int) image.Image {
+	bounds := img.Bounds()
+	if maxWidth <= 0 || bounds.Dx() <= maxWidth {
+		return img
+	}
+
+	width := maxWidth
+	height := max(1, bounds.Dy()*maxWidth/bounds.Dx())
+	scaled := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		for x := range width {
+			scaled.Set(x, y, img.At(bounds.Min.X+x*bounds.Dx()/width, bounds.Min.Y+y*bounds.Dy()/height))
+		}
+	}
+	return scaled
+}
+
+// sixel encodes a paletted image as a DEC sixel sequence.
+func sixel(img *image.Paletted) string {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	var sb strings.Builder
+	sb.WriteString("\x1bPq")
+	fmt.Fprintf(&sb, "\"1;1;%d;%d", width, height)
+	for i, c := range img.Palette {
+		r, g, b := rgbPercent(c)
+		fmt.Fprintf(&sb, "#%d;2;%d;%d;%d", i, r, g, b)
+	}
+
+	row := make([]byte, width)
+	for band := 0; band < height; band += 6 {
+		used := make(map[uint8]bool)
+		for y := band; y < min(band+6, height); y++ {
+			for x := range width {
+				used[img.ColorIndexAt(bounds.Min.X+x, bounds.Min.Y+y)] = true
+			}
+		}
+
+		for idx := range len(img.Palette) {
+			colorIndex := uint8(idx)
+			if !used[colorIndex] {
+				continue
+			}
+			for x := range width {
+				var bits byte
+				for dy := range 6 {
+					y := band + dy
+					if y < height && img.ColorIndexAt(bounds.Min.X+x, bounds.Min.Y+y) == colorIndex {
+						bits |= 1 << dy
+					}
+				}
+				row[x] = '?' + bits
+			}
+			fmt.Fprintf(&sb, "#%d", colorIndex)
+			writeSixelRun(&sb, row)
+			sb.WriteByte('$')
+		}
+		sb.WriteByte('-')
+	}
+
+	sb.WriteString("\x1b\\")
+	return sb.String()
+}
+
+// writeSixelRun writes row using sixel run-length encoding.
+func writeSixelRun(sb *strings.Builder, row []byte) {
+	for i := 0; i < len(row); {
+		j := i
+		for j < len(row) && row[j] == row[i] {
+			j++
+		}
+		if n := j - i; n > 3 {
+			fmt.Fprintf(sb, "!%d%c", n, row[i])
+		} else {
+			sb.WriteString(strings.Repeat(string(row[i]), n))
+		}
+		i = j
+	}
+}
+
+func rgbPercent(c color.Color) (r, g, b int) {
+	cr, cg, cb, _ := c.RGBA()
+	return int(cr * 100 / 0xffff), int(cg * 100 / 0xffff), int(cb * 100 / 0xffff)
+}
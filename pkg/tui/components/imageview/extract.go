@@ -0,0 +1,89 @@
+package imageview
+
+import (
+	"encoding/base64"
+	"mime"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/docker/cagent/pkg/tools"
+	"github.com/docker/cagent/pkg/tui/types"
+)
+
+const (
+	maxExtractedImages = 4
+	maxImageFileSize   = 20 << 20
+)
+
+var dataURIPattern = regexp.MustCompile(`data:(image/[a-zA-Z0-9.+-]+);base64,([A-Za-z0-9+/]+={0,2})`)
+
+// LoadedMsg carries the images of a tool call, loaded by [LoadCmd].
+type LoadedMsg struct {
+	ToolCallID string
+	Images     []types.Image
+}
+
+// LoadCmd loads, off the UI goroutine, the images a tool call returned and
+// the ones referenced in its output, and decodes their dimensions.
+func LoadCmd(toolCallID, output string, returned []tools.Image) tea.Cmd {
+	return func() tea.Msg {
+		var images []types.Image
+		for _, img := range slices.Concat(returned, Extract(output)) {
+			width, height, _ := Size(img)
+			images = append(images, types.Image{Image: img, Width: width, Height: height})
+		}
+		return LoadedMsg{ToolCallID: toolCallID, Images: images}
+	}
+}
+
+// Extract finds images referenced in a tool's text output: base64 data URIs
+// and lines that consist of the path to an existing image file.
+func Extract(output string) []tools.Image {
+	var images []tools.Image
+
+	for _, match := range dataURIPattern.FindAllStringSubmatch(output, maxExtractedImages) {
+		data, err := base64.StdEncoding.DecodeString(match[2])
+		if err != nil {
+			continue
+		}
+		images = append(images, tools.Image{MimeType: match[1], Data: data})
+	}
+
+	for line := range strings.Lines(output) {
+		if len(images) >= maxExtractedImages {
+			break
+		}
+		if img, ok := readImageFile(strings.TrimSpace(line)); ok {
+			images = append(images, img)
+		}
+	}
+
+	return images
+}
+
+func readImageFile(path string) (tools.Image, bool) {
+	if path == "" || strings.ContainsAny(path, " \t") {
+		return tools.Image{}, false
+	}
+
+	mimeType := mime.TypeByExtension(strings.ToLower(filepath.Ext(path)))
+	if !strings.HasPrefix(mimeType, "image/") {
+		return tools.Image{}, false
+	}
+
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() || info.Size() > maxImageFileSize {
+		return tools.Image{}, false
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return tools.Image{}, false
+	}
+	return tools.Image{MimeType: mimeType, Data: data}, true
+}
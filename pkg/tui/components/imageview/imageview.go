@@ -0,0 +1,118 @@
+package imageview
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/docker/cagent/pkg/browser"
+	"github.com/docker/cagent/pkg/tools"
+	"github.com/docker/cagent/pkg/tui/components/notification"
+	"github.com/docker/cagent/pkg/tui/styles"
+	"github.com/docker/cagent/pkg/tui/types"
+)
+
+// maxViewColumns caps the width of images drawn by the viewer.
+const maxViewColumns = 120
+
+// Placeholder renders the line shown in the transcript in place of an image,
+// listing the actions available for it.
+func Placeholder(img types.Image, p Protocol) string {
+	description := img.MimeType
+	if img.Width > 0 && img.Height > 0 {
+		description += fmt.Sprintf(" · %d×%d", img.Width, img.Height)
+	}
+
+	var actions []string
+	if p != ProtocolNone {
+		actions = append(actions, styles.HighlightWhiteStyle.Render("v")+" "+styles.SecondaryStyle.Render("view"))
+	}
+	actions = append(actions, styles.HighlightWhiteStyle.Render("o")+" "+styles.SecondaryStyle.Render("open externally"))
+
+	return styles.ToolMessageStyle.Render("▣ image ") + styles.MutedStyle.Render(description) + "  " + strings.Join(actions, "  ")
+}
+
+// ViewCmd suspends the TUI and draws the images in the terminal until the user presses Enter.
+func ViewCmd(images []tools.Image, p Protocol, cols int) tea.Cmd {
+	if p == ProtocolNone {
+		return notification.WarningCmd("This terminal can't display images, press o to open them externally")
+	}
+
+	return tea.Exec(&viewer{images: images, protocol: p, cols: min(cols, maxViewColumns)}, func(err error) tea.Msg {
+		if err != nil {
+			return notification.ShowMsg{Text: fmt.Sprintf("Failed to display image: %v", err), Type: notification.TypeError}
+		}
+		return nil
+	})
+}
+
+// OpenCmd opens an image with the system's default viewer.
+func OpenCmd(img tools.Image) tea.Cmd {
+	return func() tea.Msg {
+		pattern := "cagent-image-*"
+		if exts, err := mime.ExtensionsByType(img.MimeType); err == nil && len(exts) > 0 {
+			pattern += exts[len(exts)-1]
+		}
+
+		f, err := os.CreateTemp("", pattern)
+		if err != nil {
+			return notification.ShowMsg{Text: fmt.Sprintf("Failed to create temp file: %v", err), Type: notification.TypeError}
+		}
+		defer f.Close()
+
+		if _, err := f.Write(img.Data); err != nil {
+			return notification.ShowMsg{Text: fmt.Sprintf("Failed to write image: %v", err), Type: notification.TypeError}
+		}
+
+		if err := browser.Open(context.Background(), f.Name()); err != nil {
+			return notification.ShowMsg{Text: fmt.Sprintf("Failed to open image: %v", err), Type: notification.TypeError}
+		}
+		return nil
+	}
+}
+
+// viewer is a [tea.ExecCommand] that draws images on the terminal while the TUI is suspended.
+type viewer struct {
+	images   []tools.Image
+	protocol Protocol
+	cols     int
+
+	stdin  io.Reader
+	stdout io.Writer
+}
+
+func (v *viewer) SetStdin(r io.Reader)  { v.stdin = r }
+func (v *viewer) SetStdout(w io.Writer) { v.stdout = w }
+func (v *viewer) SetStderr(io.Writer)   {}
+
+func (v *viewer) Run() error {
+	// Clear the screen and move the cursor home
+	if _, err := io.WriteString(v.stdout, "\x1b[2J\x1b[H"); err != nil {
+		return err
+	}
+
+	for _, img := range v.images {
+		seq, err := Encode(v.protocol, img, v.cols)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(v.stdout, seq+"\r\n\r\n"); err != nil {
+			return err
+		}
+	}
+
+	if _, err := io.WriteString(v.stdout, "Press Enter to return to cagent"); err != nil {
+		return err
+	}
+	_, err := bufio.NewReader(v.stdin).ReadString('\n')
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,158 @@
+package imageview
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/tools"
+)
+
+func testPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		for x := range width {
+			img.Set(x, y, color.RGBA{R: uint8(x * 10), G: uint8(y * 10), B: 200, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestDetectProtocol(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected Protocol
+	}{
+		{name: "unknown terminal", env: map[string]string{"TERM": "xterm-256color"}, expected: ProtocolNone},
+		{name: "kitty", env: map[string]string{"TERM": "xterm-kitty"}, expected: ProtocolKitty},
+		{name: "kitty window", env: map[string]string{"KITTY_WINDOW_ID": "1"}, expected: ProtocolKitty},
+		{name: "ghostty", env: map[string]string{"TERM_PROGRAM": "ghostty"}, expected: ProtocolKitty},
+		{name: "iTerm2", env: map[string]string{"TERM_PROGRAM": "iTerm.app"}, expected: ProtocolITerm2},
+		{name: "foot", env: map[string]string{"TERM": "foot-extra"}, expected: ProtocolSixel},
+		{name: "tmux", env: map[string]string{"TERM_PROGRAM": "iTerm.app", "TMUX": "/tmp/tmux"}, expected: ProtocolNone},
+		{name: "override", env: map[string]string{"TERM": "xterm", "CAGENT_IMAGE_PROTOCOL": "sixel"}, expected: ProtocolSixel},
+		{name: "disabled", env: map[string]string{"TERM": "xterm-kitty", "CAGENT_IMAGE_PROTOCOL": "none"}, expected: ProtocolNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, detectProtocol(func(key string) string { return tt.env[key] }))
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	t.Parallel()
+
+	img := tools.Image{MimeType: "image/png", Data: testPNG(t, 12, 8)}
+
+	kitty, err := Encode(ProtocolKitty, img, 40)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(kitty, "\x1b_Ga=T,f=100,q=2,c=40,m=0;"))
+	assert.True(t, strings.HasSuffix(kitty, "\x1b\\"))
+
+	iterm, err := Encode(ProtocolITerm2, img, 40)
+	require.NoError(t, err)
+	assert.Contains(t, iterm, "\x1b]1337;File=inline=1;")
+	assert.Contains(t, iterm, base64.StdEncoding.EncodeToString(img.Data))
+
+	sixel, err := Encode(ProtocolSixel, img, 40)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(sixel, "\x1bPq\"1;1;12;8"))
+	assert.True(t, strings.HasSuffix(sixel, "-\x1b\\"))
+	assert.Equal(t, 2, strings.Count(sixel, "-"), "8 rows make two sixel bands")
+
+	_, err = Encode(ProtocolNone, img, 40)
+	assert.Error(t, err)
+}
+
+func TestEncodeKittyChunksLargeImages(t *testing.T) {
+	t.Parallel()
+
+	img := tools.Image{MimeType: "image/png", Data: bytes.Repeat([]byte{1}, 3*kittyChunkSize)}
+
+	kitty, err := Encode(ProtocolKitty, img, 40)
+	require.NoError(t, err)
+	assert.Equal(t, 3, strings.Count(kitty, "m=1;"))
+	assert.Equal(t, 1, strings.Count(kitty, "m=0;"))
+}
+
+func TestScaleToWidth(t *testing.T) {
+	t.Parallel()
+
+	img := image.NewRGBA(image.Rect(0, 0, 400, 200))
+	scaled := scaleToWidth(img, 100)
+	assert.Equal(t, 100, scaled.Bounds().Dx())
+	assert.Equal(t, 50, scaled.Bounds().Dy())
+
+	assert.Same(t, img, scaleToWidth(img, 1000))
+}
+
+func TestExtract(t *testing.T) {
+	t.Parallel()
+
+	data := testPNG(t, 2, 2)
+	path := filepath.Join(t.TempDir(), "screenshot.png")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	output := "Here is the chart: data:image/png;base64," + base64.StdEncoding.EncodeToString(data) + "\n" +
+		path + "\n" +
+		"Saved to " + path + "\n" +
+		"/does/not/exist.png\n"
+
+	images := Extract(output)
+	require.Len(t, images, 2)
+	for _, img := range images {
+		assert.Equal(t, "image/png", img.MimeType)
+		assert.Equal(t, data, img.Data)
+	}
+
+	assert.Empty(t, Extract("no images here"))
+}
+
+func TestLoadCmd(t *testing.T) {
+	t.Parallel()
+
+	returned := tools.Image{MimeType: "image/png", Data: testPNG(t, 12, 8)}
+	output := "data:image/png;base64," + base64.StdEncoding.EncodeToString([]byte("not an image"))
+
+	msg := LoadCmd("call-1", output, []tools.Image{returned})()
+	loaded, ok := msg.(LoadedMsg)
+	require.True(t, ok)
+	assert.Equal(t, "call-1", loaded.ToolCallID)
+	require.Len(t, loaded.Images, 2)
+	assert.Equal(t, returned, loaded.Images[0].Image)
+	assert.Equal(t, 12, loaded.Images[0].Width)
+	assert.Equal(t, 8, loaded.Images[0].Height)
+	assert.Zero(t, loaded.Images[1].Width)
+}
+
+func TestSize(t *testing.T) {
+	t.Parallel()
+
+	width, height, err := Size(tools.Image{MimeType: "image/png", Data: testPNG(t, 12, 8)})
+	require.NoError(t, err)
+	assert.Equal(t, 12, width)
+	assert.Equal(t, 8, height)
+
+	_, _, err = Size(tools.Image{MimeType: "image/png", Data: []byte("not an image")})
+	assert.Error(t, err)
+}
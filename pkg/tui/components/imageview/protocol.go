@@ -0,0 +1,72 @@
+package imageview
+
+import (
+	"os"
+	"strings"
+)
+
+// Protocol is a terminal graphics protocol used to draw images.
+type Protocol int
+
+const (
+	// ProtocolNone means the terminal can't draw images.
+	ProtocolNone Protocol = iota
+	ProtocolKitty
+	ProtocolITerm2
+	ProtocolSixel
+)
+
+func (p Protocol) String() string {
+	switch p {
+	case ProtocolKitty:
+		return "kitty"
+	case ProtocolITerm2:
+		return "iterm2"
+	case ProtocolSixel:
+		return "sixel"
+	default:
+		return "none"
+	}
+}
+
+// DetectProtocol guesses the graphics protocol supported by the current terminal.
+func DetectProtocol() Protocol {
+	return detectProtocol(os.Getenv)
+}
+
+// detectProtocol guesses the graphics protocol from the environment.
+// CAGENT_IMAGE_PROTOCOL (kitty, iterm2, sixel or none) overrides the detection.
+func detectProtocol(getenv func(string) string) Protocol {
+	switch strings.ToLower(getenv("CAGENT_IMAGE_PROTOCOL")) {
+	case "kitty":
+		return ProtocolKitty
+	case "iterm2":
+		return ProtocolITerm2
+	case "sixel":
+		return ProtocolSixel
+	case "none":
+		return ProtocolNone
+	}
+
+	// Multiplexers don't forward graphics sequences.
+	if getenv("TMUX") != "" || strings.HasPrefix(getenv("TERM"), "screen") {
+		return ProtocolNone
+	}
+
+	term := getenv("TERM")
+	switch getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm":
+		return ProtocolITerm2
+	case "ghostty":
+		return ProtocolKitty
+	}
+
+	switch {
+	case getenv("KITTY_WINDOW_ID") != "", term == "xterm-kitty", term == "xterm-ghostty":
+		return ProtocolKitty
+	case strings.HasPrefix(term, "foot"), strings.HasPrefix(term, "mlterm"), strings.Contains(term, "sixel"):
+		return ProtocolSixel
+	}
+
+	return ProtocolNone
+}
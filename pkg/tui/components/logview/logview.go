@@ -0,0 +1,131 @@
+// Package logview shows the latest logs of cagent in a pane of the TUI.
+package logview
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/docker/cagent/pkg/logging"
+	"github.com/docker/cagent/pkg/tui/styles"
+)
+
+// refreshInterval is how often the pane shows the new records.
+const refreshInterval = 500 * time.Millisecond
+
+var nextID atomic.Uint64
+
+// TickMsg refreshes the pane it was scheduled by.
+type TickMsg struct {
+	ID uint64
+}
+
+// Model is a pane tailing the records of a log ring, at a level or above.
+type Model struct {
+	id            uint64
+	ring          *logging.Ring
+	level         slog.Level
+	width, height int
+}
+
+// New creates a pane showing the records of ring at level or above.
+func New(ring *logging.Ring, level slog.Level) *Model {
+	return &Model{
+		id:    nextID.Add(1),
+		ring:  ring,
+		level: level,
+	}
+}
+
+// ParseLevel parses the name of a level: debug, info, warn or error.
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return 0, fmt.Errorf("unknown log level %q, use debug, info, warn or error", name)
+	}
+	return level, nil
+}
+
+// Level returns the level of the records shown.
+func (m *Model) Level() slog.Level {
+	return m.level
+}
+
+// SetLevel sets the level of the records shown.
+func (m *Model) SetLevel(level slog.Level) {
+	m.level = level
+}
+
+// SetSize sets the size of the pane.
+func (m *Model) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// Tick schedules the next refresh of the pane.
+func (m *Model) Tick() tea.Cmd {
+	id := m.id
+	return tea.Tick(refreshInterval, func(time.Time) tea.Msg {
+		return TickMsg{ID: id}
+	})
+}
+
+// Update schedules the next refresh when the pane is refreshed. Ticks of
+// panes that were closed are dropped.
+func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	if tick, ok := msg.(TickMsg); ok && tick.ID == m.id {
+		return m.Tick()
+	}
+	return nil
+}
+
+// View renders a title line and the latest records, one per line.
+func (m *Model) View() string {
+	if m.height <= 0 {
+		return ""
+	}
+
+	title := styles.HighlightWhiteStyle.Render("Logs") +
+		styles.MutedStyle.Render(fmt.Sprintf(" %s and above · /logs debug|info|warn|error to filter, /logs to hide", m.level))
+	lines := []string{ansi.Truncate(title, m.width, "…")}
+
+	entries := m.ring.Entries(m.level)
+	if len(entries) == 0 {
+		lines = append(lines, styles.MutedStyle.Render("No logs yet"))
+	}
+	entries = entries[max(0, len(entries)-(m.height-1)):]
+	for _, entry := range entries {
+		lines = append(lines, ansi.Truncate(m.renderEntry(entry), m.width, "…"))
+	}
+
+	return lipgloss.NewStyle().Width(m.width).Height(m.height).Render(strings.Join(lines, "\n"))
+}
+
+func (m *Model) renderEntry(entry logging.Entry) string {
+	line := styles.MutedStyle.Render(entry.Time.Format("15:04:05")) + " " +
+		levelStyle(entry.Level).Render(fmt.Sprintf("%-5s", entry.Level)) + " " +
+		strings.ReplaceAll(entry.Message, "\n", " ")
+	if entry.Attrs != "" {
+		line += " " + styles.MutedStyle.Render(strings.ReplaceAll(entry.Attrs, "\n", " "))
+	}
+	return line
+}
+
+func levelStyle(level slog.Level) lipgloss.Style {
+	switch {
+	case level >= slog.LevelError:
+		return styles.ErrorStyle
+	case level >= slog.LevelWarn:
+		return styles.WarningStyle
+	case level >= slog.LevelInfo:
+		return styles.InfoStyle
+	default:
+		return styles.MutedStyle
+	}
+}
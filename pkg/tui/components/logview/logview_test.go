@@ -0,0 +1,59 @@
+package logview
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/logging"
+)
+
+func TestView(t *testing.T) {
+	t.Parallel()
+
+	ring := logging.NewRing(10)
+	logger := slog.New(ring.Handler(slog.LevelDebug))
+	logger.Debug("initializing MCP toolset", "server", "github")
+	logger.Info("MCP toolset ready")
+	logger.Warn("retrying request", "status", 429)
+	logger.Error("request failed", "error", "timeout")
+
+	m := New(ring, slog.LevelInfo)
+	m.SetSize(80, 3)
+
+	lines := strings.Split(ansi.Strip(m.View()), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[0], "INFO and above")
+	// Only the latest records fit
+	assert.Contains(t, lines[1], "WARN  retrying request status=429")
+	assert.Contains(t, lines[2], "ERROR request failed error=timeout")
+
+	m.SetLevel(slog.LevelDebug)
+	m.SetSize(80, 10)
+	assert.Contains(t, ansi.Strip(m.View()), "initializing MCP toolset server=github")
+}
+
+func TestTicksOfClosedPanes(t *testing.T) {
+	t.Parallel()
+
+	closed := New(logging.NewRing(1), slog.LevelInfo)
+	m := New(logging.NewRing(1), slog.LevelInfo)
+
+	assert.Nil(t, m.Update(TickMsg{ID: closed.id}))
+	assert.NotNil(t, m.Update(TickMsg{ID: m.id}))
+}
+
+func TestParseLevel(t *testing.T) {
+	t.Parallel()
+
+	level, err := ParseLevel("warn")
+	require.NoError(t, err)
+	assert.Equal(t, slog.LevelWarn, level)
+
+	_, err = ParseLevel("verbose")
+	require.Error(t, err)
+}
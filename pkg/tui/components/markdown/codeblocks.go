@@ -0,0 +1,48 @@
+package markdown
+
+import (
+	"strings"
+)
+
+// CodeBlock is a fenced code block found in markdown content.
+type CodeBlock struct {
+	Lang string
+	Code string
+}
+
+// CodeBlocks returns the fenced code blocks (``` or ~~~) of markdown content, in order.
+// An unterminated block extends to the end of the content, like when it's rendered.
+func CodeBlocks(input string) []CodeBlock {
+	var (
+		blocks []CodeBlock
+		fence  string
+		lang   string
+		code   []string
+	)
+
+	for line := range strings.SplitSeq(input, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if fence == "" {
+			if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+				fence = trimmed[:3]
+				lang = strings.TrimSpace(trimmed[3:])
+				code = nil
+			}
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, fence) {
+			blocks = append(blocks, CodeBlock{Lang: lang, Code: strings.Join(code, "\n")})
+			fence = ""
+			continue
+		}
+		code = append(code, line)
+	}
+
+	if fence != "" {
+		blocks = append(blocks, CodeBlock{Lang: lang, Code: strings.Join(code, "\n")})
+	}
+
+	return blocks
+}
@@ -0,0 +1,21 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodeBlocks(t *testing.T) {
+	t.Parallel()
+
+	input := "Intro\n\n```go\nfunc main() {\n}\n```\n\nText\n\n~~~\nplain ``` text\n~~~\n\n```bash\necho unterminated"
+
+	assert.Equal(t, []CodeBlock{
+		{Lang: "go", Code: "func main() {\n}"},
+		{Lang: "", Code: "plain ``` text"},
+		{Lang: "bash", Code: "echo unterminated"},
+	}, CodeBlocks(input))
+
+	assert.Empty(t, CodeBlocks("no code here"))
+}
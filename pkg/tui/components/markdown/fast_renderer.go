@@ -73,12 +73,19 @@ type cachedStyles struct {
 }
 
 var (
-	globalStyles     *cachedStyles
-	globalStylesOnce sync.Once
+	globalStyles      *cachedStyles
+	globalStylesTheme string
+	globalStylesMu    sync.Mutex
 )
 
+// getGlobalStyles returns the styles for the current theme, rebuilding
+// them when the theme changes.
 func getGlobalStyles() *cachedStyles {
-	globalStylesOnce.Do(func() {
+	globalStylesMu.Lock()
+	defer globalStylesMu.Unlock()
+
+	if theme := styles.CurrentTheme().Name; globalStyles == nil || globalStylesTheme != theme {
+		globalStylesTheme = theme
 		mdStyle := styles.MarkdownStyle()
 
 		styleBold := buildStylePrimitive(mdStyle.Strong)
@@ -116,7 +123,12 @@ func getGlobalStyles() *cachedStyles {
 		if mdStyle.CodeBlock.BackgroundColor != nil {
 			globalStyles.styleCodeBg = globalStyles.styleCodeBg.Background(lipgloss.Color(*mdStyle.CodeBlock.BackgroundColor))
 		}
-	})
+
+		// Highlighted code depends on the code background
+		chromaStyleCacheMu.Lock()
+		clear(chromaStyleCache)
+		chromaStyleCacheMu.Unlock()
+	}
 	return globalStyles
 }
 
@@ -162,6 +174,15 @@ type parser struct {
 	out     strings.Builder
 	lines   []string
 	lineIdx int
+
+	// blocks are where each top-level block starts, in the input and in the
+	// output, for the incremental renderer
+	blocks []blockStart
+}
+
+type blockStart struct {
+	line int
+	out  int
 }
 
 func (p *parser) reset(input string, width int) {
@@ -170,6 +191,7 @@ func (p *parser) reset(input string, width int) {
 	p.styles = getGlobalStyles()
 	p.lines = strings.Split(input, "\n")
 	p.lineIdx = 0
+	p.blocks = p.blocks[:0]
 	p.out.Reset()
 	p.out.Grow(len(input) * 2) // Pre-allocate for styled output
 }
@@ -177,6 +199,7 @@ func (p *parser) reset(input string, width int) {
 func (p *parser) parse() string {
 	for p.lineIdx < len(p.lines) {
 		line := p.lines[p.lineIdx]
+		p.blocks = append(p.blocks, blockStart{line: p.lineIdx, out: p.out.Len()})
 
 		switch {
 		case p.tryCodeBlock(line):
@@ -0,0 +1,108 @@
+package markdown
+
+import (
+	"strings"
+)
+
+// IncrementalRenderer renders a markdown document that grows by appending
+// text to it, like a streamed message. The output of the blocks that the
+// appended text can no longer change is kept between calls and only the text
+// after them is parsed again, instead of the whole document each time.
+//
+// Its output is the same as the FastRenderer's for the same width.
+type IncrementalRenderer struct {
+	width  int
+	styles *cachedStyles
+
+	// source is the input of the completed blocks, always whole lines
+	source string
+	// out is the unpadded output of the completed blocks and padded the
+	// same output with its lines padded to the width
+	out    strings.Builder
+	padded strings.Builder
+}
+
+// NewIncrementalRenderer creates an incremental markdown renderer with the
+// given width.
+func NewIncrementalRenderer(width int) *IncrementalRenderer {
+	return &IncrementalRenderer{width: width}
+}
+
+// Width returns the width the renderer renders to.
+func (r *IncrementalRenderer) Width() int {
+	return r.width
+}
+
+// Render renders the whole document. When the input extends the input of the
+// previous call, only the text after its completed blocks is parsed.
+func (r *IncrementalRenderer) Render(input string) (string, error) {
+	if s := getGlobalStyles(); s != r.styles || !strings.HasPrefix(input, r.source) {
+		r.reset(s)
+	}
+	if input == "" {
+		return "", nil
+	}
+
+	tail := input[len(r.source):]
+
+	p := parserPool.Get().(*parser)
+	defer parserPool.Put(p)
+	p.reset(sanitizeForTerminal(tail), r.width)
+	p.parse()
+	out := p.out.String()
+
+	outPos := r.complete(tail, p.lines, p.blocks, out)
+
+	tailOut := strings.TrimRight(out[outPos:], "\n")
+	if tailOut == "" {
+		return padAllLines(strings.TrimRight(r.out.String(), "\n"), r.width), nil
+	}
+	return r.padded.String() + padAllLines(tailOut, r.width), nil
+}
+
+func (r *IncrementalRenderer) reset(s *cachedStyles) {
+	r.styles = s
+	r.source = ""
+	r.out.Reset()
+	r.padded.Reset()
+}
+
+// complete keeps the output of the blocks of tail that are completed and
+// returns where the output of the others starts in out.
+//
+// A block is completed when the line after the start of the next block is
+// not the last line: the last line can still be extended and a block can
+// look one line past its end to decide where it ends, e.g. a list followed by
+// a blank line.
+func (r *IncrementalRenderer) complete(tail string, lines []string, blocks []blockStart, out string) int {
+	next := -1
+	for i := len(blocks) - 1; i > 0; i-- {
+		if blocks[i].line+2 < len(lines) {
+			next = i
+			break
+		}
+	}
+	if next < 0 {
+		return 0
+	}
+
+	done := out[:blocks[next].out]
+	if done != "" && !strings.HasSuffix(done, "\n") {
+		return 0
+	}
+
+	// Lines are split the same in the sanitized text, which only loses
+	// control characters other than newlines.
+	offset := 0
+	for range blocks[next].line {
+		offset += strings.IndexByte(tail[offset:], '\n') + 1
+	}
+	r.source += tail[:offset]
+
+	if done != "" {
+		r.out.WriteString(done)
+		r.padded.WriteString(padAllLines(done[:len(done)-1], r.width))
+		r.padded.WriteByte('\n')
+	}
+	return len(done)
+}
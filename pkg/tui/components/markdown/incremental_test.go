@@ -0,0 +1,111 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var streamedInput = benchmarkInput + `
+1. First step
+2. Second step
+
+3. Third step after a blank line
+
+| Name | Value |
+|------|-------|
+| a    | 1     |
+| b    | 2     |
+
+` + "```python\r\ndef f():\r\n    return 1\r\n```" + `
+Text right after the code block.
+`
+
+// stream renders input as it is streamed, in chunks of the given size, and
+// checks that every rendering matches a full rendering.
+func stream(t *testing.T, input string, chunkSize int) {
+	t.Helper()
+
+	r := NewIncrementalRenderer(60)
+	full := NewFastRenderer(60)
+	for end := 0; end <= len(input); end += chunkSize {
+		got, err := r.Render(input[:end])
+		require.NoError(t, err)
+		want, err := full.Render(input[:end])
+		require.NoError(t, err)
+		require.Equal(t, want, got, "rendering %q", input[:end])
+	}
+}
+
+func TestIncrementalRendererMatchesFullRendering(t *testing.T) {
+	t.Parallel()
+
+	for _, chunkSize := range []int{1, 7, 64} {
+		stream(t, streamedInput, chunkSize)
+	}
+}
+
+func TestIncrementalRendererKeepsCompletedBlocks(t *testing.T) {
+	t.Parallel()
+
+	r := NewIncrementalRenderer(60)
+	_, err := r.Render("# Title\n\nFirst paragraph.\n\nSecond")
+	require.NoError(t, err)
+
+	assert.Equal(t, "# Title\n\n", r.source, "the last blocks can still change")
+
+	_, err = r.Render("# Title\n\nFirst paragraph.\n\nSecond paragraph.\n\n```go\nfunc main() {}\n")
+	require.NoError(t, err)
+
+	assert.Equal(t, "# Title\n\nFirst paragraph.\n\nSecond paragraph.\n\n", r.source)
+}
+
+func TestIncrementalRendererRestartsWhenInputChanges(t *testing.T) {
+	t.Parallel()
+
+	r := NewIncrementalRenderer(60)
+	_, err := r.Render("First paragraph.\n\nSecond paragraph.\n\nThird")
+	require.NoError(t, err)
+	require.NotEmpty(t, r.source)
+
+	got, err := r.Render("Another paragraph.\n\nThird")
+	require.NoError(t, err)
+
+	want, err := NewFastRenderer(60).Render("Another paragraph.\n\nThird")
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+	assert.NotContains(t, stripANSI(got), "First")
+}
+
+func TestIncrementalRendererEmptyInput(t *testing.T) {
+	t.Parallel()
+
+	r := NewIncrementalRenderer(60)
+	got, err := r.Render("")
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func BenchmarkFastRendererStreaming(b *testing.B) {
+	input := strings.Repeat(benchmarkInput, 5)
+	r := NewFastRenderer(80)
+	b.ResetTimer()
+	for range b.N {
+		for end := 64; end <= len(input); end += 64 {
+			_, _ = r.Render(input[:end])
+		}
+	}
+}
+
+func BenchmarkIncrementalRendererStreaming(b *testing.B) {
+	input := strings.Repeat(benchmarkInput, 5)
+	b.ResetTimer()
+	for range b.N {
+		r := NewIncrementalRenderer(80)
+		for end := 64; end <= len(input); end += 64 {
+			_, _ = r.Render(input[:end])
+		}
+	}
+}
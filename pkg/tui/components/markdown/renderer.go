@@ -1,10 +1,9 @@
 package markdown
 
 import (
-	"os"
-
 	"github.com/charmbracelet/glamour/v2"
 
+	"github.com/docker/cagent/pkg/features"
 	"github.com/docker/cagent/pkg/tui/styles"
 )
 
@@ -15,12 +14,21 @@ type Renderer interface {
 
 // NewRenderer creates a new fast markdown renderer with the given width.
 func NewRenderer(width int) Renderer {
-	if os.Getenv("CAGENT_EXPERIMENTAL_MARKDOWN_RENDERER") == "1" {
+	if features.MarkdownRenderer.Enabled() {
 		return NewFastRenderer(width)
 	}
 	return NewGlamourRenderer(width)
 }
 
+// NewStreamingRenderer creates a markdown renderer for a document that is
+// rendered again each time text is appended to it, like a streamed message.
+func NewStreamingRenderer(width int) Renderer {
+	if features.MarkdownRenderer.Enabled() {
+		return NewIncrementalRenderer(width)
+	}
+	return NewGlamourRenderer(width)
+}
+
 // NewGlamourRenderer creates a markdown renderer using glamour.
 // This is kept for compatibility and testing purposes.
 func NewGlamourRenderer(width int) *glamour.TermRenderer {
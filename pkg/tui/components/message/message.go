@@ -6,10 +6,14 @@ import (
 	"strings"
 
 	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
 	"github.com/charmbracelet/x/ansi"
 
+	"github.com/docker/cagent/pkg/confidence"
+	"github.com/docker/cagent/pkg/session"
 	"github.com/docker/cagent/pkg/tui/components/markdown"
 	"github.com/docker/cagent/pkg/tui/components/spinner"
+	"github.com/docker/cagent/pkg/tui/components/toolcommon"
 	"github.com/docker/cagent/pkg/tui/core/layout"
 	"github.com/docker/cagent/pkg/tui/styles"
 	"github.com/docker/cagent/pkg/tui/types"
@@ -21,6 +25,7 @@ type Model interface {
 	layout.Sizeable
 	SetMessage(msg *types.Message)
 	SetSelected(selected bool)
+	SetRawMarkdown(raw bool)
 }
 
 // messageModel implements Model
@@ -32,7 +37,15 @@ type messageModel struct {
 	height   int
 	focused  bool
 	selected bool
+	raw      bool // Show assistant messages as raw markdown
 	spinner  spinner.Spinner
+
+	// renderer is kept while the message is streamed so that only the
+	// appended content is parsed. It is recreated when the width or the
+	// theme changes.
+	renderer      markdown.Renderer
+	rendererWidth int
+	rendererTheme string
 }
 
 // New creates a new message view
@@ -43,7 +56,7 @@ func New(msg, previous *types.Message) *messageModel {
 		width:    80, // Default width
 		height:   1,  // Will be calculated
 		focused:  false,
-		spinner:  spinner.New(spinner.ModeBoth, styles.SpinnerDotsAccentStyle),
+		spinner:  spinner.New(spinner.ModeBoth, &styles.SpinnerDotsAccentStyle),
 	}
 }
 
@@ -65,6 +78,29 @@ func (mv *messageModel) SetSelected(selected bool) {
 	mv.selected = selected
 }
 
+func (mv *messageModel) SetRawMarkdown(raw bool) {
+	mv.raw = raw
+}
+
+// renderMarkdown renders markdown content, or wraps the raw text when the raw view is enabled
+func (mv *messageModel) renderMarkdown(content string, width int) string {
+	if mv.raw {
+		return lipgloss.NewStyle().Width(width).Render(content)
+	}
+
+	if theme := styles.CurrentTheme().Name; mv.renderer == nil || mv.rendererWidth != width || mv.rendererTheme != theme {
+		mv.renderer = markdown.NewStreamingRenderer(width)
+		mv.rendererWidth = width
+		mv.rendererTheme = theme
+	}
+
+	rendered, err := mv.renderer.Render(content)
+	if err != nil {
+		return content
+	}
+	return rendered
+}
+
 // Update handles messages and updates the message view state
 func (mv *messageModel) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
 	if mv.message.Type == types.MessageTypeSpinner || mv.message.Type == types.MessageTypeLoading {
@@ -98,9 +134,18 @@ func (mv *messageModel) Render(width int) string {
 			messageStyle = styles.SelectedMessageStyle
 		}
 
-		rendered, err := markdown.NewRenderer(width - messageStyle.GetHorizontalFrameSize()).Render(msg.Content)
-		if err != nil {
-			rendered = msg.Content
+		// The confidence the agent reported ends the answer: it's shown as a badge
+		content, report := confidence.Parse(msg.Content)
+		contentWidth := width - messageStyle.GetHorizontalFrameSize()
+		rendered := mv.renderMarkdown(content, contentWidth)
+		if report != nil {
+			rendered = strings.TrimRight(rendered, "\n") + "\n\n" + toolcommon.ConfidenceBadge(report.Level)
+			if report.Rationale != "" {
+				rendered += "\n" + styles.MutedStyle.Width(contentWidth).Render(report.Rationale)
+			}
+		}
+		if badge := feedbackBadge(msg.Feedback); badge != "" {
+			rendered = strings.TrimRight(rendered, "\n") + "\n\n" + styles.MutedStyle.Render(badge)
 		}
 
 		if mv.sameAgentAsPrevious(msg) {
@@ -116,10 +161,7 @@ func (mv *messageModel) Render(width int) string {
 		messageStyle := styles.AssistantMessageStyle
 		thinkingStyle := styles.MutedStyle.Italic(true)
 
-		rendered, err := markdown.NewRenderer(width - messageStyle.GetHorizontalFrameSize()).Render(msg.Content)
-		if err != nil {
-			rendered = msg.Content
-		}
+		rendered := mv.renderMarkdown(msg.Content, width-messageStyle.GetHorizontalFrameSize())
 
 		// Strip ANSI so muted style applies uniformly, and trim trailing whitespace.
 		// Unlike regular content where markdown ANSI output goes directly to messageStyle,
@@ -237,3 +279,15 @@ var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;]*m")
 func stripANSI(s string) string {
 	return ansiEscape.ReplaceAllString(s, "")
 }
+
+// feedbackBadge renders the rating the user gave to an answer.
+func feedbackBadge(rating string) string {
+	switch rating {
+	case session.FeedbackPositive:
+		return "👍 rated up"
+	case session.FeedbackNegative:
+		return "👎 rated down"
+	default:
+		return ""
+	}
+}
@@ -84,3 +84,35 @@ func TestErrorMessagePreservesContent(t *testing.T) {
 	assert.Contains(t, plainRendered, "database")
 	assert.Contains(t, plainRendered, "timeout")
 }
+
+func TestAssistantMessageRawMarkdown(t *testing.T) {
+	t.Parallel()
+
+	msg := types.Agent(types.MessageTypeAssistant, "", "Some **bold** text\n\n```go\nfunc main() {}\n```")
+	mv := New(msg, nil)
+	mv.SetSize(80, 0)
+
+	rendered := stripANSI(mv.View())
+	assert.NotContains(t, rendered, "**bold**")
+	assert.NotContains(t, rendered, "```go")
+
+	mv.SetRawMarkdown(true)
+	raw := stripANSI(mv.View())
+	assert.Contains(t, raw, "**bold**")
+	assert.Contains(t, raw, "```go")
+}
+
+func TestAssistantMessage_Confidence(t *testing.T) {
+	t.Parallel()
+
+	msg := types.Agent(types.MessageTypeAssistant, "root", "The limit is 100 requests a minute.\n\n<confidence level=\"low\">The docs don't say whether it's per key.</confidence>")
+	mv := New(msg, nil)
+	mv.SetSize(80, 0)
+
+	rendered := stripANSI(mv.View())
+
+	assert.Contains(t, rendered, "The limit is 100 requests a minute.")
+	assert.Contains(t, rendered, "low confidence")
+	assert.Contains(t, rendered, "The docs don't say whether it's per key.")
+	assert.NotContains(t, rendered, "<confidence")
+}
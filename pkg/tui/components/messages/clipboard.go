@@ -5,10 +5,11 @@ import (
 	"time"
 
 	tea "charm.land/bubbletea/v2"
-	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/x/ansi"
 	"github.com/mattn/go-runewidth"
 
+	"github.com/docker/cagent/pkg/tui/clipboard"
+	"github.com/docker/cagent/pkg/tui/components/markdown"
 	"github.com/docker/cagent/pkg/tui/components/notification"
 )
 
@@ -182,16 +183,23 @@ func (m *model) copySelectedMessageToClipboard() tea.Cmd {
 	return copyTextToClipboard(content)
 }
 
+// copySelectedCodeBlockToClipboard copies the last code block of the selected message to clipboard
+func (m *model) copySelectedCodeBlockToClipboard() tea.Cmd {
+	if m.selectedMessageIndex < 0 || m.selectedMessageIndex >= len(m.messages) {
+		return nil
+	}
+
+	blocks := markdown.CodeBlocks(m.messages[m.selectedMessageIndex].Content)
+	if len(blocks) == 0 {
+		return notification.InfoCmd("No code block in this message.")
+	}
+
+	return clipboard.Copy(blocks[len(blocks)-1].Code, "Code block copied to clipboard.")
+}
+
 // copyTextToClipboard copies text to the system clipboard
 func copyTextToClipboard(text string) tea.Cmd {
-	return tea.Sequence(
-		tea.SetClipboard(text),
-		func() tea.Msg {
-			_ = clipboard.WriteAll(text)
-			return nil
-		},
-		notification.SuccessCmd("Text copied to clipboard."),
-	)
+	return clipboard.Copy(text, "Text copied to clipboard.")
 }
 
 // scheduleDebouncedCopy schedules a copy after a delay, allowing triple-click to cancel it.
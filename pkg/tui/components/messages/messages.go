@@ -1,8 +1,8 @@
 package messages
 
 import (
-	"os"
 	"strings"
+	"time"
 
 	"charm.land/bubbles/v2/help"
 	"charm.land/bubbles/v2/key"
@@ -12,16 +12,22 @@ import (
 
 	"github.com/docker/cagent/pkg/app"
 	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/features"
 	"github.com/docker/cagent/pkg/runtime"
 	"github.com/docker/cagent/pkg/session"
 	"github.com/docker/cagent/pkg/tools"
 	"github.com/docker/cagent/pkg/tools/builtin"
+	"github.com/docker/cagent/pkg/tui/components/imageview"
 	"github.com/docker/cagent/pkg/tui/components/message"
+	"github.com/docker/cagent/pkg/tui/components/notification"
 	"github.com/docker/cagent/pkg/tui/components/scrollbar"
 	"github.com/docker/cagent/pkg/tui/components/tool"
 	"github.com/docker/cagent/pkg/tui/components/tool/editfile"
+	"github.com/docker/cagent/pkg/tui/components/toolcommon"
 	"github.com/docker/cagent/pkg/tui/core"
 	"github.com/docker/cagent/pkg/tui/core/layout"
+	"github.com/docker/cagent/pkg/tui/keys"
+	msgtypes "github.com/docker/cagent/pkg/tui/messages"
 	"github.com/docker/cagent/pkg/tui/service"
 	"github.com/docker/cagent/pkg/tui/styles"
 	"github.com/docker/cagent/pkg/tui/types"
@@ -35,6 +41,15 @@ type StreamCancelledMsg struct {
 // ToggleHideToolResultsMsg triggers hiding/showing tool results
 type ToggleHideToolResultsMsg struct{}
 
+// ToggleRawMarkdownMsg triggers showing assistant messages as raw or rendered markdown
+type ToggleRawMarkdownMsg struct{}
+
+// ToggleExpandToolCallsMsg triggers expanding/collapsing every finished tool call
+type ToggleExpandToolCallsMsg struct{}
+
+// ToggleHideSubAgentsMsg triggers hiding/showing the messages of the sub-agents
+type ToggleHideSubAgentsMsg struct{}
+
 // Model represents a chat message list component
 type Model interface {
 	layout.Model
@@ -52,11 +67,17 @@ type Model interface {
 	AddWelcomeMessage(content string) tea.Cmd
 	AddOrUpdateToolCall(agentName string, toolCall tools.ToolCall, toolDef tools.Tool, status types.ToolStatus) tea.Cmd
 	AddToolResult(msg *runtime.ToolCallResponseEvent, status types.ToolStatus) tea.Cmd
+	SetToolResultSummary(toolCallID, summary string)
+	SetFeedback(agentName, answer, rating string)
+	DiscardPartialResponse(agentName string) tea.Cmd
 	AppendToLastMessage(agentName string, messageType types.MessageType, content string) tea.Cmd
 	AddShellOutputMessage(content string) tea.Cmd
 	LoadFromSession(sess *session.Session) tea.Cmd
+	SetAgentDepth(agentName string, depth int)
 
 	ScrollToBottom() tea.Cmd
+	IsSearching() bool
+	IsSelectingLines() bool
 }
 
 // renderedItem represents a cached rendered message with position information
@@ -78,16 +99,24 @@ type model struct {
 	rendered      string               // Complete rendered content string
 	renderedItems map[int]renderedItem // Cache of rendered items with positions
 	totalHeight   int                  // Total height of all content in lines
+	renderFrom    int                  // Index of the first rendered message, older ones are released
 
 	selection selectionState
+	search    searchState
+	vim       vimState
 
 	sessionState *service.SessionState
 	scrollbar    *scrollbar.Model
 
+	// agentDepths is how deep in task transfers each agent last spoke, the
+	// messages of the sub-agents are nested under the task that started them
+	agentDepths map[string]int
+
 	xPos, yPos int
 
 	// User scroll state
 	userHasScrolled bool // True when user manually scrolls away from bottom
+	missedOutput    bool // True when content was added below while the user was scrolled up
 
 	// Message selection state
 	selectedMessageIndex int  // Index of selected message (-1 = no selection)
@@ -95,6 +124,59 @@ type model struct {
 
 	// Debug layout mode - highlights truncated lines with red background
 	debugLayout bool
+
+	// imageProtocol is the graphics protocol used to view images returned by tools
+	imageProtocol imageview.Protocol
+
+	keyMap keyMap
+}
+
+// keyMap defines key bindings for the transcript
+type keyMap struct {
+	SelectPrevious  key.Binding
+	SelectNext      key.Binding
+	ClearSelection  key.Binding
+	CopyMessage     key.Binding
+	CopyCode        key.Binding
+	CopySession     key.Binding
+	ToggleToolCall  key.Binding
+	ExpandToolCalls key.Binding
+	ViewImage       key.Binding
+	OpenImage       key.Binding
+	PageUp          key.Binding
+	PageDown        key.Binding
+	ScrollToTop     key.Binding
+	ScrollToBottom  key.Binding
+	Search          key.Binding
+	NextMatch       key.Binding
+	PreviousMatch   key.Binding
+	RateUp          key.Binding
+	RateDown        key.Binding
+}
+
+// defaultKeyMap returns the key bindings, with the keys set by the user
+func defaultKeyMap() keyMap {
+	return keyMap{
+		SelectPrevious:  keys.Get(keys.SelectPrevious),
+		SelectNext:      keys.Get(keys.SelectNext),
+		ClearSelection:  keys.Get(keys.ClearSelection),
+		CopyMessage:     keys.Get(keys.CopyMessage),
+		CopyCode:        keys.Get(keys.CopyCode),
+		CopySession:     keys.Get(keys.CopySession),
+		ToggleToolCall:  keys.Get(keys.ToggleToolCall),
+		ExpandToolCalls: keys.Get(keys.ExpandToolCalls),
+		ViewImage:       keys.Get(keys.ViewImage),
+		OpenImage:       keys.Get(keys.OpenImage),
+		PageUp:          keys.Get(keys.PageUp),
+		PageDown:        keys.Get(keys.PageDown),
+		ScrollToTop:     keys.Get(keys.ScrollToTop),
+		ScrollToBottom:  keys.Get(keys.ScrollToBottom),
+		Search:          keys.Get(keys.Search),
+		NextMatch:       keys.Get(keys.NextMatch),
+		PreviousMatch:   keys.Get(keys.PreviousMatch),
+		RateUp:          keys.Get(keys.RateUp),
+		RateDown:        keys.Get(keys.RateDown),
+	}
 }
 
 // New creates a new message list component
@@ -107,7 +189,9 @@ func New(a *app.App, sessionState *service.SessionState) Model {
 		sessionState:         sessionState,
 		scrollbar:            scrollbar.New(),
 		selectedMessageIndex: -1,
-		debugLayout:          os.Getenv("CAGENT_EXPERIMENTAL_DEBUG_LAYOUT") == "1",
+		debugLayout:          features.DebugLayout.Enabled(),
+		imageProtocol:        imageview.DetectProtocol(),
+		keyMap:               defaultKeyMap(),
 	}
 }
 
@@ -121,7 +205,8 @@ func NewScrollableView(width, height int, sessionState *service.SessionState) Mo
 		sessionState:         sessionState,
 		scrollbar:            scrollbar.New(),
 		selectedMessageIndex: -1,
-		debugLayout:          os.Getenv("CAGENT_EXPERIMENTAL_DEBUG_LAYOUT") == "1",
+		debugLayout:          features.DebugLayout.Enabled(),
+		keyMap:               defaultKeyMap(),
 	}
 }
 
@@ -182,6 +267,39 @@ func (m *model) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
 		m.invalidateAllItems()
 		return m, nil
 
+	case ToggleRawMarkdownMsg:
+		m.sessionState.ToggleRawMarkdown()
+		for _, view := range m.views {
+			if mv, ok := view.(message.Model); ok {
+				mv.SetRawMarkdown(m.sessionState.RawMarkdown)
+			}
+		}
+		m.invalidateAllItems()
+		return m, nil
+
+	case ToggleExpandToolCallsMsg:
+		m.sessionState.ToggleExpandToolCalls()
+		for _, msg := range m.messages {
+			msg.Expanded = false
+		}
+		m.invalidateAllItems()
+		return m, nil
+
+	case ToggleHideSubAgentsMsg:
+		m.sessionState.ToggleHideSubAgents()
+		m.invalidateAllItems()
+		return m, nil
+
+	case msgtypes.ThemeChangedMsg:
+		m.invalidateAllItems()
+		return m, nil
+
+	case imageview.LoadedMsg:
+		if len(msg.Images) > 0 {
+			m.setToolCallImages(msg.ToolCallID, msg.Images)
+		}
+		return m, nil
+
 	case tea.KeyPressMsg:
 		return m.handleKeyPress(msg)
 	}
@@ -205,6 +323,12 @@ func (m *model) handleMouseClick(msg tea.MouseClickMsg) (layout.Model, tea.Cmd)
 		return m, nil
 	}
 
+	// Clicking the scroll indicator jumps to the latest output
+	if m.showScrollIndicator() && msg.Y-m.yPos == m.scrollIndicatorRow() {
+		m.scrollToBottom()
+		return m, nil
+	}
+
 	line, col := m.mouseToLineCol(msg.X, msg.Y)
 	clickCount := m.selection.detectClickType(line, col)
 
@@ -264,6 +388,9 @@ func (m *model) handleMouseWheel(msg tea.MouseWheelMsg) (layout.Model, tea.Cmd)
 
 	switch buttonStr {
 	case "wheelup":
+		if m.scrollOffset == 0 {
+			m.loadEarlierMessages(loadMoreMessages)
+		}
 		if m.scrollOffset > 0 {
 			m.userHasScrolled = true
 			for range mouseScrollAmount {
@@ -284,40 +411,95 @@ func (m *model) handleMouseWheel(msg tea.MouseWheelMsg) (layout.Model, tea.Cmd)
 }
 
 func (m *model) handleKeyPress(msg tea.KeyPressMsg) (layout.Model, tea.Cmd) {
-	switch msg.String() {
-	case "esc":
+	if m.handleSearchKey(msg) {
+		return m, nil
+	}
+
+	if m.vimEnabled() {
+		if cmd, handled := m.handleVimKey(msg); handled {
+			return m, cmd
+		}
+	}
+
+	switch {
+	case key.Matches(msg, m.keyMap.ClearSelection):
 		m.clearSelection()
 		return m, nil
-	case "up", "k":
+	case key.Matches(msg, m.keyMap.SelectPrevious):
 		if m.focused {
 			m.selectPreviousMessage()
 		} else {
 			m.scrollUp()
 		}
 		return m, nil
-	case "down", "j":
+	case key.Matches(msg, m.keyMap.SelectNext):
 		if m.focused {
 			m.selectNextMessage()
 		} else {
 			m.scrollDown()
 		}
 		return m, nil
-	case "c":
+	case key.Matches(msg, m.keyMap.CopyMessage):
 		if m.focused && m.selectedMessageIndex >= 0 {
 			cmd := m.copySelectedMessageToClipboard()
 			return m, cmd
 		}
 		return m, nil
-	case "pgup":
+	case key.Matches(msg, m.keyMap.CopyCode):
+		if m.focused && m.selectedMessageIndex >= 0 {
+			cmd := m.copySelectedCodeBlockToClipboard()
+			return m, cmd
+		}
+		return m, nil
+	case key.Matches(msg, m.keyMap.ToggleToolCall):
+		if m.focused {
+			m.toggleSelectedToolCall()
+		}
+		return m, nil
+	case key.Matches(msg, m.keyMap.ExpandToolCalls):
+		if m.focused {
+			return m, core.CmdHandler(msgtypes.ToggleExpandToolCallsMsg{})
+		}
+		return m, nil
+	case key.Matches(msg, m.keyMap.ViewImage):
+		if m.focused {
+			if images := m.selectedImages(); len(images) > 0 {
+				return m, imageview.ViewCmd(images, m.imageProtocol, m.contentWidth())
+			}
+		}
+		return m, nil
+	case key.Matches(msg, m.keyMap.OpenImage):
+		if m.focused {
+			if images := m.selectedImages(); len(images) > 0 {
+				return m, imageview.OpenCmd(images[0])
+			}
+		}
+		return m, nil
+	case key.Matches(msg, m.keyMap.CopySession):
+		if m.focused {
+			return m, core.CmdHandler(msgtypes.CopySessionToClipboardMsg{})
+		}
+		return m, nil
+	case key.Matches(msg, m.keyMap.RateUp):
+		if m.focused {
+			return m, m.rateSelectedMessage(session.FeedbackPositive)
+		}
+		return m, nil
+	case key.Matches(msg, m.keyMap.RateDown):
+		if m.focused {
+			return m, m.rateSelectedMessage(session.FeedbackNegative)
+		}
+		return m, nil
+	case key.Matches(msg, m.keyMap.PageUp):
 		m.scrollPageUp()
 		return m, nil
-	case "pgdown":
+	case key.Matches(msg, m.keyMap.PageDown):
 		m.scrollPageDown()
 		return m, nil
-	case "home":
+	case key.Matches(msg, m.keyMap.ScrollToTop):
 		m.scrollToTop()
 		return m, nil
-	case "end":
+	case key.Matches(msg, m.keyMap.ScrollToBottom):
 		m.scrollToBottom()
 		return m, nil
 	}
@@ -325,6 +507,8 @@ func (m *model) handleKeyPress(msg tea.KeyPressMsg) (layout.Model, tea.Cmd) {
 }
 
 func (m *model) View() string {
+	defer core.TrackRender("messages")()
+
 	if len(m.messages) == 0 {
 		return ""
 	}
@@ -336,10 +520,17 @@ func (m *model) View() string {
 		return ""
 	}
 
+	// Keep search matches in sync with streamed content
+	m.refreshSearch(false)
+
 	// Calculate viewport bounds
-	maxScrollOffset := max(0, m.totalHeight-m.height)
+	height := m.viewportHeight()
+	maxScrollOffset := max(0, m.totalHeight-height)
 
 	// Auto-scroll if content grew and user hasn't manually scrolled
+	if !m.userHasScrolled {
+		m.missedOutput = false
+	}
 	if !m.userHasScrolled && m.totalHeight > prevTotalHeight {
 		m.scrollOffset = maxScrollOffset
 	} else {
@@ -352,7 +543,7 @@ func (m *model) View() string {
 	}
 
 	startLine := m.scrollOffset
-	endLine := min(startLine+m.height, len(lines))
+	endLine := min(startLine+height, len(lines))
 
 	if startLine >= endLine {
 		return ""
@@ -364,6 +555,10 @@ func (m *model) View() string {
 		visibleLines = m.applySelectionHighlight(visibleLines, startLine)
 	}
 
+	if m.search.query != "" {
+		visibleLines = m.applySearchHighlight(visibleLines, startLine)
+	}
+
 	m.scrollbar.SetDimensions(m.height, m.totalHeight)
 	m.scrollbar.SetScrollOffset(m.scrollOffset)
 
@@ -381,6 +576,21 @@ func (m *model) View() string {
 		}
 	}
 
+	if m.showScrollIndicator() {
+		for len(visibleLines) < height {
+			visibleLines = append(visibleLines, "")
+		}
+		visibleLines[m.scrollIndicatorRow()] = m.scrollIndicatorView(contentWidth)
+	}
+
+	// The search bar takes the last line of the viewport
+	if m.IsSearching() {
+		for len(visibleLines) < height {
+			visibleLines = append(visibleLines, "")
+		}
+		visibleLines = append(visibleLines, m.searchBarView(contentWidth))
+	}
+
 	contentView := strings.Join(visibleLines, "\n")
 	scrollbarView := m.scrollbar.View()
 
@@ -418,8 +628,8 @@ func (m *model) SetSize(width, height int) tea.Cmd {
 
 	// Content width reserves space for scrollbar (2 chars: space + scrollbar)
 	contentWidth := m.contentWidth()
-	for _, view := range m.views {
-		view.SetSize(contentWidth, 0)
+	for i, view := range m.views {
+		view.SetSize(m.itemWidth(m.messages[i]), 0)
 	}
 
 	m.scrollbar.SetPosition(1+m.xPos+contentWidth+1, m.yPos)
@@ -452,15 +662,27 @@ func (m *model) Focus() tea.Cmd {
 func (m *model) Blur() tea.Cmd {
 	m.focused = false
 	m.selectedMessageIndex = -1
+	m.search.clear()
+	if m.vim.visual {
+		m.stopVisual()
+	}
 	return nil
 }
 
 // Bindings returns key bindings for the component
 func (m *model) Bindings() []key.Binding {
 	return []key.Binding{
-		key.NewBinding(key.WithKeys("up"), key.WithHelp("↑", "select prev")),
-		key.NewBinding(key.WithKeys("down"), key.WithHelp("↓", "select next")),
-		key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "copy message")),
+		m.keyMap.SelectPrevious,
+		m.keyMap.SelectNext,
+		m.keyMap.CopyMessage,
+		m.keyMap.CopyCode,
+		m.keyMap.CopySession,
+		m.keyMap.ToggleToolCall,
+		m.keyMap.ExpandToolCalls,
+		m.keyMap.ViewImage,
+		m.keyMap.RateUp,
+		m.keyMap.RateDown,
+		m.keyMap.Search,
 	}
 }
 
@@ -473,6 +695,9 @@ func (m *model) Help() help.KeyMap {
 const defaultScrollAmount = 1
 
 func (m *model) scrollUp() {
+	if m.scrollOffset == 0 {
+		m.loadEarlierMessages(loadMoreMessages)
+	}
 	if m.scrollOffset > 0 {
 		m.userHasScrolled = true
 		m.setScrollOffset(max(0, m.scrollOffset-defaultScrollAmount))
@@ -488,6 +713,9 @@ func (m *model) scrollDown() {
 }
 
 func (m *model) scrollPageUp() {
+	if m.scrollOffset == 0 {
+		m.loadEarlierMessages(loadMoreMessages)
+	}
 	m.userHasScrolled = true
 	m.setScrollOffset(max(0, m.scrollOffset-m.height))
 }
@@ -501,6 +729,7 @@ func (m *model) scrollPageDown() {
 }
 
 func (m *model) scrollToTop() {
+	m.loadEarlierMessages(m.renderFrom)
 	m.userHasScrolled = true
 	m.setScrollOffset(0)
 }
@@ -510,8 +739,16 @@ func (m *model) scrollToBottom() {
 	m.setScrollOffset(9_999_999) // Will be clamped in View()
 }
 
+// viewportHeight is the number of lines available for messages
+func (m *model) viewportHeight() int {
+	if m.IsSearching() {
+		return max(1, m.height-1)
+	}
+	return m.height
+}
+
 func (m *model) setScrollOffset(offset int) {
-	maxOffset := max(0, m.totalHeight-m.height)
+	maxOffset := max(0, m.totalHeight-m.viewportHeight())
 	m.scrollOffset = max(0, min(offset, maxOffset))
 	m.scrollbar.SetScrollOffset(m.scrollOffset)
 }
@@ -520,8 +757,7 @@ func (m *model) isAtBottom() bool {
 	if len(m.messages) == 0 {
 		return true
 	}
-	maxScrollOffset := max(0, m.totalHeight-m.height)
-	return m.scrollOffset >= maxScrollOffset
+	return m.scrollOffset >= m.maxScrollOffset()
 }
 
 // Message selection methods
@@ -530,7 +766,49 @@ func (m *model) isSelectableMessage(index int) bool {
 		return false
 	}
 	msgType := m.messages[index].Type
-	return msgType == types.MessageTypeAssistant || msgType == types.MessageTypeAssistantReasoning
+	return msgType == types.MessageTypeAssistant || msgType == types.MessageTypeAssistantReasoning || m.messages[index].IsFinishedToolCall()
+}
+
+// isExpanded returns true when a finished tool call shows its full arguments and result.
+// Tool calls matching the search query are expanded so that the matches are visible.
+func (m *model) isExpanded(msg *types.Message) bool {
+	return msg.Expanded || (m.sessionState != nil && m.sessionState.ExpandToolCalls) || m.matchesSearch(msg)
+}
+
+// selectedImages returns the images attached to the selected message.
+func (m *model) selectedImages() []tools.Image {
+	if m.selectedMessageIndex < 0 || m.selectedMessageIndex >= len(m.messages) {
+		return nil
+	}
+	var images []tools.Image
+	for _, img := range m.messages[m.selectedMessageIndex].Images {
+		images = append(images, img.Image)
+	}
+	return images
+}
+
+// toggleSelectedToolCall expands or collapses the selected tool call.
+func (m *model) toggleSelectedToolCall() {
+	if m.selectedMessageIndex < 0 || m.selectedMessageIndex >= len(m.messages) {
+		return
+	}
+
+	msg := m.messages[m.selectedMessageIndex]
+	if !msg.IsFinishedToolCall() {
+		return
+	}
+
+	msg.Expanded = !m.isExpanded(msg)
+	if m.sessionState.ExpandToolCalls && !msg.Expanded {
+		// Collapsing one tool call while all are expanded leaves "expand all" mode.
+		m.sessionState.ToggleExpandToolCalls()
+		for _, other := range m.messages {
+			other.Expanded = other != msg && other.IsFinishedToolCall()
+		}
+	}
+
+	m.invalidateAllItems()
+	m.scrollToSelectedMessage()
 }
 
 func (m *model) findLastSelectableMessage() int {
@@ -565,6 +843,9 @@ func (m *model) selectPreviousMessage() {
 		return
 	}
 	if prevIndex := m.findPreviousSelectableMessage(m.selectedMessageIndex); prevIndex >= 0 {
+		if prevIndex < m.renderFrom {
+			m.loadEarlierMessages(max(loadMoreMessages, m.renderFrom-prevIndex))
+		}
 		m.selectedMessageIndex = prevIndex
 		m.invalidateAllItems()
 		m.scrollToSelectedMessage()
@@ -583,16 +864,34 @@ func (m *model) selectNextMessage() {
 }
 
 func (m *model) scrollToSelectedMessage() {
-	if m.selectedMessageIndex < 0 || m.selectedMessageIndex >= len(m.messages) {
+	startLine, endLine, ok := m.selectedMessageLines()
+	if !ok {
 		return
 	}
 
+	// Scroll to make the selected message visible
+	if startLine < m.scrollOffset {
+		m.userHasScrolled = true
+		m.setScrollOffset(startLine)
+	} else if endLine > m.scrollOffset+m.height {
+		m.userHasScrolled = true
+		m.setScrollOffset(endLine - m.height)
+	}
+}
+
+// selectedMessageLines returns the range of lines of the selected message,
+// the end excluded.
+func (m *model) selectedMessageLines() (startLine, endLine int, ok bool) {
+	if m.selectedMessageIndex < 0 || m.selectedMessageIndex >= len(m.messages) {
+		return 0, 0, false
+	}
+
 	// Ensure all items are rendered so totalHeight is accurate
 	m.ensureAllItemsRendered()
 
 	// Calculate the line range for the selected message
-	startLine := 0
-	for i := range m.selectedMessageIndex {
+	startLine = len(m.earlierMessagesLines())
+	for i := m.renderFrom; i < m.selectedMessageIndex; i++ {
 		if i < len(m.views) {
 			item := m.renderItem(i, m.views[i])
 			startLine += item.height
@@ -607,16 +906,7 @@ func (m *model) scrollToSelectedMessage() {
 		item := m.renderItem(m.selectedMessageIndex, m.views[m.selectedMessageIndex])
 		selectedHeight = item.height
 	}
-	endLine := startLine + selectedHeight
-
-	// Scroll to make the selected message visible
-	if startLine < m.scrollOffset {
-		m.userHasScrolled = true
-		m.setScrollOffset(startLine)
-	} else if endLine > m.scrollOffset+m.height {
-		m.userHasScrolled = true
-		m.setScrollOffset(endLine - m.height)
-	}
+	return startLine, startLine + selectedHeight, true
 }
 
 // Caching methods
@@ -656,7 +946,36 @@ func (m *model) renderItem(index int, view layout.Model) renderedItem {
 		}
 	}
 
-	rendered := view.View()
+	msg := m.messages[index]
+	// Tool calls waiting for a confirmation stay visible, the user has to answer them
+	if m.sessionState.HideSubAgents && msg.Depth > 0 && msg.ToolStatus != types.ToolStatusConfirmation {
+		return renderedItem{}
+	}
+
+	width := m.itemWidth(msg)
+	var rendered string
+	if msg.IsFinishedToolCall() {
+		expanded := m.isExpanded(msg)
+		switch {
+		case !expanded:
+			rendered = toolcommon.RenderSummary(msg, width, isSelected, false)
+		case isSelected:
+			rendered = toolcommon.RenderSummary(msg, width, true, true) + "\n" + view.View()
+		default:
+			rendered = view.View()
+		}
+		if expanded && msg.ContextSummary != "" {
+			rendered += "\n" + styles.MutedStyle.Render("Summary sent to the model:") + "\n" + toolcommon.FormatToolResult(msg.ContextSummary, width)
+		}
+	} else {
+		rendered = view.View()
+	}
+	for _, img := range msg.Images {
+		rendered += "\n" + ansi.Truncate(imageview.Placeholder(img, m.imageProtocol), width, "…")
+	}
+	if msg.Depth > 0 && rendered != "" {
+		rendered = nestLines(rendered, msg.Depth)
+	}
 	height := lipgloss.Height(rendered)
 	if rendered == "" {
 		height = 0
@@ -693,10 +1012,11 @@ func (m *model) ensureAllItemsRendered() {
 		return
 	}
 
-	var allLines []string
+	m.renderFrom = min(m.renderFrom, len(m.views))
+	allLines := m.earlierMessagesLines()
 
-	for i, view := range m.views {
-		item := m.renderItem(i, view)
+	for i := m.renderFrom; i < len(m.views); i++ {
+		item := m.renderItem(i, m.views[i])
 		if item.view == "" {
 			continue
 		}
@@ -784,11 +1104,14 @@ func (m *model) AddWelcomeMessage(content string) tea.Cmd {
 func (m *model) addMessage(msg *types.Message) tea.Cmd {
 	m.clearSelection()
 	shouldAutoScroll := !m.userHasScrolled
+	m.missedOutput = m.missedOutput || m.userHasScrolled
 
+	msg.Depth = m.agentDepths[msg.Sender]
 	m.messages = append(m.messages, msg)
 	view := m.createMessageView(msg)
 	m.sessionState.PreviousMessage = msg
 	m.views = append(m.views, view)
+	m.releaseOldMessages()
 
 	var cmds []tea.Cmd
 	if initCmd := view.Init(); initCmd != nil {
@@ -812,16 +1135,28 @@ func (m *model) LoadFromSession(sess *session.Session) tea.Cmd {
 	m.rendered = ""
 	m.scrollOffset = 0
 	m.totalHeight = 0
+	m.renderFrom = 0
 	m.selectedMessageIndex = -1
+	m.search.clear()
 
 	var cmds []tea.Cmd
 
 	for _, item := range sess.Messages {
+		if item.IsSubSession() {
+			m.attachHandoff(item.SubSession.Handoff)
+			continue
+		}
 		if !item.IsMessage() {
 			continue
 		}
 
 		smsg := item.Message
+		if smsg.TurnCancelled {
+			msg := types.Cancelled()
+			m.messages = append(m.messages, msg)
+			m.views = append(m.views, m.createMessageView(msg))
+			continue
+		}
 		if smsg.Implicit {
 			continue
 		}
@@ -851,19 +1186,42 @@ func (m *model) LoadFromSession(sess *session.Session) tea.Cmd {
 		}
 	}
 
+	for _, feedback := range sess.Feedback {
+		m.SetFeedback(feedback.AgentName, strings.ReplaceAll(feedback.Answer, "\t", "    "), feedback.Rating)
+	}
+
 	for _, view := range m.views {
 		cmds = append(cmds, view.Init())
 	}
+	m.releaseOldMessages()
 
 	cmds = append(cmds, m.ScrollToBottom())
 	return tea.Batch(cmds...)
 }
 
+// attachHandoff gives the handoff of a restored sub-session to the task
+// transfer that started it: the last one without a result.
+func (m *model) attachHandoff(handoff *session.Handoff) {
+	if handoff == nil {
+		return
+	}
+	for i := len(m.messages) - 1; i >= 0; i-- {
+		msg := m.messages[i]
+		if msg.Type == types.MessageTypeToolCall && msg.ToolCall.Function.Name == builtin.ToolNameTransferTask && msg.ToolResult == nil {
+			msg.ToolResult = &tools.ToolCallResult{Meta: handoff}
+			return
+		}
+	}
+}
+
 func (m *model) AddOrUpdateToolCall(agentName string, toolCall tools.ToolCall, toolDef tools.Tool, status types.ToolStatus) tea.Cmd {
 	// First try to update existing tool by ID
 	for i := len(m.messages) - 1; i >= 0; i-- {
 		msg := m.messages[i]
 		if msg.ToolCall.ID == toolCall.ID {
+			if status == types.ToolStatusRunning && msg.ToolStartedAt.IsZero() {
+				msg.ToolStartedAt = time.Now()
+			}
 			msg.ToolStatus = status
 			if toolCall.Function.Arguments != "" {
 				msg.ToolCall.Function.Arguments = toolCall.Function.Arguments
@@ -876,9 +1234,14 @@ func (m *model) AddOrUpdateToolCall(agentName string, toolCall tools.ToolCall, t
 	m.removeSpinner()
 
 	msg := types.ToolCallMessage(agentName, toolCall, toolDef, status)
+	msg.Depth = m.agentDepths[agentName]
+	if status == types.ToolStatusRunning {
+		msg.ToolStartedAt = time.Now()
+	}
 	m.messages = append(m.messages, msg)
 	view := m.createToolCallView(msg)
 	m.views = append(m.views, view)
+	m.releaseOldMessages()
 
 	return view.Init()
 }
@@ -890,16 +1253,102 @@ func (m *model) AddToolResult(msg *runtime.ToolCallResponseEvent, status types.T
 			toolMessage.Content = strings.ReplaceAll(msg.Response, "\t", "    ")
 			toolMessage.ToolStatus = status
 			toolMessage.ToolResult = msg.Result
+			toolMessage.Images = nil
+			if !toolMessage.ToolStartedAt.IsZero() {
+				toolMessage.ToolDuration = time.Since(toolMessage.ToolStartedAt)
+			}
 			m.invalidateItem(i)
 
 			view := m.createToolCallView(toolMessage)
 			m.views[i] = view
-			return view.Init()
+
+			var returned []tools.Image
+			if msg.Result != nil {
+				returned = msg.Result.Images
+			}
+			return tea.Batch(view.Init(), imageview.LoadCmd(msg.ToolCall.ID, msg.Response, returned))
 		}
 	}
 	return nil
 }
 
+// setToolCallImages attaches the images loaded for a tool call to its message.
+func (m *model) setToolCallImages(toolCallID string, images []types.Image) {
+	for i, msg := range m.messages {
+		if msg.ToolCall.ID == toolCallID {
+			msg.Images = images
+			m.invalidateItem(i)
+		}
+	}
+}
+
+// SetToolResultSummary records that the model received a summary of a tool result instead of its raw output.
+func (m *model) SetToolResultSummary(toolCallID, summary string) {
+	for i := len(m.messages) - 1; i >= 0; i-- {
+		if m.messages[i].ToolCall.ID == toolCallID {
+			m.messages[i].ContextSummary = summary
+			m.invalidateItem(i)
+			return
+		}
+	}
+}
+
+// SetFeedback shows the rating the user gave to an answer of an agent.
+func (m *model) SetFeedback(agentName, answer, rating string) {
+	for i := len(m.messages) - 1; i >= 0; i-- {
+		msg := m.messages[i]
+		if msg.Type == types.MessageTypeAssistant && msg.Sender == agentName && msg.Content == answer {
+			msg.Feedback = rating
+			m.invalidateItem(i)
+			return
+		}
+	}
+}
+
+// DiscardPartialResponse removes what an agent streamed of a response that
+// failed and is streamed again.
+func (m *model) DiscardPartialResponse(agentName string) tea.Cmd {
+	m.removeSpinner()
+
+	end := len(m.messages)
+	for end > 0 {
+		msg := m.messages[end-1]
+		partial := msg.Sender == agentName && (msg.Type == types.MessageTypeAssistant ||
+			msg.Type == types.MessageTypeAssistantReasoning ||
+			(msg.Type == types.MessageTypeToolCall && msg.ToolStatus == types.ToolStatusPending))
+		if !partial {
+			break
+		}
+		end--
+	}
+
+	if end < len(m.messages) {
+		m.messages = m.messages[:end]
+		m.views = m.views[:min(end, len(m.views))]
+		m.invalidateAllItems()
+	}
+
+	return m.AddAssistantMessage()
+}
+
+// rateSelectedMessage asks for a comment on the selected answer, rated by the user.
+func (m *model) rateSelectedMessage(rating string) tea.Cmd {
+	if m.selectedMessageIndex < 0 || m.selectedMessageIndex >= len(m.messages) {
+		return nil
+	}
+
+	msg := m.messages[m.selectedMessageIndex]
+	if msg.Type != types.MessageTypeAssistant || msg.Content == "" {
+		return notification.InfoCmd("Only answers of agents can be rated.")
+	}
+
+	return core.CmdHandler(msgtypes.ShowFeedbackInputMsg{
+		AgentName: msg.Sender,
+		Answer:    msg.Content,
+		Rating:    rating,
+	})
+}
+
 func (m *model) AppendToLastMessage(agentName string, messageType types.MessageType, content string) tea.Cmd {
 	m.removeSpinner()
 
@@ -912,6 +1361,7 @@ func (m *model) AppendToLastMessage(agentName string, messageType types.MessageT
 
 	if lastMsg.Type == messageType && lastMsg.Sender == agentName {
 		lastMsg.Content += content
+		m.missedOutput = m.missedOutput || m.userHasScrolled
 		m.views[lastIdx].(message.Model).SetMessage(lastMsg)
 		m.invalidateItem(lastIdx)
 		return nil
@@ -935,16 +1385,44 @@ func (m *model) contentWidth() int {
 	return m.width - 2
 }
 
+// itemWidth returns the width available for a message, less the indentation
+// of the sub-agents.
+func (m *model) itemWidth(msg *types.Message) int {
+	return max(1, m.contentWidth()-ansi.StringWidth(nestPrefix)*msg.Depth)
+}
+
+// SetAgentDepth records how deep in task transfers an agent is, for the
+// messages it sends next.
+func (m *model) SetAgentDepth(agentName string, depth int) {
+	if m.agentDepths == nil {
+		m.agentDepths = make(map[string]int)
+	}
+	m.agentDepths[agentName] = depth
+}
+
+// nestPrefix indents the messages of a sub-agent, once per task transfer.
+const nestPrefix = "│ "
+
+func nestLines(rendered string, depth int) string {
+	prefix := styles.MutedStyle.Render(strings.Repeat(nestPrefix, depth))
+	lines := strings.Split(rendered, "\n")
+	for i, line := range lines {
+		lines[i] = prefix + line
+	}
+	return strings.Join(lines, "\n")
+}
+
 // Helper methods
 func (m *model) createToolCallView(msg *types.Message) layout.Model {
 	view := tool.New(msg, m.sessionState)
-	view.SetSize(m.contentWidth(), 0)
+	view.SetSize(m.itemWidth(msg), 0)
 	return view
 }
 
 func (m *model) createMessageView(msg *types.Message) layout.Model {
 	view := message.New(msg, m.sessionState.PreviousMessage)
-	view.SetSize(m.contentWidth(), 0)
+	view.SetRawMarkdown(m.sessionState.RawMarkdown)
+	view.SetSize(m.itemWidth(msg), 0)
 	return view
 }
 
@@ -1005,5 +1483,6 @@ func (m *model) handleScrollbarUpdate(msg tea.Msg) (layout.Model, tea.Cmd) {
 	sb, cmd := m.scrollbar.Update(msg)
 	m.scrollbar = sb
 	m.scrollOffset = m.scrollbar.GetScrollOffset()
+	m.userHasScrolled = !m.isAtBottom()
 	return m, cmd
 }
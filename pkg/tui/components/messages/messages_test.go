@@ -1,12 +1,18 @@
 package messages
 
 import (
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 
+	tea "charm.land/bubbletea/v2"
 	"github.com/charmbracelet/x/ansi"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/tools"
 	"github.com/docker/cagent/pkg/tui/service"
 	"github.com/docker/cagent/pkg/tui/types"
 )
@@ -27,3 +33,145 @@ func TestViewDoesNotWrapWideLines(t *testing.T) {
 		assert.LessOrEqual(t, ansi.StringWidth(line), 20)
 	}
 }
+
+func TestToolCallsAreCollapsedUntilExpanded(t *testing.T) {
+	t.Parallel()
+
+	sessionState := &service.SessionState{}
+	m := NewScrollableView(80, 10, sessionState).(*model)
+	m.SetSize(80, 10)
+	m.focused = true
+
+	msg := types.ToolCallMessage("root", tools.ToolCall{
+		ID:       "call_1",
+		Function: tools.FunctionCall{Name: "list", Arguments: `{"path":"."}`},
+	}, tools.Tool{Name: "list"}, types.ToolStatusCompleted)
+	msg.Content = "file1\nfile2\nfile3"
+	msg.ToolDuration = 1500 * time.Millisecond
+	m.messages = append(m.messages, msg)
+	m.views = append(m.views, m.createToolCallView(msg))
+
+	out := ansi.Strip(m.View())
+	assert.Contains(t, out, "1.5s · done")
+	assert.NotContains(t, out, "file2")
+
+	// Enter expands the selected tool call
+	m.selectNextMessage()
+	m.handleKeyPress(tea.KeyPressMsg{Code: tea.KeyEnter})
+	assert.Contains(t, ansi.Strip(m.View()), "file2")
+
+	m.handleKeyPress(tea.KeyPressMsg{Code: tea.KeyEnter})
+	assert.NotContains(t, ansi.Strip(m.View()), "file2")
+
+	// Expand all
+	m.Update(ToggleExpandToolCallsMsg{})
+	assert.Contains(t, ansi.Strip(m.View()), "file2")
+	m.Update(ToggleExpandToolCallsMsg{})
+	assert.NotContains(t, ansi.Strip(m.View()), "file2")
+
+	// Tool calls matching the search are expanded
+	m.startSearch()
+	for _, r := range "file2" {
+		m.handleKeyPress(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+	assert.Contains(t, ansi.Strip(m.View()), "file2")
+}
+
+// BenchmarkView measures the frames per second of the transcript with N
+// messages, both when only the viewport moves and when every message has to
+// be rendered again (after a resize).
+func BenchmarkView(b *testing.B) {
+	for _, count := range []int{10, 100, 1000} {
+		newModel := func() *model {
+			m := NewScrollableView(120, 40, &service.SessionState{}).(*model)
+			m.SetSize(120, 40)
+			for i := range count {
+				msg := types.Agent(types.MessageTypeAssistant, "root", fmt.Sprintf("Message %d with **markdown** and `code`.\n\n- item one\n- item two", i))
+				m.messages = append(m.messages, msg)
+				m.views = append(m.views, m.createMessageView(msg))
+			}
+			return m
+		}
+
+		b.Run(fmt.Sprintf("messages=%d/scroll", count), func(b *testing.B) {
+			m := newModel()
+			_ = m.View()
+
+			b.ResetTimer()
+			for range b.N {
+				_ = m.View()
+			}
+			b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "frames/s")
+		})
+
+		b.Run(fmt.Sprintf("messages=%d/resize", count), func(b *testing.B) {
+			m := newModel()
+
+			b.ResetTimer()
+			for i := range b.N {
+				m.SetSize(120-i%2, 40)
+				_ = m.View()
+			}
+			b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "frames/s")
+		})
+	}
+}
+
+func TestLoadFromSessionShowsFeedback(t *testing.T) {
+	t.Parallel()
+
+	sess := session.New(session.WithUserMessage("Hello"))
+	sess.AddMessage(&session.Message{AgentName: "root", Message: chat.Message{Role: chat.MessageRoleAssistant, Content: "Hi there"}})
+	sess.AddFeedback("root", "Hi there", session.FeedbackNegative, "Too short")
+
+	m := NewScrollableView(80, 10, &service.SessionState{}).(*model)
+	m.SetSize(80, 10)
+	m.LoadFromSession(sess)
+
+	answer := m.messages[len(m.messages)-1]
+	assert.Equal(t, session.FeedbackNegative, answer.Feedback)
+	assert.Contains(t, ansi.Strip(m.View()), "👎 rated down")
+}
+
+func TestDiscardPartialResponse(t *testing.T) {
+	t.Parallel()
+
+	m := NewScrollableView(80, 10, &service.SessionState{}).(*model)
+	m.AddUserMessage("Hello")
+	m.AppendToLastMessage("root", types.MessageTypeAssistantReasoning, "Thinking")
+	m.AppendToLastMessage("root", types.MessageTypeAssistant, "Hel")
+
+	m.DiscardPartialResponse("root")
+
+	assert.Len(t, m.messages, 2)
+	assert.Equal(t, types.MessageTypeUser, m.messages[0].Type)
+	assert.Equal(t, types.MessageTypeSpinner, m.messages[1].Type)
+}
+
+func TestSubAgentMessagesAreNested(t *testing.T) {
+	t.Parallel()
+
+	m := NewScrollableView(80, 10, &service.SessionState{}).(*model)
+	m.SetSize(80, 10)
+	m.SetAgentDepth("root", 0)
+	m.SetAgentDepth("researcher", 1)
+	m.AddUserMessage("Find the sources")
+	m.AppendToLastMessage("researcher", types.MessageTypeAssistant, "Found them")
+	m.AppendToLastMessage("root", types.MessageTypeAssistant, "Done")
+
+	out := ansi.Strip(m.View())
+	assert.Contains(t, out, "Found them")
+	for _, line := range strings.Split(out, "\n") {
+		switch {
+		case strings.Contains(line, "Found them"):
+			assert.True(t, strings.HasPrefix(line, "│ "), line)
+		case strings.Contains(line, "Done"):
+			assert.False(t, strings.HasPrefix(line, "│"), line)
+		}
+	}
+
+	m.Update(ToggleHideSubAgentsMsg{})
+	out = ansi.Strip(m.View())
+	assert.NotContains(t, out, "Found them")
+	assert.Contains(t, out, "Done")
+}
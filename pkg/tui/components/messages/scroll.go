@@ -0,0 +1,52 @@
+package messages
+
+import (
+	"fmt"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/docker/cagent/pkg/tui/styles"
+)
+
+// The scroll indicator takes the last line of the viewport while the user
+// reads the history. Auto-scroll is paused until they get back to the bottom,
+// so it also tells when new output arrived below and how to jump to it.
+
+// showScrollIndicator reports whether the viewport is away from the bottom
+// because the user scrolled up.
+func (m *model) showScrollIndicator() bool {
+	return m.userHasScrolled && m.scrollOffset < m.maxScrollOffset()
+}
+
+// scrollIndicatorRow is the row of the indicator, relative to the top of
+// the viewport.
+func (m *model) scrollIndicatorRow() int {
+	return m.viewportHeight() - 1
+}
+
+// scrollPosition returns how far the viewport is through the transcript, in
+// percents.
+func (m *model) scrollPosition() int {
+	maxOffset := m.maxScrollOffset()
+	if maxOffset == 0 {
+		return 100
+	}
+	return m.scrollOffset * 100 / maxOffset
+}
+
+func (m *model) maxScrollOffset() int {
+	return max(0, m.totalHeight-m.viewportHeight())
+}
+
+func (m *model) scrollIndicatorView(width int) string {
+	status := fmt.Sprintf("%s jump to latest · %d%%", m.keyMap.ScrollToBottom.Help().Key, m.scrollPosition())
+	if m.missedOutput {
+		status = "↓ new output · " + status
+	}
+	status = " " + status + " "
+
+	gap := max(0, width-lipgloss.Width(status))
+	return ansi.Truncate(strings.Repeat(" ", gap)+styles.InfoStyle.Render(status), width, "")
+}
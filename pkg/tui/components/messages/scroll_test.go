@@ -0,0 +1,45 @@
+package messages
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/docker/cagent/pkg/tui/service"
+)
+
+func TestScrollIndicatorWhileReadingHistory(t *testing.T) {
+	t.Parallel()
+
+	m := NewScrollableView(80, 10, &service.SessionState{}).(*model)
+	m.SetSize(80, 10)
+	for i := range 20 {
+		m.AddUserMessage(fmt.Sprintf("message %d", i))
+	}
+
+	assert.NotContains(t, ansi.Strip(m.View()), "jump to latest", "no indicator at the bottom")
+
+	m.scrollPageUp()
+	view := ansi.Strip(m.View())
+	lines := strings.Split(view, "\n")
+	assert.Contains(t, lines[m.scrollIndicatorRow()], "jump to latest")
+	assert.Contains(t, lines[m.scrollIndicatorRow()], fmt.Sprintf("%d%%", m.scrollPosition()))
+	assert.NotContains(t, view, "new output")
+
+	// New messages don't move the viewport but are announced
+	offset := m.scrollOffset
+	m.AddUserMessage("latest")
+	assert.Contains(t, ansi.Strip(m.View()), "↓ new output")
+	assert.Equal(t, offset, m.scrollOffset)
+
+	// Clicking the indicator jumps to the latest message
+	m.handleMouseClick(tea.MouseClickMsg{Button: tea.MouseLeft, Y: m.scrollIndicatorRow()})
+	view = ansi.Strip(m.View())
+	assert.Contains(t, view, "latest")
+	assert.NotContains(t, view, "jump to latest")
+	assert.False(t, m.missedOutput)
+}
@@ -0,0 +1,268 @@
+package messages
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/mattn/go-runewidth"
+
+	"github.com/docker/cagent/pkg/tui/styles"
+	"github.com/docker/cagent/pkg/tui/types"
+)
+
+// searchMode is the state of the transcript search
+type searchMode int
+
+const (
+	searchOff        searchMode = iota
+	searchTyping                // The query is being typed, matches update on every key
+	searchNavigating            // The query is confirmed, n/N jump between matches
+)
+
+// searchMatch is the position of a match in the rendered content, in display columns
+type searchMatch struct {
+	line     int
+	startCol int
+	endCol   int
+}
+
+// searchState encapsulates all state related to transcript search
+type searchState struct {
+	mode    searchMode
+	query   string
+	matches []searchMatch
+	current int    // Index of the current match
+	source  string // Rendered content the matches were computed from
+}
+
+// clear resets all search state
+func (s *searchState) clear() {
+	*s = searchState{}
+}
+
+// IsSearching returns true when the search bar is open and should receive all keys
+func (m *model) IsSearching() bool {
+	return m.search.mode != searchOff
+}
+
+// handleSearchKey handles key presses while searching. Returns false if the key
+// should be handled as a regular key.
+func (m *model) handleSearchKey(msg tea.KeyPressMsg) bool {
+	switch m.search.mode {
+	case searchTyping:
+		switch msg.String() {
+		case "esc":
+			m.stopSearch()
+		case "enter":
+			if m.search.query == "" {
+				m.stopSearch()
+			} else {
+				m.search.mode = searchNavigating
+			}
+		case "backspace":
+			if m.search.query != "" {
+				runes := []rune(m.search.query)
+				m.search.query = string(runes[:len(runes)-1])
+				m.refreshSearch(true)
+			}
+		default:
+			if msg.Text != "" {
+				m.search.query += msg.Text
+				m.refreshSearch(true)
+			}
+		}
+		return true
+
+	case searchNavigating:
+		switch {
+		case msg.String() == "esc":
+			m.stopSearch()
+		case key.Matches(msg, m.keyMap.Search):
+			m.startSearch()
+		case key.Matches(msg, m.keyMap.NextMatch):
+			m.jumpToMatch(m.search.current + 1)
+		case key.Matches(msg, m.keyMap.PreviousMatch):
+			m.jumpToMatch(m.search.current - 1)
+		default:
+			return false
+		}
+		return true
+
+	default:
+		if key.Matches(msg, m.keyMap.Search) {
+			m.startSearch()
+			return true
+		}
+		return false
+	}
+}
+
+func (m *model) startSearch() {
+	m.stopSearch()
+	// Search the whole conversation, not only the recent messages
+	m.loadEarlierMessages(m.renderFrom)
+	m.search.mode = searchTyping
+}
+
+// stopSearch clears the search and collapses back the tool calls it expanded.
+func (m *model) stopSearch() {
+	m.search.clear()
+	m.invalidateAllItems()
+}
+
+// matchesSearch returns true if the content of a message matches the search query.
+func (m *model) matchesSearch(msg *types.Message) bool {
+	return m.search.query != "" && len(findMatches(msg.Content, m.search.query)) > 0
+}
+
+// refreshSearch recomputes the matches if the rendered content changed.
+// When jump is true, the first match below the top of the viewport becomes current.
+func (m *model) refreshSearch(jump bool) {
+	if jump {
+		// The query changed, tool calls matching it are expanded.
+		m.invalidateAllItems()
+	}
+	if m.search.query == "" {
+		m.search.matches = nil
+		m.search.current = 0
+		return
+	}
+
+	m.ensureAllItemsRendered()
+	if !jump && m.search.source == m.rendered {
+		return
+	}
+	m.search.source = m.rendered
+	m.search.matches = findMatches(m.rendered, m.search.query)
+
+	if !jump {
+		m.search.current = min(m.search.current, max(0, len(m.search.matches)-1))
+		return
+	}
+
+	for i, match := range m.search.matches {
+		if match.line >= m.scrollOffset {
+			m.jumpToMatch(i)
+			return
+		}
+	}
+	m.jumpToMatch(0)
+}
+
+// jumpToMatch makes the given match current, wrapping around, and scrolls to it
+func (m *model) jumpToMatch(index int) {
+	count := len(m.search.matches)
+	if count == 0 {
+		return
+	}
+
+	m.search.current = (index%count + count) % count
+	line := m.search.matches[m.search.current].line
+
+	height := m.viewportHeight()
+	if line < m.scrollOffset || line >= m.scrollOffset+height {
+		m.userHasScrolled = true
+		m.setScrollOffset(line - height/2)
+	}
+}
+
+// findMatches returns all the occurrences of query in the rendered content.
+// The search is case-insensitive unless the query contains an uppercase letter.
+func findMatches(rendered, query string) []searchMatch {
+	needle := []rune(query)
+	if len(needle) == 0 {
+		return nil
+	}
+
+	caseSensitive := strings.IndexFunc(query, unicode.IsUpper) >= 0
+	fold := func(r rune) rune {
+		if caseSensitive {
+			return r
+		}
+		return unicode.ToLower(r)
+	}
+	for i, r := range needle {
+		needle[i] = fold(r)
+	}
+
+	var matches []searchMatch
+	for lineIndex, line := range strings.Split(rendered, "\n") {
+		haystack := []rune(ansi.Strip(line))
+		for i := 0; i+len(needle) <= len(haystack); i++ {
+			found := true
+			for j, r := range needle {
+				if fold(haystack[i+j]) != r {
+					found = false
+					break
+				}
+			}
+			if !found {
+				continue
+			}
+
+			startCol := runewidth.StringWidth(string(haystack[:i]))
+			matches = append(matches, searchMatch{
+				line:     lineIndex,
+				startCol: startCol,
+				endCol:   startCol + runewidth.StringWidth(string(haystack[i:i+len(needle)])),
+			})
+			i += len(needle) - 1
+		}
+	}
+
+	return matches
+}
+
+// applySearchHighlight highlights the matches in the visible lines
+func (m *model) applySearchHighlight(lines []string, viewportStartLine int) []string {
+	if len(m.search.matches) == 0 {
+		return lines
+	}
+
+	highlighted := make([]string, len(lines))
+	copy(highlighted, lines)
+
+	for i, match := range m.search.matches {
+		index := match.line - viewportStartLine
+		if index < 0 || index >= len(lines) {
+			continue
+		}
+
+		style := styles.SearchMatchStyle
+		if i == m.search.current {
+			style = styles.SearchCurrentMatchStyle
+		}
+		highlighted[index] = highlightRange(highlighted[index], match.startCol, match.endCol, style)
+	}
+
+	return highlighted
+}
+
+// searchBarView renders the search input and the match counter
+func (m *model) searchBarView(width int) string {
+	prompt := "/" + m.search.query
+	if m.search.mode == searchTyping {
+		prompt += "█"
+	}
+
+	var status string
+	switch {
+	case m.search.query == "":
+	case len(m.search.matches) == 0:
+		status = "no matches"
+	default:
+		status = fmt.Sprintf("%d/%d", m.search.current+1, len(m.search.matches))
+	}
+	if m.search.mode == searchNavigating {
+		status += fmt.Sprintf("  %s/%s next/prev · esc close", m.keyMap.NextMatch.Help().Key, m.keyMap.PreviousMatch.Help().Key)
+	}
+
+	gap := max(1, width-lipgloss.Width(prompt)-lipgloss.Width(status))
+	bar := prompt + strings.Repeat(" ", gap) + styles.MutedStyle.Render(status)
+	return styles.SearchBarStyle.Width(width).Render(ansi.Truncate(bar, width, ""))
+}
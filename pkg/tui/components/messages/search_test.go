@@ -0,0 +1,77 @@
+package messages
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/tui/service"
+	"github.com/docker/cagent/pkg/tui/styles"
+	"github.com/docker/cagent/pkg/tui/types"
+)
+
+func TestFindMatches(t *testing.T) {
+	t.Parallel()
+
+	rendered := "Hello world\n" + styles.ErrorStyle.Render("say héllo, HELLO") + "\nnothing"
+
+	matches := findMatches(rendered, "hello")
+	assert.Equal(t, []searchMatch{
+		{line: 0, startCol: 0, endCol: 5},
+		{line: 1, startCol: 11, endCol: 16},
+	}, matches)
+
+	// Uppercase letters make the search case-sensitive
+	matches = findMatches(rendered, "HELLO")
+	assert.Equal(t, []searchMatch{{line: 1, startCol: 11, endCol: 16}}, matches)
+
+	assert.Empty(t, findMatches(rendered, "absent"))
+}
+
+func TestSearch_TypeAndNavigate(t *testing.T) {
+	t.Parallel()
+
+	m := NewScrollableView(80, 10, &service.SessionState{}).(*model)
+	m.SetSize(80, 10)
+	for _, content := range []string{"first needle", "nothing here", "second needle"} {
+		msg := types.Agent(types.MessageTypeAssistant, "", content)
+		m.messages = append(m.messages, msg)
+		m.views = append(m.views, m.createMessageView(msg))
+	}
+
+	press := func(keys ...string) {
+		for _, k := range keys {
+			m.Update(tea.KeyPressMsg{Code: []rune(k)[0], Text: k})
+		}
+	}
+
+	press("/")
+	require.True(t, m.IsSearching())
+
+	press("n", "e", "e", "d", "l", "e")
+	assert.Equal(t, "needle", m.search.query)
+	require.Len(t, m.search.matches, 2)
+	assert.Equal(t, 0, m.search.current)
+	assert.Contains(t, ansi.Strip(m.View()), "/needle█")
+
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	assert.Equal(t, searchNavigating, m.search.mode)
+
+	press("n")
+	assert.Equal(t, 1, m.search.current)
+	press("n")
+	assert.Equal(t, 0, m.search.current, "navigation wraps around")
+	press("N")
+	assert.Equal(t, 1, m.search.current)
+
+	view := ansi.Strip(m.View())
+	assert.Contains(t, view, "2/2")
+	assert.Contains(t, view, "second needle")
+
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	assert.False(t, m.IsSearching())
+	assert.Empty(t, m.search.matches)
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
 	"github.com/charmbracelet/x/ansi"
 	"github.com/mattn/go-runewidth"
 
@@ -253,6 +254,11 @@ func (m *model) applySelectionHighlight(lines []string, viewportStartLine int) [
 
 // highlightLine applies selection highlighting to a portion of a line
 func (m *model) highlightLine(line string, startCol, endCol int) string {
+	return highlightRange(line, startCol, endCol, styles.SelectionStyle)
+}
+
+// highlightRange renders a portion of a line with the given style
+func highlightRange(line string, startCol, endCol int, style lipgloss.Style) string {
 	// Get plain text for boundary checks
 	plainLine := ansi.Strip(line)
 	plainWidth := runewidth.StringWidth(plainLine)
@@ -267,7 +273,7 @@ func (m *model) highlightLine(line string, startCol, endCol int) string {
 	before := ansi.Cut(line, 0, startCol)
 	selectedText := ansi.Cut(line, startCol, endCol)
 	selectedPlain := ansi.Strip(selectedText)
-	selected := styles.SelectionStyle.Render(selectedPlain)
+	selected := style.Render(selectedPlain)
 	after := ansi.Cut(line, endCol, plainWidth)
 
 	return before + selected + after
@@ -0,0 +1,152 @@
+package messages
+
+import (
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/docker/cagent/pkg/tui/core"
+	msgtypes "github.com/docker/cagent/pkg/tui/messages"
+)
+
+// vimState is the state of the vim mode of the transcript.
+type vimState struct {
+	// pending is the first key of a two-key command: "g" of "gg"
+	pending string
+	// visual is set while lines are selected to be copied. The selection
+	// spans from the anchor line to the cursor line.
+	visual bool
+	anchor int
+	cursor int
+}
+
+// vimEnabled reports whether the keys of the transcript are the vim ones.
+func (m *model) vimEnabled() bool {
+	return m.sessionState != nil && m.sessionState.VimMode && m.focused
+}
+
+// IsSelectingLines returns true while lines are selected in visual mode, and
+// should receive all keys.
+func (m *model) IsSelectingLines() bool {
+	return m.vim.visual
+}
+
+// handleVimKey handles a key press in vim mode. Keys it returns false for are
+// handled by the regular bindings.
+func (m *model) handleVimKey(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	if m.vim.visual {
+		return m.handleVisualKey(msg), true
+	}
+
+	k := m.vim.pending + msg.String()
+	m.vim.pending = ""
+
+	switch k {
+	case "j":
+		m.scrollDown()
+	case "k":
+		m.scrollUp()
+	case "h":
+		m.selectPreviousMessage()
+	case "l":
+		m.selectNextMessage()
+	case "ctrl+d":
+		m.scrollHalfPage(1)
+	case "ctrl+u":
+		m.scrollHalfPage(-1)
+	case "g":
+		m.vim.pending = k
+	case "gg":
+		m.scrollToTop()
+	case "G":
+		m.scrollToBottom()
+	case "v", "V":
+		m.startVisual()
+	case "y":
+		return m.copySelectedMessageToClipboard(), true
+	case "Y":
+		return core.CmdHandler(msgtypes.CopySessionToClipboardMsg{}), true
+	default:
+		return nil, false
+	}
+	return nil, true
+}
+
+func (m *model) handleVisualKey(msg tea.KeyPressMsg) tea.Cmd {
+	k := m.vim.pending + msg.String()
+	m.vim.pending = ""
+
+	switch k {
+	case "j", "down":
+		m.moveVisualCursor(m.vim.cursor + 1)
+	case "k", "up":
+		m.moveVisualCursor(m.vim.cursor - 1)
+	case "ctrl+d":
+		m.moveVisualCursor(m.vim.cursor + m.viewportHeight()/2)
+	case "ctrl+u":
+		m.moveVisualCursor(m.vim.cursor - m.viewportHeight()/2)
+	case "g":
+		m.vim.pending = k
+	case "gg":
+		m.moveVisualCursor(0)
+	case "G":
+		m.moveVisualCursor(m.totalHeight - 1)
+	case "y":
+		cmd := m.copySelectionToClipboard()
+		m.stopVisual()
+		return cmd
+	case "esc", "v", "V":
+		m.stopVisual()
+	}
+	return nil
+}
+
+// startVisual selects the first line of the selected message, or the first
+// visible line.
+func (m *model) startVisual() {
+	line := m.scrollOffset
+	if start, _, ok := m.selectedMessageLines(); ok {
+		line = start
+	}
+	m.vim.visual = true
+	m.vim.anchor = line
+	m.moveVisualCursor(line)
+}
+
+func (m *model) stopVisual() {
+	m.vim.visual = false
+	m.clearSelection()
+}
+
+// moveVisualCursor moves the end of the selection to a line, scrolling to
+// keep it visible. Whole lines are selected.
+func (m *model) moveVisualCursor(line int) {
+	line = max(0, min(line, m.totalHeight-1))
+	m.vim.cursor = line
+
+	m.selection.active = true
+	m.selection.startLine = min(m.vim.anchor, line)
+	m.selection.startCol = 0
+	m.selection.endLine = max(m.vim.anchor, line)
+	m.selection.endCol = m.contentWidth()
+
+	height := m.viewportHeight()
+	switch {
+	case line < m.scrollOffset:
+		m.userHasScrolled = true
+		m.setScrollOffset(line)
+	case line >= m.scrollOffset+height:
+		m.userHasScrolled = true
+		m.setScrollOffset(line - height + 1)
+	}
+}
+
+// scrollHalfPage scrolls half a page down, or up with a negative direction.
+func (m *model) scrollHalfPage(direction int) {
+	if direction < 0 && m.scrollOffset == 0 {
+		m.loadEarlierMessages(loadMoreMessages)
+	}
+	m.userHasScrolled = true
+	m.setScrollOffset(m.scrollOffset + direction*max(1, m.viewportHeight()/2))
+	if m.isAtBottom() {
+		m.userHasScrolled = false
+	}
+}
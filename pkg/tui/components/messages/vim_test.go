@@ -0,0 +1,81 @@
+package messages
+
+import (
+	"fmt"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/tui/service"
+)
+
+func newVimTranscript(t *testing.T) *model {
+	t.Helper()
+
+	m := NewScrollableView(80, 10, &service.SessionState{VimMode: true}).(*model)
+	m.SetSize(80, 10)
+	for i := range 20 {
+		m.AddUserMessage(fmt.Sprintf("message %d", i))
+	}
+	m.Focus()
+	m.View()
+	return m
+}
+
+func pressKeys(m *model, keys string) {
+	for _, r := range keys {
+		m.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+}
+
+func TestVimScroll(t *testing.T) {
+	t.Parallel()
+
+	m := newVimTranscript(t)
+	require.True(t, m.isAtBottom())
+	bottom := m.scrollOffset
+
+	pressKeys(m, "k")
+	assert.Equal(t, bottom-1, m.scrollOffset)
+	pressKeys(m, "j")
+	assert.Equal(t, bottom, m.scrollOffset)
+
+	pressKeys(m, "gg")
+	assert.Equal(t, 0, m.scrollOffset)
+	pressKeys(m, "G")
+	assert.True(t, m.isAtBottom())
+}
+
+func TestVimVisualSelection(t *testing.T) {
+	t.Parallel()
+
+	m := newVimTranscript(t)
+	pressKeys(m, "ggv")
+	require.True(t, m.IsSelectingLines())
+
+	pressKeys(m, "jjj")
+	assert.Equal(t, 0, m.selection.startLine)
+	assert.Equal(t, 3, m.selection.endLine)
+	assert.Contains(t, m.extractSelectedText(), "message 0")
+
+	_, cmd := m.Update(tea.KeyPressMsg{Code: 'y', Text: "y"})
+	assert.NotNil(t, cmd)
+	assert.False(t, m.IsSelectingLines())
+	assert.False(t, m.selection.active)
+}
+
+func TestVimDisabled(t *testing.T) {
+	t.Parallel()
+
+	m := NewScrollableView(80, 10, &service.SessionState{}).(*model)
+	m.SetSize(80, 10)
+	for i := range 20 {
+		m.AddUserMessage(fmt.Sprintf("message %d", i))
+	}
+	m.Focus()
+
+	pressKeys(m, "gg")
+	assert.True(t, m.isAtBottom())
+}
@@ -0,0 +1,70 @@
+package messages
+
+import (
+	"fmt"
+
+	"github.com/docker/cagent/pkg/tui/styles"
+)
+
+const (
+	// maxRenderedMessages is the number of recent messages kept rendered while
+	// following a long conversation.
+	maxRenderedMessages = 200
+	// renderWindowSlack lets the rendered window grow a little past
+	// maxRenderedMessages so that messages are released in batches.
+	renderWindowSlack = 50
+	// loadMoreMessages is the number of older messages rendered again when
+	// scrolling past the top of the rendered window.
+	loadMoreMessages = 100
+)
+
+// releaseOldMessages stops rendering the oldest messages of a long
+// conversation, so that neither the memory used by their rendered content
+// nor the time spent laying out each frame keep growing over a multi-hour
+// run. The messages themselves are kept and rendered again on demand.
+func (m *model) releaseOldMessages() {
+	if m.userHasScrolled || m.search.query != "" {
+		return
+	}
+	if len(m.messages)-m.renderFrom <= maxRenderedMessages+renderWindowSlack {
+		return
+	}
+
+	from := len(m.messages) - maxRenderedMessages
+	if m.selectedMessageIndex >= 0 {
+		from = min(from, m.selectedMessageIndex)
+	}
+	for i := m.renderFrom; i < from; i++ {
+		delete(m.renderedItems, i)
+	}
+	m.renderFrom = from
+}
+
+// loadEarlierMessages renders up to count messages before the rendered
+// window, keeping the viewport on the same content. It returns false when
+// every message is already rendered.
+func (m *model) loadEarlierMessages(count int) bool {
+	if m.renderFrom == 0 || count <= 0 {
+		return false
+	}
+
+	m.ensureAllItemsRendered()
+	prevTotalHeight := m.totalHeight
+
+	m.renderFrom = max(0, m.renderFrom-count)
+	m.ensureAllItemsRendered()
+	m.setScrollOffset(m.scrollOffset + m.totalHeight - prevTotalHeight)
+
+	return true
+}
+
+// earlierMessagesLines returns the line shown in place of the messages that
+// aren't rendered, if any.
+func (m *model) earlierMessagesLines() []string {
+	if m.renderFrom == 0 {
+		return nil
+	}
+
+	text := fmt.Sprintf("↑ %d earlier messages · scroll up to show them", m.renderFrom)
+	return []string{styles.MutedStyle.Render(text), ""}
+}
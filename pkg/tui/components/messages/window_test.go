@@ -0,0 +1,61 @@
+package messages
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/docker/cagent/pkg/tui/service"
+)
+
+func TestLongConversationsReleaseOldMessages(t *testing.T) {
+	t.Parallel()
+
+	m := NewScrollableView(80, 10, &service.SessionState{}).(*model)
+	m.SetSize(80, 10)
+
+	total := 2*maxRenderedMessages + renderWindowSlack
+	for i := range total {
+		m.AddUserMessage(fmt.Sprintf("message %d", i))
+	}
+
+	assert.Positive(t, m.renderFrom)
+	assert.LessOrEqual(t, total-m.renderFrom, maxRenderedMessages+renderWindowSlack)
+	assert.Len(t, m.messages, total, "released messages are kept")
+
+	m.ensureAllItemsRendered()
+	assert.Contains(t, ansi.Strip(m.rendered), fmt.Sprintf("↑ %d earlier messages", m.renderFrom))
+	assert.NotContains(t, ansi.Strip(m.rendered), "message 0\n")
+	for i := range m.renderFrom {
+		assert.NotContains(t, m.renderedItems, i)
+	}
+
+	// Scrolling past the top renders older messages without moving the viewport
+	m.scrollToBottom()
+	m.setScrollOffset(0)
+	m.userHasScrolled = true
+	renderFrom := m.renderFrom
+	m.scrollUp()
+	assert.Equal(t, max(0, renderFrom-loadMoreMessages), m.renderFrom)
+	assert.Positive(t, m.scrollOffset)
+
+	m.scrollToTop()
+	assert.Equal(t, 0, m.renderFrom)
+	assert.NotContains(t, ansi.Strip(m.rendered), "earlier messages")
+}
+
+func TestScrolledConversationsKeepMessagesRendered(t *testing.T) {
+	t.Parallel()
+
+	m := NewScrollableView(80, 10, &service.SessionState{}).(*model)
+	m.SetSize(80, 10)
+	m.userHasScrolled = true
+
+	for i := range maxRenderedMessages + renderWindowSlack + 1 {
+		m.AddUserMessage(fmt.Sprintf("message %d", i))
+	}
+
+	assert.Equal(t, 0, m.renderFrom)
+}
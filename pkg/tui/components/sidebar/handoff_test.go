@@ -0,0 +1,32 @@
+package sidebar
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/docker/cagent/pkg/runtime"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/tui/service"
+)
+
+func TestAgentInfo_Handoff(t *testing.T) {
+	t.Parallel()
+
+	m := New(&service.SessionState{CurrentAgent: "root"}).(*model)
+	m.SetTeamInfo([]runtime.AgentDetails{
+		{Name: "root", Provider: "openai", Model: "gpt-4o"},
+		{Name: "developer", Provider: "openai", Model: "gpt-4o-mini"},
+	})
+	assert.NotContains(t, ansi.Strip(m.agentInfo(80)), "Handoff")
+
+	m.SetHandoff(&session.Handoff{
+		Agent:      "developer",
+		Outcome:    session.HandoffIncomplete,
+		Artifacts:  []string{"parser.go", "lexer.go"},
+		OpenIssues: []string{"Test the parser"},
+		Cost:       0.0125,
+	})
+	assert.Contains(t, ansi.Strip(m.agentInfo(80)), "Handoff: incomplete · 2 files · 1 open issue · $0.01")
+}
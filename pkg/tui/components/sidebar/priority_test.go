@@ -0,0 +1,48 @@
+package sidebar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/docker/cagent/pkg/runtime"
+	"github.com/docker/cagent/pkg/tui/service"
+)
+
+func TestAgentInfo_TaskPriority(t *testing.T) {
+	t.Parallel()
+
+	m := New(&service.SessionState{CurrentAgent: "root"}).(*model)
+	m.SetTeamInfo([]runtime.AgentDetails{
+		{Name: "root", Provider: "openai", Model: "gpt-4o"},
+		{Name: "researcher", Provider: "openai", Model: "gpt-4o-mini"},
+	})
+
+	m.Update(runtime.AgentSwitching(true, "root", "researcher", "background"))
+	assert.Contains(t, ansi.Strip(m.agentInfo(40)), "Priority: background")
+
+	m.Update(runtime.AgentSwitching(false, "researcher", "root", "background"))
+	assert.NotContains(t, ansi.Strip(m.agentInfo(40)), "Priority")
+
+	// Normal tasks aren't shown
+	m.Update(runtime.AgentSwitching(true, "root", "researcher", "normal"))
+	assert.NotContains(t, ansi.Strip(m.agentInfo(40)), "Priority")
+}
+
+func TestAgentInfo_Throttled(t *testing.T) {
+	t.Parallel()
+
+	m := New(&service.SessionState{CurrentAgent: "root"}).(*model)
+	m.SetTeamInfo([]runtime.AgentDetails{
+		{Name: "root", Provider: "openai", Model: "gpt-4o"},
+		{Name: "researcher", Provider: "openai", Model: "gpt-4o-mini"},
+	})
+
+	m.Update(runtime.RequestThrottled("openai/gpt-4o-mini", true, time.Second, "researcher"))
+	assert.Contains(t, ansi.Strip(m.agentInfo(60)), "Throttled: waiting for the rate limit")
+
+	m.Update(runtime.RequestThrottled("openai/gpt-4o-mini", false, 0, "researcher"))
+	assert.NotContains(t, ansi.Strip(m.agentInfo(60)), "Throttled")
+}
@@ -97,3 +97,27 @@ func TestQueueSection_InRenderSections(t *testing.T) {
 	assert.Contains(t, outputWithQueue, "Queue (1)")
 	assert.Contains(t, outputWithQueue, "Pending task")
 }
+
+func TestQueueSection_PendingMessages(t *testing.T) {
+	t.Parallel()
+
+	sessionState := &service.SessionState{}
+	m := New(sessionState).(*model)
+
+	m.SetPendingMessages([]string{"From another client"})
+
+	result := m.queueSection(40)
+
+	assert.Contains(t, result, "Queue (1)")
+	assert.Contains(t, result, "From another client")
+	assert.Contains(t, result, "pending")
+
+	// The messages queued by the runtime can't be cleared from the TUI
+	assert.NotContains(t, result, "Ctrl+X to clear")
+
+	m.SetQueuedMessages([]string{"Local"})
+	result = m.queueSection(40)
+	assert.Contains(t, result, "Queue (2)")
+	assert.Less(t, strings.Index(result, "From another client"), strings.Index(result, "Local"), "pending messages are answered first")
+	assert.Contains(t, result, "Ctrl+X to clear")
+}
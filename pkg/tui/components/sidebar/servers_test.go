@@ -0,0 +1,49 @@
+package sidebar
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/docker/cagent/pkg/runtime"
+	"github.com/docker/cagent/pkg/tools"
+	"github.com/docker/cagent/pkg/tui/service"
+)
+
+func TestToolsetInfo_ServerStatus(t *testing.T) {
+	t.Parallel()
+
+	m := New(&service.SessionState{CurrentAgent: "root"}).(*model)
+	m.SetToolsetInfo(12, false)
+
+	m.Update(runtime.MCPServerStatus(tools.ServerStatus{Server: "github", State: tools.ServerRestarting, Attempt: 2, Error: "the server exited"}, "root"))
+	assert.Contains(t, ansi.Strip(m.toolsetInfo(60)), "Restarting github (attempt 2)")
+
+	m.Update(runtime.MCPServerStatus(tools.ServerStatus{Server: "github", State: tools.ServerReconnected, Attempt: 2, Tools: 5}, "root"))
+	assert.NotContains(t, ansi.Strip(m.toolsetInfo(60)), "github")
+
+	m.Update(runtime.MCPServerStatus(tools.ServerStatus{Server: "docs", State: tools.ServerFailed, Attempt: 5, Error: "command not found"}, "root"))
+	assert.Contains(t, ansi.Strip(m.toolsetInfo(60)), "docs stopped, its tools are unavailable")
+}
+
+func TestSidebar_ServerContainers(t *testing.T) {
+	t.Parallel()
+
+	m := New(&service.SessionState{CurrentAgent: "root"}).(*model)
+	m.SetToolsetInfo(12, false)
+
+	m.Update(runtime.MCPServerStatus(tools.ServerStatus{Server: "github", State: tools.ServerStarting, Container: "node:20-alpine"}, "root"))
+	assert.Contains(t, ansi.Strip(m.toolsetInfo(60)), "Starting github in node:20-alpine")
+
+	m.Update(runtime.MCPServerStatus(tools.ServerStatus{Server: "github", State: tools.ServerRunning, Container: "node:20-alpine"}, "root"))
+	assert.Contains(t, ansi.Strip(m.toolsetInfo(60)), "github running in node:20-alpine")
+
+	// Restarts are shown rather than the state of the container
+	m.Update(runtime.MCPServerStatus(tools.ServerStatus{Server: "github", State: tools.ServerRestarting, Attempt: 1, Container: "node:20-alpine"}, "root"))
+	assert.NotContains(t, ansi.Strip(m.toolsetInfo(60)), "running in")
+
+	m.Update(runtime.MCPServerStatus(tools.ServerStatus{Server: "github", State: tools.ServerReconnected, Attempt: 1, Tools: 5, Container: "node:20-alpine"}, "root"))
+	m.Update(runtime.MCPServerStatus(tools.ServerStatus{Server: "github", State: tools.ServerStopped, Container: "node:20-alpine"}, "root"))
+	assert.Contains(t, ansi.Strip(m.toolsetInfo(60)), "github stopped, its container was removed")
+}
@@ -15,11 +15,13 @@ import (
 	"github.com/docker/cagent/pkg/runtime"
 	"github.com/docker/cagent/pkg/session"
 	"github.com/docker/cagent/pkg/tools"
+	"github.com/docker/cagent/pkg/tools/builtin"
 	"github.com/docker/cagent/pkg/tui/components/scrollbar"
 	"github.com/docker/cagent/pkg/tui/components/spinner"
 	"github.com/docker/cagent/pkg/tui/components/tab"
 	"github.com/docker/cagent/pkg/tui/components/tool/todotool"
 	"github.com/docker/cagent/pkg/tui/components/toolcommon"
+	"github.com/docker/cagent/pkg/tui/core"
 	"github.com/docker/cagent/pkg/tui/core/layout"
 	"github.com/docker/cagent/pkg/tui/service"
 	"github.com/docker/cagent/pkg/tui/styles"
@@ -39,18 +41,37 @@ type Model interface {
 	layout.Positionable
 
 	SetTokenUsage(event *runtime.TokenUsageEvent)
+	SetUsageBaseline(baseline session.UsageBaseline)
+	// SetDeduplication shows what the calls shared between agents saved
+	SetDeduplication(saved runtime.DeduplicationStats)
 	SetTodos(result *tools.ToolCallResult) error
 	SetMode(mode Mode)
 	SetAgentInfo(agentName, model, description string)
 	SetTeamInfo(availableAgents []runtime.AgentDetails)
 	SetAgentSwitching(switching bool)
+	// SetTaskPriority shows the priority of the task an agent runs, or
+	// clears it when empty
+	SetTaskPriority(agentName, priority string)
+	// SetThrottled shows that the requests of an agent wait for the rate
+	// limit of its provider
+	SetThrottled(agentName string, throttled bool)
+	// SetHandoff shows what an agent handed back once done with a task
+	SetHandoff(handoff *session.Handoff)
+	// SetFocusedPane marks the agent whose pane is focused in the split view,
+	// or none when empty
+	SetFocusedPane(agentName string)
 	SetToolsetInfo(availableTools int, loading bool)
 	SetSessionStarred(starred bool)
 	SetQueuedMessages(messages []string)
+	// SetPendingMessages sets the previews of the messages the runtime queued
+	// while the session runs, possibly sent by other clients
+	SetPendingMessages(messages []string)
 	GetSize() (width, height int)
 	LoadFromSession(sess *session.Session)
 	// HandleClick checks if click is on the star and returns true if handled
 	HandleClick(x, y int) bool
+	// UpdateWidgets forwards a message to the registered custom widgets
+	UpdateWidgets(msg tea.Msg) tea.Cmd
 }
 
 // ragIndexingState tracks per-strategy indexing progress
@@ -64,11 +85,12 @@ type ragIndexingState struct {
 type model struct {
 	width             int
 	height            int
-	xPos              int                       // absolute x position on screen
-	yPos              int                       // absolute y position on screen
-	layoutCfg         LayoutConfig              // layout configuration for spacing
-	sessionUsage      map[string]*runtime.Usage // sessionID -> latest usage snapshot
-	sessionAgent      map[string]string         // sessionID -> agent name
+	xPos              int          // absolute x position on screen
+	yPos              int          // absolute y position on screen
+	layoutCfg         LayoutConfig // layout configuration for spacing
+	usage             *runtime.UsageTracker
+	usageBaseline     session.UsageBaseline // typical usage of the past sessions of the current agent
+	deduplication     runtime.DeduplicationStats
 	todoComp          *todotool.SidebarComponent
 	mcpInit           bool
 	ragIndexing       map[string]*ragIndexingState // strategy name -> indexing state
@@ -82,18 +104,33 @@ type model struct {
 	agentDescription  string
 	availableAgents   []runtime.AgentDetails
 	agentSwitching    bool
+	taskPriorities    map[string]string           // Agent -> priority of the task it runs, when not normal
+	throttled         map[string]bool             // Agents whose requests wait for the rate limit of their provider
+	handoffs          map[string]*session.Handoff // Agent -> handoff of the last task it ran
+	focusedPane       string                      // Agent whose pane is focused in the split view
 	availableTools    int
-	toolsLoading      bool // true when more tools may still be loading
+	toolsLoading      bool                                     // true when more tools may still be loading
+	mcpServers        map[string]*runtime.MCPServerStatusEvent // MCP servers restarting, or that couldn't be restarted
+	containers        map[string]*runtime.MCPServerStatusEvent // MCP server -> state of the container it runs in
+	sampling          map[string]*samplingUsage                // MCP server -> usage of the completions it requested
 	sessionState      *service.SessionState
 	workingAgent      string // Name of the agent currently working (empty if none)
 	scrollbar         *scrollbar.Model
 	workingDirectory  string
 	queuedMessages    []string // Truncated preview of queued messages
+	pendingMessages   []string // Preview of the messages queued by the runtime
+	widgets           []Widget // Custom widgets rendered below the built-in sections
 }
 
 // Option is a functional option for configuring the sidebar.
 type Option func(*model)
 
+// WithUsageTracker makes the sidebar record and display usage through a
+// tracker shared with other components.
+func WithUsageTracker(usage *runtime.UsageTracker) Option {
+	return func(m *model) { m.usage = usage }
+}
+
 // WithLayoutConfig sets a custom layout configuration.
 func WithLayoutConfig(cfg LayoutConfig) Option {
 	return func(m *model) { m.layoutCfg = cfg }
@@ -104,10 +141,9 @@ func New(sessionState *service.SessionState, opts ...Option) Model {
 		width:            20,
 		layoutCfg:        DefaultLayoutConfig(),
 		height:           24,
-		sessionUsage:     make(map[string]*runtime.Usage),
-		sessionAgent:     make(map[string]string),
+		usage:            runtime.NewUsageTracker(),
 		todoComp:         todotool.NewSidebarComponent(),
-		spinner:          spinner.New(spinner.ModeSpinnerOnly, styles.SpinnerDotsHighlightStyle),
+		spinner:          spinner.New(spinner.ModeSpinnerOnly, &styles.SpinnerDotsHighlightStyle),
 		sessionTitle:     "New session",
 		ragIndexing:      make(map[string]*ragIndexingState),
 		sessionState:     sessionState,
@@ -121,7 +157,7 @@ func New(sessionState *service.SessionState, opts ...Option) Model {
 }
 
 func (m *model) Init() tea.Cmd {
-	return nil
+	return m.initWidgets()
 }
 
 func (m *model) SetTokenUsage(event *runtime.TokenUsageEvent) {
@@ -129,15 +165,22 @@ func (m *model) SetTokenUsage(event *runtime.TokenUsageEvent) {
 		return
 	}
 
-	// Store/replace by session ID (each event has cumulative totals for that session)
-	usage := *event.Usage
-	m.sessionUsage[event.SessionID] = &usage
-	m.sessionAgent[event.SessionID] = event.AgentName
+	m.usage.Record(event)
 
 	// Mark session as having content once we receive token usage
 	m.sessionHasContent = true
 }
 
+// SetUsageBaseline sets the typical usage the current session is compared to
+func (m *model) SetUsageBaseline(baseline session.UsageBaseline) {
+	m.usageBaseline = baseline
+}
+
+// SetDeduplication sets what the calls shared between agents saved
+func (m *model) SetDeduplication(saved runtime.DeduplicationStats) {
+	m.deduplication = saved
+}
+
 func (m *model) SetTodos(result *tools.ToolCallResult) error {
 	return m.todoComp.SetTodos(result)
 }
@@ -168,7 +211,62 @@ func (m *model) SetAgentSwitching(switching bool) {
 	m.agentSwitching = switching
 }
 
+// SetThrottled sets whether the requests of an agent wait for the rate limit
+// of its provider.
+func (m *model) SetThrottled(agentName string, throttled bool) {
+	if !throttled {
+		delete(m.throttled, agentName)
+		return
+	}
+	if m.throttled == nil {
+		m.throttled = map[string]bool{}
+	}
+	m.throttled[agentName] = true
+}
+
+// SetTaskPriority sets the priority of the task an agent runs. Normal tasks
+// aren't shown.
+func (m *model) SetTaskPriority(agentName, priority string) {
+	if priority == "" || priority == builtin.TaskPriorityNormal {
+		delete(m.taskPriorities, agentName)
+		return
+	}
+	if m.taskPriorities == nil {
+		m.taskPriorities = map[string]string{}
+	}
+	m.taskPriorities[agentName] = priority
+}
+
+// SetHandoff sets the handoff of the last task an agent ran
+func (m *model) SetHandoff(handoff *session.Handoff) {
+	if m.handoffs == nil {
+		m.handoffs = map[string]*session.Handoff{}
+	}
+	m.handoffs[handoff.Agent] = handoff
+}
+
 // SetToolsetInfo sets the number of available tools and loading state
+// SetFocusedPane sets the agent whose pane is focused in the split view
+func (m *model) SetFocusedPane(agentName string) {
+	m.focusedPane = agentName
+}
+
+// AddSamplingUsage adds the usage of a completion requested by an MCP server
+// to the usage of the server.
+func (m *model) AddSamplingUsage(event *runtime.SamplingUsageEvent) {
+	if m.sampling == nil {
+		m.sampling = map[string]*samplingUsage{}
+	}
+	usage, ok := m.sampling[event.Server]
+	if !ok {
+		usage = &samplingUsage{}
+		m.sampling[event.Server] = usage
+	}
+	usage.requests++
+	usage.tokens += event.InputTokens + event.OutputTokens
+	usage.cost += event.Cost
+}
+
 func (m *model) SetToolsetInfo(availableTools int, loading bool) {
 	m.availableTools = availableTools
 	m.toolsLoading = loading
@@ -184,6 +282,11 @@ func (m *model) SetQueuedMessages(messages []string) {
 	m.queuedMessages = messages
 }
 
+// SetPendingMessages sets the list of the messages queued by the runtime
+func (m *model) SetPendingMessages(messages []string) {
+	m.pendingMessages = messages
+}
+
 // HandleClick checks if click is on the star and returns true if it was
 // x and y are coordinates relative to the sidebar's top-left corner
 // This does NOT toggle the state - caller should handle that
@@ -216,13 +319,7 @@ func (m *model) LoadFromSession(sess *session.Session) {
 	}
 
 	// Load token usage from session
-	if sess.InputTokens > 0 || sess.OutputTokens > 0 || sess.Cost > 0 {
-		m.sessionUsage[sess.ID] = &runtime.Usage{
-			InputTokens:  sess.InputTokens,
-			OutputTokens: sess.OutputTokens,
-			Cost:         sess.Cost,
-		}
-	}
+	m.usage.Restore(sess)
 
 	// Load session title
 	if sess.Title != "" {
@@ -232,6 +329,18 @@ func (m *model) LoadFromSession(sess *session.Session) {
 	// Load starred status
 	m.sessionStarred = sess.Starred
 
+	// Load persisted todos
+	if len(sess.Todos) > 0 {
+		_ = m.todoComp.SetTodos(&tools.ToolCallResult{Meta: sess.Todos})
+	}
+
+	// Load the handoffs of the tasks transferred to sub-agents
+	for _, item := range sess.Messages {
+		if item.IsSubSession() && item.SubSession.Handoff != nil {
+			m.SetHandoff(item.SubSession.Handoff)
+		}
+	}
+
 	// Session has content if it has messages or token usage
 	m.sessionHasContent = len(sess.Messages) > 0 || sess.InputTokens > 0 || sess.OutputTokens > 0
 }
@@ -251,14 +360,9 @@ func formatCost(cost float64) string {
 }
 
 // contextPercent returns a context usage percentage string when a single session has a limit.
-func (m *model) contextPercent() string {
-	if len(m.sessionUsage) == 1 {
-		for _, usage := range m.sessionUsage {
-			if usage.ContextLimit > 0 {
-				percent := (float64(usage.ContextLength) / float64(usage.ContextLimit)) * 100
-				return fmt.Sprintf("%.0f%%", percent)
-			}
-		}
+func contextPercent(totals runtime.UsageTotals) string {
+	if percent, ok := totals.ContextPercent(); ok {
+		return fmt.Sprintf("%.0f%%", percent)
 	}
 	return "0%"
 }
@@ -340,6 +444,7 @@ func (m *model) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
 		return m, m.spinner.Init()
 	case *runtime.StreamStoppedEvent:
 		m.workingAgent = ""
+		delete(m.throttled, msg.AgentName)
 		return m, nil
 	case *runtime.AgentInfoEvent:
 		m.SetAgentInfo(msg.AgentName, msg.Model, msg.Description)
@@ -349,6 +454,14 @@ func (m *model) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
 		return m, nil
 	case *runtime.AgentSwitchingEvent:
 		m.SetAgentSwitching(msg.Switching)
+		if msg.Switching {
+			m.SetTaskPriority(msg.ToAgent, msg.Priority)
+		} else {
+			m.SetTaskPriority(msg.FromAgent, "")
+		}
+		return m, nil
+	case *runtime.RequestThrottledEvent:
+		m.SetThrottled(msg.AgentName, msg.Throttled)
 		return m, nil
 	case *runtime.ToolsetInfoEvent:
 		m.SetToolsetInfo(msg.AvailableTools, msg.Loading)
@@ -356,11 +469,38 @@ func (m *model) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
 			return m, m.spinner.Init()
 		}
 		return m, nil
+	case *runtime.SamplingUsageEvent:
+		m.AddSamplingUsage(msg)
+		return m, nil
+	case *runtime.MCPServerStatusEvent:
+		switch msg.Status {
+		case tools.ServerStarting, tools.ServerRunning, tools.ServerStopped:
+			if m.containers == nil {
+				m.containers = map[string]*runtime.MCPServerStatusEvent{}
+			}
+			m.containers[msg.Server] = msg
+			if msg.Status == tools.ServerStarting {
+				return m, m.spinner.Init()
+			}
+			return m, nil
+		}
+		if msg.Status == tools.ServerReconnected {
+			delete(m.mcpServers, msg.Server)
+			return m, nil
+		}
+		if m.mcpServers == nil {
+			m.mcpServers = map[string]*runtime.MCPServerStatusEvent{}
+		}
+		m.mcpServers[msg.Server] = msg
+		if msg.Status == tools.ServerRestarting {
+			return m, m.spinner.Init()
+		}
+		return m, nil
 	default:
 		var cmds []tea.Cmd
 
 		// Update main spinner when MCP is initializing, tools are loading, or an agent is working
-		if m.mcpInit || m.toolsLoading || m.workingAgent != "" {
+		if m.mcpInit || m.toolsLoading || m.workingAgent != "" || m.restartingServers() {
 			model, cmd := m.spinner.Update(msg)
 			m.spinner = model.(spinner.Spinner)
 			cmds = append(cmds, cmd)
@@ -379,6 +519,8 @@ func (m *model) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
 
 // View renders the component
 func (m *model) View() string {
+	defer core.TrackRender("sidebar")()
+
 	var content string
 	if m.mode == ModeVertical {
 		content = m.verticalView()
@@ -489,6 +631,10 @@ func (m *model) renderSections(contentWidth int) []string {
 	m.todoComp.SetSize(contentWidth)
 	appendSection(strings.TrimSuffix(m.todoComp.Render(), "\n"))
 
+	for _, w := range m.widgets {
+		appendSection(widgetView(w, contentWidth))
+	}
+
 	return lines
 }
 
@@ -590,41 +736,156 @@ func (m *model) formatProgress(state *ragIndexingState) string {
 }
 
 func (m *model) tokenUsage(contentWidth int) string {
-	var totalTokens int64
-	var totalCost float64
-	for _, usage := range m.sessionUsage {
-		totalTokens += usage.InputTokens + usage.OutputTokens
-		totalCost += usage.Cost
-	}
+	totals := m.usage.Totals()
 
 	var tokenUsage strings.Builder
-	fmt.Fprintf(&tokenUsage, "%s", formatTokenCount(totalTokens))
-	if ctxText := m.contextPercent(); ctxText != "" {
+	fmt.Fprintf(&tokenUsage, "%s", formatTokenCount(totals.Tokens()))
+	if ctxText := contextPercent(totals); ctxText != "" {
 		fmt.Fprintf(&tokenUsage, " (%s)", ctxText)
 	}
-	fmt.Fprintf(&tokenUsage, " %s", styles.TabAccentStyle.Render("$"+formatCost(totalCost)))
+	fmt.Fprintf(&tokenUsage, " %s", styles.TabAccentStyle.Render("$"+formatCost(totals.Cost)))
+	if delta := m.usageDelta(totals); delta != "" {
+		fmt.Fprintf(&tokenUsage, "\n%s", delta)
+	}
+	if saved := m.deduplicationSaved(); saved != "" {
+		fmt.Fprintf(&tokenUsage, "\n%s", styles.MutedStyle.Render(saved))
+	}
+	for _, line := range m.toolUsage(contentWidth) {
+		fmt.Fprintf(&tokenUsage, "\n%s", line)
+	}
+	for _, line := range m.samplingUsage(contentWidth) {
+		fmt.Fprintf(&tokenUsage, "\n%s", line)
+	}
 
 	return m.renderTab("Token Usage", tokenUsage.String(), contentWidth)
 }
 
+// maxToolUsageLines is how many tools the token usage details list.
+const maxToolUsageLines = 5
+
+// toolUsage lists the tools whose results take the most tokens in the
+// context, e.g. "github_get_file 12.5K (3 calls)".
+func (m *model) toolUsage(contentWidth int) []string {
+	tools := m.usage.Tools()
+	if len(tools) == 0 {
+		return nil
+	}
+
+	lines := []string{styles.MutedStyle.Render("Tool results:")}
+	for i, tool := range tools {
+		if i == maxToolUsageLines {
+			lines = append(lines, styles.MutedStyle.Render(fmt.Sprintf("  +%d more", len(tools)-i)))
+			break
+		}
+
+		calls := "calls"
+		if tool.Calls == 1 {
+			calls = "call"
+		}
+		usage := fmt.Sprintf(" %s (%d %s)", formatTokenCount(tool.Tokens), tool.Calls, calls)
+		name := toolcommon.TruncateText("  "+tool.Name, contentWidth-lipgloss.Width(usage))
+		lines = append(lines, name+styles.MutedStyle.Render(usage))
+	}
+	return lines
+}
+
+// samplingUsage is the usage of the completions requested by an MCP server.
+type samplingUsage struct {
+	requests int
+	tokens   int64
+	cost     float64
+}
+
+// samplingUsage lists the MCP servers that requested completions from the
+// model, e.g. "github 3.2K $0.01 (2 requests)". Their usage isn't part of the
+// session's: it's not in the context of the agent.
+func (m *model) samplingUsage(contentWidth int) []string {
+	if len(m.sampling) == 0 {
+		return nil
+	}
+
+	lines := []string{styles.MutedStyle.Render("Sampling by MCP servers:")}
+	for _, server := range slices.Sorted(maps.Keys(m.sampling)) {
+		usage := m.sampling[server]
+
+		requests := "requests"
+		if usage.requests == 1 {
+			requests = "request"
+		}
+		text := fmt.Sprintf(" %s $%s (%d %s)", formatTokenCount(usage.tokens), formatCost(usage.cost), usage.requests, requests)
+		name := toolcommon.TruncateText("  "+server, contentWidth-lipgloss.Width(text))
+		lines = append(lines, name+styles.MutedStyle.Render(text))
+	}
+	return lines
+}
+
 // tokenUsageSummary returns a single-line summary for horizontal layout.
 func (m *model) tokenUsageSummary() string {
-	if len(m.sessionUsage) == 0 {
+	totals := m.usage.Totals()
+	if totals.Sessions == 0 {
+		return ""
+	}
+
+	summary := fmt.Sprintf("Tokens: %s | Cost: $%s", formatTokenCount(totals.Tokens()), formatCost(totals.Cost))
+	if ctxText := contextPercent(totals); ctxText != "" {
+		summary += " | Context: " + ctxText
+	}
+	if delta := m.usageDelta(totals); delta != "" {
+		summary += " | " + delta
+	}
+	if saved := m.deduplicationSaved(); saved != "" {
+		summary += " | " + saved
+	}
+
+	return summary
+}
+
+// unusualUsageRatio is the ratio to the typical usage above which a session is highlighted.
+const unusualUsageRatio = 2
+
+// usageDelta compares the session usage to the typical usage of the agent, e.g. "cost 2.3× typical".
+// It's empty when there's not enough history to compare against.
+func (m *model) usageDelta(totals runtime.UsageTotals) string {
+	label, ratio := "cost", 0.0
+	ok := false
+	if totals.Cost > 0 {
+		ratio, ok = m.usageBaseline.CostRatio(totals.Cost)
+	}
+	if !ok && totals.Tokens() > 0 {
+		label = "tokens"
+		ratio, ok = m.usageBaseline.TokensRatio(totals.Tokens())
+	}
+	if !ok {
 		return ""
 	}
 
-	var totalTokens int64
-	var totalCost float64
-	for _, usage := range m.sessionUsage {
-		totalTokens += usage.InputTokens + usage.OutputTokens
-		totalCost += usage.Cost
+	delta := fmt.Sprintf("%s %.1f× typical", label, ratio)
+	if ratio >= unusualUsageRatio {
+		return styles.WarningStyle.Render(delta)
 	}
+	return styles.MutedStyle.Render(delta)
+}
 
-	if ctxText := m.contextPercent(); ctxText != "" {
-		return fmt.Sprintf("Tokens: %s | Cost: $%s | Context: %s", formatTokenCount(totalTokens), formatCost(totalCost), ctxText)
+// deduplicationSaved describes what the calls shared between agents saved,
+// e.g. "saved 3 calls, 12.5K tokens, $0.04". It's empty when nothing was shared.
+func (m *model) deduplicationSaved() string {
+	saved := m.deduplication
+	if saved.Calls() == 0 {
+		return ""
 	}
 
-	return fmt.Sprintf("Tokens: %s | Cost: $%s", formatTokenCount(totalTokens), formatCost(totalCost))
+	calls := "calls"
+	if saved.Calls() == 1 {
+		calls = "call"
+	}
+	text := fmt.Sprintf("saved %d %s", saved.Calls(), calls)
+	if saved.Tokens > 0 {
+		text += ", " + formatTokenCount(saved.Tokens) + " tokens"
+	}
+	if saved.Cost > 0 {
+		text += ", $" + formatCost(saved.Cost)
+	}
+	return text
 }
 
 func (m *model) sessionInfo(contentWidth int) string {
@@ -642,31 +903,41 @@ func (m *model) sessionInfo(contentWidth int) string {
 
 // queueSection renders the queued messages section
 func (m *model) queueSection(contentWidth int) string {
-	if len(m.queuedMessages) == 0 {
+	count := len(m.pendingMessages) + len(m.queuedMessages)
+	if count == 0 {
 		return ""
 	}
 
 	maxMsgWidth := contentWidth - treePrefixWidth
 	var lines []string
 
-	for i, msg := range m.queuedMessages {
+	// The messages the runtime queued are answered first
+	const pendingLabel = " pending"
+	for i, msg := range append(slices.Clone(m.pendingMessages), m.queuedMessages...) {
 		// Determine prefix based on position
 		var prefix string
-		if i == len(m.queuedMessages)-1 {
+		if i == count-1 {
 			prefix = styles.MutedStyle.Render("└ ")
 		} else {
 			prefix = styles.MutedStyle.Render("├ ")
 		}
 
 		// Truncate message and add prefix
+		if i < len(m.pendingMessages) {
+			truncated := toolcommon.TruncateText(msg, maxMsgWidth-len(pendingLabel))
+			lines = append(lines, prefix+truncated+styles.MutedStyle.Render(pendingLabel))
+			continue
+		}
 		truncated := toolcommon.TruncateText(msg, maxMsgWidth)
 		lines = append(lines, prefix+truncated)
 	}
 
 	// Add hint for clearing
-	lines = append(lines, styles.MutedStyle.Render("  Ctrl+X to clear"))
+	if len(m.queuedMessages) > 0 {
+		lines = append(lines, styles.MutedStyle.Render("  Ctrl+X to clear"))
+	}
 
-	title := fmt.Sprintf("Queue (%d)", len(m.queuedMessages))
+	title := fmt.Sprintf("Queue (%d)", count)
 	return m.renderTab(title, strings.Join(lines, "\n"), contentWidth)
 }
 
@@ -710,6 +981,9 @@ func (m *model) renderAgentEntry(content *strings.Builder, agent runtime.AgentDe
 	}
 	// Agent name
 	agentNameText := prefix + styles.TabAccentStyle.Render(agent.Name)
+	if agent.Name == m.focusedPane {
+		agentNameText += " " + styles.MutedStyle.Render("◧ pane")
+	}
 	// Shortcut hint (^1, ^2, etc.) - show for agents 1-9
 	var shortcutHint string
 	if index >= 0 && index < 9 {
@@ -733,6 +1007,24 @@ func (m *model) renderAgentEntry(content *strings.Builder, agent runtime.AgentDe
 		content.WriteString(toolcommon.TruncateText(desc, maxWidth))
 	}
 
+	if priority, ok := m.taskPriorities[agent.Name]; ok {
+		content.WriteString("\n")
+		content.WriteString(styles.MutedStyle.Render("├ "))
+		content.WriteString(toolcommon.TaskPriorityStyle(priority).Render(toolcommon.TruncateText("Priority: "+priority, maxWidth)))
+	}
+
+	if m.throttled[agent.Name] {
+		content.WriteString("\n")
+		content.WriteString(styles.MutedStyle.Render("├ "))
+		content.WriteString(styles.WarningStyle.Render(toolcommon.TruncateText("Throttled: waiting for the rate limit", maxWidth)))
+	}
+
+	if handoff, ok := m.handoffs[agent.Name]; ok {
+		content.WriteString("\n")
+		content.WriteString(styles.MutedStyle.Render("├ "))
+		content.WriteString(toolcommon.TruncateText("Handoff: "+toolcommon.HandoffSummary(handoff), maxWidth))
+	}
+
 	content.WriteString("\n")
 	content.WriteString(styles.MutedStyle.Render("├ "))
 	content.WriteString(toolcommon.TruncateText("Provider: "+agent.Provider, maxWidth))
@@ -747,6 +1039,7 @@ func (m *model) toolsetInfo(contentWidth int) string {
 
 	// Tools status line
 	lines = append(lines, m.renderToolsStatus())
+	lines = append(lines, m.renderServerStatuses(contentWidth)...)
 
 	// Toggle indicators with shortcuts
 	toggles := []struct {
@@ -757,6 +1050,9 @@ func (m *model) toolsetInfo(contentWidth int) string {
 		{m.sessionState.YoloMode, "YOLO mode enabled", "^y"},
 		{m.sessionState.HideToolResults, "Tool output hidden", "^o"},
 		{m.sessionState.SplitDiffView, "Split Diff View enabled", "^t"},
+		{m.sessionState.RawMarkdown, "Raw markdown", "/raw"},
+		{m.sessionState.ExpandToolCalls, "Tool calls expanded", "/expand"},
+		{m.sessionState.HideSubAgents, "Sub-agents hidden", "/subagents"},
 	}
 
 	for _, toggle := range toggles {
@@ -786,6 +1082,54 @@ func (m *model) renderToolsStatus() string {
 	return ""
 }
 
+// renderServerStatuses renders the containers of the MCP servers run in a
+// sandbox, and the MCP servers being restarted, or that couldn't be restarted
+func (m *model) renderServerStatuses(contentWidth int) []string {
+	var lines []string
+	for _, server := range slices.Sorted(maps.Keys(m.containers)) {
+		// Restarts are shown below
+		if _, ok := m.mcpServers[server]; ok {
+			continue
+		}
+
+		status := m.containers[server]
+		switch {
+		case status.Status == tools.ServerStarting:
+			lines = append(lines, m.spinner.View()+styles.TabPrimaryStyle.Render(toolcommon.TruncateText(fmt.Sprintf(" Starting %s in %s…", server, status.Container), contentWidth-2)))
+		case status.Status == tools.ServerRunning:
+			lines = append(lines, styles.TabAccentStyle.Render("▣")+styles.TabPrimaryStyle.Render(toolcommon.TruncateText(fmt.Sprintf(" %s running in %s", server, status.Container), contentWidth-2)))
+		case status.Error != "":
+			lines = append(lines, styles.ErrorStyle.Render(toolcommon.TruncateText(fmt.Sprintf("✗ %s couldn't start in %s", server, status.Container), contentWidth)))
+		default:
+			lines = append(lines, styles.MutedStyle.Render(toolcommon.TruncateText(fmt.Sprintf("□ %s stopped, its container was removed", server), contentWidth)))
+		}
+	}
+	for _, server := range slices.Sorted(maps.Keys(m.mcpServers)) {
+		status := m.mcpServers[server]
+		switch status.Status {
+		case tools.ServerRestarting:
+			lines = append(lines, m.spinner.View()+styles.WarningStyle.Render(toolcommon.TruncateText(fmt.Sprintf(" Restarting %s (attempt %d)…", server, status.Attempt), contentWidth-2)))
+		case tools.ServerFailed:
+			lines = append(lines, styles.ErrorStyle.Render(toolcommon.TruncateText("✗ "+server+" stopped, its tools are unavailable", contentWidth)))
+		}
+	}
+	return lines
+}
+
+func (m *model) restartingServers() bool {
+	for _, status := range m.mcpServers {
+		if status.Status == tools.ServerRestarting {
+			return true
+		}
+	}
+	for _, status := range m.containers {
+		if status.Status == tools.ServerStarting {
+			return true
+		}
+	}
+	return false
+}
+
 // renderToggleIndicator renders a toggle status with its keyboard shortcut
 func (m *model) renderToggleIndicator(label, shortcut string, contentWidth int) string {
 	indicator := styles.TabAccentStyle.Render("✓") + styles.TabPrimaryStyle.Render(" "+label)
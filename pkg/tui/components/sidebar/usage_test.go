@@ -0,0 +1,81 @@
+package sidebar
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/docker/cagent/pkg/runtime"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/tui/service"
+)
+
+func TestTokenUsageSummary_ComparesToBaseline(t *testing.T) {
+	t.Parallel()
+
+	m := New(&service.SessionState{}).(*model)
+	m.SetTokenUsage(runtime.TokenUsage("session", "root", 1000, 0, 1000, 0, 4.6).(*runtime.TokenUsageEvent))
+
+	// Not enough history: no comparison
+	assert.NotContains(t, m.tokenUsageSummary(), "typical")
+
+	m.SetUsageBaseline(session.UsageBaseline{AgentName: "root", Sessions: 5, AverageTokens: 500, AverageCost: 2})
+	assert.Contains(t, ansi.Strip(m.tokenUsageSummary()), "cost 2.3× typical")
+}
+
+func TestTokenUsageSummary_ComparesTokensWithoutCost(t *testing.T) {
+	t.Parallel()
+
+	m := New(&service.SessionState{}).(*model)
+	m.SetTokenUsage(runtime.TokenUsage("session", "root", 250, 0, 250, 0, 0).(*runtime.TokenUsageEvent))
+	m.SetUsageBaseline(session.UsageBaseline{AgentName: "root", Sessions: 5, AverageTokens: 500})
+
+	assert.Contains(t, ansi.Strip(m.tokenUsageSummary()), "tokens 0.5× typical")
+}
+
+func TestTokenUsageSummary_ShowsDeduplicationSavings(t *testing.T) {
+	t.Parallel()
+
+	m := New(&service.SessionState{}).(*model)
+	m.SetTokenUsage(runtime.TokenUsage("session", "root", 1000, 0, 1000, 0, 0.5).(*runtime.TokenUsageEvent))
+	assert.NotContains(t, m.tokenUsageSummary(), "saved")
+
+	m.SetDeduplication(runtime.DeduplicationStats{ModelCalls: 2, ToolCalls: 1, Tokens: 12500, Cost: 0.04})
+	assert.Contains(t, ansi.Strip(m.tokenUsageSummary()), "saved 3 calls, 12.5K tokens, $0.04")
+}
+
+func TestTokenUsage_ListsToolResults(t *testing.T) {
+	t.Parallel()
+
+	m := New(&service.SessionState{}).(*model)
+	event := runtime.TokenUsage("session", "root", 1000, 0, 1000, 0, 0).(*runtime.TokenUsageEvent)
+	assert.NotContains(t, ansi.Strip(m.tokenUsage(40)), "Tool results")
+
+	event.Usage.Tools = []runtime.ToolUsage{
+		{Name: "github_get_file", Calls: 3, Tokens: 12500},
+		{Name: "read_file", Calls: 1, Tokens: 800},
+	}
+	m.SetTokenUsage(event)
+
+	usage := ansi.Strip(m.tokenUsage(40))
+	assert.Contains(t, usage, "Tool results:")
+	assert.Contains(t, usage, "github_get_file 12.5K (3 calls)")
+	assert.Contains(t, usage, "read_file 800 (1 call)")
+}
+
+func TestTokenUsage_ListsSamplingByServer(t *testing.T) {
+	t.Parallel()
+
+	m := New(&service.SessionState{}).(*model)
+	assert.NotContains(t, ansi.Strip(m.tokenUsage(60)), "Sampling")
+
+	m.Update(runtime.SamplingUsage("github", "openai/gpt-4o", 1200, 300, 0.01, "root"))
+	m.Update(runtime.SamplingUsage("github", "openai/gpt-4o", 1500, 200, 0.02, "root"))
+	m.Update(runtime.SamplingUsage("docs", "openai/gpt-4o", 400, 100, 0, "root"))
+
+	usage := ansi.Strip(m.tokenUsage(60))
+	assert.Contains(t, usage, "Sampling by MCP servers:")
+	assert.Contains(t, usage, "github 3.2K $0.03 (2 requests)")
+	assert.Contains(t, usage, "docs 500 $0.00 (1 request)")
+}
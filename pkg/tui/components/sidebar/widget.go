@@ -0,0 +1,73 @@
+package sidebar
+
+import (
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// Widget is a custom panel rendered in the vertical sidebar, below the
+// built-in sections. It lets embedders show their own information
+// (Kubernetes context, cost per customer...) without forking the TUI.
+//
+// Widgets receive the messages handled by the chat page, including
+// runtime events, so they can react to what the agents are doing.
+type Widget interface {
+	// Init is called every time the sidebar is created, e.g. when a new
+	// session is started or a session is loaded.
+	Init() tea.Cmd
+	Update(msg tea.Msg) (Widget, tea.Cmd)
+	// View renders the widget for the given content width.
+	// An empty string hides the widget.
+	View(width int) string
+	// PreferredHeight is the maximum number of lines the widget should take.
+	// Zero means no limit.
+	PreferredHeight() int
+}
+
+// WithWidgets registers extra widgets, rendered in order below the built-in sections.
+func WithWidgets(widgets ...Widget) Option {
+	return func(m *model) {
+		m.widgets = append(m.widgets, widgets...)
+	}
+}
+
+// UpdateWidgets forwards a message to all registered widgets.
+func (m *model) UpdateWidgets(msg tea.Msg) tea.Cmd {
+	if len(m.widgets) == 0 {
+		return nil
+	}
+
+	var cmds []tea.Cmd
+	for i, w := range m.widgets {
+		updated, cmd := w.Update(msg)
+		m.widgets[i] = updated
+		cmds = append(cmds, cmd)
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *model) initWidgets() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, w := range m.widgets {
+		cmds = append(cmds, w.Init())
+	}
+	return tea.Batch(cmds...)
+}
+
+// widgetView renders a widget, capped to its preferred height.
+func widgetView(w Widget, contentWidth int) string {
+	view := strings.TrimSuffix(w.View(contentWidth), "\n")
+	if view == "" {
+		return ""
+	}
+
+	if maxLines := w.PreferredHeight(); maxLines > 0 {
+		lines := strings.Split(view, "\n")
+		if len(lines) > maxLines {
+			view = strings.Join(lines[:maxLines], "\n")
+		}
+	}
+
+	return view
+}
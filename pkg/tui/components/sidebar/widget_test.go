@@ -0,0 +1,55 @@
+package sidebar
+
+import (
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/docker/cagent/pkg/runtime"
+	"github.com/docker/cagent/pkg/tui/service"
+)
+
+// titleWidget shows the latest session title it has seen.
+type titleWidget struct {
+	title string
+	lines int
+}
+
+func (w *titleWidget) Init() tea.Cmd { return nil }
+
+func (w *titleWidget) Update(msg tea.Msg) (Widget, tea.Cmd) {
+	if e, ok := msg.(*runtime.SessionTitleEvent); ok {
+		w.title = e.Title
+	}
+	return w, nil
+}
+
+func (w *titleWidget) View(int) string {
+	if w.title == "" {
+		return ""
+	}
+	return strings.Repeat("Title: "+w.title+"\n", 5)
+}
+
+func (w *titleWidget) PreferredHeight() int { return w.lines }
+
+func TestWidgets_ReceiveEventsAndRender(t *testing.T) {
+	t.Parallel()
+
+	widget := &titleWidget{lines: 2}
+	m := New(&service.SessionState{}, WithWidgets(widget)).(*model)
+
+	// Empty widgets are hidden
+	assert.NotContains(t, strings.Join(m.renderSections(40), "\n"), "Title:")
+
+	m.UpdateWidgets(runtime.SessionTitle("session-1", "My title"))
+	assert.Equal(t, "My title", widget.title)
+
+	lines := m.renderSections(40)
+	assert.Equal(t, "Title: My title", lines[len(lines)-1])
+
+	// The widget is capped to its preferred height
+	assert.Equal(t, 2, strings.Count(strings.Join(lines, "\n"), "Title: My title"))
+}
@@ -33,7 +33,7 @@ type tickMsg struct {
 }
 
 type Spinner struct {
-	dotsStyle      lipgloss.Style
+	dotsStyle      *lipgloss.Style // points to a styles variable so theme changes apply
 	messages       []string
 	mode           Mode
 	currentMessage string
@@ -69,7 +69,7 @@ var defaultMessages = []string{
 	"Untangling yarn",
 }
 
-func New(mode Mode, dotsStyle lipgloss.Style) Spinner {
+func New(mode Mode, dotsStyle *lipgloss.Style) Spinner {
 	return Spinner{
 		dotsStyle:      dotsStyle,
 		messages:       defaultMessages,
@@ -0,0 +1,183 @@
+package statusbar
+
+import (
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/docker/cagent/pkg/runtime"
+	"github.com/docker/cagent/pkg/tui/service"
+	"github.com/docker/cagent/pkg/tui/styles"
+)
+
+// State is what the agent is doing.
+type State string
+
+const (
+	StateIdle          State = "idle"
+	StateStreaming     State = "streaming"
+	StateRunningTool   State = "running tool"
+	StateAwaitingInput State = "awaiting input"
+	StateError         State = "error"
+)
+
+// Status is the line showing the active agent, its model, what it is doing,
+// the approval mode of the tool calls and the backend the TUI is connected to.
+// It is kept up to date from the runtime events.
+type Status struct {
+	width        int
+	sessionState *service.SessionState
+	remote       bool
+
+	agent string
+	model string
+	// models are the models of the agents of the team, by agent name
+	models map[string]string
+	state  State
+	tool   string
+	// streams is the number of streams started and not stopped yet, the
+	// streams of the sub-agents included
+	streams int
+	// disconnected is set when a remote backend couldn't be reached
+	disconnected bool
+}
+
+// NewStatus creates the status line. remote tells whether the runtime runs
+// on a remote backend.
+func NewStatus(sessionState *service.SessionState, remote bool) Status {
+	return Status{
+		sessionState: sessionState,
+		remote:       remote,
+		models:       map[string]string{},
+		state:        StateIdle,
+	}
+}
+
+// SetWidth sets the width of the status line
+func (s *Status) SetWidth(width int) {
+	s.width = width
+}
+
+// State returns what the agent is doing.
+func (s *Status) State() State {
+	return s.state
+}
+
+// Update updates the status line from a runtime event.
+func (s *Status) Update(msg tea.Msg) {
+	switch msg := msg.(type) {
+	case *runtime.TeamInfoEvent:
+		for _, agent := range msg.AvailableAgents {
+			if agent.Model != "" {
+				s.models[agent.Name] = agent.Provider + "/" + agent.Model
+			}
+		}
+		s.setAgent(msg.CurrentAgent, "")
+	case *runtime.AgentInfoEvent:
+		s.setAgent(msg.AgentName, msg.Model)
+	case *runtime.StreamStartedEvent:
+		s.streams++
+		s.disconnected = false
+		s.setState(StateStreaming, "")
+	case *runtime.StreamStoppedEvent:
+		s.streams = max(0, s.streams-1)
+		if s.streams == 0 && s.state != StateError {
+			s.setState(StateIdle, "")
+		}
+	case *runtime.AgentChoiceEvent, *runtime.AgentChoiceReasoningEvent, *runtime.ToolCallResponseEvent:
+		if s.streams > 0 {
+			s.setState(StateStreaming, "")
+		}
+	case *runtime.ToolCallEvent:
+		s.setState(StateRunningTool, msg.ToolCall.Function.Name)
+	case *runtime.ToolCallConfirmationEvent, *runtime.ElicitationRequestEvent, *runtime.MaxIterationsReachedEvent:
+		s.setState(StateAwaitingInput, "")
+	case *runtime.ErrorEvent:
+		// A remote runtime reports an error without starting a stream when
+		// the backend can't be reached
+		if s.remote && s.streams == 0 {
+			s.disconnected = true
+		}
+		s.setState(StateError, "")
+	}
+}
+
+func (s *Status) setAgent(name, model string) {
+	if name == "" {
+		return
+	}
+	s.agent = name
+	if model != "" {
+		s.models[name] = model
+	}
+	s.model = s.models[name]
+}
+
+func (s *Status) setState(state State, tool string) {
+	s.state = state
+	s.tool = tool
+}
+
+// View renders the status line
+func (s *Status) View() string {
+	const separator = " · "
+
+	var parts []string
+	if s.agent != "" {
+		parts = append(parts, styles.HighlightWhiteStyle.Render(s.agent))
+	}
+	if s.model != "" {
+		parts = append(parts, styles.SecondaryStyle.Render(s.model))
+	}
+	parts = append(parts, s.renderState())
+	left := strings.Join(parts, styles.MutedStyle.Render(separator))
+
+	right := strings.Join([]string{s.renderApproval(), s.renderBackend()}, styles.MutedStyle.Render(separator))
+
+	leftStyled := styles.BaseStyle.PaddingLeft(1).Render(left)
+	rightStyled := styles.BaseStyle.PaddingRight(1).Render(right)
+	leftStyled = ansi.Truncate(leftStyled, max(0, s.width-lipgloss.Width(rightStyled)-1), "…")
+
+	spacer := strings.Repeat(" ", max(1, s.width-lipgloss.Width(leftStyled)-lipgloss.Width(rightStyled)))
+
+	return leftStyled + spacer + rightStyled
+}
+
+func (s *Status) renderState() string {
+	switch s.state {
+	case StateStreaming:
+		return styles.InProgressStyle.Render("● " + string(s.state))
+	case StateRunningTool:
+		text := "● " + string(s.state)
+		if s.tool != "" {
+			text += " " + s.tool
+		}
+		return styles.InProgressStyle.Render(text)
+	case StateAwaitingInput:
+		return styles.WarningStyle.Render("● " + string(s.state))
+	case StateError:
+		return styles.ErrorStyle.Render("● " + string(s.state))
+	default:
+		return styles.MutedStyle.Render("○ " + string(s.state))
+	}
+}
+
+func (s *Status) renderApproval() string {
+	if s.sessionState != nil && s.sessionState.YoloMode {
+		return styles.WarningStyle.Render("approval: auto")
+	}
+	return styles.MutedStyle.Render("approval: ask")
+}
+
+func (s *Status) renderBackend() string {
+	switch {
+	case !s.remote:
+		return styles.MutedStyle.Render("local")
+	case s.disconnected:
+		return styles.ErrorStyle.Render("remote: disconnected")
+	default:
+		return styles.SuccessStyle.Render("remote: connected")
+	}
+}
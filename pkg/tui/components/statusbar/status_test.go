@@ -0,0 +1,65 @@
+package statusbar
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/docker/cagent/pkg/runtime"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/tools"
+	"github.com/docker/cagent/pkg/tui/service"
+)
+
+func TestStatus(t *testing.T) {
+	t.Parallel()
+
+	sessionState := service.NewSessionState(session.New())
+	s := NewStatus(sessionState, false)
+	s.SetWidth(120)
+
+	s.Update(runtime.TeamInfo([]runtime.AgentDetails{
+		{Name: "root", Provider: "openai", Model: "gpt-4o"},
+		{Name: "helper", Provider: "anthropic", Model: "claude-sonnet-4-0"},
+	}, "root"))
+	view := ansi.Strip(s.View())
+	assert.Contains(t, view, "root · openai/gpt-4o · ○ idle")
+	assert.Contains(t, view, "approval: ask · local")
+
+	toolCall := tools.ToolCall{Function: tools.FunctionCall{Name: "shell"}}
+	s.Update(runtime.StreamStarted("1", "root"))
+	assert.Equal(t, StateStreaming, s.State())
+	s.Update(runtime.ToolCallConfirmation(toolCall, tools.Tool{}, "root"))
+	assert.Equal(t, StateAwaitingInput, s.State())
+	s.Update(runtime.ToolCall(toolCall, tools.Tool{}, "root"))
+	assert.Contains(t, ansi.Strip(s.View()), "● running tool shell")
+
+	// A sub-agent runs in a nested stream
+	s.Update(runtime.AgentInfo("helper", "", "", ""))
+	s.Update(runtime.StreamStarted("2", "helper"))
+	s.Update(runtime.StreamStopped("2", "helper"))
+	assert.Equal(t, StateStreaming, s.State())
+	assert.Contains(t, ansi.Strip(s.View()), "helper · anthropic/claude-sonnet-4-0")
+
+	s.Update(runtime.StreamStopped("1", "root"))
+	assert.Equal(t, StateIdle, s.State())
+
+	sessionState.SetYoloMode(true)
+	assert.Contains(t, ansi.Strip(s.View()), "approval: auto")
+}
+
+func TestStatus_Remote(t *testing.T) {
+	t.Parallel()
+
+	s := NewStatus(nil, true)
+	s.SetWidth(120)
+	assert.Contains(t, ansi.Strip(s.View()), "remote: connected")
+
+	s.Update(runtime.Error("failed to start remote agent: connection refused"))
+	assert.Equal(t, StateError, s.State())
+	assert.Contains(t, ansi.Strip(s.View()), "remote: disconnected")
+
+	s.Update(runtime.StreamStarted("1", "root"))
+	assert.Contains(t, ansi.Strip(s.View()), "remote: connected")
+}
@@ -3,6 +3,7 @@ package editfile
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/docker/cagent/pkg/tools/builtin"
 	"github.com/docker/cagent/pkg/tui/components/spinner"
@@ -37,6 +38,10 @@ func render(msg *types.Message, s spinner.Spinner, sessionState *service.Session
 				renderEditFile(msg.ToolCall, contentWidth, sessionState.SplitDiffView, msg.ToolStatus))
 		}
 
+		if msg.ToolStatus == types.ToolStatusConfirmation {
+			content += "\n" + renderReviewHint(sessionState.SplitDiffView)
+		}
+
 		if (msg.ToolStatus == types.ToolStatusError) && msg.Content != "" {
 			content += toolcommon.FormatToolResult(msg.Content, width)
 		}
@@ -44,3 +49,24 @@ func render(msg *types.Message, s spinner.Spinner, sessionState *service.Session
 
 	return content
 }
+
+// renderReviewHint lists the keys used to answer an edit awaiting confirmation.
+func renderReviewHint(splitView bool) string {
+	other := "unified"
+	if !splitView {
+		other = "split"
+	}
+
+	hints := [][2]string{
+		{"y", "accept"},
+		{"n", "reject"},
+		{"a", "approve all"},
+		{"ctrl+t", other + " view"},
+	}
+
+	parts := make([]string, 0, len(hints))
+	for _, h := range hints {
+		parts = append(parts, styles.HighlightWhiteStyle.Render(h[0])+" "+styles.SecondaryStyle.Render(h[1]))
+	}
+	return strings.Join(parts, "  ")
+}
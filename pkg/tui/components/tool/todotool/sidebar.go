@@ -5,8 +5,8 @@ import (
 
 	"charm.land/lipgloss/v2"
 
+	"github.com/docker/cagent/pkg/todo"
 	"github.com/docker/cagent/pkg/tools"
-	"github.com/docker/cagent/pkg/tools/builtin"
 	"github.com/docker/cagent/pkg/tui/components/tab"
 	"github.com/docker/cagent/pkg/tui/components/toolcommon"
 	"github.com/docker/cagent/pkg/tui/styles"
@@ -14,7 +14,7 @@ import (
 
 // SidebarComponent represents the todo display component for the sidebar
 type SidebarComponent struct {
-	todos []builtin.Todo
+	todos []todo.Todo
 	width int
 }
 
@@ -33,7 +33,7 @@ func (c *SidebarComponent) SetTodos(result *tools.ToolCallResult) error {
 		return nil
 	}
 
-	todos, ok := result.Meta.([]builtin.Todo)
+	todos, ok := result.Meta.([]todo.Todo)
 	if !ok {
 		return nil
 	}
@@ -55,7 +55,7 @@ func (c *SidebarComponent) Render() string {
 	return c.renderTab("TO-DO", strings.Join(lines, "\n"))
 }
 
-func (c *SidebarComponent) renderTodoLine(todo builtin.Todo) string {
+func (c *SidebarComponent) renderTodoLine(todo todo.Todo) string {
 	icon, style := renderTodoIcon(todo.Status)
 
 	// Compute prefix width dynamically (icon + space separator)
@@ -63,7 +63,24 @@ func (c *SidebarComponent) renderTodoLine(todo builtin.Todo) string {
 	maxDescWidth := c.width - lipgloss.Width(prefix)
 	description := toolcommon.TruncateText(todo.Description, maxDescWidth)
 
-	return styles.TabPrimaryStyle.Render(style.Render(prefix + description))
+	line := styles.TabPrimaryStyle.Render(style.Render(prefix + description))
+	if todo.Verification != nil {
+		line += "\n" + c.renderVerification(todo.Verification, lipgloss.Width(prefix))
+	}
+	return line
+}
+
+// renderVerification shows how the checks of a todo went, under its description.
+func (c *SidebarComponent) renderVerification(v *todo.Verification, indent int) string {
+	icon, style := "?", styles.WarningStyle
+	switch {
+	case v.Failed():
+		icon, style = "✗", styles.ErrorStyle
+	case v.Passed():
+		icon, style = "✓", styles.SuccessStyle
+	}
+	summary := toolcommon.TruncateText(icon+" "+v.Summary(), c.width-indent)
+	return strings.Repeat(" ", indent) + style.Render(summary)
 }
 
 func (c *SidebarComponent) renderTab(title, content string) string {
@@ -2,10 +2,13 @@ package transfertask
 
 import (
 	"encoding/json"
+	"fmt"
 	"strings"
+	"time"
 
 	"charm.land/lipgloss/v2"
 
+	"github.com/docker/cagent/pkg/session"
 	"github.com/docker/cagent/pkg/tools/builtin"
 	"github.com/docker/cagent/pkg/tui/components/spinner"
 	"github.com/docker/cagent/pkg/tui/components/toolcommon"
@@ -28,6 +31,9 @@ func render(msg *types.Message, _ spinner.Spinner, _ *service.SessionState, widt
 	header := styles.AgentBadgeStyle.MarginLeft(2).Render(msg.Sender) +
 		" calls " +
 		styles.AgentBadgeStyle.Render(params.Agent)
+	if params.Priority != "" && params.Priority != builtin.TaskPriorityNormal {
+		header += " " + toolcommon.TaskPriorityStyle(params.Priority).Render(params.Priority+" priority")
+	}
 
 	// Calculate the icon with its margin
 	icon := styles.ToolCompletedIcon.Render("✓")
@@ -55,5 +61,49 @@ func render(msg *types.Message, _ spinner.Spinner, _ *service.SessionState, widt
 		}
 	}
 
-	return header + "\n\n" + taskContent.String()
+	content := header + "\n\n" + taskContent.String()
+	if msg.ToolResult != nil {
+		if handoff, ok := msg.ToolResult.Meta.(*session.Handoff); ok {
+			content += "\n\n" + renderHandoff(handoff, width)
+		}
+	}
+	return content
+}
+
+// renderHandoff renders what the sub-agent handed back as a card below the
+// task.
+func renderHandoff(h *session.Handoff, width int) string {
+	// Leave room for the border, the padding and the margin of the card
+	innerWidth := max(width-6, 10)
+
+	outcomeStyle := styles.SuccessStyle
+	if h.Outcome != session.HandoffCompleted {
+		outcomeStyle = styles.WarningStyle
+	}
+
+	lines := []string{
+		styles.BoldStyle.Render("Handoff from "+h.Agent) + " " + outcomeStyle.Render(h.Outcome),
+	}
+	if len(h.Artifacts) > 0 {
+		lines = append(lines, "", "Artifacts:")
+		for _, path := range h.Artifacts {
+			lines = append(lines, toolcommon.TruncateText("  "+path, innerWidth))
+		}
+	}
+	if len(h.OpenIssues) > 0 {
+		lines = append(lines, "", "Open issues:")
+		for _, issue := range h.OpenIssues {
+			lines = append(lines, toolcommon.WrapLines("  • "+issue, innerWidth)...)
+		}
+	}
+	if h.Confidence != nil {
+		lines = append(lines, "", toolcommon.ConfidenceBadge(h.Confidence.Level))
+		if h.Confidence.Rationale != "" {
+			lines = append(lines, toolcommon.WrapLines(h.Confidence.Rationale, innerWidth)...)
+		}
+	}
+	lines = append(lines, "", styles.MutedStyle.Render(fmt.Sprintf("$%.4f · %d in / %d out tokens · %s",
+		h.Cost, h.InputTokens, h.OutputTokens, h.Duration.Round(time.Second))))
+
+	return styles.ToolHandoffCard.Render(strings.Join(lines, "\n"))
 }
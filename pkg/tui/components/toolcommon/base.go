@@ -29,7 +29,7 @@ type Base struct {
 func NewBase(msg *types.Message, sessionState *service.SessionState, render Renderer) *Base {
 	return &Base{
 		message:      msg,
-		spinner:      spinner.New(spinner.ModeSpinnerOnly, styles.SpinnerDotsAccentStyle),
+		spinner:      spinner.New(spinner.ModeSpinnerOnly, &styles.SpinnerDotsAccentStyle),
 		width:        80,
 		height:       1,
 		sessionState: sessionState,
@@ -4,10 +4,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"charm.land/lipgloss/v2"
 
+	"github.com/docker/cagent/pkg/confidence"
 	"github.com/docker/cagent/pkg/paths"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/tools/builtin"
 	"github.com/docker/cagent/pkg/tui/components/spinner"
 	"github.com/docker/cagent/pkg/tui/styles"
 	"github.com/docker/cagent/pkg/tui/types"
@@ -177,6 +181,48 @@ func RenderTool(msg *types.Message, inProgress spinner.Spinner, args, result str
 	return styles.RenderComposite(styles.ToolMessageStyle.Width(width), content)
 }
 
+// RenderSummary renders a finished tool call as a one-line summary with the
+// tool name, how long it ran and its status. The summary is highlighted when
+// selected and its marker tells whether the tool call is expanded.
+func RenderSummary(msg *types.Message, width int, selected, expanded bool) string {
+	nameStyle := styles.ToolName
+	status := "done"
+	if msg.ToolStatus == types.ToolStatusError {
+		nameStyle = styles.ToolNameError
+		status = "failed"
+	}
+
+	details := status
+	if msg.ToolDuration > 0 {
+		details = formatDuration(msg.ToolDuration) + " · " + status
+	}
+	if msg.ContextSummary != "" {
+		details += " · summarized"
+	}
+	marker := "▸"
+	if expanded {
+		marker = "▾"
+	}
+
+	detailsStyle := styles.MutedStyle
+	if selected {
+		detailsStyle = styles.ActiveStyle
+	}
+
+	content := Icon(msg, spinner.Spinner{}) + nameStyle.Render(msg.ToolDefinition.DisplayName())
+	remainingWidth := max(width-lipgloss.Width(content)-1, 1)
+	content += " " + detailsStyle.Render(TruncateText(details+" "+marker, remainingWidth))
+
+	return styles.RenderComposite(styles.ToolMessageStyle.Width(width), content)
+}
+
+func formatDuration(d time.Duration) string {
+	if d < time.Second {
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
+
 // ShortenPath replaces home directory with ~ for cleaner display.
 func ShortenPath(path string) string {
 	if path == "" {
@@ -188,3 +234,53 @@ func ShortenPath(path string) string {
 	}
 	return path
 }
+
+// TaskPriorityStyle returns the style of the priority of a transferred task:
+// critical tasks stand out and background ones fade.
+func TaskPriorityStyle(priority string) lipgloss.Style {
+	switch priority {
+	case builtin.TaskPriorityCritical:
+		return styles.WarningStyle
+	case builtin.TaskPriorityBackground:
+		return styles.MutedStyle
+	default:
+		return styles.SecondaryStyle
+	}
+}
+
+// ConfidenceBadge renders the confidence an agent reported in an answer: low
+// confidence stands out.
+func ConfidenceBadge(level string) string {
+	style := styles.WarningStyle
+	switch level {
+	case confidence.High:
+		style = styles.SuccessStyle
+	case confidence.Medium:
+		style = styles.SecondaryStyle
+	}
+	return style.Bold(true).Render("◆ " + level + " confidence")
+}
+
+// HandoffSummary sums up in one line what a sub-agent handed back once done
+// with a task.
+func HandoffSummary(h *session.Handoff) string {
+	parts := []string{h.Outcome}
+	if n := len(h.Artifacts); n > 0 {
+		parts = append(parts, pluralize(n, "file", "files"))
+	}
+	if n := len(h.OpenIssues); n > 0 {
+		parts = append(parts, pluralize(n, "open issue", "open issues"))
+	}
+	if h.Confidence != nil {
+		parts = append(parts, h.Confidence.Level+" confidence")
+	}
+	parts = append(parts, fmt.Sprintf("$%.2f", h.Cost))
+	return strings.Join(parts, " · ")
+}
+
+func pluralize(n int, singular, plural string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, singular)
+	}
+	return fmt.Sprintf("%d %s", n, plural)
+}
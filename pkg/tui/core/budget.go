@@ -0,0 +1,68 @@
+package core
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// FrameBudget is the time a component can spend rendering one frame before
+// the TUI stops feeling smooth (60 frames per second).
+const FrameBudget = 16 * time.Millisecond
+
+// budgetWarnInterval limits how often a slow component is reported.
+const budgetWarnInterval = 5 * time.Second
+
+type renderStats struct {
+	slowFrames int
+	slowest    time.Duration
+	lastWarn   time.Time
+}
+
+var (
+	renderStatsMu sync.Mutex
+	renderStatsBy = map[string]*renderStats{}
+)
+
+// TrackRender measures how long a component takes to render a frame. Call
+// the returned function once the frame is rendered:
+//
+//	defer core.TrackRender("sidebar")()
+//
+// Frames over FrameBudget are counted and logged as a warning, at most once
+// every few seconds per component.
+func TrackRender(component string) func() {
+	start := time.Now()
+	return func() {
+		recordRender(component, time.Since(start), time.Now())
+	}
+}
+
+func recordRender(component string, elapsed time.Duration, now time.Time) bool {
+	if elapsed <= FrameBudget {
+		return false
+	}
+
+	renderStatsMu.Lock()
+	defer renderStatsMu.Unlock()
+
+	stats, ok := renderStatsBy[component]
+	if !ok {
+		stats = &renderStats{}
+		renderStatsBy[component] = stats
+	}
+	stats.slowFrames++
+	stats.slowest = max(stats.slowest, elapsed)
+
+	if now.Sub(stats.lastWarn) < budgetWarnInterval {
+		return false
+	}
+
+	slog.Warn("Component exceeded its frame budget",
+		"component", component,
+		"budget", FrameBudget,
+		"slow_frames", stats.slowFrames,
+		"slowest", stats.slowest)
+	*stats = renderStats{lastWarn: now}
+	return true
+}
@@ -0,0 +1,20 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordRender(t *testing.T) {
+	t.Parallel()
+
+	const component = "test-component"
+	now := time.Now()
+
+	assert.False(t, recordRender(component, FrameBudget, now), "frames within budget are not reported")
+	assert.True(t, recordRender(component, 2*FrameBudget, now))
+	assert.False(t, recordRender(component, 3*FrameBudget, now.Add(time.Second)), "warnings are rate limited")
+	assert.True(t, recordRender(component, 2*FrameBudget, now.Add(budgetWarnInterval+time.Second)))
+}
@@ -0,0 +1,235 @@
+package layout
+
+import (
+	"slices"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+)
+
+// Pane is a model laid out by Panes, under its title.
+type Pane struct {
+	ID    string
+	Title string
+	Model Model
+}
+
+// Panes lays out models side by side, in columns of equal width separated by
+// a vertical line, each one under its title. One of the panes has the focus.
+//
+// When the columns would be narrower than MinWidth, only the focused pane is
+// shown, under a line of tabs with the titles of all the panes.
+type Panes struct {
+	// MinWidth is the narrowest a column can be before switching to tabs
+	MinWidth int
+	// TitleStyle and FocusedTitleStyle render the titles and the tabs
+	TitleStyle        lipgloss.Style
+	FocusedTitleStyle lipgloss.Style
+	// SeparatorStyle renders the line between two columns
+	SeparatorStyle lipgloss.Style
+
+	panes   []Pane
+	focused int
+	width   int
+	height  int
+}
+
+// NewPanes creates an empty pane layout.
+func NewPanes() *Panes {
+	return &Panes{MinWidth: 40}
+}
+
+// Add adds a pane after the existing ones, unless there is already a pane
+// with the same ID, and resizes the panes once the layout has a size.
+func (p *Panes) Add(pane Pane) tea.Cmd {
+	if p.index(pane.ID) >= 0 {
+		return nil
+	}
+	p.panes = append(p.panes, pane)
+	if p.width == 0 && p.height == 0 {
+		return nil
+	}
+	return p.SetSize(p.width, p.height)
+}
+
+// Remove removes a pane and resizes the remaining ones. The focus moves to
+// the previous pane when the focused pane is removed.
+func (p *Panes) Remove(id string) tea.Cmd {
+	i := p.index(id)
+	if i < 0 {
+		return nil
+	}
+	p.panes = slices.Delete(p.panes, i, i+1)
+	if p.focused >= i && p.focused > 0 {
+		p.focused--
+	}
+	return p.SetSize(p.width, p.height)
+}
+
+// Get returns the model of a pane.
+func (p *Panes) Get(id string) (Model, bool) {
+	i := p.index(id)
+	if i < 0 {
+		return nil, false
+	}
+	return p.panes[i].Model, true
+}
+
+// All returns the panes, in order.
+func (p *Panes) All() []Pane {
+	return slices.Clone(p.panes)
+}
+
+// Len returns the number of panes.
+func (p *Panes) Len() int {
+	return len(p.panes)
+}
+
+// Focused returns the focused pane. It returns false when there are no panes.
+func (p *Panes) Focused() (Pane, bool) {
+	if len(p.panes) == 0 {
+		return Pane{}, false
+	}
+	return p.panes[p.focused], true
+}
+
+// Focus moves the focus to a pane. It returns false for an unknown pane.
+func (p *Panes) Focus(id string) bool {
+	i := p.index(id)
+	if i < 0 {
+		return false
+	}
+	p.focused = i
+	return true
+}
+
+// FocusNext moves the focus to the next pane, wrapping around.
+func (p *Panes) FocusNext() {
+	if len(p.panes) > 0 {
+		p.focused = (p.focused + 1) % len(p.panes)
+	}
+}
+
+// FocusPrevious moves the focus to the previous pane, wrapping around.
+func (p *Panes) FocusPrevious() {
+	if len(p.panes) > 0 {
+		p.focused = (p.focused + len(p.panes) - 1) % len(p.panes)
+	}
+}
+
+// Tabbed reports whether the panes are shown as tabs, one at a time.
+func (p *Panes) Tabbed() bool {
+	return len(p.panes) > 1 && p.columnWidth() < p.MinWidth
+}
+
+// SetSize sets the size of the whole layout and sizes the panes to fit.
+func (p *Panes) SetSize(width, height int) tea.Cmd {
+	p.width = width
+	p.height = height
+
+	modelHeight := max(1, height-1) // -1 for the title
+	var cmds []tea.Cmd
+	for i, pane := range p.panes {
+		_, w := p.bounds(i)
+		cmds = append(cmds, pane.Model.SetSize(w, modelHeight))
+	}
+	return tea.Batch(cmds...)
+}
+
+// Bounds returns the column of a pane, relative to the left of the layout.
+// In tabs, every pane spans the whole width.
+func (p *Panes) Bounds(id string) (x, width int, ok bool) {
+	i := p.index(id)
+	if i < 0 {
+		return 0, 0, false
+	}
+	x, width = p.bounds(i)
+	return x, width, true
+}
+
+// PaneAt returns the pane shown at x, relative to the left of the layout.
+func (p *Panes) PaneAt(x int) (Pane, bool) {
+	if p.Tabbed() {
+		return p.Focused()
+	}
+	for i, pane := range p.panes {
+		if left, w := p.bounds(i); x >= left && x < left+w {
+			return pane, true
+		}
+	}
+	return Pane{}, false
+}
+
+// View renders the panes side by side, or the focused one under the tabs.
+func (p *Panes) View() string {
+	if len(p.panes) == 0 {
+		return ""
+	}
+
+	modelHeight := max(1, p.height-1)
+	if p.Tabbed() {
+		return lipgloss.JoinVertical(lipgloss.Left,
+			p.renderTabs(),
+			p.renderModel(p.panes[p.focused].Model, p.width, modelHeight),
+		)
+	}
+
+	separator := p.SeparatorStyle.Render(strings.TrimSuffix(strings.Repeat("│\n", p.height), "\n"))
+	columns := make([]string, 0, 2*len(p.panes)-1)
+	for i, pane := range p.panes {
+		if i > 0 {
+			columns = append(columns, separator)
+		}
+		_, w := p.bounds(i)
+		columns = append(columns, lipgloss.JoinVertical(lipgloss.Left,
+			p.titleStyle(i).Width(w).MaxWidth(w).Render(pane.Title),
+			p.renderModel(pane.Model, w, modelHeight),
+		))
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, columns...)
+}
+
+func (p *Panes) renderTabs() string {
+	tabs := make([]string, len(p.panes))
+	for i, pane := range p.panes {
+		tabs[i] = p.titleStyle(i).Render(" " + pane.Title + " ")
+	}
+	return lipgloss.NewStyle().Width(p.width).MaxWidth(p.width).Render(strings.Join(tabs, " "))
+}
+
+func (p *Panes) renderModel(m Model, width, height int) string {
+	return lipgloss.NewStyle().Width(width).Height(height).MaxWidth(width).MaxHeight(height).Render(m.View())
+}
+
+func (p *Panes) titleStyle(i int) lipgloss.Style {
+	if i == p.focused {
+		return p.FocusedTitleStyle
+	}
+	return p.TitleStyle
+}
+
+// columnWidth is the width of every column but the last one, which also gets
+// what's left of the division.
+func (p *Panes) columnWidth() int {
+	if len(p.panes) == 0 {
+		return p.width
+	}
+	return (p.width - (len(p.panes) - 1)) / len(p.panes)
+}
+
+func (p *Panes) bounds(i int) (x, width int) {
+	if len(p.panes) == 1 || p.Tabbed() {
+		return 0, max(1, p.width)
+	}
+	w := p.columnWidth()
+	x = i * (w + 1)
+	if i == len(p.panes)-1 {
+		return x, max(1, p.width-x)
+	}
+	return x, max(1, w)
+}
+
+func (p *Panes) index(id string) int {
+	return slices.IndexFunc(p.panes, func(pane Pane) bool { return pane.ID == id })
+}
@@ -0,0 +1,102 @@
+package layout
+
+import (
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeModel struct {
+	text          string
+	width, height int
+}
+
+func (m *fakeModel) Init() tea.Cmd                     { return nil }
+func (m *fakeModel) Update(tea.Msg) (Model, tea.Cmd)   { return m, nil }
+func (m *fakeModel) View() string                      { return m.text }
+func (m *fakeModel) SetSize(width, height int) tea.Cmd { m.width, m.height = width, height; return nil }
+
+func TestPanesSideBySide(t *testing.T) {
+	t.Parallel()
+
+	main, sub := &fakeModel{text: "main"}, &fakeModel{text: "sub"}
+	panes := NewPanes()
+	panes.Add(Pane{ID: "main", Title: "Main", Model: main})
+	panes.Add(Pane{ID: "sub", Title: "Sub", Model: sub})
+	panes.SetSize(101, 10)
+
+	assert.False(t, panes.Tabbed())
+	assert.Equal(t, 50, main.width)
+	assert.Equal(t, 50, sub.width)
+	assert.Equal(t, 9, main.height, "the title takes a line")
+
+	x, width, ok := panes.Bounds("sub")
+	require.True(t, ok)
+	assert.Equal(t, 51, x)
+	assert.Equal(t, 50, width)
+
+	pane, ok := panes.PaneAt(60)
+	require.True(t, ok)
+	assert.Equal(t, "sub", pane.ID)
+
+	view := panes.View()
+	assert.Equal(t, 10, lipgloss.Height(view))
+	assert.Equal(t, 101, lipgloss.Width(view))
+	assert.Contains(t, view, "Main")
+	assert.Contains(t, view, "sub")
+}
+
+func TestPanesTabs(t *testing.T) {
+	t.Parallel()
+
+	main, sub := &fakeModel{text: "main"}, &fakeModel{text: "sub"}
+	panes := NewPanes()
+	panes.SetSize(60, 10)
+	panes.Add(Pane{ID: "main", Title: "Main", Model: main})
+	panes.Add(Pane{ID: "sub", Title: "Sub", Model: sub})
+
+	assert.True(t, panes.Tabbed())
+	assert.Equal(t, 60, sub.width, "tabs span the whole width")
+
+	require.True(t, panes.Focus("sub"))
+	view := panes.View()
+	firstLine, _, _ := strings.Cut(view, "\n")
+	assert.Contains(t, firstLine, "Main")
+	assert.Contains(t, firstLine, "Sub")
+	assert.Contains(t, view, "sub")
+	assert.NotContains(t, view, "main", "only the focused pane is shown")
+}
+
+func TestPanesFocus(t *testing.T) {
+	t.Parallel()
+
+	panes := NewPanes()
+	_, ok := panes.Focused()
+	assert.False(t, ok)
+
+	for _, id := range []string{"a", "b", "c"} {
+		panes.Add(Pane{ID: id, Title: id, Model: &fakeModel{}})
+	}
+	panes.Add(Pane{ID: "b", Title: "duplicate", Model: &fakeModel{}})
+	assert.Equal(t, 3, panes.Len())
+
+	focused := func() string {
+		pane, _ := panes.Focused()
+		return pane.ID
+	}
+	assert.Equal(t, "a", focused())
+	panes.FocusPrevious()
+	assert.Equal(t, "c", focused())
+	panes.FocusNext()
+	assert.Equal(t, "a", focused())
+	assert.False(t, panes.Focus("unknown"))
+
+	require.True(t, panes.Focus("c"))
+	panes.Remove("c")
+	assert.Equal(t, "b", focused())
+	assert.Equal(t, 2, panes.Len())
+}
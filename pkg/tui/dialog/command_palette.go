@@ -7,6 +7,8 @@ import (
 	"charm.land/bubbles/v2/textinput"
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
+	"github.com/junegunn/fzf/src/algo"
+	"github.com/junegunn/fzf/src/util"
 
 	"github.com/docker/cagent/pkg/tui/commands"
 	"github.com/docker/cagent/pkg/tui/components/toolcommon"
@@ -174,7 +176,8 @@ func (d *commandPaletteDialog) filterCommands() {
 		for _, cmd := range cat.Commands {
 			if strings.Contains(strings.ToLower(cmd.Label), query) ||
 				strings.Contains(strings.ToLower(cmd.Description), query) ||
-				strings.Contains(strings.ToLower(cmd.Category), query) {
+				strings.Contains(strings.ToLower(cmd.Category), query) ||
+				fuzzyMatch(cmd.Label, query) {
 				d.filtered = append(d.filtered, cmd)
 			}
 		}
@@ -186,6 +189,13 @@ func (d *commandPaletteDialog) filterCommands() {
 	d.offset = 0
 }
 
+// fuzzyMatch reports whether the characters of query appear in label, in order.
+func fuzzyMatch(label, query string) bool {
+	chars := util.ToChars([]byte(label))
+	result, _ := algo.FuzzyMatchV1(false, false, true, &chars, []rune(query), false, nil)
+	return result.Start >= 0
+}
+
 // maxVisibleLines returns the maximum number of lines available for the command list
 func (d *commandPaletteDialog) maxVisibleLines() int {
 	maxHeight := min(d.Height()*70/100, 30)
@@ -79,3 +79,14 @@ func TestCommandPaletteFiltering(t *testing.T) {
 		})
 	}
 }
+
+func TestCommandPaletteFuzzyFiltering(t *testing.T) {
+	t.Parallel()
+
+	d := NewCommandPaletteDialog(categories).(*commandPaletteDialog)
+	d.textInput.SetValue("cmpct")
+	d.filterCommands()
+
+	require.Len(t, d.filtered, 1)
+	require.Equal(t, "session.compact", d.filtered[0].ID)
+}
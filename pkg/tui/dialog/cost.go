@@ -8,11 +8,10 @@ import (
 	"charm.land/bubbles/v2/key"
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
-	"github.com/atotto/clipboard"
 
 	"github.com/docker/cagent/pkg/chat"
 	"github.com/docker/cagent/pkg/session"
-	"github.com/docker/cagent/pkg/tui/components/notification"
+	"github.com/docker/cagent/pkg/tui/clipboard"
 	"github.com/docker/cagent/pkg/tui/core"
 	"github.com/docker/cagent/pkg/tui/core/layout"
 	"github.com/docker/cagent/pkg/tui/styles"
@@ -57,8 +56,7 @@ func (d *costDialog) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
 		case key.Matches(msg, d.keyMap.Close):
 			return d, core.CmdHandler(CloseDialogMsg{})
 		case key.Matches(msg, d.keyMap.Copy):
-			_ = clipboard.WriteAll(d.renderPlainText())
-			return d, notification.SuccessCmd("Cost details copied to clipboard.")
+			return d, clipboard.Copy(d.renderPlainText(), "Cost details copied to clipboard.")
 		case key.Matches(msg, d.keyMap.Up):
 			d.offset = max(0, d.offset-1)
 		case key.Matches(msg, d.keyMap.Down):
@@ -202,17 +200,17 @@ func (d *costDialog) renderContent(contentWidth, maxHeight int) string {
 		RenderTitle("Session Cost Details", contentWidth, styles.DialogTitleStyle),
 		RenderSeparator(contentWidth),
 		"",
-		sectionStyle.Render("Total"),
+		sectionStyle().Render("Total"),
 		"",
-		accentStyle.Render(formatCost(data.total.cost)),
+		accentStyle().Render(formatCost(data.total.cost)),
 		d.renderInputLine(data.total, true),
-		fmt.Sprintf("%s %s", labelStyle.Render("output:"), valueStyle.Render(formatTokenCount(data.total.outputTokens))),
+		fmt.Sprintf("%s %s", labelStyle().Render("output:"), valueStyle().Render(formatTokenCount(data.total.outputTokens))),
 		"",
 	}
 
 	// By Model Section
 	if len(data.models) > 0 {
-		lines = append(lines, sectionStyle.Render("By Model"), "")
+		lines = append(lines, sectionStyle().Render("By Model"), "")
 		for _, m := range data.models {
 			lines = append(lines, d.renderUsageLine(m))
 		}
@@ -221,7 +219,7 @@ func (d *costDialog) renderContent(contentWidth, maxHeight int) string {
 
 	// By Message Section
 	if len(data.messages) > 0 {
-		lines = append(lines, sectionStyle.Render("By Message"), "")
+		lines = append(lines, sectionStyle().Render("By Message"), "")
 		for _, m := range data.messages {
 			lines = append(lines, d.renderUsageLine(m))
 		}
@@ -235,9 +233,9 @@ func (d *costDialog) renderContent(contentWidth, maxHeight int) string {
 }
 
 func (d *costDialog) renderInputLine(u usageInfo, showBreakdown bool) string {
-	line := fmt.Sprintf("%s %s", labelStyle.Render("input:"), valueStyle.Render(formatTokenCount(u.totalInput())))
+	line := fmt.Sprintf("%s %s", labelStyle().Render("input:"), valueStyle().Render(formatTokenCount(u.totalInput())))
 	if showBreakdown && (u.cachedTokens > 0 || u.cacheWriteTokens > 0) {
-		line += valueStyle.Render(fmt.Sprintf(" (%s new + %s cached + %s cache write)",
+		line += valueStyle().Render(fmt.Sprintf(" (%s new + %s cached + %s cache write)",
 			formatTokenCount(u.inputTokens),
 			formatTokenCount(u.cachedTokens),
 			formatTokenCount(u.cacheWriteTokens)))
@@ -247,12 +245,12 @@ func (d *costDialog) renderInputLine(u usageInfo, showBreakdown bool) string {
 
 func (d *costDialog) renderUsageLine(u usageInfo) string {
 	return fmt.Sprintf("%s  %s %s  %s %s  %s",
-		accentStyle.Render(padRight(formatCostPadded(u.cost))),
-		labelStyle.Render("input:"),
-		valueStyle.Render(padRight(formatTokenCount(u.totalInput()))),
-		labelStyle.Render("output:"),
-		valueStyle.Render(padRight(formatTokenCount(u.outputTokens))),
-		accentStyle.Render(u.label))
+		accentStyle().Render(padRight(formatCostPadded(u.cost))),
+		labelStyle().Render("input:"),
+		valueStyle().Render(padRight(formatTokenCount(u.totalInput()))),
+		labelStyle().Render("output:"),
+		valueStyle().Render(padRight(formatTokenCount(u.outputTokens))),
+		accentStyle().Render(u.label))
 }
 
 func (d *costDialog) applyScrolling(allLines []string, contentWidth, maxHeight int) string {
@@ -323,13 +321,22 @@ func (d *costDialog) renderPlainText() string {
 	return strings.Join(lines, "\n")
 }
 
-// Styles
-var (
-	sectionStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(styles.ColorTextSecondary))
-	labelStyle   = lipgloss.NewStyle().Bold(true)
-	valueStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color(styles.ColorTextSecondary))
-	accentStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color(styles.ColorHighlight))
-)
+// Styles, built at render time so they follow the current theme
+func sectionStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Bold(true).Foreground(styles.TextSecondary)
+}
+
+func labelStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Bold(true)
+}
+
+func valueStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(styles.TextSecondary)
+}
+
+func accentStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(styles.Highlight)
+}
 
 func formatCost(cost float64) string {
 	if cost < 0.0001 {
@@ -0,0 +1,220 @@
+package dialog
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+
+	"github.com/docker/cagent/pkg/app"
+	"github.com/docker/cagent/pkg/tools"
+	"github.com/docker/cagent/pkg/tui/core"
+	"github.com/docker/cagent/pkg/tui/core/layout"
+	"github.com/docker/cagent/pkg/tui/styles"
+)
+
+// elicitationDialog is the form an MCP server asks the user to fill during a
+// tool call
+type elicitationDialog struct {
+	BaseDialog
+	server       string
+	message      string
+	url          string
+	fields       []tools.ElicitationField
+	inputs       []textinput.Model
+	currentInput int
+	err          string
+	app          *app.App
+	keyMap       mcpPromptInputKeyMap
+}
+
+// NewElicitationDialog creates the form of an elicitation request of an MCP server
+func NewElicitationDialog(server, message, url string, fields []tools.ElicitationField, appInstance *app.App) Dialog {
+	var inputs []textinput.Model
+	for _, field := range fields {
+		ti := textinput.New()
+		ti.SetStyles(styles.DialogInputStyle)
+		ti.Placeholder = fieldPlaceholder(field)
+		ti.CharLimit = 1000
+		ti.SetWidth(50)
+		if field.Default != nil {
+			ti.SetValue(fmt.Sprint(field.Default))
+		}
+		inputs = append(inputs, ti)
+	}
+	if len(inputs) > 0 {
+		inputs[0].Focus()
+	}
+
+	keyMap := defaultMCPPromptInputKeyMap()
+	keyMap.Enter.SetHelp("enter", "submit")
+	keyMap.Escape.SetHelp("esc", "decline")
+
+	return &elicitationDialog{
+		server:  server,
+		message: message,
+		url:     url,
+		fields:  fields,
+		inputs:  inputs,
+		app:     appInstance,
+		keyMap:  keyMap,
+	}
+}
+
+func fieldPlaceholder(field tools.ElicitationField) string {
+	switch {
+	case len(field.Enum) > 0:
+		return "one of " + strings.Join(field.Enum, ", ")
+	case field.Type == "boolean":
+		return "yes or no"
+	case field.Description != "":
+		return field.Description
+	default:
+		return field.Type
+	}
+}
+
+// Init initializes the elicitation dialog
+func (d *elicitationDialog) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update handles messages for the elicitation dialog
+func (d *elicitationDialog) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		cmd := d.SetSize(msg.Width, msg.Height)
+		return d, cmd
+
+	case tea.KeyPressMsg:
+		if cmd := HandleQuit(msg); cmd != nil {
+			return d, cmd
+		}
+
+		switch {
+		case key.Matches(msg, d.keyMap.Escape):
+			_ = d.app.ResumeElicitation(context.Background(), tools.ElicitationActionDecline, nil)
+			return d, core.CmdHandler(CloseDialogMsg{})
+
+		case key.Matches(msg, d.keyMap.Up):
+			d.focus(d.currentInput - 1)
+			return d, nil
+
+		case key.Matches(msg, d.keyMap.Down), key.Matches(msg, d.keyMap.Tab):
+			d.focus(d.currentInput + 1)
+			return d, nil
+
+		case key.Matches(msg, d.keyMap.Enter):
+			content, err := d.content()
+			if err != nil {
+				d.err = err.Error()
+				return d, nil
+			}
+			_ = d.app.ResumeElicitation(context.Background(), tools.ElicitationActionAccept, content)
+			return d, core.CmdHandler(CloseDialogMsg{})
+
+		default:
+			if d.currentInput < len(d.inputs) {
+				var cmd tea.Cmd
+				d.inputs[d.currentInput], cmd = d.inputs[d.currentInput].Update(msg)
+				return d, cmd
+			}
+		}
+	}
+
+	return d, nil
+}
+
+func (d *elicitationDialog) focus(i int) {
+	if i < 0 || i >= len(d.inputs) {
+		return
+	}
+	d.inputs[d.currentInput].Blur()
+	d.currentInput = i
+	d.inputs[d.currentInput].Focus()
+}
+
+// content converts the values of the form to the types of the fields. The
+// optional fields left empty are left out.
+func (d *elicitationDialog) content() (map[string]any, error) {
+	if len(d.fields) == 0 {
+		return nil, nil
+	}
+
+	content := make(map[string]any, len(d.fields))
+	for i, field := range d.fields {
+		text := strings.TrimSpace(d.inputs[i].Value())
+		if text == "" {
+			if field.Required {
+				return nil, fmt.Errorf("%s is required", field.Label())
+			}
+			continue
+		}
+
+		value, err := field.Parse(text)
+		if err != nil {
+			return nil, err
+		}
+		content[field.Name] = value
+	}
+	return content, nil
+}
+
+// View renders the elicitation dialog
+func (d *elicitationDialog) View() string {
+	dialogWidth := d.ComputeDialogWidth(70, 60, 90)
+	contentWidth := d.ContentWidth(dialogWidth, 2)
+
+	parts := []string{
+		RenderTitle("Input requested by "+d.server, contentWidth, styles.DialogTitleStyle),
+		"",
+		styles.DialogContentStyle.Width(contentWidth).Render(d.message),
+	}
+	if d.url != "" {
+		parts = append(parts, "", styles.InfoStyle.Width(contentWidth).Render(d.url))
+	}
+
+	if len(d.inputs) > 0 {
+		parts = append(parts, RenderSeparator(contentWidth))
+	}
+	for i, input := range d.inputs {
+		field := d.fields[i]
+
+		label := field.Label()
+		if field.Required {
+			label += " *"
+		}
+		labelStyle := styles.DialogContentStyle
+		if i == d.currentInput {
+			labelStyle = labelStyle.Bold(true)
+		}
+		parts = append(parts, labelStyle.Render(label))
+		if field.Description != "" && field.Description != input.Placeholder {
+			parts = append(parts, styles.MutedStyle.Width(contentWidth).Render(field.Description))
+		}
+		input.SetWidth(contentWidth)
+		parts = append(parts, input.View())
+		if i < len(d.inputs)-1 {
+			parts = append(parts, "")
+		}
+	}
+
+	if d.err != "" {
+		parts = append(parts, "", styles.ErrorStyle.Width(contentWidth).Render(d.err))
+	}
+	parts = append(parts, "", RenderHelpKeys(contentWidth, "↑/↓", "navigate", "enter", "submit", "esc", "decline"))
+
+	return styles.DialogStyle.
+		Padding(1, 2).
+		Width(dialogWidth).
+		Render(lipgloss.JoinVertical(lipgloss.Left, parts...))
+}
+
+// Position returns the dialog position (centered)
+func (d *elicitationDialog) Position() (row, col int) {
+	return d.CenterDialog(d.View())
+}
@@ -0,0 +1,123 @@
+package dialog
+
+import (
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/tui/core"
+	"github.com/docker/cagent/pkg/tui/core/layout"
+	"github.com/docker/cagent/pkg/tui/messages"
+	"github.com/docker/cagent/pkg/tui/styles"
+)
+
+// feedbackDialog collects an optional comment on an answer the user rated
+type feedbackDialog struct {
+	BaseDialog
+	agentName string
+	answer    string
+	rating    string
+	input     textinput.Model
+	keyMap    feedbackKeyMap
+}
+
+type feedbackKeyMap struct {
+	Enter  key.Binding
+	Escape key.Binding
+}
+
+// NewFeedbackDialog creates a new dialog for the feedback of the user on an
+// answer of an agent
+func NewFeedbackDialog(agentName, answer, rating string) Dialog {
+	ti := textinput.New()
+	ti.SetStyles(styles.DialogInputStyle)
+	ti.Placeholder = "What was good or wrong? (optional)"
+	ti.CharLimit = 1000
+	ti.SetWidth(50)
+	ti.Focus()
+
+	return &feedbackDialog{
+		agentName: agentName,
+		answer:    answer,
+		rating:    rating,
+		input:     ti,
+		keyMap: feedbackKeyMap{
+			Enter:  key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "save")),
+			Escape: key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "cancel")),
+		},
+	}
+}
+
+// Init initializes the feedback dialog
+func (d *feedbackDialog) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update handles messages for the feedback dialog
+func (d *feedbackDialog) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		cmd := d.SetSize(msg.Width, msg.Height)
+		return d, cmd
+
+	case tea.KeyPressMsg:
+		if cmd := HandleQuit(msg); cmd != nil {
+			return d, cmd
+		}
+
+		switch {
+		case key.Matches(msg, d.keyMap.Escape):
+			return d, core.CmdHandler(CloseDialogMsg{})
+
+		case key.Matches(msg, d.keyMap.Enter):
+			return d, tea.Sequence(
+				core.CmdHandler(CloseDialogMsg{}),
+				core.CmdHandler(messages.FeedbackMsg{
+					AgentName: d.agentName,
+					Answer:    d.answer,
+					Rating:    d.rating,
+					Comment:   strings.TrimSpace(d.input.Value()),
+				}),
+			)
+		}
+	}
+
+	var cmd tea.Cmd
+	d.input, cmd = d.input.Update(msg)
+	return d, cmd
+}
+
+// View renders the feedback dialog
+func (d *feedbackDialog) View() string {
+	dialogWidth := max(min(d.Width()*80/100, 80), 60)
+	contentWidth := dialogWidth - 6
+
+	title := "👍 Good answer"
+	if d.rating == session.FeedbackNegative {
+		title = "👎 Bad answer"
+	}
+
+	d.input.SetWidth(contentWidth)
+	parts := []string{
+		RenderTitle(title, contentWidth, styles.DialogTitleStyle),
+		RenderSeparator(contentWidth),
+		styles.DialogContentStyle.Render("Comment"),
+		d.input.View(),
+		"",
+		RenderHelpKeys(contentWidth, "enter", "save", "esc", "cancel"),
+	}
+
+	return styles.DialogStyle.
+		Width(dialogWidth).
+		Render(lipgloss.JoinVertical(lipgloss.Left, parts...))
+}
+
+// Position calculates the position to center the dialog
+func (d *feedbackDialog) Position() (row, col int) {
+	dialogWidth := max(min(d.Width()*80/100, 80), 60)
+	return CenterPosition(d.Width(), d.Height(), dialogWidth, 10)
+}
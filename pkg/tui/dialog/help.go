@@ -0,0 +1,123 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+
+	"github.com/docker/cagent/pkg/tui/clipboard"
+	"github.com/docker/cagent/pkg/tui/components/markdown"
+	"github.com/docker/cagent/pkg/tui/core"
+	"github.com/docker/cagent/pkg/tui/core/layout"
+	"github.com/docker/cagent/pkg/tui/styles"
+)
+
+// helpDialog describes what the team of agents can do.
+type helpDialog struct {
+	BaseDialog
+	keyMap      costDialogKeyMap
+	description string // Markdown
+	offset      int
+}
+
+// NewHelpDialog creates a dialog showing the description of the team, in
+// Markdown.
+func NewHelpDialog(description string) Dialog {
+	return &helpDialog{keyMap: defaultCostKeyMap, description: description}
+}
+
+func (d *helpDialog) Init() tea.Cmd { return nil }
+
+func (d *helpDialog) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		cmd := d.SetSize(msg.Width, msg.Height)
+		return d, cmd
+
+	case tea.KeyPressMsg:
+		switch {
+		case key.Matches(msg, d.keyMap.Close):
+			return d, core.CmdHandler(CloseDialogMsg{})
+		case key.Matches(msg, d.keyMap.Copy):
+			return d, clipboard.Copy(d.description, "Team description copied to clipboard.")
+		case key.Matches(msg, d.keyMap.Up):
+			d.offset = max(0, d.offset-1)
+		case key.Matches(msg, d.keyMap.Down):
+			d.offset++
+		case key.Matches(msg, d.keyMap.PageUp):
+			d.offset = max(0, d.offset-d.pageSize())
+		case key.Matches(msg, d.keyMap.PageDown):
+			d.offset += d.pageSize()
+		}
+
+	case tea.MouseWheelMsg:
+		switch msg.Button.String() {
+		case "wheelup":
+			d.offset = max(0, d.offset-1)
+		case "wheeldown":
+			d.offset++
+		}
+	}
+	return d, nil
+}
+
+func (d *helpDialog) dialogSize() (dialogWidth, maxHeight, contentWidth int) {
+	dialogWidth = d.ComputeDialogWidth(80, 50, 100)
+	maxHeight = min(d.Height()*80/100, 50)
+	contentWidth = d.ContentWidth(dialogWidth, 2)
+	return dialogWidth, maxHeight, contentWidth
+}
+
+func (d *helpDialog) visibleLines() int {
+	_, maxHeight, _ := d.dialogSize()
+	const chromeLines = 3 + 2 + 4 // title, separator and space, help and space, border and padding
+	return max(1, maxHeight-chromeLines)
+}
+
+func (d *helpDialog) pageSize() int {
+	return max(1, d.visibleLines()-1)
+}
+
+func (d *helpDialog) Position() (row, col int) {
+	dialogWidth, maxHeight, _ := d.dialogSize()
+	return CenterPosition(d.Width(), d.Height(), dialogWidth, maxHeight)
+}
+
+func (d *helpDialog) View() string {
+	dialogWidth, _, contentWidth := d.dialogSize()
+
+	rendered, err := markdown.NewFastRenderer(contentWidth).Render(d.description)
+	if err != nil {
+		rendered = d.description
+	}
+	lines := strings.Split(strings.TrimRight(rendered, "\n"), "\n")
+
+	visible := d.visibleLines()
+	d.offset = min(d.offset, max(0, len(lines)-visible))
+	end := min(d.offset+visible, len(lines))
+
+	parts := []string{
+		RenderTitle("What this agent can do", contentWidth, styles.DialogTitleStyle),
+		RenderSeparator(contentWidth),
+		"",
+	}
+	parts = append(parts, lines[d.offset:end]...)
+
+	if len(lines) > visible {
+		scrollInfo := fmt.Sprintf("[%d-%d of %d]", d.offset+1, end, len(lines))
+		if d.offset > 0 {
+			scrollInfo = "↑ " + scrollInfo
+		}
+		if end < len(lines) {
+			scrollInfo += " ↓"
+		}
+		parts = append(parts, styles.MutedStyle.Render(scrollInfo))
+	}
+	parts = append(parts, "", RenderHelpKeys(contentWidth, "↑↓", "scroll", "c", "copy", "Esc", "close"))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, parts...)
+	return styles.DialogStyle.Padding(1, 2).Width(dialogWidth).Render(content)
+}
@@ -0,0 +1,159 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+
+	"github.com/docker/cagent/pkg/tui/core"
+	"github.com/docker/cagent/pkg/tui/core/layout"
+	"github.com/docker/cagent/pkg/tui/keys"
+	"github.com/docker/cagent/pkg/tui/styles"
+)
+
+// keyBindingsDialog lists the current key bindings, as configured by the user.
+type keyBindingsDialog struct {
+	BaseDialog
+	keyMap   keyBindingsDialogKeyMap
+	bindings []keys.Binding
+	offset   int
+}
+
+type keyBindingsDialogKeyMap struct {
+	Close, Up, Down, PageUp, PageDown key.Binding
+}
+
+var defaultKeyBindingsKeyMap = keyBindingsDialogKeyMap{
+	Close:    key.NewBinding(key.WithKeys("esc", "enter", "q", "?"), key.WithHelp("Esc", "close")),
+	Up:       key.NewBinding(key.WithKeys("up", "k")),
+	Down:     key.NewBinding(key.WithKeys("down", "j")),
+	PageUp:   key.NewBinding(key.WithKeys("pgup")),
+	PageDown: key.NewBinding(key.WithKeys("pgdown")),
+}
+
+// NewKeyBindingsDialog creates the help overlay listing every key binding.
+func NewKeyBindingsDialog() Dialog {
+	return &keyBindingsDialog{keyMap: defaultKeyBindingsKeyMap, bindings: keys.All()}
+}
+
+func (d *keyBindingsDialog) Init() tea.Cmd { return nil }
+
+func (d *keyBindingsDialog) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		cmd := d.SetSize(msg.Width, msg.Height)
+		return d, cmd
+
+	case tea.KeyPressMsg:
+		if cmd := HandleQuit(msg); cmd != nil {
+			return d, cmd
+		}
+		switch {
+		case key.Matches(msg, d.keyMap.Close):
+			return d, core.CmdHandler(CloseDialogMsg{})
+		case key.Matches(msg, d.keyMap.Up):
+			d.offset = max(0, d.offset-1)
+		case key.Matches(msg, d.keyMap.Down):
+			d.offset++
+		case key.Matches(msg, d.keyMap.PageUp):
+			d.offset = max(0, d.offset-d.pageSize())
+		case key.Matches(msg, d.keyMap.PageDown):
+			d.offset += d.pageSize()
+		}
+
+	case tea.MouseWheelMsg:
+		switch msg.Button.String() {
+		case "wheelup":
+			d.offset = max(0, d.offset-1)
+		case "wheeldown":
+			d.offset++
+		}
+	}
+	return d, nil
+}
+
+func (d *keyBindingsDialog) dialogSize() (dialogWidth, maxHeight, contentWidth int) {
+	dialogWidth = d.ComputeDialogWidth(60, 50, 80)
+	maxHeight = min(d.Height()*80/100, 50)
+	contentWidth = d.ContentWidth(dialogWidth, 2)
+	return dialogWidth, maxHeight, contentWidth
+}
+
+// visibleLines is the number of binding lines that fit in the dialog, below
+// the title and above the help.
+func (d *keyBindingsDialog) visibleLines() int {
+	_, maxHeight, _ := d.dialogSize()
+	return max(1, maxHeight-10)
+}
+
+func (d *keyBindingsDialog) pageSize() int {
+	return d.visibleLines()
+}
+
+func (d *keyBindingsDialog) Position() (row, col int) {
+	dialogWidth, maxHeight, _ := d.dialogSize()
+	return CenterPosition(d.Width(), d.Height(), dialogWidth, maxHeight)
+}
+
+func (d *keyBindingsDialog) View() string {
+	dialogWidth, _, contentWidth := d.dialogSize()
+
+	lines := d.renderLines(contentWidth)
+	visible := d.visibleLines()
+	d.offset = min(d.offset, max(0, len(lines)-visible))
+	end := min(d.offset+visible, len(lines))
+
+	parts := []string{
+		RenderTitle("Key Bindings", contentWidth, styles.DialogTitleStyle),
+		RenderSeparator(contentWidth),
+		"",
+	}
+	parts = append(parts, lines[d.offset:end]...)
+	if len(lines) > visible {
+		scrollInfo := fmt.Sprintf("[%d-%d of %d]", d.offset+1, end, len(lines))
+		if d.offset > 0 {
+			scrollInfo = "↑ " + scrollInfo
+		}
+		if end < len(lines) {
+			scrollInfo += " ↓"
+		}
+		parts = append(parts, styles.MutedStyle.Render(scrollInfo))
+	}
+	parts = append(parts, "", RenderHelpKeys(contentWidth, "↑↓", "scroll", "Esc", "close"))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, parts...)
+	return styles.DialogStyle.Padding(1, 2).Width(dialogWidth).Render(content)
+}
+
+// renderLines renders the bindings, grouped under a header per group.
+func (d *keyBindingsDialog) renderLines(contentWidth int) []string {
+	keyWidth := 0
+	formatted := make([]string, len(d.bindings))
+	for i, b := range d.bindings {
+		names := make([]string, len(b.Keys))
+		for j, k := range b.Keys {
+			names[j] = keys.FormatKey(k)
+		}
+		formatted[i] = strings.Join(names, " / ")
+		keyWidth = max(keyWidth, lipgloss.Width(formatted[i]))
+	}
+	keyWidth = min(keyWidth, contentWidth/2)
+
+	var lines []string
+	group := ""
+	for i, b := range d.bindings {
+		if b.Group != group {
+			if group != "" {
+				lines = append(lines, "")
+			}
+			group = b.Group
+			lines = append(lines, styles.HighlightWhiteStyle.Render(group))
+		}
+		keyCol := styles.SecondaryStyle.Width(keyWidth).Render(formatted[i])
+		lines = append(lines, "  "+keyCol+"  "+styles.MutedStyle.Render(b.Description))
+	}
+	return lines
+}
@@ -0,0 +1,37 @@
+package dialog
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyBindingsDialogView(t *testing.T) {
+	t.Parallel()
+
+	d := NewKeyBindingsDialog()
+	d.Update(tea.WindowSizeMsg{Width: 120, Height: 200})
+
+	view := ansi.Strip(d.View())
+	assert.Contains(t, view, "Key Bindings")
+	assert.Contains(t, view, "Global")
+	assert.Contains(t, view, "Transcript")
+	assert.Contains(t, view, "Ctrl+k / Ctrl+p")
+	assert.Contains(t, view, "commands")
+}
+
+func TestKeyBindingsDialogScroll(t *testing.T) {
+	t.Parallel()
+
+	d := NewKeyBindingsDialog()
+	d.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+
+	assert.NotContains(t, ansi.Strip(d.View()), "previous match")
+
+	for range 10 {
+		d.Update(tea.KeyPressMsg{Code: tea.KeyPgDown})
+	}
+	assert.Contains(t, ansi.Strip(d.View()), "previous match")
+}
@@ -324,9 +324,18 @@ func (d *modelPickerDialog) View() string {
 		contentBuilder.AddContent(errorStyle.Render("⚠ " + d.errMsg))
 	}
 
-	content := contentBuilder.
+	contentBuilder.
 		AddSeparator().
-		AddContent(strings.Join(modelLines, "\n")).
+		AddContent(strings.Join(modelLines, "\n"))
+
+	// Context window and pricing of the selected model
+	if d.selected >= 0 && d.selected < len(d.filtered) {
+		if details := modelDetails(d.filtered[d.selected]); details != "" {
+			contentBuilder.AddSeparator().AddContent(details)
+		}
+	}
+
+	content := contentBuilder.
 		AddSpace().
 		AddHelpKeys("↑/↓", "navigate", "enter", "select", "esc", "cancel").
 		Build()
@@ -390,3 +399,17 @@ func (d *modelPickerDialog) Position() (row, col int) {
 	dialogWidth, maxHeight, _ := d.dialogSize()
 	return CenterPosition(d.Width(), d.Height(), dialogWidth, maxHeight)
 }
+
+// modelDetails describes the context window and the pricing of a model, when
+// they are known.
+func modelDetails(model runtime.ModelChoice) string {
+	var parts []string
+	if model.ContextLimit > 0 {
+		parts = append(parts, styles.MutedStyle.Render("Context: ")+styles.SecondaryStyle.Render(formatTokenCount(int64(model.ContextLimit))+" tokens"))
+	}
+	if model.Cost != nil {
+		price := fmt.Sprintf("%s in, %s out per 1M tokens", formatCost(model.Cost.Input), formatCost(model.Cost.Output))
+		parts = append(parts, styles.MutedStyle.Render("Price: ")+styles.SecondaryStyle.Render(price))
+	}
+	return strings.Join(parts, "\n")
+}
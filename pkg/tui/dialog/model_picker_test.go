@@ -5,9 +5,11 @@ import (
 
 	"charm.land/bubbles/v2/key"
 	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/docker/cagent/pkg/modelsdev"
 	"github.com/docker/cagent/pkg/runtime"
 )
 
@@ -163,6 +165,29 @@ func TestModelPickerViewShowsSelection(t *testing.T) {
 	require.NotEqual(t, view1, view2, "view should change after navigation")
 }
 
+func TestModelPickerShowsSelectedModelDetails(t *testing.T) {
+	t.Parallel()
+
+	models := []runtime.ModelChoice{
+		{Name: "default_model", Ref: "default_model", Provider: "anthropic", Model: "claude", IsDefault: true, ContextLimit: 200_000, Cost: &modelsdev.Cost{Input: 3, Output: 15}},
+		{Name: "local", Ref: "local", Provider: "dmr", Model: "llama"},
+	}
+
+	d := NewModelPickerDialog(models).(*modelPickerDialog)
+	d.Init()
+	d.Update(tea.WindowSizeMsg{Width: 100, Height: 50})
+
+	view := ansi.Strip(d.View())
+	assert.Contains(t, view, "Context: 200.0K tokens")
+	assert.Contains(t, view, "Price: $3.00 in, $15.00 out per 1M tokens")
+
+	// Nothing is known about the second model
+	d.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	view = ansi.Strip(d.View())
+	assert.NotContains(t, view, "Context:")
+	assert.NotContains(t, view, "Price:")
+}
+
 func TestModelPickerPageNavigation(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,146 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/tui/core"
+	"github.com/docker/cagent/pkg/tui/core/layout"
+	"github.com/docker/cagent/pkg/tui/messages"
+	"github.com/docker/cagent/pkg/tui/styles"
+)
+
+// rewindDialog lists the checkpoints of a session, the messages the user
+// sent, to pick the one to rewind the session to.
+type rewindDialog struct {
+	BaseDialog
+	sessionID   string
+	checkpoints []session.Checkpoint
+	selected    int // index in checkpoints, listed newest first
+	offset      int
+	keyMap      commandPaletteKeyMap
+}
+
+// NewRewindDialog creates a dialog picking the checkpoint of a session to
+// rewind it to.
+func NewRewindDialog(sessionID string, checkpoints []session.Checkpoint) Dialog {
+	return &rewindDialog{
+		sessionID:   sessionID,
+		checkpoints: checkpoints,
+		selected:    len(checkpoints) - 1,
+		keyMap:      defaultCommandPaletteKeyMap(),
+	}
+}
+
+func (d *rewindDialog) Init() tea.Cmd { return nil }
+
+func (d *rewindDialog) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		cmd := d.SetSize(msg.Width, msg.Height)
+		return d, cmd
+
+	case tea.KeyPressMsg:
+		if cmd := HandleQuit(msg); cmd != nil {
+			return d, cmd
+		}
+
+		switch {
+		case key.Matches(msg, d.keyMap.Escape):
+			return d, core.CmdHandler(CloseDialogMsg{})
+		case key.Matches(msg, d.keyMap.Up):
+			d.selected = min(d.selected+1, len(d.checkpoints)-1)
+		case key.Matches(msg, d.keyMap.Down):
+			d.selected = max(d.selected-1, 0)
+		case key.Matches(msg, d.keyMap.PageUp):
+			d.selected = min(d.selected+d.pageSize(), len(d.checkpoints)-1)
+		case key.Matches(msg, d.keyMap.PageDown):
+			d.selected = max(d.selected-d.pageSize(), 0)
+		case key.Matches(msg, d.keyMap.Enter):
+			return d, tea.Sequence(
+				core.CmdHandler(CloseDialogMsg{}),
+				core.CmdHandler(messages.RewindSessionMsg{SessionID: d.sessionID, Checkpoint: d.selected}),
+			)
+		}
+	}
+	return d, nil
+}
+
+func (d *rewindDialog) dialogSize() (dialogWidth, maxHeight, contentWidth int) {
+	dialogWidth = max(min(d.Width()*80/100, 80), 60)
+	maxHeight = min(d.Height()*70/100, 30)
+	contentWidth = dialogWidth - 6
+	return dialogWidth, maxHeight, contentWidth
+}
+
+func (d *rewindDialog) pageSize() int {
+	_, maxHeight, _ := d.dialogSize()
+	return max(1, maxHeight-9)
+}
+
+func (d *rewindDialog) View() string {
+	dialogWidth, _, contentWidth := d.dialogSize()
+	maxItems := d.pageSize()
+
+	// Rows are listed newest first
+	row := len(d.checkpoints) - 1 - d.selected
+	if row < d.offset {
+		d.offset = row
+	} else if row >= d.offset+maxItems {
+		d.offset = row - maxItems + 1
+	}
+
+	var lines []string
+	end := min(d.offset+maxItems, len(d.checkpoints))
+	for r := d.offset; r < end; r++ {
+		i := len(d.checkpoints) - 1 - r
+		lines = append(lines, d.renderCheckpoint(i, i == d.selected, contentWidth))
+	}
+	if end < len(d.checkpoints) {
+		lines = append(lines, styles.MutedStyle.Render(fmt.Sprintf("  … and %d more", len(d.checkpoints)-end)))
+	}
+
+	content := NewContent(contentWidth).
+		AddTitle("Rewind").
+		AddSpace().
+		AddContent(styles.MutedStyle.Render("The conversation continues from before the message, in a new session.")).
+		AddSeparator().
+		AddContent(strings.Join(lines, "\n")).
+		AddSpace().
+		AddHelpKeys("↑/↓", "navigate", "enter", "rewind", "esc", "close").
+		Build()
+
+	return styles.DialogStyle.Width(dialogWidth).Render(content)
+}
+
+func (d *rewindDialog) renderCheckpoint(i int, selected bool, maxWidth int) string {
+	promptStyle, detailsStyle := styles.PaletteUnselectedActionStyle, styles.PaletteUnselectedDescStyle
+	if selected {
+		promptStyle, detailsStyle = styles.PaletteSelectedActionStyle, styles.PaletteSelectedDescStyle
+	}
+
+	cp := d.checkpoints[i]
+	details := " • " + cp.CreatedAt.Format("Jan 2 15:04")
+	if tokens := cp.InputTokens + cp.OutputTokens; tokens > 0 {
+		details += " • " + formatTokenCount(tokens) + " tokens, " + formatCost(cp.Cost)
+	}
+
+	prompt := fmt.Sprintf("%d. %s", i+1, strings.Join(strings.Fields(cp.Prompt), " "))
+	maxPromptLen := max(10, maxWidth-lipgloss.Width(details)-2)
+	if lipgloss.Width(prompt) > maxPromptLen {
+		prompt = ansi.Truncate(prompt, maxPromptLen, "…")
+	}
+
+	return "  " + promptStyle.Render(prompt) + detailsStyle.Render(details)
+}
+
+func (d *rewindDialog) Position() (row, col int) {
+	dialogWidth, maxHeight, _ := d.dialogSize()
+	return CenterPosition(d.Width(), d.Height(), dialogWidth, maxHeight)
+}
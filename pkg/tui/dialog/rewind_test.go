@@ -0,0 +1,40 @@
+package dialog
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/session"
+)
+
+func TestRewindDialog(t *testing.T) {
+	checkpoints := []session.Checkpoint{
+		{Items: 0, Prompt: "First", CreatedAt: time.Now()},
+		{Items: 2, Prompt: "Second", CreatedAt: time.Now()},
+		{Items: 4, Prompt: "Third\nwith details", CreatedAt: time.Now(), InputTokens: 1200, Cost: 0.01},
+	}
+
+	d := NewRewindDialog("sess", checkpoints).(*rewindDialog)
+	d.Init()
+	d.Update(tea.WindowSizeMsg{Width: 100, Height: 50})
+
+	// The latest message is selected first, at the top
+	require.Equal(t, 2, d.selected)
+	view := d.View()
+	require.Contains(t, view, "3. Third with details")
+	require.Less(t, strings.Index(view, "3. Third"), strings.Index(view, "1. First"))
+
+	d.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	require.Equal(t, 1, d.selected)
+	d.Update(tea.KeyPressMsg{Code: tea.KeyUp})
+	d.Update(tea.KeyPressMsg{Code: tea.KeyUp})
+	require.Equal(t, 2, d.selected)
+
+	d.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	_, cmd := d.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	require.NotNil(t, cmd)
+}
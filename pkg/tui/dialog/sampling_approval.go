@@ -0,0 +1,95 @@
+package dialog
+
+import (
+	"context"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/docker/cagent/pkg/app"
+	"github.com/docker/cagent/pkg/tools"
+	"github.com/docker/cagent/pkg/tui/core"
+	"github.com/docker/cagent/pkg/tui/core/layout"
+	"github.com/docker/cagent/pkg/tui/styles"
+)
+
+type samplingApprovalDialog struct {
+	BaseDialog
+	message string
+	app     *app.App
+	keyMap  ConfirmKeyMap
+}
+
+// NewSamplingApprovalDialog creates a dialog asking the user to approve a
+// completion requested by an MCP server
+func NewSamplingApprovalDialog(message string, appInstance *app.App) Dialog {
+	return &samplingApprovalDialog{
+		message: message,
+		app:     appInstance,
+		keyMap:  DefaultConfirmKeyMap(),
+	}
+}
+
+// Init initializes the sampling approval dialog
+func (d *samplingApprovalDialog) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages for the sampling approval dialog
+func (d *samplingApprovalDialog) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		cmd := d.SetSize(msg.Width, msg.Height)
+		return d, cmd
+
+	case tea.KeyPressMsg:
+		if cmd := HandleQuit(msg); cmd != nil {
+			return d, cmd
+		}
+
+		model, cmd, handled := HandleConfirmKeys(msg, d.keyMap,
+			func() (layout.Model, tea.Cmd) {
+				_ = d.app.ResumeElicitation(context.Background(), tools.ElicitationActionAccept, nil)
+				return d, core.CmdHandler(CloseDialogMsg{})
+			},
+			func() (layout.Model, tea.Cmd) {
+				_ = d.app.ResumeElicitation(context.Background(), tools.ElicitationActionDecline, nil)
+				return d, core.CmdHandler(CloseDialogMsg{})
+			},
+		)
+		if handled {
+			return model, cmd
+		}
+	}
+
+	return d, nil
+}
+
+// Position returns the dialog position (centered)
+func (d *samplingApprovalDialog) Position() (row, col int) {
+	return d.CenterDialog(d.View())
+}
+
+// View renders the sampling approval dialog
+func (d *samplingApprovalDialog) View() string {
+	dialogWidth := d.ComputeDialogWidth(60, 40, 90)
+	contentWidth := d.ContentWidth(dialogWidth, 2)
+
+	request := styles.DialogContentStyle.
+		Width(contentWidth).
+		Render(d.message)
+
+	content := NewContent(contentWidth).
+		AddContent(styles.DialogTitleInfoStyle.Width(contentWidth).Render("Sampling Request")).
+		AddSpace().
+		AddContent(request).
+		AddSpace().
+		AddHelp("The completion uses the model of the agent and counts towards your usage.").
+		AddSpace().
+		AddHelpKeys("Y", "approve", "N", "decline").
+		Build()
+
+	return styles.DialogWarningStyle.
+		Padding(1, 2).
+		Width(dialogWidth).
+		Render(content)
+}
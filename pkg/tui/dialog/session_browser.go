@@ -2,6 +2,7 @@ package dialog
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -9,9 +10,10 @@ import (
 	"charm.land/bubbles/v2/textinput"
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
-	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/x/ansi"
 
 	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/tui/clipboard"
 	"github.com/docker/cagent/pkg/tui/components/notification"
 	"github.com/docker/cagent/pkg/tui/core"
 	"github.com/docker/cagent/pkg/tui/core/layout"
@@ -30,6 +32,9 @@ type sessionBrowserKeyMap struct {
 	Star       key.Binding
 	FilterStar key.Binding
 	CopyID     key.Binding
+	Rename     key.Binding
+	Delete     key.Binding
+	Rewind     key.Binding
 }
 
 // defaultSessionBrowserKeyMap returns default key bindings
@@ -45,6 +50,9 @@ func defaultSessionBrowserKeyMap() sessionBrowserKeyMap {
 		Star:       key.NewBinding(key.WithKeys("s")),
 		FilterStar: key.NewBinding(key.WithKeys("f")),
 		CopyID:     key.NewBinding(key.WithKeys("c")),
+		Rename:     key.NewBinding(key.WithKeys("ctrl+r")),
+		Delete:     key.NewBinding(key.WithKeys("ctrl+d")),
+		Rewind:     key.NewBinding(key.WithKeys("ctrl+b")),
 	}
 }
 
@@ -58,10 +66,17 @@ type sessionBrowserDialog struct {
 	keyMap     sessionBrowserKeyMap // key bindings
 	openedAt   time.Time            // when dialog was opened, for stable time display
 	starFilter int                  // 0 = all, 1 = starred only, 2 = unstarred only
+	currentID  string               // the session being used, that can't be deleted
+
+	// Renaming or confirming the deletion of the selected session
+	renameInput textinput.Model
+	renaming    bool
+	deleting    bool
 }
 
-// NewSessionBrowserDialog creates a new session browser dialog
-func NewSessionBrowserDialog(sessions []session.Summary) Dialog {
+// NewSessionBrowserDialog creates a new session browser dialog. currentID is
+// the session being used.
+func NewSessionBrowserDialog(sessions []session.Summary, currentID string) Dialog {
 	ti := textinput.New()
 	ti.Placeholder = "Type to search sessions…"
 	ti.Focus()
@@ -76,11 +91,16 @@ func NewSessionBrowserDialog(sessions []session.Summary) Dialog {
 		}
 	}
 
+	renameInput := textinput.New()
+	renameInput.CharLimit = 100
+
 	d := &sessionBrowserDialog{
-		textInput: ti,
-		sessions:  nonEmptySessions,
-		keyMap:    defaultSessionBrowserKeyMap(),
-		openedAt:  time.Now(),
+		textInput:   ti,
+		sessions:    nonEmptySessions,
+		keyMap:      defaultSessionBrowserKeyMap(),
+		openedAt:    time.Now(),
+		currentID:   currentID,
+		renameInput: renameInput,
 	}
 	// Initialize filtered list
 	d.filterSessions()
@@ -101,6 +121,12 @@ func (d *sessionBrowserDialog) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
 		if cmd := HandleQuit(msg); cmd != nil {
 			return d, cmd
 		}
+		if d.renaming {
+			return d.updateRename(msg)
+		}
+		if d.deleting {
+			return d.updateDelete(msg)
+		}
 
 		switch {
 		case key.Matches(msg, d.keyMap.Escape):
@@ -170,8 +196,35 @@ func (d *sessionBrowserDialog) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
 		case key.Matches(msg, d.keyMap.CopyID):
 			if d.selected >= 0 && d.selected < len(d.filtered) {
 				sessionID := d.filtered[d.selected].ID
-				_ = clipboard.WriteAll(sessionID)
-				return d, notification.SuccessCmd("Session ID copied to clipboard.")
+				return d, clipboard.Copy(sessionID, "Session ID copied to clipboard.")
+			}
+			return d, nil
+
+		case key.Matches(msg, d.keyMap.Rename):
+			if d.selected >= 0 && d.selected < len(d.filtered) {
+				d.renaming = true
+				d.renameInput.SetValue(d.filtered[d.selected].Title)
+				d.renameInput.CursorEnd()
+				d.textInput.Blur()
+				return d, d.renameInput.Focus()
+			}
+			return d, nil
+
+		case key.Matches(msg, d.keyMap.Rewind):
+			if d.selected >= 0 && d.selected < len(d.filtered) {
+				return d, tea.Sequence(
+					core.CmdHandler(CloseDialogMsg{}),
+					core.CmdHandler(messages.ShowRewindMsg{SessionID: d.filtered[d.selected].ID}),
+				)
+			}
+			return d, nil
+
+		case key.Matches(msg, d.keyMap.Delete):
+			if d.selected >= 0 && d.selected < len(d.filtered) {
+				if d.filtered[d.selected].ID == d.currentID {
+					return d, notification.InfoCmd("The current session can't be deleted")
+				}
+				d.deleting = true
 			}
 			return d, nil
 
@@ -186,6 +239,62 @@ func (d *sessionBrowserDialog) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
 	return d, nil
 }
 
+// updateRename handles the keys while the selected session is renamed.
+func (d *sessionBrowserDialog) updateRename(msg tea.KeyPressMsg) (layout.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, d.keyMap.Escape):
+		d.stopRenaming()
+		return d, nil
+
+	case key.Matches(msg, d.keyMap.Enter):
+		title := strings.TrimSpace(d.renameInput.Value())
+		sessionID := d.filtered[d.selected].ID
+		d.stopRenaming()
+		if title == "" || title == d.filtered[d.selected].Title {
+			return d, nil
+		}
+		d.updateSession(sessionID, func(s *session.Summary) { s.Title = title })
+		d.filterSessions()
+		return d, core.CmdHandler(messages.RenameSessionMsg{SessionID: sessionID, Title: title})
+
+	default:
+		var cmd tea.Cmd
+		d.renameInput, cmd = d.renameInput.Update(msg)
+		return d, cmd
+	}
+}
+
+func (d *sessionBrowserDialog) stopRenaming() {
+	d.renaming = false
+	d.renameInput.Blur()
+	d.textInput.Focus()
+}
+
+// updateDelete handles the keys while the deletion of the selected session
+// waits for confirmation.
+func (d *sessionBrowserDialog) updateDelete(msg tea.KeyPressMsg) (layout.Model, tea.Cmd) {
+	d.deleting = false
+	if msg.String() != "y" && msg.String() != "Y" {
+		return d, nil
+	}
+
+	sessionID := d.filtered[d.selected].ID
+	d.sessions = slices.DeleteFunc(d.sessions, func(s session.Summary) bool { return s.ID == sessionID })
+	d.filterSessions()
+	return d, core.CmdHandler(messages.DeleteSessionMsg{SessionID: sessionID})
+}
+
+// updateSession applies update to the session with the given ID, in the
+// list of all the sessions.
+func (d *sessionBrowserDialog) updateSession(sessionID string, update func(*session.Summary)) {
+	for i := range d.sessions {
+		if d.sessions[i].ID == sessionID {
+			update(&d.sessions[i])
+			return
+		}
+	}
+}
+
 func (d *sessionBrowserDialog) filterSessions() {
 	query := strings.ToLower(strings.TrimSpace(d.textInput.Value()))
 
@@ -203,13 +312,13 @@ func (d *sessionBrowserDialog) filterSessions() {
 			}
 		}
 
-		// Apply text search filter
+		// Apply text search filter, on the title and the agent
 		if query != "" {
 			title := sess.Title
 			if title == "" {
 				title = "Untitled"
 			}
-			if !strings.Contains(strings.ToLower(title), query) {
+			if !fuzzyMatch(title, query) && !fuzzyMatch(sess.AgentName, query) {
 				continue
 			}
 		}
@@ -290,16 +399,28 @@ func (d *sessionBrowserDialog) View() string {
 		idFooter = styles.MutedStyle.Render("ID: ") + styles.SecondaryStyle.Render(d.filtered[d.selected].ID)
 	}
 
+	input := d.textInput.View()
+	helpKeys := []string{"↑/↓", "navigate", "s", "star", "f", filterDesc, "c", "copy id", "ctrl+r", "rename", "ctrl+d", "delete", "ctrl+b", "rewind", "enter", "load", "esc", "close"}
+	switch {
+	case d.renaming:
+		d.renameInput.SetWidth(contentWidth - 8)
+		input = styles.MutedStyle.Render("Rename: ") + d.renameInput.View()
+		helpKeys = []string{"enter", "rename", "esc", "cancel"}
+	case d.deleting:
+		idFooter = styles.WarningStyle.Render(fmt.Sprintf("Delete %q? This can't be undone.", d.filtered[d.selected].Title))
+		helpKeys = []string{"y", "delete", "any other key", "cancel"}
+	}
+
 	content := NewContent(contentWidth).
 		AddTitle(title).
 		AddSpace().
-		AddContent(d.textInput.View()).
+		AddContent(input).
 		AddSeparator().
 		AddContent(strings.Join(sessionLines, "\n")).
 		AddSeparator().
 		AddContent(idFooter).
 		AddSpace().
-		AddHelpKeys("↑/↓", "navigate", "s", "star", "f", filterDesc, "c", "copy id", "enter", "load", "esc", "close").
+		AddHelpKeys(helpKeys...).
 		Build()
 
 	return styles.DialogStyle.Width(dialogWidth).Render(content)
@@ -321,13 +442,27 @@ func (d *sessionBrowserDialog) renderSession(sess session.Summary, selected bool
 		title = "Untitled"
 	}
 
+	// Last activity, falling back to the creation for sessions listed
+	// before it was recorded
+	lastActivity := sess.UpdatedAt
+	if lastActivity.IsZero() {
+		lastActivity = sess.CreatedAt
+	}
+	details := " • " + d.timeAgo(lastActivity)
+	if sess.AgentName != "" {
+		details = " • " + sess.AgentName + details
+	}
+	if tokens := sess.InputTokens + sess.OutputTokens; tokens > 0 {
+		details += " • " + formatTokenCount(tokens) + " tokens, " + formatCost(sess.Cost)
+	}
+
 	// Account for star indicator width in title length calculation
-	maxTitleLen := maxWidth - 28 // 25 for time + 3 for star indicator
-	if len(title) > maxTitleLen {
-		title = title[:maxTitleLen-1] + "…"
+	maxTitleLen := max(10, maxWidth-lipgloss.Width(details)-3) // 3 for star indicator
+	if lipgloss.Width(title) > maxTitleLen {
+		title = ansi.Truncate(title, maxTitleLen, "…")
 	}
 
-	return styles.StarIndicator(sess.Starred) + titleStyle.Render(title) + timeStyle.Render(" • "+d.timeAgo(sess.CreatedAt))
+	return styles.StarIndicator(sess.Starred) + titleStyle.Render(title) + timeStyle.Render(details)
 }
 
 func (d *sessionBrowserDialog) timeAgo(t time.Time) string {
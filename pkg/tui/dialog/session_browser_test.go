@@ -7,9 +7,11 @@ import (
 
 	"charm.land/bubbles/v2/key"
 	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/stretchr/testify/require"
 
 	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/tui/messages"
 )
 
 func TestSessionBrowserNavigation(t *testing.T) {
@@ -19,7 +21,7 @@ func TestSessionBrowserNavigation(t *testing.T) {
 		{ID: "3", Title: "Session 3", CreatedAt: time.Now()},
 	}
 
-	dialog := NewSessionBrowserDialog(sessions)
+	dialog := NewSessionBrowserDialog(sessions, "")
 	d := dialog.(*sessionBrowserDialog)
 
 	// Initialize and set window size like the TUI does
@@ -67,7 +69,7 @@ func TestSessionBrowserNavigationWithCtrl(t *testing.T) {
 		{ID: "3", Title: "Session 3", CreatedAt: time.Now()},
 	}
 
-	dialog := NewSessionBrowserDialog(sessions)
+	dialog := NewSessionBrowserDialog(sessions, "")
 	d := dialog.(*sessionBrowserDialog)
 	d.Init()
 	d.Update(tea.WindowSizeMsg{Width: 100, Height: 50})
@@ -96,7 +98,7 @@ func TestSessionBrowserViewShowsSelection(t *testing.T) {
 		{ID: "3", Title: "Session 3", CreatedAt: time.Now()},
 	}
 
-	dialog := NewSessionBrowserDialog(sessions)
+	dialog := NewSessionBrowserDialog(sessions, "")
 	d := dialog.(*sessionBrowserDialog)
 	d.Init()
 	d.Update(tea.WindowSizeMsg{Width: 100, Height: 50})
@@ -126,7 +128,7 @@ func TestSessionBrowserFiltersEmptySessions(t *testing.T) {
 		{ID: "5", Title: "Session 5", CreatedAt: time.Now()},
 	}
 
-	dialog := NewSessionBrowserDialog(sessions)
+	dialog := NewSessionBrowserDialog(sessions, "")
 	d := dialog.(*sessionBrowserDialog)
 
 	// Should only have non-empty sessions
@@ -145,7 +147,7 @@ func TestSessionBrowserAllEmptySessions(t *testing.T) {
 		{ID: "2", Title: "", CreatedAt: time.Now()},
 	}
 
-	dialog := NewSessionBrowserDialog(sessions)
+	dialog := NewSessionBrowserDialog(sessions, "")
 	d := dialog.(*sessionBrowserDialog)
 
 	// Should have no sessions
@@ -164,7 +166,7 @@ func TestSessionBrowserScrolling(t *testing.T) {
 		}
 	}
 
-	dialog := NewSessionBrowserDialog(sessions)
+	dialog := NewSessionBrowserDialog(sessions, "")
 	d := dialog.(*sessionBrowserDialog)
 	d.Init()
 	// Set a small window size to force scrolling
@@ -195,3 +197,138 @@ func TestSessionBrowserScrolling(t *testing.T) {
 	expectedTitle := fmt.Sprintf("Session %d", d.selected+1)
 	require.Contains(t, view, expectedTitle, "view should contain selected session")
 }
+
+func TestSessionBrowserFuzzySearch(t *testing.T) {
+	t.Parallel()
+
+	sessions := []session.Summary{
+		{ID: "1", Title: "Fix the login bug", AgentName: "root", CreatedAt: time.Now()},
+		{ID: "2", Title: "Write the docs", AgentName: "writer", CreatedAt: time.Now()},
+	}
+
+	d := NewSessionBrowserDialog(sessions, "").(*sessionBrowserDialog)
+	d.Init()
+
+	typeText(d, "lgnbug")
+	require.Len(t, d.filtered, 1)
+	require.Equal(t, "1", d.filtered[0].ID)
+
+	// The agent matches too
+	d.textInput.SetValue("")
+	typeText(d, "writer")
+	require.Len(t, d.filtered, 1)
+	require.Equal(t, "2", d.filtered[0].ID)
+}
+
+func TestSessionBrowserShowsDetails(t *testing.T) {
+	t.Parallel()
+
+	sessions := []session.Summary{
+		{ID: "1", Title: "Fix the login bug", AgentName: "root", CreatedAt: time.Now(), InputTokens: 1500, OutputTokens: 500, Cost: 0.25},
+	}
+
+	d := NewSessionBrowserDialog(sessions, "").(*sessionBrowserDialog)
+	d.Update(tea.WindowSizeMsg{Width: 100, Height: 50})
+
+	view := ansi.Strip(d.View())
+	require.Contains(t, view, "root")
+	require.Contains(t, view, "2.0K tokens, $0.25")
+}
+
+func TestSessionBrowserRename(t *testing.T) {
+	t.Parallel()
+
+	sessions := []session.Summary{
+		{ID: "1", Title: "Session 1", CreatedAt: time.Now()},
+	}
+
+	d := NewSessionBrowserDialog(sessions, "").(*sessionBrowserDialog)
+	d.Init()
+
+	d.Update(tea.KeyPressMsg{Code: 'r', Mod: tea.ModCtrl})
+	require.True(t, d.renaming)
+	require.Equal(t, "Session 1", d.renameInput.Value())
+
+	d.renameInput.SetValue("Renamed")
+	_, cmd := d.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	require.False(t, d.renaming)
+	require.Equal(t, messages.RenameSessionMsg{SessionID: "1", Title: "Renamed"}, cmd())
+	require.Equal(t, "Renamed", d.filtered[0].Title)
+
+	// Escape cancels the renaming
+	d.Update(tea.KeyPressMsg{Code: 'r', Mod: tea.ModCtrl})
+	d.renameInput.SetValue("Other")
+	_, cmd = d.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	require.Nil(t, cmd)
+	require.Equal(t, "Renamed", d.filtered[0].Title)
+}
+
+func TestSessionBrowserDelete(t *testing.T) {
+	t.Parallel()
+
+	sessions := []session.Summary{
+		{ID: "current", Title: "Current", CreatedAt: time.Now()},
+		{ID: "old", Title: "Old", CreatedAt: time.Now()},
+	}
+
+	d := NewSessionBrowserDialog(sessions, "current").(*sessionBrowserDialog)
+	d.Init()
+
+	// The current session can't be deleted
+	d.Update(tea.KeyPressMsg{Code: 'd', Mod: tea.ModCtrl})
+	require.False(t, d.deleting)
+
+	// Any other key than y cancels the deletion
+	d.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	d.Update(tea.KeyPressMsg{Code: 'd', Mod: tea.ModCtrl})
+	require.True(t, d.deleting)
+	_, cmd := d.Update(tea.KeyPressMsg{Code: 'n', Text: "n"})
+	require.Nil(t, cmd)
+	require.False(t, d.deleting)
+	require.Len(t, d.filtered, 2)
+
+	d.Update(tea.KeyPressMsg{Code: 'd', Mod: tea.ModCtrl})
+	_, cmd = d.Update(tea.KeyPressMsg{Code: 'y', Text: "y"})
+	require.Equal(t, messages.DeleteSessionMsg{SessionID: "old"}, cmd())
+	require.Len(t, d.filtered, 1)
+	require.Equal(t, "current", d.filtered[0].ID)
+}
+
+func typeText(d *sessionBrowserDialog, text string) {
+	for _, r := range text {
+		d.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+}
+
+// BenchmarkSessionBrowserView measures the frames per second of the session
+// browser with M sessions.
+func BenchmarkSessionBrowserView(b *testing.B) {
+	for _, count := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("sessions=%d", count), func(b *testing.B) {
+			sessions := make([]session.Summary, count)
+			for i := range sessions {
+				sessions[i] = session.Summary{ID: fmt.Sprint(i), Title: fmt.Sprintf("Session %d", i), CreatedAt: time.Now()}
+			}
+			d := NewSessionBrowserDialog(sessions, "").(*sessionBrowserDialog)
+			d.Init()
+			d.Update(tea.WindowSizeMsg{Width: 120, Height: 50})
+
+			b.ResetTimer()
+			for range b.N {
+				_ = d.View()
+			}
+			b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "frames/s")
+		})
+	}
+}
+
+func TestSessionBrowserRewind(t *testing.T) {
+	sessions := []session.Summary{{ID: "1", Title: "Session 1", CreatedAt: time.Now()}}
+
+	d := NewSessionBrowserDialog(sessions, "").(*sessionBrowserDialog)
+	d.Init()
+	d.Update(tea.WindowSizeMsg{Width: 100, Height: 50})
+
+	_, cmd := d.Update(tea.KeyPressMsg{Code: 'b', Mod: tea.ModCtrl})
+	require.NotNil(t, cmd)
+}
@@ -1,6 +1,9 @@
 package dialog
 
 import (
+	"encoding/json"
+	"fmt"
+	"slices"
 	"strings"
 
 	"charm.land/bubbles/v2/key"
@@ -8,6 +11,7 @@ import (
 	"charm.land/lipgloss/v2"
 
 	"github.com/docker/cagent/pkg/runtime"
+	"github.com/docker/cagent/pkg/tools"
 	"github.com/docker/cagent/pkg/tui/components/messages"
 	"github.com/docker/cagent/pkg/tui/core"
 	"github.com/docker/cagent/pkg/tui/core/layout"
@@ -58,12 +62,17 @@ func (d *toolConfirmationDialog) SetSize(width, height int) tea.Cmd {
 	question := styles.DialogQuestionStyle.Width(contentWidth).Render("Do you want to allow this tool call?")
 	questionHeight := lipgloss.Height(question)
 
-	options := RenderHelpKeys(contentWidth, "Y", "yes", "N", "no", "A", "all (approve all tools this session)")
+	options := d.renderOptions(contentWidth)
 	optionsHeight := lipgloss.Height(options)
 
+	risksHeight := 0
+	if risks := d.renderRisks(contentWidth); risks != "" {
+		risksHeight = lipgloss.Height(risks) + 1
+	}
+
 	// Calculate available height for scroll view
-	// Total = maxDialogHeight - title - separator - 2 empty lines - question - empty line - options - 4 (dialog padding/border)
-	availableHeight := max(maxDialogHeight-titleHeight-separatorHeight-2-questionHeight-1-optionsHeight-4, 5)
+	// Total = maxDialogHeight - title - separator - 2 empty lines - risks - question - empty line - options - 4 (dialog padding/border)
+	availableHeight := max(maxDialogHeight-titleHeight-separatorHeight-2-risksHeight-questionHeight-1-optionsHeight-4, 5)
 	d.scrollView.SetSize(contentWidth, availableHeight)
 
 	return nil
@@ -71,9 +80,11 @@ func (d *toolConfirmationDialog) SetSize(width, height int) tea.Cmd {
 
 // toolConfirmationKeyMap defines key bindings for tool confirmation dialog
 type toolConfirmationKeyMap struct {
-	Yes key.Binding
-	No  key.Binding
-	All key.Binding
+	Yes     key.Binding
+	No      key.Binding
+	Session key.Binding
+	Always  key.Binding
+	All     key.Binding
 }
 
 // defaultToolConfirmationKeyMap returns default key bindings
@@ -87,6 +98,14 @@ func defaultToolConfirmationKeyMap() toolConfirmationKeyMap {
 			key.WithKeys("n", "N"),
 			key.WithHelp("N", "reject"),
 		),
+		Session: key.NewBinding(
+			key.WithKeys("s", "S"),
+			key.WithHelp("S", "approve the tool for the session"),
+		),
+		Always: key.NewBinding(
+			key.WithKeys("t", "T"),
+			key.WithHelp("T", "always approve the tool"),
+		),
 		All: key.NewBinding(
 			key.WithKeys("a", "A"),
 			key.WithHelp("A", "approve all"),
@@ -139,6 +158,16 @@ func (d *toolConfirmationDialog) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
 				core.CmdHandler(CloseDialogMsg{}),
 				core.CmdHandler(RuntimeResumeMsg{Response: runtime.ResumeTypeReject}),
 			)
+		case key.Matches(msg, d.keyMap.Session):
+			return d, tea.Sequence(
+				core.CmdHandler(CloseDialogMsg{}),
+				core.CmdHandler(RuntimeResumeMsg{Response: runtime.ResumeTypeApproveTool}),
+			)
+		case key.Matches(msg, d.keyMap.Always):
+			return d, tea.Sequence(
+				core.CmdHandler(CloseDialogMsg{}),
+				core.CmdHandler(RuntimeResumeMsg{Response: runtime.ResumeTypeAlwaysApproveTool}),
+			)
 		case key.Matches(msg, d.keyMap.All):
 			d.sessionState.SetYoloMode(true)
 			return d, tea.Sequence(
@@ -192,7 +221,7 @@ func (d *toolConfirmationDialog) View() string {
 	argumentsSection := d.scrollView.View()
 
 	question := styles.DialogQuestionStyle.Width(contentWidth).Render("Do you want to allow this tool call?")
-	options := RenderHelpKeys(contentWidth, "Y", "yes", "N", "no", "A", "all (approve all tools this session)")
+	options := d.renderOptions(contentWidth)
 
 	// Combine all parts with proper spacing
 	parts := []string{title, separator}
@@ -201,6 +230,10 @@ func (d *toolConfirmationDialog) View() string {
 		parts = append(parts, "", argumentsSection)
 	}
 
+	if risks := d.renderRisks(contentWidth); risks != "" {
+		parts = append(parts, "", risks)
+	}
+
 	parts = append(parts, "", question, "", options)
 
 	content := lipgloss.JoinVertical(lipgloss.Left, parts...)
@@ -214,3 +247,74 @@ func (d *toolConfirmationDialog) Position() (row, col int) {
 	renderedDialog := d.View()
 	return CenterPosition(d.Width(), d.Height(), dialogWidth, lipgloss.Height(renderedDialog))
 }
+
+func (d *toolConfirmationDialog) renderOptions(contentWidth int) string {
+	return RenderHelpKeys(contentWidth,
+		"Y", "once",
+		"S", "this tool for the session",
+		"T", fmt.Sprintf("always allow this tool for %s", d.msg.AgentName),
+		"A", "all tools this session",
+		"N", "no",
+	)
+}
+
+func (d *toolConfirmationDialog) renderRisks(contentWidth int) string {
+	risks := toolCallRisks(d.msg.ToolCall, d.msg.ToolDefinition)
+	if len(risks) == 0 {
+		return ""
+	}
+	return styles.WarningStyle.Width(contentWidth).Render(strings.Join(risks, "\n"))
+}
+
+// maxRiskPaths is how many of the paths a tool call touches are listed
+const maxRiskPaths = 5
+
+// toolCallRisks summarizes what a tool call may do: the command it runs, the
+// paths it touches and what its tool declares about itself.
+func toolCallRisks(toolCall tools.ToolCall, tool tools.Tool) []string {
+	var args map[string]any
+	_ = json.Unmarshal([]byte(toolCall.Function.Arguments), &args)
+
+	var risks []string
+	for _, name := range []string{"cmd", "command"} {
+		if cmd, ok := args[name].(string); ok && strings.TrimSpace(cmd) != "" {
+			cmd = strings.TrimSpace(cmd)
+			if first, _, multiline := strings.Cut(cmd, "\n"); multiline {
+				cmd = first + " …"
+			}
+			risks = append(risks, "Runs: "+cmd)
+			break
+		}
+	}
+
+	var paths []string
+	addPath := func(v any) {
+		if p, ok := v.(string); ok && p != "" && !slices.Contains(paths, p) {
+			paths = append(paths, p)
+		}
+	}
+	for _, name := range []string{"path", "paths", "file", "files", "dir", "dirs", "cwd"} {
+		switch v := args[name].(type) {
+		case []any:
+			for _, p := range v {
+				addPath(p)
+			}
+		default:
+			addPath(v)
+		}
+	}
+	if len(paths) > maxRiskPaths {
+		paths = append(paths[:maxRiskPaths], fmt.Sprintf("and %d more", len(paths)-maxRiskPaths))
+	}
+	if len(paths) > 0 {
+		risks = append(risks, "Touches: "+strings.Join(paths, ", "))
+	}
+
+	if hint := tool.Annotations.DestructiveHint; hint != nil && *hint {
+		risks = append(risks, "May modify or delete data")
+	}
+	if hint := tool.Annotations.OpenWorldHint; hint != nil && *hint {
+		risks = append(risks, "Reaches systems outside of this machine")
+	}
+	return risks
+}
@@ -0,0 +1,79 @@
+package dialog
+
+import (
+	"reflect"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/runtime"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/tools"
+	"github.com/docker/cagent/pkg/tui/service"
+)
+
+func TestToolCallRisks(t *testing.T) {
+	t.Parallel()
+
+	destructive := true
+	risks := toolCallRisks(
+		tools.ToolCall{Function: tools.FunctionCall{Name: "shell", Arguments: `{"cmd":"rm -rf build\necho done","cwd":"/src"}`}},
+		tools.Tool{Annotations: tools.ToolAnnotations{DestructiveHint: &destructive}},
+	)
+	assert.Equal(t, []string{"Runs: rm -rf build …", "Touches: /src", "May modify or delete data"}, risks)
+
+	risks = toolCallRisks(
+		tools.ToolCall{Function: tools.FunctionCall{Name: "read_multiple_files", Arguments: `{"paths":["a","b","a","c","d","e","f","g"]}`}},
+		tools.Tool{},
+	)
+	assert.Equal(t, []string{"Touches: a, b, c, d, e, and 2 more"}, risks)
+
+	assert.Empty(t, toolCallRisks(tools.ToolCall{Function: tools.FunctionCall{Name: "think", Arguments: `{"thought":"hmm"}`}}, tools.Tool{}))
+}
+
+func TestToolConfirmationResponses(t *testing.T) {
+	t.Parallel()
+
+	event := &runtime.ToolCallConfirmationEvent{
+		ToolCall:     tools.ToolCall{ID: "call_1", Function: tools.FunctionCall{Name: "shell", Arguments: `{"cmd":"ls"}`}},
+		AgentContext: runtime.AgentContext{AgentName: "root"},
+	}
+
+	for keyText, want := range map[string]runtime.ResumeType{
+		"y": runtime.ResumeTypeApprove,
+		"s": runtime.ResumeTypeApproveTool,
+		"t": runtime.ResumeTypeAlwaysApproveTool,
+		"a": runtime.ResumeTypeApproveSession,
+		"n": runtime.ResumeTypeReject,
+	} {
+		d := NewToolConfirmationDialog(event, service.NewSessionState(session.New()))
+		d.Update(tea.WindowSizeMsg{Width: 120, Height: 50})
+
+		view := ansi.Strip(d.View())
+		assert.Contains(t, view, "Runs: ls")
+		assert.Contains(t, view, "always allow this tool for root")
+
+		_, cmd := d.Update(tea.KeyPressMsg{Code: rune(keyText[0]), Text: keyText})
+		require.NotNil(t, cmd)
+		assert.Contains(t, sequenceMsgs(cmd), RuntimeResumeMsg{Response: want}, keyText)
+	}
+}
+
+// sequenceMsgs runs the commands of a tea.Sequence and returns their messages.
+func sequenceMsgs(cmd tea.Cmd) []tea.Msg {
+	msg := cmd()
+	v := reflect.ValueOf(msg)
+	if v.Kind() != reflect.Slice {
+		return []tea.Msg{msg}
+	}
+	var msgs []tea.Msg
+	for i := range v.Len() {
+		if c, ok := v.Index(i).Interface().(tea.Cmd); ok && c != nil {
+			msgs = append(msgs, c())
+		}
+	}
+	return msgs
+}
@@ -2,23 +2,30 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
+	"strings"
 
 	tea "charm.land/bubbletea/v2"
-	"github.com/atotto/clipboard"
 
 	"github.com/docker/cagent/pkg/browser"
+	modelchat "github.com/docker/cagent/pkg/chat"
 	"github.com/docker/cagent/pkg/evaluation"
+	"github.com/docker/cagent/pkg/runtime"
+	"github.com/docker/cagent/pkg/session"
 	mcptools "github.com/docker/cagent/pkg/tools/mcp"
+	"github.com/docker/cagent/pkg/tui/clipboard"
 	"github.com/docker/cagent/pkg/tui/components/editor"
+	"github.com/docker/cagent/pkg/tui/components/markdown"
 	"github.com/docker/cagent/pkg/tui/components/notification"
 	"github.com/docker/cagent/pkg/tui/core"
 	"github.com/docker/cagent/pkg/tui/dialog"
 	"github.com/docker/cagent/pkg/tui/messages"
 	"github.com/docker/cagent/pkg/tui/page/chat"
 	"github.com/docker/cagent/pkg/tui/service"
+	"github.com/docker/cagent/pkg/tui/styles"
 )
 
 // Session management handlers
@@ -26,15 +33,23 @@ import (
 func (a *appModel) handleNewSession() (tea.Model, tea.Cmd) {
 	a.application.NewSession()
 	sess := a.application.Session()
-	a.sessionState = service.NewSessionState(sess)
+	a.resetSessionState(sess)
 	a.sessionTitle = ""
-	a.chatPage = chat.New(a.application, a.sessionState)
+	a.chatPage = chat.New(a.application, a.sessionState, a.sidebarOpts...)
 	a.dialog = dialog.New()
 	a.statusBar.SetHelp(a.chatPage)
 
 	return a, tea.Batch(a.Init(), a.handleWindowResize(a.wWidth, a.wHeight))
 }
 
+// resetSessionState starts the state of another session, keeping how the
+// user chose to follow the sub-agents.
+func (a *appModel) resetSessionState(sess *session.Session) {
+	hideSubAgents := a.sessionState.HideSubAgents
+	a.sessionState = service.NewSessionState(sess)
+	a.sessionState.HideSubAgents = hideSubAgents
+}
+
 func (a *appModel) handleOpenSessionBrowser() (tea.Model, tea.Cmd) {
 	store := a.application.SessionStore()
 	if store == nil {
@@ -50,10 +65,41 @@ func (a *appModel) handleOpenSessionBrowser() (tea.Model, tea.Cmd) {
 	}
 
 	return a, core.CmdHandler(dialog.OpenDialogMsg{
-		Model: dialog.NewSessionBrowserDialog(sessions),
+		Model: dialog.NewSessionBrowserDialog(sessions, a.application.Session().ID),
 	})
 }
 
+// handleResumeSession loads the given session or, without an ID, the most
+// recent session other than the current one.
+func (a *appModel) handleResumeSession(sessionID string) (tea.Model, tea.Cmd) {
+	if sessionID != "" {
+		return a.handleLoadSession(sessionID)
+	}
+
+	store := a.application.SessionStore()
+	if store == nil {
+		return a, notification.InfoCmd("No session store configured")
+	}
+
+	sessions, err := store.GetSessionSummaries(context.Background())
+	if err != nil {
+		return a, notification.ErrorCmd(fmt.Sprintf("Failed to load sessions: %v", err))
+	}
+
+	currentID := a.application.Session().ID
+	var latest *session.Summary
+	for i := range sessions {
+		if sessions[i].ID != currentID && (latest == nil || sessions[i].CreatedAt.After(latest.CreatedAt)) {
+			latest = &sessions[i]
+		}
+	}
+	if latest == nil {
+		return a, notification.InfoCmd("No previous sessions found")
+	}
+
+	return a.handleLoadSession(latest.ID)
+}
+
 func (a *appModel) handleLoadSession(sessionID string) (tea.Model, tea.Cmd) {
 	store := a.application.SessionStore()
 	if store == nil {
@@ -69,9 +115,9 @@ func (a *appModel) handleLoadSession(sessionID string) (tea.Model, tea.Cmd) {
 
 	// Cancel current session and replace with loaded one
 	a.application.ReplaceSession(context.Background(), sess)
-	a.sessionState = service.NewSessionState(sess)
+	a.resetSessionState(sess)
 	a.sessionTitle = sess.Title
-	a.chatPage = chat.New(a.application, a.sessionState)
+	a.chatPage = chat.New(a.application, a.sessionState, a.sidebarOpts...)
 	a.dialog = dialog.New()
 	a.statusBar.SetHelp(a.chatPage)
 
@@ -117,13 +163,121 @@ func (a *appModel) handleToggleSessionStar(sessionID string) (tea.Model, tea.Cmd
 	return a, nil
 }
 
+// handleFeedback records the feedback of the user on an answer, saved with the
+// session so that the instruction of the agent can be tuned from it.
+func (a *appModel) handleFeedback(msg messages.FeedbackMsg) (tea.Model, tea.Cmd) {
+	sess := a.application.Session()
+	sess.AddFeedback(msg.AgentName, msg.Answer, msg.Rating, msg.Comment)
+	a.chatPage.SetFeedback(msg.AgentName, msg.Answer, msg.Rating)
+
+	if store := a.application.SessionStore(); store != nil {
+		if err := store.UpdateSession(context.Background(), sess); err != nil {
+			return a, notification.ErrorCmd(fmt.Sprintf("Failed to save feedback: %v", err))
+		}
+	}
+
+	return a, notification.SuccessCmd("Feedback saved.")
+}
+
+func (a *appModel) handleRenameSession(sessionID, title string) (tea.Model, tea.Cmd) {
+	store := a.application.SessionStore()
+	if store == nil {
+		return a, notification.ErrorCmd("No session store configured")
+	}
+
+	if currentSess := a.application.Session(); currentSess.ID == sessionID {
+		currentSess.Title = title
+		a.sessionTitle = title
+		// The current session may not have been persisted yet
+		if err := store.UpdateSession(context.Background(), currentSess); err != nil {
+			return a, notification.ErrorCmd(fmt.Sprintf("Failed to save session: %v", err))
+		}
+		updated, cmd := a.chatPage.Update(runtime.SessionTitle(sessionID, title))
+		a.chatPage = updated.(chat.Page)
+		return a, cmd
+	}
+
+	if err := store.SetSessionTitle(context.Background(), sessionID, title); err != nil {
+		return a, notification.ErrorCmd(fmt.Sprintf("Failed to rename session: %v", err))
+	}
+	return a, nil
+}
+
+func (a *appModel) handleDeleteSession(sessionID string) (tea.Model, tea.Cmd) {
+	store := a.application.SessionStore()
+	if store == nil {
+		return a, notification.ErrorCmd("No session store configured")
+	}
+
+	if err := store.DeleteSession(context.Background(), sessionID); err != nil {
+		return a, notification.ErrorCmd(fmt.Sprintf("Failed to delete session: %v", err))
+	}
+	return a, notification.SuccessCmd("Session deleted")
+}
+
+// handleShowRewind opens the checkpoints of a session, to pick the one to
+// rewind it to.
+func (a *appModel) handleShowRewind(sessionID string) (tea.Model, tea.Cmd) {
+	sess, err := a.sessionByID(sessionID)
+	if err != nil {
+		return a, notification.ErrorCmd(fmt.Sprintf("Failed to load session: %v", err))
+	}
+	if len(sess.Checkpoints) == 0 {
+		return a, notification.InfoCmd("No checkpoint to rewind to yet")
+	}
+
+	return a, core.CmdHandler(dialog.OpenDialogMsg{
+		Model: dialog.NewRewindDialog(sess.ID, sess.Checkpoints),
+	})
+}
+
+// handleRewindSession forks a session at a checkpoint and loads the fork, with
+// the message sent at the checkpoint back in the editor. The history of the
+// session isn't changed.
+func (a *appModel) handleRewindSession(sessionID string, checkpoint int) (tea.Model, tea.Cmd) {
+	store := a.application.SessionStore()
+	if store == nil {
+		return a, notification.ErrorCmd("No session store configured")
+	}
+
+	sess, err := a.sessionByID(sessionID)
+	if err != nil {
+		return a, notification.ErrorCmd(fmt.Sprintf("Failed to load session: %v", err))
+	}
+	fork, err := sess.Fork(checkpoint)
+	if err != nil {
+		return a, notification.ErrorCmd(fmt.Sprintf("Failed to rewind session: %v", err))
+	}
+	if err := store.AddSession(context.Background(), fork); err != nil {
+		return a, notification.ErrorCmd(fmt.Sprintf("Failed to save session: %v", err))
+	}
+
+	model, cmd := a.handleLoadSession(fork.ID)
+	a.chatPage.InsertText(sess.Checkpoints[checkpoint].Prompt)
+	return model, tea.Batch(cmd, notification.SuccessCmd("Rewound in a new session; the original session is kept."))
+}
+
+// sessionByID returns the session with the given ID: the current one when the
+// ID is empty or its own, or else the one stored.
+func (a *appModel) sessionByID(sessionID string) (*session.Session, error) {
+	if current := a.application.Session(); sessionID == "" || current.ID == sessionID {
+		return current, nil
+	}
+
+	store := a.application.SessionStore()
+	if store == nil {
+		return nil, errors.New("no session store configured")
+	}
+	return store.GetSession(context.Background(), sessionID)
+}
+
 func (a *appModel) handleEvalSession(filename string) (tea.Model, tea.Cmd) {
 	evalFile, _ := evaluation.Save(a.application.Session(), filename)
 	return a, notification.SuccessCmd(fmt.Sprintf("Eval saved to file %s", evalFile))
 }
 
 func (a *appModel) handleExportSession(filename string) (tea.Model, tea.Cmd) {
-	exportFile, err := a.application.ExportHTML(context.Background(), filename)
+	exportFile, err := a.application.ExportSession(context.Background(), filename)
 	if err != nil {
 		return a, notification.ErrorCmd(fmt.Sprintf("Failed to export session: %v", err))
 	}
@@ -140,14 +294,7 @@ func (a *appModel) handleCopySessionToClipboard() (tea.Model, tea.Cmd) {
 		return a, notification.SuccessCmd("Conversation is empty; nothing copied.")
 	}
 
-	return a, tea.Sequence(
-		tea.SetClipboard(transcript),
-		func() tea.Msg {
-			_ = clipboard.WriteAll(transcript)
-			return nil
-		},
-		notification.SuccessCmd("Conversation copied to clipboard."),
-	)
+	return a, clipboard.Copy(transcript, "Conversation copied to clipboard.")
 }
 
 func (a *appModel) handleCopyLastResponseToClipboard() (tea.Model, tea.Cmd) {
@@ -161,14 +308,26 @@ func (a *appModel) handleCopyLastResponseToClipboard() (tea.Model, tea.Cmd) {
 		return a, notification.InfoCmd("No assistant response to copy.")
 	}
 
-	return a, tea.Sequence(
-		tea.SetClipboard(lastResponse),
-		func() tea.Msg {
-			_ = clipboard.WriteAll(lastResponse)
-			return nil
-		},
-		notification.SuccessCmd("Last response copied to clipboard."),
-	)
+	return a, clipboard.Copy(lastResponse, "Last response copied to clipboard.")
+}
+
+func (a *appModel) handleCopyLastCodeBlockToClipboard() (tea.Model, tea.Cmd) {
+	sess := a.application.Session()
+	if sess == nil {
+		return a, notification.InfoCmd("No active session.")
+	}
+
+	messages := sess.GetAllMessages()
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Message.Role != modelchat.MessageRoleAssistant {
+			continue
+		}
+		if blocks := markdown.CodeBlocks(messages[i].Message.Content); len(blocks) > 0 {
+			return a, clipboard.Copy(blocks[len(blocks)-1].Code, "Last code block copied to clipboard.")
+		}
+	}
+
+	return a, notification.InfoCmd("No code block to copy.")
 }
 
 // Agent management handlers
@@ -196,6 +355,40 @@ func (a *appModel) handleToggleHideToolResults() (tea.Model, tea.Cmd) {
 	return a, cmd
 }
 
+func (a *appModel) handleToggleRawMarkdown() (tea.Model, tea.Cmd) {
+	updated, cmd := a.chatPage.Update(messages.ToggleRawMarkdownMsg{})
+	a.chatPage = updated.(chat.Page)
+	return a, cmd
+}
+
+func (a *appModel) handleToggleExpandToolCalls() (tea.Model, tea.Cmd) {
+	updated, cmd := a.chatPage.Update(messages.ToggleExpandToolCallsMsg{})
+	a.chatPage = updated.(chat.Page)
+	return a, cmd
+}
+
+func (a *appModel) handleToggleHideSubAgents() (tea.Model, tea.Cmd) {
+	updated, cmd := a.chatPage.Update(messages.ToggleHideSubAgentsMsg{})
+	a.chatPage = updated.(chat.Page)
+	return a, cmd
+}
+
+func (a *appModel) handleChangeTheme(name string) (tea.Model, tea.Cmd) {
+	if name == "" {
+		name = styles.NextTheme()
+	}
+
+	theme, ok := styles.ThemeByName(name)
+	if !ok {
+		return a, notification.ErrorCmd(fmt.Sprintf("Unknown theme '%s', available themes: %s", name, strings.Join(styles.ThemeNames(), ", ")))
+	}
+	styles.ApplyTheme(theme)
+
+	updated, cmd := a.chatPage.Update(messages.ThemeChangedMsg{})
+	a.chatPage = updated.(chat.Page)
+	return a, tea.Batch(cmd, notification.SuccessCmd(fmt.Sprintf("Switched to theme '%s'", name)))
+}
+
 func (a *appModel) handleShowCostDialog() (tea.Model, tea.Cmd) {
 	sess := a.application.Session()
 	return a, core.CmdHandler(dialog.OpenDialogMsg{
@@ -203,6 +396,29 @@ func (a *appModel) handleShowCostDialog() (tea.Model, tea.Cmd) {
 	})
 }
 
+// handleShowHelp describes, in the background, what the team of agents can
+// do. Listing the tools starts the toolsets that aren't yet.
+func (a *appModel) handleShowHelp() tea.Cmd {
+	return func() tea.Msg {
+		desc, ok := a.application.DescribeTeam(context.Background())
+		if !ok {
+			return notification.ShowMsg{Text: "The team can't be described with a remote runtime.", Type: notification.TypeWarning}
+		}
+		return dialog.OpenDialogMsg{Model: dialog.NewHelpDialog(desc.Markdown())}
+	}
+}
+
+// handleReloadTools reloads, in the background, the tools of the agents. The
+// runtime reports the tools now available.
+func (a *appModel) handleReloadTools() tea.Cmd {
+	return func() tea.Msg {
+		if err := a.application.ReloadTools(context.Background()); err != nil {
+			return notification.ShowMsg{Text: fmt.Sprintf("Failed to reload the tools: %v", err), Type: notification.TypeError}
+		}
+		return nil
+	}
+}
+
 // MCP prompt handlers
 
 func (a *appModel) handleShowMCPPromptInput(promptName string, promptInfo any) (tea.Model, tea.Cmd) {
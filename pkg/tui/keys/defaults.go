@@ -0,0 +1,96 @@
+package keys
+
+// IDs of the key bindings.
+const (
+	Quit                  = "app.quit"
+	CommandPalette        = "app.commands"
+	ModelPicker           = "app.models"
+	Sessions              = "app.sessions"
+	ToggleYolo            = "app.toggle_yolo"
+	ToggleHideToolResults = "app.toggle_tool_output"
+	SwitchAgent           = "app.cycle_agent"
+	Speak                 = "app.speak"
+	ClearQueue            = "app.clear_queue"
+	KeyBindings           = "app.keybindings"
+
+	SwitchFocus     = "chat.switch_focus"
+	Cancel          = "chat.cancel"
+	ToggleSplitDiff = "chat.toggle_split_diff"
+	AcceptEdit      = "chat.accept_edit"
+	RejectEdit      = "chat.reject_edit"
+	AcceptAllEdits  = "chat.accept_all_edits"
+	NextPane        = "chat.next_pane"
+	PreviousPane    = "chat.previous_pane"
+
+	Newline        = "editor.newline"
+	ExternalEditor = "editor.external_editor"
+	HistorySearch  = "editor.history_search"
+
+	SelectPrevious  = "transcript.select_previous"
+	SelectNext      = "transcript.select_next"
+	ClearSelection  = "transcript.clear_selection"
+	CopyMessage     = "transcript.copy_message"
+	CopyCode        = "transcript.copy_code"
+	CopySession     = "transcript.copy_session"
+	ToggleToolCall  = "transcript.toggle_tool_call"
+	ExpandToolCalls = "transcript.expand_tool_calls"
+	ViewImage       = "transcript.view_image"
+	OpenImage       = "transcript.open_image"
+	PageUp          = "transcript.page_up"
+	PageDown        = "transcript.page_down"
+	ScrollToTop     = "transcript.top"
+	ScrollToBottom  = "transcript.bottom"
+	Search          = "transcript.search"
+	NextMatch       = "transcript.next_match"
+	PreviousMatch   = "transcript.previous_match"
+	RateUp          = "transcript.rate_up"
+	RateDown        = "transcript.rate_down"
+)
+
+// defaults are the bindings with their default keys, in the order they are
+// listed in the help overlay.
+var defaults = []Binding{
+	{ID: Quit, Group: "Global", Description: "quit", Keys: []string{"ctrl+c"}},
+	{ID: CommandPalette, Group: "Global", Description: "commands", Keys: []string{"ctrl+k", "ctrl+p"}},
+	{ID: ModelPicker, Group: "Global", Description: "models", Keys: []string{"ctrl+m"}},
+	{ID: Sessions, Group: "Global", Description: "sessions", Keys: []string{"ctrl+f"}},
+	{ID: ToggleYolo, Group: "Global", Description: "toggle yolo mode", Keys: []string{"ctrl+y"}},
+	{ID: ToggleHideToolResults, Group: "Global", Description: "toggle tool output", Keys: []string{"ctrl+o"}},
+	{ID: SwitchAgent, Group: "Global", Description: "cycle agent", Keys: []string{"ctrl+s"}},
+	{ID: Speak, Group: "Global", Description: "speak", Keys: []string{"ctrl+l"}},
+	{ID: ClearQueue, Group: "Global", Description: "clear queue", Keys: []string{"ctrl+x"}},
+	{ID: KeyBindings, Group: "Global", Description: "key bindings", Keys: []string{"?"}},
+
+	{ID: SwitchFocus, Group: "Chat", Description: "switch focus", Keys: []string{"tab"}},
+	{ID: Cancel, Group: "Chat", Description: "cancel", Keys: []string{"esc"}},
+	{ID: ToggleSplitDiff, Group: "Chat", Description: "toggle split diff mode", Keys: []string{"ctrl+t"}},
+	{ID: AcceptEdit, Group: "Chat", Description: "accept edit", Keys: []string{"y", "Y"}},
+	{ID: RejectEdit, Group: "Chat", Description: "reject edit", Keys: []string{"n", "N"}},
+	{ID: AcceptAllEdits, Group: "Chat", Description: "approve all", Keys: []string{"a", "A"}},
+	{ID: NextPane, Group: "Chat", Description: "next pane", Keys: []string{"]"}},
+	{ID: PreviousPane, Group: "Chat", Description: "previous pane", Keys: []string{"["}},
+
+	{ID: Newline, Group: "Prompt", Description: "newline", Keys: []string{"shift+enter", "alt+enter", "ctrl+j"}},
+	{ID: ExternalEditor, Group: "Prompt", Description: "edit in external editor", Keys: []string{"ctrl+g"}},
+	{ID: HistorySearch, Group: "Prompt", Description: "search history", Keys: []string{"ctrl+r"}},
+
+	{ID: SelectPrevious, Group: "Transcript", Description: "select prev", Keys: []string{"up", "k"}},
+	{ID: SelectNext, Group: "Transcript", Description: "select next", Keys: []string{"down", "j"}},
+	{ID: ClearSelection, Group: "Transcript", Description: "clear selection", Keys: []string{"esc"}},
+	{ID: CopyMessage, Group: "Transcript", Description: "copy message", Keys: []string{"c"}},
+	{ID: CopyCode, Group: "Transcript", Description: "copy code", Keys: []string{"b"}},
+	{ID: CopySession, Group: "Transcript", Description: "copy all", Keys: []string{"C", "shift+c"}},
+	{ID: ToggleToolCall, Group: "Transcript", Description: "expand tool", Keys: []string{"enter"}},
+	{ID: ExpandToolCalls, Group: "Transcript", Description: "expand all", Keys: []string{"e"}},
+	{ID: ViewImage, Group: "Transcript", Description: "view image", Keys: []string{"v"}},
+	{ID: OpenImage, Group: "Transcript", Description: "open image", Keys: []string{"o"}},
+	{ID: PageUp, Group: "Transcript", Description: "page up", Keys: []string{"pgup"}},
+	{ID: PageDown, Group: "Transcript", Description: "page down", Keys: []string{"pgdown"}},
+	{ID: ScrollToTop, Group: "Transcript", Description: "scroll to top", Keys: []string{"home"}},
+	{ID: ScrollToBottom, Group: "Transcript", Description: "scroll to bottom", Keys: []string{"end"}},
+	{ID: Search, Group: "Transcript", Description: "search", Keys: []string{"/"}},
+	{ID: NextMatch, Group: "Transcript", Description: "next match", Keys: []string{"n"}},
+	{ID: PreviousMatch, Group: "Transcript", Description: "previous match", Keys: []string{"N", "shift+n"}},
+	{ID: RateUp, Group: "Transcript", Description: "rate up", Keys: []string{"+"}},
+	{ID: RateDown, Group: "Transcript", Description: "rate down", Keys: []string{"-"}},
+}
@@ -0,0 +1,130 @@
+// Package keys is the registry of the TUI key bindings.
+//
+// Components look their bindings up by ID instead of hardcoding keys, so
+// that users can rebind them in the keybindings section of the user config:
+//
+//	keybindings:
+//	  app.commands: [ctrl+o]
+//	  chat.switch_focus: [tab, ctrl+w]
+package keys
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"charm.land/bubbles/v2/key"
+)
+
+// Binding describes a key binding that can be rebound by the user.
+type Binding struct {
+	// ID identifies the binding in the user config, e.g. "app.quit"
+	ID string
+	// Group is the section of the help overlay the binding is listed in
+	Group string
+	// Description says what the binding does
+	Description string
+	// Keys are the keys that trigger the binding, e.g. "ctrl+c"
+	Keys []string
+}
+
+var (
+	mu        sync.RWMutex
+	overrides = map[string][]string{}
+)
+
+// Get returns the binding registered under id, with the keys set by the
+// user if any. Unknown IDs return a disabled binding.
+func Get(id string) key.Binding {
+	b, ok := Lookup(id)
+	if !ok {
+		return key.NewBinding(key.WithDisabled())
+	}
+
+	return key.NewBinding(
+		key.WithKeys(b.Keys...),
+		key.WithHelp(FormatKey(b.Keys[0]), b.Description),
+	)
+}
+
+// Lookup returns the binding registered under id, with the keys set by the
+// user if any.
+func Lookup(id string) (Binding, bool) {
+	i := slices.IndexFunc(defaults, func(b Binding) bool { return b.ID == id })
+	if i < 0 {
+		return Binding{}, false
+	}
+
+	b := defaults[i]
+	mu.RLock()
+	if keys, ok := overrides[id]; ok {
+		b.Keys = keys
+	}
+	mu.RUnlock()
+	b.Keys = slices.Clone(b.Keys)
+	return b, true
+}
+
+// All returns every binding, with the keys set by the user, in the order
+// they are listed in the help overlay.
+func All() []Binding {
+	bindings := make([]Binding, 0, len(defaults))
+	for _, b := range defaults {
+		b, _ = Lookup(b.ID)
+		bindings = append(bindings, b)
+	}
+	return bindings
+}
+
+// SetOverrides replaces the keys of the given bindings. Overrides with an
+// unknown ID or without keys are ignored and reported in the returned error,
+// the others are applied.
+func SetOverrides(keysByID map[string][]string) error {
+	valid := map[string][]string{}
+	var invalid []string
+	for id, keys := range keysByID {
+		keys = slices.DeleteFunc(slices.Clone(keys), func(k string) bool { return strings.TrimSpace(k) == "" })
+		switch {
+		case !slices.ContainsFunc(defaults, func(b Binding) bool { return b.ID == id }):
+			invalid = append(invalid, fmt.Sprintf("unknown key binding %q", id))
+		case len(keys) == 0:
+			invalid = append(invalid, fmt.Sprintf("no keys for key binding %q", id))
+		default:
+			valid[id] = keys
+		}
+	}
+
+	mu.Lock()
+	overrides = valid
+	mu.Unlock()
+
+	if len(invalid) > 0 {
+		sort.Strings(invalid)
+		return fmt.Errorf("invalid keybindings: %s", strings.Join(invalid, ", "))
+	}
+	return nil
+}
+
+// FormatKey returns how a key is shown in the help, e.g. "Ctrl+k" for "ctrl+k".
+func FormatKey(k string) string {
+	switch k {
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	case "pgup":
+		return "PgUp"
+	case "pgdown":
+		return "PgDn"
+	}
+
+	parts := strings.Split(k, "+")
+	for i, part := range parts {
+		if len(part) > 1 {
+			parts[i] = strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+	return strings.Join(parts, "+")
+}
@@ -0,0 +1,90 @@
+package keys
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The overrides are global: tests that set them don't run in parallel.
+func setOverrides(t *testing.T, keysByID map[string][]string) error {
+	t.Helper()
+	t.Cleanup(func() { _ = SetOverrides(nil) })
+	return SetOverrides(keysByID)
+}
+
+func TestGetDefaults(t *testing.T) {
+	b := Get(CommandPalette)
+
+	assert.Equal(t, []string{"ctrl+k", "ctrl+p"}, b.Keys())
+	assert.Equal(t, "Ctrl+k", b.Help().Key)
+	assert.Equal(t, "commands", b.Help().Desc)
+	assert.True(t, b.Enabled())
+}
+
+func TestGetUnknown(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, Get("app.unknown").Enabled())
+}
+
+func TestSetOverrides(t *testing.T) {
+	require.NoError(t, setOverrides(t, map[string][]string{
+		CommandPalette: {"ctrl+o"},
+		SwitchFocus:    {"tab", "ctrl+w"},
+	}))
+
+	palette := Get(CommandPalette)
+	assert.Equal(t, []string{"ctrl+o"}, palette.Keys())
+	assert.Equal(t, "Ctrl+o", palette.Help().Key)
+	assert.Equal(t, []string{"tab", "ctrl+w"}, Get(SwitchFocus).Keys())
+	assert.Equal(t, []string{"ctrl+c"}, Get(Quit).Keys(), "other bindings keep their default keys")
+
+	require.NoError(t, SetOverrides(nil))
+	assert.Equal(t, []string{"ctrl+k", "ctrl+p"}, Get(CommandPalette).Keys())
+}
+
+func TestSetOverridesInvalid(t *testing.T) {
+	err := setOverrides(t, map[string][]string{
+		"app.unknown": {"ctrl+u"},
+		Quit:          {""},
+		Speak:         {"ctrl+e"},
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown key binding "app.unknown"`)
+	assert.Contains(t, err.Error(), `no keys for key binding "app.quit"`)
+	assert.Equal(t, []string{"ctrl+c"}, Get(Quit).Keys())
+	assert.Equal(t, []string{"ctrl+e"}, Get(Speak).Keys(), "valid overrides are applied")
+}
+
+func TestAll(t *testing.T) {
+	require.NoError(t, setOverrides(t, map[string][]string{Search: {"ctrl+f"}}))
+
+	all := All()
+	require.Len(t, all, len(defaults))
+	assert.Equal(t, Quit, all[0].ID)
+
+	seen := map[string]bool{}
+	for _, b := range all {
+		assert.False(t, seen[b.ID], "duplicate binding %s", b.ID)
+		seen[b.ID] = true
+		assert.NotEmpty(t, b.Group)
+		assert.NotEmpty(t, b.Keys)
+		if b.ID == Search {
+			assert.Equal(t, []string{"ctrl+f"}, b.Keys)
+		}
+	}
+}
+
+func TestFormatKey(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "Ctrl+k", FormatKey("ctrl+k"))
+	assert.Equal(t, "Shift+Enter", FormatKey("shift+enter"))
+	assert.Equal(t, "Tab", FormatKey("tab"))
+	assert.Equal(t, "↑", FormatKey("up"))
+	assert.Equal(t, "C", FormatKey("C"))
+	assert.Equal(t, "?", FormatKey("?"))
+}
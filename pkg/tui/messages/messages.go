@@ -2,29 +2,45 @@ package messages
 
 // Session command messages
 type (
-	NewSessionMsg                  struct{}
-	ExitSessionMsg                 struct{}
-	EvalSessionMsg                 struct{ Filename string }
-	CompactSessionMsg              struct{ AdditionalPrompt string }
-	CopySessionToClipboardMsg      struct{}
-	CopyLastResponseToClipboardMsg struct{}
-	ExportSessionMsg               struct{ Filename string }
-	ShowCostDialogMsg              struct{}
-	ToggleYoloMsg                  struct{}
-	ToggleHideToolResultsMsg       struct{}
-	StartShellMsg                  struct{}
-	SwitchAgentMsg                 struct{ AgentName string }
-	OpenSessionBrowserMsg          struct{}
-	LoadSessionMsg                 struct{ SessionID string }
-	ToggleSessionStarMsg           struct{ SessionID string } // Toggle star on a session; empty ID means current session
-	AttachFileMsg                  struct{ FilePath string }  // Attach a file directly or open file picker if empty/directory
-	InsertFileRefMsg               struct{ FilePath string }  // Insert @filepath reference into editor
-	OpenModelPickerMsg             struct{}                   // Open the model picker dialog
-	ChangeModelMsg                 struct{ ModelRef string }  // Change the model for the current agent
-	StartSpeakMsg                  struct{}                   // Start speech-to-text transcription
-	StopSpeakMsg                   struct{}                   // Stop speech-to-text transcription
-	SpeakTranscriptMsg             struct{ Delta string }     // Transcription delta from speech-to-text
-	ClearQueueMsg                  struct{}                   // Clear all queued messages
+	NewSessionMsg                   struct{}
+	ExitSessionMsg                  struct{}
+	EvalSessionMsg                  struct{ Filename string }
+	CompactSessionMsg               struct{ AdditionalPrompt string }
+	PostMortemMsg                   struct{} // Write the post-mortem of the last unsuccessful run
+	CopySessionToClipboardMsg       struct{}
+	CopyLastResponseToClipboardMsg  struct{}
+	CopyLastCodeBlockToClipboardMsg struct{}
+	ExportSessionMsg                struct{ Filename string }
+	ShowCostDialogMsg               struct{}
+	ShowHelpMsg                     struct{} // Show what the team of agents can do
+	ReloadToolsMsg                  struct{} // Reload the tools of the agents from their configuration
+	ToggleYoloMsg                   struct{}
+	ToggleHideToolResultsMsg        struct{}
+	ToggleRawMarkdownMsg            struct{}               // Toggle between rendered and raw markdown for assistant messages
+	ToggleExpandToolCallsMsg        struct{}               // Expand or collapse every finished tool call
+	ToggleHideSubAgentsMsg          struct{}               // Hide or show what the sub-agents say and do
+	ToggleSidebarMsg                struct{}               // Show or hide the sidebar
+	ToggleSplitViewMsg              struct{}               // Show or hide the sub-agent panes
+	ToggleLogsMsg                   struct{ Level string } // Show or hide the log pane; a level shows it, filtered
+	StartShellMsg                   struct{}
+	SwitchAgentMsg                  struct{ AgentName string }
+	OpenSessionBrowserMsg           struct{}
+	LoadSessionMsg                  struct{ SessionID string }
+	ResumeSessionMsg                struct{ SessionID string } // Resume a past session; empty ID means the most recent one
+	ToggleSessionStarMsg            struct{ SessionID string } // Toggle star on a session; empty ID means current session
+	ShowRewindMsg                   struct{ SessionID string } // Pick the checkpoint to rewind a session to; empty ID means current session
+	RenameSessionMsg                struct{ SessionID, Title string }
+	DeleteSessionMsg                struct{ SessionID string }
+	AttachFileMsg                   struct{ FilePath string } // Attach a file directly or open file picker if empty/directory
+	InsertFileRefMsg                struct{ FilePath string } // Insert @filepath reference into editor
+	OpenModelPickerMsg              struct{}                  // Open the model picker dialog
+	ChangeModelMsg                  struct{ ModelRef string } // Change the model for the current agent
+	StartSpeakMsg                   struct{}                  // Start speech-to-text transcription
+	StopSpeakMsg                    struct{}                  // Stop speech-to-text transcription
+	SpeakTranscriptMsg              struct{ Delta string }    // Transcription delta from speech-to-text
+	ClearQueueMsg                   struct{}                  // Clear all queued messages
+	ChangeThemeMsg                  struct{ Name string }     // Switch the color theme; empty name means next theme
+	ThemeChangedMsg                 struct{}                  // The color theme changed, cached renders must be dropped
 )
 
 // AgentCommandMsg command message
@@ -47,3 +63,26 @@ type ShowMCPPromptInputMsg struct {
 	PromptName string
 	PromptInfo any // mcptools.PromptInfo but avoiding import cycles
 }
+
+// ShowFeedbackInputMsg opens the dialog collecting the comment of the user
+// on an answer they rated
+type ShowFeedbackInputMsg struct {
+	AgentName string
+	Answer    string
+	Rating    string // session.FeedbackPositive or session.FeedbackNegative
+}
+
+// RewindSessionMsg forks a session at one of its checkpoints, and loads the
+// fork
+type RewindSessionMsg struct {
+	SessionID  string
+	Checkpoint int
+}
+
+// FeedbackMsg records the feedback of the user on an answer
+type FeedbackMsg struct {
+	AgentName string
+	Answer    string
+	Rating    string
+	Comment   string
+}
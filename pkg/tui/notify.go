@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/docker/cagent/pkg/notifications"
+	"github.com/docker/cagent/pkg/runtime"
+)
+
+// notifyRuntimeEvent notifies the user, while the terminal isn't focused,
+// that a run longer than the configured minimum completed or that a tool
+// call waits for their approval.
+func (a *appModel) notifyRuntimeEvent(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case *runtime.StreamStartedEvent:
+		// Streams of sub-agents are nested in the stream of the run
+		if a.runDepth == 0 {
+			a.runStarted = time.Now()
+		}
+		a.runDepth++
+
+	case *runtime.StreamStoppedEvent:
+		a.runDepth = max(0, a.runDepth-1)
+		if a.runDepth == 0 && time.Since(a.runStarted) >= a.notifier.MinDuration() {
+			return a.notify(notifications.RunCompleted, fmt.Sprintf("%s is done", msg.AgentName))
+		}
+
+	case *runtime.ToolCallConfirmationEvent:
+		return a.notify(notifications.ApprovalRequired, fmt.Sprintf("%s needs approval to run %s", msg.AgentName, msg.ToolCall.Function.Name))
+	}
+	return nil
+}
+
+func (a *appModel) notify(event notifications.Event, body string) tea.Cmd {
+	if a.focused {
+		return nil
+	}
+	title := a.windowTitle()
+	return func() tea.Msg {
+		if seq := a.notifier.Notify(event, title, body); seq != "" {
+			return tea.RawMsg{Msg: seq}
+		}
+		return nil
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/docker/cagent/pkg/history"
 	"github.com/docker/cagent/pkg/tui/commands"
 	"github.com/docker/cagent/pkg/tui/components/editor"
+	"github.com/docker/cagent/pkg/tui/components/logview"
 	"github.com/docker/cagent/pkg/tui/components/messages"
 	"github.com/docker/cagent/pkg/tui/components/notification"
 	"github.com/docker/cagent/pkg/tui/components/sidebar"
@@ -26,9 +27,11 @@ import (
 	"github.com/docker/cagent/pkg/tui/core"
 	"github.com/docker/cagent/pkg/tui/core/layout"
 	"github.com/docker/cagent/pkg/tui/dialog"
+	"github.com/docker/cagent/pkg/tui/keys"
 	msgtypes "github.com/docker/cagent/pkg/tui/messages"
 	"github.com/docker/cagent/pkg/tui/service"
 	"github.com/docker/cagent/pkg/tui/styles"
+	"github.com/docker/cagent/pkg/userconfig"
 )
 
 // FocusedPanel represents which panel is currently focused
@@ -56,11 +59,15 @@ type Page interface {
 	layout.Sizeable
 	layout.Help
 	CompactSession(additionalPrompt string) tea.Cmd
+	// WritePostMortem writes the post-mortem of the last unsuccessful run
+	WritePostMortem() tea.Cmd
 	Cleanup()
 	// GetInputHeight returns the current height of the editor/input area (including padding)
 	GetInputHeight() int
 	// SetSessionStarred updates the sidebar star indicator
 	SetSessionStarred(starred bool)
+	// SetFeedback shows the rating the user gave to an answer
+	SetFeedback(agentName, answer, rating string)
 	// InsertText inserts text at the current cursor position in the editor
 	InsertText(text string)
 	// SetRecording sets the recording mode on the editor
@@ -91,12 +98,17 @@ type chatPage struct {
 	sessionState *service.SessionState
 
 	// State
-	focusedPanel FocusedPanel
-	working      bool
+	focusedPanel  FocusedPanel
+	working       bool
+	sidebarHidden bool
 
 	msgCancel       context.CancelFunc
 	streamCancelled bool
 
+	// lastFailure is why the last run failed or was aborted, empty when it
+	// succeeded
+	lastFailure string
+
 	// Message queue for enqueuing messages while agent is working
 	messageQueue []queuedMessage
 
@@ -118,6 +130,22 @@ type chatPage struct {
 	isDragging       bool
 	isHoveringHandle bool
 	editorLines      int
+
+	// baselineAgent is the agent whose usage baseline was last loaded
+	baselineAgent string
+
+	// reviewingEdit is set while an edit_file diff in the chat pane is
+	// waiting to be accepted or rejected
+	reviewingEdit bool
+
+	// panes are the conversation and the transcripts of the sub-agents,
+	// shown side by side in the split view
+	panes     *layout.Panes
+	splitView bool
+
+	// logs is the pane showing cagent's logs below the transcript, nil when
+	// hidden
+	logs *logview.Model
 }
 
 // KeyMap defines key bindings for the chat page
@@ -127,7 +155,14 @@ type KeyMap struct {
 	ShiftNewline    key.Binding
 	CtrlJ           key.Binding
 	ExternalEditor  key.Binding
+	HistorySearch   key.Binding
 	ToggleSplitDiff key.Binding
+	AcceptEdit      key.Binding
+	RejectEdit      key.Binding
+	AcceptAllEdits  key.Binding
+	NextPane        key.Binding
+	PreviousPane    key.Binding
+	KeyBindings     key.Binding
 }
 
 // getEditorDisplayNameFromEnv returns a friendly display name for the configured editor.
@@ -198,47 +233,87 @@ func getEditorDisplayName() string {
 	return getEditorDisplayNameFromEnv(os.Getenv("VISUAL"), os.Getenv("EDITOR"))
 }
 
-// defaultKeyMap returns the default key bindings
+// defaultKeyMap returns the key bindings, with the keys set by the user
 func defaultKeyMap() KeyMap {
-	editorName := getEditorDisplayName()
+	externalEditor := keys.Get(keys.ExternalEditor)
+	externalEditor.SetHelp(externalEditor.Help().Key, fmt.Sprintf("edit in %s", getEditorDisplayName()))
 
 	return KeyMap{
-		Tab: key.NewBinding(
-			key.WithKeys("tab"),
-			key.WithHelp("TAB", "switch focus"),
-		),
-		Cancel: key.NewBinding(
-			key.WithKeys("esc"),
-		),
-		// Show newline help in footer. Terminals that support Shift+Enter will use it.
-		// Ctrl+J acts as a fallback on terminals that don't distinguish Shift+Enter.
-		ShiftNewline: key.NewBinding(
-			key.WithKeys("shift+enter", "ctrl+j"),
-			key.WithHelp("Shift+Enter / Ctrl+j", "newline"),
-		),
-		ExternalEditor: key.NewBinding(
-			key.WithKeys("ctrl+g"),
-			key.WithHelp("Ctrl+g", fmt.Sprintf("edit in %s", editorName)),
-		),
-		ToggleSplitDiff: key.NewBinding(
-			key.WithKeys("ctrl+t"),
-			key.WithHelp("Ctrl+t", "toggle split diff mode"),
-		),
+		Tab:             keys.Get(keys.SwitchFocus),
+		Cancel:          keys.Get(keys.Cancel),
+		ShiftNewline:    keys.Get(keys.Newline),
+		ExternalEditor:  externalEditor,
+		HistorySearch:   keys.Get(keys.HistorySearch),
+		ToggleSplitDiff: keys.Get(keys.ToggleSplitDiff),
+		AcceptEdit:      keys.Get(keys.AcceptEdit),
+		RejectEdit:      keys.Get(keys.RejectEdit),
+		AcceptAllEdits:  keys.Get(keys.AcceptAllEdits),
+		NextPane:        keys.Get(keys.NextPane),
+		PreviousPane:    keys.Get(keys.PreviousPane),
+		KeyBindings:     keys.Get(keys.KeyBindings),
 	}
 }
 
+func init() {
+	commands.Register(commands.Item{
+		ID:           "view.sidebar",
+		Label:        "Sidebar",
+		SlashCommand: "/sidebar",
+		Description:  "Show or hide the sidebar",
+		Category:     "View",
+		Execute: func(string) tea.Cmd {
+			return core.CmdHandler(msgtypes.ToggleSidebarMsg{})
+		},
+	}, commands.Item{
+		ID:           "view.split",
+		Label:        "Split View",
+		SlashCommand: "/split",
+		Description:  "Show the sub-agents' transcripts in panes",
+		Category:     "View",
+		Execute: func(string) tea.Cmd {
+			return core.CmdHandler(msgtypes.ToggleSplitViewMsg{})
+		},
+	}, commands.Item{
+		ID:           "view.logs",
+		Label:        "Logs",
+		SlashCommand: "/logs",
+		Description:  "Show or hide cagent's logs, at a level: /logs [debug|info|warn|error]",
+		Category:     "View",
+		Execute: func(arg string) tea.Cmd {
+			return core.CmdHandler(msgtypes.ToggleLogsMsg{Level: strings.TrimSpace(arg)})
+		},
+		Arguments: func(*app.App) []string {
+			return []string{"debug", "info", "warn", "error"}
+		},
+	}, commands.Item{
+		ID:           "view.keys",
+		Label:        "Key Bindings",
+		SlashCommand: "/keys",
+		Description:  "Show the key bindings",
+		Category:     "View",
+		Execute: func(string) tea.Cmd {
+			return core.CmdHandler(dialog.OpenDialogMsg{Model: dialog.NewKeyBindingsDialog()})
+		},
+	})
+}
+
 // New creates a new chat page
-func New(a *app.App, sessionState *service.SessionState) Page {
-	historyStore, err := history.New()
+func New(a *app.App, sessionState *service.SessionState, sidebarOpts ...sidebar.Option) Page {
+	var historyOpts []history.Opt
+	if cfg, err := userconfig.Load(); err == nil {
+		historyOpts = append(historyOpts, history.WithMaxSize(cfg.HistorySize))
+		sessionState.VimMode = cfg.VimMode
+	}
+	historyStore, err := history.New(historyOpts...)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed to initialize command history: %v\n", err)
 	}
 
 	p := &chatPage{
-		sidebar:      sidebar.New(sessionState),
+		sidebar:      sidebar.New(sessionState, append([]sidebar.Option{sidebar.WithUsageTracker(a.Usage())}, sidebarOpts...)...),
 		messages:     messages.New(a, sessionState),
 		editor:       editor.New(a, historyStore),
-		spinner:      spinner.New(spinner.ModeSpinnerOnly, styles.SpinnerDotsHighlightStyle),
+		spinner:      spinner.New(spinner.ModeSpinnerOnly, &styles.SpinnerDotsHighlightStyle),
 		focusedPanel: PanelEditor,
 		app:          a,
 		keyMap:       defaultKeyMap(),
@@ -249,6 +324,9 @@ func New(a *app.App, sessionState *service.SessionState) Page {
 		editorLines:                   3,
 	}
 
+	p.panes = newPanes(p.messages)
+	p.editor.SetVimMode(sessionState.VimMode)
+
 	// Initialize help text with default (ctrl+j)
 	p.updateNewlineHelp()
 
@@ -277,6 +355,8 @@ func (p *chatPage) Init() tea.Cmd {
 
 // Update handles messages and updates the page state
 func (p *chatPage) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
+	var widgetsCmd tea.Cmd
+
 	switch msg := msg.(type) {
 	case tea.KeyboardEnhancementsMsg:
 		// Track keyboard enhancement support and update help text
@@ -287,6 +367,10 @@ func (p *chatPage) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
 		p.editor = editorModel.(editor.Editor)
 		return p, editorCmd
 
+	case usageBaselineMsg:
+		p.sidebar.SetUsageBaseline(msg.baseline)
+		return p, nil
+
 	case tea.WindowSizeMsg:
 		cmdSize := p.SetSize(msg.Width, msg.Height)
 
@@ -325,11 +409,8 @@ func (p *chatPage) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
 		return p.handleSendMsg(msg)
 
 	case messages.StreamCancelledMsg:
-		model, cmd := p.messages.Update(msg)
-		p.messages = model.(messages.Model)
-
 		var cmds []tea.Cmd
-		cmds = append(cmds, cmd)
+		cmds = append(cmds, p.updateTranscripts(msg))
 
 		if msg.ShowMessage {
 			cmds = append(cmds, p.messages.AddCancelledMessage())
@@ -349,26 +430,53 @@ func (p *chatPage) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
 		return p, nil
 
 	case msgtypes.ToggleHideToolResultsMsg:
-		// Forward to messages component to invalidate cache and trigger redraw
-		model, cmd := p.messages.Update(messages.ToggleHideToolResultsMsg{})
-		p.messages = model.(messages.Model)
+		// Forward to messages components to invalidate cache and trigger redraw
+		return p, p.updateTranscripts(messages.ToggleHideToolResultsMsg{})
+
+	case msgtypes.ToggleRawMarkdownMsg:
+		return p, p.updateTranscripts(messages.ToggleRawMarkdownMsg{})
+
+	case msgtypes.ToggleExpandToolCallsMsg:
+		return p, p.updateTranscripts(messages.ToggleExpandToolCallsMsg{})
+
+	case msgtypes.ToggleHideSubAgentsMsg:
+		// Only the conversation nests the sub-agents, their panes show them whole
+		_, cmd := p.messages.Update(messages.ToggleHideSubAgentsMsg{})
 		return p, cmd
 
 	case msgtypes.ClearQueueMsg:
 		return p.handleClearQueue()
 
+	case msgtypes.ToggleSidebarMsg:
+		p.sidebarHidden = !p.sidebarHidden
+		return p, p.SetSize(p.width, p.height)
+
+	case msgtypes.ToggleSplitViewMsg:
+		return p, p.toggleSplitView()
+
+	case msgtypes.ToggleLogsMsg:
+		return p, p.toggleLogs(msg.Level)
+
+	case logview.TickMsg:
+		if p.logs == nil {
+			return p, nil
+		}
+		return p, p.logs.Update(msg)
+
 	default:
+		// Custom sidebar widgets see every message, including runtime events
+		widgetsCmd = p.sidebar.UpdateWidgets(msg)
+
 		// Try to handle as a runtime event
 		if handled, cmd := p.handleRuntimeEvent(msg); handled {
-			return p, cmd
+			return p, tea.Batch(cmd, widgetsCmd)
 		}
 	}
 
 	sidebarModel, sidebarCmd := p.sidebar.Update(msg)
 	p.sidebar = sidebarModel.(sidebar.Model)
 
-	chatModel, chatCmd := p.messages.Update(msg)
-	p.messages = chatModel.(messages.Model)
+	chatCmd := p.updateTranscripts(msg)
 
 	editorModel, editorCmd := p.editor.Update(msg)
 	p.editor = editorModel.(editor.Editor)
@@ -380,7 +488,7 @@ func (p *chatPage) Update(msg tea.Msg) (layout.Model, tea.Cmd) {
 		p.spinner = model.(spinner.Spinner)
 	}
 
-	return p, tea.Batch(sidebarCmd, chatCmd, editorCmd, cmdSpinner)
+	return p, tea.Batch(sidebarCmd, chatCmd, editorCmd, cmdSpinner, widgetsCmd)
 }
 
 func (p *chatPage) setWorking(working bool) tea.Cmd {
@@ -403,14 +511,20 @@ func (p *chatPage) View() string {
 
 	var bodyContent string
 
-	if p.width >= minWindowWidth {
+	switch {
+	case p.sidebarHidden:
+		bodyContent = styles.ChatStyle.
+			Height(p.chatHeight).
+			Width(innerWidth).
+			Render(p.chatView())
+	case p.width >= minWindowWidth:
 		// Ensure we don't exceed available space
 		chatWidth := max(1, innerWidth-sidebarWidth)
 
 		chatView := styles.ChatStyle.
 			Height(p.chatHeight).
 			Width(chatWidth).
-			Render(p.messages.View())
+			Render(p.chatView())
 
 		sidebarView := lipgloss.NewStyle().
 			Width(sidebarWidth).
@@ -423,13 +537,13 @@ func (p *chatPage) View() string {
 			chatView,
 			sidebarView,
 		)
-	} else {
+	default:
 		sidebarWidth, sidebarHeight := p.sidebar.GetSize()
 
 		chatView := styles.ChatStyle.
 			Height(p.chatHeight).
 			Width(innerWidth).
-			Render(p.messages.View())
+			Render(p.chatView())
 
 		sidebarView := lipgloss.NewStyle().
 			Width(sidebarWidth).
@@ -488,8 +602,13 @@ func (p *chatPage) SetSize(width, height int) tea.Cmd {
 	// Emit height change message so completion popup can adjust position
 	cmds = append(cmds, core.CmdHandler(EditorHeightChangedMsg{Height: actualEditorHeight}))
 
-	var mainWidth int
-	if width >= minWindowWidth {
+	const horizontalSidebarHeight = 3
+	var mainWidth, chatTop int
+	switch {
+	case p.sidebarHidden:
+		mainWidth = max(innerWidth, 1)
+		p.chatHeight = max(1, height-actualEditorHeight-2) // -1 for resize handle, -1 for empty line before status bar
+	case width >= minWindowWidth:
 		// Ensure we don't exceed available space after accounting for sidebar
 		mainWidth = max(1, innerWidth-sidebarWidth)
 		p.chatHeight = max(1, height-actualEditorHeight-2) // -1 for resize handle, -1 for empty line before status bar
@@ -497,24 +616,20 @@ func (p *chatPage) SetSize(width, height int) tea.Cmd {
 		cmds = append(cmds,
 			p.sidebar.SetSize(sidebarWidth, p.chatHeight),
 			p.sidebar.SetPosition(styles.AppPaddingLeft+mainWidth, 0),
-			p.messages.SetPosition(0, 0),
 		)
-	} else {
-		const horizontalSidebarHeight = 3
+	default:
 		mainWidth = max(innerWidth, 1)
 		p.chatHeight = max(1, height-actualEditorHeight-horizontalSidebarHeight-2) // -1 for resize handle, -1 for empty line before status bar
 		p.sidebar.SetMode(sidebar.ModeHorizontal)
 		cmds = append(cmds,
 			p.sidebar.SetSize(width, horizontalSidebarHeight),
 			p.sidebar.SetPosition(styles.AppPaddingLeft, 0),
-			p.messages.SetPosition(0, horizontalSidebarHeight),
 		)
+		chatTop = horizontalSidebarHeight
 	}
 
 	// Set component sizes
-	cmds = append(cmds,
-		p.messages.SetSize(mainWidth, p.chatHeight),
-	)
+	cmds = append(cmds, p.sizeTranscripts(mainWidth, chatTop))
 
 	return tea.Batch(cmds...)
 }
@@ -531,21 +646,35 @@ func (p *chatPage) GetInputHeight() int {
 
 // Bindings returns key bindings for the chat page
 func (p *chatPage) Bindings() []key.Binding {
-	bindings := []key.Binding{
-		p.keyMap.Tab,
-		p.keyMap.Cancel,
+	var bindings []key.Binding
+	if p.sessionState.VimMode && !p.reviewingEdit {
+		bindings = p.vimBindings()
+	}
+	bindings = append(bindings, p.keyMap.Tab, p.keyMap.Cancel)
+
+	if p.reviewingEdit && p.focusedPanel == PanelChat {
+		return append(bindings,
+			p.keyMap.AcceptEdit,
+			p.keyMap.RejectEdit,
+			p.keyMap.AcceptAllEdits,
+			p.keyMap.ToggleSplitDiff,
+		)
 	}
 
 	if p.focusedPanel == PanelChat {
-		bindings = append(bindings, p.messages.Bindings()...)
+		if p.splitView && p.panes.Len() > 1 {
+			bindings = append(bindings, p.keyMap.NextPane, p.keyMap.PreviousPane)
+		}
+		bindings = append(bindings, p.transcript().Bindings()...)
 	} else {
 		bindings = append(bindings,
 			p.keyMap.ShiftNewline,
 			p.keyMap.ExternalEditor,
+			p.keyMap.HistorySearch,
 		)
 	}
 
-	return bindings
+	return append(bindings, p.keyMap.KeyBindings)
 }
 
 // Help returns help information
@@ -555,7 +684,7 @@ func (p *chatPage) Help() help.KeyMap {
 
 // switchFocus cycles between the focusable panels
 func (p *chatPage) switchFocus() {
-	p.messages.Blur()
+	p.transcript().Blur()
 	p.editor.Blur()
 
 	// Move to next panel
@@ -565,24 +694,20 @@ func (p *chatPage) switchFocus() {
 		p.editor.Focus()
 	case PanelEditor:
 		p.focusedPanel = PanelChat
-		p.messages.Focus()
+		p.transcript().Focus()
 	}
 }
 
 // updateNewlineHelp updates the help text for the newline shortcut
 // based on keyboard enhancement support.
 func (p *chatPage) updateNewlineHelp() {
-	if p.keyboardEnhancementsSupported {
-		p.keyMap.ShiftNewline = key.NewBinding(
-			key.WithKeys("shift+enter", "ctrl+j"),
-			key.WithHelp("Shift+Enter", "newline"),
-		)
-	} else {
-		p.keyMap.ShiftNewline = key.NewBinding(
-			key.WithKeys("ctrl+j"),
-			key.WithHelp("ctrl+j", "newline"),
-		)
+	newline := keys.Get(keys.Newline)
+	newlineKeys := editor.NewlineKeys(p.keyboardEnhancementsSupported)
+	newline.SetKeys(newlineKeys...)
+	if len(newlineKeys) > 0 {
+		newline.SetHelp(keys.FormatKey(newlineKeys[0]), newline.Help().Desc)
 	}
+	p.keyMap.ShiftNewline = newline
 }
 
 // cancelStream cancels the current stream and cleans up associated state
@@ -594,12 +719,19 @@ func (p *chatPage) cancelStream(showCancelMessage bool) tea.Cmd {
 	p.msgCancel()
 	p.msgCancel = nil
 	p.streamCancelled = true
+	p.reviewingEdit = false
 	p.stopProgressBar()
 
+	var offerCmd tea.Cmd
+	if showCancelMessage {
+		offerCmd = p.offerPostMortem("it was cancelled by the user")
+	}
+
 	// Send StreamCancelledMsg to all components to handle cleanup
 	return tea.Batch(
 		core.CmdHandler(messages.StreamCancelledMsg{ShowMessage: showCancelMessage}),
 		p.setWorking(false),
+		offerCmd,
 	)
 }
 
@@ -691,6 +823,7 @@ func (p *chatPage) processMessage(msg editor.SendMsg) tea.Cmd {
 	}
 
 	_ = p.history.Add(msg.Content)
+	p.lastFailure = ""
 
 	var ctx context.Context
 	ctx, p.msgCancel = context.WithCancel(context.Background())
@@ -705,6 +838,11 @@ func (p *chatPage) processMessage(msg editor.SendMsg) tea.Cmd {
 		return cmd
 	}
 
+	// Handle the prompts of the MCP servers (e.g., /review pr=123)
+	if cmd := commands.ParseMCPPrompt(ctx, p.app, msg.Content); cmd != nil {
+		return cmd
+	}
+
 	// Start working state immediately to show the user something is happening.
 	// This provides visual feedback while the runtime loads tools and prepares the stream.
 	// The spinner will be visible in the resize handle area.
@@ -745,6 +883,26 @@ func (p *chatPage) CompactSession(additionalPrompt string) tea.Cmd {
 	return p.messages.ScrollToBottom()
 }
 
+// offerPostMortem remembers why the last run failed and offers to analyze it.
+func (p *chatPage) offerPostMortem(failure string) tea.Cmd {
+	p.lastFailure = failure
+	return notification.InfoCmd("The run didn't succeed. Type /postmortem to analyze it for the next attempt.")
+}
+
+// WritePostMortem writes the post-mortem of the last run, saved with the
+// session so that the next attempt can learn from it.
+func (p *chatPage) WritePostMortem() tea.Cmd {
+	failure := p.lastFailure
+	if failure == "" {
+		failure = "the user judged it unsuccessful"
+	}
+
+	if !p.app.WritePostMortem(failure) {
+		return notification.WarningCmd("Post-mortems aren't supported by this runtime.")
+	}
+	return notification.InfoCmd("Writing the post-mortem...")
+}
+
 func (p *chatPage) Cleanup() {
 	p.stopProgressBar()
 	p.editor.Cleanup()
@@ -755,9 +913,23 @@ func (p *chatPage) SetSessionStarred(starred bool) {
 	p.sidebar.SetSessionStarred(starred)
 }
 
+// SetFeedback shows the rating the user gave to an answer
+func (p *chatPage) SetFeedback(agentName, answer, rating string) {
+	p.messages.SetFeedback(agentName, answer, rating)
+}
+
+// verticalSidebar reports whether the sidebar is shown on the right of the chat.
+func (p *chatPage) verticalSidebar() bool {
+	return !p.sidebarHidden && p.width >= minWindowWidth
+}
+
 // handleSidebarClick checks if a click in the sidebar area should toggle the star
 // Returns true if the click was handled (star was toggled)
 func (p *chatPage) handleSidebarClick(x, y int) bool {
+	if p.sidebarHidden {
+		return false
+	}
+
 	// Account for AppStyle padding (left padding = 1)
 	adjustedX := x - styles.AppPaddingLeft
 
@@ -785,20 +957,22 @@ func (p *chatPage) handleSidebarClick(x, y int) bool {
 func (p *chatPage) routeMouseEvent(msg tea.Msg, y int) tea.Cmd {
 	editorTop := p.height - p.inputHeight
 	if y < editorTop {
-		// Check if event is in sidebar area (vertical mode only)
-		if p.width >= minWindowWidth {
-			// Get x coordinate from the message
-			var x int
-			switch m := msg.(type) {
-			case tea.MouseClickMsg:
-				x = m.X
-			case tea.MouseMotionMsg:
-				x = m.X
-			case tea.MouseReleaseMsg:
-				x = m.X
-			}
+		// Get x coordinate from the message
+		var x int
+		switch m := msg.(type) {
+		case tea.MouseClickMsg:
+			x = m.X
+		case tea.MouseMotionMsg:
+			x = m.X
+		case tea.MouseReleaseMsg:
+			x = m.X
+		case tea.MouseWheelMsg:
+			x = m.X
+		}
+		adjustedX := x - styles.AppPaddingLeft
 
-			adjustedX := x - styles.AppPaddingLeft
+		// Check if event is in sidebar area (vertical mode only)
+		if p.verticalSidebar() {
 			innerWidth := p.width - 2
 			chatWidth := max(1, innerWidth-sidebarWidth)
 
@@ -810,8 +984,15 @@ func (p *chatPage) routeMouseEvent(msg tea.Msg, y int) tea.Cmd {
 			}
 		}
 
-		model, cmd := p.messages.Update(msg)
-		p.messages = model.(messages.Model)
+		// In the split view, clicking a pane focuses it
+		id, transcript := p.transcriptAt(adjustedX)
+		if _, ok := msg.(tea.MouseClickMsg); ok && p.splitView {
+			if pane, _ := p.panes.Focused(); pane.ID != id {
+				p.focusPane(func() { p.panes.Focus(id) })
+			}
+		}
+
+		_, cmd := transcript.Update(msg)
 		return cmd
 	}
 
@@ -0,0 +1,72 @@
+package chat
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/runtime"
+	"github.com/docker/cagent/pkg/tui/dialog"
+)
+
+func TestEditReviewKeys(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		key      rune
+		expected runtime.ResumeType
+		yolo     bool
+	}{
+		{key: 'y', expected: runtime.ResumeTypeApprove},
+		{key: 'n', expected: runtime.ResumeTypeReject},
+		{key: 'a', expected: runtime.ResumeTypeApproveSession, yolo: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.key), func(t *testing.T) {
+			t.Parallel()
+
+			p := newTestChatPage(t)
+			p.keyMap = defaultKeyMap()
+			p.focusedPanel = PanelChat
+			p.reviewingEdit = true
+
+			cmd, handled := p.handleEditReviewKey(tea.KeyPressMsg{Code: tt.key, Text: string(tt.key)})
+			require.True(t, handled)
+			require.NotNil(t, cmd)
+			assert.Equal(t, dialog.RuntimeResumeMsg{Response: tt.expected}, cmd())
+			assert.False(t, p.reviewingEdit)
+			assert.Equal(t, tt.yolo, p.sessionState.YoloMode)
+		})
+	}
+}
+
+func TestEditReviewIgnoresOtherKeys(t *testing.T) {
+	t.Parallel()
+
+	p := newTestChatPage(t)
+	p.keyMap = defaultKeyMap()
+	p.focusedPanel = PanelChat
+	p.reviewingEdit = true
+
+	cmd, handled := p.handleEditReviewKey(tea.KeyPressMsg{Code: 'x', Text: "x"})
+	assert.False(t, handled)
+	assert.Nil(t, cmd)
+	assert.True(t, p.reviewingEdit)
+}
+
+func TestEditReviewKeysGoToTheFocusedEditor(t *testing.T) {
+	t.Parallel()
+
+	p := newTestChatPage(t)
+	p.keyMap = defaultKeyMap()
+	p.focusedPanel = PanelEditor
+	p.reviewingEdit = true
+
+	cmd, handled := p.handleEditReviewKey(tea.KeyPressMsg{Code: 'y', Text: "y"})
+	assert.False(t, handled)
+	assert.Nil(t, cmd)
+	assert.True(t, p.reviewingEdit)
+}
@@ -4,12 +4,13 @@ import (
 	"charm.land/bubbles/v2/key"
 	tea "charm.land/bubbletea/v2"
 
+	"github.com/docker/cagent/pkg/runtime"
 	"github.com/docker/cagent/pkg/tui/components/editor"
-	"github.com/docker/cagent/pkg/tui/components/messages"
 	"github.com/docker/cagent/pkg/tui/components/sidebar"
 	"github.com/docker/cagent/pkg/tui/components/tool/editfile"
 	"github.com/docker/cagent/pkg/tui/core"
 	"github.com/docker/cagent/pkg/tui/core/layout"
+	"github.com/docker/cagent/pkg/tui/dialog"
 	msgtypes "github.com/docker/cagent/pkg/tui/messages"
 	"github.com/docker/cagent/pkg/tui/styles"
 )
@@ -17,6 +18,29 @@ import (
 // handleKeyPress handles keyboard input events for the chat page.
 // Returns the updated model and command, plus a bool indicating if the event was handled.
 func (p *chatPage) handleKeyPress(msg tea.KeyPressMsg) (layout.Model, tea.Cmd, bool) {
+	if p.reviewingEdit {
+		if cmd, handled := p.handleEditReviewKey(msg); handled {
+			return p, cmd, true
+		}
+	}
+
+	// While searching the transcript, keys (including Esc) go to the search bar
+	if p.focusedPanel == PanelChat && p.transcript().IsSearching() && !key.Matches(msg, p.keyMap.Tab) {
+		_, cmd := p.transcript().Update(msg)
+		return p, cmd, true
+	}
+
+	// While searching the prompt history, keys (including Esc) go to the editor
+	if p.focusedPanel == PanelEditor && p.editor.IsSearchingHistory() {
+		model, cmd := p.editor.Update(msg)
+		p.editor = model.(editor.Editor)
+		return p, cmd, true
+	}
+
+	if cmd, handled := p.handleVimKey(msg); handled {
+		return p, cmd, true
+	}
+
 	switch {
 	case key.Matches(msg, p.keyMap.Tab):
 		if p.focusedPanel == PanelEditor {
@@ -36,16 +60,26 @@ func (p *chatPage) handleKeyPress(msg tea.KeyPressMsg) (layout.Model, tea.Cmd, b
 		return p, cmd, true
 
 	case key.Matches(msg, p.keyMap.ToggleSplitDiff):
-		model, cmd := p.messages.Update(editfile.ToggleDiffViewMsg{})
-		p.messages = model.(messages.Model)
-		return p, cmd, true
+		return p, p.updateTranscripts(editfile.ToggleDiffViewMsg{}), true
+
+	// The keys may be printable: only while the transcript is focused
+	case key.Matches(msg, p.keyMap.NextPane) && p.focusedPanel == PanelChat && p.splitView:
+		p.focusPane(p.panes.FocusNext)
+		return p, nil, true
+
+	case key.Matches(msg, p.keyMap.PreviousPane) && p.focusedPanel == PanelChat && p.splitView:
+		p.focusPane(p.panes.FocusPrevious)
+		return p, nil, true
+
+	// The key may be printable: only while it can't be meant for the prompt
+	case key.Matches(msg, p.keyMap.KeyBindings) && (p.focusedPanel == PanelChat || p.editor.Value() == ""):
+		return p, core.CmdHandler(dialog.OpenDialogMsg{Model: dialog.NewKeyBindingsDialog()}), true
 	}
 
 	// Route other keys to focused component
 	switch p.focusedPanel {
 	case PanelChat:
-		model, cmd := p.messages.Update(msg)
-		p.messages = model.(messages.Model)
+		_, cmd := p.transcript().Update(msg)
 		return p, cmd, true
 	case PanelEditor:
 		model, cmd := p.editor.Update(msg)
@@ -99,7 +133,7 @@ func (p *chatPage) handleMouseRelease(msg tea.MouseReleaseMsg) (layout.Model, te
 // handleMouseWheel handles mouse wheel events.
 func (p *chatPage) handleMouseWheel(msg tea.MouseWheelMsg) (layout.Model, tea.Cmd) {
 	// Check if mouse is over the sidebar in vertical mode
-	if p.width >= minWindowWidth {
+	if p.verticalSidebar() {
 		adjustedX := msg.X - styles.AppPaddingLeft
 		innerWidth := p.width - 2
 		chatWidth := max(1, innerWidth-sidebarWidth)
@@ -112,3 +146,28 @@ func (p *chatPage) handleMouseWheel(msg tea.MouseWheelMsg) (layout.Model, tea.Cm
 	cmd := p.routeMouseEvent(msg, msg.Y)
 	return p, cmd
 }
+
+// handleEditReviewKey answers a pending edit_file confirmation shown inline in
+// the chat pane. The review keys are only handled while the chat pane has the
+// focus, so that they can still be typed in the editor. Other keys fall through.
+func (p *chatPage) handleEditReviewKey(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	if p.focusedPanel != PanelChat {
+		return nil, false
+	}
+
+	var response runtime.ResumeType
+	switch {
+	case key.Matches(msg, p.keyMap.AcceptEdit):
+		response = runtime.ResumeTypeApprove
+	case key.Matches(msg, p.keyMap.RejectEdit):
+		response = runtime.ResumeTypeReject
+	case key.Matches(msg, p.keyMap.AcceptAllEdits):
+		p.sessionState.SetYoloMode(true)
+		response = runtime.ResumeTypeApproveSession
+	default:
+		return nil, false
+	}
+
+	p.reviewingEdit = false
+	return core.CmdHandler(dialog.RuntimeResumeMsg{Response: response}), true
+}
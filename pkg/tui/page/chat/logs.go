@@ -0,0 +1,52 @@
+package chat
+
+import (
+	"log/slog"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/docker/cagent/pkg/logging"
+	"github.com/docker/cagent/pkg/tui/components/logview"
+	"github.com/docker/cagent/pkg/tui/components/notification"
+)
+
+// maxLogsHeight is the height of the log pane, unless the chat area is small.
+const maxLogsHeight = 12
+
+// toggleLogs shows or hides the log pane. With a level, the pane is shown
+// with the records at that level or above.
+func (p *chatPage) toggleLogs(levelName string) tea.Cmd {
+	if levelName == "" && p.logs != nil {
+		p.logs = nil
+		return p.SetSize(p.width, p.height)
+	}
+
+	level := slog.LevelInfo
+	if levelName != "" {
+		var err error
+		if level, err = logview.ParseLevel(levelName); err != nil {
+			return notification.ErrorCmd(err.Error())
+		}
+	}
+
+	if p.logs != nil {
+		p.logs.SetLevel(level)
+		return nil
+	}
+	p.logs = logview.New(logging.Recent(), level)
+	return tea.Batch(p.SetSize(p.width, p.height), p.logs.Tick())
+}
+
+// logsHeight returns the height of the log pane, taken from the chat area.
+func (p *chatPage) logsHeight() int {
+	if p.logs == nil {
+		return 0
+	}
+	return min(maxLogsHeight, p.chatHeight/3)
+}
+
+// transcriptHeight returns the height of the chat area left to the
+// transcripts.
+func (p *chatPage) transcriptHeight() int {
+	return max(1, p.chatHeight-p.logsHeight())
+}
@@ -0,0 +1,150 @@
+package chat
+
+import (
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+
+	"github.com/docker/cagent/pkg/tui/components/messages"
+	"github.com/docker/cagent/pkg/tui/core/layout"
+	"github.com/docker/cagent/pkg/tui/styles"
+)
+
+// mainPaneID is the ID of the pane showing the whole conversation in the
+// split view. Agent names are never empty so it can't clash with the pane of
+// a sub-agent.
+const mainPaneID = ""
+
+func newPanes(main messages.Model) *layout.Panes {
+	panes := layout.NewPanes()
+	panes.Add(layout.Pane{ID: mainPaneID, Title: "Conversation", Model: main})
+	return panes
+}
+
+// transcript returns the transcript that keys go to when the chat panel is
+// focused: the focused pane in the split view.
+func (p *chatPage) transcript() messages.Model {
+	if p.splitView {
+		if pane, ok := p.panes.Focused(); ok {
+			return pane.Model.(messages.Model)
+		}
+	}
+	return p.messages
+}
+
+// agentTranscript returns the transcript of the pane of a sub-agent, or nil
+// if no task was transferred to it.
+func (p *chatPage) agentTranscript(agentName string) messages.Model {
+	if agentName == mainPaneID {
+		return nil
+	}
+	model, ok := p.panes.Get(agentName)
+	if !ok {
+		return nil
+	}
+	return model.(messages.Model)
+}
+
+// addAgentPane adds a pane for a sub-agent the first time a task is
+// transferred to it. Its stream is then shown both in the conversation and
+// in its pane.
+func (p *chatPage) addAgentPane(agentName string) tea.Cmd {
+	if agentName == mainPaneID || p.agentTranscript(agentName) != nil {
+		return nil
+	}
+
+	transcript := messages.New(p.app, p.sessionState)
+	p.panes.Add(layout.Pane{ID: agentName, Title: agentName, Model: transcript})
+	return tea.Batch(transcript.Init(), p.SetSize(p.width, p.height))
+}
+
+// updateTranscripts forwards a message to the conversation and to the panes
+// of the sub-agents.
+func (p *chatPage) updateTranscripts(msg tea.Msg) tea.Cmd {
+	var cmds []tea.Cmd
+	for _, pane := range p.panes.All() {
+		_, cmd := pane.Model.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+	return tea.Batch(cmds...)
+}
+
+// toggleSplitView shows or hides the panes of the sub-agents next to the
+// conversation.
+func (p *chatPage) toggleSplitView() tea.Cmd {
+	p.transcript().Blur()
+	p.splitView = !p.splitView
+	if p.focusedPanel == PanelChat {
+		p.transcript().Focus()
+	}
+	p.syncFocusedPane()
+	return p.SetSize(p.width, p.height)
+}
+
+// focusPane moves the focus of the split view to another pane.
+func (p *chatPage) focusPane(move func()) {
+	p.transcript().Blur()
+	move()
+	p.transcript().Focus()
+	p.syncFocusedPane()
+}
+
+// syncFocusedPane shows in the sidebar which sub-agent's pane is focused.
+func (p *chatPage) syncFocusedPane() {
+	focused := ""
+	if pane, ok := p.panes.Focused(); ok && p.splitView {
+		focused = pane.ID
+	}
+	p.sidebar.SetFocusedPane(focused)
+}
+
+// sizeTranscripts sizes the conversation, or all the panes in the split view,
+// to the chat area whose top is at y.
+func (p *chatPage) sizeTranscripts(width, y int) tea.Cmd {
+	if p.logs != nil {
+		p.logs.SetSize(width, p.logsHeight())
+	}
+
+	if !p.splitView {
+		return tea.Batch(
+			p.messages.SetPosition(0, y),
+			p.messages.SetSize(width, p.transcriptHeight()),
+		)
+	}
+
+	cmds := []tea.Cmd{p.panes.SetSize(width, p.transcriptHeight())}
+	for _, pane := range p.panes.All() {
+		x, _, _ := p.panes.Bounds(pane.ID)
+		cmds = append(cmds, pane.Model.(messages.Model).SetPosition(x, y+1)) // +1 for the title
+	}
+	return tea.Batch(cmds...)
+}
+
+// chatView renders the conversation, or all the panes in the split view, and
+// the log pane below them.
+func (p *chatPage) chatView() string {
+	view := p.messages.View()
+	if p.splitView {
+		p.panes.TitleStyle = styles.MutedStyle
+		p.panes.FocusedTitleStyle = styles.HighlightWhiteStyle
+		p.panes.SeparatorStyle = styles.ResizeHandleStyle
+		view = p.panes.View()
+	}
+
+	if p.logs == nil {
+		return view
+	}
+	return lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.NewStyle().Height(p.transcriptHeight()).MaxHeight(p.transcriptHeight()).Render(view),
+		p.logs.View(),
+	)
+}
+
+// transcriptAt returns the transcript shown at x, relative to the chat area.
+func (p *chatPage) transcriptAt(x int) (string, messages.Model) {
+	if p.splitView {
+		if pane, ok := p.panes.PaneAt(x); ok {
+			return pane.ID, pane.Model.(messages.Model)
+		}
+	}
+	return mainPaneID, p.messages
+}
@@ -1,12 +1,19 @@
 package chat
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
+	"time"
 
 	tea "charm.land/bubbletea/v2"
 
+	"github.com/docker/cagent/pkg/guardrails"
 	"github.com/docker/cagent/pkg/runtime"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/tools"
+	"github.com/docker/cagent/pkg/tools/builtin"
+	"github.com/docker/cagent/pkg/tui/components/messages"
 	"github.com/docker/cagent/pkg/tui/components/notification"
 	"github.com/docker/cagent/pkg/tui/components/sidebar"
 	"github.com/docker/cagent/pkg/tui/core"
@@ -17,9 +24,13 @@ import (
 // handleRuntimeEvent processes runtime events and returns the appropriate command.
 // Returns (handled, cmd) where handled indicates if the event was processed.
 func (p *chatPage) handleRuntimeEvent(msg tea.Msg) (bool, tea.Cmd) {
+	if e, ok := msg.(runtime.Event); ok && e.GetAgentName() != "" {
+		p.messages.SetAgentDepth(e.GetAgentName(), runtime.EventDepth(e))
+	}
+
 	switch msg := msg.(type) {
 	case *runtime.ErrorEvent:
-		return true, p.messages.AddErrorMessage(msg.Error)
+		return true, tea.Batch(p.messages.AddErrorMessage(msg.Error), p.offerPostMortem(msg.Error))
 
 	case *runtime.ShellOutputEvent:
 		return true, p.messages.AddShellOutputMessage(msg.Output)
@@ -27,9 +38,23 @@ func (p *chatPage) handleRuntimeEvent(msg tea.Msg) (bool, tea.Cmd) {
 	case *runtime.WarningEvent:
 		return true, notification.WarningCmd(msg.Message)
 
+	case *runtime.ModelRetryEvent:
+		return true, p.handleModelRetry(msg)
+
+	case *runtime.ModelFailoverEvent:
+		return true, p.handleModelFailover(msg)
+
+	case *runtime.StallDetectedEvent:
+		timeout := time.Duration(msg.TimeoutMs) * time.Millisecond
+		return true, notification.WarningCmd(fmt.Sprintf("%s sent nothing for %s, the request was cancelled.", msg.Model, timeout))
+
+	case *runtime.GuardrailTriggeredEvent:
+		return true, guardrailNotice(msg)
+
 	case *runtime.RAGIndexingStartedEvent,
 		*runtime.RAGIndexingProgressEvent,
-		*runtime.RAGIndexingCompletedEvent:
+		*runtime.RAGIndexingCompletedEvent,
+		*runtime.SamplingUsageEvent:
 		return true, p.forwardToSidebar(msg)
 
 	case *runtime.UserMessageEvent:
@@ -48,16 +73,39 @@ func (p *chatPage) handleRuntimeEvent(msg tea.Msg) (bool, tea.Cmd) {
 		p.sidebar.SetTokenUsage(msg)
 		return true, nil
 
+	case *runtime.RequestThrottledEvent:
+		p.sidebar.SetThrottled(msg.AgentName, msg.Throttled)
+		return true, nil
+
+	case *runtime.PendingMessagesEvent:
+		p.sidebar.SetPendingMessages(msg.Messages)
+		return true, nil
+
+	case *runtime.CallDeduplicatedEvent:
+		p.sidebar.SetDeduplication(msg.Saved)
+		return true, nil
+
 	case *runtime.SessionCompactionEvent:
 		if msg.Status == "completed" {
 			return true, notification.SuccessCmd("Session compacted successfully.")
 		}
 		return true, nil
 
+	case *runtime.PostMortemEvent:
+		p.lastFailure = ""
+		return true, tea.Batch(
+			p.messages.AppendToLastMessage("post-mortem", types.MessageTypeAssistant, msg.PostMortem),
+			p.messages.ScrollToBottom(),
+			notification.SuccessCmd("Post-mortem saved, the next attempt will learn from it."),
+		)
+
+	case *runtime.SessionRotatedEvent:
+		return true, notification.InfoCmd("Conversation continued in a new session, seeded with a summary of the previous one.")
+
 	case *runtime.AgentInfoEvent:
 		p.sidebar.SetAgentInfo(msg.AgentName, msg.Model, msg.Description)
 		p.messages.AddWelcomeMessage(msg.WelcomeMessage)
-		return true, nil
+		return true, p.loadUsageBaseline(msg.AgentName)
 
 	case *runtime.TeamInfoEvent:
 		p.sidebar.SetTeamInfo(msg.AvailableAgents)
@@ -65,12 +113,23 @@ func (p *chatPage) handleRuntimeEvent(msg tea.Msg) (bool, tea.Cmd) {
 
 	case *runtime.AgentSwitchingEvent:
 		p.sidebar.SetAgentSwitching(msg.Switching)
+		if msg.Switching {
+			p.sidebar.SetTaskPriority(msg.ToAgent, msg.Priority)
+			return true, p.addAgentPane(msg.ToAgent)
+		}
+		p.sidebar.SetTaskPriority(msg.FromAgent, "")
 		return true, nil
 
 	case *runtime.ToolsetInfoEvent:
 		p.sidebar.SetToolsetInfo(msg.AvailableTools, msg.Loading)
 		return true, nil
 
+	case *runtime.MCPServerStatusEvent:
+		return true, tea.Batch(p.forwardToSidebar(msg), serverStatusNotice(msg))
+
+	case *runtime.ToolsReloadedEvent:
+		return true, notification.SuccessCmd(fmt.Sprintf("Tools reloaded, %d available", msg.AvailableTools))
+
 	case *runtime.StreamStoppedEvent:
 		return true, p.handleStreamStopped(msg)
 
@@ -89,6 +148,13 @@ func (p *chatPage) handleRuntimeEvent(msg tea.Msg) (bool, tea.Cmd) {
 	case *runtime.ToolCallResponseEvent:
 		return true, p.handleToolCallResponse(msg)
 
+	case *runtime.ToolResultSummarizedEvent:
+		p.messages.SetToolResultSummary(msg.ToolCall.ID, msg.Summary)
+		if t := p.agentTranscript(msg.AgentName); t != nil {
+			t.SetToolResultSummary(msg.ToolCall.ID, msg.Summary)
+		}
+		return true, nil
+
 	case *runtime.MaxIterationsReachedEvent:
 		return true, p.handleMaxIterationsReached(msg)
 
@@ -113,21 +179,42 @@ func (p *chatPage) handleStreamStarted(msg *runtime.StreamStartedEvent) tea.Cmd
 	assistantCmd := p.messages.AddAssistantMessage()
 	p.startProgressBar()
 	sidebarCmd := p.forwardToSidebar(msg)
-	return tea.Batch(assistantCmd, spinnerCmd, sidebarCmd)
+	return tea.Batch(assistantCmd, spinnerCmd, sidebarCmd, p.forAgentTranscript(msg.AgentName, func(t messages.Model) tea.Cmd {
+		return t.AddAssistantMessage()
+	}))
+}
+
+// forAgentTranscript also applies an event of a sub-agent to its pane.
+func (p *chatPage) forAgentTranscript(agentName string, apply func(t messages.Model) tea.Cmd) tea.Cmd {
+	t := p.agentTranscript(agentName)
+	if t == nil {
+		return nil
+	}
+	return tea.Batch(apply(t), t.ScrollToBottom())
 }
 
 func (p *chatPage) handleAgentChoice(msg *runtime.AgentChoiceEvent) tea.Cmd {
 	if p.streamCancelled {
 		return nil
 	}
-	return p.messages.AppendToLastMessage(msg.AgentName, types.MessageTypeAssistant, msg.Content)
+	return tea.Batch(
+		p.messages.AppendToLastMessage(msg.AgentName, types.MessageTypeAssistant, msg.Content),
+		p.forAgentTranscript(msg.AgentName, func(t messages.Model) tea.Cmd {
+			return t.AppendToLastMessage(msg.AgentName, types.MessageTypeAssistant, msg.Content)
+		}),
+	)
 }
 
 func (p *chatPage) handleAgentChoiceReasoning(msg *runtime.AgentChoiceReasoningEvent) tea.Cmd {
 	if p.streamCancelled {
 		return nil
 	}
-	return p.messages.AppendToLastMessage(msg.AgentName, types.MessageTypeAssistantReasoning, msg.Content)
+	return tea.Batch(
+		p.messages.AppendToLastMessage(msg.AgentName, types.MessageTypeAssistantReasoning, msg.Content),
+		p.forAgentTranscript(msg.AgentName, func(t messages.Model) tea.Cmd {
+			return t.AppendToLastMessage(msg.AgentName, types.MessageTypeAssistantReasoning, msg.Content)
+		}),
+	)
 }
 
 func (p *chatPage) handleStreamStopped(msg *runtime.StreamStoppedEvent) tea.Cmd {
@@ -145,15 +232,87 @@ func (p *chatPage) handleStreamStopped(msg *runtime.StreamStoppedEvent) tea.Cmd
 	return tea.Batch(p.messages.ScrollToBottom(), spinnerCmd, sidebarCmd, queueCmd)
 }
 
+func (p *chatPage) handleModelRetry(msg *runtime.ModelRetryEvent) tea.Cmd {
+	delay := time.Duration(msg.DelayMs) * time.Millisecond
+	notice := notification.WarningCmd(fmt.Sprintf("Request to %s failed, retrying in %s (%d/%d).", msg.Model, delay, msg.Retry, msg.MaxRetries))
+	if !msg.Discarded {
+		return notice
+	}
+	return tea.Batch(notice, p.discardPartialResponse(msg.AgentName))
+}
+
+func (p *chatPage) handleModelFailover(msg *runtime.ModelFailoverEvent) tea.Cmd {
+	notice := notification.WarningCmd(fmt.Sprintf("Requests to %s keep failing, %s answers for the rest of the turn.", msg.From, msg.To))
+	if !msg.Discarded {
+		return notice
+	}
+	return tea.Batch(notice, p.discardPartialResponse(msg.AgentName))
+}
+
+// serverStatusNotice tells the user when an MCP server came back, or didn't.
+func serverStatusNotice(msg *runtime.MCPServerStatusEvent) tea.Cmd {
+	switch msg.Status {
+	case tools.ServerReconnected:
+		return notification.InfoCmd(fmt.Sprintf("MCP server %s was restarted, %d tools available.", msg.Server, msg.AvailableTools))
+	case tools.ServerFailed:
+		return notification.ErrorCmd(fmt.Sprintf("MCP server %s couldn't be restarted: %s", msg.Server, msg.Error))
+	default:
+		return nil
+	}
+}
+
+// guardrailNotice tells what a guardrail did with an answer or a tool call,
+// e.g. "Guardrail pii redacted an email address from the arguments of fetch."
+func guardrailNotice(msg *runtime.GuardrailTriggeredEvent) tea.Cmd {
+	subject := "the answer"
+	if msg.Target == guardrails.TargetToolArguments {
+		subject = "the arguments of " + msg.ToolName
+	}
+
+	switch msg.Action {
+	case guardrails.ActionBlock:
+		if msg.Target == guardrails.TargetToolArguments {
+			subject = "the call to " + msg.ToolName
+		}
+		return notification.ErrorCmd(fmt.Sprintf("Guardrail %s blocked %s: %s.", msg.Guardrail, subject, msg.Reason))
+	case guardrails.ActionRedact:
+		return notification.WarningCmd(fmt.Sprintf("Guardrail %s redacted %s from %s.", msg.Guardrail, msg.Reason, subject))
+	default:
+		return notification.WarningCmd(fmt.Sprintf("Guardrail %s found %s in %s.", msg.Guardrail, msg.Reason, subject))
+	}
+}
+
+// discardPartialResponse removes what the agent streamed of a response that
+// failed, as it's streamed again.
+func (p *chatPage) discardPartialResponse(agentName string) tea.Cmd {
+	return tea.Batch(
+		p.messages.DiscardPartialResponse(agentName),
+		p.forAgentTranscript(agentName, func(t messages.Model) tea.Cmd {
+			return t.DiscardPartialResponse(agentName)
+		}),
+	)
+}
+
 func (p *chatPage) handlePartialToolCall(msg *runtime.PartialToolCallEvent) tea.Cmd {
 	spinnerCmd := p.setWorking(true)
 	toolCmd := p.messages.AddOrUpdateToolCall(msg.AgentName, msg.ToolCall, msg.ToolDefinition, types.ToolStatusPending)
-	return tea.Batch(toolCmd, p.messages.ScrollToBottom(), spinnerCmd)
+	paneCmd := p.forAgentTranscript(msg.AgentName, func(t messages.Model) tea.Cmd {
+		return t.AddOrUpdateToolCall(msg.AgentName, msg.ToolCall, msg.ToolDefinition, types.ToolStatusPending)
+	})
+	return tea.Batch(toolCmd, p.messages.ScrollToBottom(), spinnerCmd, paneCmd)
 }
 
 func (p *chatPage) handleToolCallConfirmation(msg *runtime.ToolCallConfirmationEvent) tea.Cmd {
 	spinnerCmd := p.setWorking(false)
 	toolCmd := p.messages.AddOrUpdateToolCall(msg.AgentName, msg.ToolCall, msg.ToolDefinition, types.ToolStatusConfirmation)
+
+	// File edits are reviewed inline: the diff is already rendered in the
+	// chat pane, so accept/reject keys are handled by the page itself.
+	if msg.ToolCall.Function.Name == builtin.ToolNameEditFile {
+		p.reviewingEdit = true
+		return tea.Batch(toolCmd, p.messages.ScrollToBottom(), spinnerCmd)
+	}
+
 	dialogCmd := core.CmdHandler(dialog.OpenDialogMsg{
 		Model: dialog.NewToolConfirmationDialog(msg, p.sessionState),
 	})
@@ -163,7 +322,10 @@ func (p *chatPage) handleToolCallConfirmation(msg *runtime.ToolCallConfirmationE
 func (p *chatPage) handleToolCall(msg *runtime.ToolCallEvent) tea.Cmd {
 	spinnerCmd := p.setWorking(true)
 	toolCmd := p.messages.AddOrUpdateToolCall(msg.AgentName, msg.ToolCall, msg.ToolDefinition, types.ToolStatusRunning)
-	return tea.Batch(toolCmd, p.messages.ScrollToBottom(), spinnerCmd)
+	paneCmd := p.forAgentTranscript(msg.AgentName, func(t messages.Model) tea.Cmd {
+		return t.AddOrUpdateToolCall(msg.AgentName, msg.ToolCall, msg.ToolDefinition, types.ToolStatusRunning)
+	})
+	return tea.Batch(toolCmd, p.messages.ScrollToBottom(), spinnerCmd, paneCmd)
 }
 
 func (p *chatPage) handleToolCallResponse(msg *runtime.ToolCallResponseEvent) tea.Cmd {
@@ -174,13 +336,20 @@ func (p *chatPage) handleToolCallResponse(msg *runtime.ToolCallResponseEvent) te
 		status = types.ToolStatusError
 	}
 	toolCmd := p.messages.AddToolResult(msg, status)
+	paneCmd := p.forAgentTranscript(msg.AgentName, func(t messages.Model) tea.Cmd {
+		return t.AddToolResult(msg, status)
+	})
 
 	// Update todo sidebar if this is a todo tool
 	if msg.ToolDefinition.Category == "todo" && !msg.Result.IsError {
 		_ = p.sidebar.SetTodos(msg.Result)
 	}
 
-	return tea.Batch(toolCmd, p.messages.ScrollToBottom(), spinnerCmd)
+	if handoff, ok := msg.Result.Meta.(*session.Handoff); ok {
+		p.sidebar.SetHandoff(handoff)
+	}
+
+	return tea.Batch(toolCmd, p.messages.ScrollToBottom(), spinnerCmd, paneCmd)
 }
 
 func (p *chatPage) handleMaxIterationsReached(msg *runtime.MaxIterationsReachedEvent) tea.Cmd {
@@ -192,7 +361,27 @@ func (p *chatPage) handleMaxIterationsReached(msg *runtime.MaxIterationsReachedE
 }
 
 func (p *chatPage) handleElicitationRequest(msg *runtime.ElicitationRequestEvent) tea.Cmd {
-	// TODO: handle normal elicitation requests
+	// The agent is still working on the sampling and elicitation requests: the
+	// server sends them during a tool call
+	switch msg.Meta["cagent/type"] {
+	case "sampling":
+		return core.CmdHandler(dialog.OpenDialogMsg{
+			Model: dialog.NewSamplingApprovalDialog(msg.Message, p.app),
+		})
+	case "elicitation":
+		fields, err := tools.ElicitationFields(msg.Schema)
+		if err != nil {
+			slog.Warn("Declining elicitation request", "error", err)
+			_ = p.app.ResumeElicitation(context.Background(), tools.ElicitationActionDecline, nil)
+			return notification.ErrorCmd(err.Error())
+		}
+		server, _ := msg.Meta["cagent/server"].(string)
+		url, _ := msg.Meta["cagent/url"].(string)
+		return core.CmdHandler(dialog.OpenDialogMsg{
+			Model: dialog.NewElicitationDialog(server, msg.Message, url, fields, p.app),
+		})
+	}
+
 	spinnerCmd := p.setWorking(false)
 
 	serverURL := msg.Meta["cagent/server_url"].(string)
@@ -202,3 +391,31 @@ func (p *chatPage) handleElicitationRequest(msg *runtime.ElicitationRequestEvent
 
 	return tea.Batch(spinnerCmd, dialogCmd)
 }
+
+// usageBaselineMsg carries the usage baseline of the current agent, loaded from the session store.
+type usageBaselineMsg struct {
+	baseline session.UsageBaseline
+}
+
+// loadUsageBaseline loads, in the background, the typical usage of the past
+// sessions of an agent so that the sidebar can compare the current session to it.
+func (p *chatPage) loadUsageBaseline(agentName string) tea.Cmd {
+	if agentName == "" || agentName == p.baselineAgent {
+		return nil
+	}
+	store := p.app.SessionStore()
+	if store == nil {
+		return nil
+	}
+	p.baselineAgent = agentName
+
+	sessionID := p.app.Session().ID
+	return func() tea.Msg {
+		summaries, err := store.GetSessionSummaries(context.Background())
+		if err != nil {
+			slog.Debug("Failed to load session summaries for the usage baseline", "error", err)
+			return nil
+		}
+		return usageBaselineMsg{baseline: session.NewUsageBaseline(summaries, agentName, sessionID)}
+	}
+}
@@ -0,0 +1,80 @@
+package chat
+
+import (
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/docker/cagent/pkg/tui/components/editor"
+)
+
+// In vim mode, the editor starts in insert mode. Esc switches it to normal
+// mode, where Tab still moves the focus to the transcript and Esc cancels the
+// stream. In the transcript, i and a go back to typing, and : types a command
+// whatever is focused.
+
+// handleVimKey handles the keys of the vim mode that move between the
+// panels, before the regular bindings.
+func (p *chatPage) handleVimKey(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	if !p.sessionState.VimMode {
+		return nil, false
+	}
+
+	switch p.focusedPanel {
+	case PanelEditor:
+		// Esc leaves the insert and command modes instead of cancelling the stream
+		if msg.String() == "esc" && p.editor.Mode() != editor.ModeNormal {
+			model, cmd := p.editor.Update(msg)
+			p.editor = model.(editor.Editor)
+			return cmd, true
+		}
+	case PanelChat:
+		// While lines are selected, keys (including Esc) go to the transcript
+		if p.transcript().IsSelectingLines() && !key.Matches(msg, p.keyMap.Tab) {
+			_, cmd := p.transcript().Update(msg)
+			return cmd, true
+		}
+
+		switch msg.String() {
+		case "i", "a":
+			return p.focusEditor(editor.ModeInsert), true
+		case ":":
+			return p.focusEditor(editor.ModeCommand), true
+		}
+	}
+	return nil, false
+}
+
+// focusEditor moves the focus from the transcript to the editor, in a mode.
+func (p *chatPage) focusEditor(mode editor.Mode) tea.Cmd {
+	p.transcript().Blur()
+	p.focusedPanel = PanelEditor
+	p.editor.SetMode(mode)
+	return p.editor.Focus()
+}
+
+// vimBindings are the bindings shown in the status bar in vim mode, telling
+// the mode of the editor.
+func (p *chatPage) vimBindings() []key.Binding {
+	if p.focusedPanel == PanelChat {
+		return []key.Binding{
+			key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "insert")),
+			key.NewBinding(key.WithKeys("v"), key.WithHelp("v", "select lines")),
+		}
+	}
+
+	switch p.editor.Mode() {
+	case editor.ModeNormal:
+		return []key.Binding{
+			key.NewBinding(key.WithKeys("i"), key.WithHelp("-- NORMAL -- i", "insert")),
+			key.NewBinding(key.WithKeys(":"), key.WithHelp(":", "command")),
+		}
+	case editor.ModeCommand:
+		return []key.Binding{
+			key.NewBinding(key.WithKeys("enter"), key.WithHelp("-- COMMAND -- enter", "run")),
+		}
+	default:
+		return []key.Binding{
+			key.NewBinding(key.WithKeys("esc"), key.WithHelp("-- INSERT -- esc", "normal")),
+		}
+	}
+}
@@ -14,6 +14,14 @@ type SessionState struct {
 	SplitDiffView   bool
 	YoloMode        bool
 	HideToolResults bool
+	// RawMarkdown shows assistant messages as raw markdown instead of rendering them
+	RawMarkdown bool
+	// ExpandToolCalls shows every finished tool call expanded instead of as a one-line summary
+	ExpandToolCalls bool
+	// HideSubAgents hides the messages and tool calls of the agents tasks are transferred to
+	HideSubAgents bool
+	// VimMode enables the vim-style modal navigation of the editor and the transcript
+	VimMode         bool
 	PreviousMessage *types.Message
 	// CurrentAgent is the name of the currently active agent for user messages
 	CurrentAgent string
@@ -45,6 +53,18 @@ func (s *SessionState) ToggleHideToolResults() {
 	s.HideToolResults = !s.HideToolResults
 }
 
+func (s *SessionState) ToggleRawMarkdown() {
+	s.RawMarkdown = !s.RawMarkdown
+}
+
+func (s *SessionState) ToggleExpandToolCalls() {
+	s.ExpandToolCalls = !s.ExpandToolCalls
+}
+
+func (s *SessionState) ToggleHideSubAgents() {
+	s.HideSubAgents = !s.HideSubAgents
+}
+
 func (s *SessionState) SetCurrentAgent(agentName string) {
 	s.CurrentAgent = agentName
 }
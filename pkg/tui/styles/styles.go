@@ -1,6 +1,7 @@
 package styles
 
 import (
+	"image/color"
 	"strings"
 
 	"charm.land/bubbles/v2/textarea"
@@ -15,7 +16,7 @@ const (
 	defaultMargin     = 2
 )
 
-// Color hex values (used throughout the file)
+// Color hex values of the default dark theme
 const (
 	// Primary colors
 	ColorWhite           = "#E5F2FC"
@@ -99,298 +100,558 @@ const (
 	ANSIColor244 = "244"
 )
 
-// Tokyo Night-inspired Color Palette
+// Color palette, set from the current theme (see ApplyTheme)
 var (
 	// Background colors
-	Background    = lipgloss.Color(ColorBackground)
-	BackgroundAlt = lipgloss.Color(ColorBackgroundAlt)
+	Background    color.Color
+	BackgroundAlt color.Color
 
 	// Primary accent colors
-	White    = lipgloss.Color(ColorWhite)
-	MobyBlue = lipgloss.Color(ColorMobyBlue)
-	Accent   = lipgloss.Color(ColorAccentBlue)
+	White       color.Color
+	MobyBlue    color.Color
+	MobyBlueBg  color.Color
+	TextOnBrand color.Color
+	Accent      color.Color
 
 	// Status colors - softer, more professional
-	Success   = lipgloss.Color(ColorSuccessGreen)
-	Error     = lipgloss.Color(ColorErrorRed)
-	Warning   = lipgloss.Color(ColorWarningYellow)
-	Info      = lipgloss.Color(ColorInfoCyan)
-	Highlight = lipgloss.Color(ColorHighlight)
+	Success     color.Color
+	Error       color.Color
+	ErrorStrong color.Color
+	ErrorBg     color.Color
+	Warning     color.Color
+	Info        color.Color
+	Highlight   color.Color
 
 	// Text hierarchy
-	TextPrimary   = lipgloss.Color(ColorTextPrimary)
-	TextSecondary = lipgloss.Color(ColorTextSecondary)
-	TextMuted     = lipgloss.Color(ColorMutedBlue)
-	TextMutedGray = lipgloss.Color(ColorMutedGray)
+	TextPrimary   color.Color
+	TextSecondary color.Color
+	TextMuted     color.Color
+	TextMutedGray color.Color
 
 	// Border colors
-	BorderPrimary   = lipgloss.Color(ColorAccentBlue)
-	BorderSecondary = lipgloss.Color(ColorBorderSecondary)
-	BorderMuted     = lipgloss.Color(ColorBackgroundAlt)
-	BorderWarning   = lipgloss.Color(ColorWarningYellow)
+	BorderPrimary   color.Color
+	BorderSecondary color.Color
+	BorderMuted     color.Color
+	BorderWarning   color.Color
 
 	// Diff colors (matching glamour/markdown "dark" theme)
-	DiffAddBg    = lipgloss.Color(ColorDiffAddBg)
-	DiffRemoveBg = lipgloss.Color(ColorDiffRemoveBg)
-	DiffAddFg    = lipgloss.Color(ColorSuccessGreen)
-	DiffRemoveFg = lipgloss.Color(ColorErrorRed)
+	DiffAddBg    color.Color
+	DiffRemoveBg color.Color
+	DiffAddFg    color.Color
+	DiffRemoveFg color.Color
 
 	// UI element colors
-	LineNumber = lipgloss.Color(ColorLineNumber)
-	Separator  = lipgloss.Color(ColorSeparator)
+	LineNumber color.Color
+	Separator  color.Color
 
 	// Interactive element colors
-	Selected         = lipgloss.Color(ColorSelected)
-	SelectedFg       = lipgloss.Color(ColorTextPrimary)
-	PlaceholderColor = lipgloss.Color(ColorMutedGray)
+	Selected         color.Color
+	SelectedFg       color.Color
+	PlaceholderColor color.Color
+	SuggestionGhost  color.Color
 
 	// Badge colors
-	AgentBadgeFg = White
-	AgentBadgeBg = MobyBlue
+	AgentBadgeFg color.Color
+	AgentBadgeBg color.Color
+	BadgePurple  color.Color
+	BadgeCyan    color.Color
+	BadgeGreen   color.Color
 
 	// Tabs
-	TabBg        = lipgloss.Color(ColorTab)
-	TabPrimaryFg = lipgloss.Color(ColorMutedGray)
-	TabAccentFg  = lipgloss.Color(ColorHighlight)
+	TabBg        color.Color
+	TabPrimaryFg color.Color
+	TabAccentFg  color.Color
+
+	// Spinner glow
+	SpinnerDim       color.Color
+	SpinnerBright    color.Color
+	SpinnerBrightest color.Color
 )
 
 // Base Styles
 const AppPaddingLeft = 1 // Keep in sync with AppStyle padding
 
 var (
-	NoStyle   = lipgloss.NewStyle()
-	BaseStyle = NoStyle.Foreground(TextPrimary)
-	AppStyle  = BaseStyle.Padding(0, 1, 0, AppPaddingLeft)
+	NoStyle   lipgloss.Style
+	BaseStyle lipgloss.Style
+	AppStyle  lipgloss.Style
 )
 
 // Text Styles
 var (
-	HighlightWhiteStyle = BaseStyle.Foreground(White).Bold(true)
-	MutedStyle          = BaseStyle.Foreground(TextMutedGray)
-	SecondaryStyle      = BaseStyle.Foreground(TextSecondary)
-	BoldStyle           = BaseStyle.Bold(true)
+	HighlightWhiteStyle lipgloss.Style
+	MutedStyle          lipgloss.Style
+	SecondaryStyle      lipgloss.Style
+	BoldStyle           lipgloss.Style
 )
 
 // Status Styles
 var (
-	SuccessStyle    = BaseStyle.Foreground(Success)
-	ErrorStyle      = BaseStyle.Foreground(Error)
-	WarningStyle    = BaseStyle.Foreground(Warning)
-	InfoStyle       = BaseStyle.Foreground(Info)
-	ActiveStyle     = BaseStyle.Foreground(Success)
-	ToBeDoneStyle   = BaseStyle.Foreground(TextPrimary)
-	InProgressStyle = BaseStyle.Foreground(Highlight)
-	CompletedStyle  = BaseStyle.Foreground(TextMutedGray)
+	SuccessStyle    lipgloss.Style
+	ErrorStyle      lipgloss.Style
+	WarningStyle    lipgloss.Style
+	InfoStyle       lipgloss.Style
+	ActiveStyle     lipgloss.Style
+	ToBeDoneStyle   lipgloss.Style
+	InProgressStyle lipgloss.Style
+	CompletedStyle  lipgloss.Style
 )
 
 // Layout Styles
 var (
-	CenterStyle = BaseStyle.Align(lipgloss.Center, lipgloss.Center)
+	CenterStyle lipgloss.Style
 )
 
 // Border Styles
 var (
+	BaseMessageStyle      lipgloss.Style
+	UserMessageStyle      lipgloss.Style
+	AssistantMessageStyle lipgloss.Style
+	WelcomeMessageStyle   lipgloss.Style
+	ErrorMessageStyle     lipgloss.Style
+	SelectedMessageStyle  lipgloss.Style
+)
+
+// Dialog Styles
+var (
+	DialogStyle             lipgloss.Style
+	DialogWarningStyle      lipgloss.Style
+	DialogTitleStyle        lipgloss.Style
+	DialogTitleWarningStyle lipgloss.Style
+	DialogTitleInfoStyle    lipgloss.Style
+	DialogContentStyle      lipgloss.Style
+	DialogSeparatorStyle    lipgloss.Style
+	DialogQuestionStyle     lipgloss.Style
+	DialogOptionsStyle      lipgloss.Style
+	DialogHelpStyle         lipgloss.Style
+	TabTitleStyle           lipgloss.Style
+	TabStyle                lipgloss.Style
+	TabPrimaryStyle         lipgloss.Style
+	TabAccentStyle          lipgloss.Style
+)
+
+// Model selector Badge colors
+const (
+	ColorBadgePurple = "#B083EA" // Purple for alloy badge
+	ColorBadgeCyan   = "#7DCFFF" // Cyan for default badge
+	ColorBadgeGreen  = "#9ECE6A" // Green for current badge
+)
+
+// Command Palette Styles
+var (
+	PaletteCategoryStyle         lipgloss.Style
+	PaletteUnselectedActionStyle lipgloss.Style
+	PaletteSelectedActionStyle   lipgloss.Style
+	PaletteUnselectedDescStyle   lipgloss.Style
+	PaletteSelectedDescStyle     lipgloss.Style
+
+	// Badge styles for model picker
+	BadgeAlloyStyle   lipgloss.Style
+	BadgeDefaultStyle lipgloss.Style
+	BadgeCurrentStyle lipgloss.Style
+)
+
+// Star Styles for session browser and sidebar
+var (
+	StarredStyle   lipgloss.Style
+	UnstarredStyle lipgloss.Style
+)
+
+// StarIndicator returns the styled star indicator for a given starred status
+func StarIndicator(starred bool) string {
+	if starred {
+		return StarredStyle.Render("★") + " "
+	}
+	return UnstarredStyle.Render("☆") + " "
+}
+
+// Diff Styles (matching glamour markdown theme)
+var (
+	DiffAddStyle       lipgloss.Style
+	DiffRemoveStyle    lipgloss.Style
+	DiffUnchangedStyle lipgloss.Style
+)
+
+// Syntax highlighting UI element styles
+var (
+	LineNumberStyle lipgloss.Style
+	SeparatorStyle  lipgloss.Style
+)
+
+// Tool Call Styles
+var (
+	ToolMessageStyle      lipgloss.Style
+	ToolErrorMessageStyle lipgloss.Style
+	ToolName              lipgloss.Style
+	ToolNameError         lipgloss.Style
+	ToolCompletedIcon     lipgloss.Style
+	ToolErrorIcon         lipgloss.Style
+	ToolPendingIcon       lipgloss.Style
+	ToolCallArgs          lipgloss.Style
+	ToolCallResult        lipgloss.Style
+	ToolHandoffCard       lipgloss.Style
+)
+
+// Input Styles
+var (
+	InputStyle textarea.Styles
+
+	// DialogInputStyle is the style for textinput fields in dialogs,
+	// matching the main editor's look (cursor color, text color).
+	DialogInputStyle textinput.Styles
+	EditorStyle      lipgloss.Style
+	// SuggestionGhostStyle renders inline auto-complete hints in a muted tone.
+	// Use a distinct grey so suggestion text is visually separate from the user's input.
+	SuggestionGhostStyle lipgloss.Style
+	// SuggestionCursorStyle renders the first character of a suggestion inside the cursor.
+	// Uses the same blue accent background as the normal cursor, with ghost-colored foreground text.
+	SuggestionCursorStyle lipgloss.Style
+
+	// Attachment banner styles - polished look with subtle border
+	AttachmentBannerStyle lipgloss.Style
+	AttachmentBadgeStyle  lipgloss.Style
+	AttachmentSizeStyle   lipgloss.Style
+	AttachmentIconStyle   lipgloss.Style
+)
+
+// Scrollbar
+var (
+	TrackStyle       lipgloss.Style
+	ThumbStyle       lipgloss.Style
+	ThumbActiveStyle lipgloss.Style
+)
+
+// Resize Handle Style
+var (
+	ResizeHandleStyle       lipgloss.Style
+	ResizeHandleHoverStyle  lipgloss.Style
+	ResizeHandleActiveStyle lipgloss.Style
+)
+
+// Notification Styles
+var (
+	NotificationStyle        lipgloss.Style
+	NotificationInfoStyle    lipgloss.Style
+	NotificationWarningStyle lipgloss.Style
+	NotificationErrorStyle   lipgloss.Style
+)
+
+// Completion Styles
+var (
+	CompletionBoxStyle          lipgloss.Style
+	CompletionNormalStyle       lipgloss.Style
+	CompletionSelectedStyle     lipgloss.Style
+	CompletionDescStyle         lipgloss.Style
+	CompletionSelectedDescStyle lipgloss.Style
+	CompletionNoResultsStyle    lipgloss.Style
+)
+
+// Agent and transfer badge styles
+var (
+	AgentBadgeStyle    lipgloss.Style
+	ThinkingBadgeStyle lipgloss.Style
+)
+
+// Deprecated styles (kept for backward compatibility)
+var (
+	ChatStyle lipgloss.Style
+)
+
+// Selection Styles
+var (
+	SelectionStyle lipgloss.Style
+)
+
+// Search Styles
+var (
+	SearchMatchStyle        lipgloss.Style
+	SearchCurrentMatchStyle lipgloss.Style
+	SearchBarStyle          lipgloss.Style
+)
+
+// Spinner Styles
+var (
+	SpinnerDotsAccentStyle    lipgloss.Style
+	SpinnerDotsHighlightStyle lipgloss.Style
+	SpinnerTextBrightestStyle lipgloss.Style
+	SpinnerTextBrightStyle    lipgloss.Style
+	SpinnerTextDimStyle       lipgloss.Style
+	SpinnerTextDimmestStyle   lipgloss.Style
+)
+
+// applyColors sets the color palette from a theme.
+func applyColors(t Theme) {
+	// Background colors
+	Background = lipgloss.Color(t.Background)
+	BackgroundAlt = lipgloss.Color(t.BackgroundAlt)
+
+	// Primary accent colors
+	White = lipgloss.Color(t.TextBright)
+	MobyBlue = lipgloss.Color(t.Brand)
+	MobyBlueBg = lipgloss.Color(t.BrandBg)
+	TextOnBrand = lipgloss.Color(t.TextOnBrand)
+	Accent = lipgloss.Color(t.Accent)
+
+	// Status colors - softer, more professional
+	Success = lipgloss.Color(t.Success)
+	Error = lipgloss.Color(t.Error)
+	ErrorStrong = lipgloss.Color(t.ErrorStrong)
+	ErrorBg = lipgloss.Color(t.ErrorBg)
+	Warning = lipgloss.Color(t.Warning)
+	Info = lipgloss.Color(t.Info)
+	Highlight = lipgloss.Color(t.Highlight)
+
+	// Text hierarchy
+	TextPrimary = lipgloss.Color(t.TextPrimary)
+	TextSecondary = lipgloss.Color(t.TextSecondary)
+	TextMuted = lipgloss.Color(t.TextMuted)
+	TextMutedGray = lipgloss.Color(t.TextMutedGray)
+
+	// Border colors
+	BorderPrimary = lipgloss.Color(t.Accent)
+	BorderSecondary = lipgloss.Color(t.BorderSecondary)
+	BorderMuted = lipgloss.Color(t.BackgroundAlt)
+	BorderWarning = lipgloss.Color(t.Warning)
+
+	// Diff colors (matching glamour/markdown "dark" theme)
+	DiffAddBg = lipgloss.Color(t.DiffAddBg)
+	DiffRemoveBg = lipgloss.Color(t.DiffRemoveBg)
+	DiffAddFg = lipgloss.Color(t.Success)
+	DiffRemoveFg = lipgloss.Color(t.Error)
+
+	// UI element colors
+	LineNumber = lipgloss.Color(t.LineNumber)
+	Separator = lipgloss.Color(t.Separator)
+
+	// Interactive element colors
+	Selected = lipgloss.Color(t.Selected)
+	SelectedFg = lipgloss.Color(t.TextPrimary)
+	PlaceholderColor = lipgloss.Color(t.TextMutedGray)
+	SuggestionGhost = lipgloss.Color(t.SuggestionGhost)
+
+	// Badge colors
+	AgentBadgeFg = TextOnBrand
+	AgentBadgeBg = MobyBlue
+	BadgePurple = lipgloss.Color(t.BadgePurple)
+	BadgeCyan = lipgloss.Color(t.BadgeCyan)
+	BadgeGreen = lipgloss.Color(t.BadgeGreen)
+
+	// Tabs
+	TabBg = lipgloss.Color(t.Tab)
+	TabPrimaryFg = lipgloss.Color(t.TextMutedGray)
+	TabAccentFg = lipgloss.Color(t.Highlight)
+
+	// Spinner glow
+	SpinnerDim = lipgloss.Color(t.SpinnerDim)
+	SpinnerBright = lipgloss.Color(t.SpinnerBright)
+	SpinnerBrightest = lipgloss.Color(t.SpinnerBrightest)
+}
+
+// buildStyles derives all the styles from the color palette.
+func buildStyles() {
+	// Base Styles
+	NoStyle = lipgloss.NewStyle()
+	BaseStyle = NoStyle.Foreground(TextPrimary)
+	AppStyle = BaseStyle.Padding(0, 1, 0, AppPaddingLeft)
+
+	// Text Styles
+	HighlightWhiteStyle = BaseStyle.Foreground(White).Bold(true)
+	MutedStyle = BaseStyle.Foreground(TextMutedGray)
+	SecondaryStyle = BaseStyle.Foreground(TextSecondary)
+	BoldStyle = BaseStyle.Bold(true)
+
+	// Status Styles
+	SuccessStyle = BaseStyle.Foreground(Success)
+	ErrorStyle = BaseStyle.Foreground(Error)
+	WarningStyle = BaseStyle.Foreground(Warning)
+	InfoStyle = BaseStyle.Foreground(Info)
+	ActiveStyle = BaseStyle.Foreground(Success)
+	ToBeDoneStyle = BaseStyle.Foreground(TextPrimary)
+	InProgressStyle = BaseStyle.Foreground(Highlight)
+	CompletedStyle = BaseStyle.Foreground(TextMutedGray)
+
+	// Layout Styles
+	CenterStyle = BaseStyle.Align(lipgloss.Center, lipgloss.Center)
+
+	// Border Styles
 	BaseMessageStyle = BaseStyle.
-				Padding(1, 1).
-				BorderLeft(true).
-				BorderStyle(lipgloss.HiddenBorder()).
-				BorderForeground(BorderPrimary)
+		Padding(1, 1).
+		BorderLeft(true).
+		BorderStyle(lipgloss.HiddenBorder()).
+		BorderForeground(BorderPrimary)
 
 	UserMessageStyle = BaseMessageStyle.
-				BorderStyle(lipgloss.ThickBorder()).
-				BorderForeground(BorderPrimary).
-				Background(BackgroundAlt).
-				Bold(true)
+		BorderStyle(lipgloss.ThickBorder()).
+		BorderForeground(BorderPrimary).
+		Background(BackgroundAlt).
+		Bold(true)
 
 	AssistantMessageStyle = BaseMessageStyle.
-				Padding(0, 1)
+		Padding(0, 1)
 
 	WelcomeMessageStyle = BaseMessageStyle.
-				BorderStyle(lipgloss.DoubleBorder()).
-				Bold(true)
+		BorderStyle(lipgloss.DoubleBorder()).
+		Bold(true)
 
 	ErrorMessageStyle = BaseMessageStyle.
-				BorderStyle(lipgloss.ThickBorder()).
-				Foreground(Error)
+		BorderStyle(lipgloss.ThickBorder()).
+		Foreground(Error)
 
 	SelectedMessageStyle = AssistantMessageStyle.
-				BorderStyle(lipgloss.NormalBorder()).
-				BorderForeground(Success)
-)
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(Success)
 
-// Dialog Styles
-var (
+	// Dialog Styles
 	DialogStyle = BaseStyle.
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(BorderSecondary).
-			Foreground(TextPrimary).
-			Padding(1, 2).
-			Align(lipgloss.Left)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(BorderSecondary).
+		Foreground(TextPrimary).
+		Padding(1, 2).
+		Align(lipgloss.Left)
 
 	DialogWarningStyle = BaseStyle.
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(BorderWarning).
-				Foreground(TextPrimary).
-				Padding(1, 2).
-				Align(lipgloss.Left)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(BorderWarning).
+		Foreground(TextPrimary).
+		Padding(1, 2).
+		Align(lipgloss.Left)
 
 	DialogTitleStyle = BaseStyle.
-				Bold(true).
-				Foreground(TextSecondary).
-				Align(lipgloss.Center)
+		Bold(true).
+		Foreground(TextSecondary).
+		Align(lipgloss.Center)
 
 	DialogTitleWarningStyle = BaseStyle.
-				Bold(true).
-				Foreground(Warning).
-				Align(lipgloss.Center)
+		Bold(true).
+		Foreground(Warning).
+		Align(lipgloss.Center)
 
 	DialogTitleInfoStyle = BaseStyle.
-				Bold(true).
-				Foreground(Info).
-				Align(lipgloss.Center)
+		Bold(true).
+		Foreground(Info).
+		Align(lipgloss.Center)
 
 	DialogContentStyle = BaseStyle.
-				Foreground(TextPrimary)
+		Foreground(TextPrimary)
 
 	DialogSeparatorStyle = BaseStyle.
-				Foreground(BorderMuted)
+		Foreground(BorderMuted)
 
 	DialogQuestionStyle = BaseStyle.
-				Bold(true).
-				Foreground(TextPrimary).
-				Align(lipgloss.Center)
+		Bold(true).
+		Foreground(TextPrimary).
+		Align(lipgloss.Center)
 
 	DialogOptionsStyle = BaseStyle.
-				Foreground(TextMuted).
-				Align(lipgloss.Center)
+		Foreground(TextMuted).
+		Align(lipgloss.Center)
 
 	DialogHelpStyle = BaseStyle.
-			Foreground(TextMuted).
-			Italic(true)
+		Foreground(TextMuted).
+		Italic(true)
 
 	TabTitleStyle = BaseStyle.
-			Foreground(TabPrimaryFg)
+		Foreground(TabPrimaryFg)
 
 	TabStyle = TabPrimaryStyle.
-			Padding(1, 0)
+		Padding(1, 0)
 
 	TabPrimaryStyle = BaseStyle.
-			Foreground(TextPrimary)
+		Foreground(TextPrimary)
 
 	TabAccentStyle = BaseStyle.
-			Foreground(TabAccentFg)
-)
-
-// Model selector Badge colors
-const (
-	ColorBadgePurple = "#B083EA" // Purple for alloy badge
-	ColorBadgeCyan   = "#7DCFFF" // Cyan for default badge
-	ColorBadgeGreen  = "#9ECE6A" // Green for current badge
-)
+		Foreground(TabAccentFg)
 
-// Command Palette Styles
-var (
+	// Command Palette Styles
 	PaletteCategoryStyle = BaseStyle.
-				Bold(true).
-				Foreground(White).
-				MarginTop(1)
+		Bold(true).
+		Foreground(White).
+		MarginTop(1)
 
 	PaletteUnselectedActionStyle = BaseStyle.
-					Foreground(TextPrimary).
-					Bold(true)
+		Foreground(TextPrimary).
+		Bold(true)
 
 	PaletteSelectedActionStyle = PaletteUnselectedActionStyle.
-					Background(MobyBlue).
-					Foreground(White)
+		Background(MobyBlue).
+		Foreground(TextOnBrand)
 
 	PaletteUnselectedDescStyle = BaseStyle.
-					Foreground(TextSecondary)
+		Foreground(TextSecondary)
 
 	PaletteSelectedDescStyle = PaletteUnselectedDescStyle.
-					Background(MobyBlue).
-					Foreground(White)
+		Background(MobyBlue).
+		Foreground(TextOnBrand)
 
 	// Badge styles for model picker
 	BadgeAlloyStyle = BaseStyle.
-			Foreground(lipgloss.Color(ColorBadgePurple))
+		Foreground(BadgePurple)
 
 	BadgeDefaultStyle = BaseStyle.
-				Foreground(lipgloss.Color(ColorBadgeCyan))
+		Foreground(BadgeCyan)
 
 	BadgeCurrentStyle = BaseStyle.
-				Foreground(lipgloss.Color(ColorBadgeGreen))
-)
+		Foreground(BadgeGreen)
 
-// Star Styles for session browser and sidebar
-var (
-	StarredStyle   = BaseStyle.Foreground(Success)
+	// Star Styles for session browser and sidebar
+	StarredStyle = BaseStyle.Foreground(Success)
 	UnstarredStyle = BaseStyle.Foreground(TextMuted)
-)
 
-// StarIndicator returns the styled star indicator for a given starred status
-func StarIndicator(starred bool) string {
-	if starred {
-		return StarredStyle.Render("★") + " "
-	}
-	return UnstarredStyle.Render("☆") + " "
-}
-
-// Diff Styles (matching glamour markdown theme)
-var (
+	// Diff Styles (matching glamour markdown theme)
 	DiffAddStyle = BaseStyle.
-			Background(DiffAddBg).
-			Foreground(DiffAddFg)
+		Background(DiffAddBg).
+		Foreground(DiffAddFg)
 
 	DiffRemoveStyle = BaseStyle.
-			Background(DiffRemoveBg).
-			Foreground(DiffRemoveFg)
+		Background(DiffRemoveBg).
+		Foreground(DiffRemoveFg)
 
 	DiffUnchangedStyle = BaseStyle.Background(BackgroundAlt)
-)
 
-// Syntax highlighting UI element styles
-var (
+	// Syntax highlighting UI element styles
 	LineNumberStyle = BaseStyle.Foreground(LineNumber).Background(BackgroundAlt)
-	SeparatorStyle  = BaseStyle.Foreground(Separator).Background(BackgroundAlt)
-)
+	SeparatorStyle = BaseStyle.Foreground(Separator).Background(BackgroundAlt)
 
-// Tool Call Styles
-var (
+	// Tool Call Styles
 	ToolMessageStyle = BaseStyle.
-				Foreground(TextMutedGray)
+		Foreground(TextMutedGray)
 
 	ToolErrorMessageStyle = BaseStyle.
-				Foreground(lipgloss.Color(ColorErrorStrong))
+		Foreground(ErrorStrong)
 
 	ToolName = ToolMessageStyle.
-			Foreground(MobyBlue).
-			Background(lipgloss.Color(ColorDarkBlue)).
-			Padding(0, 1)
+		Foreground(MobyBlue).
+		Background(MobyBlueBg).
+		Padding(0, 1)
 
 	ToolNameError = ToolName.
-			Foreground(lipgloss.Color(ColorErrorStrong)).
-			Background(lipgloss.Color(ColorErrorDark))
+		Foreground(ErrorStrong).
+		Background(ErrorBg)
 
 	ToolCompletedIcon = BaseStyle.
-				MarginLeft(2).
-				Foreground(White).
-				Background(MobyBlue)
+		MarginLeft(2).
+		Foreground(TextOnBrand).
+		Background(MobyBlue)
 
 	ToolErrorIcon = ToolCompletedIcon.
-			Background(lipgloss.Color(ColorErrorStrong))
+		Background(ErrorStrong)
 
 	ToolPendingIcon = ToolCompletedIcon.
-			Background(lipgloss.Color(ColorWarningYellow))
+		Background(Warning)
 
 	ToolCallArgs = ToolMessageStyle.
-			Padding(0, 0, 0, 2)
+		Padding(0, 0, 0, 2)
 
 	ToolCallResult = ToolMessageStyle.
-			Padding(0, 0, 0, 2)
-)
+		Padding(0, 0, 0, 2)
 
-// Input Styles
-var (
+	ToolHandoffCard = ToolMessageStyle.
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(BorderSecondary).
+		Padding(0, 1).
+		MarginLeft(2)
+
+	// Input Styles
 	InputStyle = textarea.Styles{
 		Focused: textarea.StyleState{
 			Base:        BaseStyle,
@@ -423,133 +684,130 @@ var (
 	EditorStyle = BaseStyle.Padding(1, 0, 0, 0)
 	// SuggestionGhostStyle renders inline auto-complete hints in a muted tone.
 	// Use a distinct grey so suggestion text is visually separate from the user's input.
-	SuggestionGhostStyle = BaseStyle.Foreground(lipgloss.Color(ColorSuggestionGhost))
+	SuggestionGhostStyle = BaseStyle.Foreground(SuggestionGhost)
 	// SuggestionCursorStyle renders the first character of a suggestion inside the cursor.
 	// Uses the same blue accent background as the normal cursor, with ghost-colored foreground text.
-	SuggestionCursorStyle = BaseStyle.Background(Accent).Foreground(lipgloss.Color(ColorSuggestionGhost))
+	SuggestionCursorStyle = BaseStyle.Background(Accent).Foreground(SuggestionGhost)
 
 	// Attachment banner styles - polished look with subtle border
 	AttachmentBannerStyle = BaseStyle.
-				Foreground(TextSecondary)
+		Foreground(TextSecondary)
 
 	AttachmentBadgeStyle = BaseStyle.
-				Foreground(lipgloss.Color(ColorInfoCyan)).
-				Bold(true)
+		Foreground(Info).
+		Bold(true)
 
 	AttachmentSizeStyle = BaseStyle.
-				Foreground(TextMuted).
-				Italic(true)
+		Foreground(TextMuted).
+		Italic(true)
 
 	AttachmentIconStyle = BaseStyle.
-				Foreground(lipgloss.Color(ColorInfoCyan))
-)
+		Foreground(Info)
 
-// Scrollbar
-var (
-	TrackStyle       = lipgloss.NewStyle().Foreground(BorderSecondary)
-	ThumbStyle       = lipgloss.NewStyle().Foreground(Info).Background(BackgroundAlt).Bold(true)
+	// Scrollbar
+	TrackStyle = lipgloss.NewStyle().Foreground(BorderSecondary)
+	ThumbStyle = lipgloss.NewStyle().Foreground(Info).Background(BackgroundAlt).Bold(true)
 	ThumbActiveStyle = lipgloss.NewStyle().Foreground(White).Background(BackgroundAlt).Bold(true)
-)
 
-// Resize Handle Style
-var (
+	// Resize Handle Style
 	ResizeHandleStyle = BaseStyle.
-				Foreground(BorderSecondary)
+		Foreground(BorderSecondary)
 
 	ResizeHandleHoverStyle = BaseStyle.
-				Foreground(Info).
-				Bold(true)
+		Foreground(Info).
+		Bold(true)
 
 	ResizeHandleActiveStyle = BaseStyle.
-				Foreground(White).
-				Bold(true)
-)
+		Foreground(White).
+		Bold(true)
 
-// Notification Styles
-var (
+	// Notification Styles
 	NotificationStyle = BaseStyle.
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(Success).
-				Padding(0, 1)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(Success).
+		Padding(0, 1)
 
 	NotificationInfoStyle = BaseStyle.
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(Info).
-				Padding(0, 1)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(Info).
+		Padding(0, 1)
 
 	NotificationWarningStyle = BaseStyle.
-					Border(lipgloss.RoundedBorder()).
-					BorderForeground(Warning).
-					Padding(0, 1)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(Warning).
+		Padding(0, 1)
 
 	NotificationErrorStyle = BaseStyle.
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(Error).
-				Padding(0, 1)
-)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(Error).
+		Padding(0, 1)
 
-// Completion Styles
-var (
+	// Completion Styles
 	CompletionBoxStyle = BaseStyle.
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(BorderSecondary).
-				Padding(0, 1)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(BorderSecondary).
+		Padding(0, 1)
 
 	CompletionNormalStyle = BaseStyle.
-				Foreground(TextPrimary).
-				Bold(true)
+		Foreground(TextPrimary).
+		Bold(true)
 
 	CompletionSelectedStyle = CompletionNormalStyle.
-				Foreground(White).
-				Background(MobyBlue)
+		Foreground(TextOnBrand).
+		Background(MobyBlue)
 
 	CompletionDescStyle = BaseStyle.
-				Foreground(TextSecondary)
+		Foreground(TextSecondary)
 
 	CompletionSelectedDescStyle = CompletionDescStyle.
-					Foreground(White).
-					Background(MobyBlue)
+		Foreground(TextOnBrand).
+		Background(MobyBlue)
 
 	CompletionNoResultsStyle = BaseStyle.
-					Foreground(TextMuted).
-					Italic(true).
-					Align(lipgloss.Center)
-)
+		Foreground(TextMuted).
+		Italic(true).
+		Align(lipgloss.Center)
 
-// Agent and transfer badge styles
-var (
+	// Agent and transfer badge styles
 	AgentBadgeStyle = BaseStyle.
-			Foreground(AgentBadgeFg).
-			Background(AgentBadgeBg).
-			Padding(0, 1)
+		Foreground(AgentBadgeFg).
+		Background(AgentBadgeBg).
+		Padding(0, 1)
 
 	ThinkingBadgeStyle = BaseStyle.
-				Foreground(TextMuted). // Muted blue, distinct from gray italic content
-				Bold(true).
-				Italic(true)
-)
+		Foreground(TextMuted). // Muted blue, distinct from gray italic content
+		Bold(true).
+		Italic(true)
 
-// Deprecated styles (kept for backward compatibility)
-var (
+	// Deprecated styles (kept for backward compatibility)
 	ChatStyle = BaseStyle
-)
 
-// Selection Styles
-var (
+	// Selection Styles
 	SelectionStyle = BaseStyle.
 		Background(Selected).
 		Foreground(SelectedFg)
-)
 
-// Spinner Styles
-var (
-	SpinnerDotsAccentStyle    = BaseStyle.Foreground(Accent)
+	// Search Styles
+	SearchMatchStyle = BaseStyle.
+		Background(Warning).
+		Foreground(Background)
+
+	SearchCurrentMatchStyle = BaseStyle.
+		Background(Highlight).
+		Foreground(Background).
+		Bold(true)
+
+	SearchBarStyle = BaseStyle.
+		Background(BackgroundAlt)
+
+	// Spinner Styles
+	SpinnerDotsAccentStyle = BaseStyle.Foreground(Accent)
 	SpinnerDotsHighlightStyle = BaseStyle.Foreground(TabAccentFg)
-	SpinnerTextBrightestStyle = BaseStyle.Foreground(lipgloss.Color(ColorSpinnerBrightest))
-	SpinnerTextBrightStyle    = BaseStyle.Foreground(lipgloss.Color(ColorSpinnerBright))
-	SpinnerTextDimStyle       = BaseStyle.Foreground(lipgloss.Color(ColorSpinnerDim))
-	SpinnerTextDimmestStyle   = BaseStyle.Foreground(Accent)
-)
+	SpinnerTextBrightestStyle = BaseStyle.Foreground(SpinnerBrightest)
+	SpinnerTextBrightStyle = BaseStyle.Foreground(SpinnerBright)
+	SpinnerTextDimStyle = BaseStyle.Foreground(SpinnerDim)
+	SpinnerTextDimmestStyle = BaseStyle.Foreground(Accent)
+}
 
 func toChroma(style ansi.StylePrimitive) string {
 	var s []string
@@ -614,27 +872,28 @@ func ChromaStyle() *chroma.Style {
 }
 
 func MarkdownStyle() ansi.StyleConfig {
-	h1Color := ColorAccentBlue
-	h2Color := ColorAccentBlue
-	h3Color := ColorAccentBlue
-	h4Color := ColorAccentBlue
-	h5Color := ColorAccentBlue
-	h6Color := ColorAccentBlue
-	linkColor := ColorAccentBlue
-	strongColor := ColorTextPrimary
-	codeColor := ColorTextPrimary
-	codeBgColor := ColorBackgroundAlt
-	blockquoteColor := ColorTextSecondary
-	listColor := ColorTextPrimary
-	hrColor := ColorBorderSecondary
-	codeBg := ColorBackgroundAlt
+	theme := CurrentTheme()
+	h1Color := theme.Accent
+	h2Color := theme.Accent
+	h3Color := theme.Accent
+	h4Color := theme.Accent
+	h5Color := theme.Accent
+	h6Color := theme.Accent
+	linkColor := theme.Accent
+	strongColor := theme.TextPrimary
+	codeColor := theme.TextPrimary
+	codeBgColor := theme.BackgroundAlt
+	blockquoteColor := theme.TextSecondary
+	listColor := theme.TextPrimary
+	hrColor := theme.BorderSecondary
+	codeBg := theme.BackgroundAlt
 
 	customDarkStyle := ansi.StyleConfig{
 		Document: ansi.StyleBlock{
 			StylePrimitive: ansi.StylePrimitive{
 				BlockPrefix: "",
 				BlockSuffix: "",
-				Color:       stringPtr(ANSIColor252),
+				Color:       stringPtr(theme.MarkdownText),
 			},
 			Margin: uintPtr(0),
 		},
@@ -751,7 +1010,7 @@ func MarkdownStyle() ansi.StyleConfig {
 			Theme: "monokai",
 			Chroma: &ansi.Chroma{
 				Text: ansi.StylePrimitive{
-					Color: stringPtr(ColorTextPrimary),
+					Color: stringPtr(theme.TextPrimary),
 				},
 				Error: ansi.StylePrimitive{
 					Color:           stringPtr(ChromaErrorFgColor),
@@ -782,7 +1041,7 @@ func MarkdownStyle() ansi.StyleConfig {
 					Color: stringPtr(ChromaPunctuationColor),
 				},
 				Name: ansi.StylePrimitive{
-					Color: stringPtr(ColorTextPrimary),
+					Color: stringPtr(theme.TextPrimary),
 				},
 				NameBuiltin: ansi.StylePrimitive{
 					Color: stringPtr(ChromaNameBuiltinColor),
@@ -0,0 +1,332 @@
+package styles
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/docker/cagent/pkg/paths"
+)
+
+// Built-in theme names
+const (
+	ThemeDark         = "dark"
+	ThemeLight        = "light"
+	ThemeHighContrast = "high-contrast"
+
+	// ThemeCustom is the name of the theme loaded from the user's theme.yaml
+	ThemeCustom = "custom"
+)
+
+// Theme is a color scheme for the TUI. Colors are hex values ("#RRGGBB")
+// or ANSI color codes ("252").
+type Theme struct {
+	Name string `yaml:"-"`
+
+	// Background colors
+	Background    string `yaml:"background"`
+	BackgroundAlt string `yaml:"background_alt"`
+
+	// Text hierarchy
+	TextBright    string `yaml:"text_bright"`
+	TextPrimary   string `yaml:"text_primary"`
+	TextSecondary string `yaml:"text_secondary"`
+	TextMuted     string `yaml:"text_muted"`
+	TextMutedGray string `yaml:"text_muted_gray"`
+
+	// Accent colors
+	Accent      string `yaml:"accent"`
+	Brand       string `yaml:"brand"`
+	BrandBg     string `yaml:"brand_background"`
+	TextOnBrand string `yaml:"text_on_brand"`
+
+	// Status colors
+	Success     string `yaml:"success"`
+	Error       string `yaml:"error"`
+	ErrorStrong string `yaml:"error_strong"`
+	ErrorBg     string `yaml:"error_background"`
+	Warning     string `yaml:"warning"`
+	Info        string `yaml:"info"`
+	Highlight   string `yaml:"highlight"`
+
+	// UI element colors
+	BorderSecondary string `yaml:"border_secondary"`
+	Selected        string `yaml:"selected"`
+	LineNumber      string `yaml:"line_number"`
+	Separator       string `yaml:"separator"`
+	SuggestionGhost string `yaml:"suggestion_ghost"`
+	Tab             string `yaml:"tab"`
+	DiffAddBg       string `yaml:"diff_add_background"`
+	DiffRemoveBg    string `yaml:"diff_remove_background"`
+
+	// Spinner glow colors, from the accent color towards the brightest
+	SpinnerDim       string `yaml:"spinner_dim"`
+	SpinnerBright    string `yaml:"spinner_bright"`
+	SpinnerBrightest string `yaml:"spinner_brightest"`
+
+	// Model picker badges
+	BadgePurple string `yaml:"badge_purple"`
+	BadgeCyan   string `yaml:"badge_cyan"`
+	BadgeGreen  string `yaml:"badge_green"`
+
+	// Markdown body text
+	MarkdownText string `yaml:"markdown_text"`
+}
+
+// DarkTheme is the default Tokyo Night-inspired theme.
+func DarkTheme() Theme {
+	return Theme{
+		Name:             ThemeDark,
+		Background:       ColorBackground,
+		BackgroundAlt:    ColorBackgroundAlt,
+		TextBright:       ColorWhite,
+		TextPrimary:      ColorTextPrimary,
+		TextSecondary:    ColorTextSecondary,
+		TextMuted:        ColorMutedBlue,
+		TextMutedGray:    ColorMutedGray,
+		Accent:           ColorAccentBlue,
+		Brand:            ColorMobyBlue,
+		BrandBg:          ColorDarkBlue,
+		TextOnBrand:      ColorWhite,
+		Success:          ColorSuccessGreen,
+		Error:            ColorErrorRed,
+		ErrorStrong:      ColorErrorStrong,
+		ErrorBg:          ColorErrorDark,
+		Warning:          ColorWarningYellow,
+		Info:             ColorInfoCyan,
+		Highlight:        ColorHighlight,
+		BorderSecondary:  ColorBorderSecondary,
+		Selected:         ColorSelected,
+		LineNumber:       ColorLineNumber,
+		Separator:        ColorSeparator,
+		SuggestionGhost:  ColorSuggestionGhost,
+		Tab:              ColorTab,
+		DiffAddBg:        ColorDiffAddBg,
+		DiffRemoveBg:     ColorDiffRemoveBg,
+		SpinnerDim:       ColorSpinnerDim,
+		SpinnerBright:    ColorSpinnerBright,
+		SpinnerBrightest: ColorSpinnerBrightest,
+		BadgePurple:      ColorBadgePurple,
+		BadgeCyan:        ColorBadgeCyan,
+		BadgeGreen:       ColorBadgeGreen,
+		MarkdownText:     ANSIColor252,
+	}
+}
+
+// LightTheme is a theme for terminals with a light background.
+func LightTheme() Theme {
+	return Theme{
+		Name:             ThemeLight,
+		Background:       "#F5F5F7",
+		BackgroundAlt:    "#E4E6EE",
+		TextBright:       "#1A1B26",
+		TextPrimary:      "#343B58",
+		TextSecondary:    "#5A607A",
+		TextMuted:        "#6172B0",
+		TextMutedGray:    "#6E7280",
+		Accent:           "#2E7DE9",
+		Brand:            "#1D63ED",
+		BrandBg:          "#DCE6FB",
+		TextOnBrand:      "#FFFFFF",
+		Success:          "#587539",
+		Error:            "#C64343",
+		ErrorStrong:      "#B42318",
+		ErrorBg:          "#F6DADA",
+		Warning:          "#8C6C3E",
+		Info:             "#007197",
+		Highlight:        "#387068",
+		BorderSecondary:  "#8990B3",
+		Selected:         "#B7C9F0",
+		LineNumber:       "#8990B3",
+		Separator:        "#C4C8DA",
+		SuggestionGhost:  "#A0A0A8",
+		Tab:              "#E9E9ED",
+		DiffAddBg:        "#D8EED8",
+		DiffRemoveBg:     "#F4D8D8",
+		SpinnerDim:       "#1F5FD0",
+		SpinnerBright:    "#174AB0",
+		SpinnerBrightest: "#0F3590",
+		BadgePurple:      "#7847BD",
+		BadgeCyan:        "#007197",
+		BadgeGreen:       "#587539",
+		MarkdownText:     "#343B58",
+	}
+}
+
+// HighContrastTheme maximizes contrast for accessibility.
+func HighContrastTheme() Theme {
+	return Theme{
+		Name:             ThemeHighContrast,
+		Background:       "#000000",
+		BackgroundAlt:    "#1A1A1A",
+		TextBright:       "#FFFFFF",
+		TextPrimary:      "#FFFFFF",
+		TextSecondary:    "#E0E0E0",
+		TextMuted:        "#C0C8FF",
+		TextMutedGray:    "#BDBDBD",
+		Accent:           "#00BFFF",
+		Brand:            "#0050FF",
+		BrandBg:          "#000080",
+		TextOnBrand:      "#FFFFFF",
+		Success:          "#00FF00",
+		Error:            "#FF3030",
+		ErrorStrong:      "#FF0000",
+		ErrorBg:          "#400000",
+		Warning:          "#FFFF00",
+		Info:             "#00FFFF",
+		Highlight:        "#ADFF2F",
+		BorderSecondary:  "#FFFFFF",
+		Selected:         "#0050FF",
+		LineNumber:       "#BDBDBD",
+		Separator:        "#808080",
+		SuggestionGhost:  "#9E9E9E",
+		Tab:              "#1A1A1A",
+		DiffAddBg:        "#003300",
+		DiffRemoveBg:     "#4D0000",
+		SpinnerDim:       "#66D9FF",
+		SpinnerBright:    "#99E6FF",
+		SpinnerBrightest: "#CCF2FF",
+		BadgePurple:      "#D7AFFF",
+		BadgeCyan:        "#00FFFF",
+		BadgeGreen:       "#00FF00",
+		MarkdownText:     "#FFFFFF",
+	}
+}
+
+var presets = map[string]func() Theme{
+	ThemeDark:         DarkTheme,
+	ThemeLight:        LightTheme,
+	ThemeHighContrast: HighContrastTheme,
+}
+
+var (
+	themeMu      sync.Mutex
+	currentTheme = applyTheme(DarkTheme())
+	userTheme    *Theme
+)
+
+// UserThemePath returns the path to the user's theme file.
+func UserThemePath() string {
+	return filepath.Join(paths.GetConfigDir(), "theme.yaml")
+}
+
+// LoadTheme reads a theme file. The file can start from a built-in theme
+// with `preset: <name>` (dark by default) and override any of its colors:
+//
+//	preset: light
+//	accent: "#FF8800"
+//	success: "#00AA00"
+func LoadTheme(path string) (Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Theme{}, err
+	}
+
+	var header struct {
+		Preset string `yaml:"preset"`
+	}
+	if err := yaml.Unmarshal(data, &header); err != nil {
+		return Theme{}, fmt.Errorf("parsing theme %s: %w", path, err)
+	}
+
+	preset := header.Preset
+	if preset == "" {
+		preset = ThemeDark
+	}
+	newTheme, ok := presets[preset]
+	if !ok {
+		return Theme{}, fmt.Errorf("unknown theme preset %q, must be one of %v", preset, presetNames())
+	}
+
+	// Colors missing from the file keep the preset's values.
+	theme := newTheme()
+	if err := yaml.Unmarshal(data, &theme); err != nil {
+		return Theme{}, fmt.Errorf("parsing theme %s: %w", path, err)
+	}
+	theme.Name = ThemeCustom
+
+	return theme, nil
+}
+
+// LoadUserTheme loads and applies ~/.config/cagent/theme.yaml, if it exists.
+func LoadUserTheme() error {
+	theme, err := LoadTheme(UserThemePath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	themeMu.Lock()
+	userTheme = &theme
+	themeMu.Unlock()
+
+	ApplyTheme(theme)
+	return nil
+}
+
+// ThemeNames returns the names of the themes that can be switched to.
+func ThemeNames() []string {
+	names := presetNames()
+
+	themeMu.Lock()
+	defer themeMu.Unlock()
+	if userTheme != nil {
+		names = append(names, ThemeCustom)
+	}
+	return names
+}
+
+// ThemeByName returns a built-in theme, or the user's custom theme.
+func ThemeByName(name string) (Theme, bool) {
+	if newTheme, ok := presets[name]; ok {
+		return newTheme(), true
+	}
+
+	themeMu.Lock()
+	defer themeMu.Unlock()
+	if name == ThemeCustom && userTheme != nil {
+		return *userTheme, true
+	}
+	return Theme{}, false
+}
+
+// NextTheme returns the name of the theme following the current one.
+func NextTheme() string {
+	names := ThemeNames()
+	i := slices.Index(names, CurrentTheme().Name)
+	return names[(i+1)%len(names)]
+}
+
+// CurrentTheme returns the theme in use.
+func CurrentTheme() Theme {
+	themeMu.Lock()
+	defer themeMu.Unlock()
+	return currentTheme
+}
+
+// ApplyTheme switches every color and style of the package to the given theme.
+// Components read styles when they render, so the next render uses the new
+// colors. Components caching rendered output must drop their cache.
+func ApplyTheme(theme Theme) {
+	themeMu.Lock()
+	defer themeMu.Unlock()
+
+	currentTheme = applyTheme(theme)
+}
+
+func applyTheme(theme Theme) Theme {
+	applyColors(theme)
+	buildStyles()
+	return theme
+}
+
+func presetNames() []string {
+	return []string{ThemeDark, ThemeLight, ThemeHighContrast}
+}
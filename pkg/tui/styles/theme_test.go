@@ -0,0 +1,70 @@
+package styles
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"charm.land/lipgloss/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadTheme_OverridesPreset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "theme.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("preset: light\naccent: \"#FF8800\"\n"), 0o644))
+
+	theme, err := LoadTheme(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, ThemeCustom, theme.Name)
+	assert.Equal(t, "#FF8800", theme.Accent)
+	assert.Equal(t, LightTheme().Background, theme.Background)
+}
+
+func TestLoadTheme_DefaultsToDark(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "theme.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("success: \"#00FF00\"\n"), 0o644))
+
+	theme, err := LoadTheme(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "#00FF00", theme.Success)
+	assert.Equal(t, ColorBackground, theme.Background)
+}
+
+func TestLoadTheme_UnknownPreset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "theme.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("preset: solarized\n"), 0o644))
+
+	_, err := LoadTheme(path)
+	require.ErrorContains(t, err, "unknown theme preset")
+}
+
+func TestLoadUserTheme_MissingFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	require.NoError(t, LoadUserTheme())
+	assert.Equal(t, []string{ThemeDark, ThemeLight, ThemeHighContrast}, ThemeNames())
+}
+
+func TestApplyTheme_RebuildsStyles(t *testing.T) {
+	t.Cleanup(func() { ApplyTheme(DarkTheme()) })
+
+	ApplyTheme(HighContrastTheme())
+	assert.Equal(t, ThemeHighContrast, CurrentTheme().Name)
+	assert.Equal(t, lipgloss.Color("#FF3030"), ErrorStyle.GetForeground())
+	assert.Equal(t, "#FFFFFF", *MarkdownStyle().Document.Color)
+
+	ApplyTheme(DarkTheme())
+	assert.Equal(t, lipgloss.Color(ColorErrorRed), ErrorStyle.GetForeground())
+}
+
+func TestNextTheme(t *testing.T) {
+	t.Cleanup(func() { ApplyTheme(DarkTheme()) })
+
+	assert.Equal(t, ThemeLight, NextTheme())
+
+	ApplyTheme(HighContrastTheme())
+	assert.Equal(t, ThemeDark, NextTheme())
+}
@@ -3,9 +3,11 @@ package tui
 import (
 	"cmp"
 	"context"
+	"log/slog"
 	"os"
 	"os/exec"
 	goruntime "runtime"
+	"time"
 
 	"charm.land/bubbles/v2/help"
 	"charm.land/bubbles/v2/key"
@@ -15,19 +17,24 @@ import (
 	"github.com/docker/cagent/pkg/app"
 	"github.com/docker/cagent/pkg/audio/transcribe"
 	"github.com/docker/cagent/pkg/cli"
+	"github.com/docker/cagent/pkg/notifications"
 	"github.com/docker/cagent/pkg/runtime"
 	"github.com/docker/cagent/pkg/session"
 	"github.com/docker/cagent/pkg/tui/commands"
 	"github.com/docker/cagent/pkg/tui/components/completion"
 	"github.com/docker/cagent/pkg/tui/components/editor"
+	"github.com/docker/cagent/pkg/tui/components/logview"
 	"github.com/docker/cagent/pkg/tui/components/notification"
+	"github.com/docker/cagent/pkg/tui/components/sidebar"
 	"github.com/docker/cagent/pkg/tui/components/statusbar"
 	"github.com/docker/cagent/pkg/tui/core"
 	"github.com/docker/cagent/pkg/tui/dialog"
+	"github.com/docker/cagent/pkg/tui/keys"
 	"github.com/docker/cagent/pkg/tui/messages"
 	"github.com/docker/cagent/pkg/tui/page/chat"
 	"github.com/docker/cagent/pkg/tui/service"
 	"github.com/docker/cagent/pkg/tui/styles"
+	"github.com/docker/cagent/pkg/userconfig"
 )
 
 // appModel represents the main application model
@@ -38,6 +45,7 @@ type appModel struct {
 	keyMap          KeyMap
 
 	chatPage  chat.Page
+	status    statusbar.Status
 	statusBar statusbar.StatusBar
 
 	notification notification.Manager
@@ -55,11 +63,50 @@ type appModel struct {
 	// Speech-to-text transcriber
 	transcriber *transcribe.Transcriber
 
+	// Extra sidebar options (custom widgets...) applied to every chat page
+	sidebarOpts []sidebar.Option
+
+	// Notifications of runtime events while the terminal isn't focused.
+	// Terminals that don't report focus changes are always focused.
+	notifier   *notifications.Notifier
+	focused    bool
+	runDepth   int
+	runStarted time.Time
+
+	// Open the session browser on startup
+	pickSession bool
+
 	// State
 	ready bool
 	err   error
 }
 
+// Opt is an option for creating a new TUI.
+type Opt func(*appModel)
+
+// WithSidebarWidgets registers custom widgets rendered below the built-in sidebar sections.
+func WithSidebarWidgets(widgets ...sidebar.Widget) Opt {
+	return func(a *appModel) {
+		a.sidebarOpts = append(a.sidebarOpts, sidebar.WithWidgets(widgets...))
+	}
+}
+
+// WithSessionPicker opens the session browser on startup, to pick the session
+// to continue.
+func WithSessionPicker() Opt {
+	return func(a *appModel) {
+		a.pickSession = true
+	}
+}
+
+// WithSubAgentsHidden hides what the sub-agents say and do in the
+// conversation.
+func WithSubAgentsHidden() Opt {
+	return func(a *appModel) {
+		a.sessionState.HideSubAgents = true
+	}
+}
+
 // KeyMap defines global key bindings
 type KeyMap struct {
 	Quit                  key.Binding
@@ -68,52 +115,44 @@ type KeyMap struct {
 	ToggleHideToolResults key.Binding
 	SwitchAgent           key.Binding
 	ModelPicker           key.Binding
+	Sessions              key.Binding
 	Speak                 key.Binding
 	ClearQueue            key.Binding
 }
 
-// DefaultKeyMap returns the default global key bindings
+// DefaultKeyMap returns the global key bindings, with the keys set by the user
 func DefaultKeyMap() KeyMap {
 	return KeyMap{
-		Quit: key.NewBinding(
-			key.WithKeys("ctrl+c"),
-			key.WithHelp("Ctrl+c", "quit"),
-		),
-		CommandPalette: key.NewBinding(
-			key.WithKeys("ctrl+p"),
-			key.WithHelp("Ctrl+p", "commands"),
-		),
-		ToggleYolo: key.NewBinding(
-			key.WithKeys("ctrl+y"),
-			key.WithHelp("Ctrl+y", "toggle yolo mode"),
-		),
-		ToggleHideToolResults: key.NewBinding(
-			key.WithKeys("ctrl+o"),
-			key.WithHelp("Ctrl+o", "toggle tool output"),
-		),
-		SwitchAgent: key.NewBinding(
-			key.WithKeys("ctrl+s"),
-			key.WithHelp("Ctrl+s", "cycle agent"),
-		),
-		ModelPicker: key.NewBinding(
-			key.WithKeys("ctrl+m"),
-			key.WithHelp("Ctrl+m", "models"),
-		),
-		Speak: key.NewBinding(
-			key.WithKeys("ctrl+k"),
-			key.WithHelp("Ctrl+k", "speak"),
-		),
-		ClearQueue: key.NewBinding(
-			key.WithKeys("ctrl+x"),
-			key.WithHelp("Ctrl+x", "clear queue"),
-		),
+		Quit:                  keys.Get(keys.Quit),
+		CommandPalette:        keys.Get(keys.CommandPalette),
+		ToggleYolo:            keys.Get(keys.ToggleYolo),
+		ToggleHideToolResults: keys.Get(keys.ToggleHideToolResults),
+		SwitchAgent:           keys.Get(keys.SwitchAgent),
+		ModelPicker:           keys.Get(keys.ModelPicker),
+		Sessions:              keys.Get(keys.Sessions),
+		Speak:                 keys.Get(keys.Speak),
+		ClearQueue:            keys.Get(keys.ClearQueue),
 	}
 }
 
 // New creates and initializes a new TUI application model
-func New(ctx context.Context, a *app.App) tea.Model {
+func New(ctx context.Context, a *app.App, opts ...Opt) tea.Model {
 	sessionState := service.NewSessionState(a.Session())
 
+	// Key bindings are looked up when the components are created, overrides
+	// must be set before.
+	var notificationSettings map[string]notifications.Settings
+	if cfg, err := userconfig.Load(); err == nil {
+		if err := keys.SetOverrides(cfg.Keybindings); err != nil {
+			slog.Warn("Ignoring keybindings", "error", err)
+		}
+		notificationSettings = cfg.Notifications
+	}
+	notifier, err := notifications.New(notificationSettings)
+	if err != nil {
+		slog.Warn("Ignoring notifications", "error", err)
+	}
+
 	t := &appModel{
 		keyMap:       DefaultKeyMap(),
 		dialog:       dialog.New(),
@@ -122,10 +161,18 @@ func New(ctx context.Context, a *app.App) tea.Model {
 		application:  a,
 		sessionState: sessionState,
 		transcriber:  transcribe.New(os.Getenv("OPENAI_API_KEY")), // TODO(dga): should use envProvider
+		notifier:     notifier,
+		focused:      true,
+	}
+
+	for _, opt := range opts {
+		opt(t)
 	}
 
+	_, remote := a.Runtime().(*runtime.RemoteRuntime)
+	t.status = statusbar.NewStatus(sessionState, remote)
 	t.statusBar = statusbar.New(t)
-	t.chatPage = chat.New(a, sessionState)
+	t.chatPage = chat.New(a, sessionState, t.sidebarOpts...)
 
 	// Make sure to stop the progress bar when the app quits abruptly.
 	go func() {
@@ -143,6 +190,12 @@ func (a *appModel) Init() tea.Cmd {
 		a.chatPage.Init(),
 	}
 
+	// Init runs again when another session is loaded, pick only once
+	if a.pickSession {
+		a.pickSession = false
+		cmds = append(cmds, core.CmdHandler(messages.OpenSessionBrowserMsg{}))
+	}
+
 	if firstMessage := a.application.FirstMessage(); firstMessage != nil {
 		cmds = append(cmds, func() tea.Msg {
 			// Use the shared PrepareUserMessage function for consistent attachment handling
@@ -184,6 +237,10 @@ func (a *appModel) Bindings() []key.Binding {
 
 // Update handles incoming messages and updates the application state
 func (a *appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, isRuntimeEvent := msg.(runtime.Event); isRuntimeEvent {
+		a.status.Update(msg)
+	}
+
 	switch msg := msg.(type) {
 	// Handle dialog-specific messages first
 	case dialog.OpenDialogMsg, dialog.CloseDialogMsg:
@@ -219,6 +276,9 @@ func (a *appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return a, cmd
 
 	case messages.SwitchAgentMsg:
+		if msg.AgentName == "" {
+			return a.cycleToNextAgent()
+		}
 		return a.handleSwitchAgent(msg.AgentName)
 
 	case tea.WindowSizeMsg:
@@ -227,6 +287,14 @@ func (a *appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.completions.Update(msg)
 		return a, cmd
 
+	case tea.FocusMsg:
+		a.focused = true
+		return a, nil
+
+	case tea.BlurMsg:
+		a.focused = false
+		return a, nil
+
 	case tea.KeyboardEnhancementsMsg:
 		updated, cmd := a.chatPage.Update(msg)
 		a.chatPage = updated.(chat.Page)
@@ -266,6 +334,21 @@ func (a *appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case messages.LoadSessionMsg:
 		return a.handleLoadSession(msg.SessionID)
 
+	case messages.ResumeSessionMsg:
+		return a.handleResumeSession(msg.SessionID)
+
+	case messages.RenameSessionMsg:
+		return a.handleRenameSession(msg.SessionID, msg.Title)
+
+	case messages.DeleteSessionMsg:
+		return a.handleDeleteSession(msg.SessionID)
+
+	case messages.ShowRewindMsg:
+		return a.handleShowRewind(msg.SessionID)
+
+	case messages.RewindSessionMsg:
+		return a.handleRewindSession(msg.SessionID, msg.Checkpoint)
+
 	case messages.ToggleSessionStarMsg:
 		sessionID := msg.SessionID
 		if sessionID == "" {
@@ -290,19 +373,34 @@ func (a *appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case messages.CompactSessionMsg:
 		return a.handleCompactSession(msg.AdditionalPrompt)
 
+	case messages.PostMortemMsg:
+		return a, a.chatPage.WritePostMortem()
+
 	case messages.CopySessionToClipboardMsg:
 		return a.handleCopySessionToClipboard()
 
 	case messages.CopyLastResponseToClipboardMsg:
 		return a.handleCopyLastResponseToClipboard()
 
+	case messages.CopyLastCodeBlockToClipboardMsg:
+		return a.handleCopyLastCodeBlockToClipboard()
+
 	case messages.ToggleYoloMsg:
 		return a.handleToggleYolo()
 
 	case messages.ToggleHideToolResultsMsg:
 		return a.handleToggleHideToolResults()
 
-	case messages.ClearQueueMsg:
+	case messages.ToggleRawMarkdownMsg:
+		return a.handleToggleRawMarkdown()
+
+	case messages.ToggleExpandToolCallsMsg:
+		return a.handleToggleExpandToolCalls()
+
+	case messages.ToggleHideSubAgentsMsg:
+		return a.handleToggleHideSubAgents()
+
+	case messages.ClearQueueMsg, messages.ToggleSidebarMsg, messages.ToggleSplitViewMsg, messages.ToggleLogsMsg, logview.TickMsg:
 		updated, cmd := a.chatPage.Update(msg)
 		a.chatPage = updated.(chat.Page)
 		return a, cmd
@@ -310,6 +408,15 @@ func (a *appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case messages.ShowCostDialogMsg:
 		return a.handleShowCostDialog()
 
+	case messages.ShowHelpMsg:
+		return a, a.handleShowHelp()
+
+	case messages.ReloadToolsMsg:
+		return a, a.handleReloadTools()
+
+	case messages.ChangeThemeMsg:
+		return a.handleChangeTheme(msg.Name)
+
 	case messages.AgentCommandMsg:
 		return a.handleAgentCommand(msg.Command)
 
@@ -319,6 +426,14 @@ func (a *appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case messages.MCPPromptMsg:
 		return a.handleMCPPrompt(msg.PromptName, msg.Arguments)
 
+	case messages.ShowFeedbackInputMsg:
+		return a, core.CmdHandler(dialog.OpenDialogMsg{
+			Model: dialog.NewFeedbackDialog(msg.AgentName, msg.Answer, msg.Rating),
+		})
+
+	case messages.FeedbackMsg:
+		return a.handleFeedback(msg)
+
 	case messages.OpenURLMsg:
 		return a.handleOpenURL(msg.URL)
 
@@ -359,7 +474,7 @@ func (a *appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return a, tea.Quit
 
 	case chat.EditorHeightChangedMsg:
-		a.completions.SetEditorBottom(msg.Height)
+		a.completions.SetEditorBottom(msg.Height + 1) // The status line is below the editor
 		return a, nil
 
 	case firstMessageWithAttachment:
@@ -374,9 +489,10 @@ func (a *appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	default:
 		if _, isRuntimeEvent := msg.(runtime.Event); isRuntimeEvent {
 			// Always forward runtime events to chat page
+			notifyCmd := a.notifyRuntimeEvent(msg)
 			updated, cmd := a.chatPage.Update(msg)
 			a.chatPage = updated.(chat.Page)
-			return a, cmd
+			return a, tea.Batch(cmd, notifyCmd)
 		}
 
 		// For other messages, check if dialogs should handle them first
@@ -402,7 +518,7 @@ func (a *appModel) handleWindowResize(width, height int) tea.Cmd {
 	var cmds []tea.Cmd
 
 	// Update dimensions
-	a.width, a.height = width, height-1 // Account for status bar
+	a.width, a.height = width, height-2 // Account for status line and status bar
 
 	if !a.ready {
 		a.ready = true
@@ -417,9 +533,10 @@ func (a *appModel) handleWindowResize(width, height int) tea.Cmd {
 	cmds = append(cmds, cmd)
 
 	// Update completion manager with actual editor height for popup positioning
-	a.completions.SetEditorBottom(a.chatPage.GetInputHeight())
+	a.completions.SetEditorBottom(a.chatPage.GetInputHeight() + 1) // The status line is below the editor
 
-	// Update status bar width
+	// Update status line and status bar width
+	a.status.SetWidth(a.width)
 	a.statusBar.SetWidth(a.width)
 
 	// Update notification size
@@ -478,7 +595,7 @@ func (a *appModel) handleKeyPressMsg(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		})
 
 	case key.Matches(msg, a.keyMap.CommandPalette):
-		categories := commands.BuildCommandCategories(context.Background(), a.application)
+		categories := commands.ExpandArguments(commands.BuildCommandCategories(context.Background(), a.application), a.application)
 		return a, core.CmdHandler(dialog.OpenDialogMsg{
 			Model: dialog.NewCommandPaletteDialog(categories),
 		})
@@ -496,6 +613,9 @@ func (a *appModel) handleKeyPressMsg(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 	case key.Matches(msg, a.keyMap.ModelPicker):
 		return a.handleOpenModelPicker()
 
+	case key.Matches(msg, a.keyMap.Sessions):
+		return a.handleOpenSessionBrowser()
+
 	case key.Matches(msg, a.keyMap.Speak):
 		if a.transcriber.IsSupported() {
 			return a.handleStartSpeak()
@@ -558,6 +678,8 @@ func (a *appModel) cycleToNextAgent() (tea.Model, tea.Cmd) {
 
 // View renders the complete application interface
 func (a *appModel) View() tea.View {
+	defer core.TrackRender("app")()
+
 	windowTitle := a.windowTitle()
 
 	// Show error if present
@@ -582,9 +704,9 @@ func (a *appModel) View() tea.View {
 	// Create status bar
 	statusBar := a.statusBar.View()
 
-	// Combine page view with status bar
+	// Combine page view with status line and status bar
 	var components []string
-	components = append(components, pageView)
+	components = append(components, pageView, a.status.View())
 	if statusBar != "" {
 		components = append(components, statusBar)
 	}
@@ -661,6 +783,7 @@ func toFullscreenView(content, windowTitle string) tea.View {
 	view := tea.NewView(content)
 	view.AltScreen = true
 	view.MouseMode = tea.MouseModeCellMotion
+	view.ReportFocus = true
 	view.BackgroundColor = styles.Background
 	view.WindowTitle = windowTitle
 
@@ -2,6 +2,7 @@ package types
 
 import (
 	"strings"
+	"time"
 
 	"github.com/docker/cagent/pkg/tools"
 )
@@ -43,6 +44,21 @@ type Message struct {
 	ToolDefinition tools.Tool            // Definition of the tool being called
 	ToolStatus     ToolStatus            // Status for tool calls
 	ToolResult     *tools.ToolCallResult // Result of tool call (when completed)
+	ToolStartedAt  time.Time             // When the tool call started running
+	ToolDuration   time.Duration         // How long the tool call ran (when completed)
+	Expanded       bool                  // Whether a finished tool call shows its full arguments and result
+	Images         []Image               // Images returned by a tool call
+	ContextSummary string                // Summary of a large tool result, sent to the model instead of the raw output
+	Feedback       string                // Rating the user gave to an answer, see session.FeedbackPositive
+	Depth          int                   // How deep in task transfers the agent that sent the message is
+}
+
+// Image is an image returned by a tool call, with its dimensions decoded
+// once, when it is loaded.
+type Image struct {
+	tools.Image
+	Width  int // Zero if the image couldn't be decoded
+	Height int
 }
 
 func Agent(typ MessageType, agentName, content string) *Message {
@@ -103,6 +119,11 @@ func ToolCallMessage(agentName string, toolCall tools.ToolCall, toolDef tools.To
 	}
 }
 
+// IsFinishedToolCall returns true for tool calls that completed or failed.
+func (m *Message) IsFinishedToolCall() bool {
+	return m.Type == MessageTypeToolCall && (m.ToolStatus == ToolStatusCompleted || m.ToolStatus == ToolStatusError)
+}
+
 func Loading(description string) *Message {
 	return &Message{
 		Type:    MessageTypeLoading,
@@ -0,0 +1,209 @@
+// Package tuning proposes edits to the instruction of an agent from the
+// feedback users gave on its answers.
+package tuning
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/aymanbagabas/go-udiff"
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/model/provider"
+	"github.com/docker/cagent/pkg/session"
+)
+
+const (
+	systemPrompt = "You are an expert at writing the instructions of AI agents. You improve instructions from the feedback users gave on the answers of the agent, keeping what works."
+	userPrompt   = `Here is the instruction of an AI agent:
+
+<instruction>
+%s
+</instruction>
+
+Users rated answers of the agent. Here is their feedback:
+
+%s
+
+Rewrite the instruction so that the agent keeps doing what users liked and stops doing what they disliked:
+- Make concrete, targeted edits, each justified by the feedback. Don't rewrite what isn't concerned.
+- Generalize from the feedback instead of adding rules for single requests.
+- Keep the style, structure and language of the instruction.
+
+Return ONLY the new instruction, without <instruction> tags or any comment.`
+
+	// maxFeedbackEntryLength caps the size of the requests and answers sent
+	// with the feedback.
+	maxFeedbackEntryLength = 2000
+)
+
+// Collect returns the feedback users gave on the answers of an agent, in the
+// given sessions.
+func Collect(sessions []*session.Session, agentName string) []session.Feedback {
+	var feedback []session.Feedback
+	for _, sess := range sessions {
+		for _, f := range sess.Feedback {
+			if f.AgentName == agentName {
+				feedback = append(feedback, f)
+			}
+		}
+	}
+	return feedback
+}
+
+// Propose asks a model for the instruction of an agent, edited to address the
+// feedback.
+func Propose(ctx context.Context, model provider.Provider, instruction string, feedback []session.Feedback) (string, error) {
+	messages := []chat.Message{
+		{Role: chat.MessageRoleSystem, Content: systemPrompt},
+		{Role: chat.MessageRoleUser, Content: fmt.Sprintf(userPrompt, instruction, formatFeedback(feedback))},
+	}
+
+	stream, err := model.CreateChatCompletionStream(ctx, messages, nil)
+	if err != nil {
+		return "", fmt.Errorf("creating chat completion: %w", err)
+	}
+	defer stream.Close()
+
+	var proposal strings.Builder
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("receiving the proposal: %w", err)
+		}
+		for _, choice := range resp.Choices {
+			proposal.WriteString(choice.Delta.Content)
+		}
+	}
+
+	proposed := strings.TrimSpace(proposal.String())
+	if proposed == "" {
+		return "", errors.New("the model proposed an empty instruction")
+	}
+	return proposed, nil
+}
+
+func formatFeedback(feedback []session.Feedback) string {
+	var builder strings.Builder
+	for i, f := range feedback {
+		fmt.Fprintf(&builder, "<feedback rating=%q>\n", f.Rating)
+		if f.Request != "" {
+			fmt.Fprintf(&builder, "Request: %s\n", truncate(f.Request))
+		}
+		fmt.Fprintf(&builder, "Answer: %s\n", truncate(f.Answer))
+		if f.Comment != "" {
+			fmt.Fprintf(&builder, "Comment: %s\n", f.Comment)
+		}
+		builder.WriteString("</feedback>")
+		if i < len(feedback)-1 {
+			builder.WriteString("\n\n")
+		}
+	}
+	return builder.String()
+}
+
+func truncate(s string) string {
+	runes := []rune(s)
+	if len(runes) <= maxFeedbackEntryLength {
+		return s
+	}
+	return string(runes[:maxFeedbackEntryLength]) + "[...]"
+}
+
+// Instruction returns the instruction of an agent in a configuration file,
+// "" when it has none.
+func Instruction(config []byte, agentName string) (string, error) {
+	path, err := yaml.PathString("$.agents." + agentName + ".instruction")
+	if err != nil {
+		return "", err
+	}
+
+	var instruction string
+	if err := path.Read(bytes.NewReader(config), &instruction); err != nil && !yaml.IsNotFoundNodeError(err) {
+		return "", fmt.Errorf("reading the instruction of agent %s: %w", agentName, err)
+	}
+	return instruction, nil
+}
+
+// ReplaceInstruction returns a configuration file with the instruction of an
+// agent replaced by a literal block. Only the lines of the instruction change,
+// so that the diff shows nothing else.
+func ReplaceInstruction(config []byte, agentName, instruction string) ([]byte, error) {
+	file, err := parser.ParseBytes(config, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	agentPath, err := yaml.PathString("$.agents." + agentName)
+	if err != nil {
+		return nil, err
+	}
+	agentNode, err := agentPath.FilterFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("finding agent %s: %w", agentName, err)
+	}
+	mapping, ok := agentNode.(*ast.MappingNode)
+	if !ok || len(mapping.Values) == 0 {
+		return nil, fmt.Errorf("agent %s is not a mapping", agentName)
+	}
+
+	// Without an instruction, one is added before the first key of the agent
+	key := mapping.Values[0].Key.GetToken().Position
+	replaced := false
+	for _, value := range mapping.Values {
+		if value.Key.String() == "instruction" {
+			key = value.Key.GetToken().Position
+			replaced = true
+			break
+		}
+	}
+
+	lines := strings.Split(string(config), "\n")
+	start := key.Line - 1
+	end := start
+	if replaced {
+		// The value spans the lines indented deeper than its key
+		end = start + 1
+		for end < len(lines) && (strings.TrimSpace(lines[end]) == "" || indentation(lines[end]) >= key.Column) {
+			end++
+		}
+		for end > start+1 && strings.TrimSpace(lines[end-1]) == "" {
+			end--
+		}
+	}
+
+	indent := strings.Repeat(" ", key.Column-1)
+	block := []string{indent + "instruction: |"}
+	for line := range strings.SplitSeq(strings.TrimRight(instruction, "\n"), "\n") {
+		if strings.TrimSpace(line) == "" {
+			block = append(block, "")
+			continue
+		}
+		block = append(block, indent+"  "+line)
+	}
+
+	edited := slices.Concat(lines[:start], block, lines[end:])
+	return []byte(strings.Join(edited, "\n")), nil
+}
+
+// indentation returns the number of spaces a line starts with.
+func indentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// Diff returns the unified diff between two versions of a configuration
+// file, or "" when they're the same.
+func Diff(filename string, before, after []byte) string {
+	return udiff.Unified("a/"+filename, "b/"+filename, string(before), string(after))
+}
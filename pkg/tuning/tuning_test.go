@@ -0,0 +1,152 @@
+package tuning
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/chat"
+	"github.com/docker/cagent/pkg/model/provider/base"
+	"github.com/docker/cagent/pkg/session"
+	"github.com/docker/cagent/pkg/tools"
+)
+
+const config = `# Support team
+agents:
+  root:
+    model: openai/gpt-4o  # fast enough
+    instruction: |
+      You answer support questions.
+
+      Be polite.
+    toolsets:
+      - type: shell
+
+  writer:
+    model: openai/gpt-4o
+`
+
+func TestInstruction(t *testing.T) {
+	t.Parallel()
+
+	instruction, err := Instruction([]byte(config), "root")
+	require.NoError(t, err)
+	assert.Equal(t, "You answer support questions.\n\nBe polite.", instruction)
+
+	instruction, err = Instruction([]byte(config), "writer")
+	require.NoError(t, err)
+	assert.Empty(t, instruction)
+}
+
+func TestReplaceInstruction(t *testing.T) {
+	t.Parallel()
+
+	edited, err := ReplaceInstruction([]byte(config), "root", "You answer support questions.\n\nBe polite and cite the docs.")
+	require.NoError(t, err)
+	assert.Equal(t, `# Support team
+agents:
+  root:
+    model: openai/gpt-4o  # fast enough
+    instruction: |
+      You answer support questions.
+
+      Be polite and cite the docs.
+    toolsets:
+      - type: shell
+
+  writer:
+    model: openai/gpt-4o
+`, string(edited))
+
+	diff := Diff("agent.yaml", []byte(config), edited)
+	assert.True(t, strings.HasPrefix(diff, "--- a/agent.yaml\n+++ b/agent.yaml\n@@ -5,7 +5,7 @@\n"))
+	assert.Contains(t, diff, "-      Be polite.\n+      Be polite and cite the docs.\n")
+}
+
+func TestReplaceInstruction_Added(t *testing.T) {
+	t.Parallel()
+
+	edited, err := ReplaceInstruction([]byte(config), "writer", "You write the answers.")
+	require.NoError(t, err)
+	assert.Contains(t, string(edited), `
+  writer:
+    instruction: |
+      You write the answers.
+    model: openai/gpt-4o
+`)
+}
+
+func TestReplaceInstruction_UnknownAgent(t *testing.T) {
+	t.Parallel()
+
+	_, err := ReplaceInstruction([]byte(config), "reviewer", "Review.")
+	require.Error(t, err)
+}
+
+func TestCollect(t *testing.T) {
+	t.Parallel()
+
+	first := session.New()
+	first.Feedback = []session.Feedback{{AgentName: "root", Rating: session.FeedbackPositive}, {AgentName: "writer", Rating: session.FeedbackNegative}}
+	second := session.New()
+	second.Feedback = []session.Feedback{{AgentName: "root", Rating: session.FeedbackNegative, Comment: "Wrong"}}
+
+	feedback := Collect([]*session.Session{first, second}, "root")
+	require.Len(t, feedback, 2)
+	assert.Equal(t, "Wrong", feedback[1].Comment)
+}
+
+type replyProvider struct {
+	reply    string
+	messages []chat.Message
+}
+
+func (p *replyProvider) ID() string { return "test/model" }
+
+func (p *replyProvider) CreateChatCompletionStream(_ context.Context, messages []chat.Message, _ []tools.Tool) (chat.MessageStream, error) {
+	p.messages = messages
+	return &replyStream{reply: p.reply}, nil
+}
+
+func (p *replyProvider) BaseConfig() base.Config { return base.Config{} }
+
+type replyStream struct {
+	reply string
+	sent  bool
+}
+
+func (s *replyStream) Recv() (chat.MessageStreamResponse, error) {
+	if s.sent {
+		return chat.MessageStreamResponse{}, io.EOF
+	}
+	s.sent = true
+	return chat.MessageStreamResponse{Choices: []chat.MessageStreamChoice{{Delta: chat.MessageDelta{Content: s.reply}}}}, nil
+}
+
+func (s *replyStream) Close() {}
+
+func TestPropose(t *testing.T) {
+	t.Parallel()
+
+	model := &replyProvider{reply: "\nBe polite and cite the docs.\n"}
+	feedback := []session.Feedback{{
+		AgentName: "root",
+		Rating:    session.FeedbackNegative,
+		Comment:   "No sources",
+		Request:   "How do I reset my password?",
+		Answer:    "Click reset.",
+	}}
+
+	proposed, err := Propose(t.Context(), model, "Be polite.", feedback)
+	require.NoError(t, err)
+	assert.Equal(t, "Be polite and cite the docs.", proposed)
+
+	prompt := model.messages[len(model.messages)-1].Content
+	assert.Contains(t, prompt, "Be polite.")
+	assert.Contains(t, prompt, `<feedback rating="negative">`)
+	assert.Contains(t, prompt, "Comment: No sources")
+}
@@ -11,10 +11,13 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"sync"
 
 	"github.com/goccy/go-yaml"
 	"github.com/natefinch/atomic"
 
+	"github.com/docker/cagent/pkg/notifications"
 	"github.com/docker/cagent/pkg/paths"
 )
 
@@ -44,6 +47,20 @@ type Config struct {
 	ModelsGateway string `yaml:"models_gateway,omitempty"`
 	// Aliases maps alias names to alias configurations
 	Aliases map[string]*Alias `yaml:"aliases,omitempty"`
+	// HistorySize is the number of prompts kept in the prompt history
+	HistorySize int `yaml:"history_size,omitempty"`
+	// Keybindings maps TUI key binding IDs to the keys that trigger them
+	Keybindings map[string][]string `yaml:"keybindings,omitempty"`
+	// Notifications configures, by event type, how the TUI notifies the user
+	// when the terminal isn't focused
+	Notifications map[string]notifications.Settings `yaml:"notifications,omitempty"`
+	// AlwaysAllowedTools maps agent names to the tools they may call without
+	// asking the user
+	AlwaysAllowedTools map[string][]string `yaml:"always_allowed_tools,omitempty"`
+	// Features enables or disables experimental features, by name
+	Features map[string]bool `yaml:"features,omitempty"`
+	// VimMode enables the vim-style modal navigation of the TUI
+	VimMode bool `yaml:"vim_mode,omitempty"`
 }
 
 // Path returns the path to the config file
@@ -206,3 +223,70 @@ func (c *Config) DeleteAlias(name string) bool {
 	}
 	return false
 }
+
+// IsToolAlwaysAllowed reports whether the agent may call the tool without
+// asking the user.
+func (c *Config) IsToolAlwaysAllowed(agentName, toolName string) bool {
+	return slices.Contains(c.AlwaysAllowedTools[agentName], toolName)
+}
+
+// AlwaysAllowTool lets the agent call the tool without asking the user.
+// Returns false if it was already allowed.
+func (c *Config) AlwaysAllowTool(agentName, toolName string) bool {
+	if c.IsToolAlwaysAllowed(agentName, toolName) {
+		return false
+	}
+	if c.AlwaysAllowedTools == nil {
+		c.AlwaysAllowedTools = make(map[string][]string)
+	}
+	c.AlwaysAllowedTools[agentName] = append(c.AlwaysAllowedTools[agentName], toolName)
+	return true
+}
+
+// SetFeature enables or disables an experimental feature.
+func (c *Config) SetFeature(name string, enabled bool) {
+	if c.Features == nil {
+		c.Features = make(map[string]bool)
+	}
+	c.Features[name] = enabled
+}
+
+// ToolApprovals reads and records the always allowed tools in the config
+// file. The file is read on every check so that the approvals given in
+// another cagent instance are seen too.
+type ToolApprovals struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewToolApprovals returns the tool approvals of the user config file.
+func NewToolApprovals() *ToolApprovals {
+	return &ToolApprovals{path: Path()}
+}
+
+// IsAlwaysAllowed reports whether the agent may call the tool without asking
+// the user. An unreadable config allows nothing.
+func (t *ToolApprovals) IsAlwaysAllowed(agentName, toolName string) bool {
+	config, err := readConfig(t.path)
+	if err != nil {
+		slog.Warn("Failed to read always allowed tools", "path", t.path, "error", err)
+		return false
+	}
+	return config.IsToolAlwaysAllowed(agentName, toolName)
+}
+
+// AlwaysAllow lets the agent call the tool without asking the user, and
+// saves it in the config file.
+func (t *ToolApprovals) AlwaysAllow(agentName, toolName string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	config, err := readConfig(t.path)
+	if err != nil {
+		return err
+	}
+	if !config.AlwaysAllowTool(agentName, toolName) {
+		return nil
+	}
+	return config.saveTo(t.path)
+}
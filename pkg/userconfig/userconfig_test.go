@@ -4,9 +4,12 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/docker/cagent/pkg/notifications"
 )
 
 func TestConfig_Empty(t *testing.T) {
@@ -438,6 +441,43 @@ func TestConfig_ModelsGateway_OnlyGateway(t *testing.T) {
 	assert.Empty(t, config.Aliases)
 }
 
+func TestConfig_Keybindings(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "config.yaml")
+
+	data := "keybindings:\n  app.commands: [ctrl+o]\n  chat.switch_focus: [tab, ctrl+w]\n"
+	require.NoError(t, os.WriteFile(configFile, []byte(data), 0o644))
+
+	config, err := loadFrom(configFile, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string][]string{
+		"app.commands":      {"ctrl+o"},
+		"chat.switch_focus": {"tab", "ctrl+w"},
+	}, config.Keybindings)
+}
+
+func TestConfig_Notifications(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "config.yaml")
+
+	data := "notifications:\n  run_completed:\n    desktop: true\n    min_duration: 30s\n  approval_required:\n    bell: false\n"
+	require.NoError(t, os.WriteFile(configFile, []byte(data), 0o644))
+
+	config, err := loadFrom(configFile, "")
+	require.NoError(t, err)
+
+	bell := false
+	assert.Equal(t, map[string]notifications.Settings{
+		"run_completed":     {Desktop: true, MinDuration: 30 * time.Second},
+		"approval_required": {Bell: &bell},
+	}, config.Notifications)
+}
+
 func TestConfig_Version(t *testing.T) {
 	t.Parallel()
 
@@ -485,3 +525,23 @@ func TestConfig_Version_LoadLegacyWithoutVersion(t *testing.T) {
 	require.NoError(t, config.saveTo(configFile))
 	assert.Equal(t, CurrentVersion, config.Version)
 }
+
+func TestToolApprovals(t *testing.T) {
+	t.Parallel()
+
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	approvals := &ToolApprovals{path: configFile}
+
+	assert.False(t, approvals.IsAlwaysAllowed("root", "shell"))
+
+	require.NoError(t, approvals.AlwaysAllow("root", "shell"))
+	require.NoError(t, approvals.AlwaysAllow("root", "shell"))
+
+	assert.True(t, approvals.IsAlwaysAllowed("root", "shell"))
+	assert.False(t, approvals.IsAlwaysAllowed("helper", "shell"))
+	assert.False(t, approvals.IsAlwaysAllowed("root", "write_file"))
+
+	config, err := loadFrom(configFile, "")
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"root": {"shell"}}, config.AlwaysAllowedTools)
+}
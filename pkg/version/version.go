@@ -1,7 +1,27 @@
 package version
 
+import "runtime/debug"
+
 // version information
 var (
 	Version = "dev"
 	Commit  = "unknown"
 )
+
+// Module returns the version of a module compiled into the binary, or an
+// empty string if it isn't one of its dependencies.
+func Module(path string) string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == path {
+			if dep.Replace != nil {
+				dep = dep.Replace
+			}
+			return dep.Version
+		}
+	}
+	return ""
+}
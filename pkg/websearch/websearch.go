@@ -0,0 +1,236 @@
+// Package websearch searches the web with the API of a search engine: Brave
+// Search, a SearxNG instance or Tavily.
+package websearch
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/cagent/pkg/useragent"
+)
+
+// Search engines
+const (
+	Brave   = "brave"
+	SearxNG = "searxng"
+	Tavily  = "tavily"
+)
+
+// Engines are the search engines that can be used.
+var Engines = []string{Brave, SearxNG, Tavily}
+
+// Result is a page found by a search engine.
+type Result struct {
+	Title   string `json:"title"`
+	URL     string `json:"url"`
+	Snippet string `json:"snippet,omitempty"`
+}
+
+// Engine searches the web.
+type Engine interface {
+	// Search returns at most count pages matching query.
+	Search(ctx context.Context, query string, count int) ([]Result, error)
+}
+
+// APIKeyEnv returns the environment variable holding the API key of a search
+// engine, or an empty string if it doesn't need one.
+func APIKeyEnv(engine string) string {
+	switch engine {
+	case Brave:
+		return "BRAVE_API_KEY"
+	case Tavily:
+		return "TAVILY_API_KEY"
+	default:
+		return ""
+	}
+}
+
+// New returns a search engine. baseURL is the URL of its API: it's required
+// for SearxNG, which is self-hosted, and defaults to the public API of the
+// others.
+func New(engine, baseURL, apiKey string) (Engine, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	switch engine {
+	case Brave:
+		if apiKey == "" {
+			return nil, fmt.Errorf("brave search needs an API key in %s", APIKeyEnv(Brave))
+		}
+		return &braveEngine{client: client, baseURL: cmp.Or(baseURL, "https://api.search.brave.com/res/v1/web/search"), apiKey: apiKey}, nil
+	case SearxNG:
+		if baseURL == "" {
+			return nil, errors.New("searxng needs the url of an instance")
+		}
+		return &searxngEngine{client: client, baseURL: strings.TrimSuffix(baseURL, "/") + "/search"}, nil
+	case Tavily:
+		if apiKey == "" {
+			return nil, fmt.Errorf("tavily needs an API key in %s", APIKeyEnv(Tavily))
+		}
+		return &tavilyEngine{client: client, baseURL: cmp.Or(baseURL, "https://api.tavily.com/search"), apiKey: apiKey}, nil
+	default:
+		return nil, fmt.Errorf("unknown search engine %q, expected one of %s", engine, strings.Join(Engines, ", "))
+	}
+}
+
+type braveEngine struct {
+	client  *http.Client
+	baseURL string
+	apiKey  string
+}
+
+func (e *braveEngine) Search(ctx context.Context, query string, count int) ([]Result, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.baseURL+"?"+url.Values{
+		"q":     {query},
+		"count": {strconv.Itoa(count)},
+	}.Encode(), http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Subscription-Token", e.apiKey)
+
+	var resp struct {
+		Web struct {
+			Results []struct {
+				Title       string `json:"title"`
+				URL         string `json:"url"`
+				Description string `json:"description"`
+			} `json:"results"`
+		} `json:"web"`
+	}
+	if err := do(e.client, req, &resp); err != nil {
+		return nil, err
+	}
+
+	var results []Result
+	for _, r := range resp.Web.Results {
+		results = append(results, Result{Title: r.Title, URL: r.URL, Snippet: stripTags(r.Description)})
+	}
+	return limit(results, count), nil
+}
+
+type searxngEngine struct {
+	client  *http.Client
+	baseURL string
+}
+
+func (e *searxngEngine) Search(ctx context.Context, query string, count int) ([]Result, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.baseURL+"?"+url.Values{
+		"q":      {query},
+		"format": {"json"},
+	}.Encode(), http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Results []struct {
+			Title   string `json:"title"`
+			URL     string `json:"url"`
+			Content string `json:"content"`
+		} `json:"results"`
+	}
+	if err := do(e.client, req, &resp); err != nil {
+		return nil, err
+	}
+
+	var results []Result
+	for _, r := range resp.Results {
+		results = append(results, Result{Title: r.Title, URL: r.URL, Snippet: r.Content})
+	}
+	return limit(results, count), nil
+}
+
+type tavilyEngine struct {
+	client  *http.Client
+	baseURL string
+	apiKey  string
+}
+
+func (e *tavilyEngine) Search(ctx context.Context, query string, count int) ([]Result, error) {
+	body, err := json.Marshal(map[string]any{
+		"query":       query,
+		"max_results": count,
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.baseURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+e.apiKey)
+
+	var resp struct {
+		Results []struct {
+			Title   string `json:"title"`
+			URL     string `json:"url"`
+			Content string `json:"content"`
+		} `json:"results"`
+	}
+	if err := do(e.client, req, &resp); err != nil {
+		return nil, err
+	}
+
+	var results []Result
+	for _, r := range resp.Results {
+		results = append(results, Result{Title: r.Title, URL: r.URL, Snippet: r.Content})
+	}
+	return limit(results, count), nil
+}
+
+// do sends a request to the API of a search engine and decodes its JSON
+// response into v.
+func do(client *http.Client, req *http.Request, v any) error {
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", useragent.Header)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("searching: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("searching: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decoding the results: %w", err)
+	}
+	return nil
+}
+
+func limit(results []Result, count int) []Result {
+	if count > 0 && len(results) > count {
+		return results[:count]
+	}
+	return results
+}
+
+// stripTags removes the tags Brave uses to highlight the words of the query.
+func stripTags(s string) string {
+	var b strings.Builder
+	inTag := false
+	for _, r := range s {
+		switch {
+		case r == '<':
+			inTag = true
+		case r == '>' && inTag:
+			inTag = false
+		case !inTag:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,107 @@
+package websearch
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBrave(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get("X-Subscription-Token"))
+		assert.Equal(t, "golang generics", r.URL.Query().Get("q"))
+		assert.Equal(t, "2", r.URL.Query().Get("count"))
+		_, _ = w.Write([]byte(`{"web":{"results":[
+			{"title":"Generics","url":"https://go.dev/doc/tutorial/generics","description":"Getting started with <strong>generics</strong>"},
+			{"title":"Spec","url":"https://go.dev/ref/spec","description":"The spec"},
+			{"title":"Blog","url":"https://go.dev/blog","description":"The blog"}
+		]}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	engine, err := New(Brave, server.URL, "secret")
+	require.NoError(t, err)
+
+	results, err := engine.Search(t.Context(), "golang generics", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []Result{
+		{Title: "Generics", URL: "https://go.dev/doc/tutorial/generics", Snippet: "Getting started with generics"},
+		{Title: "Spec", URL: "https://go.dev/ref/spec", Snippet: "The spec"},
+	}, results)
+}
+
+func TestSearxNG(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/search", r.URL.Path)
+		assert.Equal(t, "json", r.URL.Query().Get("format"))
+		_, _ = w.Write([]byte(`{"results":[{"title":"Docker","url":"https://docker.com","content":"Build, share, run"}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	engine, err := New(SearxNG, server.URL+"/", "")
+	require.NoError(t, err)
+
+	results, err := engine.Search(t.Context(), "docker", 5)
+	require.NoError(t, err)
+	assert.Equal(t, []Result{{Title: "Docker", URL: "https://docker.com", Snippet: "Build, share, run"}}, results)
+}
+
+func TestTavily(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		var body map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, map[string]any{"query": "cagent", "max_results": float64(3)}, body)
+		_, _ = w.Write([]byte(`{"results":[{"title":"cagent","url":"https://github.com/docker/cagent","content":"Agent builder"}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	engine, err := New(Tavily, server.URL, "secret")
+	require.NoError(t, err)
+
+	results, err := engine.Search(t.Context(), "cagent", 3)
+	require.NoError(t, err)
+	assert.Equal(t, []Result{{Title: "cagent", URL: "https://github.com/docker/cagent", Snippet: "Agent builder"}}, results)
+}
+
+func TestSearchError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+	}))
+	t.Cleanup(server.Close)
+
+	engine, err := New(Brave, server.URL, "wrong")
+	require.NoError(t, err)
+
+	_, err = engine.Search(t.Context(), "query", 5)
+	require.ErrorContains(t, err, "401 Unauthorized: invalid token")
+}
+
+func TestNew_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(Brave, "", "")
+	require.ErrorContains(t, err, "BRAVE_API_KEY")
+
+	_, err = New(Tavily, "", "")
+	require.ErrorContains(t, err, "TAVILY_API_KEY")
+
+	_, err = New(SearxNG, "", "")
+	require.ErrorContains(t, err, "url of an instance")
+
+	_, err = New("google", "", "")
+	require.ErrorContains(t, err, `unknown search engine "google"`)
+}